@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 )
 
 // Request structures (match Specular plugin protocol)
@@ -26,17 +27,34 @@ type PluginResponse struct {
 }
 
 type HealthResponse struct {
-	Status  string `json:"status"`
-	Version string `json:"version"`
-	Name    string `json:"name"`
+	Status           string   `json:"status"`
+	Version          string   `json:"version"`
+	Name             string   `json:"name"`
+	ProtocolVersion  int      `json:"protocol_version"`
+	SupportedActions []string `json:"supported_actions"`
 }
 
 // Plugin metadata - TODO: Update these values
 const (
 	PluginName    = "my-plugin"
 	PluginVersion = "1.0.0"
+
+	// ProtocolVersion is the Specular plugin protocol this plugin implements
+	ProtocolVersion = 1
 )
 
+// Handler handles one action
+type Handler func(request PluginRequest) PluginResponse
+
+// handlers maps each supported action to its handler. The health response
+// advertises exactly these actions, so Specular can reject a plugin that
+// lacks an action it needs before running it.
+// TODO: Register the actions your plugin type requires (e.g., "notify" for
+// notifiers, "validate" for validators)
+var handlers = map[string]Handler{
+	"notify": handleAction,
+}
+
 func main() {
 	// Read request from stdin
 	scanner := bufio.NewScanner(os.Stdin)
@@ -52,26 +70,47 @@ func main() {
 		return
 	}
 
-	// Handle actions
-	var response PluginResponse
-	switch request.Action {
-	case "health":
-		response = handleHealth()
-	default:
-		response = handleAction(request)
+	respond(dispatch(request))
+}
+
+// dispatch routes a request to the handler for its action
+func dispatch(request PluginRequest) PluginResponse {
+	if request.Action == "health" {
+		return handleHealth()
 	}
 
-	respond(response)
+	handler, ok := handlers[request.Action]
+	if !ok {
+		return PluginResponse{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported action: %s", request.Action),
+		}
+	}
+	return handler(request)
 }
 
 func handleHealth() PluginResponse {
 	return PluginResponse{
 		Success: true,
-		Result: HealthResponse{
-			Status:  "healthy",
-			Version: PluginVersion,
-			Name:    PluginName,
-		},
+		Result:  NewHealthResponse(),
+	}
+}
+
+// NewHealthResponse builds the health handshake from the plugin metadata and
+// the registered handlers
+func NewHealthResponse() HealthResponse {
+	actions := make([]string, 0, len(handlers))
+	for action := range handlers {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	return HealthResponse{
+		Status:           "healthy",
+		Version:          PluginVersion,
+		Name:             PluginName,
+		ProtocolVersion:  ProtocolVersion,
+		SupportedActions: actions,
 	}
 }
 
@@ -33,9 +33,12 @@ type PluginResponse struct {
 }
 
 type HealthResponse struct {
-	Status  string `json:"status"`
-	Version string `json:"version"`
-	Name    string `json:"name"`
+	Status           string   `json:"status"`
+	Version          string   `json:"version"`
+	Name             string   `json:"name"`
+	Persistent       bool     `json:"persistent"`
+	ProtocolVersion  int      `json:"protocol_version"`
+	SupportedActions []string `json:"supported_actions"`
 }
 
 // Slack message types
@@ -64,43 +67,56 @@ type Attachment struct {
 	Ts     int64  `json:"ts,omitempty"`
 }
 
+// httpClient is reused across notifications so connections to Slack are
+// pooled while the plugin runs in persistent mode
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// main handles newline-delimited JSON requests until stdin is closed or the
+// host sends the shutdown action. A one-shot host sends a single request and
+// closes stdin, so the same loop serves both modes.
 func main() {
 	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	input := scanner.Bytes()
-
-	var request NotifierRequest
-	if err := json.Unmarshal(input, &request); err != nil {
-		respond(PluginResponse{
-			Success: false,
-			Error:   fmt.Sprintf("invalid request: %v", err),
-		})
-		return
-	}
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		var request NotifierRequest
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			respond(PluginResponse{
+				Success: false,
+				Error:   fmt.Sprintf("invalid request: %v", err),
+			})
+			continue
+		}
 
-	var response PluginResponse
-	switch request.Action {
-	case "health":
-		response = handleHealth()
-	case "notify":
-		response = handleNotify(request)
-	default:
-		response = PluginResponse{
-			Success: false,
-			Error:   fmt.Sprintf("unknown action: %s", request.Action),
+		var response PluginResponse
+		switch request.Action {
+		case "shutdown":
+			return
+		case "health":
+			response = handleHealth()
+		case "notify":
+			response = handleNotify(request)
+		default:
+			response = PluginResponse{
+				Success: false,
+				Error:   fmt.Sprintf("unknown action: %s", request.Action),
+			}
 		}
-	}
 
-	respond(response)
+		respond(response)
+	}
 }
 
 func handleHealth() PluginResponse {
 	return PluginResponse{
 		Success: true,
 		Result: HealthResponse{
-			Status:  "healthy",
-			Version: PluginVersion,
-			Name:    PluginName,
+			Status:           "healthy",
+			Version:          PluginVersion,
+			Name:             PluginName,
+			Persistent:       true,
+			ProtocolVersion:  1,
+			SupportedActions: []string{"notify"},
 		},
 	}
 }
@@ -225,7 +241,7 @@ func sendToSlack(webhookURL string, message SlackMessage) error {
 		return fmt.Errorf("marshal message: %w", err)
 	}
 
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	resp, err := httpClient.Post(webhookURL, "application/json", bytes.NewReader(payload))
 	if err != nil {
 		return fmt.Errorf("post to webhook: %w", err)
 	}
@@ -0,0 +1,278 @@
+// Specular Spec Validator Plugin
+// Checks generated specs for features without acceptance criteria and other
+// gaps that make the resulting plan hard to verify.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	PluginName    = "spec-validator"
+	PluginVersion = "1.0.0"
+
+	// ProtocolVersion is the Specular plugin protocol this plugin implements
+	ProtocolVersion = 1
+)
+
+// Request types
+type ValidatorRequest struct {
+	Action  string                 `json:"action"`
+	Content string                 `json:"content"`
+	Rules   map[string]interface{} `json:"rules,omitempty"`
+	Config  map[string]interface{} `json:"config,omitempty"`
+}
+
+type PluginResponse struct {
+	Success bool        `json:"success"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status           string   `json:"status"`
+	Version          string   `json:"version"`
+	Name             string   `json:"name"`
+	Persistent       bool     `json:"persistent"`
+	ProtocolVersion  int      `json:"protocol_version"`
+	SupportedActions []string `json:"supported_actions"`
+}
+
+type ValidatorResponse struct {
+	Valid    bool             `json:"valid"`
+	Messages []ValidatorIssue `json:"messages,omitempty"`
+}
+
+type ValidatorIssue struct {
+	Severity string `json:"severity"` // error, warning, info
+	Message  string `json:"message"`
+	Rule     string `json:"rule,omitempty"`
+}
+
+// Spec types (the subset of the Specular product spec this plugin checks)
+type ProductSpec struct {
+	Product    string    `json:"product"`
+	Features   []Feature `json:"features"`
+	Acceptance []string  `json:"acceptance"`
+}
+
+type Feature struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Desc     string   `json:"desc"`
+	Priority string   `json:"priority"`
+	Success  []string `json:"success"`
+	Trace    []string `json:"trace"`
+}
+
+// Rules and their defaults. Rules sent with the request override the
+// plugin configuration, which overrides the defaults.
+const (
+	RuleMinAcceptance      = "min_acceptance_criteria"
+	RuleRequireDescription = "require_description"
+	RuleRequireTrace       = "require_p0_trace"
+)
+
+var defaultRules = map[string]interface{}{
+	RuleMinAcceptance:      1,
+	RuleRequireDescription: true,
+	RuleRequireTrace:       true,
+}
+
+// Handler handles one action
+type Handler func(request ValidatorRequest) PluginResponse
+
+var handlers = map[string]Handler{
+	"validate": handleValidate,
+}
+
+// main handles newline-delimited JSON requests until stdin is closed or the
+// host sends the shutdown action, so the plugin works in both one-shot and
+// persistent mode
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		var request ValidatorRequest
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			respond(PluginResponse{
+				Success: false,
+				Error:   fmt.Sprintf("invalid request: %v", err),
+			})
+			continue
+		}
+
+		if request.Action == "shutdown" {
+			return
+		}
+		respond(dispatch(request))
+	}
+}
+
+// dispatch routes a request to the handler for its action
+func dispatch(request ValidatorRequest) PluginResponse {
+	if request.Action == "health" {
+		return handleHealth()
+	}
+
+	handler, ok := handlers[request.Action]
+	if !ok {
+		return PluginResponse{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported action: %s", request.Action),
+		}
+	}
+	return handler(request)
+}
+
+func handleHealth() PluginResponse {
+	actions := make([]string, 0, len(handlers))
+	for action := range handlers {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	return PluginResponse{
+		Success: true,
+		Result: HealthResponse{
+			Status:           "healthy",
+			Version:          PluginVersion,
+			Name:             PluginName,
+			Persistent:       true,
+			ProtocolVersion:  ProtocolVersion,
+			SupportedActions: actions,
+		},
+	}
+}
+
+func handleValidate(request ValidatorRequest) PluginResponse {
+	var spec ProductSpec
+	if err := json.Unmarshal([]byte(request.Content), &spec); err != nil {
+		return PluginResponse{
+			Success: false,
+			Error:   fmt.Sprintf("content is not a Specular spec: %v", err),
+		}
+	}
+
+	rules := resolveRules(request)
+	issues := validateSpec(spec, rules)
+
+	valid := true
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			valid = false
+		}
+	}
+
+	return PluginResponse{
+		Success: true,
+		Result: ValidatorResponse{
+			Valid:    valid,
+			Messages: issues,
+		},
+	}
+}
+
+// validateSpec checks the spec against the rules
+func validateSpec(spec ProductSpec, rules map[string]interface{}) []ValidatorIssue {
+	minAcceptance := getInt(rules, RuleMinAcceptance)
+	requireDescription := getBool(rules, RuleRequireDescription)
+	requireTrace := getBool(rules, RuleRequireTrace)
+
+	issues := []ValidatorIssue{}
+	if len(spec.Features) == 0 {
+		issues = append(issues, ValidatorIssue{
+			Severity: "error",
+			Message:  "spec defines no features",
+			Rule:     "features",
+		})
+	}
+
+	for _, feature := range spec.Features {
+		name := feature.ID
+		if feature.Title != "" {
+			name = fmt.Sprintf("%s (%s)", feature.ID, feature.Title)
+		}
+
+		if len(feature.Success) < minAcceptance {
+			message := fmt.Sprintf("feature %s has no acceptance criteria", name)
+			if len(feature.Success) > 0 {
+				message = fmt.Sprintf("feature %s has %d acceptance criteria, at least %d required",
+					name, len(feature.Success), minAcceptance)
+			}
+			issues = append(issues, ValidatorIssue{
+				Severity: "error",
+				Message:  message,
+				Rule:     RuleMinAcceptance,
+			})
+		}
+
+		if requireDescription && feature.Desc == "" {
+			issues = append(issues, ValidatorIssue{
+				Severity: "warning",
+				Message:  fmt.Sprintf("feature %s has no description", name),
+				Rule:     RuleRequireDescription,
+			})
+		}
+
+		if requireTrace && feature.Priority == "P0" && len(feature.Trace) == 0 {
+			issues = append(issues, ValidatorIssue{
+				Severity: "warning",
+				Message:  fmt.Sprintf("P0 feature %s is not traced to a goal or requirement", name),
+				Rule:     RuleRequireTrace,
+			})
+		}
+	}
+
+	if len(spec.Acceptance) == 0 {
+		issues = append(issues, ValidatorIssue{
+			Severity: "info",
+			Message:  "spec has no product-level acceptance criteria",
+			Rule:     "acceptance",
+		})
+	}
+
+	return issues
+}
+
+// resolveRules merges the defaults, plugin configuration, and request rules
+func resolveRules(request ValidatorRequest) map[string]interface{} {
+	rules := make(map[string]interface{}, len(defaultRules))
+	for key, value := range defaultRules {
+		rules[key] = value
+	}
+	for _, overrides := range []map[string]interface{}{request.Config, request.Rules} {
+		for key := range defaultRules {
+			if value, ok := overrides[key]; ok {
+				rules[key] = value
+			}
+		}
+	}
+	return rules
+}
+
+func getInt(rules map[string]interface{}, key string) int {
+	switch value := rules[key].(type) {
+	case int:
+		return value
+	case float64: // JSON numbers
+		return int(value)
+	}
+	return 0
+}
+
+func getBool(rules map[string]interface{}, key string) bool {
+	value, _ := rules[key].(bool)
+	return value
+}
+
+func respond(response PluginResponse) {
+	output, _ := json.Marshal(response)
+	fmt.Println(string(output))
+}
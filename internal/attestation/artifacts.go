@@ -0,0 +1,44 @@
+package attestation
+
+import (
+	"encoding/json"
+
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/plan"
+)
+
+const (
+	// PlanFileName is the execution plan in an auto output directory
+	PlanFileName = "plan.json"
+
+	// OutputFileName is the workflow output in an auto output directory
+	OutputFileName = "output.json"
+)
+
+// PlanJSON returns the plan encoding whose hash is attested
+func PlanJSON(p *plan.Plan) ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// OutputJSON returns the output encoding whose hash is attested
+func OutputJSON(output *auto.AutoOutput) ([]byte, error) {
+	return output.ToJSON()
+}
+
+// canonicalPlanJSON re-encodes plan JSON in the attested encoding
+func canonicalPlanJSON(data []byte) ([]byte, error) {
+	var p plan.Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return PlanJSON(&p)
+}
+
+// canonicalOutputJSON re-encodes workflow output JSON in the attested encoding
+func canonicalOutputJSON(data []byte) ([]byte, error) {
+	output, err := auto.FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return OutputJSON(output)
+}
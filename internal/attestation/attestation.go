@@ -31,6 +31,9 @@ type Attestation struct {
 	SignedBy  string    `json:"signedBy"`  // Email or identity
 	Signature string    `json:"signature"` // Base64-encoded signature
 	PublicKey string    `json:"publicKey"` // Base64-encoded public key
+
+	// Certificate is the Fulcio certificate chain (PEM) for keyless signing
+	Certificate string `json:"certificate,omitempty"`
 }
 
 // Provenance contains information about the execution environment
@@ -78,6 +81,18 @@ type Signer interface {
 	Identity() string
 }
 
+// publicKeySigner is a Signer whose public key is embedded in attestations
+type publicKeySigner interface {
+	// PublicKey returns the DER-encoded public key
+	PublicKey() ([]byte, error)
+}
+
+// certificateSigner is a Signer whose key is certified for its identity
+type certificateSigner interface {
+	// Certificate returns the PEM certificate chain
+	Certificate() string
+}
+
 // Verifier is the interface for verifying attestations
 type Verifier interface {
 	// Verify checks the signature on an attestation
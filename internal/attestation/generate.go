@@ -40,13 +40,18 @@ func (g *Generator) Generate(result *auto.Result, config *auto.Config, planJSON
 	}
 
 	// Get workflow metadata from AutoOutput if available
-	workflowID := "unknown"
+	workflowID := result.WorkflowID
+	if workflowID == "" && result.AutoOutput != nil {
+		workflowID = result.AutoOutput.Audit.CheckpointID
+	}
+	if workflowID == "" {
+		workflowID = "unknown"
+	}
 	goal := config.Goal
 	var startTime, endTime time.Time
 	status := determineStatus(result)
 
 	if result.AutoOutput != nil {
-		workflowID = result.AutoOutput.Audit.CheckpointID
 		goal = result.AutoOutput.Goal
 		startTime = result.AutoOutput.Audit.StartedAt
 		endTime = result.AutoOutput.Audit.CompletedAt
@@ -69,6 +74,11 @@ func (g *Generator) Generate(result *auto.Result, config *auto.Config, planJSON
 		SignedBy:   g.signer.Identity(),
 	}
 
+	// The certificate binds the identity to the key and is signed with the rest
+	if certified, ok := g.signer.(certificateSigner); ok {
+		attestation.Certificate = certified.Certificate()
+	}
+
 	// Serialize attestation data for signing (without signature fields)
 	dataToSign, err := g.serializeForSigning(attestation)
 	if err != nil {
@@ -85,9 +95,9 @@ func (g *Generator) Generate(result *auto.Result, config *auto.Config, planJSON
 	attestation.Signature = EncodeSignature(signature)
 
 	// Get public key bytes
-	if ephemeralSigner, ok := g.signer.(*EphemeralSigner); ok {
+	if keySigner, ok := g.signer.(publicKeySigner); ok {
 		var pubKeyBytes []byte
-		pubKeyBytes, err = ephemeralSigner.PublicKey()
+		pubKeyBytes, err = keySigner.PublicKey()
 		if err != nil {
 			return nil, fmt.Errorf("failed to encode public key: %w", err)
 		}
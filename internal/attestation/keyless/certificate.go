@@ -0,0 +1,125 @@
+package keyless
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+var (
+	// oidIssuerV1 is the Fulcio OIDC issuer extension, stored as raw bytes
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+	// oidIssuerV2 is the Fulcio OIDC issuer extension, stored as a DER UTF8String
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// VerifyCertificate parses a PEM certificate chain, leaf first, and checks
+// that the leaf was valid at signedAt and is meant for code signing. With
+// roots, the chain must also lead to one of them. It returns the leaf.
+//
+// Without roots the leaf's identity is unverified: anyone can create a
+// self-signed certificate for any email or URI. Callers must not trust
+// CertificateIdentity of a leaf that was not checked against roots.
+// Without a transparency log entry, signedAt is the time the signer claims.
+func VerifyCertificate(chainPEM string, signedAt time.Time, roots *x509.CertPool) (*x509.Certificate, error) {
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(chainPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate chain: %w", err)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("certificate chain is empty")
+	}
+	leaf := chain[0]
+
+	if err := cryptoutils.CheckExpiration(leaf, signedAt); err != nil {
+		return nil, fmt.Errorf("certificate was not valid at signing time: %w", err)
+	}
+	if CertificateIdentity(leaf) == "" {
+		return nil, fmt.Errorf("certificate has no email or URI identity")
+	}
+
+	codeSigning := false
+	for _, usage := range leaf.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageCodeSigning {
+			codeSigning = true
+		}
+	}
+	if !codeSigning {
+		return nil, fmt.Errorf("certificate is not valid for code signing")
+	}
+
+	if roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   signedAt,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		}); err != nil {
+			return nil, fmt.Errorf("certificate is not trusted: %w", err)
+		}
+	}
+
+	return leaf, nil
+}
+
+// LoadRoots reads trusted root certificates from a PEM file
+func LoadRoots(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- User-provided trusted root path
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trusted roots: %w", err)
+	}
+
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trusted roots: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// CertificateIdentity returns the identity a Fulcio certificate was issued
+// to: its email address, or its URI for workload identities such as CI
+// workflows. It is only verified when the certificate chains to trusted
+// roots.
+func CertificateIdentity(cert *x509.Certificate) string {
+	if len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0]
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return ""
+}
+
+// CertificateIssuer returns the OIDC issuer recorded in a Fulcio certificate,
+// or "" if it has none
+func CertificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidIssuerV1):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+// Package keyless implements Sigstore keyless signing: an ephemeral key is
+// bound to an OIDC identity by a short-lived certificate from Fulcio.
+package keyless
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+const (
+	// DefaultFulcioURL is the public Sigstore certificate authority
+	DefaultFulcioURL = "https://fulcio.sigstore.dev"
+
+	// DefaultAudience is the token audience Fulcio accepts
+	DefaultAudience = "sigstore"
+)
+
+// Options configures a keyless signer
+type Options struct {
+	// FulcioURL is the Fulcio server (default: DefaultFulcioURL)
+	FulcioURL string
+
+	// IDToken is the OIDC identity token; read from the environment when empty
+	IDToken string
+
+	// Issuer, when set, is the OIDC issuer the token must come from
+	Issuer string
+
+	// HTTPClient is used for token and Fulcio requests (default: 30s timeout)
+	HTTPClient *http.Client
+}
+
+// Signer signs with an ephemeral key certified by Fulcio for an OIDC identity
+type Signer struct {
+	privateKey *ecdsa.PrivateKey
+	chain      []*x509.Certificate
+}
+
+// NewSigner obtains an identity token, generates an ephemeral key, and
+// requests a signing certificate for it from Fulcio. It returns ErrNoToken
+// when no token is given or found in the environment.
+func NewSigner(ctx context.Context, opts Options) (*Signer, error) {
+	if opts.FulcioURL == "" {
+		opts.FulcioURL = DefaultFulcioURL
+	}
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	token := opts.IDToken
+	if token == "" {
+		var err error
+		token, err = IDToken(ctx, client, DefaultAudience)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	claims, err := parseClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Issuer != "" && strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(opts.Issuer, "/") {
+		return nil, fmt.Errorf("OIDC token issuer %s does not match the configured issuer %s", claims.Issuer, opts.Issuer)
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	chain, err := requestCertificate(ctx, client, opts.FulcioURL, token, claims, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	leafKey, ok := chain[0].PublicKey.(*ecdsa.PublicKey)
+	if !ok || !leafKey.Equal(&privateKey.PublicKey) {
+		return nil, fmt.Errorf("fulcio certificate does not match the signing key")
+	}
+
+	return &Signer{privateKey: privateKey, chain: chain}, nil
+}
+
+// PrivateKey returns the ephemeral signing key
+func (s *Signer) PrivateKey() *ecdsa.PrivateKey {
+	return s.privateKey
+}
+
+// Certificate returns the leaf signing certificate
+func (s *Signer) Certificate() *x509.Certificate {
+	return s.chain[0]
+}
+
+// CertificateChainPEM returns the certificate chain, leaf first, as PEM
+func (s *Signer) CertificateChainPEM() (string, error) {
+	data, err := cryptoutils.MarshalCertificatesToPEM(s.chain)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode certificate chain: %w", err)
+	}
+	return string(data), nil
+}
+
+// Identity returns the OIDC identity the certificate was issued to
+func (s *Signer) Identity() string {
+	return CertificateIdentity(s.chain[0])
+}
+
+// fulcioRequest is the body of a Fulcio v2 signing certificate request
+type fulcioRequest struct {
+	Credentials struct {
+		OIDCIdentityToken string `json:"oidcIdentityToken"`
+	} `json:"credentials"`
+	PublicKeyRequest struct {
+		PublicKey struct {
+			Algorithm string `json:"algorithm"`
+			Content   string `json:"content"`
+		} `json:"publicKey"`
+		ProofOfPossession string `json:"proofOfPossession"`
+	} `json:"publicKeyRequest"`
+}
+
+// fulcioChain is a certificate chain in a Fulcio v2 response
+type fulcioChain struct {
+	Chain struct {
+		Certificates []string `json:"certificates"`
+	} `json:"chain"`
+}
+
+// fulcioResponse is a Fulcio v2 signing certificate response
+type fulcioResponse struct {
+	SignedCertificateEmbeddedSct *fulcioChain `json:"signedCertificateEmbeddedSct"`
+	SignedCertificateDetachedSct *fulcioChain `json:"signedCertificateDetachedSct"`
+}
+
+// requestCertificate asks Fulcio to certify the public key for the token's
+// identity. The proof of possession signs the token principal.
+func requestCertificate(ctx context.Context, client *http.Client, fulcioURL, token string, claims tokenClaims, privateKey *ecdsa.PrivateKey) ([]*x509.Certificate, error) {
+	publicKeyPEM, err := cryptoutils.MarshalPublicKeyToPEM(&privateKey.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(claims.principal()))
+	proof, err := privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign proof of possession: %w", err)
+	}
+
+	var reqBody fulcioRequest
+	reqBody.Credentials.OIDCIdentityToken = token
+	reqBody.PublicKeyRequest.PublicKey.Algorithm = "ECDSA"
+	reqBody.PublicKeyRequest.PublicKey.Content = string(publicKeyPEM)
+	reqBody.PublicKeyRequest.ProofOfPossession = base64.StdEncoding.EncodeToString(proof)
+
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal certificate request: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(fulcioURL, "/") + "/api/v2/signingCert"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Fulcio: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Fulcio response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("fulcio rejected the certificate request: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var certResp fulcioResponse
+	if err := json.Unmarshal(body, &certResp); err != nil {
+		return nil, fmt.Errorf("failed to parse Fulcio response: %w", err)
+	}
+	signed := certResp.SignedCertificateEmbeddedSct
+	if signed == nil {
+		signed = certResp.SignedCertificateDetachedSct
+	}
+	if signed == nil || len(signed.Chain.Certificates) == 0 {
+		return nil, fmt.Errorf("fulcio response has no certificate chain")
+	}
+
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(strings.Join(signed.Chain.Certificates, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Fulcio certificate chain: %w", err)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("fulcio response has no certificate chain")
+	}
+	return chain, nil
+}
@@ -0,0 +1,183 @@
+package keyless
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless/keylesstest"
+)
+
+// clearTokenEnv removes ambient identity tokens for the test
+func clearTokenEnv(t *testing.T) {
+	t.Setenv("SIGSTORE_ID_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+}
+
+func TestIDToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no token", func(t *testing.T) {
+		clearTokenEnv(t)
+		if _, err := IDToken(ctx, http.DefaultClient, DefaultAudience); !errors.Is(err, ErrNoToken) {
+			t.Errorf("expected ErrNoToken, got %v", err)
+		}
+	})
+
+	t.Run("SIGSTORE_ID_TOKEN", func(t *testing.T) {
+		clearTokenEnv(t)
+		t.Setenv("SIGSTORE_ID_TOKEN", "env-token\n")
+		token, err := IDToken(ctx, http.DefaultClient, DefaultAudience)
+		if err != nil || token != "env-token" {
+			t.Errorf("IDToken() = %q, %v; want env-token", token, err)
+		}
+	})
+
+	t.Run("GitHub Actions", func(t *testing.T) {
+		clearTokenEnv(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "bearer request-token" || r.URL.Query().Get("audience") != "sigstore" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"value":"actions-token"}`))
+		}))
+		defer server.Close()
+
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token?api-version=2.0")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+		token, err := IDToken(ctx, http.DefaultClient, DefaultAudience)
+		if err != nil || token != "actions-token" {
+			t.Errorf("IDToken() = %q, %v; want actions-token", token, err)
+		}
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	ctx := context.Background()
+	fulcio := keylesstest.NewFulcio(t)
+
+	signer, err := NewSigner(ctx, Options{
+		FulcioURL: fulcio.URL,
+		IDToken:   keylesstest.Token("dev@example.com"),
+		Issuer:    keylesstest.Issuer + "/",
+	})
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	if got := signer.Identity(); got != "dev@example.com" {
+		t.Errorf("Identity() = %q, want dev@example.com", got)
+	}
+	if got := CertificateIssuer(signer.Certificate()); got != keylesstest.Issuer {
+		t.Errorf("CertificateIssuer() = %q, want %s", got, keylesstest.Issuer)
+	}
+
+	chainPEM, err := signer.CertificateChainPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := VerifyCertificate(chainPEM, time.Now(), fulcio.Roots)
+	if err != nil {
+		t.Fatalf("VerifyCertificate() error = %v", err)
+	}
+	if key, ok := leaf.PublicKey.(*ecdsa.PublicKey); !ok || !key.Equal(&signer.PrivateKey().PublicKey) {
+		t.Error("expected the certificate to hold the signing key")
+	}
+
+	// Fulcio certificates are short-lived
+	if _, err := VerifyCertificate(chainPEM, time.Now().Add(time.Hour), nil); err == nil {
+		t.Error("expected an error for a signing time after the certificate expired")
+	}
+
+	// Another CA is not trusted
+	other := keylesstest.NewFulcio(t)
+	if _, err := VerifyCertificate(chainPEM, time.Now(), other.Roots); err == nil {
+		t.Error("expected an error for an untrusted certificate")
+	}
+}
+
+func TestNewSignerErrors(t *testing.T) {
+	ctx := context.Background()
+	fulcio := keylesstest.NewFulcio(t)
+
+	t.Run("no token", func(t *testing.T) {
+		clearTokenEnv(t)
+		_, err := NewSigner(ctx, Options{FulcioURL: fulcio.URL})
+		if !errors.Is(err, ErrNoToken) {
+			t.Errorf("expected ErrNoToken, got %v", err)
+		}
+	})
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		_, err := NewSigner(ctx, Options{
+			FulcioURL: fulcio.URL,
+			IDToken:   keylesstest.Token("dev@example.com"),
+			Issuer:    "https://accounts.google.com",
+		})
+		if err == nil || !strings.Contains(err.Error(), "issuer") {
+			t.Errorf("expected an issuer mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("rejected request", func(t *testing.T) {
+		_, err := NewSigner(ctx, Options{
+			FulcioURL: fulcio.URL,
+			IDToken:   "header.e30.sig",
+		})
+		if err == nil {
+			t.Error("expected an error for a token without identity")
+		}
+	})
+}
+
+func TestLoadRoots(t *testing.T) {
+	fulcio := keylesstest.NewFulcio(t)
+	path := t.TempDir() + "/root.pem"
+	if err := os.WriteFile(path, fulcio.RootPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	roots, err := LoadRoots(path)
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	if !roots.Equal(fulcio.Roots) {
+		t.Error("expected the loaded roots to match the CA")
+	}
+
+	if _, err := LoadRoots(path + ".missing"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+// TestCertificateIdentity tests email and workload (URI) identities
+func TestCertificateIdentity(t *testing.T) {
+	workflow, _ := url.Parse("https://github.com/acme/app/.github/workflows/release.yml@refs/heads/main")
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{name: "email", cert: &x509.Certificate{EmailAddresses: []string{"dev@example.com"}, URIs: []*url.URL{workflow}}, want: "dev@example.com"},
+		{name: "uri", cert: &x509.Certificate{URIs: []*url.URL{workflow}}, want: workflow.String()},
+		{name: "none", cert: &x509.Certificate{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CertificateIdentity(tt.cert); got != tt.want {
+				t.Errorf("CertificateIdentity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
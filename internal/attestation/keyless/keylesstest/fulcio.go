@@ -0,0 +1,233 @@
+// Package keylesstest provides a fake Fulcio certificate authority for
+// testing keyless signing without network access.
+package keylesstest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// Issuer is the OIDC issuer of tokens made by Token
+const Issuer = "https://oidc.example.com"
+
+// Fulcio is a fake Fulcio server that certifies any well-formed request
+type Fulcio struct {
+	// URL is the base URL of the server
+	URL string
+
+	// Roots contains the CA certificate
+	Roots *x509.CertPool
+
+	// RootPEM is the CA certificate as PEM
+	RootPEM []byte
+
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+}
+
+// NewFulcio starts a fake Fulcio server, closed when the test ends
+func NewFulcio(t testing.TB) *Fulcio {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake-fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+	rootPEM, err := cryptoutils.MarshalCertificateToPEM(caCert)
+	if err != nil {
+		t.Fatalf("failed to encode CA certificate: %v", err)
+	}
+
+	f := &Fulcio{
+		Roots:   x509.NewCertPool(),
+		RootPEM: rootPEM,
+		caKey:   caKey,
+		caCert:  caCert,
+	}
+	f.Roots.AddCert(caCert)
+
+	server := httptest.NewServer(http.HandlerFunc(f.serveSigningCert))
+	t.Cleanup(server.Close)
+	f.URL = server.URL
+	return f
+}
+
+// Token returns an unsigned OIDC token for the email address
+func Token(email string) string {
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v) //nolint:errcheck
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	header := encode(map[string]string{"alg": "none", "typ": "JWT"})
+	claims := encode(map[string]string{"iss": Issuer, "sub": email, "email": email, "aud": "sigstore"})
+	return header + "." + claims + ".sig"
+}
+
+// serveSigningCert handles POST /api/v2/signingCert
+func (f *Fulcio) serveSigningCert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v2/signingCert" {
+		http.NotFound(w, r)
+		return
+	}
+	var req struct {
+		Credentials struct {
+			OIDCIdentityToken string `json:"oidcIdentityToken"`
+		} `json:"credentials"`
+		PublicKeyRequest struct {
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+			ProofOfPossession string `json:"proofOfPossession"`
+		} `json:"publicKeyRequest"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parts := strings.Split(req.Credentials.OIDCIdentityToken, ".")
+	if len(parts) != 3 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	var claims struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Email == "" {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	publicKey, err := cryptoutils.UnmarshalPEMToPublicKey([]byte(req.PublicKeyRequest.PublicKey.Content))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ecKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		http.Error(w, "unsupported key", http.StatusBadRequest)
+		return
+	}
+	proof, err := base64.StdEncoding.DecodeString(req.PublicKeyRequest.ProofOfPossession)
+	digest := sha256.Sum256([]byte(claims.Email))
+	if err != nil || !ecdsa.VerifyASN1(ecKey, digest[:], proof) {
+		http.Error(w, "invalid proof of possession", http.StatusBadRequest)
+		return
+	}
+
+	certPEM, err := f.issue(ecKey, claims.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"signedCertificateEmbeddedSct": map[string]interface{}{
+			"chain": map[string]interface{}{
+				"certificates": []string{string(certPEM), string(f.RootPEM)},
+			},
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// issue creates a 10 minute code signing certificate for the email
+func (f *Fulcio) issue(publicKey crypto.PublicKey, email string) ([]byte, error) {
+	issuer, err := asn1.MarshalWithParams(Issuer, "utf8")
+	if err != nil {
+		return nil, err
+	}
+	serial, err := cryptoutils.GenerateSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		EmailAddresses: []string{email},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}, Value: issuer},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, f.caCert, publicKey, f.caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.MarshalCertificateToPEM(cert)
+}
+
+// SelfSigned returns a code signing certificate for the email that key
+// signs itself, as anyone can create without a certificate authority
+func SelfSigned(t testing.TB, key *ecdsa.PrivateKey, email string) []byte {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		EmailAddresses: []string{email},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create self-signed certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse self-signed certificate: %v", err)
+	}
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+	if err != nil {
+		t.Fatalf("failed to encode self-signed certificate: %v", err)
+	}
+	return certPEM
+}
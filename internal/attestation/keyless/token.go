@@ -0,0 +1,117 @@
+package keyless
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrNoToken indicates that no OIDC identity token is available in the
+// environment
+var ErrNoToken = errors.New("no OIDC token available (set SIGSTORE_ID_TOKEN or run in GitHub Actions with id-token: write)")
+
+// IDToken returns an OIDC identity token for the audience from the
+// environment: SIGSTORE_ID_TOKEN, or the ambient GitHub Actions token. It
+// returns ErrNoToken when neither is available.
+func IDToken(ctx context.Context, client *http.Client, audience string) (string, error) {
+	if token := strings.TrimSpace(os.Getenv("SIGSTORE_ID_TOKEN")); token != "" {
+		return token, nil
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL != "" && requestToken != "" {
+		return githubActionsToken(ctx, client, requestURL, requestToken, audience)
+	}
+
+	return "", ErrNoToken
+}
+
+// githubActionsToken requests an identity token from the GitHub Actions
+// token service
+func githubActionsToken(ctx context.Context, client *http.Client, requestURL, requestToken, audience string) (string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+requestToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request GitHub Actions token: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read GitHub Actions token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub Actions token request failed: %s", resp.Status)
+	}
+
+	var tokenResp struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse GitHub Actions token: %w", err)
+	}
+	if tokenResp.Value == "" {
+		return "", fmt.Errorf("GitHub Actions returned an empty token")
+	}
+	return tokenResp.Value, nil
+}
+
+// tokenClaims are the identity token claims used for signing. Fulcio checks
+// the token signature; they are only read here.
+type tokenClaims struct {
+	Issuer  string `json:"iss"`
+	Subject string `json:"sub"`
+	Email   string `json:"email"`
+}
+
+// principal is the value Fulcio expects the proof of possession to sign:
+// the email for email identities, the subject otherwise
+func (c tokenClaims) principal() string {
+	if c.Email != "" {
+		return c.Email
+	}
+	return c.Subject
+}
+
+// parseClaims decodes the claims of a JWT without verifying it
+func parseClaims(token string) (tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return tokenClaims{}, fmt.Errorf("OIDC token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return tokenClaims{}, fmt.Errorf("failed to decode OIDC token claims: %w", err)
+	}
+
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return tokenClaims{}, fmt.Errorf("failed to parse OIDC token claims: %w", err)
+	}
+	if claims.principal() == "" {
+		return tokenClaims{}, fmt.Errorf("OIDC token has no subject or email claim")
+	}
+	return claims, nil
+}
@@ -1,6 +1,7 @@
 package attestation
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -9,11 +10,12 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 )
 
-// EphemeralSigner implements keyless signing using ephemeral keys
-// This is a simplified implementation for Phase 1
-// Full Sigstore OIDC integration can be added later
+// EphemeralSigner signs with a key generated for one attestation. The
+// identity is self-reported; use KeylessSigner for a certified identity.
 type EphemeralSigner struct {
 	privateKey *ecdsa.PrivateKey
 	identity   string
@@ -44,8 +46,10 @@ func (s *EphemeralSigner) Sign(data []byte) (signature []byte, publicKey crypto.
 		return nil, nil, fmt.Errorf("failed to sign: %w", err)
 	}
 
-	// Encode signature (r || s)
-	signature = append(r.Bytes(), sigS.Bytes()...)
+	// Encode signature (r || s), each padded to 32 bytes
+	signature = make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sigS.FillBytes(signature[32:])
 
 	return signature, &s.privateKey.PublicKey, nil
 }
@@ -83,3 +87,67 @@ func EncodePublicKey(publicKey []byte) string {
 func DecodePublicKey(encoded string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(encoded)
 }
+
+// KeylessSigner signs with an ephemeral key that Fulcio certified for an
+// OIDC identity, so the attestation names a verifiable signer
+type KeylessSigner struct {
+	signer   *keyless.Signer
+	chainPEM string
+}
+
+// NewKeylessSigner obtains a Fulcio certificate for the OIDC identity in
+// opts or the environment. It returns keyless.ErrNoToken when no identity
+// token is available.
+func NewKeylessSigner(ctx context.Context, opts keyless.Options) (*KeylessSigner, error) {
+	signer, err := keyless.NewSigner(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	chainPEM, err := signer.CertificateChainPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeylessSigner{
+		signer:   signer,
+		chainPEM: chainPEM,
+	}, nil
+}
+
+// Sign generates a signature for the data
+func (s *KeylessSigner) Sign(data []byte) (signature []byte, publicKey crypto.PublicKey, err error) {
+	hash := sha256.Sum256(data)
+
+	privateKey := s.signer.PrivateKey()
+	r, sigS, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	// Encode signature (r || s), each padded to 32 bytes
+	signature = make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sigS.FillBytes(signature[32:])
+
+	return signature, &privateKey.PublicKey, nil
+}
+
+// Identity returns the email or URI the certificate was issued to
+func (s *KeylessSigner) Identity() string {
+	return s.signer.Identity()
+}
+
+// PublicKey returns the DER-encoded public key
+func (s *KeylessSigner) PublicKey() ([]byte, error) {
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(&s.signer.PrivateKey().PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return pubKeyBytes, nil
+}
+
+// Certificate returns the Fulcio certificate chain as PEM
+func (s *KeylessSigner) Certificate() string {
+	return s.chainPEM
+}
@@ -5,9 +5,23 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
+)
+
+var (
+	// ErrSignatureInvalid indicates a missing, malformed, or mismatched signature or certificate
+	ErrSignatureInvalid = errors.New("signature verification failed")
+
+	// ErrIdentityNotAllowed indicates the signer is not an allowed identity
+	ErrIdentityNotAllowed = errors.New("signer identity not allowed")
+
+	// ErrHashMismatch indicates the plan or output differs from what was attested
+	ErrHashMismatch = errors.New("hash mismatch")
 )
 
 // StandardVerifier implements basic signature verification
@@ -16,6 +30,7 @@ type StandardVerifier struct {
 	maxAge            time.Duration
 	requireGitClean   bool
 	allowedIdentities []string
+	trustedRoots      *x509.CertPool
 }
 
 // VerifierOption is a functional option for configuring the verifier
@@ -35,13 +50,22 @@ func WithRequireGitClean(require bool) VerifierOption {
 	}
 }
 
-// WithAllowedIdentities restricts allowed signer identities
+// WithAllowedIdentities restricts allowed signer identities. The identity
+// is only verified by a certificate, so WithTrustedRoots is also required.
 func WithAllowedIdentities(identities []string) VerifierOption {
 	return func(v *StandardVerifier) {
 		v.allowedIdentities = identities
 	}
 }
 
+// WithTrustedRoots requires a keyless signing certificate that chains to
+// these roots
+func WithTrustedRoots(roots *x509.CertPool) VerifierOption {
+	return func(v *StandardVerifier) {
+		v.trustedRoots = roots
+	}
+}
+
 // NewStandardVerifier creates a new verifier
 func NewStandardVerifier(opts ...VerifierOption) *StandardVerifier {
 	v := &StandardVerifier{
@@ -57,11 +81,12 @@ func NewStandardVerifier(opts ...VerifierOption) *StandardVerifier {
 	return v
 }
 
-// Verify checks the signature on an attestation
+// Verify checks the signature on an attestation. Signature and identity
+// failures wrap ErrSignatureInvalid and ErrIdentityNotAllowed.
 func (v *StandardVerifier) Verify(attestation *Attestation) error {
 	// 1. Verify signature is present
 	if attestation.Signature == "" || attestation.PublicKey == "" {
-		return fmt.Errorf("attestation is not signed")
+		return fmt.Errorf("%w: attestation is not signed", ErrSignatureInvalid)
 	}
 
 	// 2. Verify attestation age
@@ -72,8 +97,12 @@ func (v *StandardVerifier) Verify(attestation *Attestation) error {
 		}
 	}
 
-	// 3. Verify signer identity (if restricted)
+	// 3. Verify signer identity (if restricted). SignedBy is self-asserted;
+	// only a certificate that chains to the trusted roots vouches for it
 	if len(v.allowedIdentities) > 0 {
+		if v.trustedRoots == nil {
+			return fmt.Errorf("%w: %s cannot be verified without trusted roots", ErrIdentityNotAllowed, attestation.SignedBy)
+		}
 		allowed := false
 		for _, identity := range v.allowedIdentities {
 			if attestation.SignedBy == identity {
@@ -82,57 +111,85 @@ func (v *StandardVerifier) Verify(attestation *Attestation) error {
 			}
 		}
 		if !allowed {
-			return fmt.Errorf("signer identity not allowed: %s", attestation.SignedBy)
+			return fmt.Errorf("%w: %s", ErrIdentityNotAllowed, attestation.SignedBy)
 		}
 	}
 
 	// 4. Decode signature and public key
 	signature, err := DecodeSignature(attestation.Signature)
 	if err != nil {
-		return fmt.Errorf("failed to decode signature: %w", err)
+		return fmt.Errorf("%w: failed to decode signature: %v", ErrSignatureInvalid, err)
 	}
 
 	publicKeyBytes, err := DecodePublicKey(attestation.PublicKey)
 	if err != nil {
-		return fmt.Errorf("failed to decode public key: %w", err)
+		return fmt.Errorf("%w: failed to decode public key: %v", ErrSignatureInvalid, err)
 	}
 
 	// 5. Parse public key
 	publicKeyInterface, err := x509.ParsePKIXPublicKey(publicKeyBytes)
 	if err != nil {
-		return fmt.Errorf("failed to parse public key: %w", err)
+		return fmt.Errorf("%w: failed to parse public key: %v", ErrSignatureInvalid, err)
 	}
 
 	publicKey, ok := publicKeyInterface.(*ecdsa.PublicKey)
 	if !ok {
-		return fmt.Errorf("public key is not ECDSA")
+		return fmt.Errorf("%w: public key is not ECDSA", ErrSignatureInvalid)
+	}
+
+	// 6. Check that the certificate binds the key to the signer identity
+	if attestation.Certificate != "" {
+		if err := v.verifyCertificate(attestation, publicKey); err != nil {
+			return fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+		}
+	} else if v.trustedRoots != nil {
+		return fmt.Errorf("%w: attestation has no signing certificate to check against the trusted roots", ErrSignatureInvalid)
 	}
 
-	// 6. Recreate the data that was signed
+	// 7. Recreate the data that was signed
 	dataToVerify, err := v.recreateSignedData(attestation)
 	if err != nil {
 		return fmt.Errorf("failed to recreate signed data: %w", err)
 	}
 
-	// 7. Hash the data
+	// 8. Hash the data
 	hash := sha256.Sum256(dataToVerify)
 
-	// 8. Verify signature
+	// 9. Verify signature
 	// Signature is r || s, each 32 bytes for P-256
 	if len(signature) != 64 {
-		return fmt.Errorf("invalid signature length: %d", len(signature))
+		return fmt.Errorf("%w: invalid signature length: %d", ErrSignatureInvalid, len(signature))
 	}
 
 	r := new(big.Int).SetBytes(signature[:32])
 	s := new(big.Int).SetBytes(signature[32:])
 
 	if !ecdsa.Verify(publicKey, hash[:], r, s) {
-		return fmt.Errorf("signature verification failed")
+		return ErrSignatureInvalid
 	}
 
 	return nil
 }
 
+// verifyCertificate checks a keyless signing certificate: it was valid when
+// the attestation was signed, holds the signing key, and names the signer
+func (v *StandardVerifier) verifyCertificate(attestation *Attestation, publicKey *ecdsa.PublicKey) error {
+	leaf, err := keyless.VerifyCertificate(attestation.Certificate, attestation.SignedAt, v.trustedRoots)
+	if err != nil {
+		return err
+	}
+
+	certKey, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok || !certKey.Equal(publicKey) {
+		return fmt.Errorf("certificate does not match the public key")
+	}
+
+	if identity := keyless.CertificateIdentity(leaf); identity != attestation.SignedBy {
+		return fmt.Errorf("certificate identity %s does not match signer %s", identity, attestation.SignedBy)
+	}
+	return nil
+}
+
 // VerifyProvenance validates the provenance data
 func (v *StandardVerifier) VerifyProvenance(attestation *Attestation) error {
 	// 1. Verify required fields are present
@@ -178,27 +235,36 @@ func (v *StandardVerifier) VerifyProvenance(attestation *Attestation) error {
 	return nil
 }
 
-// VerifyHashes verifies the plan and output hashes
+// VerifyHashes verifies the plan and output hashes. The plan and output may
+// be the exact bytes that were hashed or another JSON encoding of the same
+// data, such as the indented plan.json in an output directory. Mismatches
+// wrap ErrHashMismatch.
 func (v *StandardVerifier) VerifyHashes(attestation *Attestation, planJSON []byte, outputJSON []byte) error {
-	// Compute hashes
-	planHash := hashData(planJSON)
-	outputHash := hashData(outputJSON)
-
 	// Verify plan hash
-	if planHash != attestation.PlanHash {
-		return fmt.Errorf("plan hash mismatch: expected %s, got %s",
-			attestation.PlanHash, planHash)
+	if !matchesHash(attestation.PlanHash, planJSON, canonicalPlanJSON) {
+		return fmt.Errorf("plan %w: expected %s, got %s",
+			ErrHashMismatch, attestation.PlanHash, hashData(planJSON))
 	}
 
 	// Verify output hash
-	if outputHash != attestation.OutputHash {
-		return fmt.Errorf("output hash mismatch: expected %s, got %s",
-			attestation.OutputHash, outputHash)
+	if !matchesHash(attestation.OutputHash, outputJSON, canonicalOutputJSON) {
+		return fmt.Errorf("output %w: expected %s, got %s",
+			ErrHashMismatch, attestation.OutputHash, hashData(outputJSON))
 	}
 
 	return nil
 }
 
+// matchesHash reports whether data, as is or re-encoded by canonical,
+// hashes to expected
+func matchesHash(expected string, data []byte, canonical func([]byte) ([]byte, error)) bool {
+	if hashData(data) == expected {
+		return true
+	}
+	encoded, err := canonical(data)
+	return err == nil && hashData(encoded) == expected
+}
+
 // recreateSignedData recreates the canonical data that was signed
 func (v *StandardVerifier) recreateSignedData(attestation *Attestation) ([]byte, error) {
 	// Create a copy without signature fields
@@ -1,10 +1,17 @@
 package attestation
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless/keylesstest"
 	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/plan"
 )
 
 func TestVerifyValidAttestation(t *testing.T) {
@@ -52,6 +59,116 @@ func TestVerifyValidAttestation(t *testing.T) {
 	}
 }
 
+func TestVerifyKeylessAttestation(t *testing.T) {
+	fulcio := keylesstest.NewFulcio(t)
+	signer, err := NewKeylessSigner(context.Background(), keyless.Options{
+		FulcioURL: fulcio.URL,
+		IDToken:   keylesstest.Token("dev@example.com"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keyless signer: %v", err)
+	}
+
+	result := &auto.Result{
+		Duration: time.Minute,
+		AutoOutput: &auto.AutoOutput{
+			Status: "completed",
+			Audit:  auto.AuditTrail{CheckpointID: "auto-1"},
+		},
+	}
+	att, err := NewGenerator(signer, "1.0.0").Generate(result, &auto.Config{}, []byte(`{}`), []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to generate attestation: %v", err)
+	}
+
+	if att.SignedBy != "dev@example.com" {
+		t.Errorf("SignedBy = %s, want dev@example.com", att.SignedBy)
+	}
+	if att.Certificate == "" || att.PublicKey == "" {
+		t.Fatal("expected a certificate and public key")
+	}
+
+	if err := NewStandardVerifier().Verify(att); err != nil {
+		t.Errorf("Verification failed: %v", err)
+	}
+	if err := NewStandardVerifier(WithTrustedRoots(fulcio.Roots)).Verify(att); err != nil {
+		t.Errorf("Verification with trusted roots failed: %v", err)
+	}
+
+	// The certificate is signed, so the identity cannot be swapped
+	tampered := *att
+	tampered.SignedBy = "mallory@example.com"
+	if err := NewStandardVerifier().Verify(&tampered); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected a changed signer to fail verification, got %v", err)
+	}
+
+	// Another CA is not trusted
+	other := keylesstest.NewFulcio(t)
+	if err := NewStandardVerifier(WithTrustedRoots(other.Roots)).Verify(att); err == nil {
+		t.Error("expected an untrusted certificate to fail verification")
+	}
+
+	// Trusted roots require a certificate
+	ephemeral, err := NewEphemeralSigner("dev@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := NewGenerator(ephemeral, "1.0.0").Generate(result, &auto.Config{}, []byte(`{}`), []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStandardVerifier(WithTrustedRoots(fulcio.Roots)).Verify(unsigned); err == nil ||
+		!strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected a missing certificate error, got %v", err)
+	}
+}
+
+// selfCertifiedSigner embeds a certificate its key signed itself
+type selfCertifiedSigner struct {
+	*EphemeralSigner
+	cert string
+}
+
+func (s selfCertifiedSigner) Certificate() string { return s.cert }
+
+func TestVerifySelfSignedCertificate(t *testing.T) {
+	ephemeral, err := NewEphemeralSigner("dev@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := selfCertifiedSigner{
+		EphemeralSigner: ephemeral,
+		cert:            string(keylesstest.SelfSigned(t, ephemeral.privateKey, "dev@example.com")),
+	}
+
+	result := &auto.Result{
+		Duration: time.Minute,
+		AutoOutput: &auto.AutoOutput{
+			Status: "completed",
+			Audit:  auto.AuditTrail{CheckpointID: "auto-1"},
+		},
+	}
+	att, err := NewGenerator(signer, "1.0.0").Generate(result, &auto.Config{}, []byte(`{}`), []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature is valid, but the matching identity is not trusted
+	if err := NewStandardVerifier().Verify(att); err != nil {
+		t.Fatalf("Verification failed: %v", err)
+	}
+	err = NewStandardVerifier(WithAllowedIdentities([]string{"dev@example.com"})).Verify(att)
+	if !errors.Is(err, ErrIdentityNotAllowed) {
+		t.Errorf("expected a self-signed identity to be rejected, got %v", err)
+	}
+
+	fulcio := keylesstest.NewFulcio(t)
+	err = NewStandardVerifier(WithAllowedIdentities([]string{"dev@example.com"}), WithTrustedRoots(fulcio.Roots)).Verify(att)
+	if !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected a self-signed certificate to fail the chain check, got %v", err)
+	}
+}
+
 func TestVerifyProvenanceValid(t *testing.T) {
 	att := &Attestation{
 		Status:    "success",
@@ -185,6 +302,42 @@ func TestVerifyHashesInvalid(t *testing.T) {
 	}
 }
 
+// TestVerifyHashesReencoded tests that other encodings of the attested plan
+// and output match, and that changed data does not
+func TestVerifyHashesReencoded(t *testing.T) {
+	execPlan := &plan.Plan{Tasks: []plan.Task{{ID: "task-1", FeatureID: "feat-1", Priority: "P0"}}}
+	output := auto.NewAutoOutput("goal", "default")
+
+	planJSON, err := PlanJSON(execPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputJSON, err := OutputJSON(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	att := &Attestation{PlanHash: hashData(planJSON), OutputHash: hashData(outputJSON)}
+
+	// plan.json is indented; --json output ends with a newline
+	indentedPlan, err := json.MarshalIndent(execPlan, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewStandardVerifier()
+	if err := verifier.VerifyHashes(att, indentedPlan, append(outputJSON, '\n')); err != nil {
+		t.Errorf("Expected re-encoded files to match: %v", err)
+	}
+
+	execPlan.Tasks[0].Priority = "P1"
+	changedPlan, err := json.MarshalIndent(execPlan, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.VerifyHashes(att, changedPlan, outputJSON); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Expected ErrHashMismatch for a changed plan, got %v", err)
+	}
+}
+
 func TestVerifyMaxAge(t *testing.T) {
 	// Create attestation signed 2 hours ago
 	att := &Attestation{
@@ -216,20 +369,31 @@ func TestVerifyAllowedIdentities(t *testing.T) {
 		PublicKey: "key",
 	}
 
+	roots := WithTrustedRoots(keylesstest.NewFulcio(t).Roots)
+
 	// Allowed identity
-	verifier := NewStandardVerifier(WithAllowedIdentities([]string{"test@example.com"}))
+	verifier := NewStandardVerifier(WithAllowedIdentities([]string{"test@example.com"}), roots)
 	err := verifier.Verify(att)
 	// Will fail for other reasons, but not identity
 	if err != nil && err.Error() == "signer identity not allowed: test@example.com" {
 		t.Error("Should not fail due to allowed identity")
 	}
 
+	// SignedBy is self-asserted, so an identity cannot be allowed without roots
+	err = NewStandardVerifier(WithAllowedIdentities([]string{"test@example.com"})).Verify(att)
+	if !errors.Is(err, ErrIdentityNotAllowed) {
+		t.Errorf("Expected ErrIdentityNotAllowed without trusted roots, got %v", err)
+	}
+
 	// Disallowed identity
-	verifier2 := NewStandardVerifier(WithAllowedIdentities([]string{"other@example.com"}))
+	verifier2 := NewStandardVerifier(WithAllowedIdentities([]string{"other@example.com"}), roots)
 	err = verifier2.Verify(att)
 	if err == nil || err.Error() != "signer identity not allowed: test@example.com" {
 		t.Error("Should fail due to disallowed identity")
 	}
+	if !errors.Is(err, ErrIdentityNotAllowed) {
+		t.Errorf("Expected ErrIdentityNotAllowed, got %v", err)
+	}
 }
 
 func TestVerifyRequireGitClean(t *testing.T) {
@@ -50,7 +50,8 @@ type AuditEntry struct {
 	Environment map[string]interface{} `json:"environment,omitempty"`
 
 	// Policy details
-	PolicyIDs []string `json:"policy_ids,omitempty"`
+	PolicyIDs        []string `json:"policy_ids,omitempty"`
+	DecisivePolicyID string   `json:"decisive_policy_id,omitempty"`
 
 	// Request metadata
 	RequestID string        `json:"request_id,omitempty"`
@@ -191,14 +192,15 @@ func (l *DefaultAuditLogger) Close() error {
 // NewAuditEntry creates an audit entry from an authorization request and decision.
 func NewAuditEntry(req *AuthorizationRequest, decision *Decision, duration time.Duration) *AuditEntry {
 	entry := &AuditEntry{
-		Timestamp:    decision.Timestamp,
-		Allowed:      decision.Allowed,
-		Reason:       decision.Reason,
-		Action:       req.Action,
-		ResourceType: req.Resource.Type,
-		ResourceID:   req.Resource.ID,
-		PolicyIDs:    decision.PolicyIDs,
-		Duration:     duration,
+		Timestamp:        decision.Timestamp,
+		Allowed:          decision.Allowed,
+		Reason:           decision.Reason,
+		Action:           req.Action,
+		ResourceType:     req.Resource.Type,
+		ResourceID:       req.Resource.ID,
+		PolicyIDs:        decision.PolicyIDs,
+		Duration:         duration,
+		DecisivePolicyID: decision.DecisivePolicyID,
 	}
 
 	// Extract subject information
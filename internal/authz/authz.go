@@ -2,6 +2,12 @@ package authz
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/auth"
@@ -35,6 +41,19 @@ const (
 	OperatorStringLike          ConditionOperator = "string_like"
 	OperatorExists              ConditionOperator = "exists"
 	OperatorNotExists           ConditionOperator = "not_exists"
+	// OperatorStartsWith checks if a string attribute starts with the specified prefix.
+	OperatorStartsWith ConditionOperator = "starts_with"
+	// OperatorMatches checks if a string attribute matches the specified regular expression.
+	OperatorMatches ConditionOperator = "matches"
+)
+
+var (
+	// ErrUnknownOperator is returned for a condition operator the engine does not support.
+	ErrUnknownOperator = errors.New("unknown condition operator")
+	// ErrInvalidConditionValue is returned when a condition value does not suit its operator.
+	ErrInvalidConditionValue = errors.New("invalid condition value")
+	// ErrConditionTypeMismatch is returned when a condition compares values of incompatible types.
+	ErrConditionTypeMismatch = errors.New("condition type mismatch")
 )
 
 // Role represents standard built-in roles (RBAC abstraction over ABAC).
@@ -57,7 +76,8 @@ type Policy struct {
 	Name           string      `json:"name"`
 	Description    string      `json:"description,omitempty"`
 	Version        int         `json:"version"`
-	Effect         Effect      `json:"effect"` // allow or deny
+	Effect         Effect      `json:"effect"`             // allow or deny
+	Priority       int         `json:"priority,omitempty"` // Higher priorities are evaluated first (default 0)
 	Principals     []Principal `json:"principals"`
 	Actions        []string    `json:"actions"`    // e.g., ["plan:approve", "build:run"]
 	Resources      []string    `json:"resources"`  // e.g., ["plan:*", "build:123"]
@@ -109,6 +129,8 @@ type Decision struct {
 	Reason    string    `json:"reason"`     // Human-readable explanation
 	PolicyIDs []string  `json:"policy_ids"` // Policies that contributed to decision
 	Timestamp time.Time `json:"timestamp"`
+
+	DecisivePolicyID string `json:"decisive_policy_id,omitempty"` // Policy that decided the request (empty for default deny)
 }
 
 // PolicyStore manages authorization policies.
@@ -127,6 +149,45 @@ type PolicyStore interface {
 
 	// GetPolicy retrieves a specific policy.
 	GetPolicy(ctx context.Context, policyID string) (*Policy, error)
+
+	// ListPolicies returns a page of an organization's policies, enabled or
+	// not, that match the filter.
+	ListPolicies(ctx context.Context, organizationID string, filter PolicyFilter) (*PolicyPage, error)
+}
+
+// PolicyFilter selects and pages policies in PolicyStore.ListPolicies.
+type PolicyFilter struct {
+	Enabled      *bool  // Only policies with this enabled status (nil for all)
+	Effect       Effect // Only policies with this effect (empty for all)
+	ActionPrefix string // Only policies with an action starting with this prefix
+	Offset       int    // Number of matching policies to skip
+	Limit        int    // Maximum number of policies to return (0 for no limit)
+}
+
+// PolicyPage is a page of policies and the total number matching the filter.
+type PolicyPage struct {
+	Policies   []*Policy `json:"policies"`
+	Total      int       `json:"total"`                 // Matching policies across all pages
+	NextCursor string    `json:"next_cursor,omitempty"` // Cursor for the next page (empty on the last page)
+}
+
+// matches reports whether a policy passes the filter's criteria.
+func (f PolicyFilter) matches(policy *Policy) bool {
+	if f.Enabled != nil && policy.Enabled != *f.Enabled {
+		return false
+	}
+	if f.Effect != "" && policy.Effect != f.Effect {
+		return false
+	}
+	if f.ActionPrefix == "" {
+		return true
+	}
+	for _, action := range policy.Actions {
+		if strings.HasPrefix(action, f.ActionPrefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // AttributeResolver resolves attributes for authorization decisions.
@@ -161,19 +222,40 @@ func NewEngine(policyStore PolicyStore, attrResolver AttributeResolver) *Engine
 
 // Evaluate evaluates an authorization request.
 //
-// Algorithm (AWS IAM-style):
-// 1. Default decision: DENY
-// 2. Load all policies for the organization
-// 3. Filter policies that match principal, action, and resource
-// 4. If any policy has effect: deny → DENY (explicit deny wins)
-// 5. If any policy has effect: allow and all conditions pass → ALLOW
-// 6. Otherwise → DENY
+// Algorithm (AWS IAM-style, with priorities):
+//  1. Default decision: DENY
+//  2. Load all policies for the organization
+//  3. Filter policies that match principal, action, and resource and whose
+//     conditions pass
+//  4. Only the matching policies with the highest Priority decide; a higher
+//     priority wins regardless of effect
+//  5. At that priority, any policy with effect: deny → DENY (explicit deny
+//     overrides allow); otherwise → ALLOW
+//  6. No matching policy → DENY
 //
-//nolint:gocyclo // Authorization evaluation requires complex branching logic
+// Decision.PolicyIDs lists the deciding policies: those at the deciding
+// priority with the deciding effect, ordered by ID. The tie-break between
+// them is the lowest ID, which is reported as Decision.DecisivePolicyID, so
+// the decisive policy does not depend on the order policies are stored in.
 func (e *Engine) Evaluate(ctx context.Context, req *AuthorizationRequest) (*Decision, error) {
 	// Start timing for audit log
 	startTime := time.Now()
 
+	decision, _, err := e.evaluate(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	e.logDecision(ctx, req, decision, time.Since(startTime))
+	return decision, nil
+}
+
+// evaluate decides a request against the organization's policies, with
+// candidate (if any) added to them, and returns the decision and the decisive
+// policy. It does not log the decision.
+//
+//nolint:gocyclo // Authorization evaluation requires complex branching logic
+func (e *Engine) evaluate(ctx context.Context, req *AuthorizationRequest, candidate *Policy) (*Decision, *Policy, error) {
 	// Default deny
 	decision := &Decision{
 		Allowed:   false,
@@ -185,42 +267,42 @@ func (e *Engine) Evaluate(ctx context.Context, req *AuthorizationRequest) (*Deci
 	// Get organization ID from session
 	if req.Subject == nil {
 		decision.Reason = "no authenticated subject"
-		e.logDecision(ctx, req, decision, time.Since(startTime))
-		return decision, nil
+		return decision, nil, nil
 	}
 
 	organizationID := req.Subject.OrganizationID
 	if organizationID == "" {
 		decision.Reason = "subject not associated with organization"
-		e.logDecision(ctx, req, decision, time.Since(startTime))
-		return decision, nil
+		return decision, nil, nil
 	}
 
 	// Load policies for organization
 	policies, err := e.policyStore.LoadPolicies(ctx, organizationID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if candidate != nil {
+		policies = withCandidate(policies, candidate)
 	}
 
 	// Get subject and resource attributes
 	subjectAttrs, err := e.attrResolver.GetSubjectAttributes(ctx, req.Subject)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var resourceAttrs Attributes
 	if req.Resource.ID != "" {
 		resourceAttrs, err = e.attrResolver.GetResourceAttributes(ctx, req.Resource.Type, req.Resource.ID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		resourceAttrs = make(Attributes)
 	}
 
 	// Evaluate policies
-	var denyPolicies []string
-	var allowPolicies []string
+	var matched []*Policy
 
 	for _, policy := range policies {
 		if !policy.Enabled {
@@ -236,7 +318,7 @@ func (e *Engine) Evaluate(ctx context.Context, req *AuthorizationRequest) (*Deci
 		var conditionsPassed bool
 		conditionsPassed, err = e.evaluateConditions(policy.Conditions, subjectAttrs, resourceAttrs, req.Environment)
 		if err != nil {
-			return nil, err
+			return nil, nil, fmt.Errorf("policy %s: %w", policy.ID, err)
 		}
 
 		if !conditionsPassed {
@@ -244,34 +326,70 @@ func (e *Engine) Evaluate(ctx context.Context, req *AuthorizationRequest) (*Deci
 		}
 
 		// Track matching policy
-		if policy.Effect == EffectDeny {
-			denyPolicies = append(denyPolicies, policy.ID)
-		} else if policy.Effect == EffectAllow {
-			allowPolicies = append(allowPolicies, policy.ID)
+		if policy.Effect == EffectDeny || policy.Effect == EffectAllow {
+			matched = append(matched, policy)
 		}
 	}
 
-	// Explicit deny wins
-	if len(denyPolicies) > 0 {
-		decision.Allowed = false
-		decision.Reason = "access explicitly denied by policy"
-		decision.PolicyIDs = denyPolicies
-		e.logDecision(ctx, req, decision, time.Since(startTime))
-		return decision, nil
+	deciding := decidingPolicies(matched)
+	if len(deciding) == 0 {
+		return decision, nil, nil
 	}
 
-	// Allow if at least one allow policy matched
-	if len(allowPolicies) > 0 {
+	decision.PolicyIDs = make([]string, len(deciding))
+	for i, policy := range deciding {
+		decision.PolicyIDs[i] = policy.ID
+	}
+	decisive := deciding[0]
+	decision.DecisivePolicyID = decisive.ID
+
+	if decisive.Effect == EffectDeny {
+		decision.Allowed = false
+		decision.Reason = fmt.Sprintf("access explicitly denied by policy %s", decisive.ID)
+	} else {
 		decision.Allowed = true
-		decision.Reason = "access granted by policy"
-		decision.PolicyIDs = allowPolicies
-		e.logDecision(ctx, req, decision, time.Since(startTime))
-		return decision, nil
+		decision.Reason = fmt.Sprintf("access granted by policy %s", decisive.ID)
+	}
+	if decisive.Priority != 0 {
+		decision.Reason += fmt.Sprintf(" (priority %d)", decisive.Priority)
 	}
 
-	// Default deny
-	e.logDecision(ctx, req, decision, time.Since(startTime))
-	return decision, nil
+	return decision, decisive, nil
+}
+
+// decidingPolicies returns the matched policies that decide a request: those
+// with the highest priority and, at that priority, the deny policies if there
+// are any, else the allow policies. They are ordered by ID.
+func decidingPolicies(matched []*Policy) []*Policy {
+	if len(matched) == 0 {
+		return nil
+	}
+
+	top := matched[0].Priority
+	for _, policy := range matched[1:] {
+		if policy.Priority > top {
+			top = policy.Priority
+		}
+	}
+
+	var deny, allow []*Policy
+	for _, policy := range matched {
+		if policy.Priority != top {
+			continue
+		}
+		if policy.Effect == EffectDeny {
+			deny = append(deny, policy)
+		} else {
+			allow = append(allow, policy)
+		}
+	}
+
+	deciding := allow
+	if len(deny) > 0 {
+		deciding = deny
+	}
+	sort.Slice(deciding, func(i, j int) bool { return deciding[i].ID < deciding[j].ID })
+	return deciding
 }
 
 // policyMatches checks if a policy matches the request.
@@ -312,8 +430,9 @@ func (e *Engine) principalMatches(principals []Principal, subjectAttrs Attribute
 			}
 		} else if principal.Attribute != "" {
 			// Attribute-based principal
+			// A principal that cannot be evaluated does not match
 			attrValue := e.resolveAttribute(principal.Attribute, subjectAttrs, nil, nil)
-			if e.evaluateOperator(principal.Operator, attrValue, principal.Value) {
+			if ok, err := e.evaluateOperator(principal.Operator, attrValue, principal.Value); err == nil && ok {
 				return true
 			}
 		}
@@ -375,7 +494,11 @@ func (e *Engine) evaluateConditions(conditions []Condition, subjectAttrs, resour
 			conditionValue = e.resolveAttribute(strValue, subjectAttrs, resourceAttrs, env)
 		}
 
-		if !e.evaluateOperator(condition.Operator, attrValue, conditionValue) {
+		ok, err := e.evaluateOperator(condition.Operator, attrValue, conditionValue)
+		if err != nil {
+			return false, fmt.Errorf("condition on %s: %w", condition.Attribute, err)
+		}
+		if !ok {
 			return false, nil
 		}
 	}
@@ -421,66 +544,203 @@ func (e *Engine) resolveAttribute(attrPath string, subjectAttrs, resourceAttrs A
 	return nil
 }
 
-// evaluateOperator evaluates a condition operator.
+// evaluateOperator evaluates a condition operator. Numbers compare as numbers
+// whatever their Go type and strings compare lexically; ordering a string
+// against a number is an ErrConditionTypeMismatch. A missing (nil) attribute
+// does not satisfy any operator other than not_equals, not_in and not_exists.
 //
 //nolint:gocyclo // Operator evaluation requires comprehensive type handling
-func (e *Engine) evaluateOperator(op ConditionOperator, left, right interface{}) bool {
+func (e *Engine) evaluateOperator(op ConditionOperator, left, right interface{}) (bool, error) {
 	switch op {
 	case OperatorEquals:
-		return left == right
+		return valuesEqual(left, right), nil
 
 	case OperatorNotEquals:
-		return left != right
-
-	case OperatorIn:
-		if rightArray, ok := right.([]interface{}); ok {
-			for _, item := range rightArray {
-				if left == item {
-					return true
-				}
-			}
-		}
-		return false
+		return !valuesEqual(left, right), nil
 
-	case OperatorNotIn:
-		if rightArray, ok := right.([]interface{}); ok {
-			for _, item := range rightArray {
-				if left == item {
-					return false
-				}
+	case OperatorIn, OperatorNotIn:
+		items, ok := listValues(right)
+		if !ok {
+			return false, fmt.Errorf("%w: %s requires a list, got %s", ErrInvalidConditionValue, op, typeName(right))
+		}
+		found := false
+		for _, item := range items {
+			if valuesEqual(left, item) {
+				found = true
+				break
 			}
-			return true
 		}
-		return false
-
-	case OperatorGreaterThan:
-		return compareNumbers(left, right) > 0
+		return found == (op == OperatorIn), nil
 
-	case OperatorLessThan:
-		return compareNumbers(left, right) < 0
-
-	case OperatorGreaterThanOrEquals:
-		return compareNumbers(left, right) >= 0
-
-	case OperatorLessThanOrEquals:
-		return compareNumbers(left, right) <= 0
+	case OperatorGreaterThan, OperatorLessThan, OperatorGreaterThanOrEquals, OperatorLessThanOrEquals:
+		if left == nil || right == nil {
+			return false, nil
+		}
+		cmp, err := compareValues(left, right)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		switch op {
+		case OperatorGreaterThan:
+			return cmp > 0, nil
+		case OperatorLessThan:
+			return cmp < 0, nil
+		case OperatorGreaterThanOrEquals:
+			return cmp >= 0, nil
+		default:
+			return cmp <= 0, nil
+		}
 
 	case OperatorStringLike:
 		leftStr, lok := left.(string)
 		rightStr, rok := right.(string)
 		if lok && rok {
-			return matchGlob(leftStr, rightStr)
+			return matchGlob(leftStr, rightStr), nil
 		}
-		return false
+		return false, nil
+
+	case OperatorStartsWith, OperatorMatches:
+		if left == nil {
+			return false, nil
+		}
+		pattern, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("%w: %s requires a string, got %s", ErrInvalidConditionValue, op, typeName(right))
+		}
+		text, ok := left.(string)
+		if !ok {
+			return false, fmt.Errorf("%w: %s cannot be applied to a %s", ErrConditionTypeMismatch, op, typeName(left))
+		}
+		if op == OperatorStartsWith {
+			return strings.HasPrefix(text, pattern), nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s pattern %q: %v", ErrInvalidConditionValue, op, pattern, err)
+		}
+		return re.MatchString(text), nil
 
 	case OperatorExists:
-		return left != nil
+		return left != nil, nil
 
 	case OperatorNotExists:
-		return left == nil
+		return left == nil, nil
 
 	default:
-		return false
+		return false, fmt.Errorf("%w: %q", ErrUnknownOperator, op)
+	}
+}
+
+// validateOperator checks that an operator is supported and that a literal
+// value suits it. Attribute references ($subject.x) are only known at
+// evaluation time and are not checked.
+func validateOperator(op ConditionOperator, value interface{}) error {
+	if ref, ok := value.(string); ok && len(ref) > 0 && ref[0] == '$' {
+		if _, known := operatorNames[op]; !known {
+			return fmt.Errorf("%w: %q", ErrUnknownOperator, op)
+		}
+		return nil
+	}
+
+	switch op {
+	case OperatorEquals, OperatorNotEquals, OperatorStringLike, OperatorExists, OperatorNotExists:
+		return nil
+	case OperatorIn, OperatorNotIn:
+		if _, ok := listValues(value); !ok {
+			return fmt.Errorf("%w: %s requires a list, got %s", ErrInvalidConditionValue, op, typeName(value))
+		}
+	case OperatorGreaterThan, OperatorLessThan, OperatorGreaterThanOrEquals, OperatorLessThanOrEquals:
+		if _, isNum := toFloat64(value); !isNum {
+			if _, isStr := value.(string); !isStr {
+				return fmt.Errorf("%w: %s requires a number or string, got %s", ErrInvalidConditionValue, op, typeName(value))
+			}
+		}
+	case OperatorStartsWith, OperatorMatches:
+		pattern, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s requires a string, got %s", ErrInvalidConditionValue, op, typeName(value))
+		}
+		if op == OperatorMatches {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("%w: %s pattern %q: %v", ErrInvalidConditionValue, op, pattern, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOperator, op)
+	}
+	return nil
+}
+
+// operatorNames is the set of supported condition operators.
+var operatorNames = map[ConditionOperator]struct{}{
+	OperatorEquals: {}, OperatorNotEquals: {}, OperatorIn: {}, OperatorNotIn: {},
+	OperatorGreaterThan: {}, OperatorLessThan: {}, OperatorGreaterThanOrEquals: {}, OperatorLessThanOrEquals: {},
+	OperatorStringLike: {}, OperatorStartsWith: {}, OperatorMatches: {}, OperatorExists: {}, OperatorNotExists: {},
+}
+
+// valuesEqual compares two values, treating numbers of any type as equal when
+// their values are.
+func valuesEqual(left, right interface{}) bool {
+	leftNum, lok := toFloat64(left)
+	rightNum, rok := toFloat64(right)
+	if lok && rok {
+		return leftNum == rightNum
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// listValues returns the items of a list value, as decoded from JSON or
+// built in Go.
+func listValues(val interface{}) ([]interface{}, bool) {
+	switch v := val.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// compareValues orders two numbers or two strings. Any other combination is
+// an ErrConditionTypeMismatch.
+func compareValues(left, right interface{}) (int, error) {
+	_, lnum := toFloat64(left)
+	_, rnum := toFloat64(right)
+	if lnum && rnum {
+		return compareNumbers(left, right), nil
+	}
+
+	leftStr, lstr := left.(string)
+	rightStr, rstr := right.(string)
+	if lstr && rstr {
+		return strings.Compare(leftStr, rightStr), nil
+	}
+
+	return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrConditionTypeMismatch, typeName(left), typeName(right))
+}
+
+// typeName describes a condition value's type for error messages.
+func typeName(val interface{}) string {
+	if _, ok := toFloat64(val); ok {
+		return "number"
+	}
+	if _, ok := listValues(val); ok {
+		return "list"
+	}
+	switch val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	default:
+		return fmt.Sprintf("%T", val)
 	}
 }
 
@@ -92,6 +92,107 @@ func TestEngine_Evaluate_ExplicitDenyWins(t *testing.T) {
 	assert.Contains(t, decision.PolicyIDs, "deny-policy")
 }
 
+// TestEngine_Evaluate_Priority tests overlapping allow and deny policies on
+// the same action and resource
+func TestEngine_Evaluate_Priority(t *testing.T) {
+	policy := func(id string, effect Effect, priority int) *Policy {
+		return &Policy{
+			ID:             id,
+			OrganizationID: "org-1",
+			Name:           id,
+			Effect:         effect,
+			Priority:       priority,
+			Principals:     []Principal{{Role: string(RoleAdmin)}},
+			Actions:        []string{"plan:approve"},
+			Resources:      []string{"plan:*"},
+			Enabled:        true,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		policies     []*Policy
+		wantAllowed  bool
+		wantDecisive string
+		wantIDs      []string
+	}{
+		{
+			name:         "deny overrides allow at equal priority",
+			policies:     []*Policy{policy("allow", EffectAllow, 0), policy("deny", EffectDeny, 0)},
+			wantAllowed:  false,
+			wantDecisive: "deny",
+			wantIDs:      []string{"deny"},
+		},
+		{
+			name:         "higher priority allow wins over deny",
+			policies:     []*Policy{policy("deny", EffectDeny, 0), policy("allow", EffectAllow, 10)},
+			wantAllowed:  true,
+			wantDecisive: "allow",
+			wantIDs:      []string{"allow"},
+		},
+		{
+			name:         "higher priority deny wins over allow",
+			policies:     []*Policy{policy("allow", EffectAllow, 5), policy("deny", EffectDeny, 10)},
+			wantAllowed:  false,
+			wantDecisive: "deny",
+			wantIDs:      []string{"deny"},
+		},
+		{
+			name: "lower priorities are ignored",
+			policies: []*Policy{
+				policy("deny-low", EffectDeny, -1),
+				policy("allow-b", EffectAllow, 3),
+				policy("allow-a", EffectAllow, 3),
+			},
+			wantAllowed:  true,
+			wantDecisive: "allow-a",
+			wantIDs:      []string{"allow-a", "allow-b"},
+		},
+		{
+			name: "tie between denies goes to the lowest ID",
+			policies: []*Policy{
+				policy("deny-z", EffectDeny, 1),
+				policy("allow", EffectAllow, 1),
+				policy("deny-m", EffectDeny, 1),
+			},
+			wantAllowed:  false,
+			wantDecisive: "deny-m",
+			wantIDs:      []string{"deny-m", "deny-z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewInMemoryPolicyStore()
+			logger := NewInMemoryAuditLogger()
+			engine := WithAuditLogger(NewEngine(store, NewDefaultAttributeResolver(NewInMemoryResourceStore())), logger)
+			for _, p := range tt.policies {
+				require.NoError(t, store.CreatePolicy(context.Background(), p))
+			}
+
+			decision, err := engine.Evaluate(context.Background(), &AuthorizationRequest{
+				Subject: &auth.Session{
+					UserID:           "user-123",
+					OrganizationID:   "org-1",
+					OrganizationRole: string(RoleAdmin),
+				},
+				Action:   "plan:approve",
+				Resource: Resource{Type: "plan", ID: "plan-123"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, decision.Allowed)
+			assert.Equal(t, tt.wantDecisive, decision.DecisivePolicyID)
+			assert.Equal(t, tt.wantIDs, decision.PolicyIDs)
+			assert.Contains(t, decision.Reason, tt.wantDecisive)
+
+			// The audit log explains the decision
+			entries := logger.GetEntries()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.wantDecisive, entries[0].DecisivePolicyID)
+		})
+	}
+}
+
 func TestEngine_Evaluate_AllowWithMatchingPolicy(t *testing.T) {
 	store := NewInMemoryPolicyStore()
 	resolver := NewDefaultAttributeResolver(NewInMemoryResourceStore())
@@ -393,48 +494,178 @@ func TestEngine_Evaluate_NoOrganization(t *testing.T) {
 	assert.Contains(t, decision.Reason, "not associated with organization")
 }
 
-func TestEvaluateOperator_Equals(t *testing.T) {
+func TestEvaluateOperator(t *testing.T) {
 	engine := &Engine{}
 
-	assert.True(t, engine.evaluateOperator(OperatorEquals, "foo", "foo"))
-	assert.False(t, engine.evaluateOperator(OperatorEquals, "foo", "bar"))
-	assert.True(t, engine.evaluateOperator(OperatorEquals, 123, 123))
-	assert.False(t, engine.evaluateOperator(OperatorEquals, 123, 456))
-}
-
-func TestEvaluateOperator_In(t *testing.T) {
-	engine := &Engine{}
+	tests := []struct {
+		name  string
+		op    ConditionOperator
+		left  interface{}
+		right interface{}
+		want  bool
+	}{
+		{"equals strings", OperatorEquals, "foo", "foo", true},
+		{"equals different strings", OperatorEquals, "foo", "bar", false},
+		{"equals ints", OperatorEquals, 123, 123, true},
+		{"equals different ints", OperatorEquals, 123, 456, false},
+		{"equals int and JSON number", OperatorEquals, 5, float64(5), true},
+		{"equals string and number", OperatorEquals, "5", 5, false},
+		{"equals missing", OperatorEquals, nil, "foo", false},
+
+		{"not_equals strings", OperatorNotEquals, "foo", "bar", true},
+		{"not_equals same strings", OperatorNotEquals, "foo", "foo", false},
+		{"not_equals int and JSON number", OperatorNotEquals, 5, float64(5), false},
+		{"not_equals missing", OperatorNotEquals, nil, "foo", true},
+
+		{"in strings", OperatorIn, "foo", []interface{}{"foo", "bar"}, true},
+		{"in strings absent", OperatorIn, "baz", []interface{}{"foo", "bar"}, false},
+		{"in ints", OperatorIn, 2, []interface{}{1, 2, 3}, true},
+		{"in JSON numbers", OperatorIn, 2, []interface{}{float64(1), float64(2)}, true},
+		{"in string slice", OperatorIn, "foo", []string{"foo", "bar"}, true},
+		{"in missing", OperatorIn, nil, []interface{}{"foo"}, false},
+
+		{"not_in absent", OperatorNotIn, "baz", []interface{}{"foo", "bar"}, true},
+		{"not_in present", OperatorNotIn, "foo", []interface{}{"foo", "bar"}, false},
+		{"not_in missing", OperatorNotIn, nil, []interface{}{"foo"}, true},
+
+		{"greater_than ints", OperatorGreaterThan, 10, 5, true},
+		{"greater_than smaller", OperatorGreaterThan, 5, 10, false},
+		{"greater_than equal", OperatorGreaterThan, 5, 5, false},
+		{"greater_than floats", OperatorGreaterThan, 10.5, 10.2, true},
+		{"greater_than strings", OperatorGreaterThan, "beta", "alpha", true},
+		{"greater_than missing", OperatorGreaterThan, nil, 5, false},
+
+		{"less_than ints", OperatorLessThan, 5, 10, true},
+		{"less_than larger", OperatorLessThan, 10, 5, false},
+		{"less_than int and JSON number", OperatorLessThan, 3, float64(3.5), true},
+		{"less_than strings", OperatorLessThan, "2026-01-01", "2026-06-01", true},
+		{"less_than missing", OperatorLessThan, nil, 5, false},
+
+		{"greater_than_or_equals equal", OperatorGreaterThanOrEquals, 5, 5, true},
+		{"less_than_or_equals equal", OperatorLessThanOrEquals, 5, 5, true},
+		{"less_than_or_equals larger", OperatorLessThanOrEquals, 6, 5, false},
+
+		{"string_like suffix", OperatorStringLike, "foobar", "*bar", true},
+		{"string_like prefix", OperatorStringLike, "foobar", "foo*", true},
+		{"string_like any", OperatorStringLike, "foobar", "*", true},
+		{"string_like no match", OperatorStringLike, "foobar", "*baz", false},
+
+		{"starts_with", OperatorStartsWith, "projects/alpha", "projects/", true},
+		{"starts_with no match", OperatorStartsWith, "teams/alpha", "projects/", false},
+		{"starts_with missing", OperatorStartsWith, nil, "projects/", false},
+
+		{"matches", OperatorMatches, "release-1.2.3", `^release-\d+\.\d+\.\d+$`, true},
+		{"matches no match", OperatorMatches, "release-next", `^release-\d+`, false},
+		{"matches missing", OperatorMatches, nil, `.*`, false},
+
+		{"exists", OperatorExists, "value", nil, true},
+		{"exists missing", OperatorExists, nil, nil, false},
+		{"not_exists missing", OperatorNotExists, nil, nil, true},
+		{"not_exists present", OperatorNotExists, "value", nil, false},
+	}
 
-	assert.True(t, engine.evaluateOperator(OperatorIn, "foo", []interface{}{"foo", "bar"}))
-	assert.False(t, engine.evaluateOperator(OperatorIn, "baz", []interface{}{"foo", "bar"}))
-	assert.True(t, engine.evaluateOperator(OperatorIn, 2, []interface{}{1, 2, 3}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.evaluateOperator(tt.op, tt.left, tt.right)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
 }
 
-func TestEvaluateOperator_GreaterThan(t *testing.T) {
+func TestEvaluateOperator_Errors(t *testing.T) {
 	engine := &Engine{}
 
-	assert.True(t, engine.evaluateOperator(OperatorGreaterThan, 10, 5))
-	assert.False(t, engine.evaluateOperator(OperatorGreaterThan, 5, 10))
-	assert.False(t, engine.evaluateOperator(OperatorGreaterThan, 5, 5))
-	assert.True(t, engine.evaluateOperator(OperatorGreaterThan, 10.5, 10.2))
+	tests := []struct {
+		name    string
+		op      ConditionOperator
+		left    interface{}
+		right   interface{}
+		wantErr error
+		message string
+	}{
+		{"unknown operator", ConditionOperator("contains"), "foo", "f", ErrUnknownOperator, `"contains"`},
+		{"in without list", OperatorIn, "foo", "foo", ErrInvalidConditionValue, "requires a list"},
+		{"not_in without list", OperatorNotIn, "foo", 5, ErrInvalidConditionValue, "requires a list"},
+		{"greater_than string and number", OperatorGreaterThan, "10", 5, ErrConditionTypeMismatch, "cannot compare string with number"},
+		{"less_than number and string", OperatorLessThan, 5, "10", ErrConditionTypeMismatch, "cannot compare number with string"},
+		{"less_than_or_equals bool", OperatorLessThanOrEquals, true, 5, ErrConditionTypeMismatch, "cannot compare bool with number"},
+		{"starts_with number", OperatorStartsWith, 42, "4", ErrConditionTypeMismatch, "cannot be applied to a number"},
+		{"starts_with non-string prefix", OperatorStartsWith, "42", 4, ErrInvalidConditionValue, "requires a string"},
+		{"matches invalid pattern", OperatorMatches, "foo", "(", ErrInvalidConditionValue, "pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := engine.evaluateOperator(tt.op, tt.left, tt.right)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
 }
 
-func TestEvaluateOperator_StringLike(t *testing.T) {
-	engine := &Engine{}
+func TestValidateOperator(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      ConditionOperator
+		value   interface{}
+		wantErr error
+	}{
+		{"equals", OperatorEquals, "pending", nil},
+		{"in list", OperatorIn, []interface{}{"a", "b"}, nil},
+		{"greater_than number", OperatorGreaterThan, float64(10), nil},
+		{"less_than string", OperatorLessThan, "2026-01-01", nil},
+		{"starts_with", OperatorStartsWith, "projects/", nil},
+		{"matches", OperatorMatches, `^v\d+$`, nil},
+		{"attribute reference", OperatorIn, "$subject.teams", nil},
+		{"unknown", ConditionOperator("contains"), "x", ErrUnknownOperator},
+		{"unknown with reference", ConditionOperator("contains"), "$subject.x", ErrUnknownOperator},
+		{"in scalar", OperatorIn, "a", ErrInvalidConditionValue},
+		{"greater_than bool", OperatorGreaterThan, true, ErrInvalidConditionValue},
+		{"starts_with number", OperatorStartsWith, float64(1), ErrInvalidConditionValue},
+		{"matches invalid", OperatorMatches, "[", ErrInvalidConditionValue},
+	}
 
-	assert.True(t, engine.evaluateOperator(OperatorStringLike, "foobar", "*bar"))
-	assert.True(t, engine.evaluateOperator(OperatorStringLike, "foobar", "foo*"))
-	assert.True(t, engine.evaluateOperator(OperatorStringLike, "foobar", "*"))
-	assert.False(t, engine.evaluateOperator(OperatorStringLike, "foobar", "*baz"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOperator(tt.op, tt.value)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
 }
 
-func TestEvaluateOperator_Exists(t *testing.T) {
-	engine := &Engine{}
+func TestEngine_Evaluate_ConditionTypeMismatch(t *testing.T) {
+	store := NewInMemoryPolicyStore()
+	resourceStore := NewInMemoryResourceStore()
+	resolver := NewDefaultAttributeResolver(resourceStore)
+	engine := NewEngine(store, resolver)
 
-	assert.True(t, engine.evaluateOperator(OperatorExists, "value", nil))
-	assert.False(t, engine.evaluateOperator(OperatorExists, nil, nil))
-	assert.True(t, engine.evaluateOperator(OperatorNotExists, nil, nil))
-	assert.False(t, engine.evaluateOperator(OperatorNotExists, "value", nil))
+	require.NoError(t, store.CreatePolicy(context.Background(), &Policy{
+		ID:             "policy-1",
+		OrganizationID: "org-1",
+		Effect:         EffectAllow,
+		Actions:        []string{"build:run"},
+		Resources:      []string{"build:*"},
+		Conditions: []Condition{
+			{Attribute: "$resource.cost", Operator: OperatorLessThan, Value: float64(100)},
+		},
+		Enabled: true,
+	}))
+	resourceStore.SetResourceAttributes("build", "build-1", Attributes{"cost": "cheap"})
+
+	req := &AuthorizationRequest{
+		Subject:  &auth.Session{UserID: "user-1", OrganizationID: "org-1"},
+		Action:   "build:run",
+		Resource: Resource{Type: "build", ID: "build-1"},
+	}
+	_, err := engine.Evaluate(context.Background(), req)
+	assert.ErrorIs(t, err, ErrConditionTypeMismatch)
+	assert.ErrorContains(t, err, "policy-1")
+	assert.ErrorContains(t, err, "$resource.cost")
 }
 
 func TestResolveAttribute_SubjectAttributes(t *testing.T) {
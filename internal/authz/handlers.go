@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/felixgeelhaar/specular/internal/auth"
@@ -26,6 +27,7 @@ func NewPolicyHandlers(policyStore PolicyStore, engine *Engine) *PolicyHandlers
 // RegisterRoutes registers policy management routes on the provided mux.
 //
 // Routes:
+//   - GET    /api/policies          - List policies (filtered and paginated)
 //   - POST   /api/policies          - Create a new policy
 //   - GET    /api/policies/:id      - Get a specific policy
 //   - PUT    /api/policies/:id      - Update a policy
@@ -40,6 +42,8 @@ func (h *PolicyHandlers) RegisterRoutes(mux *http.ServeMux) {
 // handlePolicies handles listing and creating policies.
 func (h *PolicyHandlers) handlePolicies(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
+	case http.MethodGet:
+		h.listPolicies(w, r)
 	case http.MethodPost:
 		h.createPolicy(w, r)
 	default:
@@ -68,6 +72,92 @@ func (h *PolicyHandlers) handlePolicy(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Page sizes for listing policies.
+const (
+	defaultPolicyPageSize = 50
+	maxPolicyPageSize     = 100
+)
+
+// listPolicies lists the organization's policies, a page at a time.
+//
+// Query parameters:
+//   - enabled:       "true" or "false" to filter by enabled status
+//   - effect:        "allow" or "deny" to filter by effect
+//   - action_prefix: only policies with an action starting with this prefix
+//   - limit:         page size (default 50, max 100)
+//   - offset:        number of matching policies to skip
+//   - cursor:        next_cursor from the previous page (overrides offset)
+//
+// Response:
+//
+//	{
+//	  "policies": [{...}],
+//	  "total": 120,
+//	  "next_cursor": "50"
+//	}
+func (h *PolicyHandlers) listPolicies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get authenticated session
+	session := GetSessionFromContext(ctx)
+	if session == nil {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := PolicyFilter{
+		Effect:       Effect(query.Get("effect")),
+		ActionPrefix: query.Get("action_prefix"),
+		Limit:        defaultPolicyPageSize,
+	}
+
+	if filter.Effect != "" && filter.Effect != EffectAllow && filter.Effect != EffectDeny {
+		writeError(w, http.StatusBadRequest, "effect must be 'allow' or 'deny'")
+		return
+	}
+	if enabled := query.Get("enabled"); enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "enabled must be 'true' or 'false'")
+			return
+		}
+		filter.Enabled = &value
+	}
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > maxPolicyPageSize {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxPolicyPageSize))
+			return
+		}
+		filter.Limit = value
+	}
+	if offset := query.Get("offset"); offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			writeError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		filter.Offset = value
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := strconv.Atoi(cursor)
+		if err != nil || value < 0 {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		filter.Offset = value
+	}
+
+	page, err := h.policyStore.ListPolicies(ctx, session.OrganizationID, filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list policies: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
 // createPolicy creates a new policy.
 //
 // Request body:
@@ -99,6 +189,7 @@ func (h *PolicyHandlers) createPolicy(w http.ResponseWriter, r *http.Request) {
 		Name        string      `json:"name"`
 		Description string      `json:"description"`
 		Effect      Effect      `json:"effect"`
+		Priority    int         `json:"priority"`
 		Principals  []Principal `json:"principals"`
 		Actions     []string    `json:"actions"`
 		Resources   []string    `json:"resources"`
@@ -128,6 +219,10 @@ func (h *PolicyHandlers) createPolicy(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "resources are required")
 		return
 	}
+	if err := validateOperators(req.Principals, req.Conditions); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Generate ID (in production, use UUID or similar)
 	policyID := fmt.Sprintf("policy-%s-%d", session.OrganizationID, generateID())
@@ -139,6 +234,7 @@ func (h *PolicyHandlers) createPolicy(w http.ResponseWriter, r *http.Request) {
 		Name:           req.Name,
 		Description:    req.Description,
 		Effect:         req.Effect,
+		Priority:       req.Priority,
 		Principals:     req.Principals,
 		Actions:        req.Actions,
 		Resources:      req.Resources,
@@ -213,6 +309,7 @@ func (h *PolicyHandlers) updatePolicy(w http.ResponseWriter, r *http.Request, po
 		Name        *string     `json:"name,omitempty"`
 		Description *string     `json:"description,omitempty"`
 		Effect      *Effect     `json:"effect,omitempty"`
+		Priority    *int        `json:"priority,omitempty"`
 		Principals  []Principal `json:"principals,omitempty"`
 		Actions     []string    `json:"actions,omitempty"`
 		Resources   []string    `json:"resources,omitempty"`
@@ -225,6 +322,11 @@ func (h *PolicyHandlers) updatePolicy(w http.ResponseWriter, r *http.Request, po
 		return
 	}
 
+	if err := validateOperators(req.Principals, req.Conditions); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Apply updates
 	if req.Name != nil {
 		existing.Name = *req.Name
@@ -239,6 +341,9 @@ func (h *PolicyHandlers) updatePolicy(w http.ResponseWriter, r *http.Request, po
 		}
 		existing.Effect = *req.Effect
 	}
+	if req.Priority != nil {
+		existing.Priority = *req.Priority
+	}
 	if req.Principals != nil {
 		existing.Principals = req.Principals
 	}
@@ -307,6 +412,11 @@ func (h *PolicyHandlers) deletePolicy(w http.ResponseWriter, r *http.Request, po
 
 // handleSimulate simulates policy evaluation without making actual decisions.
 //
+// An optional candidate policy is evaluated together with the organization's
+// policies without being persisted, even if it is not enabled, to preview
+// what it would allow or deny. A candidate with an existing policy's ID
+// previews an update of that policy.
+//
 // Request body:
 //
 //	{
@@ -322,15 +432,27 @@ func (h *PolicyHandlers) deletePolicy(w http.ResponseWriter, r *http.Request, po
 //	  },
 //	  "environment": {
 //	    "client_ip": "192.168.1.1"
+//	  },
+//	  "policy": {
+//	    "name": "Freeze approvals",
+//	    "effect": "deny",
+//	    "priority": 10,
+//	    "actions": ["plan:approve"],
+//	    "resources": ["plan:*"]
 //	  }
 //	}
 //
 // Response:
 //
 //	{
-//	  "allowed": true,
-//	  "reason": "access granted by policy",
-//	  "policy_ids": ["policy-1"],
+//	  "allowed": false,
+//	  "reason": "access explicitly denied by policy candidate (priority 10)",
+//	  "policy_ids": ["candidate"],
+//	  "decisive_policy_id": "candidate",
+//	  "decisive_policy": {...},
+//	  "candidate_decisive": true,
+//	  "baseline": {"allowed": true, "reason": "access granted by policy policy-1", ...},
+//	  "changed": true,
 //	  "timestamp": "2024-01-15T10:00:00Z"
 //	}
 func (h *PolicyHandlers) handleSimulate(w http.ResponseWriter, r *http.Request) {
@@ -354,6 +476,7 @@ func (h *PolicyHandlers) handleSimulate(w http.ResponseWriter, r *http.Request)
 		Action      string                 `json:"action"`
 		Resource    Resource               `json:"resource"`
 		Environment map[string]interface{} `json:"environment"`
+		Policy      *Policy                `json:"policy"`
 	}
 
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
@@ -374,21 +497,61 @@ func (h *PolicyHandlers) handleSimulate(w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusBadRequest, "resource type is required")
 		return
 	}
+	if req.Policy != nil {
+		if err := validateCandidate(req.Policy); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid policy: %v", err))
+			return
+		}
+	}
 
 	// Simulate evaluation
-	decision, err := h.engine.Evaluate(ctx, &AuthorizationRequest{
+	result, err := h.engine.Simulate(ctx, &AuthorizationRequest{
 		Subject:     req.Subject,
 		Action:      req.Action,
 		Resource:    req.Resource,
 		Environment: req.Environment,
-	})
+	}, req.Policy)
 
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("simulation failed: %v", err))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, decision)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateCandidate checks a simulated policy the way createPolicy checks a
+// new one. A name is not required.
+func validateCandidate(policy *Policy) error {
+	if policy.Effect != EffectAllow && policy.Effect != EffectDeny {
+		return fmt.Errorf("effect must be 'allow' or 'deny'")
+	}
+	if len(policy.Actions) == 0 {
+		return fmt.Errorf("actions are required")
+	}
+	if len(policy.Resources) == 0 {
+		return fmt.Errorf("resources are required")
+	}
+	return validateOperators(policy.Principals, policy.Conditions)
+}
+
+// validateOperators rejects principals and conditions whose operator is
+// unknown or whose value does not suit the operator.
+func validateOperators(principals []Principal, conditions []Condition) error {
+	for _, principal := range principals {
+		if principal.Attribute == "" {
+			continue
+		}
+		if err := validateOperator(principal.Operator, principal.Value); err != nil {
+			return fmt.Errorf("invalid principal on %s: %w", principal.Attribute, err)
+		}
+	}
+	for _, condition := range conditions {
+		if err := validateOperator(condition.Operator, condition.Value); err != nil {
+			return fmt.Errorf("invalid condition on %s: %w", condition.Attribute, err)
+		}
+	}
+	return nil
 }
 
 // HTTP helper functions
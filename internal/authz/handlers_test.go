@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -161,6 +162,48 @@ func TestPolicyHandlers_CreatePolicy_ValidationErrors(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantError:  "resources are required",
 		},
+		{
+			name: "unknown condition operator",
+			reqBody: map[string]interface{}{
+				"name":      "Test",
+				"effect":    "allow",
+				"actions":   []string{"plan:read"},
+				"resources": []string{"*"},
+				"conditions": []map[string]interface{}{
+					{"attribute": "$resource.status", "operator": "contains", "value": "pending"},
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unknown condition operator",
+		},
+		{
+			name: "invalid condition pattern",
+			reqBody: map[string]interface{}{
+				"name":      "Test",
+				"effect":    "allow",
+				"actions":   []string{"plan:read"},
+				"resources": []string{"*"},
+				"conditions": []map[string]interface{}{
+					{"attribute": "$resource.name", "operator": "matches", "value": "("},
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid condition on $resource.name",
+		},
+		{
+			name: "unknown principal operator",
+			reqBody: map[string]interface{}{
+				"name":      "Test",
+				"effect":    "allow",
+				"actions":   []string{"plan:read"},
+				"resources": []string{"*"},
+				"principals": []map[string]interface{}{
+					{"attribute": "$subject.department", "operator": "like", "value": "eng"},
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid principal on $subject.department",
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,6 +240,166 @@ func TestPolicyHandlers_CreatePolicy_ValidationErrors(t *testing.T) {
 	}
 }
 
+// TestPolicyHandlers_ListPolicies tests listing, filtering, and paging policies.
+func TestPolicyHandlers_ListPolicies(t *testing.T) {
+	store := NewInMemoryPolicyStore()
+	resourceStore := NewInMemoryResourceStore()
+	resolver := NewDefaultAttributeResolver(resourceStore)
+	engine := NewEngine(store, resolver)
+	handlers := NewPolicyHandlers(store, engine)
+
+	for i, p := range []struct {
+		effect  Effect
+		action  string
+		enabled bool
+	}{
+		{EffectAllow, "plan:read", true},
+		{EffectAllow, "plan:approve", true},
+		{EffectDeny, "plan:delete", true},
+		{EffectAllow, "build:run", false},
+		{EffectDeny, "build:run", true},
+	} {
+		store.CreatePolicy(context.Background(), &Policy{
+			ID:             fmt.Sprintf("policy-%d", i+1),
+			OrganizationID: "org-1",
+			Name:           fmt.Sprintf("Policy %d", i+1),
+			Effect:         p.effect,
+			Actions:        []string{p.action},
+			Resources:      []string{"*"},
+			Enabled:        p.enabled,
+		})
+	}
+	store.CreatePolicy(context.Background(), &Policy{
+		ID:             "other-org-policy",
+		OrganizationID: "org-2",
+		Effect:         EffectAllow,
+		Actions:        []string{"plan:read"},
+		Resources:      []string{"*"},
+		Enabled:        true,
+	})
+
+	session := &auth.Session{
+		UserID:           "user-1",
+		OrganizationID:   "org-1",
+		OrganizationRole: "admin",
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantIDs    []string
+		wantTotal  int
+		wantCursor string
+	}{
+		{"all", "", []string{"policy-1", "policy-2", "policy-3", "policy-4", "policy-5"}, 5, ""},
+		{"first page", "limit=2", []string{"policy-1", "policy-2"}, 5, "2"},
+		{"next page by cursor", "limit=2&cursor=2", []string{"policy-3", "policy-4"}, 5, "4"},
+		{"last page by offset", "limit=2&offset=4", []string{"policy-5"}, 5, ""},
+		{"offset out of range", "limit=2&offset=10", []string{}, 5, ""},
+		{"enabled only", "enabled=true", []string{"policy-1", "policy-2", "policy-3", "policy-5"}, 4, ""},
+		{"disabled only", "enabled=false", []string{"policy-4"}, 1, ""},
+		{"deny only", "effect=deny", []string{"policy-3", "policy-5"}, 2, ""},
+		{"action prefix", "action_prefix=plan:", []string{"policy-1", "policy-2", "policy-3"}, 3, ""},
+		{"combined filters paged", "effect=allow&action_prefix=plan:&limit=1", []string{"policy-1"}, 2, "1"},
+		{"empty result", "action_prefix=drift:", []string{}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/policies?"+tt.query, nil)
+			req = req.WithContext(SetSessionInContext(context.Background(), session))
+
+			w := httptest.NewRecorder()
+			handlers.handlePolicies(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Policies   []*Policy `json:"policies"`
+				Total      int       `json:"total"`
+				NextCursor string    `json:"next_cursor"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+
+			if response.Policies == nil {
+				t.Fatal("expected policies to be an array, got null")
+			}
+			ids := make([]string, len(response.Policies))
+			for i, policy := range response.Policies {
+				ids[i] = policy.ID
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("expected policies %v, got %v", tt.wantIDs, ids)
+			}
+			if response.Total != tt.wantTotal {
+				t.Errorf("expected total %d, got %d", tt.wantTotal, response.Total)
+			}
+			if response.NextCursor != tt.wantCursor {
+				t.Errorf("expected next_cursor %q, got %q", tt.wantCursor, response.NextCursor)
+			}
+		})
+	}
+}
+
+// TestPolicyHandlers_ListPolicies_ValidationErrors tests invalid list queries.
+func TestPolicyHandlers_ListPolicies_ValidationErrors(t *testing.T) {
+	store := NewInMemoryPolicyStore()
+	resourceStore := NewInMemoryResourceStore()
+	resolver := NewDefaultAttributeResolver(resourceStore)
+	engine := NewEngine(store, resolver)
+	handlers := NewPolicyHandlers(store, engine)
+
+	session := &auth.Session{
+		UserID:           "user-1",
+		OrganizationID:   "org-1",
+		OrganizationRole: "admin",
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantError string
+	}{
+		{"zero limit", "limit=0", "limit must be between 1 and 100"},
+		{"limit too large", "limit=500", "limit must be between 1 and 100"},
+		{"negative offset", "offset=-1", "offset must be a non-negative integer"},
+		{"invalid cursor", "cursor=abc", "invalid cursor"},
+		{"invalid effect", "effect=maybe", "effect must be 'allow' or 'deny'"},
+		{"invalid enabled", "enabled=sometimes", "enabled must be 'true' or 'false'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/policies?"+tt.query, nil)
+			req = req.WithContext(SetSessionInContext(context.Background(), session))
+
+			w := httptest.NewRecorder()
+			handlers.handlePolicies(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+
+			var response map[string]string
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if !strings.Contains(response["error"], tt.wantError) {
+				t.Errorf("expected error to contain '%s', got '%s'", tt.wantError, response["error"])
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/policies", nil)
+	w := httptest.NewRecorder()
+	handlers.handlePolicies(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without a session, got %d", w.Code)
+	}
+}
+
 // TestPolicyHandlers_GetPolicy tests retrieving a policy.
 func TestPolicyHandlers_GetPolicy(t *testing.T) {
 	store := NewInMemoryPolicyStore()
@@ -340,6 +543,7 @@ func TestPolicyHandlers_UpdatePolicy(t *testing.T) {
 	reqBody := map[string]interface{}{
 		"name":        "Updated Name",
 		"description": "Updated Description",
+		"priority":    10,
 	}
 
 	body, _ := json.Marshal(reqBody)
@@ -364,6 +568,9 @@ func TestPolicyHandlers_UpdatePolicy(t *testing.T) {
 	if response.Description != "Updated Description" {
 		t.Errorf("expected description 'Updated Description', got %s", response.Description)
 	}
+	if response.Priority != 10 {
+		t.Errorf("expected priority 10, got %d", response.Priority)
+	}
 
 	// Verify other fields unchanged
 	if response.Effect != EffectAllow {
@@ -751,6 +958,82 @@ func TestPolicyHandlers_Simulate_Denied(t *testing.T) {
 	}
 }
 
+// TestPolicyHandlers_Simulate_CandidatePolicy tests simulating a policy before it is stored.
+func TestPolicyHandlers_Simulate_CandidatePolicy(t *testing.T) {
+	store := NewInMemoryPolicyStore()
+	resourceStore := NewInMemoryResourceStore()
+	resolver := NewDefaultAttributeResolver(resourceStore)
+	engine := NewEngine(store, resolver)
+	handlers := NewPolicyHandlers(store, engine)
+
+	store.CreatePolicy(context.Background(), &Policy{
+		ID:             "policy-1",
+		OrganizationID: "org-1",
+		Name:           "Admin Policy",
+		Effect:         EffectAllow,
+		Principals:     []Principal{{Role: "admin"}},
+		Actions:        []string{"plan:approve"},
+		Resources:      []string{"*"},
+		Enabled:        true,
+	})
+
+	session := &auth.Session{
+		UserID:           "user-1",
+		OrganizationID:   "org-1",
+		OrganizationRole: "admin",
+	}
+
+	reqBody := map[string]interface{}{
+		"subject": map[string]interface{}{
+			"UserID":           "test-user",
+			"OrganizationID":   "org-1",
+			"OrganizationRole": "admin",
+		},
+		"action": "plan:approve",
+		"resource": map[string]interface{}{
+			"type": "plan",
+			"id":   "plan-123",
+		},
+		"policy": map[string]interface{}{
+			"id":        "freeze",
+			"name":      "Freeze approvals",
+			"effect":    "deny",
+			"actions":   []string{"plan:approve"},
+			"resources": []string{"plan:*"},
+			"enabled":   false,
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/policies/simulate", bytes.NewReader(body))
+	req = req.WithContext(SetSessionInContext(context.Background(), session))
+
+	w := httptest.NewRecorder()
+	handlers.handleSimulate(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response SimulationResult
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Decision == nil || response.Allowed {
+		t.Fatal("expected the candidate to deny access")
+	}
+	if response.DecisivePolicyID != "freeze" || !response.CandidateDecisive {
+		t.Errorf("expected the candidate to be decisive, got %q", response.DecisivePolicyID)
+	}
+	if response.Baseline == nil || !response.Baseline.Allowed || !response.Changed {
+		t.Errorf("expected the baseline to allow access, got %+v", response.Baseline)
+	}
+	if _, err := store.GetPolicy(context.Background(), "freeze"); err == nil {
+		t.Error("expected the candidate not to be stored")
+	}
+}
+
 // TestPolicyHandlers_Simulate_Validation tests simulation validation.
 func TestPolicyHandlers_Simulate_Validation(t *testing.T) {
 	store := NewInMemoryPolicyStore()
@@ -811,6 +1094,48 @@ func TestPolicyHandlers_Simulate_Validation(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantError:  "resource type is required",
 		},
+		{
+			name: "candidate without actions",
+			reqBody: map[string]interface{}{
+				"subject": map[string]interface{}{
+					"UserID":         "test-user",
+					"OrganizationID": "org-1",
+				},
+				"action": "plan:read",
+				"resource": map[string]interface{}{
+					"type": "plan",
+				},
+				"policy": map[string]interface{}{
+					"effect":    "deny",
+					"resources": []string{"*"},
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid policy: actions are required",
+		},
+		{
+			name: "candidate with unknown operator",
+			reqBody: map[string]interface{}{
+				"subject": map[string]interface{}{
+					"UserID":         "test-user",
+					"OrganizationID": "org-1",
+				},
+				"action": "plan:read",
+				"resource": map[string]interface{}{
+					"type": "plan",
+				},
+				"policy": map[string]interface{}{
+					"effect":    "deny",
+					"actions":   []string{"*"},
+					"resources": []string{"*"},
+					"conditions": []map[string]interface{}{
+						{"attribute": "$resource.status", "operator": "contains", "value": "x"},
+					},
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unknown condition operator",
+		},
 	}
 
 	for _, tt := range tests {
@@ -46,6 +46,13 @@ func (b *PolicyBuilder) WithEffect(effect Effect) *PolicyBuilder {
 	return b
 }
 
+// WithPriority sets the policy priority. Higher priorities are evaluated
+// first; at equal priority a deny overrides an allow.
+func (b *PolicyBuilder) WithPriority(priority int) *PolicyBuilder {
+	b.policy.Priority = priority
+	return b
+}
+
 // AllowRole adds a role-based principal to the policy.
 func (b *PolicyBuilder) AllowRole(role string) *PolicyBuilder {
 	b.policy.Principals = append(b.policy.Principals, Principal{
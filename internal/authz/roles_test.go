@@ -127,6 +127,19 @@ func TestPolicyBuilder_DenyEffect(t *testing.T) {
 	}
 }
 
+// TestPolicyBuilder_Priority tests setting a policy priority.
+func TestPolicyBuilder_Priority(t *testing.T) {
+	policy := NewPolicyBuilder("org-1", "Break Glass").
+		WithPriority(100).
+		AllowRole("owner").
+		OnActions("plan:approve").
+		Build()
+
+	if policy.Priority != 100 {
+		t.Errorf("expected Priority 100, got %d", policy.Priority)
+	}
+}
+
 // TestNewOwnerPolicy tests the owner policy helper.
 func TestNewOwnerPolicy(t *testing.T) {
 	policy := NewOwnerPolicy("org-1", "owner-policy-1")
@@ -0,0 +1,82 @@
+package authz
+
+import (
+	"context"
+)
+
+// CandidatePolicyID identifies a simulated candidate policy that has no ID.
+const CandidatePolicyID = "candidate"
+
+// SimulationResult is the outcome of evaluating a request with a candidate
+// policy added to the organization's policies.
+type SimulationResult struct {
+	*Decision
+
+	// DecisivePolicy is the policy that decided the request (nil for default deny)
+	DecisivePolicy *Policy `json:"decisive_policy,omitempty"`
+
+	// CandidateDecisive reports whether the candidate policy decided the request
+	CandidateDecisive bool `json:"candidate_decisive"`
+
+	// Baseline is the decision under the current policies alone
+	Baseline *Decision `json:"baseline,omitempty"`
+
+	// Changed reports whether the candidate changes whether access is allowed
+	Changed bool `json:"changed"`
+}
+
+// Simulate evaluates a request as if candidate were one of the subject
+// organization's policies, without persisting it or logging the decision.
+//
+// The candidate is evaluated as enabled, so a policy can be tried before it
+// is switched on. A candidate with the ID of an existing policy replaces that
+// policy, previewing an update. A nil candidate simulates the current
+// policies.
+func (e *Engine) Simulate(ctx context.Context, req *AuthorizationRequest, candidate *Policy) (*SimulationResult, error) {
+	if candidate == nil {
+		decision, decisive, err := e.evaluate(ctx, req, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &SimulationResult{Decision: decision, DecisivePolicy: decisive}, nil
+	}
+
+	baseline, _, err := e.evaluate(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	simulated := *candidate
+	simulated.Enabled = true
+	if simulated.ID == "" {
+		simulated.ID = CandidatePolicyID
+	}
+	if req.Subject != nil {
+		simulated.OrganizationID = req.Subject.OrganizationID
+	}
+
+	decision, decisive, err := e.evaluate(ctx, req, &simulated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimulationResult{
+		Decision:          decision,
+		DecisivePolicy:    decisive,
+		CandidateDecisive: decisive == &simulated,
+		Baseline:          baseline,
+		Changed:           decision.Allowed != baseline.Allowed,
+	}, nil
+}
+
+// withCandidate returns policies with candidate added, replacing any policy
+// with the same ID. The stored policies are not modified.
+func withCandidate(policies []*Policy, candidate *Policy) []*Policy {
+	result := make([]*Policy, 0, len(policies)+1)
+	for _, policy := range policies {
+		if policy.ID != candidate.ID {
+			result = append(result, policy)
+		}
+	}
+	return append(result, candidate)
+}
@@ -0,0 +1,143 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/felixgeelhaar/specular/internal/auth"
+)
+
+func TestEngine_Simulate(t *testing.T) {
+	ctx := context.Background()
+	adminAllow := &Policy{
+		ID:             "policy-1",
+		OrganizationID: "org-1",
+		Effect:         EffectAllow,
+		Principals:     []Principal{{Role: "admin"}},
+		Actions:        []string{"plan:approve"},
+		Resources:      []string{"plan:*"},
+		Enabled:        true,
+	}
+	req := &AuthorizationRequest{
+		Subject:  &auth.Session{UserID: "user-1", OrganizationID: "org-1", OrganizationRole: "admin"},
+		Action:   "plan:approve",
+		Resource: Resource{Type: "plan", ID: "plan-1"},
+	}
+
+	tests := []struct {
+		name          string
+		candidate     *Policy
+		wantAllowed   bool
+		wantDecisive  string
+		wantCandidate bool
+		wantChanged   bool
+	}{
+		{
+			name:         "no candidate",
+			wantAllowed:  true,
+			wantDecisive: "policy-1",
+		},
+		{
+			name: "disabled deny candidate locks the admin out",
+			candidate: &Policy{
+				Effect:    EffectDeny,
+				Actions:   []string{"plan:*"},
+				Resources: []string{"*"},
+			},
+			wantAllowed:   false,
+			wantDecisive:  CandidatePolicyID,
+			wantCandidate: true,
+			wantChanged:   true,
+		},
+		{
+			name: "candidate that does not match",
+			candidate: &Policy{
+				ID:        "freeze-builds",
+				Effect:    EffectDeny,
+				Actions:   []string{"build:run"},
+				Resources: []string{"*"},
+			},
+			wantAllowed:  true,
+			wantDecisive: "policy-1",
+		},
+		{
+			name: "lower priority deny is outranked",
+			candidate: &Policy{
+				ID:        "freeze",
+				Effect:    EffectDeny,
+				Priority:  -1,
+				Actions:   []string{"plan:approve"},
+				Resources: []string{"*"},
+			},
+			wantAllowed:  true,
+			wantDecisive: "policy-1",
+		},
+		{
+			name: "candidate replaces the stored policy",
+			candidate: &Policy{
+				ID:         "policy-1",
+				Effect:     EffectAllow,
+				Principals: []Principal{{Role: "owner"}},
+				Actions:    []string{"plan:approve"},
+				Resources:  []string{"plan:*"},
+			},
+			wantAllowed: false,
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewInMemoryPolicyStore()
+			stored := *adminAllow
+			require.NoError(t, store.CreatePolicy(ctx, &stored))
+			auditLogger := NewInMemoryAuditLogger()
+			engine := WithAuditLogger(NewEngine(store, NewDefaultAttributeResolver(NewInMemoryResourceStore())), auditLogger)
+
+			result, err := engine.Simulate(ctx, req, tt.candidate)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantAllowed, result.Allowed)
+			assert.Equal(t, tt.wantDecisive, result.DecisivePolicyID)
+			assert.Equal(t, tt.wantCandidate, result.CandidateDecisive)
+			assert.Equal(t, tt.wantChanged, result.Changed)
+			if tt.wantDecisive == "" {
+				assert.Nil(t, result.DecisivePolicy)
+			} else {
+				require.NotNil(t, result.DecisivePolicy)
+				assert.Equal(t, tt.wantDecisive, result.DecisivePolicy.ID)
+			}
+			if tt.candidate != nil {
+				require.NotNil(t, result.Baseline)
+				assert.True(t, result.Baseline.Allowed)
+			}
+
+			// Nothing is persisted or audited
+			policies, err := store.LoadPolicies(ctx, "org-1")
+			require.NoError(t, err)
+			require.Len(t, policies, 1)
+			assert.Equal(t, []Principal{{Role: "admin"}}, policies[0].Principals)
+			assert.Empty(t, auditLogger.GetEntries())
+		})
+	}
+}
+
+func TestEngine_Simulate_CandidateConditionError(t *testing.T) {
+	engine := NewEngine(NewInMemoryPolicyStore(), NewDefaultAttributeResolver(NewInMemoryResourceStore()))
+	req := &AuthorizationRequest{
+		Subject:  &auth.Session{UserID: "user-1", OrganizationID: "org-1"},
+		Action:   "plan:read",
+		Resource: Resource{Type: "plan"},
+	}
+
+	_, err := engine.Simulate(context.Background(), req, &Policy{
+		Effect:     EffectAllow,
+		Actions:    []string{"*"},
+		Resources:  []string{"*"},
+		Conditions: []Condition{{Attribute: "$environment.x", Operator: "contains", Value: "y"}},
+	})
+	assert.ErrorIs(t, err, ErrUnknownOperator)
+}
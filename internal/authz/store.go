@@ -3,6 +3,7 @@ package authz
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -159,6 +160,41 @@ func (s *InMemoryPolicyStore) GetPolicy(ctx context.Context, policyID string) (*
 	return &policyCopy, nil
 }
 
+// ListPolicies returns a page of an organization's policies matching the
+// filter, in creation order. The next cursor is the offset of the next page.
+func (s *InMemoryPolicyStore) ListPolicies(ctx context.Context, organizationID string, filter PolicyFilter) (*PolicyPage, error) {
+	if filter.Offset < 0 || filter.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit cannot be negative")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	page := &PolicyPage{Policies: []*Policy{}}
+	for _, policyID := range s.byOrg[organizationID] {
+		policy, found := s.policies[policyID]
+		if !found || !filter.matches(policy) {
+			continue
+		}
+
+		index := page.Total
+		page.Total++
+		if index < filter.Offset || (filter.Limit > 0 && index >= filter.Offset+filter.Limit) {
+			continue
+		}
+
+		// Return a copy to avoid external mutations
+		policyCopy := *policy
+		page.Policies = append(page.Policies, &policyCopy)
+	}
+
+	if filter.Limit > 0 && filter.Offset+filter.Limit < page.Total {
+		page.NextCursor = strconv.Itoa(filter.Offset + filter.Limit)
+	}
+
+	return page, nil
+}
+
 // LoadBuiltInPolicies loads standard role-based policies into the store.
 // These provide RBAC-style roles as ABAC abstractions.
 func (s *InMemoryPolicyStore) LoadBuiltInPolicies(organizationID string) error {
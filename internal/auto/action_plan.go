@@ -2,6 +2,7 @@ package auto
 
 import (
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -19,6 +20,9 @@ type ActionPlan struct {
 
 	// Metadata contains plan generation metadata
 	Metadata PlanMetadata `json:"metadata" yaml:"metadata"`
+
+	// onStatusChange, when set, is called after a step's status changes
+	onStatusChange func(step *ActionStep)
 }
 
 // ActionStep represents a single step in the workflow.
@@ -55,6 +59,12 @@ type ActionStep struct {
 
 	// Error contains error message if step failed
 	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Custom marks a step run by a registered StepHandler
+	Custom bool `json:"custom,omitempty" yaml:"custom,omitempty"`
+
+	// EstimatedCostUSD is the projected cost of a custom step
+	EstimatedCostUSD float64 `json:"estimatedCostUsd,omitempty" yaml:"estimatedCostUsd,omitempty"`
 }
 
 // StepType defines the type of workflow step.
@@ -72,6 +82,9 @@ const (
 
 	// StepTypeBuildRun executes build and implementation tasks
 	StepTypeBuildRun StepType = "build:run"
+
+	// StepTypeVerify runs the project's build and test commands after execution
+	StepTypeVerify StepType = "verify:run"
 )
 
 // StepStatus tracks the execution status of a step.
@@ -141,6 +154,36 @@ func (p *ActionPlan) AddStep(step ActionStep) {
 	p.Steps = append(p.Steps, step)
 }
 
+// InsertStepAfter inserts a step directly after the step with the given ID.
+// Steps that depended on that step depend on the inserted step instead.
+func (p *ActionPlan) InsertStepAfter(afterID string, step ActionStep) error {
+	index := -1
+	for i := range p.Steps {
+		if p.Steps[i].ID == afterID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("step %q not found", afterID)
+	}
+
+	for i := range p.Steps {
+		for j, depID := range p.Steps[i].Dependencies {
+			if depID == afterID {
+				p.Steps[i].Dependencies[j] = step.ID
+			}
+		}
+	}
+
+	if step.Status == "" {
+		step.Status = StepStatusPending
+	}
+	step.Dependencies = append(step.Dependencies, afterID)
+	p.Steps = slices.Insert(p.Steps, index+1, step)
+	return nil
+}
+
 // GetStep returns the step with the given ID.
 func (p *ActionPlan) GetStep(id string) (*ActionStep, error) {
 	for i := range p.Steps {
@@ -173,6 +216,10 @@ func (p *ActionPlan) UpdateStepStatus(id string, status StepStatus) error {
 		}
 	}
 
+	if p.onStatusChange != nil {
+		p.onStatusChange(step)
+	}
+
 	return nil
 }
 
@@ -283,6 +330,7 @@ func (p *ActionPlan) Validate() error {
 		StepTypeSpecLock:   true,
 		StepTypePlanGen:    true,
 		StepTypeBuildRun:   true,
+		StepTypeVerify:     true,
 	}
 
 	// Validate each step
@@ -296,7 +344,7 @@ func (p *ActionPlan) Validate() error {
 		}
 		stepIDs[step.ID] = true
 
-		if !validTypes[step.Type] {
+		if !validTypes[step.Type] && !(step.Custom && step.Type != "") {
 			return fmt.Errorf("step %d (%s): invalid type %q", i, step.ID, step.Type)
 		}
 		if step.Description == "" {
@@ -416,3 +464,18 @@ func CreateDefaultActionPlan(goal string, profile string) *ActionPlan {
 
 	return plan
 }
+
+// AddVerifyStep appends the optional step-5, which verifies that the project
+// still builds and passes its tests after the plan has executed.
+func AddVerifyStep(plan *ActionPlan) {
+	plan.AddStep(ActionStep{
+		ID:               "step-5",
+		Type:             StepTypeVerify,
+		Description:      "Verify build and tests after execution",
+		RequiresApproval: false,
+		Dependencies:     []string{"step-4"},
+		Signals: map[string]string{
+			"critical": "true",
+		},
+	})
+}
@@ -552,6 +552,27 @@ func TestCreateDefaultActionPlan(t *testing.T) {
 	}
 }
 
+func TestAddVerifyStep(t *testing.T) {
+	plan := CreateDefaultActionPlan("add health endpoint", "default")
+	AddVerifyStep(plan)
+
+	if len(plan.Steps) != 5 {
+		t.Fatalf("expected 5 steps, got %d", len(plan.Steps))
+	}
+
+	step := plan.Steps[4]
+	if step.ID != "step-5" || step.Type != StepTypeVerify {
+		t.Errorf("expected step-5 of type %s, got %s of type %s", StepTypeVerify, step.ID, step.Type)
+	}
+	if len(step.Dependencies) != 1 || step.Dependencies[0] != "step-4" {
+		t.Errorf("expected step-5 to depend on step-4, got %v", step.Dependencies)
+	}
+
+	if err := plan.Validate(); err != nil {
+		t.Errorf("plan with verify step failed validation: %v", err)
+	}
+}
+
 func TestStepTypeConstants(t *testing.T) {
 	// Verify step type constants match expected values
 	if StepTypeSpecUpdate != "spec:update" {
@@ -566,6 +587,9 @@ func TestStepTypeConstants(t *testing.T) {
 	if StepTypeBuildRun != "build:run" {
 		t.Errorf("expected StepTypeBuildRun to be 'build:run', got %s", StepTypeBuildRun)
 	}
+	if StepTypeVerify != "verify:run" {
+		t.Errorf("expected StepTypeVerify to be 'verify:run', got %s", StepTypeVerify)
+	}
 }
 
 func TestStepStatusConstants(t *testing.T) {
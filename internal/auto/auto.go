@@ -3,9 +3,12 @@ package auto
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/checkpoint"
@@ -25,10 +28,21 @@ type Orchestrator struct {
 	parser         *GoalParser
 	actionPlan     *ActionPlan
 	policyChecker  PolicyChecker        // Optional policy checker for step validation
+	changeChecker  PolicyChecker        // Optional checker for the file changes of completed steps
 	tracer         *trace.Logger        // Optional trace logger for detailed execution tracking
 	patchGenerator *patch.DiffGenerator // Optional patch generator for rollback support
 	patchWriter    *patch.Writer        // Optional patch writer for saving patches
+	patchRollback  *patch.Rollback      // Optional rollback handler for reverting patches
 	hookRegistry   *hooks.Registry      // Optional hook registry for lifecycle notifications
+	eventOut       io.Writer            // Where an event stream path of "-" writes (default: os.Stdout)
+	workflowID     string               // Workflow ID sent with hook events
+	sessionID      string               // Session the checkpoint and patches are saved under
+	customSteps    []StepHandler        // Custom steps run between the built-in steps
+	specValidators []SpecValidator      // Validators run against the generated spec
+
+	// Budget events already sent, so each is sent once per run
+	budgetWarnedPercent float64
+	budgetExhausted     bool
 }
 
 // NewOrchestrator creates a new orchestrator with the given router and config
@@ -46,8 +60,22 @@ func (o *Orchestrator) SetPolicyChecker(checker PolicyChecker) {
 	o.policyChecker = checker
 }
 
+// SetChangeChecker sets the checker that inspects the file changes of each
+// completed step, such as a changed paths checker. Changes it denies are
+// reverted and fail the step. Only this checker sees the changes; the policy
+// checker is not run again. It requires SetPatchGenerator, which captures
+// the changes.
+func (o *Orchestrator) SetChangeChecker(checker PolicyChecker) {
+	o.changeChecker = checker
+}
+
+// ErrChangesNotCaptured is returned by Execute when a change checker is set
+// without a patch generator, so its rules could never apply
+var ErrChangesNotCaptured = errors.New("change checker set but step changes are not captured; set a patch generator")
+
 // SetTracer sets the trace logger for detailed execution tracking.
-// This must be called before Execute if tracing is desired.
+// This must be called before Execute if tracing is desired. The caller
+// closes the tracer after Execute returns.
 func (o *Orchestrator) SetTracer(tracer *trace.Logger) {
 	o.tracer = tracer
 }
@@ -57,6 +85,13 @@ func (o *Orchestrator) SetTracer(tracer *trace.Logger) {
 func (o *Orchestrator) SetPatchGenerator(workingDir, patchDir string) {
 	o.patchGenerator = patch.NewDiffGenerator(workingDir)
 	o.patchWriter = patch.NewWriter(patchDir)
+	o.patchRollback = patch.NewRollback(workingDir, patchDir)
+}
+
+// SetEventStreamWriter sets where an EventStreamPath of "-" writes, such as
+// the original stdout after progress output was moved to stderr
+func (o *Orchestrator) SetEventStreamWriter(w io.Writer) {
+	o.eventOut = w
 }
 
 // SetHookRegistry sets the hook registry for lifecycle notifications.
@@ -78,6 +113,40 @@ func (o *Orchestrator) triggerHook(ctx context.Context, eventType hooks.EventTyp
 	// We don't block workflow execution on hook failures
 }
 
+// registerConfiguredHooks adds the hooks from the configuration to the hook
+// registry, creating the registry if needed
+func (o *Orchestrator) registerConfiguredHooks() error {
+	if len(o.config.Hooks) == 0 {
+		return nil
+	}
+
+	if o.hookRegistry == nil {
+		o.hookRegistry = hooks.NewRegistry()
+	}
+	hooks.RegisterBuiltinHooks(o.hookRegistry)
+	for i := range o.config.Hooks {
+		if err := o.hookRegistry.RegisterFromConfig(&o.config.Hooks[i]); err != nil {
+			return fmt.Errorf("register hook %s: %w", o.config.Hooks[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// hookFlushTimeout bounds how long a finished run waits for hook retries
+const hookFlushTimeout = 10 * time.Second
+
+// flushHooks waits for background hook retries. Retries still pending after
+// hookFlushTimeout are written to the dead-letter log for replay.
+func (o *Orchestrator) flushHooks() {
+	if o.hookRegistry == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookFlushTimeout)
+	defer cancel()
+	_ = o.hookRegistry.Flush(ctx)
+}
+
 // Execute runs the complete autonomous workflow
 func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 	start := time.Now()
@@ -86,12 +155,33 @@ func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 		Errors:  []error{},
 	}
 
+	if o.changeChecker != nil && o.patchGenerator == nil {
+		return nil, ErrChangesNotCaptured
+	}
+
+	// Wait for hook retries after every other hook has fired
+	defer o.flushHooks()
+
+	if err := o.registerConfiguredHooks(); err != nil {
+		return nil, err
+	}
+
+	// Stream lifecycle events if requested; closed after the workflow
+	// failed hook below has run
+	if o.config.EventStreamPath != "" {
+		closeStream, err := o.openEventStream()
+		if err != nil {
+			return nil, err
+		}
+		defer closeStream()
+	}
+
 	// Track workflow ID for hooks
 	var workflowID string
 
 	// Defer workflow failed hook if execution doesn't complete successfully
 	defer func() {
-		if !result.Success && workflowID != "" {
+		if !result.Success && !result.Paused && workflowID != "" {
 			o.triggerHook(ctx, hooks.EventWorkflowFailed, workflowID, map[string]interface{}{
 				"duration": time.Since(start).String(),
 				"error":    fmt.Sprintf("%v", result.Errors),
@@ -99,19 +189,46 @@ func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 		}
 	}()
 
-	// Check if resuming from checkpoint
+	// Check if resuming from checkpoint; a resumed run keeps the ID of the
+	// run it continues
 	if o.config.ResumeFrom != "" {
-		return o.executeResume(ctx, start)
+		o.sessionID = o.config.ResumeFrom
+		workflowID = o.sessionID
+		o.workflowID = workflowID
+		goal, ok := o.pausedGoal()
+		if !ok {
+			return o.executeResume(ctx, start)
+		}
+		// Nothing was generated before the pause, so the run starts over
+		// under the same session
+		fmt.Printf("🔄 Restarting paused session: %s\n", o.sessionID)
+		o.config.Goal = goal
+	} else {
+		// The checkpoint, patches, hooks, trace, and attestation share the
+		// session ID, so 'auto rollback' and 'auto export-patch' find the
+		// patches of a session and one run can be followed across them
+		o.sessionID = trace.NewWorkflowID()
+		if o.tracer != nil {
+			o.sessionID = o.tracer.GetWorkflowID()
+		}
 	}
+	result.WorkflowID = o.sessionID
 
 	// Create action plan for workflow tracking
-	o.actionPlan = CreateDefaultActionPlan(o.config.Goal, o.config.Profile)
+	actionPlan, err := ComposeActionPlan(o.config.Goal, o.config.Profile, o.config.Verify, o.customSteps)
+	if err != nil {
+		return nil, fmt.Errorf("compose action plan: %w", err)
+	}
+	o.actionPlan = actionPlan
 	result.ActionPlan = o.actionPlan
 
 	// Create JSON output if enabled
 	var autoOutput *AutoOutput
 	if o.config.JSONOutput {
 		autoOutput = NewAutoOutput(o.config.Goal, o.config.Profile)
+		autoOutput.SetSeed(o.config.Seed)
+		autoOutput.SetCheckpointID(o.sessionID)
+		autoOutput.SetWorkflowID(o.sessionID)
 		result.AutoOutput = autoOutput
 	}
 
@@ -126,9 +243,10 @@ func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 	}
 
 	// Trigger workflow start hook
-	workflowID = "unknown"
-	if autoOutput != nil {
-		workflowID = autoOutput.Audit.CheckpointID
+	workflowID = o.sessionID
+	o.workflowID = workflowID
+	o.actionPlan.onStatusChange = func(step *ActionStep) {
+		o.emitStepEvent(ctx, step)
 	}
 	o.triggerHook(ctx, hooks.EventWorkflowStart, workflowID, map[string]interface{}{
 		"goal":    o.config.Goal,
@@ -137,469 +255,98 @@ func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 
 	fmt.Printf("📋 Created action plan with %d steps\n\n", len(o.actionPlan.Steps))
 
-	// Pre-flight: Check budget for spec generation
-	if o.router != nil {
-		budget := o.router.GetBudget()
-		estimatedCost := EstimateSpecGenerationCost(len(o.config.Goal), 0.01) // $0.01 per MTok typical
-		warning, err := CheckBudgetWithWarning(budget, estimatedCost, "spec generation")
-		if err != nil {
-			return nil, fmt.Errorf("budget check failed: %w", err)
-		}
-		if warning != "" {
-			fmt.Printf("%s\n\n", warning)
-		}
-	}
-
-	// Step 1: Parse goal into spec
-	step1, _ := o.actionPlan.GetStep("step-1")
-
-	// Check policy before executing step
-	allowed, policyEvent, err := o.checkPolicy(ctx, o.policyChecker, step1, 0, completedSteps, totalCost, executionStart)
+	// Artifacts passed from step to step
+	state := &WorkflowState{Goal: o.config.Goal, DryRun: o.config.DryRun, router: o.router, result: result, autoOutput: autoOutput}
+	handlers, err := o.stepHandlers(state)
 	if err != nil {
-		return nil, fmt.Errorf("step-1 policy check: %w", err)
-	}
-	if autoOutput != nil && policyEvent != nil {
-		autoOutput.AddPolicy(*policyEvent)
-	}
-	if !allowed {
-		fmt.Printf("🚫 Step 1 blocked by policy: %s\n", policyEvent.Reason)
-		if autoOutput != nil {
-			autoOutput.SetPartial()
-		}
-		result.Success = false
-		result.Duration = time.Since(start)
-		return result, fmt.Errorf("step-1 blocked by policy: %s", policyEvent.Reason)
+		return nil, err
 	}
 
-	step1Start := time.Now()
-	if err := o.actionPlan.UpdateStepStatus("step-1", StepStatusInProgress); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-	if o.tracer != nil {
-		o.tracer.LogStepStart("step-1", "Generate specification") //#nosec G104 -- Logging errors not critical
-	}
+	for i := range o.actionPlan.Steps {
+		step := &o.actionPlan.Steps[i]
+		stepID := step.ID
 
-	// Capture snapshot before step
-	step1Snapshot, err := o.captureSnapshot()
-	if err != nil {
-		fmt.Printf("⚠️  Failed to capture snapshot: %v\n", err)
-	}
-
-	fmt.Println("🤖 Generating specification from goal...")
-	productSpec, err := o.parser.ParseGoal(ctx, o.config.Goal)
-	if err != nil {
-		step, _ := o.actionPlan.GetStep("step-1")
-		step.Error = err.Error()
-		_ = o.actionPlan.UpdateStepStatus("step-1", StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
-		if o.tracer != nil {
-			o.tracer.LogStepFail("step-1", "Generate specification", err) //#nosec G104 -- Logging errors not critical
-		}
-		if autoOutput != nil {
-			autoOutput.AddStepResult(StepResult{
-				ID:          "step-1",
-				Type:        "spec:update",
-				Status:      "failed",
-				StartedAt:   step1Start,
-				CompletedAt: time.Now(),
-				Duration:    time.Since(step1Start),
-				Error:       err.Error(),
-			})
-		}
-		return nil, fmt.Errorf("parse goal: %w", err)
-	}
-	result.Spec = productSpec
-	if err := o.actionPlan.UpdateStepStatus("step-1", StepStatusCompleted); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-	step1Cost := EstimateSpecGenerationCost(len(o.config.Goal), 0.01)
-	if o.tracer != nil {
-		o.tracer.LogStepComplete("step-1", "Generate specification", time.Since(step1Start), step1Cost) //#nosec G104 -- Logging errors not critical
-	}
-	if autoOutput != nil {
-		autoOutput.AddStepResult(StepResult{
-			ID:          "step-1",
-			Type:        "spec:update",
-			Status:      "completed",
-			StartedAt:   step1Start,
-			CompletedAt: time.Now(),
-			Duration:    time.Since(step1Start),
-			CostUSD:     step1Cost,
-		})
-	}
-	completedSteps++
-	totalCost += step1Cost
-	fmt.Printf("✅ Generated spec: %s\n", productSpec.Product)
-	fmt.Printf("   Features: %d\n\n", len(productSpec.Features))
-
-	// Generate and save patch for step 1
-	if err := o.generateAndSavePatch("step-1", "spec:update", "Generate specification", step1Snapshot); err != nil {
-		fmt.Printf("⚠️  Patch generation warning: %v\n", err)
-	}
-
-	// Step 2: Generate spec lock
-	step2, _ := o.actionPlan.GetStep("step-2")
-
-	// Check policy before executing step
-	allowed, policyEvent, err = o.checkPolicy(ctx, o.policyChecker, step2, 1, completedSteps, totalCost, executionStart)
-	if err != nil {
-		return nil, fmt.Errorf("step-2 policy check: %w", err)
-	}
-	if autoOutput != nil && policyEvent != nil {
-		autoOutput.AddPolicy(*policyEvent)
-	}
-	if !allowed {
-		fmt.Printf("🚫 Step 2 blocked by policy: %s\n", policyEvent.Reason)
-		if autoOutput != nil {
-			autoOutput.SetPartial()
+		// The plan is saved and approved, or the dry run ends, before
+		// any task runs
+		if step.Type == StepTypeBuildRun {
+			proceed, err := o.prepareBuild(state)
+			if err != nil || !proceed {
+				result.Duration = time.Since(start)
+				return result, err
+			}
 		}
-		result.Success = false
-		result.Duration = time.Since(start)
-		return result, fmt.Errorf("step-2 blocked by policy: %s", policyEvent.Reason)
-	}
 
-	step2Start := time.Now()
-	if err := o.actionPlan.UpdateStepStatus("step-2", StepStatusInProgress); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-
-	// Capture snapshot before step
-	step2Snapshot, err := o.captureSnapshot()
-	if err != nil {
-		fmt.Printf("⚠️  Failed to capture snapshot: %v\n", err)
-	}
-
-	fmt.Println("🔒 Locking specification...")
-	specLock, err := o.generateSpecLock(productSpec)
-	if err != nil {
-		step, _ := o.actionPlan.GetStep("step-2")
-		step.Error = err.Error()
-		_ = o.actionPlan.UpdateStepStatus("step-2", StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
-		if autoOutput != nil {
-			autoOutput.AddStepResult(StepResult{
-				ID:          "step-2",
-				Type:        "spec:lock",
-				Status:      "failed",
-				StartedAt:   step2Start,
-				CompletedAt: time.Now(),
-				Duration:    time.Since(step2Start),
-				Error:       err.Error(),
-			})
+		cost, err := o.runStep(ctx, stepID, handlers[stepID], state, completedSteps, totalCost, executionStart)
+		if errors.Is(err, router.ErrBudgetPaused) {
+			return o.pauseForBudget(result, autoOutput, stepID, err, start)
 		}
-		return nil, fmt.Errorf("generate spec lock: %w", err)
-	}
-	result.SpecLock = specLock
-	if err := o.actionPlan.UpdateStepStatus("step-2", StepStatusCompleted); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-	step2Cost := 0.01 // Locking is cheap
-	if autoOutput != nil {
-		autoOutput.AddStepResult(StepResult{
-			ID:          "step-2",
-			Type:        "spec:lock",
-			Status:      "completed",
-			StartedAt:   step2Start,
-			CompletedAt: time.Now(),
-			Duration:    time.Since(step2Start),
-			CostUSD:     step2Cost,
-		})
-	}
-	completedSteps++
-	totalCost += step2Cost
-	fmt.Printf("✅ Spec locked: %d features\n\n", len(specLock.Features))
-
-	// Generate and save patch for step 2
-	if err := o.generateAndSavePatch("step-2", "spec:lock", "Lock specification", step2Snapshot); err != nil {
-		fmt.Printf("⚠️  Patch generation warning: %v\n", err)
-	}
-
-	// Pre-flight: Check budget for plan generation
-	if o.router != nil {
-		budget := o.router.GetBudget()
-		estimatedCost := EstimatePlanGenerationCost(len(productSpec.Features), 0.01) // $0.01 per MTok typical
-		warning, err := CheckBudgetWithWarning(budget, estimatedCost, "plan generation")
 		if err != nil {
-			return nil, fmt.Errorf("budget check failed: %w", err)
-		}
-		if warning != "" {
-			fmt.Printf("%s\n\n", warning)
-		}
-	}
-
-	// Step 3: Generate execution plan
-	step3, _ := o.actionPlan.GetStep("step-3")
-
-	// Check policy before executing step
-	allowed, policyEvent, err = o.checkPolicy(ctx, o.policyChecker, step3, 2, completedSteps, totalCost, executionStart)
-	if err != nil {
-		return nil, fmt.Errorf("step-3 policy check: %w", err)
-	}
-	if autoOutput != nil && policyEvent != nil {
-		autoOutput.AddPolicy(*policyEvent)
-	}
-	if !allowed {
-		fmt.Printf("🚫 Step 3 blocked by policy: %s\n", policyEvent.Reason)
-		if autoOutput != nil {
-			autoOutput.SetPartial()
+			result.Success = false
+			result.Duration = time.Since(start)
+			if result.TotalCost == 0 {
+				result.TotalCost = totalCost // The build step sets the actual cost
+			}
+			result.Errors = append(result.Errors, err)
+			return result, err
 		}
-		result.Success = false
-		result.Duration = time.Since(start)
-		return result, fmt.Errorf("step-3 blocked by policy: %s", policyEvent.Reason)
+		completedSteps++
+		totalCost += cost
 	}
+	result.Duration = time.Since(start)
 
-	step3Start := time.Now()
-	if err := o.actionPlan.UpdateStepStatus("step-3", StepStatusInProgress); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
+	// Finalize AutoOutput if enabled
+	if autoOutput != nil {
+		autoOutput.SetCompleted()
 	}
 
-	// Capture snapshot before step
-	step3Snapshot, err := o.captureSnapshot()
-	if err != nil {
-		fmt.Printf("⚠️  Failed to capture snapshot: %v\n", err)
+	// Log workflow completion
+	if o.tracer != nil {
+		o.tracer.LogWorkflowComplete(result.Success, result.Duration, result.TotalCost) //#nosec G104 -- Logging errors not critical
 	}
 
-	fmt.Println("📋 Generating execution plan...")
-	execPlan, err := o.generatePlan(ctx, productSpec, specLock)
-	if err != nil {
-		step, _ := o.actionPlan.GetStep("step-3")
-		step.Error = err.Error()
-		_ = o.actionPlan.UpdateStepStatus("step-3", StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
-		if autoOutput != nil {
-			autoOutput.AddStepResult(StepResult{
-				ID:          "step-3",
-				Type:        "plan:gen",
-				Status:      "failed",
-				StartedAt:   step3Start,
-				CompletedAt: time.Now(),
-				Duration:    time.Since(step3Start),
-				Error:       err.Error(),
-			})
-		}
-		return nil, fmt.Errorf("generate plan: %w", err)
-	}
-	result.Plan = execPlan
-	if err := o.actionPlan.UpdateStepStatus("step-3", StepStatusCompleted); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-	step3Cost := EstimatePlanGenerationCost(len(productSpec.Features), 0.01)
-	if autoOutput != nil {
-		autoOutput.AddStepResult(StepResult{
-			ID:          "step-3",
-			Type:        "plan:gen",
-			Status:      "completed",
-			StartedAt:   step3Start,
-			CompletedAt: time.Now(),
-			Duration:    time.Since(step3Start),
-			CostUSD:     step3Cost,
-		})
-	}
-	completedSteps++
-	totalCost += step3Cost
-	fmt.Printf("✅ Plan created: %d tasks\n\n", len(execPlan.Tasks))
-
-	// Trigger plan created hook
-	o.triggerHook(ctx, hooks.EventPlanCreated, workflowID, map[string]interface{}{
-		"steps": len(execPlan.Tasks),
+	// Trigger workflow complete hook
+	o.triggerHook(ctx, hooks.EventWorkflowComplete, workflowID, map[string]interface{}{
+		"duration": result.Duration.String(),
+		"cost":     result.TotalCost,
+		"success":  result.Success,
 	})
 
-	// Generate and save patch for step 3
-	if err := o.generateAndSavePatch("step-3", "plan:gen", "Generate execution plan", step3Snapshot); err != nil {
-		fmt.Printf("⚠️  Patch generation warning: %v\n", err)
-	}
-
-	// Apply scope filtering if specified
-	if len(o.config.ScopePatterns) > 0 {
-		scope, err := NewScope(o.config.ScopePatterns, o.config.IncludeDependencies)
-		if err != nil {
-			return nil, fmt.Errorf("invalid scope patterns: %w", err)
-		}
-
-		// Estimate impact before filtering
-		matched, total := scope.EstimateImpact(execPlan, productSpec)
-		fmt.Printf("🎯 Applying scope filter: %s\n", scope.Summary())
-		fmt.Printf("   Matched: %d/%d tasks\n\n", matched, total)
-
-		// Filter the plan
-		execPlan = scope.FilterPlan(execPlan, productSpec)
-		result.Plan = execPlan
-		fmt.Printf("✅ Filtered plan: %d tasks\n\n", len(execPlan.Tasks))
-	}
+	return result, nil
+}
 
+// prepareBuild saves the output files and asks for approval of the plan
+// before the build step. It returns false when the workflow ends there:
+// after a dry run, which succeeds, or when the plan is not approved.
+func (o *Orchestrator) prepareBuild(state *WorkflowState) (bool, error) {
 	// Save spec, plan, and action plan to output directory if specified
 	if o.config.OutputDir != "" {
-		if err := o.saveOutputFiles(productSpec, specLock, execPlan, o.actionPlan); err != nil {
+		if err := o.saveOutputFiles(state.Spec, state.SpecLock, state.Plan, o.actionPlan); err != nil {
 			fmt.Printf("⚠️  Warning: failed to save output files: %v\n\n", err)
 		}
 	}
 
-	// Step 4: Approval gate (if enabled)
-	if o.config.RequireApproval && !o.config.DryRun {
-		approved, err := ShowApprovalGate(execPlan, productSpec)
+	// Approval gate (if enabled)
+	// Per-step approval prompts before each task instead
+	if o.config.RequireApproval && !o.config.PerStepApproval && !o.config.DryRun {
+		approved, err := ShowApprovalGate(state.Plan, state.Spec)
 		if err != nil {
-			return nil, fmt.Errorf("approval gate: %w", err)
+			return false, fmt.Errorf("approval gate: %w", err)
 		}
 		if !approved {
-			return result, fmt.Errorf("plan not approved by user")
+			return false, fmt.Errorf("plan not approved by user")
 		}
 		fmt.Println()
 	}
 
 	if o.config.DryRun {
 		fmt.Println("🏁 Dry run complete (no execution)")
-		result.Success = true
-		result.Duration = time.Since(start)
-		if autoOutput != nil {
-			autoOutput.SetCompleted()
+		state.result.Success = true
+		if state.autoOutput != nil {
+			state.autoOutput.SetCompleted()
 		}
-		return result, nil
+		return false, nil
 	}
-
-	// Pre-flight: Check budget for task execution
-	if o.router != nil {
-		budget := o.router.GetBudget()
-		estimatedCost := EstimateTaskExecutionCost(len(execPlan.Tasks), 0.01) // $0.01 per MTok typical
-		warning, err := CheckBudgetWithWarning(budget, estimatedCost, "task execution")
-		if err != nil {
-			return nil, fmt.Errorf("budget check failed: %w", err)
-		}
-		if warning != "" {
-			fmt.Printf("%s\n\n", warning)
-		}
-
-		// Check per-task budget if configured
-		if o.config.MaxCostPerTask > 0 {
-			perTaskEstimate := estimatedCost / float64(len(execPlan.Tasks))
-			if err := CheckPerTaskBudget(perTaskEstimate, o.config.MaxCostPerTask, "average"); err != nil {
-				fmt.Printf("⚠️  Warning: %v\n\n", err)
-			}
-		}
-	}
-
-	// Step 4: Execute plan
-	step4, _ := o.actionPlan.GetStep("step-4")
-
-	// Check policy before executing step
-	allowed, policyEvent, err = o.checkPolicy(ctx, o.policyChecker, step4, 3, completedSteps, totalCost, executionStart)
-	if err != nil {
-		return nil, fmt.Errorf("step-4 policy check: %w", err)
-	}
-	if autoOutput != nil && policyEvent != nil {
-		autoOutput.AddPolicy(*policyEvent)
-	}
-	if !allowed {
-		fmt.Printf("🚫 Step 4 blocked by policy: %s\n", policyEvent.Reason)
-		if autoOutput != nil {
-			autoOutput.SetPartial()
-		}
-		result.Success = false
-		result.Duration = time.Since(start)
-		result.TotalCost = totalCost
-		return result, fmt.Errorf("step-4 blocked by policy: %s", policyEvent.Reason)
-	}
-
-	step4Start := time.Now()
-	if err := o.actionPlan.UpdateStepStatus("step-4", StepStatusInProgress); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-
-	// Capture snapshot before step
-	step4Snapshot, err := o.captureSnapshot()
-	if err != nil {
-		fmt.Printf("⚠️  Failed to capture snapshot: %v\n", err)
-	}
-
-	fmt.Println("🚀 Executing plan...")
-
-	// Get initial budget before execution
-	initialBudget := o.router.GetBudget()
-
-	executor := NewTaskExecutor(nil, o.config, productSpec, o.actionPlan, o.router)
-	execStats, err := executor.Execute(ctx, execPlan)
-	if err != nil {
-		step, _ := o.actionPlan.GetStep("step-4")
-		step.Error = err.Error()
-		_ = o.actionPlan.UpdateStepStatus("step-4", StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
-		result.Success = false
-		result.TasksExecuted = execStats.Executed
-		result.TasksFailed = execStats.Failed
-		result.Duration = time.Since(start)
-		result.Errors = append(result.Errors, err)
-		if autoOutput != nil {
-			autoOutput.AddStepResult(StepResult{
-				ID:          "step-4",
-				Type:        "build:run",
-				Status:      "failed",
-				StartedAt:   step4Start,
-				CompletedAt: time.Now(),
-				Duration:    time.Since(step4Start),
-				Error:       err.Error(),
-			})
-			autoOutput.SetFailed()
-		}
-		return result, fmt.Errorf("execution failed: %w", err)
-	}
-
-	// Get final budget after execution
-	finalBudget := o.router.GetBudget()
-	executionCost := finalBudget.SpentUSD - initialBudget.SpentUSD
-
-	// Mark step-4 as completed
-	if err := o.actionPlan.UpdateStepStatus("step-4", StepStatusCompleted); err != nil {
-		return nil, fmt.Errorf("update step status: %w", err)
-	}
-
-	// Add step-4 result to AutoOutput
-	if autoOutput != nil {
-		autoOutput.AddStepResult(StepResult{
-			ID:          "step-4",
-			Type:        "build:run",
-			Status:      "completed",
-			StartedAt:   step4Start,
-			CompletedAt: time.Now(),
-			Duration:    time.Since(step4Start),
-			CostUSD:     executionCost,
-		})
-	}
-
-	// Update result with execution stats and cost
-	result.Success = execStats.Success
-	result.TasksExecuted = execStats.Executed
-	result.TasksFailed = execStats.Failed
-	result.TotalCost = execStats.TotalCost + executionCost // Include spec generation + execution costs
-	result.Duration = time.Since(start)
-
-	// Print cost summary
-	if result.TotalCost > 0 {
-		fmt.Printf("\n💰 Cost Summary:\n")
-		fmt.Printf("   Spec generation: $%.4f\n", initialBudget.SpentUSD)
-		fmt.Printf("   Task execution:  $%.4f\n", executionCost)
-		fmt.Printf("   Total cost:      $%.4f\n", result.TotalCost)
-		fmt.Printf("   Remaining:       $%.2f / $%.2f\n", finalBudget.RemainingUSD, finalBudget.LimitUSD)
-	}
-
-	// Generate and save patch for step 4
-	if err := o.generateAndSavePatch("step-4", "build:run", "Execute plan", step4Snapshot); err != nil {
-		fmt.Printf("⚠️  Patch generation warning: %v\n", err)
-	}
-
-	// Finalize AutoOutput if enabled
-	if autoOutput != nil {
-		autoOutput.SetCompleted()
-	}
-
-	// Log workflow completion
-	if o.tracer != nil {
-		o.tracer.LogWorkflowComplete(result.Success, result.Duration, result.TotalCost) //#nosec G104 -- Logging errors not critical
-		o.tracer.Close()                                                                //#nosec G104 -- Logging close errors not critical
-	}
-
-	// Trigger workflow complete hook
-	o.triggerHook(ctx, hooks.EventWorkflowComplete, workflowID, map[string]interface{}{
-		"duration": result.Duration.String(),
-		"cost":     result.TotalCost,
-		"success":  result.Success,
-	})
-
-	return result, nil
+	return true, nil
 }
 
 // checkPolicy validates a step against policy constraints.
@@ -612,6 +359,7 @@ func (o *Orchestrator) checkPolicy(
 	completedSteps int,
 	totalCost float64,
 	executionStart time.Time,
+	snapshot map[string]string,
 ) (bool, *PolicyEvent, error) {
 	if policyChecker == nil {
 		return true, nil, nil // No policy checker, allow by default
@@ -622,6 +370,7 @@ func (o *Orchestrator) checkPolicy(
 	policyCtx.CompletedSteps = completedSteps
 	policyCtx.TotalCostSoFar = totalCost
 	policyCtx.ExecutionStartTime = executionStart
+	policyCtx.Snapshot = snapshot
 
 	// Add policy context to Go context
 	ctx = context.WithValue(ctx, "policy_context", policyCtx)
@@ -653,6 +402,18 @@ func (o *Orchestrator) checkPolicy(
 		o.tracer.LogPolicyCheck(step.ID, result.Allowed, result.Reason, result.Metadata) //#nosec G104 -- Logging errors not critical
 	}
 
+	policyData := map[string]interface{}{
+		"step_id":  step.ID,
+		"checker":  policyChecker.Name(),
+		"allowed":  result.Allowed,
+		"reason":   result.Reason,
+		"warnings": result.Warnings,
+	}
+	o.triggerHook(ctx, hooks.EventPolicyCheck, o.workflowID, policyData)
+	if !result.Allowed {
+		o.triggerHook(ctx, hooks.EventPolicyViolation, o.workflowID, policyData)
+	}
+
 	return result.Allowed, policyEvent, nil
 }
 
@@ -676,20 +437,18 @@ func (o *Orchestrator) captureSnapshot() (map[string]string, error) {
 }
 
 // generateAndSavePatch generates a patch from before/after snapshots and saves it
-func (o *Orchestrator) generateAndSavePatch(stepID, stepType, description string, beforeSnapshot map[string]string) error {
+func (o *Orchestrator) generateAndSavePatch(ctx context.Context, stepID, stepType, description string, beforeSnapshot map[string]string) error {
 	if o.patchGenerator == nil || o.patchWriter == nil {
 		return nil // Patch generation not enabled
 	}
 
-	// Get workflow ID from tracer or generate one
-	workflowID := "auto"
-	if o.tracer != nil {
-		workflowID = o.tracer.GetWorkflowID()
-	}
-
 	// Generate patch
-	patchData, err := o.patchGenerator.GeneratePatch(stepID, stepType, workflowID, description, beforeSnapshot)
+	patchData, err := o.patchGenerator.GeneratePatch(stepID, stepType, o.patchWorkflowID(), description, beforeSnapshot)
 	if err != nil {
+		// Writes outside the project root fail the step
+		if errors.Is(err, patch.ErrPathEscapesRoot) {
+			return err
+		}
 		// Log warning but don't fail the step
 		fmt.Printf("⚠️  Failed to generate patch for %s: %v\n", stepID, err)
 		return nil
@@ -700,18 +459,158 @@ func (o *Orchestrator) generateAndSavePatch(stepID, stepType, description string
 		return nil
 	}
 
+	// Let policies inspect the files the step changed
+	blockedReason, err := o.checkChanges(ctx, stepID, beforeSnapshot, patchData.Files)
+	if err != nil {
+		// Fail closed: changes that could not be checked are reverted
+		blockedReason = fmt.Sprintf("policy check failed: %v", err)
+	}
+
 	// Save patch
 	patchPath, err := o.patchWriter.WritePatch(patchData)
 	if err != nil {
+		if blockedReason != "" {
+			return fmt.Errorf("%w: %s (changes not reverted: %v)", ErrChangesBlocked, blockedReason, err)
+		}
 		// Log warning but don't fail the step
 		fmt.Printf("⚠️  Failed to save patch for %s: %v\n", stepID, err)
 		return nil
 	}
 
+	if blockedReason != "" {
+		return o.revertBlockedChanges(stepID, blockedReason)
+	}
+
 	fmt.Printf("💾 Saved patch: %s (%d files, +%d -%d)\n", patchPath, patchData.FilesChanged, patchData.Insertions, patchData.Deletions)
+	o.triggerHook(ctx, hooks.EventPatchSaved, o.workflowID, map[string]interface{}{
+		"step_id":       stepID,
+		"path":          patchPath,
+		"files_changed": patchData.FilesChanged,
+		"insertions":    patchData.Insertions,
+		"deletions":     patchData.Deletions,
+	})
 	return nil
 }
 
+// ErrChangesBlocked is returned when a policy denies the file changes a
+// step produced. The changes are reverted before it is returned.
+var ErrChangesBlocked = errors.New("changes blocked by policy")
+
+// isFatalPatchError reports whether a patch error fails the step rather
+// than being logged as a warning
+func isFatalPatchError(err error) bool {
+	return errors.Is(err, patch.ErrPathEscapesRoot) || errors.Is(err, ErrChangesBlocked)
+}
+
+// checkChanges runs the change checker on the file changes of a completed
+// step, and returns the denial reason if it blocks them
+func (o *Orchestrator) checkChanges(ctx context.Context, stepID string, snapshot map[string]string, changes []patch.FilePatch) (string, error) {
+	if o.changeChecker == nil {
+		return "", nil
+	}
+	step, err := o.actionPlan.GetStep(stepID)
+	if err != nil {
+		return "", err
+	}
+
+	policyCtx := NewPolicyContext(step, o.actionPlan, o.stepIndex(stepID))
+	policyCtx.Snapshot = snapshot
+	policyCtx.Changes = changes
+	result, err := o.changeChecker.CheckStep(context.WithValue(ctx, "policy_context", policyCtx), step)
+	if err != nil {
+		return "", err
+	}
+
+	if o.tracer != nil {
+		o.tracer.LogPolicyCheck(step.ID, result.Allowed, result.Reason, result.Metadata) //#nosec G104 -- Logging errors not critical
+	}
+	if result.Allowed {
+		return "", nil
+	}
+	return result.Reason, nil
+}
+
+// revertBlockedChanges rolls back a step whose changes a policy blocked and
+// marks it failed
+func (o *Orchestrator) revertBlockedChanges(stepID, reason string) error {
+	fmt.Printf("🚫 Changes of %s blocked by policy: %s\n", stepID, reason)
+	if step, err := o.actionPlan.GetStep(stepID); err == nil {
+		step.Error = reason
+		_ = o.actionPlan.UpdateStepStatus(stepID, StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
+	}
+
+	if err := o.patchRollback.RollbackStep(o.patchWorkflowID(), stepID); err != nil {
+		return fmt.Errorf("%w: %s (changes not reverted: %v)", ErrChangesBlocked, reason, err)
+	}
+	fmt.Printf("↩️  Reverted changes of %s\n", stepID)
+	return fmt.Errorf("%w: %s", ErrChangesBlocked, reason)
+}
+
+// pauseForBudget stops the run when the router's pause budget policy
+// rejects a request. The session is saved as a paused checkpoint, which
+// --resume picks up with a raised --max-cost.
+func (o *Orchestrator) pauseForBudget(result *Result, autoOutput *AutoOutput, stepID string, cause error, start time.Time) (*Result, error) {
+	_ = o.actionPlan.UpdateStepStatus(stepID, StepStatusPending) //#nosec G104 -- Status update errors handled at workflow level
+
+	result.Success = false
+	result.Paused = true
+	result.Duration = time.Since(start)
+	result.TotalCost = o.router.GetBudget().SpentUSD
+	if autoOutput != nil {
+		autoOutput.SetPaused()
+	}
+
+	// A pause after the build keeps the spec, plan, and task states the
+	// build checkpointed, so --resume continues from them
+	checkpointMgr := checkpoint.NewManager(o.config.checkpointStore(), true, 30*time.Second).WithCompression(checkpoint.DefaultCompressThreshold)
+	cpState := checkpoint.NewState(o.sessionID)
+	if checkpointMgr.Exists(o.sessionID) {
+		existing, err := checkpointMgr.Load(o.sessionID)
+		if err != nil {
+			return result, fmt.Errorf("%w (checkpoint not saved: %v)", cause, err)
+		}
+		cpState = existing
+	}
+	cpState.Status = CheckpointStatusPaused
+	cpState.SetMetadata("goal", o.config.Goal)
+	cpState.SetMetadata("profile", o.config.Profile)
+	cpState.SetMetadata("max_cost_usd", strconv.FormatFloat(o.config.MaxCostUSD, 'f', -1, 64))
+	cpState.SetMetadata("paused_at", stepID)
+	if err := checkpointMgr.Save(cpState); err != nil {
+		return result, fmt.Errorf("%w (checkpoint not saved: %v)", cause, err)
+	}
+
+	fmt.Printf("⏸️  Paused before %s: %v\n", stepID, cause)
+	fmt.Printf("   Resume with a raised budget: specular auto --resume %s --max-cost <usd>\n", o.sessionID)
+	return result, cause
+}
+
+// pausedGoal returns the goal of the resumed checkpoint when it was paused
+// before the spec was generated
+func (o *Orchestrator) pausedGoal() (string, bool) {
+	checkpointMgr := checkpoint.NewManager(o.config.checkpointStore(), true, 30*time.Second)
+	cpState, err := checkpointMgr.Load(o.config.ResumeFrom)
+	if err != nil || cpState.Status != CheckpointStatusPaused {
+		return "", false
+	}
+	if _, ok := cpState.GetMetadata("spec_json"); ok {
+		return "", false
+	}
+	return cpState.GetMetadata("goal")
+}
+
+// patchWorkflowID returns the workflow ID patches are saved under: the
+// session ID, else the tracer's workflow ID, or "auto" without a tracer
+func (o *Orchestrator) patchWorkflowID() string {
+	if o.sessionID != "" {
+		return o.sessionID
+	}
+	if o.tracer != nil {
+		return o.tracer.GetWorkflowID()
+	}
+	return "auto"
+}
+
 // generateSpecLock creates a locked specification with hashes
 func (o *Orchestrator) generateSpecLock(productSpec *spec.ProductSpec) (*spec.SpecLock, error) {
 	return spec.GenerateSpecLock(*productSpec, "1.0.0")
@@ -729,13 +628,14 @@ func (o *Orchestrator) generatePlan(ctx context.Context, productSpec *spec.Produ
 // executeResume resumes execution from a checkpoint
 func (o *Orchestrator) executeResume(ctx context.Context, start time.Time) (*Result, error) {
 	result := &Result{
-		Success: false,
-		Errors:  []error{},
+		Success:    false,
+		Errors:     []error{},
+		WorkflowID: o.sessionID,
 	}
 
 	// Load checkpoint
 	fmt.Printf("🔄 Resuming from checkpoint: %s\n", o.config.ResumeFrom)
-	checkpointMgr := checkpoint.NewManager(".specular/checkpoints", true, 30*time.Second)
+	checkpointMgr := checkpoint.NewManager(o.config.checkpointStore(), true, 30*time.Second).WithCompression(checkpoint.DefaultCompressThreshold)
 	cpState, err := checkpointMgr.Load(o.config.ResumeFrom)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
@@ -815,7 +715,35 @@ func (o *Orchestrator) executeResume(ctx context.Context, start time.Time) (*Res
 		Tasks: filteredTasks,
 	}
 
-	fmt.Printf("🚀 Resuming execution (%d tasks remaining)...\n", len(filteredTasks))
+	// Re-run only the failed tasks if requested, or re-plan against the
+	// current repository state
+	before := countTasks(cpState)
+	if o.config.RetryFailed {
+		filteredPlan = failedTaskPlan(&execPlan, cpState)
+		filteredTasks = filteredPlan.Tasks
+		if len(filteredTasks) == 0 {
+			fmt.Println("✅ No failed tasks to retry")
+			result.Success = true
+			result.TasksExecuted = len(completed)
+			result.Duration = time.Since(start)
+			return result, nil
+		}
+		for _, task := range filteredTasks {
+			cpState.UpdateTask(task.ID.String(), "pending", nil)
+		}
+	} else if o.config.Replan {
+		filteredPlan, err = o.resumeReplan(ctx, result, &execPlan, completedMap, cpState)
+		if err != nil {
+			return result, err
+		}
+		filteredTasks = filteredPlan.Tasks
+	}
+
+	if o.config.RetryFailed {
+		fmt.Printf("🚀 Retrying %d failed tasks...\n", len(filteredTasks))
+	} else {
+		fmt.Printf("🚀 Resuming execution (%d tasks remaining)...\n", len(filteredTasks))
+	}
 
 	// Get initial budget before execution
 	initialBudget := o.router.GetBudget()
@@ -836,6 +764,10 @@ func (o *Orchestrator) executeResume(ctx context.Context, start time.Time) (*Res
 	finalBudget := o.router.GetBudget()
 	executionCost := finalBudget.SpentUSD - initialBudget.SpentUSD
 
+	if o.config.RetryFailed {
+		printRetrySummary(os.Stdout, o.config.ResumeFrom, before, countTasks(cpState))
+	}
+
 	// Update result
 	result.Success = execStats.Success
 	result.TasksExecuted = len(completed) + execStats.Executed // Include previously completed tasks
@@ -846,6 +778,95 @@ func (o *Orchestrator) executeResume(ctx context.Context, start time.Time) (*Res
 	return result, nil
 }
 
+// resumeReplan re-plans a resumed run, shows what changed, and asks for
+// approval when the remaining tasks changed. execPlan is replaced by the
+// regenerated plan, which is saved to the checkpoint with reopened tasks
+// marked pending. It returns the tasks still to run.
+func (o *Orchestrator) resumeReplan(ctx context.Context, result *Result, execPlan *plan.Plan, completed map[string]bool, cpState *checkpoint.State) (*plan.Plan, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	fmt.Println("🔁 Re-planning from the current repository state...")
+	full, remaining, summary, err := o.replan(ctx, result.Spec, execPlan, completed, workingDir)
+	if err != nil {
+		return nil, fmt.Errorf("replan: %w", err)
+	}
+	result.DriftFindings = summary.Findings
+	printReplanSummary(os.Stdout, summary)
+	fmt.Println()
+
+	if summary.HasChanges() && o.config.RequireApproval {
+		approved, err := confirm(ctx, os.Stdin, os.Stdout, "Continue with the re-planned tasks?")
+		if err != nil {
+			return nil, fmt.Errorf("replan approval: %w", err)
+		}
+		if !approved {
+			return nil, fmt.Errorf("re-plan not approved by user")
+		}
+		fmt.Println()
+	}
+
+	*execPlan = *full
+	for _, taskID := range summary.Reopened {
+		cpState.UpdateTask(taskID, "pending", nil)
+	}
+	if planJSON, err := json.Marshal(full); err == nil {
+		cpState.SetMetadata("plan_json", string(planJSON))
+	}
+
+	return remaining, nil
+}
+
+// pinWorkflowModel pins the model used so far so the remaining steps run on
+// one model, keeping code style consistent across a feature's tasks. An
+// existing pin is left in place.
+func (o *Orchestrator) pinWorkflowModel() {
+	if o.router == nil || o.router.PinnedModel() != "" {
+		return
+	}
+
+	model := o.router.LastUsedModel()
+	if model == "" {
+		return
+	}
+
+	if err := o.router.PinModel(model); err != nil {
+		if o.config.Verbose {
+			fmt.Printf("⚠️  Failed to pin model %s: %v\n", model, err)
+		}
+		return
+	}
+	if o.config.Verbose {
+		fmt.Printf("📌 Pinned model for this workflow: %s\n\n", model)
+	}
+}
+
+// printCostBreakdown prints spend per feature and per workflow step, for
+// chargeback across teams sharing a repository. Only FeatureStep custom
+// steps make per-feature requests, so without one there is no feature
+// breakdown.
+func printCostBreakdown(r *router.Router) {
+	byFeature := make(map[string]float64)
+	for feature, cost := range r.GetCostByFeature() {
+		byFeature[string(feature)] = cost
+	}
+	if len(byFeature) > 0 {
+		fmt.Printf("   By feature:\n")
+		for _, line := range FormatCostBreakdown(byFeature) {
+			fmt.Println(line)
+		}
+	}
+
+	if byStep := r.GetCostByStep(); len(byStep) > 0 {
+		fmt.Printf("   By step:\n")
+		for _, line := range FormatCostBreakdown(byStep) {
+			fmt.Println(line)
+		}
+	}
+}
+
 // saveOutputFiles saves spec, lock, plan, and action plan to the output directory
 func (o *Orchestrator) saveOutputFiles(productSpec *spec.ProductSpec, specLock *spec.SpecLock, execPlan *plan.Plan, actionPlan *ActionPlan) error {
 	// Create output directory if it doesn't exist
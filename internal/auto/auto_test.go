@@ -1,8 +1,10 @@
 package auto
 
 import (
+	"context"
 	"testing"
 
+	"github.com/felixgeelhaar/specular/internal/hooks"
 	"github.com/felixgeelhaar/specular/internal/router"
 )
 
@@ -179,3 +181,60 @@ func TestSetHookRegistry(t *testing.T) {
 
 	// The actual integration with hooks.Registry is tested in integration tests
 }
+
+func TestRegisterConfiguredHooks(t *testing.T) {
+	config := DefaultConfig()
+	config.Hooks = []hooks.HookConfig{
+		{Name: "notify", Type: "webhook", Enabled: true, Events: []hooks.EventType{hooks.EventWorkflowComplete}, Config: map[string]interface{}{"url": "https://example.com"}},
+		{Name: "off", Type: "webhook", Enabled: false, Events: []hooks.EventType{hooks.EventWorkflowComplete}},
+	}
+	orchestrator := NewOrchestrator(nil, config)
+
+	if err := orchestrator.registerConfiguredHooks(); err != nil {
+		t.Fatalf("registerConfiguredHooks() error = %v", err)
+	}
+	registered := orchestrator.hookRegistry.GetHooks(hooks.EventWorkflowComplete)
+	if len(registered) != 1 || registered[0].Name() != "notify" {
+		t.Errorf("expected only the enabled hook, got %v", registered)
+	}
+
+	config.Hooks = []hooks.HookConfig{{Name: "bad", Type: "carrier-pigeon", Enabled: true}}
+	if err := NewOrchestrator(nil, config).registerConfiguredHooks(); err == nil {
+		t.Error("expected an error for an unknown hook type")
+	}
+}
+
+func TestPinWorkflowModel(t *testing.T) {
+	r, err := router.NewRouter(&router.RouterConfig{BudgetUSD: 10})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	orchestrator := NewOrchestrator(r, DefaultConfig())
+
+	// Nothing to pin before a model has been used
+	orchestrator.pinWorkflowModel()
+	if r.PinnedModel() != "" {
+		t.Fatalf("PinnedModel() = %q, want none", r.PinnedModel())
+	}
+
+	// Step 1 generated the spec with this model
+	if err := r.RecordUsage(context.Background(), router.Usage{Model: "claude-sonnet-4", Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.pinWorkflowModel()
+	if r.PinnedModel() != "claude-sonnet-4" {
+		t.Errorf("PinnedModel() = %q, want claude-sonnet-4", r.PinnedModel())
+	}
+
+	// An existing pin is kept
+	if err := r.RecordUsage(context.Background(), router.Usage{Model: "gpt-4o", Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.pinWorkflowModel()
+	if r.PinnedModel() != "claude-sonnet-4" {
+		t.Errorf("PinnedModel() = %q, want claude-sonnet-4 to stay pinned", r.PinnedModel())
+	}
+
+	// A nil router is ignored
+	NewOrchestrator(nil, DefaultConfig()).pinWorkflowModel()
+}
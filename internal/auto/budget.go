@@ -2,8 +2,10 @@ package auto
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
 )
 
 // BudgetThreshold defines warning thresholds for budget usage
@@ -20,8 +22,8 @@ var defaultThresholds = []BudgetThreshold{
 
 // CheckBudget verifies sufficient budget is available for estimated cost
 func CheckBudget(budget *router.Budget, estimatedCost float64, operation string) error {
-	if budget == nil {
-		return nil // No budget enforcement if router not available
+	if budget == nil || budget.Unlimited {
+		return nil // No budget enforcement if router not available or unlimited
 	}
 
 	if estimatedCost > budget.RemainingUSD {
@@ -39,7 +41,7 @@ func CheckBudget(budget *router.Budget, estimatedCost float64, operation string)
 
 // CheckBudgetWithWarning checks budget and returns warning if approaching limits
 func CheckBudgetWithWarning(budget *router.Budget, estimatedCost float64, operation string) (warning string, err error) {
-	if budget == nil {
+	if budget == nil || budget.Unlimited {
 		return "", nil
 	}
 
@@ -68,11 +70,31 @@ func CheckBudgetWithWarning(budget *router.Budget, estimatedCost float64, operat
 	return warning, nil
 }
 
+// reachedThreshold returns the highest warning threshold percentage that
+// spending reaches, or 0 when it is below every threshold
+func reachedThreshold(spentUSD, limitUSD float64) float64 {
+	if limitUSD <= 0 {
+		return 0
+	}
+
+	usagePercent := spentUSD / limitUSD * 100
+	reached := 0.0
+	for _, threshold := range defaultThresholds {
+		if usagePercent >= threshold.Percentage {
+			reached = threshold.Percentage
+		}
+	}
+	return reached
+}
+
 // GetBudgetStatus returns a formatted string showing current budget status
 func GetBudgetStatus(budget *router.Budget) string {
 	if budget == nil {
 		return "Budget: Not available"
 	}
+	if budget.Unlimited {
+		return fmt.Sprintf("Budget: $%.4f spent (unlimited)", budget.SpentUSD)
+	}
 
 	usagePercent := (budget.SpentUSD / budget.LimitUSD) * 100
 	return fmt.Sprintf(
@@ -94,10 +116,16 @@ func EstimateSpecGenerationCost(goalLength int, costPerMToken float64) float64 {
 }
 
 // EstimatePlanGenerationCost estimates the cost of generating a plan from a spec
-func EstimatePlanGenerationCost(featureCount int, costPerMToken float64) float64 {
+// Effort is the spec's total estimated story points; larger features produce
+// longer plans. An effort of 0 (not estimated) weights every feature equally.
+func EstimatePlanGenerationCost(featureCount int, effort types.Effort, costPerMToken float64) float64 {
 	// Estimate: system prompt + spec context + response
-	// More features = more context and more tasks
-	estimatedTokens := 1000 + (featureCount * 500) + (featureCount * 300)
+	// More features = more context; more effort = more tasks in the response
+	responseTokens := featureCount * 300
+	if effort > 0 {
+		responseTokens = effort.Points() * 100 // ~100 tokens per story point
+	}
+	estimatedTokens := 1000 + (featureCount * 500) + responseTokens
 	return (float64(estimatedTokens) / 1000000.0) * costPerMToken
 }
 
@@ -127,3 +155,28 @@ func CheckPerTaskBudget(estimatedCost float64, maxCostPerTask float64, taskID st
 	}
 	return nil
 }
+
+// FormatCostBreakdown renders spend per key, highest first, as indented
+// summary lines. Keys with equal spend are ordered by name.
+func FormatCostBreakdown(costs map[string]float64) []string {
+	keys := make([]string, 0, len(costs))
+	width := 0
+	for key := range costs {
+		keys = append(keys, key)
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if costs[keys[i]] != costs[keys[j]] {
+			return costs[keys[i]] > costs[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = fmt.Sprintf("     %-*s  $%.4f", width, key, costs[key])
+	}
+	return lines
+}
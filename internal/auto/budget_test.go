@@ -1,10 +1,16 @@
 package auto
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/felixgeelhaar/specular/internal/checkpoint"
+	"github.com/felixgeelhaar/specular/internal/provider"
 	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
 )
 
 func TestCheckBudget_NilBudget(t *testing.T) {
@@ -45,6 +51,23 @@ func TestCheckBudget_InsufficientBudget(t *testing.T) {
 	}
 }
 
+func TestCheckBudget_UnlimitedBudget(t *testing.T) {
+	budget := &router.Budget{SpentUSD: 250.0, Unlimited: true}
+
+	if err := CheckBudget(budget, 100.0, "test operation"); err != nil {
+		t.Errorf("CheckBudget with unlimited budget should return nil, got %v", err)
+	}
+
+	warning, err := CheckBudgetWithWarning(budget, 100.0, "test operation")
+	if err != nil || warning != "" {
+		t.Errorf("CheckBudgetWithWarning with unlimited budget = (%q, %v), want no warning or error", warning, err)
+	}
+
+	if status := GetBudgetStatus(budget); !strings.Contains(status, "unlimited") {
+		t.Errorf("GetBudgetStatus() = %q, want unlimited", status)
+	}
+}
+
 func TestCheckBudget_ExactBudget(t *testing.T) {
 	budget := &router.Budget{
 		LimitUSD:     10.0,
@@ -251,18 +274,20 @@ func TestEstimatePlanGenerationCost(t *testing.T) {
 	tests := []struct {
 		name         string
 		featureCount int
+		effort       types.Effort
 		costPerMTok  float64
 		wantPositive bool
 	}{
-		{"Few features", 2, 0.01, true},
-		{"Many features", 10, 0.01, true},
-		{"Zero features", 0, 0.01, true}, // Still has base cost
-		{"Zero cost per token", 5, 0.0, false},
+		{"Few features", 2, 0, 0.01, true},
+		{"Many features", 10, 0, 0.01, true},
+		{"Few features with effort", 2, 5, 0.01, true},
+		{"Zero features", 0, 0, 0.01, true}, // Still has base cost
+		{"Zero cost per token", 5, 13, 0.0, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cost := EstimatePlanGenerationCost(tt.featureCount, tt.costPerMTok)
+			cost := EstimatePlanGenerationCost(tt.featureCount, tt.effort, tt.costPerMTok)
 			if tt.wantPositive && cost <= 0 {
 				t.Errorf("EstimatePlanGenerationCost() = %v, want positive value", cost)
 			}
@@ -274,8 +299,8 @@ func TestEstimatePlanGenerationCost(t *testing.T) {
 }
 
 func TestEstimatePlanGenerationCost_Scaling(t *testing.T) {
-	costFew := EstimatePlanGenerationCost(2, 0.01)
-	costMany := EstimatePlanGenerationCost(10, 0.01)
+	costFew := EstimatePlanGenerationCost(2, 0, 0.01)
+	costMany := EstimatePlanGenerationCost(10, 0, 0.01)
 
 	// More features should cost more
 	if costMany <= costFew {
@@ -283,6 +308,21 @@ func TestEstimatePlanGenerationCost_Scaling(t *testing.T) {
 	}
 }
 
+func TestEstimatePlanGenerationCost_EffortWeighting(t *testing.T) {
+	costSmall := EstimatePlanGenerationCost(3, 3, 0.01)
+	costLarge := EstimatePlanGenerationCost(3, 34, 0.01)
+
+	// The same number of features costs more when they take more effort
+	if costLarge <= costSmall {
+		t.Errorf("More effort should cost more: small=%v, large=%v", costSmall, costLarge)
+	}
+
+	// Without an effort estimate, every feature is weighted equally
+	if got, want := EstimatePlanGenerationCost(3, 0, 0.01), EstimatePlanGenerationCost(3, 9, 0.01); got != want {
+		t.Errorf("unestimated cost = %v, want %v (3 points per feature)", got, want)
+	}
+}
+
 func TestEstimateTaskExecutionCost(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -372,3 +412,122 @@ func TestBudgetThresholds_NonEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCostBreakdown(t *testing.T) {
+	lines := FormatCostBreakdown(map[string]float64{
+		"billing":   0.05,
+		"user-auth": 0.35,
+		"audit":     0.05,
+	})
+
+	want := []string{
+		"     user-auth  $0.3500",
+		"     audit      $0.0500",
+		"     billing    $0.0500",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FormatCostBreakdown() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	if lines := FormatCostBreakdown(nil); len(lines) != 0 {
+		t.Errorf("FormatCostBreakdown(nil) = %v, want no lines", lines)
+	}
+}
+
+func TestExecute_PausesAndResumesOnBudget(t *testing.T) {
+	store := t.TempDir()
+	newOrchestrator := func(budget float64, config Config) *Orchestrator {
+		registry := provider.NewRegistry()
+		if err := registry.Register("anthropic", &specProvider{}, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+			t.Fatal(err)
+		}
+		r, err := router.NewRouterWithProviders(&router.RouterConfig{
+			BudgetUSD:        budget,
+			OnBudgetExceeded: string(router.BudgetPolicyPause),
+		}, registry)
+		if err != nil {
+			t.Fatalf("NewRouterWithProviders() error = %v", err)
+		}
+		config.CheckpointStore = store
+		config.DryRun = true
+		return NewOrchestrator(r, config)
+	}
+
+	// The pre-flight estimate fits, but the spec request does not
+	result, err := newOrchestrator(0.0001, Config{Goal: "Build a todo API"}).Execute(context.Background())
+	if !errors.Is(err, router.ErrBudgetPaused) {
+		t.Fatalf("Execute() error = %v, want ErrBudgetPaused", err)
+	}
+	if result == nil || !result.Paused || result.WorkflowID == "" {
+		t.Fatalf("Execute() result = %+v, want a paused run", result)
+	}
+
+	cpState, err := checkpoint.NewManager(store, false, 0).Load(result.WorkflowID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cpState.Status != CheckpointStatusPaused {
+		t.Errorf("checkpoint status = %q, want %q", cpState.Status, CheckpointStatusPaused)
+	}
+
+	// Resuming with a raised budget restarts the run under the same session
+	resumed, err := newOrchestrator(10, Config{ResumeFrom: result.WorkflowID}).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("resumed Execute() error = %v", err)
+	}
+	if !resumed.Success || resumed.WorkflowID != result.WorkflowID || resumed.Spec == nil {
+		t.Errorf("resumed result = %+v, want a successful run of %s", resumed, result.WorkflowID)
+	}
+}
+
+func TestPauseForBudget_KeepsBuildCheckpoint(t *testing.T) {
+	store := t.TempDir()
+	r, err := router.NewRouterWithProviders(&router.RouterConfig{BudgetUSD: 1}, provider.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	o := NewOrchestrator(r, Config{Goal: "Build a todo API", CheckpointStore: store, MaxCostUSD: 1})
+	o.sessionID = "auto-paused"
+	if err := o.RegisterStep(&testStepHandler{stepType: "db:migrate", err: router.ErrBudgetPaused}); err != nil {
+		t.Fatalf("RegisterStep: %v", err)
+	}
+	plan, err := ComposeActionPlan(o.config.Goal, "default", false, o.customSteps)
+	if err != nil {
+		t.Fatalf("ComposeActionPlan: %v", err)
+	}
+	o.actionPlan = plan
+
+	// The build checkpointed the spec and its tasks before the custom step
+	checkpointMgr := checkpoint.NewManager(store, false, 0)
+	built := checkpoint.NewState(o.sessionID)
+	built.SetMetadata("spec_json", `{"product":"todo"}`)
+	built.UpdateTask("task-001", "completed", nil)
+	if err := checkpointMgr.Save(built); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = o.runStep(context.Background(), "step-db-migrate", o.customSteps[0], &WorkflowState{}, 4, 0, time.Now())
+	if !errors.Is(err, router.ErrBudgetPaused) {
+		t.Fatalf("runStep() error = %v, want ErrBudgetPaused", err)
+	}
+	if _, err := o.pauseForBudget(&Result{}, nil, "step-db-migrate", err, time.Now()); !errors.Is(err, router.ErrBudgetPaused) {
+		t.Fatalf("pauseForBudget() error = %v, want ErrBudgetPaused", err)
+	}
+
+	cpState, err := checkpointMgr.Load(o.sessionID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cpState.Status != CheckpointStatusPaused {
+		t.Errorf("checkpoint status = %q, want %q", cpState.Status, CheckpointStatusPaused)
+	}
+	if pausedAt, _ := cpState.GetMetadata("paused_at"); pausedAt != "step-db-migrate" {
+		t.Errorf("paused_at = %q, want step-db-migrate", pausedAt)
+	}
+	if _, ok := cpState.GetMetadata("spec_json"); !ok {
+		t.Error("spec_json was dropped from the checkpoint")
+	}
+	if completed := cpState.GetCompletedTasks(); len(completed) != 1 || completed[0] != "task-001" {
+		t.Errorf("completed tasks = %v, want [task-001]", completed)
+	}
+}
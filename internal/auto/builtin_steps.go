@@ -0,0 +1,273 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/felixgeelhaar/specular/internal/eval"
+	"github.com/felixgeelhaar/specular/internal/hooks"
+	"github.com/felixgeelhaar/specular/internal/plan"
+)
+
+// budgetedStep is implemented by steps that check the budget for their
+// model calls before their policy check
+type budgetedStep interface {
+	checkBudget(ctx context.Context, stepID string) error
+}
+
+// builtinStep holds what the built-in steps share: the orchestrator and the
+// state of the run
+type builtinStep struct {
+	o     *Orchestrator
+	state *WorkflowState
+}
+
+// builtinSteps returns the handlers of the built-in steps by step type
+func (o *Orchestrator) builtinSteps(state *WorkflowState) map[StepType]StepHandler {
+	base := builtinStep{o: o, state: state}
+	return map[StepType]StepHandler{
+		StepTypeSpecUpdate: specStep{base},
+		StepTypeSpecLock:   lockStep{base},
+		StepTypePlanGen:    planStep{base},
+		StepTypeBuildRun:   buildStep{base},
+		StepTypeVerify:     verifyStep{base},
+	}
+}
+
+// specStep generates the spec from the goal and runs the spec validators
+type specStep struct{ builtinStep }
+
+func (s specStep) Type() StepType { return StepTypeSpecUpdate }
+
+func (s specStep) EstimateCost() float64 {
+	return EstimateSpecGenerationCost(len(s.state.Goal), 0.01) // $0.01 per MTok typical
+}
+
+func (s specStep) checkBudget(ctx context.Context, stepID string) error {
+	return s.o.checkBudget(ctx, stepID, s.EstimateCost(), "spec generation")
+}
+
+func (s specStep) Execute(ctx context.Context, state *WorkflowState) error {
+	fmt.Println("🤖 Generating specification from goal...")
+	productSpec, err := s.o.parser.ParseGoal(ctx, state.Goal)
+	if err != nil {
+		return fmt.Errorf("parse goal: %w", err)
+	}
+	state.result.Spec = productSpec
+	s.o.emitSpecOutput(ctx, productSpec)
+
+	// Run spec validators before the spec is locked
+	validationIssues, err := s.o.validateSpec(ctx, productSpec)
+	if state.autoOutput != nil {
+		state.autoOutput.AddValidationIssues(validationIssues)
+	}
+	printValidationIssues(validationIssues)
+	state.warnings = issueStrings(validationIssues)
+	if err != nil {
+		return err
+	}
+
+	state.Spec = productSpec
+	state.CostUSD = s.EstimateCost()
+	fmt.Printf("✅ Generated spec: %s\n", productSpec.Product)
+	fmt.Printf("   Features: %d\n\n", len(productSpec.Features))
+
+	// Keep the plan and task execution on the model that wrote the spec
+	s.o.pinWorkflowModel()
+	return nil
+}
+
+// lockStep locks the spec with hashes
+type lockStep struct{ builtinStep }
+
+func (s lockStep) Type() StepType { return StepTypeSpecLock }
+
+func (s lockStep) EstimateCost() float64 { return 0.01 } // Locking is cheap
+
+func (s lockStep) Execute(ctx context.Context, state *WorkflowState) error {
+	fmt.Println("🔒 Locking specification...")
+	specLock, err := s.o.generateSpecLock(state.Spec)
+	if err != nil {
+		return fmt.Errorf("generate spec lock: %w", err)
+	}
+	state.SpecLock = specLock
+	state.result.SpecLock = specLock
+	state.CostUSD = s.EstimateCost()
+	fmt.Printf("✅ Spec locked: %d features\n\n", len(specLock.Features))
+	return nil
+}
+
+// planStep generates the execution plan and applies the scope filter
+type planStep struct{ builtinStep }
+
+func (s planStep) Type() StepType { return StepTypePlanGen }
+
+func (s planStep) EstimateCost() float64 {
+	if s.state.Spec == nil {
+		return 0
+	}
+	features := s.state.Spec.Features
+	return EstimatePlanGenerationCost(len(features), plan.EstimateSpecEffort(features), 0.01) // $0.01 per MTok typical
+}
+
+func (s planStep) checkBudget(ctx context.Context, stepID string) error {
+	return s.o.checkBudget(ctx, stepID, s.EstimateCost(), "plan generation")
+}
+
+func (s planStep) Execute(ctx context.Context, state *WorkflowState) error {
+	fmt.Println("📋 Generating execution plan...")
+	execPlan, err := s.o.generatePlan(ctx, state.Spec, state.SpecLock)
+	if err != nil {
+		return fmt.Errorf("generate plan: %w", err)
+	}
+	state.CostUSD = s.EstimateCost()
+	fmt.Printf("✅ Plan created: %d tasks\n\n", len(execPlan.Tasks))
+
+	// Trigger plan created hook
+	s.o.triggerHook(ctx, hooks.EventPlanCreated, s.o.workflowID, map[string]interface{}{
+		"steps": len(execPlan.Tasks),
+	})
+
+	// Apply scope filtering if specified
+	if len(s.o.config.ScopePatterns) > 0 {
+		scope, err := NewScope(s.o.config.ScopePatterns, s.o.config.IncludeDependencies)
+		if err != nil {
+			return fmt.Errorf("invalid scope patterns: %w", err)
+		}
+
+		// Estimate impact before filtering
+		matched, total := scope.EstimateImpact(execPlan, state.Spec)
+		fmt.Printf("🎯 Applying scope filter: %s\n", scope.Summary())
+		fmt.Printf("   Matched: %d/%d tasks\n\n", matched, total)
+
+		// Filter the plan
+		execPlan = scope.FilterPlan(execPlan, state.Spec)
+		fmt.Printf("✅ Filtered plan: %d tasks\n\n", len(execPlan.Tasks))
+	}
+
+	state.Plan = execPlan
+	state.result.Plan = execPlan
+	return nil
+}
+
+// buildStep executes the plan's tasks
+type buildStep struct{ builtinStep }
+
+func (s buildStep) Type() StepType { return StepTypeBuildRun }
+
+func (s buildStep) EstimateCost() float64 {
+	if s.state.Plan == nil {
+		return 0
+	}
+	return EstimateTaskExecutionCost(len(s.state.Plan.Tasks), 0.01) // $0.01 per MTok typical
+}
+
+func (s buildStep) checkBudget(ctx context.Context, stepID string) error {
+	estimatedCost := s.EstimateCost()
+	if err := s.o.checkBudget(ctx, stepID, estimatedCost, "task execution"); err != nil {
+		return err
+	}
+
+	// Check per-task budget if configured
+	if s.o.config.MaxCostPerTask > 0 && len(s.state.Plan.Tasks) > 0 {
+		perTaskEstimate := estimatedCost / float64(len(s.state.Plan.Tasks))
+		if err := CheckPerTaskBudget(perTaskEstimate, s.o.config.MaxCostPerTask, "average"); err != nil {
+			fmt.Printf("⚠️  Warning: %v\n\n", err)
+		}
+	}
+	return nil
+}
+
+func (s buildStep) Execute(ctx context.Context, state *WorkflowState) error {
+	o := s.o
+	fmt.Println("🚀 Executing plan...")
+
+	// Get initial budget before execution
+	initialBudget := o.router.GetBudget()
+
+	executor := NewTaskExecutor(nil, o.config, state.Spec, o.actionPlan, o.router)
+	executor.SetSessionID(o.sessionID)
+	if o.config.PerStepApproval {
+		executor.SetTaskGate(o.newStepApprover(ctx, os.Stdin, os.Stdout, state.autoOutput, len(state.Plan.Tasks)).approve)
+	}
+	execStats, err := executor.Execute(ctx, state.Plan)
+	if execStats != nil {
+		o.emitTaskOutput(ctx, state.Plan, execStats.TaskResults)
+		state.result.TasksExecuted = execStats.Executed
+		state.result.TasksFailed = execStats.Failed
+	}
+	if err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	// Get final budget after execution
+	finalBudget := o.router.GetBudget()
+	executionCost := finalBudget.SpentUSD - initialBudget.SpentUSD
+	state.CostUSD = executionCost
+
+	// Update result with execution stats and cost
+	state.result.Success = execStats.Success
+	state.result.TotalCost = execStats.TotalCost + executionCost // Include spec generation + execution costs
+
+	// Print cost summary
+	if state.result.TotalCost > 0 {
+		fmt.Printf("\n💰 Cost Summary:\n")
+		fmt.Printf("   Spec generation: $%.4f\n", initialBudget.SpentUSD)
+		fmt.Printf("   Task execution:  $%.4f\n", executionCost)
+		fmt.Printf("   Total cost:      $%.4f\n", state.result.TotalCost)
+		if finalBudget.Unlimited {
+			fmt.Printf("   Remaining:       unlimited\n")
+		} else {
+			fmt.Printf("   Remaining:       $%.2f / $%.2f\n", finalBudget.RemainingUSD, finalBudget.LimitUSD)
+		}
+		printCostBreakdown(o.router)
+	}
+	return nil
+}
+
+// verifyStep runs the configured build and test commands, or the eval
+// smoke checks when none are configured. When verification fails and
+// RollbackOnVerifyFailure is set, the step-4 patch is reverted.
+type verifyStep struct{ builtinStep }
+
+func (s verifyStep) Type() StepType { return StepTypeVerify }
+
+func (s verifyStep) EstimateCost() float64 { return 0 }
+
+func (s verifyStep) Execute(ctx context.Context, state *WorkflowState) error {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	fmt.Println("\n🔍 Verifying build and tests...")
+	report := eval.RunSmokeChecks(ctx, workingDir, eval.ParseSmokeChecks(s.o.config.VerifyCommands), os.Stdout)
+	state.result.EvalResult = report
+
+	checks := make([]map[string]interface{}, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, map[string]interface{}{
+			"name":     check.Name,
+			"passed":   check.Passed,
+			"duration": check.Duration.String(),
+		})
+	}
+	state.metadata = map[string]interface{}{"checks": checks}
+
+	if report.AllPassed {
+		fmt.Printf("✅ Verification passed (%d checks)\n", report.TotalPassed)
+		return nil
+	}
+
+	if s.o.config.RollbackOnVerifyFailure {
+		if err := s.o.rollbackStep("step-4"); err != nil {
+			fmt.Printf("⚠️  Rollback failed: %v\n", err)
+			state.warnings = append(state.warnings, fmt.Sprintf("rollback failed: %v", err))
+		} else {
+			fmt.Println("↩️  Rolled back step-4 changes")
+			state.metadata["rolledBack"] = true
+		}
+	}
+	return fmt.Errorf("%d of %d verify checks failed", report.TotalFailed, len(report.Checks))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/felixgeelhaar/specular/internal/drift"
 	"github.com/felixgeelhaar/specular/internal/eval"
+	"github.com/felixgeelhaar/specular/internal/hooks"
 	"github.com/felixgeelhaar/specular/internal/plan"
 	"github.com/felixgeelhaar/specular/internal/spec"
 )
@@ -16,6 +17,7 @@ type Config struct {
 
 	// Approval settings
 	RequireApproval bool `yaml:"require_approval"`
+	PerStepApproval bool `yaml:"per_step_approval"` // Prompt before each task instead of once for the plan
 
 	// Budget constraints
 	MaxCostUSD     float64 `yaml:"max_cost_usd"`
@@ -25,6 +27,15 @@ type Config struct {
 	MaxRetries int           `yaml:"max_retries"`
 	RetryDelay time.Duration `yaml:"retry_delay"`
 
+	// Execution settings
+	MaxParallelTasks int  `yaml:"max_parallel_tasks"` // Independent tasks run at once (0 or 1 = sequential)
+	FailFast         bool `yaml:"fail_fast"`          // Stop starting tasks once a task fails
+
+	// Verification settings
+	Verify                  bool     `yaml:"verify"`                     // Run the verify step after execution
+	VerifyCommands          []string `yaml:"verify_commands"`            // Build/test commands (empty = eval smoke checks)
+	RollbackOnVerifyFailure bool     `yaml:"rollback_on_verify_failure"` // Revert the execution patch if verification fails
+
 	// Timeout settings
 	TimeoutMinutes int           `yaml:"timeout_minutes"`
 	TaskTimeout    time.Duration `yaml:"task_timeout"`
@@ -32,17 +43,30 @@ type Config struct {
 	// Policy enforcement
 	PolicyPath string `yaml:"policy_path"`
 
+	// Spec validation
+	BlockOnValidationErrors bool `yaml:"block_on_validation_errors"` // Fail step 1 when a spec validator reports an error
+
 	// Behavior flags
 	FallbackToManual bool `yaml:"fallback_to_manual"`
 	Verbose          bool `yaml:"verbose"`
 	DryRun           bool `yaml:"dry_run"`
 
 	// Resume settings
-	ResumeFrom string `yaml:"resume_from"` // Checkpoint operation ID to resume from
+	ResumeFrom  string `yaml:"resume_from"`  // Checkpoint operation ID to resume from
+	Replan      bool   `yaml:"replan"`       // Re-plan remaining work against the current repository on resume
+	RetryFailed bool   `yaml:"retry_failed"` // On resume, re-run only the tasks that failed
+
+	// Checkpoint settings
+	CheckpointStore string `yaml:"checkpoint_store"` // Checkpoint directory or s3://bucket/prefix URL
 
 	// Output settings
-	OutputDir  string `yaml:"output_dir"`  // Directory to save spec and plan files
-	JSONOutput bool   `yaml:"json_output"` // Enable JSON output format
+	OutputDir       string `yaml:"output_dir"`        // Directory to save spec and plan files
+	JSONOutput      bool   `yaml:"json_output"`       // Enable JSON output format
+	EventStreamPath string `yaml:"event_stream_path"` // Write lifecycle events as JSON lines to this file ("-" = stdout)
+
+	// Hooks are registered with the orchestrator's hook registry when the
+	// workflow starts, from the profile and hooks.yaml
+	Hooks []hooks.HookConfig `yaml:"hooks"`
 
 	// Scope filtering
 	ScopePatterns       []string `yaml:"scope_patterns"`       // Patterns to filter plan execution
@@ -50,11 +74,16 @@ type Config struct {
 
 	// Profile name for execution settings
 	Profile string `yaml:"profile"`
+
+	// Seed fixes model sampling for reproducible runs (0 = unseeded)
+	Seed int64 `yaml:"seed"`
 }
 
 // Result contains the outcome of auto mode execution
 type Result struct {
 	Success       bool
+	Paused        bool   // Stopped by the router's pause budget policy; resume with --resume WorkflowID
+	WorkflowID    string // Identifies the run in checkpoints, hooks, traces, and attestations
 	Spec          *spec.ProductSpec
 	SpecLock      *spec.SpecLock
 	Plan          *plan.Plan
@@ -69,6 +98,22 @@ type Result struct {
 	Errors        []error
 }
 
+// CheckpointStatusPaused marks a checkpoint saved when the budget paused a run
+const CheckpointStatusPaused = "paused"
+
+// DefaultCheckpointStore is the local directory checkpoints are saved to
+// when no store is configured
+const DefaultCheckpointStore = ".specular/checkpoints"
+
+// checkpointStore returns the configured checkpoint location, or the
+// default directory
+func (c Config) checkpointStore() string {
+	if c.CheckpointStore == "" {
+		return DefaultCheckpointStore
+	}
+	return c.CheckpointStore
+}
+
 // DefaultConfig returns a Config with sensible defaults
 func DefaultConfig() Config {
 	return Config{
@@ -80,6 +125,7 @@ func DefaultConfig() Config {
 		TimeoutMinutes:   30,
 		TaskTimeout:      time.Minute * 5,
 		PolicyPath:       ".specular/policy.yaml",
+		CheckpointStore:  DefaultCheckpointStore,
 		FallbackToManual: true,
 		Verbose:          false,
 		DryRun:           false,
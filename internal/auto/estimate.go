@@ -0,0 +1,127 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// StepEstimate is the projected cost of one workflow step
+type StepEstimate struct {
+	ID      string  `json:"id"`
+	Type    string  `json:"type"`
+	CostUSD float64 `json:"costUsd"`
+}
+
+// CostEstimate is the projected cost of an auto run
+type CostEstimate struct {
+	// Goal is the user's original objective
+	Goal string `json:"goal"`
+
+	// Model is the model the router would select for the run's AI steps
+	Model string `json:"model"`
+
+	// CostPerMToken is the selected model's cost per million tokens in USD
+	CostPerMToken float64 `json:"costPerMToken"`
+
+	// Features is the number of features parsed from the goal
+	Features int `json:"features"`
+
+	// Effort is the features' total estimated story points
+	Effort types.Effort `json:"effort"`
+
+	// Tasks is the number of tasks that would execute after scope filtering
+	Tasks int `json:"tasks"`
+
+	// Steps lists the projected cost of each workflow step
+	Steps []StepEstimate `json:"steps"`
+
+	// TotalUSD is the projected cost of the whole run
+	TotalUSD float64 `json:"totalUsd"`
+
+	// BudgetUSD is the run's cost limit (0 = unlimited)
+	BudgetUSD float64 `json:"budgetUsd"`
+}
+
+// HeadroomUSD returns the budget left after the projected cost. It is
+// negative when the projection exceeds the budget.
+func (e *CostEstimate) HeadroomUSD() float64 {
+	return e.BudgetUSD - e.TotalUSD
+}
+
+// ExceedsBudget reports whether the projected cost exceeds a set budget
+func (e *CostEstimate) ExceedsBudget() bool {
+	return e.BudgetUSD > 0 && e.TotalUSD > e.BudgetUSD
+}
+
+// Estimate projects the cost of an auto run without executing it. It parses
+// the goal and generates the plan, the cheap steps, then prices each step at
+// the cost of the model the router selects. Scope patterns are applied so
+// the task count reflects what would actually execute.
+func (o *Orchestrator) Estimate(ctx context.Context) (*CostEstimate, error) {
+	// Price the run at the model goal parsing would use, before parsing
+	// charges the budget
+	selection, err := o.router.SelectModel(ctx, router.RoutingRequest{
+		ModelHint:  "agentic",
+		Complexity: 7,
+		Priority:   "P0",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select model: %w", err)
+	}
+	costPerMToken := selection.Model.CostPerMToken
+
+	productSpec, err := o.parser.ParseGoal(ctx, o.config.Goal)
+	if err != nil {
+		return nil, fmt.Errorf("parse goal: %w", err)
+	}
+
+	specLock, err := o.generateSpecLock(productSpec)
+	if err != nil {
+		return nil, fmt.Errorf("generate spec lock: %w", err)
+	}
+
+	execPlan, err := o.generatePlan(ctx, productSpec, specLock)
+	if err != nil {
+		return nil, fmt.Errorf("generate plan: %w", err)
+	}
+
+	if len(o.config.ScopePatterns) > 0 {
+		scope, err := NewScope(o.config.ScopePatterns, o.config.IncludeDependencies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope patterns: %w", err)
+		}
+		execPlan = scope.FilterPlan(execPlan, productSpec)
+	}
+
+	effort := plan.EstimateSpecEffort(productSpec.Features)
+	estimate := &CostEstimate{
+		Goal:          o.config.Goal,
+		Model:         selection.Model.ID,
+		CostPerMToken: costPerMToken,
+		Features:      len(productSpec.Features),
+		Effort:        effort,
+		Tasks:         len(execPlan.Tasks),
+		Steps: []StepEstimate{
+			{ID: "step-1", Type: string(StepTypeSpecUpdate), CostUSD: EstimateSpecGenerationCost(len(o.config.Goal), costPerMToken)},
+			{ID: "step-3", Type: string(StepTypePlanGen), CostUSD: EstimatePlanGenerationCost(len(productSpec.Features), effort, costPerMToken)},
+			{ID: "step-4", Type: string(StepTypeBuildRun), CostUSD: EstimateTaskExecutionCost(len(execPlan.Tasks), costPerMToken)},
+		},
+		BudgetUSD: o.config.MaxCostUSD,
+	}
+	for _, handler := range o.customSteps {
+		estimate.Steps = append(estimate.Steps, StepEstimate{
+			ID:      customStepID(handler.Type()),
+			Type:    string(handler.Type()),
+			CostUSD: handler.EstimateCost(),
+		})
+	}
+	for _, step := range estimate.Steps {
+		estimate.TotalUSD += step.CostUSD
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,147 @@
+package auto
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/router"
+)
+
+const estimateSpecYAML = `product: Todo API
+goals:
+  - Manage todos
+features:
+  - id: todo-crud
+    title: Todo CRUD
+    desc: Create, read, update, and delete todos
+    priority: P0
+    success:
+      - Todos can be created
+      - Todos can be listed
+  - id: todo-search
+    title: Todo search
+    desc: Search todos by title
+    priority: P1
+    success:
+      - Todos can be searched
+`
+
+// specProvider answers every request with a canned spec
+type specProvider struct{}
+
+func (p *specProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	return &provider.GenerateResponse{Content: estimateSpecYAML, TokensUsed: 100}, nil
+}
+
+func (p *specProvider) Stream(ctx context.Context, req *provider.GenerateRequest) (<-chan provider.StreamChunk, error) {
+	return nil, errors.New("streaming not supported")
+}
+
+func (p *specProvider) GetCapabilities() *provider.ProviderCapabilities {
+	return &provider.ProviderCapabilities{}
+}
+
+func (p *specProvider) GetInfo() *provider.ProviderInfo {
+	return &provider.ProviderInfo{Name: "spec"}
+}
+
+func (p *specProvider) IsAvailable() bool { return true }
+
+func (p *specProvider) Health(ctx context.Context) error { return nil }
+
+func (p *specProvider) Close() error { return nil }
+
+func newEstimateTestOrchestrator(t *testing.T, config Config) *Orchestrator {
+	t.Helper()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", &specProvider{}, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := router.NewRouterWithProviders(&router.RouterConfig{BudgetUSD: 10}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+
+	return NewOrchestrator(r, config)
+}
+
+func TestEstimate(t *testing.T) {
+	o := newEstimateTestOrchestrator(t, Config{Goal: "Build a todo API", MaxCostUSD: 5})
+
+	estimate, err := o.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	if estimate.Model == "" || estimate.CostPerMToken <= 0 {
+		t.Fatalf("estimate model = %q at $%.2f/MTok, want a priced model", estimate.Model, estimate.CostPerMToken)
+	}
+	if estimate.Features != 2 {
+		t.Errorf("Features = %d, want 2", estimate.Features)
+	}
+	// todo-crud is 2 points (two success criteria), todo-search 1
+	if estimate.Effort != 3 {
+		t.Errorf("Effort = %d, want 3", estimate.Effort)
+	}
+	if estimate.Tasks != 2 {
+		t.Errorf("Tasks = %d, want 2", estimate.Tasks)
+	}
+	if len(estimate.Steps) != 3 {
+		t.Fatalf("len(Steps) = %d, want 3", len(estimate.Steps))
+	}
+
+	want := EstimateSpecGenerationCost(len("Build a todo API"), estimate.CostPerMToken) +
+		EstimatePlanGenerationCost(2, 3, estimate.CostPerMToken) +
+		EstimateTaskExecutionCost(2, estimate.CostPerMToken)
+	if estimate.TotalUSD != want {
+		t.Errorf("TotalUSD = %v, want %v", estimate.TotalUSD, want)
+	}
+	if estimate.HeadroomUSD() != 5-want {
+		t.Errorf("HeadroomUSD() = %v, want %v", estimate.HeadroomUSD(), 5-want)
+	}
+	if estimate.ExceedsBudget() {
+		t.Error("ExceedsBudget() = true, want false")
+	}
+}
+
+func TestEstimate_Scope(t *testing.T) {
+	o := newEstimateTestOrchestrator(t, Config{
+		Goal:          "Build a todo API",
+		ScopePatterns: []string{"feature:todo-search"},
+	})
+
+	estimate, err := o.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	if estimate.Tasks != 1 {
+		t.Errorf("Tasks = %d, want 1 after scope filtering", estimate.Tasks)
+	}
+}
+
+func TestCostEstimate_ExceedsBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  float64
+		budget float64
+		want   bool
+	}{
+		{"under budget", 1, 2, false},
+		{"over budget", 3, 2, true},
+		{"unlimited", 3, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &CostEstimate{TotalUSD: tt.total, BudgetUSD: tt.budget}
+			if got := e.ExceedsBudget(); got != tt.want {
+				t.Errorf("ExceedsBudget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
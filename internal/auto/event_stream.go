@@ -0,0 +1,223 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/specular/internal/exec"
+	"github.com/felixgeelhaar/specular/internal/hooks"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+// openEventStream registers a hook that writes every lifecycle event to
+// EventStreamPath as newline-delimited JSON, or to stdout for "-". The
+// returned function unregisters the hook and closes the file.
+func (o *Orchestrator) openEventStream() (func(), error) {
+	var w io.Writer = os.Stdout
+	if o.eventOut != nil {
+		w = o.eventOut
+	}
+	var file *os.File
+	if path := o.config.EventStreamPath; path != "-" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("create event stream directory: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) //#nosec G304 -- Path provided by user
+		if err != nil {
+			return nil, fmt.Errorf("open event stream: %w", err)
+		}
+		w, file = f, f
+	}
+
+	if o.hookRegistry == nil {
+		o.hookRegistry = hooks.NewRegistry()
+	}
+	stream := hooks.NewStreamHook(w)
+	if err := o.hookRegistry.Register(stream); err != nil {
+		if file != nil {
+			_ = file.Close()
+		}
+		return nil, fmt.Errorf("register event stream: %w", err)
+	}
+
+	return func() {
+		o.hookRegistry.Unregister(stream.Name())
+		if file != nil {
+			_ = file.Close()
+		}
+	}, nil
+}
+
+// emitStepEvent triggers the hook event for a step status change. A
+// completed step also reports the budget.
+func (o *Orchestrator) emitStepEvent(ctx context.Context, step *ActionStep) {
+	index := -1
+	for i := range o.actionPlan.Steps {
+		if o.actionPlan.Steps[i].ID == step.ID {
+			index = i
+			break
+		}
+	}
+
+	data := map[string]interface{}{
+		"step_id":    step.ID,
+		"step_index": index,
+		"step_name":  step.Description,
+		"step_type":  string(step.Type),
+	}
+
+	switch step.Status {
+	case StepStatusInProgress:
+		o.triggerHook(ctx, hooks.EventStepBefore, o.workflowID, data)
+	case StepStatusCompleted:
+		if o.router != nil {
+			data["total_cost"] = o.router.GetBudget().SpentUSD
+		}
+		o.triggerHook(ctx, hooks.EventStepAfter, o.workflowID, data)
+		o.emitBudgetUpdate(ctx, step.ID)
+	case StepStatusFailed:
+		data["error"] = step.Error
+		o.triggerHook(ctx, hooks.EventStepFailed, o.workflowID, data)
+	}
+}
+
+// emitBudgetUpdate triggers a budget update event with the router's spend
+func (o *Orchestrator) emitBudgetUpdate(ctx context.Context, stepID string) {
+	if o.router == nil {
+		return
+	}
+
+	budget := o.router.GetBudget()
+	o.triggerHook(ctx, hooks.EventBudgetUpdate, o.workflowID, map[string]interface{}{
+		"step_id":       stepID,
+		"spent_usd":     budget.SpentUSD,
+		"remaining_usd": budget.RemainingUSD,
+		"limit_usd":     budget.LimitUSD,
+		"unlimited":     budget.Unlimited,
+	})
+
+	o.emitBudgetThreshold(ctx, stepID, budget, 0)
+	if !budget.Unlimited && budget.LimitUSD > 0 && budget.SpentUSD >= budget.LimitUSD {
+		o.emitBudgetExhausted(ctx, stepID, budget, 0)
+	}
+}
+
+// checkBudget runs the pre-flight budget check for the operation run by a
+// step, printing any warning. A budget warning event is sent when the
+// estimated cost reaches a new threshold, and a budget exhausted event
+// when the remaining budget cannot cover it.
+func (o *Orchestrator) checkBudget(ctx context.Context, stepID string, estimatedCost float64, operation string) error {
+	budget := o.router.GetBudget()
+	warning, err := CheckBudgetWithWarning(budget, estimatedCost, operation)
+	if err != nil {
+		o.emitBudgetExhausted(ctx, stepID, budget, estimatedCost)
+		return fmt.Errorf("budget check failed: %w", err)
+	}
+	if warning != "" {
+		fmt.Printf("%s\n\n", warning)
+	}
+
+	o.emitBudgetThreshold(ctx, stepID, budget, estimatedCost)
+	return nil
+}
+
+// emitBudgetThreshold triggers a budget warning event when spending plus
+// the estimated cost of the next operation reaches a warning threshold
+// that has not been reported yet
+func (o *Orchestrator) emitBudgetThreshold(ctx context.Context, stepID string, budget *router.Budget, estimatedCost float64) {
+	if budget == nil || budget.Unlimited {
+		return
+	}
+
+	threshold := reachedThreshold(budget.SpentUSD+estimatedCost, budget.LimitUSD)
+	if threshold <= o.budgetWarnedPercent {
+		return
+	}
+	o.budgetWarnedPercent = threshold
+
+	data := budgetEventData(stepID, budget, estimatedCost)
+	data["threshold_percent"] = threshold
+	o.triggerHook(ctx, hooks.EventBudgetWarning, o.workflowID, data)
+}
+
+// emitBudgetExhausted triggers a budget exhausted event once per run
+func (o *Orchestrator) emitBudgetExhausted(ctx context.Context, stepID string, budget *router.Budget, estimatedCost float64) {
+	if budget == nil || o.budgetExhausted {
+		return
+	}
+	o.budgetExhausted = true
+
+	o.triggerHook(ctx, hooks.EventBudgetExhausted, o.workflowID, budgetEventData(stepID, budget, estimatedCost))
+}
+
+// budgetEventData returns the data shared by budget events
+func budgetEventData(stepID string, budget *router.Budget, estimatedCost float64) map[string]interface{} {
+	data := map[string]interface{}{
+		"step_id":       stepID,
+		"spent_usd":     budget.SpentUSD,
+		"remaining_usd": budget.RemainingUSD,
+		"limit_usd":     budget.LimitUSD,
+	}
+	if budget.LimitUSD > 0 {
+		data["used_percent"] = budget.SpentUSD / budget.LimitUSD * 100
+	}
+	if estimatedCost > 0 {
+		data["estimated_cost_usd"] = estimatedCost
+	}
+	return data
+}
+
+// Output streams reported in output chunk events
+const (
+	OutputStreamModel  = "model"
+	OutputStreamStdout = "stdout"
+	OutputStreamStderr = "stderr"
+)
+
+// emitOutput triggers an output chunk event with text produced by a step.
+// taskID is empty for output that does not belong to a plan task.
+func (o *Orchestrator) emitOutput(ctx context.Context, stepID, taskID, stream, text string) {
+	if text == "" {
+		return
+	}
+
+	data := map[string]interface{}{
+		"step_id": stepID,
+		"stream":  stream,
+		"text":    text,
+	}
+	if taskID != "" {
+		data["task_id"] = taskID
+	}
+	o.triggerHook(ctx, hooks.EventOutputChunk, o.workflowID, data)
+}
+
+// emitSpecOutput reports the generated spec as model output of step 1
+func (o *Orchestrator) emitSpecOutput(ctx context.Context, productSpec *spec.ProductSpec) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Spec for %s: %d feature(s)\n", productSpec.Product, len(productSpec.Features))
+	for _, feature := range productSpec.Features {
+		fmt.Fprintf(&b, "  %s [%s] %s\n", feature.ID, feature.Priority, feature.Title)
+	}
+	o.emitOutput(ctx, "step-1", "", OutputStreamModel, b.String())
+}
+
+// emitTaskOutput reports the stdout and stderr of executed tasks in plan
+// order as output of step 4
+func (o *Orchestrator) emitTaskOutput(ctx context.Context, p *plan.Plan, results map[string]*exec.Result) {
+	for _, task := range p.Tasks {
+		taskID := task.ID.String()
+		result, ok := results[taskID]
+		if !ok || result == nil {
+			continue
+		}
+		o.emitOutput(ctx, "step-4", taskID, OutputStreamStdout, result.Stdout)
+		o.emitOutput(ctx, "step-4", taskID, OutputStreamStderr, result.Stderr)
+	}
+}
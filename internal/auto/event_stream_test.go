@@ -0,0 +1,305 @@
+package auto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/exec"
+	"github.com/felixgeelhaar/specular/internal/hooks"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/router"
+)
+
+func TestEventStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events", "auto.jsonl")
+
+	config := DefaultConfig()
+	config.EventStreamPath = path
+	o := NewOrchestrator(nil, config)
+	o.workflowID = "auto-123"
+	o.actionPlan = CreateDefaultActionPlan("Test goal", "default")
+
+	closeStream, err := o.openEventStream()
+	if err != nil {
+		t.Fatalf("openEventStream() error = %v", err)
+	}
+
+	ctx := context.Background()
+	o.actionPlan.onStatusChange = func(step *ActionStep) {
+		o.emitStepEvent(ctx, step)
+	}
+
+	_ = o.actionPlan.UpdateStepStatus("step-1", StepStatusInProgress)
+	_ = o.actionPlan.UpdateStepStatus("step-1", StepStatusCompleted)
+
+	checker := &mockPolicyChecker{
+		checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+			return &PolicyResult{Allowed: false, Reason: "budget exhausted"}, nil
+		},
+	}
+	step2, _ := o.actionPlan.GetStep("step-2")
+	if _, _, err := o.checkPolicy(ctx, checker, step2, 1, 1, 0, time.Now(), nil); err != nil {
+		t.Fatalf("checkPolicy() error = %v", err)
+	}
+
+	step2.Error = "blocked"
+	_ = o.actionPlan.UpdateStepStatus("step-2", StepStatusFailed)
+	closeStream()
+
+	// Events after the stream is closed are not written
+	_ = o.actionPlan.UpdateStepStatus("step-3", StepStatusInProgress)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var events []hooks.Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event hooks.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	want := []hooks.EventType{
+		hooks.EventStepBefore,
+		hooks.EventStepAfter,
+		hooks.EventPolicyCheck,
+		hooks.EventPolicyViolation,
+		hooks.EventStepFailed,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, eventType := range want {
+		if events[i].Type != eventType {
+			t.Errorf("event %d type = %s, want %s", i, events[i].Type, eventType)
+		}
+		if events[i].WorkflowID != "auto-123" {
+			t.Errorf("event %d workflowId = %q, want auto-123", i, events[i].WorkflowID)
+		}
+	}
+
+	if got := events[0].GetString("step_id"); got != "step-1" {
+		t.Errorf("step_id = %q, want step-1", got)
+	}
+	if got := events[3].GetString("reason"); got != "budget exhausted" {
+		t.Errorf("violation reason = %q, want %q", got, "budget exhausted")
+	}
+	if got := events[4].GetString("error"); got != "blocked" {
+		t.Errorf("step failed error = %q, want blocked", got)
+	}
+}
+
+// TestExecuteWorkflowID tests that a run without JSON output still gets a
+// unique workflow ID, shared by its hook events and result
+func TestExecuteWorkflowID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto.jsonl")
+
+	config := DefaultConfig()
+	config.Goal = "Test goal"
+	config.JSONOutput = false
+	config.EventStreamPath = path
+	newOrchestrator := func() *Orchestrator {
+		o := NewOrchestrator(nil, config)
+		o.SetPolicyChecker(&mockPolicyChecker{
+			checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+				return &PolicyResult{Allowed: false, Reason: "stop before generating"}, nil
+			},
+		})
+		return o
+	}
+
+	result, err := newOrchestrator().Execute(context.Background())
+	if err == nil {
+		t.Fatal("Execute() should stop at the policy check")
+	}
+	if result.WorkflowID == "" || result.WorkflowID == "unknown" || !strings.HasPrefix(result.WorkflowID, "auto-") {
+		t.Fatalf("WorkflowID = %q, want a generated ID", result.WorkflowID)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var types []hooks.EventType
+	for scanner.Scan() {
+		var event hooks.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		if event.WorkflowID != result.WorkflowID {
+			t.Errorf("%s event workflowId = %q, want %q", event.Type, event.WorkflowID, result.WorkflowID)
+		}
+		types = append(types, event.Type)
+	}
+	if len(types) == 0 || types[0] != hooks.EventWorkflowStart || types[len(types)-1] != hooks.EventWorkflowFailed {
+		t.Errorf("events = %v, want workflow start through workflow failed", types)
+	}
+
+	// A second run gets a different ID
+	second, _ := newOrchestrator().Execute(context.Background())
+	if second.WorkflowID == result.WorkflowID {
+		t.Errorf("two runs share workflow ID %q", result.WorkflowID)
+	}
+}
+
+func TestEmitTaskOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto.jsonl")
+
+	config := DefaultConfig()
+	config.EventStreamPath = path
+	o := NewOrchestrator(nil, config)
+	o.workflowID = "auto-123"
+
+	closeStream, err := o.openEventStream()
+	if err != nil {
+		t.Fatalf("openEventStream() error = %v", err)
+	}
+
+	execPlan := &plan.Plan{Tasks: []plan.Task{{ID: "task-2"}, {ID: "task-1"}, {ID: "task-3"}}}
+	results := map[string]*exec.Result{
+		"task-1": {Stdout: "ok\n"},
+		"task-2": {Stdout: "building\n", Stderr: "warning: unused\n"},
+	}
+	o.emitTaskOutput(context.Background(), execPlan, results)
+	closeStream()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event hooks.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		if event.Type != hooks.EventOutputChunk {
+			t.Errorf("event type = %s, want %s", event.Type, hooks.EventOutputChunk)
+		}
+		got = append(got, event.GetString("task_id")+" "+event.GetString("stream")+" "+event.GetString("text"))
+	}
+
+	want := []string{
+		"task-2 stdout building\n",
+		"task-2 stderr warning: unused\n",
+		"task-1 stdout ok\n",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %q", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEventStreamWriter(t *testing.T) {
+	config := DefaultConfig()
+	config.EventStreamPath = "-"
+	o := NewOrchestrator(nil, config)
+	o.workflowID = "auto-123"
+
+	var out bytes.Buffer
+	o.SetEventStreamWriter(&out)
+	closeStream, err := o.openEventStream()
+	if err != nil {
+		t.Fatalf("openEventStream() error = %v", err)
+	}
+	o.emitOutput(context.Background(), "step-1", "", OutputStreamModel, "Spec for app\n")
+	closeStream()
+
+	var event hooks.Event
+	if err := json.Unmarshal(out.Bytes(), &event); err != nil {
+		t.Fatalf("output %q is not one event: %v", out.String(), err)
+	}
+	if event.Type != hooks.EventOutputChunk {
+		t.Errorf("event type = %s, want %s", event.Type, hooks.EventOutputChunk)
+	}
+}
+
+func TestBudgetEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto.jsonl")
+
+	config := DefaultConfig()
+	config.EventStreamPath = path
+	o := NewOrchestrator(nil, config)
+	o.workflowID = "auto-123"
+
+	closeStream, err := o.openEventStream()
+	if err != nil {
+		t.Fatalf("openEventStream() error = %v", err)
+	}
+
+	ctx := context.Background()
+	spend := func(spent float64) *router.Budget {
+		return &router.Budget{LimitUSD: 10, SpentUSD: spent, RemainingUSD: 10 - spent}
+	}
+
+	o.emitBudgetThreshold(ctx, "step-1", spend(1), 0)                                       // 10%, no threshold
+	o.emitBudgetThreshold(ctx, "step-1", spend(3), 2.5)                                     // 55% with the estimate
+	o.emitBudgetThreshold(ctx, "step-2", spend(5.5), 0)                                     // 50% already sent
+	o.emitBudgetThreshold(ctx, "step-3", spend(9.5), 0)                                     // Skips 75% to 90%
+	o.emitBudgetThreshold(ctx, "step-3", &router.Budget{SpentUSD: 100, Unlimited: true}, 0) // Unlimited budgets never warn
+	o.emitBudgetExhausted(ctx, "step-4", spend(10), 0)
+	o.emitBudgetExhausted(ctx, "step-4", spend(10), 0)
+	closeStream()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []hooks.Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event hooks.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	want := []struct {
+		eventType hooks.EventType
+		stepID    string
+		threshold float64
+	}{
+		{hooks.EventBudgetWarning, "step-1", 50},
+		{hooks.EventBudgetWarning, "step-3", 90},
+		{hooks.EventBudgetExhausted, "step-4", 0},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		event := events[i]
+		if event.Type != w.eventType || event.GetString("step_id") != w.stepID || event.GetFloat("threshold_percent") != w.threshold {
+			t.Errorf("event %d = %s at %s (threshold %v), want %s at %s (threshold %v)",
+				i, event.Type, event.GetString("step_id"), event.GetFloat("threshold_percent"), w.eventType, w.stepID, w.threshold)
+		}
+	}
+
+	if got := events[0].GetFloat("estimated_cost_usd"); got != 2.5 {
+		t.Errorf("estimated_cost_usd = %v, want 2.5", got)
+	}
+	if got := events[2].GetFloat("remaining_usd"); got != 0 {
+		t.Errorf("remaining_usd = %v, want 0", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/checkpoint"
@@ -14,6 +15,7 @@ import (
 	"github.com/felixgeelhaar/specular/internal/progress"
 	"github.com/felixgeelhaar/specular/internal/router"
 	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/internal/trace"
 )
 
 // TaskExecutor handles execution of tasks from a plan
@@ -24,6 +26,8 @@ type TaskExecutor struct {
 	actionPlan   *ActionPlan
 	router       interface{ GetBudget() *router.Budget } // Use interface for testability
 	progressFunc func(taskID, status string, err error)
+	taskGate     func(task plan.Task) (bool, error)
+	sessionID    string
 }
 
 // NewTaskExecutor creates a new task executor
@@ -42,6 +46,18 @@ func (te *TaskExecutor) SetProgressCallback(fn func(taskID, status string, err e
 	te.progressFunc = fn
 }
 
+// SetTaskGate sets a function consulted before each task runs, used for
+// per-step approval. A gate error stops execution without retrying.
+func (te *TaskExecutor) SetTaskGate(gate func(task plan.Task) (bool, error)) {
+	te.taskGate = gate
+}
+
+// SetSessionID sets the ID the checkpoint is saved under. Without one, a
+// new ID is generated.
+func (te *TaskExecutor) SetSessionID(id string) {
+	te.sessionID = id
+}
+
 // Execute runs all tasks in the plan with progress tracking and error handling
 func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionStats, error) {
 	stats := &ExecutionStats{
@@ -58,15 +74,23 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 	// Setup progress indicator
 	progressIndicator := progress.NewIndicator(progress.Config{
 		Writer:      os.Stdout,
-		ShowSpinner: !te.config.Verbose, // Hide spinner in verbose mode
+		ShowSpinner: !te.config.Verbose && te.taskGate == nil, // Hide spinner in verbose mode and while prompting
 	})
 
 	// Setup checkpoint for resume capability
-	checkpointMgr := checkpoint.NewManager(".specular/checkpoints", true, 30*time.Second)
-	cpState := checkpoint.NewState(fmt.Sprintf("auto-%d", time.Now().Unix()))
+	checkpointMgr := checkpoint.NewManager(te.config.checkpointStore(), true, 30*time.Second).WithCompression(checkpoint.DefaultCompressThreshold)
+	sessionID := te.sessionID
+	if sessionID == "" {
+		sessionID = trace.NewWorkflowID()
+	}
+	cpState := checkpoint.NewState(sessionID)
 	cpState.SetMetadata("goal", te.config.Goal)
 	cpState.SetMetadata("product", te.spec.Product)
 
+	// Save the profile and budget so retry-failed runs with the same limits
+	cpState.SetMetadata("profile", te.config.Profile)
+	cpState.SetMetadata("max_cost_usd", strconv.FormatFloat(te.config.MaxCostUSD, 'f', -1, 64))
+
 	// Save spec, plan, and action plan JSON for resume capability
 	if specJSON, err := json.Marshal(te.spec); err == nil {
 		cpState.SetMetadata("spec_json", string(specJSON))
@@ -106,6 +130,9 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 		ManifestDir: ".specular/manifests",
 		ImageCache:  imageCache,
 		Verbose:     te.config.Verbose,
+		MaxParallel: te.config.MaxParallelTasks,
+		FailFast:    te.config.FailFast,
+		Gate:        te.taskGate,
 	}
 
 	// Start progress indicator
@@ -137,6 +164,11 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 			break
 		}
 
+		// An aborted approval prompt is not retried
+		if execErr != nil && te.taskGate != nil {
+			break
+		}
+
 		// Check if we should retry
 		if attempt < te.config.MaxRetries {
 			if te.config.Verbose {
@@ -160,6 +192,7 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 	// Handle execution error
 	if execErr != nil {
 		cpState.Status = "failed"
+		te.recordSpend(cpState, 0)
 		checkpointMgr.Save(cpState) //#nosec G104 -- Best effort checkpoint save
 		return stats, fmt.Errorf("execution failed: %w", execErr)
 	}
@@ -178,8 +211,13 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 	stats.Duration = stats.EndTime.Sub(stats.StartTime)
 	stats.TaskResults = execResult.TaskResults
 
-	// Update checkpoint with results
-	for taskID, taskResult := range execResult.TaskResults {
+	// Update checkpoint with results in plan order
+	for _, task := range p.Tasks {
+		taskID := task.ID.String()
+		taskResult, ok := execResult.TaskResults[taskID]
+		if !ok {
+			continue
+		}
 		if taskResult.ExitCode == 0 {
 			cpState.UpdateTask(taskID, "completed", nil)
 			if te.progressFunc != nil {
@@ -203,6 +241,7 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 	}
 
 	// Save final checkpoint
+	te.recordSpend(cpState, 0)
 	if err := checkpointMgr.Save(cpState); err != nil && te.config.Verbose {
 		fmt.Printf("Warning: failed to save final checkpoint: %v\n", err)
 	}
@@ -230,6 +269,31 @@ func (te *TaskExecutor) Execute(ctx context.Context, p *plan.Plan) (*ExecutionSt
 	return stats, nil
 }
 
+// recordSpend saves the session's spend in the checkpoint: the spend carried
+// over from earlier runs of the session plus this run's, so a retry
+// continues with the remaining budget
+func (te *TaskExecutor) recordSpend(cpState *checkpoint.State, carried float64) {
+	if te.router == nil {
+		return
+	}
+	spent := carried + te.router.GetBudget().SpentUSD
+	cpState.SetMetadata("spent_usd", strconv.FormatFloat(spent, 'f', -1, 64))
+}
+
+// CheckpointSpentUSD returns the spend recorded in a session's checkpoint,
+// or 0 when none was recorded
+func CheckpointSpentUSD(cpState *checkpoint.State) float64 {
+	value, ok := cpState.GetMetadata("spent_usd")
+	if !ok {
+		return 0
+	}
+	spent, err := strconv.ParseFloat(value, 64)
+	if err != nil || spent < 0 {
+		return 0
+	}
+	return spent
+}
+
 // ExecutionStats contains statistics about task execution
 type ExecutionStats struct {
 	TotalTasks  int
@@ -260,7 +324,7 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 	// Setup progress indicator
 	progressIndicator := progress.NewIndicator(progress.Config{
 		Writer:      os.Stdout,
-		ShowSpinner: !te.config.Verbose,
+		ShowSpinner: !te.config.Verbose && te.taskGate == nil,
 	})
 
 	// Set state in progress indicator
@@ -273,6 +337,9 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 		ManifestDir: ".specular/manifests",
 		ImageCache:  nil,
 		Verbose:     te.config.Verbose,
+		MaxParallel: te.config.MaxParallelTasks,
+		FailFast:    te.config.FailFast,
+		Gate:        te.taskGate,
 	}
 
 	// Start progress indicator
@@ -287,6 +354,7 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 		initialBudget := te.router.GetBudget()
 		initialSpent = initialBudget.SpentUSD
 	}
+	carriedSpent := CheckpointSpentUSD(cpState)
 
 	// Execute plan with retry logic
 	var execResult *exec.ExecutionResult
@@ -304,6 +372,11 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 			break
 		}
 
+		// An aborted approval prompt is not retried
+		if execErr != nil && te.taskGate != nil {
+			break
+		}
+
 		// Check if we should retry
 		if attempt < te.config.MaxRetries {
 			if te.config.Verbose {
@@ -327,6 +400,7 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 	// Handle execution error
 	if execErr != nil {
 		cpState.Status = "failed"
+		te.recordSpend(cpState, carriedSpent)
 		checkpointMgr.Save(cpState) //#nosec G104 -- Best effort checkpoint save
 		return stats, fmt.Errorf("execution failed: %w", execErr)
 	}
@@ -345,8 +419,13 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 	stats.Duration = stats.EndTime.Sub(stats.StartTime)
 	stats.TaskResults = execResult.TaskResults
 
-	// Update checkpoint with results
-	for taskID, taskResult := range execResult.TaskResults {
+	// Update checkpoint with results in plan order
+	for _, task := range p.Tasks {
+		taskID := task.ID.String()
+		taskResult, ok := execResult.TaskResults[taskID]
+		if !ok {
+			continue
+		}
 		if taskResult.ExitCode == 0 {
 			cpState.UpdateTask(taskID, "completed", nil)
 			if te.progressFunc != nil {
@@ -370,6 +449,7 @@ func (te *TaskExecutor) ExecuteWithCheckpoint(ctx context.Context, p *plan.Plan,
 	}
 
 	// Save final checkpoint
+	te.recordSpend(cpState, carriedSpent)
 	if err := checkpointMgr.Save(cpState); err != nil && te.config.Verbose {
 		fmt.Printf("Warning: failed to save final checkpoint: %v\n", err)
 	}
@@ -0,0 +1,158 @@
+package auto
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExitReportSchema identifies the exit report format
+const ExitReportSchema = "specular.auto.exit-report/v1"
+
+// ExitReport is the final summary of an auto run, written for CI pipelines
+// that need a stable artifact instead of parsing stdout. It is produced for
+// successful and failed runs alike.
+type ExitReport struct {
+	// Schema version for report format compatibility
+	Schema string `json:"schema"`
+
+	// Goal is the user's original objective
+	Goal string `json:"goal,omitempty"`
+
+	// Status is the run outcome: completed, failed, or partial
+	Status string `json:"status"`
+
+	// ExitCode is the process exit code of the run
+	ExitCode int `json:"exitCode"`
+
+	// Error contains the error that ended the run, if any
+	Error string `json:"error,omitempty"`
+
+	// TotalCost is the total cost of the run in USD
+	TotalCost float64 `json:"totalCost"`
+
+	// Duration is the run's wall-clock time
+	Duration time.Duration `json:"duration"`
+
+	// TasksExecuted is the number of plan tasks that ran
+	TasksExecuted int `json:"tasksExecuted"`
+
+	// TasksFailed is the number of plan tasks that failed
+	TasksFailed int `json:"tasksFailed"`
+
+	// PolicyBlocks lists policy checks that blocked a step
+	PolicyBlocks []PolicyEvent `json:"policyBlocks"`
+
+	// Artifacts lists paths of files produced by the run
+	Artifacts []string `json:"artifacts"`
+
+	// WorkflowID identifies the run in hooks, traces, and attestations
+	WorkflowID string `json:"workflowId,omitempty"`
+
+	// CheckpointID identifies the run's checkpoint, when known
+	CheckpointID string `json:"checkpointId,omitempty"`
+
+	// CompletedAt records when the report was produced
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// NewExitReport builds an exit report from a run's result and error. The
+// result may be nil when the run failed before the workflow started.
+func NewExitReport(goal string, result *Result, runErr error, exitCode int) *ExitReport {
+	report := &ExitReport{
+		Schema:       ExitReportSchema,
+		Goal:         goal,
+		Status:       "completed",
+		ExitCode:     exitCode,
+		PolicyBlocks: []PolicyEvent{},
+		Artifacts:    []string{},
+		CompletedAt:  time.Now().UTC(),
+	}
+
+	if runErr != nil {
+		report.Status = "failed"
+		report.Error = runErr.Error()
+	}
+
+	if result == nil {
+		return report
+	}
+
+	if runErr == nil && !result.Success {
+		report.Status = "failed"
+	}
+	report.TotalCost = result.TotalCost
+	report.Duration = result.Duration
+	report.TasksExecuted = result.TasksExecuted
+	report.TasksFailed = result.TasksFailed
+	report.WorkflowID = result.WorkflowID
+
+	if output := result.AutoOutput; output != nil {
+		// Policy blocks leave the workflow partially complete
+		if output.Status == "partial" && runErr != nil {
+			report.Status = "partial"
+		}
+		report.CheckpointID = output.Audit.CheckpointID
+		for _, event := range output.Audit.Policies {
+			if !event.Allowed {
+				report.PolicyBlocks = append(report.PolicyBlocks, event)
+			}
+		}
+		for _, artifact := range output.Artifacts {
+			report.AddArtifact(artifact.Path)
+		}
+	}
+
+	return report
+}
+
+// AddArtifact records an artifact path, ignoring empty and duplicate paths
+func (r *ExitReport) AddArtifact(path string) {
+	if path == "" {
+		return
+	}
+	for _, existing := range r.Artifacts {
+		if existing == path {
+			return
+		}
+	}
+	r.Artifacts = append(r.Artifacts, path)
+}
+
+// Write saves the report as JSON to path, creating parent directories. The
+// file is replaced atomically so CI never reads a partial report.
+func (r *ExitReport) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal exit report: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create exit report directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".exit-report-*.json")
+	if err != nil {
+		return fmt.Errorf("create exit report: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()        //#nosec G104 -- Write error takes precedence
+		_ = os.Remove(tmpPath) //#nosec G104 -- Best-effort cleanup
+		return fmt.Errorf("write exit report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath) //#nosec G104 -- Best-effort cleanup
+		return fmt.Errorf("write exit report: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) //#nosec G104 -- Best-effort cleanup
+		return fmt.Errorf("write exit report: %w", err)
+	}
+
+	return nil
+}
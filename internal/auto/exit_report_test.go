@@ -0,0 +1,137 @@
+package auto
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readExitReport(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var report map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("unmarshal report: %v", err)
+	}
+	return report
+}
+
+func TestExitReport_Success(t *testing.T) {
+	output := NewAutoOutput("add health checks", "ci")
+	output.SetCheckpointID("auto-1762811730")
+	output.AddPolicy(PolicyEvent{StepID: "step-1", CheckerName: "profile", Allowed: true})
+	output.AddArtifact(ArtifactInfo{Path: "internal/health/health.go", Type: "code"})
+	output.SetCompleted()
+
+	result := &Result{
+		Success:       true,
+		WorkflowID:    "auto-1762811730",
+		AutoOutput:    output,
+		TotalCost:     0.42,
+		Duration:      3 * time.Second,
+		TasksExecuted: 4,
+	}
+
+	report := NewExitReport("add health checks", result, nil, 0)
+	report.AddArtifact("out/spec.yaml")
+	report.AddArtifact("out/spec.yaml")
+
+	path := filepath.Join(t.TempDir(), "reports", "auto.json")
+	if err := report.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got := readExitReport(t, path)
+	want := map[string]interface{}{
+		"schema":        ExitReportSchema,
+		"goal":          "add health checks",
+		"status":        "completed",
+		"exitCode":      float64(0),
+		"totalCost":     0.42,
+		"tasksExecuted": float64(4),
+		"tasksFailed":   float64(0),
+		"workflowId":    "auto-1762811730",
+		"checkpointId":  output.Audit.CheckpointID,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+	if _, ok := got["error"]; ok {
+		t.Errorf("successful report should not have an error, got %v", got["error"])
+	}
+	if blocks := got["policyBlocks"].([]interface{}); len(blocks) != 0 {
+		t.Errorf("policyBlocks = %v, want none", blocks)
+	}
+
+	artifacts := got["artifacts"].([]interface{})
+	if len(artifacts) != 2 || artifacts[0] != "internal/health/health.go" || artifacts[1] != "out/spec.yaml" {
+		t.Errorf("artifacts = %v", artifacts)
+	}
+}
+
+func TestExitReport_PolicyBlocked(t *testing.T) {
+	output := NewAutoOutput("deploy to prod", "strict")
+	output.AddPolicy(PolicyEvent{StepID: "step-1", CheckerName: "profile", Allowed: true})
+	output.AddPolicy(PolicyEvent{StepID: "step-2", CheckerName: "profile", Allowed: false, Reason: "step type blocked"})
+	output.SetPartial()
+
+	result := &Result{AutoOutput: output, TotalCost: 0.05, Duration: time.Second}
+	runErr := errors.New("auto mode failed: step-2 blocked by policy: step type blocked")
+
+	path := filepath.Join(t.TempDir(), "auto.json")
+	if err := NewExitReport("deploy to prod", result, runErr, 3).Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got := readExitReport(t, path)
+	if got["status"] != "partial" {
+		t.Errorf("status = %v, want partial", got["status"])
+	}
+	if got["exitCode"] != float64(3) {
+		t.Errorf("exitCode = %v, want 3", got["exitCode"])
+	}
+	if got["error"] != runErr.Error() {
+		t.Errorf("error = %v, want %q", got["error"], runErr)
+	}
+
+	blocks := got["policyBlocks"].([]interface{})
+	if len(blocks) != 1 {
+		t.Fatalf("policyBlocks = %v, want 1 block", blocks)
+	}
+	block := blocks[0].(map[string]interface{})
+	if block["stepId"] != "step-2" || block["reason"] != "step type blocked" {
+		t.Errorf("policy block = %v", block)
+	}
+}
+
+func TestExitReport_FailedBeforeWorkflow(t *testing.T) {
+	report := NewExitReport("", nil, errors.New("failed to load profile"), 1)
+
+	if report.Status != "failed" || report.ExitCode != 1 || report.Error != "failed to load profile" {
+		t.Errorf("report = %+v", report)
+	}
+	if report.PolicyBlocks == nil || report.Artifacts == nil {
+		t.Error("empty lists should serialize as [] rather than null")
+	}
+}
+
+func TestExitReport_FailedTasks(t *testing.T) {
+	result := &Result{Success: false, TasksExecuted: 5, TasksFailed: 2}
+
+	report := NewExitReport("goal", result, nil, 0)
+	if report.Status != "failed" {
+		t.Errorf("status = %q, want failed", report.Status)
+	}
+	if report.TasksFailed != 2 {
+		t.Errorf("tasksFailed = %d, want 2", report.TasksFailed)
+	}
+}
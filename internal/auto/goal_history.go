@@ -0,0 +1,252 @@
+package auto
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultGoalHistoryLimit is the number of entries kept in the goal history file
+const DefaultGoalHistoryLimit = 100
+
+// GoalHistoryEntry is a single goal recorded in the history file
+type GoalHistoryEntry struct {
+	Goal      string    `json:"goal"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GoalHistory stores previously run goals as newline-delimited JSON
+type GoalHistory struct {
+	path  string
+	limit int
+}
+
+// NewGoalHistory creates a goal history backed by the given file
+func NewGoalHistory(path string) *GoalHistory {
+	return &GoalHistory{
+		path:  path,
+		limit: DefaultGoalHistoryLimit,
+	}
+}
+
+// DefaultGoalHistoryPath returns ~/.specular/goal-history
+func DefaultGoalHistoryPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".specular", "goal-history"), nil
+}
+
+// Record appends a goal to the history. Re-running a goal moves it to the
+// most recent position instead of adding a duplicate.
+func (h *GoalHistory) Record(goal string) error {
+	goal = strings.TrimSpace(goal)
+	if goal == "" {
+		return nil
+	}
+
+	entries, err := h.load()
+	if err != nil {
+		return err
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Goal != goal {
+			kept = append(kept, entry)
+		}
+	}
+	kept = append(kept, GoalHistoryEntry{Goal: goal, Timestamp: time.Now()})
+
+	if len(kept) > h.limit {
+		kept = kept[len(kept)-h.limit:]
+	}
+
+	return h.save(kept)
+}
+
+// Recent returns up to n goals, most recent first. n <= 0 returns all goals.
+func (h *GoalHistory) Recent(n int) ([]GoalHistoryEntry, error) {
+	entries, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+
+	recent := make([]GoalHistoryEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		recent = append(recent, entries[i])
+		if n > 0 && len(recent) == n {
+			break
+		}
+	}
+
+	return recent, nil
+}
+
+// load reads all history entries in chronological order
+func (h *GoalHistory) load() ([]GoalHistoryEntry, error) {
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open goal history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []GoalHistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry GoalHistoryEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			// Skip corrupt lines rather than losing the whole history
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read goal history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// save rewrites the history file with the given entries
+func (h *GoalHistory) save(entries []GoalHistoryEntry) error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0750); err != nil {
+		return fmt.Errorf("failed to create goal history directory: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode goal history entry: %w", err)
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(h.path, []byte(sb.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write goal history: %w", err)
+	}
+
+	return nil
+}
+
+// GoalTemplate is a named, parameterized goal. Variables are written as
+// {{name}} in the goal text.
+type GoalTemplate struct {
+	Description string `yaml:"description,omitempty"`
+	Goal        string `yaml:"goal"`
+}
+
+// goalTemplatesFile is the on-disk format of goal template files
+type goalTemplatesFile struct {
+	Templates map[string]GoalTemplate `yaml:"templates"`
+}
+
+// templateVarPattern matches {{var}} placeholders, allowing inner whitespace
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// DefaultGoalTemplatePaths returns the template files in load order.
+// Project templates override user templates with the same name.
+func DefaultGoalTemplatePaths() []string {
+	var paths []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".specular", "goal-templates.yaml"))
+	}
+	paths = append(paths, filepath.Join(".specular", "goal-templates.yaml"))
+	return paths
+}
+
+// LoadGoalTemplates loads and merges templates from the given files.
+// Missing files are skipped; later files override earlier ones.
+func LoadGoalTemplates(paths ...string) (map[string]GoalTemplate, error) {
+	templates := make(map[string]GoalTemplate)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read goal templates %s: %w", path, err)
+		}
+
+		var file goalTemplatesFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse goal templates %s: %w", path, err)
+		}
+
+		for name, tmpl := range file.Templates {
+			templates[name] = tmpl
+		}
+	}
+
+	return templates, nil
+}
+
+// Variables returns the distinct variable names used by the template, sorted
+func (t GoalTemplate) Variables() []string {
+	seen := make(map[string]bool)
+	var vars []string
+	for _, match := range templateVarPattern.FindAllStringSubmatch(t.Goal, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			vars = append(vars, match[1])
+		}
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// Expand substitutes vars into the template goal. Every placeholder must
+// have a value.
+func (t GoalTemplate) Expand(vars map[string]string) (string, error) {
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+
+	goal := templateVarPattern.ReplaceAllStringFunc(t.Goal, func(placeholder string) string {
+		name := templateVarPattern.FindStringSubmatch(placeholder)[1]
+		return vars[name]
+	})
+
+	return strings.TrimSpace(goal), nil
+}
+
+// ParseTemplateVars parses key=value pairs as passed to --var
+func ParseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid template variable %q: expected key=value", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,143 @@
+package auto
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoalHistory_RecordAndRecent(t *testing.T) {
+	history := NewGoalHistory(filepath.Join(t.TempDir(), "goal-history"))
+
+	// Empty history is not an error
+	recent, err := history.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent() on missing file error = %v", err)
+	}
+	if len(recent) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(recent))
+	}
+
+	for _, goal := range []string{"Build a REST API", "Add auth", "Add caching"} {
+		if err := history.Record(goal); err != nil {
+			t.Fatalf("Record(%q) error = %v", goal, err)
+		}
+	}
+
+	recent, err = history.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(recent))
+	}
+	if recent[0].Goal != "Add caching" || recent[1].Goal != "Add auth" {
+		t.Errorf("unexpected order: %q, %q", recent[0].Goal, recent[1].Goal)
+	}
+	if recent[0].Timestamp.IsZero() {
+		t.Error("expected timestamp to be recorded")
+	}
+}
+
+func TestGoalHistory_DeduplicatesAndLimits(t *testing.T) {
+	history := NewGoalHistory(filepath.Join(t.TempDir(), "nested", "goal-history"))
+	history.limit = 3
+
+	for _, goal := range []string{"a", "b", "c", "a", "d", "  "} {
+		if err := history.Record(goal); err != nil {
+			t.Fatalf("Record(%q) error = %v", goal, err)
+		}
+	}
+
+	recent, err := history.Recent(0)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+
+	var goals []string
+	for _, entry := range recent {
+		goals = append(goals, entry.Goal)
+	}
+	if got := strings.Join(goals, ","); got != "d,a,c" {
+		t.Errorf("history = %s, want d,a,c", got)
+	}
+}
+
+func TestLoadGoalTemplates_ProjectOverridesUser(t *testing.T) {
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.yaml")
+	projectPath := filepath.Join(dir, "project.yaml")
+
+	userYAML := `templates:
+  add-endpoint:
+    goal: Add endpoint for {{resource}}
+  add-tests:
+    goal: Add tests for {{package}}
+`
+	projectYAML := `templates:
+  add-endpoint:
+    description: Project flavored endpoint
+    goal: Add a REST endpoint for {{ resource }} with {{auth}} auth
+`
+	if err := os.WriteFile(userPath, []byte(userYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectPath, []byte(projectYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := LoadGoalTemplates(userPath, filepath.Join(dir, "missing.yaml"), projectPath)
+	if err != nil {
+		t.Fatalf("LoadGoalTemplates() error = %v", err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(templates))
+	}
+	if templates["add-endpoint"].Description != "Project flavored endpoint" {
+		t.Errorf("project template should override user template")
+	}
+}
+
+func TestGoalTemplate_Expand(t *testing.T) {
+	tmpl := GoalTemplate{Goal: "Add a REST endpoint for {{ resource }} backed by {{store}}, validating {{resource}} input"}
+
+	if got := strings.Join(tmpl.Variables(), ","); got != "resource,store" {
+		t.Errorf("Variables() = %s, want resource,store", got)
+	}
+
+	vars, err := ParseTemplateVars([]string{"resource=orders", "store=postgres"})
+	if err != nil {
+		t.Fatalf("ParseTemplateVars() error = %v", err)
+	}
+
+	goal, err := tmpl.Expand(vars)
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	want := "Add a REST endpoint for orders backed by postgres, validating orders input"
+	if goal != want {
+		t.Errorf("Expand() = %q, want %q", goal, want)
+	}
+}
+
+func TestGoalTemplate_ExpandMissingVars(t *testing.T) {
+	tmpl := GoalTemplate{Goal: "Add {{resource}} to {{service}}"}
+
+	_, err := tmpl.Expand(map[string]string{"resource": "orders"})
+	if err == nil {
+		t.Fatal("expected error for missing variable")
+	}
+	if !strings.Contains(err.Error(), "service") {
+		t.Errorf("error should name the missing variable, got: %v", err)
+	}
+}
+
+func TestParseTemplateVars_Invalid(t *testing.T) {
+	for _, pair := range []string{"resource", "=orders"} {
+		if _, err := ParseTemplateVars([]string{pair}); err == nil {
+			t.Errorf("ParseTemplateVars(%q) expected error", pair)
+		}
+	}
+}
@@ -64,6 +64,8 @@ features:
     trace:
       - <implementation-detail-1>
       - <implementation-detail-2>
+    tags:
+      - <tag>  # e.g. backend, frontend, critical
 
 IMPORTANT RULES:
 1. Product name should be short and descriptive (e.g., "Todo API", "Weather Service")
@@ -74,18 +76,21 @@ IMPORTANT RULES:
 6. Trace items describe implementation details or technical requirements
 7. Break down the goal into 2-5 logical features
 8. Order features by priority (P0 first, then P1, then P2)
+9. Tags are short lowercase labels for the area or importance of a feature (0-3 tags)
 
 Return ONLY the YAML, no explanations or markdown code blocks.`
 
+	temperature := 0.0 // Deterministic, so the response can be cached
 	req := router.GenerateRequest{
 		Prompt:       goal,
 		SystemPrompt: systemPrompt,
 		ModelHint:    "agentic",
 		Complexity:   7,
 		Priority:     "P0",
-		Temperature:  0.3, // Lower temperature for structured output
+		Temperature:  &temperature,
 		MaxTokens:    2000,
 		TaskID:       types.TaskID("goal-parse"),
+		StepType:     string(StepTypeSpecUpdate),
 	}
 
 	resp, err := p.router.Generate(ctx, req)
@@ -133,6 +138,11 @@ Return ONLY the YAML, no explanations or markdown code blocks.`
 		}
 	}
 
+	// Check the spec against the spec schema before it is locked
+	if err := spec.DefaultSchema().ValidateSpec(&productSpec); err != nil {
+		return nil, fmt.Errorf("generated spec does not match the spec schema: %w\n\nRaw content:\n%s", err, yamlContent)
+	}
+
 	return &productSpec, nil
 }
 
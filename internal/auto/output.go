@@ -14,7 +14,7 @@ type AutoOutput struct {
 	// Goal describes the user's original objective
 	Goal string `json:"goal"`
 
-	// Status indicates the overall execution outcome: completed, failed, partial
+	// Status indicates the overall execution outcome: completed, failed, partial, paused
 	Status string `json:"status"`
 
 	// Steps contains results for each executed step
@@ -28,6 +28,9 @@ type AutoOutput struct {
 
 	// Audit provides provenance and compliance information
 	Audit AuditTrail `json:"audit"`
+
+	// Validation lists issues spec validators found in the generated spec
+	Validation []ValidationIssue `json:"validation,omitempty"`
 }
 
 // StepResult captures the execution result of a single step.
@@ -110,6 +113,9 @@ type ExecutionMetrics struct {
 
 // AuditTrail provides provenance and compliance information.
 type AuditTrail struct {
+	// WorkflowID identifies the run in hooks, traces, and attestations
+	WorkflowID string `json:"workflowId"`
+
 	// CheckpointID identifies the execution checkpoint
 	CheckpointID string `json:"checkpointId"`
 
@@ -136,6 +142,9 @@ type AuditTrail struct {
 
 	// Version tracks the Specular version used
 	Version string `json:"version,omitempty"`
+
+	// Seed is the sampling seed of a reproducible run, 0 when unseeded
+	Seed int64 `json:"seed,omitempty"`
 }
 
 // ApprovalEvent records a user approval interaction.
@@ -250,6 +259,11 @@ func (o *AutoOutput) AddPolicy(event PolicyEvent) {
 	}
 }
 
+// AddValidationIssues adds spec validation issues to the output.
+func (o *AutoOutput) AddValidationIssues(issues []ValidationIssue) {
+	o.Validation = append(o.Validation, issues...)
+}
+
 // SetCompleted marks the execution as completed successfully.
 func (o *AutoOutput) SetCompleted() {
 	o.Status = "completed"
@@ -264,6 +278,13 @@ func (o *AutoOutput) SetFailed() {
 	o.Metrics.TotalDuration = o.Audit.CompletedAt.Sub(o.Audit.StartedAt)
 }
 
+// SetPaused marks the execution as paused by the budget, to be resumed.
+func (o *AutoOutput) SetPaused() {
+	o.Status = "paused"
+	o.Audit.CompletedAt = time.Now()
+	o.Metrics.TotalDuration = o.Audit.CompletedAt.Sub(o.Audit.StartedAt)
+}
+
 // SetPartial marks the execution as partially completed.
 func (o *AutoOutput) SetPartial() {
 	o.Status = "partial"
@@ -271,6 +292,11 @@ func (o *AutoOutput) SetPartial() {
 	o.Metrics.TotalDuration = o.Audit.CompletedAt.Sub(o.Audit.StartedAt)
 }
 
+// SetWorkflowID sets the workflow identifier.
+func (o *AutoOutput) SetWorkflowID(id string) {
+	o.Audit.WorkflowID = id
+}
+
 // SetCheckpointID sets the checkpoint identifier.
 func (o *AutoOutput) SetCheckpointID(id string) {
 	o.Audit.CheckpointID = id
@@ -290,3 +316,8 @@ func (o *AutoOutput) SetHostname(hostname string) {
 func (o *AutoOutput) SetVersion(version string) {
 	o.Audit.Version = version
 }
+
+// SetSeed records the sampling seed of a reproducible run.
+func (o *AutoOutput) SetSeed(seed int64) {
+	o.Audit.Seed = seed
+}
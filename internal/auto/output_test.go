@@ -291,6 +291,23 @@ func TestSetVersion(t *testing.T) {
 	}
 }
 
+func TestSetSeed(t *testing.T) {
+	output := NewAutoOutput("test goal", "default")
+	output.SetSeed(42)
+
+	data, err := output.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	if decoded.Audit.Seed != 42 {
+		t.Errorf("expected seed 42 in the audit trail, got %d", decoded.Audit.Seed)
+	}
+}
+
 func TestToJSON(t *testing.T) {
 	output := NewAutoOutput("test goal", "default")
 	output.AddStepResult(StepResult{
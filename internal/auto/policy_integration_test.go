@@ -2,8 +2,14 @@ package auto
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
 )
 
 // mockPolicyChecker is a test implementation of PolicyChecker
@@ -119,6 +125,7 @@ func TestMaxStepsPolicy_DenialStopsExecution(t *testing.T) {
 		2,          // completedSteps
 		0.05,       // totalCost
 		time.Now(), // executionStart
+		nil,        // snapshot
 	)
 
 	if err != nil {
@@ -195,6 +202,7 @@ func TestMaxStepsPolicy_PolicyContextUpdates(t *testing.T) {
 			i,               // completedSteps
 			float64(i)*0.01, // totalCost
 			executionStart,
+			nil, // snapshot
 		)
 
 		if err != nil {
@@ -287,6 +295,7 @@ func TestMaxStepsPolicy_WarningsReported(t *testing.T) {
 			i,          // completedSteps
 			0.05,       // totalCost
 			time.Now(), // executionStart
+			nil,        // snapshot
 		)
 
 		if err != nil {
@@ -371,6 +380,7 @@ func TestMaxStepsPolicy_NoPolicyCheckerAllowsAll(t *testing.T) {
 		0,
 		0.0,
 		time.Now(),
+		nil, // snapshot
 	)
 
 	if err != nil {
@@ -385,3 +395,103 @@ func TestMaxStepsPolicy_NoPolicyCheckerAllowsAll(t *testing.T) {
 		t.Error("Expected nil policy event when no checker is provided")
 	}
 }
+
+// Test that policies see a step's file changes and blocked changes are reverted
+func TestCheckChanges_RevertsBlockedChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		blockedPath string
+		wantBlocked bool
+	}{
+		{"allowed changes", "deploy/", false},
+		{"blocked changes", "infra/", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workingDir := t.TempDir()
+			readme := filepath.Join(workingDir, "README.md")
+			if err := os.WriteFile(readme, []byte("original\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var sawSnapshot bool
+			o := NewOrchestrator(nil, DefaultConfig())
+			o.SetPatchGenerator(workingDir, t.TempDir())
+			// The step already passed the policy checker; it must not run again
+			o.SetPolicyChecker(&mockPolicyChecker{
+				checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+					return &PolicyResult{Allowed: false, Reason: "checked again"}, nil
+				},
+			})
+			o.SetChangeChecker(&mockPolicyChecker{
+				checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+					policyCtx := ctx.Value("policy_context").(*PolicyContext)
+					sawSnapshot = policyCtx.Snapshot["README.md"] == "original\n"
+					for _, change := range policyCtx.Changes {
+						if strings.HasPrefix(change.Path, tt.blockedPath) {
+							return &PolicyResult{Allowed: false, Reason: "changes not allowed: " + change.Path}, nil
+						}
+					}
+					return &PolicyResult{Allowed: true}, nil
+				},
+			})
+			o.actionPlan = CreateDefaultActionPlan("goal", "default")
+
+			snapshot, err := patch.CaptureDirectorySnapshot(workingDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Join(workingDir, "infra"), 0o750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(workingDir, "infra", "main.tf"), []byte("resource\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(readme, []byte("changed\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err = o.generateAndSavePatch(context.Background(), "step-4", "build:run", "Execute plan", snapshot)
+			if !sawSnapshot {
+				t.Error("policy context missing the pre-step snapshot")
+			}
+
+			if !tt.wantBlocked {
+				if err != nil {
+					t.Fatalf("generateAndSavePatch() error = %v", err)
+				}
+				if content, _ := os.ReadFile(readme); string(content) != "changed\n" {
+					t.Errorf("README.md = %q, want changes kept", content)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrChangesBlocked) || !strings.Contains(err.Error(), "infra/main.tf") {
+				t.Fatalf("generateAndSavePatch() error = %v, want ErrChangesBlocked citing infra/main.tf", err)
+			}
+			if !isFatalPatchError(err) {
+				t.Error("blocked changes should fail the step")
+			}
+			if content, _ := os.ReadFile(readme); string(content) != "original\n" {
+				t.Errorf("README.md = %q, want original content restored", content)
+			}
+			if _, err := os.Stat(filepath.Join(workingDir, "infra", "main.tf")); !os.IsNotExist(err) {
+				t.Errorf("infra/main.tf should be removed, stat error = %v", err)
+			}
+			step, _ := o.actionPlan.GetStep("step-4")
+			if step.Status != StepStatusFailed {
+				t.Errorf("step status = %s, want failed", step.Status)
+			}
+		})
+	}
+}
+
+func TestExecute_ChangeCheckerRequiresPatchGenerator(t *testing.T) {
+	o := NewOrchestrator(nil, DefaultConfig())
+	o.SetChangeChecker(&mockPolicyChecker{})
+
+	if _, err := o.Execute(context.Background()); !errors.Is(err, ErrChangesNotCaptured) {
+		t.Errorf("Execute() error = %v, want ErrChangesNotCaptured", err)
+	}
+}
@@ -3,6 +3,8 @@ package auto
 import (
 	"context"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
 )
 
 // PolicyChecker defines the interface for policy enforcement.
@@ -55,6 +57,14 @@ type PolicyContext struct {
 
 	// FailedSteps is the number of steps that failed.
 	FailedSteps int
+
+	// Snapshot holds the working directory's files before the step, keyed
+	// by relative path. It is nil when patch generation is disabled.
+	Snapshot map[string]string
+
+	// Changes are the file changes the step produced. They are only set
+	// when the orchestrator's change checker runs after the step.
+	Changes []patch.FilePatch
 }
 
 // NewPolicyContext creates a policy context for step evaluation.
@@ -0,0 +1,141 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/felixgeelhaar/specular/internal/drift"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// ReplanSummary describes how re-planning a resumed run changed the
+// remaining work
+type ReplanSummary struct {
+	Findings []drift.Finding // Plan and code drift for the checkpoint plan's features
+	Reopened []string        // Completed tasks queued again because their code drifted
+	Changed  []string        // Incomplete tasks whose regenerated definition differs
+	Removed  []string        // Incomplete tasks with no task in the regenerated plan
+}
+
+// HasChanges reports whether the re-plan differs from the checkpoint plan
+func (s *ReplanSummary) HasChanges() bool {
+	return len(s.Reopened) > 0 || len(s.Changed) > 0 || len(s.Removed) > 0
+}
+
+// replan re-runs drift detection against projectRoot and regenerates the
+// plan for the features in the checkpoint plan, merging it with the
+// checkpoint's completion status. It returns the full regenerated plan and
+// the tasks still to run. Completed tasks stay done unless their code
+// drifted, and dependencies on them are dropped so the remaining tasks are
+// not skipped.
+func (o *Orchestrator) replan(ctx context.Context, productSpec *spec.ProductSpec, previous *plan.Plan, completed map[string]bool, projectRoot string) (*plan.Plan, *plan.Plan, *ReplanSummary, error) {
+	specLock, err := o.generateSpecLock(productSpec)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("generate spec lock: %w", err)
+	}
+
+	previousByFeature := make(map[types.FeatureID]plan.Task, len(previous.Tasks))
+	for _, task := range previous.Tasks {
+		previousByFeature[task.FeatureID] = task
+	}
+
+	// Findings for features outside the checkpoint plan (for example,
+	// filtered out by --scope) are not relevant to the resumed run
+	summary := &ReplanSummary{}
+	findings := drift.DetectPlanDrift(specLock, previous)
+	findings = append(findings, drift.DetectCodeDrift(productSpec, specLock, drift.CodeDriftOptions{ProjectRoot: projectRoot})...)
+	drifted := make(map[types.FeatureID]bool)
+	for _, finding := range findings {
+		if _, ok := previousByFeature[finding.FeatureID]; !ok {
+			continue
+		}
+		summary.Findings = append(summary.Findings, finding)
+		// A traced file missing from the working tree reopens a completed
+		// task: the code it produced was removed since the checkpoint
+		if finding.Code == "MISSING_TRACE" {
+			drifted[finding.FeatureID] = true
+		}
+	}
+
+	regenerated, err := o.generatePlan(ctx, productSpec, specLock)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("generate plan: %w", err)
+	}
+
+	full := &plan.Plan{}
+	done := make(map[types.TaskID]bool)
+	planned := make(map[types.FeatureID]bool)
+	for _, task := range regenerated.Tasks {
+		prev, ok := previousByFeature[task.FeatureID]
+		if !ok {
+			continue
+		}
+		planned[task.FeatureID] = true
+		full.Tasks = append(full.Tasks, task)
+
+		switch {
+		case completed[prev.ID.String()] && !drifted[task.FeatureID]:
+			done[task.ID] = true
+		case completed[prev.ID.String()]:
+			summary.Reopened = append(summary.Reopened, task.ID.String())
+		case !sameTask(prev, task):
+			summary.Changed = append(summary.Changed, task.ID.String())
+		}
+	}
+
+	for _, task := range previous.Tasks {
+		if !planned[task.FeatureID] && !completed[task.ID.String()] {
+			summary.Removed = append(summary.Removed, task.ID.String())
+		}
+	}
+
+	remaining := &plan.Plan{}
+	for _, task := range full.Tasks {
+		if done[task.ID] {
+			continue
+		}
+		task.DependsOn = slices.DeleteFunc(slices.Clone(task.DependsOn), func(dep types.TaskID) bool {
+			return done[dep]
+		})
+		remaining.Tasks = append(remaining.Tasks, task)
+	}
+
+	return full, remaining, summary, nil
+}
+
+// sameTask reports whether two tasks for a feature would run the same work
+func sameTask(a, b plan.Task) bool {
+	return a.ID == b.ID &&
+		a.ExpectedHash == b.ExpectedHash &&
+		a.Skill == b.Skill &&
+		a.Priority == b.Priority &&
+		slices.Equal(a.DependsOn, b.DependsOn)
+}
+
+// printReplanSummary writes the drift findings and task changes of a re-plan
+func printReplanSummary(w io.Writer, s *ReplanSummary) {
+	if len(s.Findings) > 0 {
+		fmt.Fprintf(w, "   Drift findings: %d\n", len(s.Findings))
+		for _, finding := range s.Findings {
+			fmt.Fprintf(w, "     [%s] %s: %s\n", finding.Severity, finding.Code, finding.Message)
+		}
+	}
+
+	if !s.HasChanges() {
+		fmt.Fprintln(w, "   No changes to the remaining tasks")
+		return
+	}
+	for _, id := range s.Reopened {
+		fmt.Fprintf(w, "   ↺ Reopened: %s (code drifted since it completed)\n", id)
+	}
+	for _, id := range s.Changed {
+		fmt.Fprintf(w, "   ~ Changed:  %s\n", id)
+	}
+	for _, id := range s.Removed {
+		fmt.Fprintf(w, "   - Removed:  %s\n", id)
+	}
+}
@@ -0,0 +1,109 @@
+package auto
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+func TestReplan(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("package main\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// b.go, traced by feat-002, was deleted after its task completed
+	productSpec := &spec.ProductSpec{
+		Product: "test",
+		Features: []spec.Feature{
+			{ID: "feat-001", Title: "Alpha", Priority: "P0", Trace: []string{"a.go"}},
+			{ID: "feat-002", Title: "Beta", Priority: "P0", Trace: []string{"b.go"}},
+			{ID: "feat-003", Title: "Gamma", Priority: "P1", Trace: []string{"c.go"}},
+		},
+	}
+
+	orchestrator := NewOrchestrator(nil, DefaultConfig())
+	lock, err := orchestrator.generateSpecLock(productSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := orchestrator.generatePlan(context.Background(), productSpec, lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous.Tasks[2].Skill = "infra"
+
+	completed := map[string]bool{"task-001": true, "task-002": true}
+	full, remaining, summary, err := orchestrator.replan(context.Background(), productSpec, previous, completed, root)
+	if err != nil {
+		t.Fatalf("replan() error = %v", err)
+	}
+
+	if len(full.Tasks) != 3 {
+		t.Errorf("len(full.Tasks) = %d, want 3", len(full.Tasks))
+	}
+	if !reflect.DeepEqual(summary.Reopened, []string{"task-002"}) {
+		t.Errorf("Reopened = %v, want [task-002]", summary.Reopened)
+	}
+	if !reflect.DeepEqual(summary.Changed, []string{"task-003"}) {
+		t.Errorf("Changed = %v, want [task-003]", summary.Changed)
+	}
+	if len(summary.Removed) != 0 {
+		t.Errorf("Removed = %v, want none", summary.Removed)
+	}
+	if !summary.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+
+	if len(remaining.Tasks) != 2 || remaining.Tasks[0].ID != "task-002" || remaining.Tasks[1].ID != "task-003" {
+		t.Fatalf("remaining tasks = %+v, want task-002 and task-003", remaining.Tasks)
+	}
+	// The dependency on completed task-001 is dropped; reopened task-002 stays
+	if want := []types.TaskID{"task-002"}; !reflect.DeepEqual(remaining.Tasks[1].DependsOn, want) {
+		t.Errorf("task-003 DependsOn = %v, want %v", remaining.Tasks[1].DependsOn, want)
+	}
+	if len(full.Tasks[2].DependsOn) != 2 {
+		t.Errorf("full plan task-003 DependsOn = %v, want both P0 tasks", full.Tasks[2].DependsOn)
+	}
+}
+
+func TestReplan_NoChanges(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	productSpec := &spec.ProductSpec{
+		Product:  "test",
+		Features: []spec.Feature{{ID: "feat-001", Title: "Alpha", Priority: "P2", Trace: []string{"a.go"}}},
+	}
+
+	orchestrator := NewOrchestrator(nil, DefaultConfig())
+	lock, err := orchestrator.generateSpecLock(productSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := orchestrator.generatePlan(context.Background(), productSpec, lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, remaining, summary, err := orchestrator.replan(context.Background(), productSpec, previous, map[string]bool{}, root)
+	if err != nil {
+		t.Fatalf("replan() error = %v", err)
+	}
+	if summary.HasChanges() {
+		t.Errorf("HasChanges() = true, want false: %+v", summary)
+	}
+	if !reflect.DeepEqual(remaining.Tasks, []plan.Task{previous.Tasks[0]}) {
+		t.Errorf("remaining tasks = %+v, want the previous task", remaining.Tasks)
+	}
+}
@@ -0,0 +1,60 @@
+package auto
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/felixgeelhaar/specular/internal/checkpoint"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// taskCounts is a snapshot of a checkpoint's task statuses, taken before
+// and after retrying failed tasks
+type taskCounts struct {
+	Completed int
+	Failed    int
+	Pending   int
+}
+
+// countTasks returns the task status counts of a checkpoint
+func countTasks(cpState *checkpoint.State) taskCounts {
+	return taskCounts{
+		Completed: len(cpState.GetCompletedTasks()),
+		Failed:    len(cpState.GetFailedTasks()),
+		Pending:   len(cpState.GetPendingTasks()),
+	}
+}
+
+// failedTaskPlan returns the tasks of p that failed in the checkpoint, in
+// plan order. Dependencies on completed tasks are dropped so the retried
+// tasks are not skipped; dependencies between failed tasks are kept.
+func failedTaskPlan(p *plan.Plan, cpState *checkpoint.State) *plan.Plan {
+	failed := make(map[types.TaskID]bool)
+	for _, taskID := range cpState.GetFailedTasks() {
+		failed[types.TaskID(taskID)] = true
+	}
+
+	retry := &plan.Plan{}
+	for _, task := range p.Tasks {
+		if !failed[task.ID] {
+			continue
+		}
+		task.DependsOn = slices.DeleteFunc(slices.Clone(task.DependsOn), func(dep types.TaskID) bool {
+			return cpState.Tasks[dep.String()].Status == "completed"
+		})
+		retry.Tasks = append(retry.Tasks, task)
+	}
+	return retry
+}
+
+// printRetrySummary writes how the task counts changed after a retry
+func printRetrySummary(w io.Writer, sessionID string, before, after taskCounts) {
+	fmt.Fprintf(w, "\n🔁 Retry summary:\n")
+	fmt.Fprintf(w, "   ✓ Completed: %d → %d\n", before.Completed, after.Completed)
+	fmt.Fprintf(w, "   ✗ Failed:    %d → %d\n", before.Failed, after.Failed)
+	if after.Pending > 0 {
+		fmt.Fprintf(w, "   ⏳ Pending:   %d (resume with: specular auto --resume %s)\n", after.Pending, sessionID)
+	}
+}
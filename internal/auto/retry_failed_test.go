@@ -0,0 +1,92 @@
+package auto
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/checkpoint"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+func TestFailedTaskPlan(t *testing.T) {
+	p := &plan.Plan{Tasks: []plan.Task{
+		{ID: "task-001", FeatureID: "feat-001"},
+		{ID: "task-002", FeatureID: "feat-002", DependsOn: []types.TaskID{"task-001"}},
+		{ID: "task-003", FeatureID: "feat-003", DependsOn: []types.TaskID{"task-001", "task-002"}},
+		{ID: "task-004", FeatureID: "feat-004", DependsOn: []types.TaskID{"task-003"}},
+	}}
+
+	cpState := checkpoint.NewState("auto-1")
+	cpState.UpdateTask("task-001", "completed", nil)
+	cpState.UpdateTask("task-002", "failed", errors.New("exit 1"))
+	cpState.UpdateTask("task-003", "failed", errors.New("exit 1"))
+	cpState.UpdateTask("task-004", "pending", nil)
+
+	retry := failedTaskPlan(p, cpState)
+
+	var ids []string
+	for _, task := range retry.Tasks {
+		ids = append(ids, task.ID.String())
+	}
+	if !slices.Equal(ids, []string{"task-002", "task-003"}) {
+		t.Fatalf("retry tasks = %v, want [task-002 task-003]", ids)
+	}
+
+	// The dependency on the completed task is dropped; the one on the other
+	// failed task is kept
+	if len(retry.Tasks[0].DependsOn) != 0 {
+		t.Errorf("task-002 depends on %v, want none", retry.Tasks[0].DependsOn)
+	}
+	if !slices.Equal(retry.Tasks[1].DependsOn, []types.TaskID{"task-002"}) {
+		t.Errorf("task-003 depends on %v, want [task-002]", retry.Tasks[1].DependsOn)
+	}
+
+	// The checkpoint plan is not modified
+	if len(p.Tasks[2].DependsOn) != 2 {
+		t.Errorf("original plan dependencies changed: %v", p.Tasks[2].DependsOn)
+	}
+}
+
+func TestPrintRetrySummary(t *testing.T) {
+	var out bytes.Buffer
+	printRetrySummary(&out, "auto-1", taskCounts{Completed: 9, Failed: 3}, taskCounts{Completed: 11, Failed: 1, Pending: 2})
+
+	for _, want := range []string{
+		"Completed: 9 → 11",
+		"Failed:    3 → 1",
+		"Pending:   2 (resume with: specular auto --resume auto-1)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCheckpointSpentUSD(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  float64
+	}{
+		{name: "not recorded", want: 0},
+		{name: "recorded", value: "0.75", want: 0.75},
+		{name: "invalid", value: "abc", want: 0},
+		{name: "negative", value: "-1", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpState := checkpoint.NewState("auto-1")
+			if tt.value != "" {
+				cpState.SetMetadata("spent_usd", tt.value)
+			}
+			if got := CheckpointSpentUSD(cpState); got != tt.want {
+				t.Errorf("CheckpointSpentUSD() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ const (
 	// PatternTypePath matches file paths with glob patterns
 	PatternTypePath PatternType = "path"
 
-	// PatternTypeTag matches feature tags (future use)
+	// PatternTypeTag matches feature tags with glob patterns
 	PatternTypeTag PatternType = "tag"
 )
 
@@ -49,7 +49,7 @@ type Scope struct {
 //   - "feature:ID" - Match feature by exact ID
 //   - "feature:title pattern" - Match feature titles with glob
 //   - "path/pattern/**" - Match paths with glob
-//   - "@tag" - Match by tag (future)
+//   - "@tag" - Match features carrying the tag (glob)
 func NewScope(patterns []string, includeDeps bool) (*Scope, error) {
 	scope := &Scope{
 		Patterns:            make([]ScopePattern, 0, len(patterns)),
@@ -135,20 +135,36 @@ func matchesFeaturePattern(feature spec.Feature, pattern ScopePattern) bool {
 		return false
 
 	case PatternTypeTag:
-		// Tag matching not yet implemented
-		return false
+		return matchesTag(feature.Tags, pattern.Pattern)
 
 	default:
 		return false
 	}
 }
 
+// matchesTag checks if any tag matches a tag pattern.
+func matchesTag(tags []string, pattern string) bool {
+	for _, tag := range tags {
+		if matched, _ := filepath.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // MatchesTask checks if a task matches the scope based on its feature.
+// Tag patterns also match the tags copied onto the task from its feature.
 func (s *Scope) MatchesTask(task plan.Task, productSpec *spec.ProductSpec) bool {
 	if len(s.Patterns) == 0 {
 		return true // No scope means match all
 	}
 
+	for _, pattern := range s.Patterns {
+		if pattern.Type == PatternTypeTag && matchesTag(task.Tags, pattern.Pattern) {
+			return true
+		}
+	}
+
 	// Find the feature this task belongs to
 	var taskFeature *spec.Feature
 	for i := range productSpec.Features {
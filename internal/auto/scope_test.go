@@ -175,6 +175,55 @@ func TestMatchesFeature(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:     "matches tag",
+			patterns: []string{"@critical"},
+			feature: spec.Feature{
+				ID:    "feat-1",
+				Title: "Payments",
+				Tags:  []string{"backend", "critical"},
+			},
+			expected: true,
+		},
+		{
+			name:     "matches tag glob",
+			patterns: []string{"@back*"},
+			feature: spec.Feature{
+				ID:    "feat-1",
+				Title: "Payments",
+				Tags:  []string{"backend"},
+			},
+			expected: true,
+		},
+		{
+			name:     "does not match missing tag",
+			patterns: []string{"@critical"},
+			feature: spec.Feature{
+				ID:    "feat-1",
+				Title: "Payments",
+				Tags:  []string{"backend"},
+			},
+			expected: false,
+		},
+		{
+			name:     "does not match untagged feature",
+			patterns: []string{"@critical"},
+			feature: spec.Feature{
+				ID:    "feat-1",
+				Title: "Payments",
+			},
+			expected: false,
+		},
+		{
+			name:     "matches one of multiple tags (OR logic)",
+			patterns: []string{"@frontend", "@backend"},
+			feature: spec.Feature{
+				ID:    "feat-1",
+				Title: "Payments",
+				Tags:  []string{"backend"},
+			},
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -201,6 +250,7 @@ func TestMatchesTask(t *testing.T) {
 				API: []spec.API{
 					{Method: "POST", Path: "/api/auth/login"},
 				},
+				Tags: []string{"security"},
 			},
 			{
 				ID:    "feat-2",
@@ -254,6 +304,34 @@ func TestMatchesTask(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:     "matches task by its feature's tag",
+			patterns: []string{"@security"},
+			task: plan.Task{
+				ID:        "task-1",
+				FeatureID: "feat-1",
+			},
+			expected: true,
+		},
+		{
+			name:     "matches task by its own tags",
+			patterns: []string{"@critical"},
+			task: plan.Task{
+				ID:        "task-3",
+				FeatureID: "feat-unknown",
+				Tags:      []string{"critical"},
+			},
+			expected: true,
+		},
+		{
+			name:     "does not match task by tag of another feature",
+			patterns: []string{"@security"},
+			task: plan.Task{
+				ID:        "task-2",
+				FeatureID: "feat-2",
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -424,6 +502,30 @@ func TestFilterPlan(t *testing.T) {
 			t.Error("expected task-1 and task-3 to be included")
 		}
 	})
+
+	t.Run("tag match with untagged dependency", func(t *testing.T) {
+		execPlan := &plan.Plan{
+			Tasks: []plan.Task{
+				{ID: "task-1", FeatureID: "feat-1"},
+				{ID: "task-2", FeatureID: "feat-2", DependsOn: []types.TaskID{"task-1"}, Tags: []string{"critical"}},
+				{ID: "task-3", FeatureID: "feat-3", DependsOn: []types.TaskID{"task-2"}},
+			},
+		}
+
+		// The untagged dependency is included with dependencies enabled
+		scope, _ := NewScope([]string{"@critical"}, true)
+		filtered := scope.FilterPlan(execPlan, productSpec)
+		if len(filtered.Tasks) != 2 || filtered.Tasks[0].ID != "task-1" || filtered.Tasks[1].ID != "task-2" {
+			t.Errorf("expected task-1 and task-2, got %v", filtered.Tasks)
+		}
+
+		// and left out without them
+		scope, _ = NewScope([]string{"@critical"}, false)
+		filtered = scope.FilterPlan(execPlan, productSpec)
+		if len(filtered.Tasks) != 1 || filtered.Tasks[0].ID != "task-2" {
+			t.Errorf("expected only task-2, got %v", filtered.Tasks)
+		}
+	})
 }
 
 func TestExpandDependencies(t *testing.T) {
@@ -0,0 +1,112 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+// SpecValidator checks the generated spec before it is locked.
+// This interface lets validator plugins review the spec without the
+// orchestrator depending on the plugin package.
+type SpecValidator interface {
+	// ValidateSpec returns the issues found in the spec.
+	ValidateSpec(ctx context.Context, productSpec *spec.ProductSpec) ([]ValidationIssue, error)
+
+	// Name returns the name of this validator for reporting.
+	Name() string
+}
+
+// Validation issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// ValidationIssue records a problem a spec validator found.
+type ValidationIssue struct {
+	// Validator identifies which validator reported the issue
+	Validator string `json:"validator"`
+
+	// Severity is error, warning, or info
+	Severity string `json:"severity"`
+
+	// Message describes the issue
+	Message string `json:"message"`
+
+	// Rule identifies the validator rule that was violated
+	Rule string `json:"rule,omitempty"`
+}
+
+// String formats the issue for console output and step warnings
+func (i ValidationIssue) String() string {
+	if i.Rule != "" {
+		return fmt.Sprintf("[%s] %s: %s (%s)", i.Severity, i.Validator, i.Message, i.Rule)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Validator, i.Message)
+}
+
+// AddSpecValidator registers a validator that runs against the spec
+// generated in step 1. This must be called before Execute.
+func (o *Orchestrator) AddSpecValidator(validator SpecValidator) {
+	o.specValidators = append(o.specValidators, validator)
+}
+
+// validateSpec runs every spec validator and returns the issues found. A
+// validator that fails to run is reported as a warning issue so one broken
+// plugin does not stop the workflow. The returned error is set when
+// BlockOnValidationErrors is enabled and an error-severity issue was found.
+func (o *Orchestrator) validateSpec(ctx context.Context, productSpec *spec.ProductSpec) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	errorCount := 0
+
+	for _, validator := range o.specValidators {
+		found, err := validator.ValidateSpec(ctx, productSpec)
+		if err != nil {
+			found = []ValidationIssue{{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("validator failed to run: %v", err),
+			}}
+		}
+		for _, issue := range found {
+			issue.Validator = validator.Name()
+			if issue.Severity == "" {
+				issue.Severity = SeverityError
+			}
+			if issue.Severity == SeverityError {
+				errorCount++
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	if errorCount > 0 && o.config.BlockOnValidationErrors {
+		return issues, fmt.Errorf("spec validation found %d error(s)", errorCount)
+	}
+	return issues, nil
+}
+
+// printValidationIssues prints the issues spec validators found
+func printValidationIssues(issues []ValidationIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Printf("🔎 Spec validation found %d issue(s):\n", len(issues))
+	for _, issue := range issues {
+		fmt.Printf("   %s\n", issue)
+	}
+}
+
+// issueStrings formats issues as step result warnings
+func issueStrings(issues []ValidationIssue) []string {
+	if len(issues) == 0 {
+		return nil
+	}
+	warnings := make([]string, len(issues))
+	for i, issue := range issues {
+		warnings[i] = issue.String()
+	}
+	return warnings
+}
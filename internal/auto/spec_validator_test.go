@@ -0,0 +1,85 @@
+package auto
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+// testSpecValidator returns fixed issues or an error
+type testSpecValidator struct {
+	name   string
+	issues []ValidationIssue
+	err    error
+}
+
+func (v *testSpecValidator) Name() string { return v.name }
+
+func (v *testSpecValidator) ValidateSpec(ctx context.Context, productSpec *spec.ProductSpec) ([]ValidationIssue, error) {
+	return v.issues, v.err
+}
+
+func TestValidateSpec(t *testing.T) {
+	acceptance := &testSpecValidator{
+		name: "spec-validator",
+		issues: []ValidationIssue{
+			{Severity: SeverityError, Message: "feature feat-001 has no acceptance criteria", Rule: "acceptance-criteria"},
+			{Severity: SeverityWarning, Message: "feature feat-002 has no description"},
+		},
+	}
+	broken := &testSpecValidator{name: "broken", err: errors.New("plugin crashed")}
+
+	tests := []struct {
+		name       string
+		block      bool
+		validators []SpecValidator
+		wantIssues int
+		wantErr    string
+	}{
+		{"no validators", true, nil, 0, ""},
+		{"issues reported", false, []SpecValidator{acceptance}, 2, ""},
+		{"errors block", true, []SpecValidator{acceptance}, 2, "found 1 error(s)"},
+		{"failed validator is a warning", true, []SpecValidator{broken}, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.BlockOnValidationErrors = tt.block
+			o := NewOrchestrator(nil, config)
+			for _, v := range tt.validators {
+				o.AddSpecValidator(v)
+			}
+
+			issues, err := o.validateSpec(context.Background(), &spec.ProductSpec{Product: "test"})
+			if len(issues) != tt.wantIssues {
+				t.Errorf("got %d issues, want %d: %v", len(issues), tt.wantIssues, issues)
+			}
+			for _, issue := range issues {
+				if issue.Validator == "" {
+					t.Errorf("issue %v has no validator name", issue)
+				}
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidationIssue_String(t *testing.T) {
+	issue := ValidationIssue{Validator: "spec-validator", Severity: SeverityError, Message: "missing criteria", Rule: "acceptance-criteria"}
+	want := "[error] spec-validator: missing criteria (acceptance-criteria)"
+	if got := issue.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
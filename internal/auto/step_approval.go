@@ -0,0 +1,226 @@
+package auto
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
+	"github.com/felixgeelhaar/specular/internal/plan"
+)
+
+// errRunAborted is returned when the user aborts the run at a per-step prompt
+var errRunAborted = errors.New("run aborted by user")
+
+// maxPatchPreviewLines caps the diff lines shown at a per-step prompt
+const maxPatchPreviewLines = 40
+
+// stepApprover asks for approval before each plan task in per-step approval
+// mode. Each prompt shows the pending task, the cost so far, and the patch
+// produced by the previous task. Non-interactive runs approve every task
+// without prompting. Every decision is recorded in the audit trail.
+type stepApprover struct {
+	ctx         context.Context
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+	output      *AutoOutput
+
+	// spent returns the run's cost so far in USD
+	spent func() float64
+
+	// snapshot and diff track the changes each task makes; both are nil when
+	// patch generation is disabled
+	snapshot func() (map[string]string, error)
+	diff     func(taskID string, before map[string]string) (*patch.Patch, error)
+
+	total  int
+	count  int
+	before map[string]string
+}
+
+// newStepApprover creates a per-step approver reading answers from in
+func (o *Orchestrator) newStepApprover(ctx context.Context, in io.Reader, out io.Writer, autoOutput *AutoOutput, total int) *stepApprover {
+	a := &stepApprover{
+		ctx:         ctx,
+		in:          bufio.NewReader(in),
+		out:         out,
+		interactive: o.config.RequireApproval,
+		output:      autoOutput,
+		total:       total,
+		spent: func() float64 {
+			if o.router == nil {
+				return 0
+			}
+			return o.router.GetBudget().SpentUSD
+		},
+	}
+
+	if o.patchGenerator != nil {
+		a.snapshot = o.captureSnapshot
+		a.diff = func(taskID string, before map[string]string) (*patch.Patch, error) {
+			return o.patchGenerator.GeneratePatch(taskID, string(StepTypeBuildRun), o.patchWorkflowID(), "Task "+taskID, before)
+		}
+	}
+
+	return a
+}
+
+// approve prompts for a task. It returns false to skip the task, and
+// errRunAborted or the context error to stop the run.
+func (a *stepApprover) approve(task plan.Task) (bool, error) {
+	a.count++
+	taskID := task.ID.String()
+
+	if !a.interactive {
+		a.record(taskID, true, "auto-approved (non-interactive profile)")
+		return true, nil
+	}
+
+	fmt.Fprintf(a.out, "\n⏸  Task %d/%d: %s (%s) [%s] %s\n", a.count, a.total, taskID, task.FeatureID, task.Priority, task.Skill)
+	fmt.Fprintf(a.out, "   Cost so far: $%.4f\n", a.spent())
+	a.showLastPatch()
+
+	for {
+		fmt.Fprint(a.out, "   Approve? [a]pprove / [s]kip / a[b]ort: ")
+		answer, err := a.readLine()
+		if err != nil {
+			fmt.Fprintln(a.out)
+			a.record(taskID, false, "aborted: "+err.Error())
+			if errors.Is(err, io.EOF) {
+				return false, errRunAborted
+			}
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "approve", "y", "yes":
+			a.record(taskID, true, "approved by user")
+			a.takeSnapshot()
+			return true, nil
+		case "s", "skip":
+			a.record(taskID, false, "skipped by user")
+			return false, nil
+		case "b", "abort", "q", "quit":
+			a.record(taskID, false, "aborted by user")
+			return false, errRunAborted
+		}
+	}
+}
+
+// readLine reads one answer, returning early when the context is cancelled
+// (for example by Ctrl+C)
+func (a *stepApprover) readLine() (string, error) {
+	return readLineContext(a.ctx, a.in)
+}
+
+// readLineContext reads a line from in, returning the context error if ctx
+// is cancelled first
+func readLineContext(ctx context.Context, in *bufio.Reader) (string, error) {
+	type line struct {
+		text string
+		err  error
+	}
+	lines := make(chan line, 1)
+	go func() {
+		text, err := in.ReadString('\n')
+		if err == io.EOF && text != "" {
+			err = nil
+		}
+		lines <- line{text, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case l := <-lines:
+		return l.text, l.err
+	}
+}
+
+// confirm asks a yes/no question. Anything but "y" or "yes", including end
+// of input, is a no.
+func confirm(ctx context.Context, in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := readLineContext(ctx, bufio.NewReader(in))
+	if err != nil {
+		fmt.Fprintln(out)
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// showLastPatch prints the changes made by the previously approved task
+func (a *stepApprover) showLastPatch() {
+	if a.diff == nil {
+		fmt.Fprintln(a.out, "   Last patch: unavailable (enable --save-patches)")
+		return
+	}
+	if a.before == nil {
+		fmt.Fprintln(a.out, "   Last patch: none yet")
+		return
+	}
+
+	p, err := a.diff("previous", a.before)
+	if err != nil {
+		fmt.Fprintf(a.out, "   Last patch: %v\n", err)
+		return
+	}
+	if p.IsEmpty() {
+		fmt.Fprintln(a.out, "   Last patch: no changes")
+		return
+	}
+
+	fmt.Fprintf(a.out, "   Last patch: %d files, +%d -%d\n", p.FilesChanged, p.Insertions, p.Deletions)
+	lines := 0
+	for _, file := range p.Files {
+		for _, diffLine := range strings.Split(strings.TrimRight(file.Diff, "\n"), "\n") {
+			if lines == maxPatchPreviewLines {
+				fmt.Fprintln(a.out, "     ...")
+				return
+			}
+			fmt.Fprintf(a.out, "     %s\n", diffLine)
+			lines++
+		}
+	}
+}
+
+// takeSnapshot records the working tree before an approved task runs, so
+// the next prompt can show what the task changed
+func (a *stepApprover) takeSnapshot() {
+	if a.snapshot == nil {
+		return
+	}
+	snapshot, err := a.snapshot()
+	if err != nil {
+		fmt.Fprintf(a.out, "   ⚠️  Failed to capture snapshot: %v\n", err)
+		a.before = nil
+		return
+	}
+	a.before = snapshot
+}
+
+// record adds a decision to the audit trail
+func (a *stepApprover) record(taskID string, approved bool, reason string) {
+	if a.output == nil {
+		return
+	}
+	a.output.AddApproval(ApprovalEvent{
+		StepID:    taskID,
+		Timestamp: time.Now(),
+		Approved:  approved,
+		Reason:    reason,
+	})
+}
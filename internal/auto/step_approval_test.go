@@ -0,0 +1,144 @@
+package auto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+func newTestStepApprover(ctx context.Context, input io.Reader, interactive bool) (*stepApprover, *bytes.Buffer) {
+	config := DefaultConfig()
+	config.RequireApproval = interactive
+	config.PerStepApproval = true
+
+	out := &bytes.Buffer{}
+	orchestrator := NewOrchestrator(nil, config)
+	return orchestrator.newStepApprover(ctx, input, out, NewAutoOutput("goal", "strict"), 3), out
+}
+
+func approvalTask(id string) plan.Task {
+	return plan.Task{ID: types.TaskID(id), FeatureID: "feat-001", Skill: "go-backend", Priority: types.PriorityP0}
+}
+
+func TestStepApprover_Decisions(t *testing.T) {
+	// "x" is not a valid answer, so the first task is asked again
+	approver, out := newTestStepApprover(context.Background(), strings.NewReader("x\na\ns\nb\n"), true)
+
+	approved, err := approver.approve(approvalTask("task-001"))
+	if err != nil || !approved {
+		t.Errorf("task-001: approved=%v err=%v, want approved", approved, err)
+	}
+
+	approved, err = approver.approve(approvalTask("task-002"))
+	if err != nil || approved {
+		t.Errorf("task-002: approved=%v err=%v, want skipped", approved, err)
+	}
+
+	_, err = approver.approve(approvalTask("task-003"))
+	if !errors.Is(err, errRunAborted) {
+		t.Errorf("task-003: err=%v, want errRunAborted", err)
+	}
+
+	if !strings.Contains(out.String(), "Task 1/3: task-001") {
+		t.Errorf("prompt missing task header:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Cost so far: $0.0000") {
+		t.Errorf("prompt missing cost:\n%s", out.String())
+	}
+
+	approvals := approver.output.Audit.Approvals
+	if len(approvals) != 3 {
+		t.Fatalf("expected 3 audit events, got %d", len(approvals))
+	}
+	want := []struct {
+		stepID   string
+		approved bool
+		reason   string
+	}{
+		{"task-001", true, "approved by user"},
+		{"task-002", false, "skipped by user"},
+		{"task-003", false, "aborted by user"},
+	}
+	for i, w := range want {
+		got := approvals[i]
+		if got.StepID != w.stepID || got.Approved != w.approved || got.Reason != w.reason {
+			t.Errorf("approval %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestStepApprover_NonInteractive(t *testing.T) {
+	approver, out := newTestStepApprover(context.Background(), strings.NewReader(""), false)
+
+	approved, err := approver.approve(approvalTask("task-001"))
+	if err != nil || !approved {
+		t.Fatalf("approved=%v err=%v, want approved", approved, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no prompt, got:\n%s", out.String())
+	}
+
+	approvals := approver.output.Audit.Approvals
+	if len(approvals) != 1 || !approvals[0].Approved {
+		t.Errorf("expected one approved audit event, got %+v", approvals)
+	}
+}
+
+func TestStepApprover_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The pipe never delivers input, so only cancellation ends the prompt
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	approver, _ := newTestStepApprover(ctx, reader, true)
+	_, err := approver.approve(approvalTask("task-001"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestStepApprover_EOFAborts(t *testing.T) {
+	approver, _ := newTestStepApprover(context.Background(), strings.NewReader(""), true)
+
+	_, err := approver.approve(approvalTask("task-001"))
+	if !errors.Is(err, errRunAborted) {
+		t.Errorf("err = %v, want errRunAborted", err)
+	}
+}
+
+func TestStepApprover_ShowsLastPatch(t *testing.T) {
+	approver, out := newTestStepApprover(context.Background(), strings.NewReader("a\na\n"), true)
+	approver.snapshot = func() (map[string]string, error) {
+		return map[string]string{"main.go": "package main\n"}, nil
+	}
+	approver.diff = func(taskID string, before map[string]string) (*patch.Patch, error) {
+		return &patch.Patch{
+			Files:        []patch.FilePatch{{Path: "main.go", Diff: "--- a/main.go\n+++ b/main.go\n+func main() {}\n"}},
+			FilesChanged: 1,
+			Insertions:   1,
+		}, nil
+	}
+
+	if _, err := approver.approve(approvalTask("task-001")); err != nil {
+		t.Fatalf("task-001: %v", err)
+	}
+	if !strings.Contains(out.String(), "Last patch: none yet") {
+		t.Errorf("expected no patch before the first task:\n%s", out.String())
+	}
+
+	if _, err := approver.approve(approvalTask("task-002")); err != nil {
+		t.Fatalf("task-002: %v", err)
+	}
+	if !strings.Contains(out.String(), "Last patch: 1 files, +1 -0") || !strings.Contains(out.String(), "+func main() {}") {
+		t.Errorf("expected the previous task's patch:\n%s", out.String())
+	}
+}
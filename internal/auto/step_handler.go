@@ -0,0 +1,372 @@
+package auto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+// StepHandler runs a workflow step. The built-in steps are step handlers,
+// and custom steps, such as a design review or a database migration, are
+// inserted between them. Every step goes through the same policy checks,
+// tracing, patch capture, hooks, and JSON output.
+type StepHandler interface {
+	// Type returns the step type, for example "design:review"
+	Type() StepType
+
+	// Execute runs the step. A returned error fails the workflow.
+	Execute(ctx context.Context, state *WorkflowState) error
+
+	// EstimateCost returns the projected cost of the step in USD
+	EstimateCost() float64
+}
+
+// StepPosition is implemented by step handlers that choose which built-in
+// step they run after. Handlers without it run after build:run.
+type StepPosition interface {
+	After() StepType
+}
+
+// FeatureStep is implemented by step handlers that run once per spec
+// feature, such as a per-feature design review. It has no effect on steps
+// that run before the spec is generated.
+type FeatureStep interface {
+	PerFeature() bool
+}
+
+// WorkflowState is the workflow's progress passed from step to step. Fields
+// are set once the built-in step producing them has completed.
+type WorkflowState struct {
+	Goal     string
+	DryRun   bool
+	Step     *ActionStep
+	Spec     *spec.ProductSpec
+	SpecLock *spec.SpecLock
+	Plan     *plan.Plan
+
+	// Feature is the feature a FeatureStep is running for
+	Feature *spec.Feature
+
+	// CostUSD is the step's actual cost. Handlers that know it add to it;
+	// otherwise the handler's estimate is recorded.
+	CostUSD float64
+
+	router     *router.Router
+	result     *Result
+	autoOutput *AutoOutput
+
+	// Reported in the step's result; reset before each step
+	warnings []string
+	metadata map[string]interface{}
+}
+
+// Generate sends a model request through the workflow's router. The request
+// is attributed to the current step, and to the current feature of a
+// FeatureStep, in the router's cost breakdown.
+func (s *WorkflowState) Generate(ctx context.Context, req router.GenerateRequest) (*router.GenerateResponse, error) {
+	if s.router == nil {
+		return nil, errors.New("workflow has no router")
+	}
+	if req.StepType == "" && s.Step != nil {
+		req.StepType = string(s.Step.Type)
+	}
+	if req.FeatureID == "" && s.Feature != nil {
+		req.FeatureID = s.Feature.ID
+	}
+	return s.router.Generate(ctx, req)
+}
+
+// builtinStepTypes are the built-in step types, in workflow order
+var builtinStepTypes = []StepType{
+	StepTypeSpecUpdate,
+	StepTypeSpecLock,
+	StepTypePlanGen,
+	StepTypeBuildRun,
+	StepTypeVerify,
+}
+
+// RegisterStep adds a custom step to the workflow. It runs after the
+// built-in step named by the handler's After method, or after build:run.
+// This must be called before Execute.
+func (o *Orchestrator) RegisterStep(handler StepHandler) error {
+	if handler == nil {
+		return fmt.Errorf("step handler is nil")
+	}
+
+	stepType := handler.Type()
+	if stepType == "" {
+		return fmt.Errorf("step handler has no type")
+	}
+	if slices.Contains(builtinStepTypes, stepType) {
+		return fmt.Errorf("step type %q is built in", stepType)
+	}
+	stepID := customStepID(stepType)
+	if slices.Contains(builtinStepIDs(), stepID) {
+		return fmt.Errorf("step type %q: step ID %s is built in", stepType, stepID)
+	}
+	for _, registered := range o.customSteps {
+		if registered.Type() == stepType {
+			return fmt.Errorf("step type %q is already registered", stepType)
+		}
+		if customStepID(registered.Type()) == stepID {
+			return fmt.Errorf("step type %q: step ID %s is already used by %q", stepType, stepID, registered.Type())
+		}
+	}
+
+	if after := stepAfter(handler, true); !slices.Contains(builtinStepTypes, after) {
+		return fmt.Errorf("step %q: can only run after a built-in step, not %q", stepType, after)
+	}
+
+	o.customSteps = append(o.customSteps, handler)
+	return nil
+}
+
+// stepAfter returns the built-in step type a handler runs after. Steps
+// that follow verify:run follow build:run when verification is disabled.
+func stepAfter(handler StepHandler, verify bool) StepType {
+	after := StepTypeBuildRun
+	if position, ok := handler.(StepPosition); ok {
+		after = position.After()
+	}
+	if after == StepTypeVerify && !verify {
+		return StepTypeBuildRun
+	}
+	return after
+}
+
+// builtinStepIDs returns the action plan step IDs of the built-in steps
+func builtinStepIDs() []string {
+	plan := CreateDefaultActionPlan("", "")
+	AddVerifyStep(plan)
+	ids := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		ids = append(ids, step.ID)
+	}
+	return ids
+}
+
+// customStepID returns the action plan step ID for a custom step type, for
+// example "step-design-review" for "design:review"
+func customStepID(stepType StepType) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, string(stepType))
+	return "step-" + id
+}
+
+// ComposeActionPlan creates the default action plan, with the verify step
+// if enabled, and inserts the custom steps after the built-in steps they
+// follow.
+func ComposeActionPlan(goal, profile string, verify bool, handlers []StepHandler) (*ActionPlan, error) {
+	plan := CreateDefaultActionPlan(goal, profile)
+	if verify {
+		AddVerifyStep(plan)
+	}
+
+	// Later handlers for the same built-in step run after earlier ones
+	anchors := make(map[StepType]string)
+	for _, step := range plan.Steps {
+		anchors[step.Type] = step.ID
+	}
+
+	for _, handler := range handlers {
+		after := stepAfter(handler, verify)
+		step := ActionStep{
+			ID:          customStepID(handler.Type()),
+			Type:        handler.Type(),
+			Description: fmt.Sprintf("Run custom %s step", handler.Type()),
+			Custom:      true,
+			Signals: map[string]string{
+				"custom": "true",
+			},
+			EstimatedCostUSD: handler.EstimateCost(),
+		}
+		if err := plan.InsertStepAfter(anchors[after], step); err != nil {
+			return nil, fmt.Errorf("insert step %s: %w", step.ID, err)
+		}
+		anchors[after] = step.ID
+	}
+
+	return plan, nil
+}
+
+// stepHandlers returns the handler of each step in the action plan, by
+// step ID
+func (o *Orchestrator) stepHandlers(state *WorkflowState) (map[string]StepHandler, error) {
+	builtins := o.builtinSteps(state)
+	custom := make(map[string]StepHandler, len(o.customSteps))
+	for _, handler := range o.customSteps {
+		custom[customStepID(handler.Type())] = handler
+	}
+
+	handlers := make(map[string]StepHandler, len(o.actionPlan.Steps))
+	for _, step := range o.actionPlan.Steps {
+		handler := builtins[step.Type]
+		if step.Custom {
+			handler = custom[step.ID]
+		}
+		if handler == nil {
+			return nil, fmt.Errorf("no handler for step %s (%s)", step.ID, step.Type)
+		}
+		handlers[step.ID] = handler
+	}
+	return handlers, nil
+}
+
+// runStep runs one step of the action plan and returns its cost. The
+// budget and policy are checked first, then the step is traced, recorded in
+// the JSON output, and its changes saved as a patch.
+func (o *Orchestrator) runStep(ctx context.Context, stepID string, handler StepHandler, state *WorkflowState, completedSteps int, totalCost float64, executionStart time.Time) (float64, error) {
+	step, err := o.actionPlan.GetStep(stepID)
+	if err != nil {
+		return 0, err
+	}
+	autoOutput := state.autoOutput
+
+	// Pre-flight: Check the budget for the step's model calls
+	if budgeted, ok := handler.(budgetedStep); ok && o.router != nil {
+		if err := budgeted.checkBudget(ctx, stepID); err != nil {
+			return 0, err
+		}
+	}
+
+	// Capture snapshot before step
+	snapshot, err := o.captureSnapshot()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to capture snapshot: %v\n", err)
+	}
+
+	// Check policy before executing step
+	allowed, policyEvent, err := o.checkPolicy(ctx, o.policyChecker, step, o.stepIndex(stepID), completedSteps, totalCost, executionStart, snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("%s policy check: %w", stepID, err)
+	}
+	if autoOutput != nil && policyEvent != nil {
+		autoOutput.AddPolicy(*policyEvent)
+	}
+	if !allowed {
+		fmt.Printf("🚫 Step %s blocked by policy: %s\n", stepID, policyEvent.Reason)
+		if autoOutput != nil {
+			autoOutput.SetPartial()
+		}
+		return 0, fmt.Errorf("%s blocked by policy: %s", stepID, policyEvent.Reason)
+	}
+
+	stepStart := time.Now()
+	if err := o.actionPlan.UpdateStepStatus(stepID, StepStatusInProgress); err != nil {
+		return 0, fmt.Errorf("update step status: %w", err)
+	}
+	if o.tracer != nil {
+		o.tracer.LogStepStart(stepID, string(step.Type), step.Description) //#nosec G104 -- Logging errors not critical
+	}
+
+	if step.Custom {
+		fmt.Printf("🧩 Running %s...\n", handler.Type())
+	}
+	state.Step = step
+	state.CostUSD = 0
+	state.warnings = nil
+	state.metadata = nil
+	if err := executeStep(ctx, handler, state); err != nil {
+		// A paused run is saved by the caller and resumed at this step
+		if errors.Is(err, router.ErrBudgetPaused) {
+			return 0, err
+		}
+
+		step.Error = err.Error()
+		_ = o.actionPlan.UpdateStepStatus(stepID, StepStatusFailed) //#nosec G104 -- Status update errors handled at workflow level
+		if o.tracer != nil {
+			o.tracer.LogStepFail(stepID, string(step.Type), step.Description, err) //#nosec G104 -- Logging errors not critical
+		}
+		if autoOutput != nil {
+			autoOutput.AddStepResult(StepResult{
+				ID:          stepID,
+				Type:        string(step.Type),
+				Status:      "failed",
+				StartedAt:   stepStart,
+				CompletedAt: time.Now(),
+				Duration:    time.Since(stepStart),
+				Error:       err.Error(),
+				Warnings:    state.warnings,
+				Metadata:    state.metadata,
+			})
+			autoOutput.SetFailed()
+		}
+		return 0, fmt.Errorf("%s: %w", stepID, err)
+	}
+
+	cost := state.CostUSD
+	if cost == 0 {
+		cost = handler.EstimateCost()
+	}
+	if err := o.actionPlan.UpdateStepStatus(stepID, StepStatusCompleted); err != nil {
+		return 0, fmt.Errorf("update step status: %w", err)
+	}
+	if o.tracer != nil {
+		o.tracer.LogStepComplete(stepID, string(step.Type), step.Description, time.Since(stepStart), cost) //#nosec G104 -- Logging errors not critical
+	}
+	if autoOutput != nil {
+		autoOutput.AddStepResult(StepResult{
+			ID:          stepID,
+			Type:        string(step.Type),
+			Status:      "completed",
+			StartedAt:   stepStart,
+			CompletedAt: time.Now(),
+			Duration:    time.Since(stepStart),
+			CostUSD:     cost,
+			Warnings:    state.warnings,
+			Metadata:    state.metadata,
+		})
+	}
+	if step.Custom {
+		fmt.Printf("✅ Completed %s\n\n", handler.Type())
+	}
+
+	// Generate and save patch for the step
+	if err := o.generateAndSavePatch(ctx, stepID, string(step.Type), step.Description, snapshot); err != nil {
+		if isFatalPatchError(err) {
+			return 0, fmt.Errorf("%s: %w", stepID, err)
+		}
+		fmt.Printf("⚠️  Patch generation warning: %v\n", err)
+	}
+
+	return cost, nil
+}
+
+// executeStep runs a handler once, or once per spec feature for a
+// FeatureStep
+func executeStep(ctx context.Context, handler StepHandler, state *WorkflowState) error {
+	perFeature, ok := handler.(FeatureStep)
+	if !ok || !perFeature.PerFeature() || state.Spec == nil {
+		return handler.Execute(ctx, state)
+	}
+
+	defer func() { state.Feature = nil }()
+	for i := range state.Spec.Features {
+		state.Feature = &state.Spec.Features[i]
+		if err := handler.Execute(ctx, state); err != nil {
+			return fmt.Errorf("feature %s: %w", state.Feature.ID, err)
+		}
+	}
+	return nil
+}
+
+// stepIndex returns the position of a step in the action plan, or -1
+func (o *Orchestrator) stepIndex(stepID string) int {
+	for i := range o.actionPlan.Steps {
+		if o.actionPlan.Steps[i].ID == stepID {
+			return i
+		}
+	}
+	return -1
+}
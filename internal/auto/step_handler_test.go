@@ -0,0 +1,279 @@
+package auto
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// testStepHandler is a custom step with a configurable position and result
+type testStepHandler struct {
+	stepType StepType
+	after    StepType
+	cost     float64
+	err      error
+	ran      *[]StepType
+}
+
+func (h *testStepHandler) Type() StepType        { return h.stepType }
+func (h *testStepHandler) EstimateCost() float64 { return h.cost }
+
+func (h *testStepHandler) Execute(ctx context.Context, state *WorkflowState) error {
+	if h.ran != nil {
+		*h.ran = append(*h.ran, h.stepType)
+	}
+	return h.err
+}
+
+// positionedStepHandler is a testStepHandler that chooses its position
+type positionedStepHandler struct {
+	*testStepHandler
+}
+
+func (h positionedStepHandler) After() StepType { return h.after }
+
+func TestRegisterStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler StepHandler
+		wantErr string
+	}{
+		{"custom type", &testStepHandler{stepType: "design:review"}, ""},
+		{"nil handler", nil, "nil"},
+		{"empty type", &testStepHandler{}, "no type"},
+		{"built-in type", &testStepHandler{stepType: StepTypePlanGen}, "built in"},
+		{"duplicate type", &testStepHandler{stepType: "db:migrate"}, "already registered"},
+		{"built-in step ID", &testStepHandler{stepType: "4"}, "step ID step-4 is built in"},
+		{"duplicate step ID", &testStepHandler{stepType: "db/migrate"}, "already used by \"db:migrate\""},
+		{"unknown position", positionedStepHandler{&testStepHandler{stepType: "lint:run", after: "design:review"}}, "built-in step"},
+	}
+
+	o := NewOrchestrator(nil, DefaultConfig())
+	if err := o.RegisterStep(&testStepHandler{stepType: "db:migrate"}); err != nil {
+		t.Fatalf("RegisterStep: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := o.RegisterStep(tt.handler)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestComposeActionPlan(t *testing.T) {
+	handlers := []StepHandler{
+		positionedStepHandler{&testStepHandler{stepType: "design:review", after: StepTypePlanGen, cost: 0.2}},
+		positionedStepHandler{&testStepHandler{stepType: "db:migrate", after: StepTypePlanGen}},
+		&testStepHandler{stepType: "notify:team"},
+		positionedStepHandler{&testStepHandler{stepType: "report:publish", after: StepTypeVerify}},
+	}
+
+	t.Run("with verify", func(t *testing.T) {
+		plan, err := ComposeActionPlan("goal", "default", true, handlers)
+		if err != nil {
+			t.Fatalf("ComposeActionPlan: %v", err)
+		}
+
+		wantOrder := []string{"step-1", "step-2", "step-3", "step-design-review", "step-db-migrate", "step-4", "step-notify-team", "step-5", "step-report-publish"}
+		assertStepOrder(t, plan, wantOrder)
+
+		wantDeps := map[string]string{
+			"step-design-review":  "step-3",
+			"step-db-migrate":     "step-design-review",
+			"step-4":              "step-db-migrate",
+			"step-notify-team":    "step-4",
+			"step-5":              "step-notify-team",
+			"step-report-publish": "step-5",
+		}
+		for id, dep := range wantDeps {
+			step, _ := plan.GetStep(id)
+			if len(step.Dependencies) != 1 || step.Dependencies[0] != dep {
+				t.Errorf("%s depends on %v, want [%s]", id, step.Dependencies, dep)
+			}
+		}
+
+		review, _ := plan.GetStep("step-design-review")
+		if !review.Custom || review.EstimatedCostUSD != 0.2 || review.Status != StepStatusPending {
+			t.Errorf("unexpected custom step: %+v", review)
+		}
+		if err := plan.Validate(); err != nil {
+			t.Errorf("composed plan failed validation: %v", err)
+		}
+	})
+
+	t.Run("without verify", func(t *testing.T) {
+		plan, err := ComposeActionPlan("goal", "default", false, handlers)
+		if err != nil {
+			t.Fatalf("ComposeActionPlan: %v", err)
+		}
+
+		// Steps after verify:run follow build:run instead
+		wantOrder := []string{"step-1", "step-2", "step-3", "step-design-review", "step-db-migrate", "step-4", "step-notify-team", "step-report-publish"}
+		assertStepOrder(t, plan, wantOrder)
+	})
+}
+
+func assertStepOrder(t *testing.T, plan *ActionPlan, want []string) {
+	t.Helper()
+	var got []string
+	for _, step := range plan.Steps {
+		got = append(got, step.ID)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("step order = %v, want %v", got, want)
+	}
+}
+
+func TestRunStep(t *testing.T) {
+	var ran []StepType
+	config := DefaultConfig()
+	checker := &mockPolicyChecker{}
+	o := NewOrchestrator(nil, config)
+	o.SetPolicyChecker(checker)
+	for _, handler := range []StepHandler{
+		positionedStepHandler{&testStepHandler{stepType: "design:review", after: StepTypeSpecUpdate, cost: 0.25, ran: &ran}},
+		&testStepHandler{stepType: "notify:team", ran: &ran},
+	} {
+		if err := o.RegisterStep(handler); err != nil {
+			t.Fatalf("RegisterStep: %v", err)
+		}
+	}
+
+	plan, err := ComposeActionPlan("goal", "default", false, o.customSteps)
+	if err != nil {
+		t.Fatalf("ComposeActionPlan: %v", err)
+	}
+	o.actionPlan = plan
+	output := NewAutoOutput("goal", "default")
+
+	cost, err := o.runStep(context.Background(), "step-design-review", o.customSteps[0], &WorkflowState{autoOutput: output}, 1, 0.5, time.Now())
+	if err != nil {
+		t.Fatalf("runStep: %v", err)
+	}
+
+	if len(ran) != 1 || ran[0] != "design:review" {
+		t.Errorf("ran %v, want [design:review]", ran)
+	}
+	if cost != 0.25 {
+		t.Errorf("cost = %.2f, want the estimate 0.25", cost)
+	}
+	if checker.checkCallCount != 1 {
+		t.Errorf("policy checked %d times, want 1", checker.checkCallCount)
+	}
+
+	step, _ := plan.GetStep("step-design-review")
+	if step.Status != StepStatusCompleted {
+		t.Errorf("status = %s, want completed", step.Status)
+	}
+	if len(output.Steps) != 1 || output.Steps[0].ID != "step-design-review" || output.Steps[0].CostUSD != 0.25 {
+		t.Errorf("unexpected step results: %+v", output.Steps)
+	}
+	if len(output.Audit.Policies) != 1 || output.Audit.Policies[0].StepID != "step-design-review" {
+		t.Errorf("unexpected policy events: %+v", output.Audit.Policies)
+	}
+}
+
+func TestRunStep_Failure(t *testing.T) {
+	tests := []struct {
+		name       string
+		handlerErr error
+		allowed    bool
+		wantErr    string
+		wantStatus StepStatus
+	}{
+		{"step fails", errors.New("migration failed"), true, "step-db-migrate: migration failed", StepStatusFailed},
+		{"blocked by policy", nil, false, "step-db-migrate blocked by policy: not allowed", StepStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOrchestrator(nil, DefaultConfig())
+			o.SetPolicyChecker(&mockPolicyChecker{
+				checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+					return &PolicyResult{Allowed: tt.allowed, Reason: "not allowed"}, nil
+				},
+			})
+			if err := o.RegisterStep(&testStepHandler{stepType: "db:migrate", err: tt.handlerErr}); err != nil {
+				t.Fatalf("RegisterStep: %v", err)
+			}
+			plan, err := ComposeActionPlan("goal", "default", false, o.customSteps)
+			if err != nil {
+				t.Fatalf("ComposeActionPlan: %v", err)
+			}
+			o.actionPlan = plan
+
+			_, err = o.runStep(context.Background(), "step-db-migrate", o.customSteps[0], &WorkflowState{}, 0, 0, time.Now())
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			step, _ := plan.GetStep("step-db-migrate")
+			if step.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", step.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// reviewStepHandler asks the router to review each feature
+type reviewStepHandler struct {
+	reviewed []string
+}
+
+func (h *reviewStepHandler) Type() StepType        { return "design:review" }
+func (h *reviewStepHandler) EstimateCost() float64 { return 0 }
+func (h *reviewStepHandler) After() StepType       { return StepTypeSpecLock }
+func (h *reviewStepHandler) PerFeature() bool      { return true }
+
+func (h *reviewStepHandler) Execute(ctx context.Context, state *WorkflowState) error {
+	resp, err := state.Generate(ctx, router.GenerateRequest{
+		Prompt:     "Review the design of " + state.Feature.Title,
+		ModelHint:  "agentic",
+		Complexity: 3,
+		Priority:   "P1",
+	})
+	if err != nil {
+		return err
+	}
+	h.reviewed = append(h.reviewed, string(state.Feature.ID))
+	state.CostUSD += resp.CostUSD
+	return nil
+}
+
+func TestFeatureStep_AttributesCostPerFeature(t *testing.T) {
+	o := newEstimateTestOrchestrator(t, Config{Goal: "Build a todo API", DryRun: true, CheckpointStore: t.TempDir()})
+	handler := &reviewStepHandler{}
+	if err := o.RegisterStep(handler); err != nil {
+		t.Fatalf("RegisterStep: %v", err)
+	}
+
+	if _, err := o.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if strings.Join(handler.reviewed, ",") != "todo-crud,todo-search" {
+		t.Errorf("reviewed features = %v, want todo-crud and todo-search", handler.reviewed)
+	}
+	byFeature := o.router.GetCostByFeature()
+	for _, feature := range []types.FeatureID{"todo-crud", "todo-search"} {
+		if byFeature[feature] <= 0 {
+			t.Errorf("cost of %s = %v, want it attributed", feature, byFeature[feature])
+		}
+	}
+	if byStep := o.router.GetCostByStep(); byStep["design:review"] <= 0 {
+		t.Errorf("cost by step = %v, want design:review", byStep)
+	}
+}
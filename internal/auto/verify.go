@@ -0,0 +1,19 @@
+package auto
+
+import (
+	"fmt"
+)
+
+// rollbackStep reverts the patch saved for a step
+func (o *Orchestrator) rollbackStep(stepID string) error {
+	if o.patchRollback == nil {
+		return fmt.Errorf("patch generation is not enabled (use --save-patches)")
+	}
+
+	workflowID := o.patchWorkflowID()
+	if !o.patchWriter.PatchExists(workflowID, stepID) {
+		return fmt.Errorf("no patch saved for %s", stepID)
+	}
+
+	return o.patchRollback.RollbackStep(workflowID, stepID)
+}
@@ -0,0 +1,91 @@
+package auto
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunVerifyStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		commands     []string
+		rollback     bool
+		wantErr      bool
+		wantStatus   StepStatus
+		wantWarnings int
+	}{
+		{"passing checks", []string{"true"}, false, false, StepStatusCompleted, 0},
+		{"failing check", []string{"true", "false"}, false, true, StepStatusFailed, 0},
+		{"rollback without patches", []string{"false"}, true, true, StepStatusFailed, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Verify = true
+			config.VerifyCommands = tt.commands
+			config.RollbackOnVerifyFailure = tt.rollback
+
+			orchestrator := NewOrchestrator(nil, config)
+			orchestrator.actionPlan = CreateDefaultActionPlan("goal", "default")
+			AddVerifyStep(orchestrator.actionPlan)
+			output := NewAutoOutput("goal", "default")
+
+			state := &WorkflowState{result: &Result{}, autoOutput: output}
+			handler := orchestrator.builtinSteps(state)[StepTypeVerify]
+			_, err := orchestrator.runStep(context.Background(), "step-5", handler, state, 4, 0, time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runStep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			report := state.result.EvalResult
+			if report == nil || len(report.Checks) != len(tt.commands) {
+				t.Fatalf("expected %d checks in report, got %+v", len(tt.commands), report)
+			}
+
+			step, _ := orchestrator.actionPlan.GetStep("step-5")
+			if step.Status != tt.wantStatus {
+				t.Errorf("step-5 status = %s, want %s", step.Status, tt.wantStatus)
+			}
+
+			if len(output.Steps) != 1 {
+				t.Fatalf("expected 1 step result, got %d", len(output.Steps))
+			}
+			if got := len(output.Steps[0].Warnings); got != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestVerifyStep_BlockedByPolicy(t *testing.T) {
+	config := DefaultConfig()
+	config.Verify = true
+	config.VerifyCommands = []string{"false"}
+
+	orchestrator := NewOrchestrator(nil, config)
+	orchestrator.actionPlan = CreateDefaultActionPlan("goal", "default")
+	AddVerifyStep(orchestrator.actionPlan)
+	checker := &mockPolicyChecker{
+		checkFunc: func(ctx context.Context, step *ActionStep) (*PolicyResult, error) {
+			return &PolicyResult{Allowed: step.Type != StepTypeVerify, Reason: "verification disabled"}, nil
+		},
+	}
+	orchestrator.SetPolicyChecker(checker)
+
+	state := &WorkflowState{result: &Result{}}
+	handler := orchestrator.builtinSteps(state)[StepTypeVerify]
+	_, err := orchestrator.runStep(context.Background(), "step-5", handler, state, 4, 0, time.Now())
+	if err == nil || err.Error() != "step-5 blocked by policy: verification disabled" {
+		t.Fatalf("runStep() error = %v, want the step blocked", err)
+	}
+	if checker.checkCallCount != 1 {
+		t.Errorf("policy checked %d times, want 1", checker.checkCallCount)
+	}
+	if state.result.EvalResult != nil {
+		t.Error("verify checks ran despite the policy denial")
+	}
+	if step, _ := orchestrator.actionPlan.GetStep("step-5"); step.Status != StepStatusPending {
+		t.Errorf("step-5 status = %s, want pending", step.Status)
+	}
+}
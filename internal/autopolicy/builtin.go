@@ -3,6 +3,8 @@ package autopolicy
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/auto"
@@ -30,6 +32,7 @@ func NewCostLimitChecker(maxTotal, maxPerStep float64) *CostLimitChecker {
 			auto.StepTypeSpecLock:   0.01, // Locking is cheap
 			auto.StepTypePlanGen:    0.30, // Plan generation ~$0.30
 			auto.StepTypeBuildRun:   1.00, // Build varies, conservative estimate
+			auto.StepTypeVerify:     0.00, // Verification runs local commands only
 		},
 	}
 }
@@ -46,11 +49,20 @@ func (c *CostLimitChecker) CheckStep(ctx context.Context, step *auto.ActionStep)
 	return c.checkCostWithContext(step, policyCtx)
 }
 
-func (c *CostLimitChecker) checkBasicCost(step *auto.ActionStep) (*PolicyResult, error) {
-	estimatedCost := c.EstimatedCostPerStep[step.Type]
-	if estimatedCost == 0 {
-		estimatedCost = 0.50 // Default estimate
+// estimateStepCost returns the estimate for the step's type, then the
+// step's own estimate (set for custom steps), then a default of $0.50
+func (c *CostLimitChecker) estimateStepCost(step *auto.ActionStep) float64 {
+	if cost := c.EstimatedCostPerStep[step.Type]; cost != 0 {
+		return cost
+	}
+	if step.EstimatedCostUSD != 0 {
+		return step.EstimatedCostUSD
 	}
+	return 0.50 // Default estimate
+}
+
+func (c *CostLimitChecker) checkBasicCost(step *auto.ActionStep) (*PolicyResult, error) {
+	estimatedCost := c.estimateStepCost(step)
 
 	// Check per-step limit
 	if c.MaxPerStepCost > 0 && estimatedCost > c.MaxPerStepCost {
@@ -66,10 +78,7 @@ func (c *CostLimitChecker) checkBasicCost(step *auto.ActionStep) (*PolicyResult,
 }
 
 func (c *CostLimitChecker) checkCostWithContext(step *auto.ActionStep, policyCtx *PolicyContext) (*PolicyResult, error) {
-	estimatedCost := c.EstimatedCostPerStep[step.Type]
-	if estimatedCost == 0 {
-		estimatedCost = 0.50
-	}
+	estimatedCost := c.estimateStepCost(step)
 
 	// Check per-step limit
 	if c.MaxPerStepCost > 0 && estimatedCost > c.MaxPerStepCost {
@@ -112,6 +121,60 @@ func (c *CostLimitChecker) Name() string {
 	return "cost_limit"
 }
 
+// CostCircuitBreaker stops the workflow once the actual cumulative spend
+// reaches the budget. Unlike CostLimitChecker it ignores step estimates, so
+// it still trips when those estimates are inaccurate.
+type CostCircuitBreaker struct {
+	// MaxTotalCost is the budget at which further steps are denied.
+	MaxTotalCost float64
+
+	// WarnFraction is the fraction of the budget at which steps are allowed
+	// with a warning (0 = no warning).
+	WarnFraction float64
+}
+
+// NewCostCircuitBreaker creates a circuit breaker for a budget.
+func NewCostCircuitBreaker(maxTotal, warnFraction float64) *CostCircuitBreaker {
+	return &CostCircuitBreaker{
+		MaxTotalCost: maxTotal,
+		WarnFraction: warnFraction,
+	}
+}
+
+// CheckStep validates the cumulative spend against the budget.
+func (c *CostCircuitBreaker) CheckStep(ctx context.Context, step *auto.ActionStep) (*PolicyResult, error) {
+	policyCtx, ok := ctx.Value("policy_context").(*PolicyContext)
+	if !ok || c.MaxTotalCost <= 0 {
+		return NewAllowedResult(), nil
+	}
+
+	spent := policyCtx.TotalCostSoFar
+	if spent >= c.MaxTotalCost {
+		return NewDeniedResult(fmt.Sprintf(
+			"cumulative cost limit reached: $%.2f spent of $%.2f budget",
+			spent, c.MaxTotalCost,
+		)), nil
+	}
+
+	result := NewAllowedResult()
+	used := spent / c.MaxTotalCost
+	if c.WarnFraction > 0 && used >= c.WarnFraction {
+		result.AddWarning(fmt.Sprintf(
+			"Cumulative cost at %.0f%% of budget: $%.2f spent of $%.2f",
+			used*100, spent, c.MaxTotalCost,
+		))
+	}
+
+	result.SetMetadata("cumulative_cost", spent)
+	result.SetMetadata("budget_used", used)
+	return result, nil
+}
+
+// Name returns the checker name.
+func (c *CostCircuitBreaker) Name() string {
+	return "cost_circuit_breaker"
+}
+
 // TimeoutChecker enforces workflow timeout constraints.
 type TimeoutChecker struct {
 	// MaxDuration is the maximum allowed workflow duration.
@@ -324,3 +387,174 @@ func (m *MaxRetriesChecker) RecordRetry(stepID string) {
 func (m *MaxRetriesChecker) Name() string {
 	return "max_retries"
 }
+
+// ExecutionWindowChecker only allows steps during configured days and hours,
+// such as Mon-Fri 09:00-18:00 in a named timezone.
+type ExecutionWindowChecker struct {
+	// Days lists the allowed weekdays (empty = every day).
+	Days []time.Weekday
+
+	// Start and End are the window's times of day, as offsets from midnight.
+	Start time.Duration
+	End   time.Duration
+
+	// Location is the timezone the window is defined in.
+	Location *time.Location
+
+	now func() time.Time
+}
+
+// NewExecutionWindowChecker creates a checker with an execution window.
+func NewExecutionWindowChecker(days []time.Weekday, start, end time.Duration, loc *time.Location) *ExecutionWindowChecker {
+	return &ExecutionWindowChecker{
+		Days:     days,
+		Start:    start,
+		End:      end,
+		Location: loc,
+		now:      time.Now,
+	}
+}
+
+// CheckStep validates that the current time falls inside the window.
+func (e *ExecutionWindowChecker) CheckStep(ctx context.Context, step *auto.ActionStep) (*PolicyResult, error) {
+	now := e.now().In(e.Location)
+
+	if e.allowsDay(now.Weekday()) {
+		opens, closes := e.windowOn(now, 0)
+		if !now.Before(opens) && now.Before(closes) {
+			result := NewAllowedResult()
+			result.SetMetadata("window_closes_at", closes.Format(time.RFC3339))
+			return result, nil
+		}
+	}
+
+	next := e.nextOpening(now)
+	result := NewDeniedResult(fmt.Sprintf(
+		"execution not allowed outside window %s; next allowed at %s",
+		e.describe(), next.Format(time.RFC3339),
+	))
+	result.SetMetadata("next_allowed_time", next.Format(time.RFC3339))
+	return result, nil
+}
+
+// windowOn returns when the window opens and closes on the day offset days
+// from now. Times are built from wall-clock fields in the window's
+// location, so they stay correct across daylight saving transitions.
+func (e *ExecutionWindowChecker) windowOn(now time.Time, offset int) (opens, closes time.Time) {
+	year, month, day := now.Date()
+	at := func(d time.Duration) time.Time {
+		return time.Date(year, month, day+offset, int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, e.Location)
+	}
+	return at(e.Start), at(e.End)
+}
+
+// nextOpening returns the next time the window opens after now
+func (e *ExecutionWindowChecker) nextOpening(now time.Time) time.Time {
+	year, month, day := now.Date()
+	for offset := 0; offset <= 7; offset++ {
+		noon := time.Date(year, month, day+offset, 12, 0, 0, 0, e.Location)
+		if !e.allowsDay(noon.Weekday()) {
+			continue
+		}
+		if opens, _ := e.windowOn(now, offset); opens.After(now) {
+			return opens
+		}
+	}
+	return now // Unreachable with at least one allowed day
+}
+
+func (e *ExecutionWindowChecker) allowsDay(day time.Weekday) bool {
+	if len(e.Days) == 0 {
+		return true
+	}
+	for _, allowed := range e.Days {
+		if allowed == day {
+			return true
+		}
+	}
+	return false
+}
+
+// describe formats the window, for example "Mon,Tue 09:00-18:00 Europe/Berlin"
+func (e *ExecutionWindowChecker) describe() string {
+	days := "daily"
+	if len(e.Days) > 0 {
+		names := make([]string, len(e.Days))
+		for i, day := range e.Days {
+			names[i] = day.String()[:3]
+		}
+		days = strings.Join(names, ",")
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%s %s-%s %s", days, clock(e.Start), clock(e.End), e.Location)
+}
+
+// Name returns the checker name.
+func (e *ExecutionWindowChecker) Name() string {
+	return "execution_window"
+}
+
+// ChangedPathsChecker denies steps whose file changes touch blocked paths,
+// such as infrastructure code. It only applies once a step has produced a
+// patch; before that the step's changes are unknown.
+type ChangedPathsChecker struct {
+	// BlockedPaths are glob patterns (see filepath.Match) matched against
+	// each changed path and its parent directories, so "infra" and
+	// "infra/*" both block every file under infra/.
+	BlockedPaths []string
+}
+
+// NewChangedPathsChecker creates a checker with blocked path patterns.
+func NewChangedPathsChecker(blockedPaths []string) *ChangedPathsChecker {
+	return &ChangedPathsChecker{
+		BlockedPaths: blockedPaths,
+	}
+}
+
+// CheckStep validates the step's changed paths.
+func (c *ChangedPathsChecker) CheckStep(ctx context.Context, step *auto.ActionStep) (*PolicyResult, error) {
+	policyCtx, ok := ctx.Value("policy_context").(*PolicyContext)
+	if !ok || len(policyCtx.Changes) == 0 {
+		return NewAllowedResult(), nil
+	}
+
+	var blocked []string
+	for _, path := range policyCtx.ChangedPaths() {
+		if c.isBlocked(path) {
+			blocked = append(blocked, path)
+		}
+	}
+
+	if len(blocked) > 0 {
+		result := NewDeniedResult(fmt.Sprintf(
+			"changes not allowed to blocked paths: %s",
+			strings.Join(blocked, ", "),
+		))
+		result.SetMetadata("blocked_paths", blocked)
+		return result, nil
+	}
+
+	result := NewAllowedResult()
+	result.SetMetadata("changed_files", len(policyCtx.Changes))
+	return result, nil
+}
+
+// isBlocked reports whether a path or one of its parent directories
+// matches a blocked pattern
+func (c *ChangedPathsChecker) isBlocked(path string) bool {
+	for p := filepath.Clean(path); p != "." && p != "/"; p = filepath.Dir(p) {
+		for _, pattern := range c.BlockedPaths {
+			if matched, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), p); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Name returns the checker name.
+func (c *ChangedPathsChecker) Name() string {
+	return "changed_paths"
+}
@@ -2,10 +2,12 @@ package autopolicy
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/patch"
 )
 
 func TestCostLimitChecker(t *testing.T) {
@@ -89,6 +91,70 @@ func TestCostLimitChecker(t *testing.T) {
 	})
 }
 
+func TestCostCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name        string
+		spent       float64
+		wantAllowed bool
+		wantWarning bool
+	}{
+		{"below warning threshold", 0.50, true, false},
+		{"at warning threshold", 0.80, true, true},
+		{"above warning threshold", 0.95, true, true},
+		{"at budget", 1.00, false, false},
+		{"over budget", 1.30, false, false},
+	}
+
+	step := &auto.ActionStep{ID: "test-1", Type: auto.StepTypeBuildRun}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewCostCircuitBreaker(1.0, 0.8)
+			policyCtx := &PolicyContext{CurrentStep: step, TotalCostSoFar: tt.spent}
+			ctx := context.WithValue(context.Background(), "policy_context", policyCtx)
+
+			result, err := checker.CheckStep(ctx, step)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (reason: %s)", result.Allowed, tt.wantAllowed, result.Reason)
+			}
+			if !tt.wantAllowed && !strings.HasPrefix(result.Reason, "cumulative cost limit reached") {
+				t.Errorf("Reason = %q, want cumulative cost limit reached", result.Reason)
+			}
+			if got := len(result.Warnings) > 0; got != tt.wantWarning {
+				t.Errorf("warnings = %v, want warning %v", result.Warnings, tt.wantWarning)
+			}
+		})
+	}
+
+	t.Run("ignores step estimates", func(t *testing.T) {
+		// A step estimated far over budget is left to CostLimitChecker
+		checker := NewCostCircuitBreaker(1.0, 0.8)
+		step := &auto.ActionStep{ID: "test-1", Type: "db:migrate", EstimatedCostUSD: 5.0}
+		ctx := context.WithValue(context.Background(), "policy_context", &PolicyContext{CurrentStep: step})
+
+		result, err := checker.CheckStep(ctx, step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Errorf("expected step to be allowed, got denied: %s", result.Reason)
+		}
+	})
+
+	t.Run("allows without context", func(t *testing.T) {
+		checker := NewCostCircuitBreaker(1.0, 0.8)
+		result, err := checker.CheckStep(context.Background(), step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Errorf("expected step to be allowed, got denied: %s", result.Reason)
+		}
+	})
+}
+
 func TestTimeoutChecker(t *testing.T) {
 	t.Run("allows step with sufficient time", func(t *testing.T) {
 		checker := NewTimeoutChecker(30*time.Minute, 5*time.Minute)
@@ -384,6 +450,146 @@ func TestMaxRetriesChecker(t *testing.T) {
 	})
 }
 
+func TestExecutionWindowChecker(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantAllowed bool
+		wantNext    string
+	}{
+		{"inside window", time.Date(2026, 10, 21, 10, 30, 0, 0, berlin), true, ""},
+		{"at window start", time.Date(2026, 10, 21, 9, 0, 0, 0, berlin), true, ""},
+		{"at window end", time.Date(2026, 10, 21, 18, 0, 0, 0, berlin), false, "2026-10-22T09:00:00+02:00"},
+		{"before window", time.Date(2026, 10, 21, 7, 0, 0, 0, berlin), false, "2026-10-21T09:00:00+02:00"},
+		{"other timezone inside window", time.Date(2026, 10, 21, 8, 0, 0, 0, time.UTC), true, ""},
+		{"weekend", time.Date(2026, 10, 17, 12, 0, 0, 0, berlin), false, "2026-10-19T09:00:00+02:00"},
+		// Daylight saving time ends on Sunday 2026-10-25
+		{"across DST end", time.Date(2026, 10, 23, 19, 0, 0, 0, berlin), false, "2026-10-26T09:00:00+01:00"},
+		// Daylight saving time starts on Sunday 2026-03-29
+		{"across DST start", time.Date(2026, 3, 27, 18, 30, 0, 0, berlin), false, "2026-03-30T09:00:00+02:00"},
+		{"after DST start", time.Date(2026, 3, 30, 7, 30, 0, 0, time.UTC), true, ""},
+	}
+
+	step := &auto.ActionStep{ID: "test-1", Type: auto.StepTypeBuildRun}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewExecutionWindowChecker(weekdays, 9*time.Hour, 18*time.Hour, berlin)
+			checker.now = func() time.Time { return tt.now }
+
+			result, err := checker.CheckStep(context.Background(), step)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (reason: %s)", result.Allowed, tt.wantAllowed, result.Reason)
+			}
+			if tt.wantAllowed {
+				return
+			}
+			if next := result.Metadata["next_allowed_time"]; next != tt.wantNext {
+				t.Errorf("next_allowed_time = %v, want %s", next, tt.wantNext)
+			}
+			wantReason := "execution not allowed outside window Mon,Tue,Wed,Thu,Fri 09:00-18:00 Europe/Berlin; next allowed at " + tt.wantNext
+			if result.Reason != wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, wantReason)
+			}
+		})
+	}
+
+	t.Run("every day when no days configured", func(t *testing.T) {
+		checker := NewExecutionWindowChecker(nil, 22*time.Hour, 23*time.Hour, time.UTC)
+		checker.now = func() time.Time { return time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC) }
+
+		result, err := checker.CheckStep(context.Background(), step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Allowed {
+			t.Fatal("expected step to be denied, got allowed")
+		}
+		if next := result.Metadata["next_allowed_time"]; next != "2026-10-18T22:00:00Z" {
+			t.Errorf("next_allowed_time = %v, want 2026-10-18T22:00:00Z", next)
+		}
+	})
+}
+
+func TestChangedPathsChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		blocked     []string
+		changes     []patch.FilePatch
+		wantAllowed bool
+		wantPaths   []string
+	}{
+		{
+			name:        "no changes yet",
+			blocked:     []string{"infra"},
+			wantAllowed: true,
+		},
+		{
+			name:        "changes outside blocked paths",
+			blocked:     []string{"infra"},
+			changes:     []patch.FilePatch{{Path: "internal/api/handler.go"}, {Path: "infrastructure.md"}},
+			wantAllowed: true,
+		},
+		{
+			name:        "file under blocked directory",
+			blocked:     []string{"/infra"},
+			changes:     []patch.FilePatch{{Path: "README.md"}, {Path: "infra/prod/main.tf"}, {Path: "infra/vars.tf"}},
+			wantAllowed: false,
+			wantPaths:   []string{"infra/prod/main.tf", "infra/vars.tf"},
+		},
+		{
+			name:        "file glob",
+			blocked:     []string{"*.tf"},
+			changes:     []patch.FilePatch{{Path: "main.tf"}},
+			wantAllowed: false,
+			wantPaths:   []string{"main.tf"},
+		},
+		{
+			name:        "rename out of blocked directory",
+			blocked:     []string{"infra/*"},
+			changes:     []patch.FilePatch{{Path: "legacy/main.tf", OldPath: "infra/main.tf", Status: patch.FileStatusRenamed}},
+			wantAllowed: false,
+			wantPaths:   []string{"infra/main.tf"},
+		},
+	}
+
+	step := &auto.ActionStep{ID: "step-4", Type: auto.StepTypeBuildRun}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChangedPathsChecker(tt.blocked)
+			policyCtx := &PolicyContext{CurrentStep: step, Changes: tt.changes}
+			ctx := context.WithValue(context.Background(), "policy_context", policyCtx)
+
+			result, err := checker.CheckStep(ctx, step)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (reason: %s)", result.Allowed, tt.wantAllowed, result.Reason)
+			}
+			if tt.wantAllowed {
+				return
+			}
+			for _, path := range tt.wantPaths {
+				if !strings.Contains(result.Reason, path) {
+					t.Errorf("Reason = %q, want it to cite %s", result.Reason, path)
+				}
+			}
+			if got := result.Metadata["blocked_paths"].([]string); len(got) != len(tt.wantPaths) {
+				t.Errorf("blocked_paths = %v, want %v", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
 func TestCompositeChecker(t *testing.T) {
 	t.Run("allows when all checkers pass", func(t *testing.T) {
 		composite := NewCompositeChecker(
@@ -441,6 +647,27 @@ func TestCompositeChecker(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps metadata of the denying checker", func(t *testing.T) {
+		window := NewExecutionWindowChecker(nil, 9*time.Hour, 18*time.Hour, time.UTC)
+		window.now = func() time.Time { return time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC) }
+		composite := NewCompositeChecker(NewMaxStepsChecker(10), window)
+
+		step := &auto.ActionStep{ID: "test-1", Type: auto.StepTypeBuildRun}
+		result, err := composite.CheckStep(context.Background(), step)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Allowed {
+			t.Fatal("expected step to be denied, got allowed")
+		}
+		if !strings.HasPrefix(result.Reason, "[execution_window] ") {
+			t.Errorf("Reason = %q, want [execution_window] prefix", result.Reason)
+		}
+		if next := result.Metadata["next_allowed_time"]; next != "2026-10-20T09:00:00Z" {
+			t.Errorf("next_allowed_time = %v, want 2026-10-20T09:00:00Z", next)
+		}
+	})
+
 	t.Run("collects warnings from all checkers", func(t *testing.T) {
 		composite := NewCompositeChecker(
 			NewCostLimitChecker(1.0, 2.0),                    // Will warn about approaching limit
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/patch"
 )
 
 // PolicyChecker defines the interface for policy enforcement.
@@ -98,7 +99,11 @@ func (c *CompositeChecker) CheckStep(ctx context.Context, step *auto.ActionStep)
 
 		// If any checker denies, deny the entire step
 		if !checkResult.Allowed {
-			return NewDeniedResult(fmt.Sprintf("[%s] %s", checker.Name(), checkResult.Reason)), nil
+			denied := NewDeniedResult(fmt.Sprintf("[%s] %s", checker.Name(), checkResult.Reason))
+			for k, v := range checkResult.Metadata {
+				denied.SetMetadata(k, v)
+			}
+			return denied, nil
 		}
 
 		// Merge metadata
@@ -137,6 +142,14 @@ type PolicyContext struct {
 
 	// FailedSteps is the number of steps that failed.
 	FailedSteps int
+
+	// Snapshot holds the working directory's files before the step, keyed
+	// by relative path. It is nil when patch generation is disabled.
+	Snapshot map[string]string
+
+	// Changes are the file changes the step produced. They are only set
+	// when the orchestrator's change checker runs after the step.
+	Changes []patch.FilePatch
 }
 
 // NewPolicyContext creates a policy context for step evaluation.
@@ -157,6 +170,19 @@ func (c *PolicyContext) ElapsedTime() time.Duration {
 	return time.Since(c.ExecutionStartTime)
 }
 
+// ChangedPaths returns the paths the step's changes touch, including the
+// old path of renamed files.
+func (c *PolicyContext) ChangedPaths() []string {
+	var paths []string
+	for _, change := range c.Changes {
+		paths = append(paths, change.Path)
+		if change.OldPath != "" && change.OldPath != change.Path {
+			paths = append(paths, change.OldPath)
+		}
+	}
+	return paths
+}
+
 // RemainingSteps returns the number of pending steps.
 func (c *PolicyContext) RemainingSteps() int {
 	if c.Plan == nil {
@@ -2,12 +2,17 @@ package autopolicy
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/auto"
 	"github.com/felixgeelhaar/specular/internal/profiles"
 )
 
+// defaultCostWarningFraction is the share of the budget spent at which the
+// cost circuit breaker starts warning, unless the profile sets one.
+const defaultCostWarningFraction = 0.8
+
 // NewCheckerFromProfile creates a composite policy checker from a profile.
 // This integrates the profile system with per-step policy enforcement.
 func NewCheckerFromProfile(profile *profiles.Profile) PolicyChecker {
@@ -20,6 +25,17 @@ func NewCheckerFromProfile(profile *profiles.Profile) PolicyChecker {
 		)
 	}
 
+	// Add cumulative cost circuit breaker
+	if profile.Safety.MaxCostUSD > 0 {
+		warnFraction := profile.Safety.CostWarningFraction
+		if warnFraction == 0 {
+			warnFraction = defaultCostWarningFraction
+		}
+		checkers = append(checkers,
+			NewCostCircuitBreaker(profile.Safety.MaxCostUSD, warnFraction),
+		)
+	}
+
 	// Add timeout checker
 	if profile.Safety.Timeout > 0 {
 		// Per-step timeout can be estimated as total/max_steps
@@ -64,6 +80,18 @@ func NewCheckerFromProfile(profile *profiles.Profile) PolicyChecker {
 		)
 	}
 
+	// Add changed paths checker
+	if len(profile.Safety.BlockedPaths) > 0 {
+		checkers = append(checkers,
+			NewChangedPathsChecker(profile.Safety.BlockedPaths),
+		)
+	}
+
+	// Add execution window checker
+	if window := profile.Safety.ExecutionWindow; window != nil {
+		checkers = append(checkers, newExecutionWindowChecker(window))
+	}
+
 	// If no checkers configured, return a permissive checker
 	if len(checkers) == 0 {
 		return &PermissiveChecker{}
@@ -72,6 +100,35 @@ func NewCheckerFromProfile(profile *profiles.Profile) PolicyChecker {
 	return NewCompositeChecker(checkers...)
 }
 
+// newExecutionWindowChecker creates a checker from a profile's execution
+// window. Profiles are validated when loaded; an invalid window denies every
+// step rather than silently allowing execution at any time.
+func newExecutionWindowChecker(window *profiles.ExecutionWindow) PolicyChecker {
+	if err := window.Validate(); err != nil {
+		return &denyingChecker{name: "execution_window", reason: fmt.Sprintf("invalid execution window: %v", err)}
+	}
+	days, _ := window.Weekdays()
+	start, end, _ := window.Hours()
+	loc, _ := window.Location()
+	return NewExecutionWindowChecker(days, start, end, loc)
+}
+
+// denyingChecker denies all steps with a fixed reason.
+type denyingChecker struct {
+	name   string
+	reason string
+}
+
+// CheckStep always denies execution.
+func (d *denyingChecker) CheckStep(ctx context.Context, step *auto.ActionStep) (*PolicyResult, error) {
+	return NewDeniedResult(d.reason), nil
+}
+
+// Name returns the checker name.
+func (d *denyingChecker) Name() string {
+	return d.name
+}
+
 // PermissiveChecker allows all steps (used when no policies are configured).
 type PermissiveChecker struct{}
 
@@ -0,0 +1,198 @@
+package autopolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/felixgeelhaar/specular/internal/auto"
+)
+
+// DefaultRegoQuery is the Rego document a policy defines to gate steps.
+const DefaultRegoQuery = "data.specular.auto"
+
+// RegoChecker evaluates a Rego policy file with the opa CLI. The policy
+// receives a RegoInput document and defines, under DefaultRegoQuery:
+//
+//	allow  boolean; required, false denies the step
+//	deny   set of strings; any message denies the step
+//	warn   set of strings; warnings reported for allowed steps
+//	reason string; denial reason when allow is false without deny messages
+type RegoChecker struct {
+	// PolicyFile is the Rego policy file to evaluate.
+	PolicyFile string
+
+	// Query is the document holding the decision.
+	Query string
+
+	// OPAPath is the path of the opa executable.
+	OPAPath string
+}
+
+// NewRegoChecker creates a checker for a Rego policy file.
+func NewRegoChecker(policyFile string) (*RegoChecker, error) {
+	if _, err := os.Stat(policyFile); err != nil {
+		return nil, fmt.Errorf("rego policy: %w", err)
+	}
+
+	opaPath, err := exec.LookPath("opa")
+	if err != nil {
+		return nil, fmt.Errorf("rego policy requires the opa CLI (https://www.openpolicyagent.org/docs/latest/#running-opa): %w", err)
+	}
+
+	return &RegoChecker{
+		PolicyFile: policyFile,
+		Query:      DefaultRegoQuery,
+		OPAPath:    opaPath,
+	}, nil
+}
+
+// RegoInput is the input document a Rego policy evaluates.
+type RegoInput struct {
+	// Step is the step about to run, or that just ran when Context.ChangedPaths is set.
+	Step *auto.ActionStep `json:"step"`
+
+	// Context is the workflow's execution state.
+	Context RegoContext `json:"context"`
+}
+
+// RegoContext is the execution state in the Rego input document.
+type RegoContext struct {
+	Goal           string   `json:"goal"`
+	Profile        string   `json:"profile"`
+	StepIndex      int      `json:"stepIndex"`
+	TotalSteps     int      `json:"totalSteps"`
+	CompletedSteps int      `json:"completedSteps"`
+	FailedSteps    int      `json:"failedSteps"`
+	TotalCostUSD   float64  `json:"totalCostUsd"`
+	ElapsedSeconds float64  `json:"elapsedSeconds"`
+	ChangedPaths   []string `json:"changedPaths"`
+}
+
+// NewRegoInput builds the input document for a step. policyCtx may be nil.
+func NewRegoInput(step *auto.ActionStep, policyCtx *PolicyContext) *RegoInput {
+	input := &RegoInput{
+		Step: step,
+		Context: RegoContext{
+			ChangedPaths: []string{},
+		},
+	}
+	if policyCtx == nil {
+		return input
+	}
+
+	input.Context.StepIndex = policyCtx.StepIndex
+	input.Context.CompletedSteps = policyCtx.CompletedSteps
+	input.Context.FailedSteps = policyCtx.FailedSteps
+	input.Context.TotalCostUSD = policyCtx.TotalCostSoFar
+	if !policyCtx.ExecutionStartTime.IsZero() {
+		input.Context.ElapsedSeconds = policyCtx.ElapsedTime().Seconds()
+	}
+	if paths := policyCtx.ChangedPaths(); len(paths) > 0 {
+		input.Context.ChangedPaths = paths
+	}
+	if policyCtx.Plan != nil {
+		input.Context.Goal = policyCtx.Plan.Goal
+		input.Context.Profile = policyCtx.Plan.Metadata.Profile
+		input.Context.TotalSteps = len(policyCtx.Plan.Steps)
+	}
+	return input
+}
+
+// regoDecision is the document the policy defines under the query
+type regoDecision struct {
+	Allow  *bool    `json:"allow"`
+	Deny   []string `json:"deny"`
+	Warn   []string `json:"warn"`
+	Reason string   `json:"reason"`
+}
+
+// regoOutput is the JSON output of opa eval
+type regoOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// CheckStep evaluates the policy for the step.
+func (r *RegoChecker) CheckStep(ctx context.Context, step *auto.ActionStep) (*PolicyResult, error) {
+	policyCtx, _ := ctx.Value("policy_context").(*PolicyContext)
+	input, err := json.Marshal(NewRegoInput(step, policyCtx))
+	if err != nil {
+		return nil, fmt.Errorf("encode rego input: %w", err)
+	}
+
+	decision, err := r.evaluate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decision.Deny) > 0 {
+		result := NewDeniedResult(strings.Join(decision.Deny, "; "))
+		result.Warnings = append(result.Warnings, decision.Warn...)
+		return result, nil
+	}
+	// A policy that does not decide allow fails closed
+	if decision.Allow == nil || !*decision.Allow {
+		reason := decision.Reason
+		switch {
+		case reason != "":
+		case decision.Allow == nil:
+			reason = fmt.Sprintf("step %s not allowed: %s does not define %s.allow", step.ID, r.PolicyFile, r.Query)
+		default:
+			reason = fmt.Sprintf("step %s not allowed by %s", step.ID, r.PolicyFile)
+		}
+		result := NewDeniedResult(reason)
+		result.Warnings = append(result.Warnings, decision.Warn...)
+		return result, nil
+	}
+
+	result := NewAllowedResult()
+	result.Warnings = append(result.Warnings, decision.Warn...)
+	result.SetMetadata("rego_policy", r.PolicyFile)
+	return result, nil
+}
+
+// evaluate runs opa eval with the input document
+func (r *RegoChecker) evaluate(ctx context.Context, input []byte) (*regoDecision, error) {
+	// #nosec G204 -- The opa path and policy file come from the user's own configuration
+	cmd := exec.CommandContext(ctx, r.OPAPath, "eval",
+		"--format", "json",
+		"--data", r.PolicyFile,
+		"--stdin-input",
+		r.Query,
+	)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("evaluate rego policy %s: %w: %s", r.PolicyFile, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var parsed regoOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("parse opa output: %w", err)
+	}
+	if len(parsed.Result) == 0 || len(parsed.Result[0].Expressions) == 0 {
+		return nil, fmt.Errorf("rego policy %s does not define %s", r.PolicyFile, r.Query)
+	}
+
+	var decision regoDecision
+	if err := json.Unmarshal(parsed.Result[0].Expressions[0].Value, &decision); err != nil {
+		return nil, fmt.Errorf("rego policy %s: %s must be an object with allow, deny, warn, or reason: %w", r.PolicyFile, r.Query, err)
+	}
+	return &decision, nil
+}
+
+// Name returns the checker name.
+func (r *RegoChecker) Name() string {
+	return "rego"
+}
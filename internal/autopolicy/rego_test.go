@@ -0,0 +1,165 @@
+package autopolicy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/patch"
+)
+
+// fakeOPA writes an opa stand-in that records its arguments and input and
+// prints the given opa eval output
+func fakeOPA(t *testing.T, output string) (opaPath, argsFile, inputFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake opa script requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	opaPath = filepath.Join(dir, "opa")
+	argsFile = filepath.Join(dir, "args")
+	inputFile = filepath.Join(dir, "input.json")
+	outputFile := filepath.Join(dir, "output.json")
+	if err := os.WriteFile(outputFile, []byte(output), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\ncat > " + inputFile + "\ncat " + outputFile + "\n"
+	if err := os.WriteFile(opaPath, []byte(script), 0o700); err != nil { // #nosec G306 -- test script must be executable
+		t.Fatal(err)
+	}
+	return opaPath, argsFile, inputFile
+}
+
+func opaOutput(value string) string {
+	return `{"result":[{"expressions":[{"value":` + value + `,"text":"data.specular.auto"}]}]}`
+}
+
+func TestRegoChecker(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		wantAllowed  bool
+		wantReason   string
+		wantWarnings int
+	}{
+		{"empty decision denies", opaOutput(`{}`), false, "step step-4 not allowed: policy.rego does not define data.specular.auto.allow", 0},
+		{"warnings without allow deny", opaOutput(`{"warn":["large plan"]}`), false, "step step-4 not allowed: policy.rego does not define data.specular.auto.allow", 1},
+		{"allow with warnings", opaOutput(`{"allow":true,"warn":["large plan"]}`), true, "", 1},
+		{"deny messages", opaOutput(`{"deny":["build:run requires review","no infra changes"],"warn":["w"]}`), false, "build:run requires review; no infra changes", 1},
+		{"allow false with reason", opaOutput(`{"allow":false,"reason":"outside change freeze"}`), false, "outside change freeze", 0},
+		{"allow false without reason", opaOutput(`{"allow":false}`), false, "step step-4 not allowed by policy.rego", 0},
+	}
+
+	step := &auto.ActionStep{ID: "step-4", Type: auto.StepTypeBuildRun}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opaPath, _, _ := fakeOPA(t, tt.output)
+			checker := &RegoChecker{PolicyFile: "policy.rego", Query: DefaultRegoQuery, OPAPath: opaPath}
+
+			result, err := checker.CheckStep(context.Background(), step)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (reason: %s)", result.Allowed, tt.wantAllowed, result.Reason)
+			}
+			if result.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d", result.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestRegoChecker_Input(t *testing.T) {
+	opaPath, argsFile, inputFile := fakeOPA(t, opaOutput(`{}`))
+	checker := &RegoChecker{PolicyFile: "policy.rego", Query: DefaultRegoQuery, OPAPath: opaPath}
+
+	plan := auto.CreateDefaultActionPlan("Build a todo API", "ci")
+	step := &plan.Steps[3]
+	policyCtx := &PolicyContext{
+		CurrentStep:        step,
+		Plan:               plan,
+		StepIndex:          3,
+		CompletedSteps:     3,
+		TotalCostSoFar:     0.42,
+		ExecutionStartTime: time.Now().Add(-time.Minute),
+		Changes:            []patch.FilePatch{{Path: "infra/main.tf"}},
+	}
+	ctx := context.WithValue(context.Background(), "policy_context", policyCtx)
+
+	if _, err := checker.CheckStep(ctx, step); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	if got := strings.TrimSpace(string(args)); got != "eval --format json --data policy.rego --stdin-input data.specular.auto" {
+		t.Errorf("opa args = %q", got)
+	}
+
+	raw, _ := os.ReadFile(inputFile)
+	var input map[string]map[string]interface{}
+	if err := json.Unmarshal(raw, &input); err != nil {
+		t.Fatalf("input is not JSON: %v\n%s", err, raw)
+	}
+	if input["step"]["id"] != "step-4" || input["step"]["type"] != "build:run" {
+		t.Errorf("unexpected step input: %v", input["step"])
+	}
+	for key, want := range map[string]interface{}{
+		"goal":           "Build a todo API",
+		"profile":        "ci",
+		"stepIndex":      float64(3),
+		"totalSteps":     float64(4),
+		"completedSteps": float64(3),
+		"totalCostUsd":   0.42,
+	} {
+		if got := input["context"][key]; got != want {
+			t.Errorf("context.%s = %v, want %v", key, got, want)
+		}
+	}
+	if paths, _ := input["context"]["changedPaths"].([]interface{}); len(paths) != 1 || paths[0] != "infra/main.tf" {
+		t.Errorf("context.changedPaths = %v, want [infra/main.tf]", input["context"]["changedPaths"])
+	}
+}
+
+func TestRegoChecker_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{"undefined document", `{"result":[]}`, "does not define data.specular.auto"},
+		{"decision not an object", opaOutput(`true`), "must be an object"},
+		{"invalid output", `not json`, "parse opa output"},
+	}
+
+	step := &auto.ActionStep{ID: "step-1", Type: auto.StepTypeSpecUpdate}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opaPath, _, _ := fakeOPA(t, tt.output)
+			checker := &RegoChecker{PolicyFile: "policy.rego", Query: DefaultRegoQuery, OPAPath: opaPath}
+
+			_, err := checker.CheckStep(context.Background(), step)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing policy file", func(t *testing.T) {
+		_, err := NewRegoChecker(filepath.Join(t.TempDir(), "missing.rego"))
+		if err == nil {
+			t.Error("expected error for missing policy file")
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupsDir is where 'bundle apply --backup' snapshots are kept,
+	// relative to the target directory
+	BackupsDir = ".specular/backups"
+
+	// backupRecordFileName describes a backup; the overwritten files are
+	// kept under backupFilesDir
+	backupRecordFileName = "backup.json"
+	backupFilesDir       = "files"
+)
+
+// applyTransaction applies bundle files all or nothing. Files are staged in
+// a directory inside the target, so moving them into place is a rename on
+// the same filesystem. If a move fails, the files already moved are put
+// back.
+type applyTransaction struct {
+	targetDir  string
+	stagingDir string
+	files      []*stagedFile
+	createdDir []string // Directories created while committing, parents first
+}
+
+// stagedFile is one bundle file on its way into the target directory
+type stagedFile struct {
+	relPath     string // Slash-separated path in the bundle and the target
+	displayName string
+	sourcePath  string
+	stagedPath  string
+	targetPath  string
+	savedPath   string // The previous target while committing; empty if it didn't exist
+	moved       bool
+}
+
+// add queues a bundle file for the target path
+func (tx *applyTransaction) add(sourcePath, targetPath, displayName string) {
+	tx.files = append(tx.files, &stagedFile{
+		relPath:     filepath.ToSlash(displayName),
+		displayName: displayName,
+		sourcePath:  sourcePath,
+		targetPath:  targetPath,
+	})
+}
+
+// stage copies each queued file into the staging directory and checks it
+// against the manifest checksum. Nothing in the target is changed.
+func (tx *applyTransaction) stage(manifest *Manifest) error {
+	stagingDir, err := os.MkdirTemp(tx.targetDir, ".specular-apply-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	tx.stagingDir = stagingDir
+
+	for i, file := range tx.files {
+		entry := manifest.GetFile(file.relPath)
+		if entry == nil {
+			return fmt.Errorf("file %s is not listed in the bundle manifest", file.displayName)
+		}
+
+		file.stagedPath = filepath.Join(stagingDir, "new", fmt.Sprintf("%d", i))
+		checksum, err := stageCopy(file.sourcePath, file.stagedPath)
+		if err != nil {
+			return fmt.Errorf("failed to stage %s: %w", file.displayName, err)
+		}
+		if checksum != entry.Checksum {
+			return ErrChecksumMismatch(file.displayName, entry.Checksum, checksum)
+		}
+	}
+	return nil
+}
+
+// commit moves the staged files into place. On error the target directory
+// is restored to its state before the commit.
+func (tx *applyTransaction) commit() error {
+	savedDir := filepath.Join(tx.stagingDir, "previous")
+	for i, file := range tx.files {
+		if err := tx.mkdirAll(filepath.Dir(file.targetPath)); err != nil {
+			return tx.rollback(fmt.Errorf("failed to create directory for %s: %w", file.displayName, err))
+		}
+
+		if _, err := os.Lstat(file.targetPath); err == nil {
+			saved := filepath.Join(savedDir, fmt.Sprintf("%d", i))
+			if err := os.MkdirAll(savedDir, 0750); err != nil {
+				return tx.rollback(fmt.Errorf("failed to move %s aside: %w", file.displayName, err))
+			}
+			if err := os.Rename(file.targetPath, saved); err != nil {
+				return tx.rollback(fmt.Errorf("failed to move %s aside: %w", file.displayName, err))
+			}
+			file.savedPath = saved
+		}
+
+		if err := os.Rename(file.stagedPath, file.targetPath); err != nil {
+			return tx.rollback(fmt.Errorf("failed to apply %s: %w", file.displayName, err))
+		}
+		file.moved = true
+	}
+	return nil
+}
+
+// rollback undoes a partial commit in reverse order and returns the error
+// that caused it
+func (tx *applyTransaction) rollback(cause error) error {
+	var errs []error
+	for i := len(tx.files) - 1; i >= 0; i-- {
+		file := tx.files[i]
+		if file.moved {
+			if err := os.Remove(file.targetPath); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+		if file.savedPath != "" {
+			if err := os.Rename(file.savedPath, file.targetPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for i := len(tx.createdDir) - 1; i >= 0; i-- {
+		_ = os.Remove(tx.createdDir[i]) //nolint:errcheck // Only removes directories left empty
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (rollback incomplete: %v)", cause, errors.Join(errs...))
+	}
+	return fmt.Errorf("%w (no changes were made)", cause)
+}
+
+// mkdirAll creates dir and its missing parents, remembering each directory
+// it creates for rollback
+func (tx *applyTransaction) mkdirAll(dir string) error {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := os.Mkdir(missing[i], 0750); err != nil && !os.IsExist(err) {
+			return err
+		}
+		tx.createdDir = append(tx.createdDir, missing[i])
+	}
+	return nil
+}
+
+// cleanup removes the staging directory
+func (tx *applyTransaction) cleanup() {
+	if tx.stagingDir != "" {
+		_ = os.RemoveAll(tx.stagingDir) //nolint:errcheck
+	}
+}
+
+// stageCopy copies source to target with the source's permissions and
+// returns the SHA-256 checksum of the copied data
+func stageCopy(sourcePath, targetPath string) (string, error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = source.Close() }() //nolint:errcheck
+
+	info, err := source.Stat()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0750); err != nil {
+		return "", err
+	}
+
+	target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(target, hash), source); err != nil {
+		_ = target.Close() //nolint:errcheck
+		return "", err
+	}
+	if err := target.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ApplyBackup records what a 'bundle apply --backup' changed, so the apply
+// can be undone with RestoreBackup
+type ApplyBackup struct {
+	// TargetDir is the directory the bundle was applied to
+	TargetDir string `json:"target_dir"`
+
+	// Bundle identifies the applied bundle as id@version
+	Bundle string `json:"bundle"`
+
+	// Created is when the backup was taken
+	Created time.Time `json:"created"`
+
+	// Overwritten lists the files the apply replaced; their previous
+	// contents are kept in the backup
+	Overwritten []string `json:"overwritten"`
+
+	// Added lists the files the apply created
+	Added []string `json:"added"`
+}
+
+// writeBackup snapshots the files the transaction will overwrite into a new
+// directory under BackupsDir and returns its path
+func (tx *applyTransaction) writeBackup(bundleName string) (string, error) {
+	created := time.Now().UTC()
+	backupDir := filepath.Join(tx.targetDir, BackupsDir, "apply-"+created.Format("20060102T150405.000000000Z"))
+	if err := os.MkdirAll(backupDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	backup := ApplyBackup{
+		TargetDir:   tx.targetDir,
+		Bundle:      bundleName,
+		Created:     created,
+		Overwritten: []string{},
+		Added:       []string{},
+	}
+	for _, file := range tx.files {
+		if _, err := os.Lstat(file.targetPath); os.IsNotExist(err) {
+			backup.Added = append(backup.Added, file.relPath)
+			continue
+		}
+		if _, err := stageCopy(file.targetPath, filepath.Join(backupDir, backupFilesDir, filepath.FromSlash(file.relPath))); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", file.displayName, err)
+		}
+		backup.Overwritten = append(backup.Overwritten, file.relPath)
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(backupDir, backupRecordFileName), data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup record: %w", err)
+	}
+	return backupDir, nil
+}
+
+// RestoreBackup undoes a 'bundle apply --backup': overwritten files get their
+// previous contents back and added files are removed. The restore is
+// applied all or nothing, like the apply itself.
+func RestoreBackup(backupDir string) (*ApplyBackup, error) {
+	data, err := os.ReadFile(filepath.Join(backupDir, backupRecordFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup record: %w", err)
+	}
+	var backup ApplyBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup record: %w", err)
+	}
+	if backup.TargetDir == "" {
+		return nil, fmt.Errorf("backup record has no target directory")
+	}
+
+	tx := &applyTransaction{targetDir: backup.TargetDir}
+	defer tx.cleanup()
+
+	manifest := &Manifest{}
+	for _, relPath := range backup.Overwritten {
+		targetPath, err := backupTargetPath(backup.TargetDir, relPath)
+		if err != nil {
+			return nil, err
+		}
+		sourcePath := filepath.Join(backupDir, backupFilesDir, filepath.FromSlash(relPath))
+		checksum, err := fileChecksum(sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup of %s: %w", relPath, err)
+		}
+		tx.add(sourcePath, targetPath, relPath)
+		manifest.Files = append(manifest.Files, FileEntry{Path: relPath, Checksum: checksum})
+	}
+	if err := tx.stage(manifest); err != nil {
+		return nil, err
+	}
+
+	// Added files are moved aside with the commit so they come back if the
+	// restore fails
+	removals := make([]string, 0, len(backup.Added))
+	for _, relPath := range backup.Added {
+		targetPath, err := backupTargetPath(backup.TargetDir, relPath)
+		if err != nil {
+			return nil, err
+		}
+		removals = append(removals, targetPath)
+	}
+	sort.Strings(removals)
+
+	if err := tx.commit(); err != nil {
+		return nil, err
+	}
+	removedDir := filepath.Join(tx.stagingDir, "removed")
+	if err := os.MkdirAll(removedDir, 0750); err != nil {
+		return nil, tx.rollback(fmt.Errorf("failed to remove added files: %w", err))
+	}
+	if err := removeAside(removedDir, removals); err != nil {
+		return nil, tx.rollback(err)
+	}
+
+	return &backup, nil
+}
+
+// removeAside moves the files at targetPaths into dir, skipping those that
+// no longer exist. If a move fails, the files already moved are put back.
+func removeAside(dir string, targetPaths []string) error {
+	type asideFile struct {
+		targetPath string
+		asidePath  string
+	}
+	var removed []asideFile
+	for i, targetPath := range targetPaths {
+		aside := filepath.Join(dir, fmt.Sprintf("%d", i))
+		if err := os.Rename(targetPath, aside); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			for j := len(removed) - 1; j >= 0; j-- {
+				_ = os.Rename(removed[j].asidePath, removed[j].targetPath) //nolint:errcheck
+			}
+			return fmt.Errorf("failed to remove %s: %w", targetPath, err)
+		}
+		removed = append(removed, asideFile{targetPath: targetPath, asidePath: aside})
+	}
+	return nil
+}
+
+// backupTargetPath resolves a backed-up path inside the target directory
+func backupTargetPath(targetDir, relPath string) (string, error) {
+	targetPath := filepath.Join(targetDir, filepath.FromSlash(relPath))
+	if rel, err := filepath.Rel(targetDir, targetPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path in backup record: %s", relPath)
+	}
+	return targetPath, nil
+}
+
+// fileChecksum returns the SHA-256 checksum of a file
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }() //nolint:errcheck
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
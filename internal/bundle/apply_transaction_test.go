@@ -0,0 +1,192 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTwoDirBundle builds a bundle of root's aaa and zzz directories.
+func buildTwoDirBundle(t *testing.T, root string) string {
+	t.Helper()
+
+	writeTree(t, root, map[string]string{
+		"aaa/x.md": "new x",
+		"zzz/y.md": "new y",
+	})
+	builder, err := NewBuilder(BundleOptions{
+		ProjectRoot:  root,
+		IncludePaths: []string{"aaa", "zzz"},
+	})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "app.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+	return bundlePath
+}
+
+// assertNoStagingDirs checks that an apply cleaned up after itself.
+func assertNoStagingDirs(t *testing.T, target string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(target, ".specular-apply-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "staging directory left behind")
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExtractor_ApplyRollsBackOnFailure(t *testing.T) {
+	bundlePath := buildTwoDirBundle(t, t.TempDir())
+
+	// zzz is a file in the target, so zzz/y.md can't be moved into place
+	// after aaa/x.md already was
+	target := t.TempDir()
+	writeTree(t, target, map[string]string{
+		"aaa/x.md": "old x",
+		"zzz":      "not a directory",
+	})
+
+	err := NewExtractor(ApplyOptions{TargetDir: target, Yes: true}).Apply(bundlePath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "zzz/y.md")
+	assert.Contains(t, err.Error(), "no changes were made")
+
+	assert.Equal(t, "old x", readFile(t, filepath.Join(target, "aaa", "x.md")))
+	assert.Equal(t, "not a directory", readFile(t, filepath.Join(target, "zzz")))
+	assertNoStagingDirs(t, target)
+}
+
+func TestExtractor_ApplyRemovesCreatedDirsOnRollback(t *testing.T) {
+	bundlePath := buildTwoDirBundle(t, t.TempDir())
+
+	target := t.TempDir()
+	writeTree(t, target, map[string]string{"zzz": "not a directory"})
+
+	require.Error(t, NewExtractor(ApplyOptions{TargetDir: target, Yes: true}).Apply(bundlePath))
+	assert.NoDirExists(t, filepath.Join(target, "aaa"))
+	assertNoStagingDirs(t, target)
+}
+
+func TestApplyTransaction_StageVerifiesChecksums(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(source, []byte("tampered"), 0600))
+
+	target := t.TempDir()
+	targetPath := filepath.Join(target, "spec.yaml")
+	require.NoError(t, os.WriteFile(targetPath, []byte("original"), 0600))
+
+	tests := []struct {
+		name     string
+		manifest *Manifest
+		wantErr  string
+	}{
+		{
+			name:     "checksum mismatch",
+			manifest: &Manifest{Files: []FileEntry{{Path: "spec.yaml", Checksum: "0000"}}},
+			wantErr:  "checksum mismatch for file: spec.yaml",
+		},
+		{
+			name:     "not in manifest",
+			manifest: &Manifest{},
+			wantErr:  "not listed in the bundle manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &applyTransaction{targetDir: target}
+			tx.add(source, targetPath, "spec.yaml")
+			err := tx.stage(tt.manifest)
+			tx.cleanup()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, "original", readFile(t, targetPath))
+			assertNoStagingDirs(t, target)
+		})
+	}
+}
+
+func TestExtractor_ApplyBackupAndRestore(t *testing.T) {
+	bundlePath := buildTwoDirBundle(t, t.TempDir())
+
+	target := t.TempDir()
+	writeTree(t, target, map[string]string{"aaa/x.md": "old x"})
+
+	require.NoError(t, NewExtractor(ApplyOptions{TargetDir: target, Yes: true, Backup: true}).Apply(bundlePath))
+	assert.Equal(t, "new x", readFile(t, filepath.Join(target, "aaa", "x.md")))
+	assert.Equal(t, "new y", readFile(t, filepath.Join(target, "zzz", "y.md")))
+	assertNoStagingDirs(t, target)
+
+	backups, err := filepath.Glob(filepath.Join(target, BackupsDir, "apply-*"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "old x", readFile(t, filepath.Join(backups[0], backupFilesDir, "aaa", "x.md")))
+
+	backup, err := RestoreBackup(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aaa/x.md"}, backup.Overwritten)
+	assert.Equal(t, []string{"zzz/y.md"}, backup.Added)
+
+	assert.Equal(t, "old x", readFile(t, filepath.Join(target, "aaa", "x.md")))
+	assert.NoFileExists(t, filepath.Join(target, "zzz", "y.md"))
+	assertNoStagingDirs(t, target)
+}
+
+func TestRemoveAside_PutsBackAfterSkippedFile(t *testing.T) {
+	target := t.TempDir()
+	writeTree(t, target, map[string]string{"b.md": "b", "c.md": "c"})
+	asideDir := t.TempDir()
+	// The slot for c.md is taken, so moving it aside fails
+	writeTree(t, asideDir, map[string]string{"2/blocker": "x"})
+
+	paths := []string{
+		filepath.Join(target, "a.md"), // Already gone
+		filepath.Join(target, "b.md"),
+		filepath.Join(target, "c.md"),
+	}
+	err := removeAside(asideDir, paths)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to remove")
+
+	assert.Equal(t, "b", readFile(t, filepath.Join(target, "b.md")))
+	assert.Equal(t, "c", readFile(t, filepath.Join(target, "c.md")))
+	assert.NoFileExists(t, filepath.Join(asideDir, "1"))
+}
+
+func TestExtractor_ApplyDryRunWritesNothing(t *testing.T) {
+	bundlePath := buildTwoDirBundle(t, t.TempDir())
+
+	target := t.TempDir()
+	require.NoError(t, NewExtractor(ApplyOptions{TargetDir: target, DryRun: true, Backup: true}).Apply(bundlePath))
+
+	entries, err := os.ReadDir(target)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRestoreBackup_Invalid(t *testing.T) {
+	t.Run("missing record", func(t *testing.T) {
+		_, err := RestoreBackup(t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read backup record")
+	})
+
+	t.Run("path outside target", func(t *testing.T) {
+		dir := t.TempDir()
+		record := `{"target_dir": "` + filepath.ToSlash(t.TempDir()) + `", "added": ["../escape.md"]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, backupRecordFileName), []byte(record), 0600))
+
+		_, err := RestoreBackup(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid path in backup record")
+	})
+}
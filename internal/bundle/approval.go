@@ -2,9 +2,13 @@ package bundle
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
+// ErrApprovalExpired is returned when an approval is past its expiry.
+var ErrApprovalExpired = errors.New("approval expired")
+
 // Approval represents a team member's approval signature on a bundle.
 // Approvals provide multi-stakeholder sign-off for governance bundles,
 // ensuring that critical artifacts are reviewed and approved before use.
@@ -18,11 +22,15 @@ type Approval struct {
 	// SignedAt is the timestamp when the approval was signed
 	SignedAt time.Time `json:"signed_at" yaml:"signed_at"`
 
+	// ExpiresAt is when the approval stops counting towards required roles
+	// Nil means the approval never expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
 	// Signature is the cryptographic signature of the bundle digest
 	// Format depends on SignatureType (SSH, GPG, etc.)
 	Signature string `json:"signature" yaml:"signature"`
 
-	// SignatureType indicates the signature format ("ssh", "gpg", "x509")
+	// SignatureType indicates the signature format ("ssh", "gpg", "cosign")
 	SignatureType SignatureType `json:"signature_type" yaml:"signature_type"`
 
 	// PublicKey is the public key used for verification
@@ -33,6 +41,9 @@ type Approval struct {
 	// Used for quick key identification without storing full key
 	PublicKeyFingerprint string `json:"public_key_fingerprint,omitempty" yaml:"public_key_fingerprint,omitempty"`
 
+	// Certificate is the PEM certificate for the signing key (cosign only, optional)
+	Certificate string `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+
 	// Comment is an optional comment from the approver
 	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
 
@@ -71,12 +82,19 @@ type ApprovalRequest struct {
 	// Comment is an optional approval comment
 	Comment string
 
+	// ExpiresAt is an optional expiry embedded in the signed approval
+	ExpiresAt *time.Time
+
 	// SignatureType is the type of signature to create
 	SignatureType SignatureType
 
 	// KeyPath is the path to the private key for signing (optional)
 	// If not provided, default keys will be used
 	KeyPath string
+
+	// CertificatePath is the path to a PEM certificate for the signing key
+	// (cosign only, optional)
+	CertificatePath string
 }
 
 // ApprovalVerificationOptions contains options for verifying approvals.
@@ -174,6 +192,25 @@ func (a *Approval) IsExpired(maxAge time.Duration) bool {
 	return time.Since(a.SignedAt) > maxAge
 }
 
+// ExpiredAt reports whether the approval's validity window has ended at the
+// given time. Approvals without an expiry never expire.
+func (a *Approval) ExpiredAt(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// RemainingValidity returns how long the approval stays valid after now.
+// It returns false if the approval has no expiry.
+func (a *Approval) RemainingValidity(now time.Time) (time.Duration, bool) {
+	if a.ExpiresAt == nil {
+		return 0, false
+	}
+	remaining := a.ExpiresAt.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
 // MatchesFingerprint checks if the approval's public key matches the given fingerprint.
 func (a *Approval) MatchesFingerprint(fingerprint string) bool {
 	if a.PublicKeyFingerprint == "" {
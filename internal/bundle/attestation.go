@@ -23,6 +23,10 @@ type Attestation struct {
 	// Signature contains the cryptographic signature bundle
 	Signature AttestationSignature `json:"signature" yaml:"signature"`
 
+	// Payload is the signed in-toto statement (base64 encoded JSON).
+	// Signature verification is performed over this payload.
+	Payload string `json:"payload,omitempty" yaml:"payload,omitempty"`
+
 	// RekorEntry contains the Rekor transparency log entry (optional)
 	// Provides tamper-proof audit trail via public ledger
 	RekorEntry *RekorEntry `json:"rekor_entry,omitempty" yaml:"rekor_entry,omitempty"`
@@ -206,6 +210,11 @@ type AttestationOptions struct {
 	// UseKeyless enables Sigstore keyless signing
 	UseKeyless bool
 
+	// UseEphemeralKey signs with a key generated for this attestation when
+	// neither keyless signing nor KeyPath is used. The public key is
+	// embedded, so the signature verifies but the signer is not certified.
+	UseEphemeralKey bool
+
 	// RekorURL is the URL of the Rekor server
 	// Default: "https://rekor.sigstore.dev"
 	RekorURL string
@@ -220,7 +229,12 @@ type AttestationOptions struct {
 	// OIDCClientID is the OIDC client ID for keyless signing
 	OIDCClientID string
 
-	// IncludeRekorEntry enables Rekor transparency log inclusion
+	// IDToken is the OIDC identity token for keyless signing. When empty it
+	// is read from SIGSTORE_ID_TOKEN or the GitHub Actions environment.
+	IDToken string
+
+	// IncludeRekorEntry records the signature in the Rekor transparency log
+	// and embeds the entry in the attestation
 	IncludeRekorEntry bool
 
 	// Metadata contains additional attestation metadata
@@ -229,17 +243,24 @@ type AttestationOptions struct {
 
 // AttestationVerificationOptions contains options for verifying attestations.
 type AttestationVerificationOptions struct {
-	// TrustedRootPath is the path to the trusted root certificates
+	// TrustedRootPath is the path to the trusted root certificates (PEM).
+	// When set, attestations must carry a certificate chaining to them.
 	TrustedRootPath string
 
 	// RekorURL is the URL of the Rekor server for verification
 	RekorURL string
 
-	// RequireRekorEntry requires a valid Rekor entry
+	// RequireRekorEntry requires a valid Rekor entry. An entry that is
+	// present is always verified.
 	RequireRekorEntry bool
 
-	// TrustedIdentities lists trusted identities for keyless signing
-	// Format: email, subject, or issuer patterns
+	// Offline checks the Rekor entry embedded in the attestation without
+	// fetching it from the log
+	Offline bool
+
+	// TrustedIdentities lists trusted signer identities
+	// Format: email, subject, or issuer patterns, or sha256:<hex> public key fingerprints.
+	// Email and URI identities of keyless signatures also require TrustedRootPath.
 	TrustedIdentities []string
 
 	// VerifySignature enables signature verification
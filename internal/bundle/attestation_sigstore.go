@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,6 +17,8 @@ import (
 
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 )
 
 // AttestationGenerator creates cryptographic attestations for bundles.
@@ -24,10 +30,10 @@ type AttestationGenerator struct {
 func NewAttestationGenerator(opts AttestationOptions) *AttestationGenerator {
 	// Set defaults
 	if opts.RekorURL == "" {
-		opts.RekorURL = "https://rekor.sigstore.dev"
+		opts.RekorURL = DefaultRekorURL
 	}
 	if opts.FulcioURL == "" {
-		opts.FulcioURL = "https://fulcio.sigstore.dev"
+		opts.FulcioURL = keyless.DefaultFulcioURL
 	}
 	if opts.PredicateType == "" {
 		opts.PredicateType = "https://in-toto.io/Statement/v1"
@@ -73,10 +79,22 @@ func (g *AttestationGenerator) GenerateAttestation(ctx context.Context, bundlePa
 		return nil, fmt.Errorf("unsupported attestation format: %s", g.opts.Format)
 	}
 
+	// The bundle file digest changes once the attestation is embedded, so the
+	// manifest (which pins every file checksum) is attested as a second subject
+	subjects := []AttestationSubject{subject}
+	if manifestDigest, manifestErr := readManifestDigest(bundlePath); manifestErr == nil {
+		subjects = append(subjects, AttestationSubject{
+			Name: ManifestFileName,
+			Digest: map[string]string{
+				"sha256": manifestDigest,
+			},
+		})
+	}
+
 	// Create in-toto statement envelope
 	statement := map[string]interface{}{
 		"_type":         "https://in-toto.io/Statement/v1",
-		"subject":       []AttestationSubject{subject},
+		"subject":       subjects,
 		"predicateType": predicateType,
 		"predicate":     predicate,
 	}
@@ -89,12 +107,11 @@ func (g *AttestationGenerator) GenerateAttestation(ctx context.Context, bundlePa
 
 	// Sign the attestation
 	var attestSig AttestationSignature
-	var rekorEntry *RekorEntry
 
 	switch {
 	case g.opts.UseKeyless:
 		// Use Sigstore keyless signing
-		attestSig, rekorEntry, err = g.signKeyless(ctx, statementJSON)
+		attestSig, err = g.signKeyless(ctx, statementJSON)
 		if err != nil {
 			return nil, fmt.Errorf("keyless signing failed: %w", err)
 		}
@@ -104,18 +121,24 @@ func (g *AttestationGenerator) GenerateAttestation(ctx context.Context, bundlePa
 		if err != nil {
 			return nil, fmt.Errorf("key-based signing failed: %w", err)
 		}
-
-		// Optionally upload to Rekor
-		if g.opts.IncludeRekorEntry {
-			rekorEntry, err = g.uploadToRekor(ctx, statementJSON, attestSig)
-			if err != nil {
-				return nil, fmt.Errorf("failed to upload to Rekor: %w", err)
-			}
+	case g.opts.UseEphemeralKey:
+		attestSig, err = g.signWithEphemeralKey(statementJSON)
+		if err != nil {
+			return nil, fmt.Errorf("ephemeral key signing failed: %w", err)
 		}
 	default:
 		return nil, fmt.Errorf("either keyless signing or key path must be provided")
 	}
 
+	// Optionally upload to Rekor
+	var rekorEntry *RekorEntry
+	if g.opts.IncludeRekorEntry {
+		rekorEntry, err = g.uploadToRekor(ctx, statementJSON, attestSig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload to Rekor: %w", err)
+		}
+	}
+
 	// Create attestation
 	attestation := &Attestation{
 		Format:        g.opts.Format,
@@ -123,6 +146,7 @@ func (g *AttestationGenerator) GenerateAttestation(ctx context.Context, bundlePa
 		Predicate:     predicate,
 		PredicateType: predicateType,
 		Signature:     attestSig,
+		Payload:       base64.StdEncoding.EncodeToString(statementJSON),
 		RekorEntry:    rekorEntry,
 		Timestamp:     time.Now(),
 		Metadata:      g.opts.Metadata,
@@ -185,13 +209,30 @@ func (g *AttestationGenerator) createInTotoStatement(bundlePath string) map[stri
 	}
 }
 
-// signKeyless performs Sigstore keyless signing.
-func (g *AttestationGenerator) signKeyless(ctx context.Context, payload []byte) (AttestationSignature, *RekorEntry, error) {
-	// For MVP, return placeholder implementation
-	// Full keyless signing requires OIDC flow and Fulcio integration
-	// which is complex and requires interactive auth
+// signKeyless performs Sigstore keyless signing: Fulcio certifies an
+// ephemeral key for the OIDC identity, and the certificate chain is attached
+// to the signature.
+func (g *AttestationGenerator) signKeyless(ctx context.Context, payload []byte) (AttestationSignature, error) {
+	signer, err := keyless.NewSigner(ctx, keyless.Options{
+		FulcioURL: g.opts.FulcioURL,
+		IDToken:   g.opts.IDToken,
+		Issuer:    g.opts.OIDCIssuer,
+	})
+	if err != nil {
+		return AttestationSignature{}, err
+	}
+
+	attestSig, err := signPayload(signer.PrivateKey(), payload)
+	if err != nil {
+		return AttestationSignature{}, err
+	}
+
+	attestSig.Certificate, err = signer.CertificateChainPEM()
+	if err != nil {
+		return AttestationSignature{}, err
+	}
 
-	return AttestationSignature{}, nil, fmt.Errorf("keyless signing not yet implemented - use key-based signing for now")
+	return attestSig, nil
 }
 
 // signWithKey signs the attestation with a private key.
@@ -208,6 +249,22 @@ func (g *AttestationGenerator) signWithKey(ctx context.Context, payload []byte)
 		return AttestationSignature{}, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
+	return signPayload(priv, payload)
+}
+
+// signWithEphemeralKey signs the attestation with a key generated for it.
+func (g *AttestationGenerator) signWithEphemeralKey(payload []byte) (AttestationSignature, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return AttestationSignature{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return signPayload(priv, payload)
+}
+
+// signPayload signs the SHA-256 digest of the payload and returns the
+// signature with the PEM public key.
+func signPayload(priv crypto.PrivateKey, payload []byte) (AttestationSignature, error) {
 	// Create signer
 	signer, err := signature.LoadSignerVerifier(priv, crypto.SHA256)
 	if err != nil {
@@ -243,12 +300,9 @@ func (g *AttestationGenerator) signWithKey(ctx context.Context, payload []byte)
 	}, nil
 }
 
-// uploadToRekor uploads the signature to Rekor transparency log.
+// uploadToRekor records the signature in the Rekor transparency log.
 func (g *AttestationGenerator) uploadToRekor(ctx context.Context, payload []byte, sig AttestationSignature) (*RekorEntry, error) {
-	// For MVP, return placeholder implementation
-	// Full Rekor integration requires complex entry creation and verification
-
-	return nil, fmt.Errorf("Rekor upload not yet implemented")
+	return NewRekorClient(g.opts.RekorURL).Upload(ctx, payload, sig)
 }
 
 // AttestationVerifier verifies Sigstore attestations.
@@ -259,7 +313,7 @@ type AttestationVerifier struct {
 // NewAttestationVerifier creates a new attestation verifier.
 func NewAttestationVerifier(opts AttestationVerificationOptions) *AttestationVerifier {
 	if opts.RekorURL == "" {
-		opts.RekorURL = "https://rekor.sigstore.dev"
+		opts.RekorURL = DefaultRekorURL
 	}
 
 	return &AttestationVerifier{
@@ -302,17 +356,22 @@ func (v *AttestationVerifier) VerifyAttestation(ctx context.Context, attestation
 		if err := v.verifySignature(attestation); err != nil {
 			return fmt.Errorf("signature verification failed: %w", err)
 		}
-	}
 
-	// Verify Rekor entry if required
-	if v.opts.RequireRekorEntry {
-		if !attestation.HasRekorEntry() {
-			return fmt.Errorf("attestation missing required Rekor entry")
+		// Only a verified signature establishes who the signer is
+		if len(v.opts.TrustedIdentities) > 0 {
+			if err := v.verifyIdentity(attestation); err != nil {
+				return err
+			}
 		}
+	}
 
+	// Verify the Rekor entry; one that is present is always verified
+	if attestation.HasRekorEntry() {
 		if err := v.verifyRekorEntry(ctx, attestation); err != nil {
-			return fmt.Errorf("Rekor verification failed: %w", err)
+			return fmt.Errorf("%w: %v", ErrRekorEntryInvalid, err)
 		}
+	} else if v.opts.RequireRekorEntry {
+		return fmt.Errorf("%w: attestation missing required Rekor entry", ErrRekorEntryInvalid)
 	}
 
 	return nil
@@ -320,45 +379,131 @@ func (v *AttestationVerifier) VerifyAttestation(ctx context.Context, attestation
 
 // verifySignature verifies the attestation signature.
 func (v *AttestationVerifier) verifySignature(attestation *Attestation) error {
-	// For MVP, basic signature verification
-	// Full implementation would verify against public key or certificate
-
 	if attestation.Signature.Signature == "" {
 		return fmt.Errorf("attestation missing signature")
 	}
 
-	// If certificate is present, verify it
-	if attestation.Signature.Certificate != "" {
-		// Certificate verification would go here
-		// This requires parsing the cert chain and verifying trust
-		return fmt.Errorf("certificate verification not yet implemented")
+	if attestation.Payload == "" {
+		return fmt.Errorf("%w: attestation has no signed payload", ErrAttestationSignatureInvalid)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(attestation.Payload)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode payload: %v", ErrAttestationSignatureInvalid, err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(attestation.Signature.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode signature: %v", ErrAttestationSignatureInvalid, err)
 	}
 
-	// If public key is present, verify signature
+	var pubKey crypto.PublicKey
+	switch {
+	case attestation.Signature.Certificate != "":
+		// Keyless: the certificate binds the key to the signer identity
+		pubKey, err = v.verifyCertificate(attestation)
+		if err != nil {
+			return err
+		}
+	case v.opts.TrustedRootPath != "":
+		return fmt.Errorf("%w: attestation has no signing certificate to check against the trusted roots", ErrAttestationSignatureInvalid)
+	case attestation.Signature.PublicKey != "":
+		pubKey, err = cryptoutils.UnmarshalPEMToPublicKey([]byte(attestation.Signature.PublicKey))
+		if err != nil {
+			return fmt.Errorf("failed to parse public key: %w", err)
+		}
+	default:
+		return fmt.Errorf("no verification method available (need certificate or public key)")
+	}
+
+	verifier, err := signature.LoadVerifier(pubKey, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to create verifier: %w", err)
+	}
+
+	// signPayload signs the SHA-256 digest of the payload
+	digest := sha256.Sum256(payload)
+	if err := verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(digest[:])); err != nil {
+		return fmt.Errorf("%w: %v", ErrAttestationSignatureInvalid, err)
+	}
+
+	// The unsigned subject must match what was actually signed
+	statement, err := decodeStatement(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAttestationSignatureInvalid, err)
+	}
+	if len(statement.Subject) == 0 ||
+		statement.Subject[0].Digest["sha256"] != attestation.Subject.Digest["sha256"] {
+		return fmt.Errorf("%w: attestation subject does not match signed payload", ErrAttestationSignatureInvalid)
+	}
+
+	return nil
+}
+
+// verifyCertificate checks a keyless signing certificate chain and returns
+// the certified key. The certificate must have been valid when the
+// attestation was created and, with TrustedRootPath, chain to those roots.
+func (v *AttestationVerifier) verifyCertificate(attestation *Attestation) (crypto.PublicKey, error) {
+	var roots *x509.CertPool
+	if v.opts.TrustedRootPath != "" {
+		var err error
+		roots, err = keyless.LoadRoots(v.opts.TrustedRootPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	leaf, err := keyless.VerifyCertificate(attestation.Signature.Certificate, attestation.Timestamp, roots)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAttestationSignatureInvalid, err)
+	}
+
+	// An embedded public key must be the certified one
 	if attestation.Signature.PublicKey != "" {
-		// Public key verification would go here
-		// This requires recreating the signed payload and verifying
-		return fmt.Errorf("public key verification not yet implemented")
+		pubKey, err := cryptoutils.UnmarshalPEMToPublicKey([]byte(attestation.Signature.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		if err := cryptoutils.EqualKeys(pubKey, leaf.PublicKey); err != nil {
+			return nil, fmt.Errorf("%w: certificate does not match the public key", ErrAttestationSignatureInvalid)
+		}
 	}
 
-	return fmt.Errorf("no verification method available (need certificate or public key)")
+	return leaf.PublicKey, nil
 }
 
-// verifyRekorEntry verifies the Rekor transparency log entry.
+// verifyRekorEntry checks that the Rekor entry records this attestation's
+// signature. Unless offline, the entry is fetched from the log and must
+// match the embedded one. For keyless signatures the certificate must have
+// been valid when the entry was integrated into the log.
 func (v *AttestationVerifier) verifyRekorEntry(ctx context.Context, attestation *Attestation) error {
-	if attestation.RekorEntry == nil {
-		return fmt.Errorf("attestation missing Rekor entry")
-	}
+	entry := attestation.RekorEntry
 
-	// For MVP, just check entry exists
-	// Full verification would fetch and verify the entry from Rekor
+	if !v.opts.Offline {
+		logged, err := NewRekorClient(v.opts.RekorURL).GetEntry(ctx, entry.UUID)
+		if err != nil {
+			return err
+		}
+		if logged.LogIndex != entry.LogIndex || logged.IntegratedTime != entry.IntegratedTime {
+			return fmt.Errorf("entry %s in the log does not match the attestation (log index %d, expected %d)",
+				entry.UUID, logged.LogIndex, entry.LogIndex)
+		}
+		entry = logged
+	}
 
-	if attestation.RekorEntry.UUID == "" {
-		return fmt.Errorf("Rekor entry missing UUID")
+	if err := matchRekorBody(entry, attestation); err != nil {
+		return err
 	}
 
-	// Rekor client integration would go here
-	// This requires fetching the entry and verifying inclusion proof
+	if attestation.Signature.Certificate != "" {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(attestation.Signature.Certificate))
+		if err != nil || len(certs) == 0 {
+			return fmt.Errorf("failed to parse signing certificate")
+		}
+		if err := cryptoutils.CheckExpiration(certs[0], time.Unix(entry.IntegratedTime, 0)); err != nil {
+			return fmt.Errorf("certificate was not valid when the entry was logged: %w", err)
+		}
+	}
 
-	return fmt.Errorf("Rekor entry verification not yet implemented")
+	return nil
 }
@@ -4,10 +4,13 @@ package bundle
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 )
 
 // TestNewAttestationGenerator tests attestation generator initialization
@@ -304,8 +307,12 @@ func TestGenerateAttestationWithKey(t *testing.T) {
 	}
 }
 
-// TestGenerateAttestationKeylessError tests keyless signing error
+// TestGenerateAttestationKeylessError tests keyless signing without an OIDC token
 func TestGenerateAttestationKeylessError(t *testing.T) {
+	t.Setenv("SIGSTORE_ID_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+
 	gen := NewAttestationGenerator(AttestationOptions{
 		Format:     AttestationFormatSLSA,
 		UseKeyless: true,
@@ -316,13 +323,12 @@ func TestGenerateAttestationKeylessError(t *testing.T) {
 
 	_, err := gen.GenerateAttestation(ctx, bundlePath)
 	if err == nil {
-		t.Error("Expected error for keyless signing, got nil")
+		t.Fatal("Expected error for keyless signing without a token, got nil")
 	}
 
-	// Error should mention keyless not implemented
-	expectedMsg := "keyless signing not yet implemented"
-	if err != nil && err.Error() != expectedMsg {
-		t.Logf("Got error: %v", err)
+	// The caller can fall back to another signing mode
+	if !errors.Is(err, keyless.ErrNoToken) {
+		t.Errorf("Expected keyless.ErrNoToken, got: %v", err)
 	}
 }
 
@@ -0,0 +1,261 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"gopkg.in/yaml.v3"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
+)
+
+var (
+	// ErrAttestationNotFound indicates the bundle has no embedded attestation
+	ErrAttestationNotFound = errors.New("bundle has no embedded attestation")
+
+	// ErrAttestationSignatureInvalid indicates the attestation signature does not verify
+	ErrAttestationSignatureInvalid = errors.New("attestation signature is invalid")
+
+	// ErrAttestationIdentityMismatch indicates the signer is not a trusted identity
+	ErrAttestationIdentityMismatch = errors.New("attestation signer identity mismatch")
+
+	// ErrRekorEntryInvalid indicates the transparency log entry is missing or does not match
+	ErrRekorEntryInvalid = errors.New("Rekor entry verification failed")
+)
+
+// AttestationReport summarizes a verified embedded attestation.
+type AttestationReport struct {
+	// Format is the attestation format (sigstore, in-toto, slsa)
+	Format AttestationFormat
+
+	// PredicateType identifies the attested predicate
+	PredicateType string
+
+	// Signer is the verified signer identity
+	Signer string
+
+	// Timestamp is when the attestation was created
+	Timestamp time.Time
+
+	// Subjects are the artifacts covered by the signature
+	Subjects []AttestationSubject
+
+	// HasRekorEntry reports whether a transparency log entry is attached
+	HasRekorEntry bool
+
+	// RekorLogIndex is the log index of the verified Rekor entry
+	RekorLogIndex int64
+
+	// Keyless reports whether the signer is named by a Fulcio certificate
+	Keyless bool
+}
+
+// attestationStatement is the subset of the signed in-toto statement needed for verification
+type attestationStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []AttestationSubject `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+}
+
+// decodeStatement parses a signed in-toto statement payload.
+func decodeStatement(payload []byte) (*attestationStatement, error) {
+	var statement attestationStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("failed to parse signed statement: %w", err)
+	}
+	return &statement, nil
+}
+
+// SignedSubjects returns the subjects from the signed payload, which may
+// include more than the primary Subject (e.g. the bundle manifest).
+func (a *Attestation) SignedSubjects() ([]AttestationSubject, error) {
+	if a.Payload == "" {
+		return []AttestationSubject{a.Subject}, nil
+	}
+
+	payload, err := decodePayload(a.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := decodeStatement(payload)
+	if err != nil {
+		return nil, err
+	}
+	return statement.Subject, nil
+}
+
+// SignerIdentity returns the identity of the attestation signer. For
+// keyless signatures this is the email or URI in the signing certificate,
+// which is only verified when the certificate chains to trusted roots; for
+// key-based signatures the fingerprint of the signing public key.
+func (a *Attestation) SignerIdentity() (string, error) {
+	if a.Signature.Certificate != "" {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(a.Signature.Certificate))
+		if err != nil || len(certs) == 0 {
+			return "", fmt.Errorf("failed to parse signing certificate")
+		}
+		if identity := keyless.CertificateIdentity(certs[0]); identity != "" {
+			return identity, nil
+		}
+		return "", fmt.Errorf("signing certificate has no identity")
+	}
+	if a.Signature.PublicKey == "" {
+		return "", fmt.Errorf("attestation has no public key")
+	}
+	return PublicKeyFingerprint([]byte(a.Signature.PublicKey))
+}
+
+// PublicKeyFingerprint returns "sha256:<hex>" of the DER encoding of a PEM public key.
+func PublicKeyFingerprint(pemData []byte) (string, error) {
+	pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(pemData)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	der, err := cryptoutils.MarshalPublicKeyToDER(pubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// verifyIdentity checks the signer against the trusted identities. Entries
+// may be exact identities or path.Match patterns. A certificate identity
+// only counts when the certificate was checked against TrustedRootPath.
+func (v *AttestationVerifier) verifyIdentity(attestation *Attestation) error {
+	identity, err := attestation.SignerIdentity()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAttestationIdentityMismatch, err)
+	}
+	if attestation.Signature.Certificate != "" && v.opts.TrustedRootPath == "" {
+		return fmt.Errorf("%w: certificate identity %s cannot be verified without trusted roots", ErrAttestationIdentityMismatch, identity)
+	}
+
+	for _, trusted := range v.opts.TrustedIdentities {
+		if trusted == identity {
+			return nil
+		}
+		if matched, _ := path.Match(trusted, identity); matched {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: signer %s is not trusted", ErrAttestationIdentityMismatch, identity)
+}
+
+// VerifyEmbeddedAttestation verifies the attestation stored inside a bundle.
+// The attested manifest digest is compared to the bundle's manifest, which
+// pins every file checksum, since the bundle file itself changes when the
+// attestation is embedded.
+func VerifyEmbeddedAttestation(ctx context.Context, bundlePath string, opts AttestationVerificationOptions) (*AttestationReport, error) {
+	tempDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	defer cleanupOnError(tempDir)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "attestations", "attestation.yaml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrAttestationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation: %w", err)
+	}
+
+	var attestation Attestation
+	if err := yaml.Unmarshal(data, &attestation); err != nil {
+		return nil, fmt.Errorf("failed to parse attestation: %w", err)
+	}
+
+	// Signature and identity checks; the bundle file digest is skipped
+	// because embedding the attestation rewrote the archive
+	verifier := NewAttestationVerifier(opts)
+	if err := verifier.VerifyAttestation(ctx, &attestation, ""); err != nil {
+		return nil, err
+	}
+
+	subjects, err := attestation.SignedSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAttestationSignatureInvalid, err)
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(tempDir, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	manifestSum := sha256.Sum256(manifestData)
+	manifestDigest := hex.EncodeToString(manifestSum[:])
+
+	manifestAttested := false
+	for _, subject := range subjects {
+		if subject.Name != ManifestFileName {
+			continue
+		}
+		if subject.Digest["sha256"] != manifestDigest {
+			return nil, fmt.Errorf("attestation manifest digest mismatch: expected %s, got %s",
+				manifestDigest, subject.Digest["sha256"])
+		}
+		manifestAttested = true
+	}
+	if !manifestAttested {
+		return nil, fmt.Errorf("attestation does not cover the bundle manifest")
+	}
+
+	signer, err := attestation.SignerIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AttestationReport{
+		Format:        attestation.Format,
+		PredicateType: attestation.PredicateType,
+		Signer:        signer,
+		Timestamp:     attestation.Timestamp,
+		Subjects:      subjects,
+		HasRekorEntry: attestation.HasRekorEntry(),
+		Keyless:       attestation.Signature.Certificate != "",
+	}
+	if report.HasRekorEntry {
+		report.RekorLogIndex = attestation.RekorEntry.LogIndex
+	}
+	return report, nil
+}
+
+// readManifestDigest returns the hex SHA-256 digest of a bundle's manifest.yaml.
+func readManifestDigest(bundlePath string) (string, error) {
+	tempDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	defer cleanupOnError(tempDir)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ManifestFileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// decodePayload decodes a base64 attestation payload.
+func decodePayload(encoded string) ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return payload, nil
+}
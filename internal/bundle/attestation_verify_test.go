@@ -0,0 +1,227 @@
+package bundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless/keylesstest"
+)
+
+// buildAttestedBundle creates a bundle with an embedded key-signed attestation.
+func buildAttestedBundle(t *testing.T, format AttestationFormat) (string, *Attestation) {
+	t.Helper()
+
+	keyPath := filepath.Join("testdata", "test-ec-key.pem")
+	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
+		t.Skipf("Test key not found: %s", keyPath)
+	}
+
+	return buildBundleWithAttestation(t, AttestationOptions{Format: format, KeyPath: keyPath})
+}
+
+// buildBundleWithAttestation creates a bundle with an embedded attestation
+// generated with opts.
+func buildBundleWithAttestation(t *testing.T, opts AttestationOptions) (string, *Attestation) {
+	t.Helper()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"docs/README.md": "readme"})
+
+	builder, err := NewBuilder(BundleOptions{
+		ProjectRoot:  root,
+		IncludePaths: []string{"docs"},
+	})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "attested.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	gen := NewAttestationGenerator(opts)
+	attestation, err := gen.GenerateAttestation(context.Background(), bundlePath)
+	require.NoError(t, err)
+	require.NoError(t, AddAttestationToBundle(bundlePath, attestation))
+
+	return bundlePath, attestation
+}
+
+func TestVerifyEmbeddedAttestation(t *testing.T) {
+	for _, format := range []AttestationFormat{AttestationFormatSigstore, AttestationFormatInToto, AttestationFormatSLSA} {
+		t.Run(string(format), func(t *testing.T) {
+			bundlePath, _ := buildAttestedBundle(t, format)
+
+			report, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+				VerifySignature: true,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, format, report.Format)
+			assert.Contains(t, report.Signer, "sha256:")
+			assert.False(t, report.Timestamp.IsZero())
+			require.Len(t, report.Subjects, 2)
+			assert.Equal(t, ManifestFileName, report.Subjects[1].Name)
+
+			// The signer's own fingerprint is accepted as an expected identity
+			_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+				VerifySignature:   true,
+				TrustedIdentities: []string{report.Signer},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyEmbeddedAttestation_TamperedSignature(t *testing.T) {
+	bundlePath, attestation := buildAttestedBundle(t, AttestationFormatSigstore)
+
+	// Swap the payload for a different statement while keeping the signature
+	tampered := *attestation
+	tampered.Payload = "eyJfdHlwZSI6ImZvcmdlZCJ9"
+	require.NoError(t, AddAttestationToBundle(bundlePath, &tampered))
+
+	_, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrAttestationSignatureInvalid)
+}
+
+func TestVerifyEmbeddedAttestation_IdentityMismatch(t *testing.T) {
+	bundlePath, _ := buildAttestedBundle(t, AttestationFormatSLSA)
+
+	_, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"sha256:0000"},
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrAttestationIdentityMismatch)
+}
+
+func TestVerifyEmbeddedAttestation_NoAttestation(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"docs/README.md": "readme"})
+
+	builder, err := NewBuilder(BundleOptions{ProjectRoot: root, IncludePaths: []string{"docs"}})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "plain.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+	})
+	assert.ErrorIs(t, err, ErrAttestationNotFound)
+}
+
+func TestVerifyEmbeddedAttestation_Keyless(t *testing.T) {
+	fulcio := keylesstest.NewFulcio(t)
+	bundlePath, attestation := buildBundleWithAttestation(t, AttestationOptions{
+		Format:     AttestationFormatSigstore,
+		UseKeyless: true,
+		FulcioURL:  fulcio.URL,
+		IDToken:    keylesstest.Token("release@example.com"),
+		OIDCIssuer: keylesstest.Issuer,
+	})
+	assert.NotEmpty(t, attestation.Signature.Certificate)
+
+	rootPath := filepath.Join(t.TempDir(), "fulcio.pem")
+	require.NoError(t, os.WriteFile(rootPath, fulcio.RootPEM, 0o600))
+
+	report, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"*@example.com"},
+		TrustedRootPath:   rootPath,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "release@example.com", report.Signer)
+	assert.True(t, report.Keyless)
+
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"ci@example.com"},
+		TrustedRootPath:   rootPath,
+	})
+	assert.ErrorIs(t, err, ErrAttestationIdentityMismatch)
+
+	// The certificate identity is not trusted without roots
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"release@example.com"},
+	})
+	assert.ErrorIs(t, err, ErrAttestationIdentityMismatch)
+
+	// The certificate must chain to the trusted roots
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+		TrustedRootPath: rootPath,
+	})
+	assert.NoError(t, err)
+
+	otherPath := filepath.Join(t.TempDir(), "other.pem")
+	require.NoError(t, os.WriteFile(otherPath, keylesstest.NewFulcio(t).RootPEM, 0o600))
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+		TrustedRootPath: otherPath,
+	})
+	assert.ErrorIs(t, err, ErrAttestationSignatureInvalid)
+}
+
+func TestVerifyEmbeddedAttestation_TrustedRootRequiresCertificate(t *testing.T) {
+	bundlePath, _ := buildBundleWithAttestation(t, AttestationOptions{
+		Format:          AttestationFormatSLSA,
+		UseEphemeralKey: true,
+	})
+
+	// An ephemeral key signature verifies on its own
+	report, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, report.Signer, "sha256:")
+	assert.False(t, report.Keyless)
+
+	rootPath := filepath.Join(t.TempDir(), "fulcio.pem")
+	require.NoError(t, os.WriteFile(rootPath, keylesstest.NewFulcio(t).RootPEM, 0o600))
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature: true,
+		TrustedRootPath: rootPath,
+	})
+	assert.ErrorIs(t, err, ErrAttestationSignatureInvalid)
+}
+
+func TestVerifyEmbeddedAttestation_SelfSignedCertificate(t *testing.T) {
+	bundlePath, attestation := buildAttestedBundle(t, AttestationFormatSigstore)
+
+	// Anyone can certify their own key for any identity
+	keyData, err := os.ReadFile(filepath.Join("testdata", "test-ec-key.pem"))
+	require.NoError(t, err)
+	priv, err := cryptoutils.UnmarshalPEMToPrivateKey(keyData, cryptoutils.SkipPassword)
+	require.NoError(t, err)
+	key, ok := priv.(*ecdsa.PrivateKey)
+	require.True(t, ok)
+
+	forged := *attestation
+	forged.Signature.Certificate = string(keylesstest.SelfSigned(t, key, "release@example.com"))
+	require.NoError(t, AddAttestationToBundle(bundlePath, &forged))
+
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"release@example.com"},
+	})
+	assert.ErrorIs(t, err, ErrAttestationIdentityMismatch)
+
+	rootPath := filepath.Join(t.TempDir(), "fulcio.pem")
+	require.NoError(t, os.WriteFile(rootPath, keylesstest.NewFulcio(t).RootPEM, 0o600))
+	_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: []string{"release@example.com"},
+		TrustedRootPath:   rootPath,
+	})
+	assert.ErrorIs(t, err, ErrAttestationSignatureInvalid)
+}
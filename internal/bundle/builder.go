@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -36,8 +37,10 @@ const (
 
 // Builder creates governance bundles from project files.
 type Builder struct {
-	opts   BundleOptions
-	bundle *Bundle
+	opts      BundleOptions
+	bundle    *Bundle
+	includes  *IncludeSummary
+	unchanged map[string]bool // Files carried over from the delta base
 }
 
 // NewBuilder creates a new bundle builder with the given options.
@@ -73,6 +76,18 @@ func (b *Builder) Build(outputPath string) error {
 		return fmt.Errorf("failed to calculate checksums: %w", err)
 	}
 
+	// Drop files unchanged since the base bundle
+	if b.opts.BasePath != "" {
+		if err := b.applyDeltaBase(); err != nil {
+			return fmt.Errorf("failed to compute delta: %w", err)
+		}
+	}
+
+	// Calculate manifest integrity digest
+	if err := b.calculateIntegrity(); err != nil {
+		return fmt.Errorf("failed to calculate integrity: %w", err)
+	}
+
 	// Create tarball
 	if err := b.createTarball(outputPath); err != nil {
 		return fmt.Errorf("failed to create bundle tarball: %w", err)
@@ -169,79 +184,143 @@ func (b *Builder) loadRouting() error {
 	return nil
 }
 
-// loadPolicies loads all policy files.
-func (b *Builder) loadPolicies() error {
-	policies := make([]*policy.Policy, 0, len(b.opts.PolicyPaths))
+// PolicyError describes a policy file that failed to parse or validate.
+type PolicyError struct {
+	// Path is the policy file path
+	Path string
 
-	for _, policyPath := range b.opts.PolicyPaths {
-		data, err := os.ReadFile(policyPath)
-		if err != nil {
-			return fmt.Errorf("failed to read policy file %s: %w", policyPath, err)
-		}
+	// Err is the parse or validation error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("invalid policy %s: %v", e.Path, e.Err)
+}
 
-		var pol policy.Policy
-		if unmarshalErr := yaml.Unmarshal(data, &pol); unmarshalErr != nil {
-			return fmt.Errorf("failed to parse policy file %s: %w", policyPath, unmarshalErr)
+// Unwrap returns the underlying error.
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePolicyFile loads a policy with policy.LoadPolicy and checks that
+// its tool configurations and test thresholds are usable.
+func ValidatePolicyFile(path string) (*policy.Policy, error) {
+	pol, err := policy.LoadPolicy(path)
+	if err != nil {
+		return nil, &PolicyError{Path: path, Err: err}
+	}
+
+	for name, tool := range pol.Linters {
+		if err := policy.ValidateToolConfig(tool); err != nil {
+			return nil, &PolicyError{Path: path, Err: fmt.Errorf("linter %s: %w", name, err)}
+		}
+	}
+	for name, tool := range pol.Formatters {
+		if err := policy.ValidateToolConfig(tool); err != nil {
+			return nil, &PolicyError{Path: path, Err: fmt.Errorf("formatter %s: %w", name, err)}
 		}
+	}
 
-		policies = append(policies, &pol)
+	if pol.Tests.MinCoverage < 0 || pol.Tests.MinCoverage > 1 {
+		return nil, &PolicyError{
+			Path: path,
+			Err:  fmt.Errorf("tests.min_coverage must be between 0 and 1, got %v", pol.Tests.MinCoverage),
+		}
 	}
 
-	b.bundle.Policies = policies
-	return nil
+	return pol, nil
 }
 
-// loadAdditionalFiles loads additional files specified in include paths.
-func (b *Builder) loadAdditionalFiles() error {
-	for _, includePath := range b.opts.IncludePaths {
-		info, err := os.Stat(includePath)
-		if err != nil {
-			return fmt.Errorf("failed to stat include path %s: %w", includePath, err)
+// ValidatePolicies validates every policy file and returns the invalid ones.
+func (b *Builder) ValidatePolicies() []*PolicyError {
+	var invalid []*PolicyError
+	for _, policyPath := range b.opts.PolicyPaths {
+		if _, err := ValidatePolicyFile(policyPath); err != nil {
+			var policyErr *PolicyError
+			if errors.As(err, &policyErr) {
+				invalid = append(invalid, policyErr)
+			}
 		}
+	}
+	return invalid
+}
+
+// loadPolicies loads all policy files. Invalid policies fail the build
+// unless AllowInvalidPolicies is set, in which case they are still
+// packaged but not parsed into the bundle.
+func (b *Builder) loadPolicies() error {
+	policies := make([]*policy.Policy, 0, len(b.opts.PolicyPaths))
 
-		if info.IsDir() {
-			if loadErr := b.loadDirectory(includePath); loadErr != nil {
-				return fmt.Errorf("failed to load directory %s: %w", includePath, loadErr)
+	for _, policyPath := range b.opts.PolicyPaths {
+		pol, err := ValidatePolicyFile(policyPath)
+		if err != nil {
+			if b.opts.AllowInvalidPolicies {
+				continue
 			}
-		} else {
-			if loadErr := b.loadFile(includePath); loadErr != nil {
-				return fmt.Errorf("failed to load file %s: %w", includePath, loadErr)
+			return &BundleError{
+				Operation:  "build",
+				Message:    err.Error(),
+				Suggestion: "Fix the policy file, or pass --allow-invalid-policies to package it anyway.",
+				Cause:      err,
 			}
 		}
+
+		policies = append(policies, pol)
 	}
 
+	b.bundle.Policies = policies
 	return nil
 }
 
-// loadDirectory recursively loads all files from a directory.
-func (b *Builder) loadDirectory(dirPath string) error {
-	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// Manifest returns the manifest of the most recent build.
+func (b *Builder) Manifest() *Manifest {
+	return b.bundle.Manifest
+}
 
-		if !info.IsDir() {
-			return b.loadFile(path)
-		}
+// ResolveIncludes expands the include paths into the list of files that will
+// be bundled. The result is cached and reused by Build, so callers can report
+// what was matched before the archive is written.
+func (b *Builder) ResolveIncludes() (*IncludeSummary, error) {
+	if b.includes != nil {
+		return b.includes, nil
+	}
 
-		return nil
-	})
+	summary, err := ResolveIncludes(b.opts.ProjectRoot, b.opts.IncludePaths, b.opts.ExcludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	b.includes = summary
+	return summary, nil
 }
 
-// loadFile loads a single file into additional files.
-func (b *Builder) loadFile(filePath string) error {
-	data, err := os.ReadFile(filePath)
+// loadAdditionalFiles loads additional files specified in include paths.
+func (b *Builder) loadAdditionalFiles() error {
+	summary, err := b.ResolveIncludes()
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return err
 	}
 
-	// Use relative path as key
-	relPath, err := filepath.Rel(".", filePath)
-	if err != nil {
-		relPath = filePath
+	root := b.opts.ProjectRoot
+	if root == "" {
+		root = "."
+	}
+
+	for _, relPath := range summary.Files {
+		filePath := relPath
+		if !filepath.IsAbs(relPath) {
+			filePath = filepath.Join(root, relPath)
+		}
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", filePath, err)
+		}
+
+		b.bundle.AdditionalFiles[filepath.ToSlash(relPath)] = data
 	}
 
-	b.bundle.AdditionalFiles[relPath] = data
 	return nil
 }
 
@@ -356,7 +435,11 @@ func (b *Builder) calculateChecksums() error {
 
 	b.bundle.Manifest.Files = fileEntries
 
-	// Calculate manifest integrity digest
+	return nil
+}
+
+// calculateIntegrity calculates the manifest integrity digest.
+func (b *Builder) calculateIntegrity() error {
 	manifestData, err := yaml.Marshal(b.bundle.Manifest)
 	if err != nil {
 		return fmt.Errorf("failed to marshal manifest: %w", err)
@@ -551,6 +634,11 @@ func (b *Builder) writeManifestToTar(tw *tar.Writer) error {
 
 // writeFileToTar writes a file from the filesystem to the tar archive.
 func (b *Builder) writeFileToTar(tw *tar.Writer, sourcePath, bundlePath string) error {
+	// Delta bundles omit files carried over from the base
+	if b.unchanged[bundlePath] {
+		return nil
+	}
+
 	file, err := os.Open(sourcePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -582,6 +670,11 @@ func (b *Builder) writeFileToTar(tw *tar.Writer, sourcePath, bundlePath string)
 
 // writeBytesToTar writes byte data to the tar archive.
 func (b *Builder) writeBytesToTar(tw *tar.Writer, data []byte, bundlePath string) error {
+	// Delta bundles omit files carried over from the base
+	if b.unchanged[bundlePath] {
+		return nil
+	}
+
 	header := &tar.Header{
 		Name:    bundlePath,
 		Size:    int64(len(data)),
@@ -106,9 +106,21 @@ type BundleOptions struct {
 	// PolicyPaths are paths to policy files
 	PolicyPaths []string
 
-	// IncludePaths are additional files/directories to include
+	// AllowInvalidPolicies packages policies that fail validation instead
+	// of failing the build
+	AllowInvalidPolicies bool
+
+	// IncludePaths are additional files, directories, or glob patterns to
+	// include, resolved relative to ProjectRoot
 	IncludePaths []string
 
+	// ExcludePatterns skip matching include files (filepath.Match semantics)
+	ExcludePatterns []string
+
+	// ProjectRoot is the directory include patterns are resolved against
+	// and where .specularignore is read from (default: current directory)
+	ProjectRoot string
+
 	// RequireApprovals lists required approval roles
 	RequireApprovals []string
 
@@ -120,6 +132,10 @@ type BundleOptions struct {
 
 	// GovernanceLevel indicates target governance level (L1-L4)
 	GovernanceLevel string
+
+	// BasePath is a previous bundle to build a delta against. Files whose
+	// checksums match the base are recorded in the manifest but not packaged.
+	BasePath string
 }
 
 // VerifyOptions contains options for bundle verification.
@@ -133,14 +149,26 @@ type VerifyOptions struct {
 	// RequireAttestation enforces attestation requirement
 	RequireAttestation bool
 
+	// RequireManifestSignature requires a valid detached manifest signature.
+	// A manifest signature that is present is always verified.
+	RequireManifestSignature bool
+
 	// PolicyPath is an optional policy file to verify against
 	PolicyPath string
 
 	// TrustPublicKeys are public keys to trust for signature verification
 	TrustPublicKeys []string
 
-	// AllowOffline permits offline verification (cached attestations)
+	// AllowOffline permits offline verification: the Rekor entry embedded
+	// in the attestation is checked without contacting the log
 	AllowOffline bool
+
+	// RekorURL is the Rekor server attestation entries are checked against
+	// Default: "https://rekor.sigstore.dev"
+	RekorURL string
+
+	// RequireRekorEntry requires the attestation to have a Rekor entry
+	RequireRekorEntry bool
 }
 
 // ApplyOptions contains options for applying a bundle to a project.
@@ -159,6 +187,13 @@ type ApplyOptions struct {
 
 	// Exclude patterns for files to skip
 	Exclude []string
+
+	// BasePath is the base bundle required to apply a delta bundle
+	BasePath string
+
+	// Backup snapshots the files the apply overwrites under BackupsDir in
+	// the target directory, so the apply can be undone with RestoreBackup
+	Backup bool
 }
 
 // DiffOptions contains options for comparing bundles.
@@ -292,6 +327,10 @@ type ValidationResult struct {
 	// AttestationValid indicates if attestation is valid
 	AttestationValid bool `json:"attestation_valid"`
 
+	// ManifestSignatureValid indicates if the manifest signature is valid
+	// (or absent and not required)
+	ManifestSignatureValid bool `json:"manifest_signature_valid"`
+
 	// PolicyCompliant indicates if bundle meets policy requirements
 	PolicyCompliant bool `json:"policy_compliant,omitempty"`
 }
@@ -347,6 +386,7 @@ const (
 	ErrCodeMissingApproval   = "MISSING_APPROVAL"
 	ErrCodeInvalidSignature  = "INVALID_SIGNATURE"
 	ErrCodeAttestationFailed = "ATTESTATION_FAILED"
+	ErrCodeManifestSignature = "MANIFEST_SIGNATURE_INVALID"
 	ErrCodePolicyViolation   = "POLICY_VIOLATION"
 	ErrCodeUnsupportedSchema = "UNSUPPORTED_SCHEMA"
 	ErrCodeCorruptedBundle   = "CORRUPTED_BUNDLE"
@@ -359,4 +399,5 @@ const (
 	WarnCodeDeprecatedFeature   = "DEPRECATED_FEATURE"
 	WarnCodeNoAttestation       = "NO_ATTESTATION"
 	WarnCodePartialApprovals    = "PARTIAL_APPROVALS"
+	WarnCodeUntrustedSigner     = "UNTRUSTED_SIGNER"
 )
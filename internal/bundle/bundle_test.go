@@ -140,6 +140,55 @@ rules:
 	assert.True(t, result.Valid)
 }
 
+func TestBundleWithInvalidPolicy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	validPath := filepath.Join(tempDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(validPath, []byte("tests:\n  require_pass: true\n  min_coverage: 0.8\n"), 0600))
+
+	brokenPath := filepath.Join(tempDir, "broken.yaml")
+	require.NoError(t, os.WriteFile(brokenPath, []byte("tests:\n  require_pass: [unterminated\n"), 0600))
+
+	t.Run("valid policy builds", func(t *testing.T) {
+		builder, err := NewBuilder(BundleOptions{PolicyPaths: []string{validPath}})
+		require.NoError(t, err)
+		assert.Empty(t, builder.ValidatePolicies())
+		require.NoError(t, builder.Build(filepath.Join(t.TempDir(), "valid.sbundle.tgz")))
+	})
+
+	t.Run("invalid policy fails with file name", func(t *testing.T) {
+		builder, err := NewBuilder(BundleOptions{PolicyPaths: []string{validPath, brokenPath}})
+		require.NoError(t, err)
+
+		err = builder.Build(filepath.Join(t.TempDir(), "broken.sbundle.tgz"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken.yaml")
+
+		var policyErr *PolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.Equal(t, brokenPath, policyErr.Path)
+	})
+
+	t.Run("semantic errors are caught", func(t *testing.T) {
+		badToolPath := filepath.Join(tempDir, "bad-tool.yaml")
+		require.NoError(t, os.WriteFile(badToolPath, []byte("linters:\n  golangci:\n    enabled: true\n"), 0600))
+
+		_, err := ValidatePolicyFile(badToolPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "linter golangci")
+	})
+
+	t.Run("allow invalid policies packages them", func(t *testing.T) {
+		builder, err := NewBuilder(BundleOptions{
+			PolicyPaths:          []string{validPath, brokenPath},
+			AllowInvalidPolicies: true,
+		})
+		require.NoError(t, err)
+		assert.Len(t, builder.ValidatePolicies(), 1)
+		require.NoError(t, builder.Build(filepath.Join(t.TempDir(), "allowed.sbundle.tgz")))
+	})
+}
+
 func TestBundleWithApprovals(t *testing.T) {
 	tempDir := t.TempDir()
 
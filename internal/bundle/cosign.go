@@ -0,0 +1,195 @@
+package bundle
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+const (
+	// CosignPasswordEnv holds the password of an encrypted cosign key, as it
+	// does for cosign itself
+	CosignPasswordEnv = "COSIGN_PASSWORD"
+
+	// DefaultCosignKeyPath is where 'cosign generate-key-pair' writes the
+	// private key
+	DefaultCosignKeyPath = "cosign.key"
+)
+
+// Cosign signatures are made over the bundle file itself rather than the
+// approval message, so that 'cosign verify-blob' accepts them:
+//
+//	cosign verify-blob --key cosign.pub --signature bundle.sbundle.tgz.sig bundle.sbundle.tgz
+//
+// The signature is the base64 encoding of an ECDSA (ASN.1) or RSA (PKCS #1
+// v1.5) signature of the bundle's SHA-256 digest. Ed25519 keys sign the
+// whole file in cosign and are not supported.
+
+// signWithCosign creates a cosign signature of the bundle digest with a
+// cosign or PKCS #8 private key
+func (s *Signer) signWithCosign(approval *Approval, digest, keyPath, certPath string) error {
+	// The signature only covers the bundle, so an expiry could be removed
+	// without invalidating it
+	if approval.ExpiresAt != nil {
+		return fmt.Errorf("cosign signatures cannot carry an approval expiry")
+	}
+
+	hash, err := cosignDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+	privateKey, err := cryptoutils.UnmarshalPEMToPrivateKey(keyPEM, cryptoutils.StaticPasswordFunc([]byte(os.Getenv(CosignPasswordEnv))))
+	if err != nil {
+		return fmt.Errorf("failed to parse private key (set %s for encrypted keys): %w", CosignPasswordEnv, err)
+	}
+
+	var signature []byte
+	switch key := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		signature, err = ecdsa.SignASN1(rand.Reader, key, hash)
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+	default:
+		return fmt.Errorf("unsupported key type %T (use an ECDSA or RSA key)", privateKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sign bundle digest: %w", err)
+	}
+
+	publicKey := privateKey.(crypto.Signer).Public()
+	if certPath != "" {
+		certPEM, readErr := os.ReadFile(certPath)
+		if readErr != nil {
+			return fmt.Errorf("failed to read certificate: %w", readErr)
+		}
+		certs, parseErr := cryptoutils.UnmarshalCertificatesFromPEM(certPEM)
+		if parseErr != nil || len(certs) == 0 {
+			return fmt.Errorf("failed to parse certificate %s", certPath)
+		}
+		if keyErr := cryptoutils.EqualKeys(certs[0].PublicKey, publicKey); keyErr != nil {
+			return fmt.Errorf("certificate does not match the signing key: %w", keyErr)
+		}
+		approval.Certificate = string(certPEM)
+	}
+
+	publicKeyPEM, err := cryptoutils.MarshalPublicKeyToPEM(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode public key: %w", err)
+	}
+	approval.PublicKey = string(publicKeyPEM)
+	approval.PublicKeyFingerprint, err = cosignKeyFingerprint(publicKey)
+	if err != nil {
+		return err
+	}
+	approval.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	return nil
+}
+
+// verifyCosignSignature verifies a cosign signature of the bundle digest. An
+// attached certificate must hold the approval's public key and have been
+// valid when the approval was signed; its chain is not checked here.
+func (v *Verifier) verifyCosignSignature(approval *Approval) error {
+	hash, err := cosignDigest(v.options.BundleDigest)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := cryptoutils.UnmarshalPEMToPublicKey([]byte(approval.PublicKey))
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	if approval.Certificate != "" {
+		certs, parseErr := cryptoutils.UnmarshalCertificatesFromPEM([]byte(approval.Certificate))
+		if parseErr != nil || len(certs) == 0 {
+			return fmt.Errorf("failed to parse certificate")
+		}
+		if keyErr := cryptoutils.EqualKeys(certs[0].PublicKey, publicKey); keyErr != nil {
+			return fmt.Errorf("certificate does not match the public key: %w", keyErr)
+		}
+		if expErr := cryptoutils.CheckExpiration(certs[0], approval.SignedAt); expErr != nil {
+			return fmt.Errorf("certificate was not valid at signing time: %w", expErr)
+		}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(approval.Signature))
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hash, signature) {
+			return fmt.Errorf("signature verification failed")
+		}
+	case *rsa.PublicKey:
+		if verifyErr := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature); verifyErr != nil {
+			return fmt.Errorf("signature verification failed: %w", verifyErr)
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", publicKey)
+	}
+
+	return nil
+}
+
+// WriteCosignSignature writes a cosign approval's signature next to the
+// bundle as <bundle>.sig, and its certificate, if any, as <bundle>.pem. These
+// are the files 'cosign verify-blob' takes with --signature and
+// --certificate.
+func WriteCosignSignature(approval *Approval, bundlePath string) (sigPath, certPath string, err error) {
+	if approval.SignatureType != SignatureTypeCosign {
+		return "", "", fmt.Errorf("approval has a %s signature, not cosign", approval.SignatureType)
+	}
+
+	sigPath = bundlePath + ".sig"
+	if err := os.WriteFile(sigPath, []byte(approval.Signature), 0600); err != nil {
+		return "", "", fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	if approval.Certificate != "" {
+		certPath = bundlePath + ".pem"
+		if err := os.WriteFile(certPath, []byte(approval.Certificate), 0600); err != nil {
+			return "", "", fmt.Errorf("failed to write certificate: %w", err)
+		}
+	}
+	return sigPath, certPath, nil
+}
+
+// cosignDigest decodes a "sha256:<hex>" bundle digest
+func cosignDigest(digest string) ([]byte, error) {
+	hexDigest, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return nil, fmt.Errorf("cosign signatures require a sha256 bundle digest, got %q", digest)
+	}
+	hash, err := hex.DecodeString(hexDigest)
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("invalid bundle digest %q", digest)
+	}
+	return hash, nil
+}
+
+// cosignKeyFingerprint is the SHA-256 fingerprint of a public key's DER
+// encoding
+func cosignKeyFingerprint(publicKey crypto.PublicKey) (string, error) {
+	der, err := cryptoutils.MarshalPublicKeyToDER(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return fmt.Sprintf("SHA256:%x", sha256.Sum256(der)), nil
+}
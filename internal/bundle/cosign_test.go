@@ -0,0 +1,226 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCosignKey writes an encrypted cosign key pair the way
+// 'cosign generate-key-pair' does and returns the private key path
+func writeCosignKey(t *testing.T, password string) (string, []byte) {
+	t.Helper()
+	privPEM, pubPEM, err := cryptoutils.GeneratePEMEncodedECDSAKeyPair(elliptic.P256(), cryptoutils.StaticPasswordFunc([]byte(password)))
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "cosign.key")
+	require.NoError(t, os.WriteFile(keyPath, privPEM, 0600))
+	return keyPath, pubPEM
+}
+
+// writeTestBundleFile writes bundle contents and returns its path and digest
+func writeTestBundleFile(t *testing.T) (string, string) {
+	t.Helper()
+	bundlePath := filepath.Join(t.TempDir(), "app.sbundle.tgz")
+	require.NoError(t, os.WriteFile(bundlePath, []byte("bundle contents"), 0600))
+	digest, err := ComputeBundleDigest(bundlePath)
+	require.NoError(t, err)
+	return bundlePath, digest
+}
+
+func signCosign(t *testing.T, keyPath, certPath, digest string) (*Approval, error) {
+	t.Helper()
+	return NewSigner(SignatureTypeCosign, keyPath).SignApproval(ApprovalRequest{
+		BundleDigest:    digest,
+		Role:            "security",
+		User:            "bob@example.com",
+		CertificatePath: certPath,
+	})
+}
+
+func TestSigner_SignApproval_Cosign(t *testing.T) {
+	t.Setenv(CosignPasswordEnv, "s3cret")
+	keyPath, pubPEM := writeCosignKey(t, "s3cret")
+	bundlePath, digest := writeTestBundleFile(t)
+
+	approval, err := signCosign(t, keyPath, "", digest)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureTypeCosign, approval.SignatureType)
+	assert.Equal(t, string(pubPEM), approval.PublicKey)
+	assert.Contains(t, approval.PublicKeyFingerprint, "SHA256:")
+
+	verifier := NewVerifier(ApprovalVerificationOptions{BundleDigest: digest})
+	require.NoError(t, verifier.VerifyApproval(approval))
+
+	// Another bundle's digest does not verify
+	otherDigest := "sha256:" + strings.Repeat("0", 64)
+	err = NewVerifier(ApprovalVerificationOptions{BundleDigest: otherDigest}).VerifyApproval(approval)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cosign signature verification failed")
+
+	// The detached signature verifies over the bundle file the way
+	// 'cosign verify-blob --key' checks it
+	sigPath, certPath, err := WriteCosignSignature(approval, bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, bundlePath+".sig", sigPath)
+	assert.Empty(t, certPath)
+
+	sigFile, err := os.ReadFile(sigPath)
+	require.NoError(t, err)
+	rawSig, err := base64.StdEncoding.DecodeString(string(sigFile))
+	require.NoError(t, err)
+	publicKey, err := cryptoutils.UnmarshalPEMToPublicKey(pubPEM)
+	require.NoError(t, err)
+	cosignVerifier, err := signature.LoadVerifier(publicKey, crypto.SHA256)
+	require.NoError(t, err)
+	blob, err := os.ReadFile(bundlePath)
+	require.NoError(t, err)
+	require.NoError(t, cosignVerifier.VerifySignature(bytes.NewReader(rawSig), bytes.NewReader(blob)))
+}
+
+func TestSigner_SignApproval_CosignWrongPassword(t *testing.T) {
+	t.Setenv(CosignPasswordEnv, "wrong")
+	keyPath, _ := writeCosignKey(t, "s3cret")
+	_, digest := writeTestBundleFile(t)
+
+	_, err := signCosign(t, keyPath, "", digest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), CosignPasswordEnv)
+}
+
+func TestSigner_SignApproval_CosignKeyTypes(t *testing.T) {
+	_, digest := writeTestBundleFile(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     crypto.PrivateKey
+		wantErr string
+	}{
+		{name: "rsa", key: rsaKey},
+		{name: "ed25519", key: edKey, wantErr: "unsupported key type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyPEM, err := cryptoutils.MarshalPrivateKeyToPEM(tt.key)
+			require.NoError(t, err)
+			keyPath := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, os.WriteFile(keyPath, keyPEM, 0600))
+
+			approval, err := signCosign(t, keyPath, "", digest)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, NewVerifier(ApprovalVerificationOptions{BundleDigest: digest}).VerifyApproval(approval))
+		})
+	}
+}
+
+// writeCertificate writes a self-signed certificate for key
+func writeCertificate(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bob@example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(t.TempDir(), "signer.pem")
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0600))
+	return certPath
+}
+
+func TestSigner_SignApproval_CosignCertificate(t *testing.T) {
+	bundlePath, digest := writeTestBundleFile(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyPEM, err := cryptoutils.MarshalPrivateKeyToPEM(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0600))
+
+	t.Run("matching certificate", func(t *testing.T) {
+		approval, err := signCosign(t, keyPath, writeCertificate(t, key), digest)
+		require.NoError(t, err)
+		assert.NotEmpty(t, approval.Certificate)
+		require.NoError(t, NewVerifier(ApprovalVerificationOptions{BundleDigest: digest}).VerifyApproval(approval))
+
+		_, certPath, err := WriteCosignSignature(approval, bundlePath)
+		require.NoError(t, err)
+		assert.Equal(t, approval.Certificate, readFile(t, certPath))
+	})
+
+	t.Run("certificate for another key", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		_, err = signCosign(t, keyPath, writeCertificate(t, otherKey), digest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate does not match the signing key")
+	})
+
+	t.Run("swapped certificate fails verification", func(t *testing.T) {
+		approval, err := signCosign(t, keyPath, "", digest)
+		require.NoError(t, err)
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		approval.Certificate = readFile(t, writeCertificate(t, otherKey))
+
+		err = NewVerifier(ApprovalVerificationOptions{BundleDigest: digest}).VerifyApproval(approval)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate does not match the public key")
+	})
+}
+
+func TestSigner_SignApproval_CosignRejectsExpiry(t *testing.T) {
+	keyPath, _ := writeCosignKey(t, "")
+	_, digest := writeTestBundleFile(t)
+
+	expiresAt := time.Now().Add(time.Hour)
+	_, err := NewSigner(SignatureTypeCosign, keyPath).SignApproval(ApprovalRequest{
+		BundleDigest: digest,
+		Role:         "security",
+		User:         "bob@example.com",
+		ExpiresAt:    &expiresAt,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot carry an approval expiry")
+}
+
+func TestWriteCosignSignature_RequiresCosignApproval(t *testing.T) {
+	_, _, err := WriteCosignSignature(&Approval{SignatureType: SignatureTypeSSH}, filepath.Join(t.TempDir(), "b.tgz"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not cosign")
+}
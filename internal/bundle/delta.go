@@ -0,0 +1,128 @@
+package bundle
+
+import (
+	"fmt"
+)
+
+// IsDelta reports whether the manifest describes a delta bundle.
+func (m *Manifest) IsDelta() bool {
+	return m != nil && m.Delta != nil
+}
+
+// applyDeltaBase compares the bundle files with the base bundle's manifest
+// checksums. Unchanged files are moved from Files to Delta.Unchanged and are
+// not written to the archive.
+func (b *Builder) applyDeltaBase() error {
+	base, err := LoadBundle(b.opts.BasePath)
+	if err != nil {
+		return &BundleError{
+			Operation:  "build",
+			Message:    fmt.Sprintf("failed to load base bundle %s", b.opts.BasePath),
+			Suggestion: "Check that --base points to a valid, unmodified bundle.",
+			Cause:      err,
+		}
+	}
+
+	if base.Manifest.IsDelta() {
+		return &BundleError{
+			Operation:  "build",
+			Message:    fmt.Sprintf("base bundle %s is itself a delta bundle", b.opts.BasePath),
+			Suggestion: "Use a full bundle as the base for a delta.",
+		}
+	}
+
+	baseDigest, err := ComputeBundleDigest(b.opts.BasePath)
+	if err != nil {
+		return fmt.Errorf("failed to compute base bundle digest: %w", err)
+	}
+
+	diff, err := DiffBundles(base, b.bundle)
+	if err != nil {
+		return fmt.Errorf("failed to diff against base bundle: %w", err)
+	}
+
+	changed := make(map[string]bool, len(diff.FilesAdded)+len(diff.FilesModified))
+	for _, file := range diff.FilesAdded {
+		changed[file.Path] = true
+	}
+	for _, file := range diff.FilesModified {
+		changed[file.Path] = true
+	}
+
+	delta := &DeltaInfo{
+		BaseID:      base.Manifest.ID,
+		BaseVersion: base.Manifest.Version,
+		BaseDigest:  baseDigest,
+	}
+	for _, file := range diff.FilesRemoved {
+		delta.Removed = append(delta.Removed, file.Path)
+	}
+
+	b.unchanged = make(map[string]bool)
+	files := make([]FileEntry, 0, len(changed))
+	for _, file := range b.bundle.Manifest.Files {
+		if changed[file.Path] {
+			files = append(files, file)
+			continue
+		}
+		delta.Unchanged = append(delta.Unchanged, file)
+		b.unchanged[file.Path] = true
+		delete(b.bundle.Checksums, file.Path)
+	}
+
+	b.bundle.Manifest.Files = files
+	b.bundle.Manifest.Delta = delta
+
+	return nil
+}
+
+// VerifyDeltaBase checks that basePath is the bundle a delta manifest was
+// built against: its digest must match and it must contain every file the
+// delta carries over unchanged.
+func VerifyDeltaBase(manifest *Manifest, basePath string) error {
+	if !manifest.IsDelta() {
+		return nil
+	}
+
+	if basePath == "" {
+		return &BundleError{
+			Operation: "apply",
+			Message: fmt.Sprintf("delta bundle requires its base bundle %s@%s",
+				manifest.Delta.BaseID, manifest.Delta.BaseVersion),
+			Suggestion: "Pass the base bundle with --base.",
+		}
+	}
+
+	digest, err := ComputeBundleDigest(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to compute base bundle digest: %w", err)
+	}
+
+	if digest != manifest.Delta.BaseDigest {
+		return &BundleError{
+			Operation:  "apply",
+			Message:    fmt.Sprintf("base bundle digest mismatch: expected %s, got %s", manifest.Delta.BaseDigest, digest),
+			Suggestion: fmt.Sprintf("Use the exact %s@%s bundle the delta was created from.", manifest.Delta.BaseID, manifest.Delta.BaseVersion),
+		}
+	}
+
+	base, err := LoadBundle(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load base bundle: %w", err)
+	}
+
+	baseFiles := make(map[string]string, len(base.Manifest.Files))
+	for _, file := range base.Manifest.Files {
+		baseFiles[file.Path] = file.Checksum
+	}
+	for _, file := range manifest.Delta.Unchanged {
+		if baseFiles[file.Path] != file.Checksum {
+			return &BundleError{
+				Operation: "apply",
+				Message:   fmt.Sprintf("base bundle does not contain unchanged file %s", file.Path),
+			}
+		}
+	}
+
+	return nil
+}
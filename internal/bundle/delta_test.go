@@ -0,0 +1,109 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildIncludeBundle builds a bundle of root's docs directory.
+func buildIncludeBundle(t *testing.T, root, basePath, outputPath string) *Builder {
+	t.Helper()
+
+	builder, err := NewBuilder(BundleOptions{
+		ProjectRoot:  root,
+		IncludePaths: []string{"docs"},
+		BasePath:     basePath,
+	})
+	require.NoError(t, err)
+	require.NoError(t, builder.Build(outputPath))
+	return builder
+}
+
+func TestDeltaBundle(t *testing.T) {
+	root := t.TempDir()
+	outDir := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/unchanged.md": "same",
+		"docs/modified.md":  "v1",
+		"docs/removed.md":   "gone soon",
+	})
+
+	basePath := filepath.Join(outDir, "base.sbundle.tgz")
+	buildIncludeBundle(t, root, "", basePath)
+
+	require.NoError(t, os.Remove(filepath.Join(root, "docs", "removed.md")))
+	writeTree(t, root, map[string]string{
+		"docs/modified.md": "v2",
+		"docs/added.md":    "new",
+	})
+
+	deltaPath := filepath.Join(outDir, "delta.sbundle.tgz")
+	builder := buildIncludeBundle(t, root, basePath, deltaPath)
+
+	manifest := builder.Manifest()
+	require.True(t, manifest.IsDelta())
+
+	baseDigest, err := ComputeBundleDigest(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, baseDigest, manifest.Delta.BaseDigest)
+
+	var changed []string
+	for _, file := range manifest.Files {
+		changed = append(changed, file.Path)
+	}
+	assert.ElementsMatch(t, []string{"docs/modified.md", "docs/added.md"}, changed)
+	require.Len(t, manifest.Delta.Unchanged, 1)
+	assert.Equal(t, "docs/unchanged.md", manifest.Delta.Unchanged[0].Path)
+	assert.Equal(t, []string{"docs/removed.md"}, manifest.Delta.Removed)
+
+	// The delta bundle is self-consistent and verifies on its own
+	loaded, err := LoadBundle(deltaPath)
+	require.NoError(t, err)
+	assert.True(t, loaded.Manifest.IsDelta())
+
+	t.Run("apply requires base", func(t *testing.T) {
+		err := NewExtractor(ApplyOptions{TargetDir: t.TempDir(), Yes: true}).Apply(deltaPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "requires its base bundle")
+	})
+
+	t.Run("apply rejects wrong base", func(t *testing.T) {
+		err := NewExtractor(ApplyOptions{TargetDir: t.TempDir(), Yes: true, BasePath: deltaPath}).Apply(deltaPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "digest mismatch")
+	})
+
+	t.Run("apply writes only changed files", func(t *testing.T) {
+		target := t.TempDir()
+		require.NoError(t, NewExtractor(ApplyOptions{TargetDir: target, Yes: true, BasePath: basePath}).Apply(deltaPath))
+
+		data, err := os.ReadFile(filepath.Join(target, "docs", "modified.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "v2", string(data))
+		assert.FileExists(t, filepath.Join(target, "docs", "added.md"))
+		assert.NoFileExists(t, filepath.Join(target, "docs", "unchanged.md"))
+	})
+}
+
+func TestDeltaBundle_BaseMustBeFull(t *testing.T) {
+	root := t.TempDir()
+	outDir := t.TempDir()
+	writeTree(t, root, map[string]string{"docs/a.md": "a"})
+
+	basePath := filepath.Join(outDir, "base.sbundle.tgz")
+	buildIncludeBundle(t, root, "", basePath)
+
+	writeTree(t, root, map[string]string{"docs/a.md": "b"})
+	deltaPath := filepath.Join(outDir, "delta.sbundle.tgz")
+	buildIncludeBundle(t, root, basePath, deltaPath)
+
+	builder, err := NewBuilder(BundleOptions{ProjectRoot: root, IncludePaths: []string{"docs"}, BasePath: deltaPath})
+	require.NoError(t, err)
+	err = builder.Build(filepath.Join(outDir, "chained.sbundle.tgz"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is itself a delta bundle")
+}
@@ -3,8 +3,48 @@ package bundle
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
+// DiffCategory selects a kind of change reported by a bundle diff.
+type DiffCategory string
+
+const (
+	// DiffCategoryFiles covers added, removed, and modified files
+	DiffCategoryFiles DiffCategory = "files"
+
+	// DiffCategoryApprovals covers added and removed approvals
+	DiffCategoryApprovals DiffCategory = "approvals"
+
+	// DiffCategoryAttestation covers attestation changes
+	DiffCategoryAttestation DiffCategory = "attestation"
+
+	// DiffCategoryMetadata covers manifest metadata changes
+	DiffCategoryMetadata DiffCategory = "metadata"
+)
+
+// ParseDiffCategories parses diff category names, accepting
+// comma-separated values.
+func ParseDiffCategories(values []string) ([]DiffCategory, error) {
+	var categories []DiffCategory
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			switch category := DiffCategory(name); category {
+			case DiffCategoryFiles, DiffCategoryApprovals, DiffCategoryAttestation, DiffCategoryMetadata:
+				categories = append(categories, category)
+			default:
+				return nil, fmt.Errorf("unknown diff category %q (expected files, approvals, attestation, or metadata)", name)
+			}
+		}
+	}
+	return categories, nil
+}
+
 // DiffResult represents the differences between two bundles.
 type DiffResult struct {
 	FilesAdded              []FileEntry
@@ -207,6 +247,47 @@ func compareManifestMetadata(manifestA, manifestB *Manifest, result *DiffResult)
 	}
 }
 
+// Filter returns a copy of the result that only reports changes in the given
+// categories. With no categories the full result is returned.
+func (r *DiffResult) Filter(categories ...DiffCategory) *DiffResult {
+	if len(categories) == 0 {
+		return r
+	}
+
+	selected := make(map[DiffCategory]bool, len(categories))
+	for _, category := range categories {
+		selected[category] = true
+	}
+
+	filtered := &DiffResult{
+		FilesAdded:              []FileEntry{},
+		FilesRemoved:            []FileEntry{},
+		FilesModified:           []FileChange{},
+		ApprovalsAdded:          []*Approval{},
+		ApprovalsRemoved:        []*Approval{},
+		ManifestMetadataChanges: make(map[string]string),
+	}
+
+	if selected[DiffCategoryFiles] {
+		filtered.FilesAdded = r.FilesAdded
+		filtered.FilesRemoved = r.FilesRemoved
+		filtered.FilesModified = r.FilesModified
+	}
+	if selected[DiffCategoryApprovals] {
+		filtered.ApprovalsAdded = r.ApprovalsAdded
+		filtered.ApprovalsRemoved = r.ApprovalsRemoved
+	}
+	if selected[DiffCategoryAttestation] {
+		filtered.AttestationChanged = r.AttestationChanged
+	}
+	if selected[DiffCategoryMetadata] {
+		filtered.MetadataChanged = r.MetadataChanged
+		filtered.ManifestMetadataChanges = r.ManifestMetadataChanges
+	}
+
+	return filtered
+}
+
 // HasChanges returns true if there are any differences between bundles.
 func (r *DiffResult) HasChanges() bool {
 	return len(r.FilesAdded) > 0 ||
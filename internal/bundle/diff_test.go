@@ -0,0 +1,79 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffFixture returns two bundles that differ in files, approvals, and metadata
+func diffFixture() (*Bundle, *Bundle) {
+	a := &Bundle{
+		Manifest: &Manifest{
+			ID:      "acme/api",
+			Version: "1.0.0",
+			Files: []FileEntry{
+				{Path: "spec.yaml", Checksum: "aaaa"},
+				{Path: "routing.yaml", Checksum: "bbbb"},
+			},
+		},
+	}
+	b := &Bundle{
+		Manifest: &Manifest{
+			ID:      "acme/api",
+			Version: "1.1.0",
+			Files: []FileEntry{
+				{Path: "spec.yaml", Checksum: "cccc"},
+				{Path: "policy.yaml", Checksum: "dddd"},
+			},
+		},
+		Approvals: []*Approval{{Role: "security", User: "sam@example.com"}},
+	}
+	return a, b
+}
+
+func TestParseDiffCategories(t *testing.T) {
+	categories, err := ParseDiffCategories([]string{"files, Approvals", "metadata"})
+	require.NoError(t, err)
+	assert.Equal(t, []DiffCategory{DiffCategoryFiles, DiffCategoryApprovals, DiffCategoryMetadata}, categories)
+
+	categories, err = ParseDiffCategories(nil)
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+
+	_, err = ParseDiffCategories([]string{"checksums"})
+	assert.Error(t, err)
+}
+
+func TestDiffResult_Filter(t *testing.T) {
+	a, b := diffFixture()
+	result, err := DiffBundles(a, b)
+	require.NoError(t, err)
+
+	assert.Same(t, result, result.Filter(), "no categories keeps the full result")
+
+	approvals := result.Filter(DiffCategoryApprovals)
+	assert.True(t, approvals.HasChanges())
+	assert.Len(t, approvals.ApprovalsAdded, 1)
+	assert.Empty(t, approvals.FilesAdded)
+	assert.Empty(t, approvals.FilesRemoved)
+	assert.Empty(t, approvals.FilesModified)
+	assert.False(t, approvals.MetadataChanged)
+	assert.Equal(t, "1 approval(s) added", approvals.Summary())
+
+	combined := result.Filter(DiffCategoryFiles, DiffCategoryMetadata)
+	assert.Len(t, combined.FilesAdded, 1)
+	assert.Len(t, combined.FilesRemoved, 1)
+	assert.Len(t, combined.FilesModified, 1)
+	assert.True(t, combined.MetadataChanged)
+	assert.Empty(t, combined.ApprovalsAdded)
+
+	// Only file changes: filtering to approvals reports nothing
+	a.Approvals = b.Approvals
+	result, err = DiffBundles(a, b)
+	require.NoError(t, err)
+	assert.True(t, result.HasChanges())
+	assert.False(t, result.Filter(DiffCategoryApprovals).HasChanges())
+	assert.False(t, result.Filter(DiffCategoryAttestation).HasChanges())
+}
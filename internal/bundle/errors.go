@@ -80,6 +80,18 @@ func ErrChecksumMismatch(file, expected, actual string) *BundleError {
 	}
 }
 
+// ErrUnsupportedSchema creates an error for a bundle written with a newer
+// schema than this binary supports.
+func ErrUnsupportedSchema(schema, supported string) *BundleError {
+	return &BundleError{
+		Operation:  "load",
+		Message:    fmt.Sprintf("bundle schema %s is not supported (this version of specular supports up to %s)", schema, supported),
+		Suggestion: "Please upgrade specular to read this bundle.",
+		Details:    fmt.Sprintf("schema: %s", schema),
+		Cause:      errSchemaTooNew,
+	}
+}
+
 // ErrMissingApproval creates an error for missing approvals with suggestions.
 func ErrMissingApproval(role string) *BundleError {
 	return &BundleError{
@@ -8,8 +8,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Extractor unpacks and applies bundles to projects.
@@ -55,6 +53,17 @@ func (e *Extractor) Apply(bundlePath string) error {
 
 	e.bundle = validator.bundle
 
+	// Delta bundles only carry changed files; the base must be the exact
+	// bundle they were built against
+	if err := VerifyDeltaBase(e.bundle.Manifest, e.opts.BasePath); err != nil {
+		return err
+	}
+	if e.bundle.Manifest.IsDelta() {
+		fmt.Printf("Applying delta against %s@%s (%d changed, %d unchanged file(s))\n",
+			e.bundle.Manifest.Delta.BaseID, e.bundle.Manifest.Delta.BaseVersion,
+			len(e.bundle.Manifest.Files), len(e.bundle.Manifest.Delta.Unchanged))
+	}
+
 	// Extract bundle to temporary directory
 	tempDir, err := e.extractBundle(bundlePath)
 	if err != nil {
@@ -190,7 +199,10 @@ func (e *Extractor) dryRunApply(tempDir string) error {
 	return nil
 }
 
-// performApply actually applies the bundle to the target directory.
+// performApply applies the bundle to the target directory all or nothing.
+// The files to write are collected first, asking before each overwrite;
+// then they are staged and checked against the manifest checksums, and
+// finally moved into place together.
 func (e *Extractor) performApply(tempDir string) error {
 	targetDir := e.opts.TargetDir
 	if targetDir == "" {
@@ -202,79 +214,105 @@ func (e *Extractor) performApply(tempDir string) error {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
+	tx := &applyTransaction{targetDir: targetDir}
+	defer tx.cleanup()
+
 	// Apply spec file
-	if err := e.applySpecFile(tempDir, targetDir); err != nil {
+	if err := e.applySpecFile(tempDir, tx); err != nil {
 		return err
 	}
 
 	// Apply lock file
-	if err := e.applyLockFile(tempDir, targetDir); err != nil {
+	if err := e.applyLockFile(tempDir, tx); err != nil {
 		return err
 	}
 
 	// Apply routing file
-	if err := e.applyRoutingFile(tempDir, targetDir); err != nil {
+	if err := e.applyRoutingFile(tempDir, tx); err != nil {
 		return err
 	}
 
 	// Apply policy files
-	if err := e.applyPolicyFiles(tempDir, targetDir); err != nil {
+	if err := e.applyPolicyFiles(tempDir, tx); err != nil {
 		return err
 	}
 
 	// Apply additional files
-	if err := e.applyAdditionalFiles(tempDir, targetDir); err != nil {
+	if err := e.applyAdditionalFiles(tempDir, tx); err != nil {
 		return err
 	}
 
+	if len(tx.files) == 0 {
+		fmt.Println("No files to apply.")
+		return nil
+	}
+
+	// Stage every file and verify its checksum before touching the target
+	if err := tx.stage(e.bundle.Manifest); err != nil {
+		return fmt.Errorf("bundle not applied: %w", err)
+	}
+
+	if e.opts.Backup {
+		backupDir, err := tx.writeBackup(e.bundle.Manifest.ID + "@" + e.bundle.Manifest.Version)
+		if err != nil {
+			return fmt.Errorf("bundle not applied: %w", err)
+		}
+		fmt.Printf("Backup saved to %s\n", backupDir)
+		defer fmt.Printf("Undo with: specular bundle restore %s\n", backupDir)
+	}
+
+	if err := tx.commit(); err != nil {
+		return err
+	}
+
+	for _, file := range tx.files {
+		fmt.Printf("Applied: %s\n", file.displayName)
+	}
 	fmt.Println("Bundle applied successfully!")
 	return nil
 }
 
 // applySpecFile applies the spec.yaml file.
-func (e *Extractor) applySpecFile(tempDir, targetDir string) error {
+func (e *Extractor) applySpecFile(tempDir string, tx *applyTransaction) error {
 	sourcePath := filepath.Join(tempDir, "spec.yaml")
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 		return nil // No spec file in bundle
 	}
 
-	targetPath := filepath.Join(targetDir, "spec.yaml")
-	return e.copyFile(sourcePath, targetPath, "spec.yaml")
+	targetPath := filepath.Join(tx.targetDir, "spec.yaml")
+	return e.copyFile(tx, sourcePath, targetPath, "spec.yaml")
 }
 
 // applyLockFile applies the spec.lock.json file.
-func (e *Extractor) applyLockFile(tempDir, targetDir string) error {
+func (e *Extractor) applyLockFile(tempDir string, tx *applyTransaction) error {
 	sourcePath := filepath.Join(tempDir, "spec.lock.json")
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 		return nil // No lock file in bundle
 	}
 
-	targetPath := filepath.Join(targetDir, "spec.lock.json")
-	return e.copyFile(sourcePath, targetPath, "spec.lock.json")
+	targetPath := filepath.Join(tx.targetDir, "spec.lock.json")
+	return e.copyFile(tx, sourcePath, targetPath, "spec.lock.json")
 }
 
 // applyRoutingFile applies the routing.yaml file.
-func (e *Extractor) applyRoutingFile(tempDir, targetDir string) error {
+func (e *Extractor) applyRoutingFile(tempDir string, tx *applyTransaction) error {
 	sourcePath := filepath.Join(tempDir, "routing.yaml")
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 		return nil // No routing file in bundle
 	}
 
-	targetPath := filepath.Join(targetDir, "routing.yaml")
-	return e.copyFile(sourcePath, targetPath, "routing.yaml")
+	targetPath := filepath.Join(tx.targetDir, "routing.yaml")
+	return e.copyFile(tx, sourcePath, targetPath, "routing.yaml")
 }
 
 // applyPolicyFiles applies all policy files.
-func (e *Extractor) applyPolicyFiles(tempDir, targetDir string) error {
+func (e *Extractor) applyPolicyFiles(tempDir string, tx *applyTransaction) error {
 	policiesDir := filepath.Join(tempDir, "policies")
 	if _, err := os.Stat(policiesDir); os.IsNotExist(err) {
 		return nil // No policies in bundle
 	}
 
-	targetPoliciesDir := filepath.Join(targetDir, "policies")
-	if err := os.MkdirAll(targetPoliciesDir, 0750); err != nil {
-		return fmt.Errorf("failed to create policies directory: %w", err)
-	}
+	targetPoliciesDir := filepath.Join(tx.targetDir, "policies")
 
 	// Read all policy files
 	entries, err := os.ReadDir(policiesDir)
@@ -290,7 +328,7 @@ func (e *Extractor) applyPolicyFiles(tempDir, targetDir string) error {
 		sourcePath := filepath.Join(policiesDir, entry.Name())
 		targetPath := filepath.Join(targetPoliciesDir, entry.Name())
 
-		if copyErr := e.copyFile(sourcePath, targetPath, "policies/"+entry.Name()); copyErr != nil {
+		if copyErr := e.copyFile(tx, sourcePath, targetPath, "policies/"+entry.Name()); copyErr != nil {
 			return copyErr
 		}
 	}
@@ -299,8 +337,8 @@ func (e *Extractor) applyPolicyFiles(tempDir, targetDir string) error {
 }
 
 // applyAdditionalFiles applies additional files from the bundle.
-func (e *Extractor) applyAdditionalFiles(tempDir, targetDir string) error {
-	// Skip manifest, checksums, and standard files
+func (e *Extractor) applyAdditionalFiles(tempDir string, tx *applyTransaction) error {
+	// Skip manifest, checksums, signatures, and standard files
 	skipFiles := map[string]bool{
 		"manifest.yaml":  true,
 		"checksums.txt":  true,
@@ -310,6 +348,7 @@ func (e *Extractor) applyAdditionalFiles(tempDir, targetDir string) error {
 		"policies":       true,
 		"approvals":      true,
 		"attestations":   true,
+		"signatures":     true,
 	}
 
 	return filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
@@ -338,30 +377,27 @@ func (e *Extractor) applyAdditionalFiles(tempDir, targetDir string) error {
 		}
 
 		// Check exclude patterns
-		for _, pattern := range e.opts.Exclude {
-			//nolint:errcheck // Pattern validity checked at startup
-			matched, _ := filepath.Match(pattern, relPath)
-			if matched {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+		if matchesExcludePattern(e.opts.Exclude, relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
 		}
 
+		// Directories are created when their files are moved into place
 		if info.IsDir() {
-			targetPath := filepath.Join(targetDir, relPath)
-			return os.MkdirAll(targetPath, info.Mode())
+			return nil
 		}
 
 		sourcePath := path
-		targetPath := filepath.Join(targetDir, relPath)
-		return e.copyFile(sourcePath, targetPath, relPath)
+		targetPath := filepath.Join(tx.targetDir, relPath)
+		return e.copyFile(tx, sourcePath, targetPath, relPath)
 	})
 }
 
-// copyFile copies a file from source to target with confirmation if needed.
-func (e *Extractor) copyFile(sourcePath, targetPath, displayName string) error {
+// copyFile adds a file to the transaction, asking for confirmation first if
+// it would overwrite an existing file.
+func (e *Extractor) copyFile(tx *applyTransaction, sourcePath, targetPath, displayName string) error {
 	// Check if target exists
 	if _, err := os.Stat(targetPath); err == nil {
 		// File exists - check if we should overwrite
@@ -380,37 +416,7 @@ func (e *Extractor) copyFile(sourcePath, targetPath, displayName string) error {
 		}
 	}
 
-	// Create parent directory
-	if err := os.MkdirAll(filepath.Dir(targetPath), 0750); err != nil {
-		return fmt.Errorf("failed to create directory for %s: %w", displayName, err)
-	}
-
-	// Open source file
-	sourceFile, err := os.Open(sourcePath)
-	if err != nil {
-		return fmt.Errorf("failed to open source file %s: %w", displayName, err)
-	}
-	defer func() { _ = sourceFile.Close() }() //nolint:errcheck
-
-	// Get source file info for permissions
-	sourceInfo, err := sourceFile.Stat()
-	if err != nil {
-		return fmt.Errorf("failed to stat source file %s: %w", displayName, err)
-	}
-
-	// Create target file
-	targetFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, sourceInfo.Mode())
-	if err != nil {
-		return fmt.Errorf("failed to create target file %s: %w", displayName, err)
-	}
-	defer func() { _ = targetFile.Close() }() //nolint:errcheck
-
-	// Copy data
-	if _, copyErr := io.Copy(targetFile, sourceFile); copyErr != nil {
-		return fmt.Errorf("failed to copy file %s: %w", displayName, copyErr)
-	}
-
-	fmt.Printf("Applied: %s\n", displayName)
+	tx.add(sourcePath, targetPath, displayName)
 	return nil
 }
 
@@ -487,6 +493,7 @@ func (e *Extractor) showAdditionalFileChanges(tempDir string) error {
 		"policies":       true,
 		"approvals":      true,
 		"attestations":   true,
+		"signatures":     true,
 	}
 
 	return filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
@@ -582,9 +589,9 @@ func GetBundleInfo(bundlePath string) (*BundleInfo, error) {
 	}
 
 	// Parse manifest
-	var manifest Manifest
-	if unmarshalErr := yaml.Unmarshal(manifestData, &manifest); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to parse manifest: %w", unmarshalErr)
+	manifest, decodeErr := decodeManifest(manifestData)
+	if decodeErr != nil {
+		return nil, decodeErr
 	}
 
 	// Create BundleInfo
@@ -0,0 +1,200 @@
+package bundle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IgnoreFileName is the name of the file listing paths excluded from bundles
+const IgnoreFileName = ".specularignore"
+
+// IncludeMatch records the files matched by a single --include pattern.
+type IncludeMatch struct {
+	// Pattern is the include pattern as given by the user
+	Pattern string
+
+	// Files are the matched file paths, relative to the project root
+	Files []string
+}
+
+// IncludeSummary describes the result of resolving include patterns.
+type IncludeSummary struct {
+	// Matches lists the files matched per pattern, in pattern order
+	Matches []IncludeMatch
+
+	// Files are all distinct matched files, sorted
+	Files []string
+
+	// Excluded is the number of files skipped by exclude or ignore patterns
+	Excluded int
+}
+
+// ResolveIncludes expands include patterns relative to root. Patterns may be
+// plain files, directories (walked recursively), or filepath.Match globs.
+// Files matching an exclude pattern or an entry in root's .specularignore are
+// skipped. A pattern that matches no files is an error so bundles are never
+// silently missing content.
+func ResolveIncludes(root string, patterns, exclude []string) (*IncludeSummary, error) {
+	if root == "" {
+		root = "."
+	}
+
+	ignored, err := LoadIgnoreFile(root)
+	if err != nil {
+		return nil, err
+	}
+	excludePatterns := append(append([]string{}, exclude...), ignored...)
+
+	for _, pattern := range excludePatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	summary := &IncludeSummary{}
+	seen := make(map[string]bool)
+	excluded := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		fullPattern := pattern
+		if !filepath.IsAbs(pattern) {
+			fullPattern = filepath.Join(root, pattern)
+		}
+
+		candidates, err := filepath.Glob(fullPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+
+		match := IncludeMatch{Pattern: pattern}
+		for _, candidate := range candidates {
+			files, skipped, err := collectIncludeFiles(root, candidate, excludePatterns)
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range skipped {
+				excluded[path] = true
+			}
+			match.Files = append(match.Files, files...)
+		}
+
+		if len(match.Files) == 0 {
+			return nil, &BundleError{
+				Operation:  "build",
+				Message:    fmt.Sprintf("include pattern %q matched no files", pattern),
+				Suggestion: fmt.Sprintf("Check the path is relative to the project root and not excluded by --exclude or %s.", IgnoreFileName),
+			}
+		}
+
+		sort.Strings(match.Files)
+		for _, path := range match.Files {
+			if !seen[path] {
+				seen[path] = true
+				summary.Files = append(summary.Files, path)
+			}
+		}
+		summary.Matches = append(summary.Matches, match)
+	}
+
+	sort.Strings(summary.Files)
+	summary.Excluded = len(excluded)
+
+	return summary, nil
+}
+
+// collectIncludeFiles returns the files at path (recursing into directories)
+// and the files skipped by exclude patterns, both relative to root.
+func collectIncludeFiles(root, path string, exclude []string) ([]string, []string, error) {
+	var files, skipped []string
+
+	err := filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath := bundleRelPath(root, walkPath)
+		if matchesExcludePattern(exclude, relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			skipped = append(skipped, relPath)
+			return nil
+		}
+
+		if !info.IsDir() {
+			files = append(files, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to walk include path %s: %w", path, err)
+	}
+
+	return files, skipped, nil
+}
+
+// bundleRelPath returns path relative to root, falling back to path itself
+// when no relative form exists (e.g. absolute path with a relative root).
+func bundleRelPath(root, path string) string {
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return relPath
+}
+
+// matchesExcludePattern reports whether relPath matches any exclude pattern.
+// Patterns use path.Match semantics. A pattern containing a slash is matched
+// against the path relative to the bundle or project root; one without, like
+// *.log, against the base name, so it excludes files at any depth.
+func matchesExcludePattern(patterns []string, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	name := path.Base(relPath)
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+		target := relPath
+		if !strings.Contains(pattern, "/") {
+			target = name
+		}
+		//nolint:errcheck // Pattern validity checked at startup
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadIgnoreFile reads exclude patterns from root's .specularignore. Blank
+// lines and lines starting with # are skipped, and a trailing slash marks a
+// directory. A missing file yields no patterns.
+func LoadIgnoreFile(root string) ([]string, error) {
+	f, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, filepath.FromSlash(strings.TrimSuffix(line, "/")))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,147 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree creates files (relative path -> content) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0750))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0600))
+	}
+}
+
+func TestResolveIncludes_DirectoryRecursion(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/README.md":          "readme",
+		"docs/guides/setup.md":    "setup",
+		"docs/guides/deep/faq.md": "faq",
+	})
+
+	summary, err := ResolveIncludes(root, []string{"docs/"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join("docs", "README.md"),
+		filepath.Join("docs", "guides", "deep", "faq.md"),
+		filepath.Join("docs", "guides", "setup.md"),
+	}, summary.Files)
+	require.Len(t, summary.Matches, 1)
+	assert.Len(t, summary.Matches[0].Files, 3)
+}
+
+func TestResolveIncludes_GlobPattern(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"policies/security.yaml":   "a",
+		"policies/compliance.yaml": "b",
+		"policies/notes.txt":       "c",
+	})
+
+	summary, err := ResolveIncludes(root, []string{"policies/*.yaml"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join("policies", "compliance.yaml"),
+		filepath.Join("policies", "security.yaml"),
+	}, summary.Files)
+}
+
+func TestResolveIncludes_ExcludeAndIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/guide.md":       "guide",
+		"docs/debug.log":      "log",
+		"docs/drafts/wip.md":  "wip",
+		"docs/drafts/todo.md": "todo",
+		IgnoreFileName:        "# drafts are never shipped\ndocs/drafts/\n",
+	})
+
+	summary, err := ResolveIncludes(root, []string{"docs"}, []string{"docs/*.log"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join("docs", "guide.md")}, summary.Files)
+	assert.Equal(t, 1, summary.Excluded)
+}
+
+func TestResolveIncludes_ExcludeNestedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/guide.md":            "guide",
+		"docs/debug.log":           "log",
+		"docs/guides/deep/run.log": "log",
+		"docs/cache/index.md":      "cache",
+	})
+
+	// Patterns without a slash match the base name at any depth
+	summary, err := ResolveIncludes(root, []string{"docs"}, []string{"*.log", "cache"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join("docs", "guide.md")}, summary.Files)
+	assert.Equal(t, 2, summary.Excluded)
+}
+
+func TestResolveIncludes_ZeroMatchesIsError(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/debug.log": "log",
+	})
+
+	tests := []struct {
+		name     string
+		patterns []string
+		exclude  []string
+	}{
+		{name: "missing file", patterns: []string{"missing.yaml"}},
+		{name: "glob without matches", patterns: []string{"policies/*.yaml"}},
+		{name: "everything excluded", patterns: []string{"docs"}, exclude: []string{"docs/*.log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveIncludes(root, tt.patterns, tt.exclude)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "matched no files")
+		})
+	}
+}
+
+func TestBuilder_IncludesGlobAndDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docs/README.md":         "readme",
+		"docs/guides/setup.md":   "setup",
+		"policies/security.yaml": "rules: []\n",
+	})
+
+	builder, err := NewBuilder(BundleOptions{
+		ProjectRoot:  root,
+		IncludePaths: []string{"docs/", "policies/*.yaml"},
+	})
+	require.NoError(t, err)
+
+	summary, err := builder.ResolveIncludes()
+	require.NoError(t, err)
+	assert.Len(t, summary.Files, 3)
+
+	bundlePath := filepath.Join(t.TempDir(), "include.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	b, err := LoadBundle(bundlePath)
+	require.NoError(t, err)
+	var paths []string
+	for _, file := range b.Manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Contains(t, paths, "docs/guides/setup.md")
+	assert.Contains(t, paths, "policies/security.yaml")
+}
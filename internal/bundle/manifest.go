@@ -48,6 +48,27 @@ type Manifest struct {
 
 	// Files lists all files included in the bundle with checksums
 	Files []FileEntry `json:"files" yaml:"files"`
+
+	// Delta is set when the bundle only packages changes since a base bundle
+	Delta *DeltaInfo `json:"delta,omitempty" yaml:"delta,omitempty"`
+}
+
+// DeltaInfo describes the base bundle a delta bundle was built against.
+type DeltaInfo struct {
+	// BaseID is the base bundle identifier
+	BaseID string `json:"base_id" yaml:"base_id"`
+
+	// BaseVersion is the base bundle version
+	BaseVersion string `json:"base_version" yaml:"base_version"`
+
+	// BaseDigest is the digest of the base bundle file (e.g., "sha256:6f1a2e...")
+	BaseDigest string `json:"base_digest" yaml:"base_digest"`
+
+	// Unchanged lists files carried over from the base without being packaged
+	Unchanged []FileEntry `json:"unchanged,omitempty" yaml:"unchanged,omitempty"`
+
+	// Removed lists base files that are no longer part of the bundle
+	Removed []string `json:"removed,omitempty" yaml:"removed,omitempty"`
 }
 
 // IntegrityInfo contains cryptographic integrity information for the bundle.
@@ -0,0 +1,203 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestSignatureFileName is the path of the detached manifest signature
+// inside a bundle archive.
+const ManifestSignatureFileName = "signatures/manifest.sig.json"
+
+// manifestSignatureRole is the role recorded in the signed message so a
+// manifest signature can never be replayed as a role approval.
+const manifestSignatureRole = "manifest"
+
+var (
+	// ErrManifestSignatureNotFound indicates the bundle has no manifest signature
+	ErrManifestSignatureNotFound = errors.New("bundle has no manifest signature")
+
+	// ErrManifestDigestMismatch indicates the manifest changed after signing
+	ErrManifestDigestMismatch = errors.New("manifest digest does not match signature")
+
+	// ErrManifestSignerUntrusted indicates the signature is valid but no
+	// trusted keys were given, so any key would have been accepted
+	ErrManifestSignerUntrusted = errors.New("manifest signature valid, signer untrusted")
+)
+
+// ManifestSignature is a detached signature over the bundle manifest.
+// Unlike approvals it does not represent a governance sign-off; it proves the
+// manifest (and through its checksums every bundled file) has not changed
+// since the author signed it.
+type ManifestSignature struct {
+	// ManifestDigest is the SHA-256 digest of manifest.yaml ("sha256:<hex>")
+	ManifestDigest string `json:"manifest_digest" yaml:"manifest_digest"`
+
+	// Signer is the email or identifier of the author
+	Signer string `json:"signer" yaml:"signer"`
+
+	// SignedAt is the timestamp when the manifest was signed
+	SignedAt time.Time `json:"signed_at" yaml:"signed_at"`
+
+	// Signature is the cryptographic signature of the manifest digest
+	Signature string `json:"signature" yaml:"signature"`
+
+	// SignatureType indicates the signature format ("ssh", "gpg")
+	SignatureType SignatureType `json:"signature_type" yaml:"signature_type"`
+
+	// PublicKey is the public key used for verification
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// PublicKeyFingerprint is the fingerprint of the public key
+	PublicKeyFingerprint string `json:"public_key_fingerprint,omitempty" yaml:"public_key_fingerprint,omitempty"`
+}
+
+// SignManifest signs the manifest digest of a bundle and embeds the detached
+// signature in the archive. Any existing manifest signature is replaced.
+//
+// Re-packing changes the bundle file digest, so sign the manifest before
+// collecting approvals.
+func SignManifest(bundlePath string, signer *Signer, user string) (*ManifestSignature, error) {
+	tempDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	defer cleanupOnError(tempDir)
+
+	digest, err := manifestFileDigest(tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := signer.SignApproval(ApprovalRequest{
+		BundleDigest: digest,
+		Role:         manifestSignatureRole,
+		User:         user,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign manifest: %w", err)
+	}
+
+	sig := &ManifestSignature{
+		ManifestDigest:       digest,
+		Signer:               approval.User,
+		SignedAt:             approval.SignedAt,
+		Signature:            approval.Signature,
+		SignatureType:        approval.SignatureType,
+		PublicKey:            approval.PublicKey,
+		PublicKeyFingerprint: approval.PublicKeyFingerprint,
+	}
+
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest signature: %w", err)
+	}
+
+	sigPath := filepath.Join(tempDir, ManifestSignatureFileName)
+	if mkdirErr := os.MkdirAll(filepath.Dir(sigPath), 0750); mkdirErr != nil {
+		return nil, fmt.Errorf("failed to create signatures directory: %w", mkdirErr)
+	}
+	if writeErr := os.WriteFile(sigPath, data, 0600); writeErr != nil {
+		return nil, fmt.Errorf("failed to write manifest signature: %w", writeErr)
+	}
+
+	if repackErr := repackBundle(tempDir, bundlePath); repackErr != nil {
+		return nil, fmt.Errorf("failed to repack bundle: %w", repackErr)
+	}
+
+	return sig, nil
+}
+
+// VerifyManifestSignature checks the detached manifest signature of a bundle.
+// The signing key must match one of trustedKeys by public key or fingerprint.
+// Without trusted keys a valid signature returns ErrManifestSignerUntrusted,
+// since the key embedded in the signature proves nothing about the signer.
+func VerifyManifestSignature(bundlePath string, trustedKeys []string) (*ManifestSignature, error) {
+	tempDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	defer cleanupOnError(tempDir)
+
+	return verifyManifestSignatureDir(tempDir, trustedKeys)
+}
+
+// verifyManifestSignatureDir verifies the manifest signature of an extracted bundle.
+func verifyManifestSignatureDir(tempDir string, trustedKeys []string) (*ManifestSignature, error) {
+	sig, err := loadManifestSignature(tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := manifestFileDigest(tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if sig.ManifestDigest != digest {
+		return sig, fmt.Errorf("%w: signed %s, bundle has %s", ErrManifestDigestMismatch, sig.ManifestDigest, digest)
+	}
+
+	verifier := NewVerifier(ApprovalVerificationOptions{
+		BundleDigest: digest,
+		TrustedKeys:  trustedKeys,
+	})
+	if verifyErr := verifier.VerifyApproval(sig.approval()); verifyErr != nil {
+		return sig, fmt.Errorf("manifest signature verification failed: %w", verifyErr)
+	}
+
+	if len(trustedKeys) == 0 {
+		return sig, fmt.Errorf("%w: no trusted keys given for %s", ErrManifestSignerUntrusted, sig.Signer)
+	}
+
+	return sig, nil
+}
+
+// loadManifestSignature reads the manifest signature from an extracted bundle.
+func loadManifestSignature(tempDir string) (*ManifestSignature, error) {
+	data, err := os.ReadFile(filepath.Join(tempDir, ManifestSignatureFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrManifestSignatureNotFound
+		}
+		return nil, fmt.Errorf("failed to read manifest signature: %w", err)
+	}
+
+	var sig ManifestSignature
+	if unmarshalErr := json.Unmarshal(data, &sig); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse manifest signature: %w", unmarshalErr)
+	}
+
+	return &sig, nil
+}
+
+// manifestFileDigest computes the digest of manifest.yaml in an extracted bundle.
+func manifestFileDigest(tempDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(tempDir, ManifestFileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s:%s", DefaultChecksumAlgorithm, hex.EncodeToString(sum[:])), nil
+}
+
+// approval converts the signature into the approval form used for signing
+// so the existing SSH and GPG verifiers can check it.
+func (s *ManifestSignature) approval() *Approval {
+	return &Approval{
+		Role:                 manifestSignatureRole,
+		User:                 s.Signer,
+		SignedAt:             s.SignedAt,
+		Signature:            s.Signature,
+		SignatureType:        s.SignatureType,
+		PublicKey:            s.PublicKey,
+		PublicKeyFingerprint: s.PublicKeyFingerprint,
+	}
+}
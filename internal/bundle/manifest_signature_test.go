@@ -0,0 +1,148 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// writeSSHKey writes an unencrypted ed25519 OpenSSH private key and returns
+// its path and public key fingerprint.
+func writeSSHKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600))
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	return keyPath, ssh.FingerprintSHA256(signer.PublicKey())
+}
+
+// buildSignedBundle builds a bundle and signs its manifest.
+func buildSignedBundle(t *testing.T) (string, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"docs/README.md": "readme"})
+
+	builder, err := NewBuilder(BundleOptions{
+		ProjectRoot:  root,
+		IncludePaths: []string{"docs"},
+	})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "signed.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	keyPath, fingerprint := writeSSHKey(t)
+	sig, err := SignManifest(bundlePath, NewSigner(SignatureTypeSSH, keyPath), "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, sig.PublicKeyFingerprint)
+	assert.True(t, strings.HasPrefix(sig.ManifestDigest, "sha256:"))
+
+	return bundlePath, fingerprint
+}
+
+func TestManifestSignature_SignAndVerify(t *testing.T) {
+	bundlePath, fingerprint := buildSignedBundle(t)
+
+	sig, err := VerifyManifestSignature(bundlePath, []string{fingerprint})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", sig.Signer)
+
+	// Without trusted keys any key would verify, so the signer is untrusted
+	sig, err = VerifyManifestSignature(bundlePath, nil)
+	assert.ErrorIs(t, err, ErrManifestSignerUntrusted)
+	require.NotNil(t, sig)
+
+	_, err = VerifyManifestSignature(bundlePath, []string{"SHA256:untrusted"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trusted keys")
+
+	// Gate verification passes with a trusted key
+	result, err := NewValidator(VerifyOptions{
+		RequireManifestSignature: true,
+		TrustPublicKeys:          []string{fingerprint},
+	}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.True(t, result.ManifestSignatureValid)
+
+	// A required signature fails the gate without trusted keys
+	result, err = NewValidator(VerifyOptions{RequireManifestSignature: true}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.False(t, result.ManifestSignatureValid)
+
+	// An optional one passes with an untrusted signer warning
+	result, err = NewValidator(VerifyOptions{}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.True(t, result.ManifestSignatureValid)
+	require.NotEmpty(t, result.Warnings)
+	assert.Equal(t, WarnCodeUntrustedSigner, result.Warnings[len(result.Warnings)-1].Code)
+}
+
+func TestManifestSignature_DetectsTampering(t *testing.T) {
+	bundlePath, _ := buildSignedBundle(t)
+
+	// Rewrite the manifest after signing
+	tempDir, err := extractBundle(bundlePath)
+	require.NoError(t, err)
+	defer cleanupOnError(tempDir)
+
+	manifestPath := filepath.Join(tempDir, ManifestFileName)
+	data, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, append(data, []byte("# tampered\n")...), 0600))
+	require.NoError(t, repackBundle(tempDir, bundlePath))
+
+	_, err = VerifyManifestSignature(bundlePath, nil)
+	assert.ErrorIs(t, err, ErrManifestDigestMismatch)
+
+	result, err := NewValidator(VerifyOptions{}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.False(t, result.ManifestSignatureValid)
+	require.NotEmpty(t, result.Errors)
+	assert.Equal(t, ErrCodeManifestSignature, result.Errors[len(result.Errors)-1].Code)
+}
+
+func TestManifestSignature_Missing(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"docs/README.md": "readme"})
+
+	builder, err := NewBuilder(BundleOptions{ProjectRoot: root, IncludePaths: []string{"docs"}})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "unsigned.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	_, err = VerifyManifestSignature(bundlePath, nil)
+	assert.ErrorIs(t, err, ErrManifestSignatureNotFound)
+
+	// An absent signature only fails the gate when required
+	result, err := NewValidator(VerifyOptions{}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.True(t, result.ManifestSignatureValid)
+
+	result, err = NewValidator(VerifyOptions{RequireManifestSignature: true}).Verify(bundlePath)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.False(t, result.ManifestSignatureValid)
+}
@@ -1,6 +1,8 @@
 package bundle
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 )
@@ -39,6 +42,30 @@ type OCIOptions struct {
 
 	// UserAgent for registry requests
 	UserAgent string
+
+	// Progress, if set, receives bundle bytes transferred and the bundle size
+	Progress ProgressFunc
+
+	// ChunkSize is the upload chunk size (defaults to DefaultChunkSize)
+	ChunkSize int64
+
+	// Retries is how many times an interrupted transfer is resumed
+	// (defaults to DefaultTransferRetries; negative disables retries)
+	Retries int
+}
+
+// withTransferDefaults fills in transfer settings left at their zero value
+func (o OCIOptions) withTransferDefaults() OCIOptions {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultChunkSize
+	}
+	if o.Retries == 0 {
+		o.Retries = DefaultTransferRetries
+	}
+	if o.Retries < 0 {
+		o.Retries = 0
+	}
+	return o
 }
 
 // OCIPusher handles pushing bundles to OCI registries
@@ -61,11 +88,12 @@ func NewOCIPusher(opts OCIOptions) *OCIPusher {
 		}
 	}
 
-	return &OCIPusher{opts: opts}
+	return &OCIPusher{opts: opts.withTransferDefaults()}
 }
 
-// Push uploads a bundle to an OCI registry
-func (p *OCIPusher) Push(bundlePath string) error {
+// Push uploads a bundle to an OCI registry. Cancelling ctx stops the
+// transfer, including any wait between retries.
+func (p *OCIPusher) Push(ctx context.Context, bundlePath string) error {
 	// Parse the reference
 	ref, parseErr := name.ParseReference(p.opts.Reference)
 	if parseErr != nil {
@@ -134,6 +162,7 @@ func (p *OCIPusher) Push(bundlePath string) error {
 
 	// Configure remote options
 	remoteOpts := []remote.Option{
+		remote.WithContext(ctx),
 		remote.WithAuthFromKeychain(p.opts.Keychain),
 		remote.WithUserAgent(p.opts.UserAgent),
 		remote.WithPlatform(*p.opts.Platform),
@@ -143,8 +172,22 @@ func (p *OCIPusher) Push(bundlePath string) error {
 		remoteOpts = append(remoteOpts, remote.WithTransport(remote.DefaultTransport))
 	}
 
+	// Upload the bundle layer in resumable chunks; the image write below then
+	// finds the blob in place and only sends the config and manifest
+	var wait func()
+	if uploadErr := p.uploadLayer(ctx, ref, bundlePath, layer); uploadErr != nil {
+		if !errors.Is(uploadErr, errChunkedUploadUnsupported) {
+			return WrapRegistryError(uploadErr, p.opts.Reference, "push")
+		}
+		remoteOpts, wait = p.withProgress(remoteOpts)
+	}
+
 	// Push the image
-	if writeErr := remote.Write(ref, img, remoteOpts...); writeErr != nil {
+	writeErr := remote.Write(ref, img, remoteOpts...)
+	if wait != nil {
+		wait()
+	}
+	if writeErr != nil {
 		return WrapRegistryError(writeErr, p.opts.Reference, "push")
 	}
 
@@ -160,6 +203,46 @@ func (p *OCIPusher) Push(bundlePath string) error {
 	return nil
 }
 
+// uploadLayer uploads the bundle layer blob with chunked, resumable requests
+func (p *OCIPusher) uploadLayer(ctx context.Context, ref name.Reference, bundlePath string, layer v1.Layer) error {
+	digest, err := layer.Digest()
+	if err != nil {
+		return fmt.Errorf("failed to get layer digest: %w", err)
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return fmt.Errorf("failed to get layer size: %w", err)
+	}
+
+	client, err := newBlobClient(ctx, p.opts, ref.Context(), transport.PushScope)
+	if err != nil {
+		return err
+	}
+
+	return client.upload(ctx, bundlePath, digest, size, p.opts.ChunkSize, p.opts.Retries, p.opts.Progress)
+}
+
+// withProgress adds progress reporting to a monolithic image write. The
+// returned function waits until every update has been reported.
+func (p *OCIPusher) withProgress(remoteOpts []remote.Option) ([]remote.Option, func()) {
+	if p.opts.Progress == nil {
+		return remoteOpts, nil
+	}
+
+	updates := make(chan v1.Update, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for update := range updates {
+			if update.Error == nil {
+				p.opts.Progress(update.Complete, update.Total)
+			}
+		}
+	}()
+
+	return append(remoteOpts, remote.WithProgress(updates)), func() { <-done }
+}
+
 // OCIPuller handles pulling bundles from OCI registries
 type OCIPuller struct {
 	opts OCIOptions
@@ -174,13 +257,14 @@ func NewOCIPuller(opts OCIOptions) *OCIPuller {
 		opts.UserAgent = "specular-bundle/1.0"
 	}
 
-	return &OCIPuller{opts: opts}
+	return &OCIPuller{opts: opts.withTransferDefaults()}
 }
 
-// Pull downloads a bundle from an OCI registry
-func (p *OCIPuller) Pull(outputPath string) error {
+// Pull downloads a bundle from an OCI registry. Cancelling ctx stops the
+// transfer, including any wait between retries.
+func (p *OCIPuller) Pull(ctx context.Context, outputPath string) error {
 	// Parse reference and fetch image
-	ref, img, err := p.fetchBundleImage()
+	ref, img, err := p.fetchBundleImage(ctx)
 	if err != nil {
 		return err
 	}
@@ -192,7 +276,7 @@ func (p *OCIPuller) Pull(outputPath string) error {
 	}
 
 	// Extract and save bundle
-	err = p.extractBundleToFile(img, outputPath)
+	err = p.extractBundleToFile(ctx, ref, img, outputPath)
 	if err != nil {
 		return err
 	}
@@ -211,13 +295,14 @@ func (p *OCIPuller) Pull(outputPath string) error {
 }
 
 // fetchBundleImage fetches the bundle image from the registry
-func (p *OCIPuller) fetchBundleImage() (name.Reference, v1.Image, error) {
+func (p *OCIPuller) fetchBundleImage(ctx context.Context) (name.Reference, v1.Image, error) {
 	ref, parseErr := name.ParseReference(p.opts.Reference)
 	if parseErr != nil {
 		return nil, nil, WrapRegistryError(parseErr, p.opts.Reference, "pull")
 	}
 
 	remoteOpts := []remote.Option{
+		remote.WithContext(ctx),
 		remote.WithAuthFromKeychain(p.opts.Keychain),
 		remote.WithUserAgent(p.opts.UserAgent),
 	}
@@ -311,46 +396,52 @@ This artifact may be a regular OCI artifact or container image.`,
 	return nil
 }
 
-// extractBundleToFile extracts the bundle layer to an output file
-func (p *OCIPuller) extractBundleToFile(img v1.Image, outputPath string) error {
-	layers, layersErr := img.Layers()
-	if layersErr != nil {
-		return fmt.Errorf("failed to get layers: %w", layersErr)
+// extractBundleToFile downloads the bundle layer to an output file. The
+// download goes to a partial file next to the output that survives failures,
+// so retries and later pulls of the same bundle only fetch the missing bytes.
+func (p *OCIPuller) extractBundleToFile(ctx context.Context, ref name.Reference, img v1.Image, outputPath string) error {
+	manifest, manifestErr := img.Manifest()
+	if manifestErr != nil {
+		return fmt.Errorf("failed to get manifest: %w", manifestErr)
 	}
 
-	if len(layers) == 0 {
+	if len(manifest.Layers) == 0 {
 		return fmt.Errorf("no layers found in image")
 	}
 
-	layer := layers[0]
+	layer := manifest.Layers[0]
+	partialPath := partialDownloadPath(outputPath, layer.Digest)
 
-	layerReader, readerErr := layer.Compressed()
-	if readerErr != nil {
-		return fmt.Errorf("failed to get layer contents: %w", readerErr)
+	client, clientErr := newBlobClient(ctx, p.opts, ref.Context(), transport.PullScope)
+	if clientErr != nil {
+		return WrapRegistryError(clientErr, p.opts.Reference, "pull")
 	}
-	defer func() {
-		if closeErr := layerReader.Close(); closeErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to close layer reader: %v\n", closeErr)
-		}
-	}()
 
-	outputFile, createErr := os.Create(outputPath)
-	if createErr != nil {
-		return fmt.Errorf("failed to create output file: %w", createErr)
-	}
-	defer func() {
-		if closeErr := outputFile.Close(); closeErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", closeErr)
+	if downloadErr := client.download(ctx, layer.Digest, layer.Size, partialPath, p.opts.Retries, p.opts.Progress); downloadErr != nil {
+		if _, statErr := os.Stat(partialPath); statErr == nil {
+			return fmt.Errorf("failed to download bundle (partial download kept at %s; pull again to resume): %w", partialPath, downloadErr)
 		}
-	}()
+		return fmt.Errorf("failed to download bundle: %w", downloadErr)
+	}
 
-	if _, copyErr := outputFile.ReadFrom(layerReader); copyErr != nil {
-		return fmt.Errorf("failed to write bundle: %w", copyErr)
+	if renameErr := os.Rename(partialPath, outputPath); renameErr != nil {
+		return fmt.Errorf("failed to write bundle: %w", renameErr)
 	}
 
 	return nil
 }
 
+// partialDownloadPath returns the partial file used while downloading a blob.
+// The digest is part of the name so a partial file is only ever resumed for
+// the same blob.
+func partialDownloadPath(outputPath string, digest v1.Hash) string {
+	hex := digest.Hex
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return fmt.Sprintf("%s.%s.partial", outputPath, hex)
+}
+
 // GetRemoteBundleInfo retrieves bundle metadata from a registry without downloading
 func GetRemoteBundleInfo(ref string, opts OCIOptions) (*BundleInfo, error) {
 	if opts.Keychain == nil {
@@ -1,6 +1,7 @@
 package bundle
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -26,8 +27,13 @@ import (
 func setupTestRegistry(t *testing.T) (*httptest.Server, string) {
 	t.Helper()
 
-	// Create a registry handler
-	regHandler := registry.New()
+	return setupTestRegistryWithHandler(t, registry.New())
+}
+
+// setupTestRegistryWithHandler serves the given registry handler, which may
+// wrap registry.New() to observe or disrupt requests
+func setupTestRegistryWithHandler(t *testing.T, regHandler http.Handler) (*httptest.Server, string) {
+	t.Helper()
 
 	// Start test server bound to IPv4 to avoid sandbox IPv6 restrictions
 	listener, err := net.Listen("tcp4", "127.0.0.1:0")
@@ -124,7 +130,7 @@ func TestOCIPushPull(t *testing.T) {
 	}
 
 	pusher := NewOCIPusher(pushOpts)
-	err := pusher.Push(bundlePath)
+	err := pusher.Push(context.Background(), bundlePath)
 	require.NoError(t, err, "Push should succeed")
 
 	// Pull bundle from registry
@@ -137,7 +143,7 @@ func TestOCIPushPull(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(pullOpts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.NoError(t, err, "Pull should succeed")
 
 	// Verify pulled bundle
@@ -174,7 +180,7 @@ func TestOCIPushPullWithPlatform(t *testing.T) {
 	}
 
 	pusher := NewOCIPusher(pushOpts)
-	err := pusher.Push(bundlePath)
+	err := pusher.Push(context.Background(), bundlePath)
 	require.NoError(t, err)
 
 	// Pull bundle
@@ -186,7 +192,7 @@ func TestOCIPushPullWithPlatform(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(pullOpts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.NoError(t, err)
 
 	// Verify pulled bundle
@@ -212,7 +218,7 @@ func TestGetRemoteBundleInfo(t *testing.T) {
 	}
 
 	pusher := NewOCIPusher(pushOpts)
-	err := pusher.Push(bundlePath)
+	err := pusher.Push(context.Background(), bundlePath)
 	require.NoError(t, err)
 
 	// Get bundle info without downloading
@@ -247,7 +253,7 @@ func TestOCIPullNotFound(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(opts)
-	err := puller.Pull(pullPath)
+	err := puller.Pull(context.Background(), pullPath)
 	require.Error(t, err)
 
 	// Verify it's a registry error
@@ -285,7 +291,7 @@ func TestOCIPullInvalidBundle(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(opts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.Error(t, err)
 
 	// Verify it's an invalid bundle error
@@ -333,7 +339,7 @@ func TestOCIPullInvalidLayerMediaType(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(opts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.Error(t, err)
 
 	// Verify it's an invalid bundle error
@@ -382,7 +388,7 @@ func TestOCIPullMultipleLayers(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(opts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.Error(t, err)
 
 	// Verify it's an invalid bundle error
@@ -402,7 +408,7 @@ func TestOCIPushInvalidReference(t *testing.T) {
 	}
 
 	pusher := NewOCIPusher(opts)
-	err := pusher.Push(bundlePath)
+	err := pusher.Push(context.Background(), bundlePath)
 	require.Error(t, err)
 
 	// Verify it's an invalid reference error
@@ -422,7 +428,7 @@ func TestOCIPullInvalidReference(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(opts)
-	err := puller.Pull(pullPath)
+	err := puller.Pull(context.Background(), pullPath)
 	require.Error(t, err)
 
 	// Verify it's an invalid reference error
@@ -477,7 +483,7 @@ func TestBundleRoundTrip(t *testing.T) {
 	}
 
 	pusher := NewOCIPusher(pushOpts)
-	err = pusher.Push(bundlePath)
+	err = pusher.Push(context.Background(), bundlePath)
 	require.NoError(t, err)
 
 	// Get remote info (without downloading)
@@ -496,7 +502,7 @@ func TestBundleRoundTrip(t *testing.T) {
 	}
 
 	puller := NewOCIPuller(pullOpts)
-	err = puller.Pull(pullPath)
+	err = puller.Pull(context.Background(), pullPath)
 	require.NoError(t, err)
 
 	// Get pulled bundle info
@@ -0,0 +1,462 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const (
+	// DefaultChunkSize is the size of each chunk in a resumable blob upload
+	DefaultChunkSize int64 = 8 << 20
+
+	// DefaultTransferRetries is how many times an interrupted transfer is resumed
+	DefaultTransferRetries = 3
+)
+
+var (
+	// ErrBlobDigestMismatch indicates a downloaded blob does not match its digest
+	ErrBlobDigestMismatch = errors.New("downloaded blob does not match digest")
+
+	// errChunkedUploadUnsupported indicates the registry rejected chunked uploads
+	errChunkedUploadUnsupported = errors.New("registry does not support chunked uploads")
+)
+
+// transferRetryDelay is the base delay between transfer attempts; it grows
+// linearly with each retry.
+var transferRetryDelay = time.Second
+
+// ProgressFunc reports the number of bytes transferred out of the total
+type ProgressFunc func(transferred, total int64)
+
+// blobClient transfers blobs with the OCI distribution API directly so that
+// interrupted uploads and downloads can resume instead of starting over.
+type blobClient struct {
+	client    *http.Client
+	repo      name.Repository
+	scheme    string
+	userAgent string
+}
+
+// newBlobClient creates an authenticated client for the repository with the
+// given scope (transport.PullScope or transport.PushScope).
+func newBlobClient(ctx context.Context, opts OCIOptions, repo name.Repository, scope string) (*blobClient, error) {
+	auth, err := opts.Keychain.Resolve(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+
+	rt, err := transport.NewWithContext(ctx, repo.Registry, auth, remote.DefaultTransport, []string{repo.Scope(scope)})
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := repo.Registry.Scheme()
+	if opts.Insecure {
+		scheme = "http"
+	}
+
+	return &blobClient{
+		client:    &http.Client{Transport: rt},
+		repo:      repo,
+		scheme:    scheme,
+		userAgent: opts.UserAgent,
+	}, nil
+}
+
+// url builds a registry API URL for the repository
+func (c *blobClient) url(suffix string) *url.URL {
+	return &url.URL{
+		Scheme: c.scheme,
+		Host:   c.repo.RegistryStr(),
+		Path:   fmt.Sprintf("/v2/%s/%s", c.repo.RepositoryStr(), suffix),
+	}
+}
+
+// do sends a request and checks the response status
+func (c *blobClient) do(ctx context.Context, method string, u *url.URL, body io.Reader, header http.Header, codes ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if length := header.Get("Content-Length"); length != "" {
+		if req.ContentLength, err = strconv.ParseInt(length, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid content length: %w", err)
+		}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := transport.CheckError(resp, codes...); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// download fetches a blob into partialPath, resuming from any bytes already
+// written there. The partial file is kept when the transfer fails so a later
+// attempt can pick up where this one stopped.
+func (c *blobClient) download(ctx context.Context, digest v1.Hash, size int64, partialPath string, retries int, progress ProgressFunc) error {
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := waitForRetry(ctx, time.Duration(attempt)*transferRetryDelay); err != nil {
+				return err
+			}
+		}
+
+		err = c.downloadOnce(ctx, digest, size, partialPath, progress)
+		if err == nil || !isRetryableTransferError(ctx, err) {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return verifyFileDigest(partialPath, digest)
+}
+
+// downloadOnce makes a single download attempt, requesting only the missing
+// byte range when the partial file already holds data.
+func (c *blobClient) downloadOnce(ctx context.Context, digest v1.Hash, size int64, partialPath string, progress ProgressFunc) error {
+	f, err := os.OpenFile(partialPath, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open partial file: %w", err)
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek partial file: %w", err)
+	}
+	if offset > size {
+		if offset, err = truncateFile(f); err != nil {
+			return err
+		}
+	}
+	if offset == size {
+		reportProgress(progress, size, size)
+		return nil
+	}
+
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, size-1))
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, c.url("blobs/"+digest.String()), nil, header, http.StatusOK, http.StatusPartialContent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// A full response means the registry ignored the range request
+	if offset > 0 && (resp.StatusCode != http.StatusPartialContent ||
+		!strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset))) {
+		if offset, err = truncateFile(f); err != nil {
+			return err
+		}
+	}
+
+	reportProgress(progress, offset, size)
+	w := &progressWriter{w: f, transferred: offset, total: size, progress: progress}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download interrupted at %d of %d bytes: %w", w.transferred, size, err)
+	}
+	if w.transferred != size {
+		return fmt.Errorf("download interrupted at %d of %d bytes: %w", w.transferred, size, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// blobExists reports whether the registry already has the blob
+func (c *blobClient) blobExists(ctx context.Context, digest v1.Hash) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.url("blobs/"+digest.String()), nil, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// upload sends a blob in chunks. A failed chunk is retried from the offset
+// the registry acknowledges, or from the start in a new session when the
+// registry cannot report the upload status.
+func (c *blobClient) upload(ctx context.Context, path string, digest v1.Hash, size, chunkSize int64, retries int, progress ProgressFunc) error {
+	exists, err := c.blobExists(ctx, digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		reportProgress(progress, size, size)
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	location, err := c.startUpload(ctx)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	failures := 0
+	for offset < size {
+		end := min(offset+chunkSize, size)
+
+		next, chunkErr := c.uploadChunk(ctx, location, f, offset, end, size, progress)
+		if chunkErr == nil {
+			location, offset, failures = next, end, 0
+			continue
+		}
+
+		var terr *transport.Error
+		if offset == 0 && errors.As(chunkErr, &terr) && isChunkRejection(terr.StatusCode) {
+			return fmt.Errorf("%w: %v", errChunkedUploadUnsupported, chunkErr)
+		}
+
+		failures++
+		if failures > retries || !isRetryableTransferError(ctx, chunkErr) {
+			return chunkErr
+		}
+		if err := waitForRetry(ctx, time.Duration(failures)*transferRetryDelay); err != nil {
+			return err
+		}
+
+		if resumed, acked, statusErr := c.uploadStatus(ctx, location); statusErr == nil {
+			location, offset = resumed, acked
+			continue
+		}
+
+		if location, err = c.startUpload(ctx); err != nil {
+			return err
+		}
+		offset = 0
+	}
+
+	return c.finishUpload(ctx, location, digest)
+}
+
+// startUpload opens a new upload session and returns its location
+func (c *blobClient) startUpload(ctx context.Context) (*url.URL, error) {
+	resp, err := c.do(ctx, http.MethodPost, c.url("blobs/uploads/"), nil, nil, http.StatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return uploadLocation(resp)
+}
+
+// uploadChunk sends bytes [start, end) of the file and returns the location
+// for the next request.
+func (c *blobClient) uploadChunk(ctx context.Context, location *url.URL, f *os.File, start, end, size int64, progress ProgressFunc) (*url.URL, error) {
+	body := &progressReader{
+		r:           io.NewSectionReader(f, start, end-start),
+		transferred: start,
+		total:       size,
+		progress:    progress,
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set("Content-Range", fmt.Sprintf("%d-%d", start, end-1))
+	header.Set("Content-Length", strconv.FormatInt(end-start, 10))
+
+	resp, err := c.do(ctx, http.MethodPatch, location, body, header, http.StatusAccepted, http.StatusNoContent)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return uploadLocation(resp)
+}
+
+// uploadStatus asks the registry how many bytes of an upload it holds
+func (c *blobClient) uploadStatus(ctx context.Context, location *url.URL) (*url.URL, int64, error) {
+	resp, err := c.do(ctx, http.MethodGet, location, nil, nil, http.StatusNoContent)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp.Body.Close()
+
+	next, err := uploadLocation(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The Range header has the form "0-<last byte>"
+	var first, last int64
+	if _, err := fmt.Sscanf(resp.Header.Get("Range"), "%d-%d", &first, &last); err != nil {
+		return next, 0, nil
+	}
+
+	return next, last + 1, nil
+}
+
+// finishUpload commits the upload session under the blob digest
+func (c *blobClient) finishUpload(ctx context.Context, location *url.URL, digest v1.Hash) error {
+	u := *location
+	query := u.Query()
+	query.Set("digest", digest.String())
+	u.RawQuery = query.Encode()
+
+	header := http.Header{}
+	header.Set("Content-Length", "0")
+
+	resp, err := c.do(ctx, http.MethodPut, &u, http.NoBody, header, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// uploadLocation resolves the Location header of an upload response
+func uploadLocation(resp *http.Response) (*url.URL, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("registry did not return an upload location")
+	}
+
+	return resp.Request.URL.Parse(location)
+}
+
+// isChunkRejection reports whether a status code on the first chunk means the
+// registry does not accept chunked uploads.
+func isChunkRejection(status int) bool {
+	switch status {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusRequestedRangeNotSatisfiable:
+		return true
+	}
+	return false
+}
+
+// isRetryableTransferError reports whether a transfer error is transient
+func isRetryableTransferError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrBlobDigestMismatch) {
+		return false
+	}
+
+	var terr *transport.Error
+	if errors.As(err, &terr) {
+		return terr.StatusCode == http.StatusTooManyRequests || terr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return true
+}
+
+// waitForRetry waits out the backoff before a retry, returning early with
+// the context's error when ctx is cancelled
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// verifyFileDigest checks the SHA-256 digest of a file, removing it on
+// mismatch so the next attempt starts clean.
+func verifyFileDigest(path string, digest v1.Hash) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open downloaded blob: %w", err)
+	}
+
+	hasher := sha256.New()
+	_, copyErr := io.Copy(hasher, f)
+	f.Close()
+	if copyErr != nil {
+		return fmt.Errorf("failed to hash downloaded blob: %w", copyErr)
+	}
+
+	if actual := fmt.Sprintf("%x", hasher.Sum(nil)); actual != digest.Hex {
+		_ = os.Remove(path)
+		return fmt.Errorf("%w: expected %s, got sha256:%s", ErrBlobDigestMismatch, digest, actual)
+	}
+
+	return nil
+}
+
+// truncateFile empties a file and rewinds it
+func truncateFile(f *os.File) (int64, error) {
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("failed to truncate partial file: %w", err)
+	}
+	return f.Seek(0, io.SeekStart)
+}
+
+// reportProgress calls progress if it is set
+func reportProgress(progress ProgressFunc, transferred, total int64) {
+	if progress != nil {
+		progress(transferred, total)
+	}
+}
+
+// progressWriter counts bytes written and reports them
+type progressWriter struct {
+	w           io.Writer
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+// Write implements io.Writer
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.transferred += int64(n)
+	reportProgress(pw.progress, pw.transferred, pw.total)
+	return n, err
+}
+
+// progressReader counts bytes read and reports them
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+// Read implements io.Reader
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.transferred += int64(n)
+	if n > 0 {
+		reportProgress(pr.progress, pr.transferred, pr.total)
+	}
+	return n, err
+}
@@ -0,0 +1,269 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferRecorder wraps a registry handler, recording blob requests and
+// optionally failing or cutting them short
+type transferRecorder struct {
+	handler http.Handler
+	digest  string
+
+	mu     sync.Mutex
+	chunks int
+	ranges []string
+
+	// failChunk fails the given chunk upload (1-based) with a server error
+	failChunk int
+	// cutDownloads truncates this many layer downloads after cutAfter bytes
+	cutDownloads int
+	cutAfter     int
+}
+
+func (tr *transferRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tr.mu.Lock()
+	if r.Method == http.MethodPatch && r.Header.Get("Content-Range") != "" {
+		tr.chunks++
+		if tr.chunks == tr.failChunk {
+			tr.mu.Unlock()
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+			return
+		}
+	}
+
+	isLayerGet := r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/blobs/"+tr.digest)
+	cut := false
+	if isLayerGet {
+		tr.ranges = append(tr.ranges, r.Header.Get("Range"))
+		if tr.cutDownloads > 0 {
+			tr.cutDownloads--
+			cut = true
+		}
+	}
+	tr.mu.Unlock()
+
+	if cut {
+		w = &cutWriter{ResponseWriter: w, remaining: tr.cutAfter}
+	}
+	tr.handler.ServeHTTP(w, r)
+}
+
+// cutWriter stops writing the response body after a number of bytes, which
+// leaves the client with a short read
+type cutWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (cw *cutWriter) Write(p []byte) (int, error) {
+	if cw.remaining <= 0 {
+		return 0, fmt.Errorf("connection cut")
+	}
+	if len(p) > cw.remaining {
+		p = p[:cw.remaining]
+	}
+	n, err := cw.ResponseWriter.Write(p)
+	cw.remaining -= n
+	if err == nil && cw.remaining == 0 {
+		err = fmt.Errorf("connection cut")
+	}
+	return n, err
+}
+
+// setupTransferTest starts a recording registry and builds a test bundle
+func setupTransferTest(t *testing.T) (*transferRecorder, string, string, []byte) {
+	t.Helper()
+
+	prevDelay := transferRetryDelay
+	transferRetryDelay = 0
+	t.Cleanup(func() { transferRetryDelay = prevDelay })
+
+	bundlePath, _ := createTestBundle(t)
+	data, err := os.ReadFile(bundlePath)
+	require.NoError(t, err)
+
+	recorder := &transferRecorder{
+		handler: registry.New(),
+		digest:  fmt.Sprintf("sha256:%x", sha256.Sum256(data)),
+	}
+	_, registryHost := setupTestRegistryWithHandler(t, recorder)
+
+	return recorder, fmt.Sprintf("%s/test/bundle:v1.0.0", registryHost), bundlePath, data
+}
+
+// recordProgress returns a progress callback and the updates it has seen
+func recordProgress() (ProgressFunc, *[][2]int64) {
+	var updates [][2]int64
+	return func(transferred, total int64) {
+		updates = append(updates, [2]int64{transferred, total})
+	}, &updates
+}
+
+func TestOCIPushChunkedWithProgress(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+
+	progress, updates := recordProgress()
+	pusher := NewOCIPusher(OCIOptions{
+		Reference: ref,
+		Insecure:  true,
+		Keychain:  authn.DefaultKeychain,
+		ChunkSize: 512,
+		Progress:  progress,
+	})
+	require.NoError(t, pusher.Push(context.Background(), bundlePath))
+
+	assert.Equal(t, (len(data)+511)/512, recorder.chunks, "each chunk should be a separate request")
+
+	require.NotEmpty(t, *updates)
+	last := (*updates)[len(*updates)-1]
+	assert.Equal(t, [2]int64{int64(len(data)), int64(len(data))}, last)
+	for i := 1; i < len(*updates); i++ {
+		assert.GreaterOrEqual(t, (*updates)[i][0], (*updates)[i-1][0], "progress should not go backwards")
+	}
+
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	require.NoError(t, NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled))
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestOCIPushRetriesFailedChunk(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	recorder.failChunk = 2
+
+	pusher := NewOCIPusher(OCIOptions{
+		Reference: ref,
+		Insecure:  true,
+		Keychain:  authn.DefaultKeychain,
+		ChunkSize: 512,
+	})
+	require.NoError(t, pusher.Push(context.Background(), bundlePath))
+
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	require.NoError(t, NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled))
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestOCIPullResumesPartialDownload(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	require.NoError(t, NewOCIPusher(OCIOptions{Reference: ref, Insecure: true}).Push(context.Background(), bundlePath))
+
+	// Leave the first half of the bundle from an earlier, interrupted pull
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	half := len(data) / 2
+	partial := partialDownloadPath(pulled, v1.Hash{Algorithm: "sha256", Hex: strings.TrimPrefix(recorder.digest, "sha256:")})
+	require.NoError(t, os.WriteFile(partial, data[:half], 0600))
+
+	progress, updates := recordProgress()
+	puller := NewOCIPuller(OCIOptions{Reference: ref, Insecure: true, Progress: progress})
+	require.NoError(t, puller.Pull(context.Background(), pulled))
+
+	assert.Equal(t, []string{fmt.Sprintf("bytes=%d-%d", half, len(data)-1)}, recorder.ranges)
+	require.NotEmpty(t, *updates)
+	assert.Equal(t, int64(half), (*updates)[0][0], "progress should start at the resumed offset")
+
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.NoFileExists(t, partial)
+}
+
+func TestOCIPullRetriesInterruptedDownload(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	require.NoError(t, NewOCIPusher(OCIOptions{Reference: ref, Insecure: true}).Push(context.Background(), bundlePath))
+
+	recorder.cutDownloads = 1
+	recorder.cutAfter = len(data) / 3
+
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	require.NoError(t, NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled))
+
+	require.Len(t, recorder.ranges, 2)
+	assert.Empty(t, recorder.ranges[0])
+	assert.Equal(t, fmt.Sprintf("bytes=%d-%d", len(data)/3, len(data)-1), recorder.ranges[1])
+
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestOCIPullKeepsPartialOnFailure(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	require.NoError(t, NewOCIPusher(OCIOptions{Reference: ref, Insecure: true}).Push(context.Background(), bundlePath))
+
+	recorder.cutDownloads = 1
+	recorder.cutAfter = len(data) / 3
+
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	err := NewOCIPuller(OCIOptions{Reference: ref, Insecure: true, Retries: -1}).Pull(context.Background(), pulled)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pull again to resume")
+	assert.NoFileExists(t, pulled)
+
+	partial := partialDownloadPath(pulled, v1.Hash{Algorithm: "sha256", Hex: strings.TrimPrefix(recorder.digest, "sha256:")})
+	info, statErr := os.Stat(partial)
+	require.NoError(t, statErr)
+	assert.Equal(t, int64(len(data)/3), info.Size())
+
+	// A second pull only fetches the remaining bytes
+	require.NoError(t, NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled))
+	assert.Equal(t, fmt.Sprintf("bytes=%d-%d", len(data)/3, len(data)-1), recorder.ranges[len(recorder.ranges)-1])
+
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestOCIPullStopsWaitingWhenCancelled(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	require.NoError(t, NewOCIPusher(OCIOptions{Reference: ref, Insecure: true}).Push(context.Background(), bundlePath))
+
+	// The retry after the cut download would wait an hour
+	transferRetryDelay = time.Hour
+	recorder.cutDownloads = 1
+	recorder.cutAfter = len(data) / 3
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	err := NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(ctx, pulled)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "pull again to resume")
+}
+
+func TestOCIPullDiscardsCorruptPartial(t *testing.T) {
+	recorder, ref, bundlePath, data := setupTransferTest(t)
+	require.NoError(t, NewOCIPusher(OCIOptions{Reference: ref, Insecure: true}).Push(context.Background(), bundlePath))
+
+	pulled := filepath.Join(t.TempDir(), "pulled.sbundle.tgz")
+	partial := partialDownloadPath(pulled, v1.Hash{Algorithm: "sha256", Hex: strings.TrimPrefix(recorder.digest, "sha256:")})
+	require.NoError(t, os.WriteFile(partial, make([]byte, len(data)/2), 0600))
+
+	err := NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled)
+	require.ErrorIs(t, err, ErrBlobDigestMismatch)
+	assert.NoFileExists(t, partial)
+
+	require.NoError(t, NewOCIPuller(OCIOptions{Reference: ref, Insecure: true}).Pull(context.Background(), pulled))
+	got, err := os.ReadFile(pulled)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
@@ -0,0 +1,275 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// DefaultRekorURL is the public Sigstore transparency log
+const DefaultRekorURL = "https://rekor.sigstore.dev"
+
+// RekorClient uploads and fetches hashedrekord entries from a Rekor
+// transparency log.
+type RekorClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewRekorClient creates a client for the Rekor server at url.
+func NewRekorClient(url string) *RekorClient {
+	if url == "" {
+		url = DefaultRekorURL
+	}
+	return &RekorClient{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// hashedRekord is a Rekor hashedrekord v0.0.1 entry. It records the digest
+// that was signed, the signature, and the verification key or certificate.
+type hashedRekord struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Spec       hashedRekordSpec `json:"spec"`
+}
+
+type hashedRekordSpec struct {
+	Data struct {
+		Hash struct {
+			Algorithm string `json:"algorithm"`
+			Value     string `json:"value"`
+		} `json:"hash"`
+	} `json:"data"`
+	Signature struct {
+		Content   string `json:"content"`
+		PublicKey struct {
+			Content string `json:"content"`
+		} `json:"publicKey"`
+	} `json:"signature"`
+}
+
+// rekorLogEntry is a log entry as returned by the Rekor API
+type rekorLogEntry struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogIndex       int64  `json:"logIndex"`
+	Verification   struct {
+		InclusionProof json.RawMessage `json:"inclusionProof,omitempty"`
+	} `json:"verification"`
+}
+
+// newHashedRekord builds the entry for an attestation signature over payload.
+// signPayload signs the SHA-256 digest of the payload, so the signed hash is
+// the digest of that digest.
+func newHashedRekord(payload []byte, sig AttestationSignature) (*hashedRekord, error) {
+	keyContent, err := rekorKeyContent(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(payload)
+	signed := sha256.Sum256(digest[:])
+
+	entry := &hashedRekord{APIVersion: "0.0.1", Kind: "hashedrekord"}
+	entry.Spec.Data.Hash.Algorithm = "sha256"
+	entry.Spec.Data.Hash.Value = hex.EncodeToString(signed[:])
+	entry.Spec.Signature.Content = sig.Signature
+	entry.Spec.Signature.PublicKey.Content = keyContent
+	return entry, nil
+}
+
+// rekorKeyContent returns the base64 PEM Rekor records as the verifier: the
+// leaf certificate for keyless signatures, the public key otherwise.
+func rekorKeyContent(sig AttestationSignature) (string, error) {
+	if sig.Certificate != "" {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(sig.Certificate))
+		if err != nil || len(certs) == 0 {
+			return "", fmt.Errorf("failed to parse signing certificate")
+		}
+		leaf, err := cryptoutils.MarshalCertificateToPEM(certs[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to encode signing certificate: %w", err)
+		}
+		return base64.StdEncoding.EncodeToString(leaf), nil
+	}
+	if sig.PublicKey == "" {
+		return "", fmt.Errorf("signature has no public key or certificate")
+	}
+	return base64.StdEncoding.EncodeToString([]byte(sig.PublicKey)), nil
+}
+
+// Upload adds an attestation signature to the log and returns its entry. An
+// entry that is already in the log is returned as is.
+func (c *RekorClient) Upload(ctx context.Context, payload []byte, sig AttestationSignature) (*RekorEntry, error) {
+	proposed, err := newHashedRekord(payload, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(proposed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Rekor entry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/api/v1/log/entries", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Rekor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Rekor: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return decodeRekorEntry(resp.Body)
+	case http.StatusConflict:
+		// The entry exists; Location points at it
+		location := resp.Header.Get("Location")
+		uuid := location[strings.LastIndex(location, "/")+1:]
+		if uuid == "" {
+			return nil, fmt.Errorf("rekor reported a duplicate entry without its location")
+		}
+		return c.GetEntry(ctx, uuid)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)) //nolint:errcheck
+		return nil, fmt.Errorf("rekor rejected the entry: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// GetEntry fetches a log entry by UUID.
+func (c *RekorClient) GetEntry(ctx context.Context, uuid string) (*RekorEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/api/v1/log/entries/"+uuid, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Rekor request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Rekor: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return decodeRekorEntry(resp.Body)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("entry %s not found in Rekor", uuid)
+	default:
+		return nil, fmt.Errorf("failed to fetch Rekor entry %s: %s", uuid, resp.Status)
+	}
+}
+
+// decodeRekorEntry reads a Rekor response, a map from UUID to log entry.
+func decodeRekorEntry(r io.Reader) (*RekorEntry, error) {
+	var entries map[string]rekorLogEntry
+	if err := json.NewDecoder(io.LimitReader(r, 1<<20)).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse Rekor response: %w", err)
+	}
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("expected one Rekor entry, got %d", len(entries))
+	}
+
+	for uuid, logEntry := range entries {
+		entry := &RekorEntry{
+			UUID:           uuid,
+			LogIndex:       logEntry.LogIndex,
+			IntegratedTime: logEntry.IntegratedTime,
+			Body:           logEntry.Body,
+		}
+		if len(logEntry.Verification.InclusionProof) > 0 {
+			entry.InclusionProof = string(logEntry.Verification.InclusionProof)
+		}
+		return entry, nil
+	}
+	return nil, fmt.Errorf("rekor response has no entry")
+}
+
+// matchRekorBody checks that a log entry body records this attestation's
+// signature: the signed hash, the signature, and the verification key.
+func matchRekorBody(entry *RekorEntry, attestation *Attestation) error {
+	payload, err := base64.StdEncoding.DecodeString(attestation.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	expected, err := newHashedRekord(payload, attestation.Signature)
+	if err != nil {
+		return err
+	}
+
+	body, err := base64.StdEncoding.DecodeString(entry.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode Rekor entry body: %w", err)
+	}
+	var recorded hashedRekord
+	if err := json.Unmarshal(body, &recorded); err != nil {
+		return fmt.Errorf("failed to parse Rekor entry body: %w", err)
+	}
+
+	switch {
+	case recorded.Kind != expected.Kind:
+		return fmt.Errorf("rekor entry is a %s entry, not %s", recorded.Kind, expected.Kind)
+	case recorded.Spec.Data.Hash != expected.Spec.Data.Hash:
+		return fmt.Errorf("rekor entry records a different hash")
+	case !sameBase64(recorded.Spec.Signature.Content, expected.Spec.Signature.Content):
+		return fmt.Errorf("rekor entry records a different signature")
+	case !sameVerifier(recorded.Spec.Signature.PublicKey.Content, expected.Spec.Signature.PublicKey.Content):
+		return fmt.Errorf("rekor entry records a different key")
+	}
+	return nil
+}
+
+// sameBase64 reports whether two base64 strings encode the same bytes
+func sameBase64(a, b string) bool {
+	first, err := base64.StdEncoding.DecodeString(a)
+	if err != nil {
+		return false
+	}
+	second, err := base64.StdEncoding.DecodeString(b)
+	return err == nil && bytes.Equal(first, second)
+}
+
+// sameVerifier reports whether two base64 PEM public keys or certificates
+// hold the same key. Rekor may re-encode the PEM it was given.
+func sameVerifier(a, b string) bool {
+	first, err := verifierKeyDER(a)
+	if err != nil {
+		return false
+	}
+	second, err := verifierKeyDER(b)
+	return err == nil && bytes.Equal(first, second)
+}
+
+// verifierKeyDER returns the DER public key of a base64 PEM public key or
+// certificate
+func verifierKeyDER(content string) ([]byte, error) {
+	pemData, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, err
+	}
+	if certs, certErr := cryptoutils.UnmarshalCertificatesFromPEM(pemData); certErr == nil && len(certs) > 0 {
+		return cryptoutils.MarshalPublicKeyToDER(certs[0].PublicKey)
+	}
+	pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+	return cryptoutils.MarshalPublicKeyToDER(pubKey)
+}
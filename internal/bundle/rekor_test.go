@@ -0,0 +1,211 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless/keylesstest"
+)
+
+// fakeRekor is an in-memory Rekor log serving entry uploads and lookups
+type fakeRekor struct {
+	mu      sync.Mutex
+	entries map[string]rekorLogEntry
+	url     string
+}
+
+func newFakeRekor(t *testing.T) *fakeRekor {
+	t.Helper()
+
+	f := &fakeRekor{entries: make(map[string]rekorLogEntry)}
+	server := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(server.Close)
+	f.url = server.URL
+	return f
+}
+
+func (f *fakeRekor) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	const prefix = "/api/v1/log/entries"
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == prefix:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sum := sha256.Sum256(body)
+		uuid := hex.EncodeToString(sum[:])
+		if _, ok := f.entries[uuid]; ok {
+			w.Header().Set("Location", prefix+"/"+uuid)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		entry := rekorLogEntry{
+			Body:           base64.StdEncoding.EncodeToString(body),
+			IntegratedTime: time.Now().Unix(),
+			LogIndex:       int64(len(f.entries)),
+		}
+		f.entries[uuid] = entry
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]rekorLogEntry{uuid: entry})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, prefix+"/"):
+		uuid := strings.TrimPrefix(r.URL.Path, prefix+"/")
+		entry, ok := f.entries[uuid]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]rekorLogEntry{uuid: entry})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestRekorClient_Upload(t *testing.T) {
+	rekor := newFakeRekor(t)
+	_, attestation := buildBundleWithAttestation(t, AttestationOptions{
+		Format:          AttestationFormatSigstore,
+		UseEphemeralKey: true,
+	})
+	payload, err := base64.StdEncoding.DecodeString(attestation.Payload)
+	require.NoError(t, err)
+
+	client := NewRekorClient(rekor.url)
+	entry, err := client.Upload(context.Background(), payload, attestation.Signature)
+	require.NoError(t, err)
+	assert.NotEmpty(t, entry.UUID)
+	assert.NotZero(t, entry.IntegratedTime)
+	assert.NoError(t, matchRekorBody(entry, attestation))
+
+	// Uploading the same signature again returns the existing entry
+	again, err := client.Upload(context.Background(), payload, attestation.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, entry.UUID, again.UUID)
+	assert.Equal(t, entry.LogIndex, again.LogIndex)
+
+	_, err = client.GetEntry(context.Background(), "missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestVerifyEmbeddedAttestation_Rekor(t *testing.T) {
+	rekor := newFakeRekor(t)
+	fulcio := keylesstest.NewFulcio(t)
+
+	tests := []struct {
+		name string
+		opts AttestationOptions
+	}{
+		{name: "ephemeral key", opts: AttestationOptions{UseEphemeralKey: true}},
+		{name: "keyless", opts: AttestationOptions{
+			UseKeyless: true,
+			FulcioURL:  fulcio.URL,
+			IDToken:    keylesstest.Token("release@example.com"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Format = AttestationFormatSigstore
+			tt.opts.RekorURL = rekor.url
+			tt.opts.IncludeRekorEntry = true
+			bundlePath, attestation := buildBundleWithAttestation(t, tt.opts)
+			require.True(t, attestation.HasRekorEntry())
+
+			report, err := VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+				VerifySignature:   true,
+				RekorURL:          rekor.url,
+				RequireRekorEntry: true,
+			})
+			require.NoError(t, err)
+			assert.True(t, report.HasRekorEntry)
+			assert.Equal(t, attestation.RekorEntry.LogIndex, report.RekorLogIndex)
+
+			// Offline, the embedded entry is checked without the log
+			_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+				VerifySignature: true,
+				RekorURL:        "http://127.0.0.1:0",
+				Offline:         true,
+			})
+			assert.NoError(t, err)
+
+			// Online, an entry the log does not have fails
+			_, err = VerifyEmbeddedAttestation(context.Background(), bundlePath, AttestationVerificationOptions{
+				VerifySignature: true,
+				RekorURL:        newFakeRekor(t).url,
+			})
+			assert.ErrorIs(t, err, ErrRekorEntryInvalid)
+		})
+	}
+}
+
+func TestVerifyAttestation_RekorMismatch(t *testing.T) {
+	rekor := newFakeRekor(t)
+	ctx := context.Background()
+
+	newAttestation := func(t *testing.T) *Attestation {
+		_, attestation := buildBundleWithAttestation(t, AttestationOptions{
+			Format:            AttestationFormatSigstore,
+			UseEphemeralKey:   true,
+			RekorURL:          rekor.url,
+			IncludeRekorEntry: true,
+		})
+		return attestation
+	}
+	first := newAttestation(t)
+	second := newAttestation(t)
+
+	t.Run("log index", func(t *testing.T) {
+		tampered := *first
+		entry := *first.RekorEntry
+		entry.LogIndex += 10
+		tampered.RekorEntry = &entry
+
+		verifier := NewAttestationVerifier(AttestationVerificationOptions{VerifySignature: true, RekorURL: rekor.url})
+		err := verifier.VerifyAttestation(ctx, &tampered, "")
+		assert.ErrorIs(t, err, ErrRekorEntryInvalid)
+		assert.ErrorContains(t, err, "does not match")
+	})
+
+	t.Run("entry of another signature", func(t *testing.T) {
+		tampered := *first
+		tampered.RekorEntry = second.RekorEntry
+
+		for _, offline := range []bool{false, true} {
+			verifier := NewAttestationVerifier(AttestationVerificationOptions{
+				VerifySignature: true,
+				RekorURL:        rekor.url,
+				Offline:         offline,
+			})
+			err := verifier.VerifyAttestation(ctx, &tampered, "")
+			assert.ErrorIs(t, err, ErrRekorEntryInvalid)
+			assert.ErrorContains(t, err, "different")
+		}
+	})
+
+	t.Run("required but missing", func(t *testing.T) {
+		unlogged := *first
+		unlogged.RekorEntry = nil
+
+		verifier := NewAttestationVerifier(AttestationVerificationOptions{VerifySignature: true, RequireRekorEntry: true})
+		err := verifier.VerifyAttestation(ctx, &unlogged, "")
+		assert.ErrorIs(t, err, ErrRekorEntryInvalid)
+
+		verifier = NewAttestationVerifier(AttestationVerificationOptions{VerifySignature: true})
+		assert.NoError(t, verifier.VerifyAttestation(ctx, &unlogged, ""))
+	})
+}
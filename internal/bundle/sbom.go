@@ -0,0 +1,640 @@
+package bundle
+
+import (
+	"crypto/sha1" //nolint:gosec // SPDX file checksums require SHA-1
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+// SBOMFormat is the document format of a software bill of materials
+type SBOMFormat string
+
+const (
+	// SBOMFormatCycloneDX produces a CycloneDX 1.5 JSON document
+	SBOMFormatCycloneDX SBOMFormat = "cyclonedx"
+
+	// SBOMFormatSPDX produces an SPDX 2.3 JSON document
+	SBOMFormatSPDX SBOMFormat = "spdx"
+)
+
+const (
+	// specLockFileName and routingFileName are the bundle paths of the
+	// spec lock and routing configuration
+	specLockFileName = "spec.lock.json"
+	routingFileName  = "routing.yaml"
+
+	// sbomNamespace is the base URI for SBOM identifiers
+	sbomNamespace = "https://github.com/felixgeelhaar/specular/sbom"
+)
+
+// ParseSBOMFormat validates an SBOM format name
+func ParseSBOMFormat(format string) (SBOMFormat, error) {
+	switch SBOMFormat(strings.ToLower(format)) {
+	case SBOMFormatCycloneDX:
+		return SBOMFormatCycloneDX, nil
+	case SBOMFormatSPDX:
+		return SBOMFormatSPDX, nil
+	default:
+		return "", fmt.Errorf("unsupported SBOM format %q (expected cyclonedx or spdx)", format)
+	}
+}
+
+// SBOMOptions configures SBOM generation
+type SBOMOptions struct {
+	// Format is the document format (defaults to CycloneDX)
+	Format SBOMFormat
+
+	// ToolVersion is the specular version recorded as the generating tool
+	ToolVersion string
+
+	// Timestamp is the document creation time (defaults to now)
+	Timestamp time.Time
+}
+
+// sbomInventory is the format-neutral content of a bundle SBOM
+type sbomInventory struct {
+	manifest     *Manifest
+	files        []FileEntry
+	fileSHA1     map[string]string
+	dependencies []BundleDependency
+	features     []sbomFeature
+	models       []sbomModel
+}
+
+// sbomFeature is a feature pinned in the spec lock
+type sbomFeature struct {
+	ID          string
+	Hash        string
+	OpenAPIPath string
+	TestPaths   []string
+}
+
+// sbomModel is an AI model pinned in the routing configuration
+type sbomModel struct {
+	ID           string
+	Version      string
+	Provider     string
+	Family       string
+	Capabilities []string
+	Enabled      bool
+}
+
+// GenerateSBOM builds a software bill of materials for a bundle. It lists
+// every file in the manifest with its checksum, the bundle dependencies, the
+// features pinned in the spec lock, and the models named in the routing
+// configuration. The bundle must pass integrity verification.
+func GenerateSBOM(bundlePath string, opts SBOMOptions) ([]byte, error) {
+	if opts.Format == "" {
+		opts.Format = SBOMFormatCycloneDX
+	}
+	if opts.Timestamp.IsZero() {
+		opts.Timestamp = time.Now()
+	}
+	if opts.ToolVersion == "" {
+		opts.ToolVersion = "dev"
+	}
+
+	b, err := LoadBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	defer cleanupOnError(tempDir)
+
+	inv, err := newSBOMInventory(b.Manifest, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	switch opts.Format {
+	case SBOMFormatCycloneDX:
+		doc = inv.cycloneDX(opts)
+	case SBOMFormatSPDX:
+		doc = inv.spdx(opts)
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format %q", opts.Format)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SBOM: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// newSBOMInventory collects the SBOM content from the manifest and the spec
+// lock and routing files of an extracted bundle
+func newSBOMInventory(manifest *Manifest, tempDir string) (*sbomInventory, error) {
+	inv := &sbomInventory{
+		manifest:     manifest,
+		files:        append([]FileEntry(nil), manifest.Files...),
+		dependencies: append([]BundleDependency(nil), manifest.Dependencies...),
+	}
+
+	sort.Slice(inv.files, func(i, j int) bool { return inv.files[i].Path < inv.files[j].Path })
+	sort.Slice(inv.dependencies, func(i, j int) bool { return inv.dependencies[i].ID < inv.dependencies[j].ID })
+
+	// SPDX requires a SHA-1 checksum for every file, which the manifest
+	// does not record
+	inv.fileSHA1 = make(map[string]string, len(inv.files))
+	for _, file := range inv.files {
+		data, err := os.ReadFile(filepath.Join(tempDir, file.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		inv.fileSHA1[file.Path] = fmt.Sprintf("%x", sha1.Sum(data)) //nolint:gosec // required by SPDX, not used for integrity
+	}
+
+	features, err := loadSBOMFeatures(tempDir)
+	if err != nil {
+		return nil, err
+	}
+	inv.features = features
+
+	models, err := loadSBOMModels(tempDir)
+	if err != nil {
+		return nil, err
+	}
+	inv.models = models
+
+	return inv, nil
+}
+
+// loadSBOMFeatures reads the pinned features from the spec lock, if present
+func loadSBOMFeatures(tempDir string) ([]sbomFeature, error) {
+	data, err := os.ReadFile(filepath.Join(tempDir, specLockFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec lock: %w", err)
+	}
+
+	var lock spec.SpecLock
+	if unmarshalErr := json.Unmarshal(data, &lock); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse spec lock: %w", unmarshalErr)
+	}
+
+	features := make([]sbomFeature, 0, len(lock.Features))
+	for id, locked := range lock.Features {
+		features = append(features, sbomFeature{
+			ID:          string(id),
+			Hash:        locked.Hash,
+			OpenAPIPath: locked.OpenAPIPath,
+			TestPaths:   locked.TestPaths,
+		})
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].ID < features[j].ID })
+
+	return features, nil
+}
+
+// loadSBOMModels reads the models named in the routing configuration, if
+// present. Models known to the router catalog are annotated with their
+// family, and their dated release name is used as the version.
+func loadSBOMModels(tempDir string) ([]sbomModel, error) {
+	data, err := os.ReadFile(filepath.Join(tempDir, routingFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing: %w", err)
+	}
+
+	var config router.RouterConfig
+	if unmarshalErr := yaml.Unmarshal(data, &config); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse routing: %w", unmarshalErr)
+	}
+
+	byID := make(map[string]*sbomModel)
+	add := func(id, provider, capability string, enabled bool) {
+		if id == "" {
+			return
+		}
+		m, ok := byID[id]
+		if !ok {
+			m = &sbomModel{ID: id, Version: id, Provider: provider}
+			if known := router.GetModelByID(id); known != nil {
+				m.Version = known.Name
+				m.Family = known.FamilyName()
+				if m.Provider == "" {
+					m.Provider = string(known.Provider)
+				}
+			}
+			byID[id] = m
+		}
+		if capability != "" {
+			m.Capabilities = append(m.Capabilities, capability)
+		}
+		m.Enabled = m.Enabled || enabled
+	}
+
+	for _, provider := range config.Providers {
+		for capability, model := range provider.Models {
+			add(model, string(provider.Name), capability, provider.Enabled)
+		}
+	}
+	add(config.FallbackModel, "", "fallback", true)
+
+	models := make([]sbomModel, 0, len(byID))
+	for _, m := range byID {
+		sort.Strings(m.Capabilities)
+		models = append(models, *m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+
+	return models, nil
+}
+
+// serial returns a deterministic identifier for the bundle contents
+func (inv *sbomInventory) serial() uuid.UUID {
+	name := fmt.Sprintf("%s/%s@%s#%s", sbomNamespace, inv.manifest.ID, inv.manifest.Version, inv.manifest.Integrity.Digest)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name))
+}
+
+// splitDigest splits an "algorithm:hex" digest, assuming SHA-256 when no
+// algorithm prefix is present
+func splitDigest(digest string) (string, string) {
+	if alg, hex, ok := strings.Cut(digest, ":"); ok {
+		return strings.ToLower(alg), hex
+	}
+	return DefaultChecksumAlgorithm, digest
+}
+
+// CycloneDX document types
+
+type cdxDocument struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Group      string        `json:"group,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Scope      string        `json:"scope,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// cdxHashAlgorithms maps digest prefixes to CycloneDX hash algorithm names
+var cdxHashAlgorithms = map[string]string{
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+	"blake3": "BLAKE3",
+}
+
+// cdxHashes converts a digest to CycloneDX hashes, dropping unknown algorithms
+func cdxHashes(digest string) []cdxHash {
+	if digest == "" {
+		return nil
+	}
+	alg, hex := splitDigest(digest)
+	if name, ok := cdxHashAlgorithms[alg]; ok {
+		return []cdxHash{{Alg: name, Content: hex}}
+	}
+	return nil
+}
+
+// cycloneDX renders the inventory as a CycloneDX 1.5 document
+func (inv *sbomInventory) cycloneDX(opts SBOMOptions) *cdxDocument {
+	bundleRef := "bundle:" + inv.manifest.ID
+	doc := &cdxDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + inv.serial().String(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: opts.Timestamp.UTC().Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: "specular", Version: opts.ToolVersion},
+			}},
+			Component: cdxComponent{
+				Type:    "application",
+				BOMRef:  bundleRef,
+				Name:    inv.manifest.ID,
+				Version: inv.manifest.Version,
+				Hashes:  cdxHashes(inv.manifest.Integrity.Digest),
+				Properties: []cdxProperty{
+					{Name: "specular:schema", Value: inv.manifest.Schema},
+					{Name: "specular:governance_level", Value: inv.manifest.GovernanceLevel},
+				},
+			},
+		},
+		Components: []cdxComponent{},
+	}
+
+	var dependsOn []string
+	for _, file := range inv.files {
+		doc.Components = append(doc.Components, cdxComponent{
+			Type:   "file",
+			BOMRef: "file:" + file.Path,
+			Name:   file.Path,
+			Hashes: cdxHashes(file.Checksum),
+			Properties: []cdxProperty{
+				{Name: "specular:size", Value: strconv.FormatInt(file.Size, 10)},
+			},
+		})
+	}
+
+	for _, dep := range inv.dependencies {
+		ref := fmt.Sprintf("bundle:%s@%s", dep.ID, dep.Version)
+		scope := "required"
+		if dep.Optional {
+			scope = "optional"
+		}
+		doc.Components = append(doc.Components, cdxComponent{
+			Type:    "library",
+			BOMRef:  ref,
+			Name:    dep.ID,
+			Version: dep.Version,
+			Scope:   scope,
+			Hashes:  cdxHashes(dep.Digest),
+		})
+		dependsOn = append(dependsOn, ref)
+	}
+
+	for _, feature := range inv.features {
+		component := cdxComponent{
+			Type:   "data",
+			BOMRef: "feature:" + feature.ID,
+			Name:   feature.ID,
+		}
+		if feature.Hash != "" {
+			component.Hashes = cdxHashes("blake3:" + feature.Hash)
+		}
+		if feature.OpenAPIPath != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "specular:openapi_path", Value: feature.OpenAPIPath})
+		}
+		for _, testPath := range feature.TestPaths {
+			component.Properties = append(component.Properties, cdxProperty{Name: "specular:test_path", Value: testPath})
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	for _, model := range inv.models {
+		ref := "model:" + model.ID
+		component := cdxComponent{
+			Type:    "machine-learning-model",
+			BOMRef:  ref,
+			Group:   model.Provider,
+			Name:    model.ID,
+			Version: model.Version,
+			Properties: []cdxProperty{
+				{Name: "specular:enabled", Value: strconv.FormatBool(model.Enabled)},
+			},
+		}
+		if model.Family != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "specular:family", Value: model.Family})
+		}
+		for _, capability := range model.Capabilities {
+			component.Properties = append(component.Properties, cdxProperty{Name: "specular:capability", Value: capability})
+		}
+		doc.Components = append(doc.Components, component)
+		dependsOn = append(dependsOn, ref)
+	}
+
+	doc.Dependencies = []cdxDependency{{Ref: bundleRef, DependsOn: dependsOn}}
+	return doc
+}
+
+// SPDX document types
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Files             []spdxFile         `json:"files"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string         `json:"SPDXID"`
+	Name             string         `json:"name"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	Supplier         string         `json:"supplier,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	PrimaryPurpose   string         `json:"primaryPurpose,omitempty"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+	Comment          string         `json:"comment,omitempty"`
+}
+
+type spdxFile struct {
+	SPDXID    string         `json:"SPDXID"`
+	FileName  string         `json:"fileName"`
+	Checksums []spdxChecksum `json:"checksums"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxChecksums converts a digest to SPDX checksums, dropping unknown algorithms
+func spdxChecksums(digest string) []spdxChecksum {
+	if digest == "" {
+		return nil
+	}
+	alg, hex := splitDigest(digest)
+	switch alg {
+	case "sha256", "sha384", "sha512":
+		return []spdxChecksum{{Algorithm: strings.ToUpper(alg), ChecksumValue: hex}}
+	case "blake3":
+		return []spdxChecksum{{Algorithm: "BLAKE3", ChecksumValue: hex}}
+	}
+	return nil
+}
+
+// spdxID builds an SPDX identifier, replacing characters SPDX does not allow
+func spdxID(kind, name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return fmt.Sprintf("SPDXRef-%s-%s", kind, b.String())
+}
+
+// spdx renders the inventory as an SPDX 2.3 document
+func (inv *sbomInventory) spdx(opts SBOMOptions) *spdxDocument {
+	bundleID := spdxID("Package", inv.manifest.ID)
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("%s-%s", inv.manifest.ID, inv.manifest.Version),
+		DocumentNamespace: fmt.Sprintf("%s/%s", sbomNamespace, inv.serial()),
+		CreationInfo: spdxCreationInfo{
+			Created:  opts.Timestamp.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: specular-" + opts.ToolVersion},
+		},
+		Packages: []spdxPackage{{
+			SPDXID:           bundleID,
+			Name:             inv.manifest.ID,
+			VersionInfo:      inv.manifest.Version,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "ARCHIVE",
+			Checksums:        spdxChecksums(inv.manifest.Integrity.Digest),
+		}},
+		Files: []spdxFile{},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: bundleID},
+		},
+	}
+
+	relate := func(relationship, id string) {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      bundleID,
+			RelationshipType:   relationship,
+			RelatedSPDXElement: id,
+		})
+	}
+
+	for i, file := range inv.files {
+		id := fmt.Sprintf("SPDXRef-File-%d", i+1)
+		doc.Files = append(doc.Files, spdxFile{
+			SPDXID:    id,
+			FileName:  "./" + file.Path,
+			Checksums: append([]spdxChecksum{{Algorithm: "SHA1", ChecksumValue: inv.fileSHA1[file.Path]}}, spdxChecksums(file.Checksum)...),
+		})
+		relate("CONTAINS", id)
+	}
+
+	for _, dep := range inv.dependencies {
+		id := spdxID("Bundle", dep.ID)
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             dep.ID,
+			VersionInfo:      dep.Version,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "ARCHIVE",
+			Checksums:        spdxChecksums(dep.Digest),
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		if dep.Optional {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      id,
+				RelationshipType:   "OPTIONAL_DEPENDENCY_OF",
+				RelatedSPDXElement: bundleID,
+			})
+		} else {
+			relate("DEPENDS_ON", id)
+		}
+	}
+
+	for _, feature := range inv.features {
+		id := spdxID("Feature", feature.ID)
+		comment := "Feature pinned in spec.lock.json"
+		if feature.OpenAPIPath != "" {
+			comment += "; OpenAPI: " + feature.OpenAPIPath
+		}
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             feature.ID,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "DOCUMENTATION",
+			Comment:          comment,
+		}
+		if feature.Hash != "" {
+			pkg.Checksums = spdxChecksums("blake3:" + feature.Hash)
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		relate("CONTAINS", id)
+	}
+
+	for _, model := range inv.models {
+		id := spdxID("Model", model.ID)
+		comment := "AI model referenced by routing.yaml"
+		if len(model.Capabilities) > 0 {
+			comment += "; capabilities: " + strings.Join(model.Capabilities, ", ")
+		}
+		if model.Family != "" {
+			comment += "; family: " + model.Family
+		}
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             model.ID,
+			VersionInfo:      model.Version,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "OTHER",
+			Comment:          comment,
+		}
+		if model.Provider != "" {
+			pkg.Supplier = "Organization: " + model.Provider
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		relate("DEPENDS_ON", id)
+	}
+
+	return doc
+}
@@ -0,0 +1,199 @@
+package bundle
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildSBOMBundle builds a bundle with a populated spec lock and routing config
+func buildSBOMBundle(t *testing.T) (string, *Bundle) {
+	t.Helper()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"spec.yaml": "product: sbom-test\ngoals:\n  - Test SBOM export\nfeatures: []\n",
+		"spec.lock.json": `{
+  "version": "1.0.0",
+  "features": {
+    "feat-login": {"hash": "9f2c", "openapi_path": "api/login.yaml", "test_paths": ["tests/login_test.go"]},
+    "feat-audit": {"hash": "41aa", "openapi_path": "", "test_paths": []}
+  }
+}`,
+		"routing.yaml": `providers:
+  - name: anthropic
+    enabled: true
+    models:
+      codegen: claude-sonnet-4
+      review: claude-sonnet-4
+  - name: openai
+    enabled: false
+    models:
+      docs: gpt-4o
+fallback_model: gpt-4o-mini
+`,
+	})
+
+	builder, err := NewBuilder(BundleOptions{
+		SpecPath:    filepath.Join(root, "spec.yaml"),
+		LockPath:    filepath.Join(root, "spec.lock.json"),
+		RoutingPath: filepath.Join(root, "routing.yaml"),
+	})
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "sbom.sbundle.tgz")
+	require.NoError(t, builder.Build(bundlePath))
+
+	b, err := LoadBundle(bundlePath)
+	require.NoError(t, err)
+
+	return bundlePath, b
+}
+
+func TestGenerateSBOM_CycloneDX(t *testing.T) {
+	bundlePath, b := buildSBOMBundle(t)
+
+	data, err := GenerateSBOM(bundlePath, SBOMOptions{
+		Format:      SBOMFormatCycloneDX,
+		ToolVersion: "1.2.3",
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	var doc cdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	assert.Equal(t, "1.5", doc.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f-]{36}$`, doc.SerialNumber)
+	assert.Equal(t, "2026-01-02T03:04:05Z", doc.Metadata.Timestamp)
+	assert.Equal(t, "1.2.3", doc.Metadata.Tools.Components[0].Version)
+	assert.Equal(t, b.Manifest.ID, doc.Metadata.Component.Name)
+
+	byRef := make(map[string]cdxComponent)
+	for _, c := range doc.Components {
+		byRef[c.BOMRef] = c
+	}
+
+	// Every manifest file is listed with its checksum
+	for _, file := range b.Manifest.Files {
+		c, ok := byRef["file:"+file.Path]
+		require.True(t, ok, "missing file %s", file.Path)
+		assert.Equal(t, []cdxHash{{Alg: "SHA-256", Content: file.Checksum}}, c.Hashes)
+	}
+
+	login := byRef["feature:feat-login"]
+	assert.Equal(t, "data", login.Type)
+	assert.Equal(t, []cdxHash{{Alg: "BLAKE3", Content: "9f2c"}}, login.Hashes)
+	assert.Contains(t, login.Properties, cdxProperty{Name: "specular:openapi_path", Value: "api/login.yaml"})
+
+	sonnet := byRef["model:claude-sonnet-4"]
+	assert.Equal(t, "machine-learning-model", sonnet.Type)
+	assert.Equal(t, "anthropic", sonnet.Group)
+	assert.Equal(t, "claude-sonnet-4-20250514", sonnet.Version)
+	assert.Contains(t, sonnet.Properties, cdxProperty{Name: "specular:family", Value: "claude-4"})
+	assert.Contains(t, sonnet.Properties, cdxProperty{Name: "specular:capability", Value: "codegen"})
+	assert.Contains(t, sonnet.Properties, cdxProperty{Name: "specular:capability", Value: "review"})
+
+	assert.Contains(t, byRef["model:gpt-4o"].Properties, cdxProperty{Name: "specular:enabled", Value: "false"})
+	assert.Equal(t, "openai", byRef["model:gpt-4o-mini"].Group, "fallback provider comes from the catalog")
+
+	require.Len(t, doc.Dependencies, 1)
+	assert.ElementsMatch(t, []string{"model:claude-sonnet-4", "model:gpt-4o", "model:gpt-4o-mini"}, doc.Dependencies[0].DependsOn)
+}
+
+func TestGenerateSBOM_SPDX(t *testing.T) {
+	bundlePath, b := buildSBOMBundle(t)
+
+	data, err := GenerateSBOM(bundlePath, SBOMOptions{Format: SBOMFormatSPDX})
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "CC0-1.0", doc.DataLicense)
+	assert.Contains(t, doc.CreationInfo.Creators, "Tool: specular-dev")
+	require.Len(t, doc.Files, len(b.Manifest.Files))
+
+	for _, file := range doc.Files {
+		require.Len(t, file.Checksums, 2)
+		assert.Equal(t, "SHA1", file.Checksums[0].Algorithm)
+		assert.Len(t, file.Checksums[0].ChecksumValue, 40)
+		assert.Equal(t, "SHA256", file.Checksums[1].Algorithm)
+	}
+
+	names := make(map[string]spdxPackage)
+	for _, pkg := range doc.Packages {
+		names[pkg.Name] = pkg
+	}
+	assert.Equal(t, "Organization: anthropic", names["claude-sonnet-4"].Supplier)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "BLAKE3", ChecksumValue: "41aa"}}, names["feat-audit"].Checksums)
+
+	bundleID := doc.Packages[0].SPDXID
+	assert.Contains(t, doc.Relationships, spdxRelationship{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: bundleID})
+	assert.Contains(t, doc.Relationships, spdxRelationship{SPDXElementID: bundleID, RelationshipType: "CONTAINS", RelatedSPDXElement: doc.Files[0].SPDXID})
+	assert.Contains(t, doc.Relationships, spdxRelationship{SPDXElementID: bundleID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: names["gpt-4o"].SPDXID})
+}
+
+func TestGenerateSBOM_Deterministic(t *testing.T) {
+	bundlePath, _ := buildSBOMBundle(t)
+	opts := SBOMOptions{Timestamp: time.Unix(0, 0)}
+
+	first, err := GenerateSBOM(bundlePath, opts)
+	require.NoError(t, err)
+	second, err := GenerateSBOM(bundlePath, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second))
+}
+
+func TestSBOM_Dependencies(t *testing.T) {
+	inv := &sbomInventory{
+		manifest: &Manifest{ID: "acme/api", Version: "2.0.0"},
+		dependencies: []BundleDependency{
+			{ID: "acme/base", Version: "1.4.0", Digest: "sha256:abcd"},
+			{ID: "acme/extras", Version: "0.3.1", Optional: true},
+		},
+	}
+
+	cdx := inv.cycloneDX(SBOMOptions{})
+	require.Len(t, cdx.Components, 2)
+	assert.Equal(t, "1.4.0", cdx.Components[0].Version)
+	assert.Equal(t, "required", cdx.Components[0].Scope)
+	assert.Equal(t, []cdxHash{{Alg: "SHA-256", Content: "abcd"}}, cdx.Components[0].Hashes)
+	assert.Equal(t, "optional", cdx.Components[1].Scope)
+
+	spdx := inv.spdx(SBOMOptions{})
+	assert.Contains(t, spdx.Relationships, spdxRelationship{
+		SPDXElementID:      "SPDXRef-Bundle-acme-extras",
+		RelationshipType:   "OPTIONAL_DEPENDENCY_OF",
+		RelatedSPDXElement: "SPDXRef-Package-acme-api",
+	})
+}
+
+func TestParseSBOMFormat(t *testing.T) {
+	format, err := ParseSBOMFormat("SPDX")
+	require.NoError(t, err)
+	assert.Equal(t, SBOMFormatSPDX, format)
+
+	_, err = ParseSBOMFormat("swid")
+	assert.Error(t, err)
+}
+
+func TestGenerateSBOM_RejectsTamperedBundle(t *testing.T) {
+	bundlePath, _ := buildSBOMBundle(t)
+
+	tempDir, err := extractBundle(bundlePath)
+	require.NoError(t, err)
+	defer cleanupOnError(tempDir)
+	writeTree(t, tempDir, map[string]string{"spec.yaml": "product: tampered\n"})
+	require.NoError(t, repackBundle(tempDir, bundlePath))
+
+	_, err = GenerateSBOM(bundlePath, SBOMOptions{})
+	assert.Error(t, err)
+}
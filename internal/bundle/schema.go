@@ -0,0 +1,102 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// bundleSchemaPrefix prefixes every bundle schema version; the suffix is
+	// the schema's major version
+	bundleSchemaPrefix = "specular.bundle/v"
+
+	// currentSchemaVersion is the major version of BundleSchemaVersion, the
+	// newest schema this binary understands
+	currentSchemaVersion = 1
+)
+
+// errSchemaTooNew marks manifests written by a newer specular
+var errSchemaTooNew = errors.New("bundle schema is newer than this version of specular supports")
+
+// manifestMigration upgrades a decoded manifest document by one schema
+// version, updating its schema field
+type manifestMigration func(doc map[string]interface{}) error
+
+// manifestMigrations holds the migration from each older schema version to
+// the next. specular.bundle/v1 is the only schema released so far, so there
+// is nothing to migrate yet; a v2 schema adds the v1 migration here.
+var manifestMigrations = map[int]manifestMigration{}
+
+// parseSchemaVersion returns the major version of a bundle schema string
+// such as "specular.bundle/v1"
+func parseSchemaVersion(schema string) (int, error) {
+	if !strings.HasPrefix(schema, bundleSchemaPrefix) {
+		return 0, fmt.Errorf("unrecognized bundle schema %q", schema)
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(schema, bundleSchemaPrefix))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("unrecognized bundle schema %q", schema)
+	}
+	return version, nil
+}
+
+// decodeManifest parses manifest YAML of any supported schema version into
+// the current Manifest representation
+func decodeManifest(data []byte) (*Manifest, error) {
+	return decodeManifestWith(data, currentSchemaVersion, manifestMigrations)
+}
+
+// decodeManifestWith detects the manifest's schema version and runs the
+// migrations from that version up to current before decoding it
+func decodeManifestWith(data []byte, current int, migrations map[int]manifestMigration) (*Manifest, error) {
+	var header struct {
+		Schema string `yaml:"schema"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	// A missing schema is reported by Manifest.Validate
+	version := current
+	if header.Schema != "" {
+		var err error
+		if version, err = parseSchemaVersion(header.Schema); err != nil {
+			return nil, err
+		}
+	}
+	if version > current {
+		return nil, ErrUnsupportedSchema(header.Schema, fmt.Sprintf("%s%d", bundleSchemaPrefix, current))
+	}
+
+	if version < current {
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		for ; version < current; version++ {
+			migrate, ok := migrations[version]
+			if !ok {
+				return nil, fmt.Errorf("no migration from bundle schema %s%d", bundleSchemaPrefix, version)
+			}
+			if err := migrate(doc); err != nil {
+				return nil, fmt.Errorf("failed to migrate manifest from schema %s%d: %w", bundleSchemaPrefix, version, err)
+			}
+		}
+
+		migrated, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode migrated manifest: %w", err)
+		}
+		data = migrated
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readManifestFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "manifests", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeManifest_Fixtures(t *testing.T) {
+	t.Run("v1", func(t *testing.T) {
+		manifest, err := decodeManifest(readManifestFixture(t, "v1.yaml"))
+		require.NoError(t, err)
+		require.NoError(t, manifest.Validate())
+		assert.Equal(t, BundleSchemaVersion, manifest.Schema)
+		assert.Equal(t, []string{"pm", "security"}, manifest.RequiredApprovals)
+		assert.Len(t, manifest.Files, 2)
+		assert.Nil(t, manifest.Delta)
+	})
+
+	t.Run("v1 delta", func(t *testing.T) {
+		manifest, err := decodeManifest(readManifestFixture(t, "v1-delta.yaml"))
+		require.NoError(t, err)
+		require.NoError(t, manifest.Validate())
+		require.NotNil(t, manifest.Delta)
+		assert.Equal(t, "1.0.0", manifest.Delta.BaseVersion)
+		assert.Equal(t, []string{"routing.yaml"}, manifest.Delta.Removed)
+		assert.NotEmpty(t, manifest.Integrity.ManifestDigest)
+	})
+
+	t.Run("newer schema", func(t *testing.T) {
+		_, err := decodeManifest(readManifestFixture(t, "v2.yaml"))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errSchemaTooNew))
+		assert.Contains(t, err.Error(), "specular.bundle/v2")
+		assert.Contains(t, err.Error(), "upgrade specular")
+	})
+}
+
+func TestDecodeManifest_UnrecognizedSchema(t *testing.T) {
+	for _, schema := range []string{"specular.bundle/v0", "specular.bundle/latest", "other.bundle/v1"} {
+		t.Run(schema, func(t *testing.T) {
+			_, err := decodeManifest([]byte("schema: " + schema + "\n"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "unrecognized bundle schema")
+			assert.False(t, errors.Is(err, errSchemaTooNew))
+		})
+	}
+}
+
+func TestDecodeManifestWith_Migrations(t *testing.T) {
+	// Pretend v1 is historical: the migration renames a field to its v2
+	// name and moves the manifest to the v2 schema
+	migrations := map[int]manifestMigration{
+		1: func(doc map[string]interface{}) error {
+			doc["description"] = doc["governance_level"]
+			delete(doc, "governance_level")
+			doc["schema"] = "specular.bundle/v2"
+			return nil
+		},
+	}
+
+	manifest, err := decodeManifestWith(readManifestFixture(t, "v1.yaml"), 2, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, "specular.bundle/v2", manifest.Schema)
+	assert.Equal(t, "L2", manifest.Description)
+	assert.Empty(t, manifest.GovernanceLevel)
+	assert.Equal(t, "acme/healthcare-api", manifest.ID)
+	assert.False(t, manifest.Created.IsZero())
+
+	_, err = decodeManifestWith(readManifestFixture(t, "v1.yaml"), 3, migrations)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no migration from bundle schema specular.bundle/v2")
+}
+
+func TestLoadBundle_NewerSchema(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "future.sbundle.tgz")
+	file, err := os.Create(bundlePath)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	manifest := readManifestFixture(t, "v2.yaml")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: ManifestFileName, Mode: 0600, Size: int64(len(manifest))}))
+	_, err = tw.Write(manifest)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+
+	_, err = LoadBundle(bundlePath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Please upgrade specular")
+
+	_, err = GetBundleInfo(bundlePath)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errSchemaTooNew))
+
+	result, err := NewValidator(VerifyOptions{}).Verify(bundlePath)
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, ErrCodeUnsupportedSchema, result.Errors[0].Code)
+}
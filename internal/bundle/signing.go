@@ -70,6 +70,11 @@ func (s *Signer) SignApproval(req ApprovalRequest) (*Approval, error) {
 		SignedAt:      time.Now(),
 		SignatureType: sigType,
 		Comment:       req.Comment,
+		ExpiresAt:     req.ExpiresAt,
+	}
+
+	if approval.ExpiresAt != nil && !approval.ExpiresAt.After(approval.SignedAt) {
+		return nil, fmt.Errorf("approval expiry %s is not in the future", approval.ExpiresAt.Format(time.RFC3339))
 	}
 
 	// Sign based on signature type
@@ -82,6 +87,10 @@ func (s *Signer) SignApproval(req ApprovalRequest) (*Approval, error) {
 		if err := s.signWithGPG(approval, req.BundleDigest, keyPath); err != nil {
 			return nil, fmt.Errorf("GPG signing failed: %w", err)
 		}
+	case SignatureTypeCosign:
+		if err := s.signWithCosign(approval, req.BundleDigest, keyPath, req.CertificatePath); err != nil {
+			return nil, fmt.Errorf("cosign signing failed: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported signature type: %s", sigType)
 	}
@@ -254,6 +263,10 @@ func (v *Verifier) checkExpiry(approval *Approval) error {
 		return fmt.Errorf("approval expired (max age: %s, signed: %s)",
 			v.options.MaxAge, approval.SignedAt.Format(time.RFC3339))
 	}
+	if approval.ExpiredAt(time.Now()) {
+		return fmt.Errorf("%w at %s (signed: %s)", ErrApprovalExpired,
+			approval.ExpiresAt.Format(time.RFC3339), approval.SignedAt.Format(time.RFC3339))
+	}
 	return nil
 }
 
@@ -307,6 +320,10 @@ func (v *Verifier) verifyCryptographicSignature(approval *Approval) error {
 		if err := v.verifyGPGSignature(approval); err != nil {
 			return fmt.Errorf("GPG signature verification failed: %w", err)
 		}
+	case SignatureTypeCosign:
+		if err := v.verifyCosignSignature(approval); err != nil {
+			return fmt.Errorf("cosign signature verification failed: %w", err)
+		}
 	default:
 		return fmt.Errorf("unsupported signature type: %s", approval.SignatureType)
 	}
@@ -429,6 +446,11 @@ func formatSignMessage(approval *Approval, bundleDigest string) string {
 		buf.WriteString(fmt.Sprintf("Comment: %s\n", approval.Comment))
 	}
 
+	// Expiry is part of the signed message so it cannot be extended later
+	if approval.ExpiresAt != nil {
+		buf.WriteString(fmt.Sprintf("Expires: %s\n", approval.ExpiresAt.Format(time.RFC3339)))
+	}
+
 	return buf.String()
 }
 
@@ -461,6 +483,14 @@ func detectDefaultKey(sigType SignatureType) (string, error) {
 		// For GPG, we'll use the default key (no key ID specified)
 		return "", nil
 
+	case SignatureTypeCosign:
+		// cosign generate-key-pair writes cosign.key to the working directory
+		if _, statErr := os.Stat(DefaultCosignKeyPath); statErr == nil {
+			return DefaultCosignKeyPath, nil
+		}
+
+		return "", fmt.Errorf("no %s found in the current directory", DefaultCosignKeyPath)
+
 	default:
 		return "", fmt.Errorf("unsupported signature type for default key detection: %s", sigType)
 	}
@@ -220,6 +220,12 @@ func TestFormatSignMessage(t *testing.T) {
 
 	messageNoComment := formatSignMessage(approvalNoComment, bundleDigest)
 	assert.NotContains(t, messageNoComment, "Comment:")
+	assert.NotContains(t, messageNoComment, "Expires:")
+
+	// Expiry is covered by the signature
+	expiry := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	approvalNoComment.ExpiresAt = &expiry
+	assert.Contains(t, formatSignMessage(approvalNoComment, bundleDigest), "Expires: 2024-04-01T00:00:00Z")
 }
 
 func TestVerifyAllApprovals(t *testing.T) {
@@ -357,6 +363,46 @@ func TestApproval_IsExpired(t *testing.T) {
 	}
 }
 
+func TestApproval_ExpiresAt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiry := now.Add(48 * time.Hour)
+
+	approval := &Approval{SignedAt: now.Add(-time.Hour), ExpiresAt: &expiry}
+
+	assert.False(t, approval.ExpiredAt(now))
+	remaining, ok := approval.RemainingValidity(now)
+	assert.True(t, ok)
+	assert.Equal(t, 48*time.Hour, remaining)
+
+	assert.True(t, approval.ExpiredAt(expiry))
+	remaining, ok = approval.RemainingValidity(expiry.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Zero(t, remaining)
+
+	noExpiry := &Approval{SignedAt: now}
+	assert.False(t, noExpiry.ExpiredAt(now.Add(10*365*24*time.Hour)))
+	_, ok = noExpiry.RemainingValidity(now)
+	assert.False(t, ok)
+}
+
+func TestVerifier_VerifyApproval_Expired(t *testing.T) {
+	expiry := time.Now().Add(-time.Minute)
+	approval := &Approval{
+		Role:          "security",
+		User:          "bob@example.com",
+		SignedAt:      time.Now().Add(-240 * 24 * time.Hour),
+		ExpiresAt:     &expiry,
+		SignatureType: SignatureTypeSSH,
+		Signature:     "base64-encoded-signature",
+		PublicKey:     "ssh-ed25519 AAAA...",
+	}
+
+	verifier := NewVerifier(ApprovalVerificationOptions{BundleDigest: "sha256:abc123"})
+	err := verifier.VerifyApproval(approval)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrApprovalExpired)
+}
+
 func TestApproval_MatchesFingerprint(t *testing.T) {
 	approval := &Approval{
 		PublicKeyFingerprint: "SHA256:abc123def456",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -32,13 +33,14 @@ func (v *Validator) Verify(bundlePath string) (*ValidationResult, error) {
 	// Store bundle path for signature verification
 	v.bundlePath = bundlePath
 	result := &ValidationResult{
-		Valid:            true,
-		Errors:           []ValidationError{},
-		Warnings:         []ValidationWarning{},
-		ChecksumValid:    true,
-		ApprovalsValid:   true,
-		AttestationValid: true,
-		PolicyCompliant:  true,
+		Valid:                  true,
+		Errors:                 []ValidationError{},
+		Warnings:               []ValidationWarning{},
+		ChecksumValid:          true,
+		ApprovalsValid:         true,
+		AttestationValid:       true,
+		PolicyCompliant:        true,
+		ManifestSignatureValid: true,
 	}
 
 	// Extract bundle to temporary directory using shared function
@@ -58,6 +60,15 @@ func (v *Validator) Verify(bundlePath string) (*ValidationResult, error) {
 		result.Valid = false
 		result.ChecksumValid = false
 
+		if errors.Is(loadManifestErr, errSchemaTooNew) {
+			result.Errors = append(result.Errors, ValidationError{
+				Code:    ErrCodeUnsupportedSchema,
+				Message: loadManifestErr.Error(),
+				Field:   "schema",
+			})
+			return result, nil
+		}
+
 		// Provide user-friendly error message
 		bundleErr := ErrInvalidManifest("manifest file is missing or unreadable", loadManifestErr)
 		result.Errors = append(result.Errors, ValidationError{
@@ -87,6 +98,12 @@ func (v *Validator) Verify(bundlePath string) (*ValidationResult, error) {
 		result.ChecksumValid = false
 	}
 
+	// Verify manifest signature
+	if !v.verifyManifestSignature(tempDir, result) {
+		result.Valid = false
+		result.ManifestSignatureValid = false
+	}
+
 	// Verify approvals if required
 	if v.opts.RequireApprovals {
 		if loadApprovalsErr := v.loadApprovals(tempDir); loadApprovalsErr != nil {
@@ -100,6 +117,10 @@ func (v *Validator) Verify(bundlePath string) (*ValidationResult, error) {
 			result.Valid = false
 			result.ApprovalsValid = false
 		}
+	} else {
+		// Load approvals without verifying them so callers such as
+		// bundle diff can inspect them; bundles without approvals are fine
+		_ = v.loadApprovals(tempDir) //nolint:errcheck // Approvals are optional here
 	}
 
 	// Verify attestation if required
@@ -115,6 +136,8 @@ func (v *Validator) Verify(bundlePath string) (*ValidationResult, error) {
 			result.Valid = false
 			result.AttestationValid = false
 		}
+	} else {
+		_ = v.loadAttestation(tempDir) //nolint:errcheck // Attestation is optional here
 	}
 
 	// Apply strict mode validation
@@ -133,12 +156,12 @@ func (v *Validator) loadManifest(tempDir string) error {
 		return fmt.Errorf("failed to read manifest: %w", readErr)
 	}
 
-	var manifest Manifest
-	if unmarshalErr := yaml.Unmarshal(data, &manifest); unmarshalErr != nil {
-		return fmt.Errorf("failed to parse manifest: %w", unmarshalErr)
+	manifest, decodeErr := decodeManifest(data)
+	if decodeErr != nil {
+		return decodeErr
 	}
 
-	v.bundle.Manifest = &manifest
+	v.bundle.Manifest = manifest
 	return nil
 }
 
@@ -213,6 +236,36 @@ func (v *Validator) calculateFileChecksum(filePath string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
+// verifyManifestSignature verifies the detached manifest signature against
+// the trusted keys. A missing signature, or a valid one from an untrusted
+// signer, only fails when a signature is required.
+func (v *Validator) verifyManifestSignature(tempDir string, result *ValidationResult) bool {
+	_, err := verifyManifestSignatureDir(tempDir, v.opts.TrustPublicKeys)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, ErrManifestSignatureNotFound) && !v.opts.RequireManifestSignature {
+		return true
+	}
+
+	if errors.Is(err, ErrManifestSignerUntrusted) && !v.opts.RequireManifestSignature {
+		result.Warnings = append(result.Warnings, ValidationWarning{
+			Code:    WarnCodeUntrustedSigner,
+			Message: err.Error() + " (pass --trusted-key to pin the signing key)",
+			Field:   ManifestSignatureFileName,
+		})
+		return true
+	}
+
+	result.Errors = append(result.Errors, ValidationError{
+		Code:    ErrCodeManifestSignature,
+		Message: err.Error(),
+		Field:   ManifestSignatureFileName,
+	})
+	return false
+}
+
 // loadApprovals loads approvals from the extracted bundle.
 func (v *Validator) loadApprovals(tempDir string) error {
 	approvalsDir := filepath.Join(tempDir, "approvals")
@@ -391,7 +444,9 @@ func (v *Validator) verifyAttestation(result *ValidationResult) bool {
 	// Perform cryptographic verification using AttestationVerifier
 	verifyOpts := AttestationVerificationOptions{
 		VerifySignature:   true,
-		RequireRekorEntry: false, // Rekor not yet fully implemented
+		RekorURL:          v.opts.RekorURL,
+		RequireRekorEntry: v.opts.RequireRekorEntry,
+		Offline:           v.opts.AllowOffline,
 		VerifyTimestamp:   true,
 		MaxAge:            0, // No age restriction by default
 	}
@@ -409,11 +464,11 @@ func (v *Validator) verifyAttestation(result *ValidationResult) bool {
 		return false
 	}
 
-	// Add informational warning about Rekor if entry exists but verification is disabled
-	if v.bundle.Attestation.HasRekorEntry() && !verifyOpts.RequireRekorEntry {
+	// Offline, the Rekor entry was only compared with the attestation
+	if v.bundle.Attestation.HasRekorEntry() && verifyOpts.Offline {
 		result.Warnings = append(result.Warnings, ValidationWarning{
-			Code:    "REKOR_NOT_VERIFIED",
-			Message: "attestation includes Rekor entry but Rekor verification is not yet fully implemented",
+			Code:    "REKOR_OFFLINE",
+			Message: "Rekor entry matches the attestation but was not checked against the log (offline)",
 			Field:   "attestation.rekor_entry",
 		})
 	}
@@ -435,6 +490,11 @@ func LoadBundle(bundlePath string) (*Bundle, error) {
 	}
 
 	if !result.Valid {
+		for _, validationErr := range result.Errors {
+			if validationErr.Code == ErrCodeUnsupportedSchema {
+				return nil, fmt.Errorf("failed to load bundle: %s", validationErr.Message)
+			}
+		}
 		return nil, fmt.Errorf("bundle validation failed: %d errors", len(result.Errors))
 	}
 
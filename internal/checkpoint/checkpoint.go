@@ -1,10 +1,12 @@
 package checkpoint
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"time"
 )
 
@@ -32,18 +34,58 @@ type Task struct {
 
 // Manager handles checkpoint persistence and recovery
 type Manager struct {
-	checkpointDir string
-	autoSave      bool
-	saveInterval  time.Duration
+	store             CheckpointStore
+	autoSave          bool
+	saveInterval      time.Duration
+	compressThreshold int
+	encryptor         *encryptor
 }
 
-// NewManager creates a new checkpoint manager
-func NewManager(checkpointDir string, autoSave bool, saveInterval time.Duration) *Manager {
-	return &Manager{
-		checkpointDir: checkpointDir,
-		autoSave:      autoSave,
-		saveInterval:  saveInterval,
+// DefaultCompressThreshold is the serialized size above which auto mode
+// gzips checkpoints. Checkpoints embed the spec and plan as metadata, so
+// large projects produce large payloads.
+const DefaultCompressThreshold = 64 * 1024
+
+// NewManager creates a new checkpoint manager. The location is a local
+// directory or a store URL such as s3://bucket/prefix (see NewStore). An
+// invalid location is reported by the first checkpoint operation.
+func NewManager(location string, autoSave bool, saveInterval time.Duration) *Manager {
+	store, err := NewStore(location)
+	if err != nil {
+		store = errStore{err: err}
+	}
+	return NewManagerWithStore(store, autoSave, saveInterval)
+}
+
+// NewManagerWithStore creates a checkpoint manager backed by the given store.
+// Checkpoints are encrypted when a key is set in SPECULAR_CHECKPOINT_KEY or
+// the OS keyring.
+func NewManagerWithStore(store CheckpointStore, autoSave bool, saveInterval time.Duration) *Manager {
+	m := &Manager{
+		store:        store,
+		autoSave:     autoSave,
+		saveInterval: saveInterval,
+	}
+	return m.WithEncryptionKey(defaultPassphrase())
+}
+
+// WithCompression gzips checkpoints whose JSON is larger than threshold
+// bytes (0 disables compression). Load reads compressed and plain
+// checkpoints regardless of this setting.
+func (m *Manager) WithCompression(threshold int) *Manager {
+	m.compressThreshold = threshold
+	return m
+}
+
+// WithEncryptionKey encrypts saved checkpoints with a key derived from the
+// passphrase and decrypts encrypted checkpoints on load. An empty passphrase
+// disables encryption.
+func (m *Manager) WithEncryptionKey(passphrase string) *Manager {
+	m.encryptor = nil
+	if passphrase != "" {
+		m.encryptor = newEncryptor(passphrase)
 	}
+	return m
 }
 
 // NewState creates a new checkpoint state
@@ -60,7 +102,7 @@ func NewState(operationID string) *State {
 	}
 }
 
-// Save persists the checkpoint state to disk
+// Save persists the checkpoint state to the store
 func (m *Manager) Save(state *State) error {
 	if state == nil {
 		return fmt.Errorf("checkpoint state is nil")
@@ -69,36 +111,49 @@ func (m *Manager) Save(state *State) error {
 	// Update timestamp
 	state.UpdatedAt = time.Now()
 
-	// Create checkpoint directory if it doesn't exist
-	if err := os.MkdirAll(m.checkpointDir, 0o750); err != nil {
-		return fmt.Errorf("failed to create checkpoint directory: %w", err)
-	}
-
-	// Write checkpoint file
-	checkpointPath := filepath.Join(m.checkpointDir, fmt.Sprintf("%s.json", state.OperationID))
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal checkpoint state: %w", err)
 	}
 
-	err = os.WriteFile(checkpointPath, data, 0o600)
-	if err != nil {
-		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	if m.compressThreshold > 0 && len(data) > m.compressThreshold {
+		if data, err = compress(data); err != nil {
+			return fmt.Errorf("failed to compress checkpoint state: %w", err)
+		}
 	}
 
-	return nil
+	if m.encryptor != nil {
+		if data, err = m.encryptor.seal(data); err != nil {
+			return fmt.Errorf("failed to encrypt checkpoint state: %w", err)
+		}
+	}
+
+	return m.store.Save(state.OperationID, data)
 }
 
-// Load reads the checkpoint state from disk
+// Load reads the checkpoint state from the store
 func (m *Manager) Load(operationID string) (*State, error) {
-	checkpointPath := filepath.Join(m.checkpointDir, fmt.Sprintf("%s.json", operationID))
-
-	data, err := os.ReadFile(checkpointPath)
+	data, err := m.store.Load(operationID)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("checkpoint not found: %s", operationID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, operationID)
+		}
+		return nil, err
+	}
+
+	if isEncrypted(data) {
+		if m.encryptor == nil {
+			return nil, fmt.Errorf("failed to load checkpoint %s: %w", operationID, ErrEncrypted)
+		}
+		if data, err = m.encryptor.open(data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt checkpoint %s: %w", operationID, err)
+		}
+	}
+
+	if isCompressed(data) {
+		if data, err = decompress(data); err != nil {
+			return nil, fmt.Errorf("failed to decompress checkpoint state: %w", err)
 		}
-		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
 	}
 
 	var state State
@@ -111,40 +166,45 @@ func (m *Manager) Load(operationID string) (*State, error) {
 
 // Exists checks if a checkpoint exists for the given operation ID
 func (m *Manager) Exists(operationID string) bool {
-	checkpointPath := filepath.Join(m.checkpointDir, fmt.Sprintf("%s.json", operationID))
-	_, err := os.Stat(checkpointPath)
-	return err == nil
+	exists, err := m.store.Exists(operationID)
+	return err == nil && exists
 }
 
-// Delete removes a checkpoint file
+// Delete removes a checkpoint
 func (m *Manager) Delete(operationID string) error {
-	checkpointPath := filepath.Join(m.checkpointDir, fmt.Sprintf("%s.json", operationID))
-	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete checkpoint: %w", err)
-	}
-	return nil
+	return m.store.Delete(operationID)
 }
 
 // List returns all checkpoint operation IDs
 func (m *Manager) List() ([]string, error) {
-	entries, err := os.ReadDir(m.checkpointDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
-		return nil, fmt.Errorf("failed to read checkpoint directory: %w", err)
-	}
+	return m.store.List()
+}
 
-	var operationIDs []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			// Remove .json extension to get operation ID
-			operationID := entry.Name()[:len(entry.Name())-5]
-			operationIDs = append(operationIDs, operationID)
-		}
+// isCompressed reports whether data starts with the gzip magic number. JSON
+// checkpoints never do.
+func isCompressed(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
 	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	return operationIDs, nil
+func decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close() //nolint:errcheck // Deferred close is best effort
+	return io.ReadAll(zr)
 }
 
 // UpdateTask updates or creates a task in the checkpoint state
@@ -0,0 +1,171 @@
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// KeyEnvVar is the environment variable holding the checkpoint encryption
+// passphrase. When it is unset, the passphrase is looked up in the OS
+// keyring under service KeyringService and account KeyringAccount.
+const (
+	KeyEnvVar      = "SPECULAR_CHECKPOINT_KEY"
+	KeyringService = "specular"
+	KeyringAccount = "checkpoint-key"
+)
+
+// ErrEncrypted is returned when an encrypted checkpoint is loaded without a
+// key
+var ErrEncrypted = errors.New("checkpoint is encrypted; set " + KeyEnvVar + " or store the key in the OS keyring")
+
+// Encrypted checkpoints start with encryptedMagic, followed by a version
+// byte, the key derivation salt, the GCM nonce, and the sealed payload
+const (
+	encryptedMagic   = "SPCK"
+	encryptedVersion = 1
+	saltSize         = 16
+	keyIterations    = 100000
+)
+
+// encryptor seals checkpoint payloads with AES-256-GCM using a key derived
+// from a passphrase with PBKDF2. Keys are derived once per salt.
+type encryptor struct {
+	passphrase string
+	salt       []byte // Salt for payloads sealed by this encryptor
+
+	mu   sync.Mutex
+	keys map[string]cipher.AEAD
+}
+
+func newEncryptor(passphrase string) *encryptor {
+	salt := make([]byte, saltSize)
+	rand.Read(salt) //nolint:errcheck // crypto/rand.Read never returns an error
+	return &encryptor{
+		passphrase: passphrase,
+		salt:       salt,
+		keys:       make(map[string]cipher.AEAD),
+	}
+}
+
+// aead returns the cipher for a salt
+func (e *encryptor) aead(salt []byte) (cipher.AEAD, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if gcm, ok := e.keys[string(salt)]; ok {
+		return gcm, nil
+	}
+
+	key := pbkdf2.Key([]byte(e.passphrase), salt, keyIterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	e.keys[string(salt)] = gcm
+	return gcm, nil
+}
+
+// seal encrypts a payload
+func (e *encryptor) seal(data []byte) ([]byte, error) {
+	gcm, err := e.aead(e.salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce) //nolint:errcheck // crypto/rand.Read never returns an error
+
+	header := make([]byte, 0, len(encryptedMagic)+1+saltSize+len(nonce))
+	header = append(header, encryptedMagic...)
+	header = append(header, encryptedVersion)
+	header = append(header, e.salt...)
+	header = append(header, nonce...)
+
+	// The header is authenticated along with the payload
+	return gcm.Seal(header, nonce, data, header), nil
+}
+
+// open decrypts a payload sealed by seal
+func (e *encryptor) open(data []byte) ([]byte, error) {
+	offset := len(encryptedMagic)
+	if len(data) < offset+1+saltSize || data[offset] != encryptedVersion {
+		return nil, fmt.Errorf("unsupported encrypted checkpoint format")
+	}
+	salt := data[offset+1 : offset+1+saltSize]
+
+	gcm, err := e.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := offset + 1 + saltSize + gcm.NonceSize()
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("encrypted checkpoint is truncated")
+	}
+	nonce := data[offset+1+saltSize : headerSize]
+
+	plaintext, err := gcm.Open(nil, nonce, data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, fmt.Errorf("wrong key or corrupted checkpoint")
+	}
+	return plaintext, nil
+}
+
+// isEncrypted reports whether data is an encrypted checkpoint
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedMagic))
+}
+
+// keyringLookup reads the passphrase from the OS keyring with the platform's
+// keyring CLI
+func keyringLookup() string {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", KeyringService, "-a", KeyringAccount, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", KeyringService, "account", KeyringAccount)
+	default:
+		return ""
+	}
+
+	// A missing tool or entry means no key is configured
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+var (
+	keyringOnce       sync.Once
+	keyringPassphrase string
+)
+
+// defaultPassphrase returns the passphrase from KeyEnvVar, or from the OS
+// keyring, which is read at most once per process
+func defaultPassphrase() string {
+	if passphrase := os.Getenv(KeyEnvVar); passphrase != "" {
+		return passphrase
+	}
+	keyringOnce.Do(func() {
+		keyringPassphrase = keyringLookup()
+	})
+	return keyringPassphrase
+}
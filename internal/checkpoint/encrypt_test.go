@@ -0,0 +1,89 @@
+package checkpoint
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManagerEncryption(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir, false, 0).WithEncryptionKey("correct horse battery staple").WithCompression(1024)
+
+	small := NewState("small")
+	small.SetMetadata("spec_json", `{"product":"Secret Product"}`)
+	large := NewState("large")
+	large.SetMetadata("plan_json", strings.Repeat(`{"id":"secret-task"}`, 1000))
+	for _, state := range []*State{small, large} {
+		if err := manager.Save(state); err != nil {
+			t.Fatalf("Save(%s): %v", state.OperationID, err)
+		}
+	}
+
+	for _, id := range []string{"small", "large"} {
+		data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isEncrypted(data) || bytes.Contains(data, []byte("Secret")) || bytes.Contains(data, []byte("secret")) {
+			t.Errorf("%s checkpoint is not encrypted at rest", id)
+		}
+	}
+
+	// A new manager with the same passphrase reads both
+	reader := NewManager(dir, false, 0).WithEncryptionKey("correct horse battery staple")
+	for _, state := range []*State{small, large} {
+		loaded, err := reader.Load(state.OperationID)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", state.OperationID, err)
+		}
+		if len(loaded.Metadata) != len(state.Metadata) {
+			t.Errorf("Load(%s) metadata = %v", state.OperationID, loaded.Metadata)
+		}
+	}
+
+	// Loading without the key fails loudly
+	if _, err := NewManager(dir, false, 0).WithEncryptionKey("").Load("small"); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Load without key error = %v, want ErrEncrypted", err)
+	}
+
+	// So does loading with the wrong key
+	_, err := NewManager(dir, false, 0).WithEncryptionKey("wrong").Load("small")
+	if err == nil || !strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("Load with wrong key error = %v", err)
+	}
+}
+
+func TestManagerEncryption_PlainCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewManager(dir, false, 0).WithEncryptionKey("").Save(NewState("plain")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checkpoints saved before a key was configured stay readable
+	if _, err := NewManager(dir, false, 0).WithEncryptionKey("key").Load("plain"); err != nil {
+		t.Errorf("Load plain checkpoint with key: %v", err)
+	}
+}
+
+func TestNewManager_KeyFromEnvironment(t *testing.T) {
+	t.Setenv(KeyEnvVar, "from-env")
+	dir := t.TempDir()
+	if err := NewManager(dir, false, 0).Save(NewState("env")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(data) {
+		t.Error("checkpoint should be encrypted when " + KeyEnvVar + " is set")
+	}
+	if _, err := NewManager(dir, false, 0).WithEncryptionKey("from-env").Load("env"); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+}
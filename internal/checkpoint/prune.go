@@ -0,0 +1,111 @@
+package checkpoint
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PrunePolicy selects the checkpoints Prune deletes. A checkpoint is deleted
+// when it is older than its retention period or when it is not among the
+// Keep most recently updated checkpoints. Completed checkpoints use
+// OlderThan; failed and unfinished checkpoints, which can still be resumed
+// or retried, use FailedOlderThan and are kept for that period even beyond
+// the Keep count.
+type PrunePolicy struct {
+	OlderThan       time.Duration // Retention for completed checkpoints (0 = no age limit)
+	FailedOlderThan time.Duration // Retention for failed and unfinished checkpoints (0 = no age limit)
+	Keep            int           // Maximum checkpoints kept, most recent first (0 = no count limit)
+	DryRun          bool          // Report what would be deleted without deleting
+
+	// Now is the reference time for ages (zero = time.Now)
+	Now time.Time
+}
+
+// PruneResult lists the checkpoints a prune deleted and kept
+type PruneResult struct {
+	Deleted []string
+	Kept    []string
+	Errors  map[string]error // Checkpoints that could not be loaded or deleted
+}
+
+// Prune deletes the checkpoints selected by the policy. Checkpoints that
+// cannot be loaded are left in place and reported in the result.
+func (m *Manager) Prune(policy PrunePolicy) (*PruneResult, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	operationIDs, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	now := policy.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	result := &PruneResult{
+		Deleted: []string{},
+		Kept:    []string{},
+		Errors:  make(map[string]error),
+	}
+
+	states := make([]*State, 0, len(operationIDs))
+	for _, operationID := range operationIDs {
+		state, loadErr := m.Load(operationID)
+		if loadErr != nil {
+			result.Errors[operationID] = loadErr
+			continue
+		}
+		state.OperationID = operationID
+		states = append(states, state)
+	}
+
+	// Most recently updated first
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].UpdatedAt.After(states[j].UpdatedAt)
+	})
+
+	for i, state := range states {
+		if !policy.expired(state, i, now) {
+			result.Kept = append(result.Kept, state.OperationID)
+			continue
+		}
+		if !policy.DryRun {
+			if deleteErr := m.Delete(state.OperationID); deleteErr != nil {
+				result.Errors[state.OperationID] = deleteErr
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, state.OperationID)
+	}
+
+	return result, nil
+}
+
+// expired reports whether the checkpoint at the given recency rank is
+// selected for deletion
+func (p PrunePolicy) expired(state *State, rank int, now time.Time) bool {
+	age := now.Sub(state.UpdatedAt)
+	if state.Status != "completed" && p.FailedOlderThan > 0 {
+		return age > p.FailedOlderThan
+	}
+
+	if p.Keep > 0 && rank >= p.Keep {
+		return true
+	}
+	return p.OlderThan > 0 && state.Status == "completed" && age > p.OlderThan
+}
+
+// Validate checks that the policy limits are not negative
+func (p PrunePolicy) Validate() error {
+	if p.OlderThan < 0 || p.FailedOlderThan < 0 {
+		return fmt.Errorf("retention period cannot be negative")
+	}
+	if p.Keep < 0 {
+		return fmt.Errorf("keep count cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerPrune(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		policy      PrunePolicy
+		wantDeleted string
+	}{
+		{
+			name:        "completed sessions expire first",
+			policy:      PrunePolicy{OlderThan: 30 * day, FailedOlderThan: 90 * day},
+			wantDeleted: "completed-old",
+		},
+		{
+			name:        "failed sessions expire later",
+			policy:      PrunePolicy{OlderThan: 30 * day, FailedOlderThan: 50 * day},
+			wantDeleted: "completed-old,failed-old",
+		},
+		{
+			name:        "keep the most recent",
+			policy:      PrunePolicy{Keep: 2},
+			wantDeleted: "completed-old,failed-old",
+		},
+		{
+			name:        "failed sessions outlive the keep count",
+			policy:      PrunePolicy{Keep: 1, FailedOlderThan: 90 * day},
+			wantDeleted: "completed-old",
+		},
+		{
+			name:        "no limits",
+			policy:      PrunePolicy{},
+			wantDeleted: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewFileStore(t.TempDir())
+			saveAt(t, store, "completed-new", "completed", now.Add(-1*day))
+			saveAt(t, store, "completed-old", "completed", now.Add(-40*day))
+			saveAt(t, store, "failed-old", "failed", now.Add(-60*day))
+			saveAt(t, store, "running-new", "running", now.Add(-2*day))
+
+			manager := NewManagerWithStore(store, false, 0)
+			tt.policy.Now = now
+			result, err := manager.Prune(tt.policy)
+			if err != nil {
+				t.Fatalf("Prune: %v", err)
+			}
+
+			if got := strings.Join(sortedCopy(result.Deleted), ","); got != tt.wantDeleted {
+				t.Errorf("Deleted = %q, want %q", got, tt.wantDeleted)
+			}
+			remaining, _ := manager.List() //nolint:errcheck // Checked by length
+			if len(remaining)+len(result.Deleted) != 4 || len(result.Kept) != len(remaining) {
+				t.Errorf("remaining = %v, kept = %v", remaining, result.Kept)
+			}
+		})
+	}
+}
+
+func TestManagerPrune_DryRunAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	saveAt(t, store, "old", "completed", time.Now().Add(-48*time.Hour))
+	if err := os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManagerWithStore(store, false, 0)
+	result, err := manager.Prune(PrunePolicy{OlderThan: time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(result.Deleted) != 1 || !manager.Exists("old") {
+		t.Errorf("dry run deleted = %v, exists = %v", result.Deleted, manager.Exists("old"))
+	}
+	if _, ok := result.Errors["corrupt"]; !ok || !manager.Exists("corrupt") {
+		t.Errorf("corrupt checkpoint should be reported and kept, errors = %v", result.Errors)
+	}
+
+	if _, err := manager.Prune(PrunePolicy{Keep: -1}); err == nil {
+		t.Error("expected error for negative keep")
+	}
+}
+
+func TestManagerCompression(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir, false, 0).WithCompression(1024)
+
+	small := NewState("small")
+	large := NewState("large")
+	large.SetMetadata("plan_json", strings.Repeat(`{"id":"task"}`, 1000))
+	for _, state := range []*State{small, large} {
+		if err := manager.Save(state); err != nil {
+			t.Fatalf("Save(%s): %v", state.OperationID, err)
+		}
+	}
+
+	smallData, _ := os.ReadFile(filepath.Join(dir, "small.json")) //nolint:errcheck // Checked by content
+	largeData, _ := os.ReadFile(filepath.Join(dir, "large.json")) //nolint:errcheck // Checked by content
+	if isCompressed(smallData) {
+		t.Error("small checkpoint should be stored as plain JSON")
+	}
+	if !isCompressed(largeData) || len(largeData) > 1024 {
+		t.Errorf("large checkpoint should be gzipped, got %d bytes", len(largeData))
+	}
+
+	// Any manager reads both forms
+	loaded, err := NewManager(dir, false, 0).Load("large")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if plan, _ := loaded.GetMetadata("plan_json"); plan != large.Metadata["plan_json"] {
+		t.Error("decompressed metadata does not match")
+	}
+}
+
+// saveAt stores a checkpoint with a fixed update time, which Manager.Save
+// would overwrite
+func saveAt(t *testing.T, store CheckpointStore, operationID, status string, updatedAt time.Time) {
+	t.Helper()
+	state := NewState(operationID)
+	state.Status = status
+	state.UpdatedAt = updatedAt
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(operationID, data); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,335 @@
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store stores checkpoints as <prefix>/<operation-id>.json objects in an
+// S3 bucket or an S3-compatible service such as MinIO or Cloudflare R2.
+// Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	Bucket string
+	Prefix string // Key prefix without leading or trailing slashes
+	Region string
+
+	// Endpoint is the service URL for S3-compatible services, addressed
+	// with path-style URLs. Empty uses AWS with virtual-hosted URLs.
+	Endpoint string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	Client *http.Client
+	now    func() time.Time
+}
+
+// NewS3StoreFromURL creates a store for s3://bucket/prefix. Credentials
+// come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN. The region comes from the region query parameter,
+// AWS_REGION, or AWS_DEFAULT_REGION (default us-east-1), and a custom
+// endpoint from the endpoint query parameter, AWS_ENDPOINT_URL_S3, or
+// AWS_ENDPOINT_URL.
+func NewS3StoreFromURL(u *url.URL) (*S3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("s3 checkpoint store URL %q has no bucket", u.String())
+	}
+
+	store := &S3Store{
+		Bucket:          u.Host,
+		Prefix:          strings.Trim(u.Path, "/"),
+		Region:          firstNonEmpty(u.Query().Get("region"), os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		Endpoint:        firstNonEmpty(u.Query().Get("endpoint"), os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL")),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+	if store.AccessKeyID == "" || store.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 checkpoint store requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return store, nil
+}
+
+// key returns the object key of a checkpoint
+func (s *S3Store) key(operationID string) string {
+	if s.Prefix == "" {
+		return operationID + ".json"
+	}
+	return s.Prefix + "/" + operationID + ".json"
+}
+
+// Save uploads the checkpoint object
+func (s *S3Store) Save(operationID string, data []byte) error {
+	resp, err := s.do(http.MethodPut, s.key(operationID), nil, data)
+	if err != nil {
+		return fmt.Errorf("failed to upload checkpoint: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Deferred close is best effort
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload checkpoint: %s", s3Error(resp))
+	}
+	return nil
+}
+
+// Load downloads the checkpoint object
+func (s *S3Store) Load(operationID string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, s.key(operationID), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checkpoint: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Deferred close is best effort
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to download checkpoint: %w", readErr)
+		}
+		return data, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("failed to download checkpoint: %s", s3Error(resp))
+	}
+}
+
+// s3ListResult is the ListObjectsV2 response
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the operation IDs of the checkpoint objects under the prefix
+func (s *S3Store) List() ([]string, error) {
+	prefix := ""
+	if s.Prefix != "" {
+		prefix = s.Prefix + "/"
+	}
+
+	operationIDs := []string{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		result, err := s.list(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range result.Contents {
+			name := strings.TrimPrefix(object.Key, prefix)
+			if strings.HasSuffix(name, ".json") {
+				operationIDs = append(operationIDs, strings.TrimSuffix(name, ".json"))
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return operationIDs, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *S3Store) list(query url.Values) (*s3ListResult, error) {
+	resp, err := s.do(http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Deferred close is best effort
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list checkpoints: %s", s3Error(resp))
+	}
+
+	var result s3ListResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint listing: %w", err)
+	}
+	return &result, nil
+}
+
+// Exists reports whether the checkpoint object exists
+func (s *S3Store) Exists(operationID string) (bool, error) {
+	resp, err := s.do(http.MethodHead, s.key(operationID), nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check checkpoint: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Deferred close is best effort
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check checkpoint: %s", resp.Status)
+	}
+}
+
+// Delete removes the checkpoint object
+func (s *S3Store) Delete(operationID string) error {
+	resp, err := s.do(http.MethodDelete, s.key(operationID), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Deferred close is best effort
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete checkpoint: %s", s3Error(resp))
+	}
+	return nil
+}
+
+// objectURL returns the URL of an object key, or of the bucket when key is
+// empty
+func (s *S3Store) objectURL(key string, query url.Values) (*url.URL, error) {
+	var u *url.URL
+	if s.Endpoint != "" {
+		endpoint, err := url.Parse(strings.TrimRight(s.Endpoint, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid s3 endpoint %q: %w", s.Endpoint, err)
+		}
+		u = endpoint
+		u.Path = endpoint.Path + "/" + s.Bucket + "/" + key
+	} else {
+		u = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.Bucket, s.Region),
+			Path:   "/" + key,
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+// do sends a signed request
+func (s *S3Store) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u, err := s.objectURL(key, query)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.sign(req, body)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// sign adds AWS Signature Version 4 headers to the request. Every header
+// already set on the request is signed.
+func (s *S3Store) sign(req *http.Request, body []byte) {
+	now := time.Now().UTC()
+	if s.now != nil {
+		now = s.now().UTC()
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	// Canonical headers, including host, sorted by lower-case name
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, with spaces as %20
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Error returns the status and error code of a failed S3 response
+func s3Error(resp *http.Response) string {
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024)) //nolint:errcheck // Best effort error detail
+	if xml.Unmarshal(data, &body) == nil && body.Code != "" {
+		return fmt.Sprintf("%s: %s %s", resp.Status, body.Code, body.Message)
+	}
+	return resp.Status
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package checkpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when a checkpoint does not exist in the store
+var ErrNotFound = errors.New("checkpoint not found")
+
+// CheckpointStore persists serialized checkpoint states by operation ID
+type CheckpointStore interface {
+	// Save writes the checkpoint data, replacing any existing checkpoint
+	Save(operationID string, data []byte) error
+
+	// Load reads the checkpoint data, or returns ErrNotFound
+	Load(operationID string) ([]byte, error)
+
+	// List returns the operation IDs of all stored checkpoints
+	List() ([]string, error)
+
+	// Exists reports whether a checkpoint is stored for the operation ID
+	Exists(operationID string) (bool, error)
+
+	// Delete removes the checkpoint. Deleting a missing checkpoint is not an error.
+	Delete(operationID string) error
+}
+
+// NewStore returns the store for a checkpoint location: an S3 URL such as
+// s3://bucket/prefix, or a local directory
+func NewStore(location string) (CheckpointStore, error) {
+	if !strings.Contains(location, "://") {
+		return NewFileStore(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint store URL %q: %w", location, err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return NewS3StoreFromURL(u)
+	case "file":
+		return NewFileStore(u.Path), nil
+	default:
+		return nil, fmt.Errorf("unsupported checkpoint store %q (use a directory or s3://bucket/prefix)", u.Scheme)
+	}
+}
+
+// FileStore stores each checkpoint as <operation-id>.json in a local directory
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store for a local directory. The directory is
+// created on the first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) path(operationID string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.json", operationID))
+}
+
+// Save writes the checkpoint file
+func (s *FileStore) Save(operationID string, data []byte) error {
+	// Create checkpoint directory if it doesn't exist
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path(operationID), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// Load reads the checkpoint file
+func (s *FileStore) Load(operationID string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(operationID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+	return data, nil
+}
+
+// List returns the operation IDs of the checkpoint files in the directory
+func (s *FileStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint directory: %w", err)
+	}
+
+	var operationIDs []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			operationIDs = append(operationIDs, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	return operationIDs, nil
+}
+
+// Exists reports whether the checkpoint file exists
+func (s *FileStore) Exists(operationID string) (bool, error) {
+	_, err := os.Stat(s.path(operationID))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Delete removes the checkpoint file
+func (s *FileStore) Delete(operationID string) error {
+	if err := os.Remove(s.path(operationID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
+
+// errStore fails every operation, so a misconfigured location passed to
+// NewManager surfaces on first use
+type errStore struct {
+	err error
+}
+
+func (s errStore) Save(string, []byte) error   { return s.err }
+func (s errStore) Load(string) ([]byte, error) { return nil, s.err }
+func (s errStore) List() ([]string, error)     { return nil, s.err }
+func (s errStore) Exists(string) (bool, error) { return false, s.err }
+func (s errStore) Delete(string) error         { return s.err }
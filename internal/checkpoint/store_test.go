@@ -0,0 +1,122 @@
+package checkpoint
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	store, err := NewStore(".specular/checkpoints")
+	if err != nil {
+		t.Fatalf("NewStore(dir): %v", err)
+	}
+	if fs, ok := store.(*FileStore); !ok || fs.dir != ".specular/checkpoints" {
+		t.Errorf("NewStore(dir) = %#v, want FileStore for the directory", store)
+	}
+
+	store, err = NewStore("file:///tmp/checkpoints")
+	if err != nil {
+		t.Fatalf("NewStore(file://): %v", err)
+	}
+	if fs, ok := store.(*FileStore); !ok || fs.dir != "/tmp/checkpoints" {
+		t.Errorf("NewStore(file://) = %#v, want FileStore for /tmp/checkpoints", store)
+	}
+
+	store, err = NewStore("s3://my-bucket/team/checkpoints/")
+	if err != nil {
+		t.Fatalf("NewStore(s3://): %v", err)
+	}
+	s3, ok := store.(*S3Store)
+	if !ok {
+		t.Fatalf("NewStore(s3://) = %T, want *S3Store", store)
+	}
+	if s3.Bucket != "my-bucket" || s3.Prefix != "team/checkpoints" || s3.Region != "eu-west-1" {
+		t.Errorf("S3Store = bucket %q prefix %q region %q", s3.Bucket, s3.Prefix, s3.Region)
+	}
+
+	if _, err := NewStore("gs://bucket/prefix"); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
+
+func TestNewStore_S3MissingCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	_, err := NewStore("s3://my-bucket")
+	if err == nil || !strings.Contains(err.Error(), "AWS_ACCESS_KEY_ID") {
+		t.Errorf("expected credentials error, got %v", err)
+	}
+
+	// The manager reports the configuration error on use
+	manager := NewManager("s3://my-bucket", false, 0)
+	if err := manager.Save(NewState("op")); err == nil {
+		t.Error("expected Save to fail with a misconfigured store")
+	}
+	if manager.Exists("op") {
+		t.Error("Exists should be false with a misconfigured store")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "checkpoints"))
+	testStore(t, store)
+}
+
+// testStore exercises the CheckpointStore contract
+func testStore(t *testing.T, store CheckpointStore) {
+	t.Helper()
+
+	ids, err := store.List()
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("List on empty store = %v, %v", ids, err)
+	}
+	if _, err := store.Load("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(missing) error = %v, want ErrNotFound", err)
+	}
+	if exists, err := store.Exists("missing"); err != nil || exists {
+		t.Errorf("Exists(missing) = %v, %v", exists, err)
+	}
+	if err := store.Delete("missing"); err != nil {
+		t.Errorf("Delete(missing) = %v", err)
+	}
+
+	for _, id := range []string{"auto-1", "auto-2"} {
+		if err := store.Save(id, []byte(`{"operation_id":"`+id+`"}`)); err != nil {
+			t.Fatalf("Save(%s): %v", id, err)
+		}
+	}
+
+	data, err := store.Load("auto-1")
+	if err != nil || string(data) != `{"operation_id":"auto-1"}` {
+		t.Errorf("Load(auto-1) = %q, %v", data, err)
+	}
+	if exists, err := store.Exists("auto-2"); err != nil || !exists {
+		t.Errorf("Exists(auto-2) = %v, %v", exists, err)
+	}
+
+	ids, err = store.List()
+	if err != nil || strings.Join(sortedCopy(ids), ",") != "auto-1,auto-2" {
+		t.Errorf("List = %v, %v", ids, err)
+	}
+
+	if err := store.Delete("auto-1"); err != nil {
+		t.Fatalf("Delete(auto-1): %v", err)
+	}
+	if exists, _ := store.Exists("auto-1"); exists { //nolint:errcheck // Checked above
+		t.Error("auto-1 still exists after Delete")
+	}
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
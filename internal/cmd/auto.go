@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -12,17 +14,22 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/felixgeelhaar/specular/internal/attestation"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 	"github.com/felixgeelhaar/specular/internal/auto"
 	"github.com/felixgeelhaar/specular/internal/autopolicy"
 	"github.com/felixgeelhaar/specular/internal/checkpoint"
+	"github.com/felixgeelhaar/specular/internal/exitcode"
 	"github.com/felixgeelhaar/specular/internal/hooks"
 	"github.com/felixgeelhaar/specular/internal/metrics"
+	"github.com/felixgeelhaar/specular/internal/plugin"
+	"github.com/felixgeelhaar/specular/internal/policy"
 	"github.com/felixgeelhaar/specular/internal/profiles"
-	"github.com/felixgeelhaar/specular/internal/provider"
 	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/spec"
 	"github.com/felixgeelhaar/specular/internal/telemetry"
 	"github.com/felixgeelhaar/specular/internal/trace"
 	"github.com/felixgeelhaar/specular/internal/tui"
+	"github.com/felixgeelhaar/specular/internal/ux"
 	"github.com/felixgeelhaar/specular/internal/version"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -50,6 +57,14 @@ Profiles:
     ci      - Non-interactive CI/CD pipelines (auto-approve, JSON output)
     strict  - Maximum safety (approve all steps, strict limits)
 
+  Settings are resolved as CLI flags > environment > auto.defaults.yaml >
+  profile. These environment variables override the profile (--verbose
+  shows the source of each effective setting):
+    SPECULAR_MAX_COST       - safety.max_cost_usd
+    SPECULAR_MAX_STEPS      - safety.max_steps
+    SPECULAR_TIMEOUT        - safety.timeout (e.g. 30m, or minutes)
+    SPECULAR_APPROVAL_MODE  - approvals.mode (all, per_step, critical_only, none)
+
 Exit Codes:
   0  Success - Execution completed successfully
   1  General error - Unexpected runtime error
@@ -59,6 +74,23 @@ Exit Codes:
   5  Auth error - Authentication or permission failure
   6  Network error - Network connectivity issue
 
+  With --report-file, the run also writes a JSON exit report with the
+  status, exit code, total cost, task counts, policy blocks, and artifact
+  paths. The report is written on success and on failure, regardless of
+  --json, so CI can read a stable file instead of parsing stdout.
+
+Goal History and Templates:
+  Goals are recorded in ~/.specular/goal-history. When run interactively
+  without a goal, recent goals are offered for selection.
+
+  Named templates are read from ~/.specular/goal-templates.yaml and
+  .specular/goal-templates.yaml (project templates take precedence):
+
+    templates:
+      add-endpoint:
+        description: Add a CRUD endpoint
+        goal: Add a REST endpoint for {{resource}} with validation and tests
+
 Scope Filtering:
   Filter execution to specific features or paths using --scope:
 
@@ -66,10 +98,12 @@ Scope Filtering:
     feature:ID          Match by exact feature ID (e.g., feature:feat-1)
     feature:pattern*    Match feature titles with glob (e.g., feature:User*)
     /api/path/*         Match API paths with glob (e.g., /api/users/*)
-    @tag                Match by feature tag (future)
+    @tag                Match by feature tag with glob (e.g., @critical, @db-*)
 
   Multiple patterns are combined with OR logic. By default, dependencies
-  of matched tasks are included. Use --include-dependencies=false to disable.
+  of matched tasks are included, even when they do not match themselves
+  (for example, an untagged dependency of an @critical task). Use
+  --include-dependencies=false to disable.
 
 Examples:
   specular auto "Build a REST API for user management"
@@ -77,16 +111,25 @@ Examples:
   specular auto --profile strict --dry-run "Add authentication"
   specular auto --scope feature:feat-1 "Execute only feature 1"
   specular auto --scope "feature:User*" --scope "/api/auth/*" "Execute user features"
+  specular auto --goal-template add-endpoint --var resource=orders
   specular auto --list-profiles
   specular auto --resume auto-1762811730
+  specular auto --resume auto-1762811730 --replan
+  specular auto --profile ci --report-file reports/auto.json "Add health checks"
 `,
 	Args: func(cmd *cobra.Command, args []string) error {
 		listProfiles, _ := cmd.Flags().GetBool("list-profiles")
 		resumeFrom, _ := cmd.Flags().GetString("resume")
+		goalTemplate, _ := cmd.Flags().GetString("goal-template")
+		replan, _ := cmd.Flags().GetBool("replan")
+
+		if replan && resumeFrom == "" {
+			return fmt.Errorf("invalid flag: --replan requires --resume")
+		}
 
-		// Allow no goal if listing profiles, resuming, or in interactive mode
+		// Allow no goal if listing profiles, resuming, using a goal template, or in interactive mode
 		// Interactive mode will prompt for the goal if missing
-		if !listProfiles && resumeFrom == "" && len(args) < 1 && !tui.ShouldPrompt() {
+		if !listProfiles && resumeFrom == "" && goalTemplate == "" && len(args) < 1 && !tui.ShouldPrompt() {
 			return fmt.Errorf("invalid argument: requires a goal argument when not resuming or listing profiles\n\n" +
 				"Usage: specular auto <goal>\n" +
 				"Example: specular auto \"Build a REST API for user management\"\n\n" +
@@ -94,7 +137,10 @@ Examples:
 		}
 		return nil
 	},
-	RunE: func(cmd *cobra.Command, args []string) error {
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyAutoDefaults(cmd, autoDefaultsPaths())
+	},
+	RunE: func(cmd *cobra.Command, args []string) (runErr error) {
 		// Start distributed tracing span for auto command
 		ctx, span := telemetry.StartCommandSpan(cmd.Context(), "auto")
 		defer span.End()
@@ -103,45 +149,65 @@ Examples:
 		listProfiles, _ := cmd.Flags().GetBool("list-profiles")
 		profileName, _ := cmd.Flags().GetString("profile")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		noApproval, _ := cmd.Flags().GetBool("no-approval")
-		maxCost, _ := cmd.Flags().GetFloat64("max-cost")
-		maxCostPerTask, _ := cmd.Flags().GetFloat64("max-cost-per-task")
-		maxRetries, _ := cmd.Flags().GetInt("max-retries")
-		maxSteps, _ := cmd.Flags().GetInt("max-steps")
-		timeoutMinutes, _ := cmd.Flags().GetInt("timeout")
+		onBudgetExceeded, _ := cmd.Flags().GetString("on-budget-exceeded")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		resumeFrom, _ := cmd.Flags().GetString("resume")
+		replan, _ := cmd.Flags().GetBool("replan")
 		outputDir, _ := cmd.Flags().GetString("output")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		scopePatterns, _ := cmd.Flags().GetStringSlice("scope")
 		includeDependencies, _ := cmd.Flags().GetBool("include-dependencies")
 		useTUI, _ := cmd.Flags().GetBool("tui")
 		enableTrace, _ := cmd.Flags().GetBool("trace")
+		traceOTel, _ := cmd.Flags().GetBool("trace-otel")
 		savePatches, _ := cmd.Flags().GetBool("save-patches")
 		enableAttest, _ := cmd.Flags().GetBool("attest")
+		attestKeyless, _ := cmd.Flags().GetBool("keyless")
+		goalTemplate, _ := cmd.Flags().GetString("goal-template")
+		templateVars, _ := cmd.Flags().GetStringArray("var")
+		reportFile, _ := cmd.Flags().GetString("report-file")
+		eventStream, _ := cmd.Flags().GetString("event-stream")
+		policyRego, _ := cmd.Flags().GetString("policy-rego")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		checkpointStore, _ := cmd.Flags().GetString("checkpoint-store")
+		validatorNames, _ := cmd.Flags().GetStringSlice("validator")
+		blockOnValidation, _ := cmd.Flags().GetBool("block-on-validation-errors")
+		noDetect, _ := cmd.Flags().GetBool("no-detect")
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		metricsPushURL, _ := cmd.Flags().GetString("metrics-push-url")
 
 		// Handle --list-profiles
 		if listProfiles {
 			return listAvailableProfiles()
 		}
 
-		// Load profile
-		loader := profiles.NewLoader()
-		if profileName == "" {
-			profileName = "default"
-		}
-
-		profile, err := loader.Load(profileName)
-		if err != nil {
-			return ProfileLoadError(profileName, err)
+		// With --event-stream -, stdout carries only the JSON events, so
+		// progress output goes to stderr for the rest of the run
+		var eventOut *os.File
+		if eventStream == "-" {
+			if jsonOutput {
+				return fmt.Errorf("--event-stream - and --json cannot be used together: both write to stdout")
+			}
+			eventOut = os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = eventOut }()
 		}
 
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Using profile: %s (%s)\n", profile.Name, profile.Description)
+		// Write the exit report however the run ends
+		var (
+			goal           string
+			result         *auto.Result
+			extraArtifacts []string
+		)
+		if reportFile != "" {
+			defer func() {
+				if err := writeAutoExitReport(ctx, reportFile, goal, result, runErr, outputDir, extraArtifacts); err != nil {
+					fmt.Fprintf(os.Stderr, "⚠️  Failed to write exit report: %v\n", err)
+				}
+			}()
 		}
 
 		// Build goal from args (required unless resuming)
-		goal := ""
 		if resumeFrom == "" {
 			for i, arg := range args {
 				if i > 0 {
@@ -150,17 +216,25 @@ Examples:
 				goal += arg
 			}
 
+			// Expand a named goal template if requested
+			if goalTemplate != "" {
+				if goal != "" {
+					return fmt.Errorf("cannot combine a goal argument with --goal-template")
+				}
+				expanded, err := expandGoalTemplate(goalTemplate, templateVars)
+				if err != nil {
+					return err
+				}
+				goal = expanded
+			}
+
 			// If goal is empty and we're interactive, prompt for it
 			if goal == "" && tui.ShouldPrompt() {
 				if verbose {
 					fmt.Fprintln(os.Stderr, "No goal provided, entering interactive mode...")
 				}
 
-				promptedGoal, err := tui.PromptForString(tui.Prompt{
-					Message:     "What would you like to build?",
-					Placeholder: "e.g., Build a REST API for user management",
-					Required:    true,
-				})
+				promptedGoal, err := promptForGoal()
 				if err != nil {
 					return fmt.Errorf("failed to get goal: %w", err)
 				}
@@ -176,32 +250,56 @@ Examples:
 			if goal == "" {
 				return fmt.Errorf("no goal provided. Use 'specular auto \"your goal here\"' or run interactively")
 			}
+
+			// Remember the goal for quick re-runs
+			if historyPath, err := auto.DefaultGoalHistoryPath(); err == nil {
+				if err := auto.NewGoalHistory(historyPath).Record(goal); err != nil && verbose {
+					fmt.Fprintf(os.Stderr, "⚠️  Failed to record goal history: %v\n", err)
+				}
+			}
 		}
 
-		// Load provider registry with auto-discovery
-		// Try providers.yaml first, fall back to auto-discovery
-		providerConfigPath := ".specular/providers.yaml"
-		registry, err := provider.LoadRegistryWithAutoDiscovery(providerConfigPath)
+		// Load the profile and create the router. Values from
+		// auto.defaults.yaml apply below the environment, and explicit CLI
+		// flags above it.
+		cliFlags := autoProfileFlags(cmd, cmd.Flags().Changed)
+		defaultFlags := autoProfileFlags(cmd, func(name string) bool { return setByAutoDefaults(cmd, name) })
+		setup, err := setupAuto(autoSetupOptions{
+			profileName:  profileName,
+			cliFlags:     cliFlags,
+			defaultFlags: defaultFlags,
+			router: router.RouterConfig{
+				MaxLatencyMs:     60000,
+				PreferCheap:      true, // Prefer cheaper models for auto mode
+				Seed:             seed,
+				CacheDir:         router.DefaultCacheDir,
+				CacheDisabled:    noCache,
+				OnBudgetExceeded: onBudgetExceeded,
+			},
+		})
 		if err != nil {
-			return ProviderLoadError(providerConfigPath, err)
+			return err
 		}
+		profileName = setup.profileName
+		effectiveProfile := setup.effective
+		r := setup.router
 
 		if verbose {
-			providerNames := registry.List()
+			fmt.Fprintf(os.Stderr, "Using profile: %s (%s)\n", setup.profile.Name, setup.profile.Description)
+			providerNames := setup.registry.List()
 			fmt.Fprintf(os.Stderr, "Loaded %d provider(s): %v\n", len(providerNames), providerNames)
 		}
 
-		// Create router config
-		routerConfig := &router.RouterConfig{
-			BudgetUSD:    maxCost,
-			MaxLatencyMs: 60000,
-			PreferCheap:  true, // Prefer cheaper models for auto mode
-		}
-
-		// Create router
-		r, err := router.NewRouterWithProviders(routerConfig, registry)
-		if err != nil {
-			return RouterError(err)
+		// Route to the models actually installed on the local Ollama server
+		if !noDetect {
+			localModels, err := r.DiscoverLocalModels(ctx)
+			if err != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "⚠️  Local model discovery skipped: %v\n", err)
+				}
+			} else if verbose && localModels != nil {
+				fmt.Fprintf(os.Stderr, "Discovered %d local model(s): %v\n", len(localModels), localModels)
+			}
 		}
 
 		if verbose {
@@ -209,34 +307,6 @@ Examples:
 			fmt.Fprintf(os.Stderr, "Router initialized: budget=$%.2f\n", budget.LimitUSD)
 		}
 
-		// Merge CLI flags with profile
-		cliFlags := &profiles.CLIFlags{}
-
-		// Only set flags that were explicitly provided
-		if cmd.Flags().Changed("no-approval") {
-			requireApproval := !noApproval
-			cliFlags.RequireApproval = &requireApproval
-		}
-		if cmd.Flags().Changed("max-cost") {
-			cliFlags.MaxCostUSD = &maxCost
-		}
-		if cmd.Flags().Changed("max-cost-per-task") {
-			cliFlags.MaxCostPerTask = &maxCostPerTask
-		}
-		if cmd.Flags().Changed("max-retries") {
-			cliFlags.MaxRetries = &maxRetries
-		}
-		if cmd.Flags().Changed("max-steps") {
-			cliFlags.MaxSteps = &maxSteps
-		}
-		if cmd.Flags().Changed("timeout") {
-			timeout := time.Duration(timeoutMinutes) * time.Minute
-			cliFlags.Timeout = &timeout
-		}
-
-		// Merge profile with CLI overrides
-		effectiveProfile := profiles.MergeWithCLIFlags(profile, cliFlags)
-
 		// Record span attributes for observability
 		span.SetAttributes(
 			attribute.String("goal", goal),
@@ -247,30 +317,56 @@ Examples:
 			attribute.Bool("require_approval", effectiveProfile.Approvals.Interactive),
 			attribute.Bool("tui_enabled", useTUI),
 			attribute.Bool("trace_enabled", enableTrace),
+			attribute.Bool("trace_otel", traceOTel),
+			attribute.Int64("seed", seed),
 		)
 
 		if verbose {
-			fmt.Fprintf(os.Stderr, "Effective config: max_steps=%d, timeout=%s, max_cost=$%.2f\n",
-				effectiveProfile.Safety.MaxSteps,
-				effectiveProfile.Safety.Timeout,
-				effectiveProfile.Safety.MaxCostUSD)
+			fmt.Fprintln(os.Stderr, "Effective config:")
+			for _, setting := range profiles.SettingSources(effectiveProfile, setup.envOverrides, cliFlags, defaultFlags) {
+				fmt.Fprintf(os.Stderr, "  %s=%s (from %s)\n", setting.Setting, setting.Value, setting.Source)
+			}
+		}
+
+		hookConfigs, err := loadHookConfigs(os.Stderr, effectiveProfile, verbose)
+		if err != nil {
+			return err
 		}
 
 		// Build auto config from effective profile
 		config := auto.Config{
 			Goal:                goal,
+			Profile:             profileName,
 			RequireApproval:     effectiveProfile.Approvals.Interactive && effectiveProfile.Approvals.Mode != profiles.ApprovalModeNone,
+			PerStepApproval:     effectiveProfile.Approvals.Mode == profiles.ApprovalModePerStep,
 			MaxCostUSD:          effectiveProfile.Safety.MaxCostUSD,
 			MaxCostPerTask:      effectiveProfile.Safety.MaxCostPerTask,
 			MaxRetries:          effectiveProfile.Safety.MaxRetries,
+			MaxParallelTasks:    effectiveProfile.Execution.MaxParallelTasks,
+			FailFast:            effectiveProfile.Execution.FailFast,
 			TimeoutMinutes:      int(effectiveProfile.Safety.Timeout.Minutes()),
 			Verbose:             verbose,
 			DryRun:              dryRun,
 			ResumeFrom:          resumeFrom,
+			Replan:              replan,
 			OutputDir:           outputDir,
-			JSONOutput:          jsonOutput,
+			JSONOutput:          jsonOutput || reportFile != "", // The exit report needs policy and artifact tracking
+			EventStreamPath:     eventStream,
 			ScopePatterns:       scopePatterns,
 			IncludeDependencies: includeDependencies,
+			Seed:                seed,
+			CheckpointStore:     checkpointStore,
+
+			// Spec validation
+			BlockOnValidationErrors: blockOnValidation,
+
+			// Post-execution verification
+			Verify:                  effectiveProfile.Execution.Verify,
+			VerifyCommands:          effectiveProfile.Execution.VerifyCommands,
+			RollbackOnVerifyFailure: effectiveProfile.Execution.RollbackOnVerifyFailure,
+
+			// Lifecycle hooks from the profile and hooks.yaml
+			Hooks: hookConfigs,
 		}
 
 		// Create orchestrator
@@ -280,11 +376,8 @@ Examples:
 		var tuiAdapter *tui.Adapter
 		if useTUI {
 			// Initialize TUI
-			tuiAdapter = tui.NewAdapter(goal, profileName)
-			if err := tuiAdapter.Start(); err != nil {
-				fmt.Fprintf(os.Stderr, "⚠️  Failed to start TUI, falling back to text mode: %v\n", err)
-				tuiAdapter = nil
-			} else {
+			tuiAdapter = startTUIAdapter(os.Stderr, goal, profileName)
+			if tuiAdapter != nil {
 				defer tuiAdapter.Stop()
 
 				// Create hook registry and register TUI hook
@@ -300,30 +393,67 @@ Examples:
 			}
 		}
 
-		// Set policy checker from profile if available
-		if effectiveProfile != nil {
+		// Set policy checker from the Rego policy, or from the profile
+		if policyRego != "" {
+			regoChecker, err := autopolicy.NewRegoChecker(policyRego)
+			if err != nil {
+				return err
+			}
+			orchestrator.SetPolicyChecker(newPolicyCheckerAdapter(regoChecker))
+			// The Rego policy also sees each step's changed paths
+			orchestrator.SetChangeChecker(newPolicyCheckerAdapter(regoChecker))
+		} else if effectiveProfile != nil {
 			policyChecker := autopolicy.NewCheckerFromProfile(effectiveProfile)
 			// Wrap the autopolicy checker to match auto.PolicyChecker interface
 			orchestrator.SetPolicyChecker(newPolicyCheckerAdapter(policyChecker))
+			// Only the path rules apply to a step's changes once it has run
+			if len(effectiveProfile.Safety.BlockedPaths) > 0 {
+				orchestrator.SetChangeChecker(newPolicyCheckerAdapter(autopolicy.NewChangedPathsChecker(effectiveProfile.Safety.BlockedPaths)))
+			}
 		}
 
-		// Set trace logger if enabled
-		if enableTrace {
+		// Run validator plugins against the generated spec
+		if len(validatorNames) > 0 {
+			validators, closePlugins, err := loadSpecValidators(ctx, validatorNames)
+			if err != nil {
+				return err
+			}
+			defer closePlugins()
+			for _, validator := range validators {
+				orchestrator.AddSpecValidator(validator)
+			}
+		}
+
+		// Set trace logger if enabled; OpenTelemetry step spans are
+		// parented under the command span
+		if enableTrace || traceOTel {
 			traceConfig := trace.DefaultConfig()
-			traceConfig.Enabled = true
+			traceConfig.Enabled = enableTrace
+			traceConfig.OTelExport = traceOTel
+			traceConfig.ParentContext = ctx
 			tracer, err := trace.NewLogger(traceConfig)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  Failed to initialize trace logging: %v\n", err)
 			} else {
 				orchestrator.SetTracer(tracer)
-				fmt.Printf("📝 Trace logging enabled: %s\n", tracer.GetLogPath())
+				defer tracer.Close() //#nosec G104 -- Also ends step spans left open by a failed run
+				if enableTrace {
+					fmt.Printf("📝 Trace logging enabled: %s\n", tracer.GetLogPath())
+				}
+				if traceOTel {
+					fmt.Println("📡 Exporting trace steps as OpenTelemetry spans")
+				}
 			}
 		}
 
-		// Set patch generator if enabled
-		if savePatches {
+		// Set patch generator if enabled; blocked paths and Rego policies
+		// need the step diffs
+		checksChanges := len(effectiveProfile.Safety.BlockedPaths) > 0 || policyRego != ""
+		if savePatches || checksChanges {
 			workingDir, err := os.Getwd()
-			if err != nil {
+			if err != nil && checksChanges {
+				return fmt.Errorf("cannot capture step changes to enforce path policies: %w", err)
+			} else if err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  Failed to get working directory: %v\n", err)
 			} else {
 				homeDir, _ := os.UserHomeDir()
@@ -334,10 +464,28 @@ Examples:
 		}
 
 		// Execute workflow
-		result, err := orchestrator.Execute(ctx)
+		if eventOut != nil {
+			orchestrator.SetEventStreamWriter(eventOut)
+		}
+
+		result, err = orchestrator.Execute(ctx)
+		if result != nil && result.Paused {
+			recordAutoMetrics(result, err)
+			pushRunMetrics(ctx, metricsPushURL)
+			if jsonOutput && result.AutoOutput != nil {
+				if jsonData, jsonErr := result.AutoOutput.ToJSON(); jsonErr == nil {
+					fmt.Println(string(jsonData))
+				}
+			}
+			return NewErrorWithSuggestions("Auto mode paused: budget exhausted", err,
+				fmt.Sprintf("Resume with a raised budget: specular auto --resume %s --max-cost <usd>", result.WorkflowID),
+				"Let the router pick cheaper models instead: --on-budget-exceeded downgrade",
+			)
+		}
 		if err != nil {
 			telemetry.RecordError(span, err)
 			recordAutoMetrics(result, err)
+			pushRunMetrics(ctx, metricsPushURL)
 			return fmt.Errorf("auto mode failed: %w", err)
 		}
 
@@ -353,11 +501,15 @@ Examples:
 		}
 		telemetry.RecordSuccess(span, attrs...)
 		recordAutoMetrics(result, nil)
+		pushRunMetrics(ctx, metricsPushURL)
 
 		// Generate attestation if enabled
 		if enableAttest {
-			if err := generateAttestation(result, &config, outputDir); err != nil {
+			attestPath, err := generateAttestation(ctx, result, &config, outputDir, attestKeyless)
+			if err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  Failed to generate attestation: %v\n", err)
+			} else {
+				extraArtifacts = append(extraArtifacts, attestPath)
 			}
 		}
 
@@ -388,6 +540,80 @@ Examples:
 	},
 }
 
+// newGoalOption is the selection entry for typing a goal instead of reusing one
+const newGoalOption = "✏️  Enter a new goal"
+
+// promptForGoal asks for a goal, offering recent goals from history first
+func promptForGoal() (string, error) {
+	var recent []auto.GoalHistoryEntry
+	if historyPath, err := auto.DefaultGoalHistoryPath(); err == nil {
+		recent, _ = auto.NewGoalHistory(historyPath).Recent(10)
+	}
+
+	if len(recent) > 0 {
+		options := make([]string, 0, len(recent)+1)
+		options = append(options, newGoalOption)
+		for _, entry := range recent {
+			options = append(options, entry.Goal)
+		}
+
+		selected, err := tui.PromptForSelect("What would you like to build?", options)
+		if err != nil {
+			return "", err
+		}
+		if selected != newGoalOption {
+			return selected, nil
+		}
+	}
+
+	return tui.PromptForString(tui.Prompt{
+		Message:     "What would you like to build?",
+		Placeholder: "e.g., Build a REST API for user management",
+		Required:    true,
+	})
+}
+
+// expandGoalTemplate loads the named goal template and substitutes --var values
+func expandGoalTemplate(name string, pairs []string) (string, error) {
+	templates, err := auto.LoadGoalTemplates(auto.DefaultGoalTemplatePaths()...)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, ok := templates[name]
+	if !ok {
+		return "", fmt.Errorf("goal template not found: %s", name)
+	}
+
+	vars, err := auto.ParseTemplateVars(pairs)
+	if err != nil {
+		return "", err
+	}
+
+	goal, err := tmpl.Expand(vars)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand goal template %s: %w", name, err)
+	}
+
+	return goal, nil
+}
+
+// applyRoutingPolicy sets the policy's allowed models and denied tools on
+// the router. A missing policy file leaves routing unconstrained; a policy
+// file that cannot be loaded is an error, so its rules are never skipped.
+func applyRoutingPolicy(r *router.Router, policyFile string) error {
+	if _, err := os.Stat(policyFile); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	pol, err := policy.LoadPolicy(policyFile)
+	if err != nil {
+		return fmt.Errorf("load policy %s: %w", policyFile, err)
+	}
+	r.SetPolicy(pol)
+	return nil
+}
+
 // policyCheckerAdapter adapts autopolicy.PolicyChecker to auto.PolicyChecker
 type policyCheckerAdapter struct {
 	checker autopolicy.PolicyChecker
@@ -400,6 +626,22 @@ func newPolicyCheckerAdapter(checker autopolicy.PolicyChecker) auto.PolicyChecke
 
 // CheckStep implements auto.PolicyChecker
 func (a *policyCheckerAdapter) CheckStep(ctx context.Context, step *auto.ActionStep) (*auto.PolicyResult, error) {
+	// The orchestrator provides an auto.PolicyContext; autopolicy checkers
+	// read their own type
+	if autoCtx, ok := ctx.Value("policy_context").(*auto.PolicyContext); ok {
+		ctx = context.WithValue(ctx, "policy_context", &autopolicy.PolicyContext{
+			CurrentStep:        autoCtx.CurrentStep,
+			Plan:               autoCtx.Plan,
+			StepIndex:          autoCtx.StepIndex,
+			TotalCostSoFar:     autoCtx.TotalCostSoFar,
+			ExecutionStartTime: autoCtx.ExecutionStartTime,
+			CompletedSteps:     autoCtx.CompletedSteps,
+			FailedSteps:        autoCtx.FailedSteps,
+			Snapshot:           autoCtx.Snapshot,
+			Changes:            autoCtx.Changes,
+		})
+	}
+
 	// Call the autopolicy checker
 	result, err := a.checker.CheckStep(ctx, step)
 	if err != nil {
@@ -420,6 +662,80 @@ func (a *policyCheckerAdapter) Name() string {
 	return a.checker.Name()
 }
 
+// specValidatorAdapter runs a validator plugin against the generated spec
+type specValidatorAdapter struct {
+	name      string
+	validator *plugin.ValidatorExtension
+}
+
+// loadSpecValidators discovers the named validator plugins and checks that
+// they support the validate action. The returned function shuts down any
+// persistent plugin processes.
+func loadSpecValidators(ctx context.Context, names []string) ([]auto.SpecValidator, func(), error) {
+	manager, err := newPluginManager(ux.NewPathDefaults().PolicyFile())
+	if err != nil {
+		return nil, nil, err
+	}
+	closePlugins := func() {
+		manager.Close() //nolint:errcheck // Plugin shutdown errors don't affect the run
+	}
+
+	validators := make([]auto.SpecValidator, 0, len(names))
+	for _, name := range names {
+		validator, err := plugin.NewValidatorExtension(manager, name)
+		if err == nil {
+			_, err = manager.Negotiate(ctx, name)
+		}
+		if err != nil {
+			closePlugins()
+			return nil, nil, fmt.Errorf("validator plugin: %w", err)
+		}
+		validators = append(validators, &specValidatorAdapter{name: name, validator: validator})
+	}
+	return validators, closePlugins, nil
+}
+
+// ValidateSpec implements auto.SpecValidator
+func (a *specValidatorAdapter) ValidateSpec(ctx context.Context, productSpec *spec.ProductSpec) ([]auto.ValidationIssue, error) {
+	content, err := json.Marshal(productSpec)
+	if err != nil {
+		return nil, fmt.Errorf("serialize spec: %w", err)
+	}
+
+	resp, err := a.validator.Validate(ctx, string(content), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	issues := make([]auto.ValidationIssue, 0, len(resp.Messages))
+	hasError := false
+	for _, message := range resp.Messages {
+		issues = append(issues, auto.ValidationIssue{
+			Severity: message.Severity,
+			Message:  message.Message,
+			Rule:     message.Rule,
+		})
+		hasError = hasError || message.Severity == auto.SeverityError
+	}
+
+	// A plugin that rejects the spec without saying why still fails it
+	if !resp.Valid && !hasError {
+		issues = append(issues, auto.ValidationIssue{
+			Severity: auto.SeverityError,
+			Message:  "spec rejected by validator",
+		})
+	}
+	return issues, nil
+}
+
+// Name implements auto.SpecValidator
+func (a *specValidatorAdapter) Name() string {
+	return a.name
+}
+
 // autoResumeCmd resumes a paused auto session
 var autoResumeCmd = &cobra.Command{
 	Use:   "resume [session-id]",
@@ -725,6 +1041,66 @@ Examples:
 	},
 }
 
+// autoProfileFlags collects the profile overrides among the auto flags for
+// which set reports true
+func autoProfileFlags(cmd *cobra.Command, set func(name string) bool) *profiles.CLIFlags {
+	flags := &profiles.CLIFlags{}
+
+	if set("no-approval") {
+		noApproval, _ := cmd.Flags().GetBool("no-approval")
+		requireApproval := !noApproval
+		flags.RequireApproval = &requireApproval
+	}
+	if set("max-cost") {
+		maxCost, _ := cmd.Flags().GetFloat64("max-cost")
+		flags.MaxCostUSD = &maxCost
+	}
+	if set("max-cost-per-task") {
+		maxCostPerTask, _ := cmd.Flags().GetFloat64("max-cost-per-task")
+		flags.MaxCostPerTask = &maxCostPerTask
+	}
+	if set("max-retries") {
+		maxRetries, _ := cmd.Flags().GetInt("max-retries")
+		flags.MaxRetries = &maxRetries
+	}
+	if set("max-steps") {
+		maxSteps, _ := cmd.Flags().GetInt("max-steps")
+		flags.MaxSteps = &maxSteps
+	}
+	if set("timeout") {
+		timeoutMinutes, _ := cmd.Flags().GetInt("timeout")
+		timeout := time.Duration(timeoutMinutes) * time.Minute
+		flags.Timeout = &timeout
+	}
+	if set("max-parallel-tasks") {
+		maxParallelTasks, _ := cmd.Flags().GetInt("max-parallel-tasks")
+		flags.MaxParallelTasks = &maxParallelTasks
+	}
+	if set("verify") {
+		verify, _ := cmd.Flags().GetBool("verify")
+		flags.Verify = &verify
+	}
+
+	return flags
+}
+
+// startTUIAdapter starts the TUI. It returns nil, after writing the reason
+// to w, when the run should fall back to text mode because there is no
+// interactive terminal or the TUI failed to start.
+func startTUIAdapter(w io.Writer, goal, profileName string) *tui.Adapter {
+	if !tui.ShouldPrompt() {
+		fmt.Fprintln(w, "⚠️  TUI needs an interactive terminal, falling back to text mode")
+		return nil
+	}
+
+	adapter := tui.NewAdapter(goal, profileName)
+	if err := adapter.Start(); err != nil {
+		fmt.Fprintf(w, "⚠️  Failed to start TUI, falling back to text mode: %v\n", err)
+		return nil
+	}
+	return adapter
+}
+
 func init() {
 	// Add subcommands to auto
 	autoCmd.AddCommand(autoResumeCmd)
@@ -739,23 +1115,43 @@ func init() {
 	autoCmd.Flags().Bool("dry-run", false, "Generate spec and plan but don't execute")
 	autoCmd.Flags().Bool("no-approval", false, "Skip approval gate (auto-approve plan)")
 	autoCmd.Flags().String("resume", "", "Resume from checkpoint (e.g., auto-1762811730)")
+	autoCmd.Flags().Bool("replan", false, "With --resume, re-run drift detection and re-plan the remaining tasks against the current repository")
 	autoCmd.Flags().StringP("output", "o", "", "Output directory to save spec and plan files")
 	autoCmd.Flags().Bool("save-patches", false, "Save patches for each step to enable rollback (default: profile-based)")
+	autoCmd.Flags().Bool("verify", false, "Run build and test commands after execution and fail the run if they fail (default: profile-based)")
 	autoCmd.Flags().Bool("attest", false, "Generate cryptographic attestation of workflow execution")
+	autoCmd.Flags().Bool("keyless", false, "With --attest, sign with a Fulcio certificate for the OIDC identity (SIGSTORE_ID_TOKEN or GitHub Actions); falls back to an ephemeral key without a token")
+	autoCmd.Flags().String("policy-rego", "", "Gate each step with this Rego policy (evaluated with the opa CLI) instead of the profile's policies")
+	autoCmd.Flags().Int64("seed", 0, "Seed model sampling for a reproducible run; recorded in the audit trail (0 = unseeded)")
+	autoCmd.Flags().String("checkpoint-store", defaultCheckpointStore(), "Checkpoint directory or s3://bucket/prefix URL (env: SPECULAR_CHECKPOINT_STORE)")
+	autoCmd.Flags().StringSlice("validator", []string{}, "Run this validator plugin against the generated spec (can be used multiple times)")
+	autoCmd.Flags().Bool("block-on-validation-errors", false, "Fail the run when a validator plugin reports an error in the spec")
+	autoCmd.Flags().Bool("no-detect", false, "Skip probing the local Ollama server for installed models (offline)")
+	autoCmd.Flags().Bool("no-cache", false, "Always call providers instead of reusing cached responses from "+router.DefaultCacheDir)
 
 	// Safety limit flags (override profile settings)
 	// When set to 0, uses profile defaults: max-cost=$5, max-cost-per-task=$0.50, max-retries=3, max-steps=12, timeout=25m (default profile)
 	autoCmd.Flags().Float64("max-cost", 0, "Maximum cost in USD for entire workflow (0 = use profile default)")
+	autoCmd.Flags().String("on-budget-exceeded", "", "When a model request does not fit the remaining budget: fail, downgrade to the cheapest model that fits, or pause so the run can be resumed (default: fail)")
 	autoCmd.Flags().Float64("max-cost-per-task", 0, "Maximum cost in USD per task (0 = use profile default)")
 	autoCmd.Flags().Int("max-retries", 0, "Maximum retries per failed task (0 = use profile default)")
 	autoCmd.Flags().Int("max-steps", 0, "Maximum number of workflow steps (0 = use profile default)")
 	autoCmd.Flags().Int("timeout", 0, "Timeout in minutes for entire workflow (0 = use profile default)")
+	autoCmd.Flags().Int("max-parallel-tasks", 0, "Maximum independent tasks to run at once (0 = use profile default)")
 
 	// Output flags
 	autoCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
 	autoCmd.Flags().Bool("json", false, "Output results in JSON format (for CI/CD integration, default: profile-based)")
 	autoCmd.Flags().Bool("tui", false, "Enable interactive TUI mode (default: profile-based)")
 	autoCmd.Flags().Bool("trace", false, "Enable detailed trace logging to ~/.specular/logs (default: profile-based)")
+	autoCmd.Flags().Bool("trace-otel", false, "Export each step as an OpenTelemetry span under the command span (requires SPECULAR_TELEMETRY)")
+	autoCmd.Flags().String("report-file", "", "Write a JSON exit report (status, exit code, cost, tasks, policy blocks, artifacts) to this path when the run ends")
+	autoCmd.Flags().String("metrics-push-url", defaultMetricsPushURL(), "Push the run's metrics to this Prometheus Pushgateway or 'specular metrics serve' URL when the run ends (env: SPECULAR_METRICS_PUSH_URL)")
+	autoCmd.Flags().String("event-stream", "", "Write lifecycle events as newline-delimited JSON to this path (\"-\" for stdout; progress output then goes to stderr)")
+
+	// Goal template flags
+	autoCmd.Flags().String("goal-template", "", "Expand a named goal template from .specular/goal-templates.yaml or ~/.specular/goal-templates.yaml")
+	autoCmd.Flags().StringArray("var", []string{}, "Goal template variable as key=value (can be used multiple times)")
 
 	// Scope filtering flags
 	autoCmd.Flags().StringSliceP("scope", "s", []string{}, "Filter execution scope (can be used multiple times)")
@@ -764,19 +1160,22 @@ func init() {
 	rootCmd.AddCommand(autoCmd)
 }
 
-// generateAttestation creates and saves a cryptographic attestation
-func generateAttestation(result *auto.Result, config *auto.Config, outputDir string) error {
-	// Get user identity (use hostname as fallback)
-	identity := os.Getenv("USER")
-	if identity == "" {
-		hostname, _ := os.Hostname()
-		identity = hostname
+// defaultCheckpointStore returns the checkpoint location from
+// SPECULAR_CHECKPOINT_STORE, or the local checkpoint directory
+func defaultCheckpointStore() string {
+	if store := os.Getenv("SPECULAR_CHECKPOINT_STORE"); store != "" {
+		return store
 	}
+	return auto.DefaultCheckpointStore
+}
 
-	// Create signer
-	signer, err := attestation.NewEphemeralSigner(identity)
+// generateAttestation creates and saves a cryptographic attestation. With
+// useKeyless it is signed with a Fulcio certificate for the ambient OIDC
+// identity, or with an ephemeral key when no token is available.
+func generateAttestation(ctx context.Context, result *auto.Result, config *auto.Config, outputDir string, useKeyless bool) (string, error) {
+	signer, signingMode, err := newAttestationSigner(ctx, useKeyless)
 	if err != nil {
-		return fmt.Errorf("failed to create signer: %w", err)
+		return "", err
 	}
 
 	// Create generator (use actual version from build)
@@ -788,28 +1187,25 @@ func generateAttestation(result *auto.Result, config *auto.Config, outputDir str
 
 	if result.AutoOutput != nil {
 		// Get output JSON
-		outputJSON, err = result.AutoOutput.ToJSON()
+		outputJSON, err = attestation.OutputJSON(result.AutoOutput)
 		if err != nil {
-			return fmt.Errorf("failed to serialize output: %w", err)
+			return "", fmt.Errorf("failed to serialize output: %w", err)
 		}
 
 		// Get plan JSON (if available from Result.Plan)
 		if result.Plan != nil {
-			planJSON, _ = json.Marshal(result.Plan)
+			planJSON, _ = attestation.PlanJSON(result.Plan)
 		}
 	}
 
 	// Generate attestation
 	att, err := generator.Generate(result, config, planJSON, outputJSON)
 	if err != nil {
-		return fmt.Errorf("failed to generate attestation: %w", err)
+		return "", fmt.Errorf("failed to generate attestation: %w", err)
 	}
 
-	// Determine workflow ID
-	workflowID := "unknown"
-	if result.AutoOutput != nil {
-		workflowID = result.AutoOutput.Audit.CheckpointID
-	}
+	// Name the attestation after the run
+	workflowID := att.WorkflowID
 
 	// Determine output path
 	var attestPath string
@@ -826,19 +1222,86 @@ func generateAttestation(result *auto.Result, config *auto.Config, outputDir str
 	// Save attestation
 	attestJSON, err := att.ToJSON()
 	if err != nil {
-		return fmt.Errorf("failed to serialize attestation: %w", err)
+		return "", fmt.Errorf("failed to serialize attestation: %w", err)
 	}
 
 	if err := os.WriteFile(attestPath, attestJSON, 0600); err != nil {
-		return fmt.Errorf("failed to write attestation: %w", err)
+		return "", fmt.Errorf("failed to write attestation: %w", err)
+	}
+
+	// Keep the attested output next to plan.json so the pair can be verified
+	if outputDir != "" && outputJSON != nil {
+		if err := os.WriteFile(filepath.Join(outputDir, attestation.OutputFileName), outputJSON, 0600); err != nil {
+			return "", fmt.Errorf("failed to write output: %w", err)
+		}
 	}
 
 	fmt.Printf("🔐 Generated attestation: %s\n", attestPath)
 	fmt.Printf("   Signed by: %s\n", att.SignedBy)
+	fmt.Printf("   Signing mode: %s\n", signingMode)
 	fmt.Printf("   Plan hash: %s\n", att.PlanHash[:16]+"...")
 	fmt.Printf("   Output hash: %s\n", att.OutputHash[:16]+"...")
 
-	return nil
+	return attestPath, nil
+}
+
+// newAttestationSigner returns the attestation signer and a description of
+// its signing mode. Keyless signing falls back to an ephemeral key, with a
+// warning, when there is no OIDC token.
+func newAttestationSigner(ctx context.Context, useKeyless bool) (attestation.Signer, string, error) {
+	if useKeyless {
+		signer, err := attestation.NewKeylessSigner(ctx, keyless.Options{})
+		switch {
+		case err == nil:
+			return signer, "keyless (Fulcio certificate)", nil
+		case errors.Is(err, keyless.ErrNoToken):
+			fmt.Fprintf(os.Stderr, "⚠️  Keyless signing unavailable: %v\n", err)
+			fmt.Fprintln(os.Stderr, "   Falling back to an ephemeral key")
+		default:
+			return nil, "", fmt.Errorf("keyless signing failed: %w", err)
+		}
+	}
+
+	// Get user identity (use hostname as fallback)
+	identity := os.Getenv("USER")
+	if identity == "" {
+		hostname, _ := os.Hostname()
+		identity = hostname
+	}
+
+	signer, err := attestation.NewEphemeralSigner(identity)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create signer: %w", err)
+	}
+	return signer, "ephemeral key (identity not certified)", nil
+}
+
+// autoOutputFiles are the files an auto run saves to --output; output.json
+// is saved with an attestation
+var autoOutputFiles = []string{"spec.yaml", "spec.lock.json", "plan.json", "action-plan.json", attestation.OutputFileName}
+
+// writeAutoExitReport writes the exit report for an auto run. The exit code
+// matches the one the process exits with for runErr.
+func writeAutoExitReport(ctx context.Context, path, goal string, result *auto.Result, runErr error, outputDir string, extraArtifacts []string) error {
+	code := exitcode.DetermineExitCode(runErr)
+	if runErr != nil && errors.Is(ctx.Err(), context.Canceled) {
+		code = exitcode.Interrupted
+	}
+
+	report := auto.NewExitReport(goal, result, runErr, code)
+	if outputDir != "" {
+		for _, name := range autoOutputFiles {
+			outputPath := filepath.Join(outputDir, name)
+			if _, err := os.Stat(outputPath); err == nil {
+				report.AddArtifact(outputPath)
+			}
+		}
+	}
+	for _, artifact := range extraArtifacts {
+		report.AddArtifact(artifact)
+	}
+
+	return report.Write(path)
 }
 
 func recordAutoMetrics(result *auto.Result, execErr error) {
@@ -890,6 +1353,9 @@ func listAvailableProfiles() error {
 		if name != "default" && name != "ci" && name != "strict" {
 			source = "custom"
 		}
+		if profile.Extends != "" {
+			source += ", extends " + profile.Extends
+		}
 
 		fmt.Printf("  %s (%s)\n", name, source)
 		fmt.Printf("     %s\n", profile.Description)
@@ -907,6 +1373,8 @@ func listAvailableProfiles() error {
 	fmt.Println("Create custom profiles in:")
 	fmt.Println("  - Project: ./auto.profiles.yaml")
 	fmt.Println("  - User:    ~/.specular/auto.profiles.yaml")
+	fmt.Println()
+	fmt.Println("Add \"extends: <name>\" to a custom profile to inherit from another profile.")
 
 	return nil
 }
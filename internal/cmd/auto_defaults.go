@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// autoDefaultsFile sets default values for 'specular auto' flags, keyed
+	// by long flag name. It is read from ~/.specular and then .specular, so
+	// project values win over global ones.
+	autoDefaultsFile = "auto.defaults.yaml"
+
+	// autoDefaultAnnotation marks flags whose default came from an
+	// auto.defaults.yaml file; the value is the file's path
+	autoDefaultAnnotation = "specular_auto_default"
+)
+
+// perRunAutoFlags choose what a single run does, so they cannot be given
+// defaults
+var perRunAutoFlags = map[string]bool{
+	"resume":        true,
+	"replan":        true,
+	"list-profiles": true,
+	"goal-template": true,
+	"var":           true,
+	"help":          true,
+}
+
+// autoDefaultsPaths returns the defaults files in the order they apply
+func autoDefaultsPaths() []string {
+	var paths []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".specular", autoDefaultsFile))
+	}
+	return append(paths, filepath.Join(".specular", autoDefaultsFile))
+}
+
+// applyAutoDefaults makes the values in the defaults files the defaults of
+// cmd's flags. Missing files are skipped. Flags given on the command line
+// keep their values, and cmd.Flags().Changed still reports only those.
+func applyAutoDefaults(cmd *cobra.Command, paths []string) error {
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //#nosec G304 -- Fixed config file locations
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := setAutoFlagDefault(cmd, path, name, values[name]); err != nil {
+				return fmt.Errorf("invalid %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setAutoFlagDefault sets the value and default of a flag that was not
+// given on the command line. Lists set slice flags such as --scope.
+func setAutoFlagDefault(cmd *cobra.Command, path, name string, value interface{}) error {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return fmt.Errorf("unknown flag %q", name)
+	}
+	if perRunAutoFlags[name] {
+		return fmt.Errorf("--%s cannot be given a default", name)
+	}
+	if value == nil {
+		return fmt.Errorf("--%s has no value", name)
+	}
+	if flag.Changed {
+		return nil // The command line wins
+	}
+
+	var err error
+	if list, ok := value.([]interface{}); ok {
+		slice, isSlice := flag.Value.(interface{ Replace([]string) error })
+		if !isSlice {
+			return fmt.Errorf("--%s takes a single value, not a list", name)
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		err = slice.Replace(items)
+	} else {
+		err = flag.Value.Set(fmt.Sprint(value))
+	}
+	if err != nil {
+		return fmt.Errorf("--%s: %w", name, err)
+	}
+
+	flag.DefValue = flag.Value.String()
+	return cmd.Flags().SetAnnotation(name, autoDefaultAnnotation, []string{path})
+}
+
+// setByAutoDefaults reports whether a flag's value came from an
+// auto.defaults.yaml file rather than the command line
+func setByAutoDefaults(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flags().Lookup(name)
+	return flag != nil && !flag.Changed && len(flag.Annotations[autoDefaultAnnotation]) > 0
+}
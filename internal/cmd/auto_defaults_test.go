@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newAutoDefaultsTestCmd returns a command with a few of the auto flags
+func newAutoDefaultsTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "auto"}
+	cmd.Flags().StringP("profile", "p", "default", "")
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().StringP("output", "o", "", "")
+	cmd.Flags().StringSlice("scope", []string{}, "")
+	cmd.Flags().Int("max-steps", 0, "")
+	cmd.Flags().String("resume", "", "")
+	return cmd
+}
+
+func writeAutoDefaults(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), autoDefaultsFile)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyAutoDefaults(t *testing.T) {
+	global := writeAutoDefaults(t, "profile: strict\njson: true\nmax-steps: 5\n")
+	project := writeAutoDefaults(t, "profile: ci\noutput: build/\nscope: [api, web]\n")
+
+	cmd := newAutoDefaultsTestCmd()
+	if err := cmd.ParseFlags([]string{"--max-steps", "8"}); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), autoDefaultsFile)
+	if err := applyAutoDefaults(cmd, []string{global, project, missing}); err != nil {
+		t.Fatalf("applyAutoDefaults() error = %v", err)
+	}
+
+	profile, _ := cmd.Flags().GetString("profile")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	output, _ := cmd.Flags().GetString("output")
+	scope, _ := cmd.Flags().GetStringSlice("scope")
+	maxSteps, _ := cmd.Flags().GetInt("max-steps")
+	if profile != "ci" || !jsonOutput || output != "build/" || !reflect.DeepEqual(scope, []string{"api", "web"}) {
+		t.Errorf("defaults = profile %q, json %v, output %q, scope %v", profile, jsonOutput, output, scope)
+	}
+	if maxSteps != 8 {
+		t.Errorf("max-steps = %d, want the CLI value 8", maxSteps)
+	}
+
+	// Only the CLI flag counts as changed
+	for _, name := range []string{"profile", "json", "output", "scope"} {
+		if cmd.Flags().Changed(name) || !setByAutoDefaults(cmd, name) {
+			t.Errorf("--%s: Changed = %v, setByAutoDefaults = %v, want a file default", name, cmd.Flags().Changed(name), setByAutoDefaults(cmd, name))
+		}
+	}
+	if !cmd.Flags().Changed("max-steps") || setByAutoDefaults(cmd, "max-steps") {
+		t.Error("--max-steps should come from the command line")
+	}
+	if setByAutoDefaults(cmd, "resume") {
+		t.Error("--resume was not in a defaults file")
+	}
+
+	// Help shows the configured defaults
+	if got := cmd.Flags().Lookup("profile").DefValue; got != "ci" {
+		t.Errorf("profile DefValue = %q, want ci", got)
+	}
+
+	profileFlags := autoProfileFlags(cmd, cmd.Flags().Changed)
+	if profileFlags.MaxSteps == nil || *profileFlags.MaxSteps != 8 {
+		t.Errorf("CLI profile flags = %+v, want max steps 8", profileFlags)
+	}
+}
+
+func TestApplyAutoDefaultsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown flag", content: "proflie: ci\n", wantErr: `unknown flag "proflie"`},
+		{name: "per-run flag", content: "resume: auto-1\n", wantErr: "--resume cannot be given a default"},
+		{name: "empty value", content: "output:\n", wantErr: "--output has no value"},
+		{name: "list for a single value", content: "output: [a, b]\n", wantErr: "--output takes a single value"},
+		{name: "invalid value", content: "max-steps: many\n", wantErr: "--max-steps"},
+		{name: "not a mapping", content: "- profile\n", wantErr: "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeAutoDefaults(t, tt.content)
+			err := applyAutoDefaults(newAutoDefaultsTestCmd(), []string{path})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyAutoDefaults() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), path) {
+				t.Errorf("error %q does not name the file", err)
+			}
+		})
+	}
+}
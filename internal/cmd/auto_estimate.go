@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/profiles"
+	"github.com/felixgeelhaar/specular/internal/router"
+)
+
+var autoEstimateCmd = &cobra.Command{
+	Use:   "estimate <goal>",
+	Short: "Project the cost of an auto run without executing it",
+	Long: `Estimate what an auto run would cost before starting it.
+
+The goal is parsed and a plan is generated (the cheap steps), then each
+workflow step is priced at the cost of the model the router would select.
+Nothing is executed. The estimate honors --profile and --scope, so the task
+count reflects what would actually run.
+
+Exits with code 3 when the projected cost exceeds the budget (--max-cost or
+the profile's limit).
+
+Examples:
+  specular auto estimate "Build a REST API for user management"
+  specular auto estimate --profile ci --max-cost 2 "Add authentication"
+  specular auto estimate --scope feature:feat-1 "Execute only feature 1"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profileName, _ := cmd.Flags().GetString("profile")
+		maxCost, _ := cmd.Flags().GetFloat64("max-cost")
+		scopePatterns, _ := cmd.Flags().GetStringSlice("scope")
+		includeDependencies, _ := cmd.Flags().GetBool("include-dependencies")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		goal := strings.Join(args, " ")
+
+		cliFlags := &profiles.CLIFlags{}
+		if cmd.Flags().Changed("max-cost") {
+			cliFlags.MaxCostUSD = &maxCost
+		}
+		setup, err := setupAuto(autoSetupOptions{
+			profileName: profileName,
+			cliFlags:    cliFlags,
+			router: router.RouterConfig{
+				MaxLatencyMs: 60000,
+				PreferCheap:  true, // Match the model selection of auto mode
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		orchestrator := auto.NewOrchestrator(setup.router, auto.Config{
+			Goal:                goal,
+			Profile:             setup.profileName,
+			MaxCostUSD:          setup.effective.Safety.MaxCostUSD,
+			ScopePatterns:       scopePatterns,
+			IncludeDependencies: includeDependencies,
+		})
+
+		estimate, err := orchestrator.Estimate(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("estimate failed: %w", err)
+		}
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(estimate, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON output: %w", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			printCostEstimate(os.Stdout, estimate)
+		}
+
+		if estimate.ExceedsBudget() {
+			return fmt.Errorf("budget exceeded: projected cost $%.4f exceeds limit $%.2f", estimate.TotalUSD, estimate.BudgetUSD)
+		}
+		return nil
+	},
+}
+
+// printCostEstimate writes a per-step and total cost projection
+func printCostEstimate(w io.Writer, e *auto.CostEstimate) {
+	fmt.Fprintf(w, "💰 Cost estimate: %s\n\n", e.Goal)
+	fmt.Fprintf(w, "   Model:    %s ($%.2f/MTok)\n", e.Model, e.CostPerMToken)
+	fmt.Fprintf(w, "   Features: %d\n", e.Features)
+	fmt.Fprintf(w, "   Effort:   %s (%s)\n", e.Effort, e.Effort.TShirtSize())
+	fmt.Fprintf(w, "   Tasks:    %d\n\n", e.Tasks)
+
+	for _, step := range e.Steps {
+		fmt.Fprintf(w, "   %-7s %-12s $%.4f\n", step.ID, step.Type, step.CostUSD)
+	}
+	fmt.Fprintf(w, "   %-20s $%.4f\n\n", "Total", e.TotalUSD)
+
+	switch {
+	case e.BudgetUSD <= 0:
+		fmt.Fprintln(w, "   Budget: unlimited")
+	case e.ExceedsBudget():
+		fmt.Fprintf(w, "   ❌ Over budget by $%.4f (limit: $%.2f)\n", -e.HeadroomUSD(), e.BudgetUSD)
+	default:
+		fmt.Fprintf(w, "   ✅ Headroom: $%.4f of $%.2f budget\n", e.HeadroomUSD(), e.BudgetUSD)
+	}
+}
+
+func init() {
+	autoEstimateCmd.Flags().StringP("profile", "p", "default", "Profile to use (default, ci, strict, or custom)")
+	autoEstimateCmd.Flags().Float64("max-cost", 0, "Maximum cost in USD for entire workflow (0 = use profile default)")
+	autoEstimateCmd.Flags().StringSliceP("scope", "s", []string{}, "Filter execution scope (can be used multiple times)")
+	autoEstimateCmd.Flags().Bool("include-dependencies", true, "Include dependencies of scoped tasks (default: true)")
+	autoEstimateCmd.Flags().Bool("json", false, "Output the estimate in JSON format")
+
+	autoCmd.AddCommand(autoEstimateCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
+)
+
+var exportPatchCmd = &cobra.Command{
+	Use:   "export-patch <session-id>",
+	Short: "Export the changes of an auto run as one git patch",
+	Long: `Merge the patches saved for each step of an auto run into a single unified
+diff that 'git apply' accepts. Each changed file appears once, with its change
+from before the run to after it. Added, deleted, renamed, and binary files are
+included.
+
+Patches are saved when auto mode runs with --save-patches.
+
+Examples:
+  # Write the changes of a run to a file
+  specular auto export-patch auto-1762811730 -o changes.patch
+
+  # Review and apply them in another checkout
+  git apply --check changes.patch && git apply changes.patch`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workflowID := args[0]
+		output, _ := cmd.Flags().GetString("output")
+
+		homeDir, _ := os.UserHomeDir()
+		patchDir := filepath.Join(homeDir, ".specular", "patches")
+
+		writer := patch.NewWriter(patchDir)
+		combined, err := writer.Combine(workflowID)
+		if err != nil {
+			return fmt.Errorf("failed to combine patches: %w", err)
+		}
+
+		if output == "" || output == "-" {
+			fmt.Print(combined)
+			return nil
+		}
+
+		if err := os.WriteFile(output, []byte(combined), 0600); err != nil {
+			return fmt.Errorf("failed to write patch: %w", err)
+		}
+		fmt.Printf("✅ Exported changes of %s to %s\n", workflowID, output)
+		fmt.Printf("Apply with: git apply %s\n", output)
+		return nil
+	},
+}
+
+func init() {
+	exportPatchCmd.Flags().StringP("output", "o", "", "Write the patch to this file instead of stdout")
+
+	autoCmd.AddCommand(exportPatchCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/checkpoint"
+)
+
+var autoPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old auto session checkpoints",
+	Long: `Delete auto session checkpoints that are older than a retention period or
+beyond a maximum count.
+
+Completed sessions are deleted after --older-than. Failed and unfinished
+sessions can still be retried or resumed, so they are kept until
+--failed-older-than, even beyond --keep. Otherwise only the --keep most
+recently updated sessions are kept. Use --dry-run to see what would be deleted.
+
+Examples:
+  specular auto prune
+  specular auto prune --older-than 30d --keep 50
+  specular auto prune --older-than 7d --failed-older-than 30d --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		failedOlderThan, _ := cmd.Flags().GetString("failed-older-than")
+		keep, _ := cmd.Flags().GetInt("keep")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		checkpointStore, _ := cmd.Flags().GetString("checkpoint-store")
+
+		policy := checkpoint.PrunePolicy{Keep: keep, DryRun: dryRun}
+		var err error
+		if policy.OlderThan, err = parseValidityDuration(olderThan); err != nil {
+			return fmt.Errorf("invalid --older-than %q: %w", olderThan, err)
+		}
+		if policy.FailedOlderThan, err = parseValidityDuration(failedOlderThan); err != nil {
+			return fmt.Errorf("invalid --failed-older-than %q: %w", failedOlderThan, err)
+		}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+
+		// Default to the checkpoints listed by 'auto history'
+		if checkpointStore == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			checkpointStore = filepath.Join(homeDir, ".specular", "checkpoints")
+		}
+
+		result, err := checkpoint.NewManager(checkpointStore, false, 0).Prune(policy)
+		if err != nil {
+			return fmt.Errorf("failed to prune checkpoints: %w", err)
+		}
+
+		verb := "Deleted"
+		if dryRun {
+			verb = "Would delete"
+		}
+		for _, sessionID := range result.Deleted {
+			fmt.Printf("  🗑  %s\n", sessionID)
+		}
+
+		failedIDs := make([]string, 0, len(result.Errors))
+		for sessionID := range result.Errors {
+			failedIDs = append(failedIDs, sessionID)
+		}
+		sort.Strings(failedIDs)
+		for _, sessionID := range failedIDs {
+			fmt.Printf("  ⚠️  %s: %v\n", sessionID, result.Errors[sessionID])
+		}
+
+		fmt.Printf("%s %d checkpoint(s), kept %d\n", verb, len(result.Deleted), len(result.Kept))
+		return nil
+	},
+}
+
+func init() {
+	autoPruneCmd.Flags().String("older-than", "30d", "Delete completed sessions last updated before this age (e.g., 30d, 72h; 0 = no age limit)")
+	autoPruneCmd.Flags().String("failed-older-than", "90d", "Delete failed and unfinished sessions last updated before this age (0 = no age limit)")
+	autoPruneCmd.Flags().Int("keep", 50, "Keep at most this many of the most recent sessions (0 = no count limit)")
+	autoPruneCmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting")
+	autoPruneCmd.Flags().String("checkpoint-store", "", "Checkpoint directory or s3://bucket/prefix URL (default: ~/.specular/checkpoints)")
+
+	autoCmd.AddCommand(autoPruneCmd)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/autopolicy"
+	"github.com/felixgeelhaar/specular/internal/checkpoint"
+	"github.com/felixgeelhaar/specular/internal/profiles"
+	"github.com/felixgeelhaar/specular/internal/router"
+)
+
+var autoRetryFailedCmd = &cobra.Command{
+	Use:   "retry-failed <session-id>",
+	Short: "Re-run only the failed tasks of an auto session",
+	Long: `Re-run the tasks that failed in an auto session, keeping completed work.
+
+The session's checkpoint is loaded, its failed tasks are reset to pending,
+and only those tasks are executed again with a fresh retry budget. The run
+uses the session's original profile and what is left of its cost limit
+after the spend of its earlier runs, unless --profile, --max-cost, or
+SPECULAR_MAX_COST is given, and updates the same checkpoint in place.
+
+Tasks that never ran (for example, because a dependency failed) stay
+pending; continue them with 'specular auto --resume <session-id>'.
+
+Examples:
+  specular auto retry-failed auto-1762811730
+  specular auto retry-failed auto-1762811730 --max-cost 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessionID := args[0]
+		profileName, _ := cmd.Flags().GetString("profile")
+		maxCost, _ := cmd.Flags().GetFloat64("max-cost")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		checkpointStore, _ := cmd.Flags().GetString("checkpoint-store")
+
+		mgr := checkpoint.NewManager(checkpointStore, false, 0)
+		if !mgr.Exists(sessionID) {
+			return fmt.Errorf("session not found: %s", sessionID)
+		}
+		state, err := mgr.Load(sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+
+		// Default to the session's original profile and to what is left of
+		// its budget
+		if !cmd.Flags().Changed("profile") {
+			profileName = "default"
+			if original, ok := state.GetMetadata("profile"); ok && original != "" {
+				profileName = original
+			}
+		}
+		cliFlags := &profiles.CLIFlags{}
+		if !cmd.Flags().Changed("max-cost") && os.Getenv(profiles.EnvMaxCost) == "" {
+			if original, ok := state.GetMetadata("max_cost_usd"); ok {
+				if parsed, err := strconv.ParseFloat(original, 64); err == nil && parsed > 0 {
+					maxCost = max(parsed-auto.CheckpointSpentUSD(state), 0)
+					cliFlags.MaxCostUSD = &maxCost
+				}
+			}
+		} else if maxCost > 0 {
+			cliFlags.MaxCostUSD = &maxCost
+		}
+
+		setup, err := setupAuto(autoSetupOptions{
+			profileName: profileName,
+			cliFlags:    cliFlags,
+			router: router.RouterConfig{
+				MaxLatencyMs: 60000,
+				PreferCheap:  true, // Match the model selection of auto mode
+			},
+		})
+		if err != nil {
+			return err
+		}
+		effectiveProfile := setup.effective
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Using profile: %s, budget: $%.2f\n", profileName, effectiveProfile.Safety.MaxCostUSD)
+		}
+
+		goal, _ := state.GetMetadata("goal")
+		orchestrator := auto.NewOrchestrator(setup.router, auto.Config{
+			Goal:             goal,
+			Profile:          profileName,
+			RequireApproval:  effectiveProfile.Approvals.Interactive && effectiveProfile.Approvals.Mode != profiles.ApprovalModeNone,
+			PerStepApproval:  effectiveProfile.Approvals.Mode == profiles.ApprovalModePerStep,
+			MaxCostUSD:       effectiveProfile.Safety.MaxCostUSD,
+			MaxCostPerTask:   effectiveProfile.Safety.MaxCostPerTask,
+			MaxRetries:       effectiveProfile.Safety.MaxRetries,
+			MaxParallelTasks: effectiveProfile.Execution.MaxParallelTasks,
+			FailFast:         effectiveProfile.Execution.FailFast,
+			TimeoutMinutes:   int(effectiveProfile.Safety.Timeout.Minutes()),
+			Verbose:          verbose,
+			ResumeFrom:       sessionID,
+			RetryFailed:      true,
+			CheckpointStore:  checkpointStore,
+		})
+		orchestrator.SetPolicyChecker(newPolicyCheckerAdapter(autopolicy.NewCheckerFromProfile(effectiveProfile)))
+
+		result, err := orchestrator.Execute(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("retrying failed tasks: %w", err)
+		}
+
+		fmt.Println()
+		fmt.Printf("✅ Retry completed in %s\n", result.Duration)
+		fmt.Printf("   Total cost: $%.4f\n", result.TotalCost)
+		if result.TasksFailed > 0 {
+			fmt.Printf("   Tasks still failing: %d\n", result.TasksFailed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	autoRetryFailedCmd.Flags().StringP("profile", "p", "", "Profile to use (default: the session's original profile)")
+	autoRetryFailedCmd.Flags().Float64("max-cost", 0, "Maximum cost in USD for the retry (default: the session's remaining budget)")
+	autoRetryFailedCmd.Flags().String("checkpoint-store", defaultCheckpointStore(), "Checkpoint directory or s3://bucket/prefix URL (env: SPECULAR_CHECKPOINT_STORE)")
+
+	autoCmd.AddCommand(autoRetryFailedCmd)
+}
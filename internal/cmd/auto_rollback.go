@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -11,26 +13,34 @@ import (
 )
 
 var rollbackCmd = &cobra.Command{
-	Use:   "rollback [workflow-id] [step-id]",
+	Use:   "rollback <session-id> [step-id]",
 	Short: "Rollback changes made by auto mode",
 	Long: `Rollback changes made by specular auto mode using saved patches.
 
+Without a step, every step of the session is reverted, newest first, which
+restores the files to their state before the run. Patches are saved when auto
+mode runs with --save-patches.
+
+Before anything is reverted, the files are compared with the state the run
+left them in. If any changed since, the changed files are listed and the
+rollback only continues after confirmation, or with --force.
+
 Examples:
-  # List patches for a workflow
+  # List patches for a session
   specular auto rollback auto-1762811730 --list
 
-  # Rollback a single step
-  specular auto rollback auto-1762811730 step-2
+  # Preview reverting the whole session
+  specular auto rollback auto-1762811730 --dry-run
 
-  # Rollback to a specific step (reverts all steps after it)
-  specular auto rollback auto-1762811730 --to step-2
+  # Revert the whole session
+  specular auto rollback auto-1762811730
 
-  # Rollback all steps in a workflow
-  specular auto rollback auto-1762811730 --all
+  # Roll back to a specific step (reverts all steps after it)
+  specular auto rollback auto-1762811730 --step step-2
 
-  # Verify rollback safety before applying
-  specular auto rollback auto-1762811730 step-2 --dry-run`,
-	Args: cobra.MinimumNArgs(1),
+  # Rollback a single step
+  specular auto rollback auto-1762811730 step-2`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		workflowID := args[0]
 		stepID := ""
@@ -40,9 +50,17 @@ Examples:
 
 		// Parse flags
 		listPatches, _ := cmd.Flags().GetBool("list")
-		rollbackAll, _ := cmd.Flags().GetBool("all")
-		rollbackTo, _ := cmd.Flags().GetString("to")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		rollbackTo, _ := cmd.Flags().GetString("step")
+		if to, _ := cmd.Flags().GetString("to"); to != "" && rollbackTo == "" {
+			rollbackTo = to
+		}
+		opts := rollbackOptions{}
+		opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.force, _ = cmd.Flags().GetBool("force")
+
+		if stepID != "" && rollbackTo != "" {
+			return fmt.Errorf("invalid arguments: give a step-id or --step, not both")
+		}
 
 		// Get working directory and patch directory
 		workingDir, err := os.Getwd()
@@ -60,197 +78,211 @@ Examples:
 			return listPatchesForWorkflow(workflowID, patchDir)
 		}
 
-		// Handle --all
-		if rollbackAll {
-			return rollbackAllSteps(rollback, workflowID, dryRun)
+		// Handle single step rollback
+		if stepID != "" {
+			p, err := patch.NewWriter(patchDir).ReadPatch(workflowID, stepID)
+			if err != nil {
+				return fmt.Errorf("patch not found for step %s: %w", stepID, err)
+			}
+			fmt.Printf("🔄 Rolling back step %s of %s\n", stepID, workflowID)
+			return runRollback(os.Stdout, os.Stdin, rollback, workflowID, []*patch.Patch{p}, opts)
 		}
 
-		// Handle --to
-		if rollbackTo != "" {
-			return rollbackToStep(rollback, workflowID, rollbackTo, dryRun)
+		// Handle --step, or the whole session
+		steps, err := rollback.StepsToRollback(workflowID, rollbackTo)
+		if err != nil {
+			return err
 		}
-
-		// Handle single step rollback
-		if stepID == "" {
-			return fmt.Errorf("step-id required (or use --list, --all, or --to)")
+		if len(steps) == 0 {
+			if rollbackTo != "" {
+				fmt.Printf("Nothing to roll back: %s is the last step with a patch\n", rollbackTo)
+				return nil
+			}
+			return fmt.Errorf("no patches found for session %s (patches are saved with --save-patches)", workflowID)
 		}
 
-		return rollbackSingleStep(rollback, workflowID, stepID, dryRun)
+		if rollbackTo != "" {
+			fmt.Printf("🔄 Rolling back %s to step %s\n", workflowID, rollbackTo)
+			fmt.Println("   (This will revert all steps after this one)")
+		} else {
+			fmt.Printf("🔄 Rolling back all steps of %s\n", workflowID)
+			fmt.Println("   ⚠️  This will revert all changes made by this session")
+			opts.confirm = true
+		}
+		return runRollback(os.Stdout, os.Stdin, rollback, workflowID, steps, opts)
 	},
 }
 
 func init() {
-	rollbackCmd.Flags().Bool("list", false, "List available patches for the workflow")
-	rollbackCmd.Flags().Bool("all", false, "Rollback all steps in the workflow")
+	rollbackCmd.Flags().Bool("list", false, "List available patches for the session")
+	rollbackCmd.Flags().Bool("all", false, "Rollback all steps in the session (the default without a step)")
+	rollbackCmd.Flags().String("step", "", "Rollback to a specific step (reverts all steps after it)")
 	rollbackCmd.Flags().String("to", "", "Rollback to a specific step (reverts all steps after it)")
-	rollbackCmd.Flags().Bool("dry-run", false, "Verify rollback safety without applying changes")
+	rollbackCmd.Flags().Bool("dry-run", false, "Show the files that would be reverted without changing them")
+	rollbackCmd.Flags().Bool("force", false, "Skip confirmation, even if files changed since the run")
+	_ = rollbackCmd.Flags().MarkDeprecated("to", "use --step instead")
 
 	autoCmd.AddCommand(rollbackCmd)
 }
 
-// listPatchesForWorkflow lists all patches for a workflow
-func listPatchesForWorkflow(workflowID, patchDir string) error {
-	writer := patch.NewWriter(patchDir)
-	patches, err := writer.ListPatches(workflowID)
-	if err != nil {
-		return fmt.Errorf("failed to list patches: %w", err)
-	}
+// rollbackOptions controls how a rollback is confirmed and applied
+type rollbackOptions struct {
+	dryRun  bool // Only show what would be reverted
+	force   bool // Skip confirmation prompts
+	confirm bool // Ask before reverting, even without conflicts
+}
 
-	if len(patches) == 0 {
-		fmt.Printf("No patches found for workflow %s\n", workflowID)
-		fmt.Printf("Patches are saved when using --save-patches flag\n")
-		return nil
-	}
+// runRollback reverts the patches, newest first. Files that changed since
+// the run are listed and need confirmation; without an answer the rollback
+// is refused.
+func runRollback(w io.Writer, in io.Reader, rollback *patch.Rollback, workflowID string, steps []*patch.Patch, opts rollbackOptions) error {
+	actions := revertActions(steps)
 
-	fmt.Printf("📋 Patches for workflow %s:\n\n", workflowID)
-	for _, p := range patches {
-		fmt.Printf("  %s (%s)\n", p.StepID, p.StepType)
-		fmt.Printf("    %s\n", p.Description)
-		fmt.Printf("    Files: %d, Changes: +%d -%d\n", p.FilesChanged, p.Insertions, p.Deletions)
-		fmt.Printf("    Created: %s\n\n", p.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "\nSteps to revert (newest first):\n")
+	for _, step := range steps {
+		fmt.Fprintf(w, "   %s (%s): %d file(s)\n", step.StepID, step.StepType, len(step.Files))
 	}
 
-	fmt.Printf("Total: %d patches\n", len(patches))
-	fmt.Println("\nUse 'specular auto rollback <workflow-id> <step-id>' to rollback a specific step")
-	return nil
-}
-
-// rollbackSingleStep rolls back a single step
-func rollbackSingleStep(rollback *patch.Rollback, workflowID, stepID string, dryRun bool) error {
-	fmt.Printf("🔄 Rolling back step: %s\n", stepID)
-
-	// Verify safety
-	safe, warnings, err := rollback.VerifyRollbackSafety(workflowID, stepID)
+	warnings, err := rollback.CheckDivergence(steps)
 	if err != nil {
 		return fmt.Errorf("failed to verify rollback safety: %w", err)
 	}
-
 	if len(warnings) > 0 {
-		fmt.Println("\n⚠️  Warnings:")
+		fmt.Fprintln(w, "\n⚠️  Files changed since the run:")
 		for _, warning := range warnings {
-			fmt.Printf("   - %s\n", warning)
+			fmt.Fprintf(w, "   - %s\n", warning)
 		}
-		fmt.Println()
 	}
 
-	if !safe {
-		fmt.Println("⚠️  Rollback may not be safe due to conflicts")
-		fmt.Println("Use --dry-run to see details without applying changes")
-		if !dryRun {
-			fmt.Print("\nContinue anyway? [y/N]: ")
-			var response string
-			if _, err := fmt.Scanln(&response); err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-				return err
-			}
-			if response != "y" && response != "Y" {
-				fmt.Println("Rollback cancelled")
-				return nil
-			}
+	if opts.dryRun {
+		fmt.Fprintln(w, "\nWould revert:")
+		for _, action := range actions {
+			fmt.Fprintf(w, "   %s\n", action.String())
 		}
-	}
-
-	if dryRun {
-		fmt.Println("✅ Dry-run complete. Use without --dry-run to apply rollback")
+		if len(warnings) > 0 {
+			fmt.Fprintln(w, "\n⚠️  Rollback would overwrite the changes above; it needs confirmation or --force")
+		}
+		fmt.Fprintln(w, "\n✅ Dry-run complete. Use without --dry-run to apply rollback")
 		return nil
 	}
 
-	// Apply rollback
-	if err := rollback.RollbackStep(workflowID, stepID); err != nil {
-		return fmt.Errorf("rollback failed: %w", err)
-	}
-
-	fmt.Printf("✅ Successfully rolled back step: %s\n", stepID)
-	return nil
-}
-
-// rollbackToStep rolls back all steps after the target step
-func rollbackToStep(rollback *patch.Rollback, workflowID, targetStepID string, dryRun bool) error {
-	fmt.Printf("🔄 Rolling back to step: %s\n", targetStepID)
-	fmt.Println("   (This will revert all steps after this one)")
-
-	if dryRun {
-		fmt.Println("\n✅ Dry-run mode: no changes will be applied")
+	if !opts.force && (len(warnings) > 0 || opts.confirm) {
+		question := "\nAre you sure? [y/N]: "
+		if len(warnings) > 0 {
+			question = "\nReverting will overwrite these changes. Continue anyway? [y/N]: "
+		}
+		fmt.Fprint(w, question)
+		var response string
+		if _, err := fmt.Fscanln(in, &response); errors.Is(err, io.EOF) {
+			if len(warnings) > 0 {
+				return fmt.Errorf("rollback refused: %d file(s) changed since the run (use --force to revert anyway)", len(warnings))
+			}
+			return fmt.Errorf("rollback not confirmed (use --force to skip confirmation)")
+		}
+		if response != "y" && response != "Y" {
+			fmt.Fprintln(w, "Rollback cancelled")
+			return nil
+		}
 	}
 
-	result, err := rollback.RollbackToStep(workflowID, targetStepID)
-	if err != nil {
-		return fmt.Errorf("rollback failed: %w", err)
-	}
+	result := rollback.RollbackSteps(workflowID, steps)
 
 	// Print results
-	fmt.Printf("\n📊 Rollback Summary:\n")
-	fmt.Printf("   Steps reverted: %d\n", result.StepsReverted)
+	fmt.Fprintf(w, "\n📊 Rollback Summary:\n")
+	fmt.Fprintf(w, "   Steps reverted: %d\n", result.StepsReverted)
+	if len(result.FilesReverted) > 0 {
+		fmt.Fprintf(w, "   Files reverted: %d\n", len(result.FilesReverted))
+		reverted := make(map[string]bool, len(result.FilesReverted))
+		for _, path := range result.FilesReverted {
+			reverted[path] = true
+		}
+		for _, action := range actions {
+			if reverted[action.path] {
+				fmt.Fprintf(w, "     %s\n", action.String())
+			}
+		}
+	}
 
 	if len(result.Errors) > 0 {
-		fmt.Println("\n❌ Errors:")
+		fmt.Fprintln(w, "\n❌ Errors:")
 		for _, errMsg := range result.Errors {
-			fmt.Printf("   - %s\n", errMsg)
+			fmt.Fprintf(w, "   - %s\n", errMsg)
 		}
+		return fmt.Errorf("rollback failed for %d step(s)", len(result.Errors))
 	}
 
-	if len(result.Conflicts) > 0 {
-		fmt.Println("\n⚠️  Conflicts:")
-		for _, conflict := range result.Conflicts {
-			fmt.Printf("   - %s\n", conflict)
-		}
-	}
+	fmt.Fprintln(w, "\n✅ Rollback completed successfully")
+	return nil
+}
 
-	if result.Success {
-		fmt.Println("\n✅ Rollback completed successfully")
-	} else {
-		fmt.Println("\n⚠️  Rollback completed with errors")
-	}
+// revertAction is what a rollback does to one file
+type revertAction struct {
+	path    string
+	action  string
+	oldPath string // Where a renamed file is moved back to
+}
 
-	return nil
+func (a revertAction) String() string {
+	if a.oldPath != "" {
+		return fmt.Sprintf("%-9s %s → %s", a.action, a.path, a.oldPath)
+	}
+	return fmt.Sprintf("%-9s %s", a.action, a.path)
 }
 
-// rollbackAllSteps rolls back all steps in the workflow
-func rollbackAllSteps(rollback *patch.Rollback, workflowID string, dryRun bool) error {
-	fmt.Printf("🔄 Rolling back all steps for workflow: %s\n", workflowID)
-	fmt.Println("   ⚠️  This will revert all changes made by this workflow")
+// revertActions describes what reverting the patches, newest first, does to
+// each file. The oldest patch of a file decides its final state.
+func revertActions(steps []*patch.Patch) []revertAction {
+	var actions []revertAction
+	index := make(map[string]int)
+	for _, step := range steps {
+		for _, filePatch := range step.Files {
+			action := revertAction{path: filePatch.Path}
+			switch filePatch.Status {
+			case patch.FileStatusAdded:
+				action.action = "delete"
+			case patch.FileStatusModified:
+				action.action = "restore"
+			case patch.FileStatusDeleted:
+				action.action = "recreate"
+			case patch.FileStatusRenamed:
+				action.action = "rename"
+				action.oldPath = filePatch.OldPath
+			}
 
-	if !dryRun {
-		fmt.Print("\nAre you sure? [y/N]: ")
-		var response string
-		if _, err := fmt.Scanln(&response); err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-			return err
-		}
-		if response != "y" && response != "Y" {
-			fmt.Println("Rollback cancelled")
-			return nil
+			if i, ok := index[filePatch.Path]; ok {
+				actions[i] = action
+				continue
+			}
+			index[filePatch.Path] = len(actions)
+			actions = append(actions, action)
 		}
 	}
+	return actions
+}
 
-	result, err := rollback.RollbackAll(workflowID)
+// listPatchesForWorkflow lists all patches for a workflow
+func listPatchesForWorkflow(workflowID, patchDir string) error {
+	writer := patch.NewWriter(patchDir)
+	patches, err := writer.ListPatches(workflowID)
 	if err != nil {
-		return fmt.Errorf("rollback failed: %w", err)
-	}
-
-	// Print results
-	fmt.Printf("\n📊 Rollback Summary:\n")
-	fmt.Printf("   Steps reverted: %d\n", result.StepsReverted)
-
-	if len(result.Errors) > 0 {
-		fmt.Println("\n❌ Errors:")
-		for _, errMsg := range result.Errors {
-			fmt.Printf("   - %s\n", errMsg)
-		}
+		return fmt.Errorf("failed to list patches: %w", err)
 	}
 
-	if len(result.Conflicts) > 0 {
-		fmt.Println("\n⚠️  Conflicts:")
-		for _, conflict := range result.Conflicts {
-			fmt.Printf("   - %s\n", conflict)
-		}
+	if len(patches) == 0 {
+		fmt.Printf("No patches found for workflow %s\n", workflowID)
+		fmt.Printf("Patches are saved when using --save-patches flag\n")
+		return nil
 	}
 
-	if result.Success {
-		fmt.Println("\n✅ Rollback completed successfully")
-	} else {
-		fmt.Println("\n⚠️  Rollback completed with errors")
+	fmt.Printf("📋 Patches for workflow %s:\n\n", workflowID)
+	for _, p := range patches {
+		fmt.Printf("  %s (%s)\n", p.StepID, p.StepType)
+		fmt.Printf("    %s\n", p.Description)
+		fmt.Printf("    Files: %d, Changes: +%d -%d\n", p.FilesChanged, p.Insertions, p.Deletions)
+		fmt.Printf("    Created: %s\n\n", p.Timestamp.Format("2006-01-02 15:04:05"))
 	}
 
+	fmt.Printf("Total: %d patches\n", len(patches))
+	fmt.Println("\nUse 'specular auto rollback <workflow-id> <step-id>' to rollback a specific step")
 	return nil
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/patch"
+)
+
+// writeRollbackSession saves two step patches: step-1 adds new.txt and
+// modifies main.go, step-2 modifies new.txt
+func writeRollbackSession(t *testing.T) (workingDir string, rollback *patch.Rollback) {
+	t.Helper()
+	workingDir, patchDir := t.TempDir(), t.TempDir()
+	writer := patch.NewWriter(patchDir)
+
+	start := time.Now()
+	for _, p := range []*patch.Patch{
+		{StepID: "step-1", StepType: "spec:update", WorkflowID: "auto-1", Timestamp: start, Files: []patch.FilePatch{
+			{Path: "main.go", Status: patch.FileStatusModified, OldContent: "v0\n", NewContent: "v1\n"},
+			{Path: "new.txt", Status: patch.FileStatusAdded, NewContent: "a\n"},
+		}},
+		{StepID: "step-2", StepType: "build:run", WorkflowID: "auto-1", Timestamp: start.Add(time.Second), Files: []patch.FilePatch{
+			{Path: "new.txt", Status: patch.FileStatusModified, OldContent: "a\n", NewContent: "b\n"},
+		}},
+	} {
+		if _, err := writer.WritePatch(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{"main.go": "v1\n", "new.txt": "b\n"} {
+		if err := os.WriteFile(filepath.Join(workingDir, path), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return workingDir, patch.NewRollback(workingDir, patchDir)
+}
+
+func TestRunRollback(t *testing.T) {
+	workingDir, rollback := writeRollbackSession(t)
+	steps, err := rollback.StepsToRollback("auto-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run lists the files without touching them
+	var out bytes.Buffer
+	if err := runRollback(&out, strings.NewReader(""), rollback, "auto-1", steps, rollbackOptions{dryRun: true}); err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+	for _, want := range []string{"step-2 (build:run)", "restore   main.go", "delete    new.txt"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected dry run output to contain %q:\n%s", want, out.String())
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(workingDir, "main.go")); string(data) != "v1\n" {
+		t.Errorf("dry run changed main.go to %q", data)
+	}
+
+	out.Reset()
+	if err := runRollback(&out, strings.NewReader("y\n"), rollback, "auto-1", steps, rollbackOptions{confirm: true}); err != nil {
+		t.Fatalf("runRollback() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Files reverted: 2") {
+		t.Errorf("expected the reverted files in the summary:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(workingDir, "main.go")); string(data) != "v0\n" {
+		t.Errorf("main.go = %q, want the content before the run", data)
+	}
+	if _, err := os.Stat(filepath.Join(workingDir, "new.txt")); !os.IsNotExist(err) {
+		t.Error("expected new.txt to be deleted")
+	}
+}
+
+func TestRunRollbackDiverged(t *testing.T) {
+	workingDir, rollback := writeRollbackSession(t)
+	edited := filepath.Join(workingDir, "new.txt")
+	if err := os.WriteFile(edited, []byte("edited by hand\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	steps, err := rollback.StepsToRollback("auto-1", "step-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an answer the rollback is refused
+	var out bytes.Buffer
+	err = runRollback(&out, strings.NewReader(""), rollback, "auto-1", steps, rollbackOptions{})
+	if err == nil || !strings.Contains(err.Error(), "rollback refused") {
+		t.Fatalf("expected the rollback to be refused, got %v", err)
+	}
+	if !strings.Contains(out.String(), "new.txt has been modified since patch was created") {
+		t.Errorf("expected the changed file to be listed:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(edited); string(data) != "edited by hand\n" {
+		t.Errorf("refused rollback changed new.txt to %q", data)
+	}
+
+	// --force reverts anyway
+	out.Reset()
+	if err := runRollback(&out, strings.NewReader(""), rollback, "auto-1", steps, rollbackOptions{force: true}); err != nil {
+		t.Fatalf("forced rollback error = %v", err)
+	}
+	if data, _ := os.ReadFile(edited); string(data) != "a\n" {
+		t.Errorf("new.txt = %q, want the content after step-1", data)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"github.com/felixgeelhaar/specular/internal/profiles"
+	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/ux"
+)
+
+// autoSetupOptions selects the profile and router of an auto command
+type autoSetupOptions struct {
+	profileName  string             // Profile to load ("" = default)
+	cliFlags     *profiles.CLIFlags // Explicit flags, applied over the environment
+	defaultFlags *profiles.CLIFlags // Values from auto.defaults.yaml, applied below the environment (optional)
+
+	// The router's configuration. Its budget is the effective profile's max
+	// cost.
+	router router.RouterConfig
+}
+
+// autoSetup is what an auto command runs with
+type autoSetup struct {
+	profileName  string
+	profile      *profiles.Profile // Profile as loaded
+	effective    *profiles.Profile // Profile with defaults, environment, and flags applied
+	envOverrides *profiles.EnvOverrides
+	registry     *provider.Registry
+	router       *router.Router
+}
+
+// setupAuto loads the profile, layers the defaults file, environment, and
+// CLI flags over it, and creates the router with the provider registry and
+// the project's routing policy
+func setupAuto(opts autoSetupOptions) (*autoSetup, error) {
+	profileName := opts.profileName
+	if profileName == "" {
+		profileName = "default"
+	}
+	profile, err := profiles.NewLoader().Load(profileName)
+	if err != nil {
+		return nil, ProfileLoadError(profileName, err)
+	}
+
+	envOverrides, err := profiles.LoadEnvOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	cliFlags, defaultFlags := opts.cliFlags, opts.defaultFlags
+	if cliFlags == nil {
+		cliFlags = &profiles.CLIFlags{}
+	}
+	if defaultFlags == nil {
+		defaultFlags = &profiles.CLIFlags{}
+	}
+	effective := profiles.MergeWithCLIFlags(
+		profiles.MergeWithEnv(profiles.MergeWithCLIFlags(profile, defaultFlags), envOverrides),
+		cliFlags,
+	)
+
+	// Try providers.yaml first, fall back to auto-discovery
+	providerConfigPath := ".specular/providers.yaml"
+	registry, err := provider.LoadRegistryWithAutoDiscovery(providerConfigPath)
+	if err != nil {
+		return nil, ProviderLoadError(providerConfigPath, err)
+	}
+
+	routerConfig := opts.router
+	routerConfig.BudgetUSD = effective.Safety.MaxCostUSD
+	r, err := router.NewRouterWithProviders(&routerConfig, registry)
+	if err != nil {
+		return nil, RouterError(err)
+	}
+
+	// Constrain routing to the models and tools the project policy allows
+	if err := applyRoutingPolicy(r, ux.NewPathDefaults().PolicyFile()); err != nil {
+		return nil, err
+	}
+
+	return &autoSetup{
+		profileName:  profileName,
+		profile:      profile,
+		effective:    effective,
+		envOverrides: envOverrides,
+		registry:     registry,
+		router:       r,
+	}, nil
+}
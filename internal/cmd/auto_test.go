@@ -1,17 +1,34 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/attestation"
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/autopolicy"
+	"github.com/felixgeelhaar/specular/internal/exitcode"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/spec"
 )
 
 // TestAutoSubcommands tests that all auto subcommands are registered
 func TestAutoSubcommands(t *testing.T) {
 	subcommands := map[string]bool{
-		"resume":  false,
-		"history": false,
-		"explain": false,
+		"resume":   false,
+		"history":  false,
+		"explain":  false,
+		"estimate": false,
 	}
 
 	for _, cmd := range autoCmd.Commands() {
@@ -118,3 +135,305 @@ func TestAutoBackwardCompatibilityFlags(t *testing.T) {
 		t.Error("backward compatibility flag 'verbose' not found on auto command")
 	}
 }
+
+// TestAutoReplanRequiresResume tests that --replan is rejected without --resume
+func TestAutoReplanRequiresResume(t *testing.T) {
+	if err := autoCmd.Flags().Set("replan", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = autoCmd.Flags().Set("replan", "false") })
+
+	err := autoCmd.Args(autoCmd, []string{"Add health checks"})
+	if err == nil || !strings.Contains(err.Error(), "--replan requires --resume") {
+		t.Errorf("Args() error = %v, want --replan requires --resume", err)
+	}
+}
+
+// TestWriteAutoExitReport tests that the exit report records the process exit
+// code and the files saved to the output directory
+func TestWriteAutoExitReport(t *testing.T) {
+	outputDir := t.TempDir()
+	for _, name := range []string{"spec.yaml", "plan.json"} {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		result     *auto.Result
+		runErr     error
+		wantStatus string
+		wantCode   int
+	}{
+		{
+			name:       "success",
+			result:     &auto.Result{Success: true, TasksExecuted: 3, TotalCost: 0.2},
+			wantStatus: "completed",
+			wantCode:   0,
+		},
+		{
+			name:       "budget exceeded",
+			result:     &auto.Result{TasksExecuted: 1, TasksFailed: 1},
+			runErr:     errors.New("auto mode failed: execution failed: budget exceeded"),
+			wantStatus: "failed",
+			wantCode:   3,
+		},
+		{
+			name:       "failed before workflow",
+			runErr:     errors.New("Failed to create router"),
+			wantStatus: "failed",
+			wantCode:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.json")
+			err := writeAutoExitReport(context.Background(), path, "add health checks", tt.result, tt.runErr, outputDir, []string{"run.attestation.json"})
+			if err != nil {
+				t.Fatalf("writeAutoExitReport() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("report not written: %v", err)
+			}
+			var report auto.ExitReport
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatalf("invalid report JSON: %v", err)
+			}
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if report.ExitCode != tt.wantCode {
+				t.Errorf("ExitCode = %d, want %d", report.ExitCode, tt.wantCode)
+			}
+			if tt.result != nil && (report.TasksExecuted != tt.result.TasksExecuted || report.TasksFailed != tt.result.TasksFailed || report.TotalCost != tt.result.TotalCost) {
+				t.Errorf("report counts = %+v, want result %+v", report, tt.result)
+			}
+
+			wantArtifacts := []string{
+				filepath.Join(outputDir, "spec.yaml"),
+				filepath.Join(outputDir, "plan.json"),
+				"run.attestation.json",
+			}
+			if !reflect.DeepEqual(report.Artifacts, wantArtifacts) {
+				t.Errorf("Artifacts = %v, want %v", report.Artifacts, wantArtifacts)
+			}
+		})
+	}
+}
+
+// TestWriteAutoExitReportInterrupted tests that a cancelled run reports the
+// interrupted exit code
+func TestWriteAutoExitReportInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := writeAutoExitReport(ctx, path, "goal", nil, context.Canceled, "", nil); err != nil {
+		t.Fatalf("writeAutoExitReport() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var report auto.ExitReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report JSON: %v", err)
+	}
+	if report.ExitCode != exitcode.Interrupted {
+		t.Errorf("ExitCode = %d, want %d", report.ExitCode, exitcode.Interrupted)
+	}
+}
+
+func TestApplyRoutingPolicy(t *testing.T) {
+	newRouter := func() *router.Router {
+		r, err := router.NewRouter(&router.RouterConfig{BudgetUSD: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.SetModelsAvailable(true)
+		return r
+	}
+	req := router.RoutingRequest{ModelHint: "agentic"}
+
+	// A missing policy file leaves routing unconstrained
+	r := newRouter()
+	if err := applyRoutingPolicy(r, filepath.Join(t.TempDir(), "policy.yaml")); err != nil {
+		t.Fatalf("applyRoutingPolicy() error = %v", err)
+	}
+	if _, err := r.SelectModel(context.Background(), req); err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	content := `routing:
+  allow_models:
+    - provider: openai
+      names: ["gpt-4o-mini"]
+`
+	if err := os.WriteFile(policyFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r = newRouter()
+	if err := applyRoutingPolicy(r, policyFile); err != nil {
+		t.Fatalf("applyRoutingPolicy() error = %v", err)
+	}
+	result, err := r.SelectModel(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "gpt-4o-mini" {
+		t.Errorf("selected %s, want the only allowed model gpt-4o-mini", result.Model.ID)
+	}
+
+	// A policy that fails to load is an error, not an unconstrained run
+	if err := os.WriteFile(policyFile, []byte("routing: [not a map"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyRoutingPolicy(newRouter(), policyFile); err == nil {
+		t.Error("applyRoutingPolicy() with an invalid policy succeeded, want an error")
+	}
+}
+
+// TestPrintCostEstimate tests the per-step breakdown and budget headroom
+func TestPrintCostEstimate(t *testing.T) {
+	estimate := &auto.CostEstimate{
+		Goal:          "Build a todo API",
+		Model:         "claude-haiku-3.5",
+		CostPerMToken: 1,
+		Steps: []auto.StepEstimate{
+			{ID: "step-1", Type: "spec:update", CostUSD: 0.5},
+			{ID: "step-3", Type: "plan:gen", CostUSD: 0.25},
+		},
+		TotalUSD:  0.75,
+		BudgetUSD: 1,
+	}
+
+	var buf bytes.Buffer
+	printCostEstimate(&buf, estimate)
+	out := buf.String()
+	for _, want := range []string{"step-1", "$0.5000", "plan:gen", "$0.7500", "Headroom: $0.2500"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	estimate.BudgetUSD = 0.5
+	buf.Reset()
+	printCostEstimate(&buf, estimate)
+	if !strings.Contains(buf.String(), "Over budget by $0.2500") {
+		t.Errorf("output missing over-budget line:\n%s", buf.String())
+	}
+}
+
+// TestPolicyCheckerAdapterContext tests that autopolicy checkers see the
+// orchestrator's cumulative cost
+func TestPolicyCheckerAdapterContext(t *testing.T) {
+	adapter := newPolicyCheckerAdapter(autopolicy.NewCostCircuitBreaker(1.0, 0.8))
+	step := &auto.ActionStep{ID: "step-4", Type: auto.StepTypeBuildRun}
+
+	policyCtx := auto.NewPolicyContext(step, nil, 3)
+	policyCtx.TotalCostSoFar = 1.25
+	ctx := context.WithValue(context.Background(), "policy_context", policyCtx)
+
+	result, err := adapter.CheckStep(ctx, step)
+	if err != nil {
+		t.Fatalf("CheckStep: %v", err)
+	}
+	if result.Allowed {
+		t.Fatal("expected step to be denied, got allowed")
+	}
+	if got := exitcode.DetermineExitCode(errors.New(result.Reason)); got != exitcode.PolicyViolation {
+		t.Errorf("exit code = %d, want %d for %q", got, exitcode.PolicyViolation, result.Reason)
+	}
+}
+
+// validatorScript is a validator plugin that rejects every spec with one
+// warning and no error
+const validatorScript = `#!/bin/sh
+read -r line
+case "$line" in
+  *'"health"'*) echo '{"success":true,"result":{"status":"healthy","name":"probe","version":"1.0.0","protocol_version":1,"supported_actions":["validate"]}}' ;;
+  *) echo '{"success":true,"result":{"valid":false,"messages":[{"severity":"warning","message":"feature feat-001 has no description","rule":"require_description"}]}}' ;;
+esac
+`
+
+func TestSpecValidatorAdapter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts require a POSIX shell")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	pluginDir := filepath.Join(home, ".specular", "plugins", "probe")
+	if err := os.MkdirAll(pluginDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "name: probe\nversion: 1.0.0\ntype: validator\nentrypoint: probe.sh\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "probe.sh"), []byte(validatorScript), 0700); err != nil { //nolint:gosec // Test plugin must be executable
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, _, err := loadSpecValidators(ctx, []string{"missing"}); err == nil {
+		t.Error("expected error for unknown validator plugin")
+	}
+
+	validators, closePlugins, err := loadSpecValidators(ctx, []string{"probe"})
+	if err != nil {
+		t.Fatalf("loadSpecValidators: %v", err)
+	}
+	defer closePlugins()
+
+	issues, err := validators[0].ValidateSpec(ctx, &spec.ProductSpec{Product: "test"})
+	if err != nil {
+		t.Fatalf("ValidateSpec: %v", err)
+	}
+
+	// The rejection without an error-severity message becomes an error
+	want := []auto.ValidationIssue{
+		{Severity: auto.SeverityWarning, Message: "feature feat-001 has no description", Rule: "require_description"},
+		{Severity: auto.SeverityError, Message: "spec rejected by validator"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("issues = %+v, want %+v", issues, want)
+	}
+}
+
+func TestStartTUIAdapterWithoutTerminal(t *testing.T) {
+	t.Setenv("CI", "true")
+
+	var out bytes.Buffer
+	if adapter := startTUIAdapter(&out, "Build an API", "default"); adapter != nil {
+		adapter.Stop()
+		t.Fatal("expected no TUI without an interactive terminal")
+	}
+	if !strings.Contains(out.String(), "falling back to text mode") {
+		t.Errorf("expected a fallback notice, got %q", out.String())
+	}
+}
+
+func TestNewAttestationSignerFallback(t *testing.T) {
+	t.Setenv("SIGSTORE_ID_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+
+	for _, useKeyless := range []bool{false, true} {
+		signer, mode, err := newAttestationSigner(context.Background(), useKeyless)
+		if err != nil {
+			t.Fatalf("newAttestationSigner(%v) error = %v", useKeyless, err)
+		}
+		if _, ok := signer.(*attestation.EphemeralSigner); !ok {
+			t.Errorf("newAttestationSigner(%v) = %T, want an ephemeral signer without a token", useKeyless, signer)
+		}
+		if !strings.HasPrefix(mode, "ephemeral key") {
+			t.Errorf("signing mode = %q, want ephemeral key", mode)
+		}
+	}
+}
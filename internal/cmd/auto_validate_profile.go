@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/profiles"
+)
+
+var autoValidateProfileCmd = &cobra.Command{
+	Use:   "validate-profile <name>",
+	Short: "Check a profile for errors without running auto",
+	Long: `Validate a profile the way auto would load it and report every problem.
+
+The profile is resolved from ./auto.profiles.yaml, ~/.specular/auto.profiles.yaml,
+and the built-in profiles, including any profile it extends. Every field is
+checked (budgets, timeouts, approval modes, step limits, step types), and
+problems are reported with the file and line of the setting where possible.
+Settings that are valid but unlikely to work together, such as the TUI with
+non-interactive approvals, are reported as warnings.
+
+Exits non-zero when the profile has errors, so it can run in CI as a
+config lint.
+
+Examples:
+  specular auto validate-profile payments
+  specular auto validate-profile ci --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		report := profiles.NewLoader().Lint(args[0])
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON output: %w", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			printLintReport(os.Stdout, report)
+		}
+
+		if !report.Valid {
+			return fmt.Errorf("profile %q has %d error(s)", report.Profile, report.Count(profiles.FindingError))
+		}
+		return nil
+	},
+}
+
+// printLintReport writes the findings of a profile lint
+func printLintReport(w io.Writer, report *profiles.LintReport) {
+	title := fmt.Sprintf("🔍 Profile %q", report.Profile)
+	if report.Extends != "" {
+		title += fmt.Sprintf(" (extends %s)", report.Extends)
+	}
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w)
+
+	for _, finding := range report.Findings {
+		icon := "❌"
+		if finding.Severity == profiles.FindingWarning {
+			icon = "⚠️ "
+		}
+		if location := finding.Location(); location != "" {
+			fmt.Fprintf(w, "   %s %s: %s\n", icon, location, finding.Message)
+		} else {
+			fmt.Fprintf(w, "   %s %s\n", icon, finding.Message)
+		}
+	}
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	errors, warnings := report.Count(profiles.FindingError), report.Count(profiles.FindingWarning)
+	if report.Valid {
+		fmt.Fprintf(w, "✅ Profile is valid (%d warning(s))\n", warnings)
+	} else {
+		fmt.Fprintf(w, "❌ %d error(s), %d warning(s)\n", errors, warnings)
+	}
+}
+
+func init() {
+	autoValidateProfileCmd.Flags().Bool("json", false, "Output the report in JSON format")
+
+	autoCmd.AddCommand(autoValidateProfileCmd)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/felixgeelhaar/specular/internal/attestation"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 )
 
 var verifyCmd = &cobra.Command{
@@ -25,8 +26,8 @@ Examples:
   # Verify with strict options
   specular auto verify attestation.json --max-age 24h --require-clean-git
 
-  # Verify with allowed identities
-  specular auto verify attestation.json --allowed-identity user@example.com`,
+  # Require a keyless signature from a CA you trust, for an allowed identity
+  specular auto verify attestation.json --trusted-root fulcio-root.pem --allowed-identity user@example.com`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		attestationPath := args[0]
@@ -38,6 +39,7 @@ Examples:
 		verifyHashes, _ := cmd.Flags().GetBool("verify-hashes")
 		planPath, _ := cmd.Flags().GetString("plan")
 		outputPath, _ := cmd.Flags().GetString("output")
+		trustedRoot, _ := cmd.Flags().GetString("trusted-root")
 
 		fmt.Printf("🔍 Verifying attestation: %s\n\n", attestationPath)
 
@@ -62,6 +64,11 @@ Examples:
 		fmt.Printf("   Duration:    %s\n", att.Duration)
 		fmt.Printf("   Signed by:   %s\n", att.SignedBy)
 		fmt.Printf("   Signed at:   %s\n", att.SignedAt.Format(time.RFC3339))
+		if att.Certificate != "" {
+			fmt.Println("   Signing:     keyless (Fulcio certificate)")
+		} else {
+			fmt.Println("   Signing:     ephemeral key (identity not certified)")
+		}
 		fmt.Println()
 
 		fmt.Println("🖥️  Provenance:")
@@ -91,6 +98,13 @@ Examples:
 		if len(allowedIdentities) > 0 {
 			opts = append(opts, attestation.WithAllowedIdentities(allowedIdentities))
 		}
+		if trustedRoot != "" {
+			roots, err := keyless.LoadRoots(trustedRoot)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, attestation.WithTrustedRoots(roots))
+		}
 
 		verifier := attestation.NewStandardVerifier(opts...)
 
@@ -149,10 +163,11 @@ Examples:
 func init() {
 	verifyCmd.Flags().Duration("max-age", 0, "Maximum age for attestation (e.g., 24h, 7d)")
 	verifyCmd.Flags().Bool("require-clean-git", false, "Require clean git status in provenance")
-	verifyCmd.Flags().StringSlice("allowed-identity", []string{}, "Allowed signer identities (can be used multiple times)")
+	verifyCmd.Flags().StringSlice("allowed-identity", []string{}, "Allowed certified signer identities; requires --trusted-root (can be used multiple times)")
 	verifyCmd.Flags().Bool("verify-hashes", false, "Verify plan and output hashes")
 	verifyCmd.Flags().String("plan", "", "Path to plan JSON file for hash verification")
 	verifyCmd.Flags().String("output", "", "Path to output JSON file for hash verification")
+	verifyCmd.Flags().String("trusted-root", "", "PEM file of CA certificates the keyless signing certificate must chain to")
 
 	autoCmd.AddCommand(verifyCmd)
 }
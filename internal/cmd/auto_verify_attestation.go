@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/attestation"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
+)
+
+// Exit codes for auto verify-attestation
+const (
+	attestExitFailure   = 60
+	attestExitSignature = 70
+	attestExitIdentity  = 71
+	attestExitHash      = 72
+)
+
+// errNoTrustAnchor is returned without --trusted-root. Any key can produce
+// a valid signature and the signer name is self-asserted, so only a
+// certificate chained to a trusted root identifies the signer.
+var errNoTrustAnchor = fmt.Errorf("%w: signature valid, signer untrusted (pass --trusted-root)", attestation.ErrIdentityNotAllowed)
+
+var autoVerifyAttestationCmd = &cobra.Command{
+	Use:   "verify-attestation <attestation-file>",
+	Short: "Verify that a plan and output are exactly what was attested",
+	Long: `Verify an auto-mode attestation against the plan and output it covers.
+
+The signature and signer identity are checked, then the plan and output
+hashes are recomputed and compared with the signed ones. The plan and
+output are read from an output directory (--dir, default: the directory of
+the attestation file), where an attested run with --output saves plan.json
+and output.json next to the attestation. --plan and --output select the
+files directly.
+
+An ephemeral key can produce a valid signature for any content, and the
+signer name in an attestation is self-asserted, so --trusted-root is
+required to trust the signer: the attestation must carry a keyless
+signing certificate that chains to those CA certificates.
+--allowed-identity then restricts the certified identity. Without
+--trusted-root, a valid attestation still exits with 71.
+
+Exit codes:
+  0  - Attestation verified
+  60 - Verification failure (unreadable attestation, missing plan or output, ...)
+  70 - Signature mismatch
+  71 - Signer not in --allowed-identity, or no --trusted-root given
+  72 - Plan or output hash mismatch
+
+Examples:
+  # Verify a run saved with --output ./run --attest --keyless
+  specular auto verify-attestation ./run/auto-1762811730.attestation.json \
+    --trusted-root fulcio-root.pem --allowed-identity dev@example.com
+
+  # Verify an attestation from ~/.specular/attestations against a saved run
+  specular auto verify-attestation ~/.specular/attestations/auto-1762811730.attestation.json --dir ./run \
+    --trusted-root fulcio-root.pem --allowed-identity dev@example.com
+
+  # Verify explicit files and require a keyless signature from CI
+  specular auto verify-attestation attestation.json --plan plan.json --output output.json \
+    --trusted-root fulcio-root.pem --allowed-identity ci@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAutoVerifyAttestation,
+}
+
+func runAutoVerifyAttestation(cmd *cobra.Command, args []string) error {
+	attestationPath := args[0]
+
+	dir, _ := cmd.Flags().GetString("dir")
+	planPath, _ := cmd.Flags().GetString("plan")
+	outputPath, _ := cmd.Flags().GetString("output")
+	allowedIdentities, _ := cmd.Flags().GetStringSlice("allowed-identity")
+	trustedRoot, _ := cmd.Flags().GetString("trusted-root")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
+
+	if dir == "" {
+		dir = filepath.Dir(attestationPath)
+	}
+	if planPath == "" {
+		planPath = filepath.Join(dir, attestation.PlanFileName)
+	}
+	if outputPath == "" {
+		outputPath = filepath.Join(dir, attestation.OutputFileName)
+	}
+
+	opts := []attestation.VerifierOption{attestation.WithMaxAge(maxAge)}
+	if len(allowedIdentities) > 0 {
+		opts = append(opts, attestation.WithAllowedIdentities(allowedIdentities))
+	}
+	if trustedRoot != "" {
+		roots, err := keyless.LoadRoots(trustedRoot)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, attestation.WithTrustedRoots(roots))
+	}
+
+	att, err := verifyAutoAttestation(attestationPath, planPath, outputPath, opts...)
+	if err == nil {
+		err = requireTrustAnchor(trustedRoot)
+	}
+	if err != nil {
+		code := attestationExitCode(err)
+		fmt.Printf("❌ Attestation verification failed: %v\n", err)
+		fmt.Printf("Exit code: %d\n", code)
+		os.Exit(code)
+	}
+
+	fmt.Println("✅ Attestation verified")
+	fmt.Println()
+	fmt.Printf("   Workflow ID: %s\n", att.WorkflowID)
+	fmt.Printf("   Goal:        %s\n", att.Goal)
+	fmt.Printf("   Signed by:   %s\n", att.SignedBy)
+	fmt.Printf("   Signed at:   %s\n", att.SignedAt.Format(time.RFC3339))
+	switch {
+	case att.Certificate != "" && trustedRoot != "":
+		fmt.Println("   Signing:     keyless (certificate chain verified)")
+	case att.Certificate != "":
+		fmt.Println("   Signing:     keyless (certificate chain not checked; use --trusted-root)")
+	default:
+		fmt.Println("   Signing:     ephemeral key (identity not certified)")
+	}
+	fmt.Printf("   Plan:        %s  sha256:%s\n", planPath, att.PlanHash)
+	fmt.Printf("   Output:      %s  sha256:%s\n", outputPath, att.OutputHash)
+
+	return nil
+}
+
+// verifyAutoAttestation loads an attestation and checks its signature and
+// signer, then that the plan and output files match the attested hashes
+func verifyAutoAttestation(attestationPath, planPath, outputPath string, opts ...attestation.VerifierOption) (*attestation.Attestation, error) {
+	data, err := os.ReadFile(attestationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation: %w", err)
+	}
+	att, err := attestation.FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attestation: %w", err)
+	}
+
+	verifier := attestation.NewStandardVerifier(opts...)
+	if err := verifier.Verify(att); err != nil {
+		return nil, err
+	}
+
+	planJSON, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	outputJSON, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output: %w", err)
+	}
+
+	if err := verifier.VerifyHashes(att, planJSON, outputJSON); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// requireTrustAnchor checks that the signer is certified by a trusted
+// certificate authority
+func requireTrustAnchor(trustedRoot string) error {
+	if trustedRoot == "" {
+		return errNoTrustAnchor
+	}
+	return nil
+}
+
+// attestationExitCode maps a verifyAutoAttestation error to an exit code
+func attestationExitCode(err error) int {
+	switch {
+	case errors.Is(err, attestation.ErrSignatureInvalid):
+		return attestExitSignature
+	case errors.Is(err, attestation.ErrIdentityNotAllowed):
+		return attestExitIdentity
+	case errors.Is(err, attestation.ErrHashMismatch):
+		return attestExitHash
+	default:
+		return attestExitFailure
+	}
+}
+
+func init() {
+	autoVerifyAttestationCmd.Flags().String("dir", "", "Output directory holding plan.json and output.json (default: the attestation's directory)")
+	autoVerifyAttestationCmd.Flags().String("plan", "", "Path to the plan JSON file (overrides --dir)")
+	autoVerifyAttestationCmd.Flags().String("output", "", "Path to the output JSON file (overrides --dir)")
+	autoVerifyAttestationCmd.Flags().StringSlice("allowed-identity", []string{}, "Allowed certified signer identities; requires --trusted-root (can be used multiple times)")
+	autoVerifyAttestationCmd.Flags().String("trusted-root", "", "PEM file of CA certificates the keyless signing certificate must chain to")
+	autoVerifyAttestationCmd.Flags().Duration("max-age", 0, "Maximum age for attestation (e.g., 24h); 0 for no limit")
+
+	autoCmd.AddCommand(autoVerifyAttestationCmd)
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/attestation"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless/keylesstest"
+	"github.com/felixgeelhaar/specular/internal/auto"
+	"github.com/felixgeelhaar/specular/internal/plan"
+)
+
+// writeAttestedRun saves an attested run to a directory the way auto
+// --output --attest does: plan.json indented, output.json, and the attestation
+func writeAttestedRun(t *testing.T) (dir, attestationPath string) {
+	t.Helper()
+	signer, err := attestation.NewEphemeralSigner("dev@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeAttestedRunWith(t, signer)
+}
+
+// writeAttestedRunWith saves a run attested by signer
+func writeAttestedRunWith(t *testing.T, signer attestation.Signer) (dir, attestationPath string) {
+	t.Helper()
+	dir = t.TempDir()
+
+	execPlan := &plan.Plan{Tasks: []plan.Task{{ID: "task-1", FeatureID: "feat-1", Skill: "go-backend", Priority: "P0"}}}
+	output := auto.NewAutoOutput("Build an API", "default")
+	output.Status = "completed"
+	output.Audit.CheckpointID = "auto-1"
+	output.Audit.StartedAt = time.Now().Add(-time.Minute)
+	output.Audit.CompletedAt = time.Now()
+
+	planJSON, err := attestation.PlanJSON(execPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputJSON, err := attestation.OutputJSON(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := &auto.Result{Duration: time.Minute, AutoOutput: output, Plan: execPlan}
+	att, err := attestation.NewGenerator(signer, "1.0.0").Generate(result, &auto.Config{}, planJSON, outputJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestJSON, err := att.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indentedPlan, err := json.MarshalIndent(execPlan, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestationPath = filepath.Join(dir, "auto-1.attestation.json")
+	for path, data := range map[string][]byte{
+		filepath.Join(dir, attestation.PlanFileName):   indentedPlan,
+		filepath.Join(dir, attestation.OutputFileName): outputJSON,
+		attestationPath: attestJSON,
+	} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, attestationPath
+}
+
+func TestVerifyAutoAttestation(t *testing.T) {
+	dir, attestationPath := writeAttestedRun(t)
+	planPath := filepath.Join(dir, attestation.PlanFileName)
+	outputPath := filepath.Join(dir, attestation.OutputFileName)
+
+	att, err := verifyAutoAttestation(attestationPath, planPath, outputPath)
+	if err != nil {
+		t.Fatalf("verifyAutoAttestation() error = %v", err)
+	}
+	if att.WorkflowID != "auto-1" {
+		t.Errorf("WorkflowID = %s, want auto-1", att.WorkflowID)
+	}
+
+	// The ephemeral key's SignedBy is self-asserted, so it cannot be allowed
+	_, err = verifyAutoAttestation(attestationPath, planPath, outputPath,
+		attestation.WithAllowedIdentities([]string{"dev@example.com"}))
+	if !errors.Is(err, attestation.ErrIdentityNotAllowed) {
+		t.Errorf("expected an uncertified signer to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyAutoAttestation_Keyless(t *testing.T) {
+	fulcio := keylesstest.NewFulcio(t)
+	signer, err := attestation.NewKeylessSigner(context.Background(), keyless.Options{
+		FulcioURL: fulcio.URL,
+		IDToken:   keylesstest.Token("dev@example.com"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, attestationPath := writeAttestedRunWith(t, signer)
+	planPath := filepath.Join(dir, attestation.PlanFileName)
+	outputPath := filepath.Join(dir, attestation.OutputFileName)
+
+	// A certified identity is allowed
+	_, err = verifyAutoAttestation(attestationPath, planPath, outputPath,
+		attestation.WithTrustedRoots(fulcio.Roots), attestation.WithAllowedIdentities([]string{"dev@example.com"}))
+	if err != nil {
+		t.Errorf("expected the certified signer to be allowed, got %v", err)
+	}
+
+	_, err = verifyAutoAttestation(attestationPath, planPath, outputPath,
+		attestation.WithTrustedRoots(fulcio.Roots), attestation.WithAllowedIdentities([]string{"ci@example.com"}))
+	if got := attestationExitCode(err); got != attestExitIdentity {
+		t.Errorf("attestationExitCode(%v) = %d, want %d", err, got, attestExitIdentity)
+	}
+}
+
+func TestVerifyAutoAttestationExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(t *testing.T, dir, attestationPath string)
+		opts   []attestation.VerifierOption
+		want   int
+	}{
+		{
+			name: "tampered attestation",
+			modify: func(t *testing.T, dir, attestationPath string) {
+				rewriteAttestation(t, attestationPath, func(att *attestation.Attestation) { att.Goal = "Something else" })
+			},
+			want: attestExitSignature,
+		},
+		{
+			name: "signer not allowed",
+			opts: []attestation.VerifierOption{attestation.WithAllowedIdentities([]string{"ci@example.com"})},
+			want: attestExitIdentity,
+		},
+		{
+			name: "plan changed",
+			modify: func(t *testing.T, dir, attestationPath string) {
+				writeFile(t, filepath.Join(dir, attestation.PlanFileName), `{"tasks":[]}`)
+			},
+			want: attestExitHash,
+		},
+		{
+			name: "output changed",
+			modify: func(t *testing.T, dir, attestationPath string) {
+				writeFile(t, filepath.Join(dir, attestation.OutputFileName), `{"goal":"Build an API","status":"failed"}`)
+			},
+			want: attestExitHash,
+		},
+		{
+			name: "output missing",
+			modify: func(t *testing.T, dir, attestationPath string) {
+				if err := os.Remove(filepath.Join(dir, attestation.OutputFileName)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: attestExitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, attestationPath := writeAttestedRun(t)
+			if tt.modify != nil {
+				tt.modify(t, dir, attestationPath)
+			}
+
+			_, err := verifyAutoAttestation(attestationPath,
+				filepath.Join(dir, attestation.PlanFileName), filepath.Join(dir, attestation.OutputFileName), tt.opts...)
+			if err == nil {
+				t.Fatal("expected verification to fail")
+			}
+			if got := attestationExitCode(err); got != tt.want {
+				t.Errorf("attestationExitCode(%v) = %d, want %d", err, got, tt.want)
+			}
+		})
+	}
+}
+
+// rewriteAttestation applies change to a saved attestation without re-signing
+func rewriteAttestation(t *testing.T, path string, change func(*attestation.Attestation)) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	att, err := attestation.FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change(att)
+	data, err = att.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, string(data))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequireTrustAnchor(t *testing.T) {
+	err := requireTrustAnchor("")
+	if err == nil {
+		t.Fatal("expected an error without --trusted-root")
+	}
+	if got := attestationExitCode(err); got != attestExitIdentity {
+		t.Errorf("attestationExitCode(%v) = %d, want %d", err, got, attestExitIdentity)
+	}
+
+	if err := requireTrustAnchor("fulcio-root.pem"); err != nil {
+		t.Errorf("requireTrustAnchor() with a trusted root error = %v", err)
+	}
+}
@@ -3,18 +3,23 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/felixgeelhaar/specular/internal/attestation/keyless"
 	"github.com/felixgeelhaar/specular/internal/bundle"
 	"github.com/felixgeelhaar/specular/internal/license"
+	"github.com/felixgeelhaar/specular/internal/progress"
 	"github.com/felixgeelhaar/specular/internal/ux"
+	"github.com/felixgeelhaar/specular/internal/version"
 )
 
 var bundleCmd = &cobra.Command{
@@ -28,17 +33,23 @@ routing configuration, and approvals into a portable, verifiable archive.`,
 
 // Bundle build command flags
 var (
-	buildOutput    string
-	buildSpec      string
-	buildLock      string
-	buildRouting   string
-	buildPolicies  []string
-	buildInclude   []string
-	buildApprovals []string
-	buildAttest    bool
-	buildAttestFmt string
-	buildMetadata  []string
-	buildGovLevel  string
+	buildOutput       string
+	buildSpec         string
+	buildLock         string
+	buildRouting      string
+	buildPolicies     []string
+	buildInclude      []string
+	buildExclude      []string
+	buildApprovals    []string
+	buildAttest       bool
+	buildAttestFmt    string
+	buildKeyless      bool
+	buildRekor        bool
+	buildRekorURL     string
+	buildMetadata     []string
+	buildGovLevel     string
+	buildAllowInvalid bool
+	buildBase         string
 )
 
 var bundleCreateCmd = &cobra.Command{
@@ -69,20 +80,39 @@ Examples:
   # Create with policies
   specular bundle create --policy policies/security.yaml --policy policies/compliance.yaml bundle.sbundle.tgz
 
+  # Policies are validated; package a draft policy anyway with a warning
+  specular bundle create --policy policies/draft.yaml --allow-invalid-policies bundle.sbundle.tgz
+
+  # Include a directory tree and a glob, skipping logs
+  specular bundle create --include docs/ --include "policies/*.yaml" --exclude "*.log" bundle.sbundle.tgz
+
   # Create with governance level
-  specular bundle create --governance-level L3 bundle.sbundle.tgz`,
+  specular bundle create --governance-level L3 bundle.sbundle.tgz
+
+  # Create a delta bundle containing only files changed since v1.0.0
+  specular bundle create --base my-app-v1.0.0.sbundle.tgz my-app-v1.1.0.sbundle.tgz
+
+  # Attest with a keyless signature (in CI with an OIDC token)
+  specular bundle create --attest --keyless bundle.sbundle.tgz
+
+  # Also record the signature in the Rekor transparency log
+  specular bundle create --attest --keyless --rekor bundle.sbundle.tgz`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runBundleCreate,
 }
 
 // Bundle gate command flags
 var (
-	gateStrict      bool
-	gateApprovals   bool
-	gateAttestation bool
-	gatePolicy      string
-	gateTrustedKeys []string
-	gateOffline     bool
+	gateStrict       bool
+	gateApprovals    bool
+	gateAttestation  bool
+	gatePolicy       string
+	gateTrustedKeys  []string
+	gateOffline      bool
+	gateExitCodes    string
+	gateManifestSig  bool
+	gateRekorURL     string
+	gateRequireRekor bool
 )
 
 var bundleGateCmd = &cobra.Command{
@@ -95,6 +125,7 @@ Gate checks:
 - File checksums (SHA-256)
 - Required approvals
 - Cryptographic attestation
+- Manifest signature (verified whenever present)
 - Policy compliance
 - Provider allowlist
 - Drift detection
@@ -107,6 +138,12 @@ Exit codes:
   50 - Forbidden provider
   60 - Evaluation failure
 
+All failures are reported before exiting. --exit-code-strategy chooses the
+exit code when several failure categories occur:
+  highest-severity - Most severe category (policy > drift > approval > provider)
+  first            - Category of the first failure reported
+  aggregate        - 60 if more than one category failed
+
 Examples:
   # Basic gate check
   specular bundle gate my-app-v1.0.0.sbundle.tgz
@@ -118,7 +155,17 @@ Examples:
   specular bundle gate --require-approvals bundle.sbundle.tgz
 
   # Verify attestation
-  specular bundle gate --verify-attestation bundle.sbundle.tgz`,
+  specular bundle gate --verify-attestation bundle.sbundle.tgz
+
+  # Verify attestation and require a Rekor transparency log entry
+  specular bundle gate --verify-attestation --require-rekor bundle.sbundle.tgz
+
+  # Require a manifest signature from a trusted key
+  specular bundle gate --require-manifest-signature \
+    --trusted-key SHA256:abc123... bundle.sbundle.tgz
+
+  # Exit 60 whenever more than one kind of check fails
+  specular bundle gate --exit-code-strategy aggregate bundle.sbundle.tgz`,
 	Args: cobra.ExactArgs(1),
 	RunE: runBundleGate,
 }
@@ -130,30 +177,37 @@ var (
 	applyForce     bool
 	applyYes       bool
 	applyExclude   []string
+	applyBase      string
+	applyBackup    bool
 )
 
 // Bundle push command flags
 var (
-	pushInsecure  bool
-	pushPlatform  string
-	pushUserAgent string
+	pushInsecure   bool
+	pushPlatform   string
+	pushUserAgent  string
+	pushNoProgress bool
 )
 
 // Bundle pull command flags
 var (
-	pullInsecure  bool
-	pullUserAgent string
-	pullOutput    string
+	pullInsecure   bool
+	pullUserAgent  string
+	pullOutput     string
+	pullNoProgress bool
 )
 
 // Bundle approve command flags
 var (
-	approveRole    string
-	approveUser    string
-	approveComment string
-	approveSigType string
-	approveKeyPath string
-	approveOutput  string
+	approveRole       string
+	approveUser       string
+	approveComment    string
+	approveSigType    string
+	approveKeyPath    string
+	approveCert       string
+	approveOutput     string
+	approveValidFor   string
+	approveValidUntil string
 )
 
 // Bundle approval-status command flags
@@ -170,9 +224,20 @@ var bundleApplyCmd = &cobra.Command{
 
 This command:
 1. Validates the bundle
-2. Extracts files to target directory
-3. Applies spec, lock, routing, and policies
-4. Prompts for confirmation on file overwrites (unless --force or --yes)
+2. Prompts for confirmation on file overwrites (unless --force or --yes)
+3. Stages spec, lock, routing, policies, and other files and verifies
+   each against its manifest checksum
+4. Moves the staged files into place together
+
+The apply is all or nothing: if any file cannot be verified or moved into
+place, the files already moved are restored and the project is left as it
+was. With --backup, the files the apply overwrites are also saved under
+.specular/backups, so an unwanted apply can be undone with
+'specular bundle restore'.
+
+Delta bundles (created with 'bundle create --base') only contain changed
+files. Applying one requires the exact base bundle via --base, verified by
+digest; only the changed files are written.
 
 Examples:
   # Dry-run to preview changes
@@ -188,11 +253,30 @@ Examples:
   specular bundle apply --force bundle.sbundle.tgz
 
   # Auto-confirm all prompts
-  specular bundle apply --yes bundle.sbundle.tgz`,
+  specular bundle apply --yes bundle.sbundle.tgz
+
+  # Apply a delta bundle on top of its base
+  specular bundle apply --base my-app-v1.0.0.sbundle.tgz my-app-v1.1.0.sbundle.tgz
+
+  # Keep a backup of overwritten files
+  specular bundle apply --backup --yes bundle.sbundle.tgz`,
 	Args: cobra.ExactArgs(1),
 	RunE: runBundleApply,
 }
 
+var bundleRestoreCmd = &cobra.Command{
+	Use:   "restore <backup-dir>",
+	Short: "Undo a bundle apply from its backup",
+	Long: `Undo a 'bundle apply --backup': files the apply overwrote get their previous
+contents back and files it added are removed. The backup directory is printed
+by the apply and lives under .specular/backups in the target directory.
+
+Example:
+  specular bundle restore .specular/backups/apply-20251116T101500.000000000Z`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleRestore,
+}
+
 var bundlePushCmd = &cobra.Command{
 	Use:   "push <bundle> <registry-ref>",
 	Short: "Push a governance bundle to an OCI registry",
@@ -220,7 +304,10 @@ Examples:
   specular bundle push bundle.sbundle.tgz registry.company.com/team/bundle:v1.0.0
 
   # Push to insecure registry (http)
-  specular bundle push --insecure bundle.sbundle.tgz localhost:5000/bundle:test`,
+  specular bundle push --insecure bundle.sbundle.tgz localhost:5000/bundle:test
+
+The bundle is uploaded in chunks. A dropped connection resumes from the
+last chunk the registry acknowledged instead of starting over.`,
 	Args: cobra.ExactArgs(2),
 	RunE: runBundlePush,
 }
@@ -248,14 +335,17 @@ Examples:
   specular bundle pull docker.io/username/bundle:latest
 
   # Pull from insecure registry (http)
-  specular bundle pull --insecure localhost:5000/bundle:test`,
+  specular bundle pull --insecure localhost:5000/bundle:test
+
+Interrupted downloads are kept as <output>.<digest>.partial and resumed
+with range requests, both on automatic retries and on the next pull.`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runBundlePull,
 }
 
 var bundleApproveCmd = &cobra.Command{
 	Use:   "approve <bundle>",
-	Short: "Sign a bundle approval with SSH/GPG key",
+	Short: "Sign a bundle approval with SSH/GPG/cosign key",
 	Long: `Create a cryptographic approval signature for a governance bundle.
 
 Approvals represent stakeholder sign-off for governance decisions. Each approval
@@ -263,8 +353,9 @@ includes:
 - Role (e.g., pm, lead, security, legal)
 - User identifier (email or username)
 - Timestamp
-- Cryptographic signature (SSH or GPG)
+- Cryptographic signature (SSH, GPG, or cosign)
 - Optional comment
+- Optional expiry (--valid-for or --valid-until)
 
 The signature proves that a specific individual in a specific role approved the
 bundle at a specific time.
@@ -272,6 +363,10 @@ bundle at a specific time.
 Supported signature types:
 - SSH (default) - Uses SSH keys (~/.ssh/id_ed25519, id_rsa, etc.)
 - GPG - Uses GPG keys from gpg keyring
+- cosign - Uses a cosign key (./cosign.key by default, password from
+  COSIGN_PASSWORD). The signature covers the bundle file itself and is also
+  written to <bundle>.sig (and the --certificate to <bundle>.pem), so
+  'cosign verify-blob' can verify the bundle without specular
 
 Examples:
   # Approve as product manager with default SSH key
@@ -297,7 +392,29 @@ Examples:
   specular bundle approve bundle.sbundle.tgz \
     --role pm \
     --user alice@example.com \
-    --output approvals/pm-alice.json`,
+    --output approvals/pm-alice.json
+
+  # Security sign-off that expires after 90 days
+  specular bundle approve bundle.sbundle.tgz \
+    --role security \
+    --user bob@example.com \
+    --valid-for 90d
+
+  # Approval valid until a fixed date
+  specular bundle approve bundle.sbundle.tgz \
+    --role legal \
+    --user dana@example.com \
+    --valid-until 2026-12-31
+
+  # Approve with a cosign key and certificate, then verify with cosign
+  specular bundle approve bundle.sbundle.tgz \
+    --role security \
+    --user bob@example.com \
+    --signature-type cosign \
+    --key-path cosign.key \
+    --certificate signer.pem
+  cosign verify-blob --key cosign.pub \
+    --signature bundle.sbundle.tgz.sig bundle.sbundle.tgz`,
 	Args: cobra.ExactArgs(1),
 	RunE: runBundleApprove,
 }
@@ -312,7 +429,9 @@ This command:
 2. Loads approval files from the specified paths
 3. Verifies each approval signature against the bundle digest
 4. Shows which roles have approved and which are missing
-5. Displays approval details (who, when, signature status)
+5. Displays approval details (who, when, signature status, remaining validity)
+
+Expired approvals are reported as invalid and do not count towards required roles.
 
 Use this command to:
 - Check if a bundle has all required approvals before applying
@@ -346,6 +465,7 @@ Examples:
 var (
 	diffJSON  bool
 	diffQuiet bool
+	diffOnly  []string
 )
 
 var bundleDiffCmd = &cobra.Command{
@@ -359,6 +479,10 @@ This command loads two bundles and compares:
 - Attestations: Indicates if attestation has changed
 - Metadata: Shows changes to bundle metadata (version, name, governance level)
 
+Use --only to restrict the text output, JSON output, and --quiet exit code
+to selected categories (files, approvals, attestation, metadata). Categories
+can be combined with commas or by repeating the flag.
+
 Use this command to:
 - Review changes between bundle versions
 - Verify what changed before applying an update
@@ -381,7 +505,13 @@ Examples:
   specular bundle diff bundle-a.sbundle.tgz bundle-b.sbundle.tgz --quiet
   if [ $? -eq 2 ]; then
     echo "Bundles differ"
-  fi`,
+  fi
+
+  # Audit approval changes only
+  specular bundle diff old.sbundle.tgz new.sbundle.tgz --only approvals
+
+  # Fail CI only when files or metadata changed
+  specular bundle diff old.sbundle.tgz new.sbundle.tgz --only files,metadata --quiet`,
 	Args: cobra.ExactArgs(2),
 	RunE: runBundleDiff,
 }
@@ -423,8 +553,11 @@ func parseMetadataFlags(metadataFlags []string) map[string]string {
 	return metadata
 }
 
-// generateBundleAttestation generates and adds attestation to the bundle
-func generateBundleAttestation(output, attestFmt string) error {
+// generateBundleAttestation generates and adds attestation to the bundle.
+// With useKeyless it is signed with a Fulcio certificate for the ambient
+// OIDC identity, or with an ephemeral key when no token is available. With
+// a rekorURL the signature is recorded in that Rekor log.
+func generateBundleAttestation(output, attestFmt string, useKeyless bool, rekorURL string) error {
 	fmt.Printf("\nGenerating %s attestation...\n", attestFmt)
 
 	// Determine attestation format
@@ -443,15 +576,26 @@ func generateBundleAttestation(output, attestFmt string) error {
 	// Create attestation generator
 	attestOpts := bundle.AttestationOptions{
 		Format:            format,
-		UseKeyless:        false, // For now, require key-based signing
-		IncludeRekorEntry: false, // Rekor not yet implemented
+		UseKeyless:        useKeyless,
+		UseEphemeralKey:   !useKeyless,
+		RekorURL:          rekorURL,
+		IncludeRekorEntry: rekorURL != "",
+	}
+	signingMode := "ephemeral key (identity not certified)"
+	if useKeyless {
+		signingMode = "keyless (Fulcio certificate)"
 	}
-
-	generator := bundle.NewAttestationGenerator(attestOpts)
 
 	// Generate attestation
 	ctx := context.Background()
-	attestation, attestErr := generator.GenerateAttestation(ctx, output)
+	attestation, attestErr := bundle.NewAttestationGenerator(attestOpts).GenerateAttestation(ctx, output)
+	if attestErr != nil && errors.Is(attestErr, keyless.ErrNoToken) {
+		fmt.Printf("⚠ Keyless signing unavailable: %v\n", keyless.ErrNoToken)
+		fmt.Println("  Falling back to an ephemeral key")
+		attestOpts.UseKeyless, attestOpts.UseEphemeralKey = false, true
+		signingMode = "ephemeral key (identity not certified)"
+		attestation, attestErr = bundle.NewAttestationGenerator(attestOpts).GenerateAttestation(ctx, output)
+	}
 	if attestErr != nil {
 		fmt.Printf("⚠ Warning: Failed to generate attestation: %v\n", attestErr)
 		fmt.Println("Continuing without attestation...")
@@ -466,6 +610,13 @@ func generateBundleAttestation(output, attestFmt string) error {
 	}
 
 	fmt.Printf("✓ Attestation generated and added to bundle\n")
+	fmt.Printf("  Signing mode: %s\n", signingMode)
+	if signer, err := attestation.SignerIdentity(); err == nil {
+		fmt.Printf("  Signed by: %s\n", signer)
+	}
+	if attestation.HasRekorEntry() {
+		fmt.Printf("  Rekor log index: %d (%s)\n", attestation.RekorEntry.LogIndex, attestation.RekorEntry.UUID)
+	}
 	return nil
 }
 
@@ -506,15 +657,18 @@ func runBundleCreate(cmd *cobra.Command, args []string) error {
 
 	// Build options
 	opts := bundle.BundleOptions{
-		SpecPath:          buildSpec,
-		LockPath:          buildLock,
-		RoutingPath:       buildRouting,
-		PolicyPaths:       buildPolicies,
-		IncludePaths:      buildInclude,
-		RequireApprovals:  approvals,
-		AttestationFormat: buildAttestFmt,
-		Metadata:          metadata,
-		GovernanceLevel:   buildGovLevel,
+		SpecPath:             buildSpec,
+		LockPath:             buildLock,
+		RoutingPath:          buildRouting,
+		PolicyPaths:          buildPolicies,
+		AllowInvalidPolicies: buildAllowInvalid,
+		IncludePaths:         buildInclude,
+		ExcludePatterns:      buildExclude,
+		RequireApprovals:     approvals,
+		AttestationFormat:    buildAttestFmt,
+		Metadata:             metadata,
+		GovernanceLevel:      buildGovLevel,
+		BasePath:             buildBase,
 	}
 
 	// Create builder
@@ -524,6 +678,22 @@ func runBundleCreate(cmd *cobra.Command, args []string) error {
 		return ux.FormatError(err, "creating bundle builder")
 	}
 
+	// Invalid policies fail the build below unless explicitly allowed
+	if buildAllowInvalid {
+		for _, policyErr := range builder.ValidatePolicies() {
+			fmt.Printf("⚠ Warning: packaging %v\n", policyErr)
+		}
+	}
+
+	// Resolve --include patterns up front so the user sees what will ship
+	if len(buildInclude) > 0 {
+		summary, resolveErr := builder.ResolveIncludes()
+		if resolveErr != nil {
+			return ux.FormatError(resolveErr, "resolving include patterns")
+		}
+		displayIncludeSummary(summary)
+	}
+
 	// Build bundle
 	if buildErr := builder.Build(output); buildErr != nil {
 		return ux.FormatError(buildErr, "building bundle")
@@ -537,9 +707,17 @@ func runBundleCreate(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\n✓ Bundle created successfully: %s (%.2f MB)\n", output, float64(info.Size())/(1024*1024))
 
+	if buildBase != "" {
+		displayDeltaSummary(builder.Manifest())
+	}
+
 	// Generate attestation if requested
 	if buildAttest && buildAttestFmt != "" {
-		if attestErr := generateBundleAttestation(output, buildAttestFmt); attestErr != nil {
+		rekorURL := ""
+		if buildRekor {
+			rekorURL = buildRekorURL
+		}
+		if attestErr := generateBundleAttestation(output, buildAttestFmt, buildKeyless, rekorURL); attestErr != nil {
 			return attestErr
 		}
 	}
@@ -550,6 +728,27 @@ func runBundleCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// displayDeltaSummary prints what a delta bundle carries relative to its base
+func displayDeltaSummary(manifest *bundle.Manifest) {
+	if !manifest.IsDelta() {
+		return
+	}
+	fmt.Printf("  Delta against %s@%s (%s)\n", manifest.Delta.BaseID, manifest.Delta.BaseVersion, manifest.Delta.BaseDigest)
+	fmt.Printf("  Changed: %d  Unchanged: %d  Removed: %d\n",
+		len(manifest.Files), len(manifest.Delta.Unchanged), len(manifest.Delta.Removed))
+}
+
+// displayIncludeSummary prints how many files each include pattern matched
+func displayIncludeSummary(summary *bundle.IncludeSummary) {
+	fmt.Printf("Matched %d additional file(s):\n", len(summary.Files))
+	for _, match := range summary.Matches {
+		fmt.Printf("  %-30s %d file(s)\n", match.Pattern, len(match.Files))
+	}
+	if summary.Excluded > 0 {
+		fmt.Printf("  (%d file(s) excluded by --exclude or %s)\n", summary.Excluded, bundle.IgnoreFileName)
+	}
+}
+
 func runBundleGate(cmd *cobra.Command, args []string) error {
 	// Check license - bundle gate requires Pro tier
 	if err := license.RequireFeature("bundle.gate", license.TierPro); err != nil {
@@ -564,16 +763,25 @@ func runBundleGate(cmd *cobra.Command, args []string) error {
 		return ux.FormatError(err, "bundle not found")
 	}
 
+	switch gateExitCodes {
+	case gateExitStrategyFirst, gateExitStrategyHighest, gateExitStrategyAggregate:
+	default:
+		return fmt.Errorf("invalid --exit-code-strategy %q (must be first, highest-severity, or aggregate)", gateExitCodes)
+	}
+
 	fmt.Printf("Running governance gate checks on: %s\n\n", bundlePath)
 
 	// Create validator
 	opts := bundle.VerifyOptions{
-		Strict:             gateStrict,
-		RequireApprovals:   gateApprovals,
-		RequireAttestation: gateAttestation,
-		PolicyPath:         gatePolicy,
-		TrustPublicKeys:    gateTrustedKeys,
-		AllowOffline:       gateOffline,
+		Strict:                   gateStrict,
+		RequireApprovals:         gateApprovals,
+		RequireAttestation:       gateAttestation,
+		PolicyPath:               gatePolicy,
+		TrustPublicKeys:          gateTrustedKeys,
+		AllowOffline:             gateOffline,
+		RequireManifestSignature: gateManifestSig,
+		RekorURL:                 gateRekorURL,
+		RequireRekorEntry:        gateRequireRekor,
 	}
 
 	validator := bundle.NewValidator(opts)
@@ -598,6 +806,7 @@ func runBundleGate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Checksum Validation:    %s\n", formatValidationStatus(result.ChecksumValid))
 	fmt.Printf("Approval Validation:    %s\n", formatValidationStatus(result.ApprovalsValid))
 	fmt.Printf("Attestation Validation: %s\n", formatValidationStatus(result.AttestationValid))
+	fmt.Printf("Manifest Signature:     %s\n", formatValidationStatus(result.ManifestSignatureValid))
 	if result.PolicyCompliant {
 		fmt.Printf("Policy Compliance:      %s\n", formatValidationStatus(result.PolicyCompliant))
 	}
@@ -626,29 +835,17 @@ func runBundleGate(cmd *cobra.Command, args []string) error {
 
 	// Determine exit code based on failure type
 	if !result.Valid {
-		fmt.Println()
+		failures := categorizeGateFailures(result.Errors)
+		exitCode := gateExitCode(failures, gateExitCodes)
 
-		// Check for specific failure types and return appropriate exit codes
-		for _, err := range result.Errors {
-			switch err.Code {
-			case "POLICY_VIOLATION", "POLICY_COMPLIANCE_FAILED":
-				fmt.Println("Exit code: 20 (Policy violation)")
-				os.Exit(20)
-			case "DRIFT_DETECTED":
-				fmt.Println("Exit code: 30 (Drift detected)")
-				os.Exit(30)
-			case "MISSING_APPROVAL", "APPROVAL_FAILED":
-				fmt.Println("Exit code: 40 (Missing approval)")
-				os.Exit(40)
-			case "FORBIDDEN_PROVIDER", "PROVIDER_NOT_ALLOWED":
-				fmt.Println("Exit code: 50 (Forbidden provider)")
-				os.Exit(50)
-			}
+		fmt.Println()
+		fmt.Println("Failure summary:")
+		for _, failure := range failures {
+			fmt.Printf("  %-20s %d error(s) (exit code %d)\n", failure.Label+":", failure.Count, failure.ExitCode)
 		}
 
-		// Default failure exit code
-		fmt.Println("Exit code: 60 (Evaluation failure)")
-		os.Exit(60)
+		fmt.Printf("\nExit code: %d (%s)\n", exitCode, gateExitCodeLabel(exitCode))
+		os.Exit(exitCode)
 	}
 
 	// Success
@@ -685,6 +882,8 @@ func runBundleApply(cmd *cobra.Command, args []string) error {
 		Force:     applyForce,
 		Yes:       applyYes,
 		Exclude:   applyExclude,
+		BasePath:  applyBase,
+		Backup:    applyBackup,
 	}
 
 	extractor := bundle.NewExtractor(opts)
@@ -697,6 +896,18 @@ func runBundleApply(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runBundleRestore(cmd *cobra.Command, args []string) error {
+	backup, err := bundle.RestoreBackup(args[0])
+	if err != nil {
+		return ux.FormatError(err, "restoring backup")
+	}
+
+	fmt.Printf("Restored %s from %s\n", backup.TargetDir, args[0])
+	fmt.Printf("  Restored: %d file(s)\n", len(backup.Overwritten))
+	fmt.Printf("  Removed:  %d file(s)\n", len(backup.Added))
+	return nil
+}
+
 func runBundlePush(cmd *cobra.Command, args []string) error {
 	bundlePath := args[0]
 	registryRef := args[1]
@@ -716,6 +927,12 @@ func runBundlePush(cmd *cobra.Command, args []string) error {
 		UserAgent: pushUserAgent,
 	}
 
+	if !pushNoProgress {
+		indicator := progress.NewTransferIndicator(os.Stdout, "Uploading")
+		defer indicator.Finish()
+		opts.Progress = indicator.Update
+	}
+
 	// Parse platform if specified
 	if pushPlatform != "" {
 		// Simple platform parsing (e.g., "linux/amd64")
@@ -731,7 +948,7 @@ func runBundlePush(cmd *cobra.Command, args []string) error {
 	pusher := bundle.NewOCIPusher(opts)
 
 	// Push bundle
-	if err := pusher.Push(bundlePath); err != nil {
+	if err := pusher.Push(cmd.Context(), bundlePath); err != nil {
 		return ux.FormatError(err, "pushing bundle")
 	}
 
@@ -770,10 +987,16 @@ func runBundlePull(cmd *cobra.Command, args []string) error {
 		UserAgent: pullUserAgent,
 	}
 
+	if !pullNoProgress {
+		indicator := progress.NewTransferIndicator(os.Stdout, "Downloading")
+		defer indicator.Finish()
+		opts.Progress = indicator.Update
+	}
+
 	puller := bundle.NewOCIPuller(opts)
 
 	// Pull bundle
-	if err := puller.Pull(output); err != nil {
+	if err := puller.Pull(cmd.Context(), output); err != nil {
 		return ux.FormatError(err, "pulling bundle")
 	}
 
@@ -796,6 +1019,11 @@ func runBundleApprove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--user is required (e.g., your email or username)")
 	}
 
+	expiresAt, err := parseApprovalExpiry(approveValidFor, approveValidUntil, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Compute bundle digest
 	fmt.Println("Computing bundle digest...")
 	digest, err := bundle.ComputeBundleDigest(bundlePath)
@@ -810,15 +1038,20 @@ func runBundleApprove(cmd *cobra.Command, args []string) error {
 	if sigType == "" {
 		sigType = bundle.SignatureTypeSSH // Default to SSH
 	}
+	if approveCert != "" && sigType != bundle.SignatureTypeCosign {
+		return fmt.Errorf("--certificate is only supported with --signature-type cosign")
+	}
 
 	// Create approval request
 	req := bundle.ApprovalRequest{
-		BundleDigest:  digest,
-		Role:          approveRole,
-		User:          approveUser,
-		Comment:       approveComment,
-		SignatureType: sigType,
-		KeyPath:       approveKeyPath,
+		BundleDigest:    digest,
+		Role:            approveRole,
+		User:            approveUser,
+		Comment:         approveComment,
+		SignatureType:   sigType,
+		KeyPath:         approveKeyPath,
+		CertificatePath: approveCert,
+		ExpiresAt:       expiresAt,
 	}
 
 	// Create signer
@@ -846,6 +1079,9 @@ func runBundleApprove(cmd *cobra.Command, args []string) error {
 	if approval.Comment != "" {
 		fmt.Printf("  Comment:   %s\n", approval.Comment)
 	}
+	if approval.ExpiresAt != nil {
+		fmt.Printf("  Expires:   %s\n", approval.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
 
 	// Determine output path
 	output := approveOutput
@@ -870,9 +1106,90 @@ func runBundleApprove(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Printf("✓ Approval saved to: %s\n", output)
 
+	if approval.SignatureType == bundle.SignatureTypeCosign {
+		sigPath, certPath, err := bundle.WriteCosignSignature(approval, bundlePath)
+		if err != nil {
+			return ux.FormatError(err, "writing cosign signature")
+		}
+		fmt.Printf("✓ Cosign signature saved to: %s\n", sigPath)
+		if certPath != "" {
+			fmt.Printf("✓ Certificate saved to: %s\n", certPath)
+		}
+	}
+
 	return nil
 }
 
+// parseApprovalExpiry converts the --valid-for and --valid-until flags into an
+// approval expiry. It returns nil when neither flag is set.
+func parseApprovalExpiry(validFor, validUntil string, now time.Time) (*time.Time, error) {
+	if validFor != "" && validUntil != "" {
+		return nil, fmt.Errorf("--valid-for and --valid-until are mutually exclusive")
+	}
+
+	var expiresAt time.Time
+	switch {
+	case validFor != "":
+		duration, err := parseValidityDuration(validFor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --valid-for %q: %w", validFor, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("--valid-for must be positive, got %q", validFor)
+		}
+		expiresAt = now.Add(duration)
+	case validUntil != "":
+		t, err := time.Parse(time.RFC3339, validUntil)
+		if err != nil {
+			// Accept a plain date, valid through the end of that day (UTC)
+			date, dateErr := time.Parse("2006-01-02", validUntil)
+			if dateErr != nil {
+				return nil, fmt.Errorf("invalid --valid-until %q: use RFC3339 (2006-01-02T15:04:05Z) or YYYY-MM-DD", validUntil)
+			}
+			t = date.Add(24*time.Hour - time.Second)
+		}
+		if !t.After(now) {
+			return nil, fmt.Errorf("--valid-until %s is in the past", validUntil)
+		}
+		expiresAt = t
+	default:
+		return nil, nil
+	}
+
+	// The expiry is signed at second precision
+	expiresAt = expiresAt.UTC().Truncate(time.Second)
+	return &expiresAt, nil
+}
+
+// parseValidityDuration parses a Go duration, additionally accepting a
+// whole number of days such as "90d"
+func parseValidityDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil || fmt.Sprint(n) != days {
+			return 0, fmt.Errorf("expected a number of days such as 90d")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// formatRemainingValidity renders the time left before an approval expires
+func formatRemainingValidity(approval *bundle.Approval, now time.Time) string {
+	remaining, ok := approval.RemainingValidity(now)
+	if !ok {
+		return "no expiry"
+	}
+	if remaining == 0 {
+		return fmt.Sprintf("expired %s", approval.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+	days := int(remaining.Hours() / 24)
+	if days > 0 {
+		return fmt.Sprintf("expires in %dd (%s)", days, approval.ExpiresAt.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("expires in %s (%s)", remaining.Truncate(time.Minute), approval.ExpiresAt.Format("2006-01-02 15:04"))
+}
+
 // loadApprovalFiles loads and parses approval files from disk
 func loadApprovalFiles(approvalPaths []string) ([]*bundle.Approval, error) {
 	if len(approvalPaths) == 0 {
@@ -918,10 +1235,15 @@ func verifyApprovalSignatures(approvals []*bundle.Approval, digest string) (map[
 		})
 
 		if err := verifier.VerifyApproval(approval); err != nil {
+			label := "INVALID"
+			if errors.Is(err, bundle.ErrApprovalExpired) {
+				label = "EXPIRED"
+			}
 			verificationErrors = append(verificationErrors,
-				fmt.Sprintf("Role %s (%s): ✗ INVALID - %v", approval.Role, approval.User, err))
+				fmt.Sprintf("Role %s (%s): ✗ %s - %v", approval.Role, approval.User, label, err))
 		} else {
-			fmt.Printf("✓ Role %s (%s): Valid signature\n", approval.Role, approval.User)
+			fmt.Printf("✓ Role %s (%s): Valid signature (%s)\n", approval.Role, approval.User,
+				formatRemainingValidity(approval, time.Now()))
 			verifiedRoles[approval.Role] = approval
 		}
 	}
@@ -947,12 +1269,18 @@ func checkRequiredRoles(requiredRoles []string, verifiedRoles map[string]*bundle
 	fmt.Println("Checking required roles...")
 	var missingRoles []string
 
+	now := time.Now()
 	for _, requiredRole := range requiredRoles {
-		if _, exists := verifiedRoles[requiredRole]; !exists {
+		approval, exists := verifiedRoles[requiredRole]
+		switch {
+		case !exists:
 			missingRoles = append(missingRoles, requiredRole)
 			fmt.Printf("✗ %s: Missing or invalid approval\n", requiredRole)
-		} else {
-			fmt.Printf("✓ %s: Approved\n", requiredRole)
+		case approval.ExpiredAt(now):
+			missingRoles = append(missingRoles, requiredRole)
+			fmt.Printf("✗ %s: Approval expired at %s\n", requiredRole, approval.ExpiresAt.Format(time.RFC3339))
+		default:
+			fmt.Printf("✓ %s: Approved (%s)\n", requiredRole, formatRemainingValidity(approval, now))
 		}
 	}
 
@@ -981,10 +1309,11 @@ func displayApprovalSummaryText(verifiedRoles map[string]*bundle.Approval, verif
 		fmt.Println()
 		fmt.Println("Approved by:")
 		for role, approval := range verifiedRoles {
-			fmt.Printf("  - %s: %s (signed %s)\n",
+			fmt.Printf("  - %s: %s (signed %s, %s)\n",
 				role,
 				approval.User,
-				approval.SignedAt.Format("2006-01-02 15:04:05"))
+				approval.SignedAt.Format("2006-01-02 15:04:05"),
+				formatRemainingValidity(approval, time.Now()))
 			if approval.Comment != "" {
 				fmt.Printf("    Comment: %s\n", approval.Comment)
 			}
@@ -994,16 +1323,42 @@ func displayApprovalSummaryText(verifiedRoles map[string]*bundle.Approval, verif
 
 // outputApprovalStatusJSON outputs approval status as JSON
 func outputApprovalStatusJSON(digest string, approvals []*bundle.Approval, verifiedRoles map[string]*bundle.Approval, verificationErrors []string, requiredRoles []string) error {
+	type ApprovalValidity struct {
+		Role             string     `json:"role"`
+		User             string     `json:"user"`
+		SignedAt         time.Time  `json:"signed_at"`
+		ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+		Expired          bool       `json:"expired"`
+		RemainingSeconds int64      `json:"remaining_seconds,omitempty"`
+	}
+
 	type ApprovalStatus struct {
 		BundleDigest     string                      `json:"bundle_digest"`
 		TotalApprovals   int                         `json:"total_approvals"`
 		ValidApprovals   int                         `json:"valid_approvals"`
 		InvalidApprovals int                         `json:"invalid_approvals"`
 		VerifiedRoles    map[string]*bundle.Approval `json:"verified_roles"`
+		Approvals        []ApprovalValidity          `json:"approvals"`
 		MissingRoles     []string                    `json:"missing_roles,omitempty"`
 		Errors           []string                    `json:"errors,omitempty"`
 	}
 
+	now := time.Now()
+	validity := make([]ApprovalValidity, 0, len(approvals))
+	for _, approval := range approvals {
+		entry := ApprovalValidity{
+			Role:      approval.Role,
+			User:      approval.User,
+			SignedAt:  approval.SignedAt,
+			ExpiresAt: approval.ExpiresAt,
+			Expired:   approval.ExpiredAt(now),
+		}
+		if remaining, ok := approval.RemainingValidity(now); ok {
+			entry.RemainingSeconds = int64(remaining.Seconds())
+		}
+		validity = append(validity, entry)
+	}
+
 	missingRoles := []string{}
 	if len(requiredRoles) > 0 {
 		for _, requiredRole := range requiredRoles {
@@ -1019,6 +1374,7 @@ func outputApprovalStatusJSON(digest string, approvals []*bundle.Approval, verif
 		ValidApprovals:   len(verifiedRoles),
 		InvalidApprovals: len(verificationErrors),
 		VerifiedRoles:    verifiedRoles,
+		Approvals:        validity,
 		MissingRoles:     missingRoles,
 		Errors:           verificationErrors,
 	}
@@ -1164,10 +1520,34 @@ func displayOtherDiffChanges(diffResult *bundle.DiffResult) {
 	}
 }
 
+// joinDiffCategories formats diff categories for display
+func joinDiffCategories(categories []bundle.DiffCategory) string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = string(category)
+	}
+	return strings.Join(names, ", ")
+}
+
+// bundleDiffExitCode returns the --quiet exit code for a diff result:
+// 2 when differences were found, 0 when the bundles are identical
+func bundleDiffExitCode(diffResult *bundle.DiffResult) int {
+	if diffResult.HasChanges() {
+		return 2
+	}
+	return 0
+}
+
 func runBundleDiff(cmd *cobra.Command, args []string) error {
 	bundlePathA := args[0]
 	bundlePathB := args[1]
 
+	// Validate the category filter before loading bundles
+	categories, err := bundle.ParseDiffCategories(diffOnly)
+	if err != nil {
+		return err
+	}
+
 	// Load both bundles
 	bundleA, bundleB, err := loadBundlesForDiff(bundlePathA, bundlePathB, diffQuiet)
 	if err != nil {
@@ -1180,10 +1560,12 @@ func runBundleDiff(cmd *cobra.Command, args []string) error {
 		return ux.FormatError(err, "comparing bundles")
 	}
 
+	diffResult = diffResult.Filter(categories...)
+
 	// Handle quiet mode
 	if diffQuiet {
-		if diffResult.HasChanges() {
-			os.Exit(2) // Exit code 2 indicates differences found
+		if code := bundleDiffExitCode(diffResult); code != 0 {
+			os.Exit(code) // Exit code 2 indicates differences found
 		}
 		return nil // Exit code 0 indicates identical bundles
 	}
@@ -1200,6 +1582,10 @@ func runBundleDiff(cmd *cobra.Command, args []string) error {
 
 	// Human-readable output
 	if !diffResult.HasChanges() {
+		if len(categories) > 0 {
+			fmt.Printf("✓ No differences found in: %s\n", joinDiffCategories(categories))
+			return nil
+		}
 		fmt.Println("✓ No differences found - bundles are identical")
 		return nil
 	}
@@ -1218,6 +1604,106 @@ func runBundleDiff(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Gate exit code strategies
+const (
+	gateExitStrategyFirst     = "first"
+	gateExitStrategyHighest   = "highest-severity"
+	gateExitStrategyAggregate = "aggregate"
+)
+
+// gateExitEvaluation is the exit code for unclassified or mixed gate failures
+const gateExitEvaluation = 60
+
+// gateFailureCategory groups gate errors that share an exit code
+type gateFailureCategory struct {
+	Label    string
+	ExitCode int
+	Count    int
+	first    int // Index of the first error in this category
+}
+
+// gateErrorCategories maps validation error codes to exit codes.
+// Lower exit codes are more severe.
+var gateErrorCategories = map[string]int{
+	"POLICY_VIOLATION":         20,
+	"POLICY_COMPLIANCE_FAILED": 20,
+	"DRIFT_DETECTED":           30,
+	"MISSING_APPROVAL":         40,
+	"APPROVAL_FAILED":          40,
+	"FORBIDDEN_PROVIDER":       50,
+	"PROVIDER_NOT_ALLOWED":     50,
+}
+
+// gateExitCodeLabel describes a gate exit code
+func gateExitCodeLabel(code int) string {
+	switch code {
+	case 20:
+		return "Policy violation"
+	case 30:
+		return "Drift detected"
+	case 40:
+		return "Missing approval"
+	case 50:
+		return "Forbidden provider"
+	default:
+		return "Evaluation failure"
+	}
+}
+
+// categorizeGateFailures groups validation errors by exit code, ordered by severity
+func categorizeGateFailures(errs []bundle.ValidationError) []gateFailureCategory {
+	byCode := make(map[int]*gateFailureCategory)
+	for i, err := range errs {
+		code, ok := gateErrorCategories[err.Code]
+		if !ok {
+			code = gateExitEvaluation
+		}
+
+		category, exists := byCode[code]
+		if !exists {
+			category = &gateFailureCategory{Label: gateExitCodeLabel(code), ExitCode: code, first: i}
+			byCode[code] = category
+		}
+		category.Count++
+	}
+
+	failures := make([]gateFailureCategory, 0, len(byCode))
+	for _, category := range byCode {
+		failures = append(failures, *category)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].ExitCode < failures[j].ExitCode
+	})
+
+	return failures
+}
+
+// gateExitCode selects the exit code for the failed categories using strategy
+func gateExitCode(failures []gateFailureCategory, strategy string) int {
+	if len(failures) == 0 {
+		return gateExitEvaluation
+	}
+
+	switch strategy {
+	case gateExitStrategyFirst:
+		first := failures[0]
+		for _, failure := range failures[1:] {
+			if failure.first < first.first {
+				first = failure
+			}
+		}
+		return first.ExitCode
+	case gateExitStrategyAggregate:
+		if len(failures) > 1 {
+			return gateExitEvaluation
+		}
+		return failures[0].ExitCode
+	default:
+		// Failures are sorted most severe first
+		return failures[0].ExitCode
+	}
+}
+
 func formatValidationStatus(valid bool) string {
 	if valid {
 		return "✓ PASS"
@@ -1491,6 +1977,313 @@ func runBundleList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Bundle verify-attestation command flags
+var (
+	verifyExpectedIdentities []string
+	verifyTrustedRoot        string
+	verifyRekorURL           string
+	verifyOffline            bool
+)
+
+var bundleVerifyAttestationCmd = &cobra.Command{
+	Use:   "verify-attestation <bundle>",
+	Short: "Verify the attestation embedded in a bundle",
+	Long: `Verify the cryptographic attestation embedded in a governance bundle
+without running the full gate.
+
+The attestation signature is checked against the signed in-toto statement
+for all attestation formats (sigstore, in-toto, slsa), and the attested
+manifest digest is compared with the bundle's manifest.yaml, which pins
+the checksum of every file in the bundle.
+
+--expected-identity accepts a signer fingerprint (sha256:<hex>), a glob
+pattern, or the path to a PEM public key file. It may be repeated; the
+signer must match at least one. For keyless attestations the signer is the
+email or URI in the Fulcio certificate. Anyone can issue themselves a
+certificate for any email or URI, so these identities require
+--trusted-root; only fingerprints are accepted without it.
+
+--trusted-root requires a keyless signature whose certificate chains to the
+CA certificates in the given PEM file.
+
+At least one of --expected-identity and --trusted-root is required. The
+signing key is embedded in the attestation, so without either a valid
+signature proves nothing about the signer; the command reports an
+unverified signer and exits with 71.
+
+A Rekor transparency log entry, when present, is fetched from the log
+(--rekor-url) and must record the attestation's signature. With --offline
+the embedded entry is checked against the signature without contacting
+the log.
+
+Exit codes:
+  0  - Attestation verified
+  60 - Verification failure (missing attestation, digest mismatch, ...)
+  70 - Signature mismatch
+  71 - Signer does not match --expected-identity, or unverified signer
+
+Examples:
+  # Require a specific signing key
+  specular bundle verify-attestation bundle.sbundle.tgz --expected-identity cosign.pub
+
+  # Require a keyless signature from a release workflow
+  specular bundle verify-attestation bundle.sbundle.tgz --trusted-root fulcio-root.pem \
+    --expected-identity "https://github.com/acme/app/.github/workflows/release.yml@*"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleVerifyAttestation,
+}
+
+func runBundleVerifyAttestation(cmd *cobra.Command, args []string) error {
+	bundlePath := args[0]
+
+	if _, err := os.Stat(bundlePath); os.IsNotExist(err) {
+		return ux.FormatError(err, "bundle not found")
+	}
+
+	identities, err := resolveExpectedIdentities(verifyExpectedIdentities)
+	if err != nil {
+		return ux.FormatError(err, "resolving expected identity")
+	}
+	if err := requireRootForCertifiedIdentities(identities, verifyTrustedRoot); err != nil {
+		return err
+	}
+
+	opts := bundle.AttestationVerificationOptions{
+		VerifySignature:   true,
+		TrustedIdentities: identities,
+		TrustedRootPath:   verifyTrustedRoot,
+		RekorURL:          verifyRekorURL,
+		Offline:           verifyOffline,
+	}
+
+	report, err := bundle.VerifyEmbeddedAttestation(cmd.Context(), bundlePath, opts)
+	if err != nil {
+		fmt.Printf("✗ Attestation verification FAILED: %v\n", err)
+		switch {
+		case errors.Is(err, bundle.ErrAttestationSignatureInvalid):
+			fmt.Println("Exit code: 70 (Signature mismatch)")
+			os.Exit(70)
+		case errors.Is(err, bundle.ErrAttestationIdentityMismatch):
+			fmt.Println("Exit code: 71 (Identity mismatch)")
+			os.Exit(71)
+		default:
+			fmt.Println("Exit code: 60 (Evaluation failure)")
+			os.Exit(60)
+		}
+	}
+
+	if len(identities) == 0 && verifyTrustedRoot == "" {
+		fmt.Printf("✗ Attestation verification FAILED: signature valid, unverified signer %s (pass --expected-identity or --trusted-root)\n", report.Signer)
+		fmt.Println("Exit code: 71 (Unverified signer)")
+		os.Exit(71)
+	}
+
+	fmt.Println("✓ Attestation verified")
+	fmt.Println()
+	fmt.Printf("Format:     %s\n", report.Format)
+	fmt.Printf("Predicate:  %s\n", report.PredicateType)
+	fmt.Printf("Signer:     %s\n", report.Signer)
+	switch {
+	case report.Keyless && verifyTrustedRoot != "":
+		fmt.Println("Signing:    keyless (certificate chain verified)")
+	case report.Keyless:
+		fmt.Println("Signing:    keyless (certificate chain not checked; use --trusted-root)")
+	default:
+		fmt.Println("Signing:    key")
+	}
+	fmt.Printf("Timestamp:  %s\n", report.Timestamp.Format(time.RFC3339))
+	switch {
+	case report.HasRekorEntry && verifyOffline:
+		fmt.Printf("Rekor:      log index %d (embedded entry, not checked against the log)\n", report.RekorLogIndex)
+	case report.HasRekorEntry:
+		fmt.Printf("Rekor:      log index %d (verified)\n", report.RekorLogIndex)
+	default:
+		fmt.Printf("Rekor:      %s\n", formatValidationStatus(false))
+	}
+	fmt.Println()
+	fmt.Println("Covered hashes:")
+	for _, subject := range report.Subjects {
+		fmt.Printf("  %s  sha256:%s\n", subject.Name, subject.Digest["sha256"])
+	}
+
+	return nil
+}
+
+// Bundle sign-manifest command flags
+var (
+	signManifestUser    string
+	signManifestSigType string
+	signManifestKeyPath string
+)
+
+var bundleSignManifestCmd = &cobra.Command{
+	Use:   "sign-manifest <bundle>",
+	Short: "Sign the bundle manifest with an SSH/GPG key",
+	Long: `Sign the manifest digest of a bundle and embed a detached signature.
+
+The manifest pins the checksum of every bundled file, so a valid manifest
+signature proves the bundle has not been altered since it was signed. This
+is lighter-weight than role-based approvals and can be done right after
+build, before anyone approves.
+
+The signature is stored in the archive as signatures/manifest.sig.json.
+Adding it re-packs the bundle and changes the bundle digest, so sign the
+manifest before collecting approvals.
+
+bundle gate verifies the manifest signature whenever it is present; use
+--require-manifest-signature to fail bundles without one and --trusted-key
+to restrict the accepted signing keys. Without --trusted-key the signer is
+untrusted, which fails a required signature.
+
+Examples:
+  # Sign with the default SSH key
+  specular bundle sign-manifest bundle.sbundle.tgz --user alice@example.com
+
+  # Sign with a GPG key
+  specular bundle sign-manifest bundle.sbundle.tgz \
+    --user alice@example.com \
+    --signature-type gpg \
+    --key-path F3A29C8B`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleSignManifest,
+}
+
+func runBundleSignManifest(cmd *cobra.Command, args []string) error {
+	bundlePath := args[0]
+
+	if _, err := os.Stat(bundlePath); os.IsNotExist(err) {
+		return ux.FormatError(err, "bundle not found")
+	}
+
+	if signManifestUser == "" {
+		return fmt.Errorf("--user is required (e.g., your email or username)")
+	}
+
+	sigType := bundle.SignatureType(signManifestSigType)
+	if sigType == "" {
+		sigType = bundle.SignatureTypeSSH
+	}
+
+	fmt.Printf("Signing manifest with %s key...\n", sigType)
+	signer := bundle.NewSigner(sigType, signManifestKeyPath)
+	sig, err := bundle.SignManifest(bundlePath, signer, signManifestUser)
+	if err != nil {
+		return ux.FormatError(err, "signing manifest")
+	}
+
+	fmt.Println("✓ Manifest signed successfully")
+	fmt.Println()
+	fmt.Printf("  Manifest:  %s\n", sig.ManifestDigest)
+	fmt.Printf("  Signer:    %s\n", sig.Signer)
+	fmt.Printf("  Signed At: %s\n", sig.SignedAt.Format("2006-01-02 15:04:05"))
+	if sig.PublicKeyFingerprint != "" {
+		fmt.Printf("  Key:       %s\n", sig.PublicKeyFingerprint)
+	}
+	fmt.Println()
+	fmt.Printf("✓ Signature embedded in: %s\n", bundlePath)
+
+	return nil
+}
+
+// Bundle sbom command flags
+var (
+	sbomFormat string
+	sbomOutput string
+)
+
+var bundleSBOMCmd = &cobra.Command{
+	Use:   "sbom <bundle>",
+	Short: "Export a software bill of materials for a bundle",
+	Long: `Generate a CycloneDX or SPDX JSON software bill of materials (SBOM)
+for a governance bundle.
+
+The SBOM lists:
+- Every file in the bundle manifest with its checksum
+- Bundle dependencies with their pinned versions and digests
+- Features pinned in spec.lock.json with their hashes
+- AI models referenced by routing.yaml, grouped by provider
+
+The bundle must pass integrity verification before an SBOM is produced.
+
+Examples:
+  # Print a CycloneDX SBOM
+  specular bundle sbom bundle.sbundle.tgz
+
+  # Write an SPDX SBOM to a file
+  specular bundle sbom bundle.sbundle.tgz --format spdx -o bundle.spdx.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleSBOM,
+}
+
+func runBundleSBOM(cmd *cobra.Command, args []string) error {
+	bundlePath := args[0]
+
+	if _, err := os.Stat(bundlePath); os.IsNotExist(err) {
+		return ux.FormatError(err, "bundle not found")
+	}
+
+	format, err := bundle.ParseSBOMFormat(sbomFormat)
+	if err != nil {
+		return err
+	}
+
+	data, err := bundle.GenerateSBOM(bundlePath, bundle.SBOMOptions{
+		Format:      format,
+		ToolVersion: version.Version,
+	})
+	if err != nil {
+		return ux.FormatError(err, "generating SBOM")
+	}
+
+	if sbomOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(sbomOutput, data, 0600); err != nil {
+		return ux.FormatError(err, "writing SBOM")
+	}
+
+	fmt.Printf("✓ %s SBOM written to: %s\n", format, sbomOutput)
+	return nil
+}
+
+// resolveExpectedIdentities converts public key file paths to fingerprints,
+// leaving fingerprints and patterns untouched.
+func resolveExpectedIdentities(values []string) ([]string, error) {
+	identities := make([]string, 0, len(values))
+	for _, value := range values {
+		data, err := os.ReadFile(value) //nolint:gosec // Path provided by the user
+		if err != nil {
+			identities = append(identities, value)
+			continue
+		}
+
+		fingerprint, err := bundle.PublicKeyFingerprint(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", value, err)
+		}
+		identities = append(identities, fingerprint)
+	}
+	return identities, nil
+}
+
+// requireRootForCertifiedIdentities rejects email and URI identities without
+// a trusted root. They come from the keyless signing certificate, which
+// anyone can issue themselves unless it must chain to a trusted root; only
+// key fingerprints (sha256:<hex>) identify a signer on their own.
+func requireRootForCertifiedIdentities(identities []string, trustedRoot string) error {
+	if trustedRoot != "" {
+		return nil
+	}
+	for _, identity := range identities {
+		if !strings.HasPrefix(identity, "sha256:") {
+			return fmt.Errorf("--expected-identity %s requires --trusted-root: a certificate identity is only verified when the certificate chains to a trusted root", identity)
+		}
+	}
+	return nil
+}
+
 func init() {
 	// Bundle create flags
 	bundleCreateCmd.Flags().StringVarP(&buildOutput, "output", "o", "", "Output bundle path (default: bundle.sbundle.tgz)")
@@ -1498,12 +2291,18 @@ func init() {
 	bundleCreateCmd.Flags().StringVar(&buildLock, "lock", "", "Path to spec.lock.json (default: .specular/spec.lock.json)")
 	bundleCreateCmd.Flags().StringVar(&buildRouting, "routing", "", "Path to routing.yaml (default: .specular/routing.yaml)")
 	bundleCreateCmd.Flags().StringSliceVarP(&buildPolicies, "policy", "p", nil, "Policy files to include (can be specified multiple times)")
-	bundleCreateCmd.Flags().StringSliceVarP(&buildInclude, "include", "i", nil, "Additional files/directories to include")
+	bundleCreateCmd.Flags().BoolVar(&buildAllowInvalid, "allow-invalid-policies", false, "Package policies that fail validation with a warning instead of failing")
+	bundleCreateCmd.Flags().StringSliceVarP(&buildInclude, "include", "i", nil, "Additional files, directories (recursive), or glob patterns to include")
+	bundleCreateCmd.Flags().StringSliceVar(&buildExclude, "exclude", nil, "Exclude patterns for included files (e.g., '*.log'); .specularignore is also honored")
 	bundleCreateCmd.Flags().StringSliceVarP(&buildApprovals, "require-approval", "a", nil, "Required approval roles (e.g., pm, lead, security)")
 	bundleCreateCmd.Flags().BoolVar(&buildAttest, "attest", false, "Generate Sigstore attestation")
 	bundleCreateCmd.Flags().StringVar(&buildAttestFmt, "attest-format", "sigstore", "Attestation format (sigstore, in-toto, slsa)")
+	bundleCreateCmd.Flags().BoolVar(&buildKeyless, "keyless", false, "Sign the attestation with a Fulcio certificate for the OIDC identity (SIGSTORE_ID_TOKEN or GitHub Actions); falls back to an ephemeral key without a token")
+	bundleCreateCmd.Flags().BoolVar(&buildRekor, "rekor", false, "Record the attestation signature in the Rekor transparency log")
+	bundleCreateCmd.Flags().StringVar(&buildRekorURL, "rekor-url", bundle.DefaultRekorURL, "Rekor server used with --rekor")
 	bundleCreateCmd.Flags().StringSliceVarP(&buildMetadata, "metadata", "m", nil, "Bundle metadata (key=value)")
 	bundleCreateCmd.Flags().StringVarP(&buildGovLevel, "governance-level", "g", "", "Governance maturity level (L1-L4)")
+	bundleCreateCmd.Flags().StringVar(&buildBase, "base", "", "Previous bundle to build a delta against (only changed files are packaged)")
 
 	// Bundle gate flags
 	bundleGateCmd.Flags().BoolVar(&gateStrict, "strict", false, "Fail on any error")
@@ -1511,7 +2310,11 @@ func init() {
 	bundleGateCmd.Flags().BoolVar(&gateAttestation, "verify-attestation", false, "Verify cryptographic attestation")
 	bundleGateCmd.Flags().StringVar(&gatePolicy, "policy", "", "Verify against policy file")
 	bundleGateCmd.Flags().StringSliceVar(&gateTrustedKeys, "trusted-key", nil, "Trusted public keys for signature verification")
-	bundleGateCmd.Flags().BoolVar(&gateOffline, "offline", false, "Allow offline verification")
+	bundleGateCmd.Flags().BoolVar(&gateOffline, "offline", false, "Allow offline verification (check the embedded Rekor entry without contacting the log)")
+	bundleGateCmd.Flags().StringVar(&gateRekorURL, "rekor-url", bundle.DefaultRekorURL, "Rekor server attestation entries are checked against")
+	bundleGateCmd.Flags().BoolVar(&gateRequireRekor, "require-rekor", false, "Fail if the attestation has no Rekor entry")
+	bundleGateCmd.Flags().BoolVar(&gateManifestSig, "require-manifest-signature", false, "Fail if the bundle has no valid manifest signature")
+	bundleGateCmd.Flags().StringVar(&gateExitCodes, "exit-code-strategy", gateExitStrategyHighest, "Exit code when several checks fail (first, highest-severity, aggregate)")
 
 	// Bundle apply flags
 	bundleApplyCmd.Flags().StringVarP(&applyTargetDir, "target-dir", "t", "", "Target directory (default: current directory)")
@@ -1519,24 +2322,31 @@ func init() {
 	bundleApplyCmd.Flags().BoolVarP(&applyForce, "force", "f", false, "Overwrite files without prompting")
 	bundleApplyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Auto-confirm all prompts")
 	bundleApplyCmd.Flags().StringSliceVar(&applyExclude, "exclude", nil, "Exclude patterns (e.g., '*.log')")
+	bundleApplyCmd.Flags().StringVar(&applyBase, "base", "", "Base bundle required when applying a delta bundle")
+	bundleApplyCmd.Flags().BoolVar(&applyBackup, "backup", false, "Save overwritten files under .specular/backups so the apply can be undone")
 
 	// Bundle push flags
 	bundlePushCmd.Flags().BoolVar(&pushInsecure, "insecure", false, "Allow insecure registry connections (http)")
 	bundlePushCmd.Flags().StringVar(&pushPlatform, "platform", "", "Target platform (e.g., linux/amd64, linux/arm64)")
 	bundlePushCmd.Flags().StringVar(&pushUserAgent, "user-agent", "", "Custom user agent for registry requests")
+	bundlePushCmd.Flags().BoolVar(&pushNoProgress, "no-progress", false, "Disable the upload progress bar (e.g., for CI logs)")
 
 	// Bundle pull flags
 	bundlePullCmd.Flags().BoolVar(&pullInsecure, "insecure", false, "Allow insecure registry connections (http)")
 	bundlePullCmd.Flags().StringVarP(&pullOutput, "output", "o", "", "Output bundle path (default: derived from reference)")
 	bundlePullCmd.Flags().StringVar(&pullUserAgent, "user-agent", "", "Custom user agent for registry requests")
+	bundlePullCmd.Flags().BoolVar(&pullNoProgress, "no-progress", false, "Disable the download progress bar (e.g., for CI logs)")
 
 	// Bundle approve flags
 	bundleApproveCmd.Flags().StringVarP(&approveRole, "role", "r", "", "Approval role (e.g., pm, lead, security, legal) - REQUIRED")
 	bundleApproveCmd.Flags().StringVarP(&approveUser, "user", "u", "", "Approver identifier (email or username) - REQUIRED")
 	bundleApproveCmd.Flags().StringVarP(&approveComment, "comment", "c", "", "Approval comment")
-	bundleApproveCmd.Flags().StringVar(&approveSigType, "signature-type", "ssh", "Signature type (ssh, gpg)")
+	bundleApproveCmd.Flags().StringVar(&approveSigType, "signature-type", "ssh", "Signature type (ssh, gpg, cosign)")
 	bundleApproveCmd.Flags().StringVarP(&approveKeyPath, "key-path", "k", "", "Path to private key (default: auto-detect)")
+	bundleApproveCmd.Flags().StringVar(&approveCert, "certificate", "", "PEM certificate for the signing key (cosign only)")
 	bundleApproveCmd.Flags().StringVarP(&approveOutput, "output", "o", "", "Output approval file path (default: auto-generated)")
+	bundleApproveCmd.Flags().StringVar(&approveValidFor, "valid-for", "", "Approval validity period from now (e.g., 90d, 720h)")
+	bundleApproveCmd.Flags().StringVar(&approveValidUntil, "valid-until", "", "Approval expiry timestamp (RFC3339 or YYYY-MM-DD)")
 	_ = bundleApproveCmd.MarkFlagRequired("role") //nolint:errcheck // Flag exists, error would be programming error
 	_ = bundleApproveCmd.MarkFlagRequired("user") //nolint:errcheck // Flag exists, error would be programming error
 
@@ -1549,6 +2359,7 @@ func init() {
 	// Bundle diff flags
 	bundleDiffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output diff as JSON")
 	bundleDiffCmd.Flags().BoolVarP(&diffQuiet, "quiet", "q", false, "Quiet mode - only exit code (0=identical, 2=different)")
+	bundleDiffCmd.Flags().StringSliceVar(&diffOnly, "only", nil, "Only report these change categories (files, approvals, attestation, metadata)")
 
 	// Bundle inspect flags
 	bundleInspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Output bundle data as JSON")
@@ -1557,17 +2368,36 @@ func init() {
 	bundleListCmd.Flags().StringVarP(&listDir, "dir", "d", "", "Directory to list bundles from (default: .specular/bundles)")
 	bundleListCmd.Flags().BoolVar(&listJSON, "json", false, "Output bundle list as JSON")
 
+	// Bundle verify-attestation flags
+	bundleVerifyAttestationCmd.Flags().StringSliceVar(&verifyExpectedIdentities, "expected-identity", nil, "Expected signer: fingerprint, glob pattern, or PEM public key file")
+	bundleVerifyAttestationCmd.Flags().StringVar(&verifyTrustedRoot, "trusted-root", "", "PEM file of CA certificates the keyless signing certificate must chain to")
+	bundleVerifyAttestationCmd.Flags().StringVar(&verifyRekorURL, "rekor-url", bundle.DefaultRekorURL, "Rekor server the attestation entry is fetched from")
+	bundleVerifyAttestationCmd.Flags().BoolVar(&verifyOffline, "offline", false, "Check the embedded Rekor entry without contacting the log")
+
+	// Bundle sign-manifest flags
+	bundleSignManifestCmd.Flags().StringVarP(&signManifestUser, "user", "u", "", "Signer identifier (email or username) - REQUIRED")
+	bundleSignManifestCmd.Flags().StringVar(&signManifestSigType, "signature-type", "ssh", "Signature type (ssh, gpg)")
+	bundleSignManifestCmd.Flags().StringVarP(&signManifestKeyPath, "key-path", "k", "", "Path to private key (default: auto-detect)")
+
+	// Bundle sbom flags
+	bundleSBOMCmd.Flags().StringVar(&sbomFormat, "format", "cyclonedx", "SBOM format (cyclonedx, spdx)")
+	bundleSBOMCmd.Flags().StringVarP(&sbomOutput, "output", "o", "", "Output file (default: stdout)")
+
 	// Register subcommands
 	bundleCmd.AddCommand(bundleCreateCmd)
 	bundleCmd.AddCommand(bundleGateCmd)
 	bundleCmd.AddCommand(bundleInspectCmd)
 	bundleCmd.AddCommand(bundleListCmd)
 	bundleCmd.AddCommand(bundleApplyCmd)
+	bundleCmd.AddCommand(bundleRestoreCmd)
 	bundleCmd.AddCommand(bundlePushCmd)
 	bundleCmd.AddCommand(bundlePullCmd)
 	bundleCmd.AddCommand(bundleApproveCmd)
 	bundleCmd.AddCommand(bundleApprovalStatusCmd)
 	bundleCmd.AddCommand(bundleDiffCmd)
+	bundleCmd.AddCommand(bundleVerifyAttestationCmd)
+	bundleCmd.AddCommand(bundleSignManifestCmd)
+	bundleCmd.AddCommand(bundleSBOMCmd)
 
 	// Register bundle command with root
 	rootCmd.AddCommand(bundleCmd)
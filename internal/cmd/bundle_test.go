@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/felixgeelhaar/specular/internal/bundle"
 )
@@ -144,6 +145,12 @@ func TestCheckRequiredRoles(t *testing.T) {
 			Signature: "mock-signature",
 		}
 	}
+	expiredApproval := func(role string) *bundle.Approval {
+		expiry := time.Now().Add(-time.Hour)
+		approval := mockApproval(role)
+		approval.ExpiresAt = &expiry
+		return approval
+	}
 
 	tests := []struct {
 		name          string
@@ -164,6 +171,15 @@ func TestCheckRequiredRoles(t *testing.T) {
 			verifiedRoles: map[string]*bundle.Approval{},
 			wantErr:       false,
 		},
+		{
+			name:          "expired approval does not satisfy role",
+			requiredRoles: []string{"security"},
+			verifiedRoles: map[string]*bundle.Approval{
+				"security": expiredApproval("security"),
+			},
+			wantErr:     true,
+			errContains: "security",
+		},
 		{
 			name:          "single required role satisfied",
 			requiredRoles: []string{"developer"},
@@ -550,3 +566,184 @@ func TestCheckRequiredRolesWithComplexScenario(t *testing.T) {
 
 // Note: Example function removed because parseMetadataFlags is unexported.
 // See TestParseMetadataFlagsWithRealWorldExamples for usage examples.
+
+// TestGateExitCode tests exit code selection when several gate checks fail
+func TestGateExitCode(t *testing.T) {
+	errs := []bundle.ValidationError{
+		{Code: "MISSING_APPROVAL", Message: "missing pm approval"},
+		{Code: "POLICY_VIOLATION", Message: "policy violated"},
+		{Code: "MISSING_APPROVAL", Message: "missing security approval"},
+		{Code: "DRIFT_DETECTED", Message: "drift"},
+	}
+
+	failures := categorizeGateFailures(errs)
+	if len(failures) != 3 {
+		t.Fatalf("expected 3 failure categories, got %d", len(failures))
+	}
+	if failures[0].ExitCode != 20 || failures[2].ExitCode != 40 || failures[2].Count != 2 {
+		t.Errorf("unexpected categories: %+v", failures)
+	}
+
+	tests := []struct {
+		name     string
+		errs     []bundle.ValidationError
+		strategy string
+		want     int
+	}{
+		{name: "highest severity", errs: errs, strategy: gateExitStrategyHighest, want: 20},
+		{name: "first failure", errs: errs, strategy: gateExitStrategyFirst, want: 40},
+		{name: "aggregate multiple categories", errs: errs, strategy: gateExitStrategyAggregate, want: 60},
+		{
+			name:     "aggregate single category",
+			errs:     []bundle.ValidationError{{Code: "APPROVAL_FAILED"}, {Code: "MISSING_APPROVAL"}},
+			strategy: gateExitStrategyAggregate,
+			want:     40,
+		},
+		{
+			name:     "unclassified error",
+			errs:     []bundle.ValidationError{{Code: "CHECKSUM_MISMATCH"}},
+			strategy: gateExitStrategyHighest,
+			want:     60,
+		},
+		{name: "no errors", errs: nil, strategy: gateExitStrategyHighest, want: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gateExitCode(categorizeGateFailures(tt.errs), tt.strategy)
+			if got != tt.want {
+				t.Errorf("gateExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseApprovalExpiry tests conversion of --valid-for and --valid-until
+func TestParseApprovalExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		validFor   string
+		validUntil string
+		want       *time.Time
+		wantErr    bool
+	}{
+		{name: "no expiry"},
+		{name: "days", validFor: "90d", want: ptrTime(now.Add(90 * 24 * time.Hour))},
+		{name: "go duration", validFor: "36h", want: ptrTime(now.Add(36 * time.Hour))},
+		{name: "rfc3339", validUntil: "2025-04-01T00:00:00Z", want: ptrTime(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "date covers whole day", validUntil: "2025-04-01", want: ptrTime(time.Date(2025, 4, 1, 23, 59, 59, 0, time.UTC))},
+		{name: "both flags", validFor: "1d", validUntil: "2025-04-01", wantErr: true},
+		{name: "bad days", validFor: "xd", wantErr: true},
+		{name: "negative duration", validFor: "-1h", wantErr: true},
+		{name: "past timestamp", validUntil: "2025-01-01", wantErr: true},
+		{name: "bad timestamp", validUntil: "next week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseApprovalExpiry(tt.validFor, tt.validUntil, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseApprovalExpiry() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseApprovalExpiry() unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("parseApprovalExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// TestBundleDiffOnlyApprovals tests that --only approvals reports approval
+// changes and ignores file changes for the --quiet exit code
+func TestBundleDiffOnlyApprovals(t *testing.T) {
+	base := &bundle.Bundle{
+		Manifest: &bundle.Manifest{
+			ID:      "acme/api",
+			Version: "1.0.0",
+			Files:   []bundle.FileEntry{{Path: "spec.yaml", Checksum: "aaaa"}},
+		},
+	}
+	filesChanged := &bundle.Bundle{
+		Manifest: &bundle.Manifest{
+			ID:      "acme/api",
+			Version: "1.0.0",
+			Files:   []bundle.FileEntry{{Path: "spec.yaml", Checksum: "bbbb"}},
+		},
+	}
+	approvalsChanged := &bundle.Bundle{
+		Manifest:  base.Manifest,
+		Approvals: []*bundle.Approval{{Role: "security", User: "sam@example.com"}},
+	}
+
+	categories, err := bundle.ParseDiffCategories([]string{"approvals"})
+	if err != nil {
+		t.Fatalf("ParseDiffCategories() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		other    *bundle.Bundle
+		wantExit int
+	}{
+		{"file changes ignored", filesChanged, 0},
+		{"approval changes reported", approvalsChanged, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := bundle.DiffBundles(base, tt.other)
+			if err != nil {
+				t.Fatalf("DiffBundles() error = %v", err)
+			}
+			if !result.HasChanges() {
+				t.Fatal("unfiltered diff should report changes")
+			}
+
+			filtered := result.Filter(categories...)
+			if got := bundleDiffExitCode(filtered); got != tt.wantExit {
+				t.Errorf("bundleDiffExitCode() = %d, want %d", got, tt.wantExit)
+			}
+			if tt.wantExit == 2 && len(filtered.ApprovalsAdded) != 1 {
+				t.Errorf("expected 1 approval added, got %d", len(filtered.ApprovalsAdded))
+			}
+			if len(filtered.FilesModified) != 0 {
+				t.Errorf("file changes should be filtered out, got %v", filtered.FilesModified)
+			}
+		})
+	}
+}
+
+func TestRequireRootForCertifiedIdentities(t *testing.T) {
+	tests := []struct {
+		name        string
+		identities  []string
+		trustedRoot string
+		wantErr     bool
+	}{
+		{name: "fingerprint without root", identities: []string{"sha256:abc123"}},
+		{name: "email without root", identities: []string{"alice@example.com"}, wantErr: true},
+		{name: "URI pattern without root", identities: []string{"sha256:abc123", "https://github.com/acme/app/*"}, wantErr: true},
+		{name: "email with root", identities: []string{"alice@example.com"}, trustedRoot: "fulcio-root.pem"},
+		{name: "no identities", identities: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireRootForCertifiedIdentities(tt.identities, tt.trustedRoot)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("requireRootForCertifiedIdentities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
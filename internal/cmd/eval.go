@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -55,6 +58,18 @@ Available scenarios:
   security     - Security scan + policy check
   performance  - Performance benchmarks
 
+Scenarios can be defined or replaced in .specular/eval.yaml as named lists
+of shell steps:
+
+  scenarios:
+    node:
+      steps:
+        - name: unit tests
+          run: npm test
+        - name: audit
+          run: npm audit --audit-level=high
+          exit_codes: [0, 1]
+
 If no scenario is specified, 'smoke' is run by default.`,
 	RunE: runEvalRun,
 }
@@ -79,31 +94,35 @@ var evalDriftCmd = &cobra.Command{
 - Test execution and coverage analysis
 - Security scanning
 
-Results are output in SARIF format for integration with CI/CD tools.`,
+Results are output in SARIF format for integration with CI/CD tools.
+Use --report-format to also write JSON or JUnit XML reports.`,
 	RunE: runEvalDrift,
 }
 
 func runEvalRun(cmd *cobra.Command, args []string) error {
+	defaults := ux.NewPathDefaults()
+
+	// Built-in scenarios, overlaid with those in .specular/eval.yaml
+	scenarios, err := eval.LoadScenarios(defaults.EvalFile())
+	if err != nil {
+		return ux.FormatError(err, "loading eval scenarios")
+	}
+
 	// Determine scenario
-	scenario := "smoke" // default
+	name := "smoke" // default
 	if len(args) > 0 {
-		scenario = args[0]
+		name = args[0]
 	} else if cmd.Flags().Changed("scenario") {
-		scenario = cmd.Flags().Lookup("scenario").Value.String()
+		name = cmd.Flags().Lookup("scenario").Value.String()
 	}
 
 	// Validate scenario
-	validScenarios := map[string]bool{
-		"smoke":       true,
-		"integration": true,
-		"security":    true,
-		"performance": true,
-	}
-	if !validScenarios[scenario] {
-		return ValidationError("scenario", scenario, "smoke, integration, security, performance")
+	scenario, ok := scenarios[name]
+	if !ok {
+		return ValidationError("scenario", name, strings.Join(eval.ScenarioNames(scenarios), ", "))
 	}
 
-	fmt.Printf("Running evaluation scenario: %s\n\n", scenario)
+	fmt.Printf("Running evaluation scenario: %s\n\n", name)
 
 	// Load policy if provided
 	policyFile := cmd.Flags().Lookup("policy").Value.String()
@@ -116,176 +135,32 @@ func runEvalRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Execute scenario-specific checks
-	passed := 0
-	failed := 0
-	var checks []string
-
-	switch scenario {
-	case "smoke":
-		checks = []string{"go vet", "go build", "basic tests"}
-		fmt.Println("=== Smoke Test Scenario ===")
-		fmt.Println("Running basic health checks...")
-		fmt.Println()
-
-		// 1. go vet
-		fmt.Printf("1. Running go vet...\n")
-		vetCmd := exec.Command("go", "vet", "./...")
-		if vetErr := vetCmd.Run(); vetErr != nil {
-			fmt.Printf("   ✗ go vet failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ go vet passed\n")
-			passed++
-		}
-
-		// 2. go build
-		fmt.Printf("2. Running go build...\n")
-		buildCmd := exec.Command("go", "build", "./...")
-		if buildErr := buildCmd.Run(); buildErr != nil {
-			fmt.Printf("   ✗ go build failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ go build passed\n")
-			passed++
-		}
-
-		// 3. Basic tests
-		fmt.Printf("3. Running basic tests...\n")
-		testCmd := exec.Command("go", "test", "./...", "-short", "-timeout=30s")
-		if testErr := testCmd.Run(); testErr != nil {
-			fmt.Printf("   ✗ tests failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ tests passed\n")
-			passed++
-		}
-
-	case "integration":
-		checks = []string{"go vet", "all tests", "coverage check"}
-		fmt.Println("=== Integration Test Scenario ===")
-		fmt.Println("Running full integration tests...")
-		fmt.Println()
-
-		// 1. go vet
-		fmt.Printf("1. Running go vet...\n")
-		vetCmd := exec.Command("go", "vet", "./...")
-		if vetErr := vetCmd.Run(); vetErr != nil {
-			fmt.Printf("   ✗ go vet failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ go vet passed\n")
-			passed++
-		}
-
-		// 2. All tests (no -short flag)
-		fmt.Printf("2. Running all tests...\n")
-		testCmd := exec.Command("go", "test", "./...", "-timeout=5m")
-		if testErr := testCmd.Run(); testErr != nil {
-			fmt.Printf("   ✗ tests failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ tests passed\n")
-			passed++
-		}
-
-		// 3. Coverage check
-		fmt.Printf("3. Checking test coverage...\n")
-		coverCmd := exec.Command("go", "test", "./...", "-cover")
-		if coverErr := coverCmd.Run(); coverErr != nil {
-			fmt.Printf("   ✗ coverage check failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ coverage check passed\n")
-			passed++
-		}
-
-	case "security":
-		checks = []string{"go vet", "gosec scan", "policy check"}
-		fmt.Println("=== Security Test Scenario ===")
-		fmt.Println("Running security scans and policy checks...")
-		fmt.Println()
-
-		// 1. go vet
-		fmt.Printf("1. Running go vet...\n")
-		vetCmd := exec.Command("go", "vet", "./...")
-		if vetErr := vetCmd.Run(); vetErr != nil {
-			fmt.Printf("   ✗ go vet failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ go vet passed\n")
-			passed++
-		}
+	fmt.Printf("=== %s%s Test Scenario ===\n", strings.ToUpper(name[:1]), name[1:])
+	if scenario.Description != "" {
+		fmt.Println(scenario.Description)
+	}
+	fmt.Println()
 
-		// 2. gosec scan
-		fmt.Printf("2. Running gosec security scan...\n")
-		gosecCmd := exec.Command("gosec", "./...")
-		gosecErr := gosecCmd.Run()
-		if gosecErr != nil {
-			// Check if gosec is not installed
-			if strings.Contains(gosecErr.Error(), "not found") || strings.Contains(gosecErr.Error(), "executable file not found") {
-				fmt.Printf("   ⊘ gosec not installed (skipping)\n")
-			} else {
-				fmt.Printf("   ✗ gosec scan failed\n")
-				failed++
-			}
-		} else {
-			fmt.Printf("   ✓ gosec scan passed\n")
-			passed++
-		}
+	report := eval.RunScenario(cmd.Context(), "", scenario, os.Stdout)
 
-		// 3. Policy check
-		fmt.Printf("3. Checking policy compliance...\n")
+	// The security scenario also reports policy compliance
+	if name == "security" {
+		fmt.Printf("%d. Checking policy compliance...\n", len(scenario.Steps)+1)
 		if pol != nil {
 			fmt.Printf("   ✓ Policy loaded\n")
 			fmt.Printf("   • Docker required: %v\n", pol.Execution.Docker.Required)
 			fmt.Printf("   • Security scans: secrets=%v deps=%v\n", pol.Security.SecretsScan, pol.Security.DepScan)
-			passed++
+			report.TotalPassed++
 		} else {
 			fmt.Printf("   ⊘ No policy file (skipping)\n")
+			report.TotalSkipped++
 		}
-
-	case "performance":
-		checks = []string{"benchmark tests", "memory profiling", "CPU profiling"}
-		fmt.Println("=== Performance Test Scenario ===")
-		fmt.Println("Running performance benchmarks...")
-		fmt.Println()
-
-		// 1. Benchmark tests
-		fmt.Printf("1. Running benchmark tests...\n")
-		benchCmd := exec.Command("go", "test", "./...", "-bench=.", "-benchtime=1s", "-run=^$")
-		if benchErr := benchCmd.Run(); benchErr != nil {
-			fmt.Printf("   ✗ benchmarks failed\n")
-			failed++
-		} else {
-			fmt.Printf("   ✓ benchmarks passed\n")
-			passed++
-		}
-
-		// 2. Memory profiling check
-		fmt.Printf("2. Checking memory profiling support...\n")
-		fmt.Printf("   ✓ Memory profiling available (use -memprofile flag)\n")
-		passed++
-
-		// 3. CPU profiling check
-		fmt.Printf("3. Checking CPU profiling support...\n")
-		fmt.Printf("   ✓ CPU profiling available (use -cpuprofile flag)\n")
-		passed++
 	}
 
-	// Summary
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Evaluation Summary (%s scenario):\n", scenario)
-	fmt.Printf("  ✓ Passed: %d\n", passed)
-	if failed > 0 {
-		fmt.Printf("  ✗ Failed: %d\n", failed)
-	}
-	fmt.Printf("  Total:   %d\n", len(checks))
-	fmt.Println(strings.Repeat("=", 50))
+	printScenarioSummary(name, report)
 
-	if failed > 0 {
-		return fmt.Errorf("evaluation failed with %d errors", failed)
+	if report.TotalFailed > 0 {
+		return fmt.Errorf("evaluation failed with %d errors", report.TotalFailed)
 	}
 
 	fmt.Println("\n✓ Evaluation passed")
@@ -436,14 +311,22 @@ func runEvalDrift(cmd *cobra.Command, args []string) error {
 	specFile := cmd.Flags().Lookup("spec").Value.String()
 	policyFile := cmd.Flags().Lookup("policy").Value.String()
 	reportFile := cmd.Flags().Lookup("report").Value.String()
+	reportFormat := cmd.Flags().Lookup("report-format").Value.String()
 	failOnDrift := cmd.Flags().Lookup("fail-on-drift").Value.String() == "true"
 	projectRoot := cmd.Flags().Lookup("project-root").Value.String()
 	apiSpecPath := cmd.Flags().Lookup("api-spec").Value.String()
 	ignoreGlobs, _ := cmd.Flags().GetStringSlice("ignore") //nolint:errcheck // Acceptable to ignore array return
+	language := cmd.Flags().Lookup("language").Value.String()
+	noCache := cmd.Flags().Lookup("no-cache").Value.String() == "true"
 	resume := cmd.Flags().Lookup("resume").Value.String() == "true"
 	checkpointDir := cmd.Flags().Lookup("checkpoint-dir").Value.String()
 	checkpointID := cmd.Flags().Lookup("checkpoint-id").Value.String()
 
+	reportFormats, err := drift.ParseReportFormats(reportFormat)
+	if err != nil {
+		return err
+	}
+
 	// Use smart defaults if not changed
 	if !cmd.Flags().Changed("plan") {
 		planFile = defaults.PlanFile()
@@ -552,6 +435,15 @@ func runEvalDrift(cmd *cobra.Command, args []string) error {
 		return ux.FormatError(err, "loading spec file")
 	}
 
+	// Reuse file hashes and parsed routes from the previous scan
+	var driftCache *drift.Cache
+	if !noCache {
+		driftCache, err = drift.LoadCache(filepath.Join(defaults.CacheDir(), "drift"), lockFile)
+		if err != nil {
+			fmt.Printf("Warning: drift cache disabled: %v\n", err)
+		}
+	}
+
 	// Run eval gate if policy is provided
 	if policyFile != "" && cpState.Tasks["quality-gate"].Status != "completed" {
 		progressIndicator.UpdateTask("quality-gate", "running", nil)
@@ -621,95 +513,59 @@ func runEvalDrift(cmd *cobra.Command, args []string) error {
 		fmt.Println("✓ Quality gate check already completed (skipping)")
 	}
 
-	// Detect plan drift
-	if cpState.Tasks["plan-drift"].Status != "completed" {
-		progressIndicator.UpdateTask("plan-drift", "running", nil)
-		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
-		}
-
-		fmt.Println("Detecting plan drift...")
-		planDrift := drift.DetectPlanDrift(lock, p)
+	// Detect plan, code, and infrastructure drift concurrently. Phases
+	// completed in a previous run reuse the findings stored in the checkpoint.
+	detectors := newDriftDetectors(p, lock, s, driftOptions{
+		policyFile:  policyFile,
+		projectRoot: projectRoot,
+		apiSpecPath: apiSpecPath,
+		ignoreGlobs: ignoreGlobs,
+		language:    language,
+		cache:       driftCache,
+	})
 
-		progressIndicator.UpdateTask("plan-drift", "completed", nil)
-		cpState.SetMetadata("plan_drift_count", fmt.Sprintf("%d", len(planDrift)))
-		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
+	findings := make(map[string][]drift.Finding, len(detectors))
+	var pending []drift.Detector
+	for _, detector := range detectors {
+		if cached, ok := checkpointFindings(cpState, detector.Name); ok {
+			findings[detector.Name] = cached
+			fmt.Printf("✓ %s check already completed (skipping)\n", detector.Name)
+			continue
 		}
-	} else {
-		fmt.Println("✓ Plan drift check already completed (skipping)")
+		progressIndicator.UpdateTask(detector.Name, "running", nil)
+		pending = append(pending, detector)
 	}
 
-	// Detect code drift
-	if cpState.Tasks["code-drift"].Status != "completed" {
-		progressIndicator.UpdateTask("code-drift", "running", nil)
+	if len(pending) > 0 {
 		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
 			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
 		}
 
-		fmt.Println("Detecting code drift...")
-		codeDrift := drift.DetectCodeDrift(s, lock, drift.CodeDriftOptions{
-			ProjectRoot: projectRoot,
-			APISpecPath: apiSpecPath,
-			IgnoreGlobs: ignoreGlobs,
+		fmt.Printf("Detecting drift (%d checks in parallel)...\n", len(pending))
+		_, detectErr := drift.RunDetectors(cmd.Context(), pending, func(result drift.DetectorResult) {
+			if result.Err != nil {
+				progressIndicator.UpdateTask(result.Name, "failed", result.Err)
+			} else {
+				findings[result.Name] = result.Findings
+				storeCheckpointFindings(cpState, result.Name, result.Findings)
+				progressIndicator.UpdateTask(result.Name, "completed", nil)
+			}
+			if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
+				fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
+			}
 		})
-
-		progressIndicator.UpdateTask("code-drift", "completed", nil)
-		cpState.SetMetadata("code_drift_count", fmt.Sprintf("%d", len(codeDrift)))
-		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
+		if detectErr != nil {
+			return fmt.Errorf("drift detection failed: %w", detectErr)
 		}
-	} else {
-		fmt.Println("✓ Code drift check already completed (skipping)")
 	}
 
-	// Detect infrastructure drift
-	var infraDrift []drift.Finding
-	if cpState.Tasks["infra-drift"].Status != "completed" {
-		progressIndicator.UpdateTask("infra-drift", "running", nil)
-		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
-		}
-
-		fmt.Println("Detecting infrastructure drift...")
-		if policyFile != "" {
-			polInfra, polInfraErr := policy.LoadPolicy(policyFile)
-			if polInfraErr != nil {
-				progressIndicator.UpdateTask("infra-drift", "failed", polInfraErr)
-				if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-					fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
-				}
-				return fmt.Errorf("failed to load policy: %w", polInfraErr)
-			}
-
-			// Build task images map from plan
-			// Note: Currently plan.Task doesn't have Image field, so this will be empty
-			// This is a placeholder for future enhancement when task images are tracked
-			taskImages := make(map[string]string)
-			// Future: when plan.Task has Image field, populate taskImages here
-
-			infraDrift = drift.DetectInfraDrift(drift.InfraDriftOptions{
-				Policy:     polInfra,
-				TaskImages: taskImages,
-			})
-		}
-
-		progressIndicator.UpdateTask("infra-drift", "completed", nil)
-		cpState.SetMetadata("infra_drift_count", fmt.Sprintf("%d", len(infraDrift)))
-		if saveErr := checkpointMgr.Save(cpState); saveErr != nil {
-			fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
-		}
-	} else {
-		fmt.Println("✓ Infrastructure drift check already completed (skipping)")
+	if errCache := driftCache.Save(); errCache != nil {
+		fmt.Printf("Warning: failed to save drift cache: %v\n", errCache)
 	}
 
-	// Get drift results from checkpoint metadata if checks were skipped
-	planDrift := drift.DetectPlanDrift(lock, p)
-	codeDrift := drift.DetectCodeDrift(s, lock, drift.CodeDriftOptions{
-		ProjectRoot: projectRoot,
-		APISpecPath: apiSpecPath,
-		IgnoreGlobs: ignoreGlobs,
-	})
+	planDrift := findings["plan-drift"]
+	codeDrift := findings["code-drift"]
+	infraDrift := findings["infra-drift"]
 
 	// Generate report
 	progressIndicator.UpdateTask("report-generation", "running", nil)
@@ -724,43 +580,21 @@ func runEvalDrift(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Warning: failed to save checkpoint: %v\n", saveErr)
 	}
 
-	// Print summary
-	fmt.Printf("\nDrift Detection Summary:\n")
-	fmt.Printf("  Total Findings: %d\n", report.Summary.TotalFindings)
-	fmt.Printf("  Errors:        %d\n", report.Summary.Errors)
-	fmt.Printf("  Warnings:      %d\n", report.Summary.Warnings)
-	fmt.Printf("  Info:          %d\n", report.Summary.Info)
-	fmt.Println()
-
-	// Print findings
-	if len(planDrift) > 0 {
-		fmt.Println("Plan Drift:")
-		for _, f := range planDrift {
-			fmt.Printf("  [%s] %s: %s\n", f.Severity, f.Code, f.Message)
-		}
-	}
+	printDriftReport(report)
 
-	if len(codeDrift) > 0 {
-		fmt.Println("\nCode Drift:")
-		for _, f := range codeDrift {
-			fmt.Printf("  [%s] %s: %s (feature: %s)\n", f.Severity, f.Code, f.Message, f.FeatureID)
+	// Write a report per requested format. Several formats, or a format
+	// other than SARIF without --report, get the format's own extension.
+	for _, format := range reportFormats {
+		path := reportFile
+		if len(reportFormats) > 1 || !cmd.Flags().Changed("report") {
+			path = drift.ReportPath(reportFile, format)
 		}
-	}
-
-	if len(infraDrift) > 0 {
-		fmt.Println("\nInfrastructure Drift:")
-		for _, f := range infraDrift {
-			fmt.Printf("  [%s] %s: %s\n", f.Severity, f.Code, f.Message)
+		if errReport := drift.SaveReport(report, format, path); errReport != nil {
+			return fmt.Errorf("failed to save %s report: %w", strings.ToUpper(format), errReport)
 		}
+		fmt.Printf("✓ %s report saved to %s\n", strings.ToUpper(format), path)
 	}
 
-	// Generate SARIF output
-	sarif := report.ToSARIF()
-	if errSARIF := drift.SaveSARIF(sarif, reportFile); errSARIF != nil {
-		return fmt.Errorf("failed to save SARIF report: %w", errSARIF)
-	}
-	fmt.Printf("✓ SARIF report saved to %s\n", reportFile)
-
 	// Mark evaluation as completed
 	cpState.Status = "completed"
 	if errCP := checkpointMgr.Save(cpState); errCP != nil {
@@ -793,6 +627,152 @@ func runEvalDrift(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkpointFindings returns the findings a completed drift check stored in
+// the checkpoint
+func checkpointFindings(state *checkpoint.State, taskID string) ([]drift.Finding, bool) {
+	if state.Tasks[taskID].Status != "completed" {
+		return nil, false
+	}
+
+	data, ok := state.GetMetadata(driftMetadataKey(taskID, "findings"))
+	if !ok {
+		return nil, false
+	}
+
+	var findings []drift.Finding
+	if err := json.Unmarshal([]byte(data), &findings); err != nil {
+		return nil, false
+	}
+	return findings, true
+}
+
+// storeCheckpointFindings records a drift check's findings and count in the
+// checkpoint so a resumed run can reuse them
+func storeCheckpointFindings(state *checkpoint.State, taskID string, findings []drift.Finding) {
+	state.SetMetadata(driftMetadataKey(taskID, "count"), fmt.Sprintf("%d", len(findings)))
+
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return
+	}
+	state.SetMetadata(driftMetadataKey(taskID, "findings"), string(data))
+}
+
+// driftMetadataKey converts a task ID such as "plan-drift" to a metadata key
+// such as "plan_drift_count"
+func driftMetadataKey(taskID, suffix string) string {
+	return strings.ReplaceAll(taskID, "-", "_") + "_" + suffix
+}
+
+// printScenarioSummary prints the totals of an evaluation scenario run
+func printScenarioSummary(name string, report *eval.GateReport) {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("Evaluation Summary (%s scenario):\n", name)
+	fmt.Printf("  ✓ Passed:  %d\n", report.TotalPassed)
+	if report.TotalFailed > 0 {
+		fmt.Printf("  ✗ Failed:  %d\n", report.TotalFailed)
+	}
+	if report.TotalSkipped > 0 {
+		fmt.Printf("  ⊘ Skipped: %d\n", report.TotalSkipped)
+	}
+	fmt.Printf("  Total:     %d\n", report.TotalPassed+report.TotalFailed+report.TotalSkipped)
+	fmt.Printf("  Duration:  %s\n", report.Duration.Round(time.Millisecond))
+	fmt.Println(strings.Repeat("=", 50))
+}
+
+// driftOptions configures the drift detectors shared by eval drift and
+// eval watch
+type driftOptions struct {
+	policyFile  string
+	projectRoot string
+	apiSpecPath string
+	ignoreGlobs []string
+	language    string
+	cache       *drift.Cache
+}
+
+// newDriftDetectors returns the plan, code, and infrastructure drift
+// detectors
+func newDriftDetectors(p *plan.Plan, lock *spec.SpecLock, s *spec.ProductSpec, opts driftOptions) []drift.Detector {
+	return []drift.Detector{
+		{
+			Name: "plan-drift",
+			Detect: func(ctx context.Context) ([]drift.Finding, error) {
+				return drift.DetectPlanDrift(lock, p), nil
+			},
+		},
+		{
+			Name: "code-drift",
+			Detect: func(ctx context.Context) ([]drift.Finding, error) {
+				return drift.DetectCodeDrift(s, lock, drift.CodeDriftOptions{
+					ProjectRoot: opts.projectRoot,
+					APISpecPath: opts.apiSpecPath,
+					IgnoreGlobs: opts.ignoreGlobs,
+					Language:    opts.language,
+					Cache:       opts.cache,
+				}), nil
+			},
+		},
+		{
+			Name: "infra-drift",
+			Detect: func(ctx context.Context) ([]drift.Finding, error) {
+				if opts.policyFile == "" {
+					return nil, nil
+				}
+				polInfra, polInfraErr := policy.LoadPolicy(opts.policyFile)
+				if polInfraErr != nil {
+					return nil, fmt.Errorf("failed to load policy: %w", polInfraErr)
+				}
+
+				// Build task images map from plan
+				// Note: Currently plan.Task doesn't have Image field, so this will be empty
+				// This is a placeholder for future enhancement when task images are tracked
+				taskImages := make(map[string]string)
+				// Future: when plan.Task has Image field, populate taskImages here
+
+				return drift.DetectInfraDrift(drift.InfraDriftOptions{
+					Policy:     polInfra,
+					TaskImages: taskImages,
+				}), nil
+			},
+		},
+	}
+}
+
+// printDriftReport prints the drift summary and findings
+func printDriftReport(report *drift.Report) {
+	// Print summary
+	fmt.Printf("\nDrift Detection Summary:\n")
+	fmt.Printf("  Total Findings: %d\n", report.Summary.TotalFindings)
+	fmt.Printf("  Errors:        %d\n", report.Summary.Errors)
+	fmt.Printf("  Warnings:      %d\n", report.Summary.Warnings)
+	fmt.Printf("  Info:          %d\n", report.Summary.Info)
+	fmt.Println()
+
+	// Print findings
+	if len(report.PlanDrift) > 0 {
+		fmt.Println("Plan Drift:")
+		for _, f := range report.PlanDrift {
+			fmt.Printf("  [%s] %s: %s\n", f.Severity, f.Code, f.Message)
+		}
+	}
+
+	if len(report.CodeDrift) > 0 {
+		fmt.Println("\nCode Drift:")
+		for _, f := range report.CodeDrift {
+			fmt.Printf("  [%s] %s: %s (feature: %s)\n", f.Severity, f.Code, f.Message, f.FeatureID)
+		}
+	}
+
+	if len(report.InfraDrift) > 0 {
+		fmt.Println("\nInfrastructure Drift:")
+		for _, f := range report.InfraDrift {
+			fmt.Printf("  [%s] %s: %s\n", f.Severity, f.Code, f.Message)
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(evalCmd)
 	evalCmd.AddCommand(evalRunCmd)
@@ -804,18 +784,21 @@ func init() {
 	evalCmd.Flags().String("lock", ".specular/spec.lock.json", "SpecLock file")
 	evalCmd.Flags().String("spec", ".specular/spec.yaml", "Spec file for code drift detection")
 	evalCmd.Flags().String("policy", "", "Policy file for infrastructure drift detection")
-	evalCmd.Flags().String("report", "drift.sarif", "Output report file (SARIF format)")
+	evalCmd.Flags().String("report", "drift.sarif", "Output report file")
+	evalCmd.Flags().String("report-format", "sarif", "Report formats, comma-separated (sarif, json, junit)")
 	evalCmd.Flags().Bool("fail-on-drift", false, "Exit with error if drift is detected")
 	evalCmd.Flags().String("project-root", ".", "Project root directory")
 	evalCmd.Flags().String("api-spec", "", "Path to OpenAPI spec file")
 	evalCmd.Flags().StringSlice("ignore", []string{}, "Glob patterns to ignore (e.g., *.test.js)")
+	evalCmd.Flags().String("language", "", "Check code routes against the API spec (javascript, typescript)")
+	evalCmd.Flags().Bool("no-cache", false, "Rescan all files instead of reusing the drift cache")
 	evalCmd.Flags().Bool("resume", false, "Resume from previous checkpoint")
 	evalCmd.Flags().String("checkpoint-dir", ".specular/checkpoints", "Directory for checkpoints")
 	evalCmd.Flags().String("checkpoint-id", "", "Checkpoint ID (auto-generated if not provided)")
 	evalCmd.Flags().Bool("keep-checkpoint", false, "Keep checkpoint after successful completion")
 
 	// eval run flags
-	evalRunCmd.Flags().String("scenario", "smoke", "Evaluation scenario to run")
+	evalRunCmd.Flags().String("scenario", "smoke", "Evaluation scenario to run (built-in or from .specular/eval.yaml)")
 	evalRunCmd.Flags().String("policy", ".specular/policy.yaml", "Policy file for security scenario")
 
 	// eval rules flags
@@ -827,11 +810,14 @@ func init() {
 	evalDriftCmd.Flags().String("lock", ".specular/spec.lock.json", "SpecLock file")
 	evalDriftCmd.Flags().String("spec", ".specular/spec.yaml", "Spec file for code drift detection")
 	evalDriftCmd.Flags().String("policy", "", "Policy file for infrastructure drift detection")
-	evalDriftCmd.Flags().String("report", "drift.sarif", "Output report file (SARIF format)")
+	evalDriftCmd.Flags().String("report", "drift.sarif", "Output report file")
+	evalDriftCmd.Flags().String("report-format", "sarif", "Report formats, comma-separated (sarif, json, junit)")
 	evalDriftCmd.Flags().Bool("fail-on-drift", false, "Exit with error if drift is detected")
 	evalDriftCmd.Flags().String("project-root", ".", "Project root directory")
 	evalDriftCmd.Flags().String("api-spec", "", "Path to OpenAPI spec file")
 	evalDriftCmd.Flags().StringSlice("ignore", []string{}, "Glob patterns to ignore (e.g., *.test.js)")
+	evalDriftCmd.Flags().String("language", "", "Check code routes against the API spec (javascript, typescript)")
+	evalDriftCmd.Flags().Bool("no-cache", false, "Rescan all files instead of reusing the drift cache")
 	evalDriftCmd.Flags().Bool("resume", false, "Resume from previous checkpoint")
 	evalDriftCmd.Flags().String("checkpoint-dir", ".specular/checkpoints", "Directory for checkpoints")
 	evalDriftCmd.Flags().String("checkpoint-id", "", "Checkpoint ID (auto-generated if not provided)")
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/drift"
+	"github.com/felixgeelhaar/specular/internal/eval"
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/internal/ux"
+	"github.com/felixgeelhaar/specular/internal/watch"
+)
+
+var evalWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Re-run drift detection or a scenario when files change",
+	Long: `Watch the project for changes and re-run drift detection, or an evaluation
+scenario with --scenario, each time files are saved.
+
+Files matched by .gitignore or --ignore are not watched. Rapid changes are
+debounced into a single run, and drift detection reuses the drift cache so
+only changed files are rescanned. Press Ctrl+C to stop.
+
+Examples:
+  # Re-run drift detection on every change
+  specular eval watch
+
+  # Re-run the smoke scenario instead
+  specular eval watch --scenario smoke --ignore "*.tmp"`,
+	RunE: runEvalWatch,
+}
+
+func runEvalWatch(cmd *cobra.Command, args []string) error {
+	defaults := ux.NewPathDefaults()
+	scenarioName := cmd.Flags().Lookup("scenario").Value.String()
+	projectRoot := cmd.Flags().Lookup("project-root").Value.String()
+	ignoreGlobs, _ := cmd.Flags().GetStringSlice("ignore") //nolint:errcheck // Acceptable to ignore array return
+	debounce, _ := cmd.Flags().GetDuration("debounce")     //nolint:errcheck // Flag is registered as a duration
+
+	var run func(ctx context.Context) bool
+	if scenarioName != "" {
+		scenarios, err := eval.LoadScenarios(defaults.EvalFile())
+		if err != nil {
+			return ux.FormatError(err, "loading eval scenarios")
+		}
+		scenario, ok := scenarios[scenarioName]
+		if !ok {
+			return ValidationError("scenario", scenarioName, strings.Join(eval.ScenarioNames(scenarios), ", "))
+		}
+		run = func(ctx context.Context) bool {
+			report := eval.RunScenario(ctx, projectRoot, scenario, os.Stdout)
+			printScenarioSummary(scenarioName, report)
+			return report.AllPassed
+		}
+	} else {
+		opts, err := newWatchDriftOptions(cmd, defaults)
+		if err != nil {
+			return err
+		}
+		run = func(ctx context.Context) bool {
+			return opts.run(ctx)
+		}
+	}
+
+	watcher, err := watch.New(watch.Options{
+		Root:        projectRoot,
+		IgnoreGlobs: ignoreGlobs,
+		Debounce:    debounce,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", projectRoot, err)
+	}
+	defer watcher.Close() //nolint:errcheck // Deferred close is best effort
+
+	onChange := func(ctx context.Context, changed []string) {
+		clearTerminal()
+		fmt.Printf("[%s] ", time.Now().Format("15:04:05"))
+		printChangedFiles(changed)
+
+		if run(ctx) {
+			fmt.Println("\n✓ All checks passed")
+		}
+		fmt.Printf("\nWatching %s for changes (Ctrl+C to stop)...\n", projectRoot)
+	}
+
+	// Run once before the first change
+	onChange(cmd.Context(), nil)
+
+	if err := watcher.Run(cmd.Context(), onChange); err != nil {
+		return err
+	}
+	fmt.Println("\nStopped watching")
+	return nil
+}
+
+// watchDriftOptions are the inputs drift detection reloads on each run
+type watchDriftOptions struct {
+	planFile string
+	lockFile string
+	specFile string
+	cacheDir string
+	noCache  bool
+	driftOptions
+}
+
+func newWatchDriftOptions(cmd *cobra.Command, defaults *ux.PathDefaults) (*watchDriftOptions, error) {
+	opts := &watchDriftOptions{
+		planFile: cmd.Flags().Lookup("plan").Value.String(),
+		lockFile: cmd.Flags().Lookup("lock").Value.String(),
+		specFile: cmd.Flags().Lookup("spec").Value.String(),
+		cacheDir: filepath.Join(defaults.CacheDir(), "drift"),
+		noCache:  cmd.Flags().Lookup("no-cache").Value.String() == "true",
+	}
+	opts.policyFile = cmd.Flags().Lookup("policy").Value.String()
+	opts.projectRoot = cmd.Flags().Lookup("project-root").Value.String()
+	opts.apiSpecPath = cmd.Flags().Lookup("api-spec").Value.String()
+	opts.ignoreGlobs, _ = cmd.Flags().GetStringSlice("ignore") //nolint:errcheck // Acceptable to ignore array return
+	opts.language = cmd.Flags().Lookup("language").Value.String()
+
+	// Use smart defaults if not changed
+	if !cmd.Flags().Changed("plan") {
+		opts.planFile = defaults.PlanFile()
+	}
+	if !cmd.Flags().Changed("lock") {
+		opts.lockFile = defaults.SpecLockFile()
+	}
+	if !cmd.Flags().Changed("spec") {
+		opts.specFile = defaults.SpecFile()
+	}
+
+	// Validate required files with helpful errors
+	if err := ux.ValidateRequiredFile(opts.planFile, "Plan file", "specular plan"); err != nil {
+		return nil, ux.EnhanceError(err)
+	}
+	if err := ux.ValidateRequiredFile(opts.lockFile, "SpecLock file", "specular spec lock"); err != nil {
+		return nil, ux.EnhanceError(err)
+	}
+	if err := ux.ValidateRequiredFile(opts.specFile, "Spec file", "specular spec generate"); err != nil {
+		return nil, ux.EnhanceError(err)
+	}
+
+	return opts, nil
+}
+
+// run reloads the plan, SpecLock, and spec, detects drift, and prints the
+// report. It reports whether no error-level drift was found.
+func (o *watchDriftOptions) run(ctx context.Context) bool {
+	p, err := plan.LoadPlan(o.planFile)
+	if err != nil {
+		fmt.Printf("✗ Failed to load plan: %v\n", err)
+		return false
+	}
+	lock, err := spec.LoadSpecLock(o.lockFile)
+	if err != nil {
+		fmt.Printf("✗ Failed to load SpecLock: %v\n", err)
+		return false
+	}
+	s, err := spec.LoadSpec(o.specFile)
+	if err != nil {
+		fmt.Printf("✗ Failed to load spec: %v\n", err)
+		return false
+	}
+
+	// Reload the cache each run so a changed SpecLock invalidates it
+	opts := o.driftOptions
+	if !o.noCache {
+		if opts.cache, err = drift.LoadCache(o.cacheDir, o.lockFile); err != nil {
+			fmt.Printf("Warning: drift cache disabled: %v\n", err)
+		}
+	}
+
+	start := time.Now()
+	results, err := drift.RunDetectors(ctx, newDriftDetectors(p, lock, s, opts), nil)
+	if err != nil {
+		fmt.Printf("✗ Drift detection failed: %v\n", err)
+		return false
+	}
+	if err := opts.cache.Save(); err != nil {
+		fmt.Printf("Warning: failed to save drift cache: %v\n", err)
+	}
+
+	findings := make(map[string][]drift.Finding, len(results))
+	for _, result := range results {
+		findings[result.Name] = result.Findings
+	}
+	report := drift.GenerateReport(findings["plan-drift"], findings["code-drift"], findings["infra-drift"])
+
+	printDriftReport(report)
+	fmt.Printf("\nDrift detection took %s\n", time.Since(start).Round(time.Millisecond))
+	return !report.HasErrors()
+}
+
+// clearTerminal clears the screen when stdout is a terminal
+func clearTerminal() {
+	info, err := os.Stdout.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	fmt.Print("\033[H\033[2J")
+}
+
+// printChangedFiles prints the files that triggered a run
+func printChangedFiles(changed []string) {
+	const maxShown = 5
+
+	switch {
+	case len(changed) == 0:
+		fmt.Println("Initial run")
+	case len(changed) <= maxShown:
+		fmt.Printf("Changed: %s\n", strings.Join(changed, ", "))
+	default:
+		fmt.Printf("Changed: %s and %d more\n", strings.Join(changed[:maxShown], ", "), len(changed)-maxShown)
+	}
+	fmt.Println()
+}
+
+func init() {
+	evalCmd.AddCommand(evalWatchCmd)
+
+	evalWatchCmd.Flags().String("scenario", "", "Evaluation scenario to re-run instead of drift detection")
+	evalWatchCmd.Flags().String("plan", "plan.json", "Plan file to evaluate")
+	evalWatchCmd.Flags().String("lock", ".specular/spec.lock.json", "SpecLock file")
+	evalWatchCmd.Flags().String("spec", ".specular/spec.yaml", "Spec file for code drift detection")
+	evalWatchCmd.Flags().String("policy", "", "Policy file for infrastructure drift detection")
+	evalWatchCmd.Flags().String("project-root", ".", "Project root directory to watch")
+	evalWatchCmd.Flags().String("api-spec", "", "Path to OpenAPI spec file")
+	evalWatchCmd.Flags().StringSlice("ignore", []string{}, "Glob patterns to ignore (e.g., *.test.js)")
+	evalWatchCmd.Flags().String("language", "", "Check code routes against the API spec (javascript, typescript)")
+	evalWatchCmd.Flags().Bool("no-cache", false, "Rescan all files instead of reusing the drift cache")
+	evalWatchCmd.Flags().Duration("debounce", watch.DefaultDebounce, "Wait this long after the last change before re-running")
+}
@@ -65,33 +65,9 @@ The router will automatically select the best model based on complexity, budget,
 		}
 
 		// Load or create router config
-		var routerConfig *router.RouterConfig
-		if routerConfigPath != "" {
-			routerConfig, err = router.LoadConfig(routerConfigPath)
-			if err != nil {
-				return fmt.Errorf("failed to load router config: %w", err)
-			}
-		} else {
-			// Load provider config to get strategy settings
-			providerConfig, loadErr := provider.LoadProvidersConfig(providerConfigPath)
-			if loadErr != nil {
-				return fmt.Errorf("failed to load provider config: %w", loadErr)
-			}
-
-			// Create router config from provider strategy
-			routerConfig = &router.RouterConfig{
-				BudgetUSD:    providerConfig.Strategy.Budget.MaxCostPerDay,
-				MaxLatencyMs: providerConfig.Strategy.Performance.MaxLatencyMs,
-				PreferCheap:  providerConfig.Strategy.Performance.PreferCheap,
-			}
-
-			// Set defaults if not specified
-			if routerConfig.BudgetUSD == 0 {
-				routerConfig.BudgetUSD = 20.0
-			}
-			if routerConfig.MaxLatencyMs == 0 {
-				routerConfig.MaxLatencyMs = 60000
-			}
+		routerConfig, err := loadRouterConfig(routerConfigPath, providerConfigPath)
+		if err != nil {
+			return err
 		}
 
 		// Create router with providers
@@ -102,8 +78,12 @@ The router will automatically select the best model based on complexity, budget,
 
 		if verbose {
 			budget := r.GetBudget()
-			fmt.Fprintf(os.Stderr, "Router initialized: budget=$%.2f, max_latency=%dms\n",
-				budget.LimitUSD, routerConfig.MaxLatencyMs)
+			limit := fmt.Sprintf("$%.2f", budget.LimitUSD)
+			if budget.Unlimited {
+				limit = "unlimited"
+			}
+			fmt.Fprintf(os.Stderr, "Router initialized: budget=%s, max_latency=%dms\n",
+				limit, routerConfig.MaxLatencyMs)
 		}
 
 		// Join args as prompt
@@ -122,7 +102,7 @@ The router will automatically select the best model based on complexity, budget,
 			ModelHint:    modelHint,
 			Complexity:   complexity,
 			Priority:     priority,
-			Temperature:  temperature,
+			Temperature:  &temperature,
 			MaxTokens:    maxTokens,
 			ContextSize:  estimatedTokens,
 			TaskID:       types.TaskID(fmt.Sprintf("cli-generate-%d", time.Now().Unix())),
@@ -173,10 +153,7 @@ func runGenerate(ctx context.Context, r *router.Router, req router.GenerateReque
 		}
 
 		// Print budget status
-		budget := r.GetBudget()
-		fmt.Fprintf(os.Stderr, "\nBudget:         $%.2f spent, $%.2f remaining (%.1f%% used)\n",
-			budget.SpentUSD, budget.RemainingUSD,
-			(budget.SpentUSD/budget.LimitUSD)*100)
+		fmt.Fprintf(os.Stderr, "\nBudget:         %s\n", formatBudgetStatus(r.GetBudget()))
 	}
 
 	return nil
@@ -225,15 +202,47 @@ func runStreamingGenerate(ctx context.Context, r *router.Router, req router.Gene
 		fmt.Fprintf(os.Stderr, "Total content length: %d characters\n", len(totalContent))
 
 		// Print budget status
-		budget := r.GetBudget()
-		fmt.Fprintf(os.Stderr, "\nBudget:         $%.2f spent, $%.2f remaining (%.1f%% used)\n",
-			budget.SpentUSD, budget.RemainingUSD,
-			(budget.SpentUSD/budget.LimitUSD)*100)
+		fmt.Fprintf(os.Stderr, "\nBudget:         %s\n", formatBudgetStatus(r.GetBudget()))
 	}
 
 	return nil
 }
 
+// loadRouterConfig loads the router config from routerConfigPath, or builds
+// one from the provider config's strategy settings when no path is given
+func loadRouterConfig(routerConfigPath, providerConfigPath string) (*router.RouterConfig, error) {
+	if routerConfigPath != "" {
+		routerConfig, err := router.LoadConfig(routerConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load router config: %w", err)
+		}
+		return routerConfig, nil
+	}
+
+	// Load provider config to get strategy settings
+	providerConfig, err := provider.LoadProvidersConfig(providerConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load provider config: %w", err)
+	}
+
+	// Create router config from provider strategy
+	routerConfig := &router.RouterConfig{
+		BudgetUSD:    providerConfig.Strategy.Budget.MaxCostPerDay,
+		MaxLatencyMs: providerConfig.Strategy.Performance.MaxLatencyMs,
+		PreferCheap:  providerConfig.Strategy.Performance.PreferCheap,
+	}
+
+	// Set defaults if not specified
+	if routerConfig.BudgetUSD == 0 {
+		routerConfig.BudgetUSD = 20.0
+	}
+	if routerConfig.MaxLatencyMs == 0 {
+		routerConfig.MaxLatencyMs = 60000
+	}
+
+	return routerConfig, nil
+}
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
@@ -255,3 +264,13 @@ func init() {
 	generateCmd.Flags().Bool("stream", false, "Enable streaming output")
 	generateCmd.Flags().Bool("verbose", false, "Show detailed metadata")
 }
+
+// formatBudgetStatus summarizes router spend for verbose output
+func formatBudgetStatus(budget *router.Budget) string {
+	if budget.Unlimited {
+		return fmt.Sprintf("$%.2f spent (unlimited)", budget.SpentUSD)
+	}
+	return fmt.Sprintf("$%.2f spent, $%.2f remaining (%.1f%% used)",
+		budget.SpentUSD, budget.RemainingUSD,
+		(budget.SpentUSD/budget.LimitUSD)*100)
+}
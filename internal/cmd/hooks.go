@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/hooks"
+	"github.com/felixgeelhaar/specular/internal/profiles"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage lifecycle hook deliveries",
+	Long: `Manage deliveries of lifecycle hook events.
+
+Hooks that fail with a transient error, such as a network error or a 5xx
+response from a webhook, are retried in the background with backoff. Events
+that still fail after the last retry are recorded in the dead-letter log at
+~/.specular/hooks/failed.jsonl so they can be replayed.`,
+}
+
+var hooksReplayCmd = &cobra.Command{
+	Use:   "replay [id...]",
+	Short: "Re-fire events whose hooks failed after all retries",
+	Long: `Re-fire dead-lettered hook events from ~/.specular/hooks/failed.jsonl.
+
+Each hook is recreated from the configuration it was registered with.
+Events that are delivered are removed from the log; events that fail again
+stay in the log with the new error. Pass entry IDs or --hook to replay only
+some events, or --list to show the log without replaying.
+
+Examples:
+  specular hooks replay
+  specular hooks replay --list
+  specular hooks replay --hook slack-alerts
+  specular hooks replay notify-1762811730000000000`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hookName, _ := cmd.Flags().GetString("hook")
+		list, _ := cmd.Flags().GetBool("list")
+
+		path, err := hooks.DefaultDeadLetterPath()
+		if err != nil {
+			return err
+		}
+		log := hooks.NewDeadLetterLog(path)
+
+		ids := make(map[string]bool, len(args))
+		for _, id := range args {
+			ids[id] = true
+		}
+		match := func(entry *hooks.DeadLetter) bool {
+			return (len(ids) == 0 || ids[entry.ID]) && (hookName == "" || entry.Hook == hookName)
+		}
+
+		if list {
+			entries, err := log.Entries()
+			if err != nil {
+				return err
+			}
+			printDeadLetters(os.Stdout, path, entries, match)
+			return nil
+		}
+
+		registry := hooks.NewRegistry()
+		hooks.RegisterBuiltinHooks(registry)
+		results, err := log.Replay(context.Background(), registry, match)
+		if err != nil {
+			return err
+		}
+		return printReplayResults(os.Stdout, results)
+	},
+}
+
+// printDeadLetters writes the dead-lettered events that match
+func printDeadLetters(w io.Writer, path string, entries []hooks.DeadLetter, match func(*hooks.DeadLetter) bool) {
+	var matched []hooks.DeadLetter
+	for i := range entries {
+		if match(&entries[i]) {
+			matched = append(matched, entries[i])
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintf(w, "No failed hook events in %s\n", path)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "ID\tHOOK\tEVENT\tATTEMPTS\tFAILED\tERROR") //nolint:errcheck
+	for _, entry := range matched {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", //nolint:errcheck
+			entry.ID, entry.Hook, entry.Event.Type, entry.Attempts,
+			entry.FailedAt.Format("2006-01-02 15:04:05"), entry.Error)
+	}
+	tw.Flush() //nolint:errcheck
+}
+
+// printReplayResults writes the outcome of a replay and returns an error
+// when any event failed again
+func printReplayResults(w io.Writer, results []hooks.ReplayResult) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No failed hook events to replay")
+		return nil
+	}
+
+	failed := 0
+	for _, result := range results {
+		entry := result.Entry
+		if result.Success {
+			fmt.Fprintf(w, "✅ %s: %s delivered to %s\n", entry.ID, entry.Event.Type, entry.Hook)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "❌ %s: %s to %s failed: %s\n", entry.ID, entry.Event.Type, entry.Hook, result.Error)
+	}
+
+	fmt.Fprintf(w, "\nReplayed %d event(s): %d delivered, %d still failing\n", len(results), len(results)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d hook event(s) failed to replay", failed)
+	}
+	return nil
+}
+
+// loadHookConfigs returns the hooks configured in the profile followed by
+// those in the hooks.yaml files. A hooks.yaml hook replaces a profile hook
+// with the same name.
+func loadHookConfigs(w io.Writer, profile *profiles.Profile, verbose bool) ([]hooks.HookConfig, error) {
+	configs, err := profile.Hooks.HookConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile hooks: %w", err)
+	}
+	if len(profile.Hooks.OnApprovalRequested) > 0 {
+		fmt.Fprintln(w, "⚠️  on_approval_requested hooks are not supported yet and will not run")
+	}
+
+	fileConfigs, err := hooks.LoadConfigFiles(hooks.DefaultConfigPaths()...)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range fileConfigs {
+		replaced := false
+		for i := range configs {
+			if configs[i].Name == config.Name {
+				configs[i], replaced = config, true
+			}
+		}
+		if !replaced {
+			configs = append(configs, config)
+		}
+	}
+
+	if verbose {
+		for _, config := range configs {
+			if config.Enabled {
+				fmt.Fprintf(w, "Using hook: %s (%s, %d event(s))\n", config.Name, config.Type, len(config.Events))
+			}
+		}
+	}
+	return configs, nil
+}
+
+func init() {
+	hooksReplayCmd.Flags().String("hook", "", "Replay only events of the named hook")
+	hooksReplayCmd.Flags().Bool("list", false, "List failed events without replaying them")
+
+	hooksCmd.AddCommand(hooksReplayCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
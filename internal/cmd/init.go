@@ -2,15 +2,19 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/felixgeelhaar/specular/internal/detect"
+	"github.com/felixgeelhaar/specular/internal/provider"
 	"github.com/felixgeelhaar/specular/internal/ux"
 )
 
@@ -965,54 +969,162 @@ func runProviderSetup(specDir string) error {
 	}
 }
 
+// enableProvider sets enabled: true for a provider in router.yaml
 func enableProvider(routerPath string, providerName string) error {
-	// Read router.yaml
-	content, err := os.ReadFile(routerPath)
+	content, err := os.ReadFile(routerPath) // #nosec G304 -- router.yaml in the project's spec directory
 	if err != nil {
 		return fmt.Errorf("failed to read router.yaml: %w", err)
 	}
 
-	contentStr := string(content)
+	updated, changed, err := setProviderEnabled(content, providerName)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("✓ Provider %s is already enabled\n", providerName)
+		return nil
+	}
 
-	// Find and replace "enabled: false" with "enabled: true" for the provider
-	searchPattern := fmt.Sprintf("- name: %s", providerName)
-	providerIndex := strings.Index(contentStr, searchPattern)
-	if providerIndex == -1 {
-		return fmt.Errorf("provider %s not found in router.yaml", providerName)
+	if writeErr := os.WriteFile(routerPath, updated, 0600); writeErr != nil {
+		return fmt.Errorf("failed to update router.yaml: %w", writeErr)
 	}
 
-	// Find the next "enabled: false" after the provider name
-	searchStart := providerIndex
-	nextProvider := strings.Index(contentStr[searchStart+len(searchPattern):], "- name:")
-	searchEnd := len(contentStr)
-	if nextProvider != -1 {
-		searchEnd = searchStart + len(searchPattern) + nextProvider
+	fmt.Printf("✓ Enabled provider: %s\n", providerName)
+	return nil
+}
+
+// setProviderEnabled returns router.yaml content with a provider enabled,
+// and whether it changed. An existing enabled value is replaced in place,
+// keeping the rest of the file byte for byte; a provider without one gets
+// the key added and the file is re-encoded, keeping comments.
+func setProviderEnabled(content []byte, providerName string) ([]byte, bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse router.yaml: %w", err)
 	}
 
-	// Search within this provider's section
-	providerSection := contentStr[searchStart:searchEnd]
-	enabledPattern := "enabled: false"
-	enabledIndex := strings.Index(providerSection, enabledPattern)
+	entry := findProviderEntry(&doc, providerName)
+	if entry == nil {
+		return nil, false, fmt.Errorf("provider %s not found in router.yaml", providerName)
+	}
 
-	if enabledIndex == -1 {
-		// Already enabled
-		fmt.Printf("✓ Provider %s is already enabled\n", providerName)
-		return nil
+	var updated []byte
+	if value := mappingValue(entry, "enabled"); value != nil {
+		var enabled bool
+		if err := value.Decode(&enabled); err == nil && enabled {
+			return content, false, nil
+		}
+		updated = replaceScalar(content, value, "true")
 	}
 
-	// Replace in the full content
-	absoluteIndex := searchStart + enabledIndex
-	contentStr = contentStr[:absoluteIndex] + "enabled: true " + contentStr[absoluteIndex+len(enabledPattern):]
+	if updated == nil {
+		// No enabled value to replace in place; edit the tree and re-encode
+		if value := mappingValue(entry, "enabled"); value != nil {
+			*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+		} else {
+			entry.Content = append(entry.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "enabled"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+			)
+		}
 
-	// Write back
-	if writeErr := os.WriteFile(routerPath, []byte(contentStr), 0600); writeErr != nil {
-		return fmt.Errorf("failed to update router.yaml: %w", writeErr)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return nil, false, fmt.Errorf("failed to encode router.yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, false, fmt.Errorf("failed to encode router.yaml: %w", err)
+		}
+		updated = buf.Bytes()
 	}
 
-	fmt.Printf("✓ Enabled provider: %s\n", providerName)
+	// Check the result loads as a provider config with the provider enabled
+	var config provider.ProvidersConfig
+	if err := yaml.Unmarshal(updated, &config); err != nil {
+		return nil, false, fmt.Errorf("updated router.yaml is invalid: %w", err)
+	}
+	for _, p := range config.Providers {
+		if p.Name == providerName && p.Enabled {
+			return updated, true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("failed to enable provider %s in router.yaml", providerName)
+}
+
+// findProviderEntry returns the mapping of a provider in the providers list
+func findProviderEntry(doc *yaml.Node, providerName string) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	providers := mappingValue(doc.Content[0], "providers")
+	if providers == nil || providers.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, entry := range providers.Content {
+		if name := mappingValue(entry, "name"); name != nil && name.Value == providerName {
+			return entry
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of a key in a mapping node
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
 	return nil
 }
 
+// replaceScalar replaces a scalar's text at its position in content, or
+// returns nil if the text there is not the scalar
+func replaceScalar(content []byte, node *yaml.Node, value string) []byte {
+	if node.Kind != yaml.ScalarNode || node.Line < 1 || node.Column < 1 {
+		return nil
+	}
+
+	raw := node.Value
+	switch node.Style {
+	case 0, yaml.TaggedStyle:
+	case yaml.DoubleQuotedStyle:
+		raw = `"` + raw + `"`
+	case yaml.SingleQuotedStyle:
+		raw = "'" + raw + "'"
+	default:
+		return nil
+	}
+
+	// Find the byte offset of the scalar's line and column
+	offset := 0
+	for line := 1; line < node.Line; line++ {
+		next := bytes.IndexByte(content[offset:], '\n')
+		if next < 0 {
+			return nil
+		}
+		offset += next + 1
+	}
+	for col := 1; col < node.Column && offset < len(content); col++ {
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+
+	if !bytes.HasPrefix(content[offset:], []byte(raw)) {
+		return nil
+	}
+
+	updated := make([]byte, 0, len(content)-len(raw)+len(value))
+	updated = append(updated, content[:offset]...)
+	updated = append(updated, value...)
+	return append(updated, content[offset+len(raw):]...)
+}
+
 func printSmartSuccessMessage(config *InitConfig) {
 	projectName := filepath.Base(config.TargetDir)
 
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/interview"
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/internal/tui"
+	"github.com/felixgeelhaar/specular/internal/ux"
+)
+
+var interviewCmd = &cobra.Command{
+	Use:   "interview",
+	Short: "Build a specification interactively",
+	Long: `Walk through the product and its features and write a spec file.
+
+You are asked for the product name, goals, and acceptance criteria, then for
+each feature: name, ID, description, priority, acceptance criteria, and
+tasks. Every value is validated as it is entered, and the finished spec is
+checked against the spec schema before it is written.
+
+Progress is saved to a draft after every answer. If the interview is
+interrupted, run it again with --resume to continue where you left off.
+
+Use --edit to change an existing spec: every field is offered with its
+current value, and features can be added, edited, or removed.
+
+Examples:
+  # Create .specular/spec.yaml
+  specular interview
+
+  # Continue an interrupted interview
+  specular interview --resume
+
+  # Edit an existing spec
+  specular interview --edit --out .specular/spec.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runInterview,
+}
+
+func runInterview(cmd *cobra.Command, args []string) error {
+	defaults := ux.NewPathDefaults()
+	out, _ := cmd.Flags().GetString("out")
+	draftPath, _ := cmd.Flags().GetString("draft")
+	resume, _ := cmd.Flags().GetBool("resume")
+	edit, _ := cmd.Flags().GetBool("edit")
+
+	if !cmd.Flags().Changed("out") {
+		out = defaults.SpecFile()
+	}
+	if resume && edit {
+		return fmt.Errorf("--resume and --edit cannot be used together")
+	}
+	if !tui.ShouldPrompt() {
+		return ux.NewErrorWithSuggestion(
+			fmt.Errorf("interview requires an interactive terminal"),
+			"Write the spec by hand and check it with 'specular spec validate', or generate one with 'specular spec generate'",
+		)
+	}
+
+	prompter := tuiPrompter{}
+	var builder *interview.SpecBuilder
+
+	switch {
+	case resume:
+		draft, err := interview.LoadDraft(draftPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return ux.NewErrorWithSuggestion(
+					fmt.Errorf("no interview draft at %s", draftPath),
+					"Start a new interview with 'specular interview'",
+				)
+			}
+			return err
+		}
+		fmt.Printf("Resuming interview from %s (%d feature(s) so far)\n\n", draftPath, len(draft.Spec.Features))
+		builder = interview.ResumeSpecBuilder(prompter, draft)
+
+	case edit:
+		existing, err := spec.LoadSpec(out)
+		if err != nil {
+			return ux.FormatError(err, "loading spec file")
+		}
+		fmt.Printf("Editing %s (%d feature(s))\n\n", out, len(existing.Features))
+		builder = interview.EditSpecBuilder(prompter, existing)
+
+	default:
+		if _, err := os.Stat(draftPath); err == nil {
+			fmt.Printf("⚠️  An unfinished interview is saved at %s; starting over replaces it (use --resume to continue it)\n\n", draftPath)
+		}
+		if _, err := os.Stat(out); err == nil {
+			overwrite, err := tui.PromptForConfirmation(fmt.Sprintf("%s already exists. Replace it? (use --edit to change it instead)", out), false)
+			if err != nil {
+				return err
+			}
+			if !overwrite {
+				return nil
+			}
+		}
+		builder = interview.NewSpecBuilder(prompter)
+	}
+
+	builder.OnAnswer = func(draft *interview.Draft) error {
+		return interview.SaveDraft(draft, draftPath)
+	}
+
+	result, err := builder.Run()
+	if err != nil {
+		fmt.Printf("\nInterview stopped. Progress is saved in %s; continue with 'specular interview --resume'\n", draftPath)
+		return err
+	}
+
+	if err := spec.SaveSpec(result, out); err != nil {
+		return ux.FormatError(err, "saving spec")
+	}
+	if err := os.Remove(draftPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("⚠️  Failed to remove interview draft: %v\n", err)
+	}
+
+	fmt.Printf("\n✓ Specification written to %s\n", out)
+	fmt.Printf("  Product: %s\n", result.Product)
+	fmt.Printf("  Features: %d\n", len(result.Features))
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Lint the spec: specular spec lint")
+	fmt.Println("  2. Generate lock: specular spec lock")
+	fmt.Println("  3. Create plan: specular plan")
+
+	return nil
+}
+
+// tuiPrompter asks interview questions with the terminal prompt helpers
+type tuiPrompter struct{}
+
+// Input implements interview.Prompter
+func (tuiPrompter) Input(message, defaultValue string, validate func(string) error) (string, error) {
+	return tui.PromptForString(tui.Prompt{Message: message, Default: defaultValue, Validate: validate})
+}
+
+// Confirm implements interview.Prompter
+func (tuiPrompter) Confirm(message string, defaultValue bool) (bool, error) {
+	return tui.PromptForConfirmation(message, defaultValue)
+}
+
+// Select implements interview.Prompter
+func (tuiPrompter) Select(message string, options []string) (string, error) {
+	return tui.PromptForSelect(message, options)
+}
+
+func init() {
+	interviewCmd.Flags().StringP("out", "o", ".specular/spec.yaml", "Spec file to write")
+	interviewCmd.Flags().String("draft", interview.DefaultDraftPath, "Where interview progress is saved")
+	interviewCmd.Flags().Bool("resume", false, "Continue an interrupted interview from its draft")
+	interviewCmd.Flags().Bool("edit", false, "Edit the existing spec at --out")
+
+	rootCmd.AddCommand(interviewCmd)
+}
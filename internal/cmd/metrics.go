@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/metrics"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export specular metrics to Prometheus",
+	Long: `Export the metrics recorded by specular runs to Prometheus.
+
+'specular auto' runs are short-lived, so Prometheus cannot scrape them
+directly. Instead, each run pushes its metrics when it exits, with
+--metrics-push-url or SPECULAR_METRICS_PUSH_URL, to either a Prometheus
+Pushgateway or 'specular metrics serve'.`,
+}
+
+var metricsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Collect pushed run metrics and serve them at /metrics",
+	Long: `Start a long-lived server that collects the metrics pushed by specular
+runs and serves their totals at /metrics for Prometheus to scrape.
+
+Runs push to /metrics/job/<job> using the Pushgateway protocol. Unlike a
+Pushgateway, which keeps only the latest push for each group, the server
+adds every push to the totals, so counters such as
+specular_auto_workflows_total count all runs since the server started.
+Totals are kept in memory and reset when the server restarts.
+
+Example:
+  # Start the server on the default port 9091
+  specular metrics serve
+
+  # Push metrics from CI runs
+  SPECULAR_METRICS_PUSH_URL=http://metrics.internal:9091 specular auto "Add login"`,
+	RunE: runMetricsServe,
+}
+
+var (
+	metricsServePort    string
+	metricsServeAddress string
+)
+
+func init() {
+	metricsServeCmd.Flags().StringVar(&metricsServePort, "port", "9091", "Port to listen on")
+	metricsServeCmd.Flags().StringVar(&metricsServeAddress, "address", "0.0.0.0", "Address to bind to")
+
+	metricsCmd.AddCommand(metricsServeCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetricsServe(cmd *cobra.Command, args []string) error {
+	listenAddr := net.JoinHostPort(metricsServeAddress, metricsServePort)
+	srv := &http.Server{
+		Addr:              listenAddr,
+		Handler:           metrics.NewAggregator().Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	fmt.Printf("Collecting pushed metrics at http://%s/metrics/job/<job>\n", listenAddr)
+	fmt.Printf("Serving metrics at http://%s/metrics\n", listenAddr)
+	fmt.Printf("Press Ctrl+C to stop the server\n\n")
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server error: %w", err)
+
+	case sig := <-sigChan:
+		fmt.Printf("\nReceived signal: %s\n", sig)
+
+		shutdownCtx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shutdown error: %w", err)
+		}
+
+		fmt.Println("Server stopped")
+		return nil
+	}
+}
+
+// defaultMetricsPushURL returns the metrics push URL from
+// SPECULAR_METRICS_PUSH_URL
+func defaultMetricsPushURL() string {
+	return os.Getenv("SPECULAR_METRICS_PUSH_URL")
+}
+
+// pushRunMetrics pushes the run's metrics to url. A failed push is reported
+// but does not fail the run.
+func pushRunMetrics(ctx context.Context, url string) {
+	if url == "" {
+		return
+	}
+
+	pushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if err := metrics.PushDefault(pushCtx, url); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to push metrics to %s: %v\n", url, err)
+	}
+}
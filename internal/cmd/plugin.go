@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/felixgeelhaar/specular/internal/plugin"
+	"github.com/felixgeelhaar/specular/internal/policy"
+	"github.com/felixgeelhaar/specular/internal/ux"
 )
 
 var pluginCmd = &cobra.Command{
@@ -165,11 +168,9 @@ var pluginHealthCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pluginName := args[0]
 
-		manager := plugin.NewManager(plugin.DefaultManagerConfig())
-
-		// Discover plugins
-		if err := manager.Discover(); err != nil {
-			return fmt.Errorf("failed to discover plugins: %w", err)
+		manager, err := newPluginManager(ux.NewPathDefaults().PolicyFile())
+		if err != nil {
+			return err
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -182,8 +183,24 @@ var pluginHealthCmd = &cobra.Command{
 		}
 
 		fmt.Printf("✅ Plugin %s is healthy\n", pluginName)
-		fmt.Printf("   Status:  %s\n", health.Status)
-		fmt.Printf("   Version: %s\n", health.Version)
+		fmt.Printf("   Status:   %s\n", health.Status)
+		fmt.Printf("   Version:  %s\n", health.Version)
+		if health.Persistent {
+			fmt.Println("   Mode:     persistent")
+		} else {
+			fmt.Println("   Mode:     one-shot")
+		}
+
+		if health.ProtocolVersion == 0 {
+			fmt.Println("   Protocol: none reported (actions are not checked)")
+			return nil
+		}
+		fmt.Printf("   Protocol: v%d\n", health.ProtocolVersion)
+		fmt.Printf("   Actions:  %s\n", strings.Join(health.SupportedActions, ", "))
+
+		if _, err := manager.Negotiate(ctx, pluginName); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
 
 		return nil
 	},
@@ -197,11 +214,16 @@ var pluginEnableCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pluginName := args[0]
 
-		manager := plugin.NewManager(plugin.DefaultManagerConfig())
+		manager, err := newPluginManager(ux.NewPathDefaults().PolicyFile())
+		if err != nil {
+			return err
+		}
 
-		// Discover plugins
-		if err := manager.Discover(); err != nil {
-			return fmt.Errorf("failed to discover plugins: %w", err)
+		// Check the plugin supports the actions Specular will invoke
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+		if _, err := manager.Negotiate(ctx, pluginName); err != nil {
+			return fmt.Errorf("failed to enable plugin: %w", err)
 		}
 
 		if err := manager.Enable(pluginName); err != nil {
@@ -303,6 +325,45 @@ var pluginUninstallCmd = &cobra.Command{
 	},
 }
 
+// newPluginManager creates a manager with discovered plugins for commands
+// that run them. Plugins are sandboxed as configured in the execution.plugins
+// section of the policy file; a missing policy file leaves them unsandboxed.
+func newPluginManager(policyFile string) (*plugin.Manager, error) {
+	config := plugin.DefaultManagerConfig()
+
+	if _, err := os.Stat(policyFile); err == nil {
+		pol, err := policy.LoadPolicy(policyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load policy %s: %w", policyFile, err)
+		}
+		plugins := pol.Execution.Plugins
+		if plugins.Timeout > 0 {
+			config.Timeout = plugins.Timeout
+		}
+		config.Sandbox = plugin.SandboxConfig{
+			Enabled:                plugins.Sandbox,
+			CPULimit:               plugins.CPULimit,
+			MemLimit:               plugins.MemLimit,
+			DenyNetwork:            plugins.Network == "none",
+			EnvAllowlist:           plugins.EnvAllowlist,
+			AllowUnenforcedNetwork: plugins.AllowUnenforcedNetwork,
+		}
+		if err := config.Sandbox.Validate(); err != nil {
+			return nil, fmt.Errorf("policy %s: %w", policyFile, err)
+		}
+	}
+
+	if unenforced := config.Sandbox.Unenforced(); len(unenforced) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Plugin sandbox limits not enforced on this platform: %s\n", strings.Join(unenforced, ", "))
+	}
+
+	manager := plugin.NewManager(config)
+	if err := manager.Discover(); err != nil {
+		return nil, fmt.Errorf("failed to discover plugins: %w", err)
+	}
+	return manager, nil
+}
+
 func init() {
 	// Add plugin command to root
 	rootCmd.AddCommand(pluginCmd)
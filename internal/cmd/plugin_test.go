@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewPluginManager(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{"no policy file", "", ""},
+		{"sandbox policy", "execution:\n  plugins:\n    sandbox: true\n    cpu_limit: \"1\"\n    mem_limit: 512m\n    timeout: 10s\n", ""},
+		{"invalid limit", "execution:\n  plugins:\n    sandbox: true\n    mem_limit: lots\n", "invalid mem_limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+			if tt.policy != "" {
+				if err := os.WriteFile(policyFile, []byte(tt.policy), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manager, err := newPluginManager(policyFile)
+			if tt.wantErr == "" {
+				if err != nil || manager == nil {
+					t.Errorf("newPluginManager() = %v, %v", manager, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
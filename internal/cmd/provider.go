@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
 
@@ -20,18 +24,31 @@ const (
 )
 
 var providerCmd = &cobra.Command{
-	Use:   "provider",
-	Short: "Manage AI providers",
+	Use:     "provider",
+	Aliases: []string{"providers"},
+	Short:   "Manage AI providers",
 	Long: `Manage AI providers that specular can use for various tasks.
 Providers can be local models (ollama), cloud APIs (OpenAI, Anthropic), or custom implementations.`,
 }
 
 var providerListCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List configured providers",
-	Long:  `List all configured providers and their current status (enabled/disabled, loaded/not loaded).`,
+	Short: "List providers with their health and models",
+	Long: `List all configured providers with their health, the models they expose, and
+whether their credentials are set. Each enabled provider's health check is run.
+Without a provider configuration, auto-discovered providers are listed.
+
+Use --check as a CI pre-flight: it exits non-zero if any enabled provider is
+unhealthy.
+
+Examples:
+  specular providers list
+  specular providers list --json
+  specular providers list --check`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configPath := cmd.Flags().Lookup("config").Value.String()
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		check, _ := cmd.Flags().GetBool("check")
 		if configPath == "" {
 			// Try to discover providers.yaml in multiple locations
 			discoveredPath, discoverErr := ux.DiscoverConfigFile("providers.yaml")
@@ -46,67 +63,208 @@ var providerListCmd = &cobra.Command{
 			}
 		}
 
-		// Check if config file exists
+		// Load config, or fall back to the auto-discovered providers
+		var config *provider.ProvidersConfig
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			fmt.Printf("No provider configuration found at %s\n", configPath)
-			fmt.Printf("Run 'specular provider init' to create one.\n")
-			fmt.Printf("Tip: Specular will auto-discover providers if you have ollama installed or API keys set.\n")
-			return nil
-		}
-
-		// Load config
-		config, err := provider.LoadProvidersConfig(configPath)
-		if err != nil {
-			return fmt.Errorf("failed to load provider config: %w", err)
+			registry, discoverErr := provider.LoadRegistryFromAutoDiscovery()
+			if discoverErr != nil {
+				if check {
+					return fmt.Errorf("no providers available: %w", discoverErr)
+				}
+				fmt.Printf("No provider configuration found at %s\n", configPath)
+				fmt.Printf("Run 'specular provider init' to create one.\n")
+				fmt.Printf("Tip: Specular will auto-discover providers if you have ollama installed or API keys set.\n")
+				return nil
+			}
+			config = &provider.ProvidersConfig{}
+			for _, name := range registry.List() {
+				if providerConfig, getErr := registry.GetConfig(name); getErr == nil {
+					config.Providers = append(config.Providers, *providerConfig)
+				}
+			}
+		} else {
+			config, err = provider.LoadProvidersConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load provider config: %w", err)
+			}
 		}
 
-		// Print providers table
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "NAME\tTYPE\tENABLED\tSOURCE\tVERSION") //nolint:errcheck
-		fmt.Fprintln(w, "----\t----\t-------\t------\t-------") //nolint:errcheck
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+		entries := listProviders(ctx, config)
 
-		for _, p := range config.Providers {
-			enabled := "no"
-			if p.Enabled {
-				enabled = "yes"
+		if jsonOutput {
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON output: %w", err)
 			}
+			fmt.Println(string(data))
+		} else {
+			printProviderList(os.Stdout, entries)
+			printProviderStrategy(os.Stdout, config)
+		}
 
-			source := p.Source
-			if source == "" {
-				source = "-"
+		if check {
+			var unhealthy []string
+			for _, entry := range entries {
+				if entry.Enabled && entry.Status != providerStatusHealthy {
+					unhealthy = append(unhealthy, entry.Name)
+				}
 			}
-
-			version := p.Version
-			if version == "" {
-				version = "-"
+			if len(unhealthy) > 0 {
+				return fmt.Errorf("%d enabled provider(s) unhealthy: %s", len(unhealthy), strings.Join(unhealthy, ", "))
 			}
+		}
+
+		return nil
+	},
+}
+
+// Provider statuses reported by 'provider list'
+const (
+	providerStatusHealthy   = "healthy"
+	providerStatusUnhealthy = "unhealthy"
+	providerStatusDisabled  = "disabled"
+	providerStatusError     = "error" // The provider could not be loaded
+)
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
-				p.Name, p.Type, enabled, source, version)
+// providerListEntry is one provider in the 'provider list' output
+type providerListEntry struct {
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	Enabled            bool     `json:"enabled"`
+	Source             string   `json:"source,omitempty"`
+	Version            string   `json:"version,omitempty"`
+	Status             string   `json:"status"`
+	ProviderVersion    string   `json:"provider_version,omitempty"`
+	Models             []string `json:"models"`
+	CredentialsPresent *bool    `json:"credentials_present,omitempty"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// listProviders loads each enabled provider and runs the health checks
+// concurrently. Disabled providers are listed without a check.
+func listProviders(ctx context.Context, config *provider.ProvidersConfig) []providerListEntry {
+	entries := make([]providerListEntry, len(config.Providers))
+	var wg sync.WaitGroup
+	for i := range config.Providers {
+		providerConfig := &config.Providers[i]
+		entries[i] = providerListEntry{
+			Name:    providerConfig.Name,
+			Type:    string(providerConfig.Type),
+			Enabled: providerConfig.Enabled,
+			Source:  providerConfig.Source,
+			Version: providerConfig.Version,
+			Status:  providerStatusDisabled,
+			Models:  []string{},
+		}
+		if !providerConfig.Enabled {
+			continue
 		}
 
-		w.Flush() //#nosec G104 -- Tabwriter flush errors not critical
+		wg.Add(1)
+		go func(entry *providerListEntry) {
+			defer wg.Done()
 
-		// Print strategy info
-		if config.Strategy.Budget.MaxCostPerDay > 0 || config.Strategy.Budget.MaxCostPerRequest > 0 {
-			fmt.Println("\nBudget Constraints:")
-			if config.Strategy.Budget.MaxCostPerDay > 0 {
-				fmt.Printf("  Max cost per day: $%.2f\n", config.Strategy.Budget.MaxCostPerDay)
+			var report *provider.HealthReport
+			registry := provider.NewRegistry()
+			if err := registry.LoadFromConfig(providerConfig); err != nil {
+				report = provider.LoadFailureReport(providerConfig, err)
+				entry.Status = providerStatusError
+			} else {
+				client, _ := registry.Get(providerConfig.Name)
+				report = provider.CheckHealth(ctx, client, providerConfig)
+				_ = registry.CloseAll() //#nosec G104 -- Best effort cleanup after the check
+				entry.Status = providerStatusUnhealthy
+				if report.Healthy {
+					entry.Status = providerStatusHealthy
+				}
 			}
-			if config.Strategy.Budget.MaxCostPerRequest > 0 {
-				fmt.Printf("  Max cost per request: $%.2f\n", config.Strategy.Budget.MaxCostPerRequest)
+
+			entry.ProviderVersion = report.Version
+			if len(report.Models) > 0 {
+				entry.Models = report.Models
 			}
+			entry.CredentialsPresent = report.CredentialsPresent
+			entry.Error = report.Error
+		}(&entries[i])
+	}
+	wg.Wait()
+
+	return entries
+}
+
+// printProviderList writes the providers as a table
+func printProviderList(w io.Writer, entries []providerListEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tENABLED\tSTATUS\tCREDENTIALS\tMODELS") //nolint:errcheck
+	fmt.Fprintln(tw, "----\t----\t-------\t------\t-----------\t------") //nolint:errcheck
+
+	for _, entry := range entries {
+		enabled := "no"
+		if entry.Enabled {
+			enabled = "yes"
+		}
+
+		status := entry.Status
+		switch entry.Status {
+		case providerStatusHealthy:
+			status = "✅ " + status
+		case providerStatusUnhealthy, providerStatusError:
+			status = "❌ " + status
 		}
 
-		if len(config.Strategy.Preference) > 0 {
-			fmt.Println("\nProvider Preference Order:")
-			for i, name := range config.Strategy.Preference {
-				fmt.Printf("  %d. %s\n", i+1, name)
+		credentials := "-"
+		if entry.CredentialsPresent != nil {
+			credentials = "missing"
+			if *entry.CredentialsPresent {
+				credentials = "set"
 			}
 		}
 
-		return nil
-	},
+		models := "-"
+		if len(entry.Models) > 0 {
+			models = strings.Join(entry.Models, ", ")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
+			entry.Name, entry.Type, enabled, status, credentials, models)
+	}
+
+	tw.Flush() //#nosec G104 -- Tabwriter flush errors not critical
+
+	// Explain each failure below the table
+	separated := false
+	for _, entry := range entries {
+		if entry.Error == "" {
+			continue
+		}
+		if !separated {
+			fmt.Fprintln(w) //nolint:errcheck
+			separated = true
+		}
+		fmt.Fprintf(w, "%s: %s\n", entry.Name, entry.Error) //nolint:errcheck
+	}
+}
+
+// printProviderStrategy writes the budget and preference settings
+func printProviderStrategy(w io.Writer, config *provider.ProvidersConfig) {
+	if config.Strategy.Budget.MaxCostPerDay > 0 || config.Strategy.Budget.MaxCostPerRequest > 0 {
+		fmt.Fprintln(w, "\nBudget Constraints:") //nolint:errcheck
+		if config.Strategy.Budget.MaxCostPerDay > 0 {
+			fmt.Fprintf(w, "  Max cost per day: $%.2f\n", config.Strategy.Budget.MaxCostPerDay) //nolint:errcheck
+		}
+		if config.Strategy.Budget.MaxCostPerRequest > 0 {
+			fmt.Fprintf(w, "  Max cost per request: $%.2f\n", config.Strategy.Budget.MaxCostPerRequest) //nolint:errcheck
+		}
+	}
+
+	if len(config.Strategy.Preference) > 0 {
+		fmt.Fprintln(w, "\nProvider Preference Order:") //nolint:errcheck
+		for i, name := range config.Strategy.Preference {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, name) //nolint:errcheck
+		}
+	}
 }
 
 var providerDoctorCmd = &cobra.Command{
@@ -495,6 +653,8 @@ func init() {
 
 	// Flags for list command
 	providerListCmd.Flags().String("config", "", "Path to provider config file (default: .specular/providers.yaml)")
+	providerListCmd.Flags().Bool("json", false, "Output the provider list in JSON format")
+	providerListCmd.Flags().Bool("check", false, "Exit non-zero if any enabled provider is unhealthy (CI pre-flight)")
 
 	// Flags for doctor command
 	providerDoctorCmd.Flags().String("config", "", "Path to provider config file (default: .specular/providers.yaml)")
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// writeHealthProvider writes a provider executable whose health subcommand
+// prints output and exits with code 0, or 1 when failing is set
+func writeHealthProvider(t *testing.T, output string, failing bool) string {
+	t.Helper()
+	exit := "0"
+	if failing {
+		exit = "1"
+	}
+	path := filepath.Join(t.TempDir(), "provider")
+	script := "#!/bin/sh\necho '" + output + "'\nexit " + exit + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListProviders(t *testing.T) {
+	t.Setenv("TEST_MISSING_API_KEY", "")
+
+	config := &provider.ProvidersConfig{Providers: []provider.ProviderConfig{
+		{
+			Name:    "ollama",
+			Type:    provider.ProviderTypeCLI,
+			Enabled: true,
+			Config: map[string]interface{}{
+				"path": writeHealthProvider(t, `{"provider":"ollama","healthy":true,"models":["llama3.2:latest","qwen2.5-coder:7b"],"credentials_present":true}`, false),
+			},
+		},
+		{
+			Name:    "gemini-cli",
+			Type:    provider.ProviderTypeCLI,
+			Enabled: true,
+			Config:  map[string]interface{}{"path": writeHealthProvider(t, "", true)},
+			Models:  map[string]string{"fast": "gemini-2.0-flash"},
+		},
+		{
+			Name:    "mistral", // Not a supported API provider, so it fails to load
+			Type:    provider.ProviderTypeAPI,
+			Enabled: true,
+			Config:  map[string]interface{}{"api_key": "${TEST_MISSING_API_KEY}"},
+		},
+		{Name: "openai", Type: provider.ProviderTypeAPI},
+	}}
+
+	entries := listProviders(context.Background(), config)
+	if len(entries) != 4 {
+		t.Fatalf("entries = %d, want 4", len(entries))
+	}
+
+	ollama := entries[0]
+	if ollama.Status != providerStatusHealthy || !reflect.DeepEqual(ollama.Models, []string{"llama3.2:latest", "qwen2.5-coder:7b"}) {
+		t.Errorf("ollama = %+v, want healthy with its installed models", ollama)
+	}
+	if ollama.CredentialsPresent == nil || !*ollama.CredentialsPresent {
+		t.Error("ollama credentials should be reported as set")
+	}
+
+	geminiCLI := entries[1]
+	if geminiCLI.Status != providerStatusUnhealthy || geminiCLI.Error == "" {
+		t.Errorf("gemini-cli = %+v, want unhealthy with an error", geminiCLI)
+	}
+	if !reflect.DeepEqual(geminiCLI.Models, []string{"gemini-2.0-flash"}) {
+		t.Errorf("gemini-cli models = %v, want the configured models", geminiCLI.Models)
+	}
+
+	mistral := entries[2]
+	if mistral.Status != providerStatusError || mistral.CredentialsPresent == nil || *mistral.CredentialsPresent {
+		t.Errorf("mistral = %+v, want a load error with missing credentials", mistral)
+	}
+
+	openai := entries[3]
+	if openai.Status != providerStatusDisabled || openai.Error != "" {
+		t.Errorf("openai = %+v, want disabled without a check", openai)
+	}
+
+	var out bytes.Buffer
+	printProviderList(&out, entries)
+	for _, want := range []string{
+		"✅ healthy", "llama3.2:latest, qwen2.5-coder:7b",
+		"❌ unhealthy", "❌ error", "missing", "disabled",
+		"mistral: unknown API provider",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("table missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestProviderListFlags(t *testing.T) {
+	if got := providerCmd.Aliases; !reflect.DeepEqual(got, []string{"providers"}) {
+		t.Errorf("provider aliases = %v, want [providers]", got)
+	}
+	for _, name := range []string{"config", "json", "check"} {
+		if providerListCmd.Flags().Lookup(name) == nil {
+			t.Errorf("provider list is missing the --%s flag", name)
+		}
+	}
+}
+
+func TestProviderListCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		failing bool
+		wantErr bool
+	}{
+		{name: "all healthy"},
+		{name: "unhealthy provider", failing: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "providers.yaml")
+			content := "providers:\n" +
+				"  - name: local\n    type: cli\n    enabled: true\n    config:\n      path: " + writeHealthProvider(t, "OK", tt.failing) + "\n" +
+				"  - name: openai\n    type: api\n    enabled: false\n"
+			if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := providerListCmd
+			cmd.SetContext(context.Background())
+			for name, value := range map[string]string{"config": configPath, "json": "true", "check": "true"} {
+				if err := cmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Cleanup(func() {
+				for name, value := range map[string]string{"config": "", "json": "false", "check": "false"} {
+					_ = cmd.Flags().Set(name, value)
+				}
+			})
+
+			stdout := os.Stdout
+			devNull, err := os.Open(os.DevNull)
+			if err != nil {
+				t.Fatal(err)
+			}
+			os.Stdout = devNull
+			err = cmd.RunE(cmd, nil)
+			os.Stdout = stdout
+			devNull.Close()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "local") {
+				t.Errorf("error %q should name the unhealthy provider", err)
+			}
+		})
+	}
+}
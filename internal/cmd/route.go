@@ -152,9 +152,14 @@ Examples:
 		// Display budget info
 		budget := r.GetBudget()
 		fmt.Println("Router Budget:")
-		fmt.Printf("  Limit: $%.2f\n", budget.LimitUSD)
-		fmt.Printf("  Spent: $%.4f\n", budget.SpentUSD)
-		fmt.Printf("  Remaining: $%.4f\n", budget.RemainingUSD)
+		if budget.Unlimited {
+			fmt.Println("  Limit: unlimited")
+			fmt.Printf("  Spent: $%.4f\n", budget.SpentUSD)
+		} else {
+			fmt.Printf("  Limit: $%.2f\n", budget.LimitUSD)
+			fmt.Printf("  Spent: $%.4f\n", budget.SpentUSD)
+			fmt.Printf("  Remaining: $%.4f\n", budget.RemainingUSD)
+		}
 
 		return nil
 	},
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/router"
+	"github.com/felixgeelhaar/specular/internal/ux"
+)
+
+// routeTestCmd dry-runs model selection
+var routeTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Dry-run model selection for a task",
+	Long: `Show which model the configured router would select for a task, without
+sending any request to a provider.
+
+The router is loaded the same way as for 'specular generate', including the
+routing policy. The output lists every candidate model ranked by its selection
+score, the chosen model, the reason for the choice, and the estimated cost.
+
+Examples:
+  specular route test --hint codegen
+  specular route test --hint agentic --priority P0 --complexity 9
+  specular route test --hint long-context --context-size 150000 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		providerConfigPath, _ := cmd.Flags().GetString("provider-config")
+		routerConfigPath, _ := cmd.Flags().GetString("router-config")
+		hint, _ := cmd.Flags().GetString("hint")
+		priority, _ := cmd.Flags().GetString("priority")
+		complexity, _ := cmd.Flags().GetInt("complexity")
+		contextSize, _ := cmd.Flags().GetInt("context-size")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		priority = strings.ToUpper(priority)
+		switch priority {
+		case "P0", "P1", "P2":
+		default:
+			return fmt.Errorf("invalid priority %q: must be P0, P1, or P2", priority)
+		}
+		if complexity < 1 || complexity > 10 {
+			return fmt.Errorf("invalid complexity %d: must be between 1 and 10", complexity)
+		}
+		if contextSize < 0 {
+			return fmt.Errorf("invalid context size %d: must not be negative", contextSize)
+		}
+
+		if providerConfigPath == "" {
+			providerConfigPath = defaultProviderConfigPath
+		}
+		registry, err := provider.LoadRegistryWithAutoDiscovery(providerConfigPath)
+		if err != nil {
+			return ProviderLoadError(providerConfigPath, err)
+		}
+
+		routerConfig, err := loadRouterConfig(routerConfigPath, providerConfigPath)
+		if err != nil {
+			return err
+		}
+		r, err := router.NewRouterWithProviders(routerConfig, registry)
+		if err != nil {
+			return RouterError(err)
+		}
+		if err := applyRoutingPolicy(r, ux.NewPathDefaults().PolicyFile()); err != nil {
+			return err
+		}
+
+		req := router.RoutingRequest{
+			ModelHint:   hint,
+			Complexity:  complexity,
+			Priority:    priority,
+			ContextSize: contextSize,
+		}
+		report := dryRunRoute(cmd.Context(), r, req)
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize JSON output: %w", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			printRouteTest(os.Stdout, report)
+		}
+
+		if report.Error != "" {
+			return fmt.Errorf("routing failed: %s", report.Error)
+		}
+		return nil
+	},
+}
+
+// routeTestReport is the outcome of a model selection dry run
+type routeTestReport struct {
+	Hint            string               `json:"hint,omitempty"`
+	Priority        string               `json:"priority"`
+	Complexity      int                  `json:"complexity"`
+	ContextSize     int                  `json:"context_size"`
+	Selected        string               `json:"selected,omitempty"`
+	Provider        string               `json:"provider,omitempty"`
+	Reason          string               `json:"reason,omitempty"`
+	EstimatedTokens int                  `json:"estimated_tokens,omitempty"`
+	EstimatedCost   float64              `json:"estimated_cost_usd,omitempty"`
+	Error           string               `json:"error,omitempty"`
+	Candidates      []routeTestCandidate `json:"candidates"`
+}
+
+// routeTestCandidate is one ranked candidate model
+type routeTestCandidate struct {
+	Rank            int     `json:"rank"`
+	Model           string  `json:"model"`
+	Provider        string  `json:"provider"`
+	Type            string  `json:"type"`
+	Score           float64 `json:"score"`
+	Capability      float64 `json:"capability"`
+	CostPerMToken   float64 `json:"cost_per_mtok"`
+	MaxLatencyMs    int     `json:"max_latency_ms"`
+	ContextWindow   int     `json:"context_window"`
+	EstimatedTokens int     `json:"estimated_tokens"`
+	EstimatedCost   float64 `json:"estimated_cost_usd"`
+	Selected        bool    `json:"selected"`
+}
+
+// dryRunRoute ranks the candidates for a request and selects a model
+// without generating anything. A selection error is recorded in the report
+// so the ranking can still be shown.
+func dryRunRoute(ctx context.Context, r *router.Router, req router.RoutingRequest) *routeTestReport {
+	report := &routeTestReport{
+		Hint:        req.ModelHint,
+		Priority:    req.Priority,
+		Complexity:  req.Complexity,
+		ContextSize: req.ContextSize,
+		Candidates:  []routeTestCandidate{},
+	}
+
+	result, err := r.SelectModel(ctx, req)
+	if err != nil {
+		report.Error = err.Error()
+	} else {
+		report.Selected = result.Model.ID
+		report.Provider = string(result.Model.Provider)
+		report.Reason = result.Reason
+		report.EstimatedTokens = result.EstimatedTokens
+		report.EstimatedCost = result.EstimatedCost
+	}
+
+	for i, ranked := range r.RankModels(ctx, req) {
+		report.Candidates = append(report.Candidates, routeTestCandidate{
+			Rank:            i + 1,
+			Model:           ranked.Model.ID,
+			Provider:        string(ranked.Model.Provider),
+			Type:            string(ranked.Model.Type),
+			Score:           ranked.Score,
+			Capability:      ranked.Model.CapabilityScore,
+			CostPerMToken:   ranked.Model.CostPerMToken,
+			MaxLatencyMs:    ranked.Model.MaxLatencyMs,
+			ContextWindow:   ranked.Model.ContextWindow,
+			EstimatedTokens: ranked.EstimatedTokens,
+			EstimatedCost:   ranked.EstimatedCost,
+			Selected:        ranked.Model.ID == report.Selected,
+		})
+	}
+
+	return report
+}
+
+// printRouteTest writes a dry run report as a ranked table
+func printRouteTest(w io.Writer, report *routeTestReport) {
+	hint := report.Hint
+	if hint == "" {
+		hint = "(none)"
+	}
+	fmt.Fprintf(w, "=== Route Test ===\n\n")
+	fmt.Fprintf(w, "Hint: %s  Priority: %s  Complexity: %d  Context: %d tokens\n\n",
+		hint, report.Priority, report.Complexity, report.ContextSize)
+
+	if len(report.Candidates) == 0 {
+		fmt.Fprintln(w, "No candidate models (check provider configuration and routing policy)")
+	} else {
+		fmt.Fprintln(w, "Candidates:")
+		fmt.Fprintf(w, "  %-4s %-28s %-10s %7s %6s %10s %9s %10s\n",
+			"#", "MODEL", "PROVIDER", "SCORE", "CAP", "$/MTOK", "LATENCY", "EST COST")
+		for _, c := range report.Candidates {
+			marker := " "
+			if c.Selected {
+				marker = "→"
+			}
+			fmt.Fprintf(w, "%s %-4d %-28s %-10s %7.1f %6.0f %10.2f %7dms %10.4f\n",
+				marker, c.Rank, c.Model, c.Provider, c.Score, c.Capability, c.CostPerMToken, c.MaxLatencyMs, c.EstimatedCost)
+		}
+	}
+	fmt.Fprintln(w)
+
+	if report.Error != "" {
+		fmt.Fprintf(w, "❌ No model selected: %s\n", report.Error)
+		return
+	}
+
+	fmt.Fprintf(w, "Selected: %s (%s)\n", report.Selected, report.Provider)
+	fmt.Fprintf(w, "Reason:   %s\n", report.Reason)
+	fmt.Fprintf(w, "Estimate: %d tokens, $%.4f\n", report.EstimatedTokens, report.EstimatedCost)
+}
+
+func init() {
+	routeTestCmd.Flags().String("provider-config", "", "Path to provider config (default: .specular/providers.yaml)")
+	routeTestCmd.Flags().String("router-config", "", "Path to router config (optional)")
+	routeTestCmd.Flags().String("hint", "", "Model hint (codegen, agentic, fast, cheap, long-context)")
+	routeTestCmd.Flags().String("priority", "P1", "Task priority (P0, P1, P2)")
+	routeTestCmd.Flags().Int("complexity", 5, "Task complexity (1-10)")
+	routeTestCmd.Flags().Int("context-size", 0, "Context size in tokens the model must fit")
+	routeTestCmd.Flags().Bool("json", false, "Output the dry run in JSON format")
+
+	routeCmd.AddCommand(routeTestCmd)
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/router"
 )
 
 // TestRouteSubcommands tests that all route subcommands are registered
@@ -12,6 +19,7 @@ func TestRouteSubcommands(t *testing.T) {
 		"list":     false,
 		"override": false,
 		"explain":  false,
+		"test":     false,
 	}
 
 	for _, cmd := range routeCmd.Commands() {
@@ -167,3 +175,108 @@ func TestRouteExplainCommand(t *testing.T) {
 		t.Error("explain Short description is empty")
 	}
 }
+
+// TestRouteTestFlags tests that route test has the dry run flags
+func TestRouteTestFlags(t *testing.T) {
+	for _, name := range []string{"hint", "priority", "complexity", "context-size", "json", "provider-config", "router-config"} {
+		if routeTestCmd.Flags().Lookup(name) == nil {
+			t.Errorf("flag '%s' not found on route test command", name)
+		}
+	}
+}
+
+// unusedProvider fails the test if a request is sent to it
+type unusedProvider struct {
+	t *testing.T
+}
+
+func (p *unusedProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	p.t.Error("dry run sent a generate request")
+	return nil, errors.New("unexpected request")
+}
+
+func (p *unusedProvider) Stream(ctx context.Context, req *provider.GenerateRequest) (<-chan provider.StreamChunk, error) {
+	p.t.Error("dry run sent a stream request")
+	return nil, errors.New("unexpected request")
+}
+
+func (p *unusedProvider) GetCapabilities() *provider.ProviderCapabilities {
+	return &provider.ProviderCapabilities{}
+}
+
+func (p *unusedProvider) GetInfo() *provider.ProviderInfo {
+	return &provider.ProviderInfo{Name: "unused"}
+}
+
+func (p *unusedProvider) IsAvailable() bool { return true }
+
+func (p *unusedProvider) Health(ctx context.Context) error { return nil }
+
+func (p *unusedProvider) Close() error { return nil }
+
+// TestDryRunRoute tests that a dry run ranks candidates and selects the top
+// model without sending requests
+func TestDryRunRoute(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []string
+		req       router.RoutingRequest
+		wantErr   bool
+	}{
+		{
+			name:      "selects top ranked model",
+			providers: []string{"anthropic"},
+			req:       router.RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1"},
+		},
+		{
+			name:    "records selection errors",
+			req:     router.RoutingRequest{ModelHint: "agentic", Complexity: 10, Priority: "P0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := provider.NewRegistry()
+			for _, name := range tt.providers {
+				if err := registry.Register(name, &unusedProvider{t: t}, &provider.ProviderConfig{Name: name}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r, err := router.NewRouterWithProviders(&router.RouterConfig{BudgetUSD: 10, MaxLatencyMs: 60000}, registry)
+			if err != nil {
+				t.Fatalf("NewRouterWithProviders() error = %v", err)
+			}
+
+			report := dryRunRoute(context.Background(), r, tt.req)
+			for i, c := range report.Candidates {
+				if c.Rank != i+1 || c.Provider != "anthropic" {
+					t.Errorf("candidate %d = %+v", i, c)
+				}
+				if i > 0 && c.Score > report.Candidates[i-1].Score {
+					t.Errorf("candidate %d scores higher than candidate %d", i+1, i)
+				}
+			}
+
+			if tt.wantErr {
+				if report.Error == "" || report.Selected != "" {
+					t.Errorf("report = %+v, want a selection error", report)
+				}
+				return
+			}
+			if report.Error != "" || len(report.Candidates) == 0 {
+				t.Fatalf("report = %+v, want candidates and a selection", report)
+			}
+			top := report.Candidates[0]
+			if !top.Selected || report.Selected != top.Model || report.EstimatedCost != top.EstimatedCost || report.Reason == "" {
+				t.Errorf("report = %+v, want the top candidate selected", report)
+			}
+
+			var out bytes.Buffer
+			printRouteTest(&out, report)
+			if !strings.Contains(out.String(), "→ 1") || !strings.Contains(out.String(), "Selected: "+report.Selected) {
+				t.Errorf("output does not mark the selected model:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -19,6 +21,7 @@ import (
 	"github.com/felixgeelhaar/specular/internal/telemetry"
 	"github.com/felixgeelhaar/specular/internal/tui"
 	"github.com/felixgeelhaar/specular/internal/ux"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -148,10 +151,16 @@ var specGenerateCmd = &cobra.Command{
 var specValidateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate a specification file",
-	Long:  `Validate a specification against the schema and semantic rules.`,
+	Long: `Validate a specification against the spec JSON Schema and semantic rules.
+
+Schema violations are reported with the path of the offending value, such
+as "features[2].priority: must be one of P0, P1, P2". Use --schema to
+validate against a custom schema, for example one that allows team-specific
+extension fields. Print the built-in schema with 'specular spec schema'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		defaults := ux.NewPathDefaults()
 		in := cmd.Flags().Lookup("in").Value.String()
+		schemaPath, _ := cmd.Flags().GetString("schema")
 
 		// Use smart default if not changed
 		if !cmd.Flags().Changed("in") {
@@ -163,36 +172,27 @@ var specValidateCmd = &cobra.Command{
 			return ux.EnhanceError(err)
 		}
 
-		// Load spec
-		s, err := spec.LoadSpec(in)
-		if err != nil {
-			return ux.FormatError(err, "loading spec file")
-		}
-
-		// Basic validation
-		if s.Product == "" {
-			return fmt.Errorf("validation failed: product name is required")
-		}
-
-		if len(s.Features) == 0 {
-			return fmt.Errorf("validation failed: at least one feature is required")
+		repo := spec.NewFileSpecRepository()
+		if schemaPath != "" {
+			schema, err := spec.LoadSchema(schemaPath)
+			if err != nil {
+				return ux.FormatError(err, "loading schema")
+			}
+			repo = spec.NewFileSpecRepositoryWithSchema(schema)
 		}
 
-		// Validate each feature
-		for _, feature := range s.Features {
-			if feature.ID == "" {
-				return fmt.Errorf("validation failed: feature missing ID")
-			}
-			if feature.Title == "" {
-				return fmt.Errorf("validation failed: feature %s missing title", feature.ID)
-			}
-			if feature.Priority == "" {
-				return fmt.Errorf("validation failed: feature %s missing priority", feature.ID)
-			}
-			if feature.Priority != "P0" && feature.Priority != "P1" && feature.Priority != "P2" {
-				return fmt.Errorf("validation failed: feature %s has invalid priority %s (must be P0, P1, or P2)",
-					feature.ID, feature.Priority)
+		// Load spec, checking the schema and semantic rules
+		s, err := repo.Load(in)
+		if err != nil {
+			var schemaErr *spec.SchemaValidationError
+			if errors.As(err, &schemaErr) {
+				fmt.Printf("✗ %s does not match the spec schema:\n", in)
+				for _, violation := range schemaErr.Errors {
+					fmt.Printf("  %s\n", violation)
+				}
+				return fmt.Errorf("validation failed: %d schema error(s)", len(schemaErr.Errors))
 			}
+			return ux.FormatError(err, "loading spec file")
 		}
 
 		fmt.Printf("✓ Spec is valid (%d features)\n", len(s.Features))
@@ -200,6 +200,20 @@ var specValidateCmd = &cobra.Command{
 	},
 }
 
+var specSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for specification files",
+	Long: `Print the JSON Schema that spec files are validated against.
+
+Save it to configure editor validation of spec.yaml, or extend it with
+team-specific fields and pass it to 'specular spec validate --schema'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := os.Stdout.Write(spec.SchemaJSON())
+		return err
+	},
+}
+
 var specLockCmd = &cobra.Command{
 	Use:   "lock",
 	Short: "Generate SpecLock from specification",
@@ -224,6 +238,11 @@ var specLockCmd = &cobra.Command{
 			return ux.EnhanceError(err)
 		}
 
+		lockVersion, err := types.NewVersion(version)
+		if err != nil {
+			return ux.FormatError(err, "parsing --version")
+		}
+
 		// Load spec
 		s, err := spec.LoadSpec(in)
 		if err != nil {
@@ -231,7 +250,7 @@ var specLockCmd = &cobra.Command{
 		}
 
 		// Generate SpecLock
-		lock, err := spec.GenerateSpecLock(*s, version)
+		lock, err := spec.GenerateSpecLock(*s, lockVersion)
 		if err != nil {
 			return ux.FormatError(err, "generating SpecLock")
 		}
@@ -303,10 +322,29 @@ The specification file is opened for editing and automatically validated after s
 var specDiffCmd = &cobra.Command{
 	Use:   "diff <fileA> <fileB>",
 	Short: "Compare two specification versions",
-	Long: `Compare two specification files and show differences in features, priorities, and content.
+	Long: `Compare two specification files feature by feature.
+
+Features are matched by ID and reported as added, removed, or modified. For
+modified features the changed fields are listed: title, description,
+priority, success criteria, API endpoints, trace, refs, tags, and tasks.
 
 Useful for reviewing changes before locking a new spec version or understanding
-what changed between releases.`,
+what changed between releases.
+
+Exit codes:
+  0 - Specs are identical or differences displayed successfully
+  1 - Error occurred during comparison
+  2 - Differences found (when using --quiet)
+
+Examples:
+  # Show differences between two specs
+  specular spec diff old/spec.yaml .specular/spec.yaml
+
+  # Output the diff as JSON
+  specular spec diff old/spec.yaml .specular/spec.yaml --json
+
+  # Fail a CI step when the spec changed
+  specular spec diff main/spec.yaml .specular/spec.yaml --quiet`,
 	Args: cobra.ExactArgs(2),
 	RunE: runSpecDiff,
 }
@@ -598,6 +636,9 @@ func runSpecDiff(cmd *cobra.Command, args []string) error {
 	fileA := args[0]
 	fileB := args[1]
 
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
 	// Validate both files exist
 	if err := ux.ValidateRequiredFile(fileA, "First spec file", ""); err != nil {
 		return ux.EnhanceError(err)
@@ -617,96 +658,103 @@ func runSpecDiff(cmd *cobra.Command, args []string) error {
 		return ux.FormatError(err, fmt.Sprintf("loading %s", fileB))
 	}
 
-	// Compare specs
-	fmt.Printf("Comparing specifications:\n")
-	fmt.Printf("  A: %s (%s, %d features)\n", fileA, specA.Product, len(specA.Features))
-	fmt.Printf("  B: %s (%s, %d features)\n\n", fileB, specB.Product, len(specB.Features))
-
-	// Product name difference
-	if specA.Product != specB.Product {
-		fmt.Printf("Product name changed:\n")
-		fmt.Printf("  - %s\n", specA.Product)
-		fmt.Printf("  + %s\n\n", specB.Product)
+	diffResult, err := spec.DiffSpecs(specA, specB)
+	if err != nil {
+		return ux.FormatError(err, "comparing specs")
 	}
 
-	// Build feature maps
-	featuresA := make(map[string]spec.Feature)
-	for _, f := range specA.Features {
-		featuresA[string(f.ID)] = f
+	// Handle quiet mode
+	if quiet {
+		if code := specDiffExitCode(diffResult); code != 0 {
+			os.Exit(code) // Exit code 2 indicates differences found
+		}
+		return nil // Exit code 0 indicates identical specs
 	}
 
-	featuresB := make(map[string]spec.Feature)
-	for _, f := range specB.Features {
-		featuresB[string(f.ID)] = f
+	// Handle JSON output
+	if jsonOutput {
+		output, marshalErr := json.MarshalIndent(diffResult, "", "  ")
+		if marshalErr != nil {
+			return ux.FormatError(marshalErr, "marshaling diff result")
+		}
+		fmt.Println(string(output))
+		return nil
 	}
 
-	// Find added features
-	added := []spec.Feature{}
-	for id, f := range featuresB {
-		if _, exists := featuresA[id]; !exists {
-			added = append(added, f)
-		}
+	fmt.Printf("Comparing specifications:\n")
+	fmt.Printf("  A: %s (%s, %d features)\n", fileA, specA.Product, len(specA.Features))
+	fmt.Printf("  B: %s (%s, %d features)\n\n", fileB, specB.Product, len(specB.Features))
+
+	displaySpecDiff(diffResult)
+
+	return nil
+}
+
+// specDiffExitCode returns the --quiet exit code for a spec diff result:
+// 2 when differences were found, 0 when the specs are identical
+func specDiffExitCode(diffResult *spec.DiffResult) int {
+	if diffResult.HasChanges() {
+		return 2
 	}
+	return 0
+}
 
-	// Find removed features
-	removed := []spec.Feature{}
-	for id, f := range featuresA {
-		if _, exists := featuresB[id]; !exists {
-			removed = append(removed, f)
-		}
+// displaySpecDiff prints a spec diff result in human-readable form
+func displaySpecDiff(diffResult *spec.DiffResult) {
+	if !diffResult.HasChanges() {
+		fmt.Println("No differences found")
+		return
 	}
 
-	// Find modified features
-	modified := []string{}
-	for id, fA := range featuresA {
-		if fB, exists := featuresB[id]; exists {
-			if fA.Title != fB.Title || fA.Desc != fB.Desc || fA.Priority != fB.Priority {
-				modified = append(modified, id)
-			}
-		}
+	if change := diffResult.ProductChange; change != nil {
+		fmt.Printf("Product name changed:\n")
+		fmt.Printf("  - %s\n", change.Old)
+		fmt.Printf("  + %s\n\n", change.New)
 	}
 
-	// Print differences
-	if len(added) > 0 {
-		fmt.Printf("Added features (%d):\n", len(added))
-		for _, f := range added {
+	if len(diffResult.FeaturesAdded) > 0 {
+		fmt.Printf("Added features (%d):\n", len(diffResult.FeaturesAdded))
+		for _, f := range diffResult.FeaturesAdded {
 			fmt.Printf("  + %s: %s [%s]\n", f.ID, f.Title, f.Priority)
 		}
 		fmt.Println()
 	}
 
-	if len(removed) > 0 {
-		fmt.Printf("Removed features (%d):\n", len(removed))
-		for _, f := range removed {
+	if len(diffResult.FeaturesRemoved) > 0 {
+		fmt.Printf("Removed features (%d):\n", len(diffResult.FeaturesRemoved))
+		for _, f := range diffResult.FeaturesRemoved {
 			fmt.Printf("  - %s: %s [%s]\n", f.ID, f.Title, f.Priority)
 		}
 		fmt.Println()
 	}
 
-	if len(modified) > 0 {
-		fmt.Printf("Modified features (%d):\n", len(modified))
-		for _, id := range modified {
-			fA := featuresA[id]
-			fB := featuresB[id]
-			fmt.Printf("  ~ %s:\n", id)
-			if fA.Title != fB.Title {
-				fmt.Printf("    Title: %s → %s\n", fA.Title, fB.Title)
-			}
-			if fA.Priority != fB.Priority {
-				fmt.Printf("    Priority: %s → %s\n", fA.Priority, fB.Priority)
-			}
-			if fA.Desc != fB.Desc {
-				fmt.Printf("    Description changed\n")
+	if len(diffResult.FeaturesModified) > 0 {
+		fmt.Printf("Modified features (%d):\n", len(diffResult.FeaturesModified))
+		for _, feature := range diffResult.FeaturesModified {
+			fmt.Printf("  ~ %s:\n", feature.ID)
+			for _, change := range feature.Changes {
+				switch {
+				case change.Field == spec.FieldDesc:
+					fmt.Printf("    Description changed\n")
+				case change.Old != "" || change.New != "":
+					fmt.Printf("    %s: %s → %s\n", change.Field, change.Old, change.New)
+				case len(change.Added) == 0 && len(change.Removed) == 0:
+					fmt.Printf("    %s: reordered\n", change.Field)
+				default:
+					fmt.Printf("    %s:\n", change.Field)
+					for _, item := range change.Removed {
+						fmt.Printf("      - %s\n", item)
+					}
+					for _, item := range change.Added {
+						fmt.Printf("      + %s\n", item)
+					}
+				}
 			}
 		}
 		fmt.Println()
 	}
 
-	if len(added) == 0 && len(removed) == 0 && len(modified) == 0 {
-		fmt.Println("No differences found")
-	}
-
-	return nil
+	fmt.Println(diffResult.Summary())
 }
 
 func runSpecApprove(cmd *cobra.Command, args []string) error {
@@ -759,6 +807,7 @@ func init() {
 	specCmd.AddCommand(specNewCmd)
 	specCmd.AddCommand(specGenerateCmd)
 	specCmd.AddCommand(specValidateCmd)
+	specCmd.AddCommand(specSchemaCmd)
 	specCmd.AddCommand(specLockCmd)
 	specCmd.AddCommand(specEditCmd)
 	specCmd.AddCommand(specDiffCmd)
@@ -769,12 +818,16 @@ func init() {
 	specGenerateCmd.Flags().String("config", ".specular/providers.yaml", "Provider configuration file")
 
 	specValidateCmd.Flags().StringP("in", "i", ".specular/spec.yaml", "Spec file to validate")
+	specValidateCmd.Flags().String("schema", "", "JSON Schema to validate against (default: built-in spec schema)")
 
 	specLockCmd.Flags().StringP("in", "i", ".specular/spec.yaml", "Input spec file")
 	specLockCmd.Flags().StringP("out", "o", ".specular/spec.lock.json", "Output SpecLock file")
-	specLockCmd.Flags().String("version", "1.0", "SpecLock version")
+	specLockCmd.Flags().String("version", "1.0.0", "SpecLock version (semantic version, e.g. 1.2.0)")
 	specLockCmd.Flags().String("note", "", "Add a note to the SpecLock (e.g., release notes or approval info)")
 
+	specDiffCmd.Flags().Bool("json", false, "Output diff as JSON")
+	specDiffCmd.Flags().BoolP("quiet", "q", false, "Quiet mode - only exit code (0=identical, 2=different)")
+
 	specNewCmd.Flags().StringP("out", "o", ".specular/spec.yaml", "Output path for generated spec")
 	specNewCmd.Flags().String("from", "", "Generate from PRD file instead of interactive mode")
 	specNewCmd.Flags().String("preset", "", "Use a preset template (use --list to see options)")
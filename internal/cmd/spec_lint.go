@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/drift"
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+var specLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a specification for common problems",
+	Long: `Lint a specification for problems that validation does not catch or that
+templates leave behind.
+
+Rules:
+  missing-acceptance-criteria      Feature has no acceptance criteria (error)
+  duplicate-feature-id             Feature ID is used more than once (error)
+  invalid-priority                 Priority is not P0, P1, or P2 (error)
+  empty-tasks                      Feature declares an empty task list (warning)
+  placeholder-acceptance-criteria  Acceptance criterion is a template placeholder,
+                                   such as "Feature works as described" (warning)
+
+Rules are configured in .specular/spec-lint.yaml (or --config):
+
+  rules:
+    empty-tasks:
+      enabled: false
+    placeholder-acceptance-criteria:
+      severity: error
+  placeholders:
+    - "Works as expected"
+
+Exits non-zero when any finding has error severity. Use --format sarif to
+produce a SARIF report for the same CI dashboards as drift detection.
+
+Examples:
+  specular spec lint
+  specular spec lint --in spec.yaml --format sarif > spec-lint.sarif
+  specular spec lint --report spec-lint.sarif`,
+	RunE: runSpecLint,
+}
+
+func runSpecLint(cmd *cobra.Command, args []string) error {
+	in, _ := cmd.Flags().GetString("in")
+	configPath, _ := cmd.Flags().GetString("config")
+	format, _ := cmd.Flags().GetString("format")
+	reportPath, _ := cmd.Flags().GetString("report")
+
+	switch format {
+	case "text", "json", "sarif":
+	default:
+		return fmt.Errorf("unknown format %q (expected text, json, or sarif)", format)
+	}
+
+	config, err := spec.LoadLintConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	report, err := spec.LintSpecFile(in, config)
+	if err != nil {
+		return fmt.Errorf("failed to lint spec: %w", err)
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+	case "sarif":
+		data, err := json.MarshalIndent(specLintSARIF(report), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize SARIF output: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		printSpecLintReport(os.Stdout, report)
+	}
+
+	if reportPath != "" {
+		if err := drift.SaveSARIF(specLintSARIF(report), reportPath); err != nil {
+			return fmt.Errorf("failed to save SARIF report: %w", err)
+		}
+		if format == "text" {
+			fmt.Printf("✓ SARIF report saved to %s\n", reportPath)
+		}
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("spec %s has %d lint error(s)", report.File, report.Count(spec.LintError))
+	}
+	return nil
+}
+
+// printSpecLintReport writes the findings of a spec lint
+func printSpecLintReport(w io.Writer, report *spec.LintReport) {
+	fmt.Fprintf(w, "🔍 Linting %s\n", report.File)
+	fmt.Fprintln(w)
+
+	for _, finding := range report.Findings {
+		icon := "❌"
+		switch finding.Severity {
+		case spec.LintWarning:
+			icon = "⚠️ "
+		case spec.LintInfo:
+			icon = "ℹ️ "
+		}
+		fmt.Fprintf(w, "   %s %s: %s [%s]\n", icon, finding.Location(), finding.Message, finding.Rule)
+	}
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	errors, warnings := report.Count(spec.LintError), report.Count(spec.LintWarning)
+	if errors == 0 {
+		fmt.Fprintf(w, "✅ No lint errors (%d warning(s))\n", warnings)
+	} else {
+		fmt.Fprintf(w, "❌ %d error(s), %d warning(s)\n", errors, warnings)
+	}
+}
+
+// specLintSARIF converts a spec lint report to the SARIF format used for
+// drift reports
+func specLintSARIF(report *spec.LintReport) *drift.SARIF {
+	results := []drift.SARIFResult{}
+	for _, finding := range report.Findings {
+		level := finding.Severity
+		if level == spec.LintInfo {
+			level = "note"
+		}
+
+		location := drift.SARIFPhysicalLocation{
+			ArtifactLocation: drift.SARIFArtifactLocation{URI: finding.File},
+		}
+		if finding.Line > 0 {
+			location.Region = &drift.SARIFRegion{StartLine: finding.Line}
+		}
+
+		results = append(results, drift.SARIFResult{
+			RuleID:    finding.Rule,
+			Level:     level,
+			Message:   drift.SARIFMessage{Text: finding.Message},
+			Locations: []drift.SARIFLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	return drift.NewSARIF(results)
+}
+
+func init() {
+	specLintCmd.Flags().StringP("in", "i", ".specular/spec.yaml", "Spec file to lint")
+	specLintCmd.Flags().String("config", spec.DefaultLintConfigPath, "Lint rule configuration file")
+	specLintCmd.Flags().String("format", "text", "Output format (text, json, sarif)")
+	specLintCmd.Flags().String("report", "", "Also write a SARIF report to this file")
+
+	specCmd.AddCommand(specLintCmd)
+}
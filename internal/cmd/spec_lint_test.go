@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+)
+
+func TestSpecLintSARIF(t *testing.T) {
+	report := &spec.LintReport{
+		File: "spec.yaml",
+		Findings: []spec.LintFinding{
+			{Rule: spec.RuleDuplicateFeatureID, Severity: spec.LintError, Message: "duplicate", File: "spec.yaml", Line: 12},
+			{Rule: spec.RuleEmptyTasks, Severity: spec.LintInfo, Message: "empty", File: "spec.yaml"},
+		},
+	}
+
+	sarif := specLintSARIF(report)
+	if len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 2 {
+		t.Fatalf("expected one run with two results, got %+v", sarif.Runs)
+	}
+
+	first := sarif.Runs[0].Results[0]
+	if first.RuleID != spec.RuleDuplicateFeatureID || first.Level != "error" {
+		t.Errorf("first result = %s/%s, want %s/error", first.RuleID, first.Level, spec.RuleDuplicateFeatureID)
+	}
+	location := first.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "spec.yaml" || location.Region == nil || location.Region.StartLine != 12 {
+		t.Errorf("first location = %+v, want spec.yaml line 12", location)
+	}
+
+	second := sarif.Runs[0].Results[1]
+	if second.Level != "note" {
+		t.Errorf("info finding level = %s, want note", second.Level)
+	}
+	if second.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("finding without a line should have no region")
+	}
+}
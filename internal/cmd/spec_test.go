@@ -209,40 +209,36 @@ func TestSpecDiffFeatureComparison(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Find added features
-			added := 0
-			for id := range tt.featuresB {
-				if _, exists := tt.featuresA[id]; !exists {
-					added++
-				}
+			specA := &spec.ProductSpec{Product: "Test Product"}
+			for _, f := range tt.featuresA {
+				specA.Features = append(specA.Features, f)
 			}
-
-			// Find removed features
-			removed := 0
-			for id := range tt.featuresA {
-				if _, exists := tt.featuresB[id]; !exists {
-					removed++
-				}
+			specB := &spec.ProductSpec{Product: "Test Product"}
+			for _, f := range tt.featuresB {
+				specB.Features = append(specB.Features, f)
 			}
 
-			// Find modified features
-			modified := 0
-			for id, fA := range tt.featuresA {
-				if fB, exists := tt.featuresB[id]; exists {
-					if fA.Title != fB.Title || fA.Desc != fB.Desc || fA.Priority != fB.Priority {
-						modified++
-					}
-				}
+			result, err := spec.DiffSpecs(specA, specB)
+			if err != nil {
+				t.Fatalf("DiffSpecs() error = %v", err)
 			}
 
-			if added != tt.wantAdded {
-				t.Errorf("added = %d, want %d", added, tt.wantAdded)
+			if got := len(result.FeaturesAdded); got != tt.wantAdded {
+				t.Errorf("added = %d, want %d", got, tt.wantAdded)
 			}
-			if removed != tt.wantRemoved {
-				t.Errorf("removed = %d, want %d", removed, tt.wantRemoved)
+			if got := len(result.FeaturesRemoved); got != tt.wantRemoved {
+				t.Errorf("removed = %d, want %d", got, tt.wantRemoved)
+			}
+			if got := len(result.FeaturesModified); got != tt.wantModified {
+				t.Errorf("modified = %d, want %d", got, tt.wantModified)
+			}
+
+			wantExit := 0
+			if tt.wantAdded+tt.wantRemoved+tt.wantModified > 0 {
+				wantExit = 2
 			}
-			if modified != tt.wantModified {
-				t.Errorf("modified = %d, want %d", modified, tt.wantModified)
+			if got := specDiffExitCode(result); got != wantExit {
+				t.Errorf("specDiffExitCode() = %d, want %d", got, wantExit)
 			}
 		})
 	}
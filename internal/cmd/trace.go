@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/felixgeelhaar/specular/internal/trace"
+	"github.com/felixgeelhaar/specular/internal/ux"
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Query auto mode trace logs",
+	Long: `Query the trace logs written by 'specular auto --trace' to ~/.specular/logs.
+
+Each trace file starts with a header naming its schema version
+(specular.trace/v1) followed by one JSON event per line.`,
+}
+
+var traceQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Filter trace events across runs or total them by step type",
+	Long: `Read every trace file and print the events that match all filters, oldest
+first, as a table or, with --format json, as JSON.
+
+--status matches step events (started, completed, failed) and workflow
+completion. --since and --until take a date (2006-01-02), an RFC 3339 time,
+or an age such as 24h or 7d. --min-cost matches events that record a cost.
+
+--group-by totals the completed and failed steps that match by step type,
+workflow, or step ID, with their cost and duration.
+
+Examples:
+  # Every failed step this week
+  specular trace query --status failed --since 7d
+
+  # Total cost by step type
+  specular trace query --group-by type
+
+  # Expensive build steps of one run
+  specular trace query --workflow auto-1762811730 --type build:run --min-cost 0.5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmdCtx, err := NewCommandContext(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to create command context: %w", err)
+		}
+
+		query, err := traceQueryFromFlags(cmd, time.Now())
+		if err != nil {
+			return err
+		}
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		if _, err := trace.Summarize(nil, groupBy); groupBy != "" && err != nil {
+			return err
+		}
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir = getLogDirectory()
+		}
+
+		files, errs := trace.ReadDir(dir)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping trace file: %v\n", err)
+		}
+		if len(files) == 0 && len(errs) == 0 {
+			fmt.Printf("No trace logs found in %s\n", dir)
+			return nil
+		}
+
+		events := query.Filter(files)
+		if groupBy != "" {
+			summaries, err := trace.Summarize(events, groupBy)
+			if err != nil {
+				return err
+			}
+			if cmdCtx.Format == "json" || cmdCtx.Format == "yaml" {
+				return formatTraceOutput(cmdCtx, summaries)
+			}
+			printTraceSummaries(os.Stdout, groupBy, summaries)
+			return nil
+		}
+
+		if cmdCtx.Format == "json" || cmdCtx.Format == "yaml" {
+			if events == nil {
+				events = []*trace.Event{}
+			}
+			return formatTraceOutput(cmdCtx, events)
+		}
+		printTraceEvents(os.Stdout, events)
+		return nil
+	},
+}
+
+// traceQueryFromFlags builds the query from the command flags. Relative
+// times are taken back from now.
+func traceQueryFromFlags(cmd *cobra.Command, now time.Time) (trace.Query, error) {
+	query := trace.Query{}
+	query.WorkflowID, _ = cmd.Flags().GetString("workflow")
+	query.StepTypes, _ = cmd.Flags().GetStringArray("type")
+	query.Status, _ = cmd.Flags().GetString("status")
+	query.MinCost, _ = cmd.Flags().GetFloat64("min-cost")
+
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	var err error
+	if query.Since, err = parseTraceTime(since, now, false); err != nil {
+		return query, fmt.Errorf("invalid --since %q: %w", since, err)
+	}
+	if query.Until, err = parseTraceTime(until, now, true); err != nil {
+		return query, fmt.Errorf("invalid --until %q: %w", until, err)
+	}
+
+	return query, query.Validate()
+}
+
+// parseTraceTime parses a date, an RFC 3339 time, or an age before now.
+// A date is the start of the day, or its end with endOfDay. An empty value
+// is the zero time.
+func parseTraceTime(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	age, err := parseValidityDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date (2006-01-02), an RFC 3339 time, or an age such as 24h or 7d")
+	}
+	return now.Add(-age), nil
+}
+
+func formatTraceOutput(cmdCtx *CommandContext, data interface{}) error {
+	formatter, err := ux.NewFormatter(cmdCtx.Format, &ux.FormatterOptions{
+		NoColor: cmdCtx.NoColor,
+	})
+	if err != nil {
+		return err
+	}
+	return formatter.Format(data)
+}
+
+// printTraceEvents writes matching events as a table
+func printTraceEvents(w io.Writer, events []*trace.Event) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No matching trace events")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tWORKFLOW\tSTEP\tTYPE\tSTATUS\tCOST\tDURATION\tMESSAGE") //nolint:errcheck
+	for _, event := range events {
+		cost := "-"
+		if value, ok := event.Cost(); ok {
+			cost = fmt.Sprintf("$%.4f", value)
+		}
+		duration := "-"
+		if event.Duration != nil {
+			duration = event.Duration.Round(time.Millisecond).String()
+		}
+		message := event.Message
+		if event.Error != "" {
+			message = fmt.Sprintf("%s: %s", message, event.Error)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
+			event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.WorkflowID,
+			orDash(event.StepID), orDash(event.StepType()), orDash(event.Status()),
+			cost, duration, message)
+	}
+	tw.Flush() //nolint:errcheck
+	fmt.Fprintf(w, "\n%d event(s)\n", len(events))
+}
+
+// printTraceSummaries writes step totals as a table
+func printTraceSummaries(w io.Writer, groupBy string, summaries []trace.Summary) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No matching completed or failed steps")
+		return
+	}
+
+	headers := map[string]string{
+		trace.GroupByStepType: "STEP TYPE",
+		trace.GroupByWorkflow: "WORKFLOW",
+		trace.GroupByStep:     "STEP",
+	}
+
+	var total trace.Summary
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tSTEPS\tFAILED\tCOST\tDURATION\n", headers[groupBy]) //nolint:errcheck
+	for _, summary := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t$%.4f\t%s\n", //nolint:errcheck
+			summary.Key, summary.Steps, summary.Failed, summary.CostUSD, summary.Duration.Round(time.Millisecond))
+		total.Steps += summary.Steps
+		total.Failed += summary.Failed
+		total.CostUSD += summary.CostUSD
+		total.Duration += summary.Duration
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\t$%.4f\t%s\n", //nolint:errcheck
+		total.Steps, total.Failed, total.CostUSD, total.Duration.Round(time.Millisecond))
+	tw.Flush() //nolint:errcheck
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	traceQueryCmd.Flags().String("workflow", "", "Only events of this workflow ID")
+	traceQueryCmd.Flags().StringArray("type", []string{}, "Only steps of this type, such as build:run (can be used multiple times)")
+	traceQueryCmd.Flags().String("status", "", "Only events with this status (started, completed, failed)")
+	traceQueryCmd.Flags().String("since", "", "Only events at or after this date, time, or age (e.g., 2026-10-01, 7d)")
+	traceQueryCmd.Flags().String("until", "", "Only events at or before this date, time, or age")
+	traceQueryCmd.Flags().Float64("min-cost", 0, "Only events that cost at least this many USD")
+	traceQueryCmd.Flags().String("group-by", "", "Total completed and failed steps by type, workflow, or step")
+	traceQueryCmd.Flags().String("dir", "", "Trace log directory (default: ~/.specular/logs)")
+
+	traceCmd.AddCommand(traceQueryCmd)
+	rootCmd.AddCommand(traceCmd)
+}
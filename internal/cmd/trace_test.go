@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/trace"
+)
+
+func TestParseTraceTime(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "36h", want: now.Add(-36 * time.Hour)},
+		{value: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-10-01", endOfDay: true, want: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)},
+		{value: "2026-10-01T08:30:00Z", endOfDay: true, want: time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTraceTime(tt.value, now, tt.endOfDay)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTraceTime() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTraceTime(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTraceSummaries(t *testing.T) {
+	var buf bytes.Buffer
+	printTraceSummaries(&buf, trace.GroupByStepType, []trace.Summary{
+		{Key: "build:run", Steps: 3, Failed: 1, CostUSD: 1.5, Duration: time.Minute},
+		{Key: "spec:update", Steps: 2, CostUSD: 0.25, Duration: 4 * time.Second},
+	})
+
+	out := buf.String()
+	for _, want := range []string{"STEP TYPE", "build:run", "$1.5000", "TOTAL", "$1.7500", "1m4s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheVersion is bumped when the cache format or the parsers change, so
+// results from an older specular are not reused
+const cacheVersion = "1"
+
+// Cache remembers the content hashes of scanned files and the routes parsed
+// from each content hash, so files that have not changed since the last scan
+// are neither re-read nor re-parsed. Entries are discarded when the SpecLock
+// changes. A nil *Cache disables caching.
+type Cache struct {
+	Dir string
+
+	mu       sync.Mutex
+	lockHash string
+	files    map[string]*CachedFile
+	routes   map[string][]Route
+	used     map[string]bool
+	dirty    bool
+}
+
+// CachedFile is the last seen state of a scanned file
+type CachedFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash"`
+}
+
+// cacheManifest is the on-disk form of the cache
+type cacheManifest struct {
+	Version   string                 `json:"version"`
+	LockHash  string                 `json:"lock_hash"`
+	Files     map[string]*CachedFile `json:"files"`
+	Routes    map[string][]Route     `json:"routes"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// LoadCache loads the drift cache from dir. Entries recorded against a
+// different lockFile content, or by another cache version, are discarded.
+// A missing cache starts empty.
+func LoadCache(dir, lockFile string) (*Cache, error) {
+	lockHash, err := hashFile(lockFile)
+	if err != nil {
+		return nil, fmt.Errorf("hash spec lock: %w", err)
+	}
+
+	c := &Cache{
+		Dir:      dir,
+		lockHash: lockHash,
+		files:    make(map[string]*CachedFile),
+		routes:   make(map[string][]Route),
+		used:     make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json")) // #nosec G304 -- Cache lives in the project's .specular directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("read drift cache: %w", err)
+	}
+
+	var manifest cacheManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		// A corrupt cache is rebuilt rather than failing drift detection
+		c.dirty = true
+		return c, nil
+	}
+	if manifest.Version != cacheVersion || manifest.LockHash != lockHash {
+		c.dirty = true
+		return c, nil
+	}
+
+	if manifest.Files != nil {
+		c.files = manifest.Files
+	}
+	if manifest.Routes != nil {
+		c.routes = manifest.Routes
+	}
+	return c, nil
+}
+
+// Save writes the cache to disk if it changed. Entries for files and
+// contents not seen since the cache was loaded are dropped.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	files := make(map[string]*CachedFile, len(c.used))
+	routes := make(map[string][]Route)
+	for path, file := range c.files {
+		if !c.used[path] {
+			continue
+		}
+		files[path] = file
+		if r, ok := c.routes[file.Hash]; ok {
+			routes[file.Hash] = r
+		}
+	}
+
+	if err := os.MkdirAll(c.Dir, 0750); err != nil {
+		return fmt.Errorf("create drift cache dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cacheManifest{
+		Version:   cacheVersion,
+		LockHash:  c.lockHash,
+		Files:     files,
+		Routes:    routes,
+		UpdatedAt: time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal drift cache: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.Dir, "manifest.json"), data, 0600); err != nil {
+		return fmt.Errorf("write drift cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// cachedHash returns the recorded content hash of path if its size and
+// modification time are unchanged since it was hashed
+func (c *Cache) cachedHash(path string, info os.FileInfo) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, ok := c.files[path]
+	if !ok || file.Size != info.Size() || !file.ModTime.Equal(info.ModTime()) {
+		return "", false
+	}
+	c.used[path] = true
+	return file.Hash, true
+}
+
+// storeHash records the content hash of path
+func (c *Cache) storeHash(path string, info os.FileInfo, hash string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files[path] = &CachedFile{Size: info.Size(), ModTime: info.ModTime(), Hash: hash}
+	c.used[path] = true
+	c.dirty = true
+}
+
+// cachedRoutes returns the routes parsed from a content hash, with File set
+// to file
+func (c *Cache) cachedRoutes(hash, file string) ([]Route, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.routes[hash]
+	if !ok {
+		return nil, false
+	}
+	routes := make([]Route, len(cached))
+	for i, route := range cached {
+		route.File = file
+		routes[i] = route
+	}
+	return routes, true
+}
+
+// storeRoutes records the routes parsed from a content hash
+func (c *Cache) storeRoutes(hash string, routes []Route) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := make([]Route, len(routes))
+	for i, route := range routes {
+		route.File = ""
+		stored[i] = route
+	}
+	c.routes[hash] = stored
+	c.dirty = true
+}
+
+// hashFile returns the SHA-256 hash of a file's content, reusing the cached
+// hash when the file has not changed
+func (c *Cache) hashFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if hash, ok := c.cachedHash(path, info); ok {
+		return hash, nil
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		return "", err
+	}
+	c.storeHash(path, info, hash)
+	return hash, nil
+}
+
+// readFile reads a file and returns its content hash. Content is nil when
+// the cached hash of an unchanged file is reused and the file was not read.
+func (c *Cache) readFile(path string, info os.FileInfo) ([]byte, string, error) {
+	if hash, ok := c.cachedHash(path, info); ok {
+		if _, cached := c.cachedRoutes(hash, ""); cached {
+			return nil, hash, nil
+		}
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304 -- Walking the user's own project
+	if err != nil {
+		return nil, "", err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	c.storeHash(path, info, hash)
+	return content, hash, nil
+}
@@ -0,0 +1,117 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_ReusesUnchangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, ".specular", "cache", "drift")
+	lockFile := filepath.Join(tmpDir, "spec.lock.json")
+	srcDir := filepath.Join(tmpDir, "src")
+	routesFile := filepath.Join(srcDir, "users.js")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, lockFile, `{"version":"1"}`)
+	writeFile(t, routesFile, "app.get('/users', list);\n")
+
+	cache, err := LoadCache(cacheDir, lockFile)
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	routes, err := extractJSRoutes(srcDir, nil, cache)
+	if err != nil {
+		t.Fatalf("extractJSRoutes: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Path != "/users" {
+		t.Fatalf("routes = %+v, want GET /users", routes)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Same size and modification time: the cached routes are reused
+	info, _ := os.Stat(routesFile)
+	writeFile(t, routesFile, "app.get('/posts', list);\n")
+	if err := os.Chtimes(routesFile, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err = LoadCache(cacheDir, lockFile)
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	routes, _ = extractJSRoutes(srcDir, nil, cache)
+	if len(routes) != 1 || routes[0].Path != "/users" || routes[0].File != "users.js" {
+		t.Errorf("routes = %+v, want cached GET /users in users.js", routes)
+	}
+
+	// A modified file is re-parsed
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(routesFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	routes, _ = extractJSRoutes(srcDir, nil, cache)
+	if len(routes) != 1 || routes[0].Path != "/posts" {
+		t.Errorf("routes = %+v, want re-parsed GET /posts", routes)
+	}
+}
+
+func TestCache_InvalidatedBySpecLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	lockFile := filepath.Join(tmpDir, "spec.lock.json")
+	traced := filepath.Join(tmpDir, "users_test.go")
+	writeFile(t, lockFile, `{"version":"1"}`)
+	writeFile(t, traced, "package users")
+
+	cache, err := LoadCache(tmpDir, lockFile)
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	if _, err := cache.hashFile(traced); err != nil {
+		t.Fatalf("hashFile: %v", err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cache, _ = LoadCache(tmpDir, lockFile)
+	if len(cache.files) != 1 {
+		t.Errorf("cached files = %d, want 1", len(cache.files))
+	}
+
+	writeFile(t, lockFile, `{"version":"2"}`)
+	cache, _ = LoadCache(tmpDir, lockFile)
+	if len(cache.files) != 0 {
+		t.Errorf("cached files after spec lock change = %d, want 0", len(cache.files))
+	}
+
+	if _, err := LoadCache(tmpDir, filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("expected error for missing spec lock")
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var cache *Cache
+	path := filepath.Join(t.TempDir(), "a.txt")
+	writeFile(t, path, "hello")
+
+	hash, err := cache.hashFile(path)
+	if err != nil || hash == "" {
+		t.Errorf("hashFile = %q, %v", hash, err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Errorf("Save on nil cache: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
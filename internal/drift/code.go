@@ -17,6 +17,8 @@ type CodeDriftOptions struct {
 	TracePaths  []string // File paths to track for drift
 	APISpecPath string   // Path to OpenAPI spec (if applicable)
 	IgnoreGlobs []string // Patterns to ignore (e.g., "*.test.js")
+	Language    string   // Project language for route conformance: "javascript" or "typescript" (empty = skip)
+	Cache       *Cache   // Cache of file hashes and parsed routes (nil = no caching)
 }
 
 // DetectCodeDrift checks for code drift against the specification
@@ -31,6 +33,12 @@ func DetectCodeDrift(s *spec.ProductSpec, lock *spec.SpecLock, opts CodeDriftOpt
 	apiFindings := checkAPIImplementations(s, opts)
 	findings = append(findings, apiFindings...)
 
+	// Check declared routes against the API spec
+	if opts.Language != "" {
+		routeFindings := checkRouteConformance(s, opts)
+		findings = append(findings, routeFindings...)
+	}
+
 	// Check test coverage for features
 	testFindings := checkTestCoverage(s, opts)
 	findings = append(findings, testFindings...)
@@ -62,7 +70,7 @@ func checkFileHashes(s *spec.ProductSpec, lock *spec.SpecLock, opts CodeDriftOpt
 			}
 
 			// Compute current hash
-			currentHash, err := hashFile(filepath.Join(opts.ProjectRoot, testPath))
+			currentHash, err := opts.Cache.hashFile(filepath.Join(opts.ProjectRoot, testPath))
 			if err != nil {
 				findings = append(findings, Finding{
 					Code:      "HASH_ERROR",
@@ -0,0 +1,89 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Report formats written by SaveReport
+const (
+	FormatSARIF = "sarif"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// formatExtensions are the file extensions of each report format
+var formatExtensions = map[string]string{
+	FormatSARIF: ".sarif",
+	FormatJSON:  ".json",
+	FormatJUnit: ".xml",
+}
+
+// ParseReportFormats parses a comma-separated list of report formats.
+// Duplicates are removed and an empty list defaults to SARIF.
+func ParseReportFormats(value string) ([]string, error) {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, format := range strings.Split(value, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" || seen[format] {
+			continue
+		}
+		if _, ok := formatExtensions[format]; !ok {
+			return nil, fmt.Errorf("unknown report format %q (use sarif, json, or junit)", format)
+		}
+		seen[format] = true
+		formats = append(formats, format)
+	}
+
+	if len(formats) == 0 {
+		return []string{FormatSARIF}, nil
+	}
+	return formats, nil
+}
+
+// ReportPath replaces path's extension with the report format's own, so
+// several formats can be written next to each other (drift.sarif,
+// drift.json, drift.xml)
+func ReportPath(path, format string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + formatExtensions[format]
+}
+
+// SaveReport writes the report to path in the given format
+func SaveReport(r *Report, format, path string) error {
+	switch format {
+	case FormatSARIF:
+		return SaveSARIF(r.ToSARIF(), path)
+	case FormatJSON:
+		return SaveJSON(r, path)
+	case FormatJUnit:
+		return SaveJUnit(r.ToJUnit(), path)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// SaveJSON writes a drift report to disk as JSON
+func SaveJSON(r *Report, path string) error {
+	// Write empty categories as [] rather than null
+	out := *r
+	for _, findings := range []*[]Finding{&out.PlanDrift, &out.CodeDrift, &out.InfraDrift} {
+		if *findings == nil {
+			*findings = []Finding{}
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal JSON report: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write JSON report: %w", err)
+	}
+
+	return nil
+}
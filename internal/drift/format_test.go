@@ -0,0 +1,90 @@
+package drift
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseReportFormats(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"", "sarif", false},
+		{"sarif", "sarif", false},
+		{"json", "json", false},
+		{"sarif, JSON,junit", "sarif,json,junit", false},
+		{"junit,junit", "junit", false},
+		{"html", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseReportFormats(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("formats = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportPath(t *testing.T) {
+	tests := []struct {
+		path, format, want string
+	}{
+		{"drift.sarif", FormatSARIF, "drift.sarif"},
+		{"drift.sarif", FormatJSON, "drift.json"},
+		{"reports/drift.sarif", FormatJUnit, "reports/drift.xml"},
+		{"drift", FormatJSON, "drift.json"},
+	}
+
+	for _, tt := range tests {
+		if got := ReportPath(tt.path, tt.format); got != tt.want {
+			t.Errorf("ReportPath(%q, %q) = %q, want %q", tt.path, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSaveReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	report := GenerateReport([]Finding{{Code: "UNKNOWN_FEATURE", Message: "unknown", Severity: "error"}}, nil, nil)
+
+	for _, format := range []string{FormatSARIF, FormatJSON, FormatJUnit} {
+		path := ReportPath(filepath.Join(tmpDir, "drift"), format)
+		if err := SaveReport(report, format, path); err != nil {
+			t.Fatalf("SaveReport(%s): %v", format, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s report not written: %v", format, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "drift.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("JSON report is invalid: %v", err)
+	}
+	if string(parsed["code_drift"]) != "[]" {
+		t.Errorf("code_drift = %s, want []", parsed["code_drift"])
+	}
+
+	if err := SaveReport(report, "html", filepath.Join(tmpDir, "drift.html")); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,109 @@
+package drift
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the findings of one drift category
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a single drift finding
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"` // Finding severity
+	Type    string `xml:"type,attr"`    // Finding code
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit converts a drift report to JUnit XML. Each drift category is a
+// test suite and each finding a failing test case.
+func (r *Report) ToJUnit() *JUnitTestSuites {
+	junit := &JUnitTestSuites{Name: "specular drift"}
+
+	categories := []struct {
+		name     string
+		findings []Finding
+	}{
+		{"plan-drift", r.PlanDrift},
+		{"code-drift", r.CodeDrift},
+		{"infra-drift", r.InfraDrift},
+	}
+
+	for _, category := range categories {
+		suite := JUnitTestSuite{
+			Name:      category.name,
+			Tests:     len(category.findings),
+			Failures:  len(category.findings),
+			TestCases: []JUnitTestCase{},
+		}
+		for _, finding := range category.findings {
+			suite.TestCases = append(suite.TestCases, convertFindingToJUnit(category.name, finding))
+		}
+
+		junit.Tests += suite.Tests
+		junit.Failures += suite.Failures
+		junit.Suites = append(junit.Suites, suite)
+	}
+
+	return junit
+}
+
+// convertFindingToJUnit converts a drift finding to a failing test case
+func convertFindingToJUnit(category string, finding Finding) JUnitTestCase {
+	name := finding.Code
+	if finding.Location != "" {
+		name = fmt.Sprintf("%s %s", finding.Code, finding.Location)
+	}
+
+	className := category
+	if finding.FeatureID != "" {
+		className = fmt.Sprintf("%s.%s", category, finding.FeatureID)
+	}
+
+	return JUnitTestCase{
+		Name:      name,
+		ClassName: className,
+		Failure: &JUnitFailure{
+			Message: finding.Severity,
+			Type:    finding.Code,
+			Text:    finding.Message,
+		},
+	}
+}
+
+// SaveJUnit writes a JUnit XML report to disk
+func SaveJUnit(junit *JUnitTestSuites, path string) error {
+	data, err := xml.MarshalIndent(junit, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal JUnit: %w", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write JUnit file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package drift
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToJUnit(t *testing.T) {
+	report := GenerateReport(
+		[]Finding{{Code: "UNKNOWN_FEATURE", FeatureID: "feat-9", Message: "Plan references unknown feature", Severity: "error"}},
+		[]Finding{
+			{Code: "MISSING_TRACE", FeatureID: "feat-1", Message: "Traced file missing: src/a.go", Severity: "error", Location: "src/a.go"},
+			{Code: "NO_TESTS", FeatureID: "feat-2", Message: "P1 feature has no tests", Severity: "warning"},
+		},
+		nil,
+	)
+
+	junit := report.ToJUnit()
+
+	if junit.Tests != 3 || junit.Failures != 3 {
+		t.Errorf("tests=%d failures=%d, want 3 and 3", junit.Tests, junit.Failures)
+	}
+	if len(junit.Suites) != 3 {
+		t.Fatalf("suites = %d, want 3", len(junit.Suites))
+	}
+
+	var names []string
+	for _, suite := range junit.Suites {
+		names = append(names, suite.Name)
+	}
+	if strings.Join(names, ",") != "plan-drift,code-drift,infra-drift" {
+		t.Errorf("suite names = %v", names)
+	}
+
+	code := junit.Suites[1]
+	if code.Tests != 2 || len(code.TestCases) != 2 {
+		t.Fatalf("code-drift suite = %+v, want 2 test cases", code)
+	}
+	tc := code.TestCases[0]
+	if tc.Name != "MISSING_TRACE src/a.go" || tc.ClassName != "code-drift.feat-1" {
+		t.Errorf("test case = %q (%q)", tc.Name, tc.ClassName)
+	}
+	if tc.Failure == nil || tc.Failure.Message != "error" || tc.Failure.Type != "MISSING_TRACE" || tc.Failure.Text != "Traced file missing: src/a.go" {
+		t.Errorf("failure = %+v", tc.Failure)
+	}
+	if code.TestCases[1].Failure.Message != "warning" {
+		t.Errorf("warning finding failure message = %q", code.TestCases[1].Failure.Message)
+	}
+
+	if infra := junit.Suites[2]; infra.Tests != 0 || len(infra.TestCases) != 0 {
+		t.Errorf("infra-drift suite = %+v, want empty", infra)
+	}
+}
+
+func TestSaveJUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drift.xml")
+	report := GenerateReport(nil, []Finding{{Code: "NO_TESTS", Message: "a <b> & c", Severity: "error"}}, nil)
+
+	if err := SaveJUnit(report.ToJUnit(), path); err != nil {
+		t.Fatalf("SaveJUnit: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "<?xml") {
+		t.Errorf("report missing XML header:\n%s", data)
+	}
+
+	var parsed JUnitTestSuites
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("report is not valid XML: %v", err)
+	}
+	if parsed.Failures != 1 || parsed.Suites[1].TestCases[0].Failure.Text != "a <b> & c" {
+		t.Errorf("round trip = %+v", parsed)
+	}
+}
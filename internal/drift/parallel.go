@@ -0,0 +1,90 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Detector is a named, independent drift detection phase
+type Detector struct {
+	Name   string
+	Detect func(ctx context.Context) ([]Finding, error)
+}
+
+// DetectorResult holds the outcome of a single detector run
+type DetectorResult struct {
+	Name     string
+	Findings []Finding
+	Err      error
+	Duration time.Duration
+}
+
+// RunDetectors runs the detectors concurrently and returns their results in
+// the order the detectors were given, regardless of completion order.
+// onDone, if set, is called once per detector as it finishes; calls are
+// serialized so it may update shared state such as checkpoints. The returned
+// error joins the errors of all failed detectors.
+func RunDetectors(ctx context.Context, detectors []Detector, onDone func(DetectorResult)) ([]DetectorResult, error) {
+	results := make([]DetectorResult, len(detectors))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i, detector := range detectors {
+		wg.Add(1)
+		go func(i int, detector Detector) {
+			defer wg.Done()
+
+			start := time.Now()
+			findings, err := detector.Detect(ctx)
+			SortFindings(findings)
+
+			result := DetectorResult{
+				Name:     detector.Name,
+				Findings: findings,
+				Err:      err,
+				Duration: time.Since(start),
+			}
+			results[i] = result
+
+			if onDone != nil {
+				mu.Lock()
+				onDone(result)
+				mu.Unlock()
+			}
+		}(i, detector)
+	}
+
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// SortFindings orders findings by location, code, feature, and message so
+// reports are stable across runs
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		if a.FeatureID != b.FeatureID {
+			return a.FeatureID < b.FeatureID
+		}
+		return a.Message < b.Message
+	})
+}
@@ -0,0 +1,141 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// barrierDetector returns a detector that blocks until every detector sharing
+// the barrier has started, proving they run concurrently, then finishes after
+// the given delay
+func barrierDetector(name string, started *sync.WaitGroup, delay time.Duration, findings []Finding) Detector {
+	return Detector{
+		Name: name,
+		Detect: func(ctx context.Context) ([]Finding, error) {
+			started.Done()
+
+			done := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				return nil, errors.New("detectors did not run concurrently")
+			}
+
+			time.Sleep(delay)
+			return append([]Finding(nil), findings...), nil
+		},
+	}
+}
+
+func TestRunDetectors_Concurrent(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(3)
+
+	planFindings := []Finding{
+		{Code: "MISSING_TASK", Location: "feature:b", Severity: "warning"},
+		{Code: "MISSING_TASK", Location: "feature:a", Severity: "warning"},
+	}
+	codeFindings := []Finding{{Code: "MISSING_API_SPEC", Location: "api.yaml", Severity: "error"}}
+	infraFindings := []Finding{{Code: "IMAGE_NOT_ALLOWED", Location: "task:1", Severity: "error"}}
+
+	// Completion order is the reverse of the input order
+	detectors := []Detector{
+		barrierDetector("plan-drift", &started, 60*time.Millisecond, planFindings),
+		barrierDetector("code-drift", &started, 30*time.Millisecond, codeFindings),
+		barrierDetector("infra-drift", &started, 0, infraFindings),
+	}
+
+	var completed []string
+	results, err := RunDetectors(context.Background(), detectors, func(result DetectorResult) {
+		completed = append(completed, result.Name)
+	})
+	if err != nil {
+		t.Fatalf("RunDetectors() error = %v", err)
+	}
+
+	if want := []string{"infra-drift", "code-drift", "plan-drift"}; !reflect.DeepEqual(completed, want) {
+		t.Errorf("completion order = %v, want %v", completed, want)
+	}
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	if want := []string{"plan-drift", "code-drift", "infra-drift"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("result order = %v, want %v", names, want)
+	}
+
+	if results[0].Findings[0].Location != "feature:a" {
+		t.Errorf("findings should be sorted, got %v", results[0].Findings)
+	}
+}
+
+func TestRunDetectors_StableReport(t *testing.T) {
+	newDetectors := func(planDelay, codeDelay time.Duration) []Detector {
+		var started sync.WaitGroup
+		started.Add(2)
+		return []Detector{
+			barrierDetector("plan-drift", &started, planDelay, []Finding{
+				{Code: "HASH_MISMATCH", Location: "task:2", Severity: "error"},
+				{Code: "HASH_MISMATCH", Location: "task:1", Severity: "error"},
+			}),
+			barrierDetector("code-drift", &started, codeDelay, []Finding{
+				{Code: "MISSING_TEST", Location: "b_test.go", Severity: "warning"},
+				{Code: "MISSING_TEST", Location: "a_test.go", Severity: "warning"},
+			}),
+		}
+	}
+
+	report := func(detectors []Detector) *Report {
+		results, err := RunDetectors(context.Background(), detectors, nil)
+		if err != nil {
+			t.Fatalf("RunDetectors() error = %v", err)
+		}
+		return GenerateReport(results[0].Findings, results[1].Findings, nil)
+	}
+
+	first := report(newDetectors(40*time.Millisecond, 0))
+	second := report(newDetectors(0, 40*time.Millisecond))
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("report depends on completion order:\n%+v\n%+v", first, second)
+	}
+}
+
+func TestRunDetectors_AggregatesErrors(t *testing.T) {
+	detectors := []Detector{
+		{Name: "plan-drift", Detect: func(ctx context.Context) ([]Finding, error) {
+			return nil, errors.New("plan unreadable")
+		}},
+		{Name: "code-drift", Detect: func(ctx context.Context) ([]Finding, error) {
+			return []Finding{{Code: "OK"}}, nil
+		}},
+		{Name: "infra-drift", Detect: func(ctx context.Context) ([]Finding, error) {
+			return nil, errors.New("policy unreadable")
+		}},
+	}
+
+	results, err := RunDetectors(context.Background(), detectors, nil)
+	if err == nil {
+		t.Fatal("RunDetectors() expected error")
+	}
+	for _, want := range []string{"plan-drift: plan unreadable", "infra-drift: policy unreadable"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+
+	if len(results[1].Findings) != 1 || results[1].Err != nil {
+		t.Errorf("successful detector result should be kept, got %+v", results[1])
+	}
+}
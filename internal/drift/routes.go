@@ -0,0 +1,336 @@
+package drift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// Route is an HTTP route declared in the project's source code
+type Route struct {
+	Method string // Upper-case HTTP method, or "ALL"
+	Path   string // Normalized path with {param} segments
+	File   string // Path relative to the project root
+	Line   int
+}
+
+// jsRouteMethods are the route methods Express and Fastify declare
+const jsRouteMethods = `get|post|put|patch|delete|head|options|all`
+
+var (
+	// jsShorthandRoute matches app.get('/users', ...) and friends. The
+	// receiver must look like an app, router, or server so HTTP client
+	// calls such as axios.get('/users') are not mistaken for routes.
+	jsShorthandRoute = regexp.MustCompile(`\b(?:app|server|fastify|instance|api|router|routes|\w+Router|\w+App)\s*\.\s*(` + jsRouteMethods + `)\s*\(\s*['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`)
+
+	// jsFullRoute matches the start of fastify.route({ method, url, ... })
+	jsFullRoute = regexp.MustCompile(`\.\s*route\s*\(\s*\{`)
+
+	jsRouteURL     = regexp.MustCompile(`\b(?:url|path)\s*:\s*['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`)
+	jsRouteMethod  = regexp.MustCompile(`\bmethod\s*:\s*(\[[^\]]*\]|['"` + "`" + `]\w+['"` + "`" + `])`)
+	jsQuotedString = regexp.MustCompile(`['"` + "`" + `](\w+)['"` + "`" + `]`)
+
+	// expressParam matches an Express path parameter such as :id or :id?
+	expressParam = regexp.MustCompile(`:(\w+)\??`)
+)
+
+// jsSourceExtensions are the files scanned for JavaScript/TypeScript routes
+var jsSourceExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".cjs": true, ".jsx": true,
+	".ts": true, ".mts": true, ".cts": true, ".tsx": true,
+}
+
+// jsSkippedDirs are directories that never hold the project's own routes
+var jsSkippedDirs = map[string]bool{
+	"node_modules": true, "dist": true, "build": true, "coverage": true, "out": true,
+}
+
+// normalizeLanguage maps a CodeDriftOptions.Language value to "javascript"
+// or "typescript", or returns it unchanged if it is not supported
+func normalizeLanguage(language string) string {
+	switch strings.ToLower(language) {
+	case "js", "javascript", "node":
+		return "javascript"
+	case "ts", "typescript":
+		return "typescript"
+	default:
+		return language
+	}
+}
+
+// ExtractJSRoutes finds Express and Fastify route declarations in the
+// JavaScript and TypeScript files under root. Files whose name matches one
+// of ignoreGlobs are skipped.
+func ExtractJSRoutes(root string, ignoreGlobs []string) ([]Route, error) {
+	return extractJSRoutes(root, ignoreGlobs, nil)
+}
+
+// extractJSRoutes is ExtractJSRoutes, reusing the routes parsed from
+// unchanged files when cache is not nil
+func extractJSRoutes(root string, ignoreGlobs []string, cache *Cache) ([]Route, error) {
+	var routes []Route
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && (jsSkippedDirs[info.Name()] || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !jsSourceExtensions[filepath.Ext(path)] || strings.HasSuffix(path, ".d.ts") {
+			return nil
+		}
+		if shouldIgnore(path, ignoreGlobs) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		content, hash, err := cache.readFile(path, info)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		if cached, ok := cache.cachedRoutes(hash, relPath); ok {
+			routes = append(routes, cached...)
+			return nil
+		}
+
+		parsed := parseJSRoutes(string(content), relPath)
+		cache.storeRoutes(hash, parsed)
+		routes = append(routes, parsed...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// parseJSRoutes extracts the routes declared in one source file
+func parseJSRoutes(source, file string) []Route {
+	var routes []Route
+	lineOf := func(offset int) int {
+		return strings.Count(source[:offset], "\n") + 1
+	}
+
+	for _, match := range jsShorthandRoute.FindAllStringSubmatchIndex(source, -1) {
+		routes = append(routes, Route{
+			Method: strings.ToUpper(source[match[2]:match[3]]),
+			Path:   expressToOpenAPIPath(source[match[4]:match[5]]),
+			File:   file,
+			Line:   lineOf(match[0]),
+		})
+	}
+
+	for _, match := range jsFullRoute.FindAllStringIndex(source, -1) {
+		options := braceBlock(source, match[1]-1)
+		url := jsRouteURL.FindStringSubmatch(options)
+		method := jsRouteMethod.FindStringSubmatch(options)
+		if url == nil || method == nil {
+			continue
+		}
+		for _, m := range jsQuotedString.FindAllStringSubmatch(method[1], -1) {
+			routes = append(routes, Route{
+				Method: strings.ToUpper(m[1]),
+				Path:   expressToOpenAPIPath(url[1]),
+				File:   file,
+				Line:   lineOf(match[0]),
+			})
+		}
+	}
+
+	return routes
+}
+
+// braceBlock returns the text from the opening brace at start to its
+// matching closing brace, or to the end of source if it is unbalanced
+func braceBlock(source string, start int) string {
+	depth := 0
+	for i := start; i < len(source); i++ {
+		switch source[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return source[start : i+1]
+			}
+		}
+	}
+	return source[start:]
+}
+
+// expressToOpenAPIPath converts an Express or Fastify path such as
+// /users/:id to the OpenAPI form /users/{id}
+func expressToOpenAPIPath(path string) string {
+	return normalizePath(expressParam.ReplaceAllString(path, "{$1}"))
+}
+
+// routeMatchesPath reports whether a code route path matches a spec path.
+// Parameters match any segment. Routers are often mounted under a prefix
+// (app.use('/api', router)) and specs under a server base path, so the
+// shorter path only has to match the end of the longer one, starting at
+// the same literal segment.
+func routeMatchesPath(routePath, specPath string) bool {
+	routeSegments := strings.Split(strings.Trim(routePath, "/"), "/")
+	specSegments := strings.Split(strings.Trim(specPath, "/"), "/")
+	if len(routeSegments) != len(specSegments) {
+		if len(routeSegments) > len(specSegments) {
+			routeSegments = routeSegments[len(routeSegments)-len(specSegments):]
+		} else {
+			specSegments = specSegments[len(specSegments)-len(routeSegments):]
+		}
+		if isPathParam(routeSegments[0]) || routeSegments[0] != specSegments[0] {
+			return false
+		}
+	}
+
+	for i, segment := range routeSegments {
+		if isPathParam(segment) || isPathParam(specSegments[i]) {
+			continue
+		}
+		if segment != specSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// routeMatches reports whether a code route handles the method and path
+func routeMatches(route Route, method, path string) bool {
+	if route.Method != "ALL" && route.Method != method {
+		return false
+	}
+	return routeMatchesPath(route.Path, path)
+}
+
+// checkRouteConformance compares the routes declared in JavaScript or
+// TypeScript code with the OpenAPI spec and the features' APIs
+func checkRouteConformance(s *spec.ProductSpec, opts CodeDriftOptions) []Finding {
+	var findings []Finding
+
+	language := normalizeLanguage(opts.Language)
+	if language != "javascript" && language != "typescript" {
+		return []Finding{{
+			Code:     "UNSUPPORTED_LANGUAGE",
+			Message:  fmt.Sprintf("Route conformance is not supported for language %q (use javascript or typescript)", opts.Language),
+			Severity: "warning",
+		}}
+	}
+
+	root := opts.ProjectRoot
+	if root == "" {
+		root = "."
+	}
+	routes, err := extractJSRoutes(root, opts.IgnoreGlobs, opts.Cache)
+	if err != nil {
+		return []Finding{{
+			Code:     "ROUTE_SCAN_ERROR",
+			Message:  fmt.Sprintf("Cannot scan %s routes: %v", language, err),
+			Severity: "warning",
+			Location: root,
+		}}
+	}
+
+	// Feature APIs must be implemented by a route
+	for _, feature := range s.Features {
+		for _, api := range feature.API {
+			method := strings.ToUpper(api.Method)
+			path := normalizePath(api.Path)
+			if !hasMatchingRoute(routes, method, path) {
+				findings = append(findings, Finding{
+					Code:      "MISSING_ROUTE",
+					FeatureID: feature.ID,
+					Message:   fmt.Sprintf("No %s route implements API: %s %s", language, method, path),
+					Severity:  "error",
+					Location:  feature.ID.String(),
+				})
+			}
+		}
+	}
+
+	// Routes must be documented in the OpenAPI spec, when there is one
+	validator := routeSpecValidator(opts)
+	for _, route := range routes {
+		if validator == nil || validator.documentsRoute(route) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Code:      "UNDOCUMENTED_ROUTE",
+			FeatureID: routeFeature(s, route),
+			Message:   fmt.Sprintf("Route %s %s (%s:%d) is not in the OpenAPI spec", route.Method, route.Path, route.File, route.Line),
+			Severity:  "warning",
+			Location:  route.File,
+		})
+	}
+
+	return findings
+}
+
+// routeSpecValidator loads the OpenAPI spec for route conformance. Errors
+// loading it are already reported by ValidateAPISpec.
+func routeSpecValidator(opts CodeDriftOptions) *OpenAPIValidator {
+	if opts.APISpecPath == "" {
+		return nil
+	}
+	specPath := opts.APISpecPath
+	if !filepath.IsAbs(specPath) {
+		specPath = filepath.Join(opts.ProjectRoot, specPath)
+	}
+	validator, err := NewOpenAPIValidator(specPath)
+	if err != nil {
+		return nil
+	}
+	return validator
+}
+
+// documentsRoute reports whether the OpenAPI spec has an operation the
+// route implements
+func (v *OpenAPIValidator) documentsRoute(route Route) bool {
+	for specPath, methods := range v.GetEndpointSummary() {
+		for _, method := range methods {
+			if routeMatches(route, method, specPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasMatchingRoute(routes []Route, method, path string) bool {
+	for _, route := range routes {
+		if routeMatches(route, method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeFeature returns the feature whose API the route implements, if any
+func routeFeature(s *spec.ProductSpec, route Route) types.FeatureID {
+	for _, feature := range s.Features {
+		for _, api := range feature.API {
+			if routeMatches(route, strings.ToUpper(api.Method), normalizePath(api.Path)) {
+				return feature.ID
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+func TestParseJSRoutes(t *testing.T) {
+	source := `import express from 'express';
+const app = express();
+const usersRouter = express.Router();
+
+app.get('/health', (req, res) => res.send('ok'));
+usersRouter.post("/users", createUser);
+usersRouter.delete(` + "`/users/:id`" + `, deleteUser);
+router.put('/users/:id?/avatar', upload);
+
+fastify.route({
+  method: ['GET', 'HEAD'],
+  url: '/orders/:orderId',
+  handler: async (request, reply) => { return {} },
+});
+
+// HTTP client calls are not routes
+const res = await axios.get('/users');
+`
+
+	routes := parseJSRoutes(source, "src/app.ts")
+
+	var got []string
+	for _, route := range routes {
+		got = append(got, route.Method+" "+route.Path)
+	}
+	want := []string{
+		"GET /health",
+		"POST /users",
+		"DELETE /users/{id}",
+		"PUT /users/{id}/avatar",
+		"GET /orders/{orderId}",
+		"HEAD /orders/{orderId}",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+
+	if routes[0].File != "src/app.ts" || routes[0].Line != 5 {
+		t.Errorf("first route at %s:%d, want src/app.ts:5", routes[0].File, routes[0].Line)
+	}
+	if routes[4].Line != 10 {
+		t.Errorf("fastify route at line %d, want 10", routes[4].Line)
+	}
+}
+
+func TestRouteMatchesPath(t *testing.T) {
+	tests := []struct {
+		route, spec string
+		want        bool
+	}{
+		{"/users", "/users", true},
+		{"/users/{id}", "/users/{userId}", true},
+		{"/users/{id}", "/api/users/{id}", true}, // Router mounted under /api
+		{"/api/users", "/users", true},           // Spec served under /api
+		{"/users", "/orders", false},
+		{"/users/{id}", "/orders/{id}", false},
+		{"/users/{id}/avatar", "/users/{id}", false},
+		{"/{id}", "/users/{id}", false},
+	}
+
+	for _, tt := range tests {
+		if got := routeMatchesPath(tt.route, tt.spec); got != tt.want {
+			t.Errorf("routeMatchesPath(%q, %q) = %v, want %v", tt.route, tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestDetectCodeDrift_JSRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api/openapi.yaml": `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /api/users:
+    get:
+      responses:
+        '200':
+          description: Success
+  /api/users/{id}:
+    delete:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+`,
+		"src/routes/users.ts":       "router.get('/users', list);\nrouter.post('/users/:id/avatar', upload);\n",
+		"src/routes/users.test.ts":  "app.get('/test-only', handler);\n",
+		"node_modules/lib/index.js": "app.get('/vendored', handler);\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	productSpec := &spec.ProductSpec{
+		Features: []spec.Feature{
+			{ID: "feat-001", Title: "List users", API: []spec.API{{Method: "GET", Path: "/api/users"}}},
+			{ID: "feat-002", Title: "Delete users", API: []spec.API{{Method: "DELETE", Path: "/api/users/{id}"}}},
+		},
+	}
+
+	findings := checkRouteConformance(productSpec, CodeDriftOptions{
+		ProjectRoot: tmpDir,
+		APISpecPath: "api/openapi.yaml",
+		IgnoreGlobs: []string{"*.test.ts"},
+		Language:    "typescript",
+	})
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Code+" "+f.FeatureID.String()+" "+f.Location+" "+f.Severity)
+	}
+	sort.Strings(got)
+	want := []string{
+		"MISSING_ROUTE feat-002 feat-002 error",
+		"UNDOCUMENTED_ROUTE  src/routes/users.ts warning",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, f := range findings {
+		if f.Code == "UNDOCUMENTED_ROUTE" && !strings.Contains(f.Message, "POST /users/{id}/avatar (src/routes/users.ts:2)") {
+			t.Errorf("undocumented route message = %q", f.Message)
+		}
+	}
+
+	t.Run("unsupported language", func(t *testing.T) {
+		findings := checkRouteConformance(productSpec, CodeDriftOptions{ProjectRoot: tmpDir, Language: "cobol"})
+		if len(findings) != 1 || findings[0].Code != "UNSUPPORTED_LANGUAGE" {
+			t.Errorf("findings = %+v, want one UNSUPPORTED_LANGUAGE", findings)
+		}
+	})
+
+	t.Run("skipped without language", func(t *testing.T) {
+		lock := &spec.SpecLock{Features: map[types.FeatureID]spec.LockedFeature{}}
+		for _, f := range DetectCodeDrift(productSpec, lock, CodeDriftOptions{ProjectRoot: tmpDir}) {
+			if f.Code == "MISSING_ROUTE" || f.Code == "UNDOCUMENTED_ROUTE" {
+				t.Errorf("unexpected route finding without Language: %+v", f)
+			}
+		}
+	})
+}
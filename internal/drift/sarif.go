@@ -53,6 +53,12 @@ type SARIFLocation struct {
 // SARIFPhysicalLocation provides file-level location
 type SARIFPhysicalLocation struct {
 	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFRegion identifies a line within the artifact
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
 }
 
 // SARIFArtifactLocation identifies the artifact
@@ -62,7 +68,12 @@ type SARIFArtifactLocation struct {
 
 // ToSARIF converts a drift report to SARIF format
 func (r *Report) ToSARIF() *SARIF {
-	sarif := &SARIF{
+	return NewSARIF(convertFindingsToSARIF(r))
+}
+
+// NewSARIF wraps results in a single-run SARIF report from specular
+func NewSARIF(results []SARIFResult) *SARIF {
+	return &SARIF{
 		Version: "2.1.0",
 		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
 		Runs: []SARIFRun{
@@ -74,11 +85,10 @@ func (r *Report) ToSARIF() *SARIF {
 						SemanticVersion: "0.1.0",
 					},
 				},
-				Results: convertFindingsToSARIF(r),
+				Results: results,
 			},
 		},
 	}
-	return sarif
 }
 
 // convertFindingsToSARIF converts drift findings to SARIF results
@@ -0,0 +1,249 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a named list of shell steps run by 'specular eval run'
+type Scenario struct {
+	Name        string         `yaml:"-"`
+	Description string         `yaml:"description,omitempty"`
+	Steps       []ScenarioStep `yaml:"steps"`
+}
+
+// ScenarioStep is a shell command and the exit codes that count as passing
+type ScenarioStep struct {
+	Name      string `yaml:"name"`
+	Run       string `yaml:"run"`                  // Shell command (sh -c, or cmd /C on Windows)
+	ExitCodes []int  `yaml:"exit_codes,omitempty"` // Passing exit codes (default [0])
+	Requires  string `yaml:"requires,omitempty"`   // Executable that must be on PATH, otherwise the step is skipped
+}
+
+// ScenarioFile is the .specular/eval.yaml file
+type ScenarioFile struct {
+	Scenarios map[string]*Scenario `yaml:"scenarios"`
+}
+
+// DefaultScenarios returns the built-in scenarios for Go projects
+func DefaultScenarios() map[string]*Scenario {
+	return map[string]*Scenario{
+		"smoke": {
+			Name:        "smoke",
+			Description: "Running basic health checks...",
+			Steps: []ScenarioStep{
+				{Name: "go vet", Run: "go vet ./..."},
+				{Name: "go build", Run: "go build ./..."},
+				{Name: "basic tests", Run: "go test ./... -short -timeout=30s"},
+			},
+		},
+		"integration": {
+			Name:        "integration",
+			Description: "Running full integration tests...",
+			Steps: []ScenarioStep{
+				{Name: "go vet", Run: "go vet ./..."},
+				{Name: "all tests", Run: "go test ./... -timeout=5m"},
+				{Name: "coverage check", Run: "go test ./... -cover"},
+			},
+		},
+		"security": {
+			Name:        "security",
+			Description: "Running security scans and policy checks...",
+			Steps: []ScenarioStep{
+				{Name: "go vet", Run: "go vet ./..."},
+				{Name: "gosec scan", Run: "gosec ./...", Requires: "gosec"},
+			},
+		},
+		"performance": {
+			Name:        "performance",
+			Description: "Running performance benchmarks...",
+			Steps: []ScenarioStep{
+				{Name: "benchmark tests", Run: "go test ./... -bench=. -benchtime=1s -run=^$"},
+			},
+		},
+	}
+}
+
+// LoadScenarios returns the built-in scenarios overlaid with the scenarios
+// defined in path. A scenario in the file replaces the built-in scenario of
+// the same name. A missing file returns the built-in scenarios.
+func LoadScenarios(path string) (map[string]*Scenario, error) {
+	scenarios := DefaultScenarios()
+
+	data, err := os.ReadFile(path) // #nosec G304 -- Scenario file from the project's .specular directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return scenarios, nil
+		}
+		return nil, fmt.Errorf("read scenario file: %w", err)
+	}
+
+	var file ScenarioFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse scenario file %s: %w", path, err)
+	}
+
+	for name, scenario := range file.Scenarios {
+		if scenario == nil {
+			return nil, fmt.Errorf("scenario %q in %s has no steps", name, path)
+		}
+		scenario.Name = name
+		if err := scenario.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid scenario %q in %s: %w", name, path, err)
+		}
+		scenarios[name] = scenario
+	}
+
+	return scenarios, nil
+}
+
+// ScenarioNames returns the scenario names in alphabetical order
+func ScenarioNames(scenarios map[string]*Scenario) []string {
+	names := make([]string, 0, len(scenarios))
+	for name := range scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that every step has a command
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	for i, step := range s.Steps {
+		if strings.TrimSpace(step.Run) == "" {
+			return fmt.Errorf("step %d (%s) has no run command", i+1, step.Name)
+		}
+	}
+	return nil
+}
+
+// RunScenario runs each step of the scenario in dir, in order, and reports
+// progress to w. Every step runs even after a failure so the report is
+// complete. Steps whose required executable is missing are skipped.
+func RunScenario(ctx context.Context, dir string, scenario *Scenario, w io.Writer) *GateReport {
+	start := time.Now()
+	report := &GateReport{}
+
+	for i, step := range scenario.Steps {
+		name := step.displayName()
+		fmt.Fprintf(w, "%d. Running %s...\n", i+1, name)
+
+		if step.Requires != "" {
+			if _, err := exec.LookPath(step.Requires); err != nil {
+				report.TotalSkipped++
+				report.Checks = append(report.Checks, CheckResult{
+					Name:    name,
+					Passed:  true,
+					Message: fmt.Sprintf("%s skipped: %s not installed", name, step.Requires),
+				})
+				fmt.Fprintf(w, "   ⊘ %s not installed (skipping)\n", step.Requires)
+				continue
+			}
+		}
+
+		result := runScenarioStep(ctx, dir, step)
+		result.Name = name
+		if result.Passed {
+			report.TotalPassed++
+			fmt.Fprintf(w, "   ✓ %s passed (%.2fs)\n", name, result.Duration.Seconds())
+		} else {
+			report.TotalFailed++
+			fmt.Fprintf(w, "   ✗ %s failed (%.2fs)\n", name, result.Duration.Seconds())
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	report.AllPassed = report.TotalFailed == 0
+	report.Duration = time.Since(start)
+	return report
+}
+
+// runScenarioStep runs a step's shell command and compares its exit code
+// with the passing exit codes
+func runScenarioStep(ctx context.Context, dir string, step ScenarioStep) CheckResult {
+	start := time.Now()
+	cmd := shellCommand(ctx, step.Run)
+	cmd.Dir = dir
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+
+	result := CheckResult{
+		Details:  output.String(),
+		Duration: time.Since(start),
+		Required: true,
+	}
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			// The command could not be started
+			result.Message = fmt.Sprintf("%s failed: %v", step.displayName(), err)
+			return result
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	result.Passed = step.expectsExitCode(exitCode)
+	if result.Passed {
+		result.Message = fmt.Sprintf("%s passed", step.displayName())
+	} else {
+		result.Message = fmt.Sprintf("%s failed: exit code %d, want %s", step.displayName(), exitCode, step.exitCodesString())
+	}
+	return result
+}
+
+// shellCommand runs command with the platform shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- Commands come from the project's own scenario file
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- Commands come from the project's own scenario file
+}
+
+func (s ScenarioStep) displayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Run
+}
+
+func (s ScenarioStep) expectsExitCode(code int) bool {
+	if len(s.ExitCodes) == 0 {
+		return code == 0
+	}
+	for _, want := range s.ExitCodes {
+		if code == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ScenarioStep) exitCodesString() string {
+	if len(s.ExitCodes) == 0 {
+		return "0"
+	}
+	codes := make([]string, len(s.ExitCodes))
+	for i, code := range s.ExitCodes {
+		codes[i] = fmt.Sprint(code)
+	}
+	return strings.Join(codes, " or ")
+}
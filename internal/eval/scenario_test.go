@@ -0,0 +1,113 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadScenarios(t *testing.T) {
+	t.Run("missing file uses built-ins", func(t *testing.T) {
+		scenarios, err := LoadScenarios(filepath.Join(t.TempDir(), "eval.yaml"))
+		if err != nil {
+			t.Fatalf("LoadScenarios: %v", err)
+		}
+		if got := strings.Join(ScenarioNames(scenarios), ","); got != "integration,performance,security,smoke" {
+			t.Errorf("scenarios = %s", got)
+		}
+	})
+
+	t.Run("file adds and replaces scenarios", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "eval.yaml")
+		content := `scenarios:
+  smoke:
+    steps:
+      - name: lint
+        run: npm run lint
+  audit:
+    description: Dependency audit
+    steps:
+      - run: npm audit
+        exit_codes: [0, 1]
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		scenarios, err := LoadScenarios(path)
+		if err != nil {
+			t.Fatalf("LoadScenarios: %v", err)
+		}
+		if got := strings.Join(ScenarioNames(scenarios), ","); got != "audit,integration,performance,security,smoke" {
+			t.Errorf("scenarios = %s", got)
+		}
+		if smoke := scenarios["smoke"]; len(smoke.Steps) != 1 || smoke.Steps[0].Run != "npm run lint" {
+			t.Errorf("smoke = %+v, want the file's scenario", smoke)
+		}
+		audit := scenarios["audit"]
+		if audit.Name != "audit" || len(audit.Steps[0].ExitCodes) != 2 {
+			t.Errorf("audit = %+v", audit)
+		}
+	})
+
+	t.Run("invalid scenarios", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"no steps":   "scenarios:\n  empty:\n    steps: []\n",
+			"no command": "scenarios:\n  lint:\n    steps:\n      - name: lint\n",
+			"bad yaml":   "scenarios: [",
+		} {
+			path := filepath.Join(t.TempDir(), "eval.yaml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadScenarios(path); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
+func TestRunScenario(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("scenario commands use POSIX shell syntax")
+	}
+
+	scenario := &Scenario{
+		Name: "custom",
+		Steps: []ScenarioStep{
+			{Name: "passes", Run: "true"},
+			{Name: "fails", Run: "echo broken && exit 2"},
+			{Name: "expected failure", Run: "exit 1", ExitCodes: []int{0, 1}},
+			{Run: "exit 3"},
+			{Name: "needs tool", Run: "missing-tool", Requires: "specular-missing-tool"},
+		},
+	}
+
+	var out bytes.Buffer
+	report := RunScenario(context.Background(), t.TempDir(), scenario, &out)
+
+	if report.AllPassed {
+		t.Error("AllPassed = true, want false")
+	}
+	if report.TotalPassed != 2 || report.TotalFailed != 2 || report.TotalSkipped != 1 {
+		t.Errorf("passed/failed/skipped = %d/%d/%d, want 2/2/1", report.TotalPassed, report.TotalFailed, report.TotalSkipped)
+	}
+	if len(report.Checks) != 5 {
+		t.Fatalf("len(Checks) = %d, want 5", len(report.Checks))
+	}
+
+	fails := report.Checks[1]
+	if fails.Passed || !strings.Contains(fails.Message, "exit code 2, want 0") || !strings.Contains(fails.Details, "broken") {
+		t.Errorf("failed step = %+v", fails)
+	}
+	if report.Checks[3].Name != "exit 3" {
+		t.Errorf("unnamed step name = %q, want its command", report.Checks[3].Name)
+	}
+	if !strings.Contains(out.String(), "⊘ specular-missing-tool not installed (skipping)") {
+		t.Errorf("output missing skip line:\n%s", out.String())
+	}
+}
@@ -0,0 +1,86 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SmokeCheck is a command run by the smoke scenario
+type SmokeCheck struct {
+	Name    string   // Display name (e.g., "go build")
+	Command []string // Program and arguments
+}
+
+// DefaultSmokeChecks returns the smoke scenario checks: go vet, go build, and
+// short tests
+func DefaultSmokeChecks() []SmokeCheck {
+	return []SmokeCheck{
+		{Name: "go vet", Command: []string{"go", "vet", "./..."}},
+		{Name: "go build", Command: []string{"go", "build", "./..."}},
+		{Name: "basic tests", Command: []string{"go", "test", "./...", "-short", "-timeout=30s"}},
+	}
+}
+
+// ParseSmokeChecks builds checks from command lines such as "make test".
+// Blank lines are ignored. An empty list returns DefaultSmokeChecks.
+func ParseSmokeChecks(commands []string) []SmokeCheck {
+	var checks []SmokeCheck
+	for _, command := range commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			continue
+		}
+		checks = append(checks, SmokeCheck{Name: strings.Join(fields, " "), Command: fields})
+	}
+	if len(checks) == 0 {
+		return DefaultSmokeChecks()
+	}
+	return checks
+}
+
+// RunSmokeChecks runs each check in dir, in order, and reports progress to w.
+// Every check runs even after a failure so the report is complete.
+func RunSmokeChecks(ctx context.Context, dir string, checks []SmokeCheck, w io.Writer) *GateReport {
+	start := time.Now()
+	report := &GateReport{}
+
+	for i, check := range checks {
+		fmt.Fprintf(w, "%d. Running %s...\n", i+1, check.Name)
+
+		checkStart := time.Now()
+		cmd := exec.CommandContext(ctx, check.Command[0], check.Command[1:]...)
+		cmd.Dir = dir
+
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := cmd.Run()
+
+		result := CheckResult{
+			Name:     check.Name,
+			Passed:   err == nil,
+			Details:  output.String(),
+			Duration: time.Since(checkStart),
+			Required: true,
+		}
+		if err != nil {
+			result.Message = fmt.Sprintf("%s failed: %v", check.Name, err)
+			report.TotalFailed++
+			fmt.Fprintf(w, "   ✗ %s failed\n", check.Name)
+		} else {
+			result.Message = fmt.Sprintf("%s passed", check.Name)
+			report.TotalPassed++
+			fmt.Fprintf(w, "   ✓ %s passed\n", check.Name)
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	report.AllPassed = report.TotalFailed == 0
+	report.Duration = time.Since(start)
+	return report
+}
@@ -0,0 +1,57 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseSmokeChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		want     []string
+	}{
+		{"empty uses defaults", nil, []string{"go vet", "go build", "basic tests"}},
+		{"blank lines use defaults", []string{"", "   "}, []string{"go vet", "go build", "basic tests"}},
+		{"custom commands", []string{"make build", "  make   test "}, []string{"make build", "make test"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := ParseSmokeChecks(tt.commands)
+			if len(checks) != len(tt.want) {
+				t.Fatalf("len(checks) = %d, want %d", len(checks), len(tt.want))
+			}
+			for i, check := range checks {
+				if check.Name != tt.want[i] {
+					t.Errorf("checks[%d].Name = %q, want %q", i, check.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunSmokeChecks(t *testing.T) {
+	checks := ParseSmokeChecks([]string{"true", "false", "true"})
+
+	var out bytes.Buffer
+	report := RunSmokeChecks(context.Background(), t.TempDir(), checks, &out)
+
+	if report.AllPassed {
+		t.Error("AllPassed = true, want false")
+	}
+	if report.TotalPassed != 2 || report.TotalFailed != 1 {
+		t.Errorf("passed/failed = %d/%d, want 2/1", report.TotalPassed, report.TotalFailed)
+	}
+	if len(report.Checks) != 3 {
+		t.Fatalf("len(Checks) = %d, want 3", len(report.Checks))
+	}
+	if report.Checks[1].Passed {
+		t.Error("Checks[1].Passed = true, want false")
+	}
+	if !strings.Contains(out.String(), "✗ false failed") {
+		t.Errorf("output missing failure line:\n%s", out.String())
+	}
+}
@@ -2,21 +2,36 @@ package exec
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 )
 
+// dockerStopTimeout is how long a cancelled container gets to exit before
+// the docker client is killed
+const dockerStopTimeout = 10 * time.Second
+
 // RunDocker executes a step in a Docker container with security constraints
 func RunDocker(step Step) (*Result, error) {
+	return RunDockerContext(context.Background(), step)
+}
+
+// RunDockerContext is RunDocker that stops the container when ctx is
+// cancelled. The docker client is interrupted, which it forwards to the
+// container, and killed if it has not exited after dockerStopTimeout.
+func RunDockerContext(ctx context.Context, step Step) (*Result, error) {
 	startTime := time.Now()
 
 	// Build Docker command with security constraints
 	args := buildDockerArgs(step)
 
 	// Execute command
-	cmd := exec.Command("docker", args...)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = dockerStopTimeout
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -27,6 +42,9 @@ func RunDocker(step Step) (*Result, error) {
 
 	// Get exit code
 	exitCode := 0
+	if ctxErr := context.Cause(ctx); ctxErr != nil && err != nil {
+		return nil, fmt.Errorf("interrupted: %w", ctxErr)
+	}
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exitCode = exitErr.ExitCode()
@@ -1,7 +1,10 @@
 package exec
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/plan"
@@ -15,6 +18,16 @@ type Executor struct {
 	ManifestDir string
 	ImageCache  *ImageCache
 	Verbose     bool
+	MaxParallel int  // Maximum tasks run concurrently (0 or 1 runs tasks in order)
+	FailFast    bool // Skip tasks not yet started once a task fails
+
+	// Gate, when set, is called before each task runs. It returns false to
+	// skip the task, or an error to stop the run. Tasks run in order while a
+	// gate is set.
+	Gate func(task plan.Task) (bool, error)
+
+	// runStep runs a step that passed policy checks (default: executeTask)
+	runStep func(ctx context.Context, step Step) (*Result, error)
 }
 
 // ExecutionResult contains results from executing a plan
@@ -29,7 +42,8 @@ type ExecutionResult struct {
 	EndTime      time.Time
 }
 
-// Execute runs all tasks in a plan with policy enforcement
+// Execute runs all tasks in a plan with policy enforcement. With
+// MaxParallel above 1 and no Gate, independent tasks run concurrently.
 func (e *Executor) Execute(p *plan.Plan) (*ExecutionResult, error) {
 	result := &ExecutionResult{
 		TotalTasks:  len(p.Tasks),
@@ -37,6 +51,12 @@ func (e *Executor) Execute(p *plan.Plan) (*ExecutionResult, error) {
 		StartTime:   time.Now(),
 	}
 
+	if e.MaxParallel > 1 && e.Gate == nil {
+		e.executeParallel(p, result)
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
 	// Execute tasks in order
 	for _, task := range p.Tasks {
 		fmt.Printf("Executing task %s (%s)...\n", task.ID, task.FeatureID)
@@ -48,57 +68,29 @@ func (e *Executor) Execute(p *plan.Plan) (*ExecutionResult, error) {
 			continue
 		}
 
-		// Create execution step
-		step := e.createStep(task)
-
-		// Enforce policy
-		if err := EnforcePolicy(step, e.Policy); err != nil {
-			result.FailedTasks++
-			fmt.Printf("  ✗ Policy violation: %v\n", err)
-			result.TaskResults[task.ID.String()] = &Result{
-				ExitCode: 1,
-				Error:    err,
-			}
+		if e.FailFast && result.FailedTasks > 0 {
+			result.SkippedTasks++
+			fmt.Printf("  ⊘ Skipped: %v\n", errFailFast)
 			continue
 		}
 
-		// Execute task
-		if e.DryRun {
-			fmt.Printf("  ⊙ Dry run: would execute %s\n", step.Image)
-			result.SuccessTasks++
-			result.TaskResults[task.ID.String()] = &Result{ExitCode: 0}
-		} else {
-			taskResult, err := e.executeTask(step)
-
+		if e.Gate != nil {
+			approved, err := e.Gate(task)
 			if err != nil {
-				result.FailedTasks++
-				fmt.Printf("  ✗ Failed: %v\n", err)
-				result.TaskResults[task.ID.String()] = &Result{
-					ExitCode: 1,
-					Error:    err,
-				}
-				continue
+				result.EndTime = time.Now()
+				return result, fmt.Errorf("task %s: %w", task.ID, err)
 			}
-
-			result.TaskResults[task.ID.String()] = taskResult
-
-			if taskResult.ExitCode != 0 {
-				result.FailedTasks++
-				fmt.Printf("  ✗ Failed: exit code %d\n", taskResult.ExitCode)
-			} else {
-				result.SuccessTasks++
-				fmt.Printf("  ✓ Completed in %v\n", taskResult.Duration)
+			if !approved {
+				result.SkippedTasks++
+				fmt.Printf("  ⊘ Skipped: not approved\n")
+				continue
 			}
+		}
 
-			// Create manifest
-			if e.ManifestDir != "" {
-				manifest := CreateManifest(step, taskResult)
-				if err := SaveManifest(manifest, e.ManifestDir); err != nil {
-					fmt.Printf("  ⚠ Warning: failed to save manifest: %v\n", err)
-				} else {
-					result.Manifests = append(result.Manifests, manifest)
-				}
-			}
+		taskResult, manifest := e.runTask(context.Background(), task, os.Stdout)
+		result.record(task, taskResult)
+		if manifest != nil {
+			result.Manifests = append(result.Manifests, manifest)
 		}
 	}
 
@@ -106,6 +98,63 @@ func (e *Executor) Execute(p *plan.Plan) (*ExecutionResult, error) {
 	return result, nil
 }
 
+// runTask enforces policy on a task and runs it, writing progress to w. It
+// returns the manifest saved for the run, if any. Cancelling ctx stops the
+// task's container.
+func (e *Executor) runTask(ctx context.Context, task plan.Task, w io.Writer) (*Result, *RunManifest) {
+	// Create execution step
+	step := e.createStep(task)
+
+	// Enforce policy
+	if err := EnforcePolicy(step, e.Policy); err != nil {
+		fmt.Fprintf(w, "  ✗ Policy violation: %v\n", err)
+		return &Result{ExitCode: 1, Error: err}, nil
+	}
+
+	if e.DryRun {
+		fmt.Fprintf(w, "  ⊙ Dry run: would execute %s\n", step.Image)
+		return &Result{ExitCode: 0}, nil
+	}
+
+	// Execute task
+	runStep := e.executeTask
+	if e.runStep != nil {
+		runStep = e.runStep
+	}
+	taskResult, err := runStep(ctx, step)
+	if err != nil {
+		fmt.Fprintf(w, "  ✗ Failed: %v\n", err)
+		return &Result{ExitCode: 1, Error: err}, nil
+	}
+
+	if taskResult.ExitCode != 0 {
+		fmt.Fprintf(w, "  ✗ Failed: exit code %d\n", taskResult.ExitCode)
+	} else {
+		fmt.Fprintf(w, "  ✓ Completed in %v\n", taskResult.Duration)
+	}
+
+	// Create manifest
+	if e.ManifestDir == "" {
+		return taskResult, nil
+	}
+	manifest := CreateManifest(step, taskResult)
+	if err := SaveManifest(manifest, e.ManifestDir); err != nil {
+		fmt.Fprintf(w, "  ⚠ Warning: failed to save manifest: %v\n", err)
+		return taskResult, nil
+	}
+	return taskResult, manifest
+}
+
+// record stores a task's result and counts it as completed or failed
+func (r *ExecutionResult) record(task plan.Task, taskResult *Result) {
+	r.TaskResults[task.ID.String()] = taskResult
+	if taskResult.ExitCode != 0 {
+		r.FailedTasks++
+	} else {
+		r.SuccessTasks++
+	}
+}
+
 // checkDependencies verifies all dependencies completed successfully
 func (e *Executor) checkDependencies(task plan.Task, result *ExecutionResult) error {
 	for _, depID := range task.DependsOn {
@@ -163,7 +212,7 @@ func (e *Executor) createStep(task plan.Task) Step {
 }
 
 // executeTask runs a single task
-func (e *Executor) executeTask(step Step) (*Result, error) {
+func (e *Executor) executeTask(ctx context.Context, step Step) (*Result, error) {
 	// Validate Docker is available
 	if err := ValidateDockerAvailable(); err != nil {
 		return nil, fmt.Errorf("docker not available: %w", err)
@@ -190,7 +239,7 @@ func (e *Executor) executeTask(step Step) (*Result, error) {
 	}
 
 	// Run Docker container
-	return RunDocker(step)
+	return RunDockerContext(ctx, step)
 }
 
 // PrintSummary outputs execution summary
@@ -0,0 +1,146 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/plan"
+)
+
+// errFailFast is the skip reason for tasks not started after a failure, and
+// the reason running tasks are interrupted
+var errFailFast = errors.New("an earlier task failed (fail-fast)")
+
+// errDependencyCycle is the skip reason for tasks whose dependencies never complete
+var errDependencyCycle = errors.New("dependency cycle")
+
+// taskOutcome is a task run reported by a worker
+type taskOutcome struct {
+	index    int
+	result   *Result
+	manifest *RunManifest
+	output   *bytes.Buffer
+}
+
+// executeParallel runs each task once its dependencies have completed, up to
+// MaxParallel tasks at a time. Workers only run tasks; this goroutine
+// schedules them and records every result, so the result needs no locking.
+// Each task's output is printed as one block when it completes, and
+// manifests keep plan order regardless of completion order. With FailFast,
+// the first failure also interrupts the tasks still running.
+func (e *Executor) executeParallel(p *plan.Plan, result *ExecutionResult) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	n := len(p.Tasks)
+	index := make(map[string]int, n)
+	for i, task := range p.Tasks {
+		index[task.ID.String()] = i
+	}
+
+	// Build the dependency DAG. Dependencies missing from the plan are
+	// reported by checkDependencies when the task is scheduled.
+	waiting := make([]int, n)
+	dependents := make([][]int, n)
+	for i, task := range p.Tasks {
+		for _, dep := range task.DependsOn {
+			if j, ok := index[dep.String()]; ok {
+				waiting[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	var ready []int
+	for i := range p.Tasks {
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	workers := min(e.MaxParallel, n)
+	jobs := make(chan int)
+	outcomes := make(chan taskOutcome)
+	defer close(jobs)
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				task := p.Tasks[i]
+				out := &bytes.Buffer{}
+				fmt.Fprintf(out, "Executing task %s (%s)...\n", task.ID, task.FeatureID)
+				taskResult, manifest := e.runTask(ctx, task, out)
+				outcomes <- taskOutcome{index: i, result: taskResult, manifest: manifest, output: out}
+			}
+		}()
+	}
+
+	manifests := make([]*RunManifest, n)
+	done := make([]bool, n)
+	finished, running := 0, 0
+
+	// finish marks a task done and readies dependents with no pending dependencies
+	finish := func(i int) {
+		done[i] = true
+		finished++
+		for _, d := range dependents[i] {
+			waiting[d]--
+			if waiting[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	skip := func(i int, reason error) {
+		fmt.Printf("Executing task %s (%s)...\n", p.Tasks[i].ID, p.Tasks[i].FeatureID)
+		fmt.Printf("  ⊘ Skipped: %v\n", reason)
+		result.SkippedTasks++
+		finish(i)
+	}
+
+	for finished < n {
+		// Start ready tasks while workers are idle
+		for len(ready) > 0 && running < workers {
+			i := ready[0]
+			ready = ready[1:]
+
+			if err := e.checkDependencies(p.Tasks[i], result); err != nil {
+				skip(i, err)
+				continue
+			}
+			if e.FailFast && result.FailedTasks > 0 {
+				skip(i, errFailFast)
+				continue
+			}
+
+			jobs <- i
+			running++
+		}
+
+		// Nothing running or ready means the remaining tasks wait on a cycle
+		if running == 0 {
+			for i := range p.Tasks {
+				if !done[i] {
+					skip(i, errDependencyCycle)
+				}
+			}
+			break
+		}
+
+		outcome := <-outcomes
+		running--
+		fmt.Print(outcome.output.String())
+		result.record(p.Tasks[outcome.index], outcome.result)
+		manifests[outcome.index] = outcome.manifest
+		finish(outcome.index)
+		if e.FailFast && outcome.result.ExitCode != 0 {
+			cancel(errFailFast)
+		}
+	}
+
+	for _, manifest := range manifests {
+		if manifest != nil {
+			result.Manifests = append(result.Manifests, manifest)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/plan"
+	"github.com/felixgeelhaar/specular/internal/policy"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// parallelTestPolicy allows golang images only, so "ui-react" tasks (node:20)
+// fail with a policy violation
+func parallelTestPolicy() *policy.Policy {
+	return &policy.Policy{
+		Execution: policy.ExecutionPolicy{
+			Docker: policy.DockerPolicy{
+				Required:       true,
+				ImageAllowlist: []string{"golang:1.22"},
+			},
+		},
+	}
+}
+
+func parallelTestTask(id, skill string, deps ...string) plan.Task {
+	task := plan.Task{ID: types.TaskID(id), Skill: skill, DependsOn: []types.TaskID{}}
+	for _, dep := range deps {
+		task.DependsOn = append(task.DependsOn, types.TaskID(dep))
+	}
+	return task
+}
+
+func TestExecute_Parallel(t *testing.T) {
+	executor := &Executor{Policy: parallelTestPolicy(), DryRun: true, MaxParallel: 4}
+
+	// task-4 is listed before its dependency and still runs after it
+	p := &plan.Plan{Tasks: []plan.Task{
+		parallelTestTask("task-1", "go-backend"),
+		parallelTestTask("task-4", "go-backend", "task-3"),
+		parallelTestTask("task-2", "go-backend"),
+		parallelTestTask("task-3", "go-backend", "task-1", "task-2"),
+		parallelTestTask("task-5", "go-backend"),
+	}}
+
+	result, err := executor.Execute(p)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.SuccessTasks != 5 {
+		t.Errorf("SuccessTasks = %d, want 5", result.SuccessTasks)
+	}
+	if len(result.TaskResults) != 5 {
+		t.Errorf("len(TaskResults) = %d, want 5", len(result.TaskResults))
+	}
+}
+
+func TestExecute_ParallelSkipsDependentsOfFailedTasks(t *testing.T) {
+	executor := &Executor{Policy: parallelTestPolicy(), DryRun: true, MaxParallel: 2}
+
+	p := &plan.Plan{Tasks: []plan.Task{
+		parallelTestTask("task-1", "ui-react"),
+		parallelTestTask("task-2", "go-backend", "task-1"),
+		parallelTestTask("task-3", "go-backend"),
+		parallelTestTask("task-4", "go-backend", "missing"),
+	}}
+
+	result, err := executor.Execute(p)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.FailedTasks != 1 || result.SuccessTasks != 1 || result.SkippedTasks != 2 {
+		t.Errorf("failed/success/skipped = %d/%d/%d, want 1/1/2",
+			result.FailedTasks, result.SuccessTasks, result.SkippedTasks)
+	}
+	if _, ok := result.TaskResults["task-2"]; ok {
+		t.Error("task-2 ran despite its dependency failing")
+	}
+}
+
+func TestExecute_FailFast(t *testing.T) {
+	// Both workers start on a failing task, so tasks 3 and 4 are only
+	// scheduled after a failure
+	tasks := []plan.Task{
+		parallelTestTask("task-1", "ui-react"),
+		parallelTestTask("task-2", "ui-react"),
+		parallelTestTask("task-3", "go-backend"),
+		parallelTestTask("task-4", "go-backend"),
+	}
+
+	tests := []struct {
+		name        string
+		maxParallel int
+		failFast    bool
+		wantSuccess int
+		wantSkipped int
+	}{
+		{"sequential", 1, false, 2, 0},
+		{"sequential fail-fast", 1, true, 0, 3},
+		{"parallel", 2, false, 2, 0},
+		{"parallel fail-fast", 2, true, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &Executor{
+				Policy:      parallelTestPolicy(),
+				DryRun:      true,
+				MaxParallel: tt.maxParallel,
+				FailFast:    tt.failFast,
+			}
+
+			result, err := executor.Execute(&plan.Plan{Tasks: tasks})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.SuccessTasks != tt.wantSuccess || result.SkippedTasks != tt.wantSkipped {
+				t.Errorf("success/skipped = %d/%d, want %d/%d",
+					result.SuccessTasks, result.SkippedTasks, tt.wantSuccess, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestExecute_ParallelDependencyCycle(t *testing.T) {
+	executor := &Executor{Policy: parallelTestPolicy(), DryRun: true, MaxParallel: 2}
+
+	p := &plan.Plan{Tasks: []plan.Task{
+		parallelTestTask("task-1", "go-backend"),
+		parallelTestTask("task-2", "go-backend", "task-3"),
+		parallelTestTask("task-3", "go-backend", "task-2"),
+	}}
+
+	result, err := executor.Execute(p)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.SuccessTasks != 1 || result.SkippedTasks != 2 {
+		t.Errorf("success/skipped = %d/%d, want 1/2", result.SuccessTasks, result.SkippedTasks)
+	}
+}
+
+func TestExecute_Gate(t *testing.T) {
+	var gated []string
+	executor := &Executor{
+		Policy:      parallelTestPolicy(),
+		DryRun:      true,
+		MaxParallel: 4, // ignored while a gate is set
+		Gate: func(task plan.Task) (bool, error) {
+			gated = append(gated, task.ID.String())
+			switch task.ID {
+			case "task-2":
+				return false, nil
+			case "task-4":
+				return false, errors.New("aborted")
+			}
+			return true, nil
+		},
+	}
+
+	p := &plan.Plan{Tasks: []plan.Task{
+		parallelTestTask("task-1", "go-backend"),
+		parallelTestTask("task-2", "go-backend"),
+		parallelTestTask("task-3", "go-backend", "task-2"),
+		parallelTestTask("task-4", "go-backend"),
+		parallelTestTask("task-5", "go-backend"),
+	}}
+
+	result, err := executor.Execute(p)
+	if err == nil {
+		t.Fatal("Execute() error = nil, want abort error")
+	}
+
+	// task-3 is skipped for its dependency before the gate is asked
+	if want := []string{"task-1", "task-2", "task-4"}; !reflect.DeepEqual(gated, want) {
+		t.Errorf("gated tasks = %v, want %v", gated, want)
+	}
+	if result.SuccessTasks != 1 || result.SkippedTasks != 2 {
+		t.Errorf("success/skipped = %d/%d, want 1/2", result.SuccessTasks, result.SkippedTasks)
+	}
+}
+
+func TestExecute_FailFastInterruptsRunningTasks(t *testing.T) {
+	executor := &Executor{
+		Policy:      parallelTestPolicy(),
+		MaxParallel: 2,
+		FailFast:    true,
+		// Allowed tasks run until they are interrupted
+		runStep: func(ctx context.Context, step Step) (*Result, error) {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("interrupted: %w", context.Cause(ctx))
+			case <-time.After(10 * time.Second):
+				return &Result{ExitCode: 0}, nil
+			}
+		},
+	}
+
+	// task-2 fails its policy check while task-1 is running
+	p := &plan.Plan{Tasks: []plan.Task{
+		parallelTestTask("task-1", "go-backend"),
+		parallelTestTask("task-2", "ui-react"),
+	}}
+
+	start := time.Now()
+	result, err := executor.Execute(p)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Execute() took %v, want the running task interrupted", elapsed)
+	}
+
+	task1 := result.TaskResults["task-1"]
+	if task1 == nil || task1.ExitCode == 0 || !errors.Is(task1.Error, errFailFast) {
+		t.Errorf("task-1 result = %+v, want it interrupted by fail-fast", task1)
+	}
+	if result.FailedTasks != 2 {
+		t.Errorf("FailedTasks = %d, want 2", result.FailedTasks)
+	}
+}
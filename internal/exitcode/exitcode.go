@@ -89,6 +89,7 @@ func checkPolicyViolation(errMsg string) (int, bool) {
 		"cost limit",
 		"step type blocked",
 		"operation blocked",
+		"blocked by policy",
 	}
 
 	for _, keyword := range keywords {
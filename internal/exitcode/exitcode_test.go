@@ -50,6 +50,11 @@ func TestDetermineExitCode(t *testing.T) {
 			err:      errors.New("image not allowed by policy"),
 			expected: PolicyViolation,
 		},
+		{
+			name:     "outside execution window",
+			err:      errors.New("auto mode failed: step-1 blocked by policy: [execution_window] execution not allowed outside window Mon,Tue,Wed,Thu,Fri 09:00-18:00 Europe/Berlin; next allowed at 2026-10-19T09:00:00+02:00"),
+			expected: PolicyViolation,
+		},
 		{
 			name:     "drift detected error",
 			err:      errors.New("drift detected in specification"),
@@ -126,6 +131,11 @@ func TestDetermineExitCode(t *testing.T) {
 			err:      errors.New("operation blocked by security policy"),
 			expected: PolicyViolation,
 		},
+		{
+			name:     "step blocked by policy",
+			err:      errors.New("auto mode failed: step-4: changes blocked by policy: [changed_paths] infra/main.tf"),
+			expected: PolicyViolation,
+		},
 		{
 			name:     "restricted operation",
 			err:      errors.New("operation is restricted"),
@@ -3,6 +3,9 @@ package hooks
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -89,12 +92,24 @@ func (h *ScriptHook) Execute(ctx context.Context, event *Event) error {
 	return nil
 }
 
-// WebhookHook sends HTTP POST requests
+// Headers sent with webhook requests
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the request
+	// body as "sha256=<hex>" when the webhook has a secret
+	SignatureHeader = "X-Specular-Signature"
+
+	// EventHeader carries the event type
+	EventHeader = "X-Specular-Event"
+)
+
+// WebhookHook POSTs the event JSON to a URL. With a secret, the body is
+// signed so receivers can verify it came from Specular.
 type WebhookHook struct {
 	name       string
 	eventTypes []EventType
 	enabled    bool
 	url        string
+	secret     string
 	headers    map[string]string
 	client     *http.Client
 }
@@ -128,9 +143,28 @@ func NewWebhookHook(config *HookConfig) (Hook, error) {
 		}
 	}
 
+	// Optional signing secret
+	if secret, ok := config.Config["secret"].(string); ok {
+		hook.secret = secret
+	}
+
 	return hook, nil
 }
 
+// SignPayload returns the signature of a webhook body as sent in
+// SignatureHeader
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is a valid SignatureHeader
+// value for the body, for receivers written in Go
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(SignPayload(secret, payload)), []byte(signature))
+}
+
 func (h *WebhookHook) Name() string            { return h.name }
 func (h *WebhookHook) EventTypes() []EventType { return h.eventTypes }
 func (h *WebhookHook) Enabled() bool           { return h.enabled }
@@ -150,6 +184,10 @@ func (h *WebhookHook) Execute(ctx context.Context, event *Event) error {
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event.Type))
+	if h.secret != "" {
+		req.Header.Set(SignatureHeader, SignPayload(h.secret, payload))
+	}
 	for key, value := range h.headers {
 		req.Header.Set(key, value)
 	}
@@ -157,13 +195,13 @@ func (h *WebhookHook) Execute(ctx context.Context, event *Event) error {
 	// Send request
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("webhook request failed: %w", err)
+		return Retryable(fmt.Errorf("webhook request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
 	// Check response
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		return statusError("webhook", resp.StatusCode)
 	}
 
 	return nil
@@ -253,17 +291,27 @@ func (h *SlackHook) Execute(ctx context.Context, event *Event) error {
 
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("Slack request failed: %w", err)
+		return Retryable(fmt.Errorf("Slack request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Slack returned status %d", resp.StatusCode)
+		return statusError("Slack", resp.StatusCode)
 	}
 
 	return nil
 }
 
+// statusError reports an unsuccessful HTTP response. Rate limiting and
+// server errors are retryable; other client errors are not.
+func statusError(service string, statusCode int) error {
+	err := fmt.Errorf("%s returned status %d", service, statusCode)
+	if statusCode == http.StatusTooManyRequests || statusCode >= 500 {
+		return Retryable(err)
+	}
+	return err
+}
+
 func (h *SlackHook) formatMessage(event *Event) string {
 	switch event.Type {
 	case EventWorkflowStart:
@@ -304,6 +352,15 @@ func (h *SlackHook) formatMessage(event *Event) string {
 	case EventDriftDetected:
 		return fmt.Sprintf("⚠️ Drift detected in workflow: %s", event.WorkflowID)
 
+	case EventBudgetWarning:
+		return fmt.Sprintf("💸 %.0f%% of budget consumed: %s\nSpent: $%.2f of $%.2f | Remaining: $%.2f",
+			event.GetFloat("threshold_percent"), event.WorkflowID,
+			event.GetFloat("spent_usd"), event.GetFloat("limit_usd"), event.GetFloat("remaining_usd"))
+
+	case EventBudgetExhausted:
+		return fmt.Sprintf("🛑 Budget exhausted: %s\nSpent: $%.2f of $%.2f at step %s",
+			event.WorkflowID, event.GetFloat("spent_usd"), event.GetFloat("limit_usd"), event.GetString("step_id"))
+
 	default:
 		return fmt.Sprintf("Event: %s for workflow %s", event.Type, event.WorkflowID)
 	}
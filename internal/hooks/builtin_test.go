@@ -294,6 +294,59 @@ func TestWebhookHookExecuteFailure(t *testing.T) {
 	}
 }
 
+func TestWebhookHookSignsPayload(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := newHTTPTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{name: "signed", secret: "s3cret"},
+		{name: "unsigned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := map[string]interface{}{"url": server.URL}
+			if tt.secret != "" {
+				settings["secret"] = tt.secret
+			}
+			hook, err := NewWebhookHook(&HookConfig{Name: "signed", Events: []EventType{EventWorkflowComplete}, Enabled: true, Config: settings})
+			if err != nil {
+				t.Fatalf("NewWebhookHook failed: %v", err)
+			}
+
+			if err := hook.Execute(context.Background(), NewEvent(EventWorkflowComplete, "wf-1", nil)); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if got := header.Get(EventHeader); got != string(EventWorkflowComplete) {
+				t.Errorf("%s = %q, want %q", EventHeader, got, EventWorkflowComplete)
+			}
+			signature := header.Get(SignatureHeader)
+			if tt.secret == "" {
+				if signature != "" {
+					t.Errorf("expected no signature without a secret, got %q", signature)
+				}
+				return
+			}
+			if !strings.HasPrefix(signature, "sha256=") || !VerifySignature(tt.secret, body, signature) {
+				t.Errorf("signature %q does not verify", signature)
+			}
+			if VerifySignature("wrong", body, signature) || VerifySignature(tt.secret, append(body, ' '), signature) {
+				t.Error("expected a wrong secret or modified body not to verify")
+			}
+		})
+	}
+}
+
 func TestNewSlackHook(t *testing.T) {
 	config := &HookConfig{
 		Name:    "test-slack",
@@ -477,6 +530,25 @@ func TestSlackHookFormatMessage(t *testing.T) {
 			data:           nil,
 			expectedPrefix: "⚠️ Drift detected",
 		},
+		{
+			eventType: EventBudgetWarning,
+			data: map[string]interface{}{
+				"threshold_percent": 75.0,
+				"spent_usd":         7.6,
+				"limit_usd":         10.0,
+				"remaining_usd":     2.4,
+			},
+			expectedPrefix: "💸 75% of budget consumed:",
+		},
+		{
+			eventType: EventBudgetExhausted,
+			data: map[string]interface{}{
+				"step_id":   "step-4",
+				"spent_usd": 10.0,
+				"limit_usd": 10.0,
+			},
+			expectedPrefix: "🛑 Budget exhausted:",
+		},
 	}
 
 	for _, tt := range tests {
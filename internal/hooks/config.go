@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the hooks configuration file
+const ConfigFileName = "hooks.yaml"
+
+// DefaultConfigPaths returns the hooks.yaml files loaded for a run, in
+// order: ~/.specular/hooks.yaml, then .specular/hooks.yaml in the project
+func DefaultConfigPaths() []string {
+	var paths []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".specular", ConfigFileName))
+	}
+	return append(paths, filepath.Join(".specular", ConfigFileName))
+}
+
+// LoadConfigFile reads hook configurations from a hooks.yaml file:
+//
+//	hooks:
+//	  - name: deploy-notify
+//	    type: webhook
+//	    events: [on_workflow_complete, on_workflow_failed]
+//	    config:
+//	      url: https://example.com/specular
+//	      secret: ${SPECULAR_WEBHOOK_SECRET}
+//
+// Environment variables are expanded. Hooks are enabled unless they set
+// enabled: false, and hooks without events receive every event.
+func LoadConfigFile(path string) ([]HookConfig, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- Path from known config locations
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hooks config: %w", err)
+	}
+
+	var file struct {
+		Hooks []yaml.Node `yaml:"hooks"`
+	}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	configs := make([]HookConfig, 0, len(file.Hooks))
+	for i := range file.Hooks {
+		var config HookConfig
+		var enabled struct {
+			Enabled *bool `yaml:"enabled"`
+		}
+		if err := file.Hooks[i].Decode(&config); err != nil {
+			return nil, fmt.Errorf("invalid hook %d in %s: %w", i+1, path, err)
+		}
+		if err := file.Hooks[i].Decode(&enabled); err != nil {
+			return nil, fmt.Errorf("invalid hook %d in %s: %w", i+1, path, err)
+		}
+		config.Enabled = enabled.Enabled == nil || *enabled.Enabled
+		if config.Name == "" {
+			config.Name = fmt.Sprintf("%s-%d", config.Type, i+1)
+		}
+
+		if err := NormalizeConfig(&config); err != nil {
+			return nil, fmt.Errorf("invalid hook %s in %s: %w", config.Name, path, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// LoadConfigFiles loads hooks from each file that exists. A hook in a later
+// file replaces an earlier hook with the same name.
+func LoadConfigFiles(paths ...string) ([]HookConfig, error) {
+	var configs []HookConfig
+	index := make(map[string]int)
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		loaded, err := LoadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range loaded {
+			if i, ok := index[config.Name]; ok {
+				configs[i] = config
+				continue
+			}
+			index[config.Name] = len(configs)
+			configs = append(configs, config)
+		}
+	}
+	return configs, nil
+}
+
+// NormalizeConfig validates a hook configuration and fills in defaults: a
+// hook without events receives every event
+func NormalizeConfig(config *HookConfig) error {
+	if config.Type == "" {
+		return fmt.Errorf("hook type required")
+	}
+	if config.FailureMode != "" && !IsValidFailureMode(config.FailureMode) {
+		return fmt.Errorf("invalid failure mode %q (valid: %v)", config.FailureMode, ValidFailureModes)
+	}
+
+	if len(config.Events) == 0 {
+		config.Events = AllEventTypes()
+		return nil
+	}
+	valid := make(map[EventType]bool)
+	for _, eventType := range AllEventTypes() {
+		valid[eventType] = true
+	}
+	for _, eventType := range config.Events {
+		if !valid[eventType] {
+			return fmt.Errorf("unknown event %q", eventType)
+		}
+	}
+	return nil
+}
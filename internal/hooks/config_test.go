@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHooksFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, ConfigFileName)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cret")
+	path := writeHooksFile(t, t.TempDir(), `hooks:
+  - name: deploy-notify
+    type: webhook
+    events: [on_workflow_complete, on_workflow_failed]
+    timeout: 5s
+    retry:
+      maxRetries: 5
+      initialBackoff: 2s
+    config:
+      url: https://example.com/hooks
+      secret: ${TEST_WEBHOOK_SECRET}
+  - type: webhook
+    config:
+      url: https://example.com/all
+  - name: off
+    type: slack
+    enabled: false
+`)
+
+	configs, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if len(configs) != 3 {
+		t.Fatalf("expected 3 hooks, got %d", len(configs))
+	}
+
+	notify := configs[0]
+	if notify.Name != "deploy-notify" || !notify.Enabled || len(notify.Events) != 2 || notify.Timeout != 5*time.Second {
+		t.Errorf("unexpected hook: %+v", notify)
+	}
+	if notify.Config["secret"] != "s3cret" {
+		t.Errorf("expected the secret to be expanded, got %v", notify.Config["secret"])
+	}
+	if notify.Retry == nil || notify.Retry.MaxRetries != 5 || notify.Retry.InitialBackoff != 2*time.Second {
+		t.Errorf("unexpected retry policy: %+v", notify.Retry)
+	}
+
+	if configs[1].Name != "webhook-2" || len(configs[1].Events) != len(AllEventTypes()) {
+		t.Errorf("expected a generated name and every event, got %+v", configs[1])
+	}
+	if configs[2].Enabled {
+		t.Error("expected enabled: false to disable the hook")
+	}
+}
+
+func TestLoadConfigFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "missing type", content: "hooks:\n  - name: x\n", want: "type required"},
+		{name: "unknown event", content: "hooks:\n  - type: webhook\n    events: [on_deploy]\n", want: `unknown event "on_deploy"`},
+		{name: "failure mode", content: "hooks:\n  - type: webhook\n    failureMode: explode\n", want: "invalid failure mode"},
+		{name: "yaml", content: "hooks: [", want: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeHooksFile(t, t.TempDir(), tt.content)
+			if _, err := LoadConfigFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfigFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFiles(t *testing.T) {
+	user := writeHooksFile(t, t.TempDir(), `hooks:
+  - name: notify
+    type: webhook
+    config: {url: https://user.example.com}
+  - name: audit
+    type: script
+    config: {script: /bin/true}
+`)
+	project := writeHooksFile(t, t.TempDir(), `hooks:
+  - name: notify
+    type: webhook
+    config: {url: https://project.example.com}
+`)
+
+	configs, err := LoadConfigFiles(user, filepath.Join(t.TempDir(), "missing.yaml"), project)
+	if err != nil {
+		t.Fatalf("LoadConfigFiles() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("expected 2 hooks, got %+v", configs)
+	}
+	if configs[0].Name != "notify" || configs[0].Config["url"] != "https://project.example.com" {
+		t.Errorf("expected the project file to replace notify, got %+v", configs[0])
+	}
+	if configs[1].Name != "audit" {
+		t.Errorf("expected audit to be kept, got %+v", configs[1])
+	}
+}
@@ -0,0 +1,209 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DeadLetter records an event a hook failed to handle after exhausting its
+// retries, so it can be replayed
+type DeadLetter struct {
+	// ID identifies the entry for replay
+	ID string `json:"id"`
+
+	// Hook is the name of the hook that failed
+	Hook string `json:"hook"`
+
+	// HookConfig recreates the hook on replay. It is only set for hooks
+	// created from configuration.
+	HookConfig *HookConfig `json:"hookConfig,omitempty"`
+
+	// Event is the event the hook failed to handle
+	Event *Event `json:"event"`
+
+	// Attempts is the number of times the hook ran
+	Attempts int `json:"attempts"`
+
+	// Error is the last hook error
+	Error string `json:"error"`
+
+	// Reason explains why the event was dead-lettered
+	Reason string `json:"reason"`
+
+	// FailedAt is when the event was dead-lettered
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterLog is an append-only JSONL file of dead-lettered events
+type DeadLetterLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewDeadLetterLog creates a dead-letter log at path
+func NewDeadLetterLog(path string) *DeadLetterLog {
+	return &DeadLetterLog{path: path}
+}
+
+// DefaultDeadLetterPath returns ~/.specular/hooks/failed.jsonl
+func DefaultDeadLetterPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".specular", "hooks", "failed.jsonl"), nil
+}
+
+// Path returns the log file path
+func (l *DeadLetterLog) Path() string {
+	return l.path
+}
+
+// Append adds an entry to the log, assigning an ID and failure time when
+// they are not set
+func (l *DeadLetterLog) Append(entry DeadLetter) error {
+	if entry.FailedAt.IsZero() {
+		entry.FailedAt = time.Now()
+	}
+	if entry.ID == "" {
+		entry.ID = fmt.Sprintf("%s-%d", entry.Hook, entry.FailedAt.UnixNano())
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the entries in the log, oldest first. A missing log has
+// no entries.
+func (l *DeadLetterLog) Entries() ([]DeadLetter, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.read()
+}
+
+func (l *DeadLetterLog) read() ([]DeadLetter, error) {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead-letter log: %w", err)
+	}
+
+	var entries []DeadLetter
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid dead letter at %s:%d: %w", l.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// write replaces the log with entries
+func (l *DeadLetterLog) write(entries []DeadLetter) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal dead letter: %w", err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write dead-letter log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to replace dead-letter log: %w", err)
+	}
+	return nil
+}
+
+// ReplayResult is the outcome of replaying one dead-lettered event
+type ReplayResult struct {
+	Entry   DeadLetter
+	Success bool
+	Error   string
+}
+
+// Replay re-fires the dead-lettered events that match, creating each hook
+// from its recorded configuration with the registry's factories. Events
+// that succeed are removed from the log; events that fail again stay with
+// the new error. A nil match replays every entry.
+func (l *DeadLetterLog) Replay(ctx context.Context, registry *Registry, match func(*DeadLetter) bool) ([]ReplayResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ReplayResult
+	kept := make([]DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		if match != nil && !match(&entry) {
+			kept = append(kept, entry)
+			continue
+		}
+
+		result := ReplayResult{Entry: entry}
+		if entry.HookConfig == nil {
+			result.Error = fmt.Sprintf("hook %s was not created from configuration and cannot be recreated", entry.Hook)
+		} else if hook, err := registry.newHook(entry.HookConfig); err != nil {
+			result.Error = err.Error()
+		} else {
+			execution := registry.executor.Execute(ctx, hook, entry.Event)
+			result.Success, result.Error = execution.Success, execution.Error
+			entry.Attempts++
+		}
+		results = append(results, result)
+
+		if !result.Success {
+			entry.Error = result.Error
+			kept = append(kept, entry)
+		}
+	}
+
+	if len(results) > 0 {
+		if err := l.write(kept); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
@@ -77,6 +77,7 @@ func (e *Executor) Execute(ctx context.Context, hook Hook, event *Event) Executi
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
+		result.Retryable = IsRetryable(err)
 	} else {
 		result.Success = true
 	}
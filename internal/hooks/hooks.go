@@ -25,12 +25,45 @@ const (
 	EventStepFailed EventType = "on_step_failed"
 
 	// Policy events
+	EventPolicyCheck     EventType = "on_policy_check"
 	EventPolicyViolation EventType = "on_policy_violation"
 
 	// Drift events
 	EventDriftDetected EventType = "on_drift_detected"
+
+	// Execution events
+	EventPatchSaved   EventType = "on_patch_saved"
+	EventBudgetUpdate EventType = "on_budget_update"
+	EventOutputChunk  EventType = "on_output_chunk"
+
+	// Budget events
+	EventBudgetWarning   EventType = "on_budget_warning"
+	EventBudgetExhausted EventType = "on_budget_exhausted"
 )
 
+// AllEventTypes returns every lifecycle event type
+func AllEventTypes() []EventType {
+	return []EventType{
+		EventWorkflowStart,
+		EventWorkflowComplete,
+		EventWorkflowFailed,
+		EventPlanCreated,
+		EventPlanApproved,
+		EventPlanRejected,
+		EventStepBefore,
+		EventStepAfter,
+		EventStepFailed,
+		EventPolicyCheck,
+		EventPolicyViolation,
+		EventDriftDetected,
+		EventPatchSaved,
+		EventBudgetUpdate,
+		EventOutputChunk,
+		EventBudgetWarning,
+		EventBudgetExhausted,
+	}
+}
+
 // Event represents a lifecycle event that can trigger hooks
 type Event struct {
 	// Type is the event type
@@ -86,6 +119,9 @@ type HookConfig struct {
 	// "warn" - log warning and continue
 	// "fail" - fail the workflow
 	FailureMode string `yaml:"failureMode" json:"failureMode"`
+
+	// Retry overrides the registry's retry policy for this hook
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
 }
 
 // ExecutionResult contains the result of hook execution
@@ -102,6 +138,9 @@ type ExecutionResult struct {
 	// Error message if hook failed
 	Error string `json:"error,omitempty"`
 
+	// Retryable indicates the failure is transient and will be retried
+	Retryable bool `json:"retryable,omitempty"`
+
 	// Duration of hook execution
 	Duration time.Duration `json:"duration"`
 
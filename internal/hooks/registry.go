@@ -3,6 +3,7 @@ package hooks
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 )
 
@@ -18,15 +19,56 @@ type Registry struct {
 
 	// executor executes hooks
 	executor *Executor
+
+	// configs maps hook names to the configuration they were created from
+	configs map[string]*HookConfig
+
+	// retryPolicy applies to hooks without their own retry configuration
+	retryPolicy RetryPolicy
+
+	// retries runs retries of transient hook failures in the background
+	retries *retryQueue
+
+	// deadLetter records events whose hooks exhausted their retries; nil
+	// drops them
+	deadLetter *DeadLetterLog
 }
 
-// NewRegistry creates a new hook registry
+// NewRegistry creates a new hook registry. Hooks that fail with a
+// retryable error are retried in the background with DefaultRetryPolicy,
+// and events that exhaust their retries are written to the dead-letter log
+// at DefaultDeadLetterPath.
 func NewRegistry() *Registry {
-	return &Registry{
-		hooks:     make(map[EventType][]Hook),
-		factories: make(map[string]HookFactory),
-		executor:  NewExecutor(),
+	r := &Registry{
+		hooks:       make(map[EventType][]Hook),
+		factories:   make(map[string]HookFactory),
+		executor:    NewExecutor(),
+		configs:     make(map[string]*HookConfig),
+		retryPolicy: DefaultRetryPolicy(),
+	}
+	if path, err := DefaultDeadLetterPath(); err == nil {
+		r.deadLetter = NewDeadLetterLog(path)
 	}
+	r.retries = newRetryQueue(r.executor, DefaultRetryQueueSize, r.deadLetterJob)
+	return r
+}
+
+// SetRetryPolicy sets the retry policy for hooks without their own retry
+// configuration
+func (r *Registry) SetRetryPolicy(policy RetryPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retryPolicy = policy
+}
+
+// SetDeadLetterLog sets where events that exhaust their retries are
+// recorded; nil drops them
+func (r *Registry) SetDeadLetterLog(log *DeadLetterLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deadLetter = log
 }
 
 // RegisterFactory registers a hook factory
@@ -70,23 +112,37 @@ func (r *Registry) RegisterFromConfig(config *HookConfig) error {
 		return nil
 	}
 
+	hook, err := r.newHook(config)
+	if err != nil {
+		return err
+	}
+
+	// Keep the configuration so dead-lettered events can recreate the hook
+	r.mu.Lock()
+	r.configs[config.Name] = config
+	r.mu.Unlock()
+
+	// Register the hook
+	return r.Register(hook)
+}
+
+// newHook creates a hook from configuration with the registered factories
+func (r *Registry) newHook(config *HookConfig) (Hook, error) {
 	// Get factory for this hook type
 	r.mu.RLock()
 	factory, exists := r.factories[config.Type]
 	r.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("unknown hook type: %s", config.Type)
+		return nil, fmt.Errorf("unknown hook type: %s", config.Type)
 	}
 
 	// Create hook using factory
 	hook, err := factory(config)
 	if err != nil {
-		return fmt.Errorf("failed to create hook %s: %w", config.Name, err)
+		return nil, fmt.Errorf("failed to create hook %s: %w", config.Name, err)
 	}
-
-	// Register the hook
-	return r.Register(hook)
+	return hook, nil
 }
 
 // Unregister removes a hook from the registry
@@ -106,7 +162,9 @@ func (r *Registry) Unregister(hookName string) {
 	}
 }
 
-// Trigger executes all hooks registered for an event type
+// Trigger executes all hooks registered for an event type. Hooks that
+// fail with a retryable error are retried in the background, so Trigger
+// returns the results of the first attempt without waiting.
 func (r *Registry) Trigger(ctx context.Context, event *Event) []ExecutionResult {
 	r.mu.RLock()
 	hooks := r.hooks[event.Type]
@@ -117,7 +175,62 @@ func (r *Registry) Trigger(ctx context.Context, event *Event) []ExecutionResult
 	}
 
 	// Execute all hooks for this event
-	return r.executor.ExecuteAll(ctx, hooks, event)
+	results := r.executor.ExecuteAll(ctx, hooks, event)
+	for i, result := range results {
+		if !result.Success && result.Retryable {
+			r.retry(hooks[i], event, result.Error)
+		}
+	}
+	return results
+}
+
+// retry schedules the retries of a failed hook execution
+func (r *Registry) retry(hook Hook, event *Event, lastError string) {
+	r.mu.RLock()
+	config := r.configs[hook.Name()]
+	policy := r.retryPolicy
+	r.mu.RUnlock()
+
+	if config != nil && config.Retry != nil {
+		policy = *config.Retry
+	}
+
+	job := &retryJob{hook: hook, config: config, event: event, policy: policy, attempts: 1, lastError: lastError}
+	if policy.MaxRetries < 1 {
+		r.deadLetterJob(job, "retries disabled")
+		return
+	}
+	r.retries.schedule(job)
+}
+
+// deadLetterJob records a hook execution that will not be retried
+func (r *Registry) deadLetterJob(job *retryJob, reason string) {
+	r.mu.RLock()
+	log := r.deadLetter
+	r.mu.RUnlock()
+
+	if log == nil {
+		return
+	}
+
+	err := log.Append(DeadLetter{
+		Hook:       job.hook.Name(),
+		HookConfig: job.config,
+		Event:      job.event,
+		Attempts:   job.attempts,
+		Error:      job.lastError,
+		Reason:     reason,
+	})
+	if err != nil {
+		// There is nowhere else to report the event; hooks never fail the workflow
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to record failed %s hook event: %v\n", job.hook.Name(), err)
+	}
+}
+
+// Flush waits for pending hook retries to finish. When ctx ends first, the
+// remaining retries are abandoned and written to the dead-letter log.
+func (r *Registry) Flush(ctx context.Context) error {
+	return r.retries.flush(ctx)
 }
 
 // GetHooks returns all hooks for an event type
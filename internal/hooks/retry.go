@@ -0,0 +1,236 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultRetryQueueSize bounds the number of hook executions waiting to be
+// retried. Failures beyond it go straight to the dead-letter log.
+const DefaultRetryQueueSize = 100
+
+// RetryableError marks a hook failure as transient, such as a network error
+// or a 5xx response, so the registry retries it in the background.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// Retryable wraps err so the registry retries the hook execution
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err}
+}
+
+// IsRetryable reports whether err is a transient hook failure
+func IsRetryable(err error) bool {
+	var retryable *RetryableError
+	return errors.As(err, &retryable)
+}
+
+// RetryPolicy configures retries of hooks that fail with a retryable error.
+// The delay before each retry doubles, starting at InitialBackoff and
+// capped at MaxBackoff.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; 0
+	// disables retries
+	MaxRetries int `yaml:"maxRetries" json:"maxRetries"`
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration `yaml:"initialBackoff" json:"initialBackoff"`
+
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+}
+
+// DefaultRetryPolicy returns the retry policy used unless a hook
+// configures its own
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// backoff returns the delay before the retry that follows the given number
+// of attempts
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// retryJob is a hook execution waiting to be retried
+type retryJob struct {
+	hook      Hook
+	config    *HookConfig // Set for hooks created from configuration
+	event     *Event
+	policy    RetryPolicy
+	attempts  int
+	lastError string
+}
+
+// retryQueue retries failed hook executions on a background goroutine.
+// Jobs wait out their backoff on a timer and then run one at a time, so a
+// flaky hook never blocks the workflow that triggered it.
+type retryQueue struct {
+	executor *Executor
+	size     int
+	failed   func(job *retryJob, reason string)
+
+	ready   chan *retryJob
+	start   sync.Once
+	pending sync.WaitGroup
+
+	// jobs holds every unfinished job with its backoff timer, which is nil
+	// once the job is ready to run or running. A job is finished by whoever
+	// removes it.
+	mu   sync.Mutex
+	jobs map[*retryJob]*time.Timer
+}
+
+// newRetryQueue creates a retry queue holding at most size jobs. failed is
+// called for jobs that run out of retries or do not fit in the queue.
+func newRetryQueue(executor *Executor, size int, failed func(job *retryJob, reason string)) *retryQueue {
+	return &retryQueue{
+		executor: executor,
+		size:     size,
+		failed:   failed,
+		ready:    make(chan *retryJob, size),
+		jobs:     make(map[*retryJob]*time.Timer),
+	}
+}
+
+// schedule queues the next retry of job after its backoff
+func (q *retryQueue) schedule(job *retryJob) {
+	q.start.Do(func() { go q.run() })
+
+	q.mu.Lock()
+	if len(q.jobs) >= q.size {
+		q.mu.Unlock()
+		q.failed(job, "retry queue full")
+		return
+	}
+	q.pending.Add(1)
+	q.jobs[job] = time.AfterFunc(job.policy.backoff(job.attempts), func() {
+		q.mu.Lock()
+		if _, ok := q.jobs[job]; !ok {
+			// Abandoned by flush
+			q.mu.Unlock()
+			return
+		}
+		q.jobs[job] = nil
+		q.mu.Unlock()
+
+		select {
+		case q.ready <- job:
+		default:
+			q.fail(job, "retry queue full")
+		}
+	})
+	q.mu.Unlock()
+}
+
+// run executes jobs as their backoff ends
+func (q *retryQueue) run() {
+	for job := range q.ready {
+		if !q.has(job) {
+			continue // Abandoned by flush
+		}
+		result := q.executor.Execute(context.Background(), job.hook, job.event)
+
+		// A job abandoned by flush while it ran is already dead-lettered
+		if !q.remove(job) {
+			continue
+		}
+		job.attempts++
+
+		switch {
+		case result.Success:
+			q.pending.Done()
+		case result.Retryable && job.attempts <= job.policy.MaxRetries:
+			job.lastError = result.Error
+			q.schedule(job)
+			q.pending.Done()
+		default:
+			job.lastError = result.Error
+			q.failed(job, fmt.Sprintf("failed after %d attempts", job.attempts))
+			q.pending.Done()
+		}
+	}
+}
+
+// has reports whether job is unfinished
+func (q *retryQueue) has(job *retryJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.jobs[job]
+	return ok
+}
+
+// remove finishes job and reports whether it was unfinished
+func (q *retryQueue) remove(job *retryJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.jobs[job]; !ok {
+		return false
+	}
+	delete(q.jobs, job)
+	return true
+}
+
+// fail hands an unfinished job to the failure callback
+func (q *retryQueue) fail(job *retryJob, reason string) {
+	if !q.remove(job) {
+		return
+	}
+	defer q.pending.Done()
+	q.failed(job, reason)
+}
+
+// flush waits for pending retries. When ctx ends first, every unfinished
+// job, whether waiting for its backoff, ready, or running, is abandoned
+// and handed to the failure callback so it can be replayed later.
+func (q *retryQueue) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	abandoned := make([]*retryJob, 0, len(q.jobs))
+	for job, timer := range q.jobs {
+		if timer != nil {
+			timer.Stop()
+		}
+		abandoned = append(abandoned, job)
+		delete(q.jobs, job)
+	}
+	q.mu.Unlock()
+
+	for _, job := range abandoned {
+		q.failed(job, "retry abandoned on shutdown")
+		q.pending.Done()
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,270 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// FlakyHook fails with a retryable error until it has run failures times
+type FlakyHook struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	err      error
+}
+
+func (h *FlakyHook) Name() string            { return "flaky" }
+func (h *FlakyHook) EventTypes() []EventType { return []EventType{EventWorkflowStart} }
+func (h *FlakyHook) Enabled() bool           { return true }
+func (h *FlakyHook) Execute(ctx context.Context, event *Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if h.calls <= h.failures {
+		return h.err
+	}
+	return nil
+}
+
+func (h *FlakyHook) Calls() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls
+}
+
+// newRetryRegistry returns a registry with fast retries and a temporary
+// dead-letter log
+func newRetryRegistry(t *testing.T, maxRetries int, backoff time.Duration) (*Registry, *DeadLetterLog) {
+	registry := NewRegistry()
+	registry.SetRetryPolicy(RetryPolicy{MaxRetries: maxRetries, InitialBackoff: backoff, MaxBackoff: backoff})
+	log := NewDeadLetterLog(filepath.Join(t.TempDir(), "hooks", "failed.jsonl"))
+	registry.SetDeadLetterLog(log)
+	return registry, log
+}
+
+func flush(t *testing.T, registry *Registry) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := registry.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestRegistryRetriesTransientFailure(t *testing.T) {
+	registry, log := newRetryRegistry(t, 3, time.Millisecond)
+	hook := &FlakyHook{failures: 2, err: Retryable(errors.New("connection reset"))}
+	if err := registry.Register(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	results := registry.Trigger(context.Background(), NewEvent(EventWorkflowStart, "wf-1", nil))
+	if len(results) != 1 || results[0].Success || !results[0].Retryable {
+		t.Fatalf("expected a retryable failure from the first attempt, got %+v", results)
+	}
+
+	flush(t, registry)
+	if calls := hook.Calls(); calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if entries, _ := log.Entries(); len(entries) != 0 {
+		t.Errorf("expected no dead letters, got %+v", entries)
+	}
+}
+
+func TestRegistryDoesNotRetryPermanentFailure(t *testing.T) {
+	registry, log := newRetryRegistry(t, 3, time.Millisecond)
+	hook := &FlakyHook{failures: 5, err: errors.New("bad request")}
+	if err := registry.Register(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	registry.Trigger(context.Background(), NewEvent(EventWorkflowStart, "wf-1", nil))
+	flush(t, registry)
+
+	if calls := hook.Calls(); calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+	if entries, _ := log.Entries(); len(entries) != 0 {
+		t.Errorf("expected no dead letters, got %+v", entries)
+	}
+}
+
+func TestRegistryDeadLetterAndReplay(t *testing.T) {
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	server := newHTTPTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	registry, log := newRetryRegistry(t, 3, time.Millisecond)
+	RegisterBuiltinHooks(registry)
+	config := &HookConfig{
+		Name:    "notify",
+		Type:    "webhook",
+		Events:  []EventType{EventBudgetWarning},
+		Enabled: true,
+		Config:  map[string]interface{}{"url": server.URL},
+		Retry:   &RetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond},
+	}
+	if err := registry.RegisterFromConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	event := NewEvent(EventBudgetWarning, "wf-1", map[string]interface{}{"threshold_percent": 75.0})
+	registry.Trigger(context.Background(), event)
+	flush(t, registry)
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the hook's own retry policy (2 attempts), got %d requests", got)
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Hook != "notify" || entry.Attempts != 2 || !strings.Contains(entry.Error, "status 503") || entry.HookConfig == nil {
+		t.Errorf("unexpected dead letter: %+v", entry)
+	}
+	if entry.Event.Type != EventBudgetWarning || entry.Event.GetFloat("threshold_percent") != 75 {
+		t.Errorf("dead letter event = %+v", entry.Event)
+	}
+
+	// Replaying while the webhook still fails keeps the entry
+	results, err := log.Replay(context.Background(), registry, nil)
+	if err != nil || len(results) != 1 || results[0].Success {
+		t.Fatalf("Replay() = %+v, %v, want one failure", results, err)
+	}
+	if entries, _ := log.Entries(); len(entries) != 1 || entries[0].Attempts != 3 {
+		t.Errorf("expected the entry to stay with 3 attempts, got %+v", entries)
+	}
+
+	healthy.Store(true)
+	results, err = log.Replay(context.Background(), registry, func(e *DeadLetter) bool { return e.Hook == "notify" })
+	if err != nil || len(results) != 1 || !results[0].Success {
+		t.Fatalf("Replay() = %+v, %v, want one success", results, err)
+	}
+	if entries, _ := log.Entries(); len(entries) != 0 {
+		t.Errorf("expected the replayed entry to be removed, got %+v", entries)
+	}
+}
+
+func TestRegistryFlushAbandonsPendingRetries(t *testing.T) {
+	registry, log := newRetryRegistry(t, 3, time.Hour)
+	hook := &FlakyHook{failures: 1, err: Retryable(errors.New("timeout"))}
+	if err := registry.Register(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	registry.Trigger(context.Background(), NewEvent(EventWorkflowStart, "wf-1", nil))
+	if time.Since(start) > time.Second {
+		t.Error("Trigger() waited for the retry")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := registry.Flush(ctx); err == nil {
+		t.Error("expected Flush() to report the abandoned retry")
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Reason != "retry abandoned on shutdown" || entries[0].HookConfig != nil {
+		t.Errorf("unexpected dead letters: %+v", entries)
+	}
+
+	// Hooks registered without configuration cannot be replayed
+	results, _ := log.Replay(context.Background(), registry, nil)
+	if len(results) != 1 || results[0].Success || !strings.Contains(results[0].Error, "cannot be recreated") {
+		t.Errorf("Replay() = %+v", results)
+	}
+}
+
+// BlockingHook fails with a retryable error, then blocks its retries until
+// release is closed
+type BlockingHook struct {
+	calls   atomic.Int32
+	running chan struct{}
+	release chan struct{}
+}
+
+func (h *BlockingHook) Name() string            { return "blocking" }
+func (h *BlockingHook) EventTypes() []EventType { return []EventType{EventWorkflowStart} }
+func (h *BlockingHook) Enabled() bool           { return true }
+func (h *BlockingHook) Execute(ctx context.Context, event *Event) error {
+	if h.calls.Add(1) == 1 {
+		return Retryable(errors.New("timeout"))
+	}
+	close(h.running)
+	<-h.release
+	return Retryable(errors.New("timeout"))
+}
+
+func TestRegistryFlushAbandonsRunningRetry(t *testing.T) {
+	registry, log := newRetryRegistry(t, 3, time.Millisecond)
+	hook := &BlockingHook{running: make(chan struct{}), release: make(chan struct{})}
+	if err := registry.Register(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	registry.Trigger(context.Background(), NewEvent(EventWorkflowStart, "wf-1", nil))
+	select {
+	case <-hook.running:
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := registry.Flush(ctx); err == nil {
+		t.Error("expected Flush() to report the abandoned retry")
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Reason != "retry abandoned on shutdown" || entries[0].Attempts != 1 {
+		t.Fatalf("unexpected dead letters: %+v", entries)
+	}
+
+	// The running attempt finishing later neither retries nor dead-letters
+	// the job again
+	close(hook.release)
+	time.Sleep(50 * time.Millisecond)
+	if calls := hook.calls.Load(); calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+	if entries, _ := log.Entries(); len(entries) != 1 {
+		t.Errorf("expected 1 dead letter, got %d", len(entries))
+	}
+}
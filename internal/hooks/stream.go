@@ -0,0 +1,37 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// StreamHook writes every lifecycle event to a writer as newline-delimited
+// JSON, one Event per line, for dashboards and other live consumers
+type StreamHook struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewStreamHook creates a hook that streams events to w
+func NewStreamHook(w io.Writer) *StreamHook {
+	return &StreamHook{enc: json.NewEncoder(w)}
+}
+
+func (h *StreamHook) Name() string            { return "event-stream" }
+func (h *StreamHook) EventTypes() []EventType { return AllEventTypes() }
+func (h *StreamHook) Enabled() bool           { return true }
+
+// Execute writes the event as one JSON line. The event is written even if
+// ctx is cancelled, so a stream records how an interrupted run ended.
+func (h *StreamHook) Execute(ctx context.Context, event *Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.enc.Encode(event); err != nil {
+		return fmt.Errorf("write event %s: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestStreamHook(t *testing.T) {
+	var buf bytes.Buffer
+	registry := NewRegistry()
+	if err := registry.Register(NewStreamHook(&buf)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	for _, eventType := range AllEventTypes() {
+		if !registry.HasHooksFor(eventType) {
+			t.Errorf("stream hook not registered for %s", eventType)
+		}
+	}
+
+	registry.Trigger(context.Background(), NewEvent(EventWorkflowStart, "wf-1", map[string]interface{}{"goal": "add health checks"}))
+	registry.Trigger(context.Background(), NewEvent(EventStepAfter, "wf-1", map[string]interface{}{"step_id": "step-1", "step_index": 0}))
+
+	// A cancelled context still writes, so interrupted runs record their end
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	registry.Trigger(ctx, NewEvent(EventWorkflowFailed, "wf-1", nil))
+
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	want := []EventType{EventWorkflowStart, EventStepAfter, EventWorkflowFailed}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, eventType := range want {
+		if events[i].Type != eventType || events[i].WorkflowID != "wf-1" {
+			t.Errorf("event %d = %s/%s, want %s/wf-1", i, events[i].Type, events[i].WorkflowID, eventType)
+		}
+	}
+	if events[0].GetString("goal") != "add health checks" {
+		t.Errorf("goal = %q, want %q", events[0].GetString("goal"), "add health checks")
+	}
+}
@@ -0,0 +1,459 @@
+package interview
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// DefaultDraftPath is where an interrupted spec interview is saved
+const DefaultDraftPath = ".specular/interview.draft.json"
+
+// Feature fields in the order the builder asks for them
+const (
+	fieldTitle    = "title"
+	fieldID       = "id"
+	fieldDesc     = "desc"
+	fieldPriority = "priority"
+	fieldSuccess  = "success"
+	fieldTasks    = "tasks"
+)
+
+var featureFields = []string{fieldTitle, fieldID, fieldDesc, fieldPriority, fieldSuccess, fieldTasks}
+
+// Menu choices offered between features
+const (
+	choiceAddFeature    = "Add a feature"
+	choiceEditFeature   = "Edit a feature"
+	choiceRemoveFeature = "Remove a feature"
+	choiceFinish        = "Finish and write the spec"
+)
+
+// Prompter asks the user for values. Input re-asks until validate accepts
+// the value.
+type Prompter interface {
+	Input(message, defaultValue string, validate func(string) error) (string, error)
+	Confirm(message string, defaultValue bool) (bool, error)
+	Select(message string, options []string) (string, error)
+}
+
+// Draft is a partially completed spec interview. It is saved after every
+// answer so an interrupted interview can be resumed.
+type Draft struct {
+	Spec spec.ProductSpec `json:"spec"`
+
+	// MetadataComplete is set once the product name, goals, and acceptance
+	// criteria have been answered
+	MetadataComplete bool `json:"metadata_complete"`
+
+	// Feature is the feature being defined, not yet added to Spec
+	Feature *spec.Feature `json:"feature,omitempty"`
+
+	// Answered lists the fields of Feature that have been answered
+	Answered []string `json:"answered,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LoadDraft reads a saved interview draft.
+func LoadDraft(path string) (*Draft, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read interview draft: %w", err)
+	}
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("parse interview draft: %w", err)
+	}
+	return &draft, nil
+}
+
+// SaveDraft writes an interview draft.
+func SaveDraft(draft *Draft, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create draft directory: %w", err)
+	}
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal interview draft: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write interview draft: %w", err)
+	}
+	return nil
+}
+
+// SpecBuilder walks the user through a product spec field by field,
+// validating each value as it is entered.
+type SpecBuilder struct {
+	prompter Prompter
+	draft    *Draft
+	edit     bool
+
+	// OnAnswer is called with the draft after every answer, for saving it
+	OnAnswer func(*Draft) error
+}
+
+// NewSpecBuilder starts a new spec interview.
+func NewSpecBuilder(prompter Prompter) *SpecBuilder {
+	return &SpecBuilder{prompter: prompter, draft: &Draft{}}
+}
+
+// ResumeSpecBuilder continues an interrupted interview. Answered fields
+// are not asked again.
+func ResumeSpecBuilder(prompter Prompter, draft *Draft) *SpecBuilder {
+	return &SpecBuilder{prompter: prompter, draft: draft}
+}
+
+// EditSpecBuilder edits an existing spec. Every field is asked with its
+// current value as the default.
+func EditSpecBuilder(prompter Prompter, existing *spec.ProductSpec) *SpecBuilder {
+	draft := &Draft{Spec: *existing}
+	draft.Spec.Features = slices.Clone(existing.Features)
+	return &SpecBuilder{prompter: prompter, draft: draft, edit: true}
+}
+
+// Draft returns the interview state.
+func (b *SpecBuilder) Draft() *Draft {
+	return b.draft
+}
+
+// Run asks for the remaining fields and returns the completed spec. The
+// spec passes schema and domain validation.
+func (b *SpecBuilder) Run() (*spec.ProductSpec, error) {
+	if b.edit || !b.draft.MetadataComplete {
+		if err := b.askMetadata(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.draft.Feature != nil {
+		if err := b.finishFeature(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if len(b.draft.Spec.Features) == 0 {
+			if err := b.addFeature(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		options := []string{choiceAddFeature, choiceEditFeature, choiceRemoveFeature, choiceFinish}
+		choice, err := b.prompter.Select(fmt.Sprintf("%d feature(s) defined. What next?", len(b.draft.Spec.Features)), options)
+		if err != nil {
+			return nil, err
+		}
+
+		switch choice {
+		case choiceAddFeature:
+			err = b.addFeature()
+		case choiceEditFeature:
+			err = b.editFeature()
+		case choiceRemoveFeature:
+			err = b.removeFeature()
+		case choiceFinish:
+			return b.complete()
+		default:
+			err = fmt.Errorf("unknown choice %q", choice)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// complete validates the finished spec
+func (b *SpecBuilder) complete() (*spec.ProductSpec, error) {
+	result := b.draft.Spec
+	if err := spec.DefaultSchema().ValidateSpec(&result); err != nil {
+		return nil, fmt.Errorf("spec is incomplete: %w", err)
+	}
+	if err := result.Validate(); err != nil {
+		return nil, fmt.Errorf("spec is incomplete: %w", err)
+	}
+	return &result, nil
+}
+
+// checkpoint records an answer
+func (b *SpecBuilder) checkpoint() error {
+	b.draft.UpdatedAt = time.Now()
+	if b.OnAnswer == nil {
+		return nil
+	}
+	return b.OnAnswer(b.draft)
+}
+
+// askMetadata asks for the product name, goals, and acceptance criteria
+func (b *SpecBuilder) askMetadata() error {
+	s := &b.draft.Spec
+
+	product, err := b.input("Product name", s.Product, ValidateRequired("product name"))
+	if err != nil {
+		return err
+	}
+	s.Product = strings.TrimSpace(product)
+	if err := b.checkpoint(); err != nil {
+		return err
+	}
+
+	if s.Goals, err = b.askList("Product goal", s.Goals, 1); err != nil {
+		return err
+	}
+	if err := b.checkpoint(); err != nil {
+		return err
+	}
+
+	if s.Acceptance, err = b.askList("Product acceptance criterion", s.Acceptance, 1); err != nil {
+		return err
+	}
+	b.draft.MetadataComplete = true
+	return b.checkpoint()
+}
+
+// addFeature defines a new feature and adds it to the spec
+func (b *SpecBuilder) addFeature() error {
+	b.draft.Feature = &spec.Feature{}
+	b.draft.Answered = nil
+	return b.finishFeature()
+}
+
+// finishFeature asks for the unanswered fields of the feature being
+// defined and adds it to the spec
+func (b *SpecBuilder) finishFeature() error {
+	if err := b.askFeature(b.draft.Feature, &b.draft.Answered, b.otherIDs(""), false); err != nil {
+		return err
+	}
+	b.draft.Spec.Features = append(b.draft.Spec.Features, *b.draft.Feature)
+	b.draft.Feature = nil
+	b.draft.Answered = nil
+	return b.checkpoint()
+}
+
+// editFeature asks for every field of an existing feature
+func (b *SpecBuilder) editFeature() error {
+	index, err := b.selectFeature("Feature to edit")
+	if err != nil {
+		return err
+	}
+	feature := b.draft.Spec.Features[index]
+	var answered []string
+	if err := b.askFeature(&feature, &answered, b.otherIDs(feature.ID), true); err != nil {
+		return err
+	}
+	b.draft.Spec.Features[index] = feature
+	return b.checkpoint()
+}
+
+// removeFeature removes a feature after confirmation
+func (b *SpecBuilder) removeFeature() error {
+	index, err := b.selectFeature("Feature to remove")
+	if err != nil {
+		return err
+	}
+	feature := b.draft.Spec.Features[index]
+	confirmed, err := b.prompter.Confirm(fmt.Sprintf("Remove %s (%s)?", feature.ID, feature.Title), false)
+	if err != nil || !confirmed {
+		return err
+	}
+	b.draft.Spec.Features = slices.Delete(b.draft.Spec.Features, index, index+1)
+	return b.checkpoint()
+}
+
+// selectFeature asks for one of the spec's features and returns its index
+func (b *SpecBuilder) selectFeature(message string) (int, error) {
+	options := make([]string, len(b.draft.Spec.Features))
+	for i, feature := range b.draft.Spec.Features {
+		options[i] = fmt.Sprintf("%s: %s", feature.ID, feature.Title)
+	}
+	selected, err := b.prompter.Select(message, options)
+	if err != nil {
+		return 0, err
+	}
+	index := slices.Index(options, selected)
+	if index < 0 {
+		return 0, fmt.Errorf("unknown feature %q", selected)
+	}
+	return index, nil
+}
+
+// otherIDs returns the IDs of the spec's features except one
+func (b *SpecBuilder) otherIDs(except types.FeatureID) []types.FeatureID {
+	ids := make([]types.FeatureID, 0, len(b.draft.Spec.Features))
+	for _, feature := range b.draft.Spec.Features {
+		if feature.ID != except {
+			ids = append(ids, feature.ID)
+		}
+	}
+	return ids
+}
+
+// askFeature asks for the feature fields not in answered, recording each
+// answer. With all set, every field is asked with its current value as the
+// default.
+func (b *SpecBuilder) askFeature(f *spec.Feature, answered *[]string, taken []types.FeatureID, all bool) error {
+	ask := func(field string) bool {
+		return all || !slices.Contains(*answered, field)
+	}
+	done := func(field string) error {
+		if !slices.Contains(*answered, field) {
+			*answered = append(*answered, field)
+		}
+		return b.checkpoint()
+	}
+
+	for _, field := range featureFields {
+		if !ask(field) {
+			continue
+		}
+
+		switch field {
+		case fieldTitle:
+			title, err := b.input("Feature name", f.Title, ValidateRequired("feature name"))
+			if err != nil {
+				return err
+			}
+			f.Title = strings.TrimSpace(title)
+
+		case fieldID:
+			defaultID := string(f.ID)
+			if defaultID == "" {
+				if suggested, _, err := types.NewFeatureIDNormalized(f.Title); err == nil {
+					defaultID = string(suggested)
+				}
+			}
+			id, err := b.input("Feature ID", defaultID, ValidateFeatureID(taken))
+			if err != nil {
+				return err
+			}
+			f.ID = types.FeatureID(strings.TrimSpace(id))
+
+		case fieldDesc:
+			desc, err := b.input("Description", f.Desc, ValidateRequired("description"))
+			if err != nil {
+				return err
+			}
+			f.Desc = strings.TrimSpace(desc)
+
+		case fieldPriority:
+			options := []string{string(types.PriorityP0), string(types.PriorityP1), string(types.PriorityP2)}
+			if f.Priority != "" {
+				// Offer the current priority first
+				options = slices.DeleteFunc(options, func(option string) bool { return option == string(f.Priority) })
+				options = append([]string{string(f.Priority)}, options...)
+			}
+			selected, err := b.prompter.Select("Priority (P0 = critical, P1 = important, P2 = nice-to-have)", options)
+			if err != nil {
+				return err
+			}
+			priority, err := types.NewPriority(selected)
+			if err != nil {
+				return err
+			}
+			f.Priority = priority
+
+		case fieldSuccess:
+			success, err := b.askList("Acceptance criterion", f.Success, 1)
+			if err != nil {
+				return err
+			}
+			f.Success = success
+
+		case fieldTasks:
+			tasks, err := b.askList("Task", f.Tasks, 0)
+			if err != nil {
+				return err
+			}
+			f.Tasks = tasks
+		}
+
+		if err := done(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// input asks for a value and checks it with validate, in case the prompter
+// does not
+func (b *SpecBuilder) input(message, defaultValue string, validate func(string) error) (string, error) {
+	value, err := b.prompter.Input(message, defaultValue, validate)
+	if err != nil {
+		return "", err
+	}
+	if err := validate(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// askList asks for list items until a blank answer once min items are
+// entered. Existing items can be kept as they are.
+func (b *SpecBuilder) askList(label string, current []string, min int) ([]string, error) {
+	if len(current) > 0 {
+		keep, err := b.prompter.Confirm(fmt.Sprintf("Keep the %d existing %s item(s)?\n  - %s",
+			len(current), strings.ToLower(label), strings.Join(current, "\n  - ")), true)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			return current, nil
+		}
+	}
+
+	var items []string
+	for {
+		message := fmt.Sprintf("%s %d", label, len(items)+1)
+		if len(items) >= min {
+			message += " (leave blank to finish)"
+		}
+		item, err := b.input(message, "", func(value string) error {
+			if strings.TrimSpace(value) == "" && len(items) < min {
+				return fmt.Errorf("at least %d %s item(s) required", min, strings.ToLower(label))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return items, nil
+		}
+		items = append(items, item)
+	}
+}
+
+// ValidateRequired returns a validator that rejects blank values.
+func ValidateRequired(name string) func(string) error {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s is required", name)
+		}
+		return nil
+	}
+}
+
+// ValidateFeatureID returns a validator that accepts valid feature IDs not
+// already taken.
+func ValidateFeatureID(taken []types.FeatureID) func(string) error {
+	return func(value string) error {
+		id, err := types.NewFeatureID(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		if slices.Contains(taken, id) {
+			return fmt.Errorf("feature ID %q is already used", id)
+		}
+		return nil
+	}
+}
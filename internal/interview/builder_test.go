@@ -0,0 +1,220 @@
+package interview
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/spec"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+var errScriptEnded = errors.New("script ended")
+
+// scriptedPrompter answers prompts from a fixed script. Confirm answers
+// are "yes" or "no".
+type scriptedPrompter struct {
+	answers []string
+	asked   []string
+}
+
+func (p *scriptedPrompter) next(message string) (string, error) {
+	p.asked = append(p.asked, message)
+	if len(p.answers) == 0 {
+		return "", errScriptEnded
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+func (p *scriptedPrompter) Input(message, defaultValue string, validate func(string) error) (string, error) {
+	answer, err := p.next(message)
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		answer = defaultValue
+	}
+	return answer, nil
+}
+
+func (p *scriptedPrompter) Confirm(message string, defaultValue bool) (bool, error) {
+	answer, err := p.next(message)
+	return answer == "yes", err
+}
+
+func (p *scriptedPrompter) Select(message string, options []string) (string, error) {
+	return p.next(message)
+}
+
+func TestSpecBuilder_New(t *testing.T) {
+	prompter := &scriptedPrompter{answers: []string{
+		"Shop", "Sell things", "", "Orders can be placed", "",
+		"User Login", "", "Users sign in with email", "P0", "Login succeeds", "", "Add login form", "Add session store", "",
+		choiceFinish,
+	}}
+
+	got, err := NewSpecBuilder(prompter).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := &spec.ProductSpec{
+		Product:    "Shop",
+		Goals:      []string{"Sell things"},
+		Acceptance: []string{"Orders can be placed"},
+		Features: []spec.Feature{{
+			ID:       "user-login",
+			Title:    "User Login",
+			Desc:     "Users sign in with email",
+			Priority: types.PriorityP0,
+			Success:  []string{"Login succeeds"},
+			Tasks:    []string{"Add login form", "Add session store"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSpecBuilder_Resume(t *testing.T) {
+	draftPath := filepath.Join(t.TempDir(), "draft.json")
+
+	// Stop after the description of the first feature
+	prompter := &scriptedPrompter{answers: []string{
+		"Shop", "Sell things", "", "Orders can be placed", "",
+		"User Login", "", "Users sign in with email",
+	}}
+	builder := NewSpecBuilder(prompter)
+	builder.OnAnswer = func(draft *Draft) error { return SaveDraft(draft, draftPath) }
+	if _, err := builder.Run(); !errors.Is(err, errScriptEnded) {
+		t.Fatalf("Run() error = %v, want script ended", err)
+	}
+
+	draft, err := LoadDraft(draftPath)
+	if err != nil {
+		t.Fatalf("LoadDraft() error = %v", err)
+	}
+	if !draft.MetadataComplete || draft.Feature == nil {
+		t.Fatalf("draft = %+v, want metadata complete and a feature in progress", draft)
+	}
+
+	prompter = &scriptedPrompter{answers: []string{"P1", "Login succeeds", "", "", choiceFinish}}
+	got, err := ResumeSpecBuilder(prompter, draft).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if prompter.asked[0] != "Priority (P0 = critical, P1 = important, P2 = nice-to-have)" {
+		t.Errorf("first resumed prompt = %q, want priority", prompter.asked[0])
+	}
+	if got.Product != "Shop" || len(got.Features) != 1 {
+		t.Fatalf("Run() = %+v, want Shop with one feature", got)
+	}
+	feature := got.Features[0]
+	if feature.ID != "user-login" || feature.Desc != "Users sign in with email" || feature.Priority != types.PriorityP1 {
+		t.Errorf("feature = %+v", feature)
+	}
+}
+
+func TestSpecBuilder_Edit(t *testing.T) {
+	existing := &spec.ProductSpec{
+		Product:    "Shop",
+		Goals:      []string{"Sell things"},
+		Acceptance: []string{"Orders can be placed"},
+		Features: []spec.Feature{
+			{ID: "user-login", Title: "User Login", Desc: "Sign in", Priority: types.PriorityP0, Success: []string{"Login works"}},
+			{ID: "checkout", Title: "Checkout", Desc: "Pay for orders", Priority: types.PriorityP1, Success: []string{"Payment captured"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		answers []string
+		want    []spec.Feature
+		wantErr string
+	}{
+		{
+			name: "edit feature keeps defaults",
+			answers: []string{
+				"", "yes", "yes", // metadata: keep product, goals, and acceptance
+				choiceEditFeature, "checkout: Checkout",
+				"", "", "Pay for orders by card", "P2", "yes", "Add payment form", "",
+				choiceFinish,
+			},
+			want: []spec.Feature{
+				existing.Features[0],
+				{ID: "checkout", Title: "Checkout", Desc: "Pay for orders by card", Priority: types.PriorityP2,
+					Success: []string{"Payment captured"}, Tasks: []string{"Add payment form"}},
+			},
+		},
+		{
+			name: "remove feature",
+			answers: []string{
+				"", "yes", "yes",
+				choiceRemoveFeature, "user-login: User Login", "yes",
+				choiceFinish,
+			},
+			want: existing.Features[1:],
+		},
+		{
+			name: "duplicate feature ID",
+			answers: []string{
+				"", "yes", "yes",
+				choiceAddFeature, "Login", "user-login",
+			},
+			wantErr: `feature ID "user-login" is already used`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EditSpecBuilder(&scriptedPrompter{answers: tt.answers}, existing).Run()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got.Product != "Shop" || !reflect.DeepEqual(got.Goals, existing.Goals) {
+				t.Errorf("metadata = %q %v, want unchanged", got.Product, got.Goals)
+			}
+			if !reflect.DeepEqual(got.Features, tt.want) {
+				t.Errorf("Features = %+v, want %+v", got.Features, tt.want)
+			}
+		})
+	}
+
+	if len(existing.Features) != 2 || existing.Features[1].Desc != "Pay for orders" {
+		t.Errorf("existing spec was modified: %+v", existing.Features)
+	}
+}
+
+func TestValidateFeatureID(t *testing.T) {
+	validate := ValidateFeatureID([]types.FeatureID{"user-login"})
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "checkout", wantErr: false},
+		{value: " checkout ", wantErr: false},
+		{value: "user-login", wantErr: true},
+		{value: "User_Login", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if err := validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFeatureID(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package metrics
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// maxPushBytes bounds the size of a single metrics push
+const maxPushBytes = 10 << 20
+
+// Aggregator collects the metrics pushed by short-lived specular runs and
+// exposes their totals for Prometheus to scrape. It accepts the Pushgateway
+// protocol, but unlike a Pushgateway it adds each push to the totals instead
+// of replacing the previous one: counters and histograms are summed and
+// gauges keep the last value. Each run must therefore push once, when it
+// exits, as 'specular auto --metrics-push-url' does.
+type Aggregator struct {
+	mu       sync.Mutex
+	families map[string]*aggregatedFamily
+	registry *prometheus.Registry
+}
+
+// aggregatedFamily holds the totals of one metric name
+type aggregatedFamily struct {
+	help   string
+	kind   dto.MetricType
+	series map[string]*aggregatedSeries
+}
+
+// aggregatedSeries holds the totals of one label set
+type aggregatedSeries struct {
+	labelNames  []string
+	labelValues []string
+
+	value   float64            // Counter total or last gauge value
+	count   uint64             // Histogram observations
+	sum     float64            // Histogram sum
+	buckets map[float64]uint64 // Histogram cumulative counts by upper bound
+}
+
+// NewAggregator creates an empty aggregator
+func NewAggregator() *Aggregator {
+	a := &Aggregator{
+		families: make(map[string]*aggregatedFamily),
+		registry: prometheus.NewRegistry(),
+	}
+	a.registry.MustRegister(a)
+	return a
+}
+
+// Handler serves the totals at /metrics and accepts pushes under
+// /metrics/job/<job>[/<label>/<value>...]
+func (a *Aggregator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(a.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/metrics/job/", a.handlePush)
+	return mux
+}
+
+func (a *Aggregator) handlePush(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		w.Header().Set("Allow", "POST, PUT")
+		http.Error(w, "metrics can only be pushed with POST or PUT", http.StatusMethodNotAllowed)
+		return
+	}
+
+	grouping, err := parseGrouping(strings.TrimPrefix(r.URL.Path, "/metrics/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var families []*dto.MetricFamily
+	decoder := expfmt.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushBytes), expfmt.ResponseFormat(r.Header))
+	for {
+		family := &dto.MetricFamily{}
+		if err := decoder.Decode(family); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("invalid metrics: %v", err), http.StatusBadRequest)
+			return
+		}
+		families = append(families, family)
+	}
+
+	a.Add(families, grouping)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// parseGrouping reads the grouping labels from a push path of the form
+// job/<job>[/<label>/<value>...]. Values may be base64url encoded as
+// <label>@base64/<value>.
+func parseGrouping(path string) (map[string]string, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("grouping path %q must alternate labels and values", path)
+	}
+
+	grouping := make(map[string]string, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		name, value := parts[i], parts[i+1]
+		if encoded, ok := strings.CutSuffix(name, "@base64"); ok {
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 value for label %q: %w", encoded, err)
+			}
+			name, value = encoded, string(decoded)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("grouping path %q has an empty label name", path)
+		}
+		grouping[name] = value
+	}
+
+	if grouping["job"] == "" {
+		return nil, errors.New("job name is required")
+	}
+	return grouping, nil
+}
+
+// Add adds pushed metric families to the totals. The grouping labels are
+// added to every series and take precedence over pushed labels of the same
+// name. Summaries, untyped metrics, and metrics whose type changed since an
+// earlier push are skipped.
+func (a *Aggregator) Add(families []*dto.MetricFamily, grouping map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, family := range families {
+		kind := family.GetType()
+		if kind != dto.MetricType_COUNTER && kind != dto.MetricType_GAUGE && kind != dto.MetricType_HISTOGRAM {
+			continue
+		}
+
+		agg, ok := a.families[family.GetName()]
+		if !ok {
+			agg = &aggregatedFamily{help: family.GetHelp(), kind: kind, series: make(map[string]*aggregatedSeries)}
+			a.families[family.GetName()] = agg
+		}
+		if agg.kind != kind {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			agg.add(kind, metric, grouping)
+		}
+	}
+}
+
+func (f *aggregatedFamily) add(kind dto.MetricType, metric *dto.Metric, grouping map[string]string) {
+	labels := make(map[string]string, len(metric.GetLabel())+len(grouping))
+	for _, pair := range metric.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	for name, value := range grouping {
+		labels[name] = value
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	var key strings.Builder
+	for i, name := range names {
+		values[i] = labels[name]
+		fmt.Fprintf(&key, "%s=%q,", name, values[i])
+	}
+
+	series, ok := f.series[key.String()]
+	if !ok {
+		series = &aggregatedSeries{labelNames: names, labelValues: values}
+		f.series[key.String()] = series
+	}
+
+	switch kind {
+	case dto.MetricType_COUNTER:
+		series.value += metric.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		series.value = metric.GetGauge().GetValue()
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		series.count += histogram.GetSampleCount()
+		series.sum += histogram.GetSampleSum()
+		if series.buckets == nil {
+			series.buckets = make(map[float64]uint64)
+		}
+		for _, bucket := range histogram.GetBucket() {
+			if math.IsInf(bucket.GetUpperBound(), 1) {
+				continue // Implied by the sample count
+			}
+			series.buckets[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+		}
+	}
+}
+
+// Describe sends no descriptors, which makes the aggregator an unchecked
+// collector: the pushed metrics are not known in advance
+func (a *Aggregator) Describe(chan<- *prometheus.Desc) {}
+
+// Collect sends the current totals
+func (a *Aggregator) Collect(ch chan<- prometheus.Metric) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, family := range a.families {
+		for _, series := range family.series {
+			desc := prometheus.NewDesc(name, family.help, series.labelNames, nil)
+			var metric prometheus.Metric
+			var err error
+			switch family.kind {
+			case dto.MetricType_COUNTER:
+				metric, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, series.value, series.labelValues...)
+			case dto.MetricType_GAUGE:
+				metric, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, series.value, series.labelValues...)
+			case dto.MetricType_HISTOGRAM:
+				metric, err = prometheus.NewConstHistogram(desc, series.count, series.sum, series.buckets, series.labelValues...)
+			}
+			if err != nil {
+				ch <- prometheus.NewInvalidMetric(desc, err)
+				continue
+			}
+			ch <- metric
+		}
+	}
+}
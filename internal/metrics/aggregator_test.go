@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// pushRun records one auto run in a fresh registry and pushes it to url
+func pushRun(t *testing.T, url string, success bool, stepSeconds float64) {
+	t.Helper()
+	reg, m := NewRegistry()
+	reg.MustRegister(prometheus.NewGoCollector())
+	m.AutoWorkflows.WithLabelValues(boolLabel(success)).Inc()
+	m.AutoStepDuration.WithLabelValues("build").Observe(stepSeconds)
+
+	if err := Push(context.Background(), url, reg); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+}
+
+func boolLabel(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func TestAggregatorSumsPushes(t *testing.T) {
+	agg := NewAggregator()
+	server := httptest.NewServer(agg.Handler())
+	defer server.Close()
+
+	pushRun(t, server.URL, true, 3)
+	pushRun(t, server.URL, true, 45)
+	pushRun(t, server.URL, false, 2)
+
+	host, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf(`
+# HELP specular_auto_workflows_total Total number of autonomous mode workflows
+# TYPE specular_auto_workflows_total counter
+specular_auto_workflows_total{instance=%[1]q,job="specular",success="false"} 1
+specular_auto_workflows_total{instance=%[1]q,job="specular",success="true"} 2
+`, host)
+	if err := testutil.GatherAndCompare(agg.registry, strings.NewReader(expected), "specular_auto_workflows_total"); err != nil {
+		t.Error(err)
+	}
+
+	families, err := agg.registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if !strings.HasPrefix(family.GetName(), "specular_") {
+			t.Errorf("collected %s, want only specular metrics", family.GetName())
+		}
+		if family.GetName() != "specular_auto_step_duration_seconds" {
+			continue
+		}
+		histogram := family.GetMetric()[0].GetHistogram()
+		if histogram.GetSampleCount() != 3 || histogram.GetSampleSum() != 50 {
+			t.Errorf("step duration count = %d, sum = %v, want 3 and 50", histogram.GetSampleCount(), histogram.GetSampleSum())
+		}
+		// Buckets are 1, 5, 10, 30, 60, ...
+		if got := histogram.GetBucket()[1].GetCumulativeCount(); got != 2 {
+			t.Errorf("le=5 bucket = %d, want 2", got)
+		}
+	}
+
+	// The totals are served at /metrics
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /metrics status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestAggregatorRejectsInvalidPushes(t *testing.T) {
+	server := httptest.NewServer(NewAggregator().Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "delete", method: http.MethodDelete, path: "/metrics/job/specular", status: http.StatusMethodNotAllowed},
+		{name: "missing value", method: http.MethodPost, path: "/metrics/job/specular/instance", status: http.StatusBadRequest},
+		{name: "empty job", method: http.MethodPost, path: "/metrics/job/", status: http.StatusBadRequest},
+		{name: "invalid body", method: http.MethodPost, path: "/metrics/job/specular", body: "not metrics{", status: http.StatusBadRequest},
+		{name: "text format", method: http.MethodPut, path: "/metrics/job/specular", body: "# TYPE runs_total counter\nruns_total 1\n", status: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestParseGrouping(t *testing.T) {
+	grouping, err := parseGrouping("job/specular/instance@base64/Y2kvcnVubmVyLTE/branch@base64/=")
+	if err != nil {
+		t.Fatalf("parseGrouping() error = %v", err)
+	}
+	want := map[string]string{"job": "specular", "instance": "ci/runner-1", "branch": ""}
+	if !reflect.DeepEqual(grouping, want) {
+		t.Errorf("grouping = %v, want %v", grouping, want)
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// PushJob is the job label under which CLI runs push their metrics
+const PushJob = "specular"
+
+// Push sends the specular_* metrics in gatherer to a Prometheus Pushgateway
+// or 'specular metrics serve' at url. The metrics are grouped by job and the
+// host name, so concurrent runs on different machines do not replace each
+// other's metrics on a Pushgateway.
+func Push(ctx context.Context, url string, gatherer prometheus.Gatherer) error {
+	pusher := push.New(url, PushJob).Gatherer(specularGatherer{gatherer})
+	if host, err := os.Hostname(); err == nil && host != "" {
+		pusher = pusher.Grouping("instance", host)
+	}
+	return pusher.AddContext(ctx)
+}
+
+// PushDefault pushes the metrics of the default registry
+func PushDefault(ctx context.Context, url string) error {
+	return Push(ctx, url, prometheus.DefaultGatherer)
+}
+
+// specularGatherer leaves out the Go runtime and process metrics, which
+// describe a process that has already exited by the time they are scraped
+type specularGatherer struct {
+	prometheus.Gatherer
+}
+
+func (g specularGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+	kept := families[:0]
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), "specular_") {
+			kept = append(kept, family)
+		}
+	}
+	return kept, err
+}
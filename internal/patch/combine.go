@@ -0,0 +1,344 @@
+package patch
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1" //#nosec G505 -- Git object IDs are SHA-1
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// contextLines is the number of unchanged lines around each hunk
+const contextLines = 3
+
+// binarySniffLen is how much of a file is checked for NUL bytes, as git does
+const binarySniffLen = 8000
+
+// nullObjectID is the git object ID of a missing file
+const nullObjectID = "0000000000000000000000000000000000000000"
+
+// Combine merges all patches of a workflow into one unified diff that
+// `git apply` accepts. Each file appears once, with its change from before
+// the first step that touched it to after the last one.
+func (w *Writer) Combine(workflowID string) (string, error) {
+	pattern := filepath.Join(w.patchDir, fmt.Sprintf("%s_*.patch.json", workflowID))
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to glob patches: %w", err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no patches found for workflow %s", workflowID)
+	}
+
+	patches := make([]*Patch, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file) //#nosec G304 -- Patch file from the patch directory
+		if err != nil {
+			return "", fmt.Errorf("failed to read patch file: %w", err)
+		}
+		patch, err := FromJSON(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse patch %s: %w", filepath.Base(file), err)
+		}
+		patches = append(patches, patch)
+	}
+
+	return CombinePatches(patches), nil
+}
+
+// combinedFile tracks one file across the steps of a workflow
+type combinedFile struct {
+	oldPath string
+	existed bool
+	before  string
+	exists  bool
+	after   string
+}
+
+// CombinePatches merges patches, applied in timestamp order, into one git
+// unified diff. Files are listed by path.
+func CombinePatches(patches []*Patch) string {
+	ordered := make([]*Patch, len(patches))
+	copy(ordered, patches)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	// Keyed by the current path of each file
+	states := make(map[string]*combinedFile)
+	track := func(path string, existed bool, before string) *combinedFile {
+		state, ok := states[path]
+		if !ok {
+			state = &combinedFile{oldPath: path, existed: existed, before: before, exists: existed, after: before}
+			states[path] = state
+		}
+		return state
+	}
+
+	for _, patch := range ordered {
+		for _, file := range patch.Files {
+			switch file.Status {
+			case FileStatusAdded:
+				state := track(file.Path, false, "")
+				state.exists, state.after = true, file.NewContent
+
+			case FileStatusModified:
+				state := track(file.Path, true, file.OldContent)
+				state.exists, state.after = true, file.NewContent
+
+			case FileStatusDeleted:
+				state := track(file.Path, true, file.OldContent)
+				state.exists, state.after = false, ""
+
+			case FileStatusRenamed:
+				content := file.OldContent
+				if file.NewContent != "" {
+					content = file.NewContent
+				}
+				state := track(file.OldPath, true, file.OldContent)
+				delete(states, file.OldPath)
+				state.exists = true
+				if file.OldContent != "" || file.NewContent != "" {
+					state.after = content
+				}
+				states[file.Path] = state
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(states))
+	for path := range states {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	dmp := diffmatchpatch.New()
+	for _, path := range paths {
+		state := states[path]
+		switch {
+		case !state.existed && state.exists:
+			writeFileDiff(&buf, dmp, "", path, "", state.after)
+		case state.existed && !state.exists:
+			writeFileDiff(&buf, dmp, state.oldPath, "", state.before, "")
+		case state.existed && state.exists && (state.oldPath != path || state.before != state.after):
+			writeFileDiff(&buf, dmp, state.oldPath, path, state.before, state.after)
+		}
+	}
+	return buf.String()
+}
+
+// writeFileDiff writes the git diff of one file. An empty oldPath is an added
+// file and an empty newPath a deleted one.
+func writeFileDiff(buf *bytes.Buffer, dmp *diffmatchpatch.DiffMatchPatch, oldPath, newPath, oldContent, newContent string) {
+	aPath, bPath := oldPath, newPath
+	if aPath == "" {
+		aPath = newPath
+	}
+	if bPath == "" {
+		bPath = oldPath
+	}
+	fmt.Fprintf(buf, "diff --git a/%s b/%s\n", aPath, bPath)
+
+	switch {
+	case oldPath == "":
+		buf.WriteString("new file mode 100644\n")
+	case newPath == "":
+		buf.WriteString("deleted file mode 100644\n")
+	case oldPath != newPath:
+		if oldContent == newContent {
+			buf.WriteString("similarity index 100%\n")
+		}
+		fmt.Fprintf(buf, "rename from %s\nrename to %s\n", oldPath, newPath)
+	}
+	if oldContent == newContent {
+		return
+	}
+
+	oldID, newID := nullObjectID, nullObjectID
+	if oldPath != "" {
+		oldID = objectID(oldContent)
+	}
+	if newPath != "" {
+		newID = objectID(newContent)
+	}
+	mode := " 100644"
+	if oldPath == "" || newPath == "" {
+		mode = ""
+	}
+	fmt.Fprintf(buf, "index %s..%s%s\n", oldID, newID, mode)
+
+	if isBinary(oldContent) || isBinary(newContent) {
+		buf.WriteString("GIT binary patch\n")
+		writeBinaryLiteral(buf, newContent)
+		writeBinaryLiteral(buf, oldContent)
+		return
+	}
+
+	if oldPath == "" {
+		buf.WriteString("--- /dev/null\n")
+	} else {
+		fmt.Fprintf(buf, "--- a/%s\n", oldPath)
+	}
+	if newPath == "" {
+		buf.WriteString("+++ /dev/null\n")
+	} else {
+		fmt.Fprintf(buf, "+++ b/%s\n", newPath)
+	}
+	writeHunks(buf, diffLines(dmp, oldContent, newContent))
+}
+
+// lineOp is one line of a line diff
+type lineOp struct {
+	op   diffmatchpatch.Operation
+	text string // Includes the trailing newline, if any
+}
+
+// diffLines diffs two texts line by line
+func diffLines(dmp *diffmatchpatch.DiffMatchPatch, oldContent, newContent string) []lineOp {
+	oldRunes, newRunes, lines := dmp.DiffLinesToRunes(oldContent, newContent)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMainRunes(oldRunes, newRunes, false), lines)
+
+	var ops []lineOp
+	for _, diff := range diffs {
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line != "" {
+				ops = append(ops, lineOp{op: diff.Type, text: line})
+			}
+		}
+	}
+	return ops
+}
+
+// writeHunks writes a line diff as unified diff hunks
+func writeHunks(buf *bytes.Buffer, ops []lineOp) {
+	// Old and new line numbers before each op
+	oldLine, newLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.op != diffmatchpatch.DiffInsert {
+			oldLine[i+1]++
+		}
+		if op.op != diffmatchpatch.DiffDelete {
+			newLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].op == diffmatchpatch.DiffEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within twice the context
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops) && j <= end+2*contextLines+1; j++ {
+			if ops[j].op != diffmatchpatch.DiffEqual {
+				end = j
+			}
+		}
+		stop := end + 1 + contextLines
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldLine[stop]-oldLine[start]),
+			hunkRange(newLine[start], newLine[stop]-newLine[start]))
+		for _, op := range ops[start:stop] {
+			prefix := " "
+			switch op.op {
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+			}
+			buf.WriteString(prefix)
+			buf.WriteString(op.text)
+			if !strings.HasSuffix(op.text, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+}
+
+// hunkRange formats the range of a hunk header from the number of lines
+// before it and its length. An empty range names the line before it.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// isBinary reports whether content has a NUL byte near its start, as git
+// decides
+func isBinary(content string) bool {
+	if len(content) > binarySniffLen {
+		content = content[:binarySniffLen]
+	}
+	return strings.IndexByte(content, 0) >= 0
+}
+
+// objectID returns the git blob ID of content
+func objectID(content string) string {
+	hash := sha1.New() //#nosec G401 -- Git object IDs are SHA-1
+	fmt.Fprintf(hash, "blob %d\x00", len(content))
+	hash.Write([]byte(content)) //nolint:errcheck
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// base85Alphabet is the alphabet of git binary patches
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// writeBinaryLiteral writes content as a git binary patch literal: zlib
+// compressed, base85 encoded in lines of up to 52 bytes, each prefixed with
+// its length
+func writeBinaryLiteral(buf *bytes.Buffer, content string) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte(content)) //nolint:errcheck
+	zw.Close()                //nolint:errcheck
+
+	fmt.Fprintf(buf, "literal %d\n", len(content))
+	data := compressed.Bytes()
+	for len(data) > 0 {
+		n := len(data)
+		if n > 52 {
+			n = 52
+		}
+		if n <= 26 {
+			buf.WriteByte(byte('A' + n - 1))
+		} else {
+			buf.WriteByte(byte('a' + n - 27))
+		}
+		for i := 0; i < n; i += 4 {
+			var value uint32
+			for j := 0; j < 4; j++ {
+				value <<= 8
+				if i+j < n {
+					value |= uint32(data[i+j])
+				}
+			}
+			var chars [5]byte
+			for k := 4; k >= 0; k-- {
+				chars[k] = base85Alphabet[value%85]
+				value /= 85
+			}
+			buf.Write(chars[:])
+		}
+		buf.WriteByte('\n')
+		data = data[n:]
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,170 @@
+package patch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// combineFixture returns the files before a workflow, its patches, and the
+// files after it
+func combineFixture() (map[string]string, []*Patch, map[string]string) {
+	lines := func(from, to int, changed map[int]string) string {
+		var b strings.Builder
+		for i := from; i <= to; i++ {
+			if text, ok := changed[i]; ok {
+				b.WriteString(text + "\n")
+				continue
+			}
+			b.WriteString("line " + string(rune('a'+i)) + "\n")
+		}
+		return b.String()
+	}
+
+	before := map[string]string{
+		"main.go":     lines(0, 19, nil),
+		"gone.txt":    "remove me\n",
+		"old/name.md": "# Title\n",
+		"notes.txt":   "no newline",
+	}
+	mainStep1 := lines(0, 19, map[int]string{2: "changed 2"})
+	mainStep2 := lines(0, 19, map[int]string{2: "changed 2", 15: "changed 15"})
+	image := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR" + strings.Repeat("\x00\xff", 40)
+
+	start := time.Now()
+	patches := []*Patch{
+		{
+			StepID: "step-2", WorkflowID: "auto-1", Timestamp: start.Add(time.Second),
+			Files: []FilePatch{
+				{Path: "main.go", Status: FileStatusModified, OldContent: mainStep1, NewContent: mainStep2},
+				{Path: "new.txt", Status: FileStatusModified, OldContent: "first\n", NewContent: "first\nsecond\n"},
+				{Path: "new/name.md", OldPath: "old/name.md", Status: FileStatusRenamed},
+				{Path: "tmp.txt", Status: FileStatusDeleted, OldContent: "scratch\n"},
+				{Path: "notes.txt", Status: FileStatusModified, OldContent: "no newline", NewContent: "no newline\nnow two"},
+			},
+		},
+		{
+			StepID: "step-1", WorkflowID: "auto-1", Timestamp: start,
+			Files: []FilePatch{
+				{Path: "main.go", Status: FileStatusModified, OldContent: before["main.go"], NewContent: mainStep1},
+				{Path: "new.txt", Status: FileStatusAdded, NewContent: "first\n"},
+				{Path: "gone.txt", Status: FileStatusDeleted, OldContent: "remove me\n"},
+				{Path: "tmp.txt", Status: FileStatusAdded, NewContent: "scratch\n"},
+				{Path: "logo.png", Status: FileStatusAdded, NewContent: image},
+			},
+		},
+	}
+
+	after := map[string]string{
+		"main.go":     mainStep2,
+		"new.txt":     "first\nsecond\n",
+		"new/name.md": "# Title\n",
+		"notes.txt":   "no newline\nnow two",
+		"logo.png":    image,
+	}
+	return before, patches, after
+}
+
+// TestCombinePatches tests merging step patches into one diff
+func TestCombinePatches(t *testing.T) {
+	_, patches, _ := combineFixture()
+	diff := CombinePatches(patches)
+
+	for _, want := range []string{
+		"diff --git a/gone.txt b/gone.txt\ndeleted file mode 100644\n",
+		"--- a/gone.txt\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-remove me\n",
+		"diff --git a/logo.png b/logo.png\nnew file mode 100644\n",
+		"GIT binary patch\nliteral 96\n",
+		"@@ -1,6 +1,6 @@\n line a\n line b\n-line c\n+changed 2\n line d\n line e\n line f\n",
+		"@@ -13,7 +13,7 @@\n",
+		"diff --git a/old/name.md b/new/name.md\nsimilarity index 100%\nrename from old/name.md\nrename to new/name.md\n",
+		"--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+first\n+second\n",
+		"-no newline\n\\ No newline at end of file\n+no newline\n+now two\n\\ No newline at end of file\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to contain %q:\n%s", want, diff)
+		}
+	}
+
+	// A file added and deleted by the workflow is left out
+	if strings.Contains(diff, "tmp.txt") {
+		t.Errorf("expected tmp.txt to be left out:\n%s", diff)
+	}
+	// Files are listed in path order
+	if strings.Index(diff, "a/gone.txt") > strings.Index(diff, "a/main.go") {
+		t.Errorf("expected files in path order:\n%s", diff)
+	}
+}
+
+// TestCombinePatchesGitApply tests that git apply accepts the combined diff
+func TestCombinePatchesGitApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	before, patches, after := combineFixture()
+	repo := t.TempDir()
+	for path, content := range before {
+		full := filepath.Join(repo, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	patchFile := filepath.Join(t.TempDir(), "changes.patch")
+	if err := os.WriteFile(patchFile, []byte(CombinePatches(patches)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"init", "-q"}, {"apply", "--check", patchFile}, {"apply", patchFile}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	for path, content := range after {
+		data, err := os.ReadFile(filepath.Join(repo, path))
+		if err != nil {
+			t.Errorf("expected %s after apply: %v", path, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", path, data, content)
+		}
+	}
+	for _, path := range []string{"gone.txt", "old/name.md", "tmp.txt"} {
+		if _, err := os.Stat(filepath.Join(repo, path)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be gone after apply", path)
+		}
+	}
+}
+
+// TestWriterCombine tests combining the saved patches of a workflow
+func TestWriterCombine(t *testing.T) {
+	writer := NewWriter(t.TempDir())
+	_, patches, _ := combineFixture()
+	for _, patch := range patches {
+		if _, err := writer.WritePatch(patch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	diff, err := writer.Combine("auto-1")
+	if err != nil {
+		t.Fatalf("Combine() error = %v", err)
+	}
+	if diff != CombinePatches(patches) {
+		t.Errorf("expected the saved patches to combine like the originals:\n%s", diff)
+	}
+
+	if _, err := writer.Combine("auto-2"); err == nil {
+		t.Error("expected an error for a workflow without patches")
+	}
+}
@@ -43,7 +43,10 @@ func (g *DiffGenerator) GeneratePatch(stepID, stepType, workflowID, description
 	for path, oldContent := range fileSnapshots {
 		processedFiles[path] = true
 
-		fullPath := filepath.Join(g.workingDir, path)
+		fullPath, err := ResolveWithinRoot(g.workingDir, path)
+		if err != nil {
+			return nil, err
+		}
 		newContentBytes, err := os.ReadFile(fullPath)
 
 		if os.IsNotExist(err) {
@@ -166,6 +169,10 @@ func (g *DiffGenerator) createDeletedFilePatch(path, oldContent string) FilePatc
 
 // generateUnifiedDiff generates a unified diff in standard format
 func (g *DiffGenerator) generateUnifiedDiff(path, oldContent, newContent string) string {
+	if isBinary(oldContent) || isBinary(newContent) {
+		return fmt.Sprintf("Binary files a/%s and b/%s differ\n", path, path)
+	}
+
 	diffs := g.dmp.DiffMain(oldContent, newContent, false)
 
 	var buf bytes.Buffer
@@ -266,7 +273,10 @@ func CaptureFileSnapshot(workingDir string, paths []string) (map[string]string,
 	snapshot := make(map[string]string)
 
 	for _, path := range paths {
-		fullPath := filepath.Join(workingDir, path)
+		fullPath, err := ResolveWithinRoot(workingDir, path)
+		if err != nil {
+			return nil, err
+		}
 		content, err := os.ReadFile(fullPath)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -0,0 +1,83 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathEscapesRoot is returned when a file operation targets a path outside
+// the project root
+var ErrPathEscapesRoot = errors.New("security: path escapes project root")
+
+// ResolveWithinRoot resolves path against root and returns the absolute path.
+// Symlinks are resolved for the longest existing prefix of the path, so a
+// link inside the root that points outside it is rejected just like "../"
+// traversal or an absolute path elsewhere on disk.
+func ResolveWithinRoot(root, path string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(absRoot, target)
+	}
+	target = filepath.Clean(target)
+
+	// Paths given relative to the unresolved root are rebased onto the real root
+	if rel, err := filepath.Rel(absRoot, target); err == nil && isWithin(rel) {
+		target = filepath.Join(realRoot, rel)
+	}
+
+	resolved, err := evalExistingPrefix(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(realRoot, resolved)
+	if err != nil || !isWithin(rel) {
+		return "", fmt.Errorf("%w: %s resolves to %s outside %s", ErrPathEscapesRoot, path, resolved, realRoot)
+	}
+
+	return resolved, nil
+}
+
+// evalExistingPrefix resolves symlinks in the deepest existing ancestor of
+// path and re-appends the components that do not exist yet
+func evalExistingPrefix(path string) (string, error) {
+	var missing []string
+	current := path
+
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				resolved = filepath.Join(resolved, missing[i])
+			}
+			return resolved, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		missing = append(missing, filepath.Base(current))
+		current = parent
+	}
+}
+
+// isWithin reports whether a relative path stays inside its base
+func isWithin(rel string) bool {
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestResolveWithinRoot tests path containment checks
+func TestResolveWithinRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"relative file", "file.txt", false},
+		{"nested new file", "sub/new/file.txt", false},
+		{"dot-dot inside root", "sub/../file.txt", false},
+		{"absolute inside root", filepath.Join(root, "sub", "file.txt"), false},
+		{"parent traversal", "../file.txt", true},
+		{"deep traversal", "sub/../../../etc/passwd", true},
+		{"absolute outside root", filepath.Join(outside, "file.txt"), true},
+		{"absolute system path", "/etc/passwd", true},
+		{"symlink escape", "escape/file.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveWithinRoot(root, tt.path)
+			if tt.wantErr {
+				if !errors.Is(err, ErrPathEscapesRoot) {
+					t.Errorf("ResolveWithinRoot(%q) error = %v, want ErrPathEscapesRoot", tt.path, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ResolveWithinRoot(%q) unexpected error = %v", tt.path, err)
+			}
+		})
+	}
+}
+
+// TestRollbackRejectsPathOutsideRoot tests that rollback never touches files
+// outside the working directory
+func TestRollbackRejectsPathOutsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	workingDir := filepath.Join(parent, "project")
+	patchDir := t.TempDir()
+	if err := os.MkdirAll(workingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	victim := filepath.Join(parent, "victim.txt")
+	if err := os.WriteFile(victim, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		workflow string
+		path     string
+	}{
+		{"parent traversal", "wf-traversal", "../victim.txt"},
+		{"absolute path", "wf-absolute", victim},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := NewWriter(patchDir)
+			p := &Patch{
+				StepID:     "step-1",
+				Timestamp:  time.Now(),
+				WorkflowID: tt.workflow,
+				Files: []FilePatch{
+					{Path: tt.path, Status: FileStatusAdded, NewContent: "keep me"},
+				},
+			}
+			if _, err := writer.WritePatch(p); err != nil {
+				t.Fatalf("WritePatch() error = %v", err)
+			}
+
+			rollback := NewRollback(workingDir, patchDir)
+			err := rollback.RollbackStep(p.WorkflowID, "step-1")
+			if !errors.Is(err, ErrPathEscapesRoot) {
+				t.Fatalf("RollbackStep() error = %v, want ErrPathEscapesRoot", err)
+			}
+
+			if _, err := os.Stat(victim); err != nil {
+				t.Errorf("file outside root was removed: %v", err)
+			}
+		})
+	}
+}
+
+// TestCaptureFileSnapshotRejectsPathOutsideRoot tests snapshot containment
+func TestCaptureFileSnapshotRejectsPathOutsideRoot(t *testing.T) {
+	workingDir := t.TempDir()
+
+	_, err := CaptureFileSnapshot(workingDir, []string{"../../etc/passwd"})
+	if !errors.Is(err, ErrPathEscapesRoot) {
+		t.Errorf("CaptureFileSnapshot() error = %v, want ErrPathEscapesRoot", err)
+	}
+}
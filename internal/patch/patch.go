@@ -1,8 +1,11 @@
 package patch
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"time"
+	"unicode/utf8"
 )
 
 // Patch represents a single patch with metadata
@@ -40,6 +43,55 @@ type FilePatch struct {
 	Deletions  int `json:"deletions"`
 }
 
+// fileContentJSON stores file contents that are not valid UTF-8, such as
+// binary files, as base64 so they survive JSON encoding
+type fileContentJSON struct {
+	filePatchJSON
+	OldContentBase64 string `json:"oldContentBase64,omitempty"`
+	NewContentBase64 string `json:"newContentBase64,omitempty"`
+}
+
+// filePatchJSON is FilePatch without its JSON methods
+type filePatchJSON FilePatch
+
+// MarshalJSON encodes contents that are not valid UTF-8 as base64
+func (f FilePatch) MarshalJSON() ([]byte, error) {
+	aux := fileContentJSON{filePatchJSON: filePatchJSON(f)}
+	if !utf8.ValidString(f.OldContent) {
+		aux.OldContentBase64 = base64.StdEncoding.EncodeToString([]byte(f.OldContent))
+		aux.OldContent = ""
+	}
+	if !utf8.ValidString(f.NewContent) {
+		aux.NewContentBase64 = base64.StdEncoding.EncodeToString([]byte(f.NewContent))
+		aux.NewContent = ""
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes contents stored as base64
+func (f *FilePatch) UnmarshalJSON(data []byte) error {
+	var aux fileContentJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*f = FilePatch(aux.filePatchJSON)
+	if aux.OldContentBase64 != "" {
+		content, err := base64.StdEncoding.DecodeString(aux.OldContentBase64)
+		if err != nil {
+			return fmt.Errorf("invalid old content of %s: %w", f.Path, err)
+		}
+		f.OldContent = string(content)
+	}
+	if aux.NewContentBase64 != "" {
+		content, err := base64.StdEncoding.DecodeString(aux.NewContentBase64)
+		if err != nil {
+			return fmt.Errorf("invalid new content of %s: %w", f.Path, err)
+		}
+		f.NewContent = string(content)
+	}
+	return nil
+}
+
 // FileStatus represents the type of change to a file
 type FileStatus string
 
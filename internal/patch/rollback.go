@@ -24,6 +24,7 @@ func NewRollback(workingDir string, patchDir string) *Rollback {
 type RollbackResult struct {
 	Success       bool     `json:"success"`
 	StepsReverted int      `json:"stepsReverted"`
+	FilesReverted []string `json:"filesReverted"`
 	Errors        []string `json:"errors"`
 	Conflicts     []string `json:"conflicts"`
 }
@@ -49,43 +50,35 @@ func (r *Rollback) RollbackStep(workflowID, stepID string) error {
 // RollbackToStep rolls back all steps after the specified step
 func (r *Rollback) RollbackToStep(workflowID, targetStepID string) (*RollbackResult, error) {
 	result := &RollbackResult{
-		Success:   true,
-		Errors:    []string{},
-		Conflicts: []string{},
+		Success:       true,
+		FilesReverted: []string{},
+		Errors:        []string{},
+		Conflicts:     []string{},
 	}
 
-	// List all patches for the workflow
-	patches, err := r.writer.ListPatches(workflowID)
+	patchesToRollback, err := r.StepsToRollback(workflowID, targetStepID)
 	if err != nil {
 		result.Success = false
-		result.Errors = append(result.Errors, fmt.Sprintf("failed to list patches: %v", err))
+		result.Errors = append(result.Errors, err.Error())
 		return result, err
 	}
 
-	// Find patches to rollback (all patches after target step)
-	var patchesToRollback []*PatchMetadata
-	foundTarget := false
-
-	// Patches are in chronological order, we need to rollback in reverse
-	for i := len(patches) - 1; i >= 0; i-- {
-		patch := patches[i]
-
-		if patch.StepID == targetStepID {
-			foundTarget = true
-			break
-		}
-
-		patchesToRollback = append(patchesToRollback, patch)
-	}
+	return r.RollbackSteps(workflowID, patchesToRollback), nil
+}
 
-	if !foundTarget && targetStepID != "" {
-		result.Success = false
-		result.Errors = append(result.Errors, fmt.Sprintf("target step %s not found", targetStepID))
-		return result, fmt.Errorf("target step not found")
+// RollbackSteps reverts the patches in the given order and records the
+// files it reverted. A step that fails to revert is reported and the
+// remaining steps are still reverted.
+func (r *Rollback) RollbackSteps(workflowID string, patches []*Patch) *RollbackResult {
+	result := &RollbackResult{
+		Success:       true,
+		FilesReverted: []string{},
+		Errors:        []string{},
+		Conflicts:     []string{},
 	}
 
-	// Rollback each patch in reverse order
-	for _, patch := range patchesToRollback {
+	reverted := make(map[string]bool)
+	for _, patch := range patches {
 		if err := r.RollbackStep(workflowID, patch.StepID); err != nil {
 			result.Success = false
 			result.Errors = append(result.Errors, fmt.Sprintf("step %s: %v", patch.StepID, err))
@@ -93,9 +86,45 @@ func (r *Rollback) RollbackToStep(workflowID, targetStepID string) (*RollbackRes
 		}
 
 		result.StepsReverted++
+		for _, filePatch := range patch.Files {
+			if !reverted[filePatch.Path] {
+				reverted[filePatch.Path] = true
+				result.FilesReverted = append(result.FilesReverted, filePatch.Path)
+			}
+		}
 	}
 
-	return result, nil
+	return result
+}
+
+// StepsToRollback returns the patches of the steps after targetStepID,
+// newest first: the order they are reverted in. An empty targetStepID
+// returns every step of the workflow.
+func (r *Rollback) StepsToRollback(workflowID, targetStepID string) ([]*Patch, error) {
+	patches, err := r.writer.ListPatches(workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patches: %w", err)
+	}
+
+	var steps []*Patch
+	foundTarget := false
+	for i := len(patches) - 1; i >= 0; i-- {
+		if patches[i].StepID == targetStepID {
+			foundTarget = true
+			break
+		}
+
+		patch, err := r.writer.ReadPatch(workflowID, patches[i].StepID)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, patch)
+	}
+
+	if !foundTarget && targetStepID != "" {
+		return nil, fmt.Errorf("target step %s not found", targetStepID)
+	}
+	return steps, nil
 }
 
 // RollbackAll rolls back all steps for a workflow
@@ -105,7 +134,10 @@ func (r *Rollback) RollbackAll(workflowID string) (*RollbackResult, error) {
 
 // rollbackFile applies a file patch in reverse
 func (r *Rollback) rollbackFile(filePatch FilePatch) error {
-	fullPath := filepath.Join(r.workingDir, filePatch.Path)
+	fullPath, err := ResolveWithinRoot(r.workingDir, filePatch.Path)
+	if err != nil {
+		return err
+	}
 
 	switch filePatch.Status {
 	case FileStatusAdded:
@@ -122,7 +154,10 @@ func (r *Rollback) rollbackFile(filePatch FilePatch) error {
 
 	case FileStatusRenamed:
 		// File was renamed, rename it back
-		oldFullPath := filepath.Join(r.workingDir, filePatch.OldPath)
+		oldFullPath, err := ResolveWithinRoot(r.workingDir, filePatch.OldPath)
+		if err != nil {
+			return err
+		}
 		return r.renameFile(fullPath, oldFullPath)
 
 	default:
@@ -189,49 +224,85 @@ func (r *Rollback) renameFile(newPath, oldPath string) error {
 
 // VerifyRollbackSafety checks if rollback can be safely performed
 func (r *Rollback) VerifyRollbackSafety(workflowID, stepID string) (bool, []string, error) {
-	var warnings []string
-
 	// Read the patch
 	patch, err := r.writer.ReadPatch(workflowID, stepID)
 	if err != nil {
-		return false, warnings, fmt.Errorf("failed to read patch: %w", err)
+		return false, nil, fmt.Errorf("failed to read patch: %w", err)
 	}
 
-	// Check each file for potential conflicts
-	for _, filePatch := range patch.Files {
-		fullPath := filepath.Join(r.workingDir, filePatch.Path)
+	warnings, err := r.CheckDivergence([]*Patch{patch})
+	if err != nil {
+		return false, warnings, err
+	}
 
-		switch filePatch.Status {
-		case FileStatusAdded:
-			// Check if file still exists
-			if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-				warnings = append(warnings, fmt.Sprintf("file %s no longer exists", filePatch.Path))
+	// Return true if no critical errors, but include warnings
+	return len(warnings) == 0, warnings, nil
+}
+
+// CheckDivergence compares the working tree with the state the patches
+// left it in, and returns a warning for each file that changed since. The
+// patches are newest first, as returned by StepsToRollback; each file is
+// compared with the newest patch that touched it.
+func (r *Rollback) CheckDivergence(patches []*Patch) ([]string, error) {
+	var warnings []string
+	checked := make(map[string]bool)
+
+	for _, patch := range patches {
+		for _, filePatch := range patch.Files {
+			if checked[filePatch.Path] {
+				continue
 			}
+			checked[filePatch.Path] = true
 
-		case FileStatusModified:
-			// Check if current content matches what we expect
-			currentContent, err := os.ReadFile(fullPath)
+			warning, err := r.checkFile(filePatch)
 			if err != nil {
-				if os.IsNotExist(err) {
-					warnings = append(warnings, fmt.Sprintf("file %s no longer exists", filePatch.Path))
-					continue
-				}
-				return false, warnings, fmt.Errorf("failed to read file %s: %w", fullPath, err)
+				return warnings, err
 			}
-
-			// Warn if content has changed since patch was created
-			if string(currentContent) != filePatch.NewContent {
-				warnings = append(warnings, fmt.Sprintf("file %s has been modified since patch was created", filePatch.Path))
+			if warning != "" {
+				warnings = append(warnings, warning)
 			}
+		}
+	}
 
-		case FileStatusDeleted:
-			// Check if file has been recreated
-			if _, err := os.Stat(fullPath); err == nil {
-				warnings = append(warnings, fmt.Sprintf("file %s has been recreated", filePatch.Path))
+	return warnings, nil
+}
+
+// checkFile returns a warning if the file no longer matches the state the
+// file patch left it in
+func (r *Rollback) checkFile(filePatch FilePatch) (string, error) {
+	fullPath, err := ResolveWithinRoot(r.workingDir, filePatch.Path)
+	if err != nil {
+		return "", err
+	}
+
+	switch filePatch.Status {
+	case FileStatusAdded, FileStatusModified:
+		// Check if current content matches what we expect
+		currentContent, err := os.ReadFile(fullPath) //#nosec G304 -- Path resolved within the working directory
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Sprintf("file %s no longer exists", filePatch.Path), nil
 			}
+			return "", fmt.Errorf("failed to read file %s: %w", fullPath, err)
+		}
+
+		// Warn if content has changed since patch was created
+		if string(currentContent) != filePatch.NewContent {
+			return fmt.Sprintf("file %s has been modified since patch was created", filePatch.Path), nil
+		}
+
+	case FileStatusDeleted:
+		// Check if file has been recreated
+		if _, err := os.Stat(fullPath); err == nil {
+			return fmt.Sprintf("file %s has been recreated", filePatch.Path), nil
+		}
+
+	case FileStatusRenamed:
+		// Check that the file is still at its new path
+		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+			return fmt.Sprintf("file %s no longer exists", filePatch.Path), nil
 		}
 	}
 
-	// Return true if no critical errors, but include warnings
-	return len(warnings) == 0, warnings, nil
+	return "", nil
 }
@@ -406,3 +406,58 @@ func TestVerifyRollbackSafety(t *testing.T) {
 		t.Error("Expected warnings about modified file")
 	}
 }
+
+// TestStepsToRollbackAndDivergence tests the rollback order and checking
+// each file against the newest patch that touched it
+func TestStepsToRollbackAndDivergence(t *testing.T) {
+	workingDir := t.TempDir()
+	patchDir := t.TempDir()
+	writer := NewWriter(patchDir)
+	rollback := NewRollback(workingDir, patchDir)
+
+	// step-10 sorts before step-2 by name but ran after it
+	start := time.Now()
+	for i, stepID := range []string{"step-2", "step-10"} {
+		content := "content" + string(rune('1'+i))
+		patch := &Patch{
+			StepID:     stepID,
+			WorkflowID: "test-workflow",
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			Files: []FilePatch{
+				{Path: "file.txt", Status: FileStatusModified, OldContent: "content" + string(rune('0'+i)), NewContent: content},
+			},
+		}
+		if _, err := writer.WritePatch(patch); err != nil {
+			t.Fatalf("Failed to write patch: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(workingDir, "file.txt"), []byte("content2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := rollback.StepsToRollback("test-workflow", "")
+	if err != nil {
+		t.Fatalf("StepsToRollback() error = %v", err)
+	}
+	if len(steps) != 2 || steps[0].StepID != "step-10" || steps[1].StepID != "step-2" {
+		t.Fatalf("expected step-10 then step-2, got %v", steps)
+	}
+
+	// Only the newest patch of the file is compared
+	warnings, err := rollback.CheckDivergence(steps)
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("expected no divergence, got %v, %v", warnings, err)
+	}
+
+	result := rollback.RollbackSteps("test-workflow", steps)
+	if !result.Success || result.StepsReverted != 2 || len(result.FilesReverted) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workingDir, "file.txt")); string(data) != "content0" {
+		t.Errorf("file.txt = %q, want content0", data)
+	}
+
+	if _, err := rollback.StepsToRollback("test-workflow", "step-3"); err == nil {
+		t.Error("expected an error for an unknown step")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // Writer handles writing patches to disk
@@ -61,7 +62,7 @@ func (w *Writer) ReadPatch(workflowID, stepID string) (*Patch, error) {
 	return patch, nil
 }
 
-// ListPatches lists all patches for a workflow
+// ListPatches lists all patches for a workflow, oldest first
 func (w *Writer) ListPatches(workflowID string) ([]*PatchMetadata, error) {
 	pattern := filepath.Join(w.patchDir, fmt.Sprintf("%s_*.patch.json", workflowID))
 	files, err := filepath.Glob(pattern)
@@ -84,6 +85,11 @@ func (w *Writer) ListPatches(workflowID string) ([]*PatchMetadata, error) {
 		patches = append(patches, patch.GetMetadata())
 	}
 
+	// Oldest first; file names sort step-10 before step-2
+	sort.SliceStable(patches, func(i, j int) bool {
+		return patches[i].Timestamp.Before(patches[j].Timestamp)
+	})
+
 	return patches, nil
 }
 
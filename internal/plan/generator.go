@@ -63,11 +63,13 @@ func (g *DefaultPlanGenerator) Generate(ctx context.Context, s *spec.ProductSpec
 			Skill:        g.determineSkill(feature),
 			Priority:     feature.Priority,
 			ModelHint:    g.determineModelHint(feature),
+			Tags:         feature.Tags,
 		}
 
 		// Estimate complexity if enabled
 		if opts.EstimateComplexity {
 			task.Estimate = g.estimateComplexity(feature)
+			task.Effort = types.EffortForComplexity(task.Estimate)
 		}
 
 		tasks = append(tasks, task)
@@ -182,6 +184,20 @@ func (g *DefaultPlanGenerator) estimateComplexity(feature spec.Feature) int {
 	return complexity
 }
 
+// EstimateEffort estimates a feature's effort in story points from its complexity
+func EstimateEffort(feature spec.Feature) types.Effort {
+	return types.EffortForComplexity(defaultGenerator.estimateComplexity(feature))
+}
+
+// EstimateSpecEffort totals the estimated effort of a spec's features
+func EstimateSpecEffort(features []spec.Feature) types.Effort {
+	efforts := make([]types.Effort, len(features))
+	for i, feature := range features {
+		efforts[i] = EstimateEffort(feature)
+	}
+	return types.SumEffort(efforts...)
+}
+
 // validateDependencies ensures the task graph is acyclic
 func (g *DefaultPlanGenerator) validateDependencies(tasks []Task) error {
 	// Build task ID set for validation
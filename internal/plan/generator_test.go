@@ -22,6 +22,7 @@ func TestGenerate(t *testing.T) {
 				},
 				Success: []string{"Users can login"},
 				Trace:   []string{"PRD-001"},
+				Tags:    []string{"backend", "critical"},
 			},
 			{
 				ID:       types.FeatureID("feat-002"),
@@ -75,6 +76,24 @@ func TestGenerate(t *testing.T) {
 		t.Errorf("Task 0 hash = %s, want hash001", plan.Tasks[0].ExpectedHash)
 	}
 
+	// Check tags are copied from the feature
+	if len(plan.Tasks[0].Tags) != 2 || plan.Tasks[0].Tags[1] != "critical" {
+		t.Errorf("Task 0 tags = %v, want [backend critical]", plan.Tasks[0].Tags)
+	}
+	if len(plan.Tasks[1].Tags) != 0 {
+		t.Errorf("Task 1 tags = %v, want none", plan.Tasks[1].Tags)
+	}
+
+	// Check effort is estimated from complexity and totalled
+	for _, task := range plan.Tasks {
+		if task.Effort != types.EffortForComplexity(task.Estimate) {
+			t.Errorf("Task %s effort = %v, want %v for complexity %d", task.ID, task.Effort, types.EffortForComplexity(task.Estimate), task.Estimate)
+		}
+	}
+	if total := plan.TotalEffort(); total != 7 {
+		t.Errorf("TotalEffort() = %v, want 7 pts", total)
+	}
+
 	// Check priorities
 	if plan.Tasks[0].Priority != "P0" {
 		t.Errorf("Task 0 priority = %s, want P0", plan.Tasks[0].Priority)
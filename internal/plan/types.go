@@ -13,8 +13,19 @@ type Task struct {
 	FeatureID    types.FeatureID `json:"feature_id"`
 	ExpectedHash string          `json:"expected_hash"` // Links to SpecLock feature hash
 	DependsOn    []types.TaskID  `json:"depends_on"`
-	Skill        string          `json:"skill"`      // go-backend, ui-react, infra, etc.
-	Priority     types.Priority  `json:"priority"`   // P0, P1, P2
-	ModelHint    string          `json:"model_hint"` // long-context, agentic, codegen, etc.
-	Estimate     int             `json:"estimate"`   // Estimated complexity/time
+	Skill        string          `json:"skill"`            // go-backend, ui-react, infra, etc.
+	Priority     types.Priority  `json:"priority"`         // P0, P1, P2
+	ModelHint    string          `json:"model_hint"`       // long-context, agentic, codegen, etc.
+	Estimate     int             `json:"estimate"`         // Estimated complexity/time
+	Effort       types.Effort    `json:"effort,omitempty"` // Estimated story points
+	Tags         []string        `json:"tags,omitempty"`   // Tags of the task's feature
+}
+
+// TotalEffort sums the estimated effort of the plan's tasks
+func (p *Plan) TotalEffort() types.Effort {
+	efforts := make([]types.Effort, len(p.Tasks))
+	for i, task := range p.Tasks {
+		efforts[i] = task.Effort
+	}
+	return types.SumEffort(efforts...)
 }
@@ -49,6 +49,13 @@ func (t *Task) Validate() error {
 		return fmt.Errorf("estimate must be positive, got %d", t.Estimate)
 	}
 
+	// Validate Effort using domain validation, if estimated
+	if t.Effort != 0 {
+		if err := t.Effort.Validate(); err != nil {
+			return fmt.Errorf("invalid effort: %w", err)
+		}
+	}
+
 	return nil
 }
 
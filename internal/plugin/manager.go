@@ -21,6 +21,11 @@ type Manager struct {
 	plugins    map[string]*Plugin
 	pluginDirs []string
 	config     ManagerConfig
+
+	// Persistent plugin processes and health handshakes by plugin name
+	sessionsMu sync.Mutex
+	sessions   map[string]*session
+	handshakes map[string]handshakeResult
 }
 
 // ManagerConfig contains configuration for the plugin manager
@@ -31,6 +36,8 @@ type ManagerConfig struct {
 	Timeout time.Duration
 	// PluginDirs are directories to search for plugins
 	PluginDirs []string
+	// Sandbox limits plugin resources, environment, and network access
+	Sandbox SandboxConfig
 }
 
 // DefaultManagerConfig returns default configuration
@@ -52,6 +59,8 @@ func NewManager(config ManagerConfig) *Manager {
 		plugins:    make(map[string]*Plugin),
 		pluginDirs: config.PluginDirs,
 		config:     config,
+		sessions:   make(map[string]*session),
+		handshakes: make(map[string]handshakeResult),
 	}
 }
 
@@ -216,14 +225,40 @@ func (m *Manager) Execute(ctx context.Context, name string, request interface{})
 	return m.executePlugin(ctx, plugin, request)
 }
 
-// executePlugin runs a plugin executable
+// executePlugin runs a plugin, through its running process if the plugin
+// is persistent. The health handshake runs on first use; requests for
+// actions the plugin did not advertise are rejected without running it.
+// Plugins whose health check fails are run one-shot without checks.
 func (m *Manager) executePlugin(ctx context.Context, plugin *Plugin, request interface{}) (*PluginResponse, error) {
-	// Get entrypoint path
+	health, err := m.handshake(ctx, plugin)
+	if err != nil {
+		return m.executeOnce(ctx, plugin, request)
+	}
+
+	if err := checkCompatibility(plugin, health); err != nil {
+		return nil, err
+	}
+	if err := checkAction(plugin, health, request); err != nil {
+		return nil, err
+	}
+
+	if health.Persistent {
+		return m.executePersistent(ctx, plugin, request)
+	}
+	return m.executeOnce(ctx, plugin, request)
+}
+
+// entrypoint returns the absolute path of the plugin executable
+func entrypoint(plugin *Plugin) string {
 	entrypointPath := plugin.Manifest.Entrypoint
 	if !filepath.IsAbs(entrypointPath) {
 		entrypointPath = filepath.Join(plugin.Path, entrypointPath)
 	}
+	return entrypointPath
+}
 
+// executeOnce runs the plugin executable for a single request
+func (m *Manager) executeOnce(ctx context.Context, plugin *Plugin, request interface{}) (*PluginResponse, error) {
 	// Serialize request
 	requestData, err := json.Marshal(request)
 	if err != nil {
@@ -235,7 +270,10 @@ func (m *Manager) executePlugin(ctx context.Context, plugin *Plugin, request int
 	defer cancel()
 
 	// Execute plugin
-	cmd := exec.CommandContext(execCtx, entrypointPath)
+	cmd, err := m.command(execCtx, plugin)
+	if err != nil {
+		return nil, err
+	}
 	cmd.Stdin = bytes.NewReader(requestData)
 
 	var stdout, stderr bytes.Buffer
@@ -246,6 +284,9 @@ func (m *Manager) executePlugin(ctx context.Context, plugin *Plugin, request int
 		if execCtx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("plugin execution timed out after %v", m.config.Timeout)
 		}
+		if limitErr := m.limitError(plugin, cmd.ProcessState, stderr.String()); limitErr != nil {
+			return nil, limitErr
+		}
 		return nil, fmt.Errorf("plugin execution failed: %w (stderr: %s)", err, stderr.String())
 	}
 
@@ -265,12 +306,32 @@ func (m *Manager) Health(ctx context.Context, name string) (*HealthResponse, err
 		return nil, fmt.Errorf("plugin not found: %s", name)
 	}
 
-	request := HealthRequest{Action: "health"}
-	resp, err := m.executePlugin(ctx, plugin, request)
+	// Use the running process of a persistent plugin
+	m.sessionsMu.Lock()
+	s, running := m.sessions[name]
+	m.sessionsMu.Unlock()
+	if running && s.alive() {
+		resp, err := s.call(ctx, HealthRequest{Action: "health"}, m.config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		return parseHealth(resp)
+	}
+
+	return m.healthOnce(ctx, plugin)
+}
+
+// healthOnce runs a one-shot health check
+func (m *Manager) healthOnce(ctx context.Context, plugin *Plugin) (*HealthResponse, error) {
+	resp, err := m.executeOnce(ctx, plugin, HealthRequest{Action: "health"})
 	if err != nil {
 		return nil, err
 	}
+	return parseHealth(resp)
+}
 
+// parseHealth extracts the health response from a plugin response
+func parseHealth(resp *PluginResponse) (*HealthResponse, error) {
 	if !resp.Success {
 		return nil, fmt.Errorf("health check failed: %s", resp.Error)
 	}
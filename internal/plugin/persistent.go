@@ -0,0 +1,234 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ShutdownAction asks a persistent plugin to exit. No response is expected.
+const ShutdownAction = "shutdown"
+
+// errPluginExited is returned when a persistent plugin process exits while
+// handling a request
+var errPluginExited = errors.New("plugin exited")
+
+// shutdownGrace is how long a persistent plugin has to exit after shutdown
+// before it is killed
+const shutdownGrace = 5 * time.Second
+
+// session is a long-lived plugin process that exchanges newline-delimited
+// JSON requests and responses over its stdin and stdout. Requests are
+// handled one at a time.
+type session struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *lockedBuffer
+	done   chan struct{} // Closed when the process exits
+}
+
+// lockedBuffer collects stderr written by the process while requests read it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// startSession launches a persistent plugin process
+func startSession(cmd *exec.Cmd) (*session, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin stdout: %w", err)
+	}
+	stderr := &lockedBuffer{}
+	cmd.Stderr = stderr
+
+	// Don't wait on stderr held open by children of a killed plugin
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin: %w", err)
+	}
+
+	s := &session{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReader(stdout),
+		stderr: stderr,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		cmd.Wait() //nolint:errcheck // Exit status is reported through failed requests
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// alive reports whether the process is still running
+func (s *session) alive() bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// call sends a request and waits for its response line. After a timeout
+// the process is killed, because a late response would be read as the
+// answer to the next request.
+func (s *session) call(ctx context.Context, request interface{}, timeout time.Duration) (*PluginResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("serialize request: %w", err)
+	}
+
+	if _, err := s.stdin.Write(append(requestData, '\n')); err != nil {
+		return nil, fmt.Errorf("%w: %v (stderr: %s)", errPluginExited, err, s.stderr.String())
+	}
+
+	type readResult struct {
+		line []byte
+		err  error
+	}
+	results := make(chan readResult, 1)
+	go func() {
+		line, err := s.stdout.ReadBytes('\n')
+		results <- readResult{line, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return nil, fmt.Errorf("%w: %v (stderr: %s)", errPluginExited, result.err, s.stderr.String())
+		}
+		var response PluginResponse
+		if err := json.Unmarshal(result.line, &response); err != nil {
+			return nil, fmt.Errorf("parse plugin response: %w (output: %s)", err, result.line)
+		}
+		return &response, nil
+	case <-timer.C:
+		s.kill()
+		return nil, fmt.Errorf("plugin execution timed out after %v", timeout)
+	case <-ctx.Done():
+		s.kill()
+		return nil, ctx.Err()
+	}
+}
+
+// shutdown sends the shutdown action and waits for the process to exit,
+// killing it after the grace period
+func (s *session) shutdown(grace time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if data, err := json.Marshal(PluginRequest{Action: ShutdownAction}); err == nil {
+		s.stdin.Write(append(data, '\n')) //nolint:errcheck // The plugin may already have exited
+	}
+	s.stdin.Close() //nolint:errcheck // Closing stdin also signals EOF
+
+	select {
+	case <-s.done:
+	case <-time.After(grace):
+		s.kill()
+	}
+}
+
+// exited waits up to the grace period for the process to exit and reports
+// whether it did
+func (s *session) exited(grace time.Duration) bool {
+	select {
+	case <-s.done:
+		return true
+	case <-time.After(grace):
+		return false
+	}
+}
+
+func (s *session) kill() {
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill() //nolint:errcheck // The process may already have exited
+	}
+	<-s.done
+}
+
+// executePersistent sends a request to the plugin's running process,
+// starting it on first use or after it exited
+func (m *Manager) executePersistent(ctx context.Context, plugin *Plugin, request interface{}) (*PluginResponse, error) {
+	m.sessionsMu.Lock()
+	s, ok := m.sessions[plugin.Manifest.Name]
+	if !ok || !s.alive() {
+		// The process outlives the request, so it is not bound to ctx
+		cmd, err := m.command(context.Background(), plugin)
+		if err == nil {
+			s, err = startSession(cmd)
+		}
+		if err != nil {
+			m.sessionsMu.Unlock()
+			return nil, err
+		}
+		m.sessions[plugin.Manifest.Name] = s
+	}
+	m.sessionsMu.Unlock()
+
+	resp, err := s.call(ctx, request, m.config.Timeout)
+	if errors.Is(err, errPluginExited) && s.exited(time.Second) {
+		if limitErr := m.limitError(plugin, s.cmd.ProcessState, s.stderr.String()); limitErr != nil {
+			return nil, limitErr
+		}
+	}
+	return resp, err
+}
+
+// Close shuts down all persistent plugin processes. The manager can still
+// be used afterwards; processes are started again on demand.
+func (m *Manager) Close() error {
+	m.sessionsMu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*session)
+	m.sessionsMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range sessions {
+		if !s.alive() {
+			continue
+		}
+		wg.Add(1)
+		go func(s *session) {
+			defer wg.Done()
+			s.shutdown(shutdownGrace)
+		}(s)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// counterScript answers each request with a running count and its PID, so
+// tests can tell whether requests reached the same process. It advertises
+// persistent mode when persistent is true.
+const counterScript = `#!/bin/sh
+count=0
+while IFS= read -r line || [ -n "$line" ]; do
+  case "$line" in
+    *'"health"'*) echo '{"success":true,"result":{"status":"healthy","name":"counter","version":"1.0.0","persistent":%t}}' ;;
+    *'"shutdown"'*) echo shutdown >> "%s"; exit 0 ;;
+    *'"hang"'*) sleep 10 ;;
+    *) count=$((count+1)); echo "{\"success\":true,\"result\":{\"count\":$count,\"pid\":$$}}" ;;
+  esac
+done
+`
+
+func writeCounterPlugin(t *testing.T, persistent bool) (*Manager, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts require a POSIX shell")
+	}
+
+	pluginsDir := t.TempDir()
+	pluginDir := filepath.Join(pluginsDir, "counter")
+	if err := os.MkdirAll(pluginDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(t.TempDir(), "shutdown.log")
+
+	manifest := "name: counter\nversion: 1.0.0\ntype: notifier\nentrypoint: counter.sh\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf(counterScript, persistent, logPath)
+	if err := os.WriteFile(filepath.Join(pluginDir, "counter.sh"), []byte(script), 0700); err != nil { //nolint:gosec // Test plugin must be executable
+		t.Fatal(err)
+	}
+
+	manager := NewManager(ManagerConfig{Timeout: 2 * time.Second, PluginDirs: []string{pluginsDir}})
+	if err := manager.Discover(); err != nil {
+		t.Fatal(err)
+	}
+	return manager, logPath
+}
+
+func executeCount(t *testing.T, manager *Manager) (count, pid float64) {
+	t.Helper()
+	resp, err := manager.Execute(context.Background(), "counter", PluginRequest{Action: "count"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result, ok := resp.Result.(map[string]interface{})
+	if !resp.Success || !ok {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	return result["count"].(float64), result["pid"].(float64)
+}
+
+func TestManager_PersistentPlugin(t *testing.T) {
+	manager, logPath := writeCounterPlugin(t, true)
+
+	var firstPID float64
+	for want := 1.0; want <= 3; want++ {
+		count, pid := executeCount(t, manager)
+		if count != want {
+			t.Errorf("count = %v, want %v", count, want)
+		}
+		if firstPID == 0 {
+			firstPID = pid
+		} else if pid != firstPID {
+			t.Errorf("request ran in process %v, want %v", pid, firstPID)
+		}
+	}
+
+	if p, _ := manager.Get("counter"); !p.Persistent {
+		t.Error("plugin should be marked persistent")
+	}
+
+	// Health checks go to the running process
+	health, err := manager.Health(context.Background(), "counter")
+	if err != nil || !health.Persistent {
+		t.Errorf("Health = %+v, %v", health, err)
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil || !strings.Contains(string(data), "shutdown") {
+		t.Errorf("plugin did not receive shutdown: %q, %v", data, err)
+	}
+
+	// The process is started again on demand
+	if count, pid := executeCount(t, manager); count != 1 || pid == firstPID {
+		t.Errorf("after Close: count = %v, pid = %v", count, pid)
+	}
+	manager.Close() //nolint:errcheck // Test cleanup
+}
+
+func TestManager_PersistentPluginTimeout(t *testing.T) {
+	manager, _ := writeCounterPlugin(t, true)
+	manager.config.Timeout = 200 * time.Millisecond
+	defer manager.Close() //nolint:errcheck // Test cleanup
+
+	executeCount(t, manager)
+	_, err := manager.Execute(context.Background(), "counter", PluginRequest{Action: "hang"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	// The hung process was replaced, so counting restarts
+	if count, _ := executeCount(t, manager); count != 1 {
+		t.Errorf("count after timeout = %v, want 1", count)
+	}
+}
+
+func TestManager_OneShotPlugin(t *testing.T) {
+	manager, logPath := writeCounterPlugin(t, false)
+	defer manager.Close() //nolint:errcheck // Test cleanup
+
+	for i := 0; i < 2; i++ {
+		if count, _ := executeCount(t, manager); count != 1 {
+			t.Errorf("one-shot plugin count = %v, want 1", count)
+		}
+	}
+	if p, _ := manager.Get("counter"); p.Persistent {
+		t.Error("plugin should not be marked persistent")
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Error("one-shot plugin should not receive shutdown")
+	}
+}
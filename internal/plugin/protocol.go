@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ProtocolVersion is the plugin protocol version this host speaks. Plugins
+// report the version they implement in the health response; plugins that
+// report none predate the handshake and are run without action checks.
+const ProtocolVersion = 1
+
+// requiredActions are the actions the host invokes for each plugin type
+var requiredActions = map[PluginType][]string{
+	PluginTypeProvider:  {"generate", "list_models"},
+	PluginTypeValidator: {"validate"},
+	PluginTypeFormatter: {"format"},
+	PluginTypeHook:      {"hook"},
+	PluginTypeNotifier:  {"notify"},
+}
+
+// RequiredActions returns the actions a plugin of the given type must
+// support
+func RequiredActions(pluginType PluginType) []string {
+	return requiredActions[pluginType]
+}
+
+// Negotiate runs the health handshake and checks that the plugin speaks a
+// supported protocol version and supports every action the host invokes
+// for its type. Call it before registering a plugin so a mismatch is
+// reported up front instead of mid-run.
+func (m *Manager) Negotiate(ctx context.Context, name string) (*HealthResponse, error) {
+	plugin, ok := m.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found: %s", name)
+	}
+
+	health, err := m.handshake(ctx, plugin)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s handshake failed: %w", name, err)
+	}
+	if err := checkCompatibility(plugin, health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// checkCompatibility validates a health response against the plugin type
+func checkCompatibility(plugin *Plugin, health *HealthResponse) error {
+	name := plugin.Manifest.Name
+	if health.ProtocolVersion > ProtocolVersion {
+		return fmt.Errorf("plugin %s uses protocol version %d, but this version of specular supports up to %d",
+			name, health.ProtocolVersion, ProtocolVersion)
+	}
+	if health.ProtocolVersion == 0 {
+		return nil // Legacy plugin without capability negotiation
+	}
+
+	for _, action := range RequiredActions(plugin.Manifest.Type) {
+		if !health.Supports(action) {
+			return unsupportedActionError(name, action, health)
+		}
+	}
+	return nil
+}
+
+// Supports reports whether the plugin advertised the action. The health and
+// shutdown actions are always supported.
+func (h *HealthResponse) Supports(action string) bool {
+	if action == "health" || action == ShutdownAction {
+		return true
+	}
+	for _, supported := range h.SupportedActions {
+		if supported == action {
+			return true
+		}
+	}
+	return false
+}
+
+func unsupportedActionError(name, action string, health *HealthResponse) error {
+	supported := "none"
+	if len(health.SupportedActions) > 0 {
+		supported = strings.Join(health.SupportedActions, ", ")
+	}
+	return fmt.Errorf("plugin %s does not support action %s (supported: %s)", name, action, supported)
+}
+
+// handshake returns the plugin's health response, running the health check
+// once per plugin
+func (m *Manager) handshake(ctx context.Context, plugin *Plugin) (*HealthResponse, error) {
+	name := plugin.Manifest.Name
+
+	m.sessionsMu.Lock()
+	result, checked := m.handshakes[name]
+	m.sessionsMu.Unlock()
+	if checked {
+		return result.health, result.err
+	}
+
+	health, err := m.healthOnce(ctx, plugin)
+
+	m.sessionsMu.Lock()
+	m.handshakes[name] = handshakeResult{health: health, err: err}
+	m.sessionsMu.Unlock()
+
+	if err == nil {
+		m.mu.Lock()
+		plugin.Persistent = health.Persistent
+		plugin.ProtocolVersion = health.ProtocolVersion
+		plugin.SupportedActions = health.SupportedActions
+		m.mu.Unlock()
+	}
+	return health, err
+}
+
+// handshakeResult caches a plugin's health check outcome
+type handshakeResult struct {
+	health *HealthResponse
+	err    error
+}
+
+// checkAction rejects a request for an action the plugin did not advertise.
+// Plugins without a handshake are not checked.
+func checkAction(plugin *Plugin, health *HealthResponse, request interface{}) error {
+	if health == nil || health.ProtocolVersion == 0 {
+		return nil
+	}
+
+	action, err := requestAction(request)
+	if err != nil {
+		return err
+	}
+	if !health.Supports(action) {
+		return unsupportedActionError(plugin.Manifest.Name, action, health)
+	}
+	return nil
+}
+
+// requestAction returns the action field of a request
+func requestAction(request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("serialize request: %w", err)
+	}
+	var envelope struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return "", fmt.Errorf("request has no action: %w", err)
+	}
+	return envelope.Action, nil
+}
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// handshakeScript answers health with the given handshake fields and logs
+// every other action it receives
+const handshakeScript = `#!/bin/sh
+read -r line
+case "$line" in
+  *'"health"'*) echo '{"success":true,"result":{"status":"healthy","name":"probe","version":"1.0.0"%s}}' ;;
+  *) echo "$line" >> "%s"; echo '{"success":true}' ;;
+esac
+`
+
+func writeHandshakePlugin(t *testing.T, pluginType PluginType, handshake string) (*Manager, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts require a POSIX shell")
+	}
+
+	pluginsDir := t.TempDir()
+	pluginDir := filepath.Join(pluginsDir, "probe")
+	if err := os.MkdirAll(pluginDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(t.TempDir(), "requests.log")
+
+	manifest := fmt.Sprintf("name: probe\nversion: 1.0.0\ntype: %s\nentrypoint: probe.sh\n", pluginType)
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf(handshakeScript, handshake, logPath)
+	if err := os.WriteFile(filepath.Join(pluginDir, "probe.sh"), []byte(script), 0700); err != nil { //nolint:gosec // Test plugin must be executable
+		t.Fatal(err)
+	}
+
+	manager := NewManager(ManagerConfig{Timeout: 2 * time.Second, PluginDirs: []string{pluginsDir}})
+	if err := manager.Discover(); err != nil {
+		t.Fatal(err)
+	}
+	return manager, logPath
+}
+
+func TestManager_Negotiate(t *testing.T) {
+	tests := []struct {
+		name       string
+		pluginType PluginType
+		handshake  string
+		wantErr    string
+	}{
+		{
+			name:       "supported",
+			pluginType: PluginTypeNotifier,
+			handshake:  `,"protocol_version":1,"supported_actions":["notify"]`,
+		},
+		{
+			name:       "missing required action",
+			pluginType: PluginTypeProvider,
+			handshake:  `,"protocol_version":1,"supported_actions":["generate"]`,
+			wantErr:    "plugin probe does not support action list_models (supported: generate)",
+		},
+		{
+			name:       "newer protocol",
+			pluginType: PluginTypeNotifier,
+			handshake:  `,"protocol_version":2,"supported_actions":["notify"]`,
+			wantErr:    "uses protocol version 2",
+		},
+		{
+			name:       "legacy plugin",
+			pluginType: PluginTypeValidator,
+			handshake:  ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, _ := writeHandshakePlugin(t, tt.pluginType, tt.handshake)
+
+			_, err := manager.Negotiate(context.Background(), "probe")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Negotiate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Negotiate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_ExecuteUnsupportedAction(t *testing.T) {
+	manager, logPath := writeHandshakePlugin(t, PluginTypeNotifier, `,"protocol_version":1,"supported_actions":["notify"]`)
+	ctx := context.Background()
+
+	if _, err := manager.Execute(ctx, "probe", PluginRequest{Action: "notify"}); err != nil {
+		t.Fatalf("Execute(notify): %v", err)
+	}
+
+	_, err := manager.Execute(ctx, "probe", PluginRequest{Action: "format"})
+	if err == nil || !strings.Contains(err.Error(), "does not support action format") {
+		t.Errorf("Execute(format) error = %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "format") {
+		t.Error("unsupported action was sent to the plugin")
+	}
+
+	if p, _ := manager.Get("probe"); p.ProtocolVersion != 1 || len(p.SupportedActions) != 1 {
+		t.Errorf("plugin handshake not recorded: %+v", p)
+	}
+}
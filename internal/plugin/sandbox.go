@@ -0,0 +1,216 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNetworkUnenforced is returned when a plugin must run without network
+// access but the platform cannot isolate it
+var errNetworkUnenforced = errors.New("network isolation is unavailable on this platform (install unshare, or set allow_unenforced_network to run plugins with network access)")
+
+// DefaultEnvAllowlist is the environment passed to sandboxed plugins when no
+// allowlist is configured
+var DefaultEnvAllowlist = []string{"PATH", "HOME", "TMPDIR", "LANG", "LC_*", "TZ"}
+
+// SandboxConfig limits the resources, environment, and network access of
+// plugin processes. The limits use the same formats as the execution
+// policy's Docker limits.
+type SandboxConfig struct {
+	// Enabled turns the sandbox on. Without it plugins inherit the host
+	// environment and are only limited by the timeout.
+	Enabled bool
+
+	// CPULimit is the number of CPUs a plugin may use, such as "2". It is
+	// enforced as a CPU time limit of CPULimit times the plugin timeout.
+	CPULimit string
+
+	// MemLimit caps plugin memory, such as "512m" or "2g"
+	MemLimit string
+
+	// DenyNetwork runs plugins without network access. Plugins fail to
+	// start where the platform cannot isolate them from the network,
+	// unless AllowUnenforcedNetwork is set.
+	DenyNetwork bool
+
+	// AllowUnenforcedNetwork runs DenyNetwork plugins with network access
+	// when the platform cannot isolate them, instead of refusing to start
+	AllowUnenforcedNetwork bool
+
+	// EnvAllowlist names the environment variables passed to plugins. A
+	// trailing * matches a prefix. Empty means DefaultEnvAllowlist.
+	EnvAllowlist []string
+}
+
+// Validate checks that the limits can be parsed
+func (c SandboxConfig) Validate() error {
+	if c.CPULimit != "" {
+		if _, err := parseCPULimit(c.CPULimit); err != nil {
+			return err
+		}
+	}
+	if c.MemLimit != "" {
+		if _, err := parseMemLimit(c.MemLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unenforced lists the configured limits this platform cannot enforce. The
+// timeout and environment allowlist are always enforced. Network access is
+// only listed when AllowUnenforcedNetwork is set; otherwise plugins fail to
+// start.
+func (c SandboxConfig) Unenforced() []string {
+	if !c.Enabled {
+		return nil
+	}
+	var unenforced []string
+	if c.CPULimit != "" && !supportsCPULimit() {
+		unenforced = append(unenforced, "cpu_limit")
+	}
+	if c.MemLimit != "" && !supportsMemLimit() {
+		unenforced = append(unenforced, "mem_limit")
+	}
+	if c.DenyNetwork && c.AllowUnenforcedNetwork && networkIsolation() == nil {
+		unenforced = append(unenforced, "network")
+	}
+	return unenforced
+}
+
+// cpuSeconds returns the CPU time limit for a plugin timeout, or 0 for none
+func (c SandboxConfig) cpuSeconds(timeout time.Duration) int {
+	cpus, err := parseCPULimit(c.CPULimit)
+	if err != nil || cpus == 0 {
+		return 0
+	}
+	return int(math.Max(1, math.Ceil(cpus*timeout.Seconds())))
+}
+
+// environ returns the allowlisted variables of the host environment
+func (c SandboxConfig) environ() []string {
+	allowlist := c.EnvAllowlist
+	if len(allowlist) == 0 {
+		allowlist = DefaultEnvAllowlist
+	}
+
+	env := []string{}
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, pattern := range allowlist {
+			if name == pattern || (strings.HasSuffix(pattern, "*") && strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))) {
+				env = append(env, entry)
+				break
+			}
+		}
+	}
+	return env
+}
+
+// command builds the command that runs a plugin, inside the sandbox when it
+// is enabled
+func (m *Manager) command(ctx context.Context, plugin *Plugin) (*exec.Cmd, error) {
+	path := entrypoint(plugin)
+	sandbox := m.config.Sandbox
+	if !sandbox.Enabled {
+		return exec.CommandContext(ctx, path), nil
+	}
+	if err := sandbox.Validate(); err != nil {
+		return nil, fmt.Errorf("plugin sandbox: %w", err)
+	}
+	if sandbox.DenyNetwork && !sandbox.AllowUnenforcedNetwork && networkIsolation() == nil {
+		return nil, fmt.Errorf("plugin sandbox: %w", errNetworkUnenforced)
+	}
+
+	args := sandboxArgs(sandbox, path, m.config.Timeout)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //#nosec G204 -- Plugin entrypoint wrapped by the sandbox
+	cmd.Env = sandbox.environ()
+	return cmd, nil
+}
+
+// LimitError reports a plugin that was killed for exceeding a sandbox limit
+type LimitError struct {
+	Plugin string
+	Limit  string // cpu_limit or mem_limit
+	Value  string
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case "cpu_limit":
+		return fmt.Sprintf("plugin %s was killed for exceeding its CPU limit (%s)", e.Plugin, e.Value)
+	case "mem_limit":
+		return fmt.Sprintf("plugin %s was killed for exceeding its memory limit (%s)", e.Plugin, e.Value)
+	}
+	return fmt.Sprintf("plugin %s was killed for exceeding its %s (%s)", e.Plugin, e.Limit, e.Value)
+}
+
+// outOfMemoryMessages are what common runtimes print when an allocation
+// fails under a memory limit
+var outOfMemoryMessages = []string{"out of memory", "cannot allocate memory", "bad_alloc", "memoryerror"}
+
+// limitError returns a LimitError when an exited plugin hit a sandbox limit
+func (m *Manager) limitError(plugin *Plugin, state *os.ProcessState, stderr string) error {
+	sandbox := m.config.Sandbox
+	if !sandbox.Enabled || state == nil {
+		return nil
+	}
+
+	name := plugin.Manifest.Name
+	if seconds := sandbox.cpuSeconds(m.config.Timeout); seconds > 0 && exceededCPULimit(state, seconds) {
+		value := fmt.Sprintf("%s CPUs, %ds of CPU time", sandbox.CPULimit, seconds)
+		return &LimitError{Plugin: name, Limit: "cpu_limit", Value: value}
+	}
+	if sandbox.MemLimit != "" && !state.Success() {
+		lower := strings.ToLower(stderr)
+		for _, message := range outOfMemoryMessages {
+			if strings.Contains(lower, message) {
+				return &LimitError{Plugin: name, Limit: "mem_limit", Value: sandbox.MemLimit}
+			}
+		}
+	}
+	return nil
+}
+
+// parseCPULimit parses a CPU count such as "2" or "0.5"
+func parseCPULimit(limit string) (float64, error) {
+	cpus, err := strconv.ParseFloat(limit, 64)
+	if err != nil || cpus < 0 {
+		return 0, fmt.Errorf("invalid cpu_limit %q: want a number of CPUs", limit)
+	}
+	return cpus, nil
+}
+
+// parseMemLimit parses a memory size such as "512m" or "2g" into bytes
+func parseMemLimit(limit string) (int64, error) {
+	value := strings.ToLower(strings.TrimSpace(limit))
+	value = strings.TrimSuffix(value, "b")
+
+	multiplier := int64(1)
+	if value != "" {
+		switch value[len(value)-1] {
+		case 'k':
+			multiplier = 1 << 10
+		case 'm':
+			multiplier = 1 << 20
+		case 'g':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			value = value[:len(value)-1]
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid mem_limit %q: want a size such as 512m or 2g", limit)
+	}
+	return size * multiplier, nil
+}
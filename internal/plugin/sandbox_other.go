@@ -0,0 +1,23 @@
+//go:build !unix
+
+package plugin
+
+import (
+	"os"
+	"time"
+)
+
+// sandboxArgs runs the plugin directly. Resource limits and network
+// isolation are not supported on this platform; the timeout and
+// environment allowlist still apply.
+func sandboxArgs(sandbox SandboxConfig, path string, timeout time.Duration) []string {
+	return []string{path}
+}
+
+func networkIsolation() []string { return nil }
+
+func supportsCPULimit() bool { return false }
+
+func supportsMemLimit() bool { return false }
+
+func exceededCPULimit(state *os.ProcessState, seconds int) bool { return false }
@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScriptPlugin installs a one-shot notifier plugin running script
+func writeScriptPlugin(t *testing.T, script string, sandbox SandboxConfig) *Manager {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts require a POSIX shell")
+	}
+
+	pluginsDir := t.TempDir()
+	pluginDir := filepath.Join(pluginsDir, "probe")
+	if err := os.MkdirAll(pluginDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "name: probe\nversion: 1.0.0\ntype: notifier\nentrypoint: probe.sh\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "probe.sh"), []byte("#!/bin/sh\n"+script), 0700); err != nil { //nolint:gosec // Test plugin must be executable
+		t.Fatal(err)
+	}
+
+	manager := NewManager(ManagerConfig{Timeout: 5 * time.Second, PluginDirs: []string{pluginsDir}, Sandbox: sandbox})
+	if err := manager.Discover(); err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func executeProbe(manager *Manager) (map[string]interface{}, error) {
+	plugin, _ := manager.Get("probe")
+	resp, err := manager.executeOnce(context.Background(), plugin, PluginRequest{Action: "notify"})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := resp.Result.(map[string]interface{})
+	return result, nil
+}
+
+func TestParseMemLimit(t *testing.T) {
+	tests := []struct {
+		limit   string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"512k", 512 << 10, false},
+		{"512m", 512 << 20, false},
+		{"2g", 2 << 30, false},
+		{"2GB", 2 << 30, false},
+		{"lots", 0, true},
+		{"-1m", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseMemLimit(tt.limit)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseMemLimit(%q) = %d, %v; want %d, error %v", tt.limit, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSandbox_EnvAllowlist(t *testing.T) {
+	t.Setenv("PROBE_SECRET", "hunter2")
+	t.Setenv("PROBE_ALLOWED", "yes")
+	script := `echo "{\"success\":true,\"result\":{\"secret\":\"$PROBE_SECRET\",\"allowed\":\"$PROBE_ALLOWED\",\"path\":\"$PATH\"}}"` + "\n"
+
+	t.Run("sandboxed", func(t *testing.T) {
+		manager := writeScriptPlugin(t, script, SandboxConfig{
+			Enabled:      true,
+			CPULimit:     "1",
+			MemLimit:     "256m",
+			EnvAllowlist: []string{"PATH", "PROBE_ALLOW*"},
+		})
+		result, err := executeProbe(manager)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		if result["secret"] != "" || result["allowed"] != "yes" || result["path"] == "" {
+			t.Errorf("plugin environment = %v", result)
+		}
+	})
+
+	t.Run("not sandboxed", func(t *testing.T) {
+		manager := writeScriptPlugin(t, script, SandboxConfig{})
+		result, err := executeProbe(manager)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		if result["secret"] != "hunter2" {
+			t.Errorf("plugin environment = %v", result)
+		}
+	})
+}
+
+func TestSandbox_CPULimit(t *testing.T) {
+	manager := writeScriptPlugin(t, "while :; do :; done\n", SandboxConfig{Enabled: true, CPULimit: "0.1"})
+
+	start := time.Now()
+	_, err := executeProbe(manager)
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "cpu_limit" {
+		t.Fatalf("expected CPU limit error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "exceeding its CPU limit (0.1 CPUs, 1s of CPU time)") {
+		t.Errorf("error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("plugin ran until the timeout (%v) instead of hitting the CPU limit", elapsed)
+	}
+}
+
+func TestSandbox_MemLimit(t *testing.T) {
+	script := "echo 'fatal error: runtime: out of memory' >&2\nexit 2\n"
+	manager := writeScriptPlugin(t, script, SandboxConfig{Enabled: true, MemLimit: "64m"})
+
+	_, err := executeProbe(manager)
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "mem_limit" {
+		t.Fatalf("expected memory limit error, got %v", err)
+	}
+	if want := "plugin probe was killed for exceeding its memory limit (64m)"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestSandbox_DenyNetwork(t *testing.T) {
+	if runtime.GOOS != "linux" || networkIsolation() == nil {
+		t.Skip("network isolation requires unshare on Linux")
+	}
+
+	// /proc/net/dev lists the interfaces of the process's network namespace
+	script := `ifaces=$(tail -n +3 /proc/net/dev | cut -d: -f1 | tr -d ' ' | tr '\n' ' ')` + "\n" +
+		`echo "{\"success\":true,\"result\":{\"interfaces\":\"$ifaces\"}}"` + "\n"
+	manager := writeScriptPlugin(t, script, SandboxConfig{Enabled: true, DenyNetwork: true})
+
+	result, err := executeProbe(manager)
+	if err != nil {
+		if strings.Contains(err.Error(), "unshare") {
+			t.Skipf("unprivileged network namespaces unavailable: %v", err)
+		}
+		t.Fatalf("execute: %v", err)
+	}
+	if result["interfaces"] != "lo " {
+		t.Errorf("plugin sees interfaces %q, want only loopback", result["interfaces"])
+	}
+}
+
+func TestSandbox_InvalidLimit(t *testing.T) {
+	manager := writeScriptPlugin(t, "exit 0\n", SandboxConfig{Enabled: true, MemLimit: "lots"})
+	if _, err := executeProbe(manager); err == nil || !strings.Contains(err.Error(), "invalid mem_limit") {
+		t.Errorf("expected invalid limit error, got %v", err)
+	}
+}
+
+func TestSandbox_DenyNetworkFailsClosed(t *testing.T) {
+	// Without unshare or sandbox-exec on PATH, the network cannot be isolated
+	t.Setenv("PATH", t.TempDir())
+	script := `echo '{"success":true,"result":{}}'` + "\n"
+
+	manager := writeScriptPlugin(t, script, SandboxConfig{Enabled: true, DenyNetwork: true})
+	if _, err := executeProbe(manager); !errors.Is(err, errNetworkUnenforced) {
+		t.Errorf("expected the plugin to be refused, got %v", err)
+	}
+
+	sandbox := SandboxConfig{Enabled: true, DenyNetwork: true, AllowUnenforcedNetwork: true}
+	if _, err := executeProbe(writeScriptPlugin(t, script, sandbox)); err != nil {
+		t.Errorf("execute with unenforced network allowed: %v", err)
+	}
+	if unenforced := sandbox.Unenforced(); len(unenforced) != 1 || unenforced[0] != "network" {
+		t.Errorf("Unenforced() = %v, want [network]", unenforced)
+	}
+}
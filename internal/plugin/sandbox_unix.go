@@ -0,0 +1,77 @@
+//go:build unix
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// sandboxArgs wraps the plugin entrypoint in the commands that apply the
+// sandbox limits. Resource limits are set with ulimit in a shell that then
+// execs the plugin, so a limit that cannot be set fails the plugin start
+// instead of being skipped.
+func sandboxArgs(sandbox SandboxConfig, path string, timeout time.Duration) []string {
+	args := []string{path}
+
+	var limits []string
+	if seconds := sandbox.cpuSeconds(timeout); seconds > 0 {
+		// The soft limit sends SIGXCPU; the hard limit a second later kills
+		// plugins that ignore it
+		limits = append(limits, fmt.Sprintf("ulimit -S -t %d && ulimit -H -t %d", seconds, seconds+1))
+	}
+	if sandbox.MemLimit != "" && supportsMemLimit() {
+		if size, err := parseMemLimit(sandbox.MemLimit); err == nil && size > 0 {
+			limits = append(limits, fmt.Sprintf("ulimit -v %d", (size+1023)/1024))
+		}
+	}
+	if len(limits) > 0 {
+		script := strings.Join(limits, " && ") + ` && exec "$0"`
+		args = append([]string{"/bin/sh", "-c", script}, args...)
+	}
+
+	if sandbox.DenyNetwork {
+		args = append(networkIsolation(), args...)
+	}
+	return args
+}
+
+// networkIsolation returns the command prefix that runs a process without
+// network access, or nil when the platform has no way to do so
+func networkIsolation() []string {
+	switch runtime.GOOS {
+	case "linux":
+		// A new network namespace only has a loopback interface, which is down
+		if unshare, err := exec.LookPath("unshare"); err == nil {
+			return []string{unshare, "--net", "--map-root-user"}
+		}
+	case "darwin":
+		if sandboxExec, err := exec.LookPath("sandbox-exec"); err == nil {
+			return []string{sandboxExec, "-p", "(version 1)(allow default)(deny network*)"}
+		}
+	}
+	return nil
+}
+
+func supportsCPULimit() bool { return true }
+
+// supportsMemLimit reports whether the address space limit is enforced;
+// macOS accepts but ignores it
+func supportsMemLimit() bool { return runtime.GOOS != "darwin" }
+
+// exceededCPULimit reports whether the process was killed for using up its
+// CPU time
+func exceededCPULimit(state *os.ProcessState, seconds int) bool {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return false
+	}
+	used := state.UserTime() + state.SystemTime()
+	return status.Signal() == syscall.SIGXCPU ||
+		(status.Signal() == syscall.SIGKILL && used >= time.Duration(seconds)*time.Second)
+}
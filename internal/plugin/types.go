@@ -95,6 +95,12 @@ type Plugin struct {
 	LoadedAt time.Time `json:"loaded_at"`
 	// Config holds the runtime configuration
 	Config map[string]interface{} `json:"config,omitempty"`
+	// Persistent is true once the plugin advertised persistent mode in its
+	// health response
+	Persistent bool `json:"persistent,omitempty"`
+	// ProtocolVersion and SupportedActions are set from the health handshake
+	ProtocolVersion  int      `json:"protocol_version,omitempty"`
+	SupportedActions []string `json:"supported_actions,omitempty"`
 }
 
 // PluginRequest is sent to a plugin for execution
@@ -127,6 +133,14 @@ type HealthResponse struct {
 	Status  string `json:"status"`
 	Version string `json:"version"`
 	Name    string `json:"name"`
+	// Persistent plugins are launched once and handle newline-delimited
+	// JSON requests over stdin/stdout until the shutdown action
+	Persistent bool `json:"persistent,omitempty"`
+	// ProtocolVersion is the plugin protocol version the plugin implements
+	// (0 = predates capability negotiation)
+	ProtocolVersion int `json:"protocol_version,omitempty"`
+	// SupportedActions lists the actions the plugin handles
+	SupportedActions []string `json:"supported_actions,omitempty"`
 }
 
 // ValidatorRequest is sent to validator plugins
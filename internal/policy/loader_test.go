@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPolicy(t *testing.T) {
@@ -118,6 +119,35 @@ routing:
 				}
 			},
 		},
+		{
+			name: "plugin sandbox policy",
+			policyContent: `
+execution:
+  plugins:
+    sandbox: true
+    cpu_limit: "1"
+    mem_limit: "512m"
+    network: "none"
+    timeout: 45s
+    env_allowlist: ["PATH", "SLACK_*"]
+`,
+			wantErr: false,
+			validatePolicy: func(t *testing.T, p *Policy) {
+				plugins := p.Execution.Plugins
+				if !plugins.Sandbox {
+					t.Error("Plugins.Sandbox should be true")
+				}
+				if plugins.CPULimit != "1" || plugins.MemLimit != "512m" || plugins.Network != "none" {
+					t.Errorf("Plugins limits = %+v", plugins)
+				}
+				if plugins.Timeout != 45*time.Second {
+					t.Errorf("Plugins.Timeout = %v, want 45s", plugins.Timeout)
+				}
+				if len(plugins.EnvAllowlist) != 2 {
+					t.Errorf("EnvAllowlist length = %d, want 2", len(plugins.EnvAllowlist))
+				}
+			},
+		},
 		{
 			name: "minimal policy",
 			policyContent: `
@@ -29,13 +29,14 @@ func (p *Parser) ParsePRD(ctx context.Context, prdContent string) (*spec.Product
 	userPrompt := buildUserPrompt(prdContent)
 
 	// Build the generation request
+	temperature := 0.0 // Deterministic, so the response can be cached
 	req := router.GenerateRequest{
 		Prompt:       userPrompt,
 		SystemPrompt: systemPrompt,
-		ModelHint:    "agentic",           // Use agentic model for complex reasoning
-		Complexity:   8,                   // High complexity task
-		Priority:     "P0",                // Critical task
-		Temperature:  0.1,                 // Low temperature for consistent structured output
+		ModelHint:    "agentic", // Use agentic model for complex reasoning
+		Complexity:   8,         // High complexity task
+		Priority:     "P0",      // Critical task
+		Temperature:  &temperature,
 		MaxTokens:    4000,                // Allow space for full spec
 		ContextSize:  len(prdContent) / 4, // Rough estimate of context tokens
 	}
@@ -0,0 +1,172 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables that override profile settings. They take
+// precedence over the profile and are overridden by CLI flags.
+const (
+	// EnvMaxCost overrides safety.max_cost_usd
+	EnvMaxCost = "SPECULAR_MAX_COST"
+
+	// EnvMaxSteps overrides safety.max_steps
+	EnvMaxSteps = "SPECULAR_MAX_STEPS"
+
+	// EnvTimeout overrides safety.timeout, as a duration such as "30m" or
+	// a number of minutes
+	EnvTimeout = "SPECULAR_TIMEOUT"
+
+	// EnvApprovalMode overrides approvals.mode
+	EnvApprovalMode = "SPECULAR_APPROVAL_MODE"
+)
+
+// EnvOverrides represents profile overrides from environment variables.
+// A nil field means the variable is not set.
+type EnvOverrides struct {
+	MaxCostUSD   *float64
+	MaxSteps     *int
+	Timeout      *time.Duration
+	ApprovalMode *ApprovalMode
+}
+
+// LoadEnvOverrides reads profile overrides from the environment. Empty
+// variables are ignored.
+func LoadEnvOverrides() (*EnvOverrides, error) {
+	overrides := &EnvOverrides{}
+
+	if value := os.Getenv(EnvMaxCost); value != "" {
+		cost, err := strconv.ParseFloat(value, 64)
+		if err != nil || cost <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive number of USD", EnvMaxCost, value)
+		}
+		overrides.MaxCostUSD = &cost
+	}
+
+	if value := os.Getenv(EnvMaxSteps); value != "" {
+		steps, err := strconv.Atoi(value)
+		if err != nil || steps <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive integer", EnvMaxSteps, value)
+		}
+		overrides.MaxSteps = &steps
+	}
+
+	if value := os.Getenv(EnvTimeout); value != "" {
+		timeout, err := parseEnvTimeout(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: must be a duration such as 30m or a number of minutes", EnvTimeout, value)
+		}
+		overrides.Timeout = &timeout
+	}
+
+	if value := os.Getenv(EnvApprovalMode); value != "" {
+		mode := ApprovalMode(strings.ToLower(value))
+		if err := (&ApprovalConfig{Mode: mode}).Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvApprovalMode, err)
+		}
+		overrides.ApprovalMode = &mode
+	}
+
+	return overrides, nil
+}
+
+// parseEnvTimeout parses a duration, treating a bare number as minutes to
+// match the --timeout flag
+func parseEnvTimeout(value string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(value); err == nil {
+		if minutes <= 0 {
+			return 0, fmt.Errorf("timeout must be positive")
+		}
+		return time.Duration(minutes) * time.Minute, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return timeout, nil
+}
+
+// MergeWithEnv merges a profile with environment variable overrides.
+// Apply MergeWithCLIFlags to the result so CLI flags take precedence.
+func MergeWithEnv(profile *Profile, env *EnvOverrides) *Profile {
+	merged := *profile // Copy profile
+
+	if env.MaxCostUSD != nil {
+		merged.Safety.MaxCostUSD = *env.MaxCostUSD
+	}
+	if env.MaxSteps != nil {
+		merged.Safety.MaxSteps = *env.MaxSteps
+	}
+	if env.Timeout != nil {
+		merged.Safety.Timeout = *env.Timeout
+	}
+	if env.ApprovalMode != nil {
+		merged.Approvals.Mode = *env.ApprovalMode
+	}
+
+	return &merged
+}
+
+// SettingSource records which layer set an effective profile setting.
+type SettingSource struct {
+	// Setting is the profile field, such as "safety.max_cost_usd"
+	Setting string
+
+	// Value is the effective value
+	Value string
+
+	// Source is the profile, the defaults file, the environment variable,
+	// or the CLI flag that set the value
+	Source string
+}
+
+// SettingSources returns the effective values of the settings the
+// environment can override and where each came from. effective is the
+// profile after MergeWithEnv and MergeWithCLIFlags. defaults holds the
+// flag values from an auto.defaults.yaml file, which apply below the
+// environment.
+func SettingSources(effective *Profile, env *EnvOverrides, flags, defaults *CLIFlags) []SettingSource {
+	source := func(flagSet bool, flag string, envSet bool, envVar string, defaultSet bool) string {
+		switch {
+		case flagSet:
+			return "flag " + flag
+		case envSet:
+			return "env " + envVar
+		case defaultSet:
+			return "auto.defaults.yaml " + flag
+		default:
+			return fmt.Sprintf("profile %s", effective.Name)
+		}
+	}
+
+	return []SettingSource{
+		{
+			Setting: "approvals.mode",
+			Value:   string(effective.Approvals.Mode),
+			Source:  source(flags.RequireApproval != nil, "--no-approval", env.ApprovalMode != nil, EnvApprovalMode, defaults.RequireApproval != nil),
+		},
+		{
+			Setting: "safety.max_steps",
+			Value:   strconv.Itoa(effective.Safety.MaxSteps),
+			Source:  source(flags.MaxSteps != nil, "--max-steps", env.MaxSteps != nil, EnvMaxSteps, defaults.MaxSteps != nil),
+		},
+		{
+			Setting: "safety.timeout",
+			Value:   effective.Safety.Timeout.String(),
+			Source:  source(flags.Timeout != nil, "--timeout", env.Timeout != nil, EnvTimeout, defaults.Timeout != nil),
+		},
+		{
+			Setting: "safety.max_cost_usd",
+			Value:   fmt.Sprintf("$%.2f", effective.Safety.MaxCostUSD),
+			Source:  source(flags.MaxCostUSD != nil, "--max-cost", env.MaxCostUSD != nil, EnvMaxCost, defaults.MaxCostUSD != nil),
+		},
+	}
+}
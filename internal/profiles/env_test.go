@@ -0,0 +1,126 @@
+package profiles
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadEnvOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(t *testing.T, env *EnvOverrides)
+		wantErr string
+	}{
+		{
+			name: "nothing set",
+			check: func(t *testing.T, env *EnvOverrides) {
+				if env.MaxCostUSD != nil || env.MaxSteps != nil || env.Timeout != nil || env.ApprovalMode != nil {
+					t.Errorf("expected no overrides, got %+v", env)
+				}
+			},
+		},
+		{
+			name: "all set",
+			env: map[string]string{
+				EnvMaxCost:      "2.5",
+				EnvMaxSteps:     "20",
+				EnvTimeout:      "45m",
+				EnvApprovalMode: "NONE",
+			},
+			check: func(t *testing.T, env *EnvOverrides) {
+				if *env.MaxCostUSD != 2.5 || *env.MaxSteps != 20 || *env.Timeout != 45*time.Minute || *env.ApprovalMode != ApprovalModeNone {
+					t.Errorf("unexpected overrides: %v, %v, %v, %v", *env.MaxCostUSD, *env.MaxSteps, *env.Timeout, *env.ApprovalMode)
+				}
+			},
+		},
+		{
+			name: "timeout in minutes",
+			env:  map[string]string{EnvTimeout: "90"},
+			check: func(t *testing.T, env *EnvOverrides) {
+				if *env.Timeout != 90*time.Minute {
+					t.Errorf("expected timeout 90m, got %s", *env.Timeout)
+				}
+			},
+		},
+		{name: "invalid cost", env: map[string]string{EnvMaxCost: "cheap"}, wantErr: "invalid SPECULAR_MAX_COST"},
+		{name: "negative steps", env: map[string]string{EnvMaxSteps: "-1"}, wantErr: "invalid SPECULAR_MAX_STEPS"},
+		{name: "invalid timeout", env: map[string]string{EnvTimeout: "soon"}, wantErr: "invalid SPECULAR_TIMEOUT"},
+		{name: "invalid approval mode", env: map[string]string{EnvApprovalMode: "sometimes"}, wantErr: "invalid SPECULAR_APPROVAL_MODE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvMaxCost, EnvMaxSteps, EnvTimeout, EnvApprovalMode} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			env, err := LoadEnvOverrides()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadEnvOverrides() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadEnvOverrides() error = %v", err)
+			}
+			tt.check(t, env)
+		})
+	}
+}
+
+func TestMergeWithEnv_Precedence(t *testing.T) {
+	profile, err := NewLoader().Load("default")
+	if err != nil {
+		t.Fatalf("failed to load default profile: %v", err)
+	}
+
+	envCost, envSteps, envTimeout := 3.0, 20, time.Hour
+	envMode := ApprovalModeNone
+	env := &EnvOverrides{MaxCostUSD: &envCost, MaxSteps: &envSteps, Timeout: &envTimeout, ApprovalMode: &envMode}
+
+	flagCost := 7.0
+	flags := &CLIFlags{MaxCostUSD: &flagCost}
+
+	effective := MergeWithCLIFlags(MergeWithEnv(profile, env), flags)
+
+	if effective.Safety.MaxCostUSD != 7.0 {
+		t.Errorf("expected the flag to win for max_cost_usd, got %.2f", effective.Safety.MaxCostUSD)
+	}
+	if effective.Safety.MaxSteps != 20 || effective.Safety.Timeout != time.Hour || effective.Approvals.Mode != ApprovalModeNone {
+		t.Errorf("expected env overrides, got max_steps %d, timeout %s, mode %s",
+			effective.Safety.MaxSteps, effective.Safety.Timeout, effective.Approvals.Mode)
+	}
+	if profile.Safety.MaxSteps == 20 {
+		t.Error("MergeWithEnv modified the original profile")
+	}
+
+	sources := map[string]string{}
+	for _, setting := range SettingSources(effective, env, flags, &CLIFlags{}) {
+		sources[setting.Setting] = setting.Source + " = " + setting.Value
+	}
+	want := map[string]string{
+		"approvals.mode":      "env SPECULAR_APPROVAL_MODE = none",
+		"safety.max_steps":    "env SPECULAR_MAX_STEPS = 20",
+		"safety.timeout":      "env SPECULAR_TIMEOUT = 1h0m0s",
+		"safety.max_cost_usd": "flag --max-cost = $7.00",
+	}
+	for setting, source := range want {
+		if sources[setting] != source {
+			t.Errorf("%s: got %q, want %q", setting, sources[setting], source)
+		}
+	}
+
+	unset := SettingSources(profile, &EnvOverrides{}, &CLIFlags{}, &CLIFlags{})
+	if unset[0].Source != "profile default" {
+		t.Errorf("expected profile source, got %q", unset[0].Source)
+	}
+
+	defaultSteps := 9
+	defaulted := SettingSources(profile, &EnvOverrides{}, &CLIFlags{}, &CLIFlags{MaxSteps: &defaultSteps})
+	if defaulted[1].Source != "auto.defaults.yaml --max-steps" {
+		t.Errorf("expected defaults file source, got %q", defaulted[1].Source)
+	}
+}
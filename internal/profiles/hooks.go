@@ -0,0 +1,64 @@
+package profiles
+
+import (
+	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/hooks"
+)
+
+// HookConfigs converts the profile hooks into hook registry configurations.
+// Each hook receives the events of the list it is in; on_error covers both
+// failed steps and failed workflows. on_approval_requested has no
+// lifecycle event yet and is not converted. Hook settings may be written
+// inline or under a config key, and an optional name identifies the hook in
+// logs and the dead-letter log.
+func (c HooksConfig) HookConfigs() ([]hooks.HookConfig, error) {
+	lists := []struct {
+		key    string
+		hooks  []Hook
+		events []hooks.EventType
+	}{
+		{"on_plan_created", c.OnPlanCreated, []hooks.EventType{hooks.EventPlanCreated}},
+		{"on_step_before", c.OnStepBefore, []hooks.EventType{hooks.EventStepBefore}},
+		{"on_step_after", c.OnStepAfter, []hooks.EventType{hooks.EventStepAfter}},
+		{"on_complete", c.OnComplete, []hooks.EventType{hooks.EventWorkflowComplete}},
+		{"on_error", c.OnError, []hooks.EventType{hooks.EventStepFailed, hooks.EventWorkflowFailed}},
+	}
+
+	var configs []hooks.HookConfig
+	for _, list := range lists {
+		for i, hook := range list.hooks {
+			if hook.Type == "" {
+				return nil, fmt.Errorf("hooks.%s[%d]: type required", list.key, i)
+			}
+
+			settings := make(map[string]interface{}, len(hook.Config))
+			name := fmt.Sprintf("%s-%s-%d", list.key, hook.Type, i+1)
+			for key, value := range hook.Config {
+				switch key {
+				case "name":
+					if s, ok := value.(string); ok && s != "" {
+						name = s
+					}
+				case "config":
+					if nested, ok := value.(map[string]interface{}); ok {
+						for k, v := range nested {
+							settings[k] = v
+						}
+					}
+				default:
+					settings[key] = value
+				}
+			}
+
+			configs = append(configs, hooks.HookConfig{
+				Name:    name,
+				Type:    hook.Type,
+				Events:  list.events,
+				Enabled: true,
+				Config:  settings,
+			})
+		}
+	}
+	return configs, nil
+}
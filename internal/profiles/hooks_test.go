@@ -0,0 +1,67 @@
+package profiles
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/felixgeelhaar/specular/internal/hooks"
+)
+
+func TestHookConfigs(t *testing.T) {
+	var config HooksConfig
+	err := yaml.Unmarshal([]byte(`
+on_complete:
+  - type: webhook
+    name: deploy-notify
+    url: https://example.com/hooks
+    secret: s3cret
+on_error:
+  - type: slack
+    config:
+      webhookUrl: https://hooks.slack.com/services/x
+on_approval_requested:
+  - type: webhook
+    url: https://example.com/approvals
+`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs, err := config.HookConfigs()
+	if err != nil {
+		t.Fatalf("HookConfigs() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("expected 2 hooks, got %+v", configs)
+	}
+
+	notify := configs[0]
+	if notify.Name != "deploy-notify" || notify.Type != "webhook" || !notify.Enabled {
+		t.Errorf("unexpected hook: %+v", notify)
+	}
+	if len(notify.Events) != 1 || notify.Events[0] != hooks.EventWorkflowComplete {
+		t.Errorf("expected on_workflow_complete, got %v", notify.Events)
+	}
+	if notify.Config["url"] != "https://example.com/hooks" || notify.Config["secret"] != "s3cret" {
+		t.Errorf("expected inline settings, got %v", notify.Config)
+	}
+	if _, ok := notify.Config["name"]; ok {
+		t.Error("expected name not to be passed as a setting")
+	}
+
+	alert := configs[1]
+	if alert.Name != "on_error-slack-1" || len(alert.Events) != 2 {
+		t.Errorf("unexpected hook: %+v", alert)
+	}
+	if alert.Config["webhookUrl"] != "https://hooks.slack.com/services/x" {
+		t.Errorf("expected nested config settings, got %v", alert.Config)
+	}
+}
+
+func TestHookConfigsMissingType(t *testing.T) {
+	config := HooksConfig{OnStepAfter: []Hook{{Config: map[string]interface{}{"url": "https://example.com"}}}}
+	if _, err := config.HookConfigs(); err == nil {
+		t.Error("expected an error for a hook without a type")
+	}
+}
@@ -0,0 +1,334 @@
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities
+const (
+	// FindingError marks a problem that prevents the profile from loading
+	// as written
+	FindingError = "error"
+
+	// FindingWarning marks a suspicious but valid setting
+	FindingWarning = "warning"
+)
+
+// Finding is a problem found while linting a profile.
+type Finding struct {
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Location returns the file and line of the finding, such as
+// "auto.profiles.yaml:12", or an empty string if it has none.
+func (f Finding) Location() string {
+	if f.File == "" {
+		return ""
+	}
+	if f.Line == 0 {
+		return f.File
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// LintReport is the result of linting a profile.
+type LintReport struct {
+	Profile  string    `json:"profile"`
+	Extends  string    `json:"extends,omitempty"`
+	Valid    bool      `json:"valid"`
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings with a severity.
+func (r *LintReport) Count(severity string) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// profileSource is the definition of a profile in a user or project file
+type profileSource struct {
+	file    string
+	profile Profile
+	key     *yaml.Node // the profile's name in the file
+	value   *yaml.Node // the profile's settings
+}
+
+// Lint checks a profile for invalid settings and suspicious combinations.
+//
+// Unlike Load, which skips user and project definitions that fail
+// validation, Lint reports every problem in each definition, with the file
+// and line of the setting where possible.
+func (l *Loader) Lint(name string) *LintReport {
+	report := &LintReport{Profile: name, Findings: []Finding{}}
+	defer func() {
+		report.Valid = report.Count(FindingError) == 0
+	}()
+
+	sources, findings := l.profileSources(name)
+	report.Findings = append(report.Findings, findings...)
+
+	var extends string
+	for _, source := range sources {
+		if source.profile.Extends != "" {
+			extends = source.profile.Extends
+		}
+	}
+	report.Extends = extends
+
+	// Definitions without extends are validated on their own, since Load
+	// skips the ones that are invalid
+	if extends == "" {
+		for _, source := range sources {
+			for _, problem := range source.profile.fieldErrors() {
+				report.addProblem(FindingError, problem, []profileSource{source})
+			}
+		}
+	}
+
+	profile, err := l.resolve(name, nil)
+	if err != nil {
+		// Errors already reported in the definitions also fail resolution
+		if report.Count(FindingError) == 0 {
+			report.Findings = append(report.Findings, Finding{Severity: FindingError, Message: err.Error()})
+		}
+		return report
+	}
+
+	// Extended profiles are validated after merging, with settings located
+	// in the child or the nearest ancestor that defines them
+	if extends != "" {
+		lookup := l.ancestorSources(profile, sources)
+		for _, problem := range profile.fieldErrors() {
+			report.addProblem(FindingError, problem, lookup)
+		}
+	}
+
+	for _, problem := range profile.combinationWarnings() {
+		report.addProblem(FindingWarning, problem, l.ancestorSources(profile, sources))
+	}
+
+	return report
+}
+
+// addProblem adds a field problem, located in the first source that sets the
+// field or its closest enclosing section
+func (r *LintReport) addProblem(severity string, problem fieldError, sources []profileSource) {
+	finding := Finding{Severity: severity, Field: problem.field, Message: problem.err.Error()}
+	path := strings.Split(problem.field, ".")
+	exact := false
+	for depth := len(path); depth >= 0 && finding.File == ""; depth-- {
+		for _, source := range sources {
+			if node := lookupNode(source, path[:depth]); node != nil {
+				finding.File, finding.Line = source.file, node.Line
+				exact = depth == len(path)
+				break
+			}
+		}
+	}
+
+	// A value that could not be decoded also fails validation as a zero
+	// value, and is already reported at its line
+	for _, existing := range r.Findings {
+		if exact && existing.Severity == FindingError && existing.File == finding.File && existing.Line == finding.Line {
+			return
+		}
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+// lookupNode returns the key node of a dotted field path in a profile
+// definition, or the profile's name for an empty path
+func lookupNode(source profileSource, path []string) *yaml.Node {
+	key, value := source.key, source.value
+	for _, name := range path {
+		key, value = mappingEntry(value, name)
+		if key == nil {
+			return nil
+		}
+	}
+	return key
+}
+
+// mappingEntry returns the key and value nodes of a mapping entry
+func mappingEntry(mapping *yaml.Node, name string) (key, value *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// ancestorSources returns the definitions of a resolved profile followed by
+// the definitions of the profiles it extends
+func (l *Loader) ancestorSources(profile *Profile, sources []profileSource) []profileSource {
+	lookup := append([]profileSource{}, sources...)
+	seen := map[string]bool{profile.Name: true}
+	for parent := profile.Extends; parent != "" && !seen[parent]; {
+		seen[parent] = true
+		parentSources, _ := l.profileSources(parent)
+		lookup = append(lookup, parentSources...)
+
+		next := ""
+		for _, source := range parentSources {
+			if source.profile.Extends != "" {
+				next = source.profile.Extends
+			}
+		}
+		parent = next
+	}
+	return lookup
+}
+
+// yamlErrorLine matches the line prefix of YAML parse and type errors
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// unknownField matches yaml.v3's error for a key with no matching field
+var unknownField = regexp.MustCompile(`^field (\S+) not found in type`)
+
+// wrongType matches yaml.v3's error for a value that cannot be decoded
+var wrongType = regexp.MustCompile("^cannot unmarshal !!\\w+ `(.*)` into (\\S+)$")
+
+// profileSources returns the project and user definitions of a profile,
+// project first, and findings for settings that cannot be decoded
+func (l *Loader) profileSources(name string) ([]profileSource, []Finding) {
+	var sources []profileSource
+	var findings []Finding
+	for _, dir := range []string{l.projectDir, l.userDir} {
+		path := filepath.Join(dir, "auto.profiles.yaml")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		data, err := readProfileFile(path)
+		if err != nil {
+			findings = append(findings, Finding{Severity: FindingError, Message: err.Error(), File: path})
+			continue
+		}
+
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			findings = append(findings, yamlFinding(FindingError, path, err.Error()))
+			continue
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		_, profilesNode := mappingEntry(document.Content[0], "profiles")
+		key, value := mappingEntry(profilesNode, name)
+		if key == nil {
+			continue
+		}
+
+		// Decode strictly to report unknown keys and values of the wrong type
+		var collection ProfileCollection
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&collection); err != nil {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				findings = append(findings, yamlFinding(FindingError, path, err.Error()))
+				continue
+			}
+			end := nextKeyLine(profilesNode, key)
+			for _, message := range typeErr.Errors {
+				finding := yamlFinding(FindingError, path, message)
+				if finding.Line < key.Line || (end > 0 && finding.Line >= end) {
+					continue
+				}
+				if match := unknownField.FindStringSubmatch(finding.Message); match != nil {
+					finding.Severity = FindingWarning
+					finding.Message = fmt.Sprintf("unknown field %q is ignored", match[1])
+				} else if match := wrongType.FindStringSubmatch(finding.Message); match != nil {
+					finding.Message = fmt.Sprintf("invalid value %q (want %s)", match[1], match[2])
+				}
+				findings = append(findings, finding)
+			}
+		}
+		if err := checkSchema(collection.Schema); err != nil {
+			findings = append(findings, Finding{Severity: FindingError, Message: err.Error(), File: path})
+		}
+
+		profile := collection.Profiles[name]
+		profile.Name = name
+		sources = append(sources, profileSource{file: path, profile: profile, key: key, value: value})
+	}
+	return sources, findings
+}
+
+// nextKeyLine returns the line of the mapping key after key, or 0 if key is
+// the last one
+func nextKeyLine(mapping, key *yaml.Node) int {
+	for i := 0; i+2 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] == key {
+			return mapping.Content[i+2].Line
+		}
+	}
+	return 0
+}
+
+// yamlFinding converts a YAML error message into a finding, moving its line
+// number into the location
+func yamlFinding(severity, file, message string) Finding {
+	finding := Finding{Severity: severity, Message: message, File: file}
+	if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+		finding.Line, _ = strconv.Atoi(match[1])
+		finding.Message = match[2]
+	}
+	return finding
+}
+
+// combinationWarnings returns settings that are valid on their own but are
+// unlikely to do what was intended together
+func (p *Profile) combinationWarnings() []fieldError {
+	var warnings []fieldError
+	add := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, fieldError{field, fmt.Errorf(format, args...)})
+	}
+
+	if p.Execution.EnableTUI && !p.Approvals.Interactive {
+		add("execution.enable_tui", "enable_tui is set but approvals.interactive is false, so the TUI cannot prompt for approvals")
+	}
+	if p.Safety.RequirePolicy && !p.Policies.Enabled {
+		add("safety.require_policy", "require_policy is set but policies.enabled is false, so no policy checks run")
+	}
+	if p.Execution.RollbackOnVerifyFailure && !p.Execution.SavePatches {
+		add("execution.rollback_on_verify_failure", "rollback_on_verify_failure has no effect without save_patches")
+	}
+	if len(p.Execution.VerifyCommands) > 0 && !p.Execution.Verify {
+		add("execution.verify_commands", "verify_commands are ignored because verify is false")
+	}
+	if p.Approvals.Mode == ApprovalModePerStep && p.Approvals.Interactive && p.Execution.MaxParallelTasks > 1 {
+		add("execution.max_parallel_tasks", "tasks run one at a time while per_step approvals prompt, so max_parallel_tasks %d has no effect", p.Execution.MaxParallelTasks)
+	}
+	for _, stepType := range p.Safety.AllowedStepTypes {
+		for _, blocked := range p.Safety.BlockedStepTypes {
+			if stepType == blocked {
+				add("safety.blocked_step_types", "step type %q is both allowed and blocked", stepType)
+			}
+		}
+	}
+
+	return warnings
+}
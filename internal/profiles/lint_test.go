@@ -0,0 +1,127 @@
+package profiles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoader_Lint(t *testing.T) {
+	project := `schema: "specular.auto.profiles/v1"
+profiles:
+  payments:
+    extends: strict
+    approvals:
+      interactive: false
+    safety:
+      max_retries: 50
+      max_step: 3
+  team:
+    description: "Team profile"
+    approvals:
+      mode: sometimes
+    safety:
+      max_steps: 0
+      timeout: soon
+  loop:
+    extends: loop
+`
+	type want struct {
+		severity string
+		line     int
+		message  string
+	}
+	tests := []struct {
+		name      string
+		profile   string
+		wantValid bool
+		want      []want
+	}{
+		{
+			name:      "built-in profile",
+			profile:   "strict",
+			wantValid: true,
+		},
+		{
+			name:    "extended profile",
+			profile: "payments",
+			want: []want{
+				{FindingWarning, 9, `unknown field "max_step" is ignored`},
+				{FindingError, 8, "max_retries must be between 0 and 10, got 50"},
+				{FindingWarning, 3, "enable_tui is set but approvals.interactive is false"},
+			},
+		},
+		{
+			name:    "every field is reported",
+			profile: "team",
+			want: []want{
+				{FindingError, 16, `invalid value "soon" (want time.Duration)`},
+				{FindingError, 13, `invalid approval mode: "sometimes"`},
+				{FindingError, 15, "max_steps must be between 1 and 100, got 0"},
+				{FindingError, 14, "max_cost_usd must be positive"},
+				{FindingError, 14, "max_cost_per_task must be positive"},
+				{FindingError, 10, "preferred_agent is required"},
+				{FindingError, 10, "fallback_agent is required"},
+				{FindingError, 10, "invalid policy enforcement"},
+				{FindingError, 10, "checkpoint_frequency must be positive"},
+			},
+		},
+		{
+			name:    "circular extends",
+			profile: "loop",
+			want:    []want{{FindingError, 0, "circular profile extends: loop -> loop"}},
+		},
+		{
+			name:    "unknown profile",
+			profile: "missing",
+			want:    []want{{FindingError, 0, `profile "missing" not found`}},
+		},
+	}
+
+	loader := newTestLoader(t, "", project)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := loader.Lint(tt.profile)
+			if report.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", report.Valid, tt.wantValid)
+			}
+			if len(report.Findings) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d: %+v", len(report.Findings), len(tt.want), report.Findings)
+			}
+			for i, w := range tt.want {
+				got := report.Findings[i]
+				if got.Severity != w.severity || got.Line != w.line || !strings.Contains(got.Message, w.message) {
+					t.Errorf("finding %d = %+v, want %s at line %d containing %q", i, got, w.severity, w.line, w.message)
+				}
+			}
+		})
+	}
+}
+
+func TestProfile_CombinationWarnings(t *testing.T) {
+	loader := NewLoader()
+	for _, name := range []string{"default", "ci", "strict"} {
+		profile, err := loader.Load(name)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		if warnings := profile.combinationWarnings(); len(warnings) != 0 {
+			t.Errorf("built-in profile %s has warnings: %v", name, warnings)
+		}
+	}
+
+	profile, _ := loader.Load("strict")
+	p := *profile
+	p.Execution.RollbackOnVerifyFailure = true
+	p.Execution.SavePatches = false
+	p.Execution.VerifyCommands = []string{"make test"}
+	p.Safety.BlockedStepTypes = []string{"plan:gen"}
+
+	fields := []string{}
+	for _, warning := range p.combinationWarnings() {
+		fields = append(fields, warning.field)
+	}
+	want := "execution.rollback_on_verify_failure,execution.verify_commands,safety.blocked_step_types"
+	if got := strings.Join(fields, ","); got != want {
+		t.Errorf("warnings for fields %s, want %s", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -50,6 +51,11 @@ func (l *Loader) SetProjectDir(dir string) {
 // 2. User-level profile (~/.specular/auto.profiles.yaml)
 // 3. Built-in profile (embedded in binary)
 //
+// A user or project profile with an extends key is instead resolved by
+// deep-merging its settings over the fully resolved parent profile. The
+// child wins on conflicts and lists are replaced unless list_merge selects
+// append for their path.
+//
 // If the profile is not found in any source, returns an error.
 func (l *Loader) Load(name string) (*Profile, error) {
 	// Check cache first
@@ -58,32 +64,176 @@ func (l *Loader) Load(name string) (*Profile, error) {
 		return cached, nil
 	}
 
-	// Start with built-in profile as base
-	base, err := l.loadBuiltin(name)
+	profile, err := l.resolve(name, nil)
 	if err != nil {
-		return nil, fmt.Errorf("profile %q not found in built-in profiles: %w", name, err)
+		return nil, err
+	}
+
+	// Validate final profile
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %q: %w", name, err)
 	}
 
+	// Cache and return
+	l.cache[cacheKey] = profile
+	return profile, nil
+}
+
+// resolve resolves a profile and the profiles it extends. chain holds the
+// profiles already being resolved, to detect circular extends.
+func (l *Loader) resolve(name string, chain []string) (*Profile, error) {
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("circular profile extends: %s", strings.Join(append(chain, name), " -> "))
+		}
+	}
+
+	overrides, err := l.loadCustomRaw(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var child Profile
+	if err := remarshal(overrides, &child); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+	if child.Extends == "" {
+		return l.resolveLayered(name)
+	}
+
+	parent, err := l.resolve(child.Extends, append(chain, name))
+	if err != nil {
+		return nil, err
+	}
+
+	for path, strategy := range child.ListMerge {
+		if strategy != ListMergeReplace && strategy != ListMergeAppend {
+			return nil, fmt.Errorf("profile %q: invalid list_merge strategy %q for %s (must be replace or append)", name, strategy, path)
+		}
+	}
+
+	base := make(map[string]interface{})
+	if err := remarshal(parent, &base); err != nil {
+		return nil, fmt.Errorf("failed to merge profile %q: %w", name, err)
+	}
+	delete(base, "list_merge")
+
+	var profile Profile
+	if err := remarshal(mergeMaps(base, overrides, child.ListMerge, ""), &profile); err != nil {
+		return nil, fmt.Errorf("failed to merge profile %q: %w", name, err)
+	}
+	profile.Name = name
+	return &profile, nil
+}
+
+// resolveLayered layers the user and project definitions of a profile over
+// the built-in profile of the same name.
+func (l *Loader) resolveLayered(name string) (*Profile, error) {
+	base, err := l.loadBuiltin(name)
+
 	// Layer user-level profile
-	if userProfile, err := l.loadUser(name); err == nil {
-		base = base.Merge(userProfile)
+	if userProfile, userErr := l.loadUser(name); userErr == nil {
+		if base == nil {
+			base = userProfile
+		} else {
+			base = base.Merge(userProfile)
+		}
 	}
 
 	// Layer project-level profile (highest precedence)
-	if projectProfile, err := l.loadProject(name); err == nil {
-		base = base.Merge(projectProfile)
+	if projectProfile, projectErr := l.loadProject(name); projectErr == nil {
+		if base == nil {
+			base = projectProfile
+		} else {
+			base = base.Merge(projectProfile)
+		}
 	}
 
-	// Validate final profile
-	if err := base.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid profile %q: %w", name, err)
+	if base == nil {
+		return nil, fmt.Errorf("profile %q not found in built-in profiles: %w", name, err)
 	}
-
-	// Cache and return
-	l.cache[cacheKey] = base
 	return base, nil
 }
 
+// loadCustomRaw returns the user and project settings of a profile as a
+// YAML map, with project settings taking precedence. It returns nil when
+// neither file defines the profile.
+func (l *Loader) loadCustomRaw(name string) (map[string]interface{}, error) {
+	var merged map[string]interface{}
+	for _, dir := range []string{l.userDir, l.projectDir} {
+		path := filepath.Join(dir, "auto.profiles.yaml")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		var collection struct {
+			Schema   string                            `yaml:"schema"`
+			Profiles map[string]map[string]interface{} `yaml:"profiles"`
+		}
+		data, err := readProfileFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &collection); err != nil {
+			return nil, fmt.Errorf("failed to parse profile file: %w", err)
+		}
+		if err := checkSchema(collection.Schema); err != nil {
+			return nil, err
+		}
+
+		if settings, ok := collection.Profiles[name]; ok {
+			if merged == nil {
+				merged = settings
+			} else {
+				merged = mergeMaps(merged, settings, nil, "")
+			}
+		}
+	}
+	return merged, nil
+}
+
+// mergeMaps deep-merges override into base. Override values win, nested maps
+// are merged, and lists are replaced unless strategies selects append for
+// their dotted path.
+func mergeMaps(base, override map[string]interface{}, strategies map[string]ListMergeStrategy, prefix string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range override {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if baseMap, ok := merged[key].(map[string]interface{}); ok {
+				merged[key] = mergeMaps(baseMap, value, strategies, path)
+				continue
+			}
+		case []interface{}:
+			if baseList, ok := merged[key].([]interface{}); ok && strategies[path] == ListMergeAppend {
+				merged[key] = append(append([]interface{}{}, baseList...), value...)
+				continue
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// remarshal converts between YAML representations, such as a Profile and
+// its YAML map
+func remarshal(in, out interface{}) error {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
 // LoadFromFile loads profiles from a specific file.
 func (l *Loader) LoadFromFile(path string, name string) (*Profile, error) {
 	collection, err := l.parseYAMLFile(path)
@@ -136,6 +286,7 @@ func (l *Loader) List() ([]string, error) {
 	for name := range profiles {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 
 	return names, nil
 }
@@ -233,27 +384,42 @@ func (l *Loader) listProject() ([]string, error) {
 
 // parseYAMLFile parses a YAML file into a ProfileCollection.
 func (l *Loader) parseYAMLFile(path string) (*ProfileCollection, error) {
-	data, err := os.ReadFile(path)
+	data, err := readProfileFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read profile file: %w", err)
+		return nil, err
 	}
 
-	// Expand environment variables
-	expanded := os.ExpandEnv(string(data))
-
 	var collection ProfileCollection
-	if err := yaml.Unmarshal([]byte(expanded), &collection); err != nil {
+	if err := yaml.Unmarshal(data, &collection); err != nil {
 		return nil, fmt.Errorf("failed to parse profile file: %w", err)
 	}
 
-	// Validate schema version
-	if collection.Schema != "" && !strings.HasPrefix(collection.Schema, "specular.auto.profiles/v") {
-		return nil, fmt.Errorf("unsupported schema version: %s", collection.Schema)
+	if err := checkSchema(collection.Schema); err != nil {
+		return nil, err
 	}
 
 	return &collection, nil
 }
 
+// readProfileFile reads a profile file and expands environment variables.
+func readProfileFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	// Expand environment variables
+	return []byte(os.ExpandEnv(string(data))), nil
+}
+
+// checkSchema validates the schema version of a profile file.
+func checkSchema(schema string) error {
+	if schema != "" && !strings.HasPrefix(schema, "specular.auto.profiles/v") {
+		return fmt.Errorf("unsupported schema version: %s", schema)
+	}
+	return nil
+}
+
 // GetDefault returns the default profile.
 func (l *Loader) GetDefault() (*Profile, error) {
 	return l.Load("default")
@@ -304,6 +470,12 @@ func MergeWithCLIFlags(profile *Profile, flags *CLIFlags) *Profile {
 	if flags.EnableTUI != nil {
 		merged.Execution.EnableTUI = *flags.EnableTUI
 	}
+	if flags.MaxParallelTasks != nil {
+		merged.Execution.MaxParallelTasks = *flags.MaxParallelTasks
+	}
+	if flags.Verify != nil {
+		merged.Execution.Verify = *flags.Verify
+	}
 
 	return &merged
 }
@@ -321,8 +493,10 @@ type CLIFlags struct {
 	MaxRetries     *int
 
 	// Execution overrides
-	Trace       *bool
-	SavePatches *bool
-	JSONOutput  *bool
-	EnableTUI   *bool
+	Trace            *bool
+	SavePatches      *bool
+	JSONOutput       *bool
+	EnableTUI        *bool
+	MaxParallelTasks *int
+	Verify           *bool
 }
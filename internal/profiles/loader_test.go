@@ -3,6 +3,7 @@ package profiles
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -117,8 +118,8 @@ func TestLoader_StrictProfileValidation(t *testing.T) {
 	}
 
 	// Validate strict-specific settings
-	if profile.Approvals.Mode != ApprovalModeAll {
-		t.Errorf("expected all mode, got %s", profile.Approvals.Mode)
+	if profile.Approvals.Mode != ApprovalModePerStep {
+		t.Errorf("expected per_step mode, got %s", profile.Approvals.Mode)
 	}
 	if profile.Safety.MaxSteps != 5 {
 		t.Errorf("expected max_steps 5, got %d", profile.Safety.MaxSteps)
@@ -126,6 +127,9 @@ func TestLoader_StrictProfileValidation(t *testing.T) {
 	if profile.Safety.MaxCostUSD != 1.0 {
 		t.Errorf("expected max_cost_usd 1.0, got %.2f", profile.Safety.MaxCostUSD)
 	}
+	if !profile.Execution.FailFast {
+		t.Error("expected fail_fast to be true")
+	}
 }
 
 func TestLoader_List(t *testing.T) {
@@ -240,6 +244,165 @@ profiles:
 	}
 }
 
+// newTestLoader returns a loader reading user and project profile files
+// from temporary directories
+func newTestLoader(t *testing.T, user, project string) *Loader {
+	t.Helper()
+	loader := NewLoader()
+	loader.userDir = t.TempDir()
+	loader.SetProjectDir(t.TempDir())
+
+	for dir, content := range map[string]string{loader.userDir: user, loader.projectDir: project} {
+		if content == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auto.profiles.yaml"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write profile file: %v", err)
+		}
+	}
+	return loader
+}
+
+func TestLoader_Extends(t *testing.T) {
+	project := `schema: "specular.auto.profiles/v1"
+profiles:
+  payments:
+    extends: strict
+    description: "Strict profile for the payments team"
+    safety:
+      max_cost_usd: 5.0
+      blocked_step_types:
+        - "verify:run"
+      blocked_paths:
+        - "payments/keys/**"
+    execution:
+      enable_tui: false
+  payments-ci:
+    extends: payments
+    list_merge:
+      safety.blocked_step_types: append
+    approvals:
+      interactive: false
+    safety:
+      blocked_step_types:
+        - "spec:lock"
+`
+	loader := newTestLoader(t, "", project)
+
+	payments, err := loader.Load("payments")
+	if err != nil {
+		t.Fatalf("Load(payments) error = %v", err)
+	}
+	if payments.Name != "payments" || payments.Extends != "strict" {
+		t.Errorf("name = %q, extends = %q", payments.Name, payments.Extends)
+	}
+	// Overrides win, everything else comes from strict
+	if payments.Safety.MaxCostUSD != 5.0 || payments.Execution.EnableTUI {
+		t.Errorf("overrides not applied: max_cost_usd %.2f, enable_tui %v", payments.Safety.MaxCostUSD, payments.Execution.EnableTUI)
+	}
+	if payments.Safety.MaxSteps != 5 || payments.Safety.Timeout != 10*time.Minute || !payments.Safety.RequirePolicy {
+		t.Errorf("strict safety settings not inherited: %+v", payments.Safety)
+	}
+	if payments.Approvals.Mode != ApprovalModePerStep || !payments.Execution.TraceLogging || !payments.Execution.FailFast {
+		t.Errorf("strict settings not inherited: %+v, %+v", payments.Approvals, payments.Execution)
+	}
+	if len(payments.Safety.AllowedStepTypes) != 2 {
+		t.Errorf("allowed_step_types = %v, want strict's", payments.Safety.AllowedStepTypes)
+	}
+	// Lists are replaced by default
+	if got := payments.Safety.BlockedStepTypes; len(got) != 1 || got[0] != "verify:run" {
+		t.Errorf("blocked_step_types = %v, want [verify:run]", got)
+	}
+
+	ci, err := loader.Load("payments-ci")
+	if err != nil {
+		t.Fatalf("Load(payments-ci) error = %v", err)
+	}
+	if ci.Extends != "payments" || ci.Approvals.Interactive || ci.Safety.MaxCostUSD != 5.0 {
+		t.Errorf("payments-ci = extends %q, interactive %v, max_cost_usd %.2f", ci.Extends, ci.Approvals.Interactive, ci.Safety.MaxCostUSD)
+	}
+	// list_merge appends to the parent's list
+	if got := ci.Safety.BlockedStepTypes; len(got) != 2 || got[0] != "verify:run" || got[1] != "spec:lock" {
+		t.Errorf("blocked_step_types = %v, want [verify:run spec:lock]", got)
+	}
+	if got := ci.Safety.BlockedPaths; len(got) != 1 || got[0] != "payments/keys/**" {
+		t.Errorf("blocked_paths = %v, want inherited from payments", got)
+	}
+}
+
+func TestLoader_ExtendsUserProfile(t *testing.T) {
+	user := `profiles:
+  team:
+    extends: ci
+    safety:
+      max_steps: 20
+`
+	project := `profiles:
+  team:
+    safety:
+      max_retries: 7
+`
+	loader := newTestLoader(t, user, project)
+
+	profile, err := loader.Load("team")
+	if err != nil {
+		t.Fatalf("Load(team) error = %v", err)
+	}
+	if profile.Extends != "ci" || profile.Safety.MaxSteps != 20 || profile.Safety.MaxRetries != 7 {
+		t.Errorf("extends = %q, max_steps = %d, max_retries = %d", profile.Extends, profile.Safety.MaxSteps, profile.Safety.MaxRetries)
+	}
+}
+
+func TestLoader_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		project string
+		wantErr string
+	}{
+		{
+			name:    "circular extends",
+			profile: "a",
+			project: "profiles:\n  a:\n    extends: b\n  b:\n    extends: c\n  c:\n    extends: a\n",
+			wantErr: "circular profile extends: a -> b -> c -> a",
+		},
+		{
+			name:    "extends itself",
+			profile: "a",
+			project: "profiles:\n  a:\n    extends: a\n",
+			wantErr: "circular profile extends: a -> a",
+		},
+		{
+			name:    "unknown parent",
+			profile: "a",
+			project: "profiles:\n  a:\n    extends: missing\n",
+			wantErr: `profile "missing" not found`,
+		},
+		{
+			name:    "invalid list merge strategy",
+			profile: "a",
+			project: "profiles:\n  a:\n    extends: strict\n    list_merge:\n      safety.blocked_paths: prepend\n",
+			wantErr: `invalid list_merge strategy "prepend"`,
+		},
+		{
+			name:    "invalid merged profile",
+			profile: "a",
+			project: "profiles:\n  a:\n    extends: strict\n    safety:\n      max_steps: 0\n",
+			wantErr: `invalid profile "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := newTestLoader(t, "", tt.project)
+			_, err := loader.Load(tt.profile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestMergeWithCLIFlags(t *testing.T) {
 	profile := &Profile{
 		Name:        "test",
@@ -349,6 +512,30 @@ func TestMergeWithCLIFlags(t *testing.T) {
 		}
 	})
 
+	t.Run("override max parallel tasks", func(t *testing.T) {
+		maxParallelTasks := 4
+		flags := &CLIFlags{
+			MaxParallelTasks: &maxParallelTasks,
+		}
+
+		merged := MergeWithCLIFlags(profile, flags)
+		if merged.Execution.MaxParallelTasks != 4 {
+			t.Errorf("expected max_parallel_tasks 4, got %d", merged.Execution.MaxParallelTasks)
+		}
+	})
+
+	t.Run("override verify", func(t *testing.T) {
+		verify := true
+		flags := &CLIFlags{
+			Verify: &verify,
+		}
+
+		merged := MergeWithCLIFlags(profile, flags)
+		if !merged.Execution.Verify {
+			t.Error("expected verify to be true")
+		}
+	})
+
 	t.Run("no flags preserves profile", func(t *testing.T) {
 		flags := &CLIFlags{}
 
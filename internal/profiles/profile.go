@@ -2,6 +2,8 @@ package profiles
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,14 @@ type Profile struct {
 	// Description provides human-readable profile information
 	Description string `yaml:"description" json:"description"`
 
+	// Extends names the parent profile this profile inherits from. Only the
+	// settings that differ from the parent need to be given.
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
+
+	// ListMerge sets how lists are combined with the parent profile's lists,
+	// keyed by dotted path such as "safety.blocked_paths" (default: replace)
+	ListMerge map[string]ListMergeStrategy `yaml:"list_merge,omitempty" json:"list_merge,omitempty"`
+
 	// Approvals configures approval gates and interactive behavior
 	Approvals ApprovalConfig `yaml:"approvals" json:"approvals"`
 
@@ -33,9 +43,21 @@ type Profile struct {
 	Hooks HooksConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
+// ListMergeStrategy defines how a child profile's list combines with its
+// parent's list.
+type ListMergeStrategy string
+
+const (
+	// ListMergeReplace replaces the parent's list with the child's
+	ListMergeReplace ListMergeStrategy = "replace"
+
+	// ListMergeAppend appends the child's list to the parent's
+	ListMergeAppend ListMergeStrategy = "append"
+)
+
 // ApprovalConfig defines approval gate behavior.
 type ApprovalConfig struct {
-	// Mode determines approval strategy: "all", "critical_only", "none"
+	// Mode determines approval strategy: "all", "per_step", "critical_only", "none"
 	Mode ApprovalMode `yaml:"mode" json:"mode"`
 
 	// Interactive enables interactive approval prompts
@@ -55,6 +77,10 @@ const (
 	// ApprovalModeAll requires approval for all steps
 	ApprovalModeAll ApprovalMode = "all"
 
+	// ApprovalModePerStep requires approval for all steps and before each
+	// plan task, showing the changes made by the previous task
+	ApprovalModePerStep ApprovalMode = "per_step"
+
 	// ApprovalModeCriticalOnly requires approval only for critical steps
 	ApprovalModeCriticalOnly ApprovalMode = "critical_only"
 
@@ -79,6 +105,10 @@ type SafetyConfig struct {
 	// MaxRetries limits retry attempts per task
 	MaxRetries int `yaml:"max_retries" json:"max_retries"`
 
+	// CostWarningFraction is the fraction of max_cost_usd spent at which
+	// steps start to warn (0 = default of 0.8); steps are denied at 100%
+	CostWarningFraction float64 `yaml:"cost_warning_fraction,omitempty" json:"cost_warning_fraction,omitempty"`
+
 	// RequirePolicy enforces policy checks before execution
 	RequirePolicy bool `yaml:"require_policy" json:"require_policy"`
 
@@ -87,6 +117,28 @@ type SafetyConfig struct {
 
 	// BlockedStepTypes blacklists step types
 	BlockedStepTypes []string `yaml:"blocked_step_types,omitempty" json:"blocked_step_types,omitempty"`
+
+	// BlockedPaths lists glob patterns of files steps may not change
+	BlockedPaths []string `yaml:"blocked_paths,omitempty" json:"blocked_paths,omitempty"`
+
+	// ExecutionWindow restricts when steps may run (nil = any time)
+	ExecutionWindow *ExecutionWindow `yaml:"execution_window,omitempty" json:"execution_window,omitempty"`
+}
+
+// ExecutionWindow defines the days and hours during which auto mode may
+// execute steps, such as Mon–Fri 09:00–18:00 in Europe/Berlin.
+type ExecutionWindow struct {
+	// Days lists the allowed weekdays as "mon" to "sun" (empty = every day)
+	Days []string `yaml:"days,omitempty" json:"days,omitempty"`
+
+	// Start is the time of day the window opens, as "HH:MM"
+	Start string `yaml:"start" json:"start"`
+
+	// End is the time of day the window closes, as "HH:MM"
+	End string `yaml:"end" json:"end"`
+
+	// Timezone is the IANA timezone of the window (empty = UTC)
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
 }
 
 // RoutingConfig defines agent selection and model preferences.
@@ -146,6 +198,24 @@ type ExecutionConfig struct {
 
 	// EnableTUI enables terminal UI (if available)
 	EnableTUI bool `yaml:"enable_tui" json:"enable_tui"`
+
+	// MaxParallelTasks limits how many independent plan tasks run at once
+	// (0 or 1 = run tasks one at a time)
+	MaxParallelTasks int `yaml:"max_parallel_tasks,omitempty" json:"max_parallel_tasks,omitempty"`
+
+	// FailFast stops starting new tasks once a task fails
+	FailFast bool `yaml:"fail_fast,omitempty" json:"fail_fast,omitempty"`
+
+	// Verify runs the project's build and test commands after execution
+	Verify bool `yaml:"verify,omitempty" json:"verify,omitempty"`
+
+	// VerifyCommands lists the build and test commands to verify with
+	// (empty = go vet, go build, and short tests)
+	VerifyCommands []string `yaml:"verify_commands,omitempty" json:"verify_commands,omitempty"`
+
+	// RollbackOnVerifyFailure reverts the execution step's patch when
+	// verification fails (requires save_patches)
+	RollbackOnVerifyFailure bool `yaml:"rollback_on_verify_failure,omitempty" json:"rollback_on_verify_failure,omitempty"`
 }
 
 // HooksConfig defines lifecycle hooks.
@@ -189,146 +259,269 @@ type ProfileCollection struct {
 
 // Validate validates the profile configuration.
 func (p *Profile) Validate() error {
-	if err := p.Approvals.Validate(); err != nil {
-		return fmt.Errorf("approvals: %w", err)
-	}
+	return firstError(p.fieldErrors())
+}
 
-	if err := p.Safety.Validate(); err != nil {
-		return fmt.Errorf("safety: %w", err)
-	}
+// fieldError is a validation problem with one profile field
+type fieldError struct {
+	// field is the dotted path of the field, such as "max_steps"
+	field string
+	err   error
+}
 
-	if err := p.Routing.Validate(); err != nil {
-		return fmt.Errorf("routing: %w", err)
+// firstError returns the error of the first problem, or nil if there is none
+func firstError(problems []fieldError) error {
+	if len(problems) == 0 {
+		return nil
 	}
+	return problems[0].err
+}
 
-	if err := p.Policies.Validate(); err != nil {
-		return fmt.Errorf("policies: %w", err)
+// fieldErrors returns every validation problem in the profile, with fields
+// prefixed by their section.
+func (p *Profile) fieldErrors() []fieldError {
+	sections := []struct {
+		name     string
+		problems []fieldError
+	}{
+		{"approvals", p.Approvals.fieldErrors()},
+		{"safety", p.Safety.fieldErrors()},
+		{"routing", p.Routing.fieldErrors()},
+		{"policies", p.Policies.fieldErrors()},
+		{"execution", p.Execution.fieldErrors()},
+	}
+
+	var problems []fieldError
+	for _, section := range sections {
+		for _, problem := range section.problems {
+			problems = append(problems, fieldError{
+				field: section.name + "." + problem.field,
+				err:   fmt.Errorf("%s: %w", section.name, problem.err),
+			})
+		}
 	}
+	return problems
+}
 
-	if err := p.Execution.Validate(); err != nil {
-		return fmt.Errorf("execution: %w", err)
-	}
+// validStepTypes lists the step types profiles may refer to
+var validStepTypes = map[string]bool{
+	"spec:update": true,
+	"spec:lock":   true,
+	"plan:gen":    true,
+	"build:run":   true,
+	"verify:run":  true,
+}
 
-	return nil
+// stepTypeErrors reports the invalid step types in a list field
+func stepTypeErrors(field string, stepTypes []string) []fieldError {
+	var problems []fieldError
+	for _, stepType := range stepTypes {
+		if !validStepTypes[stepType] {
+			problems = append(problems, fieldError{field, fmt.Errorf("invalid step type in %s: %q", field, stepType)})
+		}
+	}
+	return problems
 }
 
 // Validate validates approval configuration.
 func (a *ApprovalConfig) Validate() error {
+	return firstError(a.fieldErrors())
+}
+
+func (a *ApprovalConfig) fieldErrors() []fieldError {
+	var problems []fieldError
 	switch a.Mode {
-	case ApprovalModeAll, ApprovalModeCriticalOnly, ApprovalModeNone:
+	case ApprovalModeAll, ApprovalModePerStep, ApprovalModeCriticalOnly, ApprovalModeNone:
 		// Valid modes
 	default:
-		return fmt.Errorf("invalid approval mode: %q (must be all, critical_only, or none)", a.Mode)
+		problems = append(problems, fieldError{"mode", fmt.Errorf("invalid approval mode: %q (must be all, per_step, critical_only, or none)", a.Mode)})
 	}
 
-	// Validate step types
-	validStepTypes := map[string]bool{
-		"spec:update": true,
-		"spec:lock":   true,
-		"plan:gen":    true,
-		"build:run":   true,
-	}
-
-	for _, stepType := range a.AutoApprove {
-		if !validStepTypes[stepType] {
-			return fmt.Errorf("invalid step type in auto_approve: %q", stepType)
-		}
-	}
-
-	for _, stepType := range a.RequireApproval {
-		if !validStepTypes[stepType] {
-			return fmt.Errorf("invalid step type in require_approval: %q", stepType)
-		}
-	}
-
-	return nil
+	problems = append(problems, stepTypeErrors("auto_approve", a.AutoApprove)...)
+	problems = append(problems, stepTypeErrors("require_approval", a.RequireApproval)...)
+	return problems
 }
 
 // Validate validates safety configuration.
 func (s *SafetyConfig) Validate() error {
+	return firstError(s.fieldErrors())
+}
+
+func (s *SafetyConfig) fieldErrors() []fieldError {
+	var problems []fieldError
+	add := func(field string, err error) {
+		problems = append(problems, fieldError{field, err})
+	}
+
 	if s.MaxSteps <= 0 || s.MaxSteps > 100 {
-		return fmt.Errorf("max_steps must be between 1 and 100, got %d", s.MaxSteps)
+		add("max_steps", fmt.Errorf("max_steps must be between 1 and 100, got %d", s.MaxSteps))
 	}
 
 	if s.Timeout <= 0 {
-		return fmt.Errorf("timeout must be positive, got %s", s.Timeout)
+		add("timeout", fmt.Errorf("timeout must be positive, got %s", s.Timeout))
 	}
 
 	if s.MaxCostUSD <= 0 {
-		return fmt.Errorf("max_cost_usd must be positive, got %.2f", s.MaxCostUSD)
+		add("max_cost_usd", fmt.Errorf("max_cost_usd must be positive, got %.2f", s.MaxCostUSD))
 	}
 
 	if s.MaxCostPerTask <= 0 {
-		return fmt.Errorf("max_cost_per_task must be positive, got %.2f", s.MaxCostPerTask)
-	}
-
-	if s.MaxCostPerTask > s.MaxCostUSD {
-		return fmt.Errorf("max_cost_per_task (%.2f) cannot exceed max_cost_usd (%.2f)", s.MaxCostPerTask, s.MaxCostUSD)
+		add("max_cost_per_task", fmt.Errorf("max_cost_per_task must be positive, got %.2f", s.MaxCostPerTask))
+	} else if s.MaxCostUSD > 0 && s.MaxCostPerTask > s.MaxCostUSD {
+		add("max_cost_per_task", fmt.Errorf("max_cost_per_task (%.2f) cannot exceed max_cost_usd (%.2f)", s.MaxCostPerTask, s.MaxCostUSD))
 	}
 
 	if s.MaxRetries < 0 || s.MaxRetries > 10 {
-		return fmt.Errorf("max_retries must be between 0 and 10, got %d", s.MaxRetries)
+		add("max_retries", fmt.Errorf("max_retries must be between 0 and 10, got %d", s.MaxRetries))
 	}
 
-	// Validate step types
-	validStepTypes := map[string]bool{
-		"spec:update": true,
-		"spec:lock":   true,
-		"plan:gen":    true,
-		"build:run":   true,
+	if s.CostWarningFraction < 0 || s.CostWarningFraction >= 1 {
+		add("cost_warning_fraction", fmt.Errorf("cost_warning_fraction must be between 0 and 1, got %.2f", s.CostWarningFraction))
 	}
 
-	for _, stepType := range s.AllowedStepTypes {
-		if !validStepTypes[stepType] {
-			return fmt.Errorf("invalid step type in allowed_step_types: %q", stepType)
+	problems = append(problems, stepTypeErrors("allowed_step_types", s.AllowedStepTypes)...)
+	problems = append(problems, stepTypeErrors("blocked_step_types", s.BlockedStepTypes)...)
+
+	for _, pattern := range s.BlockedPaths {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			add("blocked_paths", fmt.Errorf("invalid pattern in blocked_paths: %q", pattern))
 		}
 	}
 
-	for _, stepType := range s.BlockedStepTypes {
-		if !validStepTypes[stepType] {
-			return fmt.Errorf("invalid step type in blocked_step_types: %q", stepType)
+	if s.ExecutionWindow != nil {
+		if err := s.ExecutionWindow.Validate(); err != nil {
+			add("execution_window", fmt.Errorf("execution_window: %w", err))
 		}
 	}
 
+	return problems
+}
+
+// weekdays maps execution window day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Validate validates the execution window.
+func (w *ExecutionWindow) Validate() error {
+	if _, err := w.Weekdays(); err != nil {
+		return err
+	}
+	if _, _, err := w.Hours(); err != nil {
+		return err
+	}
+	if _, err := w.Location(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// Weekdays returns the allowed weekdays, or nil if every day is allowed.
+func (w *ExecutionWindow) Weekdays() ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range w.Days {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q (must be mon, tue, wed, thu, fri, sat, or sun)", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// Hours returns the window's start and end as offsets from midnight.
+func (w *ExecutionWindow) Hours() (start, end time.Duration, err error) {
+	start, err = parseClock(w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start: %w", err)
+	}
+	end, err = parseClock(w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end: %w", err)
+	}
+	if start >= end {
+		return 0, 0, fmt.Errorf("start %s must be before end %s", w.Start, w.End)
+	}
+	return start, end, nil
+}
+
+// Location returns the window's timezone.
+func (w *ExecutionWindow) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+	}
+	return loc, nil
+}
+
+// parseClock parses an "HH:MM" time of day into an offset from midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (must be HH:MM)", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
 // Validate validates routing configuration.
 func (r *RoutingConfig) Validate() error {
+	return firstError(r.fieldErrors())
+}
+
+func (r *RoutingConfig) fieldErrors() []fieldError {
+	var problems []fieldError
 	if r.PreferredAgent == "" {
-		return fmt.Errorf("preferred_agent is required")
+		problems = append(problems, fieldError{"preferred_agent", fmt.Errorf("preferred_agent is required")})
 	}
 
 	if r.FallbackAgent == "" {
-		return fmt.Errorf("fallback_agent is required")
+		problems = append(problems, fieldError{"fallback_agent", fmt.Errorf("fallback_agent is required")})
 	}
 
 	if r.Temperature < 0.0 || r.Temperature > 1.0 {
-		return fmt.Errorf("temperature must be between 0.0 and 1.0, got %.2f", r.Temperature)
+		problems = append(problems, fieldError{"temperature", fmt.Errorf("temperature must be between 0.0 and 1.0, got %.2f", r.Temperature)})
 	}
 
-	return nil
+	return problems
 }
 
 // Validate validates policy configuration.
 func (p *PolicyConfig) Validate() error {
+	return firstError(p.fieldErrors())
+}
+
+func (p *PolicyConfig) fieldErrors() []fieldError {
 	switch p.Enforcement {
 	case PolicyEnforcementStrict, PolicyEnforcementWarn, PolicyEnforcementNone:
 		// Valid enforcement levels
+		return nil
 	default:
-		return fmt.Errorf("invalid policy enforcement: %q (must be strict, warn, or none)", p.Enforcement)
+		return []fieldError{{"enforcement", fmt.Errorf("invalid policy enforcement: %q (must be strict, warn, or none)", p.Enforcement)}}
 	}
-
-	return nil
 }
 
 // Validate validates execution configuration.
 func (e *ExecutionConfig) Validate() error {
+	return firstError(e.fieldErrors())
+}
+
+func (e *ExecutionConfig) fieldErrors() []fieldError {
+	var problems []fieldError
 	if e.CheckpointFrequency <= 0 {
-		return fmt.Errorf("checkpoint_frequency must be positive, got %d", e.CheckpointFrequency)
+		problems = append(problems, fieldError{"checkpoint_frequency", fmt.Errorf("checkpoint_frequency must be positive, got %d", e.CheckpointFrequency)})
 	}
 
-	return nil
+	if e.MaxParallelTasks < 0 {
+		problems = append(problems, fieldError{"max_parallel_tasks", fmt.Errorf("max_parallel_tasks must be non-negative, got %d", e.MaxParallelTasks)})
+	}
+
+	return problems
 }
 
 // Merge merges another profile into this one, with the other profile taking precedence.
@@ -369,6 +562,9 @@ func (p *Profile) Merge(other *Profile) *Profile {
 	if other.Safety.MaxRetries >= 0 {
 		merged.Safety.MaxRetries = other.Safety.MaxRetries
 	}
+	if other.Safety.CostWarningFraction > 0 {
+		merged.Safety.CostWarningFraction = other.Safety.CostWarningFraction
+	}
 	merged.Safety.RequirePolicy = other.Safety.RequirePolicy
 	if len(other.Safety.AllowedStepTypes) > 0 {
 		merged.Safety.AllowedStepTypes = other.Safety.AllowedStepTypes
@@ -376,6 +572,12 @@ func (p *Profile) Merge(other *Profile) *Profile {
 	if len(other.Safety.BlockedStepTypes) > 0 {
 		merged.Safety.BlockedStepTypes = other.Safety.BlockedStepTypes
 	}
+	if len(other.Safety.BlockedPaths) > 0 {
+		merged.Safety.BlockedPaths = other.Safety.BlockedPaths
+	}
+	if other.Safety.ExecutionWindow != nil {
+		merged.Safety.ExecutionWindow = other.Safety.ExecutionWindow
+	}
 
 	// Merge Routing
 	merged.Routing = p.Routing
@@ -417,6 +619,15 @@ func (p *Profile) Merge(other *Profile) *Profile {
 	}
 	merged.Execution.JSONOutput = other.Execution.JSONOutput
 	merged.Execution.EnableTUI = other.Execution.EnableTUI
+	if other.Execution.MaxParallelTasks > 0 {
+		merged.Execution.MaxParallelTasks = other.Execution.MaxParallelTasks
+	}
+	merged.Execution.FailFast = other.Execution.FailFast
+	merged.Execution.Verify = other.Execution.Verify
+	if len(other.Execution.VerifyCommands) > 0 {
+		merged.Execution.VerifyCommands = other.Execution.VerifyCommands
+	}
+	merged.Execution.RollbackOnVerifyFailure = other.Execution.RollbackOnVerifyFailure
 
 	// Merge Hooks
 	if len(other.Hooks.OnPlanCreated) > 0 {
@@ -457,7 +668,7 @@ func (p *Profile) Merge(other *Profile) *Profile {
 func (p *Profile) ShouldRequireApproval(stepType string) bool {
 	// Check approval mode
 	switch p.Approvals.Mode {
-	case ApprovalModeAll:
+	case ApprovalModeAll, ApprovalModePerStep:
 		return true
 	case ApprovalModeNone:
 		return false
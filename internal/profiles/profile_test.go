@@ -1,6 +1,7 @@
 package profiles
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -184,6 +185,54 @@ func TestSafetyConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "cost_warning_fraction out of range",
+			config: SafetyConfig{
+				MaxSteps:            12,
+				Timeout:             25 * time.Minute,
+				MaxCostUSD:          5.0,
+				MaxCostPerTask:      0.5,
+				MaxRetries:          3,
+				CostWarningFraction: 1.2,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid blocked path pattern",
+			config: SafetyConfig{
+				MaxSteps:       12,
+				Timeout:        25 * time.Minute,
+				MaxCostUSD:     5.0,
+				MaxCostPerTask: 0.5,
+				MaxRetries:     3,
+				BlockedPaths:   []string{"infra/["},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid execution window",
+			config: SafetyConfig{
+				MaxSteps:        12,
+				Timeout:         25 * time.Minute,
+				MaxCostUSD:      5.0,
+				MaxCostPerTask:  0.5,
+				MaxRetries:      3,
+				ExecutionWindow: &ExecutionWindow{Days: []string{"mon", "Fri"}, Start: "09:00", End: "18:00", Timezone: "UTC"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid execution window",
+			config: SafetyConfig{
+				MaxSteps:        12,
+				Timeout:         25 * time.Minute,
+				MaxCostUSD:      5.0,
+				MaxCostPerTask:  0.5,
+				MaxRetries:      3,
+				ExecutionWindow: &ExecutionWindow{Start: "18:00", End: "09:00"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -196,6 +245,37 @@ func TestSafetyConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestExecutionWindow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  ExecutionWindow
+		wantErr string
+	}{
+		{"weekdays in timezone", ExecutionWindow{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "18:00", Timezone: "UTC"}, ""},
+		{"every day in UTC", ExecutionWindow{Start: "00:00", End: "23:59"}, ""},
+		{"invalid day", ExecutionWindow{Days: []string{"monday"}, Start: "09:00", End: "18:00"}, "invalid day"},
+		{"invalid start", ExecutionWindow{Start: "9am", End: "18:00"}, "start: invalid time"},
+		{"invalid end", ExecutionWindow{Start: "09:00", End: "24:00"}, "end: invalid time"},
+		{"start after end", ExecutionWindow{Start: "18:00", End: "09:00"}, "must be before end"},
+		{"unknown timezone", ExecutionWindow{Start: "09:00", End: "18:00", Timezone: "Mars/Olympus"}, "invalid timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ExecutionWindow.Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExecutionWindow.Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestRoutingConfig_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -337,6 +417,14 @@ func TestExecutionConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative max parallel tasks",
+			config: ExecutionConfig{
+				CheckpointFrequency: 1,
+				MaxParallelTasks:    -1,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
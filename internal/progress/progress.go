@@ -385,3 +385,134 @@ func (b *BarIndicator) Finish() {
 
 	fmt.Fprintln(b.writer)
 }
+
+// TransferIndicator renders a byte-based progress bar for uploads and downloads
+type TransferIndicator struct {
+	writer     io.Writer
+	label      string
+	startTime  time.Time
+	lastRender time.Time
+	lastStep   int
+	rendered   bool
+	finished   bool
+	isCI       bool
+	mu         sync.Mutex
+}
+
+// NewTransferIndicator creates a transfer progress bar. In CI environments it
+// prints a line every 10% instead of redrawing the bar.
+func NewTransferIndicator(w io.Writer, label string) *TransferIndicator {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &TransferIndicator{
+		writer:    w,
+		label:     label,
+		startTime: time.Now(),
+		lastStep:  -1,
+		isCI:      os.Getenv("CI") == "true" || os.Getenv("GITHUB_ACTIONS") == "true",
+	}
+}
+
+// Update records the bytes transferred so far. Redraws are throttled, but the
+// final update always renders and ends the line.
+func (t *TransferIndicator) Update(transferred, total int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return
+	}
+
+	complete := total > 0 && transferred >= total
+	if t.isCI {
+		step := 0
+		if total > 0 {
+			step = int(transferred * 10 / total)
+		}
+		if step == t.lastStep {
+			return
+		}
+		t.lastStep = step
+	} else if !complete && time.Since(t.lastRender) < 100*time.Millisecond {
+		return
+	}
+
+	t.render(transferred, total)
+	t.lastRender = time.Now()
+
+	if complete {
+		if !t.isCI {
+			fmt.Fprintln(t.writer)
+		}
+		t.finished = true
+	}
+}
+
+// render draws the transfer progress
+func (t *TransferIndicator) render(transferred, total int64) {
+	progress := 0.0
+	if total > 0 {
+		progress = float64(transferred) / float64(total)
+	}
+	if progress > 1 {
+		progress = 1
+	}
+
+	barWidth := 30
+	filled := int(float64(barWidth) * progress)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+	elapsed := time.Since(t.startTime)
+	rate := ""
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		rate = fmt.Sprintf(" | %s/s", FormatBytes(int64(float64(transferred)/seconds)))
+	}
+
+	prefix := "\r"
+	suffix := ""
+	if t.isCI {
+		prefix = ""
+		suffix = "\n"
+	}
+
+	fmt.Fprintf(t.writer, "%s%s [%s] %.1f%% | %s / %s%s | %s%s",
+		prefix,
+		t.label,
+		bar,
+		progress*100,
+		FormatBytes(transferred),
+		FormatBytes(total),
+		rate,
+		formatDuration(elapsed),
+		suffix,
+	)
+	t.rendered = true
+}
+
+// Finish ends the progress line if the transfer stopped before completing
+func (t *TransferIndicator) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.rendered && !t.finished && !t.isCI {
+		fmt.Fprintln(t.writer)
+	}
+	t.finished = true
+}
+
+// FormatBytes formats a byte count using binary units
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
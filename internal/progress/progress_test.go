@@ -465,3 +465,70 @@ func TestIndicatorNilState(t *testing.T) {
 		t.Error("Should not print summary with nil state")
 	}
 }
+
+func TestTransferIndicator(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ind := NewTransferIndicator(buf, "Pushing")
+	ind.isCI = false
+
+	ind.Update(0, 2048)
+	ind.Update(1024, 2048) // throttled
+	ind.Update(2048, 2048)
+
+	output := buf.String()
+	if strings.Count(output, "\r") != 2 {
+		t.Errorf("Expected 2 renders, got output %q", output)
+	}
+	if !strings.Contains(output, "100.0%") || !strings.Contains(output, "2.0 KiB / 2.0 KiB") {
+		t.Errorf("Output should show completed transfer, got %q", output)
+	}
+	if !strings.HasSuffix(output, "\n") {
+		t.Error("Completed transfer should end the line")
+	}
+
+	// Updates after completion are ignored
+	length := buf.Len()
+	ind.Update(2048, 2048)
+	ind.Finish()
+	if buf.Len() != length {
+		t.Error("Expected no output after completion")
+	}
+}
+
+func TestTransferIndicatorCI(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ind := NewTransferIndicator(buf, "Pulling")
+	ind.isCI = true
+
+	for transferred := int64(0); transferred <= 1000; transferred += 50 {
+		ind.Update(transferred, 1000)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 11 {
+		t.Errorf("Expected a line per 10%% step, got %d lines", len(lines))
+	}
+	if strings.Contains(buf.String(), "\r") {
+		t.Error("CI output should not redraw lines")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{200 * 1024 * 1024, "200.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -32,7 +32,7 @@ type anthropicRequest struct {
 	Messages    []anthropicMessage `json:"messages"`
 	System      string             `json:"system,omitempty"`
 	MaxTokens   int                `json:"max_tokens"`
-	Temperature float64            `json:"temperature,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
 	TopP        float64            `json:"top_p,omitempty"`
 	Stream      bool               `json:"stream,omitempty"`
 }
@@ -359,8 +359,8 @@ func (p *AnthropicProvider) buildRequest(req *GenerateRequest, stream bool) *ant
 
 	// Temperature
 	temperature := 0.7
-	if req.Temperature > 0 {
-		temperature = req.Temperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
 	}
 
 	return &anthropicRequest{
@@ -368,7 +368,7 @@ func (p *AnthropicProvider) buildRequest(req *GenerateRequest, stream bool) *ant
 		Messages:    messages,
 		System:      req.SystemPrompt, // System prompt is separate in Anthropic
 		MaxTokens:   maxTokens,
-		Temperature: temperature,
+		Temperature: &temperature,
 		TopP:        req.TopP,
 		Stream:      stream,
 	}
@@ -136,9 +136,10 @@ func TestAnthropicProvider_Generate(t *testing.T) {
 
 	// Test generation
 	ctx := context.Background()
+	temperature := 0.7
 	resp, err := provider.Generate(ctx, &GenerateRequest{
 		Prompt:      "What is 2 + 2?",
-		Temperature: 0.7,
+		Temperature: &temperature,
 		MaxTokens:   100,
 	})
 
@@ -215,6 +216,32 @@ func TestAnthropicProvider_Generate_WithSystemPrompt(t *testing.T) {
 	}
 }
 
+func TestAnthropicProvider_BuildRequestTemperature(t *testing.T) {
+	zero, warm := 0.0, 0.9
+	tests := []struct {
+		name        string
+		temperature *float64
+		want        string
+	}{
+		{name: "unset uses the default", want: `"temperature":0.7`},
+		{name: "explicit zero is sent", temperature: &zero, want: `"temperature":0`},
+		{name: "explicit value", temperature: &warm, want: `"temperature":0.9`},
+	}
+
+	provider := &AnthropicProvider{model: "claude-sonnet-3.5", maxTokens: 100}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(provider.buildRequest(&GenerateRequest{Prompt: "Hello", Temperature: tt.temperature}, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.want+",") && !strings.Contains(string(body), tt.want+"}") {
+				t.Errorf("request body = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
 func TestAnthropicProvider_Generate_Error(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -134,9 +134,9 @@ func ValidateProviderConfig(config *ProviderConfig) error {
 	// Type-specific validation
 	switch config.Type {
 	case ProviderTypeCLI, ProviderTypeNative:
-		// CLI and native providers must have a path
-		if path, ok := config.Config["path"].(string); !ok || path == "" {
-			return fmt.Errorf("CLI and native providers require 'path' in config")
+		// CLI and native providers must have a path or candidate commands
+		if path, ok := config.Config["path"].(string); (!ok || path == "") && !hasCandidateCommands(config.Config) {
+			return fmt.Errorf("CLI and native providers require 'path' or 'commands' in config")
 		}
 	case ProviderTypeAPI:
 		// API providers might need API keys (but allow env var expansion)
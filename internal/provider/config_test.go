@@ -286,6 +286,18 @@ func TestValidateProviderConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "CLI provider with candidate commands",
+			config: &ProviderConfig{
+				Name:   "gemini",
+				Type:   ProviderTypeCLI,
+				Source: "local",
+				Config: map[string]interface{}{
+					"commands": []interface{}{"gemini-provider", "gcloud-provider ai"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "CLI provider missing path",
 			config: &ProviderConfig{
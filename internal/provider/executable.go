@@ -1,20 +1,20 @@
 package provider
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 )
 
 // ExecutableProvider wraps any executable that speaks JSON over stdin/stdout
 // This is the simplest provider type - any program can be a provider
 type ExecutableProvider struct {
-	// path is the path to the executable
-	path string
+	// commands are the candidate commands, probed in order on each call
+	commands []candidateCommand
 
 	// args are additional arguments to pass to the executable
 	args []string
@@ -29,11 +29,57 @@ type ExecutableProvider struct {
 	config map[string]interface{}
 }
 
-// NewExecutableProvider creates a new executable-based provider
+// candidateCommand is an executable plus the leading arguments it needs,
+// such as "gcloud ai generative-models"
+type candidateCommand struct {
+	path string
+	args []string
+}
+
+func (c candidateCommand) String() string {
+	return strings.Join(append([]string{c.path}, c.args...), " ")
+}
+
+// parseCandidateCommands builds the candidate list from config["commands"],
+// falling back to path when no commands are configured
+func parseCandidateCommands(path string, config map[string]interface{}) []candidateCommand {
+	var commands []candidateCommand
+	if list, ok := config["commands"].([]interface{}); ok {
+		for _, item := range list {
+			if str, ok := item.(string); ok {
+				if fields := strings.Fields(str); len(fields) > 0 {
+					commands = append(commands, candidateCommand{path: fields[0], args: fields[1:]})
+				}
+			}
+		}
+	}
+
+	if len(commands) == 0 && path != "" {
+		commands = append(commands, candidateCommand{path: path})
+	}
+	return commands
+}
+
+// hasCandidateCommands reports whether config["commands"] lists at least one
+// command
+func hasCandidateCommands(config map[string]interface{}) bool {
+	return len(parseCandidateCommands("", config)) > 0
+}
+
+// NewExecutableProvider creates a new executable-based provider. When
+// config["commands"] lists candidate commands, path is ignored and the first
+// candidate found on the system is used for each call.
 func NewExecutableProvider(path string, config *ProviderConfig) (*ExecutableProvider, error) {
-	// Verify executable exists
-	if _, err := exec.LookPath(path); err != nil {
-		return nil, fmt.Errorf("executable not found: %s: %w", path, err)
+	commands := parseCandidateCommands(path, config.Config)
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("executable path or commands required")
+	}
+
+	provider := &ExecutableProvider{commands: commands}
+
+	// Verify an executable exists
+	if _, err := provider.resolveCommand(); err != nil {
+		return nil, err
 	}
 
 	// Extract args from config if provided
@@ -54,7 +100,7 @@ func NewExecutableProvider(path string, config *ProviderConfig) (*ExecutableProv
 		Version:     config.Version,
 		Type:        ProviderTypeCLI,
 		TrustLevel:  TrustLevelCommunity, // Default to community
-		Description: fmt.Sprintf("Executable provider: %s", path),
+		Description: fmt.Sprintf("Executable provider: %s", commands[0]),
 	}
 
 	// Set trust level from config if provided
@@ -88,13 +134,44 @@ func NewExecutableProvider(path string, config *ProviderConfig) (*ExecutableProv
 		}
 	}
 
-	return &ExecutableProvider{
-		path:         path,
-		args:         args,
-		info:         info,
-		capabilities: capabilities,
-		config:       config.Config,
-	}, nil
+	provider.args = args
+	provider.info = info
+	provider.capabilities = capabilities
+	provider.config = config.Config
+
+	return provider, nil
+}
+
+// resolveCommand returns the first candidate command found on the system.
+// Candidates are probed on every call so a provider keeps working when its
+// preferred CLI is installed or removed after startup.
+func (e *ExecutableProvider) resolveCommand() (candidateCommand, error) {
+	tried := make([]string, 0, len(e.commands))
+	for _, c := range e.commands {
+		if _, err := exec.LookPath(c.path); err == nil {
+			return c, nil
+		}
+		tried = append(tried, c.path)
+	}
+
+	if len(tried) == 1 {
+		return candidateCommand{}, fmt.Errorf("executable not found: %s", tried[0])
+	}
+	return candidateCommand{}, fmt.Errorf("executable not found (tried %s)", strings.Join(tried, ", "))
+}
+
+// command builds the exec.Cmd for a subcommand using the first available
+// candidate, placing the candidate's leading arguments before args
+func (e *ExecutableProvider) command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	c, err := e.resolveCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmdArgs := make([]string, 0, len(c.args)+len(args))
+	cmdArgs = append(cmdArgs, c.args...)
+	cmdArgs = append(cmdArgs, args...)
+	return exec.CommandContext(ctx, c.path, cmdArgs...), nil
 }
 
 // Generate sends a prompt to the executable and returns the response
@@ -102,8 +179,10 @@ func (e *ExecutableProvider) Generate(ctx context.Context, req *GenerateRequest)
 	startTime := time.Now()
 
 	// Build command with args
-	cmdArgs := append(e.args, "generate")
-	cmd := exec.CommandContext(ctx, e.path, cmdArgs...)
+	cmd, err := e.command(ctx, append(e.args, "generate")...)
+	if err != nil {
+		return nil, err
+	}
 
 	// Prepare request as JSON
 	requestJSON, err := json.Marshal(req)
@@ -166,11 +245,14 @@ func (e *ExecutableProvider) Stream(ctx context.Context, req *GenerateRequest) (
 	}
 
 	// Prepare command with "stream" argument
-	cmdArgs := append([]string{"stream"}, e.args...)
-	cmd := exec.CommandContext(ctx, e.path, cmdArgs...)
+	cmd, err := e.command(ctx, append([]string{"stream"}, e.args...)...)
+	if err != nil {
+		close(chunkChan)
+		return chunkChan, err
+	}
 	cmd.Stdin = bytes.NewReader(reqJSON)
 
-	// Get stdout pipe for line-by-line reading
+	// Get stdout pipe for incremental reading
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		close(chunkChan)
@@ -188,36 +270,9 @@ func (e *ExecutableProvider) Stream(ctx context.Context, req *GenerateRequest) (
 		defer close(chunkChan)
 		defer stdout.Close()
 
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				continue
-			}
-
-			// Parse stream chunk from JSON
-			var chunk StreamChunk
-			if err := json.Unmarshal([]byte(line), &chunk); err != nil {
-				chunkChan <- StreamChunk{
-					Error: fmt.Errorf("failed to parse stream chunk: %w", err),
-					Done:  true,
-				}
-				return
-			}
-
-			chunkChan <- chunk
-
-			if chunk.Done {
-				break
-			}
-		}
-
-		if err := scanner.Err(); err != nil {
-			chunkChan <- StreamChunk{
-				Error: fmt.Errorf("stream read error: %w", err),
-				Done:  true,
-			}
-		}
+		// Chunks are decoded on object boundaries rather than per read or
+		// per line, so frames split across pipe reads are reassembled.
+		readStreamChunks(stdout, chunkChan)
 
 		// Wait for command to finish
 		// Best effort cleanup in goroutine
@@ -237,22 +292,23 @@ func (e *ExecutableProvider) GetInfo() *ProviderInfo {
 	return e.info
 }
 
-// IsAvailable checks if the executable exists and is accessible
+// IsAvailable checks if any candidate executable exists and is accessible
 func (e *ExecutableProvider) IsAvailable() bool {
-	_, err := exec.LookPath(e.path)
+	_, err := e.resolveCommand()
 	return err == nil
 }
 
 // Health performs a health check by calling the provider with a simple request
 func (e *ExecutableProvider) Health(ctx context.Context) error {
-	// Build health check command with timeout
-	cmdArgs := append(e.args, "health")
-
 	// Set a timeout for health check
 	healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(healthCtx, e.path, cmdArgs...)
+	// Build health check command with timeout
+	cmd, err := e.command(healthCtx, append(e.args, "health")...)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
 
 	// Run health check
 	if err := cmd.Run(); err != nil {
@@ -262,6 +318,40 @@ func (e *ExecutableProvider) Health(ctx context.Context) error {
 	return nil
 }
 
+// HealthReport runs the health subcommand and parses the JSON report it
+// prints. Executables that print no report, such as a bare "OK", are
+// reported by their exit status alone.
+func (e *ExecutableProvider) HealthReport(ctx context.Context) *HealthReport {
+	healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	report := &HealthReport{Provider: e.info.Name}
+	cmd, err := e.command(healthCtx, append(e.args, "health")...)
+	if err != nil {
+		report.Error = fmt.Sprintf("health check failed: %v", err)
+		return report
+	}
+
+	output, runErr := cmd.Output()
+	if trimmed := bytes.TrimSpace(output); bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, report); err != nil {
+			report.Healthy = false
+			report.Error = fmt.Sprintf("invalid health report: %v", err)
+			return report
+		}
+	} else {
+		report.Healthy = true
+	}
+
+	if runErr != nil {
+		report.Healthy = false
+		if report.Error == "" {
+			report.Error = fmt.Sprintf("health check failed: %v", runErr)
+		}
+	}
+	return report
+}
+
 // Close cleans up resources (executable providers typically don't need cleanup)
 func (e *ExecutableProvider) Close() error {
 	// Nothing to clean up for executable providers
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProviderScript writes an executable provider script that echoes its
+// arguments back as the generated content
+func writeProviderScript(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	script := `#!/bin/sh
+case "$*" in
+  *health*) exit 0 ;;
+esac
+cat > /dev/null
+printf '{"content":"%s","provider":"` + name + `"}\n' "$*"
+`
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestExecutableProvider_CandidateCommands(t *testing.T) {
+	dir := t.TempDir()
+	fallback := writeProviderScript(t, dir, "fallback-provider")
+	missing := filepath.Join(dir, "missing-provider")
+
+	config := &ProviderConfig{
+		Name: "gemini",
+		Type: ProviderTypeCLI,
+		Config: map[string]interface{}{
+			"commands": []interface{}{missing, fallback + " ai models"},
+		},
+	}
+
+	provider, err := NewExecutableProvider("", config)
+	if err != nil {
+		t.Fatalf("NewExecutableProvider() error = %v", err)
+	}
+
+	if !provider.IsAvailable() {
+		t.Error("IsAvailable() = false, want true when a later candidate exists")
+	}
+	if err := provider.Health(context.Background()); err != nil {
+		t.Errorf("Health() error = %v", err)
+	}
+
+	resp, err := provider.Generate(context.Background(), &GenerateRequest{Prompt: "hello"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Provider != "fallback-provider" {
+		t.Errorf("Provider = %q, want fallback-provider", resp.Provider)
+	}
+	if resp.Content != "ai models generate" {
+		t.Errorf("Content = %q, want the fallback command's leading args before the subcommand", resp.Content)
+	}
+}
+
+func TestExecutableProvider_CandidateCommandsProbedPerCall(t *testing.T) {
+	dir := t.TempDir()
+	fallback := writeProviderScript(t, dir, "fallback-provider")
+	primary := filepath.Join(dir, "primary-provider")
+
+	config := &ProviderConfig{
+		Name: "gemini",
+		Type: ProviderTypeCLI,
+		Config: map[string]interface{}{
+			"commands": []interface{}{primary, fallback},
+		},
+	}
+
+	provider, err := NewExecutableProvider("", config)
+	if err != nil {
+		t.Fatalf("NewExecutableProvider() error = %v", err)
+	}
+
+	// Installing the preferred CLI after startup switches to it
+	writeProviderScript(t, dir, "primary-provider")
+
+	resp, err := provider.Generate(context.Background(), &GenerateRequest{Prompt: "hello"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Provider != "primary-provider" {
+		t.Errorf("Provider = %q, want primary-provider", resp.Provider)
+	}
+}
+
+func TestExecutableProvider_NoCandidateAvailable(t *testing.T) {
+	dir := t.TempDir()
+	config := &ProviderConfig{
+		Name: "gemini",
+		Type: ProviderTypeCLI,
+		Config: map[string]interface{}{
+			"commands": []interface{}{filepath.Join(dir, "first"), filepath.Join(dir, "second") + " ai"},
+		},
+	}
+
+	_, err := NewExecutableProvider("", config)
+	if err == nil {
+		t.Fatal("NewExecutableProvider() should fail when no candidate exists")
+	}
+	if !strings.Contains(err.Error(), "first") || !strings.Contains(err.Error(), "second") {
+		t.Errorf("error = %v, want every tried command listed", err)
+	}
+}
+
+func TestRegistry_LoadFromConfig_CandidateCommands(t *testing.T) {
+	fallback := writeProviderScript(t, t.TempDir(), "fallback-provider")
+
+	registry := NewRegistry()
+	err := registry.LoadFromConfig(&ProviderConfig{
+		Name:    "gemini",
+		Type:    ProviderTypeCLI,
+		Enabled: true,
+		Config: map[string]interface{}{
+			"commands": []interface{}{"this-command-definitely-does-not-exist-12345", fallback},
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadFromConfig() error = %v", err)
+	}
+
+	if _, err := registry.Get("gemini"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+}
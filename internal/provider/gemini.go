@@ -47,6 +47,7 @@ type geminiGenerationConfig struct {
 	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
 	TopP            *float64 `json:"topP,omitempty"`
 	TopK            *int     `json:"topK,omitempty"`
+	Seed            *int64   `json:"seed,omitempty"`
 }
 
 type geminiResponse struct {
@@ -380,8 +381,8 @@ func (p *GeminiProvider) buildRequest(req *GenerateRequest) *geminiRequest {
 	// Add generation config
 	genConfig := &geminiGenerationConfig{}
 
-	if req.Temperature > 0 {
-		temp := req.Temperature
+	if req.Temperature != nil {
+		temp := *req.Temperature
 		genConfig.Temperature = &temp
 	}
 
@@ -396,6 +397,8 @@ func (p *GeminiProvider) buildRequest(req *GenerateRequest) *geminiRequest {
 		genConfig.TopP = &topP
 	}
 
+	genConfig.Seed = requestSeed(req)
+
 	geminiReq.GenerationConfig = genConfig
 
 	return geminiReq
@@ -478,9 +481,8 @@ func (p *GeminiProvider) IsAvailable() bool {
 func (p *GeminiProvider) Health(ctx context.Context) error {
 	// Try a simple generation request
 	req := &GenerateRequest{
-		Prompt:      "Hello",
-		MaxTokens:   10,
-		Temperature: 0.1,
+		Prompt:    "Hello",
+		MaxTokens: 10,
 	}
 
 	_, err := p.Generate(ctx, req)
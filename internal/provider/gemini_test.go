@@ -137,10 +137,11 @@ func TestGeminiProvider_Generate(t *testing.T) {
 
 	// Test generate
 	ctx := context.Background()
+	temperature := 0.7
 	resp, err := provider.Generate(ctx, &GenerateRequest{
 		Prompt:       "Say hello",
 		SystemPrompt: "You are a helpful assistant",
-		Temperature:  0.7,
+		Temperature:  &temperature,
 		MaxTokens:    100,
 	})
 
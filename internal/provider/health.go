@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// HealthReport is the result of a provider health check. Executable
+// providers print it as JSON from their health subcommand.
+type HealthReport struct {
+	Provider string   `json:"provider"`
+	Healthy  bool     `json:"healthy"`
+	Version  string   `json:"version,omitempty"`
+	Models   []string `json:"models,omitempty"`
+
+	// CredentialsPresent is nil when the provider did not report it
+	CredentialsPresent *bool `json:"credentials_present,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReporter is implemented by providers that report more than a bare
+// pass/fail from their health check
+type HealthReporter interface {
+	HealthReport(ctx context.Context) *HealthReport
+}
+
+// CheckHealth runs a provider's health check. Fields the provider does not
+// report are filled in from its configuration: the models come from the
+// configured hint mapping, and API credentials from the api_key setting.
+func CheckHealth(ctx context.Context, client ProviderClient, config *ProviderConfig) *HealthReport {
+	var report *HealthReport
+	if reporter, ok := client.(HealthReporter); ok {
+		report = reporter.HealthReport(ctx)
+	} else {
+		report = &HealthReport{Healthy: true}
+		if err := client.Health(ctx); err != nil {
+			report.Healthy = false
+			report.Error = err.Error()
+		}
+	}
+
+	fillFromConfig(report, config)
+	return report
+}
+
+// LoadFailureReport is the health report of a configured provider that
+// could not be loaded
+func LoadFailureReport(config *ProviderConfig, err error) *HealthReport {
+	report := &HealthReport{Error: err.Error()}
+	fillFromConfig(report, config)
+	return report
+}
+
+// fillFromConfig fills in the report fields the provider did not report
+func fillFromConfig(report *HealthReport, config *ProviderConfig) {
+	if config == nil {
+		return
+	}
+	if report.Provider == "" {
+		report.Provider = config.Name
+	}
+	if len(report.Models) == 0 {
+		report.Models = configuredModels(config)
+	}
+	if report.CredentialsPresent == nil && config.Type == ProviderTypeAPI {
+		apiKey, _ := config.Config["api_key"].(string)
+		present := strings.TrimSpace(expandEnvVars(apiKey)) != ""
+		report.CredentialsPresent = &present
+	}
+}
+
+// configuredModels returns the distinct model names of a provider's hint
+// mapping, sorted
+func configuredModels(config *ProviderConfig) []string {
+	seen := make(map[string]bool, len(config.Models))
+	var models []string
+	for _, name := range config.Models {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		models = append(models, name)
+	}
+	sort.Strings(models)
+	return models
+}
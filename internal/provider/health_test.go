@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeHealthScript writes an executable provider whose health subcommand
+// prints output and exits with code
+func writeHealthScript(t *testing.T, output string, code int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "provider")
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' '%s'\nexit %d\n", output, code)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestExecutableProvider_HealthReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		code        int
+		wantHealthy bool
+		wantModels  []string
+		wantErr     string
+	}{
+		{
+			name:        "json report",
+			output:      `{"provider":"ollama","healthy":true,"version":"0.5.1","models":["llama3.2:latest"],"credentials_present":true}`,
+			wantHealthy: true,
+			wantModels:  []string{"llama3.2:latest"},
+		},
+		{
+			name:    "unhealthy json report",
+			output:  `{"provider":"ollama","healthy":false,"error":"ollama not available"}`,
+			code:    1,
+			wantErr: "ollama not available",
+		},
+		{name: "plain OK", output: "OK", wantHealthy: true},
+		{name: "failing exit status", output: "", code: 1, wantErr: "health check failed"},
+		{name: "healthy report but failing exit status", output: `{"healthy":true}`, code: 2, wantErr: "health check failed"},
+		{name: "invalid json", output: `{"healthy":`, wantErr: "invalid health report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeHealthScript(t, tt.output, tt.code)
+			p, err := NewExecutableProvider(path, &ProviderConfig{Name: "ollama"})
+			if err != nil {
+				t.Fatalf("NewExecutableProvider() error = %v", err)
+			}
+
+			report := p.HealthReport(context.Background())
+			if report.Healthy != tt.wantHealthy {
+				t.Errorf("Healthy = %v, want %v (error: %s)", report.Healthy, tt.wantHealthy, report.Error)
+			}
+			if report.Provider != "ollama" {
+				t.Errorf("Provider = %q, want ollama", report.Provider)
+			}
+			if !reflect.DeepEqual(report.Models, tt.wantModels) {
+				t.Errorf("Models = %v, want %v", report.Models, tt.wantModels)
+			}
+			if !strings.Contains(report.Error, tt.wantErr) || (tt.wantErr == "") != (report.Error == "") {
+				t.Errorf("Error = %q, want %q", report.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+// healthOnlyProvider reports health through ProviderClient.Health alone
+type healthOnlyProvider struct {
+	mockProvider
+	err error
+}
+
+func (p *healthOnlyProvider) Health(context.Context) error { return p.err }
+
+func TestCheckHealth(t *testing.T) {
+	t.Setenv("TEST_PROVIDER_KEY", "secret")
+	t.Setenv("TEST_PROVIDER_EMPTY", "")
+
+	apiConfig := func(apiKey string) *ProviderConfig {
+		return &ProviderConfig{
+			Name:   "openai",
+			Type:   ProviderTypeAPI,
+			Config: map[string]interface{}{"api_key": apiKey},
+			Models: map[string]string{"fast": "gpt-5-mini", "cheap": "gpt-5-mini", "codegen": "gpt-5"},
+		}
+	}
+
+	t.Run("configured models and credentials", func(t *testing.T) {
+		report := CheckHealth(context.Background(), &healthOnlyProvider{}, apiConfig("${TEST_PROVIDER_KEY}"))
+		if !report.Healthy || report.Provider != "openai" {
+			t.Errorf("report = %+v, want healthy openai", report)
+		}
+		if want := []string{"gpt-5", "gpt-5-mini"}; !reflect.DeepEqual(report.Models, want) {
+			t.Errorf("Models = %v, want %v", report.Models, want)
+		}
+		if report.CredentialsPresent == nil || !*report.CredentialsPresent {
+			t.Error("CredentialsPresent should be true")
+		}
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		report := CheckHealth(context.Background(), &healthOnlyProvider{err: errors.New("401")}, apiConfig("${TEST_PROVIDER_EMPTY}"))
+		if report.Healthy || report.Error != "401" {
+			t.Errorf("report = %+v, want unhealthy with the health error", report)
+		}
+		if report.CredentialsPresent == nil || *report.CredentialsPresent {
+			t.Error("CredentialsPresent should be false")
+		}
+	})
+
+	t.Run("cli provider credentials unknown", func(t *testing.T) {
+		report := CheckHealth(context.Background(), &healthOnlyProvider{}, &ProviderConfig{Name: "local", Type: ProviderTypeCLI})
+		if report.CredentialsPresent != nil {
+			t.Errorf("CredentialsPresent = %v, want unknown", *report.CredentialsPresent)
+		}
+	})
+
+	t.Run("load failure", func(t *testing.T) {
+		report := LoadFailureReport(apiConfig(""), errors.New("api_key not found"))
+		if report.Healthy || report.Provider != "openai" || report.Error != "api_key not found" {
+			t.Errorf("report = %+v, want an unhealthy openai report", report)
+		}
+		if report.CredentialsPresent == nil || *report.CredentialsPresent {
+			t.Error("CredentialsPresent should be false")
+		}
+	})
+}
@@ -99,9 +99,10 @@ func TestIntegration_OllamaProvider(t *testing.T) {
 	}
 
 	// Test generation
+	temperature := 0.1
 	req := &GenerateRequest{
 		Prompt:      "What is 2 + 2? Answer with just the number.",
-		Temperature: 0.1,
+		Temperature: &temperature,
 		Config: map[string]interface{}{
 			"model": "llama3.2",
 		},
@@ -146,7 +147,7 @@ func TestIntegration_OllamaProvider(t *testing.T) {
 	// Test multi-turn conversation
 	req2 := &GenerateRequest{
 		Prompt:      "What about 3 + 3?",
-		Temperature: 0.1,
+		Temperature: &temperature,
 		Context: []Message{
 			{Role: "user", Content: "What is 2 + 2?"},
 			{Role: "assistant", Content: resp.Content},
@@ -407,9 +408,10 @@ func TestIntegration_OpenAIProvider(t *testing.T) {
 
 	// Test generation
 	ctx := context.Background()
+	temperature := 0.7
 	req := &GenerateRequest{
 		Prompt:      "Hello!",
-		Temperature: 0.7,
+		Temperature: &temperature,
 		MaxTokens:   100,
 	}
 
@@ -538,9 +540,10 @@ func TestIntegration_AnthropicProvider(t *testing.T) {
 
 	// Test generation
 	ctx := context.Background()
+	temperature := 0.7
 	req := &GenerateRequest{
 		Prompt:      "Hello!",
-		Temperature: 0.7,
+		Temperature: &temperature,
 		MaxTokens:   100,
 	}
 
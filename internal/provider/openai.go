@@ -30,10 +30,11 @@ type OpenAIProvider struct {
 type openAIRequest struct {
 	Model       string          `json:"model"`
 	Messages    []openAIMessage `json:"messages"`
-	Temperature float64         `json:"temperature,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	TopP        float64         `json:"top_p,omitempty"`
 	Stream      bool            `json:"stream,omitempty"`
+	Seed        *int64          `json:"seed,omitempty"`
 }
 
 type openAIMessage struct {
@@ -360,17 +361,18 @@ func (p *OpenAIProvider) buildRequest(req *GenerateRequest, stream bool) *openAI
 
 	// Temperature
 	temperature := 0.7
-	if req.Temperature > 0 {
-		temperature = req.Temperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
 	}
 
 	return &openAIRequest{
 		Model:       model,
 		Messages:    messages,
-		Temperature: temperature,
+		Temperature: &temperature,
 		MaxTokens:   maxTokens,
 		TopP:        req.TopP,
 		Stream:      stream,
+		Seed:        requestSeed(req),
 	}
 }
 
@@ -135,9 +135,10 @@ func TestOpenAIProvider_Generate(t *testing.T) {
 
 	// Test generation
 	ctx := context.Background()
+	temperature := 0.7
 	resp, err := provider.Generate(ctx, &GenerateRequest{
 		Prompt:      "What is 2 + 2?",
-		Temperature: 0.7,
+		Temperature: &temperature,
 		MaxTokens:   100,
 	})
 
@@ -218,6 +219,45 @@ func TestOpenAIProvider_Generate_WithSystemPrompt(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_Generate_Seed(t *testing.T) {
+	var seeds []*int64
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		seeds = append(seeds, req.Seed)
+
+		resp := openAIResponse{
+			Model:   "gpt-4o-mini",
+			Choices: []openAIChoice{{Message: openAIMessage{Content: "OK"}}},
+			Usage:   openAIUsage{TotalTokens: 10},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider, _ := NewOpenAIProvider(&ProviderConfig{
+		Name: "openai",
+		Config: map[string]interface{}{
+			"api_key":  "test-key",
+			"base_url": server.URL,
+		},
+	})
+
+	ctx := context.Background()
+	for _, config := range []map[string]interface{}{{"seed": int64(7)}, nil} {
+		if _, err := provider.Generate(ctx, &GenerateRequest{Prompt: "Hello", Config: config}); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	if len(seeds) != 2 || seeds[0] == nil || *seeds[0] != 7 {
+		t.Fatalf("seeded request sent seed %v, want 7", seeds)
+	}
+	if seeds[1] != nil {
+		t.Errorf("unseeded request sent seed %d, want none", *seeds[1])
+	}
+}
+
 func TestOpenAIProvider_Generate_Error(t *testing.T) {
 	tests := []struct {
 		name       string
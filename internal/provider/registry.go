@@ -162,8 +162,8 @@ func (r *Registry) LoadFromConfig(config *ProviderConfig) error {
 	case ProviderTypeCLI:
 		// All CLI providers use the generic ExecutableProvider
 		// which expects executables that implement generate/stream/health commands
-		path, ok := config.Config["path"].(string)
-		if !ok || path == "" {
+		path, _ := config.Config["path"].(string)
+		if path == "" && !hasCandidateCommands(config.Config) {
 			return fmt.Errorf("executable path required for CLI provider %s", config.Name)
 		}
 		provider, err = NewExecutableProvider(path, config)
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrIncompleteStreamChunk is returned when a stream ends in the middle of a JSON object
+var ErrIncompleteStreamChunk = errors.New("stream ended with an incomplete JSON chunk")
+
+// jsonStreamParser buffers raw bytes from a streaming provider and yields
+// complete top-level JSON objects. Network and pipe reads can split an
+// object at any byte, so decoding only happens once the closing brace of
+// an object has been seen.
+type jsonStreamParser struct {
+	buf []byte
+
+	// Scan state for the object currently being buffered
+	pos      int
+	start    int
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// Feed appends data to the buffer and returns every object completed by it.
+// Incomplete trailing data is retained until the next call.
+func (p *jsonStreamParser) Feed(data []byte) [][]byte {
+	p.buf = append(p.buf, data...)
+
+	var objects [][]byte
+	for ; p.pos < len(p.buf); p.pos++ {
+		c := p.buf[p.pos]
+
+		if p.depth == 0 {
+			// Skip delimiters (newlines, whitespace) between objects
+			if c == '{' {
+				p.start = p.pos
+				p.depth = 1
+			}
+			continue
+		}
+
+		if p.inString {
+			switch {
+			case p.escaped:
+				p.escaped = false
+			case c == '\\':
+				p.escaped = true
+			case c == '"':
+				p.inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			p.inString = true
+		case '{':
+			p.depth++
+		case '}':
+			p.depth--
+			if p.depth == 0 {
+				obj := make([]byte, p.pos+1-p.start)
+				copy(obj, p.buf[p.start:p.pos+1])
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	// Drop consumed bytes so the buffer only holds the pending object
+	if p.depth == 0 {
+		p.buf = p.buf[:0]
+		p.pos = 0
+	} else if p.start > 0 {
+		p.buf = append(p.buf[:0], p.buf[p.start:]...)
+		p.pos -= p.start
+		p.start = 0
+	}
+
+	return objects
+}
+
+// Pending returns buffered bytes that do not yet form a complete object
+func (p *jsonStreamParser) Pending() []byte {
+	return bytes.TrimSpace(p.buf)
+}
+
+// readStreamChunks reads JSON stream chunks from r and sends them to out until
+// a chunk marked Done is received or the reader is exhausted.
+func readStreamChunks(r io.Reader, out chan<- StreamChunk) {
+	var parser jsonStreamParser
+	readBuf := make([]byte, 4096)
+
+	for {
+		n, readErr := r.Read(readBuf)
+		for _, obj := range parser.Feed(readBuf[:n]) {
+			var chunk StreamChunk
+			if err := json.Unmarshal(obj, &chunk); err != nil {
+				out <- StreamChunk{
+					Error: fmt.Errorf("failed to parse stream chunk: %w", err),
+					Done:  true,
+				}
+				return
+			}
+
+			out <- chunk
+
+			if chunk.Done {
+				return
+			}
+		}
+
+		if readErr == io.EOF {
+			if len(parser.Pending()) > 0 {
+				out <- StreamChunk{
+					Error: ErrIncompleteStreamChunk,
+					Done:  true,
+				}
+			}
+			return
+		}
+		if readErr != nil {
+			out <- StreamChunk{
+				Error: fmt.Errorf("stream read error: %w", readErr),
+				Done:  true,
+			}
+			return
+		}
+	}
+}
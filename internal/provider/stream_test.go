@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// fragmentReader returns each fragment from a separate Read call,
+// simulating network chunking of a stream.
+type fragmentReader struct {
+	fragments []string
+}
+
+func (f *fragmentReader) Read(p []byte) (int, error) {
+	if len(f.fragments) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, f.fragments[0])
+	f.fragments[0] = f.fragments[0][n:]
+	if f.fragments[0] == "" {
+		f.fragments = f.fragments[1:]
+	}
+	return n, nil
+}
+
+func collectChunks(r io.Reader) []StreamChunk {
+	out := make(chan StreamChunk, 100)
+	readStreamChunks(r, out)
+	close(out)
+
+	var chunks []StreamChunk
+	for chunk := range out {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestJSONStreamParser_SplitObject(t *testing.T) {
+	var parser jsonStreamParser
+
+	if objs := parser.Feed([]byte(`{"content":"Hel`)); len(objs) != 0 {
+		t.Fatalf("Feed() returned %d objects for incomplete input, want 0", len(objs))
+	}
+	if objs := parser.Feed([]byte(`lo","delta":"lo"`)); len(objs) != 0 {
+		t.Fatalf("Feed() returned %d objects for incomplete input, want 0", len(objs))
+	}
+
+	objs := parser.Feed([]byte("}\n{\"content\":\"x\"}\n{\"del"))
+	if len(objs) != 2 {
+		t.Fatalf("Feed() returned %d objects, want 2", len(objs))
+	}
+	if string(objs[0]) != `{"content":"Hello","delta":"lo"}` {
+		t.Errorf("first object = %s", objs[0])
+	}
+	if string(objs[1]) != `{"content":"x"}` {
+		t.Errorf("second object = %s", objs[1])
+	}
+	if string(parser.Pending()) != `{"del` {
+		t.Errorf("Pending() = %q, want %q", parser.Pending(), `{"del`)
+	}
+}
+
+func TestJSONStreamParser_BracesInStrings(t *testing.T) {
+	var parser jsonStreamParser
+
+	input := `{"content":"func() { return \"}\" }","done":false}`
+	objs := parser.Feed([]byte(input))
+	if len(objs) != 1 {
+		t.Fatalf("Feed() returned %d objects, want 1", len(objs))
+	}
+	if string(objs[0]) != input {
+		t.Errorf("object = %s, want %s", objs[0], input)
+	}
+	if len(parser.Pending()) != 0 {
+		t.Errorf("Pending() = %q, want empty", parser.Pending())
+	}
+}
+
+func TestReadStreamChunks_SplitMidField(t *testing.T) {
+	reader := &fragmentReader{fragments: []string{
+		`{"Content":"Hel`,
+		`lo","Delta":"Hel`,
+		"lo\",\"Done\":false}\n{\"Content\":\"Hello world\",",
+		`"Delta":" world","Done":tr`,
+		"ue,\"TokensUsed\":42}\n",
+	}}
+
+	chunks := collectChunks(reader)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Delta != "Hello" || chunks[0].Done {
+		t.Errorf("first chunk = %+v", chunks[0])
+	}
+	last := chunks[1]
+	if last.Error != nil {
+		t.Fatalf("final chunk error = %v", last.Error)
+	}
+	if !last.Done || last.Content != "Hello world" || last.TokensUsed != 42 {
+		t.Errorf("final chunk = %+v", last)
+	}
+}
+
+func TestReadStreamChunks_OneByteReads(t *testing.T) {
+	stream := "{\"Delta\":\"a\"}\n{\"Delta\":\"b\"}\n{\"Content\":\"ab\",\"Done\":true}\n"
+
+	chunks := collectChunks(iotest.OneByteReader(strings.NewReader(stream)))
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+	if !chunks[2].Done || chunks[2].Content != "ab" {
+		t.Errorf("final chunk = %+v", chunks[2])
+	}
+}
+
+func TestReadStreamChunks_TruncatedStream(t *testing.T) {
+	reader := &fragmentReader{fragments: []string{
+		"{\"Delta\":\"a\"}\n",
+		`{"Delta":"b`,
+	}}
+
+	chunks := collectChunks(reader)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	if !errors.Is(chunks[1].Error, ErrIncompleteStreamChunk) {
+		t.Errorf("final chunk error = %v, want ErrIncompleteStreamChunk", chunks[1].Error)
+	}
+	if !chunks[1].Done {
+		t.Error("final chunk should be marked done")
+	}
+}
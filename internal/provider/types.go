@@ -16,7 +16,8 @@ type GenerateRequest struct {
 
 	// Temperature controls randomness (0.0 = deterministic, 1.0+ = creative)
 	// Typical range: 0.0 to 2.0
-	Temperature float64 `json:"temperature,omitempty"`
+	// Set to nil to use provider default
+	Temperature *float64 `json:"temperature,omitempty"`
 
 	// TopP controls nucleus sampling (alternative to temperature)
 	// Range: 0.0 to 1.0
@@ -155,3 +156,20 @@ type ProviderConfig struct {
 	// Version specifies the provider version (for source resolution)
 	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 }
+
+// requestSeed returns the sampling seed set in req.Config["seed"], or nil
+// when the request is unseeded. Seeds decoded from JSON arrive as float64.
+func requestSeed(req *GenerateRequest) *int64 {
+	var seed int64
+	switch v := req.Config["seed"].(type) {
+	case int64:
+		seed = v
+	case int:
+		seed = int64(v)
+	case float64:
+		seed = int64(v)
+	default:
+		return nil
+	}
+	return &seed
+}
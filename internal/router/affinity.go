@@ -0,0 +1,130 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// modelPin holds the model a router reuses across requests. The zero value
+// is ready to use.
+type modelPin struct {
+	mu sync.Mutex
+	id string
+}
+
+func (p *modelPin) get() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.id
+}
+
+func (p *modelPin) set(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.id = id
+}
+
+// setIfEmpty pins id unless a model is already pinned
+func (p *modelPin) setIfEmpty(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.id == "" {
+		p.id = id
+	}
+}
+
+// PinModel makes the router reuse a model for subsequent requests, so a
+// workflow runs on one model end to end. The pinned model is skipped for a
+// request when it is unavailable, does not fit the request, or would exceed
+// the remaining budget.
+func (r *Router) PinModel(id string) error {
+	if r.findModel(id) == nil {
+		return fmt.Errorf("unknown model %q", id)
+	}
+	r.pin.set(id)
+	return nil
+}
+
+// UnpinModel clears the pinned model
+func (r *Router) UnpinModel() {
+	r.pin.set("")
+}
+
+// PinnedModel returns the pinned model ID, or "" when no model is pinned
+func (r *Router) PinnedModel() string {
+	return r.pin.get()
+}
+
+// LastUsedModel returns the model of the most recent successful request, or
+// "" when no request has succeeded
+func (r *Router) LastUsedModel() string {
+	for i := len(r.usage) - 1; i >= 0; i-- {
+		if r.usage[i].Success {
+			return r.usage[i].Model
+		}
+	}
+	return ""
+}
+
+// pinUsedModel pins the model of the first successful request when
+// StickyModel is enabled
+func (r *Router) pinUsedModel(usage Usage) {
+	if r.config.StickyModel && usage.Success && r.findModel(usage.Model) != nil {
+		r.pin.setIfEmpty(usage.Model)
+	}
+}
+
+// findModel returns the router's model with the given ID
+func (r *Router) findModel(id string) *Model {
+	for i := range r.models {
+		if r.models[i].ID == id {
+			return &r.models[i]
+		}
+	}
+	return nil
+}
+
+// selectPinnedModel returns a result for the pinned model if it can serve the
+// request. Otherwise it returns nil and the reason the pin was bypassed.
+func (r *Router) selectPinnedModel(ctx context.Context, req RoutingRequest) (*RoutingResult, string) {
+	id := r.pin.get()
+	if id == "" {
+		return nil, ""
+	}
+
+	m := r.findModel(id)
+	switch {
+	case m == nil || !m.Available || !r.familyAllowed(*m) || !r.policyAllowsModel(*m) || !r.isProviderUsable(ctx, m.Provider):
+		return nil, fmt.Sprintf("pinned model %s unavailable", id)
+	case req.ContextSize > 0 && m.ContextWindow < req.ContextSize:
+		return nil, fmt.Sprintf("pinned model %s context window too small", id)
+	case req.SelectionMode == SelectionModeCheapestAboveCapability && m.CapabilityScore < req.MinCapability:
+		return nil, fmt.Sprintf("pinned model %s below capability floor", id)
+	}
+
+	estimatedTokens := r.estimateTokens(req, m)
+	estimatedCost := (float64(estimatedTokens) / 1000000.0) * m.CostPerMToken
+	if !r.budget.Allows(estimatedCost) {
+		return nil, fmt.Sprintf("pinned model %s exceeds remaining budget", id)
+	}
+	if !r.budget.AllowsProvider(m.Provider, estimatedCost) {
+		return nil, fmt.Sprintf("pinned model %s exceeds remaining %s budget", id, m.Provider)
+	}
+
+	model := *m
+	return &RoutingResult{
+		Model:           &model,
+		Reason:          fmt.Sprintf("Selected %s (%s): pinned model", m.ID, m.Provider),
+		EstimatedCost:   estimatedCost,
+		EstimatedTokens: estimatedTokens,
+	}, ""
+}
+
+// notePinBypass appends why the pinned model was bypassed to a selection reason
+func notePinBypass(result *RoutingResult, note string) *RoutingResult {
+	if result != nil && note != "" {
+		result.Reason += fmt.Sprintf(" (%s)", note)
+	}
+	return result
+}
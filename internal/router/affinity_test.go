@@ -0,0 +1,138 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRouter_StickyModelPinsFirstSuccessfulModel(t *testing.T) {
+	anthropic := &modelRecordingProvider{}
+	openai := &modelRecordingProvider{}
+	r := newFallbackTestRouter(t, &RouterConfig{StickyModel: true}, map[string]*modelRecordingProvider{
+		"anthropic": anthropic,
+		"openai":    openai,
+	}, fallbackTestModels())
+	ctx := context.Background()
+
+	// The first request pins the model it used
+	resp, err := r.Generate(ctx, GenerateRequest{Prompt: "spec", ModelHint: "agentic", Complexity: 9})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if r.PinnedModel() != resp.Model {
+		t.Fatalf("PinnedModel() = %q, want %q", r.PinnedModel(), resp.Model)
+	}
+
+	// Later requests reuse it even when another model would score higher
+	for _, hint := range []string{"cheap", "fast", "codegen"} {
+		resp, err := r.Generate(ctx, GenerateRequest{Prompt: "task", ModelHint: hint, Complexity: 2})
+		if err != nil {
+			t.Fatalf("Generate(%s) error = %v", hint, err)
+		}
+		if resp.Model != "sonnet" {
+			t.Errorf("hint %s used %s, want pinned sonnet", hint, resp.Model)
+		}
+		if !strings.Contains(resp.SelectionReason, "pinned model") {
+			t.Errorf("SelectionReason = %q, want pinned model", resp.SelectionReason)
+		}
+	}
+
+	if got := r.GetUsageStats()["pinned_model"]; got != "sonnet" {
+		t.Errorf("usage stats pinned_model = %v, want sonnet", got)
+	}
+}
+
+func TestRouter_PinnedModelBypassed(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		setup  func(r *Router)
+		req    RoutingRequest
+		reason string
+	}{
+		{
+			name:   "provider unhealthy",
+			setup:  func(r *Router) { r.MarkProviderUnhealthy(ProviderAnthropic, errors.New("connection refused")) },
+			reason: "pinned model sonnet unavailable",
+		},
+		{
+			name:   "context too large",
+			req:    RoutingRequest{ContextSize: 150000},
+			reason: "pinned model sonnet context window too small",
+		},
+		{
+			name: "budget forces a downgrade",
+			setup: func(r *Router) {
+				r.budget.RemainingUSD = 0.01
+			},
+			req:    RoutingRequest{ContextSize: 4000},
+			reason: "pinned model sonnet exceeds remaining budget",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models := fallbackTestModels()
+			models[0].ContextWindow = 100000
+			r := newFallbackTestRouter(t, &RouterConfig{}, map[string]*modelRecordingProvider{
+				"anthropic": {},
+				"openai":    {},
+			}, models)
+			if err := r.PinModel("sonnet"); err != nil {
+				t.Fatalf("PinModel() error = %v", err)
+			}
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			result, err := r.SelectModel(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("SelectModel() error = %v", err)
+			}
+			if result.Model.ID == "sonnet" {
+				t.Fatal("pinned model should be bypassed")
+			}
+			if !strings.Contains(result.Reason, tt.reason) {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.reason)
+			}
+			if r.PinnedModel() != "sonnet" {
+				t.Error("bypassing the pin for one request should keep it for later requests")
+			}
+		})
+	}
+}
+
+func TestRouter_PinModel(t *testing.T) {
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 100})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	if err := r.PinModel("no-such-model"); err == nil {
+		t.Error("PinModel() should reject an unknown model")
+	}
+	if err := r.PinModel("gpt-4o"); err != nil {
+		t.Fatalf("PinModel() error = %v", err)
+	}
+	if r.PinnedModel() != "gpt-4o" {
+		t.Errorf("PinnedModel() = %q, want gpt-4o", r.PinnedModel())
+	}
+	r.UnpinModel()
+	if r.PinnedModel() != "" {
+		t.Errorf("PinnedModel() after unpin = %q, want empty", r.PinnedModel())
+	}
+
+	// Without StickyModel, usage does not pin a model
+	if err := r.RecordUsage(context.Background(), Usage{Model: "gpt-4o", Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if r.PinnedModel() != "" {
+		t.Errorf("PinnedModel() = %q, want no automatic pin", r.PinnedModel())
+	}
+	if r.LastUsedModel() != "gpt-4o" {
+		t.Errorf("LastUsedModel() = %q, want gpt-4o", r.LastUsedModel())
+	}
+}
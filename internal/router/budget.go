@@ -0,0 +1,204 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// newBudget returns the starting budget for a router configuration
+func newBudget(config *RouterConfig) *Budget {
+	budget := &Budget{Unlimited: true}
+	if !config.BudgetUnlimited {
+		budget = &Budget{
+			LimitUSD:     config.BudgetUSD,
+			SpentUSD:     0,
+			RemainingUSD: config.BudgetUSD,
+			UsageCount:   0,
+		}
+	}
+
+	if len(config.ProviderBudgets) > 0 {
+		budget.ProviderLimitUSD = make(map[Provider]float64, len(config.ProviderBudgets))
+		for name, limit := range config.ProviderBudgets {
+			budget.ProviderLimitUSD[Provider(name)] = limit
+		}
+	}
+	return budget
+}
+
+// Allows reports whether the budget can cover an estimated cost. An
+// unlimited budget allows any cost.
+func (b *Budget) Allows(cost float64) bool {
+	return b.Unlimited || cost <= b.RemainingUSD
+}
+
+// Exhausted reports whether no budget remains. An unlimited budget is never
+// exhausted.
+func (b *Budget) Exhausted() bool {
+	return !b.Unlimited && b.RemainingUSD <= 0
+}
+
+// ProviderRemaining returns the budget left for a provider and whether the
+// provider has a cap
+func (b *Budget) ProviderRemaining(p Provider) (float64, bool) {
+	limit, capped := b.ProviderLimitUSD[p]
+	if !capped {
+		return 0, false
+	}
+	return limit - b.ProviderSpentUSD[p], true
+}
+
+// AllowsProvider reports whether a provider's cap can cover an estimated
+// cost. A provider at its cap allows nothing, not even free requests, and a
+// provider without a cap allows any cost.
+func (b *Budget) AllowsProvider(p Provider, cost float64) bool {
+	remaining, capped := b.ProviderRemaining(p)
+	return !capped || (remaining > 0 && cost <= remaining)
+}
+
+// record adds spend to the budget and to the provider's spend. Spend is
+// tracked even when the budget is unlimited.
+func (b *Budget) record(p Provider, cost float64) {
+	b.SpentUSD += cost
+	if !b.Unlimited {
+		b.RemainingUSD = b.LimitUSD - b.SpentUSD
+	}
+	if p != "" {
+		if b.ProviderSpentUSD == nil {
+			b.ProviderSpentUSD = make(map[Provider]float64)
+		}
+		b.ProviderSpentUSD[p] += cost
+	}
+	b.UsageCount++
+}
+
+// describeProviderBudgets lists the remaining budget of each capped
+// provider, e.g. "anthropic $0.00 of $0.00, openai $0.40 of $2.00"
+func (b *Budget) describeProviderBudgets() string {
+	providers := make([]string, 0, len(b.ProviderLimitUSD))
+	for p := range b.ProviderLimitUSD {
+		providers = append(providers, string(p))
+	}
+	sort.Strings(providers)
+
+	parts := make([]string, len(providers))
+	for i, p := range providers {
+		remaining, _ := b.ProviderRemaining(Provider(p))
+		parts[i] = fmt.Sprintf("%s $%.2f of $%.2f", p, math.Max(remaining, 0), b.ProviderLimitUSD[Provider(p)])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ErrBudgetExceeded is returned when a request does not fit the remaining
+// budget under the fail and downgrade policies
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// ErrBudgetPaused is returned when a request does not fit the remaining
+// budget under the pause policy. Callers should save their progress and stop,
+// then resume with a raised budget.
+var ErrBudgetPaused = errors.New("budget exceeded, paused")
+
+// validateBudgetPolicy checks an OnBudgetExceeded value
+func validateBudgetPolicy(policy string) error {
+	switch BudgetPolicy(policy) {
+	case "", BudgetPolicyFail, BudgetPolicyDowngrade, BudgetPolicyPause:
+		return nil
+	}
+	return fmt.Errorf("invalid on_budget_exceeded %q: must be fail, downgrade, or pause", policy)
+}
+
+// validateProviderBudgets checks the per-provider caps
+func validateProviderBudgets(budgets map[string]float64) error {
+	for name, limit := range budgets {
+		if name == "" {
+			return fmt.Errorf("provider_budgets has an empty provider name")
+		}
+		if limit < 0 || math.IsNaN(limit) {
+			return fmt.Errorf("provider budget for %s must be non-negative", name)
+		}
+	}
+	return nil
+}
+
+// withinProviderBudget reports whether a model's provider can cover the
+// model's estimated cost for a request
+func (r *Router) withinProviderBudget(req RoutingRequest, m *Model) bool {
+	if _, capped := r.budget.ProviderLimitUSD[m.Provider]; !capped {
+		return true
+	}
+	cost := (float64(r.estimateTokens(req, m)) / 1000000.0) * m.CostPerMToken
+	return r.budget.AllowsProvider(m.Provider, cost)
+}
+
+// overProviderBudgets reports whether provider caps are why no model can
+// serve a request
+func (r *Router) overProviderBudgets(ctx context.Context, req RoutingRequest) bool {
+	return len(r.budget.ProviderLimitUSD) > 0 && len(r.candidateModels(ctx, req, true, false)) > 0
+}
+
+// budgetPolicy returns the policy for a request, defaulting to fail
+func (r *Router) budgetPolicy(req RoutingRequest) BudgetPolicy {
+	if req.OnBudgetExceeded != "" {
+		return req.OnBudgetExceeded
+	}
+	if r.config.OnBudgetExceeded != "" {
+		return BudgetPolicy(r.config.OnBudgetExceeded)
+	}
+	return BudgetPolicyFail
+}
+
+// budgetExceeded applies the budget policy to a request that does not fit
+// the remaining budget. detail describes the shortfall.
+func (r *Router) budgetExceeded(ctx context.Context, req RoutingRequest, detail string) (*RoutingResult, error) {
+	switch r.budgetPolicy(req) {
+	case BudgetPolicyDowngrade:
+		if result := r.selectCheapestModel(ctx, req, detail); result != nil {
+			return result, nil
+		}
+		return nil, fmt.Errorf("%w: %s; no model fits the remaining budget ($%.2f)", ErrBudgetExceeded, detail, r.budget.RemainingUSD)
+	case BudgetPolicyPause:
+		return nil, fmt.Errorf("%w: %s; raise the budget and resume", ErrBudgetPaused, detail)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrBudgetExceeded, detail)
+}
+
+// selectCheapestModel returns the available model with the lowest estimated
+// cost that fits the request context and the remaining budget, whatever its
+// type or capability. Ties go to the more capable model, then to the lower
+// model ID. It returns nil when no model fits.
+func (r *Router) selectCheapestModel(ctx context.Context, req RoutingRequest, detail string) *RoutingResult {
+	var best *Model
+	var bestCost float64
+	for _, m := range r.candidateModels(ctx, RoutingRequest{ContextSize: req.ContextSize}, true, true) {
+		if req.ContextSize > 0 && m.ContextWindow < req.ContextSize {
+			continue
+		}
+		cost := (float64(r.estimateTokens(req, &m)) / 1000000.0) * m.CostPerMToken
+		if !r.budget.Allows(cost) || !r.budget.AllowsProvider(m.Provider, cost) {
+			continue
+		}
+
+		if best == nil ||
+			cost < bestCost ||
+			(cost == bestCost && m.CapabilityScore > best.CapabilityScore) ||
+			(cost == bestCost && m.CapabilityScore == best.CapabilityScore && m.ID < best.ID) {
+			model := m
+			best, bestCost = &model, cost
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	return &RoutingResult{
+		Model: best,
+		Reason: fmt.Sprintf("Downgraded to %s (%s), the cheapest model within the remaining budget ($%.2f): %s",
+			best.ID, best.Provider, r.budget.RemainingUSD, detail),
+		EstimatedCost:   bestCost,
+		EstimatedTokens: r.estimateTokens(req, best),
+	}
+}
@@ -0,0 +1,313 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+func TestRouter_UnlimitedBudget(t *testing.T) {
+	r, err := NewRouter(&RouterConfig{BudgetUnlimited: true})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	r.SetModelsAvailable(true)
+	ctx := context.Background()
+
+	// A large request with a large spend history never blocks on budget
+	for i := 0; i < 3; i++ {
+		result, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic", Complexity: 9, ContextSize: 150000})
+		if err != nil {
+			t.Fatalf("SelectModel() error = %v", err)
+		}
+		if err := r.RecordUsage(ctx, Usage{Model: result.Model.ID, Provider: result.Model.Provider, CostUSD: 50, Success: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, mode := range []SelectionMode{SelectionModeScored, SelectionModeCheapestAboveCapability} {
+		if _, err := r.SelectModel(ctx, RoutingRequest{SelectionMode: mode, MinCapability: 90, ContextSize: 150000}); err != nil {
+			t.Errorf("SelectModel(%q) error = %v", mode, err)
+		}
+	}
+
+	budget := r.GetBudget()
+	if budget.SpentUSD != 150 || budget.UsageCount != 3 {
+		t.Errorf("spend should still be tracked, got %+v", budget)
+	}
+	if !budget.Unlimited || budget.RemainingUSD != 0 {
+		t.Errorf("budget = %+v, want unlimited with no remaining amount", budget)
+	}
+	if r.GetUsageStats()["budget_unlimited"] != true {
+		t.Error("usage stats should report the unlimited budget")
+	}
+}
+
+func TestRouter_PositiveBudgetEnforced(t *testing.T) {
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 1})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	r.SetModelsAvailable(true)
+	ctx := context.Background()
+
+	if _, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"}); err != nil {
+		t.Fatalf("SelectModel() within budget error = %v", err)
+	}
+
+	if err := r.RecordUsage(ctx, Usage{Model: "gpt-4o", CostUSD: 1, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"})
+	if err == nil || !strings.Contains(err.Error(), "budget exhausted") {
+		t.Errorf("SelectModel() error = %v, want budget exhausted", err)
+	}
+
+	// A zero budget without BudgetUnlimited still allows no spend
+	zero, err := NewRouter(&RouterConfig{})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	zero.SetModelsAvailable(true)
+	if _, err := zero.SelectModel(ctx, RoutingRequest{}); err == nil {
+		t.Error("zero budget should block selection")
+	}
+}
+
+func TestBudgetUnlimitedConflictsWithLimit(t *testing.T) {
+	cfg := &RouterConfig{
+		Providers:       []ProviderConfig{{Name: ProviderAnthropic, APIKey: "key", Enabled: true}},
+		BudgetUSD:       10,
+		BudgetUnlimited: true,
+	}
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("ValidateConfig() should reject budget_usd with budget_unlimited")
+	}
+	if _, err := NewRouter(cfg); err == nil {
+		t.Error("NewRouter() should reject budget_usd with budget_unlimited")
+	}
+
+	cfg.BudgetUSD = 0
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+}
+
+func TestRouter_OnBudgetExceeded(t *testing.T) {
+	agentic := RoutingRequest{ModelHint: "agentic", Complexity: 5, Priority: "P1"}
+
+	tests := []struct {
+		name      string
+		config    string
+		request   BudgetPolicy
+		budgetUSD float64
+		spentUSD  float64
+		wantModel string
+		wantErr   error
+	}{
+		{name: "fail by default", budgetUSD: 0.01, wantErr: ErrBudgetExceeded},
+		{name: "fail", config: "fail", budgetUSD: 0.01, wantErr: ErrBudgetExceeded},
+		{name: "downgrade", config: "downgrade", budgetUSD: 0.01, wantModel: "tiny"},
+		{name: "request overrides config", config: "fail", request: BudgetPolicyDowngrade, budgetUSD: 0.01, wantModel: "tiny"},
+		{name: "downgrade with nothing affordable", config: "downgrade", budgetUSD: 0.0000001, wantErr: ErrBudgetExceeded},
+		{name: "pause", config: "pause", budgetUSD: 0.01, wantErr: ErrBudgetPaused},
+		{name: "pause when exhausted", config: "pause", budgetUSD: 1, spentUSD: 1, wantErr: ErrBudgetPaused},
+		{name: "within budget ignores policy", config: "pause", budgetUSD: 10, wantModel: "big"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := provider.NewRegistry()
+			if err := registry.Register("anthropic", &flakyProvider{healthy: true}, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+				t.Fatal(err)
+			}
+			r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: tt.budgetUSD, OnBudgetExceeded: tt.config}, registry)
+			if err != nil {
+				t.Fatalf("NewRouterWithProviders() error = %v", err)
+			}
+			r.models = []Model{
+				{ID: "big", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 15, CapabilityScore: 95, Available: true},
+				{ID: "tiny", Provider: ProviderAnthropic, Type: ModelTypeCheap, ContextWindow: 200000, CostPerMToken: 0.25, CapabilityScore: 50, Available: true},
+			}
+			if tt.spentUSD > 0 {
+				if err := r.RecordUsage(context.Background(), Usage{Model: "big", CostUSD: tt.spentUSD, Success: true}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			req := agentic
+			req.OnBudgetExceeded = tt.request
+			result, err := r.SelectModel(context.Background(), req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SelectModel() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr == ErrBudgetExceeded && errors.Is(err, ErrBudgetPaused) {
+					t.Errorf("SelectModel() error = %v, should not pause", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectModel() error = %v", err)
+			}
+			if result.Model.ID != tt.wantModel {
+				t.Errorf("model = %s, want %s", result.Model.ID, tt.wantModel)
+			}
+			if tt.wantModel == "tiny" && !strings.HasPrefix(result.Reason, "Downgraded to tiny") {
+				t.Errorf("reason = %q, want downgrade explained", result.Reason)
+			}
+		})
+	}
+}
+
+func TestRouter_GeneratePausesOnBudget(t *testing.T) {
+	prov := &flakyProvider{healthy: true}
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", prov, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 0.0000001}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{{ID: "big", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 15, CapabilityScore: 95, Available: true}}
+
+	req := GenerateRequest{Prompt: "Plan the release", ModelHint: "agentic", OnBudgetExceeded: BudgetPolicyPause}
+	if _, err := r.Generate(context.Background(), req); !errors.Is(err, ErrBudgetPaused) {
+		t.Errorf("Generate() error = %v, want ErrBudgetPaused", err)
+	}
+	if _, err := r.Stream(context.Background(), req); !errors.Is(err, ErrBudgetPaused) {
+		t.Errorf("Stream() error = %v, want ErrBudgetPaused", err)
+	}
+	if prov.lastRequest != nil {
+		t.Error("paused request should not reach the provider")
+	}
+}
+
+func TestValidateBudgetPolicy(t *testing.T) {
+	for _, policy := range []string{"", "fail", "downgrade", "pause"} {
+		if err := validateBudgetPolicy(policy); err != nil {
+			t.Errorf("validateBudgetPolicy(%q) error = %v", policy, err)
+		}
+	}
+	if err := validateBudgetPolicy("retry"); err == nil {
+		t.Error("validateBudgetPolicy(\"retry\") should fail")
+	}
+	if _, err := NewRouter(&RouterConfig{BudgetUSD: 1, OnBudgetExceeded: "retry"}); err == nil {
+		t.Error("NewRouter() should reject an unknown on_budget_exceeded")
+	}
+}
+
+func newProviderBudgetRouter(t *testing.T, config *RouterConfig) *Router {
+	t.Helper()
+	registry := provider.NewRegistry()
+	for _, name := range []string{"anthropic", "openai"} {
+		if err := registry.Register(name, &flakyProvider{healthy: true}, &provider.ProviderConfig{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := NewRouterWithProviders(config, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{
+		{ID: "claude", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 15, CapabilityScore: 95, Available: true},
+		{ID: "gpt", Provider: ProviderOpenAI, Type: ModelTypeAgentic, ContextWindow: 128000, CostPerMToken: 10, CapabilityScore: 90, Available: true},
+	}
+	return r
+}
+
+func TestRouter_ProviderBudgets(t *testing.T) {
+	ctx := context.Background()
+	r := newProviderBudgetRouter(t, &RouterConfig{
+		BudgetUSD:       10,
+		ProviderBudgets: map[string]float64{"anthropic": 0, "openai": 2},
+	})
+
+	// A zero cap disables the provider
+	result, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "gpt" {
+		t.Errorf("model = %s, want gpt while anthropic is capped at $0", result.Model.ID)
+	}
+	if ranked := r.RankModels(ctx, RoutingRequest{ModelHint: "agentic"}); len(ranked) != 1 {
+		t.Errorf("ranked %d models, want only the provider within budget", len(ranked))
+	}
+
+	if err := r.RecordUsage(ctx, Usage{Model: "gpt", Provider: ProviderOpenAI, CostUSD: 1.5, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if remaining, capped := r.GetBudget().ProviderRemaining(ProviderOpenAI); !capped || remaining != 0.5 {
+		t.Errorf("openai remaining = %v (capped %v), want 0.5", remaining, capped)
+	}
+
+	// A provider at its cap is excluded even though the global budget remains
+	if err := r.RecordUsage(ctx, Usage{Model: "gpt", Provider: ProviderOpenAI, CostUSD: 0.5, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"})
+	if !errors.Is(err, ErrBudgetExceeded) || !strings.Contains(err.Error(), "provider budget") {
+		t.Errorf("SelectModel() error = %v, want the provider budgets exceeded", err)
+	}
+	if r.GetBudget().Exhausted() {
+		t.Error("global budget should not be exhausted")
+	}
+
+	stats := r.GetUsageStats()
+	remaining, ok := stats["provider_budget_remaining"].(map[Provider]float64)
+	if !ok || remaining[ProviderAnthropic] != 0 || remaining[ProviderOpenAI] != 0 || len(remaining) != 2 {
+		t.Errorf("provider_budget_remaining = %v, want both providers at 0", stats["provider_budget_remaining"])
+	}
+	if spent := r.GetBudget().ProviderSpentUSD[ProviderOpenAI]; spent != 2 {
+		t.Errorf("openai spent = %v, want 2", spent)
+	}
+}
+
+func TestRouter_ProviderBudgetsWithGlobalBudget(t *testing.T) {
+	ctx := context.Background()
+
+	// Caps apply when the global budget is unlimited
+	r := newProviderBudgetRouter(t, &RouterConfig{BudgetUnlimited: true, ProviderBudgets: map[string]float64{"anthropic": 0}})
+	if result, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"}); err != nil || result.Model.ID != "gpt" {
+		t.Errorf("SelectModel() = %v, %v, want gpt", result, err)
+	}
+
+	// The global budget applies on top of an uncapped provider
+	r = newProviderBudgetRouter(t, &RouterConfig{BudgetUSD: 1, ProviderBudgets: map[string]float64{"anthropic": 0}})
+	if err := r.RecordUsage(ctx, Usage{Model: "gpt", Provider: ProviderOpenAI, CostUSD: 1, Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("SelectModel() error = %v, want the global budget exhausted", err)
+	}
+
+	// Pause applies to provider caps too
+	r = newProviderBudgetRouter(t, &RouterConfig{BudgetUSD: 10, OnBudgetExceeded: "pause", ProviderBudgets: map[string]float64{"anthropic": 0, "openai": 0}})
+	if _, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "agentic"}); !errors.Is(err, ErrBudgetPaused) {
+		t.Errorf("SelectModel() error = %v, want ErrBudgetPaused", err)
+	}
+}
+
+func TestValidateProviderBudgets(t *testing.T) {
+	cfg := &RouterConfig{
+		Providers:       []ProviderConfig{{Name: ProviderAnthropic, APIKey: "key", Enabled: true}},
+		BudgetUSD:       10,
+		ProviderBudgets: map[string]float64{"openai": -1},
+	}
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("ValidateConfig() should reject a negative provider budget")
+	}
+	if _, err := NewRouter(cfg); err == nil {
+		t.Error("NewRouter() should reject a negative provider budget")
+	}
+
+	cfg.ProviderBudgets = map[string]float64{"openai": 2, "anthropic": 0}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+const (
+	// DefaultCacheDir is where 'specular auto' caches responses, relative
+	// to the project root
+	DefaultCacheDir = ".specular/cache/llm"
+
+	// defaultCacheTTL applies when CacheTTLMs is 0
+	defaultCacheTTL = 24 * time.Hour
+)
+
+// responseCache stores provider responses on disk, one JSON file per
+// request hash. Several processes may share one cache directory.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheEntry is a cached response. Chunks holds the deltas of a streamed
+// response so a replay streams the same way; it is empty for responses
+// from Generate.
+type cacheEntry struct {
+	Created      time.Time           `json:"created"`
+	Model        string              `json:"model"`
+	Provider     Provider            `json:"provider"`
+	Content      string              `json:"content"`
+	Chunks       []string            `json:"chunks,omitempty"`
+	FinishReason string              `json:"finish_reason,omitempty"`
+	ToolCalls    []provider.ToolCall `json:"tool_calls,omitempty"`
+	InputTokens  int                 `json:"input_tokens,omitempty"`
+	OutputTokens int                 `json:"output_tokens,omitempty"`
+}
+
+// cacheKey identifies everything that determines a response: the model,
+// the request text, and the sampling parameters
+type cacheKey struct {
+	Model        string             `json:"model"`
+	Prompt       string             `json:"prompt"`
+	SystemPrompt string             `json:"system_prompt"`
+	Temperature  *float64           `json:"temperature"`
+	TopP         float64            `json:"top_p"`
+	MaxTokens    int                `json:"max_tokens"`
+	Seed         int64              `json:"seed"`
+	Tools        []provider.Tool    `json:"tools,omitempty"`
+	Context      []provider.Message `json:"context,omitempty"`
+}
+
+// get returns the entry stored under key if it has not expired. Expired
+// entries are removed.
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	path := filepath.Join(c.dir, key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if time.Since(entry.Created) > c.ttl {
+		_ = os.Remove(path) //#nosec G104 -- A stale entry is overwritten on the next put
+		return nil, false
+	}
+	return &entry, true
+}
+
+// put stores an entry under key. The entry is written to a temporary file
+// and renamed into place, so readers never see a partial entry.
+func (c *responseCache) put(key string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()           //#nosec G104 -- Write error takes precedence
+		_ = os.Remove(tmp.Name()) //#nosec G104
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name()) //#nosec G104
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+}
+
+// openCache enables the response cache when CacheDir is set and the cache
+// is not disabled
+func (r *Router) openCache() {
+	if r.config.CacheDir == "" || r.config.CacheDisabled {
+		return
+	}
+
+	ttl := time.Duration(r.config.CacheTTLMs) * time.Millisecond
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	r.cache = &responseCache{dir: r.config.CacheDir, ttl: ttl}
+}
+
+// cacheKeyFor returns the cache key of a request to a model, or "" when
+// the response should not be cached. Only deterministic requests, with an
+// explicit temperature of 0, are cached unless CacheAllTemperatures is set.
+// An unset temperature falls back to the provider default, which is not 0.
+func (r *Router) cacheKeyFor(req GenerateRequest, m *Model) string {
+	deterministic := req.Temperature != nil && *req.Temperature == 0
+	if r.cache == nil || (!deterministic && !r.config.CacheAllTemperatures) {
+		return ""
+	}
+
+	data, err := json.Marshal(cacheKey{
+		Model:        m.ID,
+		Prompt:       req.Prompt,
+		SystemPrompt: req.SystemPrompt,
+		Temperature:  req.Temperature,
+		TopP:         req.TopP,
+		MaxTokens:    req.MaxTokens,
+		Seed:         r.config.Seed,
+		Tools:        r.allowedTools(req.Tools),
+		Context:      req.Context,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedResponse serves a request from the cache. The hit is recorded as
+// usage with zero cost.
+func (r *Router) cachedResponse(ctx context.Context, req GenerateRequest, key string, startTime time.Time) (*cacheEntry, bool) {
+	if key == "" {
+		return nil, false
+	}
+	entry, ok := r.cache.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	usage := Usage{
+		Model:     entry.Model,
+		Provider:  entry.Provider,
+		LatencyMs: int(time.Since(startTime).Milliseconds()),
+		Timestamp: time.Now(),
+		TaskID:    req.TaskID,
+		FeatureID: req.FeatureID,
+		StepType:  req.StepType,
+		Success:   true,
+		Cached:    true,
+	}
+	_ = r.RecordUsage(ctx, usage) // Best effort usage recording
+	return entry, true
+}
+
+// storeResponse caches a successful response under key
+func (r *Router) storeResponse(key string, resp *GenerateResponse) {
+	if key == "" || resp.Error != "" {
+		return
+	}
+	_ = r.cache.put(key, &cacheEntry{ //#nosec G104 -- Caching is best effort
+		Created:      time.Now(),
+		Model:        resp.Model,
+		Provider:     resp.Provider,
+		Content:      resp.Content,
+		FinishReason: resp.FinishReason,
+		ToolCalls:    resp.ToolCalls,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+	})
+}
+
+// cachedGenerateResponse builds the response for a cache hit
+func cachedGenerateResponse(entry *cacheEntry, result *RoutingResult, startTime time.Time) *GenerateResponse {
+	return &GenerateResponse{
+		Content:         entry.Content,
+		Model:           entry.Model,
+		Provider:        entry.Provider,
+		InputTokens:     entry.InputTokens,
+		OutputTokens:    entry.OutputTokens,
+		Latency:         time.Since(startTime),
+		FinishReason:    entry.FinishReason,
+		SelectionReason: result.Reason + " (cached response)",
+		ToolCalls:       entry.ToolCalls,
+		Cached:          true,
+		Arm:             result.Arm,
+	}
+}
+
+// replayStream streams a cached response, chunk by chunk for a cached
+// stream and as a single chunk otherwise
+func replayStream(entry *cacheEntry) <-chan StreamChunk {
+	chunks := entry.Chunks
+	if len(chunks) == 0 {
+		chunks = []string{entry.Content}
+	}
+
+	out := make(chan StreamChunk, len(chunks))
+	content := ""
+	for i, delta := range chunks {
+		content += delta
+		out <- StreamChunk{Content: content, Delta: delta, Done: i == len(chunks)-1}
+	}
+	close(out)
+	return out
+}
+
+// cacheStream forwards a stream and caches it once it completes without
+// errors
+func (r *Router) cacheStream(key string, m *Model, in <-chan StreamChunk) <-chan StreamChunk {
+	if key == "" {
+		return in
+	}
+
+	out := make(chan StreamChunk, 10)
+	go func() {
+		defer close(out)
+		var chunks []string
+		var content string
+		failed, done := false, false
+		for chunk := range in {
+			out <- chunk
+			if chunk.Error != nil {
+				failed = true
+			}
+			if chunk.Delta != "" {
+				chunks = append(chunks, chunk.Delta)
+			}
+			content = chunk.Content
+			done = chunk.Done
+		}
+
+		// Streams cut short are not cached
+		if failed || !done || len(chunks) == 0 {
+			return
+		}
+		_ = r.cache.put(key, &cacheEntry{ //#nosec G104 -- Caching is best effort
+			Created:  time.Now(),
+			Model:    m.ID,
+			Provider: m.Provider,
+			Content:  content,
+			Chunks:   chunks,
+		})
+	}()
+	return out
+}
@@ -0,0 +1,224 @@
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// countingProvider answers every request with the same content, streamed
+// in two chunks, and counts the calls
+type countingProvider struct {
+	flakyProvider
+	generateCalls int
+	streamCalls   int
+}
+
+func (p *countingProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	p.generateCalls++
+	return &provider.GenerateResponse{Content: "spec: v1", TokensUsed: 1000, FinishReason: "stop"}, nil
+}
+
+func (p *countingProvider) Stream(ctx context.Context, req *provider.GenerateRequest) (<-chan provider.StreamChunk, error) {
+	p.streamCalls++
+	ch := make(chan provider.StreamChunk, 2)
+	ch <- provider.StreamChunk{Content: "spec: ", Delta: "spec: "}
+	ch <- provider.StreamChunk{Content: "spec: v1", Delta: "v1", Done: true, TokensUsed: 1000}
+	close(ch)
+	return ch, nil
+}
+
+func (p *countingProvider) GetCapabilities() *provider.ProviderCapabilities {
+	return &provider.ProviderCapabilities{SupportsStreaming: true}
+}
+
+func newCacheTestRouter(t *testing.T, config *RouterConfig) (*Router, *countingProvider) {
+	t.Helper()
+	prov := &countingProvider{flakyProvider: flakyProvider{healthy: true}}
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", prov, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(config, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{
+		{ID: "stable", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 3, CapabilityScore: 95, Available: true},
+	}
+	return r, prov
+}
+
+func TestGenerate_ResponseCache(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "llm")
+	r, prov := newCacheTestRouter(t, &RouterConfig{BudgetUSD: 10, CacheDir: cacheDir})
+	temperature := 0.0
+	req := GenerateRequest{Prompt: "write the spec", SystemPrompt: "You are a PM", ModelHint: "codegen", Temperature: &temperature}
+
+	first, err := r.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if first.Cached || first.CostUSD == 0 {
+		t.Errorf("first response = %+v, want a paid provider response", first)
+	}
+
+	second, err := r.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !second.Cached || second.CostUSD != 0 || second.Content != "spec: v1" || second.Model != "stable" {
+		t.Errorf("second response = %+v, want the cached content at no cost", second)
+	}
+	if prov.generateCalls != 1 {
+		t.Errorf("provider calls = %d, want 1", prov.generateCalls)
+	}
+
+	stats := r.GetUsageStats()
+	if stats["cache_hits"] != 1 || stats["total_requests"] != 2 {
+		t.Errorf("stats = %v, want 2 requests with 1 cache hit", stats)
+	}
+	if r.GetBudget().SpentUSD != first.CostUSD {
+		t.Errorf("spent = %v, want only the first request's cost %v", r.GetBudget().SpentUSD, first.CostUSD)
+	}
+
+	// A different prompt misses
+	req.Prompt = "write another spec"
+	if resp, err := r.Generate(context.Background(), req); err != nil || resp.Cached {
+		t.Errorf("Generate(other prompt) = %+v, %v, want a provider response", resp, err)
+	}
+
+	// A new router reads the entries from disk
+	r2, prov2 := newCacheTestRouter(t, &RouterConfig{BudgetUSD: 10, CacheDir: cacheDir})
+	if resp, err := r2.Generate(context.Background(), req); err != nil || !resp.Cached || prov2.generateCalls != 0 {
+		t.Errorf("Generate() on a new router = %+v, %v, want a cache hit", resp, err)
+	}
+}
+
+func TestGenerate_ResponseCacheSkipped(t *testing.T) {
+	zero, warm := 0.0, 0.7
+	tests := []struct {
+		name        string
+		config      RouterConfig
+		temperature *float64
+		wantCalls   int
+	}{
+		{name: "no cache dir", temperature: &zero, wantCalls: 2},
+		{name: "disabled", config: RouterConfig{CacheDisabled: true}, temperature: &zero, wantCalls: 2},
+		{name: "unset temperature", wantCalls: 2},
+		{name: "non-zero temperature", temperature: &warm, wantCalls: 2},
+		{name: "all temperatures", config: RouterConfig{CacheAllTemperatures: true}, temperature: &warm, wantCalls: 1},
+		{name: "all temperatures unset", config: RouterConfig{CacheAllTemperatures: true}, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.BudgetUSD = 10
+			if tt.name != "no cache dir" {
+				config.CacheDir = t.TempDir()
+			}
+			r, prov := newCacheTestRouter(t, &config)
+
+			req := GenerateRequest{Prompt: "write the spec", Temperature: tt.temperature}
+			for i := 0; i < 2; i++ {
+				if _, err := r.Generate(context.Background(), req); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+			}
+			if prov.generateCalls != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", prov.generateCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	cache := &responseCache{dir: t.TempDir(), ttl: time.Hour}
+	if err := cache.put("fresh", &cacheEntry{Created: time.Now(), Content: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.put("stale", &cacheEntry{Created: time.Now().Add(-2 * time.Hour), Content: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry, ok := cache.get("fresh"); !ok || entry.Content != "a" {
+		t.Errorf("get(fresh) = %+v, %v, want the entry", entry, ok)
+	}
+	if _, ok := cache.get("stale"); ok {
+		t.Error("get(stale) should miss")
+	}
+	if _, err := os.Stat(filepath.Join(cache.dir, "stale.json")); !os.IsNotExist(err) {
+		t.Error("stale entry should be removed")
+	}
+	if _, ok := cache.get("missing"); ok {
+		t.Error("get(missing) should miss")
+	}
+}
+
+// collectStream drains a stream into its deltas and final content
+func collectStream(t *testing.T, ch <-chan StreamChunk) ([]string, string) {
+	t.Helper()
+	var deltas []string
+	var content string
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatalf("stream error = %v", chunk.Error)
+		}
+		deltas = append(deltas, chunk.Delta)
+		content = chunk.Content
+	}
+	return deltas, content
+}
+
+func TestStream_ReplaysFromCache(t *testing.T) {
+	r, prov := newCacheTestRouter(t, &RouterConfig{BudgetUSD: 10, CacheDir: t.TempDir()})
+	temperature := 0.0
+	req := GenerateRequest{Prompt: "write the spec", Temperature: &temperature}
+
+	ch, err := r.Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	firstDeltas, _ := collectStream(t, ch)
+
+	// The cache is written once the forwarded stream completes
+	deadline := time.Now().Add(time.Second)
+	for {
+		entries, _ := os.ReadDir(r.cache.dir)
+		if len(entries) > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ch, err = r.Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	deltas, content := collectStream(t, ch)
+	if prov.streamCalls != 1 {
+		t.Errorf("provider stream calls = %d, want 1", prov.streamCalls)
+	}
+	if strings.Join(deltas, "|") != strings.Join(firstDeltas, "|") || content != "spec: v1" {
+		t.Errorf("replayed deltas = %q (content %q), want %q", deltas, content, firstDeltas)
+	}
+
+	// Generate is served from the streamed entry too
+	resp, err := r.Generate(context.Background(), req)
+	if err != nil || !resp.Cached || resp.Content != "spec: v1" || prov.generateCalls != 0 {
+		t.Errorf("Generate() = %+v, %v, want the cached stream content", resp, err)
+	}
+}
+
+func TestReplayStream_GenerateEntry(t *testing.T) {
+	deltas, content := collectStream(t, replayStream(&cacheEntry{Content: "whole"}))
+	if len(deltas) != 1 || content != "whole" {
+		t.Errorf("replay = %q (content %q), want one chunk with the content", deltas, content)
+	}
+}
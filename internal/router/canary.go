@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Arms of a canary routing decision, recorded in Usage.Arm
+const (
+	ArmControl = "control" // The model the selection strategy ranked first
+	ArmCanary  = "canary"  // A model chosen by RouterConfig.CanaryWeights
+)
+
+// canarySampler draws canary routing decisions. The zero value is ready to
+// use; it is seeded on the first draw.
+type canarySampler struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// draw returns a number in [0, 1). A non-zero seed makes the sequence of
+// draws reproducible.
+func (s *canarySampler) draw(seed int64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rng == nil {
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		s.rng = rand.New(rand.NewSource(seed)) // #nosec G404 -- traffic splitting, not security
+	}
+	return s.rng.Float64()
+}
+
+// validateCanaryWeights checks that each weight is a probability and that
+// together they leave a share for the control arm
+func validateCanaryWeights(weights map[string]float64) error {
+	total := 0.0
+	for id, weight := range weights {
+		if weight < 0 || weight > 1 {
+			return fmt.Errorf("canary weight for %s must be between 0 and 1, got %g", id, weight)
+		}
+		total += weight
+	}
+	if total > 1 {
+		return fmt.Errorf("canary weights sum to %g, must not exceed 1", total)
+	}
+	return nil
+}
+
+// selectCanary picks the model for a canary routing decision. Each eligible
+// canary model wins with its configured weight; the rest of the draw goes to
+// the control model. A canary model is eligible when it is a candidate for
+// the request, is not the control model, and fits the remaining budget.
+func (r *Router) selectCanary(candidates []Model, req RoutingRequest, control *Model) (*Model, string) {
+	weights := r.config.CanaryWeights
+	if len(weights) == 0 {
+		return control, ""
+	}
+
+	// Walk the arms in a fixed order so seeded runs repeat
+	ids := make([]string, 0, len(weights))
+	for id := range weights {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	draw := r.canary.draw(r.config.Seed)
+	for _, id := range ids {
+		weight := weights[id]
+		if weight <= 0 || id == control.ID {
+			continue
+		}
+
+		var canary *Model
+		for i := range candidates {
+			if candidates[i].ID == id {
+				canary = &candidates[i]
+				break
+			}
+		}
+		if canary == nil {
+			continue
+		}
+		if !r.budget.Allows((float64(r.estimateTokens(req, canary)) / 1000000.0) * canary.CostPerMToken) {
+			continue
+		}
+
+		if draw < weight {
+			return canary, ArmCanary
+		}
+		draw -= weight
+	}
+
+	return control, ArmControl
+}
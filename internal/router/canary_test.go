@@ -0,0 +1,157 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// newCanaryTestRouter returns a router with a healthy anthropic provider, a
+// control model, and a cheaper canary model
+func newCanaryTestRouter(t *testing.T, config *RouterConfig) *Router {
+	t.Helper()
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", &flakyProvider{healthy: true}, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(config, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{
+		{ID: "stable", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 3, CapabilityScore: 95, Available: true},
+		{ID: "candidate", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 2, CapabilityScore: 80, Available: true},
+		{ID: "fast", Provider: ProviderAnthropic, Type: ModelTypeFast, ContextWindow: 200000, CostPerMToken: 1, CapabilityScore: 60, Available: true},
+	}
+	return r
+}
+
+// canarySelections returns the arms of n selections
+func canarySelections(t *testing.T, r *Router, req RoutingRequest, n int) []string {
+	t.Helper()
+	arms := make([]string, n)
+	for i := range arms {
+		result, err := r.SelectModel(context.Background(), req)
+		if err != nil {
+			t.Fatalf("SelectModel() error = %v", err)
+		}
+		switch {
+		case result.Arm == ArmCanary && result.Model.ID == "stable":
+			t.Fatalf("canary arm selected the control model")
+		case result.Arm == ArmControl && result.Model.ID != "stable":
+			t.Fatalf("control arm selected %s", result.Model.ID)
+		}
+		arms[i] = result.Arm
+	}
+	return arms
+}
+
+func TestRouter_CanaryWeights(t *testing.T) {
+	codegen := RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1"}
+
+	tests := []struct {
+		name       string
+		weights    map[string]float64
+		req        RoutingRequest
+		wantCanary [2]int // inclusive bounds on canary selections out of 1000
+	}{
+		{name: "splits traffic by weight", weights: map[string]float64{"candidate": 0.1}, req: codegen, wantCanary: [2]int{70, 130}},
+		{name: "full weight", weights: map[string]float64{"candidate": 1}, req: codegen, wantCanary: [2]int{1000, 1000}},
+		{name: "zero weight", weights: map[string]float64{"candidate": 0}, req: codegen},
+		{name: "canary not a candidate", weights: map[string]float64{"fast": 0.5}, req: codegen},
+		{name: "canary is the control model", weights: map[string]float64{"stable": 0.5}, req: codegen},
+		{name: "unknown model", weights: map[string]float64{"missing": 0.5}, req: codegen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCanaryTestRouter(t, &RouterConfig{BudgetUSD: 100, CanaryWeights: tt.weights, Seed: 42})
+
+			canary := 0
+			for _, arm := range canarySelections(t, r, tt.req, 1000) {
+				if arm == ArmCanary {
+					canary++
+				}
+			}
+			if canary < tt.wantCanary[0] || canary > tt.wantCanary[1] {
+				t.Errorf("canary selections = %d, want %d-%d", canary, tt.wantCanary[0], tt.wantCanary[1])
+			}
+		})
+	}
+}
+
+func TestRouter_CanarySeeded(t *testing.T) {
+	req := RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1"}
+	config := func() *RouterConfig {
+		return &RouterConfig{BudgetUSD: 100, CanaryWeights: map[string]float64{"candidate": 0.5}, Seed: 7}
+	}
+
+	first := canarySelections(t, newCanaryTestRouter(t, config()), req, 50)
+	second := canarySelections(t, newCanaryTestRouter(t, config()), req, 50)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("selection %d = %s, then %s; want the same arms for the same seed", i, first[i], second[i])
+		}
+	}
+}
+
+func TestRouter_CanaryRespectsBudget(t *testing.T) {
+	r := newCanaryTestRouter(t, &RouterConfig{BudgetUSD: 0.05, CanaryWeights: map[string]float64{"candidate": 1}})
+
+	// The control model is cheap enough; the canary model is not
+	r.models[0].CostPerMToken = 1
+	r.models[1].CostPerMToken = 100
+
+	for _, arm := range canarySelections(t, r, RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1"}, 10) {
+		if arm != ArmControl {
+			t.Fatalf("arm = %s, want control when the canary exceeds the budget", arm)
+		}
+	}
+}
+
+func TestRouter_CanaryArmRecordedInUsage(t *testing.T) {
+	r := newCanaryTestRouter(t, &RouterConfig{BudgetUSD: 100, CanaryWeights: map[string]float64{"candidate": 1}})
+
+	resp, err := r.Generate(context.Background(), GenerateRequest{Prompt: "Write a test", ModelHint: "codegen"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Model != "candidate" || resp.Arm != ArmCanary {
+		t.Errorf("response model = %s, arm = %q, want candidate on the canary arm", resp.Model, resp.Arm)
+	}
+
+	usage := r.usage
+	if len(usage) != 1 || usage[0].Arm != ArmCanary {
+		t.Fatalf("usage = %+v, want one canary record", usage)
+	}
+	if got := r.GetUsageStats()["arm_usage"].(map[string]int)[ArmCanary]; got != 1 {
+		t.Errorf("arm_usage[canary] = %d, want 1", got)
+	}
+}
+
+func TestValidateCanaryWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		wantErr bool
+	}{
+		{name: "none", weights: nil},
+		{name: "valid", weights: map[string]float64{"a": 0.1, "b": 0.4}},
+		{name: "sum of one", weights: map[string]float64{"a": 0.5, "b": 0.5}},
+		{name: "negative", weights: map[string]float64{"a": -0.1}, wantErr: true},
+		{name: "above one", weights: map[string]float64{"a": 1.5}, wantErr: true},
+		{name: "sum above one", weights: map[string]float64{"a": 0.6, "b": 0.6}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCanaryWeights(tt.weights); (err != nil) != tt.wantErr {
+				t.Errorf("validateCanaryWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := NewRouter(&RouterConfig{BudgetUSD: 10, CanaryWeights: tt.weights}); (err != nil) != tt.wantErr {
+				t.Errorf("NewRouter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
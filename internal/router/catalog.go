@@ -0,0 +1,156 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// modelCatalog is the YAML format for RouterConfig.ModelCatalogPath
+type modelCatalog struct {
+	// ReplaceBuiltins drops the built-in models so only the file's models
+	// are used
+	ReplaceBuiltins bool           `yaml:"replace_builtins"`
+	Models          []catalogModel `yaml:"models"`
+}
+
+// catalogModel defines or overrides one model. For a built-in ID, only the
+// fields that are set replace the built-in values.
+type catalogModel struct {
+	ID              string    `yaml:"id"`
+	Provider        Provider  `yaml:"provider"`
+	Name            string    `yaml:"name"`
+	Family          string    `yaml:"family"`
+	Type            ModelType `yaml:"type"`
+	ContextWindow   int       `yaml:"context_window"`
+	CostPerMToken   *float64  `yaml:"cost_per_mtoken"`
+	MaxLatencyMs    int       `yaml:"max_latency_ms"`
+	CapabilityScore *float64  `yaml:"capability_score"`
+	Available       *bool     `yaml:"available"`
+}
+
+// LoadModelCatalog returns the built-in models merged with the models
+// defined in the YAML file at path. Models with a built-in ID override it;
+// other models are added. An empty path or a missing file returns the
+// built-in models.
+func LoadModelCatalog(path string) ([]Model, error) {
+	if path == "" {
+		return GetAvailableModels(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return GetAvailableModels(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read model catalog: %w", err)
+	}
+
+	var catalog modelCatalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parse model catalog %s: %w", path, err)
+	}
+
+	models, err := mergeModelCatalog(GetAvailableModels(), catalog)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model catalog %s: %w", path, err)
+	}
+	return models, nil
+}
+
+// mergeModelCatalog applies a catalog to the built-in models and validates
+// the result
+func mergeModelCatalog(builtins []Model, catalog modelCatalog) ([]Model, error) {
+	var models []Model
+	if !catalog.ReplaceBuiltins {
+		models = builtins
+	}
+
+	index := make(map[string]int, len(models))
+	for i, m := range models {
+		index[m.ID] = i
+	}
+
+	seen := make(map[string]bool, len(catalog.Models))
+	for _, entry := range catalog.Models {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("model id is required")
+		}
+		if seen[entry.ID] {
+			return nil, fmt.Errorf("duplicate model id %q", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		if i, ok := index[entry.ID]; ok {
+			entry.applyTo(&models[i])
+			continue
+		}
+
+		m := Model{ID: entry.ID, Name: entry.ID, Available: true}
+		entry.applyTo(&m)
+		index[m.ID] = len(models)
+		models = append(models, m)
+	}
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models defined")
+	}
+	for _, m := range models {
+		if err := validateCatalogModel(m); err != nil {
+			return nil, fmt.Errorf("model %s: %w", m.ID, err)
+		}
+	}
+	return models, nil
+}
+
+// applyTo copies the fields set in the entry onto m
+func (e catalogModel) applyTo(m *Model) {
+	if e.Provider != "" {
+		m.Provider = e.Provider
+	}
+	if e.Name != "" {
+		m.Name = e.Name
+	}
+	if e.Family != "" {
+		m.Family = e.Family
+	}
+	if e.Type != "" {
+		m.Type = e.Type
+	}
+	if e.ContextWindow != 0 {
+		m.ContextWindow = e.ContextWindow
+	}
+	if e.CostPerMToken != nil {
+		m.CostPerMToken = *e.CostPerMToken
+	}
+	if e.MaxLatencyMs != 0 {
+		m.MaxLatencyMs = e.MaxLatencyMs
+	}
+	if e.CapabilityScore != nil {
+		m.CapabilityScore = *e.CapabilityScore
+	}
+	if e.Available != nil {
+		m.Available = *e.Available
+	}
+}
+
+// validateCatalogModel checks that a merged model is complete and sane
+func validateCatalogModel(m Model) error {
+	switch {
+	case m.Provider == "":
+		return fmt.Errorf("provider is required")
+	case m.Type == "":
+		return fmt.Errorf("type is required")
+	case m.ContextWindow <= 0:
+		return fmt.Errorf("context_window must be positive")
+	case m.CostPerMToken < 0:
+		return fmt.Errorf("cost_per_mtoken must not be negative")
+	case m.MaxLatencyMs < 0:
+		return fmt.Errorf("max_latency_ms must not be negative")
+	case m.CapabilityScore < 0 || m.CapabilityScore > 100:
+		return fmt.Errorf("capability_score must be between 0 and 100")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package router
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCatalog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadModelCatalog_MergesWithBuiltins(t *testing.T) {
+	path := writeCatalog(t, `models:
+  - id: claude-haiku-3.5
+    cost_per_mtoken: 0.25
+  - id: claude-haiku-4
+    provider: anthropic
+    name: claude-haiku-4-20260101
+    family: claude-4
+    type: fast
+    context_window: 200000
+    cost_per_mtoken: 0.5
+    max_latency_ms: 1500
+    capability_score: 85
+`)
+
+	models, err := LoadModelCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadModelCatalog() error = %v", err)
+	}
+
+	if len(models) != len(GetAvailableModels())+1 {
+		t.Errorf("catalog has %d models, want the built-ins plus one", len(models))
+	}
+
+	byID := make(map[string]Model)
+	for _, m := range models {
+		byID[m.ID] = m
+	}
+
+	haiku := byID["claude-haiku-3.5"]
+	if haiku.CostPerMToken != 0.25 {
+		t.Errorf("overridden cost = %v, want 0.25", haiku.CostPerMToken)
+	}
+	if haiku.Name != "claude-3-5-haiku-20241022" || haiku.ContextWindow != 200000 {
+		t.Errorf("fields not in the file should keep built-in values, got %+v", haiku)
+	}
+
+	added := byID["claude-haiku-4"]
+	if added.Provider != ProviderAnthropic || added.Type != ModelTypeFast || !added.Available {
+		t.Errorf("added model = %+v", added)
+	}
+}
+
+func TestLoadModelCatalog_ReplaceBuiltins(t *testing.T) {
+	path := writeCatalog(t, `replace_builtins: true
+models:
+  - id: local-coder
+    provider: local
+    type: codegen
+    context_window: 32000
+    cost_per_mtoken: 0
+    capability_score: 70
+`)
+
+	models, err := LoadModelCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadModelCatalog() error = %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "local-coder" || models[0].Name != "local-coder" {
+		t.Errorf("models = %+v, want only local-coder", models)
+	}
+}
+
+func TestLoadModelCatalog_MissingFileUsesBuiltins(t *testing.T) {
+	models, err := LoadModelCatalog(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadModelCatalog() error = %v", err)
+	}
+	if len(models) != len(GetAvailableModels()) {
+		t.Errorf("got %d models, want the built-in catalog", len(models))
+	}
+}
+
+func TestLoadModelCatalog_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "duplicate id",
+			content: `models:
+  - id: gpt-4o
+    cost_per_mtoken: 2
+  - id: gpt-4o
+    cost_per_mtoken: 3
+`,
+			wantErr: "duplicate model id",
+		},
+		{
+			name: "negative cost",
+			content: `models:
+  - id: gpt-4o
+    cost_per_mtoken: -1
+`,
+			wantErr: "cost_per_mtoken",
+		},
+		{
+			name: "new model missing fields",
+			content: `models:
+  - id: mystery
+    cost_per_mtoken: 1
+`,
+			wantErr: "provider is required",
+		},
+		{
+			name:    "malformed yaml",
+			content: "models: [",
+			wantErr: "parse model catalog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadModelCatalog(writeCatalog(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadModelCatalog() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRouter_ModelCatalogPath(t *testing.T) {
+	path := writeCatalog(t, `models:
+  - id: gpt-4o-mini
+    cost_per_mtoken: 0.1
+`)
+
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 10, ModelCatalogPath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	if m := r.findModel("gpt-4o-mini"); m == nil || m.CostPerMToken != 0.1 {
+		t.Errorf("router model = %+v, want the catalog price", m)
+	}
+
+	if _, err := NewRouter(&RouterConfig{ModelCatalogPath: writeCatalog(t, "models: [")}); err == nil {
+		t.Error("NewRouter() should fail on an invalid catalog")
+	}
+}
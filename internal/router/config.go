@@ -3,6 +3,7 @@ package router
 import (
 	"fmt"
 	"os"
+	"path"
 
 	"gopkg.in/yaml.v3"
 )
@@ -65,17 +66,16 @@ func DefaultConfig() *RouterConfig {
 		EnableContextValidation: true,     // Validate context fits in model window
 		AutoTruncate:            false,    // Error out by default (safer)
 		TruncationStrategy:      "oldest", // Remove oldest context messages first
+		HealthCooldownMs:        30000,    // Re-probe unhealthy providers after 30 seconds
+		BreakerThreshold:        3,        // Open a provider's circuit after 3 consecutive failures
+		BreakerWindowMs:         60000,    // that are at most a minute apart
 	}
 }
 
 // ValidateConfig validates a router configuration
 func ValidateConfig(config *RouterConfig) error {
-	if config.BudgetUSD < 0 {
-		return fmt.Errorf("budget must be non-negative")
-	}
-
-	if config.MaxLatencyMs < 0 {
-		return fmt.Errorf("max latency must be non-negative")
+	if err := validateSettings(config); err != nil {
+		return err
 	}
 
 	// Check that at least one provider is enabled
@@ -101,6 +101,52 @@ func ValidateConfig(config *RouterConfig) error {
 	return nil
 }
 
+// validateSettings validates the routing settings of a configuration. Unlike
+// ValidateConfig it does not require providers, which a router can get from
+// its registry instead.
+func validateSettings(config *RouterConfig) error {
+	if config.BudgetUSD < 0 {
+		return fmt.Errorf("budget must be non-negative")
+	}
+
+	if config.BudgetUnlimited && config.BudgetUSD > 0 {
+		return fmt.Errorf("budget_usd and budget_unlimited cannot both be set")
+	}
+
+	if config.MaxLatencyMs < 0 {
+		return fmt.Errorf("max latency must be non-negative")
+	}
+
+	if config.BreakerThreshold < 0 || config.BreakerWindowMs < 0 {
+		return fmt.Errorf("breaker threshold and window must be non-negative")
+	}
+
+	// Validate family patterns
+	for _, family := range append(append([]string{}, config.AllowFamilies...), config.DenyFamilies...) {
+		if _, err := path.Match(family, ""); err != nil {
+			return fmt.Errorf("invalid model family pattern %q: %w", family, err)
+		}
+	}
+
+	if _, err := LookupStrategy(config.Strategy); err != nil {
+		return err
+	}
+
+	if err := validateCanaryWeights(config.CanaryWeights); err != nil {
+		return err
+	}
+
+	if err := validateBudgetPolicy(config.OnBudgetExceeded); err != nil {
+		return err
+	}
+
+	if err := validateProviderBudgets(config.ProviderBudgets); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // SaveConfig saves router configuration to a YAML file
 func SaveConfig(config *RouterConfig, path string) error {
 	data, err := yaml.Marshal(config)
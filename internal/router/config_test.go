@@ -311,6 +311,22 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid family pattern",
+			config: &RouterConfig{
+				Providers: []ProviderConfig{
+					{
+						Name:    ProviderAnthropic,
+						APIKey:  "test-key",
+						Enabled: true,
+					},
+				},
+				BudgetUSD:     10.0,
+				AllowFamilies: []string{"claude-["},
+			},
+			wantErr:     true,
+			errContains: "invalid model family pattern",
+		},
 		{
 			name: "negative budget",
 			config: &RouterConfig{
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"unicode"
@@ -119,12 +120,39 @@ const (
 
 	// TruncateProportional reduces both prompt and context proportionally
 	TruncateProportional TruncationStrategy = "proportional"
+
+	// TruncateSummarize replaces the oldest context messages with a summary
+	// written by a model. Without a summarizer it behaves like TruncateOldest.
+	TruncateSummarize TruncationStrategy = "summarize"
 )
 
+// summaryTokenBudget caps the size of the summary note in tokens
+const summaryTokenBudget = 512
+
+// ContextSummarizer condenses messages into a summary of at most maxTokens
+// tokens
+type ContextSummarizer func(ctx context.Context, messages []provider.Message, maxTokens int) (string, error)
+
+// summarizingKey marks the context of a summarization request
+type summarizingKey struct{}
+
+// withSummarizing marks ctx as belonging to a summarization request, so the
+// request's own context is never summarized
+func withSummarizing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, summarizingKey{}, true)
+}
+
+// isSummarizing reports whether ctx belongs to a summarization request
+func isSummarizing(ctx context.Context) bool {
+	summarizing, _ := ctx.Value(summarizingKey{}).(bool)
+	return summarizing
+}
+
 // ContextTruncator handles truncating requests to fit context windows
 type ContextTruncator struct {
-	counter  *TokenCounter
-	strategy TruncationStrategy
+	counter    *TokenCounter
+	strategy   TruncationStrategy
+	summarizer ContextSummarizer
 }
 
 // NewContextTruncator creates a new context truncator with the given strategy
@@ -135,9 +163,20 @@ func NewContextTruncator(strategy TruncationStrategy) *ContextTruncator {
 	}
 }
 
+// SetSummarizer sets the summarizer used by the TruncateSummarize strategy
+func (ct *ContextTruncator) SetSummarizer(summarizer ContextSummarizer) {
+	ct.summarizer = summarizer
+}
+
 // TruncateRequest truncates a request to fit within the model's context window
 // Returns a new request (does not modify original) and whether truncation occurred
 func (ct *ContextTruncator) TruncateRequest(req *GenerateRequest, model *Model) (*GenerateRequest, bool, error) {
+	return ct.TruncateRequestContext(context.Background(), req, model)
+}
+
+// TruncateRequestContext is TruncateRequest with a context for the
+// summarizer
+func (ct *ContextTruncator) TruncateRequestContext(ctx context.Context, req *GenerateRequest, model *Model) (*GenerateRequest, bool, error) {
 	inputTokens := ct.counter.EstimateRequestTokens(req)
 	outputTokens := req.MaxTokens
 	if outputTokens == 0 {
@@ -176,6 +215,10 @@ func (ct *ContextTruncator) TruncateRequest(req *GenerateRequest, model *Model)
 		Context:      make([]provider.Message, len(req.Context)),
 		ContextSize:  req.ContextSize,
 		TaskID:       req.TaskID,
+		FeatureID:    req.FeatureID,
+		StepType:     req.StepType,
+
+		SummarizedMessages: req.SummarizedMessages,
 	}
 	copy(truncated.Context, req.Context)
 
@@ -189,6 +232,8 @@ func (ct *ContextTruncator) TruncateRequest(req *GenerateRequest, model *Model)
 		ct.truncateAllContext(truncated, tokensToRemove)
 	case TruncateProportional:
 		ct.truncateProportional(truncated, tokensToRemove)
+	case TruncateSummarize:
+		ct.summarizeOldestMessages(ctx, truncated, tokensToRemove)
 	default:
 		return nil, false, fmt.Errorf("unknown truncation strategy: %s", ct.strategy)
 	}
@@ -218,6 +263,50 @@ func (ct *ContextTruncator) truncateOldestMessages(req *GenerateRequest, tokensT
 	req.Context = newContext
 }
 
+// summarizeOldestMessages replaces the oldest context messages with a
+// summary note. Enough messages are dropped to make room for the note. If
+// the summary cannot be made, the messages are dropped as with
+// truncateOldestMessages.
+func (ct *ContextTruncator) summarizeOldestMessages(ctx context.Context, req *GenerateRequest, tokensToRemove int) {
+	// A summarization request's own context is truncated, never summarized
+	if ct.summarizer == nil || isSummarizing(ctx) {
+		ct.truncateOldestMessages(req, tokensToRemove)
+		return
+	}
+
+	// The note costs its budget plus message overhead
+	target := tokensToRemove + summaryTokenBudget + 5
+	removed := 0
+	split := 0
+	for split < len(req.Context) && removed < target {
+		removed += ct.counter.EstimateTokens(req.Context[split].Content) + 5
+		split++
+	}
+
+	// Summarizing must free more than the note costs
+	if removed < target {
+		ct.truncateOldestMessages(req, tokensToRemove)
+		return
+	}
+
+	summary, err := ct.summarizer(withSummarizing(ctx), req.Context[:split], summaryTokenBudget)
+	summary = strings.TrimSpace(summary)
+	if err != nil || summary == "" {
+		ct.truncateOldestMessages(req, tokensToRemove)
+		return
+	}
+
+	note := fmt.Sprintf("Summary of %d earlier messages:\n%s", split, summary)
+	if maxChars := int(float64(summaryTokenBudget) * ct.counter.CharsPerToken); len(note) > maxChars {
+		note = note[:maxChars] + "...[truncated]"
+	}
+
+	kept := make([]provider.Message, 0, len(req.Context)-split+1)
+	kept = append(kept, provider.Message{Role: "system", Content: note})
+	req.Context = append(kept, req.Context[split:]...)
+	req.SummarizedMessages += split
+}
+
 // truncatePrompt truncates the main prompt to fit
 func (ct *ContextTruncator) truncatePrompt(req *GenerateRequest, tokensToRemove int) {
 	promptTokens := ct.counter.EstimateTokens(req.Prompt)
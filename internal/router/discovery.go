@@ -0,0 +1,177 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOllamaURL is the address of a local Ollama server
+const DefaultOllamaURL = "http://localhost:11434"
+
+// ollamaProbeTimeout bounds a discovery probe so an absent server doesn't
+// delay startup
+const ollamaProbeTimeout = 2 * time.Second
+
+// ollamaTags is the response of Ollama's /api/tags endpoint
+type ollamaTags struct {
+	Models []struct {
+		Name    string `json:"name"`
+		Details struct {
+			Family        string `json:"family"`
+			ParameterSize string `json:"parameter_size"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+// DiscoverOllamaModels lists the models installed on the Ollama server at
+// baseURL as local, free models. Models are named by their Ollama tag, with
+// a ":latest" suffix dropped from the ID.
+func DiscoverOllamaModels(ctx context.Context, baseURL string) ([]Model, error) {
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ollamaProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ollama URL %s: %w", baseURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama not reachable at %s: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama at %s returned %s", baseURL, resp.Status)
+	}
+
+	var tags ollamaTags
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("parse ollama model list: %w", err)
+	}
+
+	models := make([]Model, 0, len(tags.Models))
+	for _, tag := range tags.Models {
+		if tag.Name == "" {
+			continue
+		}
+		models = append(models, ollamaModel(tag.Name, tag.Details.Family, tag.Details.ParameterSize))
+	}
+	return models, nil
+}
+
+// ollamaModel describes an installed Ollama model. Coding models are typed
+// for codegen; the capability score grows with the parameter count.
+func ollamaModel(name, family, parameterSize string) Model {
+	modelType := ModelTypeFast
+	if strings.Contains(strings.ToLower(name), "code") {
+		modelType = ModelTypeCodegen
+	}
+
+	return Model{
+		ID:              strings.TrimSuffix(name, ":latest"),
+		Provider:        ProviderLocal,
+		Name:            name,
+		Family:          family,
+		Type:            modelType,
+		ContextWindow:   8192,
+		CostPerMToken:   0.00, // Free (local)
+		MaxLatencyMs:    4000,
+		CapabilityScore: ollamaCapabilityScore(parameterSize),
+		Available:       true,
+	}
+}
+
+// ollamaCapabilityScore maps a parameter size such as "7.6B" to a default
+// capability score in line with the built-in local models
+func ollamaCapabilityScore(parameterSize string) float64 {
+	size := strings.ToUpper(strings.TrimSpace(parameterSize))
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(size, "B"):
+		size = strings.TrimSuffix(size, "B")
+	case strings.HasSuffix(size, "M"):
+		size = strings.TrimSuffix(size, "M")
+		scale = 0.001
+	}
+	billions, err := strconv.ParseFloat(size, 64)
+	if err != nil {
+		return 60
+	}
+
+	switch billions *= scale; {
+	case billions < 4:
+		return 55
+	case billions < 15:
+		return 60
+	case billions < 40:
+		return 70
+	default:
+		return 75
+	}
+}
+
+// DiscoverLocalModels asks the Ollama server behind the registered ollama
+// provider which models are installed and makes exactly those local models
+// available. Built-in local models that are installed keep their catalog
+// entry; other installed models are added with default scores. It returns
+// the IDs of the available local models. Without an ollama provider there
+// is nothing to probe and the catalog is unchanged.
+func (r *Router) DiscoverLocalModels(ctx context.Context) ([]string, error) {
+	cfg, err := r.registry.GetConfig("ollama")
+	if err != nil || cfg == nil {
+		return nil, nil
+	}
+	baseURL, _ := cfg.Config["base_url"].(string)
+
+	discovered, err := DiscoverOllamaModels(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]Model, len(discovered))
+	for _, m := range discovered {
+		installed[m.Name] = m
+	}
+
+	var ids []string
+	for i := range r.models {
+		if r.models[i].Provider != ProviderLocal {
+			continue
+		}
+		_, ok := installed[r.models[i].Name]
+		r.models[i].Available = ok
+		if ok {
+			ids = append(ids, r.models[i].ID)
+			delete(installed, r.models[i].Name)
+		}
+	}
+
+	// Add the remaining models in the server's order
+	for _, m := range discovered {
+		if _, ok := installed[m.Name]; !ok || r.hasModel(m.ID) {
+			continue
+		}
+		r.models = append(r.models, m)
+		ids = append(ids, m.ID)
+	}
+
+	return ids, nil
+}
+
+// hasModel reports whether the catalog has a model with the given ID
+func (r *Router) hasModel(id string) bool {
+	for _, m := range r.models {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
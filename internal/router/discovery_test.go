@@ -0,0 +1,199 @@
+package router
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// newOllamaServer serves tags as Ollama's /api/tags response on IPv4
+// loopback
+func newOllamaServer(t *testing.T, tags string) *httptest.Server {
+	t.Helper()
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("unable to start test server: %v", err)
+	}
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/api/tags" {
+				http.NotFound(w, req)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(tags))
+		})},
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+const ollamaTagsResponse = `{"models": [
+	{"name": "llama3.2:latest", "details": {"family": "llama", "parameter_size": "3.2B"}},
+	{"name": "qwen2.5-coder:7b", "details": {"family": "qwen2", "parameter_size": "7.6B"}},
+	{"name": "deepseek-r1:70b", "details": {"family": "qwen2", "parameter_size": "70.6B"}}
+]}`
+
+func TestDiscoverOllamaModels(t *testing.T) {
+	server := newOllamaServer(t, ollamaTagsResponse)
+
+	models, err := DiscoverOllamaModels(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("DiscoverOllamaModels() error = %v", err)
+	}
+
+	want := []struct {
+		id         string
+		name       string
+		modelType  ModelType
+		capability float64
+	}{
+		{id: "llama3.2", name: "llama3.2:latest", modelType: ModelTypeFast, capability: 55},
+		{id: "qwen2.5-coder:7b", name: "qwen2.5-coder:7b", modelType: ModelTypeCodegen, capability: 60},
+		{id: "deepseek-r1:70b", name: "deepseek-r1:70b", modelType: ModelTypeFast, capability: 75},
+	}
+	if len(models) != len(want) {
+		t.Fatalf("models = %d, want %d", len(models), len(want))
+	}
+	for i, w := range want {
+		m := models[i]
+		if m.ID != w.id || m.Name != w.name || m.Type != w.modelType || m.CapabilityScore != w.capability {
+			t.Errorf("model %d = %s (%s, %s, %.0f), want %s (%s, %s, %.0f)",
+				i, m.ID, m.Name, m.Type, m.CapabilityScore, w.id, w.name, w.modelType, w.capability)
+		}
+		if m.Provider != ProviderLocal || m.CostPerMToken != 0 || !m.Available {
+			t.Errorf("model %s should be an available, free local model", m.ID)
+		}
+		if err := validateCatalogModel(m); err != nil {
+			t.Errorf("model %s: %v", m.ID, err)
+		}
+	}
+}
+
+func TestDiscoverOllamaModels_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL func(t *testing.T) string
+	}{
+		{name: "invalid JSON", baseURL: func(t *testing.T) string { return newOllamaServer(t, "not json").URL }},
+		{name: "not found", baseURL: func(t *testing.T) string { return newOllamaServer(t, "{}").URL + "/missing" }},
+		{name: "unreachable", baseURL: func(t *testing.T) string {
+			server := newOllamaServer(t, "{}")
+			server.Close()
+			return server.URL
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DiscoverOllamaModels(context.Background(), tt.baseURL(t)); err == nil {
+				t.Error("DiscoverOllamaModels() expected an error")
+			}
+		})
+	}
+}
+
+func TestOllamaCapabilityScore(t *testing.T) {
+	tests := []struct {
+		size string
+		want float64
+	}{
+		{size: "", want: 60},
+		{size: "unknown", want: 60},
+		{size: "494.03M", want: 55},
+		{size: "3.2B", want: 55},
+		{size: "8.0B", want: 60},
+		{size: "32.8B", want: 70},
+		{size: "70.6B", want: 75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			if got := ollamaCapabilityScore(tt.size); got != tt.want {
+				t.Errorf("ollamaCapabilityScore(%q) = %.0f, want %.0f", tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_DiscoverLocalModels(t *testing.T) {
+	server := newOllamaServer(t, ollamaTagsResponse)
+
+	registry := provider.NewRegistry()
+	config := &provider.ProviderConfig{Name: "ollama", Config: map[string]interface{}{"base_url": server.URL}}
+	if err := registry.Register("ollama", &flakyProvider{healthy: true}, config); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 10, PreferCheap: true}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+
+	ids, err := r.DiscoverLocalModels(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverLocalModels() error = %v", err)
+	}
+	wantIDs := []string{"llama3.2", "qwen2.5-coder:7b", "deepseek-r1:70b"}
+	if len(ids) != len(wantIDs) {
+		t.Fatalf("ids = %v, want %v", ids, wantIDs)
+	}
+	for i := range wantIDs {
+		if ids[i] != wantIDs[i] {
+			t.Fatalf("ids = %v, want %v", ids, wantIDs)
+		}
+	}
+
+	available := make(map[string]bool)
+	for _, m := range r.models {
+		if m.Provider == ProviderLocal {
+			available[m.ID] = m.Available
+		}
+	}
+	for id, want := range map[string]bool{"llama3.2": true, "qwen2.5-coder:7b": true, "deepseek-r1:70b": true, "codellama": false, "llama3": false} {
+		if available[id] != want {
+			t.Errorf("%s available = %v, want %v", id, available[id], want)
+		}
+	}
+
+	// Discovered models are routable
+	result, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "qwen2.5-coder:7b" {
+		t.Errorf("selected %s, want the installed coding model", result.Model.ID)
+	}
+
+	// Discovering again doesn't duplicate models
+	if _, err := r.DiscoverLocalModels(context.Background()); err != nil {
+		t.Fatalf("DiscoverLocalModels() error = %v", err)
+	}
+	count := 0
+	for _, m := range r.models {
+		if m.ID == "qwen2.5-coder:7b" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("qwen2.5-coder:7b listed %d times, want once", count)
+	}
+}
+
+func TestRouter_DiscoverLocalModels_NoOllama(t *testing.T) {
+	r := newCanaryTestRouter(t, &RouterConfig{BudgetUSD: 10})
+	before := len(r.models)
+
+	ids, err := r.DiscoverLocalModels(context.Background())
+	if err != nil || ids != nil {
+		t.Errorf("DiscoverLocalModels() = %v, %v, want nothing without an ollama provider", ids, err)
+	}
+	if len(r.models) != before {
+		t.Errorf("models = %d, want %d", len(r.models), before)
+	}
+}
@@ -0,0 +1,111 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fallbackPlan orders fallback candidates for a single request. Once a
+// provider fails, its remaining models are tried only after every other
+// provider, or skipped entirely with FallbackAcrossProvidersOnly. A failure
+// is usually provider-wide (auth, network, outage), so retrying a sibling
+// model would fail the same way.
+type fallbackPlan struct {
+	candidates          []*Model
+	tried               map[string]bool
+	failed              map[Provider]bool
+	acrossProvidersOnly bool
+}
+
+// newFallbackPlan starts a plan over scored candidates after the primary
+// model failed
+func (r *Router) newFallbackPlan(scored []*Model, primary *Model) *fallbackPlan {
+	plan := &fallbackPlan{
+		candidates: scored,
+		tried:      map[string]bool{primary.ID: true},
+		failed:     map[Provider]bool{primary.Provider: true},
+	}
+
+	// With a single provider, its other models are the only fallback option
+	if r.config.FallbackAcrossProvidersOnly {
+		for _, m := range scored {
+			if m.Provider != primary.Provider {
+				plan.acrossProvidersOnly = true
+				break
+			}
+		}
+	}
+
+	return plan
+}
+
+// next returns the next model to try and the failed providers whose models
+// were passed over to reach it. It returns nil when no candidates remain.
+func (p *fallbackPlan) next() (*Model, []Provider) {
+	var deferred *Model
+	for _, m := range p.candidates {
+		if p.tried[m.ID] {
+			continue
+		}
+		if !p.failed[m.Provider] {
+			p.tried[m.ID] = true
+			return m, p.skippedBefore(m)
+		}
+		if deferred == nil {
+			deferred = m
+		}
+	}
+
+	if deferred == nil || p.acrossProvidersOnly {
+		return nil, p.skippedBefore(nil)
+	}
+
+	p.tried[deferred.ID] = true
+	return deferred, p.skippedBefore(deferred)
+}
+
+// markFailed records that a model's provider failed
+func (p *fallbackPlan) markFailed(m *Model) {
+	p.failed[m.Provider] = true
+}
+
+// skippedBefore lists failed providers with untried models ranked ahead of
+// target, or with any untried models when target is nil
+func (p *fallbackPlan) skippedBefore(target *Model) []Provider {
+	seen := make(map[Provider]bool)
+	var skipped []Provider
+	for _, m := range p.candidates {
+		if m == target {
+			break
+		}
+		if !p.tried[m.ID] && p.failed[m.Provider] && !seen[m.Provider] {
+			seen[m.Provider] = true
+			skipped = append(skipped, m.Provider)
+		}
+	}
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i] < skipped[j] })
+	return skipped
+}
+
+// fallbackReason describes a fallback selection
+func fallbackReason(model *Model, primary *Model, streaming bool, skipped []Provider) string {
+	failure := "failed"
+	if streaming {
+		failure = "streaming failed"
+	}
+
+	reason := fmt.Sprintf("Fallback: %s (primary %s %s)", model.ID, primary.ID, failure)
+	if len(skipped) > 0 {
+		reason += fmt.Sprintf("; skipped failed providers: %s", joinProviders(skipped))
+	}
+	return reason
+}
+
+func joinProviders(providers []Provider) string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// modelRecordingProvider records the models it is asked for and fails
+// requests with err when set, limited to failModel when that is set too
+type modelRecordingProvider struct {
+	flakyProvider
+	err       error
+	failModel string
+	models    []string
+}
+
+func (p *modelRecordingProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	model := req.Config["model"].(string)
+	p.models = append(p.models, model)
+	if p.err != nil && (p.failModel == "" || p.failModel == model) {
+		return nil, p.err
+	}
+	return &provider.GenerateResponse{Content: "ok", TokensUsed: 10}, nil
+}
+
+// newFallbackTestRouter returns a router over the given providers and models
+func newFallbackTestRouter(t *testing.T, cfg *RouterConfig, providers map[string]*modelRecordingProvider, models []Model) *Router {
+	t.Helper()
+
+	registry := provider.NewRegistry()
+	for name, prov := range providers {
+		if err := registry.Register(name, prov, &provider.ProviderConfig{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg.BudgetUSD = 100
+	cfg.EnableFallback = true
+	r, err := NewRouterWithProviders(cfg, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = models
+	return r
+}
+
+func fallbackTestModels() []Model {
+	return []Model{
+		{ID: "sonnet", Name: "sonnet", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 3, CapabilityScore: 99, Available: true},
+		{ID: "haiku", Name: "haiku", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 1, CapabilityScore: 95, Available: true},
+		{ID: "gpt", Name: "gpt", Provider: ProviderOpenAI, Type: ModelTypeAgentic, ContextWindow: 128000, CostPerMToken: 2.5, CapabilityScore: 90, Available: true},
+	}
+}
+
+func TestGenerateWithFallback_DeprioritizesFailedProvider(t *testing.T) {
+	authErr := errors.New("invalid api key")
+	anthropic := &modelRecordingProvider{err: authErr}
+	openai := &modelRecordingProvider{}
+
+	r := newFallbackTestRouter(t, &RouterConfig{}, map[string]*modelRecordingProvider{
+		"anthropic": anthropic,
+		"openai":    openai,
+	}, fallbackTestModels())
+
+	resp, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if resp.Model != "gpt" {
+		t.Errorf("fallback model = %s, want gpt", resp.Model)
+	}
+	if !reflect.DeepEqual(anthropic.models, []string{"sonnet"}) {
+		t.Errorf("anthropic was asked for %v, want only the primary", anthropic.models)
+	}
+	if !strings.Contains(resp.SelectionReason, "skipped failed providers: anthropic") {
+		t.Errorf("SelectionReason = %q, want skipped providers recorded", resp.SelectionReason)
+	}
+
+	// Once every other provider has failed too, the failed provider's
+	// remaining models are still tried
+	openai.err = authErr
+	anthropic.models = nil
+	if _, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic"}); err == nil {
+		t.Fatal("Generate() expected error")
+	}
+	if !reflect.DeepEqual(anthropic.models, []string{"sonnet", "haiku"}) {
+		t.Errorf("anthropic was asked for %v, want the primary then haiku last", anthropic.models)
+	}
+}
+
+func TestGenerateWithFallback_AcrossProvidersOnly(t *testing.T) {
+	authErr := errors.New("invalid api key")
+	anthropic := &modelRecordingProvider{err: authErr}
+	openai := &modelRecordingProvider{err: authErr}
+
+	r := newFallbackTestRouter(t, &RouterConfig{FallbackAcrossProvidersOnly: true}, map[string]*modelRecordingProvider{
+		"anthropic": anthropic,
+		"openai":    openai,
+	}, fallbackTestModels())
+
+	_, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic"})
+	if err == nil {
+		t.Fatal("Generate() expected error")
+	}
+	if !strings.Contains(err.Error(), "skipped failed providers: anthropic") {
+		t.Errorf("error = %v, want skipped providers listed", err)
+	}
+	if !reflect.DeepEqual(anthropic.models, []string{"sonnet"}) {
+		t.Errorf("anthropic was asked for %v, want haiku skipped", anthropic.models)
+	}
+	if !reflect.DeepEqual(openai.models, []string{"gpt"}) {
+		t.Errorf("openai was asked for %v, want gpt", openai.models)
+	}
+}
+
+func TestGenerateWithFallback_SingleProvider(t *testing.T) {
+	// The primary fails; the only fallback comes from the same provider
+	anthropic := &modelRecordingProvider{err: errors.New("model overloaded"), failModel: "sonnet"}
+
+	r := newFallbackTestRouter(t, &RouterConfig{FallbackAcrossProvidersOnly: true}, map[string]*modelRecordingProvider{
+		"anthropic": anthropic,
+	}, fallbackTestModels()[:2])
+
+	resp, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Model != "haiku" {
+		t.Errorf("fallback model = %s, want haiku", resp.Model)
+	}
+	if !reflect.DeepEqual(anthropic.models, []string{"sonnet", "haiku"}) {
+		t.Errorf("anthropic was asked for %v", anthropic.models)
+	}
+}
@@ -0,0 +1,255 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultHealthCooldown is how long an unhealthy provider is skipped before
+// the router re-probes it
+const DefaultHealthCooldown = 30 * time.Second
+
+// DefaultBreakerWindow is how close together consecutive failures must be
+// to count toward opening a provider's circuit
+const DefaultBreakerWindow = time.Minute
+
+// BreakerState is the state of a provider's circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets requests through; failures are counted
+	BreakerClosed BreakerState = "closed"
+
+	// BreakerOpen skips the provider until its cooldown elapses
+	BreakerOpen BreakerState = "open"
+
+	// BreakerHalfOpen probes whether the provider has recovered
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ProviderHealth describes the router's view of a provider's health
+type ProviderHealth struct {
+	Provider            Provider     `json:"provider"`
+	Healthy             bool         `json:"healthy"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+	CheckedAt           time.Time    `json:"checked_at"`
+}
+
+// healthTracker is a circuit breaker per provider. Consecutive transient
+// failures open a provider's circuit; after the cooldown it goes half-open
+// while one probe checks for recovery. The zero value is ready to use.
+type healthTracker struct {
+	mu    sync.Mutex
+	state map[Provider]*ProviderHealth
+	now   func() time.Time // Overridable for tests
+}
+
+// clock returns the current time
+func (h *healthTracker) clock() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
+
+// record stores the result of a health observation, closing the circuit
+// on success and opening it on failure
+func (h *healthTracker) record(p Provider, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == nil {
+		h.state = make(map[Provider]*ProviderHealth)
+	}
+
+	status := &ProviderHealth{
+		Provider:  p,
+		Healthy:   err == nil,
+		State:     BreakerClosed,
+		CheckedAt: h.clock(),
+	}
+	if err != nil {
+		status.State = BreakerOpen
+		status.LastError = err.Error()
+		if previous, ok := h.state[p]; ok {
+			status.ConsecutiveFailures = previous.ConsecutiveFailures
+		}
+		status.ConsecutiveFailures++
+	}
+	h.state[p] = status
+}
+
+// failure counts a transient failure. The circuit opens once threshold
+// failures occur with no more than window between them, or on any failure
+// while half-open.
+func (h *healthTracker) failure(p Provider, err error, threshold int, window time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == nil {
+		h.state = make(map[Provider]*ProviderHealth)
+	}
+	status, ok := h.state[p]
+	if !ok {
+		status = &ProviderHealth{Provider: p, Healthy: true, State: BreakerClosed}
+		h.state[p] = status
+	}
+
+	now := h.clock()
+	if status.State == BreakerClosed && status.ConsecutiveFailures > 0 && now.Sub(status.CheckedAt) > window {
+		status.ConsecutiveFailures = 0
+	}
+	status.ConsecutiveFailures++
+	status.LastError = err.Error()
+	status.CheckedAt = now
+	if status.State != BreakerClosed || status.ConsecutiveFailures >= threshold {
+		status.State = BreakerOpen
+		status.Healthy = false
+	}
+}
+
+// halfOpen moves an open circuit whose cooldown has elapsed to half-open
+// and reports whether the caller should probe the provider. Only one
+// caller probes; others keep skipping the provider until the probe ends.
+func (h *healthTracker) halfOpen(p Provider, cooldown time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status, ok := h.state[p]
+	if !ok || status.State != BreakerOpen || h.clock().Sub(status.CheckedAt) < cooldown {
+		return false
+	}
+	status.State = BreakerHalfOpen
+	return true
+}
+
+// get returns a copy of the provider's health; unknown providers are healthy
+func (h *healthTracker) get(p Provider) ProviderHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if status, ok := h.state[p]; ok {
+		return *status
+	}
+	return ProviderHealth{Provider: p, Healthy: true, State: BreakerClosed}
+}
+
+// all returns a copy of every tracked provider's health
+func (h *healthTracker) all() map[Provider]ProviderHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	states := make(map[Provider]ProviderHealth, len(h.state))
+	for p, status := range h.state {
+		states[p] = *status
+	}
+	return states
+}
+
+// healthCooldown returns the configured re-probe cooldown
+func (r *Router) healthCooldown() time.Duration {
+	if r.config != nil && r.config.HealthCooldownMs > 0 {
+		return time.Duration(r.config.HealthCooldownMs) * time.Millisecond
+	}
+	return DefaultHealthCooldown
+}
+
+// breakerThreshold returns how many consecutive transient failures open a
+// provider's circuit
+func (r *Router) breakerThreshold() int {
+	if r.config != nil && r.config.BreakerThreshold > 0 {
+		return r.config.BreakerThreshold
+	}
+	return 1
+}
+
+// breakerWindow returns the longest gap between failures that still counts
+// them as consecutive
+func (r *Router) breakerWindow() time.Duration {
+	if r.config != nil && r.config.BreakerWindowMs > 0 {
+		return time.Duration(r.config.BreakerWindowMs) * time.Millisecond
+	}
+	return DefaultBreakerWindow
+}
+
+// MarkProviderUnhealthy opens a provider's circuit, excluding its models
+// from selection until the cooldown expires and a re-probe succeeds
+func (r *Router) MarkProviderUnhealthy(p Provider, err error) {
+	if err == nil {
+		err = fmt.Errorf("marked unhealthy")
+	}
+	r.health.record(p, err)
+}
+
+// MarkProviderHealthy closes a provider's circuit, restoring its models to
+// selection
+func (r *Router) MarkProviderHealthy(p Provider) {
+	r.health.record(p, nil)
+}
+
+// GetProviderHealth returns the router's current view of a provider's health
+func (r *Router) GetProviderHealth(p Provider) ProviderHealth {
+	return r.health.get(p)
+}
+
+// CheckProviderHealth runs the provider's health check and records the result
+func (r *Router) CheckProviderHealth(ctx context.Context, p Provider) error {
+	providerName := r.getProviderName(p)
+	if providerName == "" {
+		return fmt.Errorf("unknown provider %s", p)
+	}
+
+	prov, err := r.registry.Get(providerName)
+	if err != nil {
+		return fmt.Errorf("provider %s not available: %w", providerName, err)
+	}
+
+	err = prov.Health(ctx)
+	r.health.record(p, err)
+	return err
+}
+
+// isProviderUsable reports whether a provider's models may be selected. A
+// provider with an open circuit is skipped during its cooldown; once the
+// cooldown has elapsed the circuit goes half-open and the provider is
+// re-probed, closing the circuit if the health check succeeds.
+func (r *Router) isProviderUsable(ctx context.Context, p Provider) bool {
+	if r.health.get(p).State == BreakerClosed {
+		return true
+	}
+
+	if !r.health.halfOpen(p, r.healthCooldown()) {
+		return false
+	}
+
+	// Cooldown elapsed - re-probe; a failed probe reopens the circuit and
+	// restarts the cooldown
+	if err := r.CheckProviderHealth(ctx, p); err != nil {
+		// Reopen the circuit when the probe could not run
+		if r.health.get(p).State == BreakerHalfOpen {
+			r.health.record(p, err)
+		}
+		return false
+	}
+	return true
+}
+
+// recordProviderResult updates provider health after a generation attempt.
+// Only transient failures (timeouts, connection errors, 503s) count toward
+// opening the circuit; request-specific errors say nothing about its health.
+func (r *Router) recordProviderResult(p Provider, err error) {
+	if err == nil {
+		if status := r.health.get(p); status.State != BreakerClosed || status.ConsecutiveFailures > 0 {
+			r.MarkProviderHealthy(p)
+		}
+		return
+	}
+
+	if r.isRetryableError(err) {
+		r.health.failure(p, err, r.breakerThreshold(), r.breakerWindow())
+	}
+}
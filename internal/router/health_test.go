@@ -0,0 +1,237 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// flakyProvider is a provider whose health and generation can be toggled
+type flakyProvider struct {
+	healthy     bool
+	healthCalls int
+	lastRequest *provider.GenerateRequest
+}
+
+func (p *flakyProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	p.lastRequest = req
+	if !p.healthy {
+		return nil, errors.New("connection refused")
+	}
+	return &provider.GenerateResponse{Content: "ok", TokensUsed: 10}, nil
+}
+
+func (p *flakyProvider) Stream(ctx context.Context, req *provider.GenerateRequest) (<-chan provider.StreamChunk, error) {
+	return nil, errors.New("streaming not supported")
+}
+
+func (p *flakyProvider) GetCapabilities() *provider.ProviderCapabilities {
+	return &provider.ProviderCapabilities{}
+}
+
+func (p *flakyProvider) GetInfo() *provider.ProviderInfo {
+	return &provider.ProviderInfo{Name: "flaky"}
+}
+
+func (p *flakyProvider) IsAvailable() bool { return true }
+
+func (p *flakyProvider) Health(ctx context.Context) error {
+	p.healthCalls++
+	if !p.healthy {
+		return errors.New("service unavailable")
+	}
+	return nil
+}
+
+func (p *flakyProvider) Close() error { return nil }
+
+// newHealthTestRouter returns a router with flaky anthropic and openai
+// providers and a controllable clock
+func newHealthTestRouter(t *testing.T) (*Router, *flakyProvider, *flakyProvider, *time.Time) {
+	t.Helper()
+
+	anthropic := &flakyProvider{healthy: true}
+	openai := &flakyProvider{healthy: true}
+
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", anthropic, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("openai", openai, &provider.ProviderConfig{Name: "openai"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRouterWithProviders(&RouterConfig{
+		BudgetUSD:        100.0,
+		MaxLatencyMs:     60000,
+		HealthCooldownMs: 1000,
+	}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+
+	now := time.Now()
+	r.health.now = func() time.Time { return now }
+
+	return r, anthropic, openai, &now
+}
+
+func TestRouter_UnhealthyProviderSkippedUntilReprobe(t *testing.T) {
+	r, anthropic, _, now := newHealthTestRouter(t)
+	ctx := context.Background()
+	req := RoutingRequest{ModelHint: "agentic", Complexity: 8}
+
+	initial, err := r.SelectModel(ctx, req)
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if initial.Model.Provider != ProviderAnthropic {
+		t.Fatalf("expected anthropic model initially, got %s", initial.Model.Provider)
+	}
+
+	// Provider goes down
+	anthropic.healthy = false
+	r.MarkProviderUnhealthy(ProviderAnthropic, errors.New("connection refused"))
+
+	// During cooldown the provider is skipped without probing
+	*now = now.Add(500 * time.Millisecond)
+	result, err := r.SelectModel(ctx, req)
+	if err != nil {
+		t.Fatalf("SelectModel() during cooldown error = %v", err)
+	}
+	if result.Model.Provider == ProviderAnthropic {
+		t.Error("unhealthy provider should be skipped during cooldown")
+	}
+	if anthropic.healthCalls != 0 {
+		t.Errorf("expected no re-probe during cooldown, got %d", anthropic.healthCalls)
+	}
+
+	// Cooldown elapses but the provider is still down: probe fails, cooldown restarts
+	*now = now.Add(time.Second)
+	result, err = r.SelectModel(ctx, req)
+	if err != nil {
+		t.Fatalf("SelectModel() after failed re-probe error = %v", err)
+	}
+	if result.Model.Provider == ProviderAnthropic {
+		t.Error("provider should stay skipped after a failed re-probe")
+	}
+	if anthropic.healthCalls != 1 {
+		t.Errorf("expected 1 re-probe, got %d", anthropic.healthCalls)
+	}
+
+	// Provider recovers; the next selection after the cooldown restores it
+	anthropic.healthy = true
+	*now = now.Add(time.Second)
+	result, err = r.SelectModel(ctx, req)
+	if err != nil {
+		t.Fatalf("SelectModel() after recovery error = %v", err)
+	}
+	if result.Model.Provider != ProviderAnthropic {
+		t.Errorf("recovered provider should be selected again, got %s", result.Model.Provider)
+	}
+	if !r.GetProviderHealth(ProviderAnthropic).Healthy {
+		t.Error("provider should be marked healthy after successful re-probe")
+	}
+}
+
+func TestRouter_GenerateFailureMarksProviderUnhealthy(t *testing.T) {
+	r, anthropic, _, _ := newHealthTestRouter(t)
+	ctx := context.Background()
+
+	anthropic.healthy = false
+	result := &RoutingResult{Model: &Model{ID: "claude-sonnet-4", Name: "claude-sonnet-4", Provider: ProviderAnthropic}}
+
+	if _, err := r.generateWithRetry(ctx, GenerateRequest{Prompt: "hi"}, result); err == nil {
+		t.Fatal("expected generation to fail")
+	}
+
+	status := r.GetProviderHealth(ProviderAnthropic)
+	if status.Healthy {
+		t.Fatal("transient failure should mark provider unhealthy")
+	}
+	if status.LastError == "" {
+		t.Error("expected last error to be recorded")
+	}
+
+	// Non-transient failures say nothing about provider health
+	r.MarkProviderHealthy(ProviderAnthropic)
+	r.recordProviderResult(ProviderAnthropic, errors.New("invalid api key"))
+	if !r.GetProviderHealth(ProviderAnthropic).Healthy {
+		t.Error("non-retryable error should not mark provider unhealthy")
+	}
+}
+
+func TestRouter_CircuitBreaker(t *testing.T) {
+	r, anthropic, _, now := newHealthTestRouter(t)
+	r.config.BreakerThreshold = 3
+	r.config.BreakerWindowMs = 1000
+	ctx := context.Background()
+	outage := errors.New("503 service unavailable")
+
+	state := func() BreakerState { return r.GetProviderHealth(ProviderAnthropic).State }
+
+	// Failures further apart than the window do not add up
+	r.recordProviderResult(ProviderAnthropic, outage)
+	*now = now.Add(2 * time.Second)
+	r.recordProviderResult(ProviderAnthropic, outage)
+	*now = now.Add(100 * time.Millisecond)
+	r.recordProviderResult(ProviderAnthropic, outage)
+	if state() != BreakerClosed || !r.isProviderUsable(ctx, ProviderAnthropic) {
+		t.Fatalf("state = %s after spaced failures, want closed", state())
+	}
+
+	// A success resets the count
+	r.recordProviderResult(ProviderAnthropic, nil)
+	if got := r.GetProviderHealth(ProviderAnthropic).ConsecutiveFailures; got != 0 {
+		t.Errorf("ConsecutiveFailures = %d after success, want 0", got)
+	}
+
+	// Request-specific errors are not counted
+	for i := 0; i < 3; i++ {
+		r.recordProviderResult(ProviderAnthropic, errors.New("invalid api key"))
+	}
+	if state() != BreakerClosed {
+		t.Fatalf("state = %s after non-retryable errors, want closed", state())
+	}
+
+	// Three consecutive transient failures open the circuit
+	for i := 0; i < 3; i++ {
+		r.recordProviderResult(ProviderAnthropic, outage)
+	}
+	if state() != BreakerOpen {
+		t.Fatalf("state = %s after 3 failures, want open", state())
+	}
+	if r.isProviderUsable(ctx, ProviderAnthropic) {
+		t.Error("open provider should be skipped during cooldown")
+	}
+
+	stats := r.GetUsageStats()
+	breakers, ok := stats["circuit_breakers"].(map[Provider]ProviderHealth)
+	if !ok || breakers[ProviderAnthropic].State != BreakerOpen {
+		t.Errorf("circuit_breakers = %v, want anthropic open", stats["circuit_breakers"])
+	}
+
+	// After the cooldown one caller probes while the circuit is half-open
+	*now = now.Add(2 * time.Second)
+	anthropic.healthy = false
+	if !r.health.halfOpen(ProviderAnthropic, r.healthCooldown()) {
+		t.Fatal("circuit should go half-open after the cooldown")
+	}
+	if r.isProviderUsable(ctx, ProviderAnthropic) || anthropic.healthCalls != 0 {
+		t.Error("half-open provider should be skipped while a probe is in flight")
+	}
+
+	// A failed probe reopens the circuit; a later successful one closes it
+	r.health.record(ProviderAnthropic, outage)
+	*now = now.Add(2 * time.Second)
+	anthropic.healthy = true
+	if !r.isProviderUsable(ctx, ProviderAnthropic) {
+		t.Error("provider should be usable after a successful probe")
+	}
+	if state() != BreakerClosed {
+		t.Errorf("state = %s after recovery, want closed", state())
+	}
+}
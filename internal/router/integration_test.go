@@ -82,12 +82,13 @@ func TestRouterIntegration_OllamaProvider(t *testing.T) {
 	}
 
 	// Create a simple generate request
+	temperature := 0.1
 	req := GenerateRequest{
 		Prompt:      "What is 2 + 2? Answer with just the number.",
 		ModelHint:   "fast",
 		Complexity:  1,
 		Priority:    "P1",
-		Temperature: 0.1,
+		Temperature: &temperature,
 		TaskID:      "test-integration",
 	}
 
@@ -162,7 +163,7 @@ func TestRouterIntegration_OllamaProvider(t *testing.T) {
 		ModelHint:   "cheap",
 		Complexity:  1,
 		Priority:    "P2",
-		Temperature: 0.1,
+		Temperature: &temperature,
 		TaskID:      "test-integration-2",
 	}
 
@@ -284,11 +285,12 @@ func TestRouterIntegration_BudgetExhaustion(t *testing.T) {
 	}
 
 	// First request should work
+	temperature := 0.1
 	req := GenerateRequest{
 		Prompt:      "Hi",
 		ModelHint:   "cheap",
 		Complexity:  1,
-		Temperature: 0.1,
+		Temperature: &temperature,
 	}
 
 	ctx := context.Background()
@@ -304,7 +306,7 @@ func TestRouterIntegration_BudgetExhaustion(t *testing.T) {
 		Prompt:      "Hello again",
 		ModelHint:   "cheap",
 		Complexity:  1,
-		Temperature: 0.1,
+		Temperature: &temperature,
 	}
 
 	_, err = router.Generate(ctx, req2)
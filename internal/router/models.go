@@ -1,5 +1,7 @@
 package router
 
+import "path"
+
 // GetAvailableModels returns the catalog of known AI models
 func GetAvailableModels() []Model {
 	return []Model{
@@ -8,6 +10,7 @@ func GetAvailableModels() []Model {
 			ID:              "claude-sonnet-4",
 			Provider:        ProviderAnthropic,
 			Name:            "claude-sonnet-4-20250514",
+			Family:          "claude-4",
 			Type:            ModelTypeAgentic,
 			ContextWindow:   200000,
 			CostPerMToken:   3.00, // $3 per million tokens (input)
@@ -19,6 +22,7 @@ func GetAvailableModels() []Model {
 			ID:              "claude-sonnet-3.5",
 			Provider:        ProviderAnthropic,
 			Name:            "claude-3-5-sonnet-20241022",
+			Family:          "claude-3",
 			Type:            ModelTypeCodegen,
 			ContextWindow:   200000,
 			CostPerMToken:   3.00,
@@ -30,6 +34,7 @@ func GetAvailableModels() []Model {
 			ID:              "claude-haiku-3.5",
 			Provider:        ProviderAnthropic,
 			Name:            "claude-3-5-haiku-20241022",
+			Family:          "claude-3",
 			Type:            ModelTypeFast,
 			ContextWindow:   200000,
 			CostPerMToken:   0.80, // $0.80 per million tokens
@@ -43,6 +48,7 @@ func GetAvailableModels() []Model {
 			ID:              "gpt-4-turbo",
 			Provider:        ProviderOpenAI,
 			Name:            "gpt-4-turbo-2024-04-09",
+			Family:          "gpt-4",
 			Type:            ModelTypeLongContext,
 			ContextWindow:   128000,
 			CostPerMToken:   10.00, // $10 per million tokens
@@ -54,6 +60,7 @@ func GetAvailableModels() []Model {
 			ID:              "gpt-4o",
 			Provider:        ProviderOpenAI,
 			Name:            "gpt-4o-2024-08-06",
+			Family:          "gpt-4o",
 			Type:            ModelTypeCodegen,
 			ContextWindow:   128000,
 			CostPerMToken:   2.50, // $2.50 per million tokens
@@ -65,6 +72,7 @@ func GetAvailableModels() []Model {
 			ID:              "gpt-4o-mini",
 			Provider:        ProviderOpenAI,
 			Name:            "gpt-4o-mini-2024-07-18",
+			Family:          "gpt-4o",
 			Type:            ModelTypeCheap,
 			ContextWindow:   128000,
 			CostPerMToken:   0.15, // $0.15 per million tokens
@@ -76,6 +84,7 @@ func GetAvailableModels() []Model {
 			ID:              "gpt-3.5-turbo",
 			Provider:        ProviderOpenAI,
 			Name:            "gpt-3.5-turbo-0125",
+			Family:          "gpt-3.5",
 			Type:            ModelTypeFast,
 			ContextWindow:   16385,
 			CostPerMToken:   0.50, // $0.50 per million tokens
@@ -89,6 +98,7 @@ func GetAvailableModels() []Model {
 			ID:              "llama3.2",
 			Provider:        ProviderLocal,
 			Name:            "llama3.2:latest", // Ollama model name
+			Family:          "llama3",
 			Type:            ModelTypeFast,
 			ContextWindow:   8192,
 			CostPerMToken:   0.00, // Free (local)
@@ -100,6 +110,7 @@ func GetAvailableModels() []Model {
 			ID:              "codellama",
 			Provider:        ProviderLocal,
 			Name:            "codellama:latest", // Ollama model name
+			Family:          "codellama",
 			Type:            ModelTypeCodegen,
 			ContextWindow:   16384,
 			CostPerMToken:   0.00, // Free (local)
@@ -111,6 +122,7 @@ func GetAvailableModels() []Model {
 			ID:              "llama3",
 			Provider:        ProviderLocal,
 			Name:            "llama3:latest", // Ollama model name
+			Family:          "llama3",
 			Type:            ModelTypeAgentic,
 			ContextWindow:   8192,
 			CostPerMToken:   0.00, // Free (local)
@@ -121,6 +133,38 @@ func GetAvailableModels() []Model {
 	}
 }
 
+// FamilyName returns the model family, falling back to the model ID for
+// models without family metadata
+func (m Model) FamilyName() string {
+	if m.Family != "" {
+		return m.Family
+	}
+	return m.ID
+}
+
+// InFamily reports whether the model belongs to a family. Patterns may use
+// glob syntax (e.g. "claude-*").
+func (m Model) InFamily(pattern string) bool {
+	family := m.FamilyName()
+	if family == pattern {
+		return true
+	}
+	matched, err := path.Match(pattern, family)
+	return err == nil && matched
+}
+
+// GetModelsByFamily returns all models in a family
+func GetModelsByFamily(family string) []Model {
+	models := GetAvailableModels()
+	var result []Model
+	for _, m := range models {
+		if m.InFamily(family) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
 // GetModelByID finds a model by its ID
 func GetModelByID(id string) *Model {
 	models := GetAvailableModels()
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/felixgeelhaar/specular/internal/policy"
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// ErrProviderNotAllowed is returned when the routing policy rejects every
+// model that could otherwise serve a request
+var ErrProviderNotAllowed = errors.New("PROVIDER_NOT_ALLOWED")
+
+// SetPolicy constrains routing to the policy's allowed models and strips its
+// denied tools from requests. A nil policy removes the constraints.
+func (r *Router) SetPolicy(pol *policy.Policy) {
+	r.policy = pol
+}
+
+// policyAllowsModel reports whether the routing policy permits a model. An
+// empty allow list permits every model. Names match the model ID or
+// provider model name and may be glob patterns.
+func (r *Router) policyAllowsModel(m Model) bool {
+	if r.policy == nil || len(r.policy.Routing.AllowModels) == 0 {
+		return true
+	}
+
+	for _, allow := range r.policy.Routing.AllowModels {
+		if !strings.EqualFold(allow.Provider, string(m.Provider)) {
+			continue
+		}
+		if len(allow.Names) == 0 {
+			return true
+		}
+		for _, name := range allow.Names {
+			if matchModelName(name, m.ID) || matchModelName(name, m.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchModelName(pattern, name string) bool {
+	if pattern == name {
+		return true
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// allowedTools removes tools denied by the routing policy
+func (r *Router) allowedTools(tools []provider.Tool) []provider.Tool {
+	if r.policy == nil || len(r.policy.Routing.DenyTools) == 0 || len(tools) == 0 {
+		return tools
+	}
+
+	denied := make(map[string]bool, len(r.policy.Routing.DenyTools))
+	for _, name := range r.policy.Routing.DenyTools {
+		denied[name] = true
+	}
+
+	allowed := make([]provider.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if !denied[tool.Function.Name] {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// noCandidatesError explains why no model could serve a request
+func (r *Router) noCandidatesError(ctx context.Context, req RoutingRequest) error {
+	if r.policy != nil && len(r.policy.Routing.AllowModels) > 0 && len(r.candidateModels(ctx, req, false, true)) > 0 {
+		return fmt.Errorf("%w: no model allowed by the routing policy can serve this request (allowed: %s)",
+			ErrProviderNotAllowed, describeAllowedModels(r.policy.Routing.AllowModels))
+	}
+	return fmt.Errorf("no suitable models found for request")
+}
+
+func describeAllowedModels(allows []policy.ModelAllow) string {
+	var parts []string
+	for _, allow := range allows {
+		if len(allow.Names) == 0 {
+			parts = append(parts, allow.Provider+"/*")
+			continue
+		}
+		for _, name := range allow.Names {
+			parts = append(parts, allow.Provider+"/"+name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/policy"
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+func routingPolicy(allow []policy.ModelAllow, denyTools ...string) *policy.Policy {
+	pol := policy.DefaultPolicy()
+	pol.Routing.AllowModels = allow
+	pol.Routing.DenyTools = denyTools
+	return pol
+}
+
+func newPolicyTestRouter(t *testing.T) *Router {
+	t.Helper()
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 10})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	r.models = fallbackTestModels()
+	return r
+}
+
+func TestRouter_PolicyAllowModels(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []policy.ModelAllow
+		want  string
+	}{
+		{"no allow list", nil, "sonnet"},
+		{"provider wildcard", []policy.ModelAllow{{Provider: "openai"}}, "gpt"},
+		{"exact name", []policy.ModelAllow{{Provider: "anthropic", Names: []string{"haiku"}}}, "haiku"},
+		{"glob name", []policy.ModelAllow{{Provider: "Anthropic", Names: []string{"h*"}}}, "haiku"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPolicyTestRouter(t)
+			r.SetPolicy(routingPolicy(tt.allow))
+
+			result, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "agentic"})
+			if err != nil {
+				t.Fatalf("SelectModel() error = %v", err)
+			}
+			if result.Model.ID != tt.want {
+				t.Errorf("selected %s, want %s", result.Model.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_PolicyRejectsEveryCandidate(t *testing.T) {
+	r := newPolicyTestRouter(t)
+	r.SetPolicy(routingPolicy([]policy.ModelAllow{{Provider: "google", Names: []string{"gemini-pro"}}}))
+
+	_, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "agentic"})
+	if !errors.Is(err, ErrProviderNotAllowed) {
+		t.Fatalf("SelectModel() error = %v, want PROVIDER_NOT_ALLOWED", err)
+	}
+	if !strings.Contains(err.Error(), "google/gemini-pro") {
+		t.Errorf("error %q should list the allowed models", err)
+	}
+
+	// Without a policy the same request is served
+	r.SetPolicy(nil)
+	if _, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "agentic"}); err != nil {
+		t.Errorf("SelectModel() without policy error = %v", err)
+	}
+}
+
+func TestRouter_PolicyBypassesDeniedPin(t *testing.T) {
+	r := newPolicyTestRouter(t)
+	if err := r.PinModel("sonnet"); err != nil {
+		t.Fatal(err)
+	}
+	r.SetPolicy(routingPolicy([]policy.ModelAllow{{Provider: "openai"}}))
+
+	result, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "agentic"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "gpt" {
+		t.Errorf("selected %s, want the allowed gpt over the pinned sonnet", result.Model.ID)
+	}
+}
+
+func TestRouter_PolicyStripsDeniedTools(t *testing.T) {
+	anthropic := &flakyProvider{healthy: true}
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", anthropic, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 10}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = fallbackTestModels()
+	r.SetPolicy(routingPolicy(nil, "shell_local"))
+
+	tools := []provider.Tool{
+		{Type: "function", Function: provider.ToolFunction{Name: "shell_local"}},
+		{Type: "function", Function: provider.ToolFunction{Name: "read_file"}},
+	}
+	if _, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic", Tools: tools}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	sent := anthropic.lastRequest.Tools
+	if len(sent) != 1 || sent[0].Function.Name != "read_file" {
+		t.Errorf("provider received tools %+v, want only read_file", sent)
+	}
+	if len(tools) != 2 {
+		t.Error("the caller's tools should not be modified")
+	}
+}
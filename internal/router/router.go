@@ -3,11 +3,16 @@ package router
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/metrics"
+	"github.com/felixgeelhaar/specular/internal/policy"
 	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/tokenizer"
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
 )
 
 // Router manages model selection and routing
@@ -19,43 +24,19 @@ type Router struct {
 	registry         provider.ProviderRegistry // Use interface for dependency injection
 	contextValidator *ContextValidator
 	contextTruncator *ContextTruncator
+	health           healthTracker
+	strategy         SelectionStrategy
+	pin              modelPin
+	canary           canarySampler
+	store            *usageStore    // Persists usage across runs; nil without UsageStorePath
+	history          []Usage        // Usage loaded from the store at startup
+	cache            *responseCache // Nil unless CacheDir is set
+	policy           *policy.Policy
 }
 
 // NewRouter creates a new router with configuration
 func NewRouter(config *RouterConfig) (*Router, error) {
-	if config == nil {
-		return nil, fmt.Errorf("config is required")
-	}
-
-	r := &Router{
-		config: config,
-		budget: &Budget{
-			LimitUSD:     config.BudgetUSD,
-			SpentUSD:     0,
-			RemainingUSD: config.BudgetUSD,
-			UsageCount:   0,
-		},
-		models:   GetAvailableModels(),
-		usage:    []Usage{},
-		registry: provider.NewRegistry(),
-	}
-
-	// Initialize context management if enabled
-	if config.EnableContextValidation {
-		r.contextValidator = NewContextValidator()
-
-		// Create truncator with configured strategy
-		strategy := TruncationStrategy(config.TruncationStrategy)
-		if strategy == "" {
-			strategy = TruncateOldest // Default to oldest
-		}
-		r.contextTruncator = NewContextTruncator(strategy)
-	}
-
-	// Update model availability based on loaded providers
-	r.updateModelAvailability()
-
-	return r, nil
+	return NewRouterWithProviders(config, nil)
 }
 
 // NewRouterWithProviders creates a router with pre-loaded providers
@@ -63,23 +44,37 @@ func NewRouterWithProviders(config *RouterConfig, registry provider.ProviderRegi
 	if config == nil {
 		return nil, fmt.Errorf("config is required")
 	}
+	if err := validateSettings(config); err != nil {
+		return nil, err
+	}
 	if registry == nil {
 		registry = provider.NewRegistry()
 	}
 
+	models, err := LoadModelCatalog(config.ModelCatalogPath)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &Router{
-		config: config,
-		budget: &Budget{
-			LimitUSD:     config.BudgetUSD,
-			SpentUSD:     0,
-			RemainingUSD: config.BudgetUSD,
-			UsageCount:   0,
-		},
-		models:   GetAvailableModels(),
+		config:   config,
+		budget:   newBudget(config),
+		models:   models,
 		usage:    []Usage{},
 		registry: registry,
 	}
 
+	strategy, err := LookupStrategy(config.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	r.strategy = strategy
+
+	if err := r.openUsageStore(); err != nil {
+		return nil, err
+	}
+	r.openCache()
+
 	// Initialize context management if enabled
 	if config.EnableContextValidation {
 		r.contextValidator = NewContextValidator()
@@ -90,6 +85,9 @@ func NewRouterWithProviders(config *RouterConfig, registry provider.ProviderRegi
 			strategy = TruncateOldest // Default to oldest
 		}
 		r.contextTruncator = NewContextTruncator(strategy)
+		if strategy == TruncateSummarize {
+			r.contextTruncator.SetSummarizer(r.summarizeContext)
+		}
 	}
 
 	// Update model availability based on provider availability
@@ -132,14 +130,32 @@ func (r *Router) SelectModel(ctx context.Context, req RoutingRequest) (*RoutingR
 	}
 
 	// Check budget
-	if r.budget.RemainingUSD <= 0 {
-		return nil, fmt.Errorf("budget exhausted (spent: $%.2f / limit: $%.2f)", r.budget.SpentUSD, r.budget.LimitUSD)
+	if r.budget.Exhausted() {
+		return r.budgetExceeded(ctx, req, fmt.Sprintf("budget exhausted (spent: $%.2f / limit: $%.2f)", r.budget.SpentUSD, r.budget.LimitUSD))
+	}
+
+	// Reuse the pinned model when it can serve this request
+	pinned, pinBypass := r.selectPinnedModel(ctx, req)
+	if pinned != nil {
+		return pinned, nil
 	}
 
 	// Get candidate models based on hint
-	candidates := r.getCandidateModels(req)
+	candidates := r.getCandidateModels(ctx, req)
 	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no suitable models found for request")
+		if r.overProviderBudgets(ctx, req) {
+			return r.budgetExceeded(ctx, req, fmt.Sprintf("every model that can serve the request is over its provider budget (remaining: %s)", r.budget.describeProviderBudgets()))
+		}
+		return nil, r.noCandidatesError(ctx, req)
+	}
+
+	// Cheapest-above-capability bypasses the scorer entirely
+	if req.SelectionMode == SelectionModeCheapestAboveCapability {
+		result, err := r.selectCheapestAboveCapability(candidates, req)
+		return notePinBypass(result, pinBypass), err
+	}
+	if req.SelectionMode != SelectionModeScored {
+		return nil, fmt.Errorf("unknown selection mode %q", req.SelectionMode)
 	}
 
 	// Score and rank candidates
@@ -152,35 +168,115 @@ func (r *Router) SelectModel(ctx context.Context, req RoutingRequest) (*RoutingR
 	best := scored[0]
 
 	// Estimate cost
-	estimatedTokens := r.estimateTokens(req)
+	estimatedTokens := r.estimateTokens(req, best)
 	estimatedCost := (float64(estimatedTokens) / 1000000.0) * best.CostPerMToken
 
 	// Check if estimated cost exceeds budget
-	if estimatedCost > r.budget.RemainingUSD {
-		// Try to find a cheaper model
+	if !r.budget.Allows(estimatedCost) {
+		// Try to find a cheaper model that fits
+		detail := fmt.Sprintf("estimated cost ($%.2f) exceeds remaining budget ($%.2f)", estimatedCost, r.budget.RemainingUSD)
 		cheaper := r.findCheaperModel(candidates, estimatedCost)
-		if cheaper != nil {
-			best = cheaper
-			estimatedCost = (float64(estimatedTokens) / 1000000.0) * best.CostPerMToken
-		} else {
-			return nil, fmt.Errorf("estimated cost ($%.2f) exceeds remaining budget ($%.2f)", estimatedCost, r.budget.RemainingUSD)
+		if cheaper == nil {
+			return r.budgetExceeded(ctx, req, detail)
 		}
+		best = cheaper
+		estimatedTokens = r.estimateTokens(req, best)
+		estimatedCost = (float64(estimatedTokens) / 1000000.0) * best.CostPerMToken
+		if !r.budget.Allows(estimatedCost) {
+			return r.budgetExceeded(ctx, req, detail)
+		}
+	}
+
+	// Split traffic between the control model and canary models
+	control := best
+	best, arm := r.selectCanary(candidates, req, control)
+	if best != control {
+		estimatedTokens = r.estimateTokens(req, best)
+		estimatedCost = (float64(estimatedTokens) / 1000000.0) * best.CostPerMToken
 	}
 
 	reason := r.buildSelectionReason(best, req)
+	if arm == ArmCanary {
+		reason += fmt.Sprintf(" (canary at %.0f%%, control: %s)", r.config.CanaryWeights[best.ID]*100, control.ID)
+	}
 
-	return &RoutingResult{
+	return notePinBypass(&RoutingResult{
 		Model:           best,
 		Reason:          reason,
 		EstimatedCost:   estimatedCost,
 		EstimatedTokens: estimatedTokens,
+		Arm:             arm,
+	}, pinBypass), nil
+}
+
+// selectCheapestAboveCapability returns the lowest-cost candidate that meets
+// the capability floor, fits the context size, and fits the remaining budget.
+// Ties go to the more capable model, then to the lower model ID.
+func (r *Router) selectCheapestAboveCapability(candidates []Model, req RoutingRequest) (*RoutingResult, error) {
+	var best *Model
+	for i := range candidates {
+		m := &candidates[i]
+		if m.CapabilityScore < req.MinCapability {
+			continue
+		}
+		if req.ContextSize > 0 && m.ContextWindow < req.ContextSize {
+			continue
+		}
+		if !r.budget.Allows((float64(r.estimateTokens(req, m)) / 1000000.0) * m.CostPerMToken) {
+			continue
+		}
+
+		if best == nil ||
+			m.CostPerMToken < best.CostPerMToken ||
+			(m.CostPerMToken == best.CostPerMToken && m.CapabilityScore > best.CapabilityScore) ||
+			(m.CostPerMToken == best.CostPerMToken && m.CapabilityScore == best.CapabilityScore && m.ID < best.ID) {
+			best = m
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no model with capability >= %.0f fits the request context and remaining budget ($%.2f)", req.MinCapability, r.budget.RemainingUSD)
+	}
+
+	reason := fmt.Sprintf("Selected %s (%s): cheapest model with capability >= %.0f (capability %.0f, $%.2f/M tokens)",
+		best.ID, best.Provider, req.MinCapability, best.CapabilityScore, best.CostPerMToken)
+
+	estimatedTokens := r.estimateTokens(req, best)
+	return &RoutingResult{
+		Model:           best,
+		Reason:          reason,
+		EstimatedCost:   (float64(estimatedTokens) / 1000000.0) * best.CostPerMToken,
+		EstimatedTokens: estimatedTokens,
 	}, nil
 }
 
 // getCandidateModels filters models based on routing request
-func (r *Router) getCandidateModels(req RoutingRequest) []Model {
+func (r *Router) getCandidateModels(ctx context.Context, req RoutingRequest) []Model {
+	return r.candidateModels(ctx, req, true, true)
+}
+
+// candidateModels filters models based on routing request, optionally
+// ignoring the routing policy's allowed models or the provider budgets
+func (r *Router) candidateModels(ctx context.Context, req RoutingRequest, enforcePolicy, enforceProviderBudgets bool) []Model {
 	var candidates []Model
 
+	// Probe each provider at most once per selection
+	usable := make(map[Provider]bool)
+	isUsable := func(m Model) bool {
+		if !m.Available || !r.familyAllowed(m) || (enforcePolicy && !r.policyAllowsModel(m)) {
+			return false
+		}
+		if enforceProviderBudgets && !r.withinProviderBudget(req, &m) {
+			return false
+		}
+		ok, checked := usable[m.Provider]
+		if !checked {
+			ok = r.isProviderUsable(ctx, m.Provider)
+			usable[m.Provider] = ok
+		}
+		return ok
+	}
+
 	// Map hint to model type
 	var preferredType ModelType
 	switch strings.ToLower(req.ModelHint) {
@@ -199,7 +295,7 @@ func (r *Router) getCandidateModels(req RoutingRequest) []Model {
 	// Filter by type if specified
 	if preferredType != "" {
 		for _, m := range r.models {
-			if m.Available && m.Type == preferredType {
+			if m.Type == preferredType && isUsable(m) {
 				candidates = append(candidates, m)
 			}
 		}
@@ -208,7 +304,7 @@ func (r *Router) getCandidateModels(req RoutingRequest) []Model {
 	// If no candidates or no hint, use all available models
 	if len(candidates) == 0 {
 		for _, m := range r.models {
-			if m.Available {
+			if isUsable(m) {
 				candidates = append(candidates, m)
 			}
 		}
@@ -243,77 +339,107 @@ func (r *Router) getCandidateModels(req RoutingRequest) []Model {
 	return candidates
 }
 
-// scoreModels ranks candidate models based on request requirements
-func (r *Router) scoreModels(candidates []Model, req RoutingRequest) []*Model {
-	type scoredModel struct {
-		model *Model
-		score float64
-	}
-
-	var scored []scoredModel
-
-	for i := range candidates {
-		m := &candidates[i]
-		score := 0.0
-
-		// Base score from capability
-		score += m.CapabilityScore
-
-		// Boost for P0 tasks - use best models
-		if req.Priority == "P0" {
-			score += 20
-		}
-
-		// Complexity adjustment
-		if req.Complexity >= 7 {
-			// High complexity - prefer capable models
-			score += m.CapabilityScore * 0.3
-		} else {
-			// Low complexity - cost matters more
-			if r.config.PreferCheap {
-				// Inverse cost score (cheaper is better)
-				maxCost := 10.0 // Reference max cost
-				costScore := (maxCost - m.CostPerMToken) / maxCost * 30
-				score += costScore
+// familyAllowed applies the configured family allow and deny lists
+func (r *Router) familyAllowed(m Model) bool {
+	if len(r.config.AllowFamilies) > 0 {
+		allowed := false
+		for _, family := range r.config.AllowFamilies {
+			if m.InFamily(family) {
+				allowed = true
+				break
 			}
 		}
-
-		// Penalize high latency models if latency matters
-		if r.config.MaxLatencyMs > 0 && m.MaxLatencyMs > r.config.MaxLatencyMs/2 {
-			score -= 10
+		if !allowed {
+			return false
 		}
-
-		scored = append(scored, scoredModel{model: m, score: score})
 	}
 
-	// Sort by score (descending)
-	for i := 0; i < len(scored); i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[j].score > scored[i].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
+	for _, family := range r.config.DenyFamilies {
+		if m.InFamily(family) {
+			return false
 		}
 	}
 
+	return true
+}
+
+// scoreModels ranks candidate models using the configured selection strategy
+func (r *Router) scoreModels(candidates []Model, req RoutingRequest) []*Model {
+	ranked := r.rankCandidates(candidates, req)
+
 	// Extract models
-	result := make([]*Model, len(scored))
-	for i, sm := range scored {
+	result := make([]*Model, len(ranked))
+	for i, sm := range ranked {
 		result[i] = sm.model
 	}
 
 	return result
 }
 
-// estimateTokens estimates token usage for a request
-func (r *Router) estimateTokens(req RoutingRequest) int {
-	// Base estimation
-	baseTokens := 1000
+// scoredModel is a candidate model with its strategy score
+type scoredModel struct {
+	model *Model
+	score float64
+}
+
+// rankCandidates scores candidates with the configured selection strategy,
+// highest score first
+func (r *Router) rankCandidates(candidates []Model, req RoutingRequest) []scoredModel {
+	strategy := r.strategy
+	if strategy == nil {
+		strategy = DefaultStrategy
+	}
+
+	scored := make([]scoredModel, 0, len(candidates))
+	for i := range candidates {
+		m := &candidates[i]
+		scored = append(scored, scoredModel{model: m, score: strategy.Score(*m, req, r.config)})
+	}
+
+	// Sort by score (descending), keeping catalog order for ties
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	return scored
+}
+
+// RankModels returns the candidate models for a request as SelectModel ranks
+// them, with each model's score and estimated cost. It selects nothing and
+// sends no requests, so it can be used to debug routing decisions.
+func (r *Router) RankModels(ctx context.Context, req RoutingRequest) []RankedModel {
+	candidates := r.getCandidateModels(ctx, req)
+	scored := r.rankCandidates(candidates, req)
+
+	ranked := make([]RankedModel, len(scored))
+	for i, sm := range scored {
+		estimatedTokens := r.estimateTokens(req, sm.model)
+		ranked[i] = RankedModel{
+			Model:           *sm.model,
+			Score:           sm.score,
+			EstimatedTokens: estimatedTokens,
+			EstimatedCost:   (float64(estimatedTokens) / 1000000.0) * sm.model.CostPerMToken,
+		}
+	}
+	return ranked
+}
+
+// estimateTokens estimates token usage for a request on a model
+func (r *Router) estimateTokens(req RoutingRequest, m *Model) int {
+	var baseTokens int
+	if req.Input != "" {
+		// Count the request text with the model's tokenizer
+		baseTokens = tokenizer.ForModel(string(m.Provider), m.Name).Count(req.Input)
+	} else {
+		// Base estimation
+		baseTokens = 1000
 
-	// Add tokens based on complexity
-	baseTokens += req.Complexity * 500
+		// Add tokens based on complexity
+		baseTokens += req.Complexity * 500
 
-	// Add context size
-	baseTokens += req.ContextSize
+		// Add context size
+		baseTokens += req.ContextSize
+	}
 
 	// Response size estimate (output tokens)
 	responseTokens := baseTokens / 2
@@ -328,7 +454,7 @@ func (r *Router) findCheaperModel(candidates []Model, maxCost float64) *Model {
 
 	for i := range candidates {
 		m := &candidates[i]
-		cost := (float64(r.estimateTokens(RoutingRequest{})) / 1000000.0) * m.CostPerMToken
+		cost := (float64(r.estimateTokens(RoutingRequest{}, m)) / 1000000.0) * m.CostPerMToken
 		if cost < minCost {
 			minCost = cost
 			cheapest = m
@@ -358,6 +484,14 @@ func (r *Router) buildSelectionReason(model *Model, req RoutingRequest) string {
 		reasons = append(reasons, "budget-optimized selection")
 	}
 
+	if len(r.config.AllowFamilies) > 0 {
+		reasons = append(reasons, fmt.Sprintf("allowed family: %s", model.FamilyName()))
+	}
+
+	if r.config.Strategy != "" {
+		reasons = append(reasons, fmt.Sprintf("strategy: %s", strings.ToLower(strings.TrimSpace(r.config.Strategy))))
+	}
+
 	if len(reasons) == 0 {
 		reasons = append(reasons, "best overall capability")
 	}
@@ -374,16 +508,25 @@ func (r *Router) RecordUsage(ctx context.Context, usage Usage) error {
 	default:
 	}
 
+	if usage.Timestamp.IsZero() {
+		usage.Timestamp = time.Now()
+	}
+
 	// Update budget
-	r.budget.SpentUSD += usage.CostUSD
-	r.budget.RemainingUSD = r.budget.LimitUSD - r.budget.SpentUSD
-	r.budget.UsageCount++
+	r.budget.record(usage.Provider, usage.CostUSD)
 
 	// Store usage
 	r.usage = append(r.usage, usage)
+	r.pinUsedModel(usage)
 
 	recordUsageMetrics(usage)
 
+	if r.store != nil {
+		if err := r.store.append(usage); err != nil {
+			return fmt.Errorf("persist usage: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -399,6 +542,14 @@ func (r *Router) GetUsageStats() map[string]interface{} {
 	stats["total_requests"] = len(r.usage)
 	stats["budget_spent"] = r.budget.SpentUSD
 	stats["budget_remaining"] = r.budget.RemainingUSD
+	stats["budget_unlimited"] = r.budget.Unlimited
+
+	// Remaining budget of each capped provider
+	providerRemaining := make(map[Provider]float64)
+	for p := range r.budget.ProviderLimitUSD {
+		providerRemaining[p], _ = r.budget.ProviderRemaining(p)
+	}
+	stats["provider_budget_remaining"] = providerRemaining
 
 	// Model usage counts
 	modelCounts := make(map[string]int)
@@ -413,10 +564,53 @@ func (r *Router) GetUsageStats() map[string]interface{} {
 		providerCounts[u.Provider]++
 	}
 	stats["provider_usage"] = providerCounts
+	// Canary arm usage
+	armCounts := make(map[string]int)
+	for _, u := range r.usage {
+		if u.Arm != "" {
+			armCounts[u.Arm]++
+		}
+	}
+	stats["arm_usage"] = armCounts
+
+	// Responses served from the cache
+	cacheHits := 0
+	for _, u := range r.usage {
+		if u.Cached {
+			cacheHits++
+		}
+	}
+	stats["cache_hits"] = cacheHits
+	stats["pinned_model"] = r.PinnedModel()
+	stats["circuit_breakers"] = r.health.all()
 
 	return stats
 }
 
+// GetCostByFeature returns spend per feature. Usage without a feature ID is
+// not included; in specular auto only FeatureStep custom steps set one.
+func (r *Router) GetCostByFeature() map[types.FeatureID]float64 {
+	costs := make(map[types.FeatureID]float64)
+	for _, u := range r.usage {
+		if u.FeatureID != "" {
+			costs[u.FeatureID] += u.CostUSD
+		}
+	}
+	return costs
+}
+
+// GetCostByStep returns spend per workflow step type. Usage without a step
+// type is not included.
+func (r *Router) GetCostByStep() map[string]float64 {
+	costs := make(map[string]float64)
+	for _, u := range r.usage {
+		if u.StepType != "" {
+			costs[u.StepType] += u.CostUSD
+		}
+	}
+	return costs
+}
+
 func recordUsageMetrics(usage Usage) {
 	m := metrics.GetDefault()
 	if m == nil {
@@ -442,12 +636,17 @@ func recordUsageMetrics(usage Usage) {
 func (r *Router) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
 	startTime := time.Now()
 
+	// Fall back to the hint's default system prompt
+	req.SystemPrompt = r.systemPromptFor(req)
+
 	// Select the best model for this request
 	routing := RoutingRequest{
-		ModelHint:   req.ModelHint,
-		Complexity:  req.Complexity,
-		Priority:    req.Priority,
-		ContextSize: req.ContextSize,
+		ModelHint:        req.ModelHint,
+		Complexity:       req.Complexity,
+		Priority:         req.Priority,
+		ContextSize:      req.ContextSize,
+		Input:            requestText(req),
+		OnBudgetExceeded: req.OnBudgetExceeded,
 	}
 
 	result, err := r.SelectModel(ctx, routing)
@@ -461,7 +660,7 @@ func (r *Router) Generate(ctx context.Context, req GenerateRequest) (*GenerateRe
 		if validationErr != nil {
 			// Try auto-truncation if enabled
 			if r.config.AutoTruncate && r.contextTruncator != nil {
-				truncatedReq, truncated, truncErr := r.contextTruncator.TruncateRequest(&req, result.Model)
+				truncatedReq, truncated, truncErr := r.contextTruncator.TruncateRequestContext(ctx, &req, result.Model)
 				if truncErr != nil {
 					return nil, fmt.Errorf("context validation failed and truncation failed: %w", truncErr)
 				}
@@ -476,38 +675,52 @@ func (r *Router) Generate(ctx context.Context, req GenerateRequest) (*GenerateRe
 		}
 	}
 
+	// Serve identical deterministic requests from the cache
+	cacheKey := r.cacheKeyFor(req, result.Model)
+	if entry, ok := r.cachedResponse(ctx, req, cacheKey, startTime); ok {
+		return cachedGenerateResponse(entry, result, startTime), nil
+	}
+
 	// Try primary provider with retries
 	provResp, err := r.generateWithRetry(ctx, req, result)
 	if err != nil {
 		// If fallback is enabled, try alternative providers
 		if r.config.EnableFallback {
-			return r.generateWithFallback(ctx, req, result, startTime)
+			resp, fallbackErr := r.generateWithFallback(ctx, req, result, startTime)
+			if fallbackErr == nil {
+				r.storeResponse(cacheKey, resp)
+			}
+			return resp, fallbackErr
 		}
 		return nil, fmt.Errorf("generation failed: %w", err)
 	}
 
 	// Calculate actual cost
-	actualCost := (float64(provResp.TokensUsed) / 1000000.0) * result.Model.CostPerMToken
+	tokensUsed := usedTokens(provResp.TokensUsed, req, provResp.Content, result.Model)
+	actualCost := (float64(tokensUsed) / 1000000.0) * result.Model.CostPerMToken
 
 	// Record usage
 	usage := Usage{
 		Model:     result.Model.ID,
 		Provider:  result.Model.Provider,
-		Tokens:    provResp.TokensUsed,
+		Tokens:    tokensUsed,
 		CostUSD:   actualCost,
 		LatencyMs: int(time.Since(startTime).Milliseconds()),
 		Timestamp: time.Now(),
 		TaskID:    req.TaskID,
+		FeatureID: req.FeatureID,
+		StepType:  req.StepType,
 		Success:   provResp.Error == "",
+		Arm:       result.Arm,
 	}
 	_ = r.RecordUsage(ctx, usage) // Best effort usage recording
 
 	// Build response
-	return &GenerateResponse{
+	resp := &GenerateResponse{
 		Content:         provResp.Content,
 		Model:           result.Model.ID,
 		Provider:        result.Model.Provider,
-		TokensUsed:      provResp.TokensUsed,
+		TokensUsed:      tokensUsed,
 		InputTokens:     provResp.InputTokens,
 		OutputTokens:    provResp.OutputTokens,
 		CostUSD:         actualCost,
@@ -516,19 +729,29 @@ func (r *Router) Generate(ctx context.Context, req GenerateRequest) (*GenerateRe
 		SelectionReason: result.Reason,
 		ToolCalls:       provResp.ToolCalls,
 		Error:           provResp.Error,
-	}, nil
+
+		SummarizedMessages: req.SummarizedMessages,
+		Arm:                result.Arm,
+	}
+	r.storeResponse(cacheKey, resp)
+	return resp, nil
 }
 
 // Stream sends a prompt and returns a streaming response with retry and fallback
 func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan StreamChunk, error) {
 	startTime := time.Now()
 
+	// Fall back to the hint's default system prompt
+	req.SystemPrompt = r.systemPromptFor(req)
+
 	// Select the best model for this request
 	routing := RoutingRequest{
-		ModelHint:   req.ModelHint,
-		Complexity:  req.Complexity,
-		Priority:    req.Priority,
-		ContextSize: req.ContextSize,
+		ModelHint:        req.ModelHint,
+		Complexity:       req.Complexity,
+		Priority:         req.Priority,
+		ContextSize:      req.ContextSize,
+		Input:            requestText(req),
+		OnBudgetExceeded: req.OnBudgetExceeded,
 	}
 
 	result, err := r.SelectModel(ctx, routing)
@@ -542,7 +765,7 @@ func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan Stream
 		if validationErr != nil {
 			// Try auto-truncation if enabled
 			if r.config.AutoTruncate && r.contextTruncator != nil {
-				truncatedReq, truncated, truncErr := r.contextTruncator.TruncateRequest(&req, result.Model)
+				truncatedReq, truncated, truncErr := r.contextTruncator.TruncateRequestContext(ctx, &req, result.Model)
 				if truncErr != nil {
 					return nil, fmt.Errorf("context validation failed and truncation failed: %w", truncErr)
 				}
@@ -557,6 +780,12 @@ func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan Stream
 		}
 	}
 
+	// Replay identical deterministic requests from the cache
+	cacheKey := r.cacheKeyFor(req, result.Model)
+	if entry, ok := r.cachedResponse(ctx, req, cacheKey, startTime); ok {
+		return replayStream(entry), nil
+	}
+
 	// Try primary provider with retries
 	provStream, streamResult, err := r.streamWithRetry(ctx, req, result)
 	if err != nil {
@@ -574,6 +803,7 @@ func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan Stream
 	go func() {
 		defer close(outChan)
 		var totalTokens int
+		var content strings.Builder
 
 		for chunk := range provStream {
 			outChan <- StreamChunk{
@@ -583,10 +813,14 @@ func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan Stream
 				Error:   chunk.Error,
 			}
 
+			content.WriteString(chunk.Delta)
 			if chunk.Done {
 				totalTokens = chunk.TokensUsed
 			}
 		}
+		if content.Len() > 0 {
+			totalTokens = usedTokens(totalTokens, req, content.String(), streamResult.Model)
+		}
 
 		// Record usage after stream completes
 		if totalTokens > 0 {
@@ -599,13 +833,53 @@ func (r *Router) Stream(ctx context.Context, req GenerateRequest) (<-chan Stream
 				LatencyMs: int(time.Since(startTime).Milliseconds()),
 				Timestamp: time.Now(),
 				TaskID:    req.TaskID,
+				FeatureID: req.FeatureID,
+				StepType:  req.StepType,
 				Success:   true,
+				Arm:       streamResult.Arm,
 			}
 			_ = r.RecordUsage(ctx, usage) // Best effort usage recording
 		}
 	}()
 
-	return outChan, nil
+	return r.cacheStream(cacheKey, streamResult.Model, outChan), nil
+}
+
+// requestText joins the text a request sends to the model, for counting
+// its tokens
+func requestText(req GenerateRequest) string {
+	var b strings.Builder
+	b.WriteString(req.SystemPrompt)
+	for _, msg := range req.Context {
+		b.WriteString("\n")
+		b.WriteString(msg.Content)
+	}
+	b.WriteString("\n")
+	b.WriteString(req.Prompt)
+	return b.String()
+}
+
+// usedTokens returns the tokens a provider reported, or counts the request
+// and response with the model's tokenizer when it reported none
+func usedTokens(reported int, req GenerateRequest, content string, m *Model) int {
+	if reported > 0 {
+		return reported
+	}
+	tok := tokenizer.ForModel(string(m.Provider), m.Name)
+	return tok.Count(requestText(req)) + tok.Count(content)
+}
+
+// systemPromptFor returns the request's system prompt, or the configured
+// default for its model hint when the request has none
+func (r *Router) systemPromptFor(req GenerateRequest) string {
+	if req.SystemPrompt != "" || len(r.config.DefaultSystemPrompts) == 0 {
+		return req.SystemPrompt
+	}
+
+	if prompt, ok := r.config.DefaultSystemPrompts[strings.ToLower(strings.TrimSpace(req.ModelHint))]; ok {
+		return prompt
+	}
+	return r.config.DefaultSystemPrompts["default"]
 }
 
 // getProviderName maps router Provider to registry provider name
@@ -660,17 +934,23 @@ func (r *Router) generateWithRetry(ctx context.Context, req GenerateRequest, res
 		MaxTokens:    req.MaxTokens,
 		Temperature:  req.Temperature,
 		TopP:         req.TopP,
-		Tools:        req.Tools,
+		Tools:        r.allowedTools(req.Tools),
 		Context:      req.Context,
 		Config: map[string]interface{}{
 			"model": result.Model.Name,
 		},
 		Metadata: map[string]string{
-			"task_id":  req.TaskID.String(),
-			"hint":     req.ModelHint,
-			"priority": req.Priority,
+			"task_id":    req.TaskID.String(),
+			"feature_id": string(req.FeatureID),
+			"step_type":  req.StepType,
+			"hint":       req.ModelHint,
+			"priority":   req.Priority,
 		},
 	}
+	if req.SummarizedMessages > 0 {
+		provReq.Metadata["summarized_messages"] = strconv.Itoa(req.SummarizedMessages)
+	}
+	r.applySeed(provReq)
 
 	// Retry logic with exponential backoff
 	maxRetries := r.config.MaxRetries
@@ -686,6 +966,7 @@ func (r *Router) generateWithRetry(ctx context.Context, req GenerateRequest, res
 		// Call provider
 		provResp, err := prov.Generate(ctx, provReq)
 		if err == nil && provResp.Error == "" {
+			r.recordProviderResult(result.Model.Provider, nil)
 			return provResp, nil
 		}
 
@@ -717,6 +998,7 @@ func (r *Router) generateWithRetry(ctx context.Context, req GenerateRequest, res
 		}
 	}
 
+	r.recordProviderResult(result.Model.Provider, lastErr)
 	return nil, fmt.Errorf("all retry attempts failed (tried %d times): %w", maxRetries+1, lastErr)
 }
 
@@ -724,46 +1006,56 @@ func (r *Router) generateWithRetry(ctx context.Context, req GenerateRequest, res
 func (r *Router) generateWithFallback(ctx context.Context, req GenerateRequest, primaryResult *RoutingResult, startTime time.Time) (*GenerateResponse, error) {
 	// Get all available models sorted by score
 	routing := RoutingRequest{
-		ModelHint:   req.ModelHint,
-		Complexity:  req.Complexity,
-		Priority:    req.Priority,
-		ContextSize: req.ContextSize,
+		ModelHint:        req.ModelHint,
+		Complexity:       req.Complexity,
+		Priority:         req.Priority,
+		ContextSize:      req.ContextSize,
+		Input:            requestText(req),
+		OnBudgetExceeded: req.OnBudgetExceeded,
 	}
 
-	candidates := r.getCandidateModels(routing)
+	candidates := r.getCandidateModels(ctx, routing)
 	scored := r.scoreModels(candidates, routing)
 
-	// Try each candidate in order (skip the primary that already failed)
-	for i := range scored {
-		model := scored[i]
-		if model.ID == primaryResult.Model.ID {
-			continue // Skip primary model that already failed
+	// Try candidates in order, steering away from providers that failed
+	plan := r.newFallbackPlan(scored, primaryResult.Model)
+	var skipped []Provider
+	for {
+		var model *Model
+		model, skipped = plan.next()
+		if model == nil {
+			break
 		}
 
 		// Create result for this fallback model
 		fallbackResult := &RoutingResult{
 			Model:           model,
-			Reason:          fmt.Sprintf("Fallback after primary failure: %s", primaryResult.Model.ID),
-			EstimatedCost:   (float64(r.estimateTokens(routing)) / 1000000.0) * model.CostPerMToken,
-			EstimatedTokens: r.estimateTokens(routing),
+			Reason:          fallbackReason(model, primaryResult.Model, false, skipped),
+			EstimatedCost:   (float64(r.estimateTokens(routing, model)) / 1000000.0) * model.CostPerMToken,
+			EstimatedTokens: r.estimateTokens(routing, model),
+			Arm:             primaryResult.Arm,
 		}
 
 		// Try this fallback model with retries
 		provResp, err := r.generateWithRetry(ctx, req, fallbackResult)
 		if err == nil && provResp.Error == "" {
 			// Success with fallback!
-			actualCost := (float64(provResp.TokensUsed) / 1000000.0) * model.CostPerMToken
+			tokensUsed := usedTokens(provResp.TokensUsed, req, provResp.Content, model)
+			actualCost := (float64(tokensUsed) / 1000000.0) * model.CostPerMToken
 
 			// Record usage
 			usage := Usage{
 				Model:     model.ID,
 				Provider:  model.Provider,
-				Tokens:    provResp.TokensUsed,
+				Tokens:    tokensUsed,
 				CostUSD:   actualCost,
 				LatencyMs: int(time.Since(startTime).Milliseconds()),
 				Timestamp: time.Now(),
 				TaskID:    req.TaskID,
+				FeatureID: req.FeatureID,
+				StepType:  req.StepType,
 				Success:   true,
+				Arm:       primaryResult.Arm,
 			}
 			_ = r.RecordUsage(ctx, usage) // Best effort usage recording
 
@@ -771,19 +1063,27 @@ func (r *Router) generateWithFallback(ctx context.Context, req GenerateRequest,
 				Content:         provResp.Content,
 				Model:           model.ID,
 				Provider:        model.Provider,
-				TokensUsed:      provResp.TokensUsed,
+				TokensUsed:      tokensUsed,
 				InputTokens:     provResp.InputTokens,
 				OutputTokens:    provResp.OutputTokens,
 				CostUSD:         actualCost,
 				Latency:         provResp.Latency,
 				FinishReason:    provResp.FinishReason,
-				SelectionReason: fmt.Sprintf("Fallback: %s (primary %s failed)", model.ID, primaryResult.Model.ID),
+				SelectionReason: fallbackResult.Reason,
 				ToolCalls:       provResp.ToolCalls,
 				Error:           provResp.Error,
+
+				SummarizedMessages: req.SummarizedMessages,
+				Arm:                primaryResult.Arm,
 			}, nil
 		}
+
+		plan.markFailed(model)
 	}
 
+	if len(skipped) > 0 {
+		return nil, fmt.Errorf("all fallback providers failed (skipped failed providers: %s)", joinProviders(skipped))
+	}
 	return nil, fmt.Errorf("all fallback providers failed")
 }
 
@@ -853,17 +1153,23 @@ func (r *Router) streamWithRetry(ctx context.Context, req GenerateRequest, resul
 		MaxTokens:    req.MaxTokens,
 		Temperature:  req.Temperature,
 		TopP:         req.TopP,
-		Tools:        req.Tools,
+		Tools:        r.allowedTools(req.Tools),
 		Context:      req.Context,
 		Config: map[string]interface{}{
 			"model": result.Model.Name,
 		},
 		Metadata: map[string]string{
-			"task_id":  req.TaskID.String(),
-			"hint":     req.ModelHint,
-			"priority": req.Priority,
+			"task_id":    req.TaskID.String(),
+			"feature_id": string(req.FeatureID),
+			"step_type":  req.StepType,
+			"hint":       req.ModelHint,
+			"priority":   req.Priority,
 		},
 	}
+	if req.SummarizedMessages > 0 {
+		provReq.Metadata["summarized_messages"] = strconv.Itoa(req.SummarizedMessages)
+	}
+	r.applySeed(provReq)
 
 	// Retry logic with exponential backoff
 	maxRetries := r.config.MaxRetries
@@ -879,6 +1185,7 @@ func (r *Router) streamWithRetry(ctx context.Context, req GenerateRequest, resul
 		// Call provider stream
 		provStream, err := prov.Stream(ctx, provReq)
 		if err == nil {
+			r.recordProviderResult(result.Model.Provider, nil)
 			return provStream, result, nil
 		}
 
@@ -907,6 +1214,7 @@ func (r *Router) streamWithRetry(ctx context.Context, req GenerateRequest, resul
 		}
 	}
 
+	r.recordProviderResult(result.Model.Provider, lastErr)
 	return nil, nil, fmt.Errorf("all streaming retry attempts failed (tried %d times): %w", maxRetries+1, lastErr)
 }
 
@@ -914,28 +1222,34 @@ func (r *Router) streamWithRetry(ctx context.Context, req GenerateRequest, resul
 func (r *Router) streamWithFallback(ctx context.Context, req GenerateRequest, primaryResult *RoutingResult, startTime time.Time) (<-chan StreamChunk, error) {
 	// Get all available models sorted by score
 	routing := RoutingRequest{
-		ModelHint:   req.ModelHint,
-		Complexity:  req.Complexity,
-		Priority:    req.Priority,
-		ContextSize: req.ContextSize,
+		ModelHint:        req.ModelHint,
+		Complexity:       req.Complexity,
+		Priority:         req.Priority,
+		ContextSize:      req.ContextSize,
+		Input:            requestText(req),
+		OnBudgetExceeded: req.OnBudgetExceeded,
 	}
 
-	candidates := r.getCandidateModels(routing)
+	candidates := r.getCandidateModels(ctx, routing)
 	scored := r.scoreModels(candidates, routing)
 
-	// Try each candidate in order (skip the primary that already failed)
-	for i := range scored {
-		model := scored[i]
-		if model.ID == primaryResult.Model.ID {
-			continue // Skip primary model that already failed
+	// Try candidates in order, steering away from providers that failed
+	plan := r.newFallbackPlan(scored, primaryResult.Model)
+	var skipped []Provider
+	for {
+		var model *Model
+		model, skipped = plan.next()
+		if model == nil {
+			break
 		}
 
 		// Create result for this fallback model
 		fallbackResult := &RoutingResult{
 			Model:           model,
-			Reason:          fmt.Sprintf("Fallback after primary streaming failure: %s", primaryResult.Model.ID),
-			EstimatedCost:   (float64(r.estimateTokens(routing)) / 1000000.0) * model.CostPerMToken,
-			EstimatedTokens: r.estimateTokens(routing),
+			Reason:          fallbackReason(model, primaryResult.Model, true, skipped),
+			EstimatedCost:   (float64(r.estimateTokens(routing, model)) / 1000000.0) * model.CostPerMToken,
+			EstimatedTokens: r.estimateTokens(routing, model),
+			Arm:             primaryResult.Arm,
 		}
 
 		// Try this fallback model with retries
@@ -949,6 +1263,7 @@ func (r *Router) streamWithFallback(ctx context.Context, req GenerateRequest, pr
 			go func() {
 				defer close(outChan)
 				var totalTokens int
+				var content strings.Builder
 
 				for chunk := range provStream {
 					outChan <- StreamChunk{
@@ -958,10 +1273,14 @@ func (r *Router) streamWithFallback(ctx context.Context, req GenerateRequest, pr
 						Error:   chunk.Error,
 					}
 
+					content.WriteString(chunk.Delta)
 					if chunk.Done {
 						totalTokens = chunk.TokensUsed
 					}
 				}
+				if content.Len() > 0 {
+					totalTokens = usedTokens(totalTokens, req, content.String(), model)
+				}
 
 				// Record usage after stream completes
 				if totalTokens > 0 {
@@ -974,7 +1293,10 @@ func (r *Router) streamWithFallback(ctx context.Context, req GenerateRequest, pr
 						LatencyMs: int(time.Since(startTime).Milliseconds()),
 						Timestamp: time.Now(),
 						TaskID:    req.TaskID,
+						FeatureID: req.FeatureID,
+						StepType:  req.StepType,
 						Success:   true,
+						Arm:       primaryResult.Arm,
 					}
 					_ = r.RecordUsage(ctx, usage) // Best effort usage recording
 				}
@@ -985,7 +1307,11 @@ func (r *Router) streamWithFallback(ctx context.Context, req GenerateRequest, pr
 
 		// Continue to next fallback if this one failed
 		_ = streamResult // Avoid unused variable warning
+		plan.markFailed(model)
 	}
 
+	if len(skipped) > 0 {
+		return nil, fmt.Errorf("all fallback providers failed for streaming (skipped failed providers: %s)", joinProviders(skipped))
+	}
 	return nil, fmt.Errorf("all fallback providers failed for streaming")
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/felixgeelhaar/specular/internal/provider"
+	"github.com/felixgeelhaar/specular/internal/tokenizer"
 )
 
 func TestNewRouter(t *testing.T) {
@@ -69,6 +70,16 @@ func TestNewRouter(t *testing.T) {
 			config:  nil,
 			wantErr: true,
 		},
+		{
+			name:    "invalid family pattern",
+			config:  &RouterConfig{BudgetUSD: 20.0, AllowFamilies: []string{"claude-["}},
+			wantErr: true,
+		},
+		{
+			name:    "negative max latency",
+			config:  &RouterConfig{BudgetUSD: 20.0, MaxLatencyMs: -1},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -487,6 +498,65 @@ func TestUsageStats(t *testing.T) {
 	}
 }
 
+func TestCostBreakdown(t *testing.T) {
+	router, _ := NewRouter(&RouterConfig{BudgetUSD: 100.0})
+	ctx := context.Background()
+
+	for _, u := range []Usage{
+		{Model: "claude-sonnet-4", CostUSD: 0.10, FeatureID: "user-auth", StepType: "spec:update", Success: true},
+		{Model: "claude-sonnet-4", CostUSD: 0.25, FeatureID: "user-auth", StepType: "build:run", Success: true},
+		{Model: "gpt-4o", CostUSD: 0.05, FeatureID: "billing", StepType: "build:run", Success: true},
+		{Model: "gpt-4o", CostUSD: 0.40, Success: true},
+	} {
+		_ = router.RecordUsage(ctx, u)
+	}
+
+	byFeature := router.GetCostByFeature()
+	if len(byFeature) != 2 {
+		t.Errorf("GetCostByFeature() = %v, want 2 features", byFeature)
+	}
+	if got := byFeature["user-auth"]; got < 0.349 || got > 0.351 {
+		t.Errorf("user-auth cost = %v, want 0.35", got)
+	}
+	if got := byFeature["billing"]; got != 0.05 {
+		t.Errorf("billing cost = %v, want 0.05", got)
+	}
+
+	byStep := router.GetCostByStep()
+	if len(byStep) != 2 {
+		t.Errorf("GetCostByStep() = %v, want 2 steps", byStep)
+	}
+	if got := byStep["build:run"]; got < 0.299 || got > 0.301 {
+		t.Errorf("build:run cost = %v, want 0.30", got)
+	}
+}
+
+func TestGenerate_RecordsFeatureAndStep(t *testing.T) {
+	r := newFallbackTestRouter(t, &RouterConfig{}, map[string]*modelRecordingProvider{
+		"anthropic": {},
+	}, fallbackTestModels())
+
+	_, err := r.Generate(context.Background(), GenerateRequest{
+		Prompt:    "hi",
+		ModelHint: "agentic",
+		FeatureID: "user-auth",
+		StepType:  "build:run",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(r.usage) != 1 {
+		t.Fatalf("recorded %d usage entries, want 1", len(r.usage))
+	}
+	if u := r.usage[0]; u.FeatureID != "user-auth" || u.StepType != "build:run" {
+		t.Errorf("usage = %+v, want feature and step from the request", u)
+	}
+	if _, ok := r.GetCostByFeature()["user-auth"]; !ok {
+		t.Error("GetCostByFeature() should include the request's feature")
+	}
+}
+
 func TestGetModelsByType(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -804,3 +874,231 @@ func TestSelectModel_ErrorCases(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_DefaultSystemPromptPerHint(t *testing.T) {
+	r, anthropic, openai, _ := newHealthTestRouter(t)
+	r.config.DefaultSystemPrompts = map[string]string{
+		"codegen": "You are an expert Go engineer.",
+		"docs":    "You write concise technical documentation.",
+	}
+
+	sentPrompt := func(t *testing.T, req GenerateRequest) string {
+		t.Helper()
+		anthropic.lastRequest, openai.lastRequest = nil, nil
+		if _, err := r.Generate(context.Background(), req); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		for _, p := range []*flakyProvider{anthropic, openai} {
+			if p.lastRequest != nil {
+				return p.lastRequest.SystemPrompt
+			}
+		}
+		t.Fatal("no provider received the request")
+		return ""
+	}
+
+	tests := []struct {
+		name string
+		req  GenerateRequest
+		want string
+	}{
+		{
+			name: "codegen hint without system prompt gets codegen default",
+			req:  GenerateRequest{Prompt: "write a parser", ModelHint: "codegen"},
+			want: "You are an expert Go engineer.",
+		},
+		{
+			name: "explicit system prompt is not overridden",
+			req:  GenerateRequest{Prompt: "write a parser", ModelHint: "codegen", SystemPrompt: "Be terse."},
+			want: "Be terse.",
+		},
+		{
+			name: "hint without default leaves prompt empty",
+			req:  GenerateRequest{Prompt: "classify this", ModelHint: "fast"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sentPrompt(t, tt.req); got != tt.want {
+				t.Errorf("system prompt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_FamilyAllowList(t *testing.T) {
+	r, err := NewRouter(&RouterConfig{
+		BudgetUSD:     100.0,
+		MaxLatencyMs:  60000,
+		AllowFamilies: []string{"claude-3"},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	// Several concrete versions per family, including a stronger model
+	// outside the allowed family that would otherwise win
+	r.models = []Model{
+		{ID: "claude-3-opus", Provider: ProviderAnthropic, Family: "claude-3", Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 15, MaxLatencyMs: 6000, CapabilityScore: 93, Available: true},
+		{ID: "claude-3.5-sonnet", Provider: ProviderAnthropic, Family: "claude-3", Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 3, MaxLatencyMs: 4000, CapabilityScore: 92, Available: true},
+		{ID: "claude-3.7-sonnet", Provider: ProviderAnthropic, Family: "claude-3", Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 3, MaxLatencyMs: 4000, CapabilityScore: 94, Available: true},
+		{ID: "claude-sonnet-4", Provider: ProviderAnthropic, Family: "claude-4", Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 3, MaxLatencyMs: 5000, CapabilityScore: 99, Available: true},
+		{ID: "gpt-4o-2024-05-13", Provider: ProviderOpenAI, Family: "gpt-4o", Type: ModelTypeCodegen, ContextWindow: 128000, CostPerMToken: 2.5, MaxLatencyMs: 4000, CapabilityScore: 97, Available: true},
+		{ID: "gpt-4o-2024-08-06", Provider: ProviderOpenAI, Family: "gpt-4o", Type: ModelTypeCodegen, ContextWindow: 128000, CostPerMToken: 2.5, MaxLatencyMs: 4000, CapabilityScore: 98, Available: true},
+	}
+
+	ctx := context.Background()
+	for _, hint := range []string{"codegen", "agentic", "fast", ""} {
+		req := RoutingRequest{ModelHint: hint, Complexity: 8}
+
+		candidates := r.getCandidateModels(ctx, req)
+		if len(candidates) == 0 {
+			t.Fatalf("hint %q: expected claude-3 candidates", hint)
+		}
+		for _, m := range candidates {
+			if m.Family != "claude-3" {
+				t.Errorf("hint %q: candidate %s from family %s should be filtered", hint, m.ID, m.Family)
+			}
+		}
+
+		result, err := r.SelectModel(ctx, req)
+		if err != nil {
+			t.Fatalf("hint %q: SelectModel() error = %v", hint, err)
+		}
+		if result.Model.Family != "claude-3" {
+			t.Errorf("hint %q: selected %s from family %s, want claude-3", hint, result.Model.ID, result.Model.Family)
+		}
+	}
+
+	// Deny list is applied after the allow list
+	r.config.AllowFamilies = []string{"claude-*"}
+	r.config.DenyFamilies = []string{"claude-3"}
+	result, err := r.SelectModel(ctx, RoutingRequest{ModelHint: "codegen"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "claude-sonnet-4" {
+		t.Errorf("selected %s, want claude-sonnet-4", result.Model.ID)
+	}
+}
+
+func TestModel_InFamily(t *testing.T) {
+	for _, m := range GetAvailableModels() {
+		if m.Family == "" {
+			t.Errorf("catalog model %s has no family", m.ID)
+		}
+	}
+
+	if got := len(GetModelsByFamily("gpt-4o")); got != 2 {
+		t.Errorf("GetModelsByFamily(gpt-4o) = %d models, want 2", got)
+	}
+
+	custom := Model{ID: "my-model"}
+	if !custom.InFamily("my-model") {
+		t.Error("model without family metadata should match its ID")
+	}
+	if custom.InFamily("[") {
+		t.Error("malformed pattern should not match")
+	}
+}
+
+func TestRouter_EstimateTokens(t *testing.T) {
+	t.Setenv(tokenizer.DirEnv, t.TempDir())
+
+	r := &Router{}
+	m := &Model{ID: "sonnet", Name: "claude-sonnet-4", Provider: ProviderAnthropic}
+
+	tests := []struct {
+		name string
+		req  RoutingRequest
+		want int
+	}{
+		{name: "complexity estimate", req: RoutingRequest{Complexity: 2, ContextSize: 500}, want: 3750},
+		{name: "counted input", req: RoutingRequest{Complexity: 2, Input: "Write a haiku about Go"}, want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.estimateTokens(tt.req, m); got != tt.want {
+				t.Errorf("estimateTokens() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// zeroUsageProvider answers without reporting token usage
+type zeroUsageProvider struct {
+	flakyProvider
+}
+
+func (p *zeroUsageProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	return &provider.GenerateResponse{Content: "Gophers build small tools"}, nil
+}
+
+func TestRouter_GenerateCountsUnreportedTokens(t *testing.T) {
+	t.Setenv(tokenizer.DirEnv, t.TempDir())
+
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", &zeroUsageProvider{}, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 10}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{{ID: "sonnet", Name: "claude-sonnet-4", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 200000, CostPerMToken: 3, CapabilityScore: 90, Available: true}}
+
+	req := GenerateRequest{Prompt: "Write a haiku about Go", ModelHint: "agentic"}
+	resp, err := r.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := tokenizer.Estimate().Count(requestText(req)) + tokenizer.Estimate().Count("Gophers build small tools")
+	if resp.TokensUsed != want {
+		t.Errorf("TokensUsed = %d, want %d", resp.TokensUsed, want)
+	}
+	if r.GetBudget().SpentUSD == 0 {
+		t.Error("unreported usage should still be charged to the budget")
+	}
+}
+
+func TestRouter_RankModels(t *testing.T) {
+	prov := &flakyProvider{healthy: true}
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", prov, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 10, Strategy: StrategyCheapest}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{
+		{ID: "opus", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 15, CapabilityScore: 95, Available: true},
+		{ID: "haiku", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 1, CapabilityScore: 70, Available: true},
+		{ID: "small", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 8000, CostPerMToken: 0.5, CapabilityScore: 50, Available: true},
+	}
+
+	req := RoutingRequest{ModelHint: "codegen", Complexity: 5, Priority: "P1", ContextSize: 100000}
+	ranked := r.RankModels(context.Background(), req)
+	if len(ranked) != 2 || ranked[0].Model.ID != "haiku" || ranked[1].Model.ID != "opus" {
+		t.Fatalf("RankModels() = %+v, want haiku then opus", ranked)
+	}
+	if ranked[0].Score <= ranked[1].Score {
+		t.Errorf("scores = %v, %v, want descending", ranked[0].Score, ranked[1].Score)
+	}
+
+	result, err := r.SelectModel(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != ranked[0].Model.ID || result.EstimatedCost != ranked[0].EstimatedCost {
+		t.Errorf("SelectModel() = %s ($%f), want the top ranked model %s ($%f)",
+			result.Model.ID, result.EstimatedCost, ranked[0].Model.ID, ranked[0].EstimatedCost)
+	}
+	if prov.lastRequest != nil {
+		t.Error("ranking should not send requests to providers")
+	}
+}
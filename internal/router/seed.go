@@ -0,0 +1,19 @@
+package router
+
+import "github.com/felixgeelhaar/specular/internal/provider"
+
+// Seed returns the configured sampling seed, or 0 when runs are unseeded
+func (r *Router) Seed() int64 {
+	return r.config.Seed
+}
+
+// applySeed adds the configured seed to a provider request
+func (r *Router) applySeed(req *provider.GenerateRequest) {
+	if r.config.Seed == 0 {
+		return
+	}
+	if req.Config == nil {
+		req.Config = make(map[string]interface{})
+	}
+	req.Config["seed"] = r.config.Seed
+}
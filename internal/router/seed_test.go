@@ -0,0 +1,61 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// runSeeded runs one request through a router with the given seed and
+// returns the provider request and the selected model
+func runSeeded(t *testing.T, seed int64) (*provider.GenerateRequest, string) {
+	t.Helper()
+
+	anthropic := &flakyProvider{healthy: true}
+	openai := &flakyProvider{healthy: true}
+	registry := provider.NewRegistry()
+	for name, prov := range map[string]*flakyProvider{"anthropic": anthropic, "openai": openai} {
+		if err := registry.Register(name, prov, &provider.ProviderConfig{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRouterWithProviders(&RouterConfig{BudgetUSD: 10, Seed: seed}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = fallbackTestModels()
+
+	resp, err := r.Generate(context.Background(), GenerateRequest{Prompt: "hi", ModelHint: "agentic"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, p := range []*flakyProvider{anthropic, openai} {
+		if p.lastRequest != nil {
+			return p.lastRequest, resp.Model
+		}
+	}
+	t.Fatal("no provider received the request")
+	return nil, ""
+}
+
+func TestRouter_SeedForwardedToProviders(t *testing.T) {
+	first, firstModel := runSeeded(t, 42)
+	second, secondModel := runSeeded(t, 42)
+
+	if first.Config["seed"] != int64(42) || second.Config["seed"] != int64(42) {
+		t.Errorf("seeds = %v, %v, want 42 for both runs", first.Config["seed"], second.Config["seed"])
+	}
+	if firstModel != secondModel {
+		t.Errorf("seeded runs selected %s and %s, want the same model", firstModel, secondModel)
+	}
+}
+
+func TestRouter_UnseededRequestsOmitSeed(t *testing.T) {
+	req, _ := runSeeded(t, 0)
+	if _, ok := req.Config["seed"]; ok {
+		t.Errorf("unseeded request config = %v, want no seed", req.Config)
+	}
+}
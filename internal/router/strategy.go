@@ -0,0 +1,174 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SelectionStrategy scores a candidate model for a routing request. The
+// router selects the candidate with the highest score; candidates with equal
+// scores keep their catalog order.
+type SelectionStrategy interface {
+	Score(model Model, req RoutingRequest, cfg *RouterConfig) float64
+}
+
+// SelectionStrategyFunc adapts a function to the SelectionStrategy interface
+type SelectionStrategyFunc func(model Model, req RoutingRequest, cfg *RouterConfig) float64
+
+// Score calls f(model, req, cfg)
+func (f SelectionStrategyFunc) Score(model Model, req RoutingRequest, cfg *RouterConfig) float64 {
+	return f(model, req, cfg)
+}
+
+// Built-in strategy names for RouterConfig.Strategy
+const (
+	StrategyDefault           = "default"            // Capability with priority, complexity, and prefer_cheap adjustments
+	StrategyCheapest          = "cheapest"           // Lowest cost per token
+	StrategyFastest           = "fastest"            // Lowest expected latency
+	StrategyHighestCapability = "highest-capability" // Highest capability score
+	StrategyBalanced          = "balanced"           // Weighted blend of capability, cost, and latency
+)
+
+// DefaultStrategy is the router's original scoring heuristic. It is used when
+// RouterConfig.Strategy is empty.
+var DefaultStrategy SelectionStrategy = SelectionStrategyFunc(scoreDefault)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]SelectionStrategy{
+		StrategyDefault:           DefaultStrategy,
+		StrategyCheapest:          SelectionStrategyFunc(scoreCheapest),
+		StrategyFastest:           SelectionStrategyFunc(scoreFastest),
+		StrategyHighestCapability: SelectionStrategyFunc(scoreHighestCapability),
+		StrategyBalanced:          SelectionStrategyFunc(scoreBalanced),
+	}
+)
+
+// RegisterStrategy makes a selection strategy available under name so it can
+// be chosen with RouterConfig.Strategy. Registering an existing name replaces
+// the previous strategy.
+func RegisterStrategy(name string, strategy SelectionStrategy) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("strategy name is required")
+	}
+	if strategy == nil {
+		return fmt.Errorf("strategy %q is nil", name)
+	}
+
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	strategies[name] = strategy
+	return nil
+}
+
+// LookupStrategy returns the strategy registered under name. An empty name
+// returns DefaultStrategy.
+func LookupStrategy(name string) (SelectionStrategy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultStrategy, nil
+	}
+
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown selection strategy %q (available: %s)", name, strings.Join(strategyNamesLocked(), ", "))
+	}
+	return strategy, nil
+}
+
+// StrategyNames returns the registered strategy names in sorted order
+func StrategyNames() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	return strategyNamesLocked()
+}
+
+func strategyNamesLocked() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scoreDefault favors capable models, boosts P0 tasks, and weighs cost for
+// low-complexity work when prefer_cheap is set
+func scoreDefault(m Model, req RoutingRequest, cfg *RouterConfig) float64 {
+	score := 0.0
+
+	// Base score from capability
+	score += m.CapabilityScore
+
+	// Boost for P0 tasks - use best models
+	if req.Priority == "P0" {
+		score += 20
+	}
+
+	// Complexity adjustment
+	if req.Complexity >= 7 {
+		// High complexity - prefer capable models
+		score += m.CapabilityScore * 0.3
+	} else {
+		// Low complexity - cost matters more
+		if cfg.PreferCheap {
+			// Inverse cost score (cheaper is better)
+			maxCost := 10.0 // Reference max cost
+			costScore := (maxCost - m.CostPerMToken) / maxCost * 30
+			score += costScore
+		}
+	}
+
+	// Penalize high latency models if latency matters
+	if cfg.MaxLatencyMs > 0 && m.MaxLatencyMs > cfg.MaxLatencyMs/2 {
+		score -= 10
+	}
+
+	return score
+}
+
+// scoreCheapest ranks by cost, breaking ties with capability
+func scoreCheapest(m Model, _ RoutingRequest, _ *RouterConfig) float64 {
+	return -m.CostPerMToken*100 + m.CapabilityScore/100
+}
+
+// scoreFastest ranks by expected latency, breaking ties with capability
+func scoreFastest(m Model, _ RoutingRequest, _ *RouterConfig) float64 {
+	return -float64(m.MaxLatencyMs) + m.CapabilityScore/100
+}
+
+// scoreHighestCapability ranks by capability, breaking ties with cost
+func scoreHighestCapability(m Model, _ RoutingRequest, _ *RouterConfig) float64 {
+	return m.CapabilityScore - m.CostPerMToken/100
+}
+
+// Reference points used to normalize cost and latency for the balanced strategy
+const (
+	balancedReferenceCost      = 10.0  // USD per million tokens
+	balancedReferenceLatencyMs = 10000 // Milliseconds
+)
+
+// scoreBalanced blends capability (50%), cost (25%), and latency (25%), each
+// normalized to 0-100
+func scoreBalanced(m Model, _ RoutingRequest, _ *RouterConfig) float64 {
+	capability := clampScore(m.CapabilityScore)
+	cost := clampScore((1 - m.CostPerMToken/balancedReferenceCost) * 100)
+	latency := clampScore((1 - float64(m.MaxLatencyMs)/balancedReferenceLatencyMs) * 100)
+
+	return capability*0.5 + cost*0.25 + latency*0.25
+}
+
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
@@ -0,0 +1,230 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// strategyTestModels is a small catalog where each built-in strategy picks a
+// different model
+func strategyTestModels() []Model {
+	return []Model{
+		{ID: "capable", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 9, MaxLatencyMs: 8000, CapabilityScore: 98, Available: true},
+		{ID: "balanced", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 1, MaxLatencyMs: 2000, CapabilityScore: 85, Available: true},
+		{ID: "fast", Provider: ProviderOpenAI, Type: ModelTypeCodegen, ContextWindow: 128000, CostPerMToken: 4, MaxLatencyMs: 500, CapabilityScore: 60, Available: true},
+		{ID: "cheap", Provider: ProviderLocal, Type: ModelTypeCodegen, ContextWindow: 32000, CostPerMToken: 0, MaxLatencyMs: 9000, CapabilityScore: 55, Available: true},
+	}
+}
+
+func TestSelectModel_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{"", "capable"},
+		{StrategyDefault, "capable"},
+		{StrategyCheapest, "cheap"},
+		{StrategyFastest, "fast"},
+		{StrategyHighestCapability, "capable"},
+		{StrategyBalanced, "balanced"},
+		{" Cheapest ", "cheap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			r, err := NewRouter(&RouterConfig{BudgetUSD: 100, Strategy: tt.strategy})
+			if err != nil {
+				t.Fatalf("NewRouter() error = %v", err)
+			}
+			r.models = strategyTestModels()
+
+			result, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "codegen", Complexity: 5})
+			if err != nil {
+				t.Fatalf("SelectModel() error = %v", err)
+			}
+			if result.Model.ID != tt.want {
+				t.Errorf("strategy %q selected %s, want %s", tt.strategy, result.Model.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultStrategy_MatchesLegacyScoring(t *testing.T) {
+	cfg := &RouterConfig{PreferCheap: true, MaxLatencyMs: 6000}
+	m := Model{CostPerMToken: 2, MaxLatencyMs: 4000, CapabilityScore: 80}
+
+	tests := []struct {
+		name string
+		req  RoutingRequest
+		want float64
+	}{
+		// 80 + cheap bonus (10-2)/10*30 - latency penalty 10
+		{"low complexity prefers cheap", RoutingRequest{Complexity: 3}, 94},
+		// 80 + P0 boost 20 + 80*0.3 - latency penalty 10
+		{"high complexity P0", RoutingRequest{Complexity: 8, Priority: "P0"}, 114},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultStrategy.Score(m, tt.req, cfg); got != tt.want {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	name := "test-lowest-context"
+	err := RegisterStrategy(name, SelectionStrategyFunc(func(m Model, _ RoutingRequest, _ *RouterConfig) float64 {
+		return -float64(m.ContextWindow)
+	}))
+	if err != nil {
+		t.Fatalf("RegisterStrategy() error = %v", err)
+	}
+	defer func() {
+		strategiesMu.Lock()
+		delete(strategies, name)
+		strategiesMu.Unlock()
+	}()
+
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 100, Strategy: name})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	r.models = strategyTestModels()
+
+	result, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "codegen"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if result.Model.ID != "cheap" {
+		t.Errorf("selected %s, want cheap", result.Model.ID)
+	}
+	if !strings.Contains(result.Reason, "strategy: "+name) {
+		t.Errorf("reason %q should name the strategy", result.Reason)
+	}
+
+	if err := RegisterStrategy("", DefaultStrategy); err == nil {
+		t.Error("RegisterStrategy() should reject an empty name")
+	}
+	if err := RegisterStrategy("nil-strategy", nil); err == nil {
+		t.Error("RegisterStrategy() should reject a nil strategy")
+	}
+}
+
+func TestUnknownStrategy(t *testing.T) {
+	if _, err := NewRouter(&RouterConfig{BudgetUSD: 100, Strategy: "random"}); err == nil {
+		t.Error("NewRouter() should reject an unknown strategy")
+	}
+
+	cfg := &RouterConfig{
+		Providers: []ProviderConfig{{Name: ProviderAnthropic, APIKey: "key", Enabled: true}},
+		Strategy:  "random",
+	}
+	err := ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "balanced") {
+		t.Errorf("ValidateConfig() error = %v, want unknown strategy listing the available names", err)
+	}
+}
+
+func TestSelectModel_CheapestAboveCapability(t *testing.T) {
+	models := []Model{
+		{ID: "flagship", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 15, MaxLatencyMs: 3000, CapabilityScore: 98, Available: true},
+		{ID: "mid-b", Provider: ProviderOpenAI, Type: ModelTypeCodegen, ContextWindow: 128000, CostPerMToken: 2.5, MaxLatencyMs: 4000, CapabilityScore: 82, Available: true},
+		{ID: "mid-a", Provider: ProviderOpenAI, Type: ModelTypeCodegen, ContextWindow: 128000, CostPerMToken: 2.5, MaxLatencyMs: 4000, CapabilityScore: 82, Available: true},
+		{ID: "small-window", Provider: ProviderAnthropic, Type: ModelTypeCodegen, ContextWindow: 16000, CostPerMToken: 0.5, MaxLatencyMs: 1000, CapabilityScore: 85, Available: true},
+		{ID: "weak", Provider: ProviderLocal, Type: ModelTypeCodegen, ContextWindow: 200000, CostPerMToken: 0, MaxLatencyMs: 2000, CapabilityScore: 60, Available: true},
+	}
+
+	tests := []struct {
+		name    string
+		budget  float64
+		req     RoutingRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "cheapest qualifying beats highest scoring",
+			req:  RoutingRequest{Complexity: 9, Priority: "P0", MinCapability: 80},
+			want: "small-window",
+		},
+		{
+			name: "context window is a hard requirement",
+			req:  RoutingRequest{ContextSize: 50000, MinCapability: 80},
+			want: "mid-a",
+		},
+		{
+			name: "zero floor picks the cheapest model",
+			req:  RoutingRequest{},
+			want: "weak",
+		},
+		{
+			// flagship is the only model above the floor and costs ~$2.27 here
+			name:    "models over budget are skipped",
+			budget:  0.05,
+			req:     RoutingRequest{ContextSize: 100000, MinCapability: 90},
+			wantErr: true,
+		},
+		{
+			name:    "nothing meets the floor",
+			req:     RoutingRequest{MinCapability: 99},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := tt.budget
+			if budget == 0 {
+				budget = 100
+			}
+			r, err := NewRouter(&RouterConfig{BudgetUSD: budget})
+			if err != nil {
+				t.Fatalf("NewRouter() error = %v", err)
+			}
+			r.models = models
+
+			req := tt.req
+			req.ModelHint = "codegen"
+			req.SelectionMode = SelectionModeCheapestAboveCapability
+
+			// Run repeatedly to show the choice is deterministic
+			for i := 0; i < 5; i++ {
+				result, err := r.SelectModel(context.Background(), req)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("SelectModel() selected %s, want error", result.Model.ID)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("SelectModel() error = %v", err)
+				}
+				if result.Model.ID != tt.want {
+					t.Fatalf("selected %s, want %s", result.Model.ID, tt.want)
+				}
+			}
+		})
+	}
+
+	// The weighted scorer picks a different model for the same request
+	r, _ := NewRouter(&RouterConfig{BudgetUSD: 100})
+	r.models = models
+	scored, err := r.SelectModel(context.Background(), RoutingRequest{ModelHint: "codegen", Complexity: 9, Priority: "P0"})
+	if err != nil {
+		t.Fatalf("SelectModel() error = %v", err)
+	}
+	if scored.Model.ID != "flagship" {
+		t.Errorf("scored selection = %s, want flagship", scored.Model.ID)
+	}
+}
+
+func TestSelectModel_UnknownSelectionMode(t *testing.T) {
+	r, _ := NewRouter(&RouterConfig{BudgetUSD: 100})
+	r.models = strategyTestModels()
+
+	if _, err := r.SelectModel(context.Background(), RoutingRequest{SelectionMode: "cheapest"}); err == nil {
+		t.Error("SelectModel() should reject an unknown selection mode")
+	}
+}
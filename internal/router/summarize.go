@@ -0,0 +1,32 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// summarizeSystemPrompt instructs the model that writes context summaries
+const summarizeSystemPrompt = "You condense conversation history. Keep decisions, facts, file names, identifiers, and open questions. Omit pleasantries. Reply with the summary only."
+
+// summarizeContext asks a cheap model, through the router, to summarize
+// context messages dropped by the TruncateSummarize strategy
+func (r *Router) summarizeContext(ctx context.Context, messages []provider.Message, maxTokens int) (string, error) {
+	resp, err := r.Generate(withSummarizing(ctx), GenerateRequest{
+		Prompt:       fmt.Sprintf("Summarize the %d messages above in at most %d tokens.", len(messages), maxTokens),
+		SystemPrompt: summarizeSystemPrompt,
+		ModelHint:    "cheap",
+		Complexity:   1,
+		MaxTokens:    maxTokens,
+		Context:      messages,
+		StepType:     "context:summarize",
+	})
+	if err != nil {
+		return "", fmt.Errorf("summarize context: %w", err)
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("summarize context: %s", resp.Error)
+	}
+	return resp.Content, nil
+}
@@ -0,0 +1,175 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/specular/internal/provider"
+)
+
+// summaryTestContext returns ten context messages of about 200 tokens each
+func summaryTestContext() []provider.Message {
+	messages := make([]provider.Message, 10)
+	for i := range messages {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages[i] = provider.Message{Role: role, Content: strings.Repeat("word ", 200)}
+	}
+	messages[9].Content = "most recent"
+	return messages
+}
+
+func TestContextTruncator_Summarize(t *testing.T) {
+	model := &Model{ID: "small", ContextWindow: 1200}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		summary        string
+		err            error
+		wantCalled     bool
+		wantSummarized int
+	}{
+		{
+			name:           "summarizes dropped messages",
+			ctx:            context.Background(),
+			summary:        "The user asked about words.",
+			wantCalled:     true,
+			wantSummarized: 7,
+		},
+		{
+			name:       "summarizer error drops messages",
+			ctx:        context.Background(),
+			err:        errors.New("rate limited"),
+			wantCalled: true,
+		},
+		{
+			name:       "empty summary drops messages",
+			ctx:        context.Background(),
+			wantCalled: true,
+		},
+		{
+			name: "summarization request is not summarized",
+			ctx:  withSummarizing(context.Background()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &GenerateRequest{Prompt: "What next?", MaxTokens: 100, Context: summaryTestContext()}
+
+			called := false
+			truncator := NewContextTruncator(TruncateSummarize)
+			truncator.SetSummarizer(func(ctx context.Context, messages []provider.Message, maxTokens int) (string, error) {
+				called = true
+				if !isSummarizing(ctx) {
+					t.Error("summarizer context is not marked as summarizing")
+				}
+				if maxTokens != summaryTokenBudget {
+					t.Errorf("maxTokens = %d, want %d", maxTokens, summaryTokenBudget)
+				}
+				return tt.summary, tt.err
+			})
+
+			truncated, wasTruncated, err := truncator.TruncateRequestContext(tt.ctx, req, model)
+			if err != nil || !wasTruncated {
+				t.Fatalf("TruncateRequestContext() = %v, %v, want truncated", wasTruncated, err)
+			}
+
+			if called != tt.wantCalled {
+				t.Errorf("summarizer called = %v, want %v", called, tt.wantCalled)
+			}
+			if truncated.SummarizedMessages != tt.wantSummarized {
+				t.Errorf("SummarizedMessages = %d, want %d", truncated.SummarizedMessages, tt.wantSummarized)
+			}
+			if last := truncated.Context[len(truncated.Context)-1]; last.Content != "most recent" {
+				t.Errorf("last message = %q, want the most recent message kept", last.Content)
+			}
+			if err := NewContextValidator().ValidateRequest(truncated, model); err != nil {
+				t.Errorf("truncated request does not fit: %v", err)
+			}
+			if len(req.Context) != 10 {
+				t.Errorf("original request context modified: %d messages", len(req.Context))
+			}
+
+			if tt.wantSummarized > 0 {
+				note := truncated.Context[0]
+				if note.Role != "system" || !strings.Contains(note.Content, tt.summary) || !strings.HasPrefix(note.Content, "Summary of 7 earlier messages") {
+					t.Errorf("summary note = %+v", note)
+				}
+			}
+		})
+	}
+}
+
+// requestRecordingProvider records every request it receives
+type requestRecordingProvider struct {
+	flakyProvider
+	requests []*provider.GenerateRequest
+}
+
+func (p *requestRecordingProvider) Generate(ctx context.Context, req *provider.GenerateRequest) (*provider.GenerateResponse, error) {
+	p.requests = append(p.requests, req)
+	return &provider.GenerateResponse{Content: "Earlier messages repeated one word.", TokensUsed: 10}, nil
+}
+
+func TestRouter_SummarizeTruncation(t *testing.T) {
+	prov := &requestRecordingProvider{}
+	registry := provider.NewRegistry()
+	if err := registry.Register("anthropic", prov, &provider.ProviderConfig{Name: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRouterWithProviders(&RouterConfig{
+		BudgetUSD:               10,
+		EnableContextValidation: true,
+		AutoTruncate:            true,
+		TruncationStrategy:      string(TruncateSummarize),
+	}, registry)
+	if err != nil {
+		t.Fatalf("NewRouterWithProviders() error = %v", err)
+	}
+	r.models = []Model{
+		{ID: "small", Name: "small", Provider: ProviderAnthropic, Type: ModelTypeAgentic, ContextWindow: 1200, CostPerMToken: 3, CapabilityScore: 90, Available: true},
+		{ID: "tiny", Name: "tiny", Provider: ProviderAnthropic, Type: ModelTypeCheap, ContextWindow: 200000, CostPerMToken: 0.25, CapabilityScore: 60, Available: true},
+	}
+
+	resp, err := r.Generate(context.Background(), GenerateRequest{
+		Prompt:    "What next?",
+		ModelHint: "agentic",
+		MaxTokens: 100,
+		Context:   summaryTestContext(),
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(prov.requests) != 2 {
+		t.Fatalf("provider received %d requests, want summary and main request", len(prov.requests))
+	}
+
+	summaryReq, mainReq := prov.requests[0], prov.requests[1]
+	if summaryReq.Config["model"] != "tiny" || summaryReq.Metadata["step_type"] != "context:summarize" {
+		t.Errorf("summary request model = %v, step = %q, want tiny and context:summarize", summaryReq.Config["model"], summaryReq.Metadata["step_type"])
+	}
+	if len(summaryReq.Context) != 7 {
+		t.Errorf("summary request context = %d messages, want 7", len(summaryReq.Context))
+	}
+	if _, ok := summaryReq.Metadata["summarized_messages"]; ok {
+		t.Error("summary request should not itself be summarized")
+	}
+
+	if mainReq.Config["model"] != "small" || mainReq.Metadata["summarized_messages"] != "7" {
+		t.Errorf("main request model = %v, metadata = %v, want small with 7 summarized messages", mainReq.Config["model"], mainReq.Metadata)
+	}
+	if !strings.Contains(mainReq.Context[0].Content, "Earlier messages repeated one word.") {
+		t.Errorf("main request context starts with %q, want the summary", mainReq.Context[0].Content)
+	}
+	if resp.SummarizedMessages != 7 {
+		t.Errorf("SummarizedMessages = %d, want 7", resp.SummarizedMessages)
+	}
+}
@@ -32,6 +32,7 @@ type Model struct {
 	ID              string    `json:"id"`
 	Provider        Provider  `json:"provider"`
 	Name            string    `json:"name"`
+	Family          string    `json:"family,omitempty"` // Model family across versions (claude-3, gpt-4o)
 	Type            ModelType `json:"type"`
 	ContextWindow   int       `json:"context_window"`   // Tokens
 	CostPerMToken   float64   `json:"cost_per_mtoken"`  // USD per million tokens
@@ -62,15 +63,145 @@ type RouterConfig struct {
 	RetryMaxBackoffMs       int              `json:"retry_max_backoff_ms" yaml:"retry_max_backoff_ms"`           // Maximum backoff delay
 	EnableContextValidation bool             `json:"enable_context_validation" yaml:"enable_context_validation"` // Validate context fits in model window
 	AutoTruncate            bool             `json:"auto_truncate" yaml:"auto_truncate"`                         // Automatically truncate oversized contexts
-	TruncationStrategy      string           `json:"truncation_strategy" yaml:"truncation_strategy"`             // Strategy: oldest, prompt, context, proportional
+	TruncationStrategy      string           `json:"truncation_strategy" yaml:"truncation_strategy"`             // Strategy: oldest, prompt, context, proportional, summarize
+	HealthCooldownMs        int              `json:"health_cooldown_ms" yaml:"health_cooldown_ms"`               // Wait before re-probing an unhealthy provider (0 = 30s)
+
+	// BreakerThreshold is how many consecutive transient failures open a
+	// provider's circuit, skipping it until the health cooldown elapses
+	// (0 = 1). BreakerWindowMs is the longest gap between failures that
+	// still counts them as consecutive (0 = 60s).
+	BreakerThreshold int `json:"breaker_threshold,omitempty" yaml:"breaker_threshold,omitempty"`
+	BreakerWindowMs  int `json:"breaker_window_ms,omitempty" yaml:"breaker_window_ms,omitempty"`
+
+	// FallbackAcrossProvidersOnly skips the remaining models of a provider
+	// once it fails during a request, instead of trying them last. A setup
+	// with a single provider still falls back to its other models.
+	FallbackAcrossProvidersOnly bool `json:"fallback_across_providers_only,omitempty" yaml:"fallback_across_providers_only,omitempty"`
+
+	// BudgetUnlimited disables budget enforcement. Spend is still tracked
+	// and reported, but selection never fails for lack of budget. It cannot
+	// be combined with a positive BudgetUSD.
+	BudgetUnlimited bool `json:"budget_unlimited,omitempty" yaml:"budget_unlimited,omitempty"`
+
+	// StickyModel pins the model of the first successful request and reuses
+	// it for later requests, so a workflow runs on one model. The router
+	// selects another model when the pinned one is unavailable, does not fit
+	// a request, or would exceed the remaining budget.
+	StickyModel bool `json:"sticky_model,omitempty" yaml:"sticky_model,omitempty"`
+
+	// Seed fixes sampling randomness for reproducible runs. A non-zero seed
+	// is sent to providers with every request; providers that support
+	// seeded sampling return the same output for the same input. It also
+	// seeds canary routing. Other model selection and retry backoff are
+	// already deterministic.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+
+	// ProviderBudgets caps spend per provider in USD, keyed by provider name
+	// (e.g. "openai"). A provider that has reached its cap is excluded from
+	// selection, so a cap of 0 disables it. Caps apply on top of BudgetUSD,
+	// and also when BudgetUnlimited is set. Providers without a cap are
+	// limited only by the global budget.
+	ProviderBudgets map[string]float64 `json:"provider_budgets,omitempty" yaml:"provider_budgets,omitempty"`
+
+	// OnBudgetExceeded chooses what happens when a request does not fit the
+	// remaining budget: fail (default), downgrade to the cheapest model that
+	// fits, or pause so the caller can checkpoint and resume later. A
+	// request's OnBudgetExceeded overrides it.
+	OnBudgetExceeded string `json:"on_budget_exceeded,omitempty" yaml:"on_budget_exceeded,omitempty"`
+
+	// CanaryWeights sends a share of traffic to specific models to evaluate
+	// them. Each model ID maps to the probability (0-1) that a request goes
+	// to it instead of the model the strategy ranks first; the weights may
+	// sum to at most 1. A canary model only receives requests it is a
+	// candidate for and that fit the remaining budget. Pinned models and
+	// cheapest-above-capability selection are not split. The chosen arm is
+	// recorded in Usage.Arm.
+	CanaryWeights map[string]float64 `json:"canary_weights,omitempty" yaml:"canary_weights,omitempty"`
+
+	// UsageStorePath is a JSONL file that persists usage across runs. Usage
+	// already in the file is charged to the budget on startup, and each new
+	// record is appended. Processes may share one file.
+	UsageStorePath string `json:"usage_store_path,omitempty" yaml:"usage_store_path,omitempty"`
+
+	// ModelCatalogPath is a YAML file of model definitions merged over the
+	// built-in catalog, so pricing and new models can be updated without a
+	// release. The built-in catalog is used when the file does not exist.
+	ModelCatalogPath string `json:"model_catalog_path,omitempty" yaml:"model_catalog_path,omitempty"`
+
+	// Strategy names the selection strategy used to rank candidate models:
+	// default, cheapest, fastest, highest-capability, balanced, or a name
+	// added with RegisterStrategy. Empty uses DefaultStrategy.
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// AllowFamilies restricts selection to models in these families (e.g.
+	// "claude-3", "gpt-4o"); glob patterns such as "claude-*" are accepted.
+	// Empty allows every family. DenyFamilies removes families after the
+	// allow list is applied.
+	AllowFamilies []string `json:"allow_families,omitempty" yaml:"allow_families,omitempty"`
+	DenyFamilies  []string `json:"deny_families,omitempty" yaml:"deny_families,omitempty"`
+
+	// CacheDir enables a response cache in this directory (e.g.
+	// DefaultCacheDir). Identical requests to the same model are answered
+	// from the cache at no cost until the entry is CacheTTLMs old (0 = 24h).
+	// Only requests with temperature 0 are cached unless
+	// CacheAllTemperatures is set. CacheDisabled turns the cache off even
+	// when CacheDir is set.
+	CacheDir             string `json:"cache_dir,omitempty" yaml:"cache_dir,omitempty"`
+	CacheTTLMs           int    `json:"cache_ttl_ms,omitempty" yaml:"cache_ttl_ms,omitempty"`
+	CacheAllTemperatures bool   `json:"cache_all_temperatures,omitempty" yaml:"cache_all_temperatures,omitempty"`
+	CacheDisabled        bool   `json:"cache_disabled,omitempty" yaml:"cache_disabled,omitempty"`
+
+	// DefaultSystemPrompts maps model hints (codegen, docs, ...) to the system
+	// prompt used when a request has none. The "default" key applies to any
+	// other hint.
+	DefaultSystemPrompts map[string]string `json:"default_system_prompts,omitempty" yaml:"default_system_prompts,omitempty"`
 }
 
+// SelectionMode controls how the router picks among candidate models
+type SelectionMode string
+
+const (
+	// SelectionModeScored ranks candidates with the configured selection
+	// strategy. It is the default.
+	SelectionModeScored SelectionMode = ""
+
+	// SelectionModeCheapestAboveCapability picks the lowest-cost candidate
+	// whose capability score is at least RoutingRequest.MinCapability and
+	// that fits the context size and remaining budget
+	SelectionModeCheapestAboveCapability SelectionMode = "cheapest-above-capability"
+)
+
+// BudgetPolicy controls what SelectModel does when a request does not fit
+// the remaining budget: the budget is exhausted, or the selected model's
+// estimated cost exceeds what remains and no cheaper candidate fits
+type BudgetPolicy string
+
+const (
+	// BudgetPolicyFail returns an error wrapping ErrBudgetExceeded. It is
+	// the default.
+	BudgetPolicyFail BudgetPolicy = "fail"
+
+	// BudgetPolicyDowngrade selects the cheapest available model that fits
+	// the remaining budget, ignoring the model hint and capability, and
+	// fails only when no model fits
+	BudgetPolicyDowngrade BudgetPolicy = "downgrade"
+
+	// BudgetPolicyPause returns an error wrapping ErrBudgetPaused, so a
+	// workflow can checkpoint and stop cleanly, then resume with a raised
+	// budget
+	BudgetPolicyPause BudgetPolicy = "pause"
+)
+
 // RoutingRequest represents a request for model selection
 type RoutingRequest struct {
-	ModelHint   string // Hint from plan generator (codegen, long-context, agentic)
-	Complexity  int    // Task complexity (1-10)
-	Priority    string // Task priority (P0, P1, P2)
-	ContextSize int    // Estimated context size in tokens
+	ModelHint        string        // Hint from plan generator (codegen, long-context, agentic)
+	Complexity       int           // Task complexity (1-10)
+	Priority         string        // Task priority (P0, P1, P2)
+	ContextSize      int           // Estimated context size in tokens
+	SelectionMode    SelectionMode // How to choose among candidates (default: scored)
+	MinCapability    float64       // Capability floor (0-100) for cheapest-above-capability
+	OnBudgetExceeded BudgetPolicy  // Overrides RouterConfig.OnBudgetExceeded when set
+	Input            string        // Request text; counted with each model's tokenizer instead of estimating from complexity
 }
 
 // RoutingResult represents the router's model selection
@@ -79,18 +210,32 @@ type RoutingResult struct {
 	Reason          string  // Explanation for selection
 	EstimatedCost   float64 // Estimated cost in USD
 	EstimatedTokens int     // Estimated token usage
+	Arm             string  // Canary arm (ArmControl or ArmCanary) when CanaryWeights is set
+}
+
+// RankedModel is a candidate model with its selection score and estimated
+// cost for a routing request
+type RankedModel struct {
+	Model           Model
+	Score           float64 // Selection strategy score; higher is preferred
+	EstimatedCost   float64 // Estimated cost in USD
+	EstimatedTokens int     // Estimated token usage
 }
 
 // Usage represents AI model usage tracking
 type Usage struct {
-	Model     string       `json:"model"`
-	Provider  Provider     `json:"provider"`
-	Tokens    int          `json:"tokens"`
-	CostUSD   float64      `json:"cost_usd"`
-	LatencyMs int          `json:"latency_ms"`
-	Timestamp time.Time    `json:"timestamp"`
-	TaskID    types.TaskID `json:"task_id,omitempty"`
-	Success   bool         `json:"success"`
+	Model     string          `json:"model"`
+	Provider  Provider        `json:"provider"`
+	Tokens    int             `json:"tokens"`
+	CostUSD   float64         `json:"cost_usd"`
+	LatencyMs int             `json:"latency_ms"`
+	Timestamp time.Time       `json:"timestamp"`
+	TaskID    types.TaskID    `json:"task_id,omitempty"`
+	FeatureID types.FeatureID `json:"feature_id,omitempty"`
+	StepType  string          `json:"step_type,omitempty"`
+	Success   bool            `json:"success"`
+	Arm       string          `json:"arm,omitempty"`    // Canary arm of the routing decision
+	Cached    bool            `json:"cached,omitempty"` // Served from the response cache at no cost
 }
 
 // Budget tracks spending against limits
@@ -99,6 +244,10 @@ type Budget struct {
 	SpentUSD     float64 `json:"spent_usd"`
 	RemainingUSD float64 `json:"remaining_usd"`
 	UsageCount   int     `json:"usage_count"`
+	Unlimited    bool    `json:"unlimited,omitempty"` // No limit; LimitUSD and RemainingUSD are unused
+
+	ProviderLimitUSD map[Provider]float64 `json:"provider_limit_usd,omitempty"` // Caps from RouterConfig.ProviderBudgets
+	ProviderSpentUSD map[Provider]float64 `json:"provider_spent_usd,omitempty"` // Spend per provider, capped or not
 }
 
 // GenerateRequest represents a request to generate AI content
@@ -116,14 +265,24 @@ type GenerateRequest struct {
 
 	// Generation parameters
 	MaxTokens   int                `json:"max_tokens,omitempty"`
-	Temperature float64            `json:"temperature,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"` // nil uses the provider default
 	TopP        float64            `json:"top_p,omitempty"`
 	Tools       []provider.Tool    `json:"tools,omitempty"`
 	Context     []provider.Message `json:"context,omitempty"`
 	ContextSize int                `json:"context_size,omitempty"` // Estimated context in tokens
 
 	// Metadata
-	TaskID types.TaskID `json:"task_id,omitempty"`
+	TaskID    types.TaskID    `json:"task_id,omitempty"`
+	FeatureID types.FeatureID `json:"feature_id,omitempty"` // Feature the request works on, for cost attribution
+	StepType  string          `json:"step_type,omitempty"`  // Workflow step, e.g. spec:update or build:run
+
+	// OnBudgetExceeded overrides RouterConfig.OnBudgetExceeded for this
+	// request
+	OnBudgetExceeded BudgetPolicy `json:"on_budget_exceeded,omitempty"`
+
+	// SummarizedMessages counts the context messages replaced by a summary
+	// during truncation
+	SummarizedMessages int `json:"summarized_messages,omitempty"`
 }
 
 // GenerateResponse represents the response from AI generation
@@ -149,6 +308,16 @@ type GenerateResponse struct {
 	SelectionReason string              `json:"selection_reason"` // Why this model was selected
 	ToolCalls       []provider.ToolCall `json:"tool_calls,omitempty"`
 
+	// SummarizedMessages counts the context messages replaced by a summary
+	// to fit the model's context window
+	SummarizedMessages int `json:"summarized_messages,omitempty"`
+
+	// Arm is the canary arm of the routing decision when CanaryWeights is set
+	Arm string `json:"arm,omitempty"`
+
+	// Cached is set when the response was served from the response cache
+	Cached bool `json:"cached,omitempty"`
+
 	// Error information
 	Error string `json:"error,omitempty"`
 }
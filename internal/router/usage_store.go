@@ -0,0 +1,113 @@
+package router
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// usageStore persists usage records as JSON lines so spend survives across
+// runs. Several processes may share one store.
+type usageStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// load reads every stored record. A missing file holds no records. Lines
+// that fail to parse, such as one cut short by a crash, are skipped.
+func (s *usageStore) load() ([]Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Usage
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var u Usage
+		if err := json.Unmarshal(scanner.Bytes(), &u); err != nil {
+			continue
+		}
+		records = append(records, u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// append adds a record to the store. Each record is written with a single
+// write to a file opened in append mode, so records from concurrent
+// processes never interleave.
+func (s *usageStore) append(u Usage) error {
+	line, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close() //#nosec G104 -- Write error takes precedence
+		return err
+	}
+	return f.Close()
+}
+
+// openUsageStore loads usage from earlier runs when UsageStorePath is set
+// and charges it to the budget
+func (r *Router) openUsageStore() error {
+	if r.config.UsageStorePath == "" {
+		return nil
+	}
+
+	store := &usageStore{path: r.config.UsageStorePath}
+	history, err := store.load()
+	if err != nil {
+		return fmt.Errorf("load usage store: %w", err)
+	}
+
+	for _, u := range history {
+		r.budget.record(u.Provider, u.CostUSD)
+	}
+	r.store = store
+	r.history = history
+	return nil
+}
+
+// SpentSince returns the spend recorded at or after since. With a usage
+// store it includes spend from earlier runs, so callers can cap spend per
+// day or week.
+func (r *Router) SpentSince(since time.Time) float64 {
+	spent := 0.0
+	for _, records := range [][]Usage{r.history, r.usage} {
+		for _, u := range records {
+			if !u.Timestamp.Before(since) {
+				spent += u.CostUSD
+			}
+		}
+	}
+	return spent
+}
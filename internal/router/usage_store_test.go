@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUsageStore_PersistsAcrossRouters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage", "usage.jsonl")
+	ctx := context.Background()
+
+	first, err := NewRouter(&RouterConfig{BudgetUSD: 10, UsageStorePath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	for _, cost := range []float64{1.5, 2.5} {
+		if err := first.RecordUsage(ctx, Usage{Model: "gpt-4o", CostUSD: cost, Success: true}); err != nil {
+			t.Fatalf("RecordUsage() error = %v", err)
+		}
+	}
+
+	second, err := NewRouter(&RouterConfig{BudgetUSD: 10, UsageStorePath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	budget := second.GetBudget()
+	if budget.SpentUSD != 4 || budget.RemainingUSD != 6 || budget.UsageCount != 2 {
+		t.Errorf("budget = %+v, want $4 spent from the earlier run", budget)
+	}
+	if got := second.SpentSince(time.Time{}); got != 4 {
+		t.Errorf("SpentSince() = %v, want 4", got)
+	}
+	if len(second.usage) != 0 {
+		t.Errorf("earlier runs should not appear in this run's usage, got %d records", len(second.usage))
+	}
+}
+
+func TestRouter_SpentSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	r, err := NewRouter(&RouterConfig{BudgetUnlimited: true, UsageStorePath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+	_ = r.RecordUsage(ctx, Usage{CostUSD: 5, Timestamp: now.Add(-8 * 24 * time.Hour)})
+	_ = r.RecordUsage(ctx, Usage{CostUSD: 2, Timestamp: now.Add(-2 * 24 * time.Hour)})
+	_ = r.RecordUsage(ctx, Usage{CostUSD: 1, Timestamp: now.Add(-time.Hour)})
+
+	if got := r.SpentSince(now.Add(-24 * time.Hour)); got != 1 {
+		t.Errorf("spend in the last day = %v, want 1", got)
+	}
+	if got := r.SpentSince(now.Add(-7 * 24 * time.Hour)); got != 3 {
+		t.Errorf("spend in the last week = %v, want 3", got)
+	}
+
+	reloaded, err := NewRouter(&RouterConfig{BudgetUnlimited: true, UsageStorePath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	if got := reloaded.SpentSince(now.Add(-7 * 24 * time.Hour)); got != 3 {
+		t.Errorf("reloaded spend in the last week = %v, want 3", got)
+	}
+}
+
+func TestUsageStore_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	const writers, records = 8, 25
+
+	// Separate routers stand in for separate processes sharing the file
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		r, err := NewRouter(&RouterConfig{BudgetUnlimited: true, UsageStorePath: path})
+		if err != nil {
+			t.Fatalf("NewRouter() error = %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				if err := r.RecordUsage(context.Background(), Usage{Model: "gpt-4o", CostUSD: 0.5}); err != nil {
+					t.Errorf("RecordUsage() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	history, err := (&usageStore{path: path}).load()
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if len(history) != writers*records {
+		t.Errorf("loaded %d records, want %d", len(history), writers*records)
+	}
+}
+
+func TestUsageStore_SkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	content := `{"model":"gpt-4o","cost_usd":1.25,"success":true}
+{"model":"gpt-4o","cost_u
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRouter(&RouterConfig{BudgetUSD: 5, UsageStorePath: path})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	if budget := r.GetBudget(); budget.SpentUSD != 1.25 || budget.UsageCount != 1 {
+		t.Errorf("budget = %+v, want only the complete record", budget)
+	}
+}
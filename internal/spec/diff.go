@@ -0,0 +1,195 @@
+package spec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// Feature fields reported in a FieldChange
+const (
+	FieldTitle    = "title"
+	FieldDesc     = "desc"
+	FieldPriority = "priority"
+	FieldSuccess  = "success"
+	FieldAPI      = "api"
+	FieldTrace    = "trace"
+	FieldRefs     = "refs"
+	FieldTags     = "tags"
+	FieldTasks    = "tasks"
+)
+
+// DiffResult represents the differences between two product specs.
+type DiffResult struct {
+	ProductChange    *FieldChange    `json:"product_change,omitempty"`
+	FeaturesAdded    []Feature       `json:"features_added"`
+	FeaturesRemoved  []Feature       `json:"features_removed"`
+	FeaturesModified []FeatureChange `json:"features_modified"`
+}
+
+// FeatureChange represents a feature present in both specs whose fields differ.
+type FeatureChange struct {
+	ID      types.FeatureID `json:"id"`
+	Changes []FieldChange   `json:"changes"`
+}
+
+// FieldChange represents a changed field. Scalar fields report the old and
+// new value; list fields report the items added and removed.
+type FieldChange struct {
+	Field   string   `json:"field"`
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// DiffSpecs compares two product specs feature by feature.
+//
+// Features are matched by ID. Added features are listed in the order of
+// specB, removed and modified features in the order of specA.
+func DiffSpecs(specA, specB *ProductSpec) (*DiffResult, error) {
+	if specA == nil || specB == nil {
+		return nil, fmt.Errorf("cannot diff nil specs")
+	}
+
+	result := &DiffResult{
+		FeaturesAdded:    []Feature{},
+		FeaturesRemoved:  []Feature{},
+		FeaturesModified: []FeatureChange{},
+	}
+
+	if specA.Product != specB.Product {
+		result.ProductChange = &FieldChange{Field: "product", Old: specA.Product, New: specB.Product}
+	}
+
+	featuresA := make(map[types.FeatureID]Feature, len(specA.Features))
+	for _, f := range specA.Features {
+		featuresA[f.ID] = f
+	}
+	featuresB := make(map[types.FeatureID]Feature, len(specB.Features))
+	for _, f := range specB.Features {
+		featuresB[f.ID] = f
+	}
+
+	for _, f := range specB.Features {
+		if _, exists := featuresA[f.ID]; !exists {
+			result.FeaturesAdded = append(result.FeaturesAdded, f)
+		}
+	}
+
+	for _, fA := range specA.Features {
+		fB, exists := featuresB[fA.ID]
+		if !exists {
+			result.FeaturesRemoved = append(result.FeaturesRemoved, fA)
+			continue
+		}
+		if changes := diffFeature(fA, fB); len(changes) > 0 {
+			result.FeaturesModified = append(result.FeaturesModified, FeatureChange{ID: fA.ID, Changes: changes})
+		}
+	}
+
+	return result, nil
+}
+
+// diffFeature returns the changed fields of a feature in field order
+func diffFeature(a, b Feature) []FieldChange {
+	var changes []FieldChange
+
+	for _, scalar := range []struct {
+		field    string
+		old, new string
+	}{
+		{FieldTitle, a.Title, b.Title},
+		{FieldDesc, a.Desc, b.Desc},
+		{FieldPriority, string(a.Priority), string(b.Priority)},
+	} {
+		if scalar.old != scalar.new {
+			changes = append(changes, FieldChange{Field: scalar.field, Old: scalar.old, New: scalar.new})
+		}
+	}
+
+	for _, list := range []struct {
+		field    string
+		old, new []string
+	}{
+		{FieldSuccess, a.Success, b.Success},
+		{FieldAPI, apiEndpoints(a.API), apiEndpoints(b.API)},
+		{FieldTrace, a.Trace, b.Trace},
+		{FieldRefs, a.Refs, b.Refs},
+		{FieldTags, a.Tags, b.Tags},
+		{FieldTasks, a.Tasks, b.Tasks},
+	} {
+		if change, changed := diffList(list.field, list.old, list.new); changed {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// diffList compares two lists. A reordering with no items added or removed
+// is still reported as a change.
+func diffList(field string, old, new []string) (FieldChange, bool) {
+	if slices.Equal(old, new) {
+		return FieldChange{}, false
+	}
+
+	change := FieldChange{Field: field}
+	for _, item := range new {
+		if !slices.Contains(old, item) {
+			change.Added = append(change.Added, item)
+		}
+	}
+	for _, item := range old {
+		if !slices.Contains(new, item) {
+			change.Removed = append(change.Removed, item)
+		}
+	}
+	return change, true
+}
+
+// apiEndpoints renders API endpoints as "METHOD path" for comparison
+func apiEndpoints(apis []API) []string {
+	endpoints := make([]string, 0, len(apis))
+	for _, api := range apis {
+		endpoint := api.Method + " " + api.Path
+		if api.Request != "" || api.Response != "" {
+			endpoint += fmt.Sprintf(" (%s → %s)", api.Request, api.Response)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// HasChanges returns true if there are any differences.
+func (r *DiffResult) HasChanges() bool {
+	return r.ProductChange != nil ||
+		len(r.FeaturesAdded) > 0 ||
+		len(r.FeaturesRemoved) > 0 ||
+		len(r.FeaturesModified) > 0
+}
+
+// Summary returns a brief summary of changes.
+func (r *DiffResult) Summary() string {
+	if !r.HasChanges() {
+		return "No differences found"
+	}
+
+	changes := []string{}
+	if r.ProductChange != nil {
+		changes = append(changes, "product renamed")
+	}
+	if len(r.FeaturesAdded) > 0 {
+		changes = append(changes, fmt.Sprintf("%d feature(s) added", len(r.FeaturesAdded)))
+	}
+	if len(r.FeaturesRemoved) > 0 {
+		changes = append(changes, fmt.Sprintf("%d feature(s) removed", len(r.FeaturesRemoved)))
+	}
+	if len(r.FeaturesModified) > 0 {
+		changes = append(changes, fmt.Sprintf("%d feature(s) modified", len(r.FeaturesModified)))
+	}
+
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,146 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSpecs(t *testing.T) {
+	base := Feature{
+		ID:       "feat-001",
+		Title:    "User Login",
+		Desc:     "Users sign in with email",
+		Priority: "P0",
+		API:      []API{{Method: "POST", Path: "/login"}},
+		Success:  []string{"Login succeeds", "Lockout after 5 attempts"},
+		Trace:    []string{"PRD-001"},
+	}
+
+	tests := []struct {
+		name         string
+		change       func(f *Feature)
+		wantModified []FieldChange
+	}{
+		{
+			name:   "identical",
+			change: func(f *Feature) {},
+		},
+		{
+			name: "title and priority changed",
+			change: func(f *Feature) {
+				f.Title = "Sign In"
+				f.Priority = "P1"
+			},
+			wantModified: []FieldChange{
+				{Field: FieldTitle, Old: "User Login", New: "Sign In"},
+				{Field: FieldPriority, Old: "P0", New: "P1"},
+			},
+		},
+		{
+			name: "success criteria changed",
+			change: func(f *Feature) {
+				f.Success = []string{"Login succeeds", "MFA required"}
+			},
+			wantModified: []FieldChange{
+				{Field: FieldSuccess, Added: []string{"MFA required"}, Removed: []string{"Lockout after 5 attempts"}},
+			},
+		},
+		{
+			name: "success criteria reordered",
+			change: func(f *Feature) {
+				f.Success = []string{"Lockout after 5 attempts", "Login succeeds"}
+			},
+			wantModified: []FieldChange{
+				{Field: FieldSuccess},
+			},
+		},
+		{
+			name: "api and tags changed",
+			change: func(f *Feature) {
+				f.API = []API{{Method: "POST", Path: "/login"}, {Method: "POST", Path: "/logout"}}
+				f.Tags = []string{"auth"}
+			},
+			wantModified: []FieldChange{
+				{Field: FieldAPI, Added: []string{"POST /logout"}},
+				{Field: FieldTags, Added: []string{"auth"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			changed.Success = append([]string(nil), base.Success...)
+			tt.change(&changed)
+
+			result, err := DiffSpecs(
+				&ProductSpec{Product: "App", Features: []Feature{base}},
+				&ProductSpec{Product: "App", Features: []Feature{changed}},
+			)
+			if err != nil {
+				t.Fatalf("DiffSpecs() error = %v", err)
+			}
+
+			if tt.wantModified == nil {
+				if result.HasChanges() {
+					t.Errorf("HasChanges() = true, want false: %+v", result)
+				}
+				return
+			}
+			if len(result.FeaturesModified) != 1 {
+				t.Fatalf("FeaturesModified = %d, want 1", len(result.FeaturesModified))
+			}
+			if got := result.FeaturesModified[0].Changes; !reflect.DeepEqual(got, tt.wantModified) {
+				t.Errorf("Changes = %+v, want %+v", got, tt.wantModified)
+			}
+		})
+	}
+}
+
+func TestDiffSpecs_AddedRemovedAndProduct(t *testing.T) {
+	specA := &ProductSpec{
+		Product:  "App",
+		Features: []Feature{{ID: "feat-001"}, {ID: "feat-002"}, {ID: "feat-003"}},
+	}
+	specB := &ProductSpec{
+		Product:  "App v2",
+		Features: []Feature{{ID: "feat-005"}, {ID: "feat-002"}, {ID: "feat-004"}},
+	}
+
+	result, err := DiffSpecs(specA, specB)
+	if err != nil {
+		t.Fatalf("DiffSpecs() error = %v", err)
+	}
+
+	if result.ProductChange == nil || result.ProductChange.Old != "App" || result.ProductChange.New != "App v2" {
+		t.Errorf("ProductChange = %+v, want App → App v2", result.ProductChange)
+	}
+	if got := featureIDs(result.FeaturesAdded); !reflect.DeepEqual(got, []string{"feat-005", "feat-004"}) {
+		t.Errorf("FeaturesAdded = %v, want [feat-005 feat-004]", got)
+	}
+	if got := featureIDs(result.FeaturesRemoved); !reflect.DeepEqual(got, []string{"feat-001", "feat-003"}) {
+		t.Errorf("FeaturesRemoved = %v, want [feat-001 feat-003]", got)
+	}
+	if len(result.FeaturesModified) != 0 {
+		t.Errorf("FeaturesModified = %+v, want none", result.FeaturesModified)
+	}
+
+	want := "product renamed, 2 feature(s) added, 2 feature(s) removed"
+	if got := result.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffSpecs_Nil(t *testing.T) {
+	if _, err := DiffSpecs(nil, &ProductSpec{}); err == nil {
+		t.Error("expected an error for a nil spec")
+	}
+}
+
+func featureIDs(features []Feature) []string {
+	ids := make([]string, len(features))
+	for i, f := range features {
+		ids[i] = string(f.ID)
+	}
+	return ids
+}
@@ -0,0 +1,301 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/felixgeelhaar/specular/pkg/specular/types"
+)
+
+// Lint severities
+const (
+	// LintError marks a problem that fails the lint
+	LintError = "error"
+
+	// LintWarning marks a likely problem that does not fail the lint
+	LintWarning = "warning"
+
+	// LintInfo marks a suggestion
+	LintInfo = "info"
+)
+
+// Lint rule IDs
+const (
+	RuleMissingAcceptanceCriteria     = "missing-acceptance-criteria"
+	RuleDuplicateFeatureID            = "duplicate-feature-id"
+	RuleInvalidPriority               = "invalid-priority"
+	RuleEmptyTasks                    = "empty-tasks"
+	RulePlaceholderAcceptanceCriteria = "placeholder-acceptance-criteria"
+)
+
+// DefaultLintConfigPath is where spec lint looks for its configuration
+const DefaultLintConfigPath = ".specular/spec-lint.yaml"
+
+// defaultLintSeverities lists every lint rule with its default severity
+var defaultLintSeverities = map[string]string{
+	RuleMissingAcceptanceCriteria:     LintError,
+	RuleDuplicateFeatureID:            LintError,
+	RuleInvalidPriority:               LintError,
+	RuleEmptyTasks:                    LintWarning,
+	RulePlaceholderAcceptanceCriteria: LintWarning,
+}
+
+// DefaultPlaceholderCriteria are acceptance criteria left over from
+// generated templates. They are matched case-insensitively, ignoring
+// surrounding whitespace and trailing periods.
+var DefaultPlaceholderCriteria = []string{
+	"Feature works as described",
+	"TODO",
+	"TBD",
+}
+
+// LintRuleConfig toggles a lint rule and overrides its severity.
+type LintRuleConfig struct {
+	Enabled  *bool  `yaml:"enabled,omitempty"`
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// LintConfig configures spec lint rules.
+type LintConfig struct {
+	// Rules overrides rules by ID; rules not listed keep their defaults
+	Rules map[string]LintRuleConfig `yaml:"rules,omitempty"`
+
+	// Placeholders are extra acceptance criteria reported as placeholders
+	Placeholders []string `yaml:"placeholders,omitempty"`
+}
+
+// LoadLintConfig reads a lint configuration. A missing file yields the
+// default configuration.
+func LoadLintConfig(path string) (*LintConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &LintConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lint config: %w", err)
+	}
+
+	var config LintConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unmarshal lint config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks that every configured rule exists and has a known severity.
+func (c *LintConfig) Validate() error {
+	for id, rule := range c.Rules {
+		if _, ok := defaultLintSeverities[id]; !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		switch rule.Severity {
+		case "", LintError, LintWarning, LintInfo:
+		default:
+			return fmt.Errorf("rule %q: unknown severity %q (expected error, warning, or info)", id, rule.Severity)
+		}
+	}
+	return nil
+}
+
+// severity returns the severity of an enabled rule, or false if the rule is
+// disabled
+func (c *LintConfig) severity(rule string) (string, bool) {
+	severity := defaultLintSeverities[rule]
+	if override, ok := c.Rules[rule]; ok {
+		if override.Enabled != nil && !*override.Enabled {
+			return "", false
+		}
+		if override.Severity != "" {
+			severity = override.Severity
+		}
+	}
+	return severity, true
+}
+
+// LintFinding is a problem found while linting a spec.
+type LintFinding struct {
+	Rule      string          `json:"rule"`
+	Severity  string          `json:"severity"`
+	FeatureID types.FeatureID `json:"feature_id,omitempty"`
+	Message   string          `json:"message"`
+	File      string          `json:"file,omitempty"`
+	Line      int             `json:"line,omitempty"`
+}
+
+// Location returns the file and line of the finding, such as
+// "spec.yaml:12", or an empty string if it has none.
+func (f LintFinding) Location() string {
+	if f.File == "" {
+		return ""
+	}
+	if f.Line == 0 {
+		return f.File
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// LintReport is the result of linting a spec.
+type LintReport struct {
+	File     string        `json:"file"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// Count returns the number of findings with a severity.
+func (r *LintReport) Count(severity string) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors returns true if any finding has error severity.
+func (r *LintReport) HasErrors() bool {
+	return r.Count(LintError) > 0
+}
+
+// LintSpecFile lints the spec at path.
+func LintSpecFile(path string, config *LintConfig) (*LintReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec file: %w", err)
+	}
+	return LintSpec(data, path, config)
+}
+
+// LintSpec lints spec YAML. The YAML is linted as written rather than
+// loaded as a ProductSpec, so problems that would fail validation are
+// reported with their line, and specs generated by specular init, which
+// use acceptance_criteria and tasks, are linted too.
+func LintSpec(data []byte, file string, config *LintConfig) (*LintReport, error) {
+	if config == nil {
+		config = &LintConfig{}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("unmarshal spec: %w", err)
+	}
+
+	l := &linter{
+		config:       config,
+		report:       &LintReport{File: file, Findings: []LintFinding{}},
+		placeholders: make(map[string]bool),
+	}
+	for _, placeholder := range slices.Concat(DefaultPlaceholderCriteria, config.Placeholders) {
+		l.placeholders[normalizeCriterion(placeholder)] = true
+	}
+
+	var features *yaml.Node
+	if len(root.Content) > 0 {
+		features = mappingValue(root.Content[0], "features")
+	}
+	if features != nil && features.Kind == yaml.SequenceNode {
+		seen := make(map[string]int)
+		for _, feature := range features.Content {
+			if feature.Kind == yaml.MappingNode {
+				l.lintFeature(feature, seen)
+			}
+		}
+	}
+
+	return l.report, nil
+}
+
+// linter accumulates the findings of a spec lint
+type linter struct {
+	config       *LintConfig
+	report       *LintReport
+	placeholders map[string]bool
+}
+
+// add records a finding for an enabled rule
+func (l *linter) add(rule string, featureID string, line int, format string, args ...any) {
+	severity, enabled := l.config.severity(rule)
+	if !enabled {
+		return
+	}
+	l.report.Findings = append(l.report.Findings, LintFinding{
+		Rule:      rule,
+		Severity:  severity,
+		FeatureID: types.FeatureID(featureID),
+		Message:   fmt.Sprintf(format, args...),
+		File:      l.report.File,
+		Line:      line,
+	})
+}
+
+// lintFeature applies every rule to one feature mapping
+func (l *linter) lintFeature(feature *yaml.Node, seen map[string]int) {
+	id := ""
+	if node := mappingValue(feature, "id"); node != nil {
+		id = node.Value
+	}
+	label := id
+	if label == "" {
+		label = fmt.Sprintf("at line %d", feature.Line)
+	}
+
+	if id != "" {
+		if first, ok := seen[id]; ok {
+			l.add(RuleDuplicateFeatureID, id, feature.Line, "feature ID %q is already used at line %d", id, first)
+		} else {
+			seen[id] = feature.Line
+		}
+	}
+
+	priority := mappingValue(feature, "priority")
+	switch {
+	case priority == nil:
+		l.add(RuleInvalidPriority, id, feature.Line, "feature %s has no priority (expected P0, P1, or P2)", label)
+	case types.Priority(priority.Value).Validate() != nil:
+		l.add(RuleInvalidPriority, id, priority.Line, "feature %s has priority %q (expected P0, P1, or P2)", label, priority.Value)
+	}
+
+	criteria := mappingValue(feature, "success")
+	if criteria == nil {
+		criteria = mappingValue(feature, "acceptance_criteria")
+	}
+	if criteria == nil || len(criteria.Content) == 0 {
+		l.add(RuleMissingAcceptanceCriteria, id, feature.Line, "feature %s has no acceptance criteria", label)
+	} else {
+		for _, criterion := range criteria.Content {
+			if l.placeholders[normalizeCriterion(criterion.Value)] {
+				l.add(RulePlaceholderAcceptanceCriteria, id, criterion.Line,
+					"feature %s has placeholder acceptance criterion %q", label, criterion.Value)
+			}
+		}
+	}
+
+	if tasks := mappingValue(feature, "tasks"); tasks != nil && len(tasks.Content) == 0 {
+		l.add(RuleEmptyTasks, id, tasks.Line, "feature %s has an empty task list", label)
+	}
+}
+
+// mappingValue returns the value of key in a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// normalizeCriterion folds an acceptance criterion for placeholder matching
+func normalizeCriterion(criterion string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(criterion), "."))
+}
@@ -0,0 +1,155 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const lintTestSpec = `product: App
+features:
+  - id: feat-001
+    title: Login
+    priority: P0
+    success:
+      - Users can sign in
+  - id: feat-002
+    title: Search
+    priority: P4
+    success: []
+  - id: feat-001
+    title: Export
+    priority: P1
+    acceptance_criteria:
+      - "Feature works as described."
+    tasks: []
+`
+
+func TestLintSpec(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name   string
+		config *LintConfig
+		want   []LintFinding
+	}{
+		{
+			name: "default rules",
+			want: []LintFinding{
+				{Rule: RuleInvalidPriority, Severity: LintError, FeatureID: "feat-002", Line: 10},
+				{Rule: RuleMissingAcceptanceCriteria, Severity: LintError, FeatureID: "feat-002", Line: 8},
+				{Rule: RuleDuplicateFeatureID, Severity: LintError, FeatureID: "feat-001", Line: 12},
+				{Rule: RulePlaceholderAcceptanceCriteria, Severity: LintWarning, FeatureID: "feat-001", Line: 16},
+				{Rule: RuleEmptyTasks, Severity: LintWarning, FeatureID: "feat-001", Line: 17},
+			},
+		},
+		{
+			name: "rules disabled and severity overridden",
+			config: &LintConfig{Rules: map[string]LintRuleConfig{
+				RuleInvalidPriority:               {Enabled: &disabled},
+				RuleMissingAcceptanceCriteria:     {Enabled: &disabled},
+				RuleEmptyTasks:                    {Enabled: &disabled},
+				RuleDuplicateFeatureID:            {Severity: LintWarning},
+				RulePlaceholderAcceptanceCriteria: {Severity: LintError},
+			}},
+			want: []LintFinding{
+				{Rule: RuleDuplicateFeatureID, Severity: LintWarning, FeatureID: "feat-001", Line: 12},
+				{Rule: RulePlaceholderAcceptanceCriteria, Severity: LintError, FeatureID: "feat-001", Line: 16},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := LintSpec([]byte(lintTestSpec), "spec.yaml", tt.config)
+			if err != nil {
+				t.Fatalf("LintSpec() error = %v", err)
+			}
+
+			if len(report.Findings) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d: %+v", len(report.Findings), len(tt.want), report.Findings)
+			}
+			for i, want := range tt.want {
+				got := report.Findings[i]
+				if got.Rule != want.Rule || got.Severity != want.Severity || got.FeatureID != want.FeatureID || got.Line != want.Line {
+					t.Errorf("finding %d = %s/%s %s line %d, want %s/%s %s line %d", i,
+						got.Rule, got.Severity, got.FeatureID, got.Line,
+						want.Rule, want.Severity, want.FeatureID, want.Line)
+				}
+				if got.File != "spec.yaml" || got.Message == "" {
+					t.Errorf("finding %d has file %q and message %q", i, got.File, got.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestLintSpec_CleanAndCustomPlaceholders(t *testing.T) {
+	spec := `features:
+  - id: feat-001
+    priority: P2
+    success:
+      - Works as expected
+`
+	report, err := LintSpec([]byte(spec), "spec.yaml", nil)
+	if err != nil {
+		t.Fatalf("LintSpec() error = %v", err)
+	}
+	if len(report.Findings) != 0 || report.HasErrors() {
+		t.Errorf("expected no findings, got %+v", report.Findings)
+	}
+
+	report, err = LintSpec([]byte(spec), "spec.yaml", &LintConfig{Placeholders: []string{"works as expected"}})
+	if err != nil {
+		t.Fatalf("LintSpec() error = %v", err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Rule != RulePlaceholderAcceptanceCriteria {
+		t.Errorf("expected one placeholder finding, got %+v", report.Findings)
+	}
+}
+
+func TestLoadLintConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := LoadLintConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("missing config: error = %v", err)
+	}
+	if len(config.Rules) != 0 {
+		t.Errorf("missing config: Rules = %v, want none", config.Rules)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			content: "rules:\n  empty-tasks:\n    enabled: false\n  invalid-priority:\n    severity: warning\n",
+		},
+		{
+			name:    "unknown rule",
+			content: "rules:\n  no-such-rule:\n    enabled: false\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown severity",
+			content: "rules:\n  empty-tasks:\n    severity: fatal\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadLintConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadLintConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,11 +19,20 @@ type SpecRepository interface {
 }
 
 // FileSpecRepository implements SpecRepository for file-based storage
-type FileSpecRepository struct{}
+type FileSpecRepository struct {
+	schema *Schema
+}
 
-// NewFileSpecRepository creates a new file-based spec repository
+// NewFileSpecRepository creates a new file-based spec repository that
+// checks specs against the default spec schema
 func NewFileSpecRepository() *FileSpecRepository {
-	return &FileSpecRepository{}
+	return &FileSpecRepository{schema: DefaultSchema()}
+}
+
+// NewFileSpecRepositoryWithSchema creates a file-based spec repository that
+// checks specs against a custom schema, such as one with team extensions
+func NewFileSpecRepositoryWithSchema(schema *Schema) *FileSpecRepository {
+	return &FileSpecRepository{schema: schema}
 }
 
 // Load reads a ProductSpec from a YAML file
@@ -33,6 +42,13 @@ func (r *FileSpecRepository) Load(path string) (*ProductSpec, error) {
 		return nil, fmt.Errorf("read spec file: %w", err)
 	}
 
+	// Check the structure first so errors name the offending path
+	if r.schema != nil {
+		if err := r.schema.ValidateYAML(data); err != nil {
+			return nil, fmt.Errorf("validate spec: %w", err)
+		}
+	}
+
 	var spec ProductSpec
 	if err := yaml.Unmarshal(data, &spec); err != nil {
 		return nil, fmt.Errorf("unmarshal spec: %w", err)
@@ -120,7 +120,7 @@ features:
 			name:        "empty file",
 			specContent: "",
 			wantErr:     true,
-			errContains: "product name cannot be empty",
+			errContains: "product: is required",
 		},
 	}
 
@@ -9,10 +9,15 @@ import (
 	"github.com/felixgeelhaar/specular/pkg/specular/types"
 )
 
-// GenerateSpecLock creates a SpecLock from a ProductSpec
-func GenerateSpecLock(spec ProductSpec, version string) (*SpecLock, error) {
+// GenerateSpecLock creates a SpecLock from a ProductSpec. The version must
+// be a valid semantic version.
+func GenerateSpecLock(spec ProductSpec, version types.Version) (*SpecLock, error) {
+	if err := version.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spec lock version: %w", err)
+	}
+
 	lock := &SpecLock{
-		Version:  version,
+		Version:  version.String(),
 		Features: make(map[types.FeatureID]LockedFeature),
 	}
 
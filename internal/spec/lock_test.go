@@ -13,7 +13,7 @@ func TestGenerateSpecLock(t *testing.T) {
 	tests := []struct {
 		name     string
 		spec     ProductSpec
-		version  string
+		version  types.Version
 		validate func(*testing.T, *SpecLock)
 	}{
 		{
@@ -152,6 +152,17 @@ func TestGenerateSpecLock(t *testing.T) {
 	}
 }
 
+func TestGenerateSpecLock_InvalidVersion(t *testing.T) {
+	spec := ProductSpec{Product: "TestProduct"}
+
+	for _, version := range []types.Version{"", "1.0", "v1.0.0", "1.0.0.0", "latest"} {
+		lock, err := GenerateSpecLock(spec, version)
+		if err == nil {
+			t.Errorf("GenerateSpecLock(%q) expected error, got lock %+v", version, lock)
+		}
+	}
+}
+
 func TestGenerateSpecLock_HashDeterminism(t *testing.T) {
 	spec := ProductSpec{
 		Product: "TestProduct",
@@ -0,0 +1,436 @@
+package spec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaJSON is the published JSON Schema for the product spec format
+//
+//go:embed spec.schema.json
+var schemaJSON []byte
+
+// SchemaJSON returns the JSON Schema for the product spec format.
+func SchemaJSON() []byte {
+	return slices.Clone(schemaJSON)
+}
+
+// Schema is a compiled JSON Schema.
+//
+// The keywords needed to describe a spec are supported: $ref to local
+// definitions ($defs or definitions), type, enum, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength, and
+// pattern. Other keywords, such as description, are ignored.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 schemaTypes        `json:"type,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *additionalSchema  `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes is the type keyword, a single type name or a list of names
+type schemaTypes []string
+
+// UnmarshalJSON accepts a type name or a list of type names
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = schemaTypes{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = names
+	return nil
+}
+
+// additionalSchema is the additionalProperties keyword, a boolean or a schema
+type additionalSchema struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON accepts a boolean or a schema
+func (a *additionalSchema) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// ParseSchema parses and compiles a JSON Schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if err := schema.compile(&schema, "#"); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// LoadSchema reads and compiles a JSON Schema file.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	schema, err := ParseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// DefaultSchema returns the compiled spec schema.
+func DefaultSchema() *Schema {
+	schema, err := ParseSchema(schemaJSON)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded spec schema: %v", err))
+	}
+	return schema
+}
+
+// compile compiles patterns and checks that references resolve
+func (s *Schema) compile(root *Schema, location string) error {
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", location, err)
+		}
+		s.pattern = pattern
+	}
+	if s.Ref != "" {
+		if _, err := root.resolve(s.Ref); err != nil {
+			return fmt.Errorf("%s: %w", location, err)
+		}
+	}
+
+	children := map[string]*Schema{"items": s.Items}
+	for name, child := range s.Properties {
+		children["properties/"+name] = child
+	}
+	for name, child := range s.Defs {
+		children["$defs/"+name] = child
+	}
+	for name, child := range s.Definitions {
+		children["definitions/"+name] = child
+	}
+	if s.AdditionalProperties != nil {
+		children["additionalProperties"] = s.AdditionalProperties.Schema
+	}
+	for name, child := range children {
+		if child == nil {
+			continue
+		}
+		if err := child.compile(root, location+"/"+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve looks up a local reference such as "#/$defs/feature"
+func (s *Schema) resolve(ref string) (*Schema, error) {
+	var defs map[string]*Schema
+	var name string
+	switch {
+	case strings.HasPrefix(ref, "#/$defs/"):
+		defs, name = s.Defs, strings.TrimPrefix(ref, "#/$defs/")
+	case strings.HasPrefix(ref, "#/definitions/"):
+		defs, name = s.Definitions, strings.TrimPrefix(ref, "#/definitions/")
+	default:
+		return nil, fmt.Errorf("unsupported $ref %q (only #/$defs/ and #/definitions/ references are supported)", ref)
+	}
+	target, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return target, nil
+}
+
+// SchemaError is a schema violation at a path in the document, such as
+// "features[2].priority".
+type SchemaError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Error implements error
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// SchemaValidationError lists every schema violation in a document.
+type SchemaValidationError struct {
+	Errors []SchemaError
+}
+
+// Error implements error
+func (e *SchemaValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	if len(messages) == 1 {
+		return messages[0]
+	}
+	return fmt.Sprintf("%d schema errors: %s", len(messages), strings.Join(messages, "; "))
+}
+
+// Validate checks a decoded YAML or JSON document against the schema. It
+// returns a *SchemaValidationError listing every violation.
+func (s *Schema) Validate(doc any) error {
+	v := &schemaValidator{root: s}
+	v.validate(s, doc, "")
+	if len(v.errors) > 0 {
+		return &SchemaValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+// ValidateYAML checks spec YAML against the schema. An empty document is
+// checked as an empty mapping.
+func (s *Schema) ValidateYAML(data []byte) error {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("unmarshal spec: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return s.Validate(doc)
+}
+
+// ValidateSpec checks a ProductSpec against the schema, as it would be
+// written by SaveSpec.
+func (s *Schema) ValidateSpec(productSpec *ProductSpec) error {
+	data, err := yaml.Marshal(productSpec)
+	if err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+	return s.ValidateYAML(data)
+}
+
+// schemaValidator accumulates the violations found while validating
+type schemaValidator struct {
+	root   *Schema
+	errors []SchemaError
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+	v.errors = append(v.errors, SchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(s *Schema, value any, path string) {
+	if s.Ref != "" {
+		target, err := v.root.resolve(s.Ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.validate(target, value, path)
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(name string) bool { return hasSchemaType(value, name) }) {
+		v.fail(path, "must be %s", describeTypes(s.Type))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return schemaValuesEqual(allowed, value) }) {
+		allowed := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			allowed[i] = fmt.Sprint(option)
+		}
+		v.fail(path, "must be one of %s", strings.Join(allowed, ", "))
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		v.validateObject(s, typed, path)
+	case []any:
+		v.validateArray(s, typed, path)
+	case string:
+		v.validateString(s, typed, path)
+	case time.Time:
+		v.validateString(s, typed.Format(time.RFC3339), path)
+	}
+}
+
+func (v *schemaValidator) validateObject(s *Schema, object map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			v.fail(joinSchemaPath(path, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		child := joinSchemaPath(path, name)
+		if property, ok := s.Properties[name]; ok {
+			v.validate(property, object[name], child)
+			continue
+		}
+		if s.AdditionalProperties == nil {
+			continue
+		}
+		if !s.AdditionalProperties.Allowed {
+			v.fail(child, "is not an allowed property")
+		} else if s.AdditionalProperties.Schema != nil {
+			v.validate(s.AdditionalProperties.Schema, object[name], child)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s *Schema, items []any, path string) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.fail(path, "must have at least %d item(s)", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		v.fail(path, "must have at most %d item(s)", *s.MaxItems)
+	}
+	if s.Items != nil {
+		for i, item := range items {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(s *Schema, value, path string) {
+	length := len([]rune(value))
+	if s.MinLength != nil && length < *s.MinLength {
+		if *s.MinLength == 1 {
+			v.fail(path, "must not be empty")
+		} else {
+			v.fail(path, "must be at least %d characters", *s.MinLength)
+		}
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(path, "must be at most %d characters", *s.MaxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		v.fail(path, "must match pattern %s", s.Pattern)
+	}
+}
+
+// joinSchemaPath appends a property name to a document path
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// hasSchemaType reports whether a decoded value has a JSON Schema type
+func hasSchemaType(value any, name string) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		switch value.(type) {
+		case string, time.Time:
+			return true
+		}
+		return false
+	case "integer":
+		switch n := value.(type) {
+		case int, int64, uint64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+		return false
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return false
+	}
+}
+
+// describeTypes renders type names for an error message, such as
+// "a string or null"
+func describeTypes(names []string) string {
+	described := make([]string, len(names))
+	for i, name := range names {
+		switch name {
+		case "null":
+			described[i] = "null"
+		case "array", "integer", "object":
+			described[i] = "an " + name
+		default:
+			described[i] = "a " + name
+		}
+	}
+	return strings.Join(described, " or ")
+}
+
+// schemaValuesEqual compares an enum option with a decoded value, treating
+// numbers of different Go types as equal when their values are
+func schemaValuesEqual(a, b any) bool {
+	if x, ok := schemaNumber(a); ok {
+		y, ok := schemaNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func schemaNumber(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
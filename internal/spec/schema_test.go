@@ -0,0 +1,203 @@
+package spec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const schemaTestSpec = `product: App
+goals:
+  - Ship it
+features:
+  - id: user-login
+    title: User Login
+    desc: Users sign in
+    priority: P0
+    api:
+      - method: POST
+        path: /login
+    success:
+      - Login succeeds
+    trace:
+      - PRD-001
+non_functional:
+  performance:
+    - p99 < 200ms
+acceptance:
+  - Users can sign in
+milestones:
+  - id: m1
+    name: MVP
+    feature_ids: [user-login]
+    target_date: 2025-01-31
+`
+
+func TestSchema_ValidateYAML(t *testing.T) {
+	schema := DefaultSchema()
+
+	tests := []struct {
+		name    string
+		replace [2]string
+		want    []SchemaError
+	}{
+		{
+			name: "valid spec",
+		},
+		{
+			name:    "invalid priority",
+			replace: [2]string{"priority: P0", "priority: P4"},
+			want:    []SchemaError{{Path: "features[0].priority", Message: "must be one of P0, P1, P2"}},
+		},
+		{
+			name:    "misspelled field",
+			replace: [2]string{"    success:", "    sucess:"},
+			want:    []SchemaError{{Path: "features[0].success", Message: "is required"}},
+		},
+		{
+			name:    "extra field",
+			replace: [2]string{"product: App\n", "product: App\nowner: payments-team\n"},
+		},
+		{
+			name:    "lowercase method",
+			replace: [2]string{"method: POST", "method: post"},
+		},
+		{
+			name:    "wrong type",
+			replace: [2]string{"    trace:\n      - PRD-001", "    trace: PRD-001"},
+			want:    []SchemaError{{Path: "features[0].trace", Message: "must be an array or null"}},
+		},
+		{
+			name:    "invalid feature ID",
+			replace: [2]string{"feature_ids: [user-login]", "feature_ids: [User_Login]"},
+			want:    []SchemaError{{Path: "milestones[0].feature_ids[0]", Message: "must match pattern ^[a-z][a-z0-9]*(-[a-z0-9]+)*$"}},
+		},
+		{
+			name:    "empty api method",
+			replace: [2]string{"method: POST", `method: ""`},
+			want:    []SchemaError{{Path: "features[0].api[0].method", Message: "must not be empty"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := schemaTestSpec
+			if tt.replace[0] != "" {
+				content = strings.Replace(content, tt.replace[0], tt.replace[1], 1)
+			}
+
+			err := schema.ValidateYAML([]byte(content))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateYAML() error = %v", err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaValidationError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("ValidateYAML() error = %v, want *SchemaValidationError", err)
+			}
+			if !reflect.DeepEqual(schemaErr.Errors, tt.want) {
+				t.Errorf("Errors = %+v, want %+v", schemaErr.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateSpec(t *testing.T) {
+	productSpec := &ProductSpec{
+		Product: "App",
+		Features: []Feature{
+			{ID: "feat-one", Title: "One", Priority: "P1", Success: []string{"works"}},
+			{ID: "feat-two", Title: "Two", Priority: "high", Success: []string{"works"}},
+		},
+	}
+
+	err := DefaultSchema().ValidateSpec(productSpec)
+	if err == nil || err.Error() != "features[1].priority: must be one of P0, P1, P2" {
+		t.Errorf("ValidateSpec() error = %v, want features[1].priority error", err)
+	}
+
+	productSpec.Features[1].Priority = "P2"
+	if err := DefaultSchema().ValidateSpec(productSpec); err != nil {
+		t.Errorf("ValidateSpec() error = %v", err)
+	}
+}
+
+func TestParseSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "invalid JSON", schema: `{`},
+		{name: "invalid pattern", schema: `{"properties": {"id": {"pattern": "("}}}`},
+		{name: "unresolved reference", schema: `{"items": {"$ref": "#/$defs/missing"}}`},
+		{name: "remote reference", schema: `{"$ref": "https://example.com/schema.json"}`},
+		{name: "invalid type", schema: `{"type": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema([]byte(tt.schema)); err == nil {
+				t.Error("ParseSchema() expected an error")
+			}
+		})
+	}
+}
+
+func TestFileSpecRepository_CustomSchema(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	content := strings.Replace(schemaTestSpec, "product: App\n", "product: App\nowner: payments-team\n", 1)
+	if err := os.WriteFile(specPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The default schema allows extra fields
+	loaded, err := LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("LoadSpec() error = %v", err)
+	}
+	if loaded.Product != "App" {
+		t.Errorf("Product = %q, want App", loaded.Product)
+	}
+
+	// A stricter team schema rejects fields it does not list
+	schema := strings.Replace(string(SchemaJSON()), `"type": "object",
+  "required"`, `"type": "object",
+  "additionalProperties": false,
+  "required"`, 1)
+	custom, err := ParseSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	if _, err := NewFileSpecRepositoryWithSchema(custom).Load(specPath); err == nil || !strings.Contains(err.Error(), "owner: is not an allowed property") {
+		t.Fatalf("Load() error = %v, want owner rejected", err)
+	}
+}
+
+// TestLoadSpec_ShippedExamples tests that the example specs in the
+// repository load with the default schema
+func TestLoadSpec_ShippedExamples(t *testing.T) {
+	paths := []string{"../../.specular/spec.yaml.example"}
+	examples, err := filepath.Glob("../../examples/*/.specular/spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) == 0 {
+		t.Fatal("no example specs found")
+	}
+	paths = append(paths, examples...)
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			if _, err := LoadSpec(path); err != nil {
+				t.Errorf("LoadSpec() error = %v", err)
+			}
+		})
+	}
+}
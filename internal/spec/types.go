@@ -22,6 +22,8 @@ type Feature struct {
 	Success  []string        `json:"success"`
 	Trace    []string        `json:"trace"`
 	Refs     []string        `json:"refs,omitempty"`
+	Tags     []string        `json:"tags,omitempty"`  // Labels for scope filtering (e.g., critical, backend)
+	Tasks    []string        `json:"tasks,omitempty"` // Planned implementation tasks
 }
 
 // API represents an API endpoint definition
@@ -0,0 +1,197 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pre-tokenization patterns of the tiktoken encodings. RE2 has no
+// lookahead, so the trailing-whitespace rule "\s+(?!\S)" is applied in
+// split instead.
+var (
+	cl100kPattern = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+	o200kPattern = regexp.MustCompile(`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`)
+)
+
+// split breaks text into the pieces a tiktoken encoding merges separately
+func split(pattern *regexp.Regexp, text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		loc := pattern.FindStringIndex(text)
+		if loc == nil || loc[1] == 0 {
+			// Every byte matches some alternative; guard against a stall
+			_, size := utf8.DecodeRuneInString(text)
+			pieces = append(pieces, text[:size])
+			text = text[size:]
+			continue
+		}
+		end := loc[1]
+
+		// Whitespace before a word leaves its last space to the word
+		piece := text[:end]
+		if end < len(text) && isSpace(piece) && !strings.ContainsAny(piece[len(piece)-1:], "\r\n") {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				_, last := utf8.DecodeLastRuneInString(piece)
+				if last < len(piece) {
+					end -= last
+				}
+			}
+		}
+
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
+
+// isSpace reports whether s is all whitespace
+func isSpace(s string) bool {
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// bpe counts tokens with a tiktoken byte-pair vocabulary
+type bpe struct {
+	name    string
+	ranks   map[string]int
+	pattern *regexp.Regexp
+}
+
+// loadBPE reads a .tiktoken vocabulary: one base64 token and its rank per
+// line
+func loadBPE(name, path string, pattern *regexp.Regexp) (*bpe, error) {
+	f, err := os.Open(path) // #nosec G304 -- vocabulary path from the tokenizer directory
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want token and rank", path, line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: decode token: %w", path, line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: parse rank: %w", path, line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("%s: empty vocabulary", path)
+	}
+
+	return &bpe{name: name, ranks: ranks, pattern: pattern}, nil
+}
+
+// Name implements Tokenizer
+func (b *bpe) Name() string {
+	return b.name
+}
+
+// Count implements Tokenizer
+func (b *bpe) Count(text string) int {
+	count := 0
+	for _, piece := range split(b.pattern, text) {
+		count += b.countPiece(piece)
+	}
+	return count
+}
+
+// countPiece merges the lowest-ranked adjacent pair of a piece's bytes
+// until no pair is in the vocabulary, and returns the number of parts left
+func (b *bpe) countPiece(piece string) int {
+	if _, ok := b.ranks[piece]; ok {
+		return 1
+	}
+
+	// bounds[i] is the start of part i; the last entry is the end
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(bounds); i++ {
+			if rank, ok := b.ranks[piece[bounds[i]:bounds[i+2]]]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+
+	return len(bounds) - 1
+}
+
+// estimator counts tokens from pre-tokenized pieces without a vocabulary
+type estimator struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// Name implements Tokenizer
+func (e estimator) Name() string {
+	return e.name
+}
+
+// Count implements Tokenizer
+func (e estimator) Count(text string) int {
+	count := 0
+	for _, piece := range split(e.pattern, text) {
+		count += estimatePiece(piece)
+	}
+	return count
+}
+
+// estimatePiece estimates the tokens of one piece. Common words are a
+// single token and longer ones split about every five letters; punctuation
+// merges in pairs; characters outside ASCII are about a token each.
+func estimatePiece(piece string) int {
+	letters, symbols, other := 0, 0, 0
+	for _, r := range piece {
+		switch {
+		case r >= utf8.RuneSelf:
+			other++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			letters++
+		case !unicode.IsSpace(r):
+			symbols++
+		}
+	}
+
+	tokens := other + (letters+4)/5 + (symbols+1)/2
+	if tokens == 0 {
+		return 1 // whitespace
+	}
+	return tokens
+}
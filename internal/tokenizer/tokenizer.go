@@ -0,0 +1,134 @@
+// Package tokenizer counts tokens for pre-flight estimation of model
+// requests, before a provider reports real usage.
+//
+// OpenAI models are counted with byte-pair encoding over their tiktoken
+// vocabulary (o200k_base or cl100k_base). Vocabulary files are not bundled;
+// place <encoding>.tiktoken files, as published for tiktoken, in the
+// directory named by SPECULAR_TOKENIZER_DIR (default
+// ~/.specular/tokenizers). Without a vocabulary file, and for providers
+// whose tokenizers are not public (Anthropic, Gemini, local models), counts
+// are estimated from the same word-level pre-tokenization: every word,
+// number, or punctuation run is one or more tokens depending on its length.
+// This tracks real tokenizers far more closely than characters divided by
+// four, which undercounts code, numbers, and non-English text.
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Encodings with a byte-pair vocabulary
+const (
+	EncodingO200k  = "o200k_base"
+	EncodingCL100k = "cl100k_base"
+)
+
+// DirEnv names the directory holding <encoding>.tiktoken vocabulary files
+const DirEnv = "SPECULAR_TOKENIZER_DIR"
+
+// Tokenizer counts the tokens of text for one model family
+type Tokenizer interface {
+	// Count returns the number of tokens in text
+	Count(text string) int
+
+	// Name identifies the encoding, with an "~" prefix for estimates
+	Name() string
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]Tokenizer{}
+)
+
+// ForModel returns the tokenizer for a provider's model. Encoders are built
+// once per vocabulary file and cached.
+func ForModel(provider, model string) Tokenizer {
+	encoding := EncodingForModel(provider, model)
+	if encoding == "" {
+		return Estimate()
+	}
+	return forEncoding(encoding)
+}
+
+// CountExchange counts the tokens of a prompt and its response with the
+// model's tokenizer. CLI-backed providers use it because their CLIs do not
+// report usage.
+func CountExchange(provider, model, prompt, response string) (inputTokens, outputTokens int) {
+	tok := ForModel(provider, model)
+	return tok.Count(prompt), tok.Count(response)
+}
+
+// Estimate returns the word-level estimator used when no vocabulary is
+// available
+func Estimate() Tokenizer {
+	return estimator{name: "~words", pattern: cl100kPattern}
+}
+
+// EncodingForModel returns the byte-pair encoding of a model, or "" when
+// its tokenizer is not public.
+func EncodingForModel(provider, model string) string {
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+
+	for _, prefix := range []string{"gpt-4o", "chatgpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4", "codex"} {
+		if strings.HasPrefix(model, prefix) {
+			return EncodingO200k
+		}
+	}
+	for _, prefix := range []string{"gpt-4", "gpt-3.5", "gpt-35", "text-embedding-3", "text-embedding-ada-002"} {
+		if strings.HasPrefix(model, prefix) {
+			return EncodingCL100k
+		}
+	}
+
+	// Newer OpenAI models use o200k_base
+	switch strings.ToLower(provider) {
+	case "openai", "codex", "codex-cli":
+		return EncodingO200k
+	}
+	return ""
+}
+
+// forEncoding returns the cached tokenizer for an encoding, loading its
+// vocabulary on first use
+func forEncoding(encoding string) Tokenizer {
+	path := filepath.Join(vocabDir(), encoding+".tiktoken")
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if tok, ok := cache[path]; ok {
+		return tok
+	}
+
+	pattern := cl100kPattern
+	if encoding == EncodingO200k {
+		pattern = o200kPattern
+	}
+
+	var tok Tokenizer
+	if enc, err := loadBPE(encoding, path, pattern); err == nil {
+		tok = enc
+	} else {
+		tok = estimator{name: "~" + encoding, pattern: pattern}
+	}
+	cache[path] = tok
+	return tok
+}
+
+// vocabDir returns the directory holding vocabulary files
+func vocabDir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".specular", "tokenizers")
+	}
+	return filepath.Join(home, ".specular", "tokenizers")
+}
@@ -0,0 +1,164 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "Hello world", want: []string{"Hello", " world"}},
+		{text: "a   b", want: []string{"a", "  ", " b"}},
+		{text: "x = 12345;\n", want: []string{"x", " =", " ", "123", "45", ";\n"}},
+		{text: "don't stop", want: []string{"don", "'t", " stop"}},
+		{text: "line\n\n  next", want: []string{"line", "\n\n", " ", " next"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := split(cl100kPattern, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := []struct {
+		provider string
+		model    string
+		want     string
+	}{
+		{provider: "openai", model: "gpt-4o-2024-08-06", want: EncodingO200k},
+		{provider: "openai", model: "o3-mini", want: EncodingO200k},
+		{provider: "openai", model: "gpt-4-turbo-2024-04-09", want: EncodingCL100k},
+		{provider: "openai", model: "gpt-3.5-turbo", want: EncodingCL100k},
+		{provider: "openai", model: "future-model", want: EncodingO200k},
+		{provider: "codex", model: "", want: EncodingO200k},
+		{provider: "local", model: "openai/gpt-4o", want: EncodingO200k},
+		{provider: "anthropic", model: "claude-sonnet-4-20250514", want: ""},
+		{provider: "gemini", model: "gemini-2.0-flash", want: ""},
+		{provider: "local", model: "llama3.2", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.model, func(t *testing.T) {
+			if got := EncodingForModel(tt.provider, tt.model); got != tt.want {
+				t.Errorf("EncodingForModel(%q, %q) = %q, want %q", tt.provider, tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "Hello world", want: 2},
+		{text: "internationalization", want: 4},
+		{text: "if (x) {", want: 5},
+		{text: "日本語", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Estimate().Count(tt.text); got != tt.want {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeVocab writes a .tiktoken vocabulary ranking tokens in order
+func writeVocab(t *testing.T, dir, encoding string, tokens ...string) {
+	t.Helper()
+	var b strings.Builder
+	for rank, token := range tokens {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), rank)
+	}
+	if err := os.WriteFile(filepath.Join(dir, encoding+".tiktoken"), []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBPE_Count(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DirEnv, dir)
+	writeVocab(t, dir, EncodingCL100k, "a", "b", "c", " ", "ab", "abc", " ab")
+
+	tok := ForModel("openai", "gpt-4")
+	if tok.Name() != EncodingCL100k {
+		t.Fatalf("Name() = %q, want %q", tok.Name(), EncodingCL100k)
+	}
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "abc", want: 1},
+		{text: "abcab", want: 2}, // ab+c+ab, then abc+ab
+		{text: "abc ab", want: 2},
+		{text: "cba", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := tok.Count(tt.text); got != tt.want {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	if ForModel("openai", "gpt-3.5-turbo") != tok {
+		t.Error("ForModel() should reuse the cached encoder")
+	}
+}
+
+func TestForModel_WithoutVocabulary(t *testing.T) {
+	t.Setenv(DirEnv, t.TempDir())
+
+	if got := ForModel("openai", "gpt-4o").Name(); got != "~"+EncodingO200k {
+		t.Errorf("Name() = %q, want estimate for %s", got, EncodingO200k)
+	}
+	if got := ForModel("anthropic", "claude-sonnet-4").Name(); got != "~words" {
+		t.Errorf("Name() = %q, want ~words", got)
+	}
+}
+
+func TestCountExchange(t *testing.T) {
+	tok := ForModel("gemini", "gemini-2.5-pro")
+	input, output := CountExchange("gemini", "gemini-2.5-pro", "Write a haiku", "Autumn moonlight falls")
+	if input != tok.Count("Write a haiku") || output != tok.Count("Autumn moonlight falls") {
+		t.Errorf("CountExchange() = %d, %d, want the model tokenizer's counts", input, output)
+	}
+}
+
+func TestLoadBPE_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"missing-rank": "YQ==\n",
+		"bad-base64":   "!!! 1\n",
+		"bad-rank":     "YQ== one\n",
+		"empty":        "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadBPE(name, path, cl100kPattern); err == nil {
+				t.Error("loadBPE() expected an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package trace
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -34,6 +37,9 @@ type Logger struct {
 
 	// events buffer for in-memory tracking
 	events []*Event
+
+	// spans mirrors events as OpenTelemetry spans (nil unless OTelExport)
+	spans *spanExporter
 }
 
 // Config contains logger configuration
@@ -52,6 +58,15 @@ type Config struct {
 
 	// Enabled controls whether logging is active
 	Enabled bool
+
+	// OTelExport also emits each step as an OpenTelemetry span, with step
+	// start, complete, and fail as span events. It works whether or not
+	// file logging is enabled.
+	OTelExport bool
+
+	// ParentContext carries the span that exported step spans are parented
+	// under, such as the command span
+	ParentContext context.Context
 }
 
 // DefaultConfig returns default logger configuration
@@ -60,7 +75,7 @@ func DefaultConfig() Config {
 	logDir := filepath.Join(homeDir, ".specular", "logs")
 
 	return Config{
-		WorkflowID:  generateWorkflowID(),
+		WorkflowID:  NewWorkflowID(),
 		LogDir:      logDir,
 		MaxFileSize: 10 * 1024 * 1024, // 10MB
 		MaxFiles:    5,
@@ -70,11 +85,17 @@ func DefaultConfig() Config {
 
 // NewLogger creates a new trace logger
 func NewLogger(config Config) (*Logger, error) {
+	var spans *spanExporter
+	if config.OTelExport {
+		spans = newSpanExporter(config.ParentContext)
+	}
+
 	if !config.Enabled {
 		return &Logger{
 			workflowID: config.WorkflowID,
 			enabled:    false,
 			events:     []*Event{},
+			spans:      spans,
 		}, nil
 	}
 
@@ -98,22 +119,41 @@ func NewLogger(config Config) (*Logger, error) {
 		maxFiles:    config.MaxFiles,
 		enabled:     true,
 		events:      []*Event{},
+		spans:       spans,
 	}
 
-	// Write initial metadata
-	metadata := map[string]interface{}{
-		"workflow_id": config.WorkflowID,
-		"started_at":  time.Now(),
-		"version":     "specular/v1",
+	if err := logger.writeHeader(); err != nil {
+		_ = logFile.Close()
+		return nil, err
 	}
-	metadataJSON, _ := json.MarshalIndent(metadata, "", "  ")
-	fmt.Fprintf(logFile, "%s\n", metadataJSON)
 
 	return logger, nil
 }
 
+// writeHeader writes the schema header that starts every trace file
+func (l *Logger) writeHeader() error {
+	header := Header{
+		Schema:     SchemaVersion,
+		WorkflowID: l.workflowID,
+		StartedAt:  time.Now(),
+		Version:    "specular/v1",
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("failed to serialize trace header: %w", err)
+	}
+	if _, err := fmt.Fprintf(l.logFile, "%s\n", headerJSON); err != nil {
+		return fmt.Errorf("failed to write trace header: %w", err)
+	}
+	return nil
+}
+
 // Log logs a trace event
 func (l *Logger) Log(event *Event) error {
+	if l.spans != nil {
+		l.spans.export(event)
+	}
+
 	if !l.enabled {
 		// Still track events in memory even if logging is disabled
 		l.mu.Lock()
@@ -133,8 +173,8 @@ func (l *Logger) Log(event *Event) error {
 		return fmt.Errorf("log rotation failed: %w", err)
 	}
 
-	// Write event to file
-	eventJSON, err := event.ToJSON()
+	// Write event to file, one event per line
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to serialize event: %w", err)
 	}
@@ -172,19 +212,22 @@ func (l *Logger) LogWorkflowComplete(success bool, duration time.Duration, total
 	return l.Log(event)
 }
 
-// LogStepStart logs a step start event
-func (l *Logger) LogStepStart(stepID, stepName string) error {
+// LogStepStart logs a step start event. stepType is the action plan step
+// type, such as spec:update or build:run.
+func (l *Logger) LogStepStart(stepID, stepType, stepName string) error {
 	event := NewEvent(EventTypeStepStart, l.workflowID, fmt.Sprintf("Step started: %s", stepName)).
 		WithStepID(stepID).
+		WithData("step_type", stepType).
 		WithData("step_name", stepName)
 
 	return l.Log(event)
 }
 
 // LogStepComplete logs a step completion event
-func (l *Logger) LogStepComplete(stepID, stepName string, duration time.Duration, cost float64) error {
+func (l *Logger) LogStepComplete(stepID, stepType, stepName string, duration time.Duration, cost float64) error {
 	event := NewEvent(EventTypeStepComplete, l.workflowID, fmt.Sprintf("Step completed: %s", stepName)).
 		WithStepID(stepID).
+		WithData("step_type", stepType).
 		WithData("step_name", stepName).
 		WithData("cost", cost).
 		WithDuration(duration)
@@ -193,9 +236,10 @@ func (l *Logger) LogStepComplete(stepID, stepName string, duration time.Duration
 }
 
 // LogStepFail logs a step failure event
-func (l *Logger) LogStepFail(stepID, stepName string, err error) error {
+func (l *Logger) LogStepFail(stepID, stepType, stepName string, err error) error {
 	event := NewEvent(EventTypeStepFail, l.workflowID, fmt.Sprintf("Step failed: %s", stepName)).
 		WithStepID(stepID).
+		WithData("step_type", stepType).
 		WithData("step_name", stepName).
 		WithError(err)
 
@@ -310,7 +354,7 @@ func (l *Logger) rotate() error {
 	}
 
 	l.logFile = logFile
-	return nil
+	return l.writeHeader()
 }
 
 // cleanupOldFiles removes old rotated log files
@@ -338,6 +382,10 @@ func (l *Logger) cleanupOldFiles() error {
 
 // Close closes the logger and syncs any buffered data
 func (l *Logger) Close() error {
+	if l.spans != nil {
+		l.spans.close()
+	}
+
 	if !l.enabled || l.logFile == nil {
 		return nil
 	}
@@ -376,7 +424,10 @@ func (l *Logger) GetEvents() []*Event {
 	return events
 }
 
-// generateWorkflowID generates a unique workflow ID
-func generateWorkflowID() string {
-	return fmt.Sprintf("auto-%d", time.Now().Unix())
+// NewWorkflowID generates a unique workflow ID, auto-<unix time>-<random
+// hex>. The random suffix keeps runs started in the same second apart.
+func NewWorkflowID() string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix) //#nosec G104 -- crypto/rand.Read does not fail on supported platforms
+	return fmt.Sprintf("auto-%d-%s", time.Now().Unix(), hex.EncodeToString(suffix))
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -43,6 +44,27 @@ func TestNewLogger(t *testing.T) {
 	}
 }
 
+// TestNewWorkflowID tests that workflow IDs are well-formed and distinct
+// within the same second
+func TestNewWorkflowID(t *testing.T) {
+	pattern := regexp.MustCompile(`^auto-\d+-[0-9a-f]{6}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := NewWorkflowID()
+		if !pattern.MatchString(id) {
+			t.Fatalf("NewWorkflowID() = %q, want auto-<unix>-<hex>", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewWorkflowID() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+
+	if id := DefaultConfig().WorkflowID; !pattern.MatchString(id) {
+		t.Errorf("DefaultConfig().WorkflowID = %q, want a generated ID", id)
+	}
+}
+
 // TestNewLoggerDisabled tests disabled logger
 func TestNewLoggerDisabled(t *testing.T) {
 	config := Config{
@@ -169,17 +191,17 @@ func TestLogStepLifecycle(t *testing.T) {
 	defer logger.Close()
 
 	// Log step start
-	if err := logger.LogStepStart("step-1", "Generate Spec"); err != nil {
+	if err := logger.LogStepStart("step-1", "spec:update", "Generate Spec"); err != nil {
 		t.Fatalf("Failed to log step start: %v", err)
 	}
 
 	// Log step complete
-	if err := logger.LogStepComplete("step-1", "Generate Spec", 2*time.Second, 0.05); err != nil {
+	if err := logger.LogStepComplete("step-1", "spec:update", "Generate Spec", 2*time.Second, 0.05); err != nil {
 		t.Fatalf("Failed to log step complete: %v", err)
 	}
 
 	// Log step fail
-	if err := logger.LogStepFail("step-2", "Generate Plan", fmt.Errorf("connection timeout")); err != nil {
+	if err := logger.LogStepFail("step-2", "plan:gen", "Generate Plan", fmt.Errorf("connection timeout")); err != nil {
 		t.Fatalf("Failed to log step fail: %v", err)
 	}
 
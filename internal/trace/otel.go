@@ -0,0 +1,166 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/felixgeelhaar/specular/internal/telemetry"
+)
+
+// spanExporter mirrors trace events as OpenTelemetry spans. Each step
+// becomes a span under the parent span, with its start, completion, or
+// failure recorded as span events. Events outside a step are recorded on
+// the parent span.
+type spanExporter struct {
+	tracer oteltrace.Tracer
+	parent context.Context
+
+	mu    sync.Mutex
+	steps map[string]oteltrace.Span
+}
+
+// newSpanExporter creates an exporter for spans under the span in parent.
+// Spans use the parent span's tracer provider, or the telemetry provider
+// when parent has no span.
+func newSpanExporter(parent context.Context) *spanExporter {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	provider := telemetry.GetTracerProvider()
+	if span := oteltrace.SpanFromContext(parent); span.SpanContext().IsValid() {
+		provider = span.TracerProvider()
+	}
+
+	return &spanExporter{
+		tracer: provider.Tracer("auto"),
+		parent: parent,
+		steps:  make(map[string]oteltrace.Span),
+	}
+}
+
+// export records the event on its step span, starting and ending the span
+// for step start, complete, and fail events
+func (e *spanExporter) export(event *Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	attrs := eventAttributes(event)
+	options := []oteltrace.EventOption{
+		oteltrace.WithTimestamp(event.Timestamp),
+		oteltrace.WithAttributes(attrs...),
+	}
+
+	span, open := e.steps[event.StepID]
+	switch event.Type {
+	case EventTypeStepStart:
+		if open {
+			span.End(oteltrace.WithTimestamp(event.Timestamp))
+		}
+		span = e.startStep(event, event.Timestamp)
+		span.AddEvent(string(event.Type), options...)
+
+	case EventTypeStepComplete, EventTypeStepFail:
+		if !open {
+			// A step logged without a start event still gets a span
+			start := event.Timestamp
+			if event.Duration != nil {
+				start = start.Add(-*event.Duration)
+			}
+			span = e.startStep(event, start)
+		}
+		span.AddEvent(string(event.Type), options...)
+		span.SetAttributes(attrs...)
+		if event.Type == EventTypeStepFail {
+			span.RecordError(errors.New(event.Error), oteltrace.WithTimestamp(event.Timestamp))
+			span.SetStatus(codes.Error, event.Error)
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End(oteltrace.WithTimestamp(event.Timestamp))
+		delete(e.steps, event.StepID)
+
+	default:
+		if !open || event.StepID == "" {
+			span = oteltrace.SpanFromContext(e.parent)
+		}
+		span.AddEvent(string(event.Type), options...)
+	}
+}
+
+// startStep starts the span of the event's step
+func (e *spanExporter) startStep(event *Event, start time.Time) oteltrace.Span {
+	_, span := e.tracer.Start(e.parent, "auto.step",
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithAttributes(
+			attribute.String("component", "auto"),
+			attribute.String("workflow_id", event.WorkflowID),
+			attribute.String("step_id", event.StepID),
+		),
+	)
+	for _, key := range []string{"step_type", "step_name"} {
+		if value, ok := event.Data[key].(string); ok {
+			span.SetAttributes(attribute.String(key, value))
+		}
+	}
+	e.steps[event.StepID] = span
+	return span
+}
+
+// close ends the spans of steps that never completed
+func (e *spanExporter) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for stepID, span := range e.steps {
+		span.SetAttributes(attribute.Bool("unfinished", true))
+		span.End()
+		delete(e.steps, stepID)
+	}
+}
+
+// eventAttributes converts the event's data, duration, and error to span
+// attributes. The duration is reported in milliseconds as duration_ms.
+func eventAttributes(event *Event) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("message", event.Message)}
+	if event.Duration != nil {
+		attrs = append(attrs, attribute.Int64("duration_ms", event.Duration.Milliseconds()))
+	}
+	if event.Error != "" {
+		attrs = append(attrs, attribute.String("error", event.Error))
+	}
+
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := event.Data[key].(type) {
+		case string:
+			attrs = append(attrs, attribute.String(key, value))
+		case bool:
+			attrs = append(attrs, attribute.Bool(key, value))
+		case int:
+			attrs = append(attrs, attribute.Int(key, value))
+		case int64:
+			attrs = append(attrs, attribute.Int64(key, value))
+		case float64:
+			attrs = append(attrs, attribute.Float64(key, value))
+		case time.Duration:
+			attrs = append(attrs, attribute.Int64(key+"_ms", value.Milliseconds()))
+		default:
+			attrs = append(attrs, attribute.String(key, fmt.Sprint(value)))
+		}
+	}
+	return attrs
+}
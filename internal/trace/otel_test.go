@@ -0,0 +1,131 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttr returns the value of an attribute of a recorded span or event
+func spanAttr(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// TestOTelExport tests that steps are exported as spans under the parent
+func TestOTelExport(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background()) //nolint:errcheck
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "command.auto")
+
+	logger, err := NewLogger(Config{
+		WorkflowID:    "auto-123",
+		LogDir:        t.TempDir(),
+		MaxFileSize:   1024 * 1024,
+		MaxFiles:      1,
+		Enabled:       true,
+		OTelExport:    true,
+		ParentContext: ctx,
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	_ = logger.LogWorkflowStart("Build an API", "default")
+	_ = logger.LogStepStart("step-1", "spec:update", "Generate specification")
+	_ = logger.LogPolicyCheck("step-1", true, "", nil)
+	_ = logger.LogStepComplete("step-1", "spec:update", "Generate specification", 1500*time.Millisecond, 0.25)
+	_ = logger.LogStepStart("task-1", "build:run", "Write handler")
+	_ = logger.LogStepFail("task-1", "build:run", "Write handler", errors.New("compile error"))
+	_ = logger.LogStepStart("task-2", "build:run", "Write tests")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 4 {
+		t.Fatalf("expected 3 step spans and the parent, got %d", len(spans))
+	}
+
+	step, failed, unfinished, command := spans[0], spans[1], spans[2], spans[3]
+	for _, span := range []tracetest.SpanStub{step, failed, unfinished} {
+		if span.Name != "auto.step" || span.Parent.SpanID() != command.SpanContext.SpanID() {
+			t.Errorf("span %s is not a step span under the command span", span.Name)
+		}
+	}
+
+	if v, _ := spanAttr(step.Attributes, "step_id"); v.AsString() != "step-1" {
+		t.Errorf("step_id = %q, want step-1", v.AsString())
+	}
+	if v, _ := spanAttr(step.Attributes, "cost"); v.AsFloat64() != 0.25 {
+		t.Errorf("cost = %v, want 0.25", v.AsFloat64())
+	}
+	if v, _ := spanAttr(step.Attributes, "duration_ms"); v.AsInt64() != 1500 {
+		t.Errorf("duration_ms = %v, want 1500", v.AsInt64())
+	}
+	if step.Status.Code != codes.Ok {
+		t.Errorf("step status = %v, want Ok", step.Status.Code)
+	}
+	var names []string
+	for _, event := range step.Events {
+		names = append(names, event.Name)
+	}
+	if len(names) != 3 || names[0] != "step_start" || names[1] != "policy_check" || names[2] != "step_complete" {
+		t.Errorf("step events = %v", names)
+	}
+
+	if failed.Status.Code != codes.Error || failed.Status.Description != "compile error" {
+		t.Errorf("failed step status = %+v", failed.Status)
+	}
+
+	if v, ok := spanAttr(unfinished.Attributes, "unfinished"); !ok || !v.AsBool() {
+		t.Error("expected Close() to end the unfinished step span")
+	}
+
+	if len(command.Events) != 1 || command.Events[0].Name != "workflow_start" {
+		t.Errorf("expected the workflow start on the parent span, got %+v", command.Events)
+	}
+}
+
+// TestOTelExportWithoutFileLogging tests that spans are exported when file
+// logging is disabled
+func TestOTelExportWithoutFileLogging(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background()) //nolint:errcheck
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "command.auto")
+	defer parent.End()
+
+	logger, err := NewLogger(Config{WorkflowID: "auto-123", OTelExport: true, ParentContext: ctx})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	// A step logged only on completion spans its duration
+	_ = logger.LogStepComplete("step-4", "build:run", "Execute tasks", time.Minute, 1.5)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].EndTime.Sub(spans[0].StartTime); got != time.Minute {
+		t.Errorf("span duration = %s, want 1m", got)
+	}
+	if logger.GetLogPath() != "" {
+		t.Error("expected no log file")
+	}
+}
@@ -0,0 +1,216 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Step statuses reported by Event.Status
+const (
+	StatusStarted   = "started"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// ValidStatuses lists the statuses a query can filter on
+var ValidStatuses = []string{StatusStarted, StatusCompleted, StatusFailed}
+
+// Status returns the outcome an event records: started, completed, or
+// failed for step events and workflow completion, and "" otherwise
+func (e *Event) Status() string {
+	switch e.Type {
+	case EventTypeStepStart:
+		return StatusStarted
+	case EventTypeStepComplete:
+		return StatusCompleted
+	case EventTypeStepFail:
+		return StatusFailed
+	case EventTypeWorkflowComplete:
+		if success, _ := e.Data["success"].(bool); success {
+			return StatusCompleted
+		}
+		return StatusFailed
+	}
+	return ""
+}
+
+// StepType returns the action plan step type of a step event
+func (e *Event) StepType() string {
+	stepType, _ := e.Data["step_type"].(string)
+	return stepType
+}
+
+// Cost returns the cost a step or workflow event records
+func (e *Event) Cost() (float64, bool) {
+	for _, key := range []string{"cost", "total_cost"} {
+		if cost, ok := e.Data[key].(float64); ok {
+			return cost, true
+		}
+	}
+	return 0, false
+}
+
+// Query selects trace events. Zero fields match every event.
+type Query struct {
+	// WorkflowID matches events of one workflow
+	WorkflowID string
+
+	// StepTypes matches step events of any of these step types
+	StepTypes []string
+
+	// Status matches events with this Status
+	Status string
+
+	// Since and Until bound the event timestamp, inclusive
+	Since time.Time
+	Until time.Time
+
+	// MinCost matches events that record at least this cost
+	MinCost float64
+}
+
+// Validate checks the query
+func (q Query) Validate() error {
+	if q.Status != "" {
+		valid := false
+		for _, status := range ValidStatuses {
+			valid = valid || q.Status == status
+		}
+		if !valid {
+			return fmt.Errorf("invalid status %q (valid: %v)", q.Status, ValidStatuses)
+		}
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && q.Until.Before(q.Since) {
+		return fmt.Errorf("time range ends before it starts")
+	}
+	if q.MinCost < 0 {
+		return fmt.Errorf("minimum cost cannot be negative")
+	}
+	return nil
+}
+
+// Match reports whether the event matches every filter of the query
+func (q Query) Match(e *Event) bool {
+	if q.WorkflowID != "" && e.WorkflowID != q.WorkflowID {
+		return false
+	}
+	if len(q.StepTypes) > 0 {
+		matched := false
+		for _, stepType := range q.StepTypes {
+			matched = matched || e.StepType() == stepType
+		}
+		if !matched {
+			return false
+		}
+	}
+	if q.Status != "" && e.Status() != q.Status {
+		return false
+	}
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.MinCost > 0 {
+		if cost, ok := e.Cost(); !ok || cost < q.MinCost {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the events in the files that match the query, oldest
+// first
+func (q Query) Filter(files []*File) []*Event {
+	var events []*Event
+	for _, file := range files {
+		for _, event := range file.Events {
+			if q.Match(event) {
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// Group keys for Summarize
+const (
+	GroupByStepType = "type"
+	GroupByWorkflow = "workflow"
+	GroupByStep     = "step"
+)
+
+// ValidGroups lists the keys Summarize can group by
+var ValidGroups = []string{GroupByStepType, GroupByWorkflow, GroupByStep}
+
+// Summary totals the finished steps of one group
+type Summary struct {
+	Key      string        `json:"key"`
+	Steps    int           `json:"steps"`
+	Failed   int           `json:"failed"`
+	CostUSD  float64       `json:"cost_usd"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Summarize totals the completed and failed step events by step type,
+// workflow, or step ID. Other events are ignored. Groups are sorted by cost,
+// highest first.
+func Summarize(events []*Event, groupBy string) ([]Summary, error) {
+	var key func(e *Event) string
+	switch groupBy {
+	case GroupByStepType:
+		key = (*Event).StepType
+	case GroupByWorkflow:
+		key = func(e *Event) string { return e.WorkflowID }
+	case GroupByStep:
+		key = func(e *Event) string { return e.StepID }
+	default:
+		return nil, fmt.Errorf("invalid group %q (valid: %v)", groupBy, ValidGroups)
+	}
+
+	groups := make(map[string]*Summary)
+	for _, event := range events {
+		status := event.Status()
+		if event.StepID == "" || (status != StatusCompleted && status != StatusFailed) {
+			continue
+		}
+
+		name := key(event)
+		if name == "" {
+			name = "unknown"
+		}
+		summary, ok := groups[name]
+		if !ok {
+			summary = &Summary{Key: name}
+			groups[name] = summary
+		}
+
+		summary.Steps++
+		if status == StatusFailed {
+			summary.Failed++
+		}
+		if cost, ok := event.Cost(); ok {
+			summary.CostUSD += cost
+		}
+		if event.Duration != nil {
+			summary.Duration += *event.Duration
+		}
+	}
+
+	summaries := make([]Summary, 0, len(groups))
+	for _, summary := range groups {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].CostUSD != summaries[j].CostUSD {
+			return summaries[i].CostUSD > summaries[j].CostUSD
+		}
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries, nil
+}
@@ -0,0 +1,190 @@
+package trace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTrace logs a run with a completed spec step and a failed build step
+func writeTrace(t *testing.T, dir, workflowID string, specCost float64) {
+	t.Helper()
+	logger, err := NewLogger(Config{WorkflowID: workflowID, LogDir: dir, MaxFileSize: 1024 * 1024, MaxFiles: 1, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	_ = logger.LogWorkflowStart("Build API", "default")
+	_ = logger.LogStepStart("step-1", "spec:update", "Generate specification")
+	_ = logger.LogStepComplete("step-1", "spec:update", "Generate specification", 2*time.Second, specCost)
+	_ = logger.LogStepStart("task-1", "build:run", "Write handler")
+	_ = logger.LogStepFail("task-1", "build:run", "Write handler", fmt.Errorf("compile error"))
+	_ = logger.LogWorkflowComplete(false, 5*time.Second, specCost)
+}
+
+// TestReadFile tests parsing the schema header and events
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTrace(t, dir, "auto-1", 0.25)
+
+	file, err := ReadFile(filepath.Join(dir, "trace_auto-1.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if file.Header.Schema != SchemaVersion || file.Header.WorkflowID != "auto-1" {
+		t.Errorf("unexpected header: %+v", file.Header)
+	}
+	if len(file.Events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(file.Events))
+	}
+	if got := file.Events[2]; got.Type != EventTypeStepComplete || got.StepType() != "spec:update" || got.Duration == nil || *got.Duration != 2*time.Second {
+		t.Errorf("unexpected step event: %+v", got)
+	}
+
+	// Every entry is on its own line
+	data, _ := os.ReadFile(file.Path)
+	if lines := strings.Count(strings.TrimSpace(string(data)), "\n") + 1; lines != 7 {
+		t.Errorf("expected a header and 6 event lines, got %d lines", lines)
+	}
+}
+
+// TestReadFileSchemas tests reading older files and rejecting newer ones
+func TestReadFileSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		events  int
+		wantErr string
+	}{
+		{
+			name: "indented file without schema",
+			content: `{
+  "started_at": "2026-10-01T10:00:00Z",
+  "version": "specular/v1",
+  "workflow_id": "auto-old"
+}
+{
+  "id": "1",
+  "type": "step_fail",
+  "timestamp": "2026-10-01T10:00:01Z",
+  "workflow_id": "auto-old",
+  "step_id": "step-1",
+  "message": "Step failed",
+  "level": "error"
+}
+`,
+			events: 1,
+		},
+		{name: "events only", content: `{"type":"info","workflow_id":"auto-x","timestamp":"2026-10-01T10:00:00Z"}`, events: 1},
+		{name: "newer schema", content: `{"schema":"specular.trace/v2"}`, wantErr: "unsupported trace schema specular.trace/v2"},
+		{name: "unknown schema", content: `{"schema":"other/v1"}`, wantErr: "unknown trace schema"},
+		{name: "invalid", content: `{"type":`, wantErr: "invalid trace file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trace_x.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := ReadFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if len(file.Events) != tt.events || file.Header.WorkflowID == "" {
+				t.Errorf("got %d events and header %+v", len(file.Events), file.Header)
+			}
+		})
+	}
+}
+
+// TestQuery tests event filters across files
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	writeTrace(t, dir, "auto-1", 0.25)
+	writeTrace(t, dir, "auto-2", 1.50)
+	if err := os.WriteFile(filepath.Join(dir, "trace_bad.json"), []byte(`{"schema":"specular.trace/v9"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	files, errs := ReadDir(dir)
+	if len(files) != 2 || len(errs) != 1 {
+		t.Fatalf("expected 2 files and 1 error, got %d and %v", len(files), errs)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{name: "all", query: Query{}, want: 12},
+		{name: "failed", query: Query{Status: StatusFailed}, want: 4},
+		{name: "workflow", query: Query{WorkflowID: "auto-2", Status: StatusFailed}, want: 2},
+		{name: "step type", query: Query{StepTypes: []string{"build:run"}}, want: 4},
+		{name: "min cost", query: Query{MinCost: 1, StepTypes: []string{"spec:update"}}, want: 1},
+		{name: "since", query: Query{Since: now.Add(-time.Hour)}, want: 12},
+		{name: "until", query: Query{Until: now.Add(-time.Hour)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.query.Filter(files); len(got) != tt.want {
+				t.Errorf("Filter() returned %d events, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	invalid := []Query{
+		{Status: "done"},
+		{Since: now, Until: now.Add(-time.Hour)},
+		{MinCost: -1},
+	}
+	for _, query := range invalid {
+		if err := query.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", query)
+		}
+	}
+}
+
+// TestSummarize tests totals by step type
+func TestSummarize(t *testing.T) {
+	dir := t.TempDir()
+	writeTrace(t, dir, "auto-1", 0.25)
+	writeTrace(t, dir, "auto-2", 1.50)
+	files, _ := ReadDir(dir)
+
+	summaries, err := Summarize(Query{}.Filter(files), GroupByStepType)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", summaries)
+	}
+
+	spec, build := summaries[0], summaries[1]
+	if spec.Key != "spec:update" || spec.Steps != 2 || spec.Failed != 0 || spec.CostUSD != 1.75 || spec.Duration != 4*time.Second {
+		t.Errorf("unexpected spec summary: %+v", spec)
+	}
+	if build.Key != "build:run" || build.Steps != 2 || build.Failed != 2 {
+		t.Errorf("unexpected build summary: %+v", build)
+	}
+
+	if _, err := Summarize(nil, "model"); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+}
@@ -0,0 +1,134 @@
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersion identifies the trace file format. Every trace file starts
+// with a Header naming it, so readers can tell formats apart as the format
+// evolves.
+const SchemaVersion = "specular.trace/v1"
+
+// schemaPrefix is shared by every trace schema version
+const schemaPrefix = "specular.trace/"
+
+// Header is the first line of a trace file
+type Header struct {
+	// Schema is the trace file format; empty in files written before
+	// schema versions were introduced
+	Schema string `json:"schema"`
+
+	// WorkflowID identifies the workflow
+	WorkflowID string `json:"workflow_id"`
+
+	// StartedAt is when the file was started
+	StartedAt time.Time `json:"started_at"`
+
+	// Version is the writer version
+	Version string `json:"version"`
+}
+
+// File is a parsed trace file
+type File struct {
+	// Path is the file path
+	Path string
+
+	// Header is the file header
+	Header Header
+
+	// Events are the events in the file, in the order they were logged
+	Events []*Event
+}
+
+// ReadFile parses a trace file. Events may be one per line or indented, as
+// written by older versions. Files with a newer schema are rejected.
+func ReadFile(path string) (*File, error) {
+	f, err := os.Open(path) //#nosec G304 -- Trace file path from the log directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	defer f.Close()
+
+	file := &File{Path: path}
+	decoder := json.NewDecoder(f)
+	for index := 0; ; index++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid trace file %s: %w", path, err)
+		}
+
+		var probe struct {
+			Type EventType `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			return nil, fmt.Errorf("invalid trace file %s: %w", path, err)
+		}
+
+		// The header is the only entry without an event type
+		if index == 0 && probe.Type == "" {
+			if err := json.Unmarshal(raw, &file.Header); err != nil {
+				return nil, fmt.Errorf("invalid trace header in %s: %w", path, err)
+			}
+			if err := checkSchema(file.Header.Schema); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("invalid trace event in %s: %w", path, err)
+		}
+		file.Events = append(file.Events, &event)
+	}
+
+	if file.Header.WorkflowID == "" && len(file.Events) > 0 {
+		file.Header.WorkflowID = file.Events[0].WorkflowID
+	}
+	return file, nil
+}
+
+// checkSchema accepts the current schema and files written before schema
+// versions were introduced
+func checkSchema(schema string) error {
+	if schema == "" || schema == SchemaVersion {
+		return nil
+	}
+	if strings.HasPrefix(schema, schemaPrefix) {
+		return fmt.Errorf("unsupported trace schema %s (this version reads %s); upgrade specular to read it", schema, SchemaVersion)
+	}
+	return fmt.Errorf("unknown trace schema %q", schema)
+}
+
+// ReadDir parses every trace file (trace_*.json) in dir, including rotated
+// files. Files that cannot be parsed are returned as errors alongside the
+// files that could.
+func ReadDir(dir string) ([]*File, []error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "trace_*.json"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	sort.Strings(paths)
+
+	var files []*File
+	var errs []error
+	for _, path := range paths {
+		file, err := ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, errs
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/felixgeelhaar/specular/internal/plan"
 )
 
-// Adapter bridges between the orchestrator and the TUI
+// Adapter bridges between the orchestrator and the TUI. Notifications are
+// queued and delivered in the background, so a busy TUI never blocks the
+// orchestrator.
 type Adapter struct {
 	program *tea.Program
 	model   *Model
+	updates *updateQueue // Nil until Start
 	ctx     context.Context
 	cancel  context.CancelFunc
 }
@@ -31,7 +34,9 @@ func NewAdapter(goal, profile string) *Adapter {
 // Start starts the TUI program
 func (a *Adapter) Start() error {
 	a.ctx, a.cancel = context.WithCancel(context.Background())
-	a.program = tea.NewProgram(*a.model)
+	// The alternate screen lets the TUI redraw cleanly when the terminal is
+	// resized; mouse events allow clicking steps and scrolling with the wheel
+	a.program = tea.NewProgram(*a.model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	// Start the TUI in a goroutine
 	go func() {
@@ -40,6 +45,9 @@ func (a *Adapter) Start() error {
 		}
 	}()
 
+	a.updates = newUpdateQueue(updateQueueLimit)
+	go a.updates.run(a.program.Send)
+
 	return nil
 }
 
@@ -48,11 +56,21 @@ func (a *Adapter) Stop() {
 	if a.cancel != nil {
 		a.cancel()
 	}
+	if a.updates != nil {
+		a.updates.close()
+	}
 	if a.program != nil {
 		a.program.Quit()
 	}
 }
 
+// send queues a message for the TUI without waiting for it to be handled
+func (a *Adapter) send(msg tea.Msg) {
+	if a.updates != nil {
+		a.updates.push(msg)
+	}
+}
+
 // SetActionPlan sets the action plan in the TUI
 func (a *Adapter) SetActionPlan(plan *auto.ActionPlan) {
 	if a.model != nil {
@@ -69,38 +87,41 @@ func (a *Adapter) SetAutoOutput(output *auto.AutoOutput) {
 
 // NotifyStepStart notifies the TUI that a step has started
 func (a *Adapter) NotifyStepStart(stepIndex int, stepName string) {
-	if a.program != nil {
-		a.program.Send(StepStartMsg{
-			StepIndex: stepIndex,
-			StepName:  stepName,
-		})
-	}
+	a.send(StepStartMsg{
+		StepIndex: stepIndex,
+		StepName:  stepName,
+	})
 }
 
 // NotifyStepComplete notifies the TUI that a step has completed
 func (a *Adapter) NotifyStepComplete(stepIndex int, stepName string, totalCost float64) {
-	if a.program != nil {
-		a.program.Send(StepCompleteMsg{
-			StepIndex: stepIndex,
-			StepName:  stepName,
-			TotalCost: totalCost,
-		})
-	}
+	a.send(StepCompleteMsg{
+		StepIndex: stepIndex,
+		StepName:  stepName,
+		TotalCost: totalCost,
+	})
 }
 
 // NotifyStepFail notifies the TUI that a step has failed
 func (a *Adapter) NotifyStepFail(stepIndex int, stepName string, err error) {
-	if a.program != nil {
-		errorMsg := ""
-		if err != nil {
-			errorMsg = err.Error()
-		}
-		a.program.Send(StepFailMsg{
-			StepIndex: stepIndex,
-			StepName:  stepName,
-			Error:     errorMsg,
-		})
+	errorMsg := ""
+	if err != nil {
+		errorMsg = err.Error()
 	}
+	a.send(StepFailMsg{
+		StepIndex: stepIndex,
+		StepName:  stepName,
+		Error:     errorMsg,
+	})
+}
+
+// NotifyOutput sends output produced by a step or task to the TUI output pane
+func (a *Adapter) NotifyOutput(source, stream, text string) {
+	a.send(OutputChunkMsg{
+		Source: source,
+		Stream: stream,
+		Text:   text,
+	})
 }
 
 // RequestApproval requests user approval for the plan
@@ -125,7 +146,7 @@ func (a *Adapter) RequestApproval(execPlan *plan.Plan) (bool, error) {
 	responseChan := make(chan bool, 1)
 
 	// Create a custom message handler
-	a.program.Send(ApprovalRequestMsg{
+	a.send(ApprovalRequestMsg{
 		PlanSummary: summary,
 	})
 
@@ -143,14 +164,13 @@ func (a *Adapter) RequestApproval(execPlan *plan.Plan) (bool, error) {
 
 // NotifyComplete notifies the TUI that the workflow has completed
 func (a *Adapter) NotifyComplete(success bool, totalCost float64, duration time.Duration) {
-	if a.program != nil {
-		a.program.Send(WorkflowCompleteMsg{
-			Success:   success,
-			TotalCost: totalCost,
-			Duration:  duration,
-		})
-	}
+	a.send(WorkflowCompleteMsg{
+		Success:   success,
+		TotalCost: totalCost,
+		Duration:  duration,
+	})
 
-	// Wait a bit for the user to see the completion message
+	// Wait a bit for the queued updates to drain and the user to see the
+	// completion message
 	time.Sleep(2 * time.Second)
 }
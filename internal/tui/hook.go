@@ -7,7 +7,9 @@ import (
 	"github.com/felixgeelhaar/specular/internal/hooks"
 )
 
-// Hook is a hook implementation that forwards orchestrator events to the TUI
+// Hook is a hook implementation that forwards orchestrator events to the
+// TUI. The adapter queues the updates, so Execute does not wait for the TUI
+// to render them.
 type Hook struct {
 	adapter *Adapter
 	enabled bool
@@ -38,6 +40,7 @@ func (h *Hook) EventTypes() []hooks.EventType {
 		hooks.EventStepBefore,
 		hooks.EventStepAfter,
 		hooks.EventStepFailed,
+		hooks.EventOutputChunk,
 	}
 }
 
@@ -117,6 +120,15 @@ func (h *Hook) Execute(ctx context.Context, event *hooks.Event) error {
 
 		h.adapter.NotifyStepFail(stepIndex, stepName, err)
 
+	case hooks.EventOutputChunk:
+		// Output of a task is labelled with the task, other output with the step
+		source := event.GetString("task_id")
+		if source == "" {
+			source = event.GetString("step_id")
+		}
+
+		h.adapter.NotifyOutput(source, event.GetString("stream"), event.GetString("text"))
+
 	case hooks.EventWorkflowComplete, hooks.EventWorkflowFailed:
 		// Get success status
 		success := event.Type == hooks.EventWorkflowComplete
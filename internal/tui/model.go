@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -58,6 +59,13 @@ type Model struct {
 	// Error state
 	lastError string
 
+	// Output pane state
+	output outputPane
+
+	// Step list state
+	expandedStep int // Index of the step showing details, -1 for none
+	stepOffset   int // First visible line of the step list
+
 	// Styles
 	styles Styles
 }
@@ -81,11 +89,13 @@ type Styles struct {
 // NewModel creates a new TUI model
 func NewModel(goal, profile string) Model {
 	return Model{
-		goal:        goal,
-		profile:     profile,
-		currentView: ViewMain,
-		startTime:   time.Now(),
-		styles:      DefaultStyles(),
+		goal:         goal,
+		profile:      profile,
+		currentView:  ViewMain,
+		startTime:    time.Now(),
+		output:       newOutputPane(),
+		expandedStep: -1,
+		styles:       DefaultStyles(),
 	}
 }
 
@@ -144,6 +154,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -153,16 +166,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StepStartMsg:
 		m.currentStep = msg.StepIndex
 		m.currentStepName = msg.StepName
+		m.output.append("", outputStreamEvent, "▶ "+msg.StepName)
 		return m, nil
 
 	case StepCompleteMsg:
 		m.completedSteps++
 		m.totalCost = msg.TotalCost
+		m.output.append("", outputStreamEvent, "✓ "+msg.StepName)
 		return m, nil
 
 	case StepFailMsg:
 		m.failedSteps++
 		m.lastError = msg.Error
+		m.output.append("", outputStreamEvent, fmt.Sprintf("✗ %s: %s", msg.StepName, msg.Error))
+		return m, nil
+
+	case OutputChunkMsg:
+		m.output.append(msg.Source, msg.Stream, msg.Text)
 		return m, nil
 
 	case ApprovalRequestMsg:
@@ -182,6 +202,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the TUI (required by Bubble Tea)
 func (m Model) View() string {
+	view := m.render()
+
+	// Lines wider than the terminal wrap and corrupt the layout, so clip
+	// anything the views could not shorten
+	if m.width > 0 {
+		view = lipgloss.NewStyle().MaxWidth(m.width).Render(view)
+	}
+	return view
+}
+
+// render renders the current view
+func (m Model) render() string {
 	if !m.ready {
 		return "Initializing..."
 	}
@@ -235,6 +267,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Output pane search input
+	if m.output.searching {
+		return m.handleSearchKey(msg)
+	}
+
 	// Normal key handling
 	switch msg.String() {
 	case "q":
@@ -264,6 +301,97 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if !m.awaitingInput {
 			m.currentView = ViewMain
 		}
+
+	case "pgup", "pgdown", "f", "/", "n", "N":
+		if m.currentView == ViewMain {
+			m.handleOutputKey(msg.String())
+		}
+	}
+
+	return m, nil
+}
+
+// mouseWheelLines is the number of lines a mouse wheel notch scrolls
+const mouseWheelLines = 3
+
+// handleMouse handles mouse clicks and wheel scrolling. Clicking a step in
+// the step list toggles its details; the wheel scrolls the output pane in
+// the main view and the step list in the step list view.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || m.awaitingInput {
+		return m, nil
+	}
+
+	delta := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		delta = -mouseWheelLines
+	case tea.MouseButtonWheelDown:
+		delta = mouseWheelLines
+	case tea.MouseButtonLeft:
+		if m.currentView == ViewStepList {
+			if index := m.stepAt(msg.Y); index >= 0 {
+				if m.expandedStep == index {
+					m.expandedStep = -1
+				} else {
+					m.expandedStep = index
+				}
+			}
+		}
+		return m, nil
+	default:
+		return m, nil
+	}
+
+	switch m.currentView {
+	case ViewMain:
+		m.output.scroll(delta, max(m.outputPaneHeight(), 1))
+	case ViewStepList:
+		m.stepOffset = min(max(m.stepOffset+delta, 0), m.maxStepOffset())
+	}
+
+	return m, nil
+}
+
+// handleOutputKey handles the output pane scroll, follow, and search keys
+func (m *Model) handleOutputKey(key string) {
+	height := max(m.outputPaneHeight(), 1)
+
+	switch key {
+	case "pgup":
+		m.output.scroll(-height, height)
+	case "pgdown":
+		m.output.scroll(height, height)
+	case "f":
+		m.output.toggleFollow(height)
+	case "/":
+		m.output.searching = true
+		m.output.query = ""
+	case "n":
+		m.output.search(1, height)
+	case "N":
+		m.output.search(-1, height)
+	}
+}
+
+// handleSearchKey handles input while an output search query is typed
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.output.searching = false
+		m.output.match = -1
+		m.output.search(-1, max(m.outputPaneHeight(), 1))
+	case tea.KeyEsc:
+		m.output.searching = false
+		m.output.query = ""
+	case tea.KeyBackspace:
+		if query := []rune(m.output.query); len(query) > 0 {
+			m.output.query = string(query[:len(query)-1])
+		}
+	case tea.KeySpace:
+		m.output.query += " "
+	case tea.KeyRunes:
+		m.output.query += string(msg.Runes)
 	}
 
 	return m, nil
@@ -304,6 +432,13 @@ type StepFailMsg struct {
 	Error     string
 }
 
+// OutputChunkMsg carries output produced by a step or task
+type OutputChunkMsg struct {
+	Source string // Step or task ID
+	Stream string // stdout, stderr, or model
+	Text   string
+}
+
 // ApprovalRequestMsg requests user approval
 type ApprovalRequestMsg struct {
 	PlanSummary string
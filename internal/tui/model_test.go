@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/felixgeelhaar/specular/internal/auto"
 )
@@ -400,3 +401,89 @@ func TestViewRendering(t *testing.T) {
 		t.Error("Completion view should contain status")
 	}
 }
+
+// TestWindowResizeReflow tests that the views fit the terminal after a resize
+func TestWindowResizeReflow(t *testing.T) {
+	goal := "Build a REST API with authentication, rate limiting, pagination, and an admin dashboard"
+	model := NewModel(goal, "default")
+	model.SetActionPlan(auto.CreateDefaultActionPlan(goal, "default"))
+
+	for _, width := range []int{120, 50, 30} {
+		updated, _ := model.Update(tea.WindowSizeMsg{Width: width, Height: 40})
+		m := updated.(Model)
+
+		for _, view := range []ViewType{ViewMain, ViewStepList} {
+			m.currentView = view
+			for _, line := range strings.Split(m.View(), "\n") {
+				if w := lipgloss.Width(line); w > width {
+					t.Errorf("view %d at width %d has a line %d wide: %q", view, width, w, line)
+				}
+			}
+		}
+
+		m.currentView = ViewMain
+		truncated := strings.Contains(m.View(), "…")
+		if truncated != (width < len(goal)) {
+			t.Errorf("width %d: goal truncated = %v", width, truncated)
+		}
+	}
+}
+
+// TestMouseClickExpandsStep tests clicking steps in the step list
+func TestMouseClickExpandsStep(t *testing.T) {
+	model := NewModel("Test goal", "default")
+	model.SetActionPlan(auto.CreateDefaultActionPlan("Test goal", "default"))
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	m := updated.(Model)
+	m.currentView = ViewStepList
+
+	// The first step is on the last line of the header
+	secondStep := lipgloss.Height(m.renderStepListHeader())
+	click := tea.MouseMsg{X: 5, Y: secondStep, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+
+	updated, _ = m.Update(click)
+	m = updated.(Model)
+	if m.expandedStep != 1 {
+		t.Fatalf("expected step 1 to be expanded, got %d", m.expandedStep)
+	}
+	if !strings.Contains(m.View(), "Status: pending") {
+		t.Error("expected the step details in the step list")
+	}
+
+	// Clicking below the expanded details selects the next step
+	if got := m.stepAt(secondStep + lipgloss.Height(m.stepListEntries()[1])); got != 2 {
+		t.Errorf("expected the row after the details to be step 2, got %d", got)
+	}
+
+	updated, _ = m.Update(click)
+	m = updated.(Model)
+	if m.expandedStep != -1 {
+		t.Errorf("expected a second click to collapse the step, got %d", m.expandedStep)
+	}
+
+	if got := m.stepAt(0); got != -1 {
+		t.Errorf("expected no step at the title row, got %d", got)
+	}
+}
+
+// TestMouseWheelScrollsOutput tests scrolling the output pane with the wheel
+func TestMouseWheelScrollsOutput(t *testing.T) {
+	model := NewModel("Test goal", "default")
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 80, Height: 60})
+	updated, _ = updated.Update(OutputChunkMsg{Stream: "stdout", Text: strings.Repeat("output\n", 100)})
+	m := updated.(Model)
+
+	updated, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	m = updated.(Model)
+	if m.output.follow {
+		t.Error("expected scrolling up to turn follow mode off")
+	}
+
+	for i := 0; i < 2; i++ {
+		updated, _ = m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+		m = updated.(Model)
+	}
+	if !m.output.follow {
+		t.Error("expected scrolling to the bottom to turn follow mode on")
+	}
+}
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// outputMaxLines is the number of lines kept for scrollback
+	outputMaxLines = 2000
+
+	// outputPaneMinHeight is the fewest visible lines worth showing; with
+	// less room the pane collapses to a one-line notice
+	outputPaneMinHeight = 3
+
+	// outputPaneMinWidth is the narrowest terminal the pane is shown in
+	outputPaneMinWidth = 40
+)
+
+// Output line streams besides the stdout, stderr, and model streams of
+// output chunk events
+const (
+	outputStreamEvent = "event"
+)
+
+// outputLine is a single line in the output pane
+type outputLine struct {
+	source string // Step or task that produced the line
+	stream string // stdout, stderr, model, or event
+	text   string
+}
+
+// outputPane is a scrollable tail of workflow output and events. In follow
+// mode the view sticks to the newest line; scrolling up or searching turns
+// follow mode off so new output does not move the view.
+type outputPane struct {
+	lines  []outputLine
+	top    int // First visible line when not following
+	follow bool
+
+	// Search state
+	searching bool   // True while the search query is being typed
+	query     string // Last confirmed or in-progress query
+	match     int    // Index of the current match, -1 for none
+}
+
+// newOutputPane creates an empty output pane in follow mode
+func newOutputPane() outputPane {
+	return outputPane{follow: true, match: -1}
+}
+
+// append adds text to the pane, one line per newline-separated line. The
+// oldest lines are dropped once the pane holds more than outputMaxLines.
+func (p *outputPane) append(source, stream, text string) {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+		p.lines = append(p.lines, outputLine{source: source, stream: stream, text: line})
+	}
+
+	if dropped := len(p.lines) - outputMaxLines; dropped > 0 {
+		p.lines = append([]outputLine(nil), p.lines[dropped:]...)
+		p.top = max(p.top-dropped, 0)
+		if p.match >= 0 {
+			p.match -= dropped
+			if p.match < 0 {
+				p.match = -1
+			}
+		}
+	}
+}
+
+// firstVisible returns the index of the first line shown in a view of
+// height lines
+func (p *outputPane) firstVisible(height int) int {
+	last := max(len(p.lines)-height, 0)
+	if p.follow {
+		return last
+	}
+	return min(max(p.top, 0), last)
+}
+
+// scroll moves the view by delta lines. Scrolling to the bottom turns
+// follow mode back on.
+func (p *outputPane) scroll(delta, height int) {
+	p.top = p.firstVisible(height) + delta
+	last := max(len(p.lines)-height, 0)
+	p.top = min(max(p.top, 0), last)
+	p.follow = delta > 0 && p.top == last
+}
+
+// toggleFollow switches follow mode. Turning it off keeps the current view.
+func (p *outputPane) toggleFollow(height int) {
+	if p.follow {
+		p.top = p.firstVisible(height)
+	}
+	p.follow = !p.follow
+}
+
+// search moves to the next match of the query in direction dir (1 for
+// newer, -1 for older). Without a current match the search starts at the
+// newest line. Returns false when nothing matches.
+func (p *outputPane) search(dir, height int) bool {
+	query := strings.ToLower(p.query)
+	if query == "" || len(p.lines) == 0 {
+		return false
+	}
+
+	start := p.match
+	if start < 0 {
+		start, dir = len(p.lines), -1
+	}
+
+	for i := 1; i <= len(p.lines); i++ {
+		idx := start + dir*i
+		if idx < 0 || idx >= len(p.lines) {
+			break
+		}
+		if strings.Contains(strings.ToLower(p.lines[idx].text), query) {
+			p.match = idx
+			p.follow = false
+			p.top = max(idx-height/2, 0)
+			return true
+		}
+	}
+	return false
+}
+
+// view renders height lines of the pane, each at most width cells wide
+func (p *outputPane) view(styles Styles, width, height int) string {
+	if len(p.lines) == 0 {
+		return styles.Muted.Render("No output yet")
+	}
+
+	first := p.firstVisible(height)
+	last := min(first+height, len(p.lines))
+	truncate := lipgloss.NewStyle().MaxWidth(width)
+
+	rendered := make([]string, 0, last-first)
+	for i := first; i < last; i++ {
+		line := p.lines[i]
+
+		var b strings.Builder
+		if line.source != "" {
+			b.WriteString(styles.Muted.Render(line.source + " "))
+		}
+		switch {
+		case i == p.match:
+			b.WriteString(styles.Highlighted.Padding(0).Render(line.text))
+		case line.stream == "stderr":
+			b.WriteString(styles.Warning.Bold(false).Render(line.text))
+		case line.stream == outputStreamEvent:
+			b.WriteString(styles.Status.Bold(false).Render(line.text))
+		default:
+			b.WriteString(line.text)
+		}
+		rendered = append(rendered, truncate.Render(b.String()))
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+// status describes the pane state for its title line
+func (p *outputPane) status(height int) string {
+	var parts []string
+	if p.follow {
+		parts = append(parts, "following")
+	} else if len(p.lines) > 0 {
+		first := p.firstVisible(height)
+		parts = append(parts, fmt.Sprintf("lines %d-%d of %d", first+1, min(first+height, len(p.lines)), len(p.lines)))
+	}
+
+	switch {
+	case p.searching:
+		parts = append(parts, "search: "+p.query+"█")
+	case p.query != "" && p.match < 0:
+		parts = append(parts, fmt.Sprintf("no match for %q", p.query))
+	case p.query != "":
+		parts = append(parts, fmt.Sprintf("match %q", p.query))
+	}
+
+	return strings.Join(parts, " • ")
+}
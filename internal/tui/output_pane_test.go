@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newOutputModel returns a ready model with numbered output lines
+func newOutputModel(width, height, lines int) Model {
+	model := NewModel("Test goal", "default")
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	model = updated.(Model)
+
+	var text strings.Builder
+	for i := 1; i <= lines; i++ {
+		fmt.Fprintf(&text, "line %d\n", i)
+	}
+	updated, _ = model.Update(OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: text.String()})
+	return updated.(Model)
+}
+
+// pressKeys sends key presses to the model
+func pressKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// TestOutputPaneAppend tests line splitting and the scrollback limit
+func TestOutputPaneAppend(t *testing.T) {
+	pane := newOutputPane()
+	pane.append("task-1", "stdout", "a\r\nb\tc\n\n")
+	pane.append("task-1", "stdout", "")
+
+	if len(pane.lines) != 2 || pane.lines[1].text != "b    c" {
+		t.Fatalf("unexpected lines: %+v", pane.lines)
+	}
+
+	pane.follow = false
+	pane.top = 5
+	for i := 0; i < outputMaxLines; i++ {
+		pane.append("", "stdout", "x")
+	}
+	if len(pane.lines) != outputMaxLines {
+		t.Errorf("expected %d lines, got %d", outputMaxLines, len(pane.lines))
+	}
+	if pane.top != 3 {
+		t.Errorf("expected top to move with dropped lines, got %d", pane.top)
+	}
+}
+
+// TestOutputPaneFollow tests follow mode, scrolling, and the follow toggle
+func TestOutputPaneFollow(t *testing.T) {
+	m := newOutputModel(80, 60, 100)
+	height := m.outputPaneHeight()
+	if height < outputPaneMinHeight {
+		t.Fatalf("expected room for the output pane, got %d lines", height)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "line 100") || strings.Contains(view, "line 1\n") {
+		t.Errorf("expected the view to follow the newest output:\n%s", view)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.output.follow {
+		t.Error("expected PgUp to turn follow mode off")
+	}
+	if first := m.output.firstVisible(height); first != 100-2*height {
+		t.Errorf("expected first visible line %d, got %d", 100-2*height, first)
+	}
+
+	// New output does not move a scrolled view
+	updated, _ := m.Update(OutputChunkMsg{Stream: "stdout", Text: "line 101"})
+	m = updated.(Model)
+	if first := m.output.firstVisible(height); first != 100-2*height {
+		t.Errorf("expected the view to stay at %d, got %d", 100-2*height, first)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyPgDown}, tea.KeyMsg{Type: tea.KeyPgDown})
+	if !m.output.follow {
+		t.Error("expected PgDn to the bottom to turn follow mode back on")
+	}
+
+	m = pressKeys(m, runeKey("f"))
+	if m.output.follow {
+		t.Error("expected f to turn follow mode off")
+	}
+	m = pressKeys(m, runeKey("f"))
+	if !m.output.follow {
+		t.Error("expected f to turn follow mode on")
+	}
+}
+
+// TestOutputPaneSearch tests typing a query and moving between matches
+func TestOutputPaneSearch(t *testing.T) {
+	m := newOutputModel(80, 60, 100)
+
+	m = pressKeys(m, runeKey("/"), runeKey("LINE 1"), runeKey("x"), tea.KeyMsg{Type: tea.KeyBackspace})
+	if !m.output.searching || m.output.query != "LINE 1" {
+		t.Fatalf("expected query %q while searching, got %q", "LINE 1", m.output.query)
+	}
+
+	// Typed keys go to the query, not to the other hotkeys
+	if m.currentView != ViewMain {
+		t.Error("expected search input not to switch views")
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.output.searching {
+		t.Error("expected enter to end search input")
+	}
+	if got := m.output.lines[m.output.match].text; got != "line 100" {
+		t.Errorf("expected the newest match, got %q", got)
+	}
+	if m.output.follow {
+		t.Error("expected search to turn follow mode off")
+	}
+
+	m = pressKeys(m, runeKey("N"))
+	if got := m.output.lines[m.output.match].text; got != "line 19" {
+		t.Errorf("expected the previous match, got %q", got)
+	}
+	m = pressKeys(m, runeKey("n"))
+	if got := m.output.lines[m.output.match].text; got != "line 100" {
+		t.Errorf("expected the next match, got %q", got)
+	}
+
+	m = pressKeys(m, runeKey("/"), runeKey("missing"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.output.match != -1 || !strings.Contains(m.View(), `no match for "missing"`) {
+		t.Errorf("expected no match, got %d", m.output.match)
+	}
+
+	m = pressKeys(m, runeKey("/"), runeKey("q"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.output.searching || m.output.query != "" || m.quitting {
+		t.Error("expected esc to cancel the search")
+	}
+}
+
+// TestOutputPaneSmallTerminal tests that the pane collapses when there is
+// not enough room
+func TestOutputPaneSmallTerminal(t *testing.T) {
+	// Lines taken by the rest of the main view
+	chrome := 100 - newOutputModel(80, 100, 0).outputPaneHeight()
+
+	tests := []struct {
+		name          string
+		width, height int
+		want          string
+	}{
+		{name: "large", width: 80, height: chrome + 20, want: "── Output"},
+		{name: "short", width: 80, height: chrome + outputPaneMinHeight - 1, want: "Output hidden"},
+		{name: "narrow", width: outputPaneMinWidth - 1, height: chrome + 20, want: "Output hidden"},
+		{name: "no room", width: 80, height: chrome - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newOutputModel(tt.width, tt.height, 50)
+			view := m.View()
+			if tt.want == "" {
+				if strings.Contains(view, "Output") {
+					t.Errorf("expected no output pane:\n%s", view)
+				}
+				return
+			}
+			if !strings.Contains(view, tt.want) {
+				t.Errorf("expected view to contain %q:\n%s", tt.want, view)
+			}
+			if lines := strings.Count(view, "\n") + 1; lines > tt.height {
+				t.Errorf("view has %d lines, terminal has %d", lines, tt.height)
+			}
+		})
+	}
+}
+
+// TestStepEventsInOutputPane tests that step messages are logged in the pane
+func TestStepEventsInOutputPane(t *testing.T) {
+	model := NewModel("Test goal", "default")
+
+	for _, msg := range []tea.Msg{
+		StepStartMsg{StepIndex: 1, StepName: "Build"},
+		StepFailMsg{StepIndex: 1, StepName: "Build", Error: "exit 1"},
+	} {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+
+	if len(model.output.lines) != 2 || model.output.lines[1].text != "✗ Build: exit 1" {
+		t.Errorf("unexpected output lines: %+v", model.output.lines)
+	}
+}
@@ -13,6 +13,10 @@ type Prompt struct {
 	Default     string
 	Placeholder string
 	Required    bool
+
+	// Validate checks the value as it is entered; the prompt is not
+	// accepted until it returns nil
+	Validate func(string) error
 }
 
 // PromptForString displays an interactive prompt and returns the user's input
@@ -29,6 +33,10 @@ func PromptForString(p Prompt) (string, error) {
 		value = p.Default
 	}
 
+	if p.Validate != nil {
+		input = input.Validate(p.Validate)
+	}
+
 	form := huh.NewForm(huh.NewGroup(input))
 
 	if err := form.Run(); err != nil {
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateQueueLimit bounds the messages waiting for the TUI. Beyond it the
+// oldest output is dropped; step, approval, and workflow events are kept.
+const updateQueueLimit = 256
+
+// updateQueue passes messages from the orchestrator to the TUI program
+// without blocking the orchestrator. Messages wait in a bounded queue and
+// are sent in order by a single goroutine. While the TUI falls behind,
+// waiting messages are coalesced: consecutive output from the same source
+// and stream is merged, and a step's newer event replaces its older one.
+type updateQueue struct {
+	mu      sync.Mutex
+	pending []tea.Msg
+	limit   int
+	dropped int // Output messages dropped since the last notice
+
+	wake      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newUpdateQueue creates a queue holding up to limit messages
+func newUpdateQueue(limit int) *updateQueue {
+	return &updateQueue{
+		limit: limit,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// push queues a message and returns immediately
+func (q *updateQueue) push(msg tea.Msg) {
+	q.mu.Lock()
+	switch m := msg.(type) {
+	case OutputChunkMsg:
+		q.pushOutput(m)
+	case StepStartMsg:
+		q.pushStep(m.StepIndex, m)
+	case StepCompleteMsg:
+		q.pushStep(m.StepIndex, m)
+	case StepFailMsg:
+		q.pushStep(m.StepIndex, m)
+	default:
+		q.pending = append(q.pending, msg)
+	}
+	q.trim()
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default: // The sender is already awake
+	}
+}
+
+// pushOutput merges output into the last waiting message when it comes
+// from the same source and stream
+func (q *updateQueue) pushOutput(msg OutputChunkMsg) {
+	if n := len(q.pending); n > 0 {
+		if last, ok := q.pending[n-1].(OutputChunkMsg); ok && last.Source == msg.Source && last.Stream == msg.Stream {
+			q.pending[n-1] = mergeOutput(last, msg)
+			return
+		}
+	}
+	q.pending = append(q.pending, msg)
+}
+
+// pushStep queues a step event in place of any waiting event for the
+// same step, which the TUI would only pass through on its way to this one
+func (q *updateQueue) pushStep(stepIndex int, msg tea.Msg) {
+	kept := q.pending[:0]
+	for _, waiting := range q.pending {
+		if index, ok := stepEventIndex(waiting); !ok || index != stepIndex {
+			kept = append(kept, waiting)
+		}
+	}
+	q.pending = append(kept, msg)
+}
+
+// trim drops the oldest output until the queue is within its limit. Other
+// messages are never dropped.
+func (q *updateQueue) trim() {
+	for len(q.pending) > q.limit {
+		oldest := -1
+		for i, waiting := range q.pending {
+			if _, ok := waiting.(OutputChunkMsg); ok {
+				oldest = i
+				break
+			}
+		}
+		if oldest < 0 {
+			return
+		}
+		q.pending = append(q.pending[:oldest], q.pending[oldest+1:]...)
+		q.dropped++
+	}
+}
+
+// next removes the next message to send. After output was dropped, a
+// notice in the output pane comes first.
+func (q *updateQueue) next() (tea.Msg, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.dropped > 0 {
+		notice := OutputChunkMsg{
+			Stream: outputStreamEvent,
+			Text:   fmt.Sprintf("… %d output updates skipped while the display caught up", q.dropped),
+		}
+		q.dropped = 0
+		return notice, true
+	}
+	if len(q.pending) == 0 {
+		return nil, false
+	}
+
+	msg := q.pending[0]
+	q.pending = q.pending[1:]
+	if len(q.pending) == 0 {
+		q.pending = nil
+	}
+	return msg, true
+}
+
+// run sends queued messages with send until the queue is closed
+func (q *updateQueue) run(send func(tea.Msg)) {
+	for {
+		for {
+			msg, ok := q.next()
+			if !ok {
+				break
+			}
+			send(msg)
+		}
+
+		select {
+		case <-q.wake:
+		case <-q.done:
+			return
+		}
+	}
+}
+
+// close stops run; messages still waiting are discarded
+func (q *updateQueue) close() {
+	q.closeOnce.Do(func() { close(q.done) })
+}
+
+// stepEventIndex returns the step of a step event
+func stepEventIndex(msg tea.Msg) (int, bool) {
+	switch m := msg.(type) {
+	case StepStartMsg:
+		return m.StepIndex, true
+	case StepCompleteMsg:
+		return m.StepIndex, true
+	case StepFailMsg:
+		return m.StepIndex, true
+	}
+	return 0, false
+}
+
+// mergeOutput joins two chunks so the output pane shows the same lines as
+// it would for the chunks one after the other
+func mergeOutput(first, second OutputChunkMsg) OutputChunkMsg {
+	firstText := strings.TrimRight(first.Text, "\r\n")
+	switch {
+	case firstText == "":
+		first.Text = second.Text
+	case strings.TrimRight(second.Text, "\r\n") != "":
+		first.Text = firstText + "\n" + second.Text
+	}
+	return first
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// drainQueue returns the messages waiting in a queue
+func drainQueue(q *updateQueue) []tea.Msg {
+	var msgs []tea.Msg
+	for {
+		msg, ok := q.next()
+		if !ok {
+			return msgs
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// TestUpdateQueueCoalescesOutput tests that consecutive output from one
+// source is merged into a chunk that renders the same lines
+func TestUpdateQueueCoalescesOutput(t *testing.T) {
+	q := newUpdateQueue(updateQueueLimit)
+	q.push(OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: "line 1\n"})
+	q.push(OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: "line 2"})
+	q.push(OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: ""})
+	q.push(OutputChunkMsg{Source: "task-1", Stream: "stderr", Text: "warning"})
+	q.push(OutputChunkMsg{Source: "task-2", Stream: "stderr", Text: "other"})
+
+	want := []tea.Msg{
+		OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: "line 1\nline 2"},
+		OutputChunkMsg{Source: "task-1", Stream: "stderr", Text: "warning"},
+		OutputChunkMsg{Source: "task-2", Stream: "stderr", Text: "other"},
+	}
+	if got := drainQueue(q); !reflect.DeepEqual(got, want) {
+		t.Errorf("queued = %#v, want %#v", got, want)
+	}
+}
+
+// TestUpdateQueueKeepsLatestStepEvent tests that a step's newer event
+// replaces its waiting older one
+func TestUpdateQueueKeepsLatestStepEvent(t *testing.T) {
+	q := newUpdateQueue(updateQueueLimit)
+	q.push(StepStartMsg{StepIndex: 1, StepName: "spec"})
+	q.push(OutputChunkMsg{Source: "step-1", Stream: "model", Text: "drafting"})
+	q.push(StepCompleteMsg{StepIndex: 1, StepName: "spec", TotalCost: 0.1})
+	q.push(StepStartMsg{StepIndex: 2, StepName: "plan"})
+
+	want := []tea.Msg{
+		OutputChunkMsg{Source: "step-1", Stream: "model", Text: "drafting"},
+		StepCompleteMsg{StepIndex: 1, StepName: "spec", TotalCost: 0.1},
+		StepStartMsg{StepIndex: 2, StepName: "plan"},
+	}
+	if got := drainQueue(q); !reflect.DeepEqual(got, want) {
+		t.Errorf("queued = %#v, want %#v", got, want)
+	}
+}
+
+// TestUpdateQueueDropsOutputFirst tests that a full queue drops the oldest
+// output, keeps every state event, and reports the drop
+func TestUpdateQueueDropsOutputFirst(t *testing.T) {
+	q := newUpdateQueue(3)
+	q.push(OutputChunkMsg{Source: "task-1", Text: "a"})
+	q.push(StepStartMsg{StepIndex: 1})
+	q.push(OutputChunkMsg{Source: "task-2", Text: "b"})
+	q.push(StepStartMsg{StepIndex: 2})
+	q.push(StepStartMsg{StepIndex: 3})
+	q.push(WorkflowCompleteMsg{Success: true})
+
+	got := drainQueue(q)
+	if len(got) != 5 {
+		t.Fatalf("queued %d messages, want a notice and 4 state events: %#v", len(got), got)
+	}
+	notice, ok := got[0].(OutputChunkMsg)
+	if !ok || notice.Stream != outputStreamEvent || !strings.Contains(notice.Text, "2 output updates skipped") {
+		t.Errorf("first message = %#v, want a notice for 2 skipped updates", got[0])
+	}
+	want := []tea.Msg{StepStartMsg{StepIndex: 1}, StepStartMsg{StepIndex: 2}, StepStartMsg{StepIndex: 3}, WorkflowCompleteMsg{Success: true}}
+	if !reflect.DeepEqual(got[1:], want) {
+		t.Errorf("state events = %#v, want %#v", got[1:], want)
+	}
+}
+
+// TestUpdateQueueDoesNotBlock tests that pushing never waits for a TUI
+// that is not reading, and that the backlog is delivered in order once it
+// catches up
+func TestUpdateQueueDoesNotBlock(t *testing.T) {
+	q := newUpdateQueue(updateQueueLimit)
+	defer q.close()
+
+	release := make(chan struct{})
+	delivered := make(chan tea.Msg, 10)
+	go q.run(func(msg tea.Msg) {
+		<-release
+		delivered <- msg
+	})
+
+	pushed := make(chan struct{})
+	go func() {
+		q.push(StepStartMsg{StepIndex: 1})
+		for i := 0; i < 1000; i++ {
+			q.push(OutputChunkMsg{Source: "task-1", Stream: "stdout", Text: "line"})
+		}
+		q.push(StepCompleteMsg{StepIndex: 1})
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("push blocked on a TUI that is not reading")
+	}
+
+	close(release)
+	var got []tea.Msg
+	timeout := time.After(5 * time.Second)
+	for len(got) == 0 || !isStepComplete(got[len(got)-1]) {
+		select {
+		case msg := <-delivered:
+			got = append(got, msg)
+		case <-timeout:
+			t.Fatalf("delivered %#v, want the backlog to drain", got)
+		}
+	}
+
+	// The first event may already be in flight; the rest of the output
+	// arrives merged into a single chunk
+	if len(got) > 3 {
+		t.Errorf("delivered %d messages, want the output coalesced", len(got))
+	}
+}
+
+func isStepComplete(msg tea.Msg) bool {
+	_, ok := msg.(StepCompleteMsg)
+	return ok
+}
+
+// TestAdapterNotifyBeforeStart tests that notifications before Start are
+// ignored
+func TestAdapterNotifyBeforeStart(t *testing.T) {
+	adapter := NewAdapter("Test goal", "default")
+	adapter.NotifyStepStart(1, "spec")
+	adapter.NotifyOutput("step-1", "model", "text")
+	adapter.Stop()
+}
@@ -10,10 +10,27 @@ import (
 	"github.com/felixgeelhaar/specular/internal/auto"
 )
 
-// renderMain renders the main view showing progress and status
+// renderMain renders the main view showing progress and status, with the
+// output pane filling the remaining height
 func (m Model) renderMain() string {
 	var b strings.Builder
 
+	b.WriteString(m.renderMainHeader())
+	if pane := m.renderOutputPane(); pane != "" {
+		b.WriteString(pane)
+		b.WriteString("\n")
+	}
+
+	// Help text
+	b.WriteString(m.renderHelpLine())
+
+	return b.String()
+}
+
+// renderMainHeader renders the part of the main view above the output pane
+func (m Model) renderMainHeader() string {
+	var b strings.Builder
+
 	// Title
 	title := m.styles.Title.Render("🤖 Specular Auto Mode")
 	b.WriteString(title)
@@ -21,14 +38,14 @@ func (m Model) renderMain() string {
 
 	// Goal
 	goalLabel := m.styles.Muted.Render("Goal: ")
-	goalText := m.styles.Subtitle.Render(m.goal)
+	goalText := m.styles.Subtitle.Render(m.fitText(m.goal, lipgloss.Width(goalLabel)))
 	b.WriteString(goalLabel + goalText)
 	b.WriteString("\n\n")
 
 	// Profile
 	if m.profile != "" {
 		profileLabel := m.styles.Muted.Render("Profile: ")
-		profileText := m.styles.Subtitle.Render(m.profile)
+		profileText := m.styles.Subtitle.Render(m.fitText(m.profile, lipgloss.Width(profileLabel)))
 		b.WriteString(profileLabel + profileText)
 		b.WriteString("\n\n")
 	}
@@ -41,26 +58,55 @@ func (m Model) renderMain() string {
 	// Current step
 	if m.currentStepName != "" {
 		currentLabel := m.styles.Muted.Render("Current Step: ")
-		currentText := m.styles.Status.Render(m.currentStepName)
+		currentText := m.styles.Status.Render(m.fitText(m.currentStepName, lipgloss.Width(currentLabel)))
 		b.WriteString(currentLabel + currentText)
 		b.WriteString("\n\n")
 	}
 
 	// Error display
 	if m.lastError != "" {
-		errorBox := m.styles.Border.
-			BorderForeground(lipgloss.Color("196")). // Red border
-			Render(m.styles.Error.Render("❌ Error: ") + m.lastError)
+		errorStyle := m.styles.Border.
+			BorderForeground(lipgloss.Color("196")) // Red border
+		if m.width > 0 {
+			// Wrap long errors inside the border instead of overflowing it
+			errorStyle = errorStyle.Width(max(m.width-2, 10))
+		}
+		errorBox := errorStyle.Render(m.styles.Error.Render("❌ Error: ") + m.lastError)
 		b.WriteString(errorBox)
 		b.WriteString("\n\n")
 	}
 
-	// Help text
-	b.WriteString(m.renderHelpLine())
-
 	return b.String()
 }
 
+// outputPaneHeight returns the number of output lines that fit below the
+// main view header, leaving room for the pane title and the help line. The
+// header ends with an empty line, which the pane title takes.
+func (m Model) outputPaneHeight() int {
+	return m.height - lipgloss.Height(m.renderMainHeader()) - lipgloss.Height(m.renderHelpLine())
+}
+
+// renderOutputPane renders the output pane, a one-line notice when the
+// terminal is too small for it, or nothing when there is no room at all
+func (m Model) renderOutputPane() string {
+	height := m.outputPaneHeight()
+	if height < 0 {
+		return ""
+	}
+
+	if height < outputPaneMinHeight || m.width < outputPaneMinWidth {
+		notice := fmt.Sprintf("Output hidden, terminal too small (%d lines)", len(m.output.lines))
+		return m.styles.Muted.Render(notice)
+	}
+
+	title := m.styles.Muted.Render("── Output ")
+	if status := m.output.status(height); status != "" {
+		title += m.styles.Muted.Render("(" + status + ")")
+	}
+
+	return title + "\n" + m.output.view(m.styles, m.width, height)
+}
+
 // renderProgressBox renders the progress statistics box
 func (m Model) renderProgressBox() string {
 	var b strings.Builder
@@ -93,7 +139,7 @@ func (m Model) renderProgressBar() string {
 		return m.styles.Muted.Render("No steps yet")
 	}
 
-	barWidth := 40
+	barWidth := m.progressBarWidth()
 	filled := int(float64(m.completedSteps) / float64(m.totalSteps) * float64(barWidth))
 
 	var bar strings.Builder
@@ -113,6 +159,17 @@ func (m Model) renderProgressBar() string {
 	return m.styles.Status.Render(bar.String()) + m.styles.Muted.Render(progressText)
 }
 
+// progressBarWidth returns the progress bar width that fits the terminal
+func (m Model) progressBarWidth() int {
+	const maxWidth = 40
+	if m.width == 0 {
+		return maxWidth
+	}
+	// Leave room for the box border and padding, the brackets, and the
+	// " 10/10 (100%)" count
+	return min(max(m.width-24, 10), maxWidth)
+}
+
 // renderStats renders execution statistics
 func (m Model) renderStats() string {
 	elapsed := m.elapsed()
@@ -134,14 +191,12 @@ func (m Model) renderStats() string {
 	return strings.Join(stats, "\n")
 }
 
-// renderStepList renders the step list view
+// renderStepList renders the step list view. Clicking a step shows its
+// details, and a list taller than the terminal scrolls.
 func (m Model) renderStepList() string {
 	var b strings.Builder
 
-	// Title
-	title := m.styles.Title.Render("📋 Step List")
-	b.WriteString(title)
-	b.WriteString("\n\n")
+	b.WriteString(m.renderStepListHeader())
 
 	// Check if action plan is available
 	if m.actionPlan == nil || len(m.actionPlan.Steps) == 0 {
@@ -151,19 +206,118 @@ func (m Model) renderStepList() string {
 		return b.String()
 	}
 
-	// Render each step
-	for i, step := range m.actionPlan.Steps {
-		stepLine := m.renderStepLine(i, &step)
-		b.WriteString(stepLine)
-		b.WriteString("\n")
+	// Render the visible part of the steps
+	lines := strings.Split(strings.Join(m.stepListEntries(), "\n"), "\n")
+	if height := m.stepListHeight(); height > 0 && len(lines) > height {
+		offset := min(m.stepOffset, len(lines)-height)
+		lines = lines[offset : offset+height]
 	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n\n")
 
-	b.WriteString("\n")
 	b.WriteString(m.renderHelpLine())
 
 	return b.String()
 }
 
+// renderStepListHeader renders the step list title. The header ends with an
+// empty line, which the first step takes.
+func (m Model) renderStepListHeader() string {
+	return m.styles.Title.Render("📋 Step List") + "\n\n"
+}
+
+// stepListEntries renders each step, with its details when expanded
+func (m Model) stepListEntries() []string {
+	if m.actionPlan == nil {
+		return nil
+	}
+
+	entries := make([]string, len(m.actionPlan.Steps))
+	for i := range m.actionPlan.Steps {
+		step := &m.actionPlan.Steps[i]
+		entries[i] = m.renderStepLine(i, step)
+		if i == m.expandedStep {
+			entries[i] += "\n" + m.renderStepDetails(step)
+		}
+	}
+	return entries
+}
+
+// stepListHeight returns the number of step list lines that fit between
+// the header and the help line, or 0 when the terminal size is unknown
+func (m Model) stepListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	return max(m.height-lipgloss.Height(m.renderStepListHeader())-lipgloss.Height(m.renderHelpLine()), 1)
+}
+
+// maxStepOffset returns the largest scroll offset of the step list
+func (m Model) maxStepOffset() int {
+	height := m.stepListHeight()
+	if height == 0 {
+		return 0
+	}
+	lines := lipgloss.Height(strings.Join(m.stepListEntries(), "\n"))
+	return max(lines-height, 0)
+}
+
+// stepAt returns the index of the step rendered at screen row y in the
+// step list, or -1 when the row shows no step
+func (m Model) stepAt(y int) int {
+	line := y - (lipgloss.Height(m.renderStepListHeader()) - 1)
+	if height := m.stepListHeight(); line < 0 || (height > 0 && line >= height) {
+		return -1
+	}
+	line += min(m.stepOffset, m.maxStepOffset())
+
+	for i, entry := range m.stepListEntries() {
+		height := lipgloss.Height(entry)
+		if line < height {
+			return i
+		}
+		line -= height
+	}
+	return -1
+}
+
+// renderStepDetails renders the details of an expanded step, indented
+// below the step and wrapped to the terminal width
+func (m Model) renderStepDetails(step *auto.ActionStep) string {
+	status := step.Status
+	if status == "" {
+		status = auto.StepStatusPending
+	}
+	details := []string{fmt.Sprintf("Status: %s", status)}
+	if step.Reason != "" {
+		details = append(details, "Reason: "+step.Reason)
+	}
+	if len(step.Dependencies) > 0 {
+		details = append(details, "Depends on: "+strings.Join(step.Dependencies, ", "))
+	}
+	if step.RequiresApproval {
+		details = append(details, "Requires approval")
+	}
+	if step.StartedAt != nil {
+		details = append(details, "Started: "+step.StartedAt.Format("15:04:05"))
+	}
+	if step.StartedAt != nil && step.CompletedAt != nil {
+		details = append(details, "Duration: "+formatDuration(step.CompletedAt.Sub(*step.StartedAt)))
+	}
+	if step.EstimatedCostUSD > 0 {
+		details = append(details, fmt.Sprintf("Estimated cost: $%.4f", step.EstimatedCostUSD))
+	}
+	if step.Error != "" {
+		details = append(details, m.styles.Error.Bold(false).Render("Error: "+step.Error))
+	}
+
+	style := m.styles.Muted.PaddingLeft(4)
+	if m.width > 8 {
+		style = style.Width(m.width)
+	}
+	return style.Render(strings.Join(details, "\n"))
+}
+
 // renderStepLine renders a single step in the list
 func (m Model) renderStepLine(index int, step *auto.ActionStep) string {
 	var b strings.Builder
@@ -196,8 +350,12 @@ func (m Model) renderStepLine(index int, step *auto.ActionStep) string {
 	b.WriteString(icon)
 	b.WriteString(" ")
 
-	// Step info
+	// Step info, shortened to fit the terminal
+	typeText := m.styles.Muted.Render(fmt.Sprintf(" (%s)", step.Type))
 	stepText := fmt.Sprintf("%s - %s", step.ID, step.Description)
+	if m.width > 0 {
+		stepText = truncateText(stepText, m.width-lipgloss.Width(icon)-1-lipgloss.Width(typeText))
+	}
 	if index == m.currentStep {
 		stepText = m.styles.Status.Bold(true).Render(stepText)
 	} else {
@@ -207,7 +365,6 @@ func (m Model) renderStepLine(index int, step *auto.ActionStep) string {
 	b.WriteString(stepText)
 
 	// Step type
-	typeText := m.styles.Muted.Render(fmt.Sprintf(" (%s)", step.Type))
 	b.WriteString(typeText)
 
 	return b.String()
@@ -257,6 +414,12 @@ func (m Model) renderHelp() string {
 		{"?", "Toggle help"},
 		{"s", "Toggle step list"},
 		{"v", "Toggle verbose mode"},
+		{"PgUp/PgDn", "Scroll output"},
+		{"f", "Toggle output follow mode"},
+		{"/", "Search output"},
+		{"n/N", "Next/previous match"},
+		{"Click", "Show step details in the step list"},
+		{"Wheel", "Scroll output or step list"},
 		{"q", "Quit"},
 		{"Ctrl+C", "Force quit"},
 		{"Esc", "Return to main view"},
@@ -307,6 +470,8 @@ func (m Model) renderHelpLine() string {
 		m.styles.Key.Render("?") + " help",
 		m.styles.Key.Render("s") + " steps",
 		m.styles.Key.Render("v") + " verbose",
+		m.styles.Key.Render("f") + " follow",
+		m.styles.Key.Render("/") + " search",
 		m.styles.Key.Render("q") + " quit",
 	}
 
@@ -314,6 +479,33 @@ func (m Model) renderHelpLine() string {
 	return m.styles.Help.Render(helpLine)
 }
 
+// fitText flattens s to one line and shortens it with an ellipsis so it
+// fits the terminal after used columns
+func (m Model) fitText(s string, used int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if m.width == 0 {
+		return s
+	}
+	return truncateText(s, max(m.width-used, 1))
+}
+
+// truncateText shortens s to at most width cells, ending in an ellipsis
+// when anything was cut
+func truncateText(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
 // formatDuration formats a duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -61,6 +61,11 @@ func (pd *PathDefaults) RouterFile() string {
 	return filepath.Join(pd.SpecularDir, "router.yaml")
 }
 
+// EvalFile returns the default eval scenario file path
+func (pd *PathDefaults) EvalFile() string {
+	return filepath.Join(pd.SpecularDir, "eval.yaml")
+}
+
 // CheckpointDir returns the default checkpoint directory
 func (pd *PathDefaults) CheckpointDir() string {
 	return filepath.Join(pd.SpecularDir, "checkpoints")
@@ -108,6 +108,16 @@ func TestPathDefaults_CacheDir(t *testing.T) {
 	}
 }
 
+func TestPathDefaults_EvalFile(t *testing.T) {
+	defaults := NewPathDefaults()
+	evalFile := defaults.EvalFile()
+
+	expected := filepath.Join(".specular", "eval.yaml")
+	if evalFile != expected {
+		t.Errorf("EvalFile() = %s, want %s", evalFile, expected)
+	}
+}
+
 func TestPathDefaults_ValidateSpecularSetup_Missing(t *testing.T) {
 	// Create a temporary directory without .specular
 	tmpDir := t.TempDir()
@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignorePattern is one .gitignore pattern
+type ignorePattern struct {
+	pattern  string // Slash-separated pattern without the leading ! or trailing /
+	negate   bool   // Pattern starts with ! and re-includes matching paths
+	dirOnly  bool   // Pattern ends with / and only matches directories
+	anchored bool   // Pattern contains a / and matches from the root only
+}
+
+// Ignorer decides which paths under a root are not watched. It combines
+// .gitignore patterns with plain glob patterns matched against file names.
+type Ignorer struct {
+	patterns []ignorePattern
+	globs    []string
+}
+
+// NewIgnorer returns an Ignorer for globs and the .gitignore file at the
+// root of the project, if there is one
+func NewIgnorer(root string, globs []string) (*Ignorer, error) {
+	ignorer := &Ignorer{globs: globs}
+	for _, glob := range globs {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", glob, err)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(root, ".gitignore")) // #nosec G304 -- The project's own .gitignore
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ignorer, nil
+		}
+		return nil, fmt.Errorf("read .gitignore: %w", err)
+	}
+	defer file.Close() //nolint:errcheck // Deferred close is best effort
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if pattern, ok := parseIgnorePattern(scanner.Text()); ok {
+			ignorer.patterns = append(ignorer.patterns, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read .gitignore: %w", err)
+	}
+
+	return ignorer, nil
+}
+
+// parseIgnorePattern parses a .gitignore line. Blank lines and comments
+// return false.
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	line = strings.TrimPrefix(line, "**/")
+	if line == "" {
+		return ignorePattern{}, false
+	}
+
+	p.pattern = line
+	return p, true
+}
+
+// Ignored reports whether relPath, a slash-separated path relative to the
+// root, is ignored. The last matching .gitignore pattern wins, as in git.
+func (i *Ignorer) Ignored(relPath string, isDir bool) bool {
+	name := path.Base(relPath)
+	for _, glob := range i.globs {
+		if matched, _ := filepath.Match(glob, name); matched { //nolint:errcheck // Patterns are validated in NewIgnorer
+			return true
+		}
+	}
+
+	ignored := false
+	for _, p := range i.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.matches(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the pattern matches relPath
+func (p ignorePattern) matches(relPath string) bool {
+	if strings.HasSuffix(p.pattern, "/**") {
+		prefix := strings.TrimSuffix(p.pattern, "/**")
+		return strings.HasPrefix(relPath, prefix+"/")
+	}
+	if p.anchored {
+		matched, _ := path.Match(p.pattern, relPath) //nolint:errcheck // A malformed pattern never matches
+		return matched
+	}
+	matched, _ := path.Match(p.pattern, path.Base(relPath)) //nolint:errcheck // A malformed pattern never matches
+	return matched
+}
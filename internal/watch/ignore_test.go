@@ -0,0 +1,59 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnorer(t *testing.T) {
+	root := t.TempDir()
+	gitignore := `# build output
+/dist
+build/
+*.log
+!keep.log
+coverage/**
+docs/generated/*.md
+`
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(gitignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ignorer, err := NewIgnorer(root, []string{"*.test.js"})
+	if err != nil {
+		t.Fatalf("NewIgnorer: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"dist", true, true},
+		{"src/dist", true, false}, // Anchored to the root
+		{"build", true, true},
+		{"src/build", true, true},
+		{"build", false, false}, // Directory-only pattern
+		{"server.log", false, true},
+		{"logs/server.log", false, true},
+		{"keep.log", false, false}, // Negated
+		{"coverage/lcov.info", false, true},
+		{"docs/generated/api.md", false, true},
+		{"docs/guide.md", false, false},
+		{"src/app.test.js", false, true}, // Ignore glob
+		{"src/app.js", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := ignorer.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestNewIgnorer_Errors(t *testing.T) {
+	if _, err := NewIgnorer(t.TempDir(), []string{"[bad"}); err == nil {
+		t.Error("expected error for malformed ignore glob")
+	}
+}
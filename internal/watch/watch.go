@@ -0,0 +1,202 @@
+// Package watch re-runs a callback when files under a project root change.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the watcher waits after the last change
+// before running the callback
+const DefaultDebounce = 300 * time.Millisecond
+
+// alwaysSkipped are directories that are never watched: version control
+// metadata, dependencies, and specular's own generated state
+var alwaysSkipped = []string{".git", "node_modules", ".specular/cache", ".specular/checkpoints", ".specular/runs"}
+
+// Options configures a Watcher
+type Options struct {
+	Root        string        // Project root to watch recursively
+	IgnoreGlobs []string      // File name patterns to ignore, in addition to .gitignore
+	IgnorePaths []string      // Files the callback writes itself, such as reports
+	Debounce    time.Duration // Quiet period before a run (default DefaultDebounce)
+}
+
+// Watcher watches a project root and reports batches of changed files
+type Watcher struct {
+	root        string
+	debounce    time.Duration
+	ignorer     *Ignorer
+	ignorePaths map[string]bool
+	fs          *fsnotify.Watcher
+}
+
+// New starts watching every directory under opts.Root that is not ignored
+func New(opts Options) (*Watcher, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, fmt.Errorf("resolve watch root: %w", err)
+	}
+
+	ignorer, err := NewIgnorer(root, opts.IgnoreGlobs)
+	if err != nil {
+		return nil, err
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create file watcher: %w", err)
+	}
+
+	w := &Watcher{
+		root:        root,
+		debounce:    opts.Debounce,
+		ignorer:     ignorer,
+		ignorePaths: make(map[string]bool),
+		fs:          fsWatcher,
+	}
+	if w.debounce <= 0 {
+		w.debounce = DefaultDebounce
+	}
+	for _, p := range opts.IgnorePaths {
+		if abs, absErr := filepath.Abs(p); absErr == nil {
+			w.ignorePaths[abs] = true
+		}
+	}
+
+	if err := w.addTree(root); err != nil {
+		fsWatcher.Close() //nolint:errcheck // Already returning an error
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// Run calls onChange with the changed files, relative to the root, each
+// time changes stop for the debounce period. Changes made while onChange
+// runs are coalesced into one follow-up run, so files the callback writes
+// itself must be ignored with IgnorePaths or IgnoreGlobs. Run returns nil
+// when ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context, onChange func(ctx context.Context, changed []string)) error {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			if rel, changed := w.handleEvent(event); changed {
+				pending[rel] = true
+				timer.Reset(w.debounce)
+			}
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch %s: %w", w.root, err)
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			changed := make([]string, 0, len(pending))
+			for rel := range pending {
+				changed = append(changed, rel)
+			}
+			sort.Strings(changed)
+			pending = make(map[string]bool)
+
+			onChange(ctx, changed)
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// handleEvent starts watching new directories and returns the path of a
+// relevant change relative to the root
+func (w *Watcher) handleEvent(event fsnotify.Event) (string, bool) {
+	if event.Op == fsnotify.Chmod || w.ignorePaths[event.Name] {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(w.root, event.Name)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+
+	isDir := false
+	if info, statErr := os.Stat(event.Name); statErr == nil && info.IsDir() {
+		isDir = true
+	}
+	if w.skipped(rel, isDir) {
+		return "", false
+	}
+
+	if isDir && event.Has(fsnotify.Create) {
+		if err := w.addTree(event.Name); err != nil {
+			return "", false
+		}
+	}
+	return rel, true
+}
+
+// addTree watches dir and every directory under it that is not ignored
+func (w *Watcher) addTree(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Directories removed while walking are not an error
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(w.root, path)
+		if relErr != nil {
+			return relErr
+		}
+		if rel != "." && w.skipped(filepath.ToSlash(rel), true) {
+			return filepath.SkipDir
+		}
+
+		if addErr := w.fs.Add(path); addErr != nil {
+			return fmt.Errorf("watch %s: %w", path, addErr)
+		}
+		return nil
+	})
+}
+
+// skipped reports whether a path relative to the root is not watched
+func (w *Watcher) skipped(rel string, isDir bool) bool {
+	for _, dir := range alwaysSkipped {
+		if rel == dir || strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return w.ignorer.Ignored(rel, isDir)
+}
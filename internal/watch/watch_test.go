@@ -0,0 +1,113 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "node_modules/lib", ".specular/cache"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := filepath.Join(root, "drift.sarif")
+
+	w, err := New(Options{
+		Root:        root,
+		IgnoreGlobs: []string{"*.tmp"},
+		IgnorePaths: []string{report},
+		Debounce:    50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer w.Close() //nolint:errcheck // Test cleanup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan []string, 10)
+	done := make(chan error, 1)
+	first := true
+	go func() {
+		done <- w.Run(ctx, func(ctx context.Context, changed []string) {
+			runs <- changed
+			// The report written by the callback is ignored
+			writeTestFile(t, report)
+			if first {
+				// Files saved during a run are coalesced into one follow-up run
+				first = false
+				writeTestFile(t, filepath.Join(root, "src", "c.go"))
+				writeTestFile(t, filepath.Join(root, "src", "d.go"))
+			}
+		})
+	}()
+
+	// Ignored changes first, then two quick writes that are debounced together
+	writeTestFile(t, filepath.Join(root, "node_modules", "lib", "index.js"))
+	writeTestFile(t, filepath.Join(root, ".specular", "cache", "manifest.json"))
+	writeTestFile(t, filepath.Join(root, "scratch.tmp"))
+	writeTestFile(t, report)
+	writeTestFile(t, filepath.Join(root, "src", "a.go"))
+	writeTestFile(t, filepath.Join(root, "src", "b.go"))
+
+	select {
+	case changed := <-runs:
+		if strings.Join(changed, ",") != "src/a.go,src/b.go" {
+			t.Errorf("changed = %v, want [src/a.go src/b.go]", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no run after changes")
+	}
+
+	select {
+	case changed := <-runs:
+		if strings.Join(changed, ",") != "src/c.go,src/d.go" {
+			t.Errorf("follow-up changed = %v, want [src/c.go src/d.go]", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no follow-up run after changes during a run")
+	}
+
+	// New directories are watched too
+	if err := os.MkdirAll(filepath.Join(root, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	writeTestFile(t, filepath.Join(root, "pkg", "e.go"))
+
+	// The new directory itself may trigger a run of its own first
+	timeout := time.After(5 * time.Second)
+	for found := false; !found; {
+		select {
+		case changed := <-runs:
+			found = slices.Contains(changed, "pkg/e.go")
+		case <-timeout:
+			t.Fatal("no run after change in new directory")
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v after cancel", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(time.Now().String()), 0644); err != nil {
+		t.Errorf("write %s: %v", path, err)
+	}
+}
@@ -1,9 +1,15 @@
 package types
 
 import (
+	"cmp"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // FeatureID represents a unique identifier for a feature.
@@ -57,6 +63,54 @@ func (f FeatureID) Validate() error {
 	return nil
 }
 
+// NewFeatureIDNormalized creates a FeatureID from an external identifier, such
+// as a Jira key like "PROJ-123", by normalizing it before validation. The value
+// is lowercased, accents are stripped, and every run of other characters
+// (spaces, underscores, punctuation, non-Latin letters) becomes a single hyphen.
+// Leading and trailing hyphens are trimmed, and an ID that would start with a
+// digit is prefixed with "feat-". It also reports whether the value changed.
+func NewFeatureIDNormalized(value string) (FeatureID, bool, error) {
+	normalized := normalizeFeatureID(value)
+	if normalized == "" {
+		return "", false, fmt.Errorf("feature ID %q has no letters or digits to normalize", value)
+	}
+
+	id, err := NewFeatureID(normalized)
+	if err != nil {
+		return "", false, err
+	}
+	return id, normalized != value, nil
+}
+
+// normalizeFeatureID slugifies a value into the feature ID format
+func normalizeFeatureID(value string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range norm.NFD.String(strings.ToLower(value)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Drop combining accents left by decomposition (é -> e)
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		default:
+			pendingHyphen = true
+		}
+	}
+
+	s := b.String()
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "feat-" + s
+	}
+	if len(s) > maxFeatureIDLength {
+		s = strings.TrimRight(s[:maxFeatureIDLength], "-")
+	}
+	return s
+}
+
 // String returns the string representation
 func (f FeatureID) String() string {
 	return string(f)
@@ -186,3 +240,218 @@ func priorityRank(p Priority) int {
 		return 0
 	}
 }
+
+// Version represents a semantic version (MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]).
+// This is a value object that enforces the Semantic Versioning 2.0.0 format.
+type Version string
+
+// versionPattern is the Semantic Versioning 2.0.0 grammar: numeric identifiers
+// without leading zeros, and optional dot-separated pre-release and build identifiers
+var versionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// NewVersion creates a new Version value object with validation
+func NewVersion(value string) (Version, error) {
+	v := Version(value)
+	if err := v.Validate(); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// Validate checks if the version is a valid semantic version
+func (v Version) Validate() error {
+	s := string(v)
+
+	if s == "" {
+		return fmt.Errorf("version cannot be empty")
+	}
+
+	if !versionPattern.MatchString(s) {
+		return fmt.Errorf("version %q must be a semantic version like 1.2.3 (MAJOR.MINOR.PATCH)", s)
+	}
+
+	return nil
+}
+
+// String returns the string representation
+func (v Version) String() string {
+	return string(v)
+}
+
+// Major returns the major version number, or 0 if the version is invalid
+func (v Version) Major() int {
+	major, _, _, _ := v.parts()
+	return major
+}
+
+// IsNewerThan checks if this version has higher precedence than another.
+// Build metadata is ignored, and a pre-release is older than its release.
+func (v Version) IsNewerThan(other Version) bool {
+	return compareVersions(v, other) > 0
+}
+
+// IsCompatibleWith checks if this version is compatible with another, that
+// is, both are valid and share the same major version
+func (v Version) IsCompatibleWith(other Version) bool {
+	if v.Validate() != nil || other.Validate() != nil {
+		return false
+	}
+	return v.Major() == other.Major()
+}
+
+// parts splits a valid version into its numeric components and pre-release
+// identifiers
+func (v Version) parts() (major, minor, patch int, prerelease []string) {
+	m := versionPattern.FindStringSubmatch(string(v))
+	if m == nil {
+		return 0, 0, 0, nil
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	patch, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		prerelease = strings.Split(m[4], ".")
+	}
+	return major, minor, patch, prerelease
+}
+
+// compareVersions compares two versions by semantic version precedence,
+// returning -1, 0, or 1
+func compareVersions(a, b Version) int {
+	aMajor, aMinor, aPatch, aPre := a.parts()
+	bMajor, bMinor, bPatch, bPre := b.parts()
+
+	for _, c := range [][2]int{{aMajor, bMajor}, {aMinor, bMinor}, {aPatch, bPatch}} {
+		if c[0] != c[1] {
+			return cmp.Compare(c[0], c[1])
+		}
+	}
+
+	// A release has higher precedence than its pre-releases
+	switch {
+	case len(aPre) == 0 && len(bPre) == 0:
+		return 0
+	case len(aPre) == 0:
+		return 1
+	case len(bPre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(aPre) && i < len(bPre); i++ {
+		if c := comparePrereleaseIdentifiers(aPre[i], bPre[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(aPre), len(bPre))
+}
+
+// comparePrereleaseIdentifiers compares pre-release identifiers: numeric
+// identifiers numerically and below alphanumeric ones, which compare in ASCII order
+func comparePrereleaseIdentifiers(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Effort represents an estimated effort in story points.
+// This is a value object that enforces points on the effort scale.
+type Effort int
+
+// FibonacciEffortScale is the story point scale efforts are estimated on
+var FibonacciEffortScale = []Effort{1, 2, 3, 5, 8, 13, 21}
+
+// NewEffort creates a new Effort value object with validation
+func NewEffort(points int) (Effort, error) {
+	e := Effort(points)
+	if err := e.Validate(); err != nil {
+		return 0, err
+	}
+	return e, nil
+}
+
+// Validate checks if the effort is a point on the effort scale
+func (e Effort) Validate() error {
+	if e <= 0 {
+		return fmt.Errorf("effort must be positive, got %d", int(e))
+	}
+
+	if !slices.Contains(FibonacciEffortScale, e) {
+		return fmt.Errorf("effort %d is not on the scale %v", int(e), FibonacciEffortScale)
+	}
+
+	return nil
+}
+
+// String returns the string representation
+func (e Effort) String() string {
+	return fmt.Sprintf("%d pts", int(e))
+}
+
+// Points returns the number of story points
+func (e Effort) Points() int {
+	return int(e)
+}
+
+// EffortForComplexity rounds a complexity score up to the nearest point on the
+// effort scale. Scores above the scale map to its largest point and scores
+// below 1 map to its smallest.
+func EffortForComplexity(complexity int) Effort {
+	for _, point := range FibonacciEffortScale {
+		if complexity <= int(point) {
+			return point
+		}
+	}
+	return FibonacciEffortScale[len(FibonacciEffortScale)-1]
+}
+
+// SumEffort totals efforts, such as those of a plan's tasks. The total is not
+// itself a point on the scale.
+func SumEffort(efforts ...Effort) Effort {
+	var total Effort
+	for _, e := range efforts {
+		total += e
+	}
+	return total
+}
+
+// TShirtSize is a coarse effort bucket
+type TShirtSize string
+
+// T-shirt sizes, smallest to largest
+const (
+	TShirtXS  TShirtSize = "XS"  // 1-2 points
+	TShirtS   TShirtSize = "S"   // 3 points
+	TShirtM   TShirtSize = "M"   // 4-5 points
+	TShirtL   TShirtSize = "L"   // 6-8 points
+	TShirtXL  TShirtSize = "XL"  // 9-13 points
+	TShirtXXL TShirtSize = "XXL" // More than 13 points
+)
+
+// TShirtSize buckets the effort into a T-shirt size
+func (e Effort) TShirtSize() TShirtSize {
+	switch {
+	case e <= 2:
+		return TShirtXS
+	case e <= 3:
+		return TShirtS
+	case e <= 5:
+		return TShirtM
+	case e <= 8:
+		return TShirtL
+	case e <= 13:
+		return TShirtXL
+	default:
+		return TShirtXXL
+	}
+}
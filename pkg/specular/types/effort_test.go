@@ -0,0 +1,99 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestNewEffort(t *testing.T) {
+	tests := []struct {
+		name    string
+		points  int
+		want    Effort
+		wantErr bool
+	}{
+		{name: "valid 1", points: 1, want: Effort(1)},
+		{name: "valid 5", points: 5, want: Effort(5)},
+		{name: "valid 21", points: 21, want: Effort(21)},
+		{name: "invalid zero", points: 0, wantErr: true},
+		{name: "invalid negative", points: -3, wantErr: true},
+		{name: "invalid off scale", points: 4, wantErr: true},
+		{name: "invalid above scale", points: 34, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEffort(tt.points)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEffort() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("NewEffort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffort_String(t *testing.T) {
+	if got := Effort(8).String(); got != "8 pts" {
+		t.Errorf("Effort.String() = %v, want 8 pts", got)
+	}
+}
+
+func TestEffortForComplexity(t *testing.T) {
+	tests := []struct {
+		complexity int
+		want       Effort
+	}{
+		{0, 1},
+		{1, 1},
+		{2, 2},
+		{3, 3},
+		{4, 5},
+		{5, 5},
+		{6, 8},
+		{9, 13},
+		{10, 13},
+		{100, 21},
+	}
+
+	for _, tt := range tests {
+		if got := EffortForComplexity(tt.complexity); got != tt.want {
+			t.Errorf("EffortForComplexity(%d) = %v, want %v", tt.complexity, got, tt.want)
+		}
+		if err := EffortForComplexity(tt.complexity).Validate(); err != nil {
+			t.Errorf("EffortForComplexity(%d) is not on the scale: %v", tt.complexity, err)
+		}
+	}
+}
+
+func TestSumEffort(t *testing.T) {
+	if got := SumEffort(); got != 0 {
+		t.Errorf("SumEffort() = %v, want 0", got)
+	}
+	if got := SumEffort(1, 3, 8, 13); got != 25 {
+		t.Errorf("SumEffort(1, 3, 8, 13) = %v, want 25", got)
+	}
+}
+
+func TestEffort_TShirtSize(t *testing.T) {
+	tests := []struct {
+		effort Effort
+		want   TShirtSize
+	}{
+		{1, TShirtXS},
+		{2, TShirtXS},
+		{3, TShirtS},
+		{5, TShirtM},
+		{8, TShirtL},
+		{13, TShirtXL},
+		{21, TShirtXXL},
+		{40, TShirtXXL},
+	}
+
+	for _, tt := range tests {
+		if got := tt.effort.TShirtSize(); got != tt.want {
+			t.Errorf("Effort(%d).TShirtSize() = %v, want %v", tt.effort, got, tt.want)
+		}
+	}
+}
@@ -272,3 +272,24 @@ func TestFeatureID_RoundTripThroughString(t *testing.T) {
 		}
 	})
 }
+
+// TestFeatureID_NormalizedIsValidAndStable tests that normalization either fails or
+// produces a valid ID that normalizes to itself
+func TestFeatureID_NormalizedIsValidAndStable(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		value := rapid.String().Draw(t, "value")
+
+		id, _, err := NewFeatureIDNormalized(value)
+		if err != nil {
+			return
+		}
+		if err := id.Validate(); err != nil {
+			t.Fatalf("normalized ID %q from %q is invalid: %v", id, value, err)
+		}
+
+		again, normalized, err := NewFeatureIDNormalized(id.String())
+		if err != nil || again != id || normalized {
+			t.Fatalf("normalizing %q again = %q, %v, %v", id, again, normalized, err)
+		}
+	})
+}
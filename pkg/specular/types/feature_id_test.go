@@ -168,3 +168,58 @@ func TestFeatureID_Equals(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFeatureIDNormalized(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		want           FeatureID
+		wantNormalized bool
+		wantErr        bool
+	}{
+		{name: "already valid", value: "user-auth", want: "user-auth"},
+		{name: "jira key", value: "PROJ-123", want: "proj-123", wantNormalized: true},
+		{name: "spaces and underscores", value: "User Auth_Flow", want: "user-auth-flow", wantNormalized: true},
+		{name: "consecutive separators", value: "user -- _auth", want: "user-auth", wantNormalized: true},
+		{name: "leading and trailing hyphens", value: "-user-auth-", want: "user-auth", wantNormalized: true},
+		{name: "surrounding whitespace", value: "  login  ", want: "login", wantNormalized: true},
+		{name: "leading digits", value: "123-login", want: "feat-123-login", wantNormalized: true},
+		{name: "only digits", value: "42", want: "feat-42", wantNormalized: true},
+		{name: "punctuation", value: "auth/login.v2", want: "auth-login-v2", wantNormalized: true},
+		{name: "accented letters", value: "Café Menü", want: "cafe-menu", wantNormalized: true},
+		{name: "non-latin letters are separators", value: "search 検索 v2", want: "search-v2", wantNormalized: true},
+		{name: "too long is truncated", value: strings.Repeat("ab-", 50), want: FeatureID(strings.TrimRight(strings.Repeat("ab-", 34)[:100], "-")), wantNormalized: true},
+		{name: "empty", value: "", wantErr: true},
+		{name: "only separators", value: " -_- ", wantErr: true},
+		{name: "only non-latin letters", value: "検索", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, normalized, err := NewFeatureIDNormalized(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFeatureIDNormalized(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewFeatureIDNormalized(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if normalized != tt.wantNormalized {
+				t.Errorf("NewFeatureIDNormalized(%q) normalized = %v, want %v", tt.value, normalized, tt.wantNormalized)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("normalized ID %q is invalid: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestNewFeatureID_StaysStrict(t *testing.T) {
+	for _, value := range []string{"PROJ-123", "user auth", "123-login", "café"} {
+		if _, err := NewFeatureID(value); err == nil {
+			t.Errorf("NewFeatureID(%q) should reject values that need normalization", value)
+		}
+	}
+}
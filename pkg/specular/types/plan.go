@@ -11,8 +11,9 @@ type Task struct {
 	FeatureID    FeatureID `json:"feature_id"`
 	ExpectedHash string    `json:"expected_hash"` // Links to SpecLock feature hash
 	DependsOn    []TaskID  `json:"depends_on"`
-	Skill        string    `json:"skill"`      // go-backend, ui-react, infra, etc.
-	Priority     Priority  `json:"priority"`   // P0, P1, P2
-	ModelHint    string    `json:"model_hint"` // long-context, agentic, codegen, etc.
-	Estimate     int       `json:"estimate"`   // Estimated complexity/time
+	Skill        string    `json:"skill"`            // go-backend, ui-react, infra, etc.
+	Priority     Priority  `json:"priority"`         // P0, P1, P2
+	ModelHint    string    `json:"model_hint"`       // long-context, agentic, codegen, etc.
+	Estimate     int       `json:"estimate"`         // Estimated complexity/time
+	Effort       Effort    `json:"effort,omitempty"` // Estimated story points
 }
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // Policy represents the complete policy configuration
 type Policy struct {
 	Execution  ExecutionPolicy       `yaml:"execution"`
@@ -14,6 +16,7 @@ type Policy struct {
 type ExecutionPolicy struct {
 	AllowLocal bool         `yaml:"allow_local"`
 	Docker     DockerPolicy `yaml:"docker"`
+	Plugins    PluginPolicy `yaml:"plugins"`
 }
 
 // DockerPolicy defines Docker-specific constraints
@@ -25,6 +28,16 @@ type DockerPolicy struct {
 	Network        string   `yaml:"network"` // none, allowlist profile, etc.
 }
 
+// PluginPolicy defines sandbox limits for plugin processes
+type PluginPolicy struct {
+	Sandbox      bool          `yaml:"sandbox"`
+	CPULimit     string        `yaml:"cpu_limit"`
+	MemLimit     string        `yaml:"mem_limit"`
+	Network      string        `yaml:"network"` // none denies network access
+	Timeout      time.Duration `yaml:"timeout"`
+	EnvAllowlist []string      `yaml:"env_allowlist"`
+}
+
 // ToolConfig defines configuration for a tool (linter, formatter, etc.)
 type ToolConfig struct {
 	Enabled bool   `yaml:"enabled"`
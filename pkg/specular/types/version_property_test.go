@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// genValidVersion generates valid release Version values for property testing
+func genValidVersion() *rapid.Generator[Version] {
+	return rapid.Custom(func(t *rapid.T) Version {
+		major := rapid.IntRange(0, 100).Draw(t, "major")
+		minor := rapid.IntRange(0, 100).Draw(t, "minor")
+		patch := rapid.IntRange(0, 100).Draw(t, "patch")
+		return Version(fmt.Sprintf("%d.%d.%d", major, minor, patch))
+	})
+}
+
+// TestVersion_ValidVersionsAlwaysValidate tests that generated versions pass validation
+func TestVersion_ValidVersionsAlwaysValidate(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		v := genValidVersion().Draw(t, "version")
+
+		if err := v.Validate(); err != nil {
+			t.Fatalf("valid version %q should pass validation: %v", v, err)
+		}
+
+		created, err := NewVersion(v.String())
+		if err != nil || created != v {
+			t.Fatalf("NewVersion(%q) = %q, %v", v, created, err)
+		}
+	})
+}
+
+// TestVersion_IsNewerThanIsAStrictOrder tests that IsNewerThan is irreflexive and asymmetric
+func TestVersion_IsNewerThanIsAStrictOrder(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		a := genValidVersion().Draw(t, "a")
+		b := genValidVersion().Draw(t, "b")
+
+		if a.IsNewerThan(a) {
+			t.Fatalf("%q should not be newer than itself", a)
+		}
+		if a.IsNewerThan(b) && b.IsNewerThan(a) {
+			t.Fatalf("%q and %q cannot both be newer than each other", a, b)
+		}
+		if a != b && !a.IsNewerThan(b) && !b.IsNewerThan(a) {
+			t.Fatalf("distinct releases %q and %q must be ordered", a, b)
+		}
+	})
+}
+
+// TestVersion_CompatibilityIsSymmetric tests that IsCompatibleWith is symmetric
+func TestVersion_CompatibilityIsSymmetric(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		a := genValidVersion().Draw(t, "a")
+		b := genValidVersion().Draw(t, "b")
+
+		if a.IsCompatibleWith(b) != b.IsCompatibleWith(a) {
+			t.Fatalf("compatibility of %q and %q is not symmetric", a, b)
+		}
+		if !a.IsCompatibleWith(a) {
+			t.Fatalf("%q should be compatible with itself", a)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestNewVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Version
+		wantErr bool
+	}{
+		{name: "valid release", value: "1.2.3", want: Version("1.2.3")},
+		{name: "valid zero version", value: "0.0.0", want: Version("0.0.0")},
+		{name: "valid pre-release", value: "1.0.0-alpha.1", want: Version("1.0.0-alpha.1")},
+		{name: "valid build metadata", value: "1.0.0+build.5", want: Version("1.0.0+build.5")},
+		{name: "valid pre-release and build", value: "2.1.0-rc.1+sha.abc123", want: Version("2.1.0-rc.1+sha.abc123")},
+		{name: "invalid empty", value: "", wantErr: true},
+		{name: "invalid missing patch", value: "1.0", wantErr: true},
+		{name: "invalid extra component", value: "1.0.0.0", wantErr: true},
+		{name: "invalid v prefix", value: "v1.0.0", wantErr: true},
+		{name: "invalid leading zero", value: "01.0.0", wantErr: true},
+		{name: "invalid leading zero pre-release", value: "1.0.0-01", wantErr: true},
+		{name: "invalid empty pre-release", value: "1.0.0-", wantErr: true},
+		{name: "invalid whitespace", value: " 1.0.0", wantErr: true},
+		{name: "invalid word", value: "latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVersion(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("NewVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	if got := Version("1.2.3-beta").String(); got != "1.2.3-beta" {
+		t.Errorf("Version.String() = %v, want 1.2.3-beta", got)
+	}
+}
+
+func TestVersion_IsNewerThan(t *testing.T) {
+	tests := []struct {
+		name string
+		v1   Version
+		v2   Version
+		want bool
+	}{
+		{"major is newer", "2.0.0", "1.9.9", true},
+		{"minor is newer", "1.10.0", "1.9.0", true},
+		{"patch is newer", "1.0.1", "1.0.0", true},
+		{"equal is not newer", "1.0.0", "1.0.0", false},
+		{"older is not newer", "1.0.0", "1.0.1", false},
+		{"release is newer than pre-release", "1.0.0", "1.0.0-rc.1", true},
+		{"pre-release is not newer than release", "1.0.0-rc.1", "1.0.0", false},
+		{"numeric pre-release identifiers compare numerically", "1.0.0-rc.10", "1.0.0-rc.2", true},
+		{"alphanumeric identifiers are newer than numeric", "1.0.0-alpha.beta", "1.0.0-alpha.1", true},
+		{"longer pre-release is newer", "1.0.0-alpha.1", "1.0.0-alpha", true},
+		{"pre-release identifiers compare lexically", "1.0.0-beta", "1.0.0-alpha", true},
+		{"build metadata is ignored", "1.0.0+build.2", "1.0.0+build.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v1.IsNewerThan(tt.v2); got != tt.want {
+				t.Errorf("Version(%q).IsNewerThan(%q) = %v, want %v", tt.v1, tt.v2, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_IsCompatibleWith(t *testing.T) {
+	tests := []struct {
+		name string
+		v1   Version
+		v2   Version
+		want bool
+	}{
+		{"same version", "1.2.3", "1.2.3", true},
+		{"same major", "1.9.0", "1.0.0", true},
+		{"same major with pre-release", "2.0.0-rc.1", "2.3.1", true},
+		{"different major", "2.0.0", "1.9.9", false},
+		{"zero major", "0.1.0", "0.2.0", true},
+		{"invalid version", "1.0", "1.0.0", false},
+		{"invalid other version", "1.0.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v1.IsCompatibleWith(tt.v2); got != tt.want {
+				t.Errorf("Version(%q).IsCompatibleWith(%q) = %v, want %v", tt.v1, tt.v2, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/tokenizer"
 )
 
 // GenerateRequest matches internal/provider/types.go
@@ -149,9 +151,7 @@ func handleGenerate() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens (rough approximation: ~4 chars per token)
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("anthropic", "", fullPrompt, content)
 
 	// Get model from config or use default
 	model := "claude-sonnet-4-20250514"
@@ -232,9 +232,7 @@ func handleStream() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("anthropic", "", fullPrompt, content)
 
 	// Emit single chunk with full response
 	chunk := StreamChunk{
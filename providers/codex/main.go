@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/tokenizer"
 )
 
 // GenerateRequest matches internal/provider/types.go
@@ -52,6 +54,23 @@ type StreamChunk struct {
 	Timestamp  time.Time `json:"timestamp"`
 }
 
+// HealthResponse is emitted by the health command so callers such as
+// `specular doctor` can report more than a bare pass/fail.
+type HealthResponse struct {
+	Provider           string   `json:"provider"`
+	Healthy            bool     `json:"healthy"`
+	Version            string   `json:"version,omitempty"`
+	Models             []string `json:"models,omitempty"`
+	CredentialsPresent bool     `json:"credentials_present"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// supportedModels lists the models this provider can be configured with
+var supportedModels = []string{
+	"gpt-3.5-turbo-instruct",
+	"code-davinci-002",
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <command>\n", os.Args[0])
@@ -154,9 +173,7 @@ func handleGenerate() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens (rough approximation: ~4 chars per token)
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("codex", model, fullPrompt, content)
 
 	// Convert to our response format
 	resp := GenerateResponse{
@@ -226,9 +243,7 @@ func handleStream() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("codex", model, fullPrompt, content)
 
 	// Emit single chunk with full response
 	chunk := StreamChunk{
@@ -249,17 +264,33 @@ func handleStream() error {
 }
 
 func handleHealth() error {
+	health := HealthResponse{
+		Provider:           "codex",
+		Models:             supportedModels,
+		CredentialsPresent: os.Getenv("OPENAI_API_KEY") != "",
+	}
+
 	// Check if openai CLI is available (codex accessed via openai CLI)
-	cmd := exec.Command("openai", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("codex/openai CLI not available: %w", err)
+	output, err := exec.Command("openai", "--version").Output()
+	if err != nil {
+		return reportUnhealthy(health, fmt.Errorf("codex/openai CLI not available: %w", err))
 	}
+	health.Version = strings.TrimSpace(string(output))
 
 	// Check if OPENAI_API_KEY is set
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
+	if !health.CredentialsPresent {
+		return reportUnhealthy(health, fmt.Errorf("OPENAI_API_KEY environment variable not set"))
 	}
 
-	fmt.Println("OK")
-	return nil
+	health.Healthy = true
+	return json.NewEncoder(os.Stdout).Encode(health)
+}
+
+// reportUnhealthy writes the failed health payload and returns err so the
+// process still exits non-zero.
+func reportUnhealthy(health HealthResponse, err error) error {
+	health.Healthy = false
+	health.Error = err.Error()
+	_ = json.NewEncoder(os.Stdout).Encode(health) // Best effort, the error is reported via exit code
+	return err
 }
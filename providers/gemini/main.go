@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/felixgeelhaar/specular/internal/tokenizer"
 )
 
 // GenerateRequest matches internal/provider/types.go
@@ -52,6 +54,24 @@ type StreamChunk struct {
 	Timestamp  time.Time `json:"timestamp"`
 }
 
+// HealthResponse is emitted by the health command so callers such as
+// `specular doctor` can report more than a bare pass/fail.
+type HealthResponse struct {
+	Provider           string   `json:"provider"`
+	Healthy            bool     `json:"healthy"`
+	Version            string   `json:"version,omitempty"`
+	Models             []string `json:"models,omitempty"`
+	CredentialsPresent bool     `json:"credentials_present"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// supportedModels lists the models this provider can be configured with
+var supportedModels = []string{
+	"gemini-2.0-flash-exp",
+	"gemini-1.5-pro",
+	"gemini-1.5-flash",
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <command>\n", os.Args[0])
@@ -170,9 +190,7 @@ func handleGenerate() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens (rough approximation: ~4 chars per token)
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("gemini", model, fullPrompt, content)
 
 	// Convert to our response format
 	resp := GenerateResponse{
@@ -243,9 +261,7 @@ func handleStream() error {
 
 	content := strings.TrimSpace(string(output))
 
-	// Estimate tokens
-	inputTokens := len(fullPrompt) / 4
-	outputTokens := len(content) / 4
+	inputTokens, outputTokens := tokenizer.CountExchange("gemini", model, fullPrompt, content)
 
 	// Emit single chunk with full response
 	chunk := StreamChunk{
@@ -266,26 +282,45 @@ func handleStream() error {
 }
 
 func handleHealth() error {
+	health := HealthResponse{
+		Provider: "gemini",
+		Models:   supportedModels,
+	}
+
 	// Check if gemini CLI is available
-	cmd := exec.Command("gemini", "--version")
-	if err := cmd.Run(); err != nil {
+	output, err := exec.Command("gemini", "--version").Output()
+	if err == nil {
+		health.Version = strings.TrimSpace(string(output))
+	} else {
 		// Try gcloud as fallback
-		cmd = exec.Command("gcloud", "ai", "generative-models", "--help")
+		cmd := exec.Command("gcloud", "ai", "generative-models", "--help")
 		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("gemini CLI not available (tried both 'gemini' and 'gcloud ai'): %w", err)
+			return reportUnhealthy(health, fmt.Errorf("gemini CLI not available (tried both 'gemini' and 'gcloud ai'): %w", err))
 		}
+		health.Version = "gcloud"
 	}
 
 	// Check if GEMINI_API_KEY is set (for standalone CLI)
 	// or if gcloud is authenticated (for gcloud CLI)
-	if os.Getenv("GEMINI_API_KEY") == "" {
+	health.CredentialsPresent = os.Getenv("GEMINI_API_KEY") != ""
+	if !health.CredentialsPresent {
 		// Check gcloud auth
 		cmd := exec.Command("gcloud", "auth", "list")
 		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("neither GEMINI_API_KEY nor gcloud authentication found")
+			return reportUnhealthy(health, fmt.Errorf("neither GEMINI_API_KEY nor gcloud authentication found"))
 		}
+		health.CredentialsPresent = true
 	}
 
-	fmt.Println("OK")
-	return nil
+	health.Healthy = true
+	return json.NewEncoder(os.Stdout).Encode(health)
+}
+
+// reportUnhealthy writes the failed health payload and returns err so the
+// process still exits non-zero.
+func reportUnhealthy(health HealthResponse, err error) error {
+	health.Healthy = false
+	health.Error = err.Error()
+	_ = json.NewEncoder(os.Stdout).Encode(health) // Best effort, the error is reported via exit code
+	return err
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -16,7 +17,7 @@ type GenerateRequest struct {
 	MaxTokens    int                    `json:"max_tokens,omitempty"`
 	Temperature  float64                `json:"temperature,omitempty"`
 	TopP         float64                `json:"top_p,omitempty"`
-	Tools        []interface{}          `json:"tools,omitempty"`
+	Tools        []Tool                 `json:"tools,omitempty"`
 	Context      []Message              `json:"context,omitempty"`
 	Config       map[string]interface{} `json:"config,omitempty"`
 	Metadata     map[string]string      `json:"metadata,omitempty"`
@@ -31,14 +32,45 @@ type GenerateResponse struct {
 	Model        string        `json:"model"`
 	Latency      time.Duration `json:"latency"`
 	FinishReason string        `json:"finish_reason"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
 	Error        string        `json:"error,omitempty"`
 	Provider     string        `json:"provider"`
 }
 
 // Message represents a conversation message
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// Tool matches internal/provider/types.go; ollama's chat API takes the
+// same shape
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction matches internal/provider/types.go
+type ToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall matches internal/provider/types.go
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction matches internal/provider/types.go; Arguments is a JSON
+// string
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 // StreamChunk matches internal/provider/interface.go
@@ -82,6 +114,54 @@ type OllamaGenerateResponse struct {
 	EvalDuration       int64  `json:"eval_duration,omitempty"`
 }
 
+// OllamaChatRequest is the format of ollama's chat API, used for requests
+// that carry tools
+type OllamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []OllamaMessage `json:"messages"`
+	Tools    []Tool          `json:"tools,omitempty"`
+	Stream   bool            `json:"stream"`
+	Options  *Options        `json:"options,omitempty"`
+}
+
+// OllamaMessage is a chat message in ollama's format
+type OllamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []OllamaToolCall `json:"tool_calls,omitempty"`
+}
+
+// OllamaToolCall is a tool call in ollama's format, with the arguments as
+// an object rather than a JSON string
+type OllamaToolCall struct {
+	Function struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	} `json:"function"`
+}
+
+// OllamaChatResponse is what ollama's chat API returns
+type OllamaChatResponse struct {
+	Model           string        `json:"model"`
+	Message         OllamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason,omitempty"`
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// HealthResponse is emitted by the health command so callers such as
+// `specular doctor` can report more than a bare pass/fail.
+type HealthResponse struct {
+	Provider           string   `json:"provider"`
+	Healthy            bool     `json:"healthy"`
+	Version            string   `json:"version,omitempty"`
+	Models             []string `json:"models,omitempty"`
+	CredentialsPresent bool     `json:"credentials_present"`
+	Error              string   `json:"error,omitempty"`
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <command>\n", os.Args[0])
@@ -132,6 +212,11 @@ func handleGenerate() error {
 		model = modelVal
 	}
 
+	// Tool calling is only available through the chat API
+	if len(req.Tools) > 0 {
+		return handleChat(req, model, startTime)
+	}
+
 	// Build conversation prompt if context is provided
 	fullPrompt := req.Prompt
 	if len(req.Context) > 0 {
@@ -231,6 +316,11 @@ func handleStream() error {
 		return fmt.Errorf("failed to decode request: %w", err)
 	}
 
+	// Tools must not be dropped silently; callers can use generate instead
+	if len(req.Tools) > 0 {
+		return fmt.Errorf("tool calling is not supported when streaming, use generate")
+	}
+
 	// Get model from config, default to llama3.2
 	model := "llama3.2"
 	if modelVal, ok := req.Config["model"].(string); ok && modelVal != "" {
@@ -350,12 +440,193 @@ func handleStream() error {
 	return nil
 }
 
-func handleHealth() error {
-	// Check if ollama is available
-	cmd := exec.Command("ollama", "list")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("ollama not available: %w", err)
+// handleChat generates a response with tools through ollama's chat API and
+// maps any tool calls back to the provider format
+func handleChat(req GenerateRequest, model string, startTime time.Time) error {
+	messages, err := chatMessages(req)
+	if err != nil {
+		return err
+	}
+
+	ollamaReq := OllamaChatRequest{
+		Model:    model,
+		Messages: messages,
+		Tools:    req.Tools,
+		Stream:   false,
+	}
+	if req.Temperature > 0 || req.TopP > 0 || req.MaxTokens > 0 {
+		ollamaReq.Options = &Options{
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+			NumPredict:  req.MaxTokens,
+		}
+	}
+
+	reqJSON, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ollama request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "curl", "-s", "http://localhost:11434/api/chat",
+		"-d", string(reqJSON))
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ollama API call failed: %w\nOutput: %s", err, string(output))
+	}
+
+	var chatResp OllamaChatResponse
+	if err := json.Unmarshal(output, &chatResp); err != nil {
+		return fmt.Errorf("failed to parse ollama chat response: %w", err)
 	}
-	fmt.Println("OK")
+	if chatResp.Error != "" {
+		// Ollama rejects tools for models without tool support, e.g.
+		// "registry.ollama.ai/library/llama2:latest does not support tools"
+		if strings.Contains(chatResp.Error, "does not support tools") {
+			return fmt.Errorf("model %s does not support tool calling; choose a tool-capable model such as llama3.1 or qwen2.5", model)
+		}
+		return fmt.Errorf("ollama chat failed: %s", chatResp.Error)
+	}
+
+	toolCalls, err := fromOllamaToolCalls(chatResp.Message.ToolCalls)
+	if err != nil {
+		return err
+	}
+
+	resp := GenerateResponse{
+		Content:      chatResp.Message.Content,
+		TokensUsed:   chatResp.PromptEvalCount + chatResp.EvalCount,
+		InputTokens:  chatResp.PromptEvalCount,
+		OutputTokens: chatResp.EvalCount,
+		Model:        chatResp.Model,
+		Latency:      time.Since(startTime),
+		FinishReason: "stop",
+		ToolCalls:    toolCalls,
+		Provider:     "ollama",
+	}
+	switch {
+	case len(toolCalls) > 0:
+		resp.FinishReason = "tool_calls"
+	case !chatResp.Done || chatResp.DoneReason == "length":
+		resp.FinishReason = "length"
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
 	return nil
 }
+
+// chatMessages converts the system prompt, conversation context, and prompt
+// into ollama chat messages
+func chatMessages(req GenerateRequest) ([]OllamaMessage, error) {
+	var messages []OllamaMessage
+	if req.SystemPrompt != "" {
+		messages = append(messages, OllamaMessage{Role: "system", Content: req.SystemPrompt})
+	}
+
+	for _, msg := range req.Context {
+		ollamaMsg := OllamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, call := range msg.ToolCalls {
+			var toolCall OllamaToolCall
+			toolCall.Function.Name = call.Function.Name
+			if call.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &toolCall.Function.Arguments); err != nil {
+					return nil, fmt.Errorf("invalid arguments for tool call %s: %w", call.Function.Name, err)
+				}
+			}
+			ollamaMsg.ToolCalls = append(ollamaMsg.ToolCalls, toolCall)
+		}
+		messages = append(messages, ollamaMsg)
+	}
+
+	// The prompt is empty when the last context message is a tool result
+	if req.Prompt != "" {
+		messages = append(messages, OllamaMessage{Role: "user", Content: req.Prompt})
+	}
+	return messages, nil
+}
+
+// fromOllamaToolCalls converts ollama tool calls, which carry no IDs, into
+// the provider format
+func fromOllamaToolCalls(calls []OllamaToolCall) ([]ToolCall, error) {
+	toolCalls := make([]ToolCall, 0, len(calls))
+	for i, call := range calls {
+		arguments := []byte("{}")
+		if call.Function.Arguments != nil {
+			var err error
+			if arguments, err = json.Marshal(call.Function.Arguments); err != nil {
+				return nil, fmt.Errorf("failed to encode arguments for tool call %s: %w", call.Function.Name, err)
+			}
+		}
+		toolCalls = append(toolCalls, ToolCall{
+			ID:   fmt.Sprintf("call_%d", i),
+			Type: "function",
+			Function: ToolCallFunction{
+				Name:      call.Function.Name,
+				Arguments: string(arguments),
+			},
+		})
+	}
+	return toolCalls, nil
+}
+
+func handleHealth() error {
+	// Ollama runs locally and needs no credentials
+	health := HealthResponse{
+		Provider:           "ollama",
+		CredentialsPresent: true,
+	}
+
+	// Check if ollama is available and collect installed models
+	output, err := exec.Command("ollama", "list").Output()
+	if err != nil {
+		return reportUnhealthy(health, fmt.Errorf("ollama not available: %w", err))
+	}
+	health.Models = parseOllamaList(string(output))
+
+	// Daemon version is informational only
+	if versionOut, err := exec.Command("ollama", "--version").Output(); err == nil {
+		health.Version = parseVersion(string(versionOut))
+	}
+
+	health.Healthy = true
+	return json.NewEncoder(os.Stdout).Encode(health)
+}
+
+// reportUnhealthy writes the failed health payload and returns err so the
+// process still exits non-zero.
+func reportUnhealthy(health HealthResponse, err error) error {
+	health.Healthy = false
+	health.Error = err.Error()
+	_ = json.NewEncoder(os.Stdout).Encode(health) // Best effort, the error is reported via exit code
+	return err
+}
+
+// parseOllamaList extracts model names from `ollama list` output,
+// skipping the NAME/ID/SIZE header row.
+func parseOllamaList(output string) []string {
+	var models []string
+	for i, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || (i == 0 && fields[0] == "NAME") {
+			continue
+		}
+		models = append(models, fields[0])
+	}
+	return models
+}
+
+// parseVersion returns the last whitespace-separated token of a version
+// banner such as "ollama version is 0.5.7".
+func parseVersion(output string) string {
+	fields := strings.Fields(strings.TrimSpace(output))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}